@@ -155,6 +155,12 @@ func init() {
 			Name:   "services",
 			Usage:  "List services in the registry",
 			Action: util.Print(listServices),
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "graph",
+					Usage: "Show the dependency graph of the services, pass a service name to show only its dependencies and dependents",
+				},
+			},
 		},
 	)
 }
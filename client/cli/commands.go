@@ -99,6 +99,16 @@ func QueryStats(c *cli.Context, args []string) ([]byte, error) {
 				node.Id, node.Address, started, uptime, memory, rsp.Threads, gc)
 
 			output = append(output, line)
+
+			// the metrics registered by the service, sorted by name
+			names := make([]string, 0, len(rsp.Metrics))
+			for name := range rsp.Metrics {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				output = append(output, fmt.Sprintf("\t%s\t%v", name, rsp.Metrics[name]))
+			}
 		}
 	}
 
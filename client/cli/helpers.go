@@ -18,6 +18,9 @@ import (
 )
 
 func listServices(c *cli.Context, args []string) ([]byte, error) {
+	if c.Bool("graph") {
+		return clic.ServiceGraph(c, args)
+	}
 	return clic.ListServices(c)
 }
 
@@ -0,0 +1,416 @@
+// Package latency implements the micro latency command, a heatmap of the latency of the
+// endpoints of a service over time which links the outliers to the traces of the slowest
+// requests, so a spike can be followed straight to a trace of a request which caused it
+package latency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/debug"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/registry"
+	rutil "github.com/micro/micro/v3/service/registry/util"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:  "latency",
+		Usage: "Heatmap of the latency of the endpoints of a service with the traces of outliers",
+		Description: `Latency prints a heatmap of the latency of the requests served by the endpoints of a
+service over time, merged across its replicas, followed by the slowest requests in the outlier
+buckets and their trace IDs. Pass a trace ID with --trace to print the spans of the trace. e.g.
+
+	micro latency users Users.Read --since=1h
+	micro latency users --trace=<trace id>`,
+		ArgsUsage: "<service> [endpoint]",
+		Action:    latency,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "since",
+				Usage: "How far back to show the latency from",
+				Value: time.Hour,
+			},
+			&cli.Float64Flag{
+				Name:  "percentile",
+				Usage: "Percentile at or above which buckets are outliers",
+				Value: 95,
+			},
+			&cli.IntFlag{
+				Name:  "outliers",
+				Usage: "Number of the slowest outliers to list",
+				Value: 10,
+			},
+			&cli.StringFlag{
+				Name:  "trace",
+				Usage: "Print the spans of the trace recorded by the service",
+			},
+		},
+	})
+}
+
+// shades of the cells of the heatmap, from the fewest requests to the most
+var shades = []string{".", ":", "-", "=", "+", "*", "#", "%", "@"}
+
+// histogram is the latency of an endpoint merged across the replicas of the service
+type histogram struct {
+	Endpoint string
+	Bounds   []int64
+	Window   time.Duration
+	Windows  []*window
+}
+
+// window of a histogram
+type window struct {
+	Time   time.Time
+	Counts []uint64
+	// Exemplars by bucket, the slowest of the replicas
+	Exemplars map[int32]*exemplar
+}
+
+// exemplar is the slowest request of a bucket and the replica which served it
+type exemplar struct {
+	*pb.Exemplar
+	Node string
+}
+
+// node is the latency reported by a replica of the service
+type node struct {
+	id  string
+	rsp *pb.LatencyResponse
+}
+
+func latency(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 || ctx.Args().Len() > 2 {
+		return cli.Exit("Required usage: micro latency <service> [endpoint]", 1)
+	}
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+	service := ctx.Args().Get(0)
+
+	nodes, err := registry.GetService(service, goregistry.GetDomain(ns))
+	if rutil.IsNotFound(err) || (err == nil && len(nodes) == 0) {
+		return fmt.Errorf("Service %v not found", service)
+	} else if err != nil {
+		return err
+	}
+
+	if id := ctx.String("trace"); len(id) > 0 {
+		renderTrace(os.Stdout, id, collectTrace(service, nodes, id))
+		return nil
+	}
+
+	req := &pb.LatencyRequest{
+		Endpoint:   ctx.Args().Get(1),
+		Since:      int64(ctx.Duration("since").Seconds()),
+		Percentile: ctx.Float64("percentile"),
+	}
+	hists, down := merge(collect(service, nodes, req))
+	if down > 0 {
+		fmt.Fprintf(os.Stderr, "%d replicas of %v didn't report their latency\n", down, service)
+	}
+	if len(hists) == 0 {
+		fmt.Println("No requests recorded")
+		return nil
+	}
+	render(os.Stdout, service, hists, ctx.Int("outliers"))
+	return nil
+}
+
+// collect the latency reported by every replica of the service, the response of replicas
+// which couldn't be reached is nil
+func collect(service string, srvs []*goregistry.Service, req *pb.LatencyRequest) []*node {
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	var result []*node
+
+	for _, srv := range srvs {
+		for _, n := range srv.Nodes {
+			wg.Add(1)
+			go func(n *goregistry.Node) {
+				defer wg.Done()
+
+				rsp := &pb.LatencyResponse{}
+				r := client.NewRequest(service, "Debug.Latency", req)
+				if err := client.Call(context.Background(), r, rsp, goclient.WithAddress(n.Address)); err != nil {
+					rsp = nil
+				}
+
+				mtx.Lock()
+				result = append(result, &node{id: n.Id, rsp: rsp})
+				mtx.Unlock()
+			}(n)
+		}
+	}
+
+	wg.Wait()
+	return result
+}
+
+// merge the histograms of the replicas, summing the counts of the windows which start at the
+// same time and keeping the slowest exemplar of each bucket. Replicas whose buckets differ to
+// the first replica's, e.g. as they run another version, are skipped. The number of replicas
+// which didn't report their latency is returned.
+func merge(nodes []*node) ([]*histogram, int) {
+	var down int
+	hists := map[string]*histogram{}
+	windows := map[string]map[int64]*window{}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+	for _, n := range nodes {
+		if n.rsp == nil {
+			down++
+			continue
+		}
+
+		for _, e := range n.rsp.Endpoints {
+			h, ok := hists[e.Endpoint]
+			if !ok {
+				h = &histogram{Endpoint: e.Endpoint, Bounds: e.Bounds, Window: time.Duration(e.Window)}
+				hists[e.Endpoint] = h
+				windows[e.Endpoint] = map[int64]*window{}
+			} else if !sameBounds(h.Bounds, e.Bounds) {
+				continue
+			}
+
+			for _, lw := range e.Windows {
+				w, ok := windows[e.Endpoint][lw.Timestamp]
+				if !ok {
+					w = &window{
+						Time:      time.Unix(lw.Timestamp, 0),
+						Counts:    make([]uint64, len(e.Bounds)+1),
+						Exemplars: map[int32]*exemplar{},
+					}
+					windows[e.Endpoint][lw.Timestamp] = w
+					h.Windows = append(h.Windows, w)
+				}
+				for i, c := range lw.Counts {
+					if i < len(w.Counts) {
+						w.Counts[i] += c
+					}
+				}
+				for _, ex := range lw.Exemplars {
+					if prev, ok := w.Exemplars[ex.Bucket]; !ok || ex.Latency > prev.Latency {
+						w.Exemplars[ex.Bucket] = &exemplar{Exemplar: ex, Node: n.id}
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]*histogram, 0, len(hists))
+	for _, h := range hists {
+		sort.Slice(h.Windows, func(i, j int) bool { return h.Windows[i].Time.Before(h.Windows[j].Time) })
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Endpoint < result[j].Endpoint })
+	return result, down
+}
+
+func sameBounds(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bucketLabel returns the label of the bucket, its upper bound or the last bound exceeded
+func bucketLabel(bounds []int64, i int) string {
+	if i < len(bounds) {
+		return "<=" + time.Duration(bounds[i]).String()
+	}
+	if len(bounds) == 0 {
+		return "all"
+	}
+	return ">" + time.Duration(bounds[len(bounds)-1]).String()
+}
+
+// shade returns the cell of the heatmap for the count, relative to the largest count
+func shade(count, max uint64) string {
+	if count == 0 || max == 0 {
+		return " "
+	}
+	i := int((count - 1) * uint64(len(shades)) / max)
+	if i >= len(shades) {
+		i = len(shades) - 1
+	}
+	return shades[i]
+}
+
+func render(out io.Writer, service string, hists []*histogram, outliers int) {
+	var slowest []*exemplar
+	buckets := map[*exemplar]string{}
+
+	for _, h := range hists {
+		var max uint64
+		for _, w := range h.Windows {
+			for _, c := range w.Counts {
+				if c > max {
+					max = c
+				}
+			}
+		}
+
+		fmt.Fprintf(out, "%s.%s - %v windows\n\n", service, h.Endpoint, h.Window)
+		tw := tabwriter.NewWriter(out, 0, 8, 1, ' ', 0)
+		header := []string{"TIME"}
+		for i := 0; i <= len(h.Bounds); i++ {
+			header = append(header, bucketLabel(h.Bounds, i))
+		}
+		fmt.Fprintln(tw, strings.Join(append(header, "REQUESTS"), "\t"))
+
+		for _, w := range h.Windows {
+			cells := []string{w.Time.Format("15:04")}
+			var total uint64
+			for i, c := range w.Counts {
+				cell := shade(c, max)
+				if _, ok := w.Exemplars[int32(i)]; ok {
+					// outlier buckets link to a trace
+					cell += "!"
+				}
+				cells = append(cells, cell)
+				total += c
+			}
+			fmt.Fprintln(tw, strings.Join(append(cells, fmt.Sprintf("%d", total)), "\t"))
+
+			for _, ex := range w.Exemplars {
+				buckets[ex] = bucketLabel(h.Bounds, int(ex.Bucket))
+				slowest = append(slowest, ex)
+			}
+		}
+		tw.Flush()
+		fmt.Fprintln(out)
+	}
+
+	if len(slowest) == 0 {
+		return
+	}
+	sort.Slice(slowest, func(i, j int) bool {
+		if slowest[i].Latency == slowest[j].Latency {
+			return slowest[i].Timestamp > slowest[j].Timestamp
+		}
+		return slowest[i].Latency > slowest[j].Latency
+	})
+	if outliers > 0 && len(slowest) > outliers {
+		slowest = slowest[:outliers]
+	}
+
+	fmt.Fprintln(out, "Slowest outliers, marked ! above:")
+	fmt.Fprintln(out)
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"TIME", "LATENCY", "BUCKET", "NODE", "TRACE"}, "\t"))
+	for _, ex := range slowest {
+		fmt.Fprintf(tw, "%s\t%v\t%s\t%s\t%s\n", time.Unix(ex.Timestamp, 0).Format("15:04:05"),
+			time.Duration(ex.Latency), buckets[ex], ex.Node, ex.Trace)
+	}
+	tw.Flush()
+	fmt.Fprintf(out, "\nRun micro latency %s --trace=<trace> to print the spans of a trace\n", service)
+}
+
+// collectTrace returns the spans of the trace recorded by the replicas of the service
+func collectTrace(service string, srvs []*goregistry.Service, id string) []*pb.Span {
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	spans := map[string]*pb.Span{}
+
+	for _, srv := range srvs {
+		for _, n := range srv.Nodes {
+			wg.Add(1)
+			go func(n *goregistry.Node) {
+				defer wg.Done()
+
+				rsp := &pb.TraceResponse{}
+				r := client.NewRequest(service, "Debug.Trace", &pb.TraceRequest{Id: id})
+				if err := client.Call(context.Background(), r, rsp, goclient.WithAddress(n.Address)); err != nil {
+					return
+				}
+
+				mtx.Lock()
+				for _, s := range rsp.Spans {
+					spans[s.Id] = s
+				}
+				mtx.Unlock()
+			}(n)
+		}
+	}
+
+	wg.Wait()
+	result := make([]*pb.Span, 0, len(spans))
+	for _, s := range spans {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Started < result[j].Started })
+	return result
+}
+
+// renderTrace prints the spans of the trace as a tree, each span indented under its parent
+func renderTrace(out io.Writer, id string, spans []*pb.Span) {
+	if len(spans) == 0 {
+		fmt.Fprintf(out, "Trace %s not found, traces are only kept for the most recent requests\n", id)
+		return
+	}
+
+	ids := map[string]bool{}
+	children := map[string][]*pb.Span{}
+	for _, s := range spans {
+		ids[s.Id] = true
+	}
+	var roots []*pb.Span
+	for _, s := range spans {
+		if ids[s.Parent] && s.Parent != s.Id {
+			children[s.Parent] = append(children[s.Parent], s)
+		} else {
+			roots = append(roots, s)
+		}
+	}
+
+	fmt.Fprintf(out, "Trace %s\n\n", id)
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"SPAN", "TYPE", "START", "DURATION", "STATUS"}, "\t"))
+	start := spans[0].Started
+
+	var walk func(s *pb.Span, depth int)
+	walk = func(s *pb.Span, depth int) {
+		typ := "inbound"
+		if s.Type == pb.SpanType_OUTBOUND {
+			typ = "outbound"
+		}
+		status := s.Metadata[debug.SpanStatus]
+		if e := s.Metadata[debug.SpanError]; len(e) > 0 {
+			status = strings.TrimSpace(status + " " + e)
+		}
+		if len(status) == 0 {
+			status = "-"
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t+%v\t%v\t%s\n", strings.Repeat("  ", depth), s.Name, typ,
+			time.Duration(s.Started-start), time.Duration(s.Duration), status)
+		for _, c := range children[s.Id] {
+			walk(c, depth+1)
+		}
+	}
+	for _, s := range roots {
+		walk(s, 0)
+	}
+	tw.Flush()
+}
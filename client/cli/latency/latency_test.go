@@ -0,0 +1,86 @@
+package latency
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/service/debug/proto"
+)
+
+func TestMerge(t *testing.T) {
+	bounds := []int64{int64(time.Millisecond * 10), int64(time.Second)}
+	nodes := []*node{
+		{id: "users-2", rsp: &pb.LatencyResponse{Endpoints: []*pb.EndpointLatency{
+			{Endpoint: "Users.Read", Bounds: bounds, Window: int64(time.Minute), Windows: []*pb.LatencyWindow{
+				{Timestamp: 120, Counts: []uint64{5, 1, 0}, Exemplars: []*pb.Exemplar{{Bucket: 1, Trace: "b", Latency: int64(time.Millisecond * 500)}}},
+				{Timestamp: 60, Counts: []uint64{10, 0, 0}},
+			}},
+		}}},
+		{id: "users-1", rsp: &pb.LatencyResponse{Endpoints: []*pb.EndpointLatency{
+			{Endpoint: "Users.Read", Bounds: bounds, Window: int64(time.Minute), Windows: []*pb.LatencyWindow{
+				{Timestamp: 120, Counts: []uint64{2, 1, 1}, Exemplars: []*pb.Exemplar{
+					{Bucket: 1, Trace: "a", Latency: int64(time.Millisecond * 200)},
+					{Bucket: 2, Trace: "c", Latency: int64(time.Second * 3)},
+				}},
+			}},
+		}}},
+		// running another version with other buckets
+		{id: "users-3", rsp: &pb.LatencyResponse{Endpoints: []*pb.EndpointLatency{
+			{Endpoint: "Users.Read", Bounds: bounds[:1], Windows: []*pb.LatencyWindow{{Timestamp: 120, Counts: []uint64{100, 100}}}},
+		}}},
+		{id: "users-4"},
+	}
+
+	hists, down := merge(nodes)
+	if down != 1 {
+		t.Errorf("Expected 1 replica to be down, got %v", down)
+	}
+	if len(hists) != 1 || len(hists[0].Windows) != 2 {
+		t.Fatalf("Expected 2 windows of Users.Read, got %+v", hists)
+	}
+
+	h := hists[0]
+	if h.Window != time.Minute || h.Windows[0].Time.Unix() != 60 {
+		t.Errorf("Expected the windows to be ordered, got %+v", h.Windows)
+	}
+	w := h.Windows[1]
+	if w.Counts[0] != 7 || w.Counts[1] != 2 || w.Counts[2] != 1 {
+		t.Errorf("Expected the counts to be summed, got %v", w.Counts)
+	}
+	if ex := w.Exemplars[1]; ex == nil || ex.Trace != "b" || ex.Node != "users-2" {
+		t.Errorf("Expected the slowest exemplar of the bucket, got %+v", ex)
+	}
+
+	var out bytes.Buffer
+	render(&out, "users", hists, 1)
+	for _, s := range []string{"users.Users.Read - 1m0s windows", "<=10ms", ">1s", "users-1", "--trace"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", s, out.String())
+		}
+	}
+	// only the slowest outlier is listed
+	if strings.Contains(out.String(), "users-2") {
+		t.Errorf("Expected only the slowest outlier to be listed, got:\n%s", out.String())
+	}
+}
+
+func TestShade(t *testing.T) {
+	if shade(0, 10) != " " || shade(1, 10) != shades[0] || shade(10, 10) != shades[len(shades)-1] {
+		t.Errorf("Unexpected shades %q %q %q", shade(0, 10), shade(1, 10), shade(10, 10))
+	}
+}
+
+func TestRenderTrace(t *testing.T) {
+	spans := []*pb.Span{
+		{Id: "1", Name: "users.Users.Read", Started: 0, Duration: uint64(time.Second), Metadata: map[string]string{"status": "200"}},
+		{Id: "2", Parent: "1", Name: "store.Store.Read", Started: uint64(time.Millisecond), Duration: uint64(time.Millisecond * 900), Type: pb.SpanType_OUTBOUND, Metadata: map[string]string{"error": "timeout"}},
+	}
+
+	var out bytes.Buffer
+	renderTrace(&out, "abc", spans)
+	if !strings.Contains(out.String(), "\n  store.Store.Read") || !strings.Contains(out.String(), "timeout") {
+		t.Errorf("Expected the outbound span to be nested under the request, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,394 @@
+// Package top implements the micro top command, a live view of the resources and
+// traffic of the services running in a namespace
+package top
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/debug/latency"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:      "top",
+		Usage:     "Live view of the resources and traffic of running services",
+		ArgsUsage: "[service...]",
+		Action:    top,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:    "interval",
+				Aliases: []string{"i"},
+				Usage:   "Interval between refreshes",
+				Value:   time.Second * 2,
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: "Column to sort by: name, cpu, memory, requests, errors or latency",
+				Value: "name",
+			},
+			&cli.BoolFlag{
+				Name:  "once",
+				Usage: "Print a single view and exit, rates are measured over one interval",
+			},
+		},
+	})
+}
+
+// node is the stats of a single replica at a point in time
+type node struct {
+	id    string
+	stats *pb.StatsResponse
+	// latency is nil if the replica didn't return its latency
+	latency *pb.LatencyResponse
+	time    time.Time
+}
+
+// row is the aggregated view of a service
+type row struct {
+	Service  string
+	Status   string
+	Replicas int
+	// Unreachable is the number of replicas which didn't return stats
+	Unreachable int
+	// CPU as a percentage of a single core
+	CPU float64
+	// Memory in bytes
+	Memory uint64
+	// Requests and Errors per second
+	Requests float64
+	Errors   float64
+	// Latency is the upper bound of the latency bucket the 95th percentile of the requests
+	// falls in, zero if no requests were served
+	Latency time.Duration
+	// SlowerThan is true if the 95th percentile is above Latency, the last bound
+	SlowerThan bool
+}
+
+// ErrorRate returns the percentage of requests which errored
+func (r *row) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return r.Errors / r.Requests * 100
+}
+
+func top(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	interval := ctx.Duration("interval")
+	if interval <= 0 {
+		interval = time.Second * 2
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	// the latency of the windows which ended since the previous refresh is requested, so the
+	// requests served in between can be counted
+	since := int64(math.Ceil(interval.Seconds())) + 1
+
+	prev := collect(ns, ctx.Args().Slice(), since)
+	if !ctx.Bool("once") {
+		render(os.Stdout, ns, aggregate(prev, prev, status(ns)), ctx.String("sort"), true)
+	}
+
+	for {
+		select {
+		case <-sig:
+			return nil
+		case <-tick.C:
+		}
+
+		curr := collect(ns, ctx.Args().Slice(), since)
+		rows := aggregate(prev, curr, status(ns))
+		prev = curr
+
+		if ctx.Bool("once") {
+			render(os.Stdout, ns, rows, ctx.String("sort"), false)
+			return nil
+		}
+		render(os.Stdout, ns, rows, ctx.String("sort"), true)
+	}
+}
+
+// collect the stats and the latency of the windows which ended in the last since seconds of
+// every replica of the services, keyed by service name
+func collect(ns string, filter []string, since int64) map[string][]*node {
+	names := filter
+	if len(names) == 0 {
+		services, err := registry.ListServices(goregistry.ListDomain(ns))
+		if err != nil {
+			return nil
+		}
+		for _, s := range services {
+			names = append(names, s.Name)
+		}
+	}
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	result := make(map[string][]*node, len(names))
+
+	for _, name := range names {
+		services, err := registry.GetService(name, goregistry.GetDomain(ns))
+		if err != nil {
+			continue
+		}
+
+		for _, srv := range services {
+			for _, n := range srv.Nodes {
+				wg.Add(1)
+				go func(name string, n *goregistry.Node) {
+					defer wg.Done()
+
+					req := client.NewRequest(name, "Debug.Stats", &pb.StatsRequest{})
+					rsp := &pb.StatsResponse{}
+					if err := client.Call(context.Background(), req, rsp, goclient.WithAddress(n.Address)); err != nil {
+						rsp = nil
+					}
+
+					lreq := client.NewRequest(name, "Debug.Latency", &pb.LatencyRequest{Since: since})
+					lrsp := &pb.LatencyResponse{}
+					if err := client.Call(context.Background(), lreq, lrsp, goclient.WithAddress(n.Address)); err != nil {
+						lrsp = nil
+					}
+
+					mtx.Lock()
+					result[name] = append(result[name], &node{id: n.Id, stats: rsp, latency: lrsp, time: time.Now()})
+					mtx.Unlock()
+				}(srv.Name, n)
+			}
+		}
+	}
+
+	wg.Wait()
+	return result
+}
+
+// status returns the runtime status of the services keyed by name, services which
+// aren't managed by the runtime have no status
+func status(ns string) map[string]string {
+	result := map[string]string{}
+	services, err := runtime.Read(goruntime.ReadNamespace(ns))
+	if err != nil {
+		return result
+	}
+	for _, s := range services {
+		result[s.Name] = s.Metadata["status"]
+	}
+	return result
+}
+
+// aggregate the stats of the replicas into a row per service. Rates are calculated from
+// the difference between the previous and current stats of each replica.
+func aggregate(prev, curr map[string][]*node, status map[string]string) []*row {
+	rows := make([]*row, 0, len(curr))
+	for name, nodes := range curr {
+		r := &row{Service: name, Status: status[name], Replicas: len(nodes)}
+		hist := &histogram{}
+
+		last := make(map[string]*node, len(prev[name]))
+		for _, n := range prev[name] {
+			last[n.id] = n
+		}
+
+		for _, n := range nodes {
+			if n.stats == nil {
+				r.Unreachable++
+				continue
+			}
+			r.Memory += n.stats.Memory
+
+			p, ok := last[n.id]
+			if !ok || p.stats == nil {
+				continue
+			}
+			secs := n.time.Sub(p.time).Seconds()
+			if secs <= 0 {
+				continue
+			}
+			// counters reset when a replica restarts
+			if n.stats.Started != p.stats.Started {
+				continue
+			}
+			r.CPU += float64(delta(p.stats.Cpu, n.stats.Cpu)) / (secs * 1e9) * 100
+			r.Requests += float64(delta(p.stats.Requests, n.stats.Requests)) / secs
+			r.Errors += float64(delta(p.stats.Errors, n.stats.Errors)) / secs
+			if p.latency != nil && n.latency != nil {
+				hist.add(p.latency, n.latency)
+			}
+		}
+
+		r.Latency, r.SlowerThan = hist.percentile(latency.DefaultPercentile)
+		rows = append(rows, r)
+	}
+
+	return rows
+}
+
+func delta(prev, curr uint64) uint64 {
+	if curr < prev {
+		return 0
+	}
+	return curr - prev
+}
+
+// histogram of the latency of the requests the replicas of a service served between two
+// collections, across all their endpoints
+type histogram struct {
+	bounds []int64
+	counts []uint64
+}
+
+// add the requests the replica served since the previous response to the histogram. The
+// endpoints whose buckets differ to the first added, e.g. as the replica runs another
+// version, are skipped.
+func (h *histogram) add(prev, curr *pb.LatencyResponse) {
+	last := map[string]map[int64][]uint64{}
+	for _, e := range prev.Endpoints {
+		last[e.Endpoint] = map[int64][]uint64{}
+		for _, w := range e.Windows {
+			last[e.Endpoint][w.Timestamp] = w.Counts
+		}
+	}
+
+	for _, e := range curr.Endpoints {
+		if h.counts == nil {
+			h.bounds = e.Bounds
+			h.counts = make([]uint64, len(e.Bounds)+1)
+		} else if !sameBounds(h.bounds, e.Bounds) {
+			continue
+		}
+
+		for _, w := range e.Windows {
+			counts := last[e.Endpoint][w.Timestamp]
+			for i, c := range w.Counts {
+				if i >= len(h.counts) {
+					break
+				}
+				var pc uint64
+				if i < len(counts) {
+					pc = counts[i]
+				}
+				h.counts[i] += delta(pc, c)
+			}
+		}
+	}
+}
+
+// percentile returns the upper bound of the bucket the percentile of the requests falls in,
+// or the last bound and true if it falls in the last bucket which has no upper bound
+func (h *histogram) percentile(p float64) (time.Duration, bool) {
+	i := latency.Percentile(h.counts, p)
+	if i < 0 || len(h.bounds) == 0 {
+		return 0, false
+	}
+	if i >= len(h.bounds) {
+		return time.Duration(h.bounds[len(h.bounds)-1]), true
+	}
+	return time.Duration(h.bounds[i]), false
+}
+
+func sameBounds(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortRows orders the rows by the column, numeric columns are sorted in descending order
+func sortRows(rows []*row, column string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		switch column {
+		case "cpu":
+			return rows[i].CPU > rows[j].CPU
+		case "memory":
+			return rows[i].Memory > rows[j].Memory
+		case "requests":
+			return rows[i].Requests > rows[j].Requests
+		case "errors":
+			return rows[i].ErrorRate() > rows[j].ErrorRate()
+		case "latency":
+			if rows[i].SlowerThan != rows[j].SlowerThan {
+				return rows[i].SlowerThan
+			}
+			return rows[i].Latency > rows[j].Latency
+		default:
+			return rows[i].Service < rows[j].Service
+		}
+	})
+}
+
+func render(out io.Writer, ns string, rows []*row, column string, clear bool) {
+	sortRows(rows, column)
+
+	if clear {
+		// move the cursor home and clear the screen
+		fmt.Fprint(out, "\033[H\033[2J")
+	}
+
+	var replicas int
+	var reqs float64
+	for _, r := range rows {
+		replicas += r.Replicas
+		reqs += r.Requests
+	}
+	fmt.Fprintf(out, "micro top - %s - namespace: %s, services: %d, replicas: %d, requests: %.1f/s\n\n",
+		time.Now().Format("15:04:05"), ns, len(rows), replicas, reqs)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join([]string{"SERVICE", "STATUS", "REPLICAS", "CPU", "MEMORY", "REQ/S", "ERR/S", "ERR%", "P95"}, "\t"))
+	for _, r := range rows {
+		replicas := fmt.Sprintf("%d", r.Replicas)
+		if r.Unreachable > 0 {
+			replicas = fmt.Sprintf("%d (%d down)", r.Replicas, r.Unreachable)
+		}
+		status := r.Status
+		if len(status) == 0 {
+			status = "-"
+		}
+		p95 := "-"
+		if r.SlowerThan {
+			p95 = ">" + r.Latency.String()
+		} else if r.Latency > 0 {
+			p95 = r.Latency.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f%%\t%.2fmb\t%.1f\t%.1f\t%.1f%%\t%s\n",
+			r.Service, status, replicas, r.CPU, float64(r.Memory)/(1024.0*1024.0), r.Requests, r.Errors, r.ErrorRate(), p95)
+	}
+	w.Flush()
+}
@@ -0,0 +1,89 @@
+package top
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/service/debug/proto"
+)
+
+func TestAggregate(t *testing.T) {
+	now := time.Now()
+	prev := map[string][]*node{
+		"foo": {
+			{id: "foo-1", time: now, stats: &pb.StatsResponse{Started: 1, Requests: 100, Errors: 10, Cpu: 0, Memory: 1024}, latency: latencyResponse(map[int64][]uint64{
+				0: {50, 40, 10},
+			})},
+			{id: "foo-2", time: now, stats: &pb.StatsResponse{Started: 1, Requests: 50}},
+		},
+	}
+	curr := map[string][]*node{
+		"foo": {
+			{id: "foo-1", time: now.Add(time.Second * 2), stats: &pb.StatsResponse{Started: 1, Requests: 120, Errors: 14, Cpu: 1e9, Memory: 1024}, latency: latencyResponse(map[int64][]uint64{
+				// the requests served since the previous stats are counted across windows
+				0:  {50, 45, 10},
+				60: {5, 5, 5},
+			})},
+			// restarted so the counters can't be compared
+			{id: "foo-2", time: now.Add(time.Second * 2), stats: &pb.StatsResponse{Started: 2, Requests: 5}},
+			{id: "foo-3", time: now.Add(time.Second * 2)},
+		},
+	}
+
+	rows := aggregate(prev, curr, map[string]string{"foo": "running"})
+	if len(rows) != 1 {
+		t.Fatalf("Expected 1 row, got %v", len(rows))
+	}
+
+	r := rows[0]
+	if r.Status != "running" || r.Replicas != 3 || r.Unreachable != 1 {
+		t.Fatalf("Unexpected status or replicas: %+v", r)
+	}
+	if r.Requests != 10 || r.Errors != 2 || r.ErrorRate() != 20 {
+		t.Fatalf("Unexpected rates: %+v", r)
+	}
+	if r.CPU != 50 {
+		t.Fatalf("Expected 50%% cpu, got %v", r.CPU)
+	}
+	if r.Memory != 1024 {
+		t.Fatalf("Expected 1024 bytes of memory, got %v", r.Memory)
+	}
+	if r.Latency != time.Millisecond*10 || !r.SlowerThan {
+		t.Fatalf("Expected the 95th percentile to be above 10ms, got %v", r.Latency)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := &histogram{}
+	if l, _ := h.percentile(95); l != 0 {
+		t.Errorf("Expected no latency without requests, got %v", l)
+	}
+
+	h.add(&pb.LatencyResponse{}, latencyResponse(map[int64][]uint64{0: {90, 10, 0}}))
+	if l, over := h.percentile(95); l != time.Millisecond*10 || over {
+		t.Errorf("Expected the 95th percentile to be within 10ms, got %v", l)
+	}
+
+	// endpoints with other buckets are skipped
+	h.add(&pb.LatencyResponse{}, &pb.LatencyResponse{Endpoints: []*pb.EndpointLatency{{
+		Endpoint: "Foo.Bar",
+		Bounds:   []int64{1},
+		Windows:  []*pb.LatencyWindow{{Counts: []uint64{0, 1000}}},
+	}}})
+	if l, over := h.percentile(95); l != time.Millisecond*10 || over {
+		t.Errorf("Expected the endpoint with other buckets to be skipped, got %v", l)
+	}
+}
+
+// latencyResponse returns the latency of an endpoint with buckets of 1ms and 10ms, and the
+// counts of the windows keyed by timestamp
+func latencyResponse(windows map[int64][]uint64) *pb.LatencyResponse {
+	el := &pb.EndpointLatency{
+		Endpoint: "Foo.Bar",
+		Bounds:   []int64{int64(time.Millisecond), int64(time.Millisecond * 10)},
+	}
+	for ts, counts := range windows {
+		el.Windows = append(el.Windows, &pb.LatencyWindow{Timestamp: ts, Counts: counts})
+	}
+	return &pb.LatencyResponse{Endpoints: []*pb.EndpointLatency{el}}
+}
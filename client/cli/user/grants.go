@@ -0,0 +1,128 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+)
+
+var grantsCommand = &cli.Command{
+	Name:   "grants",
+	Usage:  "List the client applications you've granted access to your account",
+	Action: listGrants,
+	Subcommands: []*cli.Command{
+		{
+			Name:   "approve",
+			Usage:  "Approve the scopes requested by a client application, e.g. micro user grants approve myapp --scopes read,write",
+			Action: approveGrant,
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:  "scopes",
+					Usage: "Scopes requested by the client application",
+				},
+				&cli.BoolFlag{
+					Name:  "yes",
+					Usage: "Approve without prompting for consent",
+				},
+			},
+		},
+		{
+			Name:   "revoke",
+			Usage:  "Revoke a client application's access to your account, e.g. micro user grants revoke myapp",
+			Action: revokeGrant,
+		},
+	},
+}
+
+// list the grants of the current user
+func listGrants(ctx *cli.Context) error {
+	grantsService := pb.NewGrantsService("auth", client.DefaultClient)
+	rsp, err := grantsService.List(context.DefaultContext, &pb.ListGrantsRequest{}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+	if len(rsp.Grants) == 0 {
+		fmt.Println("No client applications have been granted access")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLIENT\tSCOPES\tGRANTED")
+	for _, g := range rsp.Grants {
+		fmt.Fprintf(w, "%v\t%v\t%v\n", g.ClientId, strings.Join(g.Scopes, ", "), time.Unix(g.Created, 0).Format(time.RFC822))
+	}
+	return w.Flush()
+}
+
+// approve the scopes requested by a client after the user consents
+func approveGrant(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Required usage: micro user grants approve CLIENT --scopes SCOPES")
+	}
+	clientID := ctx.Args().First()
+
+	var scopes []string
+	for _, s := range ctx.StringSlice("scopes") {
+		for _, sc := range strings.Split(s, ",") {
+			if sc = strings.TrimSpace(sc); len(sc) > 0 {
+				scopes = append(scopes, sc)
+			}
+		}
+	}
+	if len(scopes) == 0 {
+		return fmt.Errorf("At least one scope is required")
+	}
+
+	if !ctx.Bool("yes") {
+		fmt.Printf("The application %v is requesting access to your account with the scopes:\n\n", clientID)
+		for _, s := range scopes {
+			fmt.Printf("  - %v\n", s)
+		}
+		fmt.Print("\nAllow access? [y/N]: ")
+
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Access denied")
+			return nil
+		}
+	}
+
+	grantsService := pb.NewGrantsService("auth", client.DefaultClient)
+	rsp, err := grantsService.Create(context.DefaultContext, &pb.CreateGrantRequest{
+		ClientId: clientID,
+		Scopes:   scopes,
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Granted %v access with the scopes: %v\n", clientID, strings.Join(rsp.Grant.Scopes, ", "))
+	return nil
+}
+
+// revoke a client's access to the current user
+func revokeGrant(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Required usage: micro user grants revoke CLIENT")
+	}
+
+	grantsService := pb.NewGrantsService("auth", client.DefaultClient)
+	_, err := grantsService.Revoke(context.DefaultContext, &pb.RevokeGrantRequest{
+		ClientId: ctx.Args().First(),
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked %v's access\n", ctx.Args().First())
+	return nil
+}
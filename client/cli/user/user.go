@@ -44,6 +44,7 @@ func init() {
 					Usage:  "Get the current namespace",
 					Action: getNamespace,
 				},
+				grantsCommand,
 				{
 					Name:  "set",
 					Usage: "Set various user based properties, eg. password",
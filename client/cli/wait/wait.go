@@ -0,0 +1,373 @@
+// Package wait implements the micro wait command, which blocks until a condition is met so
+// deploy scripts don't need to poll with sleep loops
+package wait
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gorouter "github.com/micro/go-micro/v3/router"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/client"
+	config "github.com/micro/micro/v3/service/config/proto"
+	mucontext "github.com/micro/micro/v3/service/context"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/registry"
+	rutil "github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+// Exit codes returned by micro wait
+const (
+	// ExitError is returned when the condition can't be met, e.g. the service failed to start
+	ExitError = 1
+	// ExitTimeout is returned when the condition wasn't met before the timeout
+	ExitTimeout = 2
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:  "wait",
+		Usage: "Wait for a condition to be met",
+		Description: `Wait blocks until the condition of the resource is met, exiting with 0 once it is,
+1 if it can't be met and 2 if the timeout expires first. e.g.
+
+	micro wait service/users --for=ready --timeout=120s
+	micro wait route/users
+	micro wait config/users.database --for=value='"postgres"'
+	micro wait consumer/billing/orders --for=sequence=1042
+
+Conditions:
+	service/<name>: ready (default), registered or deleted
+	route/<service>: exists (default) or deleted
+	config/<path>: exists (default), deleted or value=<json>
+	consumer/<group>/<topic>: caught-up (default) or sequence=<n>
+
+A consumer group has caught up once it has processed the events published to the topic before
+the wait started, which requires it to record its history using events.RecordHistory.`,
+		ArgsUsage: "<resource>/<name>",
+		Action:    wait,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "for",
+				Usage: "Condition to wait for, the default depends on the resource",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "How long to wait for, 0 waits indefinitely",
+				Value: time.Minute * 5,
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "How often the condition is checked",
+				Value: time.Second,
+			},
+			&cli.IntFlag{
+				Name:  "replicas",
+				Usage: "Number of healthy replicas a service needs to be ready",
+				Value: 1,
+			},
+		},
+	})
+}
+
+// condition returns true once it's met. An error which isn't fatal is retried until the
+// timeout and reported if it expires.
+type condition func() (bool, error)
+
+// fatal is an error which means the condition can't be met
+type fatal struct {
+	error
+}
+
+func wait(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return cli.Exit("Required usage: micro wait <resource>/<name> [--for=condition]", ExitError)
+	}
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return cli.Exit(err.Error(), ExitError)
+	}
+
+	cond, err := parse(ns, ctx.Args().First(), ctx.String("for"), ctx.Int("replicas"))
+	if err != nil {
+		return cli.Exit(err.Error(), ExitError)
+	}
+
+	return poll(cond, ctx.Duration("interval"), ctx.Duration("timeout"))
+}
+
+// poll the condition until it's met, returning an error with the exit code if it isn't
+func poll(cond condition, interval, timeout time.Duration) error {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		deadline = t.C
+	}
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		ok, err := cond()
+		if ok {
+			return nil
+		}
+		if f, isFatal := err.(fatal); isFatal {
+			return cli.Exit(f.Error(), ExitError)
+		}
+
+		select {
+		case <-deadline:
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Timed out after %v: %v", timeout, err), ExitTimeout)
+			}
+			return cli.Exit(fmt.Sprintf("Timed out after %v", timeout), ExitTimeout)
+		case <-tick.C:
+		}
+	}
+}
+
+// parse the resource and condition
+func parse(ns, resource, cond string, replicas int) (condition, error) {
+	parts := strings.SplitN(resource, "/", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("Invalid resource %v, expected <resource>/<name>", resource)
+	}
+	kind, name := parts[0], parts[1]
+
+	switch kind {
+	case "service":
+		switch cond {
+		case "", "ready":
+			return serviceReady(ns, name, replicas), nil
+		case "registered":
+			return serviceRegistered(ns, name), nil
+		case "deleted":
+			return serviceDeleted(ns, name), nil
+		}
+	case "route":
+		switch cond {
+		case "", "exists":
+			return routeExists(ns, name, true), nil
+		case "deleted":
+			return routeExists(ns, name, false), nil
+		}
+	case "config":
+		switch {
+		case cond == "" || cond == "exists":
+			return configValue(ns, name, func(v []byte) bool { return v != nil }), nil
+		case cond == "deleted":
+			return configValue(ns, name, func(v []byte) bool { return v == nil }), nil
+		case strings.HasPrefix(cond, "value="):
+			want, err := compactJSON([]byte(strings.TrimPrefix(cond, "value=")))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value, expected JSON: %v", err)
+			}
+			return configValue(ns, name, func(v []byte) bool {
+				got, err := compactJSON(v)
+				return err == nil && bytes.Equal(got, want)
+			}), nil
+		}
+	case "consumer":
+		comps := strings.SplitN(name, "/", 2)
+		if len(comps) != 2 || len(comps[0]) == 0 || len(comps[1]) == 0 {
+			return nil, fmt.Errorf("Invalid consumer %v, expected consumer/<group>/<topic>", name)
+		}
+		switch {
+		case cond == "" || cond == "caught-up":
+			return consumerCaughtUp(ns, comps[0], comps[1], 0), nil
+		case strings.HasPrefix(cond, "sequence="):
+			seq, err := strconv.ParseUint(strings.TrimPrefix(cond, "sequence="), 10, 64)
+			if err != nil || seq == 0 {
+				return nil, fmt.Errorf("Invalid sequence, expected a positive number")
+			}
+			return consumerCaughtUp(ns, comps[0], comps[1], seq), nil
+		}
+	default:
+		return nil, fmt.Errorf("Unknown resource %v, expected service, route, config or consumer", kind)
+	}
+
+	return nil, fmt.Errorf("Unknown condition %v for %v", cond, kind)
+}
+
+// serviceRegistered waits for the service to have a node in the registry
+func serviceRegistered(ns, name string) condition {
+	return func() (bool, error) {
+		srvs, err := registry.GetService(name, goregistry.GetDomain(ns))
+		if err != nil {
+			return false, err
+		}
+		for _, s := range srvs {
+			if len(s.Nodes) > 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// serviceDeleted waits for the service to be removed from the runtime and registry
+func serviceDeleted(ns, name string) condition {
+	return func() (bool, error) {
+		srvs, err := runtime.Read(goruntime.ReadService(name), goruntime.ReadNamespace(ns))
+		if err != nil {
+			return false, err
+		}
+		if len(srvs) > 0 {
+			return false, nil
+		}
+		_, err = registry.GetService(name, goregistry.GetDomain(ns))
+		if rutil.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+// serviceReady waits for the number of replicas of the service to pass their health checks.
+// A service which has errored in the runtime can't become ready.
+func serviceReady(ns, name string, replicas int) condition {
+	return func() (bool, error) {
+		if srvs, err := runtime.Read(goruntime.ReadService(name), goruntime.ReadNamespace(ns)); err == nil {
+			for _, s := range srvs {
+				if s.Metadata["status"] == "error" {
+					return false, fatal{fmt.Errorf("Service %v failed: %v", name, s.Metadata["error"])}
+				}
+			}
+		}
+
+		srvs, err := registry.GetService(name, goregistry.GetDomain(ns))
+		if err != nil {
+			return false, err
+		}
+
+		var healthy int
+		var lastErr error
+		for _, s := range srvs {
+			for _, n := range s.Nodes {
+				if err := health(name, n.Address); err != nil {
+					lastErr = err
+					continue
+				}
+				healthy++
+			}
+		}
+		if healthy >= replicas {
+			return true, nil
+		}
+		if lastErr != nil {
+			return false, fmt.Errorf("%d of %d replicas healthy: %v", healthy, replicas, lastErr)
+		}
+		return false, fmt.Errorf("%d of %d replicas healthy", healthy, replicas)
+	}
+}
+
+// health checks the node responds to a health check
+func health(service, address string) error {
+	ctx, cancel := context.WithTimeout(mucontext.DefaultContext, time.Second*5)
+	defer cancel()
+
+	req := client.NewRequest(service, "Debug.Health", &debug.HealthRequest{})
+	rsp := &debug.HealthResponse{}
+	if err := client.Call(ctx, req, rsp, goclient.WithAddress(address), goclient.WithAuthToken()); err != nil {
+		return err
+	}
+	if rsp.Status != "ok" {
+		return fmt.Errorf("status %v", rsp.Status)
+	}
+	return nil
+}
+
+// routeExists waits for the router to have, or not have, a route to the service in the namespace
+func routeExists(ns, service string, exists bool) condition {
+	return func() (bool, error) {
+		routes, err := router.DefaultRouter.Lookup(service, gorouter.LookupNetwork(ns))
+		if err == gorouter.ErrRouteNotFound {
+			return !exists, nil
+		} else if err != nil {
+			return false, err
+		}
+		return (len(routes) > 0) == exists, nil
+	}
+}
+
+// configValue waits for the value at the path to match, the value is nil if it isn't set
+func configValue(ns, path string, match func([]byte) bool) condition {
+	return func() (bool, error) {
+		pb := config.NewConfigService("config", client.DefaultClient)
+		rsp, err := pb.Read(mucontext.DefaultContext, &config.ReadRequest{
+			Namespace: ns,
+			Path:      path,
+		}, goclient.WithAuthToken())
+		if verr := errors.Parse(err); verr != nil && verr.Code == 404 {
+			return match(nil), nil
+		} else if err != nil {
+			return false, err
+		}
+
+		var v []byte
+		if rsp.Change != nil && rsp.Change.ChangeSet != nil {
+			if d := rsp.Change.ChangeSet.Data; len(d) > 0 && d != "null" {
+				v = []byte(d)
+			}
+		}
+		return match(v), nil
+	}
+}
+
+// consumerCaughtUp waits for the consumer group to have processed the event with the sequence
+// in the topic. If the sequence is zero, the latest sequence of the topic when the condition is
+// first checked is waited for.
+func consumerCaughtUp(ns, group, topic string, seq uint64) condition {
+	return func() (bool, error) {
+		if seq == 0 {
+			latest, err := events.TopicSequence(topic, gostore.ReadFrom(ns, events.DefaultSequenceTable))
+			if err != nil {
+				return false, err
+			}
+			if latest == 0 {
+				// nothing has been published so there's nothing to catch up with
+				return true, nil
+			}
+			seq = latest
+		}
+
+		processed, err := events.LatestSequence(group, topic, gostore.ReadFrom(ns, events.DefaultHistoryTable))
+		if err == gostore.ErrNotFound {
+			return false, fmt.Errorf("No history recorded for group %v on topic %v", group, topic)
+		} else if err != nil {
+			return false, err
+		}
+		if processed >= seq {
+			return true, nil
+		}
+		return false, fmt.Errorf("Processed up to sequence %v of %v", processed, seq)
+	}
+}
+
+func compactJSON(v []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
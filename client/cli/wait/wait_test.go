@@ -0,0 +1,69 @@
+package wait
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/cli/v2"
+)
+
+func TestParse(t *testing.T) {
+	valid := [][]string{
+		{"service/foo", ""},
+		{"service/foo", "registered"},
+		{"service/foo", "deleted"},
+		{"route/foo", "exists"},
+		{"config/foo.bar", "value={\"a\": 1}"},
+		{"consumer/billing/orders", ""},
+		{"consumer/billing/orders", "sequence=10"},
+	}
+	for _, v := range valid {
+		if _, err := parse("micro", v[0], v[1], 1); err != nil {
+			t.Errorf("Unexpected error parsing %v --for=%v: %v", v[0], v[1], err)
+		}
+	}
+
+	invalid := [][]string{
+		{"service", ""},
+		{"service/", ""},
+		{"foo/bar", ""},
+		{"route/foo", "ready"},
+		{"config/foo", "value={"},
+		{"consumer/billing", ""},
+		{"consumer/billing/orders", "sequence=x"},
+	}
+	for _, v := range invalid {
+		if _, err := parse("micro", v[0], v[1], 1); err == nil {
+			t.Errorf("Expected an error parsing %v --for=%v", v[0], v[1])
+		}
+	}
+}
+
+func TestPoll(t *testing.T) {
+	exitCode := func(err error) int {
+		if err == nil {
+			return 0
+		}
+		return err.(cli.ExitCoder).ExitCode()
+	}
+
+	var checks int
+	met := func() (bool, error) {
+		checks++
+		return checks == 3, errors.New("not yet")
+	}
+	if code := exitCode(poll(met, time.Millisecond, time.Second)); code != 0 || checks != 3 {
+		t.Errorf("Expected the condition to be met on the third check, got exit code %v after %v checks", code, checks)
+	}
+
+	never := func() (bool, error) { return false, nil }
+	if code := exitCode(poll(never, time.Millisecond, time.Millisecond*10)); code != ExitTimeout {
+		t.Errorf("Expected a timeout, got exit code %v", code)
+	}
+
+	failed := func() (bool, error) { return false, fatal{errors.New("crashed")} }
+	if code := exitCode(poll(failed, time.Millisecond, time.Second)); code != ExitError {
+		t.Errorf("Expected an error, got exit code %v", code)
+	}
+}
@@ -0,0 +1,458 @@
+// Package why implements the micro why command, which works through the usual triage
+// checklist for a call which is failing and prints the most likely cause
+package why
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gorouter "github.com/micro/go-micro/v3/router"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/config"
+	"github.com/micro/micro/v3/service/auth"
+	authpb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	configpb "github.com/micro/micro/v3/service/config/proto"
+	mucontext "github.com/micro/micro/v3/service/context"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	rutil "github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:  "why",
+		Usage: "Find the most likely cause of a failing call",
+		Description: `Why checks the runtime status, registration, routes, auth rules, health, recent errors
+and config of the service called and prints the most likely cause of the call failing. e.g.
+
+	micro why users
+	micro why users.Users.Read`,
+		ArgsUsage: "<service>[.<endpoint>]",
+		Action:    why,
+	})
+}
+
+// status of a check
+type status int
+
+const (
+	statusOK status = iota
+	statusWarn
+	statusFail
+	statusSkip
+)
+
+func (s status) String() string {
+	switch s {
+	case statusOK:
+		return "OK"
+	case statusWarn:
+		return "WARN"
+	case statusFail:
+		return "FAIL"
+	default:
+		return "SKIP"
+	}
+}
+
+// result of a check
+type result struct {
+	Check  string
+	Status status
+	Detail string
+}
+
+// facts gathered about the target of the call, errors are those returned gathering them
+type facts struct {
+	Namespace string
+	Service   string
+	Endpoint  string
+	// Account making the call, blank if not logged in
+	Account string
+
+	Runtime    []*goruntime.Service
+	RuntimeErr error
+
+	Services    []*goregistry.Service
+	RegistryErr error
+
+	Routes   []gorouter.Route
+	RouteErr error
+
+	Access    []*authpb.MatrixEntry
+	AccessErr error
+
+	// Health and Stats of each node, keyed by node id
+	Health map[string]error
+	Stats  map[string]*debug.StatsResponse
+
+	ConfigSet bool
+	ConfigErr error
+}
+
+func why(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return cli.Exit("Required usage: micro why <service>[.<endpoint>]", 1)
+	}
+	env := util.GetEnv(ctx)
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return err
+	}
+
+	service, endpoint := parseTarget(ctx.Args().First())
+
+	// calls are made as the account logged in, or publicly if there's none
+	var account string
+	if token, err := config.Get("micro", "auth", env.Name, "token"); err == nil && len(token) > 0 {
+		if acc, err := auth.Inspect(token); err == nil {
+			account = acc.ID
+		}
+	}
+
+	f := gather(ns, service, endpoint, account)
+	report(os.Stdout, diagnose(f))
+	return nil
+}
+
+// parseTarget splits the target into the service and the endpoint, e.g. users.Users.Read is
+// the Users.Read endpoint of the users service
+func parseTarget(target string) (string, string) {
+	parts := strings.SplitN(target, ".", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// gather the facts about the target
+func gather(ns, service, endpoint, account string) *facts {
+	f := &facts{
+		Namespace: ns,
+		Service:   service,
+		Endpoint:  endpoint,
+		Account:   account,
+		Health:    make(map[string]error),
+		Stats:     make(map[string]*debug.StatsResponse),
+	}
+
+	f.Runtime, f.RuntimeErr = runtime.Read(goruntime.ReadService(service), goruntime.ReadNamespace(ns))
+	f.Services, f.RegistryErr = registry.GetService(service, goregistry.GetDomain(ns))
+	f.Routes, f.RouteErr = router.DefaultRouter.Lookup(service, gorouter.LookupNetwork(ns))
+
+	rules := authpb.NewRulesService("auth", client.DefaultClient)
+	if rsp, err := rules.Matrix(mucontext.DefaultContext, &authpb.MatrixRequest{
+		Options:  &authpb.Options{Namespace: ns},
+		Services: []string{service},
+	}, goclient.WithAuthToken()); err != nil {
+		f.AccessErr = err
+	} else {
+		f.Access = rsp.Entries
+	}
+
+	for _, s := range f.Services {
+		for _, n := range s.Nodes {
+			f.Health[n.Id] = health(service, n.Address)
+			if st, err := stats(service, n.Address); err == nil {
+				f.Stats[n.Id] = st
+			}
+		}
+	}
+
+	cfg := configpb.NewConfigService("config", client.DefaultClient)
+	rsp, err := cfg.Read(mucontext.DefaultContext, &configpb.ReadRequest{
+		Namespace: ns,
+		Path:      service,
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil && verr.Code == 404 {
+		f.ConfigSet = false
+	} else if err != nil {
+		f.ConfigErr = err
+	} else if rsp.Change != nil && rsp.Change.ChangeSet != nil {
+		d := rsp.Change.ChangeSet.Data
+		f.ConfigSet = len(d) > 0 && d != "null" && d != "{}"
+	}
+
+	return f
+}
+
+// health checks the node responds to a health check
+func health(service, address string) error {
+	ctx, cancel := context.WithTimeout(mucontext.DefaultContext, time.Second*5)
+	defer cancel()
+
+	req := client.NewRequest(service, "Debug.Health", &debug.HealthRequest{})
+	rsp := &debug.HealthResponse{}
+	if err := client.Call(ctx, req, rsp, goclient.WithAddress(address), goclient.WithAuthToken()); err != nil {
+		return err
+	}
+	if rsp.Status != "ok" {
+		return fmt.Errorf("status %v", rsp.Status)
+	}
+	return nil
+}
+
+// stats returns the stats of the node
+func stats(service, address string) (*debug.StatsResponse, error) {
+	ctx, cancel := context.WithTimeout(mucontext.DefaultContext, time.Second*5)
+	defer cancel()
+
+	req := client.NewRequest(service, "Debug.Stats", &debug.StatsRequest{})
+	rsp := &debug.StatsResponse{}
+	if err := client.Call(ctx, req, rsp, goclient.WithAddress(address), goclient.WithAuthToken()); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// diagnose the facts, returning the result of each check in the order the causes are most
+// likely. Checks which need facts that couldn't be gathered are skipped.
+func diagnose(f *facts) []*result {
+	return []*result{
+		checkRuntime(f),
+		checkRegistry(f),
+		checkEndpoint(f),
+		checkRoutes(f),
+		checkAccess(f),
+		checkHealth(f),
+		checkErrors(f),
+		checkConfig(f),
+	}
+}
+
+func checkRuntime(f *facts) *result {
+	r := &result{Check: "runtime"}
+	switch {
+	case f.RuntimeErr != nil:
+		r.Status, r.Detail = statusSkip, fmt.Sprintf("unable to read the runtime: %v", f.RuntimeErr)
+	case len(f.Runtime) == 0:
+		// services run outside the runtime, e.g. locally, are only registered
+		r.Status, r.Detail = statusWarn, fmt.Sprintf("service %v is not running in the runtime in namespace %v", f.Service, f.Namespace)
+	default:
+		r.Status, r.Detail = statusOK, "running"
+		for _, s := range f.Runtime {
+			if s.Metadata["status"] == "error" {
+				r.Status = statusFail
+				r.Detail = fmt.Sprintf("service %v failed in namespace %v: %v", f.Service, f.Namespace, s.Metadata["error"])
+				break
+			}
+			if st := s.Metadata["status"]; st != "running" && len(st) > 0 {
+				r.Status, r.Detail = statusWarn, fmt.Sprintf("service %v is %v", f.Service, st)
+			}
+		}
+	}
+	return r
+}
+
+func checkRegistry(f *facts) *result {
+	r := &result{Check: "registry"}
+	switch {
+	case f.RegistryErr == rutil.ErrGone:
+		r.Status, r.Detail = statusFail, fmt.Sprintf("service %v was deregistered recently from namespace %v", f.Service, f.Namespace)
+	case f.RegistryErr == goregistry.ErrNotFound || (f.RegistryErr == nil && nodes(f.Services) == 0):
+		r.Status, r.Detail = statusFail, fmt.Sprintf("service %v is not registered in namespace %v", f.Service, f.Namespace)
+	case f.RegistryErr != nil:
+		r.Status, r.Detail = statusSkip, fmt.Sprintf("unable to read the registry: %v", f.RegistryErr)
+	default:
+		r.Status, r.Detail = statusOK, fmt.Sprintf("%d nodes registered", nodes(f.Services))
+	}
+	return r
+}
+
+func checkEndpoint(f *facts) *result {
+	r := &result{Check: "endpoint"}
+	if len(f.Endpoint) == 0 || nodes(f.Services) == 0 {
+		r.Status = statusSkip
+		return r
+	}
+
+	var names []string
+	for _, s := range f.Services {
+		for _, e := range s.Endpoints {
+			if e.Name == f.Endpoint {
+				r.Status, r.Detail = statusOK, "registered"
+				return r
+			}
+			names = append(names, e.Name)
+		}
+	}
+	if len(names) == 0 {
+		// services don't have to register their endpoints
+		r.Status, r.Detail = statusWarn, fmt.Sprintf("service %v doesn't register its endpoints", f.Service)
+		return r
+	}
+	r.Status = statusFail
+	r.Detail = fmt.Sprintf("endpoint %v is not registered by %v, it has %v", f.Endpoint, f.Service, strings.Join(names, ", "))
+	return r
+}
+
+func checkRoutes(f *facts) *result {
+	r := &result{Check: "routes"}
+	switch {
+	case f.RouteErr == gorouter.ErrRouteNotFound || (f.RouteErr == nil && len(f.Routes) == 0):
+		r.Status, r.Detail = statusFail, fmt.Sprintf("no routes to %v in namespace %v", f.Service, f.Namespace)
+	case f.RouteErr != nil:
+		r.Status, r.Detail = statusSkip, fmt.Sprintf("unable to lookup routes: %v", f.RouteErr)
+	default:
+		r.Status, r.Detail = statusOK, fmt.Sprintf("%d routes", len(f.Routes))
+	}
+	return r
+}
+
+func checkAccess(f *facts) *result {
+	r := &result{Check: "auth"}
+	if f.AccessErr != nil {
+		r.Status, r.Detail = statusSkip, fmt.Sprintf("unable to evaluate the rules: %v", f.AccessErr)
+		return r
+	}
+
+	identity := f.Account
+	if len(identity) == 0 {
+		identity = "the public"
+	}
+
+	var entry *authpb.MatrixEntry
+	for _, e := range f.Access {
+		if e.Identity != f.Account {
+			continue
+		}
+		if len(f.Endpoint) == 0 || e.Endpoint == f.Endpoint || e.Endpoint == "*" {
+			entry = e
+			if !e.Granted {
+				break
+			}
+		}
+	}
+
+	switch {
+	case entry == nil:
+		r.Status, r.Detail = statusSkip, fmt.Sprintf("no access was evaluated for %v", identity)
+	case entry.Granted:
+		r.Status, r.Detail = statusOK, fmt.Sprintf("rule %v grants %v access", entry.Rule, identity)
+	case len(entry.Rule) == 0:
+		r.Status, r.Detail = statusFail, fmt.Sprintf("no rule grants %v access to %v %v", identity, entry.Service, entry.Endpoint)
+	case len(entry.Scopes) > 0:
+		r.Status, r.Detail = statusFail, fmt.Sprintf("rule %v denies scope %v", entry.Rule, strings.Join(entry.Scopes, ", "))
+	default:
+		r.Status, r.Detail = statusFail, fmt.Sprintf("rule %v denies %v", entry.Rule, identity)
+	}
+	return r
+}
+
+func checkHealth(f *facts) *result {
+	r := &result{Check: "health"}
+	if len(f.Health) == 0 {
+		r.Status = statusSkip
+		return r
+	}
+
+	var unhealthy []string
+	for id, err := range f.Health {
+		if err != nil {
+			unhealthy = append(unhealthy, fmt.Sprintf("%v: %v", id, err))
+		}
+	}
+	switch {
+	case len(unhealthy) == len(f.Health):
+		r.Status, r.Detail = statusFail, fmt.Sprintf("no healthy nodes, %v", strings.Join(unhealthy, "; "))
+	case len(unhealthy) > 0:
+		r.Status, r.Detail = statusWarn, fmt.Sprintf("%d of %d nodes unhealthy, %v", len(unhealthy), len(f.Health), strings.Join(unhealthy, "; "))
+	default:
+		r.Status, r.Detail = statusOK, fmt.Sprintf("%d nodes healthy", len(f.Health))
+	}
+	return r
+}
+
+func checkErrors(f *facts) *result {
+	r := &result{Check: "errors"}
+	if len(f.Stats) == 0 {
+		r.Status = statusSkip
+		return r
+	}
+
+	var requests, errs uint64
+	for _, s := range f.Stats {
+		if s != nil {
+			requests += s.Requests
+			errs += s.Errors
+		}
+	}
+	switch {
+	case requests == 0:
+		r.Status, r.Detail = statusOK, "no requests handled"
+	case errs*2 > requests:
+		r.Status, r.Detail = statusFail, fmt.Sprintf("%d of %d requests errored, check micro logs %v", errs, requests, f.Service)
+	case errs > 0:
+		r.Status, r.Detail = statusWarn, fmt.Sprintf("%d of %d requests errored", errs, requests)
+	default:
+		r.Status, r.Detail = statusOK, fmt.Sprintf("%d requests handled without errors", requests)
+	}
+	return r
+}
+
+func checkConfig(f *facts) *result {
+	r := &result{Check: "config"}
+	switch {
+	case f.ConfigErr != nil:
+		r.Status, r.Detail = statusSkip, fmt.Sprintf("unable to read config: %v", f.ConfigErr)
+	case !f.ConfigSet:
+		// not every service is configured, so missing config isn't a cause on its own
+		r.Status, r.Detail = statusOK, fmt.Sprintf("no config is set at %v in namespace %v", f.Service, f.Namespace)
+	default:
+		r.Status, r.Detail = statusOK, "set"
+	}
+	return r
+}
+
+// cause returns the most likely cause of the failure, the first check which failed or else the
+// first warning. Nil if every check passed.
+func cause(results []*result) *result {
+	for _, s := range []status{statusFail, statusWarn} {
+		for _, r := range results {
+			if r.Status == s {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// report the results and the most likely cause
+func report(w io.Writer, results []*result) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", r.Status, r.Check, r.Detail)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	if c := cause(results); c != nil {
+		fmt.Fprintf(w, "Most likely cause: %v\n", c.Detail)
+	} else {
+		fmt.Fprintln(w, "No problems found, the handler itself may be returning the error")
+	}
+}
+
+// nodes returns the number of nodes of the services
+func nodes(srvs []*goregistry.Service) int {
+	var n int
+	for _, s := range srvs {
+		n += len(s.Nodes)
+	}
+	return n
+}
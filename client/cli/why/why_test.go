@@ -0,0 +1,109 @@
+package why
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gorouter "github.com/micro/go-micro/v3/router"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	authpb "github.com/micro/micro/v3/service/auth/proto"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+)
+
+func TestParseTarget(t *testing.T) {
+	if s, e := parseTarget("users.Users.Read"); s != "users" || e != "Users.Read" {
+		t.Errorf("Expected users Users.Read, got %v %v", s, e)
+	}
+	if s, e := parseTarget("users"); s != "users" || e != "" {
+		t.Errorf("Expected users without an endpoint, got %v %v", s, e)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	healthy := func() *facts {
+		return &facts{
+			Namespace: "staging",
+			Service:   "users",
+			Endpoint:  "Users.Read",
+			Account:   "alice",
+			Runtime:   []*goruntime.Service{{Name: "users", Metadata: map[string]string{"status": "running"}}},
+			Services: []*goregistry.Service{{
+				Name:      "users",
+				Nodes:     []*goregistry.Node{{Id: "users-1"}},
+				Endpoints: []*goregistry.Endpoint{{Name: "Users.Read"}},
+			}},
+			Routes: []gorouter.Route{{Service: "users"}},
+			Access: []*authpb.MatrixEntry{
+				{Identity: "alice", Scopes: []string{"customer"}, Service: "users", Endpoint: "Users.Read", Granted: true, Rule: "default"},
+			},
+			Health:    map[string]error{"users-1": nil},
+			Stats:     map[string]*debug.StatsResponse{"users-1": {Requests: 10}},
+			ConfigSet: true,
+		}
+	}
+
+	tt := []struct {
+		Name   string
+		Modify func(f *facts)
+		Cause  string
+	}{
+		{"Healthy", func(f *facts) {}, ""},
+		{"Failed", func(f *facts) {
+			f.Runtime[0].Metadata = map[string]string{"status": "error", "error": "exit status 1"}
+		}, "service users failed in namespace staging: exit status 1"},
+		{"NotRegistered", func(f *facts) {
+			f.Services, f.RegistryErr = nil, goregistry.ErrNotFound
+			f.Health, f.Stats = nil, nil
+		}, "service users is not registered in namespace staging"},
+		{"NoEndpoint", func(f *facts) {
+			f.Endpoint = "Users.Write"
+		}, "endpoint Users.Write is not registered by users, it has Users.Read"},
+		{"NoRoutes", func(f *facts) {
+			f.Routes, f.RouteErr = nil, gorouter.ErrRouteNotFound
+		}, "no routes to users in namespace staging"},
+		{"Denied", func(f *facts) {
+			f.Access[0].Granted, f.Access[0].Rule = false, "deny-customers"
+		}, "rule deny-customers denies scope customer"},
+		{"Unhealthy", func(f *facts) {
+			f.Health["users-1"] = errors.New("status unhealthy")
+		}, "no healthy nodes, users-1: status unhealthy"},
+		{"Errors", func(f *facts) {
+			f.Stats["users-1"].Errors = 8
+		}, "8 of 10 requests errored, check micro logs users"},
+		{"AuthUnavailable", func(f *facts) {
+			f.AccessErr = errors.New("forbidden")
+		}, ""},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			f := healthy()
+			tc.Modify(f)
+
+			c := cause(diagnose(f))
+			if len(tc.Cause) == 0 {
+				if c != nil {
+					t.Errorf("Expected no cause, got %v", c.Detail)
+				}
+				return
+			}
+			if c == nil || c.Detail != tc.Cause {
+				t.Errorf("Expected cause %q, got %v", tc.Cause, c)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	report(&buf, []*result{
+		{Check: "registry", Status: statusOK, Detail: "1 nodes registered"},
+		{Check: "routes", Status: statusFail, Detail: "no routes to users in namespace staging"},
+	})
+	if !strings.Contains(buf.String(), "Most likely cause: no routes to users in namespace staging") {
+		t.Errorf("Expected the cause to be reported, got %v", buf.String())
+	}
+}
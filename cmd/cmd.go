@@ -34,13 +34,46 @@ import (
 	configCli "github.com/micro/micro/v3/service/config/client"
 
 	muauth "github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/auth/classify"
+	authClient "github.com/micro/micro/v3/service/auth/client"
+	"github.com/micro/micro/v3/service/auth/federation"
 	mubroker "github.com/micro/micro/v3/service/broker"
 	muclient "github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/client/balance"
+	"github.com/micro/micro/v3/service/client/breaker"
+	"github.com/micro/micro/v3/service/client/cache"
+	"github.com/micro/micro/v3/service/client/compat"
+	"github.com/micro/micro/v3/service/client/compress"
+	"github.com/micro/micro/v3/service/client/deadline"
+	"github.com/micro/micro/v3/service/client/failover"
+	"github.com/micro/micro/v3/service/client/hedge"
+	"github.com/micro/micro/v3/service/client/limit"
+	"github.com/micro/micro/v3/service/client/negotiate"
+	"github.com/micro/micro/v3/service/client/passthrough"
+	"github.com/micro/micro/v3/service/client/pool"
+	"github.com/micro/micro/v3/service/client/profiles"
+	"github.com/micro/micro/v3/service/client/resume"
+	"github.com/micro/micro/v3/service/client/retry"
+	"github.com/micro/micro/v3/service/client/static"
 	muconfig "github.com/micro/micro/v3/service/config"
 	muregistry "github.com/micro/micro/v3/service/registry"
+	regcache "github.com/micro/micro/v3/service/registry/cache"
+	murouter "github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/router/pin"
 	muruntime "github.com/micro/micro/v3/service/runtime"
 	muserver "github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/service/server/annotations"
+	"github.com/micro/micro/v3/service/server/concurrency"
+	"github.com/micro/micro/v3/service/server/dedupe"
+	"github.com/micro/micro/v3/service/server/drain"
+	"github.com/micro/micro/v3/service/server/flow"
+	slimit "github.com/micro/micro/v3/service/server/limit"
+	"github.com/micro/micro/v3/service/server/recovery"
+	"github.com/micro/micro/v3/service/server/shed"
+	"github.com/micro/micro/v3/service/server/validate"
 	mustore "github.com/micro/micro/v3/service/store"
+	storeClient "github.com/micro/micro/v3/service/store/client"
+	"github.com/micro/micro/v3/service/store/shard"
 )
 
 type Cmd interface {
@@ -125,6 +158,11 @@ var (
 			EnvVars: []string{"MICRO_AUTH_PRIVATE_KEY"},
 			Usage:   "Private key for JWT auth (base64 encoded PEM)",
 		},
+		&cli.StringFlag{
+			Name:    "auth_federation",
+			EnvVars: []string{"MICRO_AUTH_FEDERATION"},
+			Usage:   "Path to a JSON file listing the peer clusters whose tokens are trusted",
+		},
 		&cli.StringFlag{
 			Name:    "registry_address",
 			EnvVars: []string{"MICRO_REGISTRY_ADDRESS"},
@@ -185,6 +223,11 @@ var (
 			EnvVars: []string{"MICRO_STORE_ADDRESS"},
 			Usage:   "Comma-separated list of store addresses",
 		},
+		&cli.StringFlag{
+			Name:    "store_shards",
+			EnvVars: []string{"MICRO_STORE_SHARDS"},
+			Usage:   "Comma-separated list of store shards to partition keys across, e.g. a=10.0.0.1:8002,b=10.0.0.2:8002",
+		},
 		&cli.StringFlag{
 			Name:    "proxy_address",
 			Usage:   "Proxy requests via the HTTP address specified",
@@ -196,6 +239,158 @@ var (
 			EnvVars: []string{"MICRO_REPORT_USAGE"},
 			Value:   true,
 		},
+		&cli.StringFlag{
+			Name:    "failover_address",
+			Usage:   "Address of a secondary region's proxy to fail calls over to",
+			EnvVars: []string{"MICRO_FAILOVER_ADDRESS"},
+		},
+		&cli.StringFlag{
+			Name:    "failover_services",
+			Usage:   "Comma-separated list of services which can fail over to the secondary region",
+			EnvVars: []string{"MICRO_FAILOVER_SERVICES"},
+		},
+		&cli.BoolFlag{
+			Name:    "circuit_breaker",
+			Usage:   "Stop calling endpoints which are failing until they recover",
+			EnvVars: []string{"MICRO_CIRCUIT_BREAKER"},
+			Value:   true,
+		},
+		&cli.IntFlag{
+			Name:    "client_pool_size",
+			Usage:   "Number of connections kept open to each node, zero for the client default",
+			EnvVars: []string{"MICRO_CLIENT_POOL_SIZE"},
+		},
+		&cli.DurationFlag{
+			Name:    "client_pool_ttl",
+			Usage:   "How long pooled connections are kept open, zero for the client default",
+			EnvVars: []string{"MICRO_CLIENT_POOL_TTL"},
+		},
+		&cli.IntFlag{
+			Name:    "client_pool_max_streams",
+			Usage:   "Number of concurrent streams on each pooled connection, zero for the client default",
+			EnvVars: []string{"MICRO_CLIENT_POOL_MAX_STREAMS"},
+		},
+		&cli.IntFlag{
+			Name:    "client_cache_size",
+			Usage:   "Number of responses the client caches, zero for no limit",
+			EnvVars: []string{"MICRO_CLIENT_CACHE_SIZE"},
+			Value:   cache.DefaultMaxSize,
+		},
+		&cli.IntFlag{
+			Name:    "client_max_send_size",
+			Usage:   "Maximum size in bytes of the messages the client sends, zero for the client default",
+			EnvVars: []string{"MICRO_CLIENT_MAX_SEND_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "client_max_recv_size",
+			Usage:   "Maximum size in bytes of the messages the client receives, zero for the client default",
+			EnvVars: []string{"MICRO_CLIENT_MAX_RECV_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "client_stream_rate",
+			Usage:   "Maximum bytes per second sent and received on each stream, zero for no limit",
+			EnvVars: []string{"MICRO_CLIENT_STREAM_RATE"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "client_static_addresses",
+			Usage:   "Addresses to call services at if their nodes can't be looked up, e.g. auth=10.0.0.5:8010",
+			EnvVars: []string{"MICRO_CLIENT_STATIC_ADDRESSES"},
+		},
+		&cli.IntFlag{
+			Name:    "client_compress_threshold",
+			Usage:   "Size in bytes of the smallest request the client compresses, negative to disable compression",
+			EnvVars: []string{"MICRO_CLIENT_COMPRESS_THRESHOLD"},
+			Value:   compress.DefaultThreshold,
+		},
+		&cli.StringFlag{
+			Name:    "client_schema_check",
+			Usage:   "Check calls are compatible with the schemas of the services called: off, warn or fail",
+			EnvVars: []string{"MICRO_CLIENT_SCHEMA_CHECK"},
+			Value:   string(compat.DefaultMode),
+		},
+		&cli.DurationFlag{
+			Name:    "min_deadline_budget",
+			Usage:   "Fail calls and reject requests with less than this left before their deadline, zero to disable",
+			EnvVars: []string{"MICRO_MIN_DEADLINE_BUDGET"},
+		},
+		&cli.DurationFlag{
+			Name:    "dedupe_window",
+			Usage:   "How long the IDs of requests and messages handled are kept to deduplicate retries, e.g. 1h, disabled if not set",
+			EnvVars: []string{"MICRO_DEDUPE_WINDOW"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "dedupe_endpoints",
+			Usage:   "How long the responses of endpoints are kept to deduplicate retries, e.g. Orders.Create=24h, zero to disable",
+			EnvVars: []string{"MICRO_DEDUPE_ENDPOINTS"},
+		},
+		&cli.IntFlag{
+			Name:    "server_max_recv_size",
+			Usage:   "Maximum size in bytes of the requests and stream messages the server receives, zero for the server default",
+			EnvVars: []string{"MICRO_SERVER_MAX_RECV_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "server_max_send_size",
+			Usage:   "Maximum size in bytes of the responses and stream messages the server sends, zero for the server default",
+			EnvVars: []string{"MICRO_SERVER_MAX_SEND_SIZE"},
+		},
+		&cli.DurationFlag{
+			Name:    "server_read_timeout",
+			Usage:   "How long server streams wait for the client's next message, zero for no timeout",
+			EnvVars: []string{"MICRO_SERVER_READ_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "server_write_timeout",
+			Usage:   "How long handlers have to respond and server streams wait for the client to accept a message, zero for no timeout",
+			EnvVars: []string{"MICRO_SERVER_WRITE_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "server_handshake_timeout",
+			Usage:   "How long connections to the server have to complete their handshake, zero for the server default",
+			EnvVars: []string{"MICRO_SERVER_HANDSHAKE_TIMEOUT"},
+		},
+		&cli.IntFlag{
+			Name:    "server_max_concurrent",
+			Usage:   "Number of requests each endpoint handles at once, zero for no limit",
+			EnvVars: []string{"MICRO_SERVER_MAX_CONCURRENT"},
+		},
+		&cli.IntFlag{
+			Name:    "server_max_queue",
+			Usage:   "Number of requests to each endpoint waiting for a handler before they're rejected",
+			EnvVars: []string{"MICRO_SERVER_MAX_QUEUE"},
+		},
+		&cli.DurationFlag{
+			Name:    "server_shed_latency",
+			Usage:   "P99 latency above which the server sheds a share of requests, zero to disable",
+			EnvVars: []string{"MICRO_SERVER_SHED_LATENCY"},
+		},
+		&cli.Float64Flag{
+			Name:    "server_shed_cpu",
+			Usage:   "Share of the cpus used above which the server sheds a share of requests, e.g. 0.8, zero to disable",
+			EnvVars: []string{"MICRO_SERVER_SHED_CPU"},
+		},
+		&cli.IntFlag{
+			Name:    "server_stream_window",
+			Usage:   "Number of messages waiting to be sent on each server stream, zero to send each message as it's sent",
+			EnvVars: []string{"MICRO_SERVER_STREAM_WINDOW"},
+		},
+		&cli.StringFlag{
+			Name:    "server_stream_policy",
+			Usage:   "What sending on a server stream does when its window is full; {block, drop, error}",
+			EnvVars: []string{"MICRO_SERVER_STREAM_POLICY"},
+			Value:   flow.Block.String(),
+		},
+		&cli.BoolFlag{
+			Name:    "server_recover_panics",
+			Usage:   "Return an internal server error when a handler panics rather than crashing the server",
+			EnvVars: []string{"MICRO_SERVER_RECOVER_PANICS"},
+			Value:   true,
+		},
+		&cli.DurationFlag{
+			Name:    "server_drain_period",
+			Usage:   "How long the server keeps serving after deregistering on shutdown, so clients stop routing to it",
+			EnvVars: []string{"MICRO_SERVER_DRAIN_PERIOD"},
+			Value:   drain.DefaultGracePeriod,
+		},
 		&cli.StringFlag{
 			Name:    "service_name",
 			Usage:   "Name of the micro service",
@@ -303,26 +498,142 @@ func (c *command) Before(ctx *cli.Context) error {
 		muclient.DefaultClient.Init(client.Proxy(proxy))
 	}
 
-	// use the internal network lookup
+	// use the internal network lookup, falling back to the static addresses of services
+	// if it fails
+	addrs, err := static.ParseFlag(ctx.StringSlice("client_static_addresses"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	static.DefaultAddresses = addrs
 	muclient.DefaultClient.Init(
-		client.Lookup(network.Lookup),
+		client.Lookup(static.Lookup(network.Lookup, static.ConfigSource)),
 	)
 
+	// retry the errors classified as retryable, waiting as long as overloaded servers ask
+	retry.Register(shed.Classify)
+	muclient.DefaultClient.Init(client.Retry(retry.Func))
+
+	// size and instrument the connection pool
+	muclient.DefaultClient.Init(pool.Options(
+		ctx.Int("client_pool_size"),
+		ctx.Duration("client_pool_ttl"),
+		ctx.Int("client_pool_max_streams"),
+	)...)
+	muclient.DefaultClient.Init(pool.Instrument()...)
+
+	// bound the size of the messages the client transfers
+	muclient.DefaultClient.Init(limit.Options(
+		ctx.Int("client_max_send_size"),
+		ctx.Int("client_max_recv_size"),
+	)...)
+
 	// wrap the client
 	muclient.DefaultClient = wrapper.AuthClient(muclient.DefaultClient)
+	deadline.DefaultMinBudget = ctx.Duration("min_deadline_budget")
+	muclient.DefaultClient = deadline.NewClient(muclient.DefaultClient)
+	limit.DefaultStreamRate = ctx.Int("client_stream_rate")
+	muclient.DefaultClient = limit.NewClient(muclient.DefaultClient)
+	muclient.DefaultClient = profiles.NewClient(muclient.DefaultClient, profiles.ConfigSource)
+	muclient.DefaultClient = negotiate.NewClient(muclient.DefaultClient)
+	if compat.DefaultMode, err = compat.ParseMode(ctx.String("client_schema_check")); err != nil {
+		logger.Fatal(err)
+	}
+	muclient.DefaultClient = compat.NewClient(muclient.DefaultClient)
+	compress.DefaultThreshold = ctx.Int("client_compress_threshold")
+	muclient.DefaultClient = compress.NewClient(muclient.DefaultClient)
+	muclient.DefaultClient = balance.NewClient(muclient.DefaultClient)
+	muclient.DefaultClient = pin.NewClient(muclient.DefaultClient, murouter.DefaultRouter)
+	muclient.DefaultClient = passthrough.NewClient(muclient.DefaultClient, passthrough.ConfigSource)
+	muclient.DefaultClient = hedge.NewClient(muclient.DefaultClient)
+	muclient.DefaultClient = resume.NewClient(muclient.DefaultClient)
+	if ctx.Bool("circuit_breaker") {
+		muclient.DefaultClient = breaker.NewClient(muclient.DefaultClient)
+	}
+	if addr := ctx.String("failover_address"); len(addr) > 0 {
+		muclient.DefaultClient = failover.NewClient(muclient.DefaultClient,
+			failover.Address(addr),
+			failover.Services(strings.Split(ctx.String("failover_services"), ",")...),
+		)
+	}
+	cache.DefaultCache = cache.New(
+		cache.MaxSize(ctx.Int("client_cache_size")),
+		cache.WithSource(cache.ConfigSource),
+	)
 	muclient.DefaultClient = wrapper.CacheClient(muclient.DefaultClient)
+	muclient.DefaultClient = muclient.InterceptClient(muclient.DefaultClient)
 	muclient.DefaultClient = wrapper.TraceCall(muclient.DefaultClient)
 	muclient.DefaultClient = wrapper.FromService(muclient.DefaultClient)
 	muclient.DefaultClient = wrapper.LogClient(muclient.DefaultClient)
 
-	// wrap the server
+	// wrap the server, tracking the requests in flight so they're drained on shutdown
+	drain.DefaultDrainer = drain.New(ctx.Duration("server_drain_period"))
 	muserver.DefaultServer.Init(
+		server.WrapHandler(drain.DefaultDrainer.HandlerWrapper()),
 		server.WrapHandler(wrapper.AuthHandler()),
+		server.WrapHandler(deadline.HandlerWrapper(ctx.Duration("min_deadline_budget"))),
 		server.WrapHandler(wrapper.TraceHandler()),
+		server.WrapHandler(wrapper.LatencyHandler()),
 		server.WrapHandler(wrapper.HandlerStats()),
 		server.WrapHandler(wrapper.LogHandler()),
+		server.WrapHandler(annotations.HandlerWrapper()),
+		server.WrapHandler(validate.HandlerWrapper()),
+		server.WrapHandler(classify.HandlerWrapper(
+			regcache.New(muregistry.DefaultRegistry), classify.DefaultPolicy,
+			registry.GetDomain(ctx.String("namespace")),
+		)),
+		server.WrapHandler(slimit.HandlerWrapper(
+			slimit.MaxRecvSize(ctx.Int("server_max_recv_size")),
+			slimit.MaxSendSize(ctx.Int("server_max_send_size")),
+			slimit.ReadTimeout(ctx.Duration("server_read_timeout")),
+			slimit.WriteTimeout(ctx.Duration("server_write_timeout")),
+			slimit.WithSource(slimit.ConfigSource),
+		)),
 	)
 
+	// bound the size of the messages the transport accepts in either direction, the limits of
+	// endpoints can't exceed it
+	var maxMsgSize int
+	if recv, send := ctx.Int("server_max_recv_size"), ctx.Int("server_max_send_size"); recv > 0 && send > 0 {
+		maxMsgSize = recv
+		if send > recv {
+			maxMsgSize = send
+		}
+	}
+	muserver.DefaultServer.Init(slimit.ServerOptions(maxMsgSize, ctx.Duration("server_handshake_timeout"))...)
+	if n := ctx.Int("server_stream_window"); n > 0 {
+		policy, err := flow.ParsePolicy(ctx.String("server_stream_policy"))
+		if err != nil {
+			logger.Fatal(err)
+		}
+		muserver.DefaultServer.Init(server.WrapHandler(flow.HandlerWrapper(flow.Window(n), flow.OnFull(policy))))
+	}
+	if l, c := ctx.Duration("server_shed_latency"), ctx.Float64("server_shed_cpu"); l > 0 || c > 0 {
+		muserver.DefaultServer.Init(server.WrapHandler(shed.New(shed.MaxLatency(l), shed.MaxCPU(c)).HandlerWrapper()))
+	}
+	if n := ctx.Int("server_max_concurrent"); n > 0 {
+		muserver.DefaultServer.Init(server.WrapHandler(concurrency.HandlerWrapper(
+			concurrency.MaxConcurrent(n),
+			concurrency.MaxQueue(ctx.Int("server_max_queue")),
+		)))
+	}
+	endpoints, err := dedupe.ParseEndpoints(ctx.StringSlice("dedupe_endpoints"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if window := ctx.Duration("dedupe_window"); window > 0 || len(endpoints) > 0 {
+		muserver.DefaultServer.Init(server.WrapHandler(dedupe.HandlerWrapper(window, endpoints...)))
+	}
+	if window := ctx.Duration("dedupe_window"); window > 0 {
+		muserver.DefaultServer.Init(server.WrapSubscriber(dedupe.SubscriberWrapper(window)))
+	}
+	// recover from panics innermost, so the other wrappers see the error returned in its place
+	if ctx.Bool("server_recover_panics") {
+		muserver.DefaultServer.Init(
+			server.WrapHandler(recovery.HandlerWrapper()),
+			server.WrapSubscriber(recovery.SubscriberWrapper()),
+		)
+	}
+
 	// initialize the server with the namespace so it knows which domain to register in
 	muserver.DefaultServer.Init(server.Namespace(ctx.String("namespace")))
 
@@ -345,6 +656,13 @@ func (c *command) Before(ctx *cli.Context) error {
 	if len(ctx.String("auth_private_key")) > 0 {
 		authOpts = append(authOpts, auth.PrivateKey(ctx.String("auth_private_key")))
 	}
+	if path := ctx.String("auth_federation"); len(path) > 0 {
+		f, err := federation.Load(path)
+		if err != nil {
+			logger.Fatalf("Error loading federation peers: %v", err)
+		}
+		authOpts = append(authOpts, authClient.Federation(f))
+	}
 	muauth.DefaultAuth.Init(authOpts...)
 
 	// setup registry
@@ -416,7 +734,15 @@ func (c *command) Before(ctx *cli.Context) error {
 
 	// Setup store options
 	storeOpts := []store.Option{}
-	if len(ctx.String("store_address")) > 0 {
+	if len(ctx.String("store_shards")) > 0 {
+		// shards added by resharding are connected to when the ring recorded in the shards changes
+		dial := func(address string) store.Store { return storeClient.NewStore(store.Nodes(address)) }
+		shards, err := shard.ParseShards(ctx.String("store_shards"), dial)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		mustore.DefaultStore = shard.NewStore(shards, shard.Dial(dial))
+	} else if len(ctx.String("store_address")) > 0 {
 		storeOpts = append(storeOpts, store.Nodes(strings.Split(ctx.String("store_address"), ",")...))
 	}
 	if len(ctx.String("namespace")) > 0 {
@@ -436,12 +762,11 @@ func (c *command) Before(ctx *cli.Context) error {
 	)
 	muserver.DefaultServer.Init(
 		server.Broker(mubroker.DefaultBroker),
-		server.Registry(muregistry.DefaultRegistry),
+		server.Registry(drain.DefaultDrainer.Registry(muregistry.DefaultRegistry)),
 	)
 
 	// setup auth credentials, use local credentials for the CLI and injected creds
 	// for the service.
-	var err error
 	if c.service {
 		err = setupAuthForService()
 	} else {
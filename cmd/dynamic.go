@@ -16,6 +16,7 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/registry"
+	rutil "github.com/micro/micro/v3/service/registry/util"
 )
 
 // lookupService queries the service for a service with the given alias. If
@@ -254,7 +255,7 @@ loop:
 // find a service in a domain matching the name
 func serviceWithName(name, domain string) (*goregistry.Service, error) {
 	srvs, err := registry.GetService(name, goregistry.GetDomain(domain))
-	if err == goregistry.ErrNotFound {
+	if rutil.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
 		return nil, err
@@ -0,0 +1,120 @@
+package micro
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/micro/micro/v3/cmd/protoc-gen-micro/generator"
+)
+
+// event is a message marked as an event
+type event struct {
+	// typeName is the fully qualified name of the message, e.g. .users.UserCreated
+	typeName string
+	// name of the message in Go
+	name string
+	// topic the event is published to
+	topic string
+}
+
+// events returns the top level messages of the file marked as events with the micro:event
+// directive in their leading comment. The topic is the one following the directive, or the
+// package and the name of the message in snake case, e.g. users.user_created.
+func (g *micro) events(file *generator.FileDescriptor) []event {
+	comments := make(map[int32]string)
+	for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+		// 4 means message, the path of top level messages being 4,index
+		if p := loc.GetPath(); len(p) == 2 && p[0] == 4 {
+			comments[p[1]] = loc.GetLeadingComments()
+		}
+	}
+
+	var events []event
+	for i, msg := range file.FileDescriptorProto.MessageType {
+		topic, ok := eventTopic(comments[int32(i)])
+		if !ok {
+			continue
+		}
+		if len(topic) == 0 {
+			topic = snakeCase(msg.GetName())
+			if pkg := file.GetPackage(); len(pkg) > 0 {
+				topic = pkg + "." + topic
+			}
+		}
+
+		typeName := "." + msg.GetName()
+		if pkg := file.GetPackage(); len(pkg) > 0 {
+			typeName = "." + pkg + typeName
+		}
+		events = append(events, event{typeName: typeName, name: generator.CamelCase(msg.GetName()), topic: topic})
+	}
+	return events
+}
+
+// eventTopic returns true if the comment has the event directive, and the topic following it
+func eventTopic(comment string) (string, bool) {
+	for _, line := range strings.Split(comment, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != eventDirective {
+			continue
+		}
+		if len(fields) > 1 {
+			return fields[1], true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// snakeCase converts a message name to snake case, keeping acronyms together, e.g.
+// URLChanged becomes url_changed
+func snakeCase(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			next := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if !unicode.IsUpper(prev) || next {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// generateEvent generates the typed publisher and consumer of the event
+func (g *micro) generateEvent(ev event) {
+	typ := g.typeName(ev.typeName)
+	topic := ev.name + "Topic"
+
+	g.P()
+	g.P("// Events API for ", ev.name)
+	g.P()
+	g.P("// ", topic, " is the topic ", ev.name, " events are published to")
+	g.P("const ", topic, ` = "`, ev.topic, `"`)
+	g.P()
+	g.P("func init() {")
+	g.P(eventsPkg, ".RegisterSchema(", topic, ", new(", typ, "))")
+	g.P("}")
+	g.P()
+	g.P("// Publish", ev.name, " publishes the event to ", topic)
+	g.P("func Publish", ev.name, "(ev *", typ, ", opts ...", goEventsPkg, ".PublishOption) error {")
+	g.P("return ", eventsPkg, ".Publish(", topic, ", ev, opts...)")
+	g.P("}")
+	g.P()
+	g.P("// ", ev.name, "Handler handles the ", ev.name, " events consumed")
+	g.P("type ", ev.name, "Handler func(ctx ", contextPkg, ".Context, ev *", typ, ") error")
+	g.P()
+	g.P("// Consume", ev.name, " consumes the events published to ", topic, " with the handler")
+	g.P("func Consume", ev.name, "(h ", ev.name, "Handler, opts ...", eventsPkg, ".ConsumeOption) error {")
+	g.P("return ", eventsPkg, ".Consume(", topic, ", func(ctx ", contextPkg, ".Context, ev *", goEventsPkg, ".Event) error {")
+	g.P("msg := new(", typ, ")")
+	g.P("if err := ev.Unmarshal(msg); err != nil {")
+	g.P("return err")
+	g.P("}")
+	g.P("return h(ctx, msg)")
+	g.P("}, opts...)")
+	g.P("}")
+}
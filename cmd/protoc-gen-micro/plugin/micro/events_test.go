@@ -0,0 +1,29 @@
+package micro
+
+import "testing"
+
+func TestEventTopic(t *testing.T) {
+	tt := []struct {
+		Comment string
+		Topic   string
+		Event   bool
+	}{
+		{" UserCreated is published when a user signs up\n", "", false},
+		{" micro:event\n", "", true},
+		{" UserCreated is published when a user signs up\n micro:event users.created\n", "users.created", true},
+	}
+	for _, tc := range tt {
+		topic, ok := eventTopic(tc.Comment)
+		if ok != tc.Event || topic != tc.Topic {
+			t.Errorf("Expected %q to be %v with topic %q, got %v %q", tc.Comment, tc.Event, tc.Topic, ok, topic)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, out := range map[string]string{"UserCreated": "user_created", "URLChanged": "url_changed", "Signup": "signup"} {
+		if s := snakeCase(in); s != out {
+			t.Errorf("Expected %v to be %v, got %v", in, out, s)
+		}
+	}
+}
@@ -19,8 +19,15 @@ const (
 	contextPkgPath = "context"
 	clientPkgPath  = "github.com/micro/go-micro/v3/client"
 	serverPkgPath  = "github.com/micro/go-micro/v3/server"
+
+	eventsPkgPath   = "github.com/micro/micro/v3/service/events"
+	goEventsPkgPath = "github.com/micro/go-micro/v3/events"
 )
 
+// eventDirective marks a message as an event in its leading comment, optionally followed by
+// the topic it's published to
+const eventDirective = "micro:event"
+
 func init() {
 	generator.RegisterPlugin(new(micro))
 }
@@ -45,6 +52,9 @@ var (
 	clientPkg  string
 	serverPkg  string
 	pkgImports map[generator.GoPackageName]bool
+
+	eventsPkg   string
+	goEventsPkg string
 )
 
 // Init initializes the plugin.
@@ -54,6 +64,8 @@ func (g *micro) Init(gen *generator.Generator) {
 	contextPkg = generator.RegisterUniquePackageName("context", nil)
 	clientPkg = generator.RegisterUniquePackageName("client", nil)
 	serverPkg = generator.RegisterUniquePackageName("server", nil)
+	eventsPkg = generator.RegisterUniquePackageName("events", nil)
+	goEventsPkg = generator.RegisterUniquePackageName("goevents", nil)
 }
 
 // Given a type name defined in a .proto, return its object.
@@ -71,9 +83,10 @@ func (g *micro) typeName(str string) string {
 // P forwards to g.gen.P.
 func (g *micro) P(args ...interface{}) { g.gen.P(args...) }
 
-// Generate generates code for the services in the given file.
+// Generate generates code for the services and events in the given file.
 func (g *micro) Generate(file *generator.FileDescriptor) {
-	if len(file.FileDescriptorProto.Service) == 0 {
+	events := g.events(file)
+	if len(file.FileDescriptorProto.Service) == 0 && len(events) == 0 {
 		return
 	}
 	g.P("// Reference imports to suppress errors if they are not otherwise used.")
@@ -81,16 +94,24 @@ func (g *micro) Generate(file *generator.FileDescriptor) {
 	g.P("var _ ", contextPkg, ".Context")
 	g.P("var _ ", clientPkg, ".Option")
 	g.P("var _ ", serverPkg, ".Option")
+	if len(events) > 0 {
+		g.P("var _ ", eventsPkg, ".Handler")
+		g.P("var _ ", goEventsPkg, ".Event")
+	}
 	g.P()
 
 	for i, service := range file.FileDescriptorProto.Service {
 		g.generateService(file, service, i)
 	}
+	for _, ev := range events {
+		g.generateEvent(ev)
+	}
 }
 
 // GenerateImports generates the import declaration for this file.
 func (g *micro) GenerateImports(file *generator.FileDescriptor, imports map[generator.GoImportPath]generator.GoPackageName) {
-	if len(file.FileDescriptorProto.Service) == 0 {
+	events := g.events(file)
+	if len(file.FileDescriptorProto.Service) == 0 && len(events) == 0 {
 		return
 	}
 	g.P("import (")
@@ -98,6 +119,10 @@ func (g *micro) GenerateImports(file *generator.FileDescriptor, imports map[gene
 	g.P(contextPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, contextPkgPath)))
 	g.P(clientPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, clientPkgPath)))
 	g.P(serverPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, serverPkgPath)))
+	if len(events) > 0 {
+		g.P(eventsPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, eventsPkgPath)))
+		g.P(goEventsPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, goEventsPkgPath)))
+	}
 	g.P(")")
 	g.P()
 
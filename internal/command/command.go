@@ -20,6 +20,7 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	proto "github.com/micro/micro/v3/service/debug/proto"
 	"github.com/micro/micro/v3/service/registry"
+	regutil "github.com/micro/micro/v3/service/registry/util"
 
 	"github.com/serenize/snaker"
 )
@@ -116,6 +117,10 @@ func GetService(c *cli.Context, args []string) ([]byte, error) {
 		var request, response string
 		var meta []string
 		for k, v := range e.Metadata {
+			// the schemas are output separately
+			if k == regutil.RequestSchemaKey || k == regutil.ResponseSchemaKey {
+				continue
+			}
 			meta = append(meta, k+"="+v)
 		}
 		if e.Request != nil && len(e.Request.Values) > 0 {
@@ -145,11 +150,31 @@ func GetService(c *cli.Context, args []string) ([]byte, error) {
 		}
 
 		output = append(output, fmt.Sprintf("Request: %s\n\nResponse: %s\n", request, response))
+
+		// output the schemas if the endpoint was registered with them
+		if s := formatSchema(e.Metadata[regutil.RequestSchemaKey]); len(s) > 0 {
+			output = append(output, fmt.Sprintf("Request schema: %s\n", s))
+		}
+		if s := formatSchema(e.Metadata[regutil.ResponseSchemaKey]); len(s) > 0 {
+			output = append(output, fmt.Sprintf("Response schema: %s\n", s))
+		}
 	}
 
 	return []byte(strings.Join(output, "\n")), nil
 }
 
+// formatSchema indents the JSON schema, returning a blank string if it's invalid
+func formatSchema(schema string) string {
+	if len(schema) == 0 {
+		return ""
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(schema), "", "\t"); err != nil {
+		return ""
+	}
+	return out.String()
+}
+
 func ListServices(c *cli.Context) ([]byte, error) {
 	var rsp []*goregistry.Service
 	var err error
@@ -174,6 +199,42 @@ func ListServices(c *cli.Context) ([]byte, error) {
 	return []byte(strings.Join(services, "\n")), nil
 }
 
+// ServiceGraph returns the dependency graph of the services, optionally limited to
+// the services connected to the one specified
+func ServiceGraph(c *cli.Context, args []string) ([]byte, error) {
+	ns, err := namespace.Get(util.GetEnv(c).Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var service string
+	if len(args) > 0 {
+		service = args[0]
+	}
+
+	nodes, err := registry.Graph(service, goregistry.ListDomain(ns))
+	if err != nil {
+		return nil, err
+	}
+
+	var output []string
+	for _, n := range nodes {
+		name := n.Name
+		if n.Missing {
+			name += " (not registered)"
+		}
+		output = append(output, name)
+		if len(n.Dependencies) > 0 {
+			output = append(output, "\tdepends on: "+strings.Join(n.Dependencies, ", "))
+		}
+		if len(n.Dependents) > 0 {
+			output = append(output, "\tused by: "+strings.Join(n.Dependents, ", "))
+		}
+	}
+
+	return []byte(strings.Join(output, "\n")), nil
+}
+
 func Publish(c *cli.Context, args []string) error {
 	if len(args) < 2 {
 		return errors.New("require topic and message e.g micro publish event '{\"hello\": \"world\"}'")
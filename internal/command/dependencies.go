@@ -0,0 +1,127 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/client"
+	proto "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// QueryDependencyHealth queries the health of the dependencies of each node of the services,
+// or every service if none are given, and outputs them as a matrix of nodes and dependencies
+func QueryDependencyHealth(c *cli.Context, args []string) ([]byte, error) {
+	ns, err := namespace.Get(util.GetEnv(c).Name)
+	if err != nil {
+		return nil, err
+	}
+
+	names := args
+	if len(names) == 0 {
+		list, err := registry.ListServices(goregistry.ListDomain(ns))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list {
+			names = append(names, s.Name)
+		}
+	}
+
+	services, err := registry.GetServices(names, goregistry.GetDomain(ns))
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, errors.New("Service not found")
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	type row struct {
+		service string
+		node    string
+		deps    map[string]*proto.Dependency
+		err     error
+	}
+
+	var rows []*row
+	columns := map[string]bool{}
+
+	for _, srv := range services {
+		for _, node := range srv.Nodes {
+			r := &row{service: srv.Name, node: node.Id, deps: map[string]*proto.Dependency{}}
+			rows = append(rows, r)
+
+			req := client.NewRequest(srv.Name, "Debug.DependencyHealth", &proto.DependencyHealthRequest{})
+			rsp := &proto.DependencyHealthResponse{}
+			// the request outlives the checks, which time out after 5 seconds
+			opts := []goclient.CallOption{goclient.WithAddress(node.Address), goclient.WithRequestTimeout(time.Second * 10)}
+			if err := client.Call(context.Background(), req, rsp, opts...); err != nil {
+				r.err = err
+				continue
+			}
+			for _, d := range rsp.Dependencies {
+				r.deps[d.Name] = d
+				columns[d.Name] = true
+			}
+		}
+	}
+
+	deps := make([]string, 0, len(columns))
+	for d := range columns {
+		deps = append(deps, d)
+	}
+	sort.Strings(deps)
+
+	var out bytes.Buffer
+	w := tabwriter.NewWriter(&out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprint(w, "SERVICE\tNODE")
+	for _, d := range deps {
+		fmt.Fprint(w, "\t"+d)
+	}
+	fmt.Fprintln(w)
+
+	var failures []string
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s", r.service, r.node)
+		if r.err != nil {
+			failures = append(failures, fmt.Sprintf("%s %s: %v", r.service, r.node, r.err))
+		}
+		for _, name := range deps {
+			d, ok := r.deps[name]
+			switch {
+			case r.err != nil:
+				fmt.Fprint(w, "\t?")
+			case !ok:
+				fmt.Fprint(w, "\t-")
+			case d.Status == "ok":
+				fmt.Fprintf(w, "\tok %v", time.Duration(d.Latency).Round(time.Millisecond))
+			default:
+				fmt.Fprintf(w, "\t%s", d.Status)
+				failures = append(failures, fmt.Sprintf("%s %s -> %s: %s", r.service, r.node, name, d.Error))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	if len(failures) > 0 {
+		out.WriteString("\nErrors:\n")
+		for _, f := range failures {
+			out.WriteString(f + "\n")
+		}
+	}
+
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/micro/go-micro/v3/api/handler/event"
 	"github.com/micro/go-micro/v3/api/router"
 	"github.com/micro/go-micro/v3/client"
-	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/api/websocket"
 	"github.com/micro/micro/v3/service/errors"
 
 	// TODO: only import handler package
@@ -33,6 +33,13 @@ func (m *metaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// websocket upgrades of the endpoints served by the websocket handler, which are routed
+	// to the rpc handler by default
+	if websocket.IsUpgrade(r) && websocket.Serves(service) {
+		websocket.WithService(service, handler.WithClient(m.c)).ServeHTTP(w, r)
+		return
+	}
+
 	// TODO: don't do this ffs
 	switch service.Endpoint.Handler {
 	// web socket handler
@@ -61,9 +68,9 @@ func (m *metaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // Meta is a http.Handler that routes based on endpoint metadata
-func Meta(s *service.Service, r router.Router, ns string) http.Handler {
+func Meta(c client.Client, r router.Router, ns string) http.Handler {
 	return &metaHandler{
-		c:  s.Client(),
+		c:  c,
 		r:  r,
 		ns: ns,
 	}
@@ -0,0 +1,142 @@
+// Package domain provides a resolver which routes requests to custom domains to the namespace
+// which verified the domain
+package domain
+
+import (
+	"container/list"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultTTL is how long the namespace a domain resolves to is cached for
+	DefaultTTL = time.Minute
+	// DefaultErrorTTL is how long the result is cached for when the domain can't be resolved,
+	// so requests with unknown hosts don't each call the auth service whilst it's failing
+	DefaultErrorTTL = time.Second * 5
+	// DefaultMaxDomains is the number of domains cached, the least recently used are evicted
+	DefaultMaxDomains = 10000
+)
+
+// cached namespace of a domain, blank if the domain isn't verified
+type cached struct {
+	domain    string
+	namespace string
+	expires   time.Time
+}
+
+// Resolver sets the domain of requests to hosts verified by a namespace before resolving them
+// with the wrapped resolver
+type Resolver struct {
+	resolver.Resolver
+
+	// Domains service used to resolve hosts
+	Domains pb.DomainsService
+
+	sync.Mutex
+	// cache of the domains, the elements of the lru
+	cache map[string]*list.Element
+	// lru is the cached domains, the most recently used at the front
+	lru *list.List
+}
+
+// NewResolver wraps the resolver with one which resolves custom domains
+func NewResolver(r resolver.Resolver) resolver.Resolver {
+	return &Resolver{
+		Resolver: r,
+		Domains:  pb.NewDomainsService("auth", client.DefaultClient),
+		cache:    make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Resolve the request, using the namespace the host is bound to unless the namespace was set
+// in the request header
+func (r *Resolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	if len(req.Header.Get("Micro-Namespace")) > 0 {
+		return r.Resolver.Resolve(req, opts...)
+	}
+	if ns := r.namespace(req.Host); len(ns) > 0 {
+		opts = append(opts, resolver.Domain(ns))
+	}
+	return r.Resolver.Resolve(req, opts...)
+}
+
+func (r *Resolver) String() string {
+	return "domain"
+}
+
+// namespace returns the namespace the host is bound to, blank if it isn't verified
+func (r *Resolver) namespace(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if len(host) == 0 || net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+		return ""
+	}
+
+	c, ok := r.get(host)
+	if ok && time.Now().Before(c.expires) {
+		return c.namespace
+	}
+
+	ttl := DefaultTTL
+	var ns string
+	rsp, err := r.Domains.Resolve(context.TODO(), &pb.ResolveDomainRequest{Domain: host})
+	if err == nil {
+		ns = rsp.Namespace
+	} else if verr := errors.Parse(err); verr == nil || verr.Code != http.StatusNotFound {
+		// serve the previous result until the auth service is reachable, retrying after the
+		// error ttl
+		logger.Debugf("Error resolving domain %v: %v", host, err)
+		ns = c.namespace
+		ttl = DefaultErrorTTL
+	}
+
+	r.set(cached{domain: host, namespace: ns, expires: time.Now().Add(ttl)})
+	return ns
+}
+
+// get the cached namespace of the domain
+func (r *Resolver) get(domain string) (cached, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	e, ok := r.cache[domain]
+	if !ok {
+		return cached{}, false
+	}
+	r.lru.MoveToFront(e)
+	return e.Value.(cached), true
+}
+
+// set the cached namespace of the domain, evicting the least recently used domain once there
+// are too many
+func (r *Resolver) set(c cached) {
+	r.Lock()
+	defer r.Unlock()
+
+	if e, ok := r.cache[c.domain]; ok {
+		e.Value = c
+		r.lru.MoveToFront(e)
+		return
+	}
+	r.cache[c.domain] = r.lru.PushFront(c)
+
+	if DefaultMaxDomains > 0 && r.lru.Len() > DefaultMaxDomains {
+		e := r.lru.Back()
+		r.lru.Remove(e)
+		delete(r.cache, e.Value.(cached).domain)
+	}
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/client"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testDomains struct {
+	pb.DomainsService
+	domains map[string]string
+	calls   int
+	err     error
+}
+
+func (t *testDomains) Resolve(ctx context.Context, req *pb.ResolveDomainRequest, opts ...client.CallOption) (*pb.ResolveDomainResponse, error) {
+	t.calls++
+	if t.err != nil {
+		return nil, t.err
+	}
+	ns, ok := t.domains[req.Domain]
+	if !ok {
+		return nil, errors.NotFound("auth.Domains.Resolve", "Domain is not verified")
+	}
+	return &pb.ResolveDomainResponse{Namespace: ns}, nil
+}
+
+type testResolver struct{}
+
+func (testResolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	// apply the options directly, since NewResolveOptions defaults the domain
+	var options resolver.ResolveOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return &resolver.Endpoint{Name: "foo", Domain: options.Domain}, nil
+}
+
+func (testResolver) String() string {
+	return "test"
+}
+
+func TestResolver(t *testing.T) {
+	doms := &testDomains{domains: map[string]string{"api.example.com": "acme"}}
+	r := &Resolver{Resolver: testResolver{}, Domains: doms, cache: make(map[string]*list.Element), lru: list.New()}
+
+	tt := []struct {
+		Name      string
+		Host      string
+		Header    string
+		Namespace string
+	}{
+		{"Verified", "api.example.com", "", "acme"},
+		{"Port", "API.example.com:8080", "", "acme"},
+		{"Header", "api.example.com", "other", ""},
+		{"Unverified", "api.other.com", "", ""},
+		{"IP", "127.0.0.1:8080", "", ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://"+tc.Host+"/foo", nil)
+			if len(tc.Header) > 0 {
+				req.Header.Set("Micro-Namespace", tc.Header)
+			}
+			ep, err := r.Resolve(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ep.Domain != tc.Namespace {
+				t.Errorf("Expected domain %q, got %q", tc.Namespace, ep.Domain)
+			}
+		})
+	}
+
+	// both the verified and unverified domains are cached
+	calls := doms.calls
+	req, _ := http.NewRequest("GET", "http://api.other.com/foo", nil)
+	r.Resolve(req)
+	req, _ = http.NewRequest("GET", "http://api.example.com/foo", nil)
+	r.Resolve(req)
+	if doms.calls != calls {
+		t.Errorf("Expected the domains to be cached, got %v more calls", doms.calls-calls)
+	}
+
+	// errors are cached so unknown hosts don't each call the auth service
+	doms.err = errors.InternalServerError("auth.Domains.Resolve", "Unavailable")
+	calls = doms.calls
+	for i := 0; i < 2; i++ {
+		req, _ = http.NewRequest("GET", "http://api.unknown.com/foo", nil)
+		r.Resolve(req)
+	}
+	if doms.calls != calls+1 {
+		t.Errorf("Expected the error to be cached, got %v calls", doms.calls-calls)
+	}
+}
+
+func TestResolverEviction(t *testing.T) {
+	max := DefaultMaxDomains
+	DefaultMaxDomains = 2
+	defer func() { DefaultMaxDomains = max }()
+
+	doms := &testDomains{}
+	r := &Resolver{Resolver: testResolver{}, Domains: doms, cache: make(map[string]*list.Element), lru: list.New()}
+
+	for i := 0; i < 10; i++ {
+		r.namespace(fmt.Sprintf("api%d.example.com", i))
+	}
+	if len(r.cache) != 2 || r.lru.Len() != 2 {
+		t.Fatalf("Expected 2 domains to be cached, got %v", len(r.cache))
+	}
+	if _, ok := r.cache["api9.example.com"]; !ok {
+		t.Errorf("Expected the most recently used domain to be cached")
+	}
+}
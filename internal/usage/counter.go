@@ -0,0 +1,30 @@
+package usage
+
+import (
+	"sync"
+)
+
+var (
+	countersMtx sync.Mutex
+	// counters are added to the next report, e.g. bytes sent by the network
+	counters = map[string]uint64{}
+)
+
+// Add n to the counter, which is included in the next usage report and then reset
+func Add(key string, n uint64) {
+	countersMtx.Lock()
+	counters[key] += n
+	countersMtx.Unlock()
+}
+
+// flush the counters into the metrics, resetting them to zero so counters which aren't
+// added to before the next report are reported as zero
+func flush(m map[string]uint64) {
+	countersMtx.Lock()
+	defer countersMtx.Unlock()
+
+	for k, v := range counters {
+		m[k] = v
+		counters[k] = 0
+	}
+}
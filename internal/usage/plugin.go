@@ -71,6 +71,7 @@ func Plugin() plugin.Plugin {
 					u.Metrics.Count["instances"] = uint64(1)
 					u.Metrics.Count["requests"] = reqs
 					u.Metrics.Count["services"] = srvs
+					flush(u.Metrics.Count)
 
 					// attempt to send report 3 times
 					for i := 1; i <= 3; i++ {
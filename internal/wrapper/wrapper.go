@@ -3,7 +3,9 @@ package wrapper
 import (
 	"context"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	goauth "github.com/micro/go-micro/v3/auth"
 	"github.com/micro/go-micro/v3/client"
@@ -207,6 +209,24 @@ func HandlerStats() server.HandlerWrapper {
 	}
 }
 
+// LatencyHandler records the latency of the requests served, with the trace of each request so
+// the slowest can be found. It must wrap the handler inside TraceHandler.
+func LatencyHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if strings.HasPrefix(req.Endpoint(), "Debug.") {
+				return h(ctx, req, rsp)
+			}
+
+			started := time.Now()
+			err := h(ctx, req, rsp)
+			traceID, _, _ := trace.FromContext(ctx)
+			debug.DefaultLatency.Record(req.Endpoint(), traceID, time.Since(started))
+			return err
+		}
+	}
+}
+
 type traceWrapper struct {
 	client.Client
 }
@@ -248,9 +268,20 @@ func TraceHandler() server.HandlerWrapper {
 			newCtx, s := debug.DefaultTracer.Start(ctx, req.Service()+"."+req.Endpoint())
 			s.Type = trace.SpanTypeRequestInbound
 
+			// set the attributes of the request so spans can be filtered without decoding names
+			s.Metadata[debug.SpanService] = req.Service()
+			s.Metadata[debug.SpanEndpoint] = req.Endpoint()
+			if peer, ok := metadata.Get(ctx, HeaderPrefix+"From-Service"); ok && len(peer) > 0 {
+				s.Metadata[debug.SpanPeer] = peer
+			}
+			if acc, ok := goauth.AccountFromContext(ctx); ok && acc != nil {
+				s.Metadata[debug.SpanAccount] = acc.ID
+			}
+
 			err := h(newCtx, req, rsp)
+			s.Metadata[debug.SpanStatus] = spanStatus(err)
 			if err != nil {
-				s.Metadata["error"] = err.Error()
+				s.Metadata[debug.SpanError] = err.Error()
 			}
 
 			// finish
@@ -261,13 +292,25 @@ func TraceHandler() server.HandlerWrapper {
 	}
 }
 
+// spanStatus returns the status code of the error, errors which aren't micro errors being
+// internal server errors
+func spanStatus(err error) string {
+	if err == nil {
+		return "200"
+	}
+	if verr := errors.Parse(err); verr != nil && verr.Code > 0 {
+		return strconv.Itoa(int(verr.Code))
+	}
+	return "500"
+}
+
 type cacheWrapper struct {
 	Cache *cache.Cache
 	client.Client
 }
 
-// Call executes the request. If the CacheExpiry option was set, the response will be cached using
-// a hash of the metadata and request as the key.
+// Call executes the request. If the CacheExpiry option was set or an expiry is configured for the
+// endpoint, the response will be cached using a hash of the metadata and request as the key.
 func (c *cacheWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
 	// parse the options
 	var options client.CallOptions
@@ -280,13 +323,16 @@ func (c *cacheWrapper) Call(ctx context.Context, req client.Request, rsp interfa
 		return c.Client.Call(ctx, req, rsp, opts...)
 	}
 
-	cacheOpts, ok := cache.GetOptions(options.Context)
-	if !ok {
-		return c.Client.Call(ctx, req, rsp, opts...)
+	// the expiry passed to the call takes precedence over the configured one
+	var expiry time.Duration
+	if cacheOpts, ok := cache.GetOptions(options.Context); ok {
+		expiry = cacheOpts.Expiry
+	} else {
+		expiry = c.Cache.Expiry(req.Service(), req.Endpoint())
 	}
 
 	// if the cache expiry is not set, execute the call without the cache
-	if cacheOpts.Expiry == 0 || rsp == nil {
+	if expiry == 0 || rsp == nil {
 		return c.Client.Call(ctx, req, rsp, opts...)
 	}
 
@@ -303,14 +349,14 @@ func (c *cacheWrapper) Call(ctx context.Context, req client.Request, rsp interfa
 	}
 
 	// set the result in the cache
-	c.Cache.Set(ctx, req, rsp, cacheOpts.Expiry)
+	c.Cache.Set(ctx, req, rsp, expiry)
 	return nil
 }
 
-// CacheClient wraps requests with the cache wrapper
+// CacheClient wraps requests with the cache wrapper, using the default cache
 func CacheClient(c client.Client) client.Client {
 	return &cacheWrapper{
-		Cache:  cache.New(),
+		Cache:  cache.DefaultCache,
 		Client: c,
 	}
 }
@@ -0,0 +1,146 @@
+package wrapper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/debug/trace"
+	memTrace "github.com/micro/go-micro/v3/debug/trace/memory"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/debug/latency"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+}
+
+func (r testRequest) Service() string {
+	return "users"
+}
+
+func (r testRequest) Endpoint() string {
+	return r.endpoint
+}
+
+func TestTraceHandler(t *testing.T) {
+	defer func(t trace.Tracer) { debug.DefaultTracer = t }(debug.DefaultTracer)
+
+	tt := []struct {
+		Name     string
+		Endpoint string
+		Peer     string
+		Account  *goauth.Account
+		Err      error
+		Expected map[string]string
+	}{
+		{
+			Name:     "Anonymous",
+			Endpoint: "Users.Read",
+			Expected: map[string]string{
+				debug.SpanService:  "users",
+				debug.SpanEndpoint: "Users.Read",
+				debug.SpanStatus:   "200",
+			},
+		},
+		{
+			Name:     "Attributes",
+			Endpoint: "Users.Update",
+			Peer:     "billing",
+			Account:  &goauth.Account{ID: "john"},
+			Err:      errors.NotFound("users", "user not found"),
+			Expected: map[string]string{
+				debug.SpanService:  "users",
+				debug.SpanEndpoint: "Users.Update",
+				debug.SpanPeer:     "billing",
+				debug.SpanAccount:  "john",
+				debug.SpanStatus:   "404",
+			},
+		},
+		{
+			Name:     "UnknownError",
+			Endpoint: "Users.Delete",
+			Err:      context.Canceled,
+			Expected: map[string]string{
+				debug.SpanService:  "users",
+				debug.SpanEndpoint: "Users.Delete",
+				debug.SpanStatus:   "500",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			debug.DefaultTracer = memTrace.NewTracer()
+
+			ctx := context.Background()
+			if len(tc.Peer) > 0 {
+				ctx = metadata.Set(ctx, HeaderPrefix+"From-Service", tc.Peer)
+			}
+			if tc.Account != nil {
+				ctx = goauth.ContextWithAccount(ctx, tc.Account)
+			}
+
+			h := TraceHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+				return tc.Err
+			})
+			if err := h(ctx, testRequest{endpoint: tc.Endpoint}, nil); err != tc.Err {
+				t.Fatalf("Expected the handler's error %v, got %v", tc.Err, err)
+			}
+
+			spans, err := debug.DefaultTracer.Read()
+			if err != nil {
+				t.Fatalf("Unexpected error reading the spans: %v", err)
+			}
+			if len(spans) != 1 {
+				t.Fatalf("Expected 1 span, got %v", len(spans))
+			}
+
+			md := spans[0].Metadata
+			if tc.Err != nil && md[debug.SpanError] != tc.Err.Error() {
+				t.Errorf("Expected the error %q, got %q", tc.Err.Error(), md[debug.SpanError])
+			}
+			delete(md, debug.SpanError)
+			if len(md) != len(tc.Expected) {
+				t.Errorf("Expected attributes %v, got %v", tc.Expected, md)
+			}
+			for k, v := range tc.Expected {
+				if md[k] != v {
+					t.Errorf("Expected %v to be %q, got %q", k, v, md[k])
+				}
+			}
+		})
+	}
+}
+
+func TestLatencyHandler(t *testing.T) {
+	defer func(t trace.Tracer) { debug.DefaultTracer = t }(debug.DefaultTracer)
+	defer func(r *latency.Recorder) { debug.DefaultLatency = r }(debug.DefaultLatency)
+	debug.DefaultTracer = memTrace.NewTracer()
+	debug.DefaultLatency = latency.NewRecorder()
+
+	h := TraceHandler()(LatencyHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		time.Sleep(time.Millisecond * 5)
+		return nil
+	}))
+	h(context.Background(), testRequest{endpoint: "Users.Read"}, nil)
+	h(context.Background(), testRequest{endpoint: "Debug.Stats"}, nil)
+
+	spans, err := debug.DefaultTracer.Read()
+	if err != nil || len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %v, %v", spans, err)
+	}
+	hists := debug.DefaultLatency.Read("", time.Time{})
+	if len(hists) != 1 || hists[0].Endpoint != "Users.Read" || len(hists[0].Frames) != 1 {
+		t.Fatalf("Expected the latency of Users.Read to be recorded, got %+v", hists)
+	}
+	ex := hists[0].Frames[0].Outliers(latency.DefaultPercentile)
+	if len(ex) != 1 || ex[0].Trace != spans[0].Trace || ex[0].Latency < time.Millisecond*5 {
+		t.Errorf("Expected an exemplar of the request's trace %v, got %+v", spans[0].Trace, ex)
+	}
+}
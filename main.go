@@ -13,14 +13,21 @@ import (
 
 	// load packages so they can register commands
 	_ "github.com/micro/micro/v3/client/cli"
+	_ "github.com/micro/micro/v3/client/cli/latency"
 	_ "github.com/micro/micro/v3/client/cli/new"
+	_ "github.com/micro/micro/v3/client/cli/top"
 	_ "github.com/micro/micro/v3/client/cli/user"
+	_ "github.com/micro/micro/v3/client/cli/wait"
+	_ "github.com/micro/micro/v3/client/cli/why"
 	_ "github.com/micro/micro/v3/platform/cli"
 	_ "github.com/micro/micro/v3/server"
+	_ "github.com/micro/micro/v3/service/api/cli"
 	_ "github.com/micro/micro/v3/service/auth/cli"
 	_ "github.com/micro/micro/v3/service/cli"
 	_ "github.com/micro/micro/v3/service/config/cli"
+	_ "github.com/micro/micro/v3/service/events/cli"
 	_ "github.com/micro/micro/v3/service/network/cli"
+	_ "github.com/micro/micro/v3/service/registry/cli"
 	_ "github.com/micro/micro/v3/service/runtime/cli"
 	_ "github.com/micro/micro/v3/service/store/cli"
 )
@@ -31,7 +31,6 @@ import (
 	"github.com/micro/go-micro/v3/runtime/local"
 	"github.com/micro/go-micro/v3/server"
 	"github.com/micro/go-micro/v3/store"
-	"github.com/micro/go-micro/v3/store/cockroach"
 	"github.com/micro/go-micro/v3/store/file"
 	mem "github.com/micro/go-micro/v3/store/memory"
 	"github.com/micro/micro/v3/service/logger"
@@ -43,10 +42,13 @@ import (
 	microConfig "github.com/micro/micro/v3/service/config"
 	microEvents "github.com/micro/micro/v3/service/events"
 	microRegistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/hybrid"
 	microRouter "github.com/micro/micro/v3/service/router"
 	microRuntime "github.com/micro/micro/v3/service/runtime"
 	microServer "github.com/micro/micro/v3/service/server"
 	microStore "github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/cockroach"
+	persisted "github.com/micro/micro/v3/service/store/memory"
 )
 
 // profiles which when called will configure micro to run in that environment
@@ -55,10 +57,12 @@ var profiles = map[string]*Profile{
 	"ci":         CI,
 	"test":       Test,
 	"local":      Local,
+	"edge":       Edge,
 	"kubernetes": Kubernetes,
 	"platform":   Platform,
 	"client":     Client,
 	"service":    Service,
+	"hybrid":     Hybrid,
 }
 
 // Profile configures an environment
@@ -134,6 +138,30 @@ var Local = &Profile{
 	},
 }
 
+// Edge profile to run on edge nodes, like the local profile but the store is kept in memory and
+// persisted to disk rather than needing a database
+var Edge = &Profile{
+	Name: "edge",
+	Setup: func(ctx *cli.Context) error {
+		microAuth.DefaultAuth = noop.NewAuth()
+		microRuntime.DefaultRuntime = local.NewRuntime()
+		microStore.DefaultStore = persisted.NewStore()
+		microConfig.DefaultConfig, _ = config.NewConfig()
+		setBroker(http.NewBroker())
+		setRegistry(mdns.NewRegistry())
+		setupJWTRules()
+
+		var err error
+		microEvents.DefaultStream, err = memStream.NewStream()
+		if err != nil {
+			logger.Fatalf("Error configuring stream: %v", err)
+		}
+		microEvents.DefaultStore = evStore.NewStore(evStore.WithStore(microStore.DefaultStore))
+
+		return nil
+	},
+}
+
 // Kubernetes profile to run on kubernetes
 var Kubernetes = &Profile{
 	Name: "kubernetes",
@@ -169,7 +197,8 @@ var Platform = &Profile{
 
 		// the cockroach store will connect immediately so the address must be passed
 		// when the store is created. The cockroach store address contains the location
-		// of certs so it can't be defaulted like the broker and registry.
+		// of certs so it can't be defaulted like the broker and registry. Conditional
+		// writes are applied in transactions since the store service has many replicas.
 		microStore.DefaultStore = cockroach.NewStore(store.Nodes(ctx.String("store_address")))
 		microEvents.DefaultStore = evStore.NewStore(evStore.WithStore(microStore.DefaultStore))
 		return nil
@@ -182,6 +211,16 @@ var Service = &Profile{
 	Setup: func(ctx *cli.Context) error { return nil },
 }
 
+// Hybrid profile is for running services locally against a shared environment. Services are
+// registered using mDNS and preferred to those in the remote registry.
+var Hybrid = &Profile{
+	Name: "hybrid",
+	Setup: func(ctx *cli.Context) error {
+		setRegistry(hybrid.New(mdns.NewRegistry(), microRegistry.DefaultRegistry))
+		return nil
+	},
+}
+
 // Test profile is used for the go test suite
 var Test = &Profile{
 	Name: "test",
@@ -2,6 +2,7 @@
 package api
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
@@ -32,11 +33,23 @@ import (
 	"github.com/micro/micro/v3/internal/handler"
 	"github.com/micro/micro/v3/internal/helper"
 	rrmicro "github.com/micro/micro/v3/internal/resolver/api"
+	"github.com/micro/micro/v3/internal/resolver/domain"
 	"github.com/micro/micro/v3/plugin"
 	"github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/api/auth"
+	"github.com/micro/micro/v3/service/api/deprecation"
+	"github.com/micro/micro/v3/service/api/hints"
+	"github.com/micro/micro/v3/service/api/locale"
+	"github.com/micro/micro/v3/service/api/mtls"
+	"github.com/micro/micro/v3/service/api/redact"
+	"github.com/micro/micro/v3/service/api/tenant"
+	"github.com/micro/micro/v3/service/api/upload"
+	"github.com/micro/micro/v3/service/api/validate"
+	"github.com/micro/micro/v3/service/api/websocket"
+	"github.com/micro/micro/v3/service/auth/classify"
 	log "github.com/micro/micro/v3/service/logger"
 	muregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/cache"
 	"github.com/micro/micro/v3/service/store"
 )
 
@@ -81,6 +94,74 @@ var (
 			EnvVars: []string{"MICRO_API_ENABLE_CORS"},
 			Value:   true,
 		},
+		&cli.StringSliceFlag{
+			Name:    "websocket_origins",
+			Usage:   "Set the origins of other sites whose pages can open websockets to the API, e.g. https://app.example.com",
+			EnvVars: []string{"MICRO_API_WEBSOCKET_ORIGINS"},
+		},
+		&cli.IntFlag{
+			Name:    "tenant_max_concurrent",
+			Usage:   "Set the number of requests each namespace can have in flight, 0 for no limit",
+			EnvVars: []string{"MICRO_API_TENANT_MAX_CONCURRENT"},
+			Value:   tenant.DefaultMaxConcurrent,
+		},
+		&cli.IntFlag{
+			Name:    "tenant_max_queue",
+			Usage:   "Set the number of requests each namespace can have queued once it reaches its limit",
+			EnvVars: []string{"MICRO_API_TENANT_MAX_QUEUE"},
+			Value:   tenant.DefaultMaxQueue,
+		},
+		&cli.DurationFlag{
+			Name:    "tenant_queue_timeout",
+			Usage:   "Set how long a request can be queued before it's rejected",
+			EnvVars: []string{"MICRO_API_TENANT_QUEUE_TIMEOUT"},
+			Value:   tenant.DefaultQueueTimeout,
+		},
+		&cli.IntFlag{
+			Name:    "tenant_max_tenants",
+			Usage:   "Set the number of namespaces whose requests are tracked, the least recently used are evicted",
+			EnvVars: []string{"MICRO_API_TENANT_MAX_TENANTS"},
+			Value:   tenant.DefaultMaxTenants,
+		},
+		&cli.BoolFlag{
+			Name:    "validate_requests",
+			Usage:   "Validate JSON requests against the schemas the endpoints were registered with",
+			EnvVars: []string{"MICRO_API_VALIDATE_REQUESTS"},
+		},
+		&cli.StringFlag{
+			Name:    "tls_client_auth",
+			Usage:   "Set to optional to only require client certificates on the routes in the api.mtls config, rather than every request",
+			EnvVars: []string{"MICRO_API_TLS_CLIENT_AUTH"},
+			Value:   "require",
+		},
+		&cli.StringFlag{
+			Name:    "upload_dir",
+			Usage:   "Stream the files in multipart/form-data requests to the directory and pass references to them to the backend",
+			EnvVars: []string{"MICRO_API_UPLOAD_DIR"},
+		},
+		&cli.Int64Flag{
+			Name:    "upload_max_size",
+			Usage:   "Set the maximum size of an uploaded file in bytes",
+			EnvVars: []string{"MICRO_API_UPLOAD_MAX_SIZE"},
+			Value:   upload.DefaultMaxSize,
+		},
+		&cli.Int64Flag{
+			Name:    "upload_max_total_size",
+			Usage:   "Set the maximum size of all the files uploaded in a request in bytes",
+			EnvVars: []string{"MICRO_API_UPLOAD_MAX_TOTAL_SIZE"},
+			Value:   upload.DefaultMaxTotalSize,
+		},
+		&cli.IntFlag{
+			Name:    "upload_max_files",
+			Usage:   "Set the maximum number of files uploaded in a request",
+			EnvVars: []string{"MICRO_API_UPLOAD_MAX_FILES"},
+			Value:   upload.DefaultMaxFiles,
+		},
+		&cli.StringSliceFlag{
+			Name:    "upload_types",
+			Usage:   "Set the types of file which can be uploaded, e.g. image/png or image/, all types are allowed if unset",
+			EnvVars: []string{"MICRO_API_UPLOAD_TYPES"},
+		},
 	)
 )
 
@@ -166,6 +247,12 @@ func Run(ctx *cli.Context) error {
 			return err
 		}
 
+		// verify client certificates if they're given, leaving the routes which require them
+		// to the mtls config
+		if config.ClientCAs != nil && ctx.String("tls_client_auth") == "optional" {
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+
 		opts = append(opts, server.EnableTLS(true))
 		opts = append(opts, server.TLSConfig(config))
 	}
@@ -173,6 +260,7 @@ func Run(ctx *cli.Context) error {
 	if ctx.Bool("enable_cors") {
 		opts = append(opts, server.EnableCORS(true))
 	}
+	websocket.AllowedOrigins = ctx.StringSlice("websocket_origins")
 
 	// create the router
 	var h http.Handler
@@ -212,6 +300,12 @@ func Run(ctx *cli.Context) error {
 		rr = grpc.NewResolver(ropts...)
 	}
 
+	// route requests to custom domains to the namespace which verified them
+	rr = domain.NewResolver(rr)
+
+	var hdlr http.Handler
+	prefix := APIPath
+
 	switch Handler {
 	case "rpc":
 		log.Infof("Registering API RPC Handler at %s", APIPath)
@@ -220,12 +314,11 @@ func Run(ctx *cli.Context) error {
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		rp := arpc.NewHandler(
+		hdlr = arpc.NewHandler(
 			ahandler.WithNamespace(Namespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(srv.Client()),
 		)
-		r.PathPrefix(APIPath).Handler(rp)
 	case "api":
 		log.Infof("Registering API Request Handler at %s", APIPath)
 		rt := regRouter.NewRouter(
@@ -233,12 +326,11 @@ func Run(ctx *cli.Context) error {
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		ap := aapi.NewHandler(
+		hdlr = aapi.NewHandler(
 			ahandler.WithNamespace(Namespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(srv.Client()),
 		)
-		r.PathPrefix(APIPath).Handler(ap)
 	case "event":
 		log.Infof("Registering API Event Handler at %s", APIPath)
 		rt := regRouter.NewRouter(
@@ -246,12 +338,11 @@ func Run(ctx *cli.Context) error {
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		ev := event.NewHandler(
+		hdlr = event.NewHandler(
 			ahandler.WithNamespace(Namespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(srv.Client()),
 		)
-		r.PathPrefix(APIPath).Handler(ev)
 	case "http":
 		log.Infof("Registering API HTTP Handler at %s", ProxyPath)
 		rt := regRouter.NewRouter(
@@ -259,12 +350,12 @@ func Run(ctx *cli.Context) error {
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		ht := ahttp.NewHandler(
+		hdlr = ahttp.NewHandler(
 			ahandler.WithNamespace(Namespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(srv.Client()),
 		)
-		r.PathPrefix(ProxyPath).Handler(ht)
+		prefix = ProxyPath
 	case "web":
 		log.Infof("Registering API Web Handler at %s", APIPath)
 		rt := regRouter.NewRouter(
@@ -272,21 +363,28 @@ func Run(ctx *cli.Context) error {
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		w := web.NewHandler(
+		hdlr = web.NewHandler(
 			ahandler.WithNamespace(Namespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(srv.Client()),
 		)
-		r.PathPrefix(APIPath).Handler(w)
 	default:
 		log.Infof("Registering API Default Handler at %s", APIPath)
 		rt := regRouter.NewRouter(
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		r.PathPrefix(APIPath).Handler(handler.Meta(srv, rt, Namespace))
+		hdlr = handler.Meta(srv.Client(), rt, Namespace)
 	}
 
+	// limit the requests each namespace has in flight so they don't share resources
+	r.PathPrefix(prefix).Handler(tenant.NewHandler(hdlr,
+		tenant.MaxConcurrent(ctx.Int("tenant_max_concurrent")),
+		tenant.MaxQueue(ctx.Int("tenant_max_queue")),
+		tenant.QueueTimeout(ctx.Duration("tenant_queue_timeout")),
+		tenant.MaxTenants(ctx.Int("tenant_max_tenants")),
+	))
+
 	// register all the http handler plugins
 	for _, p := range plugin.Plugins() {
 		if v := p.Handler(); v != nil {
@@ -294,8 +392,41 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
+	// the wrappers which read the endpoints resolved share a cache of the registry
+	reg := cache.New(muregistry.DefaultRegistry)
+
+	// validate requests once the auth wrapper has resolved the endpoint
+	if ctx.Bool("validate_requests") {
+		h = validate.Wrapper(reg)(h)
+	}
+
+	// send the resources the pages of the endpoint depend on once it's been resolved
+	h = hints.Wrapper(reg)(h)
+
+	// redact the classified fields of responses for callers without the scope to read them
+	h = redact.Wrapper(reg, classify.DefaultPolicy)(h)
+
+	// stream uploads to the blob store once the request is authorized, before it's validated
+	if dir := ctx.String("upload_dir"); len(dir) > 0 {
+		h = upload.Wrapper(upload.NewFileBlobs(dir),
+			upload.MaxSize(ctx.Int64("upload_max_size")),
+			upload.MaxTotalSize(ctx.Int64("upload_max_total_size")),
+			upload.MaxFiles(ctx.Int("upload_max_files")),
+			upload.Types(ctx.StringSlice("upload_types")...),
+		)(h)
+	}
+
+	// set the headers of deprecated routes once the endpoint has been resolved
+	h = deprecation.Wrapper(deprecation.ConfigSource, store.DefaultStore)(h)
+
 	// append the auth wrapper
-	h = auth.Wrapper(rr, Namespace)(h)
+	h = auth.Wrapper(rr, Namespace, auth.WithMethods(auth.ConfigSource))(h)
+
+	// authenticate requests by their client certificate before the auth wrapper verifies them
+	h = mtls.Wrapper(rr, mtls.ConfigSource)(h)
+
+	// set the language from the Accept-Language header before the metadata is extracted
+	h = locale.Wrapper()(h)
 
 	// create a new api server with wrappers
 	api := httpapi.NewServer(Address)
@@ -0,0 +1,321 @@
+package auth
+
+import (
+	"bytes"
+	gocontext "context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// Method of authenticating requests
+type Method string
+
+const (
+	// JWT authenticates requests with a bearer token in the Authorization header or the token
+	// cookie
+	JWT Method = "jwt"
+	// APIKey authenticates requests with the credentials of an account, "id:secret", in the
+	// APIKeyHeader
+	APIKey Method = "apikey"
+	// MTLS authenticates requests with the account their client certificate maps to
+	MTLS Method = "mtls"
+	// None accepts requests without authenticating them, the route is public
+	None Method = "none"
+)
+
+var (
+	// DefaultPath is the path of the auth methods in the config
+	DefaultPath = []string{"api", "auth"}
+	// DefaultMethods are the methods accepted by routes which don't declare their own
+	DefaultMethods = []Method{JWT, APIKey, MTLS}
+	// APIKeyHeader is the header API keys are passed in. It's removed from requests before
+	// they're forwarded so the secrets don't reach services.
+	APIKeyHeader = "Micro-Api-Key"
+)
+
+// Methods of authentication accepted by the routes, tried in order until one authenticates the
+// request. The config is read from "api.auth", for example:
+//
+//	{
+//		"default": ["jwt", "apikey"],
+//		"routes": {
+//			"webhooks": {"*": ["none"]},
+//			"partners": {"*": ["mtls"]},
+//			"billing": {"Billing.Invoice": ["mtls", "jwt"], "*": ["jwt"]}
+//		}
+//	}
+//
+// Routes are keyed by service then endpoint, "*" matching any endpoint of the service. Routes
+// which aren't listed accept the default methods.
+type Methods struct {
+	Default []Method                       `json:"default"`
+	Routes  map[string]map[string][]Method `json:"routes"`
+}
+
+// ParseMethods parses the methods from their JSON encoding
+func ParseMethods(b []byte) (*Methods, error) {
+	var m Methods
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if err := validMethods("default", m.Default, true); err != nil {
+		return nil, err
+	}
+	for srv, endpoints := range m.Routes {
+		for ep, methods := range endpoints {
+			if err := validMethods(srv+" "+ep, methods, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &m, nil
+}
+
+func validMethods(route string, methods []Method, optional bool) error {
+	if len(methods) == 0 && !optional {
+		return fmt.Errorf("no auth methods for %v", route)
+	}
+	for _, m := range methods {
+		switch m {
+		case JWT, APIKey, MTLS, None:
+		default:
+			return fmt.Errorf("unknown auth method %q for %v, expected jwt, apikey, mtls or none", m, route)
+		}
+	}
+	return nil
+}
+
+// For returns the methods accepted by the endpoint of the service
+func (m *Methods) For(service, endpoint string) []Method {
+	if m != nil {
+		if eps, ok := m.Routes[service]; ok {
+			if methods, ok := eps[endpoint]; ok {
+				return methods
+			}
+			if methods, ok := eps["*"]; ok {
+				return methods
+			}
+		}
+		if len(m.Default) > 0 {
+			return m.Default
+		}
+	}
+	return DefaultMethods
+}
+
+func accepts(methods []Method, method Method) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Source returns the encoded methods, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the methods from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+// methodsConfig caches the methods read from the source
+type methodsConfig struct {
+	source Source
+
+	sync.RWMutex
+	// raw is the encoding the methods were last parsed from
+	raw     []byte
+	methods *Methods
+}
+
+// get the methods, parsing them when they change. Invalid methods are logged and the last
+// valid methods kept.
+func (c *methodsConfig) get() *Methods {
+	if c == nil || c.source == nil {
+		return nil
+	}
+	raw := c.source()
+
+	c.RLock()
+	if bytes.Equal(raw, c.raw) {
+		defer c.RUnlock()
+		return c.methods
+	}
+	c.RUnlock()
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.raw = raw
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		c.methods = nil
+		return nil
+	}
+
+	m, err := ParseMethods(raw)
+	if err != nil {
+		logger.Errorf("Error parsing the auth methods in the config: %v", err)
+		return c.methods
+	}
+	c.methods = m
+	return m
+}
+
+var (
+	// DefaultKeyFailureTTL is how long a key the auth service rejected is rejected for without
+	// exchanging it again
+	DefaultKeyFailureTTL = time.Minute
+	// MaxKeyFailures is the number of rejected keys a client can send in the KeyFailureWindow,
+	// after which its keys are rejected without being exchanged until the window ends
+	MaxKeyFailures = 10
+	// KeyFailureWindow is the window the rejected keys of each client are counted over
+	KeyFailureWindow = time.Minute
+
+	errKeyRejected = fmt.Errorf("api key rejected")
+)
+
+// apiKeys exchanges API keys for tokens, caching the tokens until shortly before they expire so
+// the auth service isn't called on every request. Keys the auth service rejects are cached too,
+// and clients which keep sending rejected keys are limited, so the gateway can't be used to
+// guess credentials at the cost of a call to the auth service per request.
+type apiKeys struct {
+	sync.Mutex
+	// tokens keyed by the hash of the key
+	tokens map[[sha256.Size]byte]*goauth.Token
+	// rejected keys keyed by their hash, with the time they're rejected until
+	rejected map[[sha256.Size]byte]time.Time
+	// failures of clients in the current window, keyed by ip
+	failures map[string]*keyFailures
+}
+
+type keyFailures struct {
+	count int
+	since time.Time
+}
+
+// token returns the access token of the account the key is the credentials of, the key being
+// sent with the request
+func (k *apiKeys) token(req *http.Request, key string) (string, error) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", fmt.Errorf("invalid api key")
+	}
+
+	hash := sha256.Sum256([]byte(key))
+	ip := remoteIP(req)
+	now := time.Now()
+
+	k.Lock()
+	tok, ok := k.tokens[hash]
+	rejected := k.rejected[hash].After(now) || k.limited(ip, now)
+	k.Unlock()
+	if ok && tok.Expiry.After(now.Add(time.Minute)) {
+		return tok.AccessToken, nil
+	}
+	if rejected {
+		return "", errKeyRejected
+	}
+
+	tok, err := auth.TokenContext(origin(req, ip), goauth.WithCredentials(parts[0], parts[1]))
+	if err != nil {
+		// only the keys the auth service rejected are cached, not its failures
+		if verr := errors.Parse(err); verr != nil && (verr.Code == 400 || verr.Code == 401 || verr.Code == 403) {
+			k.reject(hash, ip, now)
+		}
+		return "", err
+	}
+
+	k.Lock()
+	defer k.Unlock()
+	if k.tokens == nil {
+		k.tokens = make(map[[sha256.Size]byte]*goauth.Token)
+	}
+	// drop the expired tokens so keys which are no longer used aren't kept forever
+	for h, t := range k.tokens {
+		if t.Expiry.Before(now) {
+			delete(k.tokens, h)
+		}
+	}
+	k.tokens[hash] = tok
+	return tok.AccessToken, nil
+}
+
+// limited returns true if the client has sent too many rejected keys in the window. The lock
+// must be held.
+func (k *apiKeys) limited(ip string, now time.Time) bool {
+	f, ok := k.failures[ip]
+	return ok && now.Sub(f.since) < KeyFailureWindow && f.count >= MaxKeyFailures
+}
+
+// reject the key sent by the client
+func (k *apiKeys) reject(hash [sha256.Size]byte, ip string, now time.Time) {
+	k.Lock()
+	defer k.Unlock()
+
+	if k.rejected == nil {
+		k.rejected = make(map[[sha256.Size]byte]time.Time)
+		k.failures = make(map[string]*keyFailures)
+	}
+	// drop the expired entries so the maps don't grow with every key and client ever rejected
+	for h, t := range k.rejected {
+		if !t.After(now) {
+			delete(k.rejected, h)
+		}
+	}
+	for c, f := range k.failures {
+		if now.Sub(f.since) >= KeyFailureWindow {
+			delete(k.failures, c)
+		}
+	}
+
+	k.rejected[hash] = now.Add(DefaultKeyFailureTTL)
+	f, ok := k.failures[ip]
+	if !ok {
+		f = &keyFailures{since: now}
+		k.failures[ip] = f
+	}
+	f.count++
+}
+
+// remoteIP returns the ip of the peer which sent the request
+func remoteIP(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
+// origin returns the context the key of the request is exchanged with, which forwards the
+// origin of the request to the auth service so it can evaluate the risk of issuing the token.
+// The auth service only reads the forwarded ip if it trusts the gateway as a proxy.
+func origin(req *http.Request, ip string) gocontext.Context {
+	fwd := ip
+	if prev := req.Header.Get("X-Forwarded-For"); len(prev) > 0 {
+		fwd = prev + ", " + ip
+	}
+	ctx := context.SetMetadata(context.DefaultContext, "X-Forwarded-For", fwd)
+	for _, h := range []string{"Micro-Device", "User-Agent"} {
+		if v := req.Header.Get(h); len(v) > 0 {
+			ctx = context.SetMetadata(ctx, h, v)
+		}
+	}
+	return ctx
+}
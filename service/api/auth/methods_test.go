@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/auth/noop"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/service/api/mtls"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testResolver struct{}
+
+func (testResolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	q := req.URL.Query()
+	return &resolver.Endpoint{Name: q.Get("service"), Method: q.Get("endpoint"), Domain: "micro"}, nil
+}
+
+func (testResolver) String() string {
+	return "test"
+}
+
+// testAuth authenticates the "user" token and exchanges the "partner:secret" credentials for
+// the "partner" token, only allowing authenticated accounts
+type testAuth struct {
+	goauth.Auth
+	exchanged int
+}
+
+func (a *testAuth) Inspect(token string) (*goauth.Account, error) {
+	switch token {
+	case "user", "partner":
+		return &goauth.Account{ID: token, Issuer: "micro"}, nil
+	}
+	return nil, goauth.ErrInvalidToken
+}
+
+func (a *testAuth) Token(opts ...goauth.TokenOption) (*goauth.Token, error) {
+	var o goauth.TokenOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ID != "partner" || o.Secret != "secret" {
+		return nil, goauth.ErrInvalidToken
+	}
+	a.exchanged++
+	return &goauth.Token{AccessToken: "partner", Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func (a *testAuth) Verify(acc *goauth.Account, res *goauth.Resource, opts ...goauth.VerifyOption) error {
+	if acc == nil {
+		return goauth.ErrForbidden
+	}
+	return nil
+}
+
+func TestParseMethods(t *testing.T) {
+	m, err := ParseMethods([]byte(`{
+		"default": ["jwt"],
+		"routes": {
+			"webhooks": {"*": ["none"]},
+			"billing": {"Billing.Invoice": ["mtls", "jwt"], "*": ["apikey"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tt := []struct {
+		Service  string
+		Endpoint string
+		Methods  []Method
+	}{
+		{"webhooks", "Webhooks.Stripe", []Method{None}},
+		{"billing", "Billing.Invoice", []Method{MTLS, JWT}},
+		{"billing", "Billing.List", []Method{APIKey}},
+		{"users", "Users.Read", []Method{JWT}},
+	}
+	for _, tc := range tt {
+		if got := m.For(tc.Service, tc.Endpoint); !reflect.DeepEqual(got, tc.Methods) {
+			t.Errorf("Expected %v for %v %v, got %v", tc.Methods, tc.Service, tc.Endpoint, got)
+		}
+	}
+
+	var none *Methods
+	if got := none.For("users", "Users.Read"); !reflect.DeepEqual(got, DefaultMethods) {
+		t.Errorf("Expected the default methods without a config, got %v", got)
+	}
+
+	for _, raw := range []string{
+		`{"routes": {"billing": {"*": ["password"]}}}`,
+		`{"routes": {"billing": {"*": []}}}`,
+		`{"default": ["basic"]}`,
+	} {
+		if _, err := ParseMethods([]byte(raw)); err == nil {
+			t.Errorf("Expected an error parsing %v", raw)
+		}
+	}
+}
+
+func TestWrapperMethods(t *testing.T) {
+	a := &testAuth{Auth: noop.NewAuth()}
+	defer func(d goauth.Auth) { auth.DefaultAuth = d }(auth.DefaultAuth)
+	auth.DefaultAuth = a
+
+	raw := []byte(`{
+		"routes": {
+			"webhooks": {"*": ["none"]},
+			"partners": {"*": ["mtls"]},
+			"billing": {"Billing.Invoice": ["apikey", "mtls"], "*": ["jwt", "none"]}
+		}
+	}`)
+
+	cert := &goauth.Account{ID: "acme", Type: mtls.AccountType, Issuer: "micro"}
+
+	tt := []struct {
+		Name          string
+		Service       string
+		Endpoint      string
+		Token         string
+		Key           string
+		Cert          *goauth.Account
+		Status        int
+		Authorization string
+	}{
+		{"Public", "webhooks", "Webhooks.Stripe", "", "", nil, http.StatusOK, ""},
+		{"PublicStripsToken", "webhooks", "Webhooks.Stripe", "user", "", nil, http.StatusOK, ""},
+		{"MTLS", "partners", "Partners.List", "", "", cert, http.StatusOK, ""},
+		{"MTLSRejectsToken", "partners", "Partners.List", "user", "", nil, http.StatusUnauthorized, ""},
+		{"APIKey", "billing", "Billing.Invoice", "", "partner:secret", nil, http.StatusOK, "Bearer partner"},
+		{"InvalidAPIKey", "billing", "Billing.Invoice", "", "partner:wrong", nil, http.StatusUnauthorized, ""},
+		{"FallsBackToMTLS", "billing", "Billing.Invoice", "", "partner:wrong", cert, http.StatusOK, ""},
+		{"JWT", "billing", "Billing.List", "user", "", nil, http.StatusOK, "Bearer user"},
+		{"AnonymousFallback", "billing", "Billing.List", "invalid", "", nil, http.StatusOK, "Bearer invalid"},
+		{"Default", "users", "Users.Read", "", "partner:secret", nil, http.StatusOK, "Bearer partner"},
+		{"DefaultUnauthenticated", "users", "Users.Read", "", "", nil, http.StatusUnauthorized, ""},
+	}
+
+	var authorization, key string
+	h := Wrapper(testResolver{}, "", WithMethods(func() []byte { return raw }))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		key = r.Header.Get(APIKeyHeader)
+	}))
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			authorization, key = "", ""
+			req := httptest.NewRequest("POST", "/?"+url.Values{"service": {tc.Service}, "endpoint": {tc.Endpoint}}.Encode(), nil)
+			if len(tc.Token) > 0 {
+				req.Header.Set("Authorization", goauth.BearerScheme+tc.Token)
+			}
+			if len(tc.Key) > 0 {
+				req.Header.Set(APIKeyHeader, tc.Key)
+			}
+			if tc.Cert != nil {
+				req = req.WithContext(goauth.ContextWithAccount(context.Background(), tc.Cert))
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tc.Status {
+				t.Fatalf("Expected status %v, got %v", tc.Status, w.Code)
+			}
+			if authorization != tc.Authorization {
+				t.Errorf("Expected the authorization %q to be forwarded, got %q", tc.Authorization, authorization)
+			}
+			if len(key) > 0 {
+				t.Errorf("Expected the api key not to be forwarded")
+			}
+		})
+	}
+
+	if a.exchanged != 1 {
+		t.Errorf("Expected the api key to be exchanged once, got %v", a.exchanged)
+	}
+}
+
+// contextAuth rejects every key, recording the origin forwarded with each exchange
+type contextAuth struct {
+	goauth.Auth
+	exchanges []string
+}
+
+func (a *contextAuth) TokenContext(ctx context.Context, opts ...goauth.TokenOption) (*goauth.Token, error) {
+	fwd, _ := metadata.Get(ctx, "X-Forwarded-For")
+	a.exchanges = append(a.exchanges, fwd)
+	return nil, errors.Unauthorized("auth.Auth.Token", "Secret not correct")
+}
+
+func TestAPIKeyFailures(t *testing.T) {
+	a := &contextAuth{Auth: noop.NewAuth()}
+	defer func(d goauth.Auth) { auth.DefaultAuth = d }(auth.DefaultAuth)
+	auth.DefaultAuth = a
+	defer func(m int) { MaxKeyFailures = m }(MaxKeyFailures)
+	MaxKeyFailures = 2
+
+	keys := &apiKeys{}
+	token := func(remote, key string) error {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.RemoteAddr = remote + ":1234"
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		_, err := keys.token(req, key)
+		return err
+	}
+
+	// rejected keys aren't exchanged again
+	token("1.1.1.1", "partner:wrong")
+	if err := token("1.1.1.1", "partner:wrong"); err != errKeyRejected {
+		t.Errorf("Expected the key to be rejected, got %v", err)
+	}
+	if len(a.exchanges) != 1 || a.exchanges[0] != "10.0.0.1, 1.1.1.1" {
+		t.Fatalf("Expected one exchange forwarding the origin, got %v", a.exchanges)
+	}
+
+	// clients which send too many rejected keys are limited
+	token("1.1.1.1", "partner:other")
+	if err := token("1.1.1.1", "partner:another"); err != errKeyRejected {
+		t.Errorf("Expected the client to be limited, got %v", err)
+	}
+	if err := token("2.2.2.2", "partner:another"); err == errKeyRejected || len(a.exchanges) != 3 {
+		t.Errorf("Expected other clients not to be limited, got %v after %v exchanges", err, len(a.exchanges))
+	}
+}
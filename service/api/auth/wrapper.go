@@ -14,18 +14,35 @@ import (
 	"github.com/micro/go-micro/v3/util/ctx"
 	inauth "github.com/micro/micro/v3/internal/auth"
 	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/api/mtls"
 	"github.com/micro/micro/v3/service/auth"
 	"github.com/micro/micro/v3/service/logger"
 )
 
+// Option of the wrapper
+type Option func(w *authWrapper)
+
+// WithMethods sets the source of the auth methods accepted by each route, by default every
+// route accepts DefaultMethods
+func WithMethods(s Source) Option {
+	return func(w *authWrapper) {
+		w.methods = &methodsConfig{source: s}
+	}
+}
+
 // Wrapper wraps a handler and authenticates requests
-func Wrapper(r resolver.Resolver, prefix string) server.Wrapper {
+func Wrapper(r resolver.Resolver, prefix string, opts ...Option) server.Wrapper {
 	return func(h http.Handler) http.Handler {
-		return authWrapper{
+		w := authWrapper{
 			handler:       h,
 			resolver:      r,
 			servicePrefix: prefix,
+			keys:          &apiKeys{},
 		}
+		for _, o := range opts {
+			o(&w)
+		}
+		return w
 	}
 }
 
@@ -33,6 +50,8 @@ type authWrapper struct {
 	handler       http.Handler
 	resolver      resolver.Resolver
 	servicePrefix string
+	methods       *methodsConfig
+	keys          *apiKeys
 }
 
 func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -58,28 +77,14 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		endpoint.Domain = r.Domain(req)
 	}
 
+	// Authenticate the request using the methods the route accepts. Some routes are
+	// unauthenticated, so the lack of an account doesn't necesserially mean a forbidden request.
+	methods := a.methods.get().For(endpoint.Name, endpoint.Method)
+	acc, public := a.authenticate(req, methods)
+
 	// Set the metadata so we can access it in micro api / web
 	req = req.WithContext(ctx.FromRequest(req))
 
-	// Extract the token from the request
-	var token string
-	if header := req.Header.Get("Authorization"); len(header) > 0 {
-		// Extract the auth token from the request
-		if strings.HasPrefix(header, goauth.BearerScheme) {
-			token = header[len(goauth.BearerScheme):]
-		}
-	} else {
-		// Get the token out the cookies if not provided in headers
-		if c, err := req.Cookie("micro-token"); err == nil && c != nil {
-			token = strings.TrimPrefix(c.Value, inauth.TokenCookieName+"=")
-			req.Header.Set("Authorization", goauth.BearerScheme+token)
-		}
-	}
-
-	// Get the account using the token, some are unauthenticated, so the lack of an
-	// account doesn't necesserially mean a forbidden request
-	acc, _ := auth.Inspect(token)
-
 	// Determine the namespace and set it in the header. If the user passed auth creds
 	// on the request, use the namespace that issued the account, otherwise check for
 	// the domain of the resolved endpoint.
@@ -97,6 +102,12 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		acc = nil
 	}
 
+	// Routes which accept requests without authentication don't need to be verified
+	if acc == nil && public {
+		a.handler.ServeHTTP(w, req)
+		return
+	}
+
 	// construct the resource name, e.g. home => foo.api.home
 	resName := endpoint.Name
 	if len(a.servicePrefix) > 0 {
@@ -121,7 +132,11 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// the resource they're requesting
 	res := &goauth.Resource{Type: "service", Name: resName, Endpoint: resEndpoint}
 	if err := auth.Verify(acc, res, verifyOpts...); err == nil {
-		// The account has the necessary permissions to access the resource
+		// The account has the necessary permissions to access the resource. It's set in the
+		// context so the handlers can trust it, unlike the headers of the request.
+		if acc != nil {
+			req = req.WithContext(goauth.ContextWithAccount(req.Context(), acc))
+		}
 		a.handler.ServeHTTP(w, req)
 		return
 	} else if err != goauth.ErrForbidden {
@@ -147,3 +162,65 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	loginWithRedirect := fmt.Sprintf("%v?%v", loginURL, params.Encode())
 	http.Redirect(w, req, loginWithRedirect, http.StatusTemporaryRedirect)
 }
+
+// authenticate the request with the first of the methods which succeeds, returning the account
+// and whether the route accepts the request without one
+func (a authWrapper) authenticate(req *http.Request, methods []Method) (*goauth.Account, bool) {
+	// the API key is never forwarded to services
+	key := req.Header.Get(APIKeyHeader)
+	req.Header.Del(APIKeyHeader)
+
+	// Extract the token from the request
+	var token string
+	if header := req.Header.Get("Authorization"); len(header) > 0 {
+		// Extract the auth token from the request
+		if strings.HasPrefix(header, goauth.BearerScheme) {
+			token = header[len(goauth.BearerScheme):]
+		}
+	} else {
+		// Get the token out the cookies if not provided in headers
+		if c, err := req.Cookie("micro-token"); err == nil && c != nil {
+			token = strings.TrimPrefix(c.Value, inauth.TokenCookieName+"=")
+			req.Header.Set("Authorization", goauth.BearerScheme+token)
+		}
+	}
+
+	// services would otherwise authenticate the token themselves on routes which don't accept it
+	if !accepts(methods, JWT) {
+		req.Header.Del("Authorization")
+	}
+
+	for _, m := range methods {
+		switch m {
+		case JWT:
+			if len(token) == 0 {
+				continue
+			}
+			if acc, err := auth.Inspect(token); err == nil {
+				return acc, false
+			}
+		case APIKey:
+			if len(key) == 0 {
+				continue
+			}
+			tok, err := a.keys.token(req, key)
+			if err != nil {
+				logger.Debugf("Error exchanging api key for a token: %v", err)
+				continue
+			}
+			if acc, err := auth.Inspect(tok); err == nil {
+				// services authenticate the request using the token the key was exchanged for
+				req.Header.Set("Authorization", goauth.BearerScheme+tok)
+				return acc, false
+			}
+		case MTLS:
+			if acc, ok := mtls.AccountFromContext(req.Context()); ok {
+				return acc, false
+			}
+		case None:
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
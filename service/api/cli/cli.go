@@ -0,0 +1,109 @@
+// Package cli implements the `micro api` subcommands
+// for example:
+//   micro api deprecations
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/micro/cli/v2"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/service/api/deprecation"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "api",
+		Usage:  "Commands for managing the api gateway",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "deprecations",
+				Usage:  "List the accounts which still call deprecated routes",
+				Action: deprecations,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "service",
+						Usage: "Only list the routes of the service",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "output format (json, table)",
+						Value: "table",
+					},
+				},
+			},
+		},
+	})
+}
+
+// deprecations is the entrypoint for micro api deprecations
+func deprecations(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	usage, err := deprecation.List(store.DefaultStore, ns)
+	if err != nil {
+		return errors.Wrap(err, "couldn't list the usage of deprecated routes")
+	}
+
+	var filtered []*deprecation.Usage
+	for _, u := range usage {
+		if srv := ctx.String("service"); len(srv) > 0 && u.Service != srv {
+			continue
+		}
+		filtered = append(filtered, u)
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].Service != filtered[j].Service {
+			return filtered[i].Service < filtered[j].Service
+		}
+		if filtered[i].Method != filtered[j].Method {
+			return filtered[i].Method < filtered[j].Method
+		}
+		return filtered[i].LastCalled > filtered[j].LastCalled
+	})
+
+	if ctx.String("output") == "json" {
+		b, err := json.MarshalIndent(filtered, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed marshalling JSON")
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if len(filtered) == 0 {
+		fmt.Println("No calls to deprecated routes recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tENDPOINT\tACCOUNT\tCALLS\tLAST CALLED\tSUNSET")
+	for _, u := range filtered {
+		account := u.Account
+		if len(account) == 0 {
+			account = "(unauthenticated)"
+		}
+		sunset := "-"
+		if u.Sunset > 0 {
+			sunset = time.Unix(u.Sunset, 0).UTC().Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%d\t%v\t%v\n", u.Service, u.Method, account, u.Calls,
+			humanize.Time(time.Unix(u.LastCalled, 0)), sunset)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,216 @@
+// Package deprecation marks API routes as deprecated. Responses from deprecated routes have the
+// Deprecation and Sunset headers set, and the accounts which still call them are recorded so
+// API owners can reach out to them before the route is removed. The routes are read from the
+// "api.deprecations" config, keyed by service and endpoint, "*" matching any endpoint of the
+// service, for example:
+//
+//	{
+//		"helloworld": {
+//			"Helloworld.Call": {"sunset": "2021-06-01", "link": "https://example.com/migrate"},
+//			"*": {"deprecated": "2021-01-01"}
+//		}
+//	}
+package deprecation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/api/server"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultPath is the path of the deprecated routes in the config
+	DefaultPath = []string{"api", "deprecations"}
+	// DefaultTable is the table the calls to deprecated routes are recorded in, in the database
+	// of the namespace which made them
+	DefaultTable = "deprecations"
+	// DefaultInterval is how often the calls to deprecated routes are written to the store
+	DefaultInterval = time.Minute
+)
+
+// Route which is deprecated
+type Route struct {
+	// Deprecated is when the route was deprecated, zero if no date was given
+	Deprecated time.Time
+	// Sunset is when the route will be removed, zero if no date has been set
+	Sunset time.Time
+	// Link to the documentation of the deprecation, e.g. how to migrate
+	Link string
+}
+
+// Headers sets the deprecation headers of the route
+func (r Route) Headers(h http.Header) {
+	if r.Deprecated.IsZero() {
+		h.Set("Deprecation", "true")
+	} else {
+		h.Set("Deprecation", r.Deprecated.UTC().Format(http.TimeFormat))
+	}
+	if !r.Sunset.IsZero() {
+		h.Set("Sunset", r.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if len(r.Link) > 0 {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, r.Link))
+	}
+}
+
+// Parse the routes from their JSON encoding, where dates are either RFC 3339 timestamps or
+// dates such as "2021-06-01"
+func Parse(b []byte) (map[string]map[string]Route, error) {
+	var raw map[string]map[string]struct {
+		Deprecated string `json:"deprecated"`
+		Sunset     string `json:"sunset"`
+		Link       string `json:"link"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	routes := make(map[string]map[string]Route, len(raw))
+	for service, endpoints := range raw {
+		routes[service] = make(map[string]Route, len(endpoints))
+		for endpoint, r := range endpoints {
+			route := Route{Link: r.Link}
+
+			var err error
+			if route.Deprecated, err = parseDate(r.Deprecated); err != nil {
+				return nil, fmt.Errorf("invalid deprecation date for %v %v: %v", service, endpoint, err)
+			}
+			if route.Sunset, err = parseDate(r.Sunset); err != nil {
+				return nil, fmt.Errorf("invalid sunset date for %v %v: %v", service, endpoint, err)
+			}
+			routes[service][endpoint] = route
+		}
+	}
+	return routes, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// Source returns the encoded routes, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the routes from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+// Wrapper wraps a handler and sets the deprecation headers of requests to the deprecated routes
+// read from the source, recording the accounts which made them in the store. The auth wrapper
+// must be applied after this wrapper so the endpoint has been resolved and the account verified.
+func Wrapper(source Source, s store.Store) server.Wrapper {
+	u := newUsage(s)
+	go u.run(DefaultInterval)
+
+	return func(h http.Handler) http.Handler {
+		return &deprecationWrapper{handler: h, source: source, usage: u}
+	}
+}
+
+type deprecationWrapper struct {
+	handler http.Handler
+	source  Source
+	usage   *usage
+
+	sync.RWMutex
+	// raw is the encoding the routes were last parsed from
+	raw    []byte
+	routes map[string]map[string]Route
+}
+
+func (d *deprecationWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	endpoint, ok := req.Context().Value(resolver.Endpoint{}).(*resolver.Endpoint)
+	if !ok {
+		d.handler.ServeHTTP(w, req)
+		return
+	}
+
+	route, ok := d.route(endpoint.Name, endpoint.Method)
+	if !ok {
+		d.handler.ServeHTTP(w, req)
+		return
+	}
+
+	route.Headers(w.Header())
+	ns, account := caller(req, endpoint)
+	d.usage.record(ns, endpoint.Name, endpoint.Method, account, route)
+	d.handler.ServeHTTP(w, req)
+}
+
+// route returns the deprecated route of the endpoint, parsing the routes if they've changed
+func (d *deprecationWrapper) route(service, endpoint string) (Route, bool) {
+	raw := d.source()
+
+	d.RLock()
+	if bytes.Equal(raw, d.raw) {
+		r, ok := lookupRoute(d.routes, service, endpoint)
+		d.RUnlock()
+		return r, ok
+	}
+	d.RUnlock()
+
+	d.Lock()
+	defer d.Unlock()
+
+	var routes map[string]map[string]Route
+	if len(bytes.TrimSpace(raw)) > 0 && !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		parsed, err := Parse(raw)
+		if err != nil {
+			// keep using the last valid routes
+			logger.Errorf("Error parsing deprecated routes: %v", err)
+			d.raw = raw
+			return lookupRoute(d.routes, service, endpoint)
+		}
+		routes = parsed
+	}
+
+	d.raw = raw
+	d.routes = routes
+	return lookupRoute(d.routes, service, endpoint)
+}
+
+func lookupRoute(routes map[string]map[string]Route, service, endpoint string) (Route, bool) {
+	endpoints, ok := routes[service]
+	if !ok {
+		return Route{}, false
+	}
+	if r, ok := endpoints[endpoint]; ok {
+		return r, true
+	}
+	r, ok := endpoints["*"]
+	return r, ok
+}
+
+// caller returns the namespace the call is recorded in and the ID of the account which made it,
+// blank if it's unauthenticated. Calls are recorded in the namespace which issued the account
+// verified by the auth wrapper, and unauthenticated calls in the namespace of the endpoint.
+func caller(req *http.Request, endpoint *resolver.Endpoint) (string, string) {
+	if acc, ok := goauth.AccountFromContext(req.Context()); ok && acc != nil {
+		return acc.Issuer, acc.ID
+	}
+	if len(endpoint.Domain) > 0 {
+		return endpoint.Domain, ""
+	}
+	return namespace.DefaultNamespace, ""
+}
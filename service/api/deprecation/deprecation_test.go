@@ -0,0 +1,120 @@
+package deprecation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+)
+
+func TestDeprecation(t *testing.T) {
+	raw := []byte(`{
+		"helloworld": {
+			"Helloworld.Call": {"sunset": "2021-06-01", "link": "https://example.com/migrate"},
+			"*": {"deprecated": "2021-01-01T00:00:00Z"}
+		}
+	}`)
+
+	s := memory.NewStore()
+	u := newUsage(s)
+	h := &deprecationWrapper{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		source:  func() []byte { return raw },
+		usage:   u,
+	}
+
+	serve := func(service, method string) http.Header {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set(namespace.NamespaceKey, "bar")
+		ep := &resolver.Endpoint{Name: service, Method: method}
+		ctx := context.WithValue(req.Context(), resolver.Endpoint{}, ep)
+		ctx = goauth.ContextWithAccount(ctx, &goauth.Account{ID: "john", Issuer: "foo"})
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	hdr := serve("helloworld", "Helloworld.Call")
+	if v := hdr.Get("Deprecation"); v != "true" {
+		t.Errorf("Expected Deprecation true, got %v", v)
+	}
+	if v := hdr.Get("Sunset"); v != "Tue, 01 Jun 2021 00:00:00 GMT" {
+		t.Errorf("Expected the sunset date, got %v", v)
+	}
+	if v := hdr.Get("Link"); v != `<https://example.com/migrate>; rel="deprecation"` {
+		t.Errorf("Expected the link, got %v", v)
+	}
+
+	hdr = serve("helloworld", "Helloworld.Stream")
+	if v := hdr.Get("Deprecation"); v != "Fri, 01 Jan 2021 00:00:00 GMT" {
+		t.Errorf("Expected the deprecation date, got %v", v)
+	}
+	if v := hdr.Get("Sunset"); len(v) > 0 {
+		t.Errorf("Expected no sunset, got %v", v)
+	}
+
+	if hdr = serve("greeter", "Greeter.Hello"); len(hdr.Get("Deprecation")) > 0 {
+		t.Errorf("Expected no deprecation headers for routes which aren't deprecated")
+	}
+
+	// the calls are recorded in the namespace which issued the account, not the one requested
+	serve("helloworld", "Helloworld.Call")
+	u.flush()
+	serve("helloworld", "Helloworld.Call")
+	u.flush()
+
+	usage, err := List(s, "foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calls := make(map[string]int64)
+	for _, u := range usage {
+		calls[u.Method] = u.Calls
+	}
+	if calls["Helloworld.Call"] != 3 || calls["Helloworld.Stream"] != 1 || len(calls) != 2 {
+		t.Errorf("Expected 3 calls to Helloworld.Call and 1 to Helloworld.Stream, got %v", calls)
+	}
+	if usage, _ := List(s, "bar"); len(usage) != 0 {
+		t.Errorf("Expected no calls to be recorded in the namespace requested, got %v", usage)
+	}
+}
+
+func TestUsageReplicas(t *testing.T) {
+	s := memory.NewStore()
+	route := Route{}
+
+	// each replica of the gateway flushes the calls it received concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		u := newUsage(s)
+		for j := 0; j < 5; j++ {
+			u.record("foo", "helloworld", "Helloworld.Call", "john", route)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.flush()
+		}()
+	}
+	wg.Wait()
+
+	usage, err := List(s, "foo")
+	if err != nil || len(usage) != 1 || usage[0].Calls != 50 {
+		t.Errorf("Expected the calls of every replica to be recorded, got %v: %v", usage, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse([]byte(`{"helloworld": {"*": {"sunset": "soon"}}}`)); err == nil {
+		t.Errorf("Expected an error parsing an invalid date")
+	}
+}
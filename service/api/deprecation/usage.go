@@ -0,0 +1,158 @@
+package deprecation
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+// Usage of a deprecated route by an account
+type Usage struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+	// Account which made the calls, blank for unauthenticated calls
+	Account string `json:"account"`
+	Calls   int64  `json:"calls"`
+	// FirstCalled and LastCalled are unix timestamps
+	FirstCalled int64 `json:"first_called"`
+	LastCalled  int64 `json:"last_called"`
+	// Sunset is the unix timestamp the route will be removed at, zero if not set
+	Sunset int64 `json:"sunset,omitempty"`
+}
+
+// Key of the usage in the store
+func (u *Usage) Key() string {
+	return strings.Join([]string{u.Service, u.Method, u.Account}, "/")
+}
+
+// List the usage of deprecated routes recorded for the namespace
+func List(s store.Store, ns string) ([]*Usage, error) {
+	recs, err := s.Read("", store.ReadFrom(ns, DefaultTable), store.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]*Usage, 0, len(recs))
+	for _, r := range recs {
+		var u Usage
+		if err := json.Unmarshal(r.Value, &u); err != nil {
+			logger.Errorf("Error decoding the usage of deprecated route %v: %v", r.Key, err)
+			continue
+		}
+		usage = append(usage, &u)
+	}
+	return usage, nil
+}
+
+// maxAttempts is the number of times the usage is added when it's updated concurrently
+const maxAttempts = 10
+
+// usage buffers the calls to deprecated routes until they're written to the store, so requests
+// don't wait on the store
+type usage struct {
+	store store.Store
+
+	sync.Mutex
+	// pending usage keyed by namespace then usage key
+	pending map[string]map[string]*Usage
+}
+
+func newUsage(s store.Store) *usage {
+	return &usage{store: s, pending: make(map[string]map[string]*Usage)}
+}
+
+func (u *usage) record(ns, service, method, account string, route Route) {
+	now := time.Now().Unix()
+	rec := &Usage{Service: service, Method: method, Account: account}
+	if !route.Sunset.IsZero() {
+		rec.Sunset = route.Sunset.Unix()
+	}
+
+	u.Lock()
+	defer u.Unlock()
+
+	if _, ok := u.pending[ns]; !ok {
+		u.pending[ns] = make(map[string]*Usage)
+	}
+	if p, ok := u.pending[ns][rec.Key()]; ok {
+		rec = p
+	} else {
+		rec.FirstCalled = now
+		u.pending[ns][rec.Key()] = rec
+	}
+	rec.Calls++
+	rec.LastCalled = now
+}
+
+func (u *usage) run(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for range t.C {
+		u.flush()
+	}
+}
+
+// flush the pending usage to the store, adding it to the usage already recorded
+func (u *usage) flush() {
+	u.Lock()
+	pending := u.pending
+	u.pending = make(map[string]map[string]*Usage)
+	u.Unlock()
+
+	for ns, recs := range pending {
+		for key, rec := range recs {
+			if err := u.add(ns, key, rec); err != nil {
+				logger.Errorf("Error recording the usage of deprecated route %v: %v", key, err)
+			}
+		}
+	}
+}
+
+// add the usage to the usage recorded in the store. Every replica of the gateway adds its
+// usage, so it's only written if the usage recorded is unchanged since it was read.
+func (u *usage) add(ns, key string, rec *Usage) error {
+	for i := 0; i < maxAttempts; i++ {
+		next := *rec
+		cond := bulk.Condition{NotExists: true}
+
+		existing, err := u.store.Read(key, store.ReadFrom(ns, DefaultTable))
+		if err != nil && err != store.ErrNotFound {
+			return err
+		} else if err == nil && len(existing) > 0 {
+			var prev Usage
+			if err := json.Unmarshal(existing[0].Value, &prev); err == nil {
+				next.Calls += prev.Calls
+				next.FirstCalled = prev.FirstCalled
+				if prev.LastCalled > next.LastCalled {
+					next.LastCalled = prev.LastCalled
+				}
+			}
+			cond = bulk.Condition{ETag: bulk.ETag(existing[0].Value)}
+		}
+
+		b, err := json.Marshal(&next)
+		if err != nil {
+			return err
+		}
+		upsert := &bulk.Upsert{Record: &store.Record{Key: key, Value: b}, Condition: cond}
+		results, err := bulk.Write(u.store, []*bulk.Upsert{upsert}, store.WriteTo(ns, DefaultTable))
+		if err != nil {
+			return err
+		} else if len(results) != 1 {
+			return errors.New("unexpected number of results")
+		}
+		if results[0].Written {
+			return nil
+		} else if !results[0].Conflict {
+			return errors.New(results[0].Error)
+		}
+	}
+	return errors.New("too many concurrent updates")
+}
@@ -0,0 +1,15 @@
+//go:build go1.19
+// +build go1.19
+
+package hints
+
+import "net/http"
+
+// earlyHintsSupported is true if informational responses can be written, which net/http
+// supports from go 1.19
+const earlyHintsSupported = true
+
+// writeEarlyHints sends the Link headers set on the response in a 103 Early Hints response
+func writeEarlyHints(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusEarlyHints)
+}
@@ -0,0 +1,12 @@
+//go:build !go1.19
+// +build !go1.19
+
+package hints
+
+import "net/http"
+
+// earlyHintsSupported is false since net/http before go 1.19 treats any status written as the
+// final one, so the hints are only sent as Link headers on the response
+const earlyHintsSupported = false
+
+func writeEarlyHints(w http.ResponseWriter) {}
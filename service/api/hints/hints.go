@@ -0,0 +1,242 @@
+// Package hints tells browsers which resources the pages of an API route depend on, so single
+// page apps served through the API fetch their critical assets sooner. The resources are
+// declared in the metadata of the endpoint serving the page, using Preload, and are sent as
+// preload Link headers on the response. Optionally they're sent ahead of the response as 103
+// Early Hints while the service is still handling the request, when built with go 1.19 or
+// later, or pushed over HTTP/2.
+package hints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/api/server"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	goserver "github.com/micro/go-micro/v3/server"
+)
+
+const (
+	// PreloadKey is the endpoint metadata listing the resources, e.g.
+	// "/app.js;as=script,/app.css;as=style"
+	PreloadKey = "preload"
+	// ModeKey is the endpoint metadata setting how the resources are sent
+	ModeKey = "preload_mode"
+)
+
+var (
+	// DefaultTTL is how long the resources of the endpoints of a service are cached for
+	DefaultTTL = time.Second * 30
+)
+
+// Mode of sending the resources to the browser
+type Mode string
+
+const (
+	// Link sets preload Link headers on the response
+	Link Mode = "link"
+	// EarlyHints also sends the Link headers in a 103 Early Hints response before the request
+	// is forwarded to the service. Builds with go versions before 1.19 fall back to Link.
+	EarlyHints Mode = "early_hints"
+	// Push also pushes the resources over HTTP/2, for clients which accept pushes
+	Push Mode = "push"
+)
+
+// Resource a page depends on
+type Resource struct {
+	// Path of the resource, e.g. /static/app.js
+	Path string
+	// As is the type of the resource, e.g. script, style, font or image
+	As string
+	// CrossOrigin is true if the resource is fetched with CORS, as fonts are
+	CrossOrigin bool
+}
+
+// Link returns the Link header value preloading the resource
+func (r Resource) Link() string {
+	link := fmt.Sprintf("<%s>; rel=preload", r.Path)
+	if len(r.As) > 0 {
+		link += "; as=" + r.As
+	}
+	if r.CrossOrigin {
+		link += "; crossorigin"
+	}
+	return link
+}
+
+func (r Resource) String() string {
+	s := r.Path
+	if len(r.As) > 0 {
+		s += ";as=" + r.As
+	}
+	if r.CrossOrigin {
+		s += ";crossorigin"
+	}
+	return s
+}
+
+// ParseResources parses the resources in the PreloadKey metadata
+func ParseResources(s string) []Resource {
+	var resources []Resource
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(entry, ";")
+		r := Resource{Path: strings.TrimSpace(parts[0])}
+		if len(r.Path) == 0 {
+			continue
+		}
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			switch {
+			case strings.HasPrefix(p, "as="):
+				r.As = strings.TrimPrefix(p, "as=")
+			case p == "crossorigin":
+				r.CrossOrigin = true
+			}
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// Preload declares the resources the pages of the endpoint depend on and how they're sent. The
+// endpoint keeps any other metadata it's registered with.
+func Preload(endpoint string, mode Mode, resources ...Resource) goserver.HandlerOption {
+	return func(o *goserver.HandlerOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]map[string]string)
+		}
+		md := make(map[string]string, len(o.Metadata[endpoint])+2)
+		for k, v := range o.Metadata[endpoint] {
+			md[k] = v
+		}
+
+		values := make([]string, len(resources))
+		for i, r := range resources {
+			values[i] = r.String()
+		}
+		md[PreloadKey] = strings.Join(values, ",")
+		md[ModeKey] = string(mode)
+		o.Metadata[endpoint] = md
+	}
+}
+
+// Wrapper wraps a handler and sends the resources the endpoints of requests depend on, read
+// from the endpoint metadata in the registry. The auth wrapper must be applied after this
+// wrapper so the endpoint has been resolved.
+func Wrapper(r goregistry.Registry) server.Wrapper {
+	return func(h http.Handler) http.Handler {
+		return &hintsWrapper{handler: h, registry: r, services: make(map[string]*hints)}
+	}
+}
+
+type hintsWrapper struct {
+	handler  http.Handler
+	registry goregistry.Registry
+
+	sync.RWMutex
+	// services are the hints of the endpoints of the services requested, keyed by domain and
+	// service name. Only services found in the registry are cached so the number of entries is
+	// bounded by the services registered.
+	services map[string]*hints
+}
+
+// hints of the endpoints of a service
+type hints struct {
+	endpoints map[string]endpointHints
+	expiry    time.Time
+}
+
+type endpointHints struct {
+	resources []Resource
+	mode      Mode
+}
+
+func (h *hintsWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// only pages fetched by the browser benefit from hints
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		h.handler.ServeHTTP(w, req)
+		return
+	}
+	endpoint, ok := req.Context().Value(resolver.Endpoint{}).(*resolver.Endpoint)
+	if !ok {
+		h.handler.ServeHTTP(w, req)
+		return
+	}
+
+	resources, mode := h.resources(endpoint)
+	if len(resources) == 0 {
+		h.handler.ServeHTTP(w, req)
+		return
+	}
+
+	for _, r := range resources {
+		w.Header().Add("Link", r.Link())
+	}
+
+	switch mode {
+	case EarlyHints:
+		// the Link headers are sent with the early hints and kept for the final response
+		if req.ProtoAtLeast(1, 1) {
+			writeEarlyHints(w)
+		}
+	case Push:
+		if p, ok := w.(http.Pusher); ok {
+			for _, r := range resources {
+				// only resources of the same origin can be pushed, the client may also
+				// have disabled pushes in which case the Link headers are used
+				if strings.HasPrefix(r.Path, "/") && !strings.HasPrefix(r.Path, "//") {
+					p.Push(r.Path, nil)
+				}
+			}
+		}
+	}
+
+	h.handler.ServeHTTP(w, req)
+}
+
+// resources returns the resources the endpoint depends on and how they're sent
+func (h *hintsWrapper) resources(endpoint *resolver.Endpoint) ([]Resource, Mode) {
+	key := endpoint.Domain + "/" + endpoint.Name
+
+	h.RLock()
+	cached, ok := h.services[key]
+	h.RUnlock()
+	if !ok || time.Now().After(cached.expiry) {
+		srvs, err := h.registry.GetService(endpoint.Name, goregistry.GetDomain(endpoint.Domain))
+		if err != nil {
+			return nil, Link
+		}
+		cached = parseHints(srvs)
+
+		h.Lock()
+		h.services[key] = cached
+		h.Unlock()
+	}
+
+	e, ok := cached.endpoints[endpoint.Method]
+	if !ok {
+		return nil, Link
+	}
+	return e.resources, e.mode
+}
+
+// parseHints parses the resources of the endpoints of the service
+func parseHints(srvs []*goregistry.Service) *hints {
+	hs := &hints{endpoints: make(map[string]endpointHints), expiry: time.Now().Add(DefaultTTL)}
+	for _, srv := range srvs {
+		for _, e := range srv.Endpoints {
+			if _, ok := hs.endpoints[e.Name]; ok || len(e.Metadata[PreloadKey]) == 0 {
+				continue
+			}
+			mode := Mode(e.Metadata[ModeKey])
+			if len(mode) == 0 {
+				mode = Link
+			}
+			hs.endpoints[e.Name] = endpointHints{resources: ParseResources(e.Metadata[PreloadKey]), mode: mode}
+		}
+	}
+	return hs
+}
@@ -0,0 +1,100 @@
+package hints
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	goserver "github.com/micro/go-micro/v3/server"
+)
+
+// recorder records the status codes written, including informational ones
+type recorder struct {
+	*httptest.ResponseRecorder
+	codes []int
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.codes = append(r.codes, code)
+	if code >= 200 {
+		r.ResponseRecorder.WriteHeader(code)
+	}
+}
+
+func TestParseResources(t *testing.T) {
+	resources := []Resource{
+		{Path: "/app.js", As: "script"},
+		{Path: "/font.woff2", As: "font", CrossOrigin: true},
+	}
+
+	var opts goserver.HandlerOptions
+	opts.Metadata = map[string]map[string]string{"Web.Index": {"foo": "bar"}}
+	Preload("Web.Index", EarlyHints, resources...)(&opts)
+
+	md := opts.Metadata["Web.Index"]
+	if md["foo"] != "bar" || md[ModeKey] != string(EarlyHints) {
+		t.Errorf("Expected the metadata to be merged, got %v", md)
+	}
+	if got := ParseResources(md[PreloadKey]); !reflect.DeepEqual(got, resources) {
+		t.Errorf("Expected %v, got %v", resources, got)
+	}
+	if l := resources[1].Link(); l != "</font.woff2>; rel=preload; as=font; crossorigin" {
+		t.Errorf("Unexpected link %v", l)
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	reg := memory.NewRegistry()
+	reg.Register(&goregistry.Service{
+		Name:  "web",
+		Nodes: []*goregistry.Node{{Id: "web-1", Address: "127.0.0.1:1"}},
+		Endpoints: []*goregistry.Endpoint{
+			{Name: "Web.Index", Metadata: map[string]string{PreloadKey: "/app.js;as=script", ModeKey: string(EarlyHints)}},
+			{Name: "Web.Api"},
+		},
+	})
+
+	h := Wrapper(reg)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method, endpoint string) *recorder {
+		req := httptest.NewRequest(method, "/", nil)
+		ctx := context.WithValue(req.Context(), resolver.Endpoint{}, &resolver.Endpoint{
+			Name: "web", Method: endpoint, Domain: goregistry.DefaultDomain,
+		})
+		w := &recorder{ResponseRecorder: httptest.NewRecorder()}
+		h.ServeHTTP(w, req.WithContext(ctx))
+		return w
+	}
+
+	codes := []int{http.StatusOK}
+	if earlyHintsSupported {
+		codes = []int{http.StatusEarlyHints, http.StatusOK}
+	}
+	w := serve(http.MethodGet, "Web.Index")
+	if !reflect.DeepEqual(w.codes, codes) {
+		t.Errorf("Expected codes %v, got %v", codes, w.codes)
+	}
+	if l := w.Header().Get("Link"); l != "</app.js>; rel=preload; as=script" {
+		t.Errorf("Expected the preload link header, got %q", l)
+	}
+
+	if w := serve(http.MethodPost, "Web.Index"); len(w.Header().Get("Link")) > 0 {
+		t.Errorf("Expected no hints for a POST")
+	}
+	if w := serve(http.MethodGet, "Web.Api"); len(w.codes) != 1 || len(w.Header().Get("Link")) > 0 {
+		t.Errorf("Expected no hints for an endpoint without resources, got %v", w.codes)
+	}
+
+	// the hints are cached until they expire
+	reg.Deregister(&goregistry.Service{Name: "web", Nodes: []*goregistry.Node{{Id: "web-1"}}})
+	if w := serve(http.MethodGet, "Web.Index"); len(w.Header().Get("Link")) == 0 {
+		t.Errorf("Expected the cached hints to be sent")
+	}
+}
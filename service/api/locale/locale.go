@@ -0,0 +1,75 @@
+// Package locale sets the language of requests to the API from their Accept-Language header
+package locale
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v3/api/server"
+	"github.com/micro/micro/v3/service/context"
+)
+
+// Wrapper wraps a handler and sets the language header propagated to services from the
+// Accept-Language header, unless the client provided one. The timezone and currency headers
+// are passed through as provided by the client.
+func Wrapper() server.Wrapper {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if len(req.Header.Get(context.LanguageKey)) == 0 {
+				if langs := ParseAcceptLanguage(req.Header.Get("Accept-Language")); len(langs) > 0 {
+					req.Header.Set(context.LanguageKey, langs[0])
+				}
+			}
+			h.ServeHTTP(w, req)
+		})
+	}
+}
+
+// ParseAcceptLanguage returns the languages in the Accept-Language header in order of
+// preference. The wildcard and languages with a quality of zero are omitted.
+func ParseAcceptLanguage(header string) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var langs []language
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		tag := strings.TrimSpace(params[0])
+		if len(tag) == 0 || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(p[2:], 64)
+			if err != nil {
+				q = 0
+			}
+			quality = q
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		langs = append(langs, language{tag, quality})
+	}
+
+	// languages of the same quality keep the order they were provided in
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].quality > langs[j].quality
+	})
+
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
@@ -0,0 +1,48 @@
+package locale
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tt := []struct {
+		Header string
+		Langs  []string
+	}{
+		{"", []string{}},
+		{"en-GB", []string{"en-GB"}},
+		{"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"fr-CH", "fr", "en", "de"}},
+		{"en;q=0.5, de", []string{"de", "en"}},
+		{"en;q=0, de;q=invalid, es", []string{"es"}},
+	}
+
+	for _, tc := range tt {
+		if langs := ParseAcceptLanguage(tc.Header); !reflect.DeepEqual(langs, tc.Langs) {
+			t.Errorf("Expected %v for %q, got %v", tc.Langs, tc.Header, langs)
+		}
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	var lang string
+	h := Wrapper()(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lang = req.Header.Get("Micro-Language")
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "de;q=0.8, en-GB")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if lang != "en-GB" {
+		t.Errorf("Expected the language to be set from the Accept-Language header, got %v", lang)
+	}
+
+	// the language provided by the client takes precedence
+	req.Header.Set("Micro-Language", "fr")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if lang != "fr" {
+		t.Errorf("Expected the language provided to be used, got %v", lang)
+	}
+}
@@ -0,0 +1,259 @@
+// Package mtls authenticates API requests with client certificates, for partners which mandate
+// mutual TLS rather than bearer tokens. The gateway must verify client certificates, by setting
+// --tls_client_ca_file, and --tls_client_auth=optional to only require them on the routes
+// configured here. The config is read from "api.mtls", for example:
+//
+//	{
+//		"routes": {"partners": ["*"], "billing": ["Billing.Invoice"]},
+//		"identities": [
+//			{"identity": "uri:spiffe://acme.com/gateway", "account": "acme", "scopes": ["partner"]},
+//			{"identity": "dns:*.example.com", "account": "example", "namespace": "example"}
+//		]
+//	}
+//
+// Requests to the routes, keyed by service with "*" matching any endpoint, must present a
+// certificate which maps to an account. Certificates map to the account of the first identity
+// rule which matches one of their identities: "cn:" followed by the common name, or "dns:",
+// "email:" or "uri:" followed by a subject alternative name. Rules can contain wildcards, as
+// matched by path.Match. Certificates which map to an account authenticate requests to any
+// route which doesn't have a bearer token, unless the route's auth methods in "api.auth" don't
+// include mtls.
+package mtls
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/api/server"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultPath is the path of the mTLS config
+	DefaultPath = []string{"api", "mtls"}
+	// IdentityHeader is set to the identity the account was mapped from. It's removed from
+	// incoming requests so it can be trusted by services.
+	IdentityHeader = "Micro-Client-Identity"
+	// AccountType is the type of the accounts mapped from certificates
+	AccountType = "certificate"
+)
+
+// Rule maps a certificate identity to an account
+type Rule struct {
+	// Identity to match, e.g. "dns:*.example.com"
+	Identity string `json:"identity"`
+	// Account the certificate authenticates as
+	Account string `json:"account"`
+	// Namespace the account belongs to, defaults to the default namespace
+	Namespace string `json:"namespace"`
+	// Scopes of the account
+	Scopes []string `json:"scopes"`
+}
+
+// Config of the routes which require client certificates and the rules mapping certificates to
+// accounts
+type Config struct {
+	Routes     map[string][]string `json:"routes"`
+	Identities []Rule              `json:"identities"`
+}
+
+// Parse the config from its JSON encoding
+func Parse(b []byte) (*Config, error) {
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	for _, r := range c.Identities {
+		if len(r.Account) == 0 {
+			return nil, fmt.Errorf("missing account for identity %q", r.Identity)
+		}
+		if _, err := path.Match(r.Identity, ""); err != nil {
+			return nil, fmt.Errorf("invalid identity %q: %v", r.Identity, err)
+		}
+	}
+	return &c, nil
+}
+
+// Required returns true if requests to the endpoint of the service require a certificate
+func (c *Config) Required(service, endpoint string) bool {
+	for _, e := range c.Routes[service] {
+		if e == "*" || e == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// Account returns the account the certificate maps to and the identity it was mapped from,
+// nil if no rule matches
+func (c *Config) Account(cert *x509.Certificate) (*goauth.Account, string) {
+	ids := Identities(cert)
+	for _, r := range c.Identities {
+		for _, id := range ids {
+			if ok, _ := path.Match(r.Identity, id); !ok {
+				continue
+			}
+			ns := r.Namespace
+			if len(ns) == 0 {
+				ns = namespace.DefaultNamespace
+			}
+			return &goauth.Account{
+				ID:       r.Account,
+				Type:     AccountType,
+				Issuer:   ns,
+				Scopes:   r.Scopes,
+				Metadata: map[string]string{"identity": id},
+			}, id
+		}
+	}
+	return nil, ""
+}
+
+// Identities returns the identities of a certificate which rules are matched against
+func Identities(cert *x509.Certificate) []string {
+	var ids []string
+	if len(cert.Subject.CommonName) > 0 {
+		ids = append(ids, "cn:"+cert.Subject.CommonName)
+	}
+	for _, n := range cert.DNSNames {
+		ids = append(ids, "dns:"+n)
+	}
+	for _, e := range cert.EmailAddresses {
+		ids = append(ids, "email:"+e)
+	}
+	for _, u := range cert.URIs {
+		ids = append(ids, "uri:"+u.String())
+	}
+	return ids
+}
+
+// Source returns the encoded config, blank if there is none
+type Source func() []byte
+
+// ConfigSource reads the config from DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+// Wrapper wraps a handler and authenticates requests by their client certificate, rejecting
+// requests to the routes which require one if they don't present a certificate which maps to an
+// account. The account is set in the request context for the auth wrapper, so this wrapper must
+// be applied after it.
+func Wrapper(r resolver.Resolver, source Source) server.Wrapper {
+	return func(h http.Handler) http.Handler {
+		return &mtlsWrapper{handler: h, resolver: r, source: source}
+	}
+}
+
+type mtlsWrapper struct {
+	handler  http.Handler
+	resolver resolver.Resolver
+	source   Source
+
+	sync.RWMutex
+	// raw is the encoding the config was last parsed from
+	raw    []byte
+	config *Config
+}
+
+func (m *mtlsWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	req.Header.Del(IdentityHeader)
+
+	c := m.getConfig()
+	if c == nil {
+		m.handler.ServeHTTP(w, req)
+		return
+	}
+
+	var required bool
+	if ep, err := m.resolver.Resolve(req); err == nil {
+		required = c.Required(ep.Name, ep.Method)
+	}
+
+	var cert *x509.Certificate
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.VerifiedChains[0]) > 0 {
+		cert = req.TLS.VerifiedChains[0][0]
+	}
+	if cert == nil {
+		if required {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		m.handler.ServeHTTP(w, req)
+		return
+	}
+
+	acc, id := c.Account(cert)
+	if acc == nil {
+		if required {
+			http.Error(w, "client certificate not authorized", http.StatusForbidden)
+			return
+		}
+		m.handler.ServeHTTP(w, req)
+		return
+	}
+
+	// a certificate authenticates as the account even if a token is passed on routes which
+	// require it, elsewhere the token takes precedence
+	if required {
+		req.Header.Del("Authorization")
+	}
+	if len(req.Header.Get(namespace.NamespaceKey)) == 0 {
+		req.Header.Set(namespace.NamespaceKey, acc.Issuer)
+	}
+	req.Header.Set(IdentityHeader, id)
+	ctx := goauth.ContextWithAccount(req.Context(), acc)
+	m.handler.ServeHTTP(w, req.WithContext(ctx))
+}
+
+// getConfig returns the config, parsing it if it's changed
+func (m *mtlsWrapper) getConfig() *Config {
+	raw := m.source()
+
+	m.RLock()
+	if bytes.Equal(raw, m.raw) {
+		defer m.RUnlock()
+		return m.config
+	}
+	m.RUnlock()
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.raw = raw
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		m.config = nil
+		return nil
+	}
+
+	c, err := Parse(raw)
+	if err != nil {
+		// keep using the last valid config
+		logger.Errorf("Error parsing the mTLS config: %v", err)
+		return m.config
+	}
+	m.config = c
+	return c
+}
+
+// AccountFromContext returns the account a client certificate authenticated the request as
+func AccountFromContext(ctx context.Context) (*goauth.Account, bool) {
+	acc, ok := goauth.AccountFromContext(ctx)
+	if !ok || acc.Type != AccountType {
+		return nil, false
+	}
+	return acc, true
+}
@@ -0,0 +1,95 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+)
+
+type testResolver struct{}
+
+func (testResolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	return &resolver.Endpoint{Name: req.URL.Query().Get("service"), Method: req.URL.Query().Get("endpoint")}, nil
+}
+
+func (testResolver) String() string {
+	return "test"
+}
+
+func TestWrapper(t *testing.T) {
+	raw := []byte(`{
+		"routes": {"partners": ["*"], "billing": ["Billing.Invoice"]},
+		"identities": [
+			{"identity": "uri:spiffe://acme.com/gateway", "account": "acme", "scopes": ["partner"]},
+			{"identity": "dns:*.example.com", "account": "example", "namespace": "example"}
+		]
+	}`)
+
+	spiffe, _ := url.Parse("spiffe://acme.com/gateway")
+	acme := &x509.Certificate{Subject: pkix.Name{CommonName: "acme"}, URIs: []*url.URL{spiffe}}
+	example := &x509.Certificate{DNSNames: []string{"api.example.com"}}
+	other := &x509.Certificate{Subject: pkix.Name{CommonName: "other"}}
+
+	tt := []struct {
+		Name     string
+		Service  string
+		Endpoint string
+		Cert     *x509.Certificate
+		Status   int
+		Account  string
+	}{
+		{"NoCert", "partners", "Partners.List", nil, http.StatusUnauthorized, ""},
+		{"UnmappedCert", "partners", "Partners.List", other, http.StatusForbidden, ""},
+		{"URI", "partners", "Partners.List", acme, http.StatusOK, "acme"},
+		{"DNS", "billing", "Billing.Invoice", example, http.StatusOK, "example"},
+		{"NotRequired", "billing", "Billing.List", nil, http.StatusOK, ""},
+		{"Optional", "billing", "Billing.List", acme, http.StatusOK, "acme"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			var account, identity string
+			h := Wrapper(testResolver{}, func() []byte { return raw })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if acc, ok := AccountFromContext(r.Context()); ok {
+					account = acc.ID
+				}
+				identity = r.Header.Get(IdentityHeader)
+			}))
+
+			req := httptest.NewRequest("POST", "/?service="+tc.Service+"&endpoint="+tc.Endpoint, nil)
+			req.Header.Set(IdentityHeader, "spoofed")
+			if tc.Cert != nil {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tc.Cert}}}
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tc.Status {
+				t.Fatalf("Expected status %v, got %v", tc.Status, w.Code)
+			}
+			if account != tc.Account {
+				t.Errorf("Expected account %q, got %q", tc.Account, account)
+			}
+			if identity == "spoofed" {
+				t.Errorf("Expected the identity header to be removed")
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, raw := range []string{
+		`{"identities": [{"identity": "cn:foo"}]}`,
+		`{"identities": [{"identity": "cn:[", "account": "foo"}]}`,
+	} {
+		if _, err := Parse([]byte(raw)); err == nil {
+			t.Errorf("Expected an error parsing %v", raw)
+		}
+	}
+}
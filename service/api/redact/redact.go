@@ -0,0 +1,114 @@
+// Package redact redacts the classified fields of API responses for callers without the scope
+// required to read them. The fields are classified in the metadata of the endpoints using
+// classify.Classify, so the fields are redacted by the API gateway even when the service
+// doesn't redact them itself.
+package redact
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/api/server"
+	goauth "github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/auth/classify"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// Wrapper wraps a handler and redacts the classified fields of the JSON responses of endpoints
+// for callers the policy doesn't allow to read them. The auth wrapper must be applied after
+// this wrapper so the endpoint has been resolved.
+func Wrapper(r goregistry.Registry, p classify.Policy) server.Wrapper {
+	return func(h http.Handler) http.Handler {
+		return redactWrapper{handler: h, registry: r, policy: p}
+	}
+}
+
+type redactWrapper struct {
+	handler  http.Handler
+	registry goregistry.Registry
+	policy   classify.Policy
+}
+
+func (rw redactWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	endpoint, ok := req.Context().Value(resolver.Endpoint{}).(*resolver.Endpoint)
+	if !ok {
+		rw.handler.ServeHTTP(w, req)
+		return
+	}
+	fields := classify.Lookup(rw.registry, endpoint.Name, endpoint.Method, goregistry.GetDomain(endpoint.Domain))
+	if len(fields) == 0 {
+		rw.handler.ServeHTTP(w, req)
+		return
+	}
+	paths := classify.Fields(fields, scopes(req), rw.policy)
+	if len(paths) == 0 {
+		rw.handler.ServeHTTP(w, req)
+		return
+	}
+
+	// buffer the response so the fields are redacted before any of it is sent
+	buf := &buffer{header: make(http.Header), code: http.StatusOK}
+	rw.handler.ServeHTTP(buf, req)
+
+	body := buf.body.Bytes()
+	if isJSON(buf.header.Get("Content-Type")) && len(body) > 0 {
+		b, err := classify.RedactJSON(body, paths)
+		if err != nil {
+			// never send the classified fields if they can't be redacted
+			logger.Errorf("Error redacting the response of %v.%v: %v", endpoint.Name, endpoint.Method, err)
+			http.Error(w, "Error redacting the response", http.StatusInternalServerError)
+			return
+		}
+		body = b
+	}
+
+	for k, v := range buf.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(buf.code)
+	w.Write(body)
+}
+
+// isJSON returns true if the content type is JSON, responses with no content type are assumed
+// to be JSON as that's what the API returns by default
+func isJSON(ct string) bool {
+	return len(ct) == 0 || strings.HasPrefix(ct, "application/json")
+}
+
+// scopes returns the scopes of the account which made the request, nil if it's unauthenticated
+func scopes(req *http.Request) []string {
+	header := req.Header.Get("Authorization")
+	if !strings.HasPrefix(header, goauth.BearerScheme) {
+		return nil
+	}
+	acc, err := auth.Inspect(strings.TrimPrefix(header, goauth.BearerScheme))
+	if err != nil {
+		return nil
+	}
+	return acc.Scopes
+}
+
+// buffer is a response writer which buffers the response
+type buffer struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (b *buffer) Header() http.Header {
+	return b.header
+}
+
+func (b *buffer) WriteHeader(code int) {
+	b.code = code
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
@@ -0,0 +1,54 @@
+package redact
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/auth/classify"
+)
+
+func TestWrapper(t *testing.T) {
+	reg := memory.NewRegistry()
+	reg.Register(&goregistry.Service{
+		Name:  "users",
+		Nodes: []*goregistry.Node{{Id: "users-1", Address: "127.0.0.1:1"}},
+		Endpoints: []*goregistry.Endpoint{
+			{Name: "Users.Read", Metadata: map[string]string{
+				classify.Key: classify.Format(map[string]classify.Class{"user.email": classify.PII}),
+			}},
+			{Name: "Users.List"},
+		},
+	})
+
+	h := Wrapper(reg, classify.DefaultPolicy)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user":{"name":"john","email":"john@example.com"}}`))
+	}))
+
+	serve := func(endpoint string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := context.WithValue(req.Context(), resolver.Endpoint{}, &resolver.Endpoint{
+			Name: "users", Method: endpoint, Domain: goregistry.DefaultDomain,
+		})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req.WithContext(ctx))
+		return w
+	}
+
+	w := serve("Users.Read")
+	if body := w.Body.String(); body != `{"user":{"email":"[REDACTED]","name":"john"}}` {
+		t.Errorf("Expected the email to be redacted, got %v", body)
+	}
+	if l := w.Header().Get("Content-Length"); l != "45" {
+		t.Errorf("Expected the content length of the redacted body, got %v", l)
+	}
+
+	if body := serve("Users.List").Body.String(); body != `{"user":{"name":"john","email":"john@example.com"}}` {
+		t.Errorf("Expected the response of an endpoint without classified fields to be unchanged, got %v", body)
+	}
+}
@@ -0,0 +1,213 @@
+// Package tenant isolates the resources used by the api gateway for each namespace, so one
+// tenant's traffic spike or slow backend cannot degrade the other tenants sharing the gateway.
+package tenant
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+)
+
+var (
+	// DefaultMaxConcurrent is the number of requests a namespace can have in flight
+	DefaultMaxConcurrent = 1000
+	// DefaultMaxQueue is the number of requests a namespace can have waiting for one of
+	// its in flight requests to complete
+	DefaultMaxQueue = 1000
+	// DefaultQueueTimeout is how long a request waits in the queue before it's rejected
+	DefaultQueueTimeout = time.Second * 10
+	// DefaultMaxTenants is the number of namespaces whose requests are tracked
+	DefaultMaxTenants = 1000
+)
+
+var (
+	errQueueFull    = errors.New("Too many requests for this namespace")
+	errQueueTimeout = errors.New("Timed out waiting for the requests of this namespace to complete")
+)
+
+// Options for the tenant handler
+type Options struct {
+	// MaxConcurrent is the number of requests a namespace can have in flight
+	MaxConcurrent int
+	// MaxQueue is the number of requests a namespace can have queued
+	MaxQueue int
+	// QueueTimeout is how long a request can be queued for
+	QueueTimeout time.Duration
+	// MaxTenants is the number of namespaces tracked, the least recently used are evicted once
+	// it's reached. Zero for no limit.
+	MaxTenants int
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// MaxConcurrent sets the number of requests a namespace can have in flight
+func MaxConcurrent(n int) Option {
+	return func(o *Options) {
+		o.MaxConcurrent = n
+	}
+}
+
+// MaxQueue sets the number of requests a namespace can have queued
+func MaxQueue(n int) Option {
+	return func(o *Options) {
+		o.MaxQueue = n
+	}
+}
+
+// QueueTimeout sets how long a request can be queued for
+func QueueTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.QueueTimeout = d
+	}
+}
+
+// MaxTenants sets the number of namespaces tracked
+func MaxTenants(n int) Option {
+	return func(o *Options) {
+		o.MaxTenants = n
+	}
+}
+
+// NewHandler returns a http.Handler which limits the requests each namespace can have in flight
+// to the handler. The namespace is the issuer of the account the auth wrapper authenticated the
+// request as, since the headers of the request can be set by the caller. Requests without an
+// account share the limits of the anonymous tenant.
+func NewHandler(h http.Handler, opts ...Option) http.Handler {
+	options := Options{
+		MaxConcurrent: DefaultMaxConcurrent,
+		MaxQueue:      DefaultMaxQueue,
+		QueueTimeout:  DefaultQueueTimeout,
+		MaxTenants:    DefaultMaxTenants,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &tenantHandler{
+		handler: h,
+		opts:    options,
+		tenants: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+type tenantHandler struct {
+	handler http.Handler
+	opts    Options
+
+	sync.Mutex
+	tenants map[string]*list.Element
+	// lru is the tenants, the most recently used at the front
+	lru *list.List
+}
+
+// tenant are the resources of a namespace
+type tenant struct {
+	namespace string
+	// slots is a semaphore of the requests in flight
+	slots chan struct{}
+	// queue is a semaphore of the requests waiting for a slot
+	queue chan struct{}
+}
+
+func (t *tenantHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var ns string
+	if acc, ok := auth.AccountFromContext(req.Context()); ok && acc != nil {
+		ns = acc.Issuer
+	}
+	tn := t.tenant(ns)
+
+	release, err := tn.acquire(req.Context(), t.opts.QueueTimeout)
+	if err == errQueueFull {
+		w.Header().Set("Retry-After", strconv.Itoa(int(t.opts.QueueTimeout.Seconds())))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	} else if err == errQueueTimeout {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		// the request was cancelled while queued
+		return
+	}
+	defer release()
+
+	t.handler.ServeHTTP(w, req)
+}
+
+// acquire a slot for a request, waiting in the queue if the namespace has no free slots. The
+// returned func releases the slot.
+func (tn *tenant) acquire(ctx context.Context, timeout time.Duration) (func(), error) {
+	if tn.slots == nil {
+		return func() {}, nil
+	}
+	release := func() { <-tn.slots }
+
+	select {
+	case tn.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	// join the queue
+	if tn.queue == nil {
+		return nil, errQueueFull
+	}
+	select {
+	case tn.queue <- struct{}{}:
+		defer func() { <-tn.queue }()
+	default:
+		return nil, errQueueFull
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	select {
+	case tn.slots <- struct{}{}:
+		return release, nil
+	case <-expired:
+		return nil, errQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// tenant returns the resources of the namespace, creating them if it's the first request. The
+// least recently used tenant is evicted once there are too many, its requests in flight
+// releasing the slots of the evicted resources.
+func (t *tenantHandler) tenant(ns string) *tenant {
+	t.Lock()
+	defer t.Unlock()
+
+	if e, ok := t.tenants[ns]; ok {
+		t.lru.MoveToFront(e)
+		return e.Value.(*tenant)
+	}
+
+	tn := &tenant{namespace: ns}
+	if t.opts.MaxConcurrent > 0 {
+		tn.slots = make(chan struct{}, t.opts.MaxConcurrent)
+	}
+	if t.opts.MaxQueue > 0 {
+		tn.queue = make(chan struct{}, t.opts.MaxQueue)
+	}
+	t.tenants[ns] = t.lru.PushFront(tn)
+
+	if t.opts.MaxTenants > 0 && t.lru.Len() > t.opts.MaxTenants {
+		e := t.lru.Back()
+		t.lru.Remove(e)
+		delete(t.tenants, e.Value.(*tenant).namespace)
+	}
+	return tn
+}
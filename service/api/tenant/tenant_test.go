@@ -0,0 +1,106 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+)
+
+func TestIsolation(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{}, 10)
+
+	h := NewHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if acc, _ := auth.AccountFromContext(req.Context()); acc.Issuer == "slow" {
+			started <- struct{}{}
+			<-block
+		}
+	}), MaxConcurrent(1), MaxQueue(1), QueueTimeout(time.Millisecond*50))
+
+	serve := func(ns string) int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req = req.WithContext(auth.ContextWithAccount(req.Context(), &auth.Account{ID: "john", Issuer: ns}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// fill the slot of the slow namespace
+	go serve("slow")
+	<-started
+
+	// queue a request which times out
+	timeout := make(chan int)
+	go func() { timeout <- serve("slow") }()
+	time.Sleep(time.Millisecond * 10)
+
+	// the queue is full so the next request is rejected
+	if code := serve("slow"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected %v for a full queue, got %v", http.StatusTooManyRequests, code)
+	}
+	if code := <-timeout; code != http.StatusServiceUnavailable {
+		t.Errorf("Expected %v for a queue timeout, got %v", http.StatusServiceUnavailable, code)
+	}
+
+	// other namespaces are unaffected
+	if code := serve("fast"); code != http.StatusOK {
+		t.Errorf("Expected %v for another namespace, got %v", http.StatusOK, code)
+	}
+
+	// the namespace header can't be used to choose the tenant
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Micro-Namespace", "fast")
+	req = req.WithContext(auth.ContextWithAccount(req.Context(), &auth.Account{ID: "john", Issuer: "slow"}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests && rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the request to be limited as the account's namespace, got %v", rec.Code)
+	}
+
+	close(block)
+}
+
+func TestQueue(t *testing.T) {
+	tn := &tenant{slots: make(chan struct{}, 1), queue: make(chan struct{}, 1)}
+
+	release, err := tn.acquire(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error acquiring a free slot: %v", err)
+	}
+
+	// release the slot while the next request is queued
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		release()
+	}()
+
+	if _, err := tn.acquire(context.Background(), time.Second); err != nil {
+		t.Fatalf("Expected the queued request to acquire the released slot, got %v", err)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	h := NewHandler(http.NotFoundHandler(), MaxTenants(2)).(*tenantHandler)
+
+	foo := h.tenant("foo")
+	h.tenant("bar")
+	if h.tenant("foo") != foo {
+		t.Fatalf("Expected the tenant to be reused")
+	}
+
+	// bar is the least recently used
+	h.tenant("baz")
+	if len(h.tenants) != 2 {
+		t.Fatalf("Expected 2 tenants, got %v", len(h.tenants))
+	}
+	if _, ok := h.tenants["bar"]; ok {
+		t.Errorf("Expected the least recently used tenant to be evicted")
+	}
+	if h.tenant("foo") != foo {
+		t.Errorf("Expected the recently used tenant to be kept")
+	}
+}
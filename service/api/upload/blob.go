@@ -0,0 +1,66 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Blobs stores the uploaded files
+type Blobs interface {
+	// Put streams the blob into the store, returning the number of bytes written
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Delete the blob, e.g. when an upload is rejected after it's been stored
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFileBlobs returns blobs stored in the directory, e.g. a volume shared with the services
+// handling the uploads
+func NewFileBlobs(dir string) Blobs {
+	return &fileBlobs{dir: dir}
+}
+
+type fileBlobs struct {
+	dir string
+}
+
+func (f *fileBlobs) path(key string) string {
+	// keys are generated by the gateway but shouldn't escape the directory regardless
+	return filepath.Join(f.dir, filepath.FromSlash(strings.TrimLeft(filepath.Clean("/"+key), "/")))
+}
+
+func (f *fileBlobs) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return 0, err
+	}
+
+	// write to a temporary file so a partial upload is never visible under the key
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".upload-")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return n, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return n, err
+	}
+	return n, nil
+}
+
+func (f *fileBlobs) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(f.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
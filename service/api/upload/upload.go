@@ -0,0 +1,326 @@
+// Package upload streams the files in multipart/form-data requests to the API into a blob store
+// and passes references to them to the backend, so uploads are never buffered in memory.
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/api/server"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultMaxSize is the maximum size of an uploaded file in bytes
+	DefaultMaxSize int64 = 32 << 20
+	// DefaultMaxTotalSize is the maximum size of all the files uploaded in a request in bytes
+	DefaultMaxTotalSize int64 = 128 << 20
+	// DefaultMaxFiles is the maximum number of files uploaded in a request
+	DefaultMaxFiles = 16
+	// DefaultMaxFieldSize is the maximum size of the form fields which aren't files
+	DefaultMaxFieldSize int64 = 1 << 20
+
+	errTooLarge = errors.New("file too large")
+)
+
+// ScanFunc scans the content of an uploaded file as it's streamed to the blob store, e.g. for
+// viruses. The upload is rejected and the blob deleted if it returns an error.
+type ScanFunc func(ctx context.Context, filename string, r io.Reader) error
+
+// Reference to an uploaded file, which replaces the file in the request passed to the backend
+type Reference struct {
+	// Key of the blob in the store
+	Key         string `json:"key"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Options for the upload wrapper
+type Options struct {
+	// MaxSize of each file in bytes
+	MaxSize int64
+	// MaxTotalSize of all the files in a request in bytes
+	MaxTotalSize int64
+	// MaxFiles in a request
+	MaxFiles int
+	// Types of file which can be uploaded, e.g. image/png or image/, all types are allowed
+	// if empty. The type of a file is detected from its content, not the type the client
+	// declares.
+	Types []string
+	// Scan the files as they're uploaded
+	Scan ScanFunc
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// MaxSize sets the maximum size of each file in bytes
+func MaxSize(n int64) Option {
+	return func(o *Options) {
+		o.MaxSize = n
+	}
+}
+
+// MaxTotalSize sets the maximum size of all the files in a request in bytes
+func MaxTotalSize(n int64) Option {
+	return func(o *Options) {
+		o.MaxTotalSize = n
+	}
+}
+
+// MaxFiles sets the maximum number of files in a request
+func MaxFiles(n int) Option {
+	return func(o *Options) {
+		o.MaxFiles = n
+	}
+}
+
+// Types sets the types of file which can be uploaded. Types ending in a slash match any
+// subtype, e.g. image/
+func Types(types ...string) Option {
+	return func(o *Options) {
+		o.Types = types
+	}
+}
+
+// Scan sets the function files are scanned with as they're uploaded
+func Scan(fn ScanFunc) Option {
+	return func(o *Options) {
+		o.Scan = fn
+	}
+}
+
+// Wrapper wraps a handler and streams the files in multipart/form-data requests into the blob
+// store. The request is passed to the handler as JSON with each file replaced by its Reference,
+// and each field by its value. Fields with multiple values are passed as arrays. The auth
+// wrapper must be applied after this wrapper so only authorized requests are stored.
+func Wrapper(blobs Blobs, opts ...Option) server.Wrapper {
+	options := Options{MaxSize: DefaultMaxSize, MaxTotalSize: DefaultMaxTotalSize, MaxFiles: DefaultMaxFiles}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return func(h http.Handler) http.Handler {
+		return uploadWrapper{handler: h, blobs: blobs, options: options}
+	}
+}
+
+type uploadWrapper struct {
+	handler http.Handler
+	blobs   Blobs
+	options Options
+}
+
+func (u uploadWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !isMultipart(req) {
+		u.handler.ServeHTTP(w, req)
+		return
+	}
+
+	mr, err := req.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// blobs are namespaced by the domain of the endpoint
+	ns := goregistry.DefaultDomain
+	if e, ok := req.Context().Value(resolver.Endpoint{}).(*resolver.Endpoint); ok && len(e.Domain) > 0 {
+		ns = e.Domain
+	}
+
+	var stored []string
+	fail := func(err error, status int) {
+		for _, k := range stored {
+			if err := u.blobs.Delete(req.Context(), k); err != nil {
+				logger.Errorf("Error deleting upload %v: %v", k, err)
+			}
+		}
+		http.Error(w, err.Error(), status)
+	}
+
+	fields := make(map[string]interface{})
+	var fieldSize, totalSize int64
+	var files int
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			fail(err, http.StatusBadRequest)
+			return
+		}
+
+		name := part.FormName()
+		if len(name) == 0 {
+			part.Close()
+			continue
+		}
+
+		if len(part.FileName()) == 0 {
+			b, err := ioutil.ReadAll(io.LimitReader(part, DefaultMaxFieldSize-fieldSize+1))
+			part.Close()
+			if err != nil {
+				fail(err, http.StatusBadRequest)
+				return
+			}
+			if fieldSize += int64(len(b)); fieldSize > DefaultMaxFieldSize {
+				fail(errors.New("form fields too large"), http.StatusRequestEntityTooLarge)
+				return
+			}
+			add(fields, name, string(b))
+			continue
+		}
+
+		if files++; files > u.options.MaxFiles {
+			part.Close()
+			fail(fmt.Errorf("more than %d files uploaded", u.options.MaxFiles), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		ref, status, err := u.store(req.Context(), ns, part, u.options.MaxTotalSize-totalSize)
+		part.Close()
+		totalSize += ref.Size
+		if len(ref.Key) > 0 {
+			stored = append(stored, ref.Key)
+		}
+		if err != nil {
+			fail(err, status)
+			return
+		}
+		add(fields, name, ref)
+	}
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		fail(err, http.StatusInternalServerError)
+		return
+	}
+
+	// pass the references to the handler in place of the multipart body
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	req.Header.Set("Content-Type", "application/json")
+	u.handler.ServeHTTP(w, req)
+}
+
+// store the file part in the blob store, if it's no larger than the remaining bytes of the
+// request. The key of the reference is set if the blob may have been stored, even if an error
+// is returned, so it can be deleted.
+func (u uploadWrapper) store(ctx context.Context, ns string, part *multipart.Part, remaining int64) (*Reference, int, error) {
+	ref := &Reference{Filename: part.FileName()}
+
+	// the type is detected from the content, the type declared by the client can't be trusted
+	br := bufio.NewReaderSize(part, 512)
+	head, _ := br.Peek(512)
+	ref.ContentType = http.DetectContentType(head)
+	if !u.allowed(ref.ContentType) {
+		return ref, http.StatusUnsupportedMediaType, fmt.Errorf("file type %v not allowed", ref.ContentType)
+	}
+
+	limit, tooLarge := u.options.MaxSize, fmt.Errorf("file %v exceeds %d bytes", ref.Filename, u.options.MaxSize)
+	if remaining < limit {
+		limit, tooLarge = remaining, fmt.Errorf("files exceed %d bytes in total", u.options.MaxTotalSize)
+	}
+
+	ref.Key = ns + "/" + uuid.New().String()
+	var src io.Reader = &limitReader{r: br, n: limit}
+
+	// scan the file as it's streamed to the store
+	var pw *io.PipeWriter
+	var scanned chan error
+	if u.options.Scan != nil {
+		var pr *io.PipeReader
+		pr, pw = io.Pipe()
+		src = io.TeeReader(src, pw)
+		scanned = make(chan error, 1)
+		go func() {
+			err := u.options.Scan(ctx, ref.Filename, pr)
+			// drain the pipe if the scan returns early so the upload isn't blocked
+			io.Copy(ioutil.Discard, pr)
+			scanned <- err
+		}()
+	}
+
+	size, err := u.blobs.Put(ctx, ref.Key, src)
+	ref.Size = size
+
+	if pw != nil {
+		pw.CloseWithError(err)
+		if serr := <-scanned; serr != nil && err == nil {
+			return ref, http.StatusUnprocessableEntity, fmt.Errorf("file %v rejected: %v", ref.Filename, serr)
+		}
+	}
+	if err == errTooLarge {
+		return ref, http.StatusRequestEntityTooLarge, tooLarge
+	} else if err != nil {
+		return ref, http.StatusInternalServerError, fmt.Errorf("error storing %v: %v", ref.Filename, err)
+	}
+	return ref, 0, nil
+}
+
+// allowed returns true if the type of file can be uploaded
+func (u uploadWrapper) allowed(ct string) bool {
+	if len(u.options.Types) == 0 {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	for _, t := range u.options.Types {
+		if mt == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mt, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitReader returns errTooLarge once more than n bytes have been read
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	if l.n -= int64(n); l.n < 0 {
+		return n, errTooLarge
+	}
+	return n, err
+}
+
+// add the value to the field, converting it to an array if it has multiple values
+func add(fields map[string]interface{}, name string, v interface{}) {
+	existing, ok := fields[name]
+	if !ok {
+		fields[name] = v
+		return
+	}
+	if arr, ok := existing.([]interface{}); ok {
+		fields[name] = append(arr, v)
+		return
+	}
+	fields[name] = []interface{}{existing, v}
+}
+
+func isMultipart(req *http.Request) bool {
+	ct, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && ct == "multipart/form-data"
+}
@@ -0,0 +1,169 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newRequest(t *testing.T, fields map[string]string, files map[string]string) *http.Request {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	for k, v := range files {
+		fw, err := w.CreateFormFile(k, k+".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(v))
+	}
+	w.Close()
+
+	req := httptest.NewRequest("POST", "/foo/bar", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestWrapper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var body map[string]interface{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected a JSON request, got %v", ct)
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+	})
+
+	t.Run("Stored", func(t *testing.T) {
+		h := Wrapper(NewFileBlobs(dir))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, newRequest(t, map[string]string{"name": "foo"}, map[string]string{"file": "hello world"}))
+		if rsp.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %v: %v", rsp.Code, rsp.Body.String())
+		}
+		if body["name"] != "foo" {
+			t.Errorf("Expected the field to be passed through, got %v", body["name"])
+		}
+
+		ref, _ := body["file"].(map[string]interface{})
+		if ref["filename"] != "file.txt" || ref["size"] != float64(11) {
+			t.Fatalf("Unexpected reference %v", ref)
+		}
+		if !strings.HasPrefix(ref["content_type"].(string), "text/plain") {
+			t.Errorf("Expected the type to be detected, got %v", ref["content_type"])
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, ref["key"].(string)))
+		if err != nil || string(b) != "hello world" {
+			t.Errorf("Expected the blob to be stored, got %q %v", b, err)
+		}
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		h := Wrapper(NewFileBlobs(dir), MaxSize(5))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, newRequest(t, nil, map[string]string{"file": "hello world"}))
+		if rsp.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("Expected 413, got %v", rsp.Code)
+		}
+	})
+
+	t.Run("Type", func(t *testing.T) {
+		h := Wrapper(NewFileBlobs(dir), Types("image/"))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, newRequest(t, nil, map[string]string{"file": "hello world"}))
+		if rsp.Code != http.StatusUnsupportedMediaType {
+			t.Fatalf("Expected 415, got %v", rsp.Code)
+		}
+	})
+
+	t.Run("DeclaredType", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		fw, _ := w.CreatePart(textproto.MIMEHeader{
+			"Content-Disposition": {`form-data; name="file"; filename="file.png"`},
+			"Content-Type":        {"image/png"},
+		})
+		fw.Write([]byte("hello world"))
+		w.Close()
+		req := httptest.NewRequest("POST", "/foo/bar", &buf)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+
+		h := Wrapper(NewFileBlobs(dir), Types("image/"))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+		if rsp.Code != http.StatusUnsupportedMediaType {
+			t.Fatalf("Expected the declared type to be ignored with a 415, got %v", rsp.Code)
+		}
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		h := Wrapper(NewFileBlobs(dir), MaxFiles(1))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, newRequest(t, nil, map[string]string{"a": "hello", "b": "world"}))
+		if rsp.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("Expected 413, got %v", rsp.Code)
+		}
+	})
+
+	t.Run("TotalTooLarge", func(t *testing.T) {
+		before := countFiles(t, dir)
+		h := Wrapper(NewFileBlobs(dir), MaxSize(5), MaxTotalSize(8))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, newRequest(t, nil, map[string]string{"a": "hello", "b": "world"}))
+		if rsp.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("Expected 413, got %v", rsp.Code)
+		}
+		if after := countFiles(t, dir); after != before {
+			t.Errorf("Expected the rejected upload's blobs to be deleted, got %v files, had %v", after, before)
+		}
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		before := countFiles(t, dir)
+		scan := func(ctx context.Context, filename string, r io.Reader) error {
+			b, _ := ioutil.ReadAll(r)
+			if strings.Contains(string(b), "virus") {
+				return errors.New("infected")
+			}
+			return nil
+		}
+		h := Wrapper(NewFileBlobs(dir), Scan(scan))(next)
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, newRequest(t, nil, map[string]string{"a": "clean", "b": "a virus"}))
+		if rsp.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected 422, got %v", rsp.Code)
+		}
+		if after := countFiles(t, dir); after != before {
+			t.Errorf("Expected the rejected upload's blobs to be deleted, got %v files, had %v", after, before)
+		}
+	})
+}
+
+func countFiles(t *testing.T, dir string) int {
+	var n int
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,100 @@
+// Package validate rejects API requests whose payloads don't match the schema the endpoint was
+// registered with, before they're forwarded to the service.
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/api/server"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// DefaultMaxBodySize is the largest body in bytes which is validated, larger requests are
+// rejected
+var DefaultMaxBodySize int64 = 10 << 20
+
+// Wrapper wraps a handler and validates the JSON body of requests using the request schemas of
+// the endpoints in the registry. The auth wrapper must be applied after this wrapper so the
+// endpoint has been resolved.
+func Wrapper(r goregistry.Registry) server.Wrapper {
+	return func(h http.Handler) http.Handler {
+		return validateWrapper{handler: h, registry: r}
+	}
+}
+
+type validateWrapper struct {
+	handler  http.Handler
+	registry goregistry.Registry
+}
+
+func (v validateWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	endpoint, ok := req.Context().Value(resolver.Endpoint{}).(*resolver.Endpoint)
+	if !ok || req.Body == nil || !isJSON(req) {
+		v.handler.ServeHTTP(w, req)
+		return
+	}
+
+	schema := v.schema(endpoint)
+	if schema == nil {
+		v.handler.ServeHTTP(w, req)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, DefaultMaxBodySize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > DefaultMaxBodySize {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := util.Validate(schema, body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// replace the body which has been consumed
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	v.handler.ServeHTTP(w, req)
+}
+
+// schema returns the request schema of the endpoint, or nil if it wasn't registered with one
+func (v validateWrapper) schema(endpoint *resolver.Endpoint) *util.Schema {
+	srvs, err := v.registry.GetService(endpoint.Name, goregistry.GetDomain(endpoint.Domain))
+	if err != nil {
+		return nil
+	}
+
+	for _, srv := range srvs {
+		for _, e := range srv.Endpoints {
+			if e.Name != endpoint.Method || len(e.Metadata[util.RequestSchemaKey]) == 0 {
+				continue
+			}
+
+			var schema util.Schema
+			if err := json.Unmarshal([]byte(e.Metadata[util.RequestSchemaKey]), &schema); err != nil {
+				logger.Debugf("Error decoding the request schema of %v.%v: %v", endpoint.Name, e.Name, err)
+				return nil
+			}
+			return &schema
+		}
+	}
+
+	return nil
+}
+
+func isJSON(req *http.Request) bool {
+	ct, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && ct == "application/json"
+}
@@ -0,0 +1,340 @@
+// Package websocket provides an API handler which upgrades HTTP requests to WebSockets and
+// bridges them to the bidirectional streams of services, so services can serve realtime
+// browser clients through the API. Each text or binary message received from the browser is
+// sent on the stream and each message received from the stream is written back as a message
+// of the same type as the browser's, binary if it asked for the "binary" subprotocol. The
+// messages are JSON unless the request sets another content type.
+//
+// Endpoints are served by the handler once they're registered with Endpoint, for example:
+//
+//	pb.RegisterChatHandler(srv.Server(), new(handler.Chat), websocket.Endpoint("Chat.Connect"))
+package websocket
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/micro/go-micro/v3/api"
+	"github.com/micro/go-micro/v3/api/handler"
+	"github.com/micro/go-micro/v3/client"
+	raw "github.com/micro/go-micro/v3/codec/bytes"
+	goserver "github.com/micro/go-micro/v3/server"
+	"github.com/micro/go-micro/v3/util/ctx"
+	"github.com/micro/go-micro/v3/util/router"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// Handler is the name of the handler in the endpoint metadata
+const Handler = "websocket"
+
+var (
+	// DefaultHandshakeTimeout is how long the handshake response can take to write
+	DefaultHandshakeTimeout = time.Second * 5
+	// AllowedOrigins are the origins, e.g. https://app.example.com, of the pages which can open
+	// websockets to the API in addition to its own. Browsers send cookies with websockets opened
+	// by any page so the origin must be checked before the micro-token cookie is trusted.
+	AllowedOrigins []string
+)
+
+// Endpoint registers the endpoint, which must be a bidirectional stream, as served by the
+// websocket handler. The endpoint keeps any other metadata it's registered with.
+func Endpoint(endpoint string) goserver.HandlerOption {
+	return func(o *goserver.HandlerOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]map[string]string)
+		}
+		md := make(map[string]string, len(o.Metadata[endpoint])+2)
+		for k, v := range o.Metadata[endpoint] {
+			md[k] = v
+		}
+		md["handler"] = Handler
+		md["stream"] = "true"
+		o.Metadata[endpoint] = md
+	}
+}
+
+// IsUpgrade returns true if the request asks to be upgraded to a websocket
+func IsUpgrade(r *http.Request) bool {
+	return headerContains(r, "Connection", "upgrade") && headerContains(r, "Upgrade", "websocket")
+}
+
+func headerContains(r *http.Request, key, val string) bool {
+	for _, v := range strings.Split(r.Header.Get(key), ",") {
+		if strings.ToLower(strings.TrimSpace(v)) == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Serves returns true if the endpoint the service was routed to is served by the handler
+func Serves(s *api.Service) bool {
+	if s.Endpoint == nil {
+		return false
+	}
+	if s.Endpoint.Handler == Handler {
+		return true
+	}
+	for _, srv := range s.Services {
+		for _, e := range srv.Endpoints {
+			if e.Name == s.Endpoint.Name && e.Metadata["handler"] == Handler {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type wsHandler struct {
+	opts handler.Options
+	s    *api.Service
+}
+
+// NewHandler returns a websocket handler which routes requests with the router option
+func NewHandler(opts ...handler.Option) handler.Handler {
+	return &wsHandler{opts: handler.NewOptions(opts...)}
+}
+
+// WithService returns a websocket handler of the service
+func WithService(s *api.Service, opts ...handler.Option) handler.Handler {
+	return &wsHandler{opts: handler.NewOptions(opts...), s: s}
+}
+
+func (h *wsHandler) String() string {
+	return Handler
+}
+
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	service := h.s
+	if service == nil {
+		if h.opts.Router == nil {
+			writeError(w, errors.InternalServerError("websocket", "no route found"))
+			return
+		}
+		s, err := h.opts.Router.Route(r)
+		if err != nil {
+			writeError(w, errors.InternalServerError("websocket", err.Error()))
+			return
+		}
+		service = s
+	}
+
+	if !IsUpgrade(r) {
+		writeError(w, errors.BadRequest("websocket", "%v expects a websocket upgrade", service.Endpoint.Name))
+		return
+	}
+	if !allowedOrigin(r) {
+		writeError(w, errors.Forbidden("websocket", "Origin %v is not allowed", r.Header.Get("Origin")))
+		return
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if idx := strings.IndexRune(ct, ';'); idx >= 0 {
+		ct = ct[:idx]
+	}
+	if len(ct) == 0 {
+		ct = "application/json"
+	}
+
+	// open the stream before upgrading so the error can be returned as a response
+	cx := ctx.FromRequest(r)
+	req := h.opts.Client.NewRequest(service.Name, service.Endpoint.Name, &raw.Frame{},
+		client.WithContentType(ct),
+		client.StreamingRequest(),
+	)
+	stream, err := h.opts.Client.Stream(cx, req, client.WithRouter(router.New(service.Services)))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	// browsers can't set the content type of websockets, the subprotocol sets the frame type.
+	// the upgrader writes the protocol it selects in the response.
+	upgrader := ws.HTTPUpgrader{
+		Timeout:  DefaultHandshakeTimeout,
+		Protocol: func(p string) bool { return p == "binary" },
+	}
+	nc, rw, hs, err := upgrader.Upgrade(r, w)
+	if err != nil {
+		logger.Errorf("Error upgrading the request to %v to a websocket: %v", service.Endpoint.Name, err)
+		return
+	}
+	c := &conn{Conn: nc, r: rw.Reader}
+	defer c.Close()
+
+	op := ws.OpText
+	if hs.Protocol == "binary" {
+		op = ws.OpBinary
+	}
+
+	go c.forward(stream, h.opts.MaxRecvSize)
+	c.reply(stream, op)
+}
+
+// conn to the browser. The frames written in reply to control frames and the messages
+// received from the stream are written whole so they don't interleave.
+type conn struct {
+	net.Conn
+	r  io.Reader
+	mu sync.Mutex
+}
+
+// flush the frames in the buffer to the connection
+func (c *conn) flush(buf *bytes.Buffer) error {
+	if buf.Len() == 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.Conn.Write(buf.Bytes())
+	buf.Reset()
+	return err
+}
+
+// write a message to the browser
+func (c *conn) write(op ws.OpCode, p []byte) error {
+	var buf bytes.Buffer
+	if err := wsutil.WriteServerMessage(&buf, op, p); err != nil {
+		return err
+	}
+	return c.flush(&buf)
+}
+
+// close the connection with the status, the reason is truncated to fit in a control frame
+func (c *conn) close(code ws.StatusCode, reason string) {
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	c.write(ws.OpClose, ws.NewCloseFrameBody(code, reason))
+}
+
+// read the next data message from the browser, answering the control frames read before it
+func (c *conn) read(maxSize int64) ([]byte, error) {
+	var ctrl bytes.Buffer
+	handle := wsutil.ControlFrameHandler(&ctrl, ws.StateServerSide)
+	rd := &wsutil.Reader{
+		Source:         c.r,
+		State:          ws.StateServerSide,
+		CheckUTF8:      true,
+		OnIntermediate: handle,
+	}
+
+	for {
+		hdr, err := rd.NextFrame()
+		if err != nil {
+			return nil, err
+		}
+		if hdr.OpCode.IsControl() {
+			err := handle(hdr, rd)
+			if ferr := c.flush(&ctrl); err == nil {
+				err = ferr
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		p, err := ioutil.ReadAll(io.LimitReader(rd, maxSize+1))
+		if ferr := c.flush(&ctrl); err == nil {
+			err = ferr
+		}
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(p)) > maxSize {
+			c.close(ws.StatusMessageTooBig, "Message too big")
+			return nil, errMessageTooBig
+		}
+		return p, nil
+	}
+}
+
+var errMessageTooBig = errors.BadRequest("websocket", "Message too big")
+
+// forward the messages from the browser to the stream until either closes
+func (c *conn) forward(stream client.Stream, maxSize int64) {
+	// closing the stream ends the replies
+	defer stream.Close()
+
+	for {
+		p, err := c.read(maxSize)
+		if err != nil {
+			if _, ok := err.(wsutil.ClosedError); !ok && err != io.EOF && err != errMessageTooBig {
+				logger.Debugf("Error reading from websocket: %v", err)
+			}
+			return
+		}
+		if err := stream.Send(&raw.Frame{Data: p}); err != nil {
+			logger.Debugf("Error sending to stream: %v", err)
+			return
+		}
+	}
+}
+
+// reply to the browser with the messages from the stream until either closes
+func (c *conn) reply(stream client.Stream, op ws.OpCode) {
+	for {
+		var f raw.Frame
+		if err := stream.Recv(&f); err == io.EOF {
+			c.close(ws.StatusNormalClosure, "")
+			return
+		} else if err != nil {
+			// the detail of the error is sent so the browser can tell why the stream ended
+			detail := err.Error()
+			if verr := errors.Parse(err); verr != nil {
+				detail = verr.Detail
+			}
+			c.close(ws.StatusInternalServerError, detail)
+			return
+		}
+		if err := c.write(op, f.Data); err != nil {
+			return
+		}
+	}
+}
+
+// allowedOrigin returns true if the request wasn't made by a browser, or was made by a page
+// of the API itself or one of the allowed origins
+func allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, o := range AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	verr := errors.Parse(err)
+	if verr == nil {
+		verr = errors.Parse(errors.InternalServerError("websocket", err.Error()))
+	} else if verr.Code == 0 {
+		verr.Code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(verr.Code))
+	w.Write([]byte(verr.Error()))
+}
@@ -0,0 +1,244 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/micro/go-micro/v3/api"
+	"github.com/micro/go-micro/v3/api/handler"
+	"github.com/micro/go-micro/v3/client"
+	raw "github.com/micro/go-micro/v3/codec/bytes"
+	"github.com/micro/go-micro/v3/registry"
+	goserver "github.com/micro/go-micro/v3/server"
+)
+
+type testRequest struct {
+	client.Request
+	service, endpoint, contentType string
+}
+
+func (r *testRequest) Service() string     { return r.service }
+func (r *testRequest) Endpoint() string    { return r.endpoint }
+func (r *testRequest) ContentType() string { return r.contentType }
+
+// testStream echoes the messages sent on it
+type testStream struct {
+	client.Stream
+	msgs chan []byte
+	once sync.Once
+}
+
+func (s *testStream) Send(v interface{}) error {
+	s.msgs <- v.(*raw.Frame).Data
+	return nil
+}
+
+func (s *testStream) Recv(v interface{}) error {
+	b, ok := <-s.msgs
+	if !ok {
+		return io.EOF
+	}
+	v.(*raw.Frame).Data = b
+	return nil
+}
+
+func (s *testStream) Close() error {
+	s.once.Do(func() { close(s.msgs) })
+	return nil
+}
+
+type testClient struct {
+	client.Client
+	req *testRequest
+	err error
+}
+
+func (c *testClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	var options client.RequestOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	c.req = &testRequest{service: service, endpoint: endpoint, contentType: options.ContentType}
+	return c.req
+}
+
+func (c *testClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &testStream{msgs: make(chan []byte, 1)}, nil
+}
+
+func TestEndpoint(t *testing.T) {
+	var opts goserver.HandlerOptions
+	opts.Metadata = map[string]map[string]string{"Chat.Connect": {"auth": "public"}}
+	Endpoint("Chat.Connect")(&opts)
+
+	md := opts.Metadata["Chat.Connect"]
+	if md["handler"] != Handler || md["stream"] != "true" || md["auth"] != "public" {
+		t.Errorf("Expected the endpoint to be served by the websocket handler, got %v", md)
+	}
+
+	service := &api.Service{
+		Name:     "chat",
+		Endpoint: &api.Endpoint{Name: "Chat.Connect"},
+		Services: []*registry.Service{{
+			Name:      "chat",
+			Endpoints: []*registry.Endpoint{{Name: "Chat.Connect", Metadata: md}},
+		}},
+	}
+	if !Serves(service) {
+		t.Errorf("Expected the endpoint to be served by the websocket handler")
+	}
+	service.Endpoint.Name = "Chat.History"
+	if Serves(service) {
+		t.Errorf("Expected the endpoint not to be served by the websocket handler")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c := &testClient{}
+	service := &api.Service{Name: "chat", Endpoint: &api.Endpoint{Name: "Chat.Connect"}}
+	srv := httptest.NewServer(WithService(service, handler.WithClient(c)))
+	defer srv.Close()
+
+	// requests which aren't upgrades are rejected
+	rsp, err := srv.Client().Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != 400 {
+		t.Errorf("Expected a bad request, got %v", rsp.Status)
+	}
+
+	conn, _, _, err := ws.Dial(context.TODO(), "ws://"+strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := wsutil.WriteClientText(conn, []byte(`{"message":"hello"}`)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := wsutil.ReadServerText(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"message":"hello"}` {
+		t.Errorf("Expected the message to be echoed, got %s", b)
+	}
+
+	if c.req.Service() != "chat" || c.req.Endpoint() != "Chat.Connect" || c.req.ContentType() != "application/json" {
+		t.Errorf("Expected a json stream to chat Chat.Connect, got %v %v %v", c.req.Service(), c.req.Endpoint(), c.req.ContentType())
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	service := &api.Service{Name: "chat", Endpoint: &api.Endpoint{Name: "Chat.Connect"}}
+	srv := httptest.NewServer(WithService(service, handler.WithClient(&testClient{})))
+	defer srv.Close()
+
+	// the selected subprotocol is written once
+	rsp := upgrade(t, srv.URL, http.Header{"Sec-WebSocket-Protocol": {"chat, binary"}})
+	if p := rsp.Header["Sec-Websocket-Protocol"]; len(p) != 1 || p[0] != "binary" {
+		t.Fatalf("Expected the binary subprotocol to be selected once, got %v", p)
+	}
+
+	dialer := ws.Dialer{Protocols: []string{"binary"}}
+	conn, _, hs, err := dialer.Dial(context.TODO(), "ws://"+strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if hs.Protocol != "binary" {
+		t.Fatalf("Expected the binary subprotocol, got %q", hs.Protocol)
+	}
+	if err := wsutil.WriteClientBinary(conn, []byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	b, op, err := wsutil.ReadServerData(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op != ws.OpBinary || len(b) != 3 {
+		t.Errorf("Expected the message to be echoed as binary, got %v %v", op, b)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	defer func(o []string) { AllowedOrigins = o }(AllowedOrigins)
+	AllowedOrigins = []string{"https://app.example.com"}
+
+	service := &api.Service{Name: "chat", Endpoint: &api.Endpoint{Name: "Chat.Connect"}}
+	srv := httptest.NewServer(WithService(service, handler.WithClient(&testClient{})))
+	defer srv.Close()
+
+	tt := []struct {
+		Origin string
+		Code   int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{srv.URL, http.StatusSwitchingProtocols},
+		{"https://app.example.com", http.StatusSwitchingProtocols},
+		{"https://evil.example.com", http.StatusForbidden},
+	}
+	for _, tc := range tt {
+		hdr := http.Header{}
+		if len(tc.Origin) > 0 {
+			hdr.Set("Origin", tc.Origin)
+		}
+		if rsp := upgrade(t, srv.URL, hdr); rsp.StatusCode != tc.Code {
+			t.Errorf("Expected %v from origin %q, got %v", tc.Code, tc.Origin, rsp.Status)
+		}
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	service := &api.Service{Name: "chat", Endpoint: &api.Endpoint{Name: "Chat.Connect"}}
+	c := &testClient{err: fmt.Errorf("connection refused")}
+	srv := httptest.NewServer(WithService(service, handler.WithClient(c)))
+	defer srv.Close()
+
+	if rsp := upgrade(t, srv.URL, nil); rsp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected an internal server error, got %v", rsp.Status)
+	}
+}
+
+// upgrade sends a websocket upgrade request and returns the response
+func upgrade(t *testing.T, addr string, hdr http.Header) *http.Response {
+	u, _ := url.Parse(addr)
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	req, _ := http.NewRequest("GET", addr, nil)
+	for k, v := range hdr {
+		req.Header[k] = v
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rsp
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+
 	"github.com/micro/go-micro/v3/auth"
 	"github.com/micro/micro/v3/service/auth/client"
 )
@@ -28,6 +30,17 @@ func Token(opts ...auth.TokenOption) (*auth.Token, error) {
 	return DefaultAuth.Token(opts...)
 }
 
+// TokenContext generates a token like Token, forwarding the metadata of the context to the auth
+// service, such as the origin of the request the token is generated for, if DefaultAuth supports it
+func TokenContext(ctx context.Context, opts ...auth.TokenOption) (*auth.Token, error) {
+	if a, ok := DefaultAuth.(interface {
+		TokenContext(context.Context, ...auth.TokenOption) (*auth.Token, error)
+	}); ok {
+		return a.TokenContext(ctx, opts...)
+	}
+	return DefaultAuth.Token(opts...)
+}
+
 // Grant access to a resource
 func Grant(rule *auth.Rule) error {
 	return DefaultAuth.Grant(rule)
@@ -0,0 +1,230 @@
+// Package classify declares the classification of the data the fields of endpoint responses
+// hold, e.g. personal data, and redacts the classified fields from the responses sent to callers
+// without the scope the policy requires to read them. Classifications are declared in the
+// endpoint metadata using Classify, so the policy is enforced in one place by the server and
+// the API gateway rather than by each handler.
+package classify
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// Key is the endpoint metadata the classified fields are listed in, e.g.
+// "email=pii,card.number=secret"
+const Key = "classified"
+
+// Class of data
+type Class string
+
+const (
+	// PII is personally identifiable information, e.g. names and email addresses
+	PII Class = "pii"
+	// Secret is data which grants access, e.g. credentials and keys
+	Secret Class = "secret"
+)
+
+// Redacted replaces the classified string fields, other fields are removed
+const Redacted = "[REDACTED]"
+
+// Policy is the scopes which may read each class of data, an account with any of the scopes
+// reads the data
+type Policy map[Class][]string
+
+// DefaultPolicy lets services read the data they pass between each other
+var DefaultPolicy = Policy{
+	PII:    {"pii", "admin", "service"},
+	Secret: {"secret", "service"},
+}
+
+// Allowed returns true if an account with the scopes may read the class of data. Classes the
+// policy doesn't know are only read by accounts with the scope of the same name.
+func (p Policy) Allowed(c Class, scopes []string) bool {
+	allowed, ok := p[c]
+	if !ok {
+		allowed = []string{string(c)}
+	}
+	for _, s := range scopes {
+		for _, a := range allowed {
+			if s == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Classify declares the classes of the fields of the endpoint's responses, keyed by the path of
+// their JSON names, e.g. "user.email". Fields of the objects in arrays are classified by the
+// path of the array, e.g. "users.email". The endpoint keeps any other metadata it's registered
+// with.
+func Classify(endpoint string, fields map[string]Class) server.HandlerOption {
+	return func(o *server.HandlerOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]map[string]string)
+		}
+		md := make(map[string]string, len(o.Metadata[endpoint])+1)
+		for k, v := range o.Metadata[endpoint] {
+			md[k] = v
+		}
+		md[Key] = Format(fields)
+		o.Metadata[endpoint] = md
+	}
+}
+
+// Format the classified fields as endpoint metadata, sorted by path
+func Format(fields map[string]Class) string {
+	entries := make([]string, 0, len(fields))
+	for path, c := range fields {
+		entries = append(entries, path+"="+string(c))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// Parse the classified fields from the endpoint metadata
+func Parse(s string) map[string]Class {
+	fields := make(map[string]Class)
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			continue
+		}
+		fields[parts[0]] = Class(strings.TrimSpace(parts[1]))
+	}
+	return fields
+}
+
+// Fields returns the paths of the fields an account with the scopes may not read
+func Fields(fields map[string]Class, scopes []string, p Policy) []string {
+	var paths []string
+	for path, c := range fields {
+		if !p.Allowed(c, scopes) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Redact the fields at the paths from the decoded JSON value
+func Redact(v interface{}, paths []string) {
+	for _, p := range paths {
+		redact(v, strings.Split(p, "."))
+	}
+}
+
+func redact(v interface{}, path []string) {
+	switch val := v.(type) {
+	case []interface{}:
+		for _, e := range val {
+			redact(e, path)
+		}
+	case map[string]interface{}:
+		field, ok := val[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) > 1 {
+			redact(field, path[1:])
+			return
+		}
+		if _, ok := field.(string); ok {
+			val[path[0]] = Redacted
+		} else {
+			delete(val, path[0])
+		}
+	}
+}
+
+// RedactJSON redacts the fields at the paths from the JSON encoded value
+func RedactJSON(b []byte, paths []string) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	Redact(v, paths)
+	return json.Marshal(v)
+}
+
+// Lookup returns the classified fields of the endpoint of the service in the registry
+func Lookup(r goregistry.Registry, service, endpoint string, opts ...goregistry.GetOption) map[string]Class {
+	srvs, err := r.GetService(service, opts...)
+	if err != nil {
+		return nil
+	}
+	for _, srv := range srvs {
+		for _, e := range srv.Endpoints {
+			if e.Name == endpoint && len(e.Metadata[Key]) > 0 {
+				return Parse(e.Metadata[Key])
+			}
+		}
+	}
+	return nil
+}
+
+// scopes returns the scopes of the account in the context, nil if there's no account
+func scopes(ctx context.Context) []string {
+	acc, ok := goauth.AccountFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return acc.Scopes
+}
+
+// HandlerWrapper redacts the classified fields of the responses sent to callers without the
+// scope the policy requires to read them. The classifications are read from the endpoint
+// metadata in the registry, which should be cached, using the options, e.g. the domain the
+// server registers in.
+func HandlerWrapper(r goregistry.Registry, p Policy, opts ...goregistry.GetOption) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if err := h(ctx, req, rsp); err != nil || req.Stream() {
+				return err
+			}
+
+			fields := Lookup(r, req.Service(), req.Endpoint(), opts...)
+			if len(fields) == 0 {
+				return nil
+			}
+			paths := Fields(fields, scopes(ctx), p)
+			if len(paths) == 0 {
+				return nil
+			}
+
+			if err := redactResponse(rsp, paths); err != nil {
+				// never send the classified fields if they can't be redacted
+				logger.Errorf("Error redacting the response of %v: %v", req.Endpoint(), err)
+				return errors.InternalServerError(req.Service(), "Error redacting the response")
+			}
+			return nil
+		}
+	}
+}
+
+// redactResponse redacts the fields of the response, which is replaced by the redacted value
+func redactResponse(rsp interface{}, paths []string) error {
+	b, err := json.Marshal(rsp)
+	if err != nil {
+		return err
+	}
+	if b, err = RedactJSON(b, paths); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(rsp)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	return json.Unmarshal(b, rsp)
+}
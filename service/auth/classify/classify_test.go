@@ -0,0 +1,98 @@
+package classify
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+func TestParse(t *testing.T) {
+	fields := map[string]Class{"user.email": PII, "token": Secret}
+	s := Format(fields)
+	if s != "token=secret,user.email=pii" {
+		t.Errorf("Expected the fields to be sorted, got %v", s)
+	}
+	if got := Parse(s); !reflect.DeepEqual(got, fields) {
+		t.Errorf("Expected %v, got %v", fields, got)
+	}
+	if got := Parse("bad, =pii"); len(got) != 0 {
+		t.Errorf("Expected invalid entries to be skipped, got %v", got)
+	}
+
+	paths := Fields(fields, []string{"pii"}, DefaultPolicy)
+	if !reflect.DeepEqual(paths, []string{"token"}) {
+		t.Errorf("Expected only the secret to be redacted, got %v", paths)
+	}
+	if paths := Fields(fields, []string{"service"}, DefaultPolicy); len(paths) != 0 {
+		t.Errorf("Expected services to read every field, got %v", paths)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	b := []byte(`{"users":[{"name":"john","email":"john@example.com","age":40}],"total":1}`)
+	b, err := RedactJSON(b, []string{"users.email", "users.age", "missing.field"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"total":1,"users":[{"email":"[REDACTED]","name":"john"}]}`
+	if string(b) != expected {
+		t.Errorf("Expected %v, got %v", expected, string(b))
+	}
+}
+
+type testRequest struct {
+	server.Request
+}
+
+func (r *testRequest) Service() string  { return "users" }
+func (r *testRequest) Endpoint() string { return "Users.Read" }
+func (r *testRequest) Stream() bool     { return false }
+
+type testUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type testResponse struct {
+	User *testUser `json:"user"`
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	r := memory.NewRegistry()
+	r.Register(&goregistry.Service{
+		Name:    "users",
+		Version: "latest",
+		Nodes:   []*goregistry.Node{{Id: "users-1", Address: "localhost:9090"}},
+		Endpoints: []*goregistry.Endpoint{{
+			Name:     "Users.Read",
+			Metadata: map[string]string{Key: Format(map[string]Class{"user.email": PII})},
+		}},
+	})
+
+	h := HandlerWrapper(r, DefaultPolicy)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		rsp.(*testResponse).User = &testUser{Name: "john", Email: "john@example.com"}
+		return nil
+	})
+
+	call := func(scopes ...string) *testResponse {
+		ctx := goauth.ContextWithAccount(context.TODO(), &goauth.Account{ID: "caller", Scopes: scopes})
+		rsp := &testResponse{}
+		if err := h(ctx, &testRequest{}, rsp); err != nil {
+			t.Fatalf("Expected no error, got %v", errors.Parse(err))
+		}
+		return rsp
+	}
+
+	if rsp := call("developer"); rsp.User.Email != Redacted || rsp.User.Name != "john" {
+		t.Errorf("Expected the email to be redacted, got %v", rsp.User)
+	}
+	if rsp := call("pii"); rsp.User.Email != "john@example.com" {
+		t.Errorf("Expected the email to be read with the pii scope, got %v", rsp.User)
+	}
+}
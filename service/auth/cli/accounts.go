@@ -105,3 +105,60 @@ func deleteAccount(ctx *cli.Context) error {
 
 	return nil
 }
+
+func updateProfile(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: ID")
+	}
+	cli := pb.NewAccountsService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	profile := make(map[string]string)
+	for _, attr := range ctx.StringSlice("attribute") {
+		parts := strings.SplitN(attr, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Invalid attribute %v, expected key=value", attr)
+		}
+		profile[parts[0]] = parts[1]
+	}
+
+	rsp, err := cli.UpdateProfile(context.DefaultContext, &pb.UpdateProfileRequest{
+		Id:      ctx.Args().First(),
+		Profile: profile,
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error updating profile: %v", err)
+	}
+
+	json, _ := json.Marshal(rsp.Profile)
+	fmt.Printf("Profile updated: %v\n", string(json))
+	return nil
+}
+
+func enrollMFA(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: ID")
+	}
+	cli := pb.NewAccountsService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.EnrollMFA(context.DefaultContext, &pb.EnrollMFARequest{
+		Id:      ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error enrolling second factor: %v", err)
+	}
+
+	fmt.Printf("Add the secret to an authenticator app: %v\n%v\n", rsp.Secret, rsp.Url)
+	return nil
+}
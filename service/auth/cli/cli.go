@@ -40,6 +40,13 @@ var (
 			Usage: "Comma seperated list of scopes to give the account",
 		},
 	}
+	// profileFlags are provided to the update profile command
+	profileFlags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "attribute",
+			Usage: "Profile attribute to set in the format key=value, leave the value blank to remove it",
+		},
+	}
 )
 
 func init() {
@@ -83,6 +90,76 @@ func init() {
 						},
 					},
 				},
+				{
+					Name:  "update",
+					Usage: "Update an auth resource",
+					Subcommands: []*cli.Command{
+						{
+							Name:   "profile",
+							Usage:  "Update the profile attributes of an auth account",
+							Flags:  profileFlags,
+							Action: updateProfile,
+						},
+						{
+							Name:      "mfa",
+							Usage:     "Enroll a TOTP second factor for an auth account, required for step-up verification",
+							ArgsUsage: "[id]",
+							Action:    enrollMFA,
+						},
+					},
+				},
+				{
+					Name:   "matrix",
+					Usage:  "Show which identities may call which services and endpoints, as decided by the rules",
+					Action: authMatrix,
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:  "services",
+							Usage: "Services to include, defaults to every registered service",
+						},
+						&cli.StringFlag{
+							Name:  "output",
+							Usage: "output format (table, csv, json)",
+							Value: "table",
+						},
+					},
+				},
+				{
+					Name:   "domains",
+					Usage:  "Manage the domains claimed by the namespace",
+					Action: helper.UnexpectedSubcommand,
+					Subcommands: []*cli.Command{
+						{
+							Name:      "claim",
+							Usage:     "Claim a domain, returning the TXT record which verifies it",
+							ArgsUsage: "[domain]",
+							Action:    claimDomain,
+							Flags: []cli.Flag{
+								&cli.BoolFlag{
+									Name:  "auto_join",
+									Usage: "New users with an email address at the domain join the namespace",
+								},
+							},
+						},
+						{
+							Name:      "verify",
+							Usage:     "Verify a claimed domain once its TXT record has been created",
+							ArgsUsage: "[domain]",
+							Action:    verifyDomain,
+						},
+						{
+							Name:   "list",
+							Usage:  "List the domains claimed by the namespace",
+							Action: listDomains,
+						},
+						{
+							Name:      "release",
+							Usage:     "Release a domain claimed by the namespace",
+							ArgsUsage: "[domain]",
+							Action:    releaseDomain,
+						},
+					},
+				},
 				{
 					Name:  "delete",
 					Usage: "Delete a auth resource",
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+func claimDomain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Expected one argument: the domain to claim")
+	}
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewDomainsService("auth", client.DefaultClient)
+	rsp, err := cli.Claim(context.DefaultContext, &pb.ClaimDomainRequest{
+		Domain:   ctx.Args().First(),
+		AutoJoin: ctx.Bool("auto_join"),
+		Options:  &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error: %v", verr.Detail)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Printf("Create a TXT record to prove control of %v, then run 'micro auth domains verify %v'\n\n", rsp.Domain.Name, rsp.Domain.Name)
+	fmt.Printf("Name:  %v\nValue: %v\n", rsp.Record, rsp.Domain.Challenge)
+	return nil
+}
+
+func verifyDomain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Expected one argument: the domain to verify")
+	}
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewDomainsService("auth", client.DefaultClient)
+	rsp, err := cli.Verify(context.DefaultContext, &pb.VerifyDomainRequest{
+		Domain:  ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error: %v", verr.Detail)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Printf("Domain %v verified for namespace %v\n", rsp.Domain.Name, rsp.Domain.Namespace)
+	return nil
+}
+
+func listDomains(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewDomainsService("auth", client.DefaultClient)
+	rsp, err := cli.List(context.DefaultContext, &pb.ListDomainsRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing domains: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Domain", "Verified", "Auto Join", "Created"}, "\t\t"))
+	for _, d := range rsp.Domains {
+		verified := "no"
+		if d.Verified {
+			verified = time.Unix(d.VerifiedAt, 0).Format(time.RFC3339)
+		}
+		created := time.Unix(d.Created, 0).Format(time.RFC3339)
+		fmt.Fprintln(w, strings.Join([]string{d.Name, verified, fmt.Sprintf("%v", d.AutoJoin), created}, "\t\t"))
+	}
+	return nil
+}
+
+func releaseDomain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Expected one argument: the domain to release")
+	}
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewDomainsService("auth", client.DefaultClient)
+	_, err = cli.Release(context.DefaultContext, &pb.ReleaseDomainRequest{
+		Domain:  ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error: %v", verr.Detail)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Println("Domain released")
+	return nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+)
+
+// authMatrix prints the access each identity has to the endpoints of the services
+func authMatrix(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewRulesService("auth", client.DefaultClient)
+	rsp, err := cli.Matrix(context.DefaultContext, &pb.MatrixRequest{
+		Options:  &pb.Options{Namespace: ns},
+		Services: ctx.StringSlice("services"),
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error evaluating rules: %v", err)
+	}
+
+	switch ctx.String("output") {
+	case "json":
+		b, err := json.MarshalIndent(rsp.Entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"identity", "scopes", "service", "endpoint", "access", "rule"})
+		for _, e := range rsp.Entries {
+			w.Write([]string{identity(e), strings.Join(e.Scopes, " "), e.Service, e.Endpoint, access(e), e.Rule})
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		printMatrix(rsp.Entries)
+	}
+	return nil
+}
+
+// printMatrix prints a table with a row per identity and a column per endpoint. The entries are
+// returned grouped by identity, with the endpoints in the same order for each.
+func printMatrix(entries []*pb.MatrixEntry) {
+	var columns []string
+	var rows []string
+	cells := make(map[string][]string)
+	for _, e := range entries {
+		id := identity(e)
+		if _, ok := cells[id]; !ok {
+			rows = append(rows, id)
+		}
+		if len(rows) == 1 {
+			columns = append(columns, e.Service+" "+e.Endpoint)
+		}
+		cells[id] = append(cells[id], access(e))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "IDENTITY\t"+strings.Join(columns, "\t"))
+	for _, id := range rows {
+		fmt.Fprintln(w, id+"\t"+strings.Join(cells[id], "\t"))
+	}
+}
+
+func identity(e *pb.MatrixEntry) string {
+	if len(e.Identity) == 0 {
+		return "<public>"
+	}
+	return e.Identity
+}
+
+func access(e *pb.MatrixEntry) string {
+	if e.Granted {
+		return "granted"
+	}
+	return "denied"
+}
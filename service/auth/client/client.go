@@ -1,6 +1,7 @@
 package client
 
 import (
+	gocontext "context"
 	"strings"
 	"time"
 
@@ -175,7 +176,24 @@ func (s *srv) Verify(acc *auth.Account, res *auth.Resource, opts ...auth.VerifyO
 func (s *srv) Inspect(token string) (*auth.Account, error) {
 	// try to decode JWT locally and fall back to srv if an error occurs
 	if len(strings.Split(token, ".")) == 3 && len(s.options.PublicKey) > 0 {
-		return s.token.Inspect(token)
+		acc, err := s.token.Inspect(token)
+		if err == nil {
+			return acc, nil
+		}
+		// the token may have been issued by a peer cluster
+		if f := getFederation(s.options); f != nil {
+			if acc, ferr := f.Inspect(token); ferr == nil {
+				return acc, nil
+			}
+		}
+		return nil, err
+	}
+
+	// verify tokens issued by peer clusters locally
+	if f := getFederation(s.options); f != nil {
+		if acc, err := f.Inspect(token); err == nil {
+			return acc, nil
+		}
 	}
 
 	// the token is not a JWT or we do not have the keys to decode it,
@@ -191,6 +209,12 @@ func (s *srv) Inspect(token string) (*auth.Account, error) {
 
 // Token generation using an account ID and secret
 func (s *srv) Token(opts ...auth.TokenOption) (*auth.Token, error) {
+	return s.TokenContext(context.DefaultContext, opts...)
+}
+
+// TokenContext generates a token like Token, calling the auth service with the context so the
+// metadata of the request the token is generated for, such as its origin, is forwarded
+func (s *srv) TokenContext(ctx gocontext.Context, opts ...auth.TokenOption) (*auth.Token, error) {
 	options := auth.NewTokenOptions(opts...)
 	if len(options.Issuer) == 0 {
 		options.Issuer = s.options.Issuer
@@ -220,7 +244,7 @@ func (s *srv) Token(opts ...auth.TokenOption) (*auth.Token, error) {
 		}, nil
 	}
 
-	rsp, err := s.auth.Token(context.DefaultContext, &pb.TokenRequest{
+	rsp, err := s.auth.Token(ctx, &pb.TokenRequest{
 		Id:           options.ID,
 		Secret:       options.Secret,
 		RefreshToken: options.RefreshToken,
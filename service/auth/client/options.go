@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/micro/v3/service/auth/federation"
+)
+
+type federationKey struct{}
+
+// Federation sets the peer clusters whose tokens are trusted
+func Federation(f *federation.Federation) auth.Option {
+	return func(o *auth.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, federationKey{}, f)
+	}
+}
+
+func getFederation(o auth.Options) *federation.Federation {
+	if o.Context == nil {
+		return nil
+	}
+	f, _ := o.Context.Value(federationKey{}).(*federation.Federation)
+	return f
+}
@@ -0,0 +1,146 @@
+// Package federation allows tokens issued by other micro clusters to be trusted. The tokens of a
+// peer cluster are verified with its public key and the account is translated into the local
+// cluster's namespaces and scopes, so services can call each other without authenticating
+// twice or sharing signing keys.
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/util/token"
+	"github.com/micro/go-micro/v3/util/token/jwt"
+)
+
+const (
+	// Wildcard matches any namespace or scope in a mapping which isn't mapped explicitly
+	Wildcard = "*"
+	// PeerKey is the account metadata key set to the name of the peer which issued the token
+	PeerKey = "Micro-Federation-Peer"
+)
+
+var (
+	// ErrUntrusted is returned when a token wasn't issued by a trusted peer
+	ErrUntrusted = errors.New("token not issued by a trusted peer")
+	// ErrNamespace is returned when a peer's token is for a namespace which isn't mapped
+	ErrNamespace = errors.New("namespace not trusted")
+)
+
+// Peer is a cluster whose tokens are trusted
+type Peer struct {
+	// Name of the peer
+	Name string `json:"name"`
+	// PublicKey the peer's tokens are signed with (base64 encoded PEM)
+	PublicKey string `json:"public_key"`
+	// Namespaces maps the namespaces of the peer to local namespaces, a blank value keeps the
+	// namespace as it is. Tokens for a namespace which isn't mapped are rejected.
+	Namespaces map[string]string `json:"namespaces"`
+	// Scopes maps the scopes of the peer to local scopes, a blank value keeps the scope as it
+	// is. Scopes which aren't mapped are removed from the account.
+	Scopes map[string]string `json:"scopes"`
+}
+
+// Federation verifies and translates the tokens of its peers
+type Federation struct {
+	peers []*peer
+}
+
+type peer struct {
+	*Peer
+	token token.Provider
+}
+
+// New returns a federation trusting the peers
+func New(peers ...*Peer) *Federation {
+	f := &Federation{}
+	for _, p := range peers {
+		f.peers = append(f.peers, &peer{
+			Peer:  p,
+			token: jwt.NewTokenProvider(token.WithPublicKey(p.PublicKey)),
+		})
+	}
+	return f
+}
+
+// Load the peers from a JSON file containing a list of peers
+func Load(path string) (*Federation, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []*Peer
+	if err := json.Unmarshal(b, &peers); err != nil {
+		return nil, err
+	}
+	for _, p := range peers {
+		if len(p.Name) == 0 || len(p.PublicKey) == 0 {
+			return nil, errors.New("peers require a name and public key")
+		}
+	}
+	return New(peers...), nil
+}
+
+// Inspect a token issued by a peer, returning the account translated to the local cluster
+func (f *Federation) Inspect(tok string) (*auth.Account, error) {
+	if len(strings.Split(tok, ".")) != 3 {
+		return nil, ErrUntrusted
+	}
+
+	for _, p := range f.peers {
+		acc, err := p.token.Inspect(tok)
+		if err != nil {
+			continue
+		}
+		return p.translate(acc)
+	}
+
+	return nil, ErrUntrusted
+}
+
+// translate the account issued by the peer to the local namespace and scopes
+func (p *peer) translate(acc *auth.Account) (*auth.Account, error) {
+	ns, ok := lookup(p.Namespaces, acc.Issuer)
+	if !ok {
+		return nil, ErrNamespace
+	}
+
+	var scopes []string
+	for _, s := range acc.Scopes {
+		if scope, ok := lookup(p.Scopes, s); ok {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	md := make(map[string]string, len(acc.Metadata)+1)
+	for k, v := range acc.Metadata {
+		md[k] = v
+	}
+	md[PeerKey] = p.Name
+
+	return &auth.Account{
+		ID:       acc.ID,
+		Type:     acc.Type,
+		Issuer:   ns,
+		Scopes:   scopes,
+		Metadata: md,
+	}, nil
+}
+
+// lookup the value in the mapping, falling back to the wildcard
+func lookup(mapping map[string]string, v string) (string, bool) {
+	m, ok := mapping[v]
+	if !ok {
+		m, ok = mapping[Wildcard]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(m) == 0 {
+		return v, true
+	}
+	return m, true
+}
@@ -0,0 +1,81 @@
+package federation
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/util/token"
+	"github.com/micro/go-micro/v3/util/token/jwt"
+)
+
+// keys returns a base64 encoded PEM key pair
+func keys(t *testing.T) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(typ string, b []byte) string {
+		return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}))
+	}
+	return encode("PUBLIC KEY", pub), encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func TestInspect(t *testing.T) {
+	pub, priv := keys(t)
+	otherPub, otherPriv := keys(t)
+
+	f := New(&Peer{
+		Name:       "eu",
+		PublicKey:  pub,
+		Namespaces: map[string]string{"micro": "eu-micro", "shared": ""},
+		Scopes:     map[string]string{"admin": "eu-admin", "service": ""},
+	}, &Peer{Name: "us", PublicKey: otherPub})
+
+	generate := func(priv string, acc *auth.Account) string {
+		tok, err := jwt.NewTokenProvider(token.WithPrivateKey(priv)).Generate(acc, token.WithExpiry(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok.Token
+	}
+
+	acc, err := f.Inspect(generate(priv, &auth.Account{ID: "foo", Issuer: "micro", Scopes: []string{"admin", "service", "other"}}))
+	if err != nil {
+		t.Fatalf("Unexpected error inspecting the token: %v", err)
+	}
+	if acc.ID != "foo" || acc.Issuer != "eu-micro" || acc.Metadata[PeerKey] != "eu" {
+		t.Errorf("Expected the account to be translated, got %+v", acc)
+	}
+	if len(acc.Scopes) != 2 || acc.Scopes[0] != "eu-admin" || acc.Scopes[1] != "service" {
+		t.Errorf("Expected the scopes to be translated, got %v", acc.Scopes)
+	}
+
+	if acc, err := f.Inspect(generate(priv, &auth.Account{ID: "foo", Issuer: "shared"})); err != nil || acc.Issuer != "shared" {
+		t.Errorf("Expected the namespace to be kept, got %v: %v", acc, err)
+	}
+	if _, err := f.Inspect(generate(priv, &auth.Account{ID: "foo", Issuer: "other"})); err != ErrNamespace {
+		t.Errorf("Expected the namespace not to be trusted, got %v", err)
+	}
+	if _, err := f.Inspect(generate(otherPriv, &auth.Account{ID: "foo", Issuer: "micro"})); err != ErrNamespace {
+		t.Errorf("Expected the namespace not to be trusted by a peer without mappings, got %v", err)
+	}
+
+	_, untrusted := keys(t)
+	if _, err := f.Inspect(generate(untrusted, &auth.Account{ID: "foo", Issuer: "micro"})); err != ErrUntrusted {
+		t.Errorf("Expected the token not to be trusted, got %v", err)
+	}
+	if _, err := f.Inspect("foo"); err != ErrUntrusted {
+		t.Errorf("Expected the token not to be trusted, got %v", err)
+	}
+}
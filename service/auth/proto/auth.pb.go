@@ -790,11 +790,21 @@ func (m *InspectResponse) GetAccount() *Account {
 }
 
 type TokenRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Secret               string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
-	RefreshToken         string   `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	TokenExpiry          int64    `protobuf:"varint,4,opt,name=token_expiry,json=tokenExpiry,proto3" json:"token_expiry,omitempty"`
-	Options              *Options `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	Id           string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret       string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	RefreshToken string   `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TokenExpiry  int64    `protobuf:"varint,4,opt,name=token_expiry,json=tokenExpiry,proto3" json:"token_expiry,omitempty"`
+	Options      *Options `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	// client application the token is being issued to. The account must have
+	// granted the client the scopes requested.
+	ClientId string `protobuf:"bytes,6,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// scopes requested by the client, none are issued if not set
+	Scopes []string `protobuf:"bytes,7,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// step-up verification, e.g. an MFA code, required when the risk of
+	// issuing the token is high
+	Verification string `protobuf:"bytes,8,opt,name=verification,proto3" json:"verification,omitempty"`
+	// secret of the client application, required with the client id
+	ClientSecret         string   `protobuf:"bytes,9,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -860,6 +870,34 @@ func (m *TokenRequest) GetOptions() *Options {
 	return nil
 }
 
+func (m *TokenRequest) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *TokenRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *TokenRequest) GetVerification() string {
+	if m != nil {
+		return m.Verification
+	}
+	return ""
+}
+
+func (m *TokenRequest) GetClientSecret() string {
+	if m != nil {
+		return m.ClientSecret
+	}
+	return ""
+}
+
 type TokenResponse struct {
 	Token                *Token   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1337,401 +1375,2025 @@ func (m *ChangeSecretResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ChangeSecretResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterEnum("auth.Access", Access_name, Access_value)
-	proto.RegisterType((*ListAccountsRequest)(nil), "auth.ListAccountsRequest")
-	proto.RegisterType((*ListAccountsResponse)(nil), "auth.ListAccountsResponse")
-	proto.RegisterType((*DeleteAccountRequest)(nil), "auth.DeleteAccountRequest")
-	proto.RegisterType((*DeleteAccountResponse)(nil), "auth.DeleteAccountResponse")
-	proto.RegisterType((*Token)(nil), "auth.Token")
-	proto.RegisterType((*Account)(nil), "auth.Account")
-	proto.RegisterMapType((map[string]string)(nil), "auth.Account.MetadataEntry")
-	proto.RegisterType((*Resource)(nil), "auth.Resource")
-	proto.RegisterType((*GenerateRequest)(nil), "auth.GenerateRequest")
-	proto.RegisterMapType((map[string]string)(nil), "auth.GenerateRequest.MetadataEntry")
-	proto.RegisterType((*GenerateResponse)(nil), "auth.GenerateResponse")
-	proto.RegisterType((*GrantRequest)(nil), "auth.GrantRequest")
-	proto.RegisterType((*GrantResponse)(nil), "auth.GrantResponse")
-	proto.RegisterType((*RevokeRequest)(nil), "auth.RevokeRequest")
-	proto.RegisterType((*RevokeResponse)(nil), "auth.RevokeResponse")
-	proto.RegisterType((*InspectRequest)(nil), "auth.InspectRequest")
-	proto.RegisterType((*InspectResponse)(nil), "auth.InspectResponse")
-	proto.RegisterType((*TokenRequest)(nil), "auth.TokenRequest")
-	proto.RegisterType((*TokenResponse)(nil), "auth.TokenResponse")
-	proto.RegisterType((*Rule)(nil), "auth.Rule")
-	proto.RegisterType((*Options)(nil), "auth.Options")
-	proto.RegisterType((*CreateRequest)(nil), "auth.CreateRequest")
-	proto.RegisterType((*CreateResponse)(nil), "auth.CreateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "auth.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "auth.DeleteResponse")
-	proto.RegisterType((*ListRequest)(nil), "auth.ListRequest")
-	proto.RegisterType((*ListResponse)(nil), "auth.ListResponse")
-	proto.RegisterType((*ChangeSecretRequest)(nil), "auth.ChangeSecretRequest")
-	proto.RegisterType((*ChangeSecretResponse)(nil), "auth.ChangeSecretResponse")
+type ReadProfileRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("service/auth/proto/auth.proto", fileDescriptor_6198f7e829fc4ef7) }
-
-var fileDescriptor_6198f7e829fc4ef7 = []byte{
-	// 1046 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xae, 0x63, 0xe7, 0xa7, 0x27, 0x3f, 0x0d, 0xd3, 0xb4, 0x84, 0x2c, 0x8b, 0x5a, 0x2f, 0x52,
-	0xcb, 0x5e, 0x24, 0x90, 0x6a, 0x61, 0xb5, 0x95, 0x58, 0x95, 0x36, 0x2a, 0x2b, 0x20, 0x95, 0xcc,
-	0x22, 0x10, 0x37, 0x95, 0xd7, 0x39, 0x6c, 0xac, 0xa6, 0xb6, 0xf1, 0xd8, 0x59, 0xc2, 0x1d, 0xf7,
-	0x3c, 0x01, 0x0f, 0x00, 0xdc, 0xf2, 0x38, 0x3c, 0x03, 0x2f, 0x81, 0x3c, 0x73, 0xc6, 0xb1, 0x53,
-	0x37, 0x14, 0xed, 0xc5, 0xde, 0x58, 0x73, 0xfe, 0x66, 0xce, 0xf7, 0xcd, 0x39, 0xc7, 0x03, 0xf7,
-	0x39, 0x86, 0x73, 0xd7, 0xc1, 0x81, 0x1d, 0x47, 0xd3, 0x41, 0x10, 0xfa, 0x91, 0x2f, 0x96, 0x7d,
-	0xb1, 0x64, 0x46, 0xb2, 0x36, 0x3f, 0x85, 0xed, 0x2f, 0x5d, 0x1e, 0x9d, 0x38, 0x8e, 0x1f, 0x7b,
-	0x11, 0xb7, 0xf0, 0xc7, 0x18, 0x79, 0xc4, 0x0e, 0xa0, 0xea, 0x07, 0x91, 0xeb, 0x7b, 0xbc, 0xab,
-	0xed, 0x69, 0x87, 0xf5, 0x61, 0xb3, 0x2f, 0x42, 0x2f, 0xa4, 0xd2, 0x52, 0x56, 0xf3, 0x04, 0x3a,
-	0xf9, 0x78, 0x1e, 0xf8, 0x1e, 0x47, 0xf6, 0x01, 0xd4, 0x6c, 0xd2, 0x75, 0xb5, 0x3d, 0x7d, 0xb9,
-	0x03, 0x79, 0x5a, 0xa9, 0xd9, 0xbc, 0x80, 0xce, 0x19, 0xce, 0x30, 0x42, 0x65, 0xa2, 0x1c, 0x5a,
-	0x50, 0x72, 0x27, 0xe2, 0xf8, 0x4d, 0xab, 0xe4, 0x4e, 0xb2, 0x39, 0x95, 0xd6, 0xe6, 0xf4, 0x36,
-	0xec, 0xac, 0x6c, 0x28, 0x93, 0x32, 0x7f, 0xd1, 0xa0, 0xfc, 0xdc, 0xbf, 0x42, 0x8f, 0xed, 0x43,
-	0xc3, 0x76, 0x1c, 0xe4, 0xfc, 0x32, 0x4a, 0x64, 0x3a, 0xa5, 0x2e, 0x75, 0xd2, 0xe5, 0x01, 0x34,
-	0x43, 0xfc, 0x21, 0x44, 0x3e, 0x25, 0x9f, 0x92, 0xf0, 0x69, 0x90, 0x52, 0x3a, 0x75, 0xa1, 0xea,
-	0x84, 0x68, 0x47, 0x38, 0xe9, 0xea, 0x7b, 0xda, 0xa1, 0x6e, 0x29, 0x91, 0xed, 0x42, 0x05, 0x7f,
-	0x0a, 0xdc, 0x70, 0xd1, 0x35, 0x84, 0x81, 0x24, 0xf3, 0x1f, 0x0d, 0xaa, 0x94, 0xd7, 0x0d, 0x84,
-	0x0c, 0x8c, 0x68, 0x11, 0x20, 0x9d, 0x24, 0xd6, 0xec, 0x13, 0xa8, 0x5d, 0x63, 0x64, 0x4f, 0xec,
-	0xc8, 0xee, 0x1a, 0x82, 0xc8, 0x7b, 0x39, 0x22, 0xfb, 0x5f, 0x91, 0x75, 0xe4, 0x45, 0xe1, 0xc2,
-	0x4a, 0x9d, 0x93, 0x04, 0xb8, 0xe3, 0x07, 0xc8, 0xbb, 0xe5, 0x3d, 0xfd, 0x70, 0xd3, 0x22, 0x29,
-	0xd1, 0xbb, 0x9c, 0xc7, 0x18, 0x76, 0x2b, 0xe2, 0x18, 0x92, 0x84, 0x3f, 0x3a, 0x21, 0x46, 0xdd,
-	0xaa, 0xd4, 0x4b, 0xa9, 0x77, 0x0c, 0xcd, 0xdc, 0x11, 0xac, 0x0d, 0xfa, 0x15, 0x2e, 0x28, 0xed,
-	0x64, 0xc9, 0x3a, 0x50, 0x9e, 0xdb, 0xb3, 0x58, 0x25, 0x2e, 0x85, 0x27, 0xa5, 0xc7, 0x9a, 0x39,
-	0x86, 0x9a, 0x85, 0xdc, 0x8f, 0x43, 0x07, 0x13, 0x74, 0x9e, 0x7d, 0x8d, 0x14, 0x28, 0xd6, 0x85,
-	0x88, 0x7b, 0x50, 0x43, 0x6f, 0x12, 0xf8, 0xae, 0x17, 0x09, 0x52, 0x37, 0xad, 0x54, 0x36, 0xff,
-	0x2c, 0xc1, 0xd6, 0x39, 0x7a, 0x18, 0xda, 0x11, 0xde, 0x56, 0x27, 0x4f, 0x33, 0x8c, 0xe9, 0x82,
-	0xb1, 0x07, 0x92, 0xb1, 0x95, 0xc0, 0x3b, 0x30, 0x67, 0xac, 0x32, 0x47, 0x0c, 0x95, 0xb3, 0x0c,
-	0xa5, 0x20, 0x2a, 0x79, 0x10, 0x41, 0xe8, 0xcf, 0xdd, 0x09, 0x86, 0xc4, 0x67, 0x2a, 0x67, 0x0b,
-	0xb9, 0xb6, 0xae, 0x90, 0x5f, 0x8f, 0xfa, 0x63, 0x68, 0x2f, 0x01, 0x53, 0x57, 0x1e, 0x40, 0x95,
-	0xda, 0x2e, 0xdf, 0xd6, 0xaa, 0x51, 0x94, 0xd5, 0x5c, 0x40, 0xe3, 0x3c, 0xb4, 0x97, 0xbd, 0xd8,
-	0x81, 0xb2, 0x20, 0x81, 0x8e, 0x96, 0x02, 0x7b, 0x08, 0xb5, 0x90, 0x6e, 0x97, 0x5a, 0xb2, 0x25,
-	0xf7, 0x53, 0x77, 0x6e, 0xa5, 0xf6, 0x2c, 0x68, 0x7d, 0x6d, 0xf7, 0x6e, 0x41, 0x93, 0x8e, 0xa6,
-	0xae, 0xfd, 0x19, 0x9a, 0x16, 0xce, 0xfd, 0x2b, 0x7c, 0x03, 0xc9, 0xb4, 0xa1, 0xa5, 0xce, 0xa6,
-	0x6c, 0x2e, 0xa0, 0xf5, 0xcc, 0xe3, 0x01, 0x3a, 0x59, 0x6e, 0xb2, 0x43, 0x44, 0x0a, 0x77, 0x9f,
-	0x56, 0x4f, 0x60, 0x2b, 0xdd, 0xf0, 0xff, 0x5e, 0xd3, 0x1f, 0x1a, 0x34, 0xc4, 0x20, 0xba, 0xad,
-	0x17, 0x96, 0x25, 0x5b, 0xca, 0x95, 0xec, 0x8d, 0xe1, 0xa6, 0x17, 0x0c, 0xb7, 0x7d, 0x68, 0x08,
-	0xe3, 0x65, 0x6e, 0x90, 0xd5, 0x85, 0x6e, 0x24, 0x54, 0x59, 0x94, 0xe5, 0xb5, 0x28, 0x87, 0xd0,
-	0xa4, 0x44, 0x09, 0xe3, 0x7e, 0x96, 0xb5, 0xfa, 0xb0, 0x2e, 0xe3, 0xa4, 0x8f, 0xb4, 0x98, 0xbf,
-	0x69, 0x60, 0x58, 0xf1, 0x0c, 0x6f, 0xa0, 0x4a, 0x0b, 0xa0, 0x74, 0x5b, 0x01, 0xe8, 0xff, 0x51,
-	0x00, 0xef, 0x43, 0x45, 0xce, 0x7a, 0x01, 0xaa, 0x35, 0x6c, 0xa4, 0x04, 0x23, 0xe7, 0x16, 0xd9,
-	0x64, 0x13, 0xbb, 0x7e, 0xe8, 0x46, 0x0b, 0x01, 0xaf, 0x6c, 0xa5, 0xb2, 0x79, 0x00, 0x55, 0x02,
-	0xc9, 0xde, 0x85, 0xcd, 0x64, 0x98, 0xf1, 0xc0, 0x76, 0x54, 0x4d, 0x2e, 0x15, 0xe6, 0x77, 0xd0,
-	0x3c, 0x15, 0xff, 0x04, 0x75, 0x47, 0xef, 0x81, 0x11, 0xc6, 0x33, 0x24, 0xe0, 0x40, 0x39, 0xc6,
-	0x33, 0xb4, 0x84, 0xfe, 0xee, 0x95, 0xd3, 0x86, 0x96, 0xda, 0x99, 0x8a, 0xf3, 0x73, 0x68, 0xca,
-	0x3f, 0xdf, 0x6b, 0xff, 0x43, 0xdb, 0xd0, 0x52, 0x3b, 0xd1, 0xde, 0x1f, 0x43, 0x3d, 0xf9, 0xd3,
-	0x17, 0xbc, 0x10, 0xd6, 0xef, 0xf4, 0x21, 0x34, 0x64, 0x1c, 0x5d, 0xfc, 0x1e, 0x94, 0x13, 0x98,
-	0xea, 0x59, 0x90, 0xc5, 0x2f, 0x0d, 0xe6, 0xaf, 0x1a, 0x6c, 0x9f, 0x4e, 0x6d, 0xef, 0x25, 0x7e,
-	0x2d, 0xaa, 0xf5, 0x36, 0x30, 0xf7, 0x01, 0xfc, 0xd9, 0xe4, 0x32, 0x57, 0xe0, 0x9b, 0xfe, 0x6c,
-	0x22, 0xa3, 0x12, 0xb3, 0x87, 0xaf, 0x94, 0x59, 0xa7, 0x7b, 0xc1, 0x57, 0x64, 0xce, 0x00, 0x30,
-	0xd6, 0x02, 0xd8, 0x85, 0x4e, 0x3e, 0x1b, 0x09, 0xe4, 0x61, 0x1f, 0x2a, 0xb2, 0x5e, 0x58, 0x1d,
-	0xaa, 0xdf, 0x8c, 0xbf, 0x18, 0x5f, 0x7c, 0x3b, 0x6e, 0x6f, 0x24, 0xc2, 0xb9, 0x75, 0x32, 0x7e,
-	0x3e, 0x3a, 0x6b, 0x6b, 0x0c, 0xa0, 0x72, 0x36, 0x1a, 0x3f, 0x1b, 0x9d, 0xb5, 0x4b, 0xc3, 0xbf,
-	0x34, 0x30, 0x4e, 0xe2, 0x68, 0xca, 0x8e, 0xa1, 0xa6, 0x26, 0x33, 0xdb, 0x29, 0xfc, 0x35, 0xf5,
-	0x76, 0x57, 0xd5, 0x74, 0x09, 0x1b, 0xec, 0x31, 0x54, 0x69, 0x5c, 0xb0, 0x8e, 0x74, 0xca, 0x8f,
-	0xa3, 0xde, 0xce, 0x8a, 0x36, 0x8d, 0x1c, 0xaa, 0xc7, 0x0f, 0xcb, 0xf6, 0x1a, 0x45, 0x6d, 0xe7,
-	0x74, 0x2a, 0x66, 0xf8, 0xb7, 0x06, 0x35, 0xf5, 0xb6, 0x63, 0x4f, 0xc1, 0x48, 0x6e, 0x92, 0xbd,
-	0x23, 0x7d, 0x0b, 0xde, 0x8d, 0xbd, 0x5e, 0x91, 0x29, 0xcd, 0xe0, 0x14, 0x2a, 0xb2, 0xa8, 0x18,
-	0xf9, 0x15, 0xbd, 0xfb, 0x7a, 0xf7, 0x0a, 0x6d, 0xe9, 0x26, 0xe7, 0xd0, 0xc8, 0x5e, 0x87, 0xca,
-	0xa6, 0xa0, 0x60, 0x54, 0x36, 0x45, 0xb7, 0x67, 0x6e, 0x0c, 0x7f, 0xd7, 0xa0, 0x9c, 0x94, 0x1d,
-	0x67, 0x8f, 0xa0, 0x22, 0x1b, 0x89, 0x11, 0x0d, 0xb9, 0x86, 0xed, 0x75, 0xf2, 0xca, 0x34, 0x93,
-	0x47, 0x29, 0x9c, 0xed, 0x6c, 0xca, 0x2b, 0x61, 0x2b, 0x6d, 0xb4, 0xc1, 0x06, 0x44, 0xe3, 0x5b,
-	0x4b, 0xae, 0x54, 0x08, 0xcb, 0xaa, 0x54, 0xc0, 0x67, 0x47, 0xdf, 0x7f, 0xf4, 0xd2, 0x8d, 0xa6,
-	0xf1, 0x8b, 0xbe, 0xe3, 0x5f, 0x0f, 0xae, 0x5d, 0x27, 0xf4, 0xe9, 0x3b, 0x3f, 0x1a, 0xdc, 0x7c,
-	0xe4, 0x1f, 0x27, 0xcb, 0x17, 0x15, 0xb1, 0x3e, 0xfa, 0x37, 0x00, 0x00, 0xff, 0xff, 0xa5, 0x1f,
-	0xe0, 0x03, 0x06, 0x0c, 0x00, 0x00,
+func (m *ReadProfileRequest) Reset()         { *m = ReadProfileRequest{} }
+func (m *ReadProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadProfileRequest) ProtoMessage()    {}
+func (*ReadProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{27}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// AuthClient is the client API for Auth service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthClient interface {
-	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
-	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
-	Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+func (m *ReadProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadProfileRequest.Unmarshal(m, b)
 }
-
-type authClient struct {
-	cc *grpc.ClientConn
+func (m *ReadProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadProfileRequest.Marshal(b, m, deterministic)
 }
-
-func NewAuthClient(cc *grpc.ClientConn) AuthClient {
-	return &authClient{cc}
+func (m *ReadProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadProfileRequest.Merge(m, src)
 }
-
-func (c *authClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
-	out := new(GenerateResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Generate", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *ReadProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadProfileRequest.Size(m)
+}
+func (m *ReadProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadProfileRequest.DiscardUnknown(m)
 }
 
-func (c *authClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
-	out := new(InspectResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Inspect", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ReadProfileRequest proto.InternalMessageInfo
+
+func (m *ReadProfileRequest) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-func (c *authClient) Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
-	out := new(TokenResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Token", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ReadProfileRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
 	}
-	return out, nil
+	return nil
 }
 
-// AuthServer is the server API for Auth service.
-type AuthServer interface {
-	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
-	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
-	Token(context.Context, *TokenRequest) (*TokenResponse, error)
+type ReadProfileResponse struct {
+	Profile              map[string]string `protobuf:"bytes,1,rep,name=profile,proto3" json:"profile,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-// UnimplementedAuthServer can be embedded to have forward compatible implementations.
-type UnimplementedAuthServer struct {
+func (m *ReadProfileResponse) Reset()         { *m = ReadProfileResponse{} }
+func (m *ReadProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadProfileResponse) ProtoMessage()    {}
+func (*ReadProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{28}
 }
 
-func (*UnimplementedAuthServer) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+func (m *ReadProfileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadProfileResponse.Unmarshal(m, b)
 }
-func (*UnimplementedAuthServer) Inspect(ctx context.Context, req *InspectRequest) (*InspectResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Inspect not implemented")
+func (m *ReadProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadProfileResponse.Marshal(b, m, deterministic)
 }
-func (*UnimplementedAuthServer) Token(ctx context.Context, req *TokenRequest) (*TokenResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Token not implemented")
+func (m *ReadProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadProfileResponse.Merge(m, src)
 }
-
-func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
-	s.RegisterService(&_Auth_serviceDesc, srv)
+func (m *ReadProfileResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadProfileResponse.Size(m)
 }
-
-func _Auth_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GenerateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).Generate(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/auth.Auth/Generate",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Generate(ctx, req.(*GenerateRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+func (m *ReadProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadProfileResponse.DiscardUnknown(m)
 }
 
-func _Auth_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InspectRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).Inspect(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/auth.Auth/Inspect",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Inspect(ctx, req.(*InspectRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
+var xxx_messageInfo_ReadProfileResponse proto.InternalMessageInfo
 
-func _Auth_Token_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).Token(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/auth.Auth/Token",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Token(ctx, req.(*TokenRequest))
+func (m *ReadProfileResponse) GetProfile() map[string]string {
+	if m != nil {
+		return m.Profile
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _Auth_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "auth.Auth",
-	HandlerType: (*AuthServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Generate",
-			Handler:    _Auth_Generate_Handler,
-		},
-		{
-			MethodName: "Inspect",
-			Handler:    _Auth_Inspect_Handler,
-		},
-		{
-			MethodName: "Token",
-			Handler:    _Auth_Token_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "service/auth/proto/auth.proto",
+type UpdateProfileRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// attributes to set, a blank value removes the attribute
+	Profile              map[string]string `protobuf:"bytes,2,rep,name=profile,proto3" json:"profile,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Options              *Options          `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-// AccountsClient is the client API for Accounts service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AccountsClient interface {
-	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
-	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
-	ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error)
+func (m *UpdateProfileRequest) Reset()         { *m = UpdateProfileRequest{} }
+func (m *UpdateProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateProfileRequest) ProtoMessage()    {}
+func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{29}
 }
 
-type accountsClient struct {
-	cc *grpc.ClientConn
+func (m *UpdateProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateProfileRequest.Unmarshal(m, b)
 }
-
-func NewAccountsClient(cc *grpc.ClientConn) AccountsClient {
-	return &accountsClient{cc}
+func (m *UpdateProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateProfileRequest.Merge(m, src)
+}
+func (m *UpdateProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateProfileRequest.Size(m)
+}
+func (m *UpdateProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateProfileRequest.DiscardUnknown(m)
 }
 
-func (c *accountsClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
-	out := new(ListAccountsResponse)
-	err := c.cc.Invoke(ctx, "/auth.Accounts/List", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_UpdateProfileRequest proto.InternalMessageInfo
+
+func (m *UpdateProfileRequest) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountsClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
-	out := new(DeleteAccountResponse)
-	err := c.cc.Invoke(ctx, "/auth.Accounts/Delete", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *UpdateProfileRequest) GetProfile() map[string]string {
+	if m != nil {
+		return m.Profile
 	}
-	return out, nil
+	return nil
 }
 
-func (c *accountsClient) ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error) {
-	out := new(ChangeSecretResponse)
-	err := c.cc.Invoke(ctx, "/auth.Accounts/ChangeSecret", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *UpdateProfileRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
 	}
-	return out, nil
+	return nil
 }
 
-// AccountsServer is the server API for Accounts service.
-type AccountsServer interface {
-	List(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
-	Delete(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
-	ChangeSecret(context.Context, *ChangeSecretRequest) (*ChangeSecretResponse, error)
+type UpdateProfileResponse struct {
+	Profile              map[string]string `protobuf:"bytes,1,rep,name=profile,proto3" json:"profile,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-// UnimplementedAccountsServer can be embedded to have forward compatible implementations.
-type UnimplementedAccountsServer struct {
+func (m *UpdateProfileResponse) Reset()         { *m = UpdateProfileResponse{} }
+func (m *UpdateProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateProfileResponse) ProtoMessage()    {}
+func (*UpdateProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{30}
 }
 
-func (*UnimplementedAccountsServer) List(ctx context.Context, req *ListAccountsRequest) (*ListAccountsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+func (m *UpdateProfileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateProfileResponse.Unmarshal(m, b)
+}
+func (m *UpdateProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateProfileResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateProfileResponse.Merge(m, src)
+}
+func (m *UpdateProfileResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateProfileResponse.Size(m)
+}
+func (m *UpdateProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateProfileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateProfileResponse proto.InternalMessageInfo
+
+func (m *UpdateProfileResponse) GetProfile() map[string]string {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+// Grant records the scopes an account has consented to a client application using
+type Grant struct {
+	AccountId string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	ClientId  string   `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Scopes    []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// unix timestamp the grant was created or last updated
+	Created              int64    `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Grant) Reset()         { *m = Grant{} }
+func (m *Grant) String() string { return proto.CompactTextString(m) }
+func (*Grant) ProtoMessage()    {}
+func (*Grant) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{31}
+}
+
+func (m *Grant) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Grant.Unmarshal(m, b)
+}
+func (m *Grant) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Grant.Marshal(b, m, deterministic)
+}
+func (m *Grant) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Grant.Merge(m, src)
+}
+func (m *Grant) XXX_Size() int {
+	return xxx_messageInfo_Grant.Size(m)
+}
+func (m *Grant) XXX_DiscardUnknown() {
+	xxx_messageInfo_Grant.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Grant proto.InternalMessageInfo
+
+func (m *Grant) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *Grant) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *Grant) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *Grant) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+// CreateGrantRequest records the consent of the calling account for the
+// client to use the scopes on its behalf
+type CreateGrantRequest struct {
+	ClientId             string   `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Scopes               []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Options              *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateGrantRequest) Reset()         { *m = CreateGrantRequest{} }
+func (m *CreateGrantRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateGrantRequest) ProtoMessage()    {}
+func (*CreateGrantRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{32}
+}
+
+func (m *CreateGrantRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateGrantRequest.Unmarshal(m, b)
+}
+func (m *CreateGrantRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateGrantRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateGrantRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateGrantRequest.Merge(m, src)
+}
+func (m *CreateGrantRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateGrantRequest.Size(m)
+}
+func (m *CreateGrantRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateGrantRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateGrantRequest proto.InternalMessageInfo
+
+func (m *CreateGrantRequest) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *CreateGrantRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateGrantRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CreateGrantResponse struct {
+	Grant                *Grant   `protobuf:"bytes,1,opt,name=grant,proto3" json:"grant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateGrantResponse) Reset()         { *m = CreateGrantResponse{} }
+func (m *CreateGrantResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateGrantResponse) ProtoMessage()    {}
+func (*CreateGrantResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{33}
+}
+
+func (m *CreateGrantResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateGrantResponse.Unmarshal(m, b)
+}
+func (m *CreateGrantResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateGrantResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateGrantResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateGrantResponse.Merge(m, src)
+}
+func (m *CreateGrantResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateGrantResponse.Size(m)
+}
+func (m *CreateGrantResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateGrantResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateGrantResponse proto.InternalMessageInfo
+
+func (m *CreateGrantResponse) GetGrant() *Grant {
+	if m != nil {
+		return m.Grant
+	}
+	return nil
+}
+
+// ListGrantsRequest lists the grants of the calling account
+type ListGrantsRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGrantsRequest) Reset()         { *m = ListGrantsRequest{} }
+func (m *ListGrantsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGrantsRequest) ProtoMessage()    {}
+func (*ListGrantsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{34}
+}
+
+func (m *ListGrantsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGrantsRequest.Unmarshal(m, b)
+}
+func (m *ListGrantsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGrantsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGrantsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGrantsRequest.Merge(m, src)
+}
+func (m *ListGrantsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGrantsRequest.Size(m)
+}
+func (m *ListGrantsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGrantsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGrantsRequest proto.InternalMessageInfo
+
+func (m *ListGrantsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListGrantsResponse struct {
+	Grants               []*Grant `protobuf:"bytes,1,rep,name=grants,proto3" json:"grants,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGrantsResponse) Reset()         { *m = ListGrantsResponse{} }
+func (m *ListGrantsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListGrantsResponse) ProtoMessage()    {}
+func (*ListGrantsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{35}
+}
+
+func (m *ListGrantsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGrantsResponse.Unmarshal(m, b)
+}
+func (m *ListGrantsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGrantsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListGrantsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGrantsResponse.Merge(m, src)
+}
+func (m *ListGrantsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListGrantsResponse.Size(m)
+}
+func (m *ListGrantsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGrantsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGrantsResponse proto.InternalMessageInfo
+
+func (m *ListGrantsResponse) GetGrants() []*Grant {
+	if m != nil {
+		return m.Grants
+	}
+	return nil
+}
+
+type RevokeGrantRequest struct {
+	ClientId             string   `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeGrantRequest) Reset()         { *m = RevokeGrantRequest{} }
+func (m *RevokeGrantRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeGrantRequest) ProtoMessage()    {}
+func (*RevokeGrantRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{36}
+}
+
+func (m *RevokeGrantRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeGrantRequest.Unmarshal(m, b)
+}
+func (m *RevokeGrantRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeGrantRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeGrantRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeGrantRequest.Merge(m, src)
+}
+func (m *RevokeGrantRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeGrantRequest.Size(m)
+}
+func (m *RevokeGrantRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeGrantRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeGrantRequest proto.InternalMessageInfo
+
+func (m *RevokeGrantRequest) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *RevokeGrantRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevokeGrantResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeGrantResponse) Reset()         { *m = RevokeGrantResponse{} }
+func (m *RevokeGrantResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeGrantResponse) ProtoMessage()    {}
+func (*RevokeGrantResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{37}
+}
+
+func (m *RevokeGrantResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeGrantResponse.Unmarshal(m, b)
+}
+func (m *RevokeGrantResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeGrantResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeGrantResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeGrantResponse.Merge(m, src)
+}
+func (m *RevokeGrantResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeGrantResponse.Size(m)
+}
+func (m *RevokeGrantResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeGrantResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeGrantResponse proto.InternalMessageInfo
+
+// Domain is a DNS domain claimed by a namespace
+type Domain struct {
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// value of the TXT record which proves control of the domain
+	Challenge string `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Verified  bool   `protobuf:"varint,4,opt,name=verified,proto3" json:"verified,omitempty"`
+	// new users with an email address at the domain join the namespace
+	AutoJoin bool `protobuf:"varint,5,opt,name=auto_join,json=autoJoin,proto3" json:"auto_join,omitempty"`
+	// unix timestamps the domain was claimed and verified
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	VerifiedAt           int64    `protobuf:"varint,7,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Domain) Reset()         { *m = Domain{} }
+func (m *Domain) String() string { return proto.CompactTextString(m) }
+func (*Domain) ProtoMessage()    {}
+func (*Domain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{38}
+}
+
+func (m *Domain) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Domain.Unmarshal(m, b)
+}
+func (m *Domain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Domain.Marshal(b, m, deterministic)
+}
+func (m *Domain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Domain.Merge(m, src)
+}
+func (m *Domain) XXX_Size() int {
+	return xxx_messageInfo_Domain.Size(m)
+}
+func (m *Domain) XXX_DiscardUnknown() {
+	xxx_messageInfo_Domain.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Domain proto.InternalMessageInfo
+
+func (m *Domain) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Domain) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Domain) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *Domain) GetVerified() bool {
+	if m != nil {
+		return m.Verified
+	}
+	return false
+}
+
+func (m *Domain) GetAutoJoin() bool {
+	if m != nil {
+		return m.AutoJoin
+	}
+	return false
+}
+
+func (m *Domain) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Domain) GetVerifiedAt() int64 {
+	if m != nil {
+		return m.VerifiedAt
+	}
+	return 0
+}
+
+// ClaimDomainRequest starts the verification of a domain for a namespace
+type ClaimDomainRequest struct {
+	Domain               string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	AutoJoin             bool     `protobuf:"varint,2,opt,name=auto_join,json=autoJoin,proto3" json:"auto_join,omitempty"`
+	Options              *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClaimDomainRequest) Reset()         { *m = ClaimDomainRequest{} }
+func (m *ClaimDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*ClaimDomainRequest) ProtoMessage()    {}
+func (*ClaimDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{39}
+}
+
+func (m *ClaimDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClaimDomainRequest.Unmarshal(m, b)
+}
+func (m *ClaimDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClaimDomainRequest.Marshal(b, m, deterministic)
+}
+func (m *ClaimDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClaimDomainRequest.Merge(m, src)
+}
+func (m *ClaimDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_ClaimDomainRequest.Size(m)
+}
+func (m *ClaimDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClaimDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClaimDomainRequest proto.InternalMessageInfo
+
+func (m *ClaimDomainRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *ClaimDomainRequest) GetAutoJoin() bool {
+	if m != nil {
+		return m.AutoJoin
+	}
+	return false
+}
+
+func (m *ClaimDomainRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ClaimDomainResponse struct {
+	Domain *Domain `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// name of the TXT record to create with the challenge as its value
+	Record               string   `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClaimDomainResponse) Reset()         { *m = ClaimDomainResponse{} }
+func (m *ClaimDomainResponse) String() string { return proto.CompactTextString(m) }
+func (*ClaimDomainResponse) ProtoMessage()    {}
+func (*ClaimDomainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{40}
+}
+
+func (m *ClaimDomainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClaimDomainResponse.Unmarshal(m, b)
+}
+func (m *ClaimDomainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClaimDomainResponse.Marshal(b, m, deterministic)
+}
+func (m *ClaimDomainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClaimDomainResponse.Merge(m, src)
+}
+func (m *ClaimDomainResponse) XXX_Size() int {
+	return xxx_messageInfo_ClaimDomainResponse.Size(m)
+}
+func (m *ClaimDomainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClaimDomainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClaimDomainResponse proto.InternalMessageInfo
+
+func (m *ClaimDomainResponse) GetDomain() *Domain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *ClaimDomainResponse) GetRecord() string {
+	if m != nil {
+		return m.Record
+	}
+	return ""
+}
+
+// VerifyDomainRequest checks the TXT record of a claimed domain
+type VerifyDomainRequest struct {
+	Domain               string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyDomainRequest) Reset()         { *m = VerifyDomainRequest{} }
+func (m *VerifyDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyDomainRequest) ProtoMessage()    {}
+func (*VerifyDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{41}
+}
+
+func (m *VerifyDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyDomainRequest.Unmarshal(m, b)
+}
+func (m *VerifyDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyDomainRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyDomainRequest.Merge(m, src)
+}
+func (m *VerifyDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyDomainRequest.Size(m)
+}
+func (m *VerifyDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyDomainRequest proto.InternalMessageInfo
+
+func (m *VerifyDomainRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *VerifyDomainRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type VerifyDomainResponse struct {
+	Domain               *Domain  `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyDomainResponse) Reset()         { *m = VerifyDomainResponse{} }
+func (m *VerifyDomainResponse) String() string { return proto.CompactTextString(m) }
+func (*VerifyDomainResponse) ProtoMessage()    {}
+func (*VerifyDomainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{42}
+}
+
+func (m *VerifyDomainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyDomainResponse.Unmarshal(m, b)
+}
+func (m *VerifyDomainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyDomainResponse.Marshal(b, m, deterministic)
+}
+func (m *VerifyDomainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyDomainResponse.Merge(m, src)
+}
+func (m *VerifyDomainResponse) XXX_Size() int {
+	return xxx_messageInfo_VerifyDomainResponse.Size(m)
+}
+func (m *VerifyDomainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyDomainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyDomainResponse proto.InternalMessageInfo
+
+func (m *VerifyDomainResponse) GetDomain() *Domain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+type ListDomainsRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDomainsRequest) Reset()         { *m = ListDomainsRequest{} }
+func (m *ListDomainsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDomainsRequest) ProtoMessage()    {}
+func (*ListDomainsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{43}
+}
+
+func (m *ListDomainsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDomainsRequest.Unmarshal(m, b)
+}
+func (m *ListDomainsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDomainsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDomainsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDomainsRequest.Merge(m, src)
+}
+func (m *ListDomainsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDomainsRequest.Size(m)
+}
+func (m *ListDomainsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDomainsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDomainsRequest proto.InternalMessageInfo
+
+func (m *ListDomainsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListDomainsResponse struct {
+	Domains              []*Domain `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListDomainsResponse) Reset()         { *m = ListDomainsResponse{} }
+func (m *ListDomainsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDomainsResponse) ProtoMessage()    {}
+func (*ListDomainsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{44}
+}
+
+func (m *ListDomainsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDomainsResponse.Unmarshal(m, b)
+}
+func (m *ListDomainsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDomainsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDomainsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDomainsResponse.Merge(m, src)
+}
+func (m *ListDomainsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDomainsResponse.Size(m)
+}
+func (m *ListDomainsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDomainsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDomainsResponse proto.InternalMessageInfo
+
+func (m *ListDomainsResponse) GetDomains() []*Domain {
+	if m != nil {
+		return m.Domains
+	}
+	return nil
+}
+
+type ReleaseDomainRequest struct {
+	Domain               string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseDomainRequest) Reset()         { *m = ReleaseDomainRequest{} }
+func (m *ReleaseDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*ReleaseDomainRequest) ProtoMessage()    {}
+func (*ReleaseDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{45}
+}
+
+func (m *ReleaseDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseDomainRequest.Unmarshal(m, b)
+}
+func (m *ReleaseDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseDomainRequest.Marshal(b, m, deterministic)
+}
+func (m *ReleaseDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseDomainRequest.Merge(m, src)
+}
+func (m *ReleaseDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_ReleaseDomainRequest.Size(m)
+}
+func (m *ReleaseDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseDomainRequest proto.InternalMessageInfo
+
+func (m *ReleaseDomainRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *ReleaseDomainRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ReleaseDomainResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseDomainResponse) Reset()         { *m = ReleaseDomainResponse{} }
+func (m *ReleaseDomainResponse) String() string { return proto.CompactTextString(m) }
+func (*ReleaseDomainResponse) ProtoMessage()    {}
+func (*ReleaseDomainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{46}
+}
+
+func (m *ReleaseDomainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseDomainResponse.Unmarshal(m, b)
+}
+func (m *ReleaseDomainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseDomainResponse.Marshal(b, m, deterministic)
+}
+func (m *ReleaseDomainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseDomainResponse.Merge(m, src)
+}
+func (m *ReleaseDomainResponse) XXX_Size() int {
+	return xxx_messageInfo_ReleaseDomainResponse.Size(m)
+}
+func (m *ReleaseDomainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseDomainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseDomainResponse proto.InternalMessageInfo
+
+// ResolveDomainRequest looks up the namespace a verified domain is bound to
+type ResolveDomainRequest struct {
+	Domain               string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResolveDomainRequest) Reset()         { *m = ResolveDomainRequest{} }
+func (m *ResolveDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*ResolveDomainRequest) ProtoMessage()    {}
+func (*ResolveDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{47}
+}
+
+func (m *ResolveDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveDomainRequest.Unmarshal(m, b)
+}
+func (m *ResolveDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveDomainRequest.Marshal(b, m, deterministic)
+}
+func (m *ResolveDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveDomainRequest.Merge(m, src)
+}
+func (m *ResolveDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_ResolveDomainRequest.Size(m)
+}
+func (m *ResolveDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveDomainRequest proto.InternalMessageInfo
+
+func (m *ResolveDomainRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+type ResolveDomainResponse struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	AutoJoin             bool     `protobuf:"varint,2,opt,name=auto_join,json=autoJoin,proto3" json:"auto_join,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResolveDomainResponse) Reset()         { *m = ResolveDomainResponse{} }
+func (m *ResolveDomainResponse) String() string { return proto.CompactTextString(m) }
+func (*ResolveDomainResponse) ProtoMessage()    {}
+func (*ResolveDomainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{48}
+}
+
+func (m *ResolveDomainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveDomainResponse.Unmarshal(m, b)
+}
+func (m *ResolveDomainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveDomainResponse.Marshal(b, m, deterministic)
+}
+func (m *ResolveDomainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveDomainResponse.Merge(m, src)
+}
+func (m *ResolveDomainResponse) XXX_Size() int {
+	return xxx_messageInfo_ResolveDomainResponse.Size(m)
+}
+func (m *ResolveDomainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveDomainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveDomainResponse proto.InternalMessageInfo
+
+func (m *ResolveDomainResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ResolveDomainResponse) GetAutoJoin() bool {
+	if m != nil {
+		return m.AutoJoin
+	}
+	return false
+}
+
+// MatrixRequest evaluates the rules for the identities which call services
+type MatrixRequest struct {
+	Options *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// services to include as callees, blank for every registered service
+	Services             []string `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MatrixRequest) Reset()         { *m = MatrixRequest{} }
+func (m *MatrixRequest) String() string { return proto.CompactTextString(m) }
+func (*MatrixRequest) ProtoMessage()    {}
+func (*MatrixRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{49}
+}
+
+func (m *MatrixRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatrixRequest.Unmarshal(m, b)
+}
+func (m *MatrixRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatrixRequest.Marshal(b, m, deterministic)
+}
+func (m *MatrixRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatrixRequest.Merge(m, src)
+}
+func (m *MatrixRequest) XXX_Size() int {
+	return xxx_messageInfo_MatrixRequest.Size(m)
+}
+func (m *MatrixRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatrixRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatrixRequest proto.InternalMessageInfo
+
+func (m *MatrixRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *MatrixRequest) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+// MatrixEntry is the access an identity has to an endpoint
+type MatrixEntry struct {
+	// id of the account, blank for public access
+	Identity string   `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Scopes   []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Service  string   `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+	Endpoint string   `protobuf:"bytes,4,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Granted  bool     `protobuf:"varint,5,opt,name=granted,proto3" json:"granted,omitempty"`
+	// id of the rule which decided the access, blank if no rule applied
+	Rule                 string   `protobuf:"bytes,6,opt,name=rule,proto3" json:"rule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MatrixEntry) Reset()         { *m = MatrixEntry{} }
+func (m *MatrixEntry) String() string { return proto.CompactTextString(m) }
+func (*MatrixEntry) ProtoMessage()    {}
+func (*MatrixEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{50}
+}
+
+func (m *MatrixEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatrixEntry.Unmarshal(m, b)
+}
+func (m *MatrixEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatrixEntry.Marshal(b, m, deterministic)
+}
+func (m *MatrixEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatrixEntry.Merge(m, src)
+}
+func (m *MatrixEntry) XXX_Size() int {
+	return xxx_messageInfo_MatrixEntry.Size(m)
+}
+func (m *MatrixEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatrixEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatrixEntry proto.InternalMessageInfo
+
+func (m *MatrixEntry) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *MatrixEntry) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *MatrixEntry) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *MatrixEntry) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *MatrixEntry) GetGranted() bool {
+	if m != nil {
+		return m.Granted
+	}
+	return false
+}
+
+func (m *MatrixEntry) GetRule() string {
+	if m != nil {
+		return m.Rule
+	}
+	return ""
+}
+
+type MatrixResponse struct {
+	Entries              []*MatrixEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *MatrixResponse) Reset()         { *m = MatrixResponse{} }
+func (m *MatrixResponse) String() string { return proto.CompactTextString(m) }
+func (*MatrixResponse) ProtoMessage()    {}
+func (*MatrixResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{51}
+}
+
+func (m *MatrixResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatrixResponse.Unmarshal(m, b)
+}
+func (m *MatrixResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatrixResponse.Marshal(b, m, deterministic)
+}
+func (m *MatrixResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatrixResponse.Merge(m, src)
+}
+func (m *MatrixResponse) XXX_Size() int {
+	return xxx_messageInfo_MatrixResponse.Size(m)
+}
+func (m *MatrixResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatrixResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatrixResponse proto.InternalMessageInfo
+
+func (m *MatrixResponse) GetEntries() []*MatrixEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// EnrollMFARequest generates a new TOTP secret for an account, which is then required
+// for step-up verification
+type EnrollMFARequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnrollMFARequest) Reset()         { *m = EnrollMFARequest{} }
+func (m *EnrollMFARequest) String() string { return proto.CompactTextString(m) }
+func (*EnrollMFARequest) ProtoMessage()    {}
+func (*EnrollMFARequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{52}
+}
+
+func (m *EnrollMFARequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnrollMFARequest.Unmarshal(m, b)
+}
+func (m *EnrollMFARequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnrollMFARequest.Marshal(b, m, deterministic)
+}
+func (m *EnrollMFARequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnrollMFARequest.Merge(m, src)
+}
+func (m *EnrollMFARequest) XXX_Size() int {
+	return xxx_messageInfo_EnrollMFARequest.Size(m)
+}
+func (m *EnrollMFARequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnrollMFARequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnrollMFARequest proto.InternalMessageInfo
+
+func (m *EnrollMFARequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *EnrollMFARequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type EnrollMFAResponse struct {
+	// base32 encoded TOTP secret
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// otpauth url of the secret, e.g. to render as a QR code
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnrollMFAResponse) Reset()         { *m = EnrollMFAResponse{} }
+func (m *EnrollMFAResponse) String() string { return proto.CompactTextString(m) }
+func (*EnrollMFAResponse) ProtoMessage()    {}
+func (*EnrollMFAResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6198f7e829fc4ef7, []int{53}
+}
+
+func (m *EnrollMFAResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnrollMFAResponse.Unmarshal(m, b)
+}
+func (m *EnrollMFAResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnrollMFAResponse.Marshal(b, m, deterministic)
+}
+func (m *EnrollMFAResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnrollMFAResponse.Merge(m, src)
+}
+func (m *EnrollMFAResponse) XXX_Size() int {
+	return xxx_messageInfo_EnrollMFAResponse.Size(m)
+}
+func (m *EnrollMFAResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnrollMFAResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnrollMFAResponse proto.InternalMessageInfo
+
+func (m *EnrollMFAResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *EnrollMFAResponse) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("auth.Access", Access_name, Access_value)
+	proto.RegisterType((*ListAccountsRequest)(nil), "auth.ListAccountsRequest")
+	proto.RegisterType((*ListAccountsResponse)(nil), "auth.ListAccountsResponse")
+	proto.RegisterType((*DeleteAccountRequest)(nil), "auth.DeleteAccountRequest")
+	proto.RegisterType((*DeleteAccountResponse)(nil), "auth.DeleteAccountResponse")
+	proto.RegisterType((*Token)(nil), "auth.Token")
+	proto.RegisterType((*Account)(nil), "auth.Account")
+	proto.RegisterMapType((map[string]string)(nil), "auth.Account.MetadataEntry")
+	proto.RegisterType((*Resource)(nil), "auth.Resource")
+	proto.RegisterType((*GenerateRequest)(nil), "auth.GenerateRequest")
+	proto.RegisterMapType((map[string]string)(nil), "auth.GenerateRequest.MetadataEntry")
+	proto.RegisterType((*GenerateResponse)(nil), "auth.GenerateResponse")
+	proto.RegisterType((*GrantRequest)(nil), "auth.GrantRequest")
+	proto.RegisterType((*GrantResponse)(nil), "auth.GrantResponse")
+	proto.RegisterType((*RevokeRequest)(nil), "auth.RevokeRequest")
+	proto.RegisterType((*RevokeResponse)(nil), "auth.RevokeResponse")
+	proto.RegisterType((*InspectRequest)(nil), "auth.InspectRequest")
+	proto.RegisterType((*InspectResponse)(nil), "auth.InspectResponse")
+	proto.RegisterType((*TokenRequest)(nil), "auth.TokenRequest")
+	proto.RegisterType((*TokenResponse)(nil), "auth.TokenResponse")
+	proto.RegisterType((*Rule)(nil), "auth.Rule")
+	proto.RegisterType((*Options)(nil), "auth.Options")
+	proto.RegisterType((*CreateRequest)(nil), "auth.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "auth.CreateResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "auth.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "auth.DeleteResponse")
+	proto.RegisterType((*ListRequest)(nil), "auth.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "auth.ListResponse")
+	proto.RegisterType((*ChangeSecretRequest)(nil), "auth.ChangeSecretRequest")
+	proto.RegisterType((*ChangeSecretResponse)(nil), "auth.ChangeSecretResponse")
+	proto.RegisterType((*ReadProfileRequest)(nil), "auth.ReadProfileRequest")
+	proto.RegisterType((*ReadProfileResponse)(nil), "auth.ReadProfileResponse")
+	proto.RegisterMapType((map[string]string)(nil), "auth.ReadProfileResponse.ProfileEntry")
+	proto.RegisterType((*UpdateProfileRequest)(nil), "auth.UpdateProfileRequest")
+	proto.RegisterMapType((map[string]string)(nil), "auth.UpdateProfileRequest.ProfileEntry")
+	proto.RegisterType((*UpdateProfileResponse)(nil), "auth.UpdateProfileResponse")
+	proto.RegisterMapType((map[string]string)(nil), "auth.UpdateProfileResponse.ProfileEntry")
+	proto.RegisterType((*Grant)(nil), "auth.Grant")
+	proto.RegisterType((*CreateGrantRequest)(nil), "auth.CreateGrantRequest")
+	proto.RegisterType((*CreateGrantResponse)(nil), "auth.CreateGrantResponse")
+	proto.RegisterType((*ListGrantsRequest)(nil), "auth.ListGrantsRequest")
+	proto.RegisterType((*ListGrantsResponse)(nil), "auth.ListGrantsResponse")
+	proto.RegisterType((*RevokeGrantRequest)(nil), "auth.RevokeGrantRequest")
+	proto.RegisterType((*RevokeGrantResponse)(nil), "auth.RevokeGrantResponse")
+	proto.RegisterType((*Domain)(nil), "auth.Domain")
+	proto.RegisterType((*ClaimDomainRequest)(nil), "auth.ClaimDomainRequest")
+	proto.RegisterType((*ClaimDomainResponse)(nil), "auth.ClaimDomainResponse")
+	proto.RegisterType((*VerifyDomainRequest)(nil), "auth.VerifyDomainRequest")
+	proto.RegisterType((*VerifyDomainResponse)(nil), "auth.VerifyDomainResponse")
+	proto.RegisterType((*ListDomainsRequest)(nil), "auth.ListDomainsRequest")
+	proto.RegisterType((*ListDomainsResponse)(nil), "auth.ListDomainsResponse")
+	proto.RegisterType((*ReleaseDomainRequest)(nil), "auth.ReleaseDomainRequest")
+	proto.RegisterType((*ReleaseDomainResponse)(nil), "auth.ReleaseDomainResponse")
+	proto.RegisterType((*ResolveDomainRequest)(nil), "auth.ResolveDomainRequest")
+	proto.RegisterType((*ResolveDomainResponse)(nil), "auth.ResolveDomainResponse")
+	proto.RegisterType((*MatrixRequest)(nil), "auth.MatrixRequest")
+	proto.RegisterType((*MatrixEntry)(nil), "auth.MatrixEntry")
+	proto.RegisterType((*MatrixResponse)(nil), "auth.MatrixResponse")
+	proto.RegisterType((*EnrollMFARequest)(nil), "auth.EnrollMFARequest")
+	proto.RegisterType((*EnrollMFAResponse)(nil), "auth.EnrollMFAResponse")
+}
+
+func init() { proto.RegisterFile("service/auth/proto/auth.proto", fileDescriptor_6198f7e829fc4ef7) }
+
+var fileDescriptor_6198f7e829fc4ef7 = []byte{
+	// 1846 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc5, 0x19, 0x4d, 0x6f, 0x1b, 0x45,
+	0x34, 0xeb, 0x6f, 0x3f, 0xdb, 0x69, 0x32, 0x71, 0x1a, 0xd7, 0xa5, 0xd0, 0x6e, 0xab, 0xb6, 0x14,
+	0x29, 0x01, 0x57, 0x85, 0xd2, 0x34, 0x2d, 0xa6, 0x09, 0xa1, 0x2d, 0x4d, 0xd0, 0xf6, 0x0b, 0xf5,
+	0x12, 0x6d, 0xed, 0x69, 0xb3, 0xd4, 0xf1, 0x9a, 0xdd, 0x75, 0xda, 0x70, 0xe3, 0xce, 0x8d, 0x03,
+	0x12, 0xf7, 0x4a, 0x48, 0x9c, 0xf8, 0x15, 0x5c, 0x10, 0x57, 0x4e, 0xfc, 0x02, 0xfe, 0x04, 0xf3,
+	0xf1, 0x66, 0x3d, 0x63, 0xaf, 0x5d, 0x47, 0x01, 0x71, 0xb1, 0xe6, 0xbd, 0x37, 0xf3, 0xbe, 0xe6,
+	0x7d, 0xcd, 0x1a, 0x4e, 0x85, 0x34, 0xd8, 0xf7, 0x5a, 0x74, 0xc5, 0xed, 0x47, 0xbb, 0x2b, 0xbd,
+	0xc0, 0x8f, 0x7c, 0xb1, 0x5c, 0x16, 0x4b, 0x92, 0xe1, 0x6b, 0xfb, 0x06, 0x2c, 0x7c, 0xe1, 0x85,
+	0x51, 0xb3, 0xd5, 0xf2, 0xfb, 0xdd, 0x28, 0x74, 0xe8, 0x37, 0x7d, 0x1a, 0x46, 0xe4, 0x02, 0xe4,
+	0xfd, 0x5e, 0xe4, 0xf9, 0xdd, 0xb0, 0x66, 0x9d, 0xb6, 0x2e, 0x96, 0x1a, 0x95, 0x65, 0x71, 0x74,
+	0x5b, 0x22, 0x1d, 0x45, 0xb5, 0x9b, 0x50, 0x35, 0xcf, 0x87, 0x3d, 0x86, 0xa6, 0xe4, 0x5d, 0x28,
+	0xb8, 0x88, 0x63, 0x1c, 0xd2, 0x03, 0x0e, 0xb8, 0xd3, 0x89, 0xc9, 0xf6, 0x36, 0x54, 0xd7, 0x69,
+	0x87, 0x46, 0x54, 0x91, 0x50, 0x87, 0x59, 0x48, 0x79, 0x6d, 0x21, 0xbe, 0xe8, 0xb0, 0x95, 0xae,
+	0x53, 0x6a, 0xa2, 0x4e, 0x4b, 0xb0, 0x38, 0xc4, 0x50, 0x2a, 0x65, 0x7f, 0x67, 0x41, 0xf6, 0x81,
+	0xff, 0x82, 0x76, 0xc9, 0x19, 0x28, 0x33, 0xf9, 0x34, 0x0c, 0x77, 0x22, 0x0e, 0xa3, 0x94, 0x92,
+	0xc4, 0xc9, 0x2d, 0x67, 0xa1, 0x12, 0xd0, 0x67, 0x01, 0x0d, 0x77, 0x71, 0x4f, 0x4a, 0xec, 0x29,
+	0x23, 0x52, 0x6e, 0xaa, 0x41, 0xbe, 0x15, 0x50, 0x37, 0xa2, 0xed, 0x5a, 0x9a, 0x91, 0xd3, 0x8e,
+	0x02, 0xc9, 0x71, 0xc8, 0xd1, 0x57, 0x3d, 0x2f, 0x38, 0xa8, 0x65, 0x04, 0x01, 0x21, 0xfb, 0x6f,
+	0x0b, 0xf2, 0xa8, 0xd7, 0x88, 0x85, 0x04, 0x32, 0xd1, 0x41, 0x8f, 0xa2, 0x24, 0xb1, 0x26, 0x1f,
+	0x41, 0x61, 0x8f, 0x46, 0x6e, 0xdb, 0x8d, 0x5c, 0xc6, 0x89, 0x3b, 0xf2, 0xa4, 0xe1, 0xc8, 0xe5,
+	0x7b, 0x48, 0xdd, 0xe8, 0x46, 0xc1, 0x81, 0x13, 0x6f, 0xe6, 0x0a, 0x84, 0x2d, 0xbf, 0x47, 0xc3,
+	0x5a, 0x96, 0x1d, 0x2b, 0x3a, 0x08, 0x71, 0xbc, 0x17, 0x86, 0x7d, 0x1a, 0xd4, 0x72, 0x42, 0x0c,
+	0x42, 0x62, 0x3f, 0x65, 0xda, 0x47, 0xb5, 0xbc, 0xc4, 0x4b, 0xa8, 0xbe, 0x0a, 0x15, 0x43, 0x04,
+	0x99, 0x83, 0xf4, 0x0b, 0x7a, 0x80, 0x6a, 0xf3, 0x25, 0xa9, 0x42, 0x76, 0xdf, 0xed, 0xf4, 0x95,
+	0xe2, 0x12, 0xb8, 0x96, 0xba, 0x6a, 0xd9, 0x5b, 0x50, 0x60, 0xde, 0xf7, 0xfb, 0x41, 0x8b, 0x72,
+	0xeb, 0xba, 0xee, 0x1e, 0xc5, 0x83, 0x62, 0x9d, 0x68, 0x71, 0x1d, 0x0a, 0xb4, 0xdb, 0xee, 0xf9,
+	0x5e, 0x37, 0x12, 0x4e, 0x2d, 0x3a, 0x31, 0x6c, 0xff, 0x9c, 0x82, 0x63, 0x9b, 0xb4, 0x4b, 0x03,
+	0xe6, 0xe3, 0x71, 0x71, 0x72, 0x53, 0xf3, 0x58, 0x5a, 0x78, 0xec, 0xac, 0xf4, 0xd8, 0xd0, 0xc1,
+	0x29, 0x3c, 0x97, 0x19, 0xf6, 0x1c, 0x7a, 0x28, 0xab, 0x7b, 0x28, 0x36, 0x22, 0x67, 0x1a, 0xc1,
+	0xd2, 0x6c, 0xdf, 0x6b, 0x33, 0x3f, 0x4b, 0x7f, 0xc6, 0xb0, 0x1e, 0xc8, 0x85, 0x49, 0x81, 0x7c,
+	0x34, 0xd7, 0xaf, 0xc2, 0xdc, 0xc0, 0x60, 0xcc, 0x4a, 0x26, 0x19, 0xd3, 0xce, 0x4c, 0x6b, 0x95,
+	0x28, 0x8a, 0x6a, 0x1f, 0x40, 0x79, 0x33, 0x70, 0x07, 0xb9, 0xc8, 0xc4, 0x08, 0x27, 0xa0, 0x68,
+	0x09, 0x90, 0x4b, 0x50, 0x08, 0xf0, 0x76, 0x31, 0x25, 0x67, 0x25, 0x3f, 0x75, 0xe7, 0x4e, 0x4c,
+	0xd7, 0x8d, 0x4e, 0x4f, 0xcc, 0xde, 0x63, 0x50, 0x41, 0xd1, 0x98, 0xb5, 0xdf, 0x42, 0xc5, 0xa1,
+	0xfb, 0x2c, 0xdd, 0xfe, 0x07, 0x65, 0xe6, 0x60, 0x56, 0xc9, 0x46, 0x6d, 0xb6, 0x61, 0xf6, 0x76,
+	0x37, 0xec, 0xd1, 0x96, 0xee, 0x1b, 0xbd, 0x88, 0x48, 0x60, 0xfa, 0x6a, 0x75, 0x0d, 0x8e, 0xc5,
+	0x0c, 0x0f, 0x7b, 0x4d, 0xaf, 0x53, 0x50, 0x16, 0x85, 0x68, 0x5c, 0x2e, 0x0c, 0x42, 0x36, 0x65,
+	0x84, 0xec, 0x48, 0x71, 0x4b, 0x27, 0x14, 0x37, 0x56, 0x24, 0x05, 0x71, 0xc7, 0x28, 0x64, 0x25,
+	0x81, 0xdb, 0x10, 0x28, 0xdd, 0xca, 0xec, 0x24, 0x2b, 0xc9, 0x49, 0x28, 0xb6, 0x3a, 0x1e, 0xed,
+	0x46, 0x3b, 0x4c, 0x3f, 0x99, 0x28, 0x05, 0x89, 0xb8, 0xdd, 0xd6, 0x12, 0x2e, 0x6f, 0x24, 0x9c,
+	0x0d, 0xe5, 0x7d, 0x1a, 0x78, 0xcf, 0xbc, 0x96, 0xcb, 0xb9, 0x88, 0x6c, 0x61, 0x4a, 0xea, 0x38,
+	0x6e, 0x09, 0x32, 0x46, 0x43, 0x8b, 0x72, 0x93, 0x44, 0xde, 0x17, 0x38, 0xbb, 0x01, 0x15, 0x74,
+	0x13, 0x7a, 0xf8, 0x8c, 0x7e, 0x67, 0xa5, 0x46, 0x49, 0x6a, 0x2d, 0xf7, 0x48, 0x8a, 0xfd, 0x93,
+	0x05, 0x19, 0xa7, 0xdf, 0xa1, 0x23, 0x3e, 0x8d, 0xc3, 0x2f, 0x35, 0x2e, 0xfc, 0xd2, 0x6f, 0x08,
+	0xbf, 0x73, 0x90, 0x93, 0x9d, 0x46, 0xb8, 0x74, 0xb6, 0x51, 0x8e, 0xaf, 0x97, 0xe1, 0x1c, 0xa4,
+	0xc9, 0x12, 0xe2, 0xf9, 0x81, 0x17, 0x1d, 0x08, 0xe7, 0x66, 0x9d, 0x18, 0xb6, 0x99, 0xdf, 0xd1,
+	0xc5, 0xe4, 0x2d, 0x28, 0xf2, 0x52, 0x1a, 0xf6, 0xdc, 0x96, 0xca, 0x88, 0x01, 0xc2, 0xfe, 0x0a,
+	0x2a, 0xb7, 0x44, 0x47, 0x52, 0x11, 0xf2, 0x36, 0x64, 0x02, 0x66, 0x15, 0x1a, 0x0e, 0xa8, 0x23,
+	0xc3, 0x38, 0x02, 0x3f, 0x7d, 0xdc, 0xb2, 0xd4, 0x50, 0x9c, 0x31, 0x35, 0x3e, 0x87, 0x8a, 0xec,
+	0xbb, 0x47, 0xee, 0xe0, 0x8c, 0xb7, 0xe2, 0x84, 0xbc, 0x3f, 0x84, 0x12, 0x9f, 0x33, 0x12, 0xe6,
+	0x93, 0xc9, 0x9c, 0xde, 0x87, 0xb2, 0x3c, 0x87, 0x17, 0x7f, 0x1a, 0xb2, 0xdc, 0x4c, 0x35, 0x94,
+	0xe8, 0xf6, 0x4b, 0x82, 0xfd, 0xbd, 0x05, 0x0b, 0xb7, 0x76, 0xdd, 0xee, 0x73, 0x2a, 0x83, 0x67,
+	0x9c, 0x31, 0xa7, 0x00, 0xfc, 0x4e, 0x7b, 0xc7, 0x48, 0xaf, 0x22, 0xc3, 0xc8, 0x53, 0x9c, 0xdc,
+	0xa5, 0x2f, 0x15, 0x39, 0x8d, 0xf7, 0x42, 0x5f, 0x22, 0x59, 0x33, 0x20, 0x33, 0xd1, 0x80, 0xe3,
+	0x50, 0x35, 0xb5, 0x41, 0x87, 0xdc, 0x03, 0xe2, 0x50, 0xb7, 0xfd, 0x65, 0xe0, 0x3f, 0xf3, 0x3a,
+	0x47, 0xf7, 0xf8, 0x0f, 0xcc, 0x6a, 0x83, 0x1f, 0xfa, 0xeb, 0x13, 0xc8, 0xf7, 0x24, 0x0a, 0x3d,
+	0x76, 0x5e, 0x45, 0xf5, 0xc8, 0xde, 0x65, 0x84, 0x65, 0x3b, 0x55, 0xc7, 0xea, 0xd7, 0xa0, 0xac,
+	0x13, 0x0e, 0xd5, 0xc3, 0xfe, 0xb0, 0xa0, 0xfa, 0xb0, 0xc7, 0xfa, 0x1f, 0x7d, 0x83, 0x9d, 0xcd,
+	0x81, 0x9a, 0x29, 0xa1, 0xe6, 0x05, 0xa9, 0x66, 0xd2, 0xe1, 0x64, 0x3d, 0xa7, 0xee, 0x09, 0x47,
+	0x32, 0xe8, 0x47, 0x0b, 0x16, 0x87, 0x74, 0x42, 0x47, 0x7f, 0x3a, 0xec, 0xe8, 0x8b, 0x89, 0x16,
+	0xfc, 0x67, 0xae, 0xee, 0x43, 0x56, 0xb4, 0x5d, 0x1e, 0xb8, 0xd8, 0x5e, 0x76, 0x62, 0x17, 0x17,
+	0x11, 0xc3, 0x6a, 0xb5, 0x51, 0xc8, 0x53, 0x63, 0x0b, 0x79, 0xda, 0x28, 0xe4, 0xda, 0x98, 0x9c,
+	0x31, 0xc6, 0x64, 0x3b, 0x00, 0x22, 0xab, 0x88, 0x31, 0x6e, 0x18, 0x42, 0xac, 0xb1, 0x42, 0x52,
+	0x86, 0x90, 0xa9, 0x9b, 0xfa, 0x55, 0x96, 0xe0, 0xba, 0xcc, 0x41, 0x4f, 0x78, 0xce, 0x11, 0x66,
+	0x4f, 0x90, 0x7b, 0x24, 0xc5, 0xbe, 0x0e, 0xf3, 0xbc, 0x9a, 0x08, 0xdc, 0xe1, 0xdf, 0x4a, 0x1f,
+	0x03, 0xd1, 0x4f, 0xa3, 0xd8, 0xb3, 0x90, 0x13, 0xcc, 0x55, 0x49, 0x32, 0xe4, 0x22, 0xc9, 0x7e,
+	0xc2, 0xb3, 0x9d, 0xcf, 0x21, 0xd3, 0xbb, 0x69, 0xea, 0xd4, 0x5f, 0xe4, 0x99, 0xaf, 0xf1, 0xc6,
+	0x02, 0xf3, 0xbb, 0x05, 0xb9, 0x75, 0x7f, 0xcf, 0xf5, 0xba, 0x89, 0x93, 0xbb, 0xd1, 0x76, 0x52,
+	0x43, 0x6d, 0x87, 0x53, 0x5b, 0xbb, 0x6e, 0xa7, 0x43, 0x59, 0xe1, 0x52, 0xc5, 0x2f, 0x46, 0xf0,
+	0xce, 0x26, 0x7b, 0x38, 0xc6, 0x43, 0xc1, 0x89, 0x61, 0x6e, 0x13, 0x53, 0xd3, 0xdf, 0xf9, 0x9a,
+	0xcd, 0xfb, 0xa2, 0xed, 0x31, 0x22, 0x47, 0xdc, 0x61, 0xb0, 0x1e, 0x47, 0x39, 0xf3, 0xb9, 0xf5,
+	0x0e, 0x94, 0x14, 0x8b, 0x1d, 0x57, 0x3e, 0x61, 0xd2, 0x0e, 0x28, 0x54, 0x33, 0x12, 0x81, 0xd6,
+	0x71, 0xbd, 0x3d, 0x69, 0x92, 0xf2, 0x20, 0x8b, 0xa5, 0xb6, 0x40, 0xa0, 0x6d, 0x08, 0x99, 0x5a,
+	0xa4, 0x86, 0xb4, 0x98, 0x3a, 0xd0, 0xee, 0xb3, 0x40, 0xd3, 0x65, 0xe2, 0x8d, 0x9f, 0x33, 0x84,
+	0x96, 0x54, 0xfb, 0xc7, 0x5d, 0x4a, 0x05, 0xa6, 0x5a, 0x40, 0x5b, 0x7e, 0xa0, 0xb2, 0x0c, 0x21,
+	0xfb, 0x11, 0x2c, 0x3c, 0xe2, 0x66, 0x1d, 0x4c, 0x67, 0xc9, 0xd4, 0x61, 0x70, 0x1d, 0xaa, 0x26,
+	0xdf, 0xc3, 0x68, 0x6b, 0xaf, 0xc9, 0xd8, 0x96, 0xd8, 0xc3, 0xa7, 0xc6, 0x9a, 0xfc, 0x0c, 0x11,
+	0x1f, 0x47, 0xd9, 0xe7, 0x21, 0x2f, 0xf9, 0xab, 0xe4, 0x30, 0x85, 0x2b, 0xa2, 0xfd, 0x18, 0xaa,
+	0x0e, 0x9b, 0x17, 0xdc, 0x90, 0xfe, 0xcb, 0x4e, 0x59, 0x82, 0xc5, 0x21, 0xc6, 0x98, 0x1d, 0xcb,
+	0x5c, 0x62, 0xe8, 0x77, 0xf6, 0xa7, 0x93, 0x68, 0x3b, 0x9c, 0x91, 0xb1, 0x1f, 0x4d, 0x9c, 0x38,
+	0xbe, 0x4d, 0x8c, 0x43, 0xfb, 0x01, 0x7b, 0x1e, 0xba, 0x51, 0xe0, 0xbd, 0x3a, 0xac, 0xbb, 0x79,
+	0x02, 0xe2, 0xc7, 0x21, 0x55, 0x44, 0x63, 0xd8, 0x7e, 0x6d, 0x41, 0x49, 0xb2, 0x95, 0x4d, 0x84,
+	0xed, 0x65, 0xaf, 0xd6, 0x6e, 0xc4, 0xc7, 0x50, 0xac, 0x31, 0x0a, 0x1e, 0x5b, 0x8a, 0x59, 0x9e,
+	0x22, 0x3f, 0x4c, 0x7e, 0x05, 0x1a, 0x8f, 0xfb, 0x8c, 0xf9, 0xb8, 0xe7, 0xa7, 0x44, 0xb9, 0x63,
+	0xd9, 0x2d, 0x13, 0x5f, 0x81, 0xbc, 0x00, 0x89, 0xa1, 0x15, 0x5f, 0xd8, 0x7c, 0xcd, 0x42, 0x66,
+	0x56, 0x59, 0x8f, 0xae, 0x7c, 0x0f, 0xf2, 0x4c, 0xaf, 0xc0, 0x8b, 0xa7, 0xbb, 0x79, 0x69, 0xbe,
+	0x66, 0x8d, 0xa3, 0x76, 0xd8, 0x77, 0x61, 0x6e, 0xa3, 0x1b, 0xf8, 0x9d, 0xce, 0xbd, 0xcf, 0x9a,
+	0x47, 0x9e, 0x9e, 0xd6, 0x60, 0x5e, 0x63, 0x86, 0xea, 0x0c, 0xde, 0x5e, 0x96, 0xf1, 0xf6, 0x62,
+	0x5d, 0xb9, 0x1f, 0x74, 0x30, 0xab, 0xf9, 0xf2, 0xd2, 0x32, 0xe4, 0xe4, 0xec, 0x4f, 0x4a, 0x90,
+	0x7f, 0xb8, 0x75, 0x77, 0x6b, 0xfb, 0xf1, 0xd6, 0xdc, 0x0c, 0x07, 0x36, 0x9d, 0xe6, 0xd6, 0x83,
+	0x8d, 0xf5, 0x39, 0x8b, 0x00, 0xab, 0xc6, 0x1b, 0x5b, 0xb7, 0xd9, 0x3a, 0xd5, 0xf8, 0x95, 0x3d,
+	0x4d, 0x9a, 0x4c, 0x11, 0xb2, 0x0a, 0x05, 0xf5, 0xc6, 0x27, 0x8b, 0x89, 0x1f, 0x39, 0xea, 0xc7,
+	0x87, 0xd1, 0x18, 0xc0, 0x33, 0xe4, 0x2a, 0xe4, 0xf1, 0xe1, 0x49, 0xaa, 0x72, 0x93, 0xf9, 0xb0,
+	0xad, 0x2f, 0x0e, 0x61, 0xe3, 0x93, 0x0d, 0xf5, 0x19, 0x8d, 0xe8, 0xef, 0x26, 0x3c, 0xb5, 0x60,
+	0xe0, 0xd4, 0x99, 0xc6, 0x2f, 0x69, 0x28, 0xa8, 0xaf, 0x84, 0xe4, 0x26, 0x64, 0x78, 0xba, 0x93,
+	0x13, 0x72, 0x6f, 0xc2, 0x17, 0xc8, 0x7a, 0x3d, 0x89, 0x14, 0x6b, 0x70, 0x8b, 0x79, 0x43, 0x3c,
+	0x10, 0x08, 0xee, 0x4b, 0xfa, 0x82, 0x58, 0x3f, 0x99, 0x48, 0x8b, 0x99, 0x6c, 0x42, 0x59, 0x1f,
+	0xad, 0x95, 0x36, 0x09, 0xc3, 0xbf, 0xd2, 0x26, 0x71, 0x12, 0x9f, 0x21, 0xeb, 0x50, 0xd2, 0xe6,
+	0x61, 0x52, 0x4b, 0x18, 0x91, 0x25, 0x9b, 0x13, 0x63, 0x87, 0x67, 0xc6, 0xe5, 0x0e, 0x54, 0x8c,
+	0x61, 0x4f, 0x99, 0x96, 0x34, 0xc3, 0x2a, 0xd3, 0x12, 0xa7, 0x43, 0xc6, 0xeb, 0x06, 0x14, 0xe3,
+	0x80, 0x24, 0x18, 0x02, 0xc3, 0xe1, 0x5e, 0x5f, 0x1a, 0xc1, 0xc7, 0xb7, 0xf5, 0x1b, 0x6b, 0xfe,
+	0x72, 0x4e, 0x61, 0x77, 0x95, 0x93, 0xd3, 0x92, 0xb2, 0x6b, 0x74, 0x5e, 0x53, 0x76, 0x25, 0x4c,
+	0x55, 0x4c, 0x97, 0x55, 0xbc, 0xec, 0xa5, 0xc1, 0x8d, 0x1a, 0x03, 0x54, 0xbd, 0x36, 0x4a, 0x88,
+	0x0f, 0x33, 0xe9, 0x72, 0x38, 0x19, 0x78, 0x75, 0x78, 0x0c, 0x1a, 0x78, 0x75, 0x74, 0x88, 0x99,
+	0x69, 0xfc, 0x65, 0x41, 0x96, 0x3f, 0xef, 0x42, 0x72, 0x25, 0x36, 0x64, 0x41, 0x57, 0x57, 0x71,
+	0xa9, 0x9a, 0xc8, 0x58, 0x83, 0x2b, 0x71, 0xa8, 0x2d, 0xe8, 0xe1, 0x34, 0x74, 0x6c, 0xe8, 0xb9,
+	0x3a, 0x43, 0x56, 0xd0, 0xea, 0xf9, 0x81, 0x71, 0xea, 0x08, 0xd1, 0x51, 0xba, 0x1c, 0x59, 0xa8,
+	0x94, 0x1c, 0xa3, 0xb6, 0x2b, 0x39, 0x66, 0xc9, 0x63, 0xf6, 0xfd, 0x99, 0x82, 0x3c, 0xb6, 0x4d,
+	0x76, 0xeb, 0x59, 0x31, 0x6f, 0xc4, 0x37, 0x35, 0x32, 0xf0, 0xc4, 0x37, 0x35, 0x3a, 0x96, 0x30,
+	0x15, 0x9a, 0x90, 0x93, 0x23, 0x80, 0x4a, 0x85, 0x84, 0x41, 0x43, 0xa5, 0x42, 0xd2, 0xac, 0xc0,
+	0x58, 0xac, 0xa1, 0xd9, 0xda, 0x9d, 0x9a, 0x33, 0x41, 0xfd, 0x44, 0x02, 0x45, 0xcb, 0xa4, 0x3c,
+	0xf6, 0x5b, 0x15, 0xfd, 0x49, 0x7d, 0x5d, 0x45, 0x7f, 0x72, 0x6b, 0x46, 0x2e, 0xa2, 0xd9, 0x0e,
+	0xb8, 0x8c, 0xf6, 0xea, 0x01, 0x97, 0x84, 0xbe, 0x6c, 0xcf, 0x7c, 0x7a, 0xf9, 0xc9, 0x07, 0xcf,
+	0xbd, 0x68, 0xb7, 0xff, 0x74, 0xb9, 0xe5, 0xef, 0xad, 0xec, 0x79, 0xad, 0xc0, 0xc7, 0xdf, 0xfd,
+	0xcb, 0x2b, 0xa3, 0xff, 0xad, 0xac, 0xf2, 0xe5, 0xd3, 0x9c, 0x58, 0x5f, 0xfe, 0x07, 0x41, 0x6d,
+	0x09, 0x7c, 0x7d, 0x19, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AuthClient is the client API for Auth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthClient interface {
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
+	Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+}
+
+type authClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuthClient(cc *grpc.ClientConn) AuthClient {
+	return &authClient{cc}
+}
+
+func (c *authClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, "/auth.Auth/Generate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
+	out := new(InspectResponse)
+	err := c.cc.Invoke(ctx, "/auth.Auth/Inspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/auth.Auth/Token", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthServer is the server API for Auth service.
+type AuthServer interface {
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
+	Token(context.Context, *TokenRequest) (*TokenResponse, error)
+}
+
+// UnimplementedAuthServer can be embedded to have forward compatible implementations.
+type UnimplementedAuthServer struct {
+}
+
+func (*UnimplementedAuthServer) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (*UnimplementedAuthServer) Inspect(ctx context.Context, req *InspectRequest) (*InspectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inspect not implemented")
+}
+func (*UnimplementedAuthServer) Token(ctx context.Context, req *TokenRequest) (*TokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Token not implemented")
+}
+
+func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
+	s.RegisterService(&_Auth_serviceDesc, srv)
+}
+
+func _Auth_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Auth/Generate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Auth/Inspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Inspect(ctx, req.(*InspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Token_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Token(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Auth/Token",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Token(ctx, req.(*TokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Auth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Auth",
+	HandlerType: (*AuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _Auth_Generate_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _Auth_Inspect_Handler,
+		},
+		{
+			MethodName: "Token",
+			Handler:    _Auth_Token_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service/auth/proto/auth.proto",
+}
+
+// AccountsClient is the client API for Accounts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AccountsClient interface {
+	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
+	ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error)
+	ReadProfile(ctx context.Context, in *ReadProfileRequest, opts ...grpc.CallOption) (*ReadProfileResponse, error)
+	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*UpdateProfileResponse, error)
+	EnrollMFA(ctx context.Context, in *EnrollMFARequest, opts ...grpc.CallOption) (*EnrollMFAResponse, error)
+}
+
+type accountsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAccountsClient(cc *grpc.ClientConn) AccountsClient {
+	return &accountsClient{cc}
+}
+
+func (c *accountsClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
+	out := new(DeleteAccountResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error) {
+	out := new(ChangeSecretResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/ChangeSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ReadProfile(ctx context.Context, in *ReadProfileRequest, opts ...grpc.CallOption) (*ReadProfileResponse, error) {
+	out := new(ReadProfileResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/ReadProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*UpdateProfileResponse, error) {
+	out := new(UpdateProfileResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/UpdateProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) EnrollMFA(ctx context.Context, in *EnrollMFARequest, opts ...grpc.CallOption) (*EnrollMFAResponse, error) {
+	out := new(EnrollMFAResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/EnrollMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountsServer is the server API for Accounts service.
+type AccountsServer interface {
+	List(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	Delete(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
+	ChangeSecret(context.Context, *ChangeSecretRequest) (*ChangeSecretResponse, error)
+	ReadProfile(context.Context, *ReadProfileRequest) (*ReadProfileResponse, error)
+	UpdateProfile(context.Context, *UpdateProfileRequest) (*UpdateProfileResponse, error)
+	EnrollMFA(context.Context, *EnrollMFARequest) (*EnrollMFAResponse, error)
+}
+
+// UnimplementedAccountsServer can be embedded to have forward compatible implementations.
+type UnimplementedAccountsServer struct {
+}
+
+func (*UnimplementedAccountsServer) List(ctx context.Context, req *ListAccountsRequest) (*ListAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
 func (*UnimplementedAccountsServer) Delete(ctx context.Context, req *DeleteAccountRequest) (*DeleteAccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
-func (*UnimplementedAccountsServer) ChangeSecret(ctx context.Context, req *ChangeSecretRequest) (*ChangeSecretResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ChangeSecret not implemented")
+func (*UnimplementedAccountsServer) ChangeSecret(ctx context.Context, req *ChangeSecretRequest) (*ChangeSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeSecret not implemented")
+}
+func (*UnimplementedAccountsServer) ReadProfile(ctx context.Context, req *ReadProfileRequest) (*ReadProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadProfile not implemented")
+}
+func (*UnimplementedAccountsServer) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*UpdateProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (*UnimplementedAccountsServer) EnrollMFA(ctx context.Context, req *EnrollMFARequest) (*EnrollMFAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollMFA not implemented")
+}
+
+func RegisterAccountsServer(s *grpc.Server, srv AccountsServer) {
+	s.RegisterService(&_Accounts_serviceDesc, srv)
+}
+
+func _Accounts_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).List(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).Delete(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ChangeSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ChangeSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/ChangeSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ChangeSecret(ctx, req.(*ChangeSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ReadProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ReadProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/ReadProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ReadProfile(ctx, req.(*ReadProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/UpdateProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).UpdateProfile(ctx, req.(*UpdateProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_EnrollMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollMFARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).EnrollMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/EnrollMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).EnrollMFA(ctx, req.(*EnrollMFARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Accounts_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Accounts",
+	HandlerType: (*AccountsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Accounts_List_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Accounts_Delete_Handler,
+		},
+		{
+			MethodName: "ChangeSecret",
+			Handler:    _Accounts_ChangeSecret_Handler,
+		},
+		{
+			MethodName: "ReadProfile",
+			Handler:    _Accounts_ReadProfile_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _Accounts_UpdateProfile_Handler,
+		},
+		{
+			MethodName: "EnrollMFA",
+			Handler:    _Accounts_EnrollMFA_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service/auth/proto/auth.proto",
 }
 
-func RegisterAccountsServer(s *grpc.Server, srv AccountsServer) {
-	s.RegisterService(&_Accounts_serviceDesc, srv)
+// GrantsClient is the client API for Grants service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrantsClient interface {
+	Create(ctx context.Context, in *CreateGrantRequest, opts ...grpc.CallOption) (*CreateGrantResponse, error)
+	List(ctx context.Context, in *ListGrantsRequest, opts ...grpc.CallOption) (*ListGrantsResponse, error)
+	Revoke(ctx context.Context, in *RevokeGrantRequest, opts ...grpc.CallOption) (*RevokeGrantResponse, error)
 }
 
-func _Accounts_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListAccountsRequest)
+type grantsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrantsClient(cc *grpc.ClientConn) GrantsClient {
+	return &grantsClient{cc}
+}
+
+func (c *grantsClient) Create(ctx context.Context, in *CreateGrantRequest, opts ...grpc.CallOption) (*CreateGrantResponse, error) {
+	out := new(CreateGrantResponse)
+	err := c.cc.Invoke(ctx, "/auth.Grants/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grantsClient) List(ctx context.Context, in *ListGrantsRequest, opts ...grpc.CallOption) (*ListGrantsResponse, error) {
+	out := new(ListGrantsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Grants/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grantsClient) Revoke(ctx context.Context, in *RevokeGrantRequest, opts ...grpc.CallOption) (*RevokeGrantResponse, error) {
+	out := new(RevokeGrantResponse)
+	err := c.cc.Invoke(ctx, "/auth.Grants/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrantsServer is the server API for Grants service.
+type GrantsServer interface {
+	Create(context.Context, *CreateGrantRequest) (*CreateGrantResponse, error)
+	List(context.Context, *ListGrantsRequest) (*ListGrantsResponse, error)
+	Revoke(context.Context, *RevokeGrantRequest) (*RevokeGrantResponse, error)
+}
+
+// UnimplementedGrantsServer can be embedded to have forward compatible implementations.
+type UnimplementedGrantsServer struct {
+}
+
+func (*UnimplementedGrantsServer) Create(ctx context.Context, req *CreateGrantRequest) (*CreateGrantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedGrantsServer) List(ctx context.Context, req *ListGrantsRequest) (*ListGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedGrantsServer) Revoke(ctx context.Context, req *RevokeGrantRequest) (*RevokeGrantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+
+func RegisterGrantsServer(s *grpc.Server, srv GrantsServer) {
+	s.RegisterService(&_Grants_serviceDesc, srv)
+}
+
+func _Grants_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGrantRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountsServer).List(ctx, in)
+		return srv.(GrantsServer).Create(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Accounts/List",
+		FullMethod: "/auth.Grants/Create",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountsServer).List(ctx, req.(*ListAccountsRequest))
+		return srv.(GrantsServer).Create(ctx, req.(*CreateGrantRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Accounts_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAccountRequest)
+func _Grants_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGrantsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountsServer).Delete(ctx, in)
+		return srv.(GrantsServer).List(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Accounts/Delete",
+		FullMethod: "/auth.Grants/List",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountsServer).Delete(ctx, req.(*DeleteAccountRequest))
+		return srv.(GrantsServer).List(ctx, req.(*ListGrantsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Accounts_ChangeSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChangeSecretRequest)
+func _Grants_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeGrantRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountsServer).ChangeSecret(ctx, in)
+		return srv.(GrantsServer).Revoke(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Accounts/ChangeSecret",
+		FullMethod: "/auth.Grants/Revoke",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountsServer).ChangeSecret(ctx, req.(*ChangeSecretRequest))
+		return srv.(GrantsServer).Revoke(ctx, req.(*RevokeGrantRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Accounts_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "auth.Accounts",
-	HandlerType: (*AccountsServer)(nil),
+var _Grants_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Grants",
+	HandlerType: (*GrantsServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "List",
-			Handler:    _Accounts_List_Handler,
+			MethodName: "Create",
+			Handler:    _Grants_Create_Handler,
 		},
 		{
-			MethodName: "Delete",
-			Handler:    _Accounts_Delete_Handler,
+			MethodName: "List",
+			Handler:    _Grants_List_Handler,
 		},
 		{
-			MethodName: "ChangeSecret",
-			Handler:    _Accounts_ChangeSecret_Handler,
+			MethodName: "Revoke",
+			Handler:    _Grants_Revoke_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -1745,6 +3407,7 @@ type RulesClient interface {
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Matrix(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*MatrixResponse, error)
 }
 
 type rulesClient struct {
@@ -1782,11 +3445,21 @@ func (c *rulesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *rulesClient) Matrix(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*MatrixResponse, error) {
+	out := new(MatrixResponse)
+	err := c.cc.Invoke(ctx, "/auth.Rules/Matrix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RulesServer is the server API for Rules service.
 type RulesServer interface {
 	Create(context.Context, *CreateRequest) (*CreateResponse, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
+	Matrix(context.Context, *MatrixRequest) (*MatrixResponse, error)
 }
 
 // UnimplementedRulesServer can be embedded to have forward compatible implementations.
@@ -1802,6 +3475,9 @@ func (*UnimplementedRulesServer) Delete(ctx context.Context, req *DeleteRequest)
 func (*UnimplementedRulesServer) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (*UnimplementedRulesServer) Matrix(ctx context.Context, req *MatrixRequest) (*MatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Matrix not implemented")
+}
 
 func RegisterRulesServer(s *grpc.Server, srv RulesServer) {
 	s.RegisterService(&_Rules_serviceDesc, srv)
@@ -1861,6 +3537,24 @@ func _Rules_List_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Rules_Matrix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).Matrix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Rules/Matrix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).Matrix(ctx, req.(*MatrixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Rules_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "auth.Rules",
 	HandlerType: (*RulesServer)(nil),
@@ -1877,6 +3571,226 @@ var _Rules_serviceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _Rules_List_Handler,
 		},
+		{
+			MethodName: "Matrix",
+			Handler:    _Rules_Matrix_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service/auth/proto/auth.proto",
+}
+
+// DomainsClient is the client API for Domains service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DomainsClient interface {
+	Claim(ctx context.Context, in *ClaimDomainRequest, opts ...grpc.CallOption) (*ClaimDomainResponse, error)
+	Verify(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error)
+	List(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error)
+	Release(ctx context.Context, in *ReleaseDomainRequest, opts ...grpc.CallOption) (*ReleaseDomainResponse, error)
+	Resolve(ctx context.Context, in *ResolveDomainRequest, opts ...grpc.CallOption) (*ResolveDomainResponse, error)
+}
+
+type domainsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDomainsClient(cc *grpc.ClientConn) DomainsClient {
+	return &domainsClient{cc}
+}
+
+func (c *domainsClient) Claim(ctx context.Context, in *ClaimDomainRequest, opts ...grpc.CallOption) (*ClaimDomainResponse, error) {
+	out := new(ClaimDomainResponse)
+	err := c.cc.Invoke(ctx, "/auth.Domains/Claim", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsClient) Verify(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error) {
+	out := new(VerifyDomainResponse)
+	err := c.cc.Invoke(ctx, "/auth.Domains/Verify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsClient) List(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error) {
+	out := new(ListDomainsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Domains/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsClient) Release(ctx context.Context, in *ReleaseDomainRequest, opts ...grpc.CallOption) (*ReleaseDomainResponse, error) {
+	out := new(ReleaseDomainResponse)
+	err := c.cc.Invoke(ctx, "/auth.Domains/Release", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsClient) Resolve(ctx context.Context, in *ResolveDomainRequest, opts ...grpc.CallOption) (*ResolveDomainResponse, error) {
+	out := new(ResolveDomainResponse)
+	err := c.cc.Invoke(ctx, "/auth.Domains/Resolve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DomainsServer is the server API for Domains service.
+type DomainsServer interface {
+	Claim(context.Context, *ClaimDomainRequest) (*ClaimDomainResponse, error)
+	Verify(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error)
+	List(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error)
+	Release(context.Context, *ReleaseDomainRequest) (*ReleaseDomainResponse, error)
+	Resolve(context.Context, *ResolveDomainRequest) (*ResolveDomainResponse, error)
+}
+
+// UnimplementedDomainsServer can be embedded to have forward compatible implementations.
+type UnimplementedDomainsServer struct {
+}
+
+func (*UnimplementedDomainsServer) Claim(ctx context.Context, req *ClaimDomainRequest) (*ClaimDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Claim not implemented")
+}
+func (*UnimplementedDomainsServer) Verify(ctx context.Context, req *VerifyDomainRequest) (*VerifyDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (*UnimplementedDomainsServer) List(ctx context.Context, req *ListDomainsRequest) (*ListDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedDomainsServer) Release(ctx context.Context, req *ReleaseDomainRequest) (*ReleaseDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+func (*UnimplementedDomainsServer) Resolve(ctx context.Context, req *ResolveDomainRequest) (*ResolveDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+
+func RegisterDomainsServer(s *grpc.Server, srv DomainsServer) {
+	s.RegisterService(&_Domains_serviceDesc, srv)
+}
+
+func _Domains_Claim_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainsServer).Claim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Domains/Claim",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainsServer).Claim(ctx, req.(*ClaimDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Domains_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainsServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Domains/Verify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainsServer).Verify(ctx, req.(*VerifyDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Domains_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Domains/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainsServer).List(ctx, req.(*ListDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Domains_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainsServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Domains/Release",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainsServer).Release(ctx, req.(*ReleaseDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Domains_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainsServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Domains/Resolve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainsServer).Resolve(ctx, req.(*ResolveDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Domains_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Domains",
+	HandlerType: (*DomainsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Claim",
+			Handler:    _Domains_Claim_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _Domains_Verify_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Domains_List_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _Domains_Release_Handler,
+		},
+		{
+			MethodName: "Resolve",
+			Handler:    _Domains_Resolve_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service/auth/proto/auth.proto",
@@ -138,6 +138,9 @@ type AccountsService interface {
 	List(ctx context.Context, in *ListAccountsRequest, opts ...client.CallOption) (*ListAccountsResponse, error)
 	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...client.CallOption) (*DeleteAccountResponse, error)
 	ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...client.CallOption) (*ChangeSecretResponse, error)
+	ReadProfile(ctx context.Context, in *ReadProfileRequest, opts ...client.CallOption) (*ReadProfileResponse, error)
+	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...client.CallOption) (*UpdateProfileResponse, error)
+	EnrollMFA(ctx context.Context, in *EnrollMFARequest, opts ...client.CallOption) (*EnrollMFAResponse, error)
 }
 
 type accountsService struct {
@@ -182,12 +185,45 @@ func (c *accountsService) ChangeSecret(ctx context.Context, in *ChangeSecretRequ
 	return out, nil
 }
 
+func (c *accountsService) ReadProfile(ctx context.Context, in *ReadProfileRequest, opts ...client.CallOption) (*ReadProfileResponse, error) {
+	req := c.c.NewRequest(c.name, "Accounts.ReadProfile", in)
+	out := new(ReadProfileResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsService) UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...client.CallOption) (*UpdateProfileResponse, error) {
+	req := c.c.NewRequest(c.name, "Accounts.UpdateProfile", in)
+	out := new(UpdateProfileResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsService) EnrollMFA(ctx context.Context, in *EnrollMFARequest, opts ...client.CallOption) (*EnrollMFAResponse, error) {
+	req := c.c.NewRequest(c.name, "Accounts.EnrollMFA", in)
+	out := new(EnrollMFAResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Accounts service
 
 type AccountsHandler interface {
 	List(context.Context, *ListAccountsRequest, *ListAccountsResponse) error
 	Delete(context.Context, *DeleteAccountRequest, *DeleteAccountResponse) error
 	ChangeSecret(context.Context, *ChangeSecretRequest, *ChangeSecretResponse) error
+	ReadProfile(context.Context, *ReadProfileRequest, *ReadProfileResponse) error
+	UpdateProfile(context.Context, *UpdateProfileRequest, *UpdateProfileResponse) error
+	EnrollMFA(context.Context, *EnrollMFARequest, *EnrollMFAResponse) error
 }
 
 func RegisterAccountsHandler(s server.Server, hdlr AccountsHandler, opts ...server.HandlerOption) error {
@@ -195,6 +231,9 @@ func RegisterAccountsHandler(s server.Server, hdlr AccountsHandler, opts ...serv
 		List(ctx context.Context, in *ListAccountsRequest, out *ListAccountsResponse) error
 		Delete(ctx context.Context, in *DeleteAccountRequest, out *DeleteAccountResponse) error
 		ChangeSecret(ctx context.Context, in *ChangeSecretRequest, out *ChangeSecretResponse) error
+		ReadProfile(ctx context.Context, in *ReadProfileRequest, out *ReadProfileResponse) error
+		UpdateProfile(ctx context.Context, in *UpdateProfileRequest, out *UpdateProfileResponse) error
+		EnrollMFA(ctx context.Context, in *EnrollMFARequest, out *EnrollMFAResponse) error
 	}
 	type Accounts struct {
 		accounts
@@ -219,6 +258,111 @@ func (h *accountsHandler) ChangeSecret(ctx context.Context, in *ChangeSecretRequ
 	return h.AccountsHandler.ChangeSecret(ctx, in, out)
 }
 
+func (h *accountsHandler) ReadProfile(ctx context.Context, in *ReadProfileRequest, out *ReadProfileResponse) error {
+	return h.AccountsHandler.ReadProfile(ctx, in, out)
+}
+
+func (h *accountsHandler) UpdateProfile(ctx context.Context, in *UpdateProfileRequest, out *UpdateProfileResponse) error {
+	return h.AccountsHandler.UpdateProfile(ctx, in, out)
+}
+
+func (h *accountsHandler) EnrollMFA(ctx context.Context, in *EnrollMFARequest, out *EnrollMFAResponse) error {
+	return h.AccountsHandler.EnrollMFA(ctx, in, out)
+}
+
+// Api Endpoints for Grants service
+
+func NewGrantsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Grants service
+
+type GrantsService interface {
+	Create(ctx context.Context, in *CreateGrantRequest, opts ...client.CallOption) (*CreateGrantResponse, error)
+	List(ctx context.Context, in *ListGrantsRequest, opts ...client.CallOption) (*ListGrantsResponse, error)
+	Revoke(ctx context.Context, in *RevokeGrantRequest, opts ...client.CallOption) (*RevokeGrantResponse, error)
+}
+
+type grantsService struct {
+	c    client.Client
+	name string
+}
+
+func NewGrantsService(name string, c client.Client) GrantsService {
+	return &grantsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *grantsService) Create(ctx context.Context, in *CreateGrantRequest, opts ...client.CallOption) (*CreateGrantResponse, error) {
+	req := c.c.NewRequest(c.name, "Grants.Create", in)
+	out := new(CreateGrantResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grantsService) List(ctx context.Context, in *ListGrantsRequest, opts ...client.CallOption) (*ListGrantsResponse, error) {
+	req := c.c.NewRequest(c.name, "Grants.List", in)
+	out := new(ListGrantsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grantsService) Revoke(ctx context.Context, in *RevokeGrantRequest, opts ...client.CallOption) (*RevokeGrantResponse, error) {
+	req := c.c.NewRequest(c.name, "Grants.Revoke", in)
+	out := new(RevokeGrantResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Grants service
+
+type GrantsHandler interface {
+	Create(context.Context, *CreateGrantRequest, *CreateGrantResponse) error
+	List(context.Context, *ListGrantsRequest, *ListGrantsResponse) error
+	Revoke(context.Context, *RevokeGrantRequest, *RevokeGrantResponse) error
+}
+
+func RegisterGrantsHandler(s server.Server, hdlr GrantsHandler, opts ...server.HandlerOption) error {
+	type grants interface {
+		Create(ctx context.Context, in *CreateGrantRequest, out *CreateGrantResponse) error
+		List(ctx context.Context, in *ListGrantsRequest, out *ListGrantsResponse) error
+		Revoke(ctx context.Context, in *RevokeGrantRequest, out *RevokeGrantResponse) error
+	}
+	type Grants struct {
+		grants
+	}
+	h := &grantsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Grants{h}, opts...))
+}
+
+type grantsHandler struct {
+	GrantsHandler
+}
+
+func (h *grantsHandler) Create(ctx context.Context, in *CreateGrantRequest, out *CreateGrantResponse) error {
+	return h.GrantsHandler.Create(ctx, in, out)
+}
+
+func (h *grantsHandler) List(ctx context.Context, in *ListGrantsRequest, out *ListGrantsResponse) error {
+	return h.GrantsHandler.List(ctx, in, out)
+}
+
+func (h *grantsHandler) Revoke(ctx context.Context, in *RevokeGrantRequest, out *RevokeGrantResponse) error {
+	return h.GrantsHandler.Revoke(ctx, in, out)
+}
+
 // Api Endpoints for Rules service
 
 func NewRulesEndpoints() []*api.Endpoint {
@@ -231,6 +375,7 @@ type RulesService interface {
 	Create(ctx context.Context, in *CreateRequest, opts ...client.CallOption) (*CreateResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (*ListResponse, error)
+	Matrix(ctx context.Context, in *MatrixRequest, opts ...client.CallOption) (*MatrixResponse, error)
 }
 
 type rulesService struct {
@@ -275,12 +420,23 @@ func (c *rulesService) List(ctx context.Context, in *ListRequest, opts ...client
 	return out, nil
 }
 
+func (c *rulesService) Matrix(ctx context.Context, in *MatrixRequest, opts ...client.CallOption) (*MatrixResponse, error) {
+	req := c.c.NewRequest(c.name, "Rules.Matrix", in)
+	out := new(MatrixResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Rules service
 
 type RulesHandler interface {
 	Create(context.Context, *CreateRequest, *CreateResponse) error
 	Delete(context.Context, *DeleteRequest, *DeleteResponse) error
 	List(context.Context, *ListRequest, *ListResponse) error
+	Matrix(context.Context, *MatrixRequest, *MatrixResponse) error
 }
 
 func RegisterRulesHandler(s server.Server, hdlr RulesHandler, opts ...server.HandlerOption) error {
@@ -288,6 +444,7 @@ func RegisterRulesHandler(s server.Server, hdlr RulesHandler, opts ...server.Han
 		Create(ctx context.Context, in *CreateRequest, out *CreateResponse) error
 		Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error
 		List(ctx context.Context, in *ListRequest, out *ListResponse) error
+		Matrix(ctx context.Context, in *MatrixRequest, out *MatrixResponse) error
 	}
 	type Rules struct {
 		rules
@@ -311,3 +468,134 @@ func (h *rulesHandler) Delete(ctx context.Context, in *DeleteRequest, out *Delet
 func (h *rulesHandler) List(ctx context.Context, in *ListRequest, out *ListResponse) error {
 	return h.RulesHandler.List(ctx, in, out)
 }
+
+func (h *rulesHandler) Matrix(ctx context.Context, in *MatrixRequest, out *MatrixResponse) error {
+	return h.RulesHandler.Matrix(ctx, in, out)
+}
+
+// Api Endpoints for Domains service
+
+func NewDomainsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Domains service
+
+type DomainsService interface {
+	Claim(ctx context.Context, in *ClaimDomainRequest, opts ...client.CallOption) (*ClaimDomainResponse, error)
+	Verify(ctx context.Context, in *VerifyDomainRequest, opts ...client.CallOption) (*VerifyDomainResponse, error)
+	List(ctx context.Context, in *ListDomainsRequest, opts ...client.CallOption) (*ListDomainsResponse, error)
+	Release(ctx context.Context, in *ReleaseDomainRequest, opts ...client.CallOption) (*ReleaseDomainResponse, error)
+	Resolve(ctx context.Context, in *ResolveDomainRequest, opts ...client.CallOption) (*ResolveDomainResponse, error)
+}
+
+type domainsService struct {
+	c    client.Client
+	name string
+}
+
+func NewDomainsService(name string, c client.Client) DomainsService {
+	return &domainsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *domainsService) Claim(ctx context.Context, in *ClaimDomainRequest, opts ...client.CallOption) (*ClaimDomainResponse, error) {
+	req := c.c.NewRequest(c.name, "Domains.Claim", in)
+	out := new(ClaimDomainResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsService) Verify(ctx context.Context, in *VerifyDomainRequest, opts ...client.CallOption) (*VerifyDomainResponse, error) {
+	req := c.c.NewRequest(c.name, "Domains.Verify", in)
+	out := new(VerifyDomainResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsService) List(ctx context.Context, in *ListDomainsRequest, opts ...client.CallOption) (*ListDomainsResponse, error) {
+	req := c.c.NewRequest(c.name, "Domains.List", in)
+	out := new(ListDomainsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsService) Release(ctx context.Context, in *ReleaseDomainRequest, opts ...client.CallOption) (*ReleaseDomainResponse, error) {
+	req := c.c.NewRequest(c.name, "Domains.Release", in)
+	out := new(ReleaseDomainResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainsService) Resolve(ctx context.Context, in *ResolveDomainRequest, opts ...client.CallOption) (*ResolveDomainResponse, error) {
+	req := c.c.NewRequest(c.name, "Domains.Resolve", in)
+	out := new(ResolveDomainResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Domains service
+
+type DomainsHandler interface {
+	Claim(context.Context, *ClaimDomainRequest, *ClaimDomainResponse) error
+	Verify(context.Context, *VerifyDomainRequest, *VerifyDomainResponse) error
+	List(context.Context, *ListDomainsRequest, *ListDomainsResponse) error
+	Release(context.Context, *ReleaseDomainRequest, *ReleaseDomainResponse) error
+	Resolve(context.Context, *ResolveDomainRequest, *ResolveDomainResponse) error
+}
+
+func RegisterDomainsHandler(s server.Server, hdlr DomainsHandler, opts ...server.HandlerOption) error {
+	type domains interface {
+		Claim(ctx context.Context, in *ClaimDomainRequest, out *ClaimDomainResponse) error
+		Verify(ctx context.Context, in *VerifyDomainRequest, out *VerifyDomainResponse) error
+		List(ctx context.Context, in *ListDomainsRequest, out *ListDomainsResponse) error
+		Release(ctx context.Context, in *ReleaseDomainRequest, out *ReleaseDomainResponse) error
+		Resolve(ctx context.Context, in *ResolveDomainRequest, out *ResolveDomainResponse) error
+	}
+	type Domains struct {
+		domains
+	}
+	h := &domainsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Domains{h}, opts...))
+}
+
+type domainsHandler struct {
+	DomainsHandler
+}
+
+func (h *domainsHandler) Claim(ctx context.Context, in *ClaimDomainRequest, out *ClaimDomainResponse) error {
+	return h.DomainsHandler.Claim(ctx, in, out)
+}
+
+func (h *domainsHandler) Verify(ctx context.Context, in *VerifyDomainRequest, out *VerifyDomainResponse) error {
+	return h.DomainsHandler.Verify(ctx, in, out)
+}
+
+func (h *domainsHandler) List(ctx context.Context, in *ListDomainsRequest, out *ListDomainsResponse) error {
+	return h.DomainsHandler.List(ctx, in, out)
+}
+
+func (h *domainsHandler) Release(ctx context.Context, in *ReleaseDomainRequest, out *ReleaseDomainResponse) error {
+	return h.DomainsHandler.Release(ctx, in, out)
+}
+
+func (h *domainsHandler) Resolve(ctx context.Context, in *ResolveDomainRequest, out *ResolveDomainResponse) error {
+	return h.DomainsHandler.Resolve(ctx, in, out)
+}
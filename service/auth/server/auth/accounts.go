@@ -93,14 +93,42 @@ func (a *Auth) Delete(ctx context.Context, req *pb.DeleteAccountRequest, rsp *pb
 		return errors.BadRequest("auth.Accounts.Delete", "Error querying accounts: %v", err)
 	}
 
-	// delete the refresh token linked to the account
-	tok, err := a.refreshTokenForAccount(req.Options.Namespace, req.Id)
+	// delete the refresh tokens linked to the account, including those issued to clients
+	prefix := strings.Join([]string{storePrefixRefreshTokens, req.Options.Namespace, req.Id, ""}, joinKey)
+	refreshKeys, err := a.Options.Store.List(store.ListPrefix(prefix))
 	if err != nil {
 		return errors.InternalServerError("auth.Accounts.Delete", "Error finding refresh token")
 	}
-	refreshKey := strings.Join([]string{storePrefixRefreshTokens, req.Options.Namespace, req.Id, tok}, joinKey)
-	if err := a.Options.Store.Delete(refreshKey); err != nil {
-		return errors.InternalServerError("auth.Accounts.Delete", "Error deleting refresh token: %v", err)
+	for _, k := range refreshKeys {
+		if err := a.Options.Store.Delete(k); err != nil {
+			return errors.InternalServerError("auth.Accounts.Delete", "Error deleting refresh token: %v", err)
+		}
+	}
+
+	// delete the second factor and known origins so they aren't inherited by a new account
+	for _, prefix := range []string{storePrefixMFA, storePrefixKnown} {
+		k := strings.Join([]string{prefix, req.Options.Namespace, req.Id}, joinKey)
+		if err := a.Options.Store.Delete(k); err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.Accounts.Delete", "Error deleting account data: %v", err)
+		}
+	}
+
+	// delete the profile linked to the account
+	profileKey := strings.Join([]string{storePrefixProfiles, req.Options.Namespace, req.Id}, joinKey)
+	if err := a.Options.Store.Delete(profileKey); err != nil && err != store.ErrNotFound {
+		return errors.InternalServerError("auth.Accounts.Delete", "Error deleting profile: %v", err)
+	}
+
+	// delete the grants the account has given to client applications
+	grantPrefix := strings.Join([]string{storePrefixGrants, req.Options.Namespace, req.Id, ""}, joinKey)
+	grants, err := a.Options.Store.List(store.ListPrefix(grantPrefix))
+	if err != nil {
+		return errors.InternalServerError("auth.Accounts.Delete", "Error listing grants: %v", err)
+	}
+	for _, k := range grants {
+		if err := a.Options.Store.Delete(k); err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.Accounts.Delete", "Error deleting grant: %v", err)
+		}
 	}
 
 	// delete the account
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/micro/go-micro/v3/util/token"
 	"github.com/micro/go-micro/v3/util/token/basic"
 	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/auth/federation"
 	pb "github.com/micro/micro/v3/service/auth/proto"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
@@ -38,6 +40,20 @@ var defaultAccount = auth.Account{
 type Auth struct {
 	Options       auth.Options
 	TokenProvider token.Provider
+	// ProfileSchema defines the attributes which can be set on
+	// account profiles, DefaultProfileSchema is used if nil
+	ProfileSchema map[string]*ProfileField
+	// Federation verifies the tokens issued by peer clusters, nil if there are none
+	Federation *federation.Federation
+	// Risk evaluates the risk of issuing tokens to accounts, nil to issue them without
+	// evaluating the risk
+	Risk RiskEvaluator
+	// StepUp verifies the step-up verification of tokens which are risky to issue, if nil
+	// they can't be issued
+	StepUp StepUpVerifier
+	// TrustedProxies are the networks of the gateways and proxies whose X-Forwarded-For
+	// header is trusted when evaluating the risk of issuing tokens
+	TrustedProxies []*net.IPNet
 
 	namespaces map[string]bool
 	sync.Mutex
@@ -133,6 +149,14 @@ func (a *Auth) Generate(ctx context.Context, req *pb.GenerateRequest, rsp *pb.Ge
 		return errors.InternalServerError("auth.Auth.Generate", err.Error())
 	}
 
+	// users signing up with an email address at a verified domain join the namespace which
+	// claimed it, if it enabled auto join
+	if req.Type == "user" && req.Options.Namespace == namespace.DefaultNamespace {
+		if ns := autoJoinNamespace(req.Id); len(ns) > 0 {
+			req.Options.Namespace = ns
+		}
+	}
+
 	// check the user does not already exists
 	key := strings.Join([]string{storePrefixAccounts, req.Options.Namespace, req.Id}, joinKey)
 	if _, err := store.Read(key); err != gostore.ErrNotFound {
@@ -195,6 +219,14 @@ func (a *Auth) createAccount(acc *auth.Account) error {
 // Inspect a token and retrieve the account
 func (a *Auth) Inspect(ctx context.Context, req *pb.InspectRequest, rsp *pb.InspectResponse) error {
 	acc, err := a.TokenProvider.Inspect(req.Token)
+	if err != nil && a.Federation != nil {
+		// the token may have been issued by a peer cluster
+		if facc, ferr := a.Federation.Inspect(req.Token); ferr == nil {
+			acc, err = facc, nil
+		} else if ferr == federation.ErrNamespace {
+			return errors.Forbidden("auth.Auth.Inspect", ferr.Error())
+		}
+	}
 	if err == token.ErrInvalidToken || err == token.ErrNotFound {
 		return errors.BadRequest("auth.Auth.Inspect", err.Error())
 	} else if err != nil {
@@ -227,6 +259,13 @@ func (a *Auth) Token(ctx context.Context, req *pb.TokenRequest, rsp *pb.TokenRes
 		return errors.BadRequest("auth.Auth.Token", "Credentials or a refresh token required")
 	}
 
+	// client applications must authenticate with their own credentials
+	if len(req.ClientId) > 0 {
+		if err := authenticateClient(req.Options.Namespace, req.ClientId, req.ClientSecret); err != nil {
+			return err
+		}
+	}
+
 	// check to see if the secret is a JWT. this is a workaround to allow accounts issued
 	// by the runtime to be refreshed whilst keeping the private key in the server.
 	if a.TokenProvider.String() == "jwt" {
@@ -236,6 +275,18 @@ func (a *Auth) Token(ctx context.Context, req *pb.TokenRequest, rsp *pb.TokenRes
 		}
 
 		if acc, err := a.TokenProvider.Inspect(jwt); err == nil {
+			if err := checkClient(acc.Metadata[clientIDKey], req.ClientId); err != nil {
+				return err
+			}
+			if len(req.ClientId) > 0 {
+				if acc, err = a.accountForClient(req.Options.Namespace, acc, req.ClientId, req.Scopes); err != nil {
+					return err
+				}
+			}
+			if err := a.evaluateRisk(ctx, req.Options.Namespace, acc, req.Verification); err != nil {
+				return err
+			}
+
 			expiry := time.Duration(int64(time.Second) * req.TokenExpiry)
 			tok, _ := a.TokenProvider.Generate(acc, token.WithExpiry(expiry))
 			rsp.Token = serializeToken(tok, tok.Token)
@@ -247,14 +298,18 @@ func (a *Auth) Token(ctx context.Context, req *pb.TokenRequest, rsp *pb.TokenRes
 	accountID := req.Id
 	refreshToken := req.RefreshToken
 
-	// If the refresh token is set, check this
+	// If the refresh token is set, check this. Refresh tokens issued to a client can only
+	// be used by the same client.
 	if len(req.RefreshToken) > 0 {
-		accID, err := a.accountIDForRefreshToken(req.Options.Namespace, req.RefreshToken)
+		accID, clientID, err := a.accountIDForRefreshToken(req.Options.Namespace, req.RefreshToken)
 		if err == gostore.ErrNotFound {
 			return errors.BadRequest("auth.Auth.Token", "Account can't be found for refresh token")
 		} else if err != nil {
 			return errors.InternalServerError("auth.Auth.Token", "Unable to lookup token: %v", err)
 		}
+		if err := checkClient(clientID, req.ClientId); err != nil {
+			return err
+		}
 		accountID = accID
 	}
 
@@ -273,19 +328,32 @@ func (a *Auth) Token(ctx context.Context, req *pb.TokenRequest, rsp *pb.TokenRes
 		return errors.InternalServerError("auth.Auth.Token", "Unable to unmarshal account: %v", err)
 	}
 
-	// If the refresh token was not used, validate the secrets match and then set the refresh token
-	// so it can be returned to the user
-	if len(req.RefreshToken) == 0 {
-		if !secretsMatch(acc.Secret, req.Secret) {
-			return errors.BadRequest("auth.Auth.Token", "Secret not correct")
+	// If the refresh token was not used, validate the secrets match
+	if len(req.RefreshToken) == 0 && !secretsMatch(acc.Secret, req.Secret) {
+		return errors.BadRequest("auth.Auth.Token", "Secret not correct")
+	}
+
+	// Restrict the token to the scopes the account has granted the client
+	if len(req.ClientId) > 0 {
+		if acc, err = a.accountForClient(req.Options.Namespace, acc, req.ClientId, req.Scopes); err != nil {
+			return err
 		}
+	}
 
-		refreshToken, err = a.refreshTokenForAccount(req.Options.Namespace, acc.ID)
+	// Set the refresh token so it can be returned to the user. Clients are returned a refresh
+	// token bound to them rather than the account's own, which would exceed the grant.
+	if len(req.RefreshToken) == 0 || len(req.ClientId) > 0 {
+		refreshToken, err = a.refreshTokenForAccount(req.Options.Namespace, acc.ID, req.ClientId)
 		if err != nil {
 			return errors.InternalServerError("auth.Auth.Token", "Unable to get refresh token: %v", err)
 		}
 	}
 
+	// Require step-up verification if the token is risky to issue
+	if err := a.evaluateRisk(ctx, req.Options.Namespace, acc, req.Verification); err != nil {
+		return err
+	}
+
 	// Generate a new access token
 	duration := time.Duration(req.TokenExpiry) * time.Second
 	tok, err := a.TokenProvider.Generate(acc, token.WithExpiry(duration))
@@ -297,49 +365,71 @@ func (a *Auth) Token(ctx context.Context, req *pb.TokenRequest, rsp *pb.TokenRes
 	return nil
 }
 
-// set the refresh token for an account
+// set the refresh token for an account. Tokens issued to a client application record the
+// client as the value.
 func (a *Auth) setRefreshToken(ns, id, token string) error {
+	return a.setClientRefreshToken(ns, id, "", token)
+}
+
+func (a *Auth) setClientRefreshToken(ns, id, clientID, token string) error {
 	key := strings.Join([]string{storePrefixRefreshTokens, ns, id, token}, joinKey)
-	return store.Write(&gostore.Record{Key: key})
+	return store.Write(&gostore.Record{Key: key, Value: []byte(clientID)})
 }
 
-// get the refresh token for an accutn
-func (a *Auth) refreshTokenForAccount(ns, id string) (string, error) {
+// get the refresh token for an account, or the one issued to the client if the client ID is
+// set. Refresh tokens are created for clients the first time they're issued a token.
+func (a *Auth) refreshTokenForAccount(ns, id, clientID string) (string, error) {
 	prefix := strings.Join([]string{storePrefixRefreshTokens, ns, id, ""}, joinKey)
 
 	recs, err := store.Read(prefix, gostore.ReadPrefix())
-	if err != nil {
+	if err != nil && err != gostore.ErrNotFound {
 		return "", err
-	} else if len(recs) == 0 {
-		return "", gostore.ErrNotFound
 	}
 
-	comps := strings.Split(recs[0].Key, "/")
-	if len(comps) != 4 {
+	for _, r := range recs {
+		if string(r.Value) != clientID {
+			continue
+		}
+		comps := strings.Split(r.Key, "/")
+		if len(comps) != 4 {
+			return "", gostore.ErrNotFound
+		}
+		return comps[3], nil
+	}
+	if len(clientID) == 0 {
 		return "", gostore.ErrNotFound
 	}
-	return comps[3], nil
+
+	token := uuid.New().String()
+	if err := a.setClientRefreshToken(ns, id, clientID, token); err != nil {
+		return "", err
+	}
+	return token, nil
 }
 
-// get the account ID for the given refresh token
-func (a *Auth) accountIDForRefreshToken(ns, token string) (string, error) {
+// get the account ID for the given refresh token, and the client it was issued to
+func (a *Auth) accountIDForRefreshToken(ns, token string) (string, string, error) {
 	prefix := strings.Join([]string{storePrefixRefreshTokens, ns}, joinKey)
 	keys, err := store.List(gostore.ListPrefix(prefix))
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	for _, k := range keys {
 		if strings.HasSuffix(k, "/"+token) {
 			comps := strings.Split(k, "/")
 			if len(comps) != 4 {
-				return "", gostore.ErrNotFound
+				return "", "", gostore.ErrNotFound
+			}
+			recs, err := store.Read(k)
+			if err != nil {
+				return "", "", err
 			}
-			return comps[2], nil
+			return comps[2], string(recs[0].Value), nil
 		}
 	}
 
-	return "", gostore.ErrNotFound
+	return "", "", gostore.ErrNotFound
 }
 
 func serializeToken(t *token.Token, refresh string) *pb.Token {
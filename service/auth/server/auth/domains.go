@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	storePrefixDomains = "domain"
+	// challengeRecord is prefixed to a domain to get the name of the TXT record which proves
+	// control of it
+	challengeRecord = "_micro-challenge."
+	// challengePrefix is prefixed to the challenge token in the value of the TXT record
+	challengePrefix = "micro-verification="
+)
+
+// Domains processes RPC calls for the DNS domains claimed by namespaces. A namespace proves
+// control of a domain by creating a TXT record with a challenge, after which requests to the
+// domain are routed to the namespace and, if enabled, new users with an email address at the
+// domain join it.
+type Domains struct {
+	// LookupTXT resolves the TXT records of a name, defaults to net.LookupTXT
+	LookupTXT func(name string) ([]string, error)
+}
+
+// Claim a domain for a namespace, returning the TXT record to create to verify it. Claiming a
+// domain again returns the existing challenge.
+func (d *Domains) Claim(ctx context.Context, req *pb.ClaimDomainRequest, rsp *pb.ClaimDomainResponse) error {
+	name := normalizeDomain(req.Domain)
+	if len(name) == 0 {
+		return errors.BadRequest("auth.Domains.Claim", "Missing domain")
+	}
+	ns, err := authorizeDomains(ctx, "auth.Domains.Claim", req.Options)
+	if err != nil {
+		return err
+	}
+
+	dom, err := readDomain(name, ns)
+	if err == gostore.ErrNotFound {
+		dom = &pb.Domain{
+			Name:      name,
+			Namespace: ns,
+			Challenge: challengePrefix + uuid.New().String(),
+			Created:   time.Now().Unix(),
+		}
+	} else if err != nil {
+		return errors.InternalServerError("auth.Domains.Claim", "Unable to read domain: %v", err)
+	}
+	dom.AutoJoin = req.AutoJoin
+
+	if err := writeDomain(dom); err != nil {
+		return errors.InternalServerError("auth.Domains.Claim", "Unable to write domain: %v", err)
+	}
+
+	rsp.Domain = dom
+	rsp.Record = challengeRecord + name
+	return nil
+}
+
+// Verify a claimed domain by checking its TXT record contains the challenge. A domain can only
+// be verified by one namespace at a time.
+func (d *Domains) Verify(ctx context.Context, req *pb.VerifyDomainRequest, rsp *pb.VerifyDomainResponse) error {
+	name := normalizeDomain(req.Domain)
+	if len(name) == 0 {
+		return errors.BadRequest("auth.Domains.Verify", "Missing domain")
+	}
+	ns, err := authorizeDomains(ctx, "auth.Domains.Verify", req.Options)
+	if err != nil {
+		return err
+	}
+
+	dom, err := readDomain(name, ns)
+	if err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Domains.Verify", "Domain has not been claimed")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Domains.Verify", "Unable to read domain: %v", err)
+	}
+
+	if owner, err := resolveDomain(name); err == nil && owner.Namespace != ns {
+		return errors.Conflict("auth.Domains.Verify", "Domain is verified by another namespace")
+	} else if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("auth.Domains.Verify", "Unable to resolve domain: %v", err)
+	}
+
+	lookup := d.LookupTXT
+	if lookup == nil {
+		lookup = net.LookupTXT
+	}
+	records, err := lookup(challengeRecord + name)
+	if err != nil {
+		return errors.BadRequest("auth.Domains.Verify", "Unable to lookup TXT record %v%v: %v", challengeRecord, name, err)
+	}
+	var found bool
+	for _, r := range records {
+		if strings.TrimSpace(r) == dom.Challenge {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.BadRequest("auth.Domains.Verify", "TXT record %v%v does not contain the challenge", challengeRecord, name)
+	}
+
+	if !dom.Verified {
+		dom.Verified = true
+		dom.VerifiedAt = time.Now().Unix()
+		if err := writeDomain(dom); err != nil {
+			return errors.InternalServerError("auth.Domains.Verify", "Unable to write domain: %v", err)
+		}
+	}
+
+	rsp.Domain = dom
+	return nil
+}
+
+// List the domains claimed by a namespace
+func (d *Domains) List(ctx context.Context, req *pb.ListDomainsRequest, rsp *pb.ListDomainsResponse) error {
+	ns, err := authorizeDomains(ctx, "auth.Domains.List", req.Options)
+	if err != nil {
+		return err
+	}
+
+	recs, err := store.Read(storePrefixDomains+joinKey, gostore.ReadPrefix())
+	if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("auth.Domains.List", "Unable to read domains: %v", err)
+	}
+
+	rsp.Domains = make([]*pb.Domain, 0)
+	for _, r := range recs {
+		var dom *pb.Domain
+		if err := json.Unmarshal(r.Value, &dom); err != nil {
+			return errors.InternalServerError("auth.Domains.List", "Unable to unmarshal domain: %v", err)
+		}
+		if dom.Namespace == ns {
+			rsp.Domains = append(rsp.Domains, dom)
+		}
+	}
+	return nil
+}
+
+// Release a domain claimed by a namespace
+func (d *Domains) Release(ctx context.Context, req *pb.ReleaseDomainRequest, rsp *pb.ReleaseDomainResponse) error {
+	name := normalizeDomain(req.Domain)
+	if len(name) == 0 {
+		return errors.BadRequest("auth.Domains.Release", "Missing domain")
+	}
+	ns, err := authorizeDomains(ctx, "auth.Domains.Release", req.Options)
+	if err != nil {
+		return err
+	}
+
+	key := strings.Join([]string{storePrefixDomains, name, ns}, joinKey)
+	if err := store.Delete(key); err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Domains.Release", "Domain has not been claimed")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Domains.Release", "Unable to delete domain: %v", err)
+	}
+	return nil
+}
+
+// Resolve the namespace a verified domain is bound to. Domains aren't secret, so no account
+// is required, allowing the gateway to route requests by their host.
+func (d *Domains) Resolve(ctx context.Context, req *pb.ResolveDomainRequest, rsp *pb.ResolveDomainResponse) error {
+	name := normalizeDomain(req.Domain)
+	if len(name) == 0 {
+		return errors.BadRequest("auth.Domains.Resolve", "Missing domain")
+	}
+
+	dom, err := resolveDomain(name)
+	if err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Domains.Resolve", "Domain is not verified")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Domains.Resolve", "Unable to resolve domain: %v", err)
+	}
+
+	rsp.Namespace = dom.Namespace
+	rsp.AutoJoin = dom.AutoJoin
+	return nil
+}
+
+// authorizeDomains returns the namespace of the request, checking the caller has access to it
+func authorizeDomains(ctx context.Context, method string, opts *pb.Options) (string, error) {
+	ns := namespace.FromContext(ctx)
+	if opts != nil && len(opts.Namespace) > 0 {
+		ns = opts.Namespace
+	}
+
+	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
+		return "", errors.Forbidden(method, err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return "", errors.Unauthorized(method, err.Error())
+	} else if err != nil {
+		return "", errors.InternalServerError(method, err.Error())
+	}
+	return ns, nil
+}
+
+// normalizeDomain lower cases the domain and strips any trailing dot
+func normalizeDomain(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+func readDomain(name, ns string) (*pb.Domain, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixDomains, name, ns}, joinKey))
+	if err != nil {
+		return nil, err
+	}
+
+	var dom *pb.Domain
+	if err := json.Unmarshal(recs[0].Value, &dom); err != nil {
+		return nil, err
+	}
+	return dom, nil
+}
+
+func writeDomain(dom *pb.Domain) error {
+	bytes, err := json.Marshal(dom)
+	if err != nil {
+		return err
+	}
+	key := strings.Join([]string{storePrefixDomains, dom.Name, dom.Namespace}, joinKey)
+	return store.Write(&gostore.Record{Key: key, Value: bytes})
+}
+
+// resolveDomain returns the verified claim of a domain, gostore.ErrNotFound if no namespace
+// has verified it
+func resolveDomain(name string) (*pb.Domain, error) {
+	prefix := strings.Join([]string{storePrefixDomains, name, ""}, joinKey)
+	recs, err := store.Read(prefix, gostore.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range recs {
+		var dom *pb.Domain
+		if err := json.Unmarshal(r.Value, &dom); err != nil {
+			return nil, err
+		}
+		if dom.Verified {
+			return dom, nil
+		}
+	}
+	return nil, gostore.ErrNotFound
+}
+
+// autoJoinNamespace returns the namespace a new user joins by the domain of their email
+// address, blank if the domain isn't verified with auto join enabled
+func autoJoinNamespace(id string) string {
+	idx := strings.LastIndex(id, "@")
+	if idx < 0 {
+		return ""
+	}
+	dom, err := resolveDomain(normalizeDomain(id[idx+1:]))
+	if err != nil || !dom.AutoJoin {
+		return ""
+	}
+	return dom.Namespace
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	storePrefixGrants = "grant"
+	// clientIDKey is set in the metadata of accounts issued to client applications
+	clientIDKey = "client_id"
+	// clientAccountType is the type of the accounts client applications authenticate as
+	clientAccountType = "client"
+)
+
+// Grants processes RPC calls for the consent given by accounts to client applications
+type Grants struct{}
+
+// Create records the consent of the calling account for a client application to use the
+// scopes on its behalf. Scopes are added to any the client was previously granted.
+func (g *Grants) Create(ctx context.Context, req *pb.CreateGrantRequest, rsp *pb.CreateGrantResponse) error {
+	// validate the request
+	if len(req.ClientId) == 0 {
+		return errors.BadRequest("auth.Grants.Create", "Missing client ID")
+	}
+	if len(req.Scopes) == 0 {
+		return errors.BadRequest("auth.Grants.Create", "Missing scopes")
+	}
+
+	acc, ns, err := grantAccount(ctx, "auth.Grants.Create", req.Options)
+	if err != nil {
+		return err
+	}
+
+	// an account can only grant the scopes it has
+	for _, s := range req.Scopes {
+		if !hasScope(acc.Scopes, s) {
+			return errors.Forbidden("auth.Grants.Create", "Account does not have the %v scope", s)
+		}
+	}
+
+	grant, err := readGrant(ns, acc.ID, req.ClientId)
+	if err == gostore.ErrNotFound {
+		grant = &pb.Grant{AccountId: acc.ID, ClientId: req.ClientId}
+	} else if err != nil {
+		return errors.InternalServerError("auth.Grants.Create", "Unable to read grant: %v", err)
+	}
+	for _, s := range req.Scopes {
+		if !hasScope(grant.Scopes, s) {
+			grant.Scopes = append(grant.Scopes, s)
+		}
+	}
+	sort.Strings(grant.Scopes)
+	grant.Created = time.Now().Unix()
+
+	bytes, err := json.Marshal(grant)
+	if err != nil {
+		return errors.InternalServerError("auth.Grants.Create", "Unable to marshal grant: %v", err)
+	}
+	key := strings.Join([]string{storePrefixGrants, ns, acc.ID, req.ClientId}, joinKey)
+	if err := store.Write(&gostore.Record{Key: key, Value: bytes}); err != nil {
+		return errors.InternalServerError("auth.Grants.Create", "Unable to write grant: %v", err)
+	}
+
+	rsp.Grant = grant
+	return nil
+}
+
+// List the grants of the calling account
+func (g *Grants) List(ctx context.Context, req *pb.ListGrantsRequest, rsp *pb.ListGrantsResponse) error {
+	acc, ns, err := grantAccount(ctx, "auth.Grants.List", req.Options)
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.Join([]string{storePrefixGrants, ns, acc.ID, ""}, joinKey)
+	recs, err := store.Read(prefix, gostore.ReadPrefix())
+	if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("auth.Grants.List", "Unable to read grants: %v", err)
+	}
+
+	rsp.Grants = make([]*pb.Grant, 0, len(recs))
+	for _, r := range recs {
+		var grant *pb.Grant
+		if err := json.Unmarshal(r.Value, &grant); err != nil {
+			return errors.InternalServerError("auth.Grants.List", "Unable to unmarshal grant: %v", err)
+		}
+		rsp.Grants = append(rsp.Grants, grant)
+	}
+	return nil
+}
+
+// Revoke the grant of a client application. Tokens already issued to the client remain
+// valid until they expire.
+func (g *Grants) Revoke(ctx context.Context, req *pb.RevokeGrantRequest, rsp *pb.RevokeGrantResponse) error {
+	// validate the request
+	if len(req.ClientId) == 0 {
+		return errors.BadRequest("auth.Grants.Revoke", "Missing client ID")
+	}
+
+	acc, ns, err := grantAccount(ctx, "auth.Grants.Revoke", req.Options)
+	if err != nil {
+		return err
+	}
+
+	key := strings.Join([]string{storePrefixGrants, ns, acc.ID, req.ClientId}, joinKey)
+	if err := store.Delete(key); err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Grants.Revoke", "Grant not found for this client")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Grants.Revoke", "Unable to delete grant: %v", err)
+	}
+	return nil
+}
+
+// grantAccount returns the account making the request and the namespace of its grants
+func grantAccount(ctx context.Context, method string, opts *pb.Options) (*auth.Account, string, error) {
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return nil, "", errors.Unauthorized(method, "An account is required")
+	}
+
+	ns := acc.Issuer
+	if opts != nil && len(opts.Namespace) > 0 {
+		ns = opts.Namespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
+		return nil, "", errors.Forbidden(method, err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return nil, "", errors.Unauthorized(method, err.Error())
+	} else if err != nil {
+		return nil, "", errors.InternalServerError(method, err.Error())
+	}
+
+	return acc, ns, nil
+}
+
+func readGrant(ns, accountID, clientID string) (*pb.Grant, error) {
+	key := strings.Join([]string{storePrefixGrants, ns, accountID, clientID}, joinKey)
+	recs, err := store.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var grant *pb.Grant
+	if err := json.Unmarshal(recs[0].Value, &grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// authenticateClient verifies the credentials of a client application. Clients authenticate
+// as accounts of the client type in the namespace.
+func authenticateClient(ns, clientID, secret string) error {
+	if len(secret) == 0 {
+		return errors.Unauthorized("auth.Auth.Token", "Client secret required")
+	}
+
+	key := strings.Join([]string{storePrefixAccounts, ns, clientID}, joinKey)
+	recs, err := store.Read(key)
+	if err == gostore.ErrNotFound {
+		return errors.Unauthorized("auth.Auth.Token", "Invalid client credentials")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Auth.Token", "Unable to read from store: %v", err)
+	}
+
+	var acc *auth.Account
+	if err := json.Unmarshal(recs[0].Value, &acc); err != nil {
+		return errors.InternalServerError("auth.Auth.Token", "Unable to unmarshal account: %v", err)
+	}
+	if acc.Type != clientAccountType || !secretsMatch(acc.Secret, secret) {
+		return errors.Unauthorized("auth.Auth.Token", "Invalid client credentials")
+	}
+	return nil
+}
+
+// checkClient ensures tokens issued to a client application are only refreshed by that client
+func checkClient(issuedTo, clientID string) error {
+	if len(issuedTo) == 0 || issuedTo == clientID {
+		return nil
+	}
+	return errors.Forbidden("auth.Auth.Token", "Token was issued to another client")
+}
+
+// accountForClient restricts the account to the scopes the client requested. The account must
+// have granted the client each of the scopes, and still hold them itself. No scopes are issued
+// if the client didn't request any.
+func (a *Auth) accountForClient(ns string, acc *auth.Account, clientID string, scopes []string) (*auth.Account, error) {
+	grant, err := readGrant(ns, acc.ID, clientID)
+	if err == gostore.ErrNotFound {
+		return nil, errors.Forbidden("auth.Auth.Token", "Consent required for client %v", clientID)
+	} else if err != nil {
+		return nil, errors.InternalServerError("auth.Auth.Token", "Unable to read grant: %v", err)
+	}
+
+	var missing []string
+	for _, s := range scopes {
+		if !hasScope(grant.Scopes, s) || !hasScope(acc.Scopes, s) {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.Forbidden("auth.Auth.Token", "Consent required for scopes: %v", strings.Join(missing, ", "))
+	}
+
+	// copy the account so the stored one isn't modified
+	md := make(map[string]string, len(acc.Metadata)+1)
+	for k, v := range acc.Metadata {
+		md[k] = v
+	}
+	md[clientIDKey] = clientID
+
+	return &auth.Account{
+		ID:       acc.ID,
+		Type:     acc.Type,
+		Issuer:   acc.Issuer,
+		Metadata: md,
+		Scopes:   scopes,
+		Secret:   acc.Secret,
+	}, nil
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestClientTokens(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Auth{}
+	a.Init()
+
+	john := &auth.Account{ID: "john", Type: "user", Issuer: "micro", Scopes: []string{"read", "write"}, Secret: "password"}
+	for _, acc := range []*auth.Account{
+		john,
+		{ID: "app", Type: clientAccountType, Issuer: "micro", Secret: "app-secret"},
+		{ID: "other", Type: clientAccountType, Issuer: "micro", Secret: "other-secret"},
+	} {
+		if err := a.createAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// john consents to both clients reading on his behalf
+	ctx := auth.ContextWithAccount(context.TODO(), john)
+	for _, id := range []string{"app", "other"} {
+		req := &pb.CreateGrantRequest{ClientId: id, Scopes: []string{"read"}}
+		if err := new(Grants).Create(ctx, req, &pb.CreateGrantResponse{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	token := func(req *pb.TokenRequest) (*pb.Token, int32) {
+		rsp := &pb.TokenResponse{}
+		if err := a.Token(context.TODO(), req, rsp); err != nil {
+			if verr := errors.Parse(err); verr != nil {
+				return nil, verr.Code
+			}
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return rsp.Token, 200
+	}
+
+	// the account's own token isn't restricted
+	own, code := token(&pb.TokenRequest{Id: "john", Secret: "password"})
+	if code != 200 {
+		t.Fatalf("Expected a token, got %v", code)
+	}
+
+	// clients must authenticate with their own credentials
+	tt := []struct {
+		Name   string
+		Client string
+		Secret string
+		Code   int32
+	}{
+		{"missing secret", "app", "", 401},
+		{"unknown client", "unknown", "secret", 401},
+		{"wrong secret", "app", "other-secret", 401},
+		{"user account", "john", "password", 401},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := &pb.TokenRequest{Id: "john", Secret: "password", ClientId: tc.Client, ClientSecret: tc.Secret, Scopes: []string{"read"}}
+			if _, code := token(req); code != tc.Code {
+				t.Errorf("Expected %v, got %v", tc.Code, code)
+			}
+		})
+	}
+
+	// scopes which weren't granted can't be requested, and none are issued by default
+	req := &pb.TokenRequest{Id: "john", Secret: "password", ClientId: "app", ClientSecret: "app-secret", Scopes: []string{"write"}}
+	if _, code := token(req); code != 403 {
+		t.Errorf("Expected an ungranted scope to be forbidden, got %v", code)
+	}
+	req.Scopes = nil
+	tok, code := token(req)
+	if code != 200 {
+		t.Fatalf("Expected a token, got %v", code)
+	}
+	if acc, err := a.TokenProvider.Inspect(tok.AccessToken); err != nil || len(acc.Scopes) != 0 {
+		t.Errorf("Expected no scopes to be issued by default, got %v %v", acc, err)
+	}
+
+	req.Scopes = []string{"read"}
+	tok, code = token(req)
+	if code != 200 {
+		t.Fatalf("Expected a token, got %v", code)
+	}
+	acc, err := a.TokenProvider.Inspect(tok.AccessToken)
+	if err != nil || len(acc.Scopes) != 1 || acc.Metadata[clientIDKey] != "app" {
+		t.Fatalf("Expected a token for the client with the read scope, got %v %v", acc, err)
+	}
+	if tok.RefreshToken == "" || tok.RefreshToken == own.RefreshToken {
+		t.Fatalf("Expected a refresh token bound to the client")
+	}
+
+	// the client's refresh token can only be used by the client
+	refresh := []struct {
+		Name   string
+		Client string
+		Secret string
+		Code   int32
+	}{
+		{"omitted client", "", "", 403},
+		{"mismatched client", "other", "other-secret", 403},
+		{"same client", "app", "app-secret", 200},
+	}
+	for _, tc := range refresh {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := &pb.TokenRequest{RefreshToken: tok.RefreshToken, ClientId: tc.Client, ClientSecret: tc.Secret, Scopes: []string{"read"}}
+			rt, code := token(req)
+			if code != tc.Code {
+				t.Fatalf("Expected %v, got %v", tc.Code, code)
+			}
+			if code == 200 && rt.RefreshToken != tok.RefreshToken {
+				t.Errorf("Expected the client's refresh token to be returned")
+			}
+		})
+	}
+
+	// a client refreshing the account's own token is returned its bound one
+	req = &pb.TokenRequest{RefreshToken: own.RefreshToken, ClientId: "app", ClientSecret: "app-secret", Scopes: []string{"read"}}
+	if rt, code := token(req); code != 200 || rt.RefreshToken != tok.RefreshToken {
+		t.Errorf("Expected the client's refresh token, got %v", code)
+	}
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	storePrefixMFA = "mfa"
+
+	// totpPeriod is the number of seconds each TOTP code is valid for
+	totpPeriod = 30
+	// totpSkew is the number of periods either side of the current one a code is accepted from,
+	// allowing for clock drift
+	totpSkew = 1
+)
+
+var (
+	// now is used to generate and validate TOTP codes, tests override it
+	now = time.Now
+	// base32 encoding of TOTP secrets, as expected by authenticator apps
+	totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// mfa is the second factor enrolled by an account
+type mfa struct {
+	Secret string `json:"secret"`
+	// Counter of the last code used, codes can't be reused
+	Counter uint64 `json:"counter"`
+}
+
+// EnrollMFA generates a TOTP secret for an account. Accounts can enroll themselves once, after
+// which only an admin can replace the secret.
+func (a *Auth) EnrollMFA(ctx context.Context, req *pb.EnrollMFARequest, rsp *pb.EnrollMFAResponse) error {
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.Accounts.EnrollMFA", "Missing ID")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.Accounts.EnrollMFA", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.Accounts.EnrollMFA", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.Accounts.EnrollMFA", err.Error())
+	}
+
+	// only the account itself or an admin can enroll it. tokens issued to clients can't be used
+	// since they only act on behalf of the account.
+	caller, _ := auth.AccountFromContext(ctx)
+	admin := hasScope(caller.Scopes, "admin")
+	self := caller.ID == req.Id && caller.Issuer == ns && len(caller.Metadata[clientIDKey]) == 0
+	if !admin && !self {
+		return errors.Forbidden("auth.Accounts.EnrollMFA", "Only admins can enroll other accounts")
+	}
+
+	// check the account exists
+	key := strings.Join([]string{storePrefixAccounts, ns, req.Id}, joinKey)
+	if _, err := store.Read(key); err == gostore.ErrNotFound {
+		return errors.BadRequest("auth.Accounts.EnrollMFA", "Account not found with this ID")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Accounts.EnrollMFA", "Unable to read from store: %v", err)
+	}
+
+	if _, err := readMFA(ns, req.Id); err == nil && !admin {
+		return errors.Forbidden("auth.Accounts.EnrollMFA", "A second factor is already enrolled, an admin must replace it")
+	} else if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("auth.Accounts.EnrollMFA", "Unable to read second factor: %v", err)
+	}
+
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return errors.InternalServerError("auth.Accounts.EnrollMFA", "Unable to generate secret: %v", err)
+	}
+	m := &mfa{Secret: totpEncoding.EncodeToString(b)}
+	if err := writeMFA(ns, req.Id, m); err != nil {
+		return errors.InternalServerError("auth.Accounts.EnrollMFA", "Unable to write second factor: %v", err)
+	}
+
+	rsp.Secret = m.Secret
+	rsp.Url = fmt.Sprintf("otpauth://totp/%v:%v?secret=%v&issuer=%v",
+		url.PathEscape(ns), url.PathEscape(req.Id), m.Secret, url.QueryEscape(ns))
+	return nil
+}
+
+// VerifyTOTP is a StepUpVerifier which requires a code generated from the TOTP secret the
+// account enrolled with EnrollMFA. Each code can only be used once.
+func VerifyTOTP(ctx context.Context, ns string, acc *auth.Account, verification string) error {
+	m, err := readMFA(ns, acc.ID)
+	if err == gostore.ErrNotFound {
+		return fmt.Errorf("No second factor enrolled")
+	} else if err != nil {
+		return err
+	}
+	secret, err := totpEncoding.DecodeString(m.Secret)
+	if err != nil {
+		return err
+	}
+
+	current := uint64(now().Unix() / totpPeriod)
+	for c := current - totpSkew; c <= current+totpSkew; c++ {
+		if c <= m.Counter || !hmac.Equal([]byte(totpCode(secret, c)), []byte(verification)) {
+			continue
+		}
+		m.Counter = c
+		return writeMFA(ns, acc.ID, m)
+	}
+	return fmt.Errorf("Code not correct")
+}
+
+// totpCode generates the six digit code for the counter as defined by RFC 6238
+func totpCode(secret []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	h := hmac.New(sha1.New, secret)
+	h.Write(msg)
+	sum := h.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", code%1000000)
+}
+
+func readMFA(ns, id string) (*mfa, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixMFA, ns, id}, joinKey))
+	if err != nil {
+		return nil, err
+	} else if len(recs) == 0 {
+		return nil, gostore.ErrNotFound
+	}
+
+	var m *mfa
+	if err := json.Unmarshal(recs[0].Value, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeMFA(ns, id string, m *mfa) error {
+	bytes, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{
+		Key:   strings.Join([]string{storePrefixMFA, ns, id}, joinKey),
+		Value: bytes,
+	})
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// test vectors from RFC 6238, truncated to six digits
+	secret := []byte("12345678901234567890")
+	tt := []struct {
+		Time int64
+		Code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range tt {
+		if code := totpCode(secret, uint64(tc.Time/totpPeriod)); code != tc.Code {
+			t.Errorf("Expected %v at %v, got %v", tc.Code, tc.Time, code)
+		}
+	}
+}
+
+func TestEnrollMFA(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Auth{}
+	a.Init()
+	for _, id := range []string{"john", "jane"} {
+		if err := a.createAccount(&auth.Account{ID: id, Type: "user", Issuer: "micro", Secret: "password"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	enroll := func(caller *auth.Account, id string) int32 {
+		ctx := auth.ContextWithAccount(context.TODO(), caller)
+		rsp := &pb.EnrollMFAResponse{}
+		if err := a.EnrollMFA(ctx, &pb.EnrollMFARequest{Id: id}, rsp); err != nil {
+			return errors.Parse(err).Code
+		}
+		if len(rsp.Secret) == 0 || len(rsp.Url) == 0 {
+			t.Fatalf("Expected a secret to be returned")
+		}
+		return 200
+	}
+
+	john := &auth.Account{ID: "john", Issuer: "micro"}
+	admin := &auth.Account{ID: "admin", Issuer: "micro", Scopes: []string{"admin"}}
+	client := &auth.Account{ID: "jane", Issuer: "micro", Metadata: map[string]string{clientIDKey: "app"}}
+
+	tt := []struct {
+		Name   string
+		Caller *auth.Account
+		ID     string
+		Code   int32
+	}{
+		{"another account", john, "jane", 403},
+		{"client token", client, "jane", 403},
+		{"self", john, "john", 200},
+		{"replace own", john, "john", 403},
+		{"admin replaces", admin, "john", 200},
+		{"unknown account", admin, "joe", 400},
+	}
+	for _, tc := range tt {
+		if code := enroll(tc.Caller, tc.ID); code != tc.Code {
+			t.Errorf("%v: expected %v, got %v", tc.Name, tc.Code, code)
+		}
+	}
+}
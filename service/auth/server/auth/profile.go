@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v3/auth"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const storePrefixProfiles = "profile"
+
+// ProfileField describes an attribute which can be set on an account profile
+type ProfileField struct {
+	// Type of the value, one of string, url, email, int or bool. Defaults to string.
+	Type string `json:"type"`
+	// Required fields must be set on every profile, so the first update of a profile must
+	// set them and they can't be removed
+	Required bool `json:"required"`
+	// MaxLength of the value, zero means no limit
+	MaxLength int `json:"max_length"`
+	// Pattern is an optional regular expression the value must match
+	Pattern string `json:"pattern"`
+	// Self indicates the account can update its own value
+	Self bool `json:"self"`
+	// Scopes which allow an account to update the value on any account
+	Scopes []string `json:"scopes"`
+}
+
+// DefaultProfileSchema is used when the handler has no schema configured
+var DefaultProfileSchema = map[string]*ProfileField{
+	"display_name": {Type: "string", MaxLength: 100, Self: true, Scopes: []string{"admin"}},
+	"avatar_url":   {Type: "url", MaxLength: 2048, Self: true, Scopes: []string{"admin"}},
+}
+
+// Validate the value against the field definition
+func (f *ProfileField) Validate(value string) error {
+	if f.MaxLength > 0 && len(value) > f.MaxLength {
+		return fmt.Errorf("exceeds max length of %v", f.MaxLength)
+	}
+
+	switch f.Type {
+	case "", "string":
+	case "url":
+		if u, err := url.Parse(value); err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+			return fmt.Errorf("must be a valid url")
+		}
+	case "email":
+		if _, err := mail.ParseAddress(value); err != nil {
+			return fmt.Errorf("must be a valid email address")
+		}
+	case "int":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+	default:
+		return fmt.Errorf("has unknown type %v", f.Type)
+	}
+
+	if len(f.Pattern) > 0 {
+		re, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return fmt.Errorf("has an invalid pattern: %v", err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match the pattern %v", f.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// canUpdate returns true if the account is permitted to update the field on the account with
+// the given id
+func (f *ProfileField) canUpdate(acc *auth.Account, id string) bool {
+	if acc == nil {
+		return false
+	}
+	if f.Self && acc.ID == id {
+		return true
+	}
+	for _, s := range f.Scopes {
+		for _, as := range acc.Scopes {
+			if s == as {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// profileSchema returns the schema used to validate profiles
+func (a *Auth) profileSchema() map[string]*ProfileField {
+	if a.ProfileSchema == nil {
+		return DefaultProfileSchema
+	}
+	return a.ProfileSchema
+}
+
+// ReadProfile returns the profile attributes for an account
+func (a *Auth) ReadProfile(ctx context.Context, req *pb.ReadProfileRequest, rsp *pb.ReadProfileResponse) error {
+	// validate the request
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.Accounts.ReadProfile", "Missing ID")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.Accounts.ReadProfile", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.Accounts.ReadProfile", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.Accounts.ReadProfile", err.Error())
+	}
+
+	// check the account exists
+	key := strings.Join([]string{storePrefixAccounts, req.Options.Namespace, req.Id}, joinKey)
+	if _, err := store.Read(key); err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Accounts.ReadProfile", "Account not found with this ID")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Accounts.ReadProfile", "Unable to read from store: %v", err)
+	}
+
+	profile, err := a.readProfile(req.Options.Namespace, req.Id)
+	if err != nil {
+		return errors.InternalServerError("auth.Accounts.ReadProfile", "Unable to read profile: %v", err)
+	}
+
+	rsp.Profile = profile
+	return nil
+}
+
+// UpdateProfile validates and sets profile attributes on an account
+func (a *Auth) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest, rsp *pb.UpdateProfileResponse) error {
+	// validate the request
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.Accounts.UpdateProfile", "Missing ID")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.Accounts.UpdateProfile", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.Accounts.UpdateProfile", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.Accounts.UpdateProfile", err.Error())
+	}
+
+	// check the account exists
+	key := strings.Join([]string{storePrefixAccounts, req.Options.Namespace, req.Id}, joinKey)
+	if _, err := store.Read(key); err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Accounts.UpdateProfile", "Account not found with this ID")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Accounts.UpdateProfile", "Unable to read from store: %v", err)
+	}
+
+	profile, err := a.readProfile(req.Options.Namespace, req.Id)
+	if err != nil {
+		return errors.InternalServerError("auth.Accounts.UpdateProfile", "Unable to read profile: %v", err)
+	}
+
+	// validate each of the attributes and check the caller has permission to set them
+	acc, _ := auth.AccountFromContext(ctx)
+	schema := a.profileSchema()
+	for k, v := range req.Profile {
+		field, ok := schema[k]
+		if !ok {
+			return errors.BadRequest("auth.Accounts.UpdateProfile", "Unknown profile attribute %v", k)
+		}
+		if !field.canUpdate(acc, req.Id) {
+			return errors.Forbidden("auth.Accounts.UpdateProfile", "Not permitted to update profile attribute %v", k)
+		}
+		if len(v) == 0 {
+			delete(profile, k)
+			continue
+		}
+		if err := field.Validate(v); err != nil {
+			return errors.BadRequest("auth.Accounts.UpdateProfile", "Profile attribute %v %v", k, err)
+		}
+		profile[k] = v
+	}
+
+	// check the required attributes are set once the update is applied
+	if name := missingRequired(schema, profile); len(name) > 0 {
+		return errors.BadRequest("auth.Accounts.UpdateProfile", "Profile attribute %v is required", name)
+	}
+
+	// marshal to json
+	bytes, err := json.Marshal(profile)
+	if err != nil {
+		return errors.InternalServerError("auth.Accounts.UpdateProfile", "Unable to marshal json: %v", err)
+	}
+
+	// write to the store
+	profileKey := strings.Join([]string{storePrefixProfiles, req.Options.Namespace, req.Id}, joinKey)
+	if err := store.Write(&gostore.Record{Key: profileKey, Value: bytes}); err != nil {
+		return errors.InternalServerError("auth.Accounts.UpdateProfile", "Unable to write profile to store: %v", err)
+	}
+
+	rsp.Profile = profile
+	return nil
+}
+
+// missingRequired returns the first required attribute of the schema which isn't set on the
+// profile, or a blank string if they're all set
+func missingRequired(schema map[string]*ProfileField, profile map[string]string) string {
+	var names []string
+	for name, f := range schema {
+		if f.Required {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if len(profile[name]) == 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+// readProfile loads the profile for an account, returning an empty profile if none is set
+func (a *Auth) readProfile(ns, id string) (map[string]string, error) {
+	key := strings.Join([]string{storePrefixProfiles, ns, id}, joinKey)
+	recs, err := store.Read(key)
+	if err == gostore.ErrNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	profile := map[string]string{}
+	if err := json.Unmarshal(recs[0].Value, &profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
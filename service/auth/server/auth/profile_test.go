@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestProfileFieldValidate(t *testing.T) {
+	tt := []struct {
+		Field ProfileField
+		Value string
+		Valid bool
+	}{
+		{ProfileField{}, "anything", true},
+		{ProfileField{MaxLength: 3}, "abcd", false},
+		{ProfileField{Type: "url"}, "https://micro.mu/avatar.png", true},
+		{ProfileField{Type: "url"}, "avatar.png", false},
+		{ProfileField{Type: "email"}, "john@micro.mu", true},
+		{ProfileField{Type: "email"}, "john", false},
+		{ProfileField{Type: "int"}, "42", true},
+		{ProfileField{Type: "int"}, "4.2", false},
+		{ProfileField{Type: "bool"}, "true", true},
+		{ProfileField{Type: "bool"}, "yes", false},
+		{ProfileField{Type: "date"}, "2020-01-01", false},
+		{ProfileField{Pattern: "^[a-z]+$"}, "john", true},
+		{ProfileField{Pattern: "^[a-z]+$"}, "John", false},
+	}
+
+	for _, tc := range tt {
+		if err := tc.Field.Validate(tc.Value); (err == nil) != tc.Valid {
+			t.Errorf("Expected %v to be valid for %+v: %v, got %v", tc.Value, tc.Field, tc.Valid, err)
+		}
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Auth{ProfileSchema: map[string]*ProfileField{
+		"display_name": {Self: true},
+		"email":        {Type: "email", Required: true, Self: true},
+		"role":         {Scopes: []string{"admin"}},
+	}}
+	a.Init()
+	if err := a.createAccount(&auth.Account{ID: "john", Type: "user", Issuer: "micro", Secret: "password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	john := &auth.Account{ID: "john", Issuer: "micro"}
+	jane := &auth.Account{ID: "jane", Issuer: "micro"}
+	admin := &auth.Account{ID: "admin", Issuer: "micro", Scopes: []string{"admin"}}
+
+	update := func(caller *auth.Account, id string, profile map[string]string) int32 {
+		ctx := auth.ContextWithAccount(context.TODO(), caller)
+		rsp := &pb.UpdateProfileResponse{}
+		if err := a.UpdateProfile(ctx, &pb.UpdateProfileRequest{Id: id, Profile: profile}, rsp); err != nil {
+			return errors.Parse(err).Code
+		}
+		return 200
+	}
+
+	tt := []struct {
+		Name    string
+		Caller  *auth.Account
+		ID      string
+		Profile map[string]string
+		Code    int32
+	}{
+		{"RequiredMissing", john, "john", map[string]string{"display_name": "John"}, 400},
+		{"RequiredSet", john, "john", map[string]string{"display_name": "John", "email": "john@micro.mu"}, 200},
+		{"RequiredRemoved", john, "john", map[string]string{"email": ""}, 400},
+		{"Optional", john, "john", map[string]string{"display_name": "Johnny"}, 200},
+		{"OptionalRemoved", john, "john", map[string]string{"display_name": ""}, 200},
+		{"Invalid", john, "john", map[string]string{"email": "john"}, 400},
+		{"Unknown", john, "john", map[string]string{"age": "42"}, 400},
+		{"OtherAccount", jane, "john", map[string]string{"display_name": "Jane"}, 403},
+		{"Scope", john, "john", map[string]string{"role": "owner"}, 403},
+		{"Admin", admin, "john", map[string]string{"role": "owner"}, 200},
+		{"NotFound", admin, "missing", map[string]string{"role": "owner"}, 404},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if code := update(tc.Caller, tc.ID, tc.Profile); code != tc.Code {
+				t.Errorf("Expected %v, got %v", tc.Code, code)
+			}
+		})
+	}
+
+	// the profile keeps the attributes of the successful updates
+	ctx := auth.ContextWithAccount(context.TODO(), john)
+	rsp := &pb.ReadProfileResponse{}
+	if err := a.ReadProfile(ctx, &pb.ReadProfileRequest{Id: "john"}, rsp); err != nil {
+		t.Fatalf("Unexpected error reading profile: %v", err)
+	}
+	if len(rsp.Profile) != 2 || rsp.Profile["email"] != "john@micro.mu" || rsp.Profile["role"] != "owner" {
+		t.Errorf("Unexpected profile %v", rsp.Profile)
+	}
+}
@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	storePrefixKnown = "known"
+	storePrefixAudit = "audit"
+
+	// DeviceKey is the header clients identify the device they're running on with, the
+	// user agent is used if it's not set. The device is asserted by the client so only ever
+	// adds to the risk of issuing a token.
+	DeviceKey = "Micro-Device"
+)
+
+// Risk signals raised by DefaultRiskEvaluator
+const (
+	SignalNewDevice      = "new_device"
+	SignalNewIP          = "new_ip"
+	SignalSensitiveScope = "sensitive_scope"
+	SignalFirstUse       = "first_use"
+)
+
+var (
+	// DefaultAuditRetention is how long the entries in the audit log are kept
+	DefaultAuditRetention = time.Hour * 24 * 90
+)
+
+// Attempt to obtain a token, which the risk of is evaluated
+type Attempt struct {
+	Namespace string
+	Account   *auth.Account
+	// IP and Device the token was requested from
+	IP     string
+	Device string
+	// KnownIP and KnownDevice are true if the account has been issued a token from them before,
+	// they're never known if the IP or device couldn't be determined
+	KnownIP     bool
+	KnownDevice bool
+	// FirstUse is true if the account has never been issued a token
+	FirstUse bool
+}
+
+// Risk of issuing a token
+type Risk struct {
+	// Signals which contributed to the risk, recorded in the audit log
+	Signals []string
+	// StepUp is true if the token can't be issued without step-up verification
+	StepUp bool
+}
+
+// RiskEvaluator assesses the risk of issuing a token
+type RiskEvaluator func(ctx context.Context, a *Attempt) (*Risk, error)
+
+// StepUpVerifier verifies the step-up verification provided with the request, e.g. an MFA
+// code, returning an error if it's not valid
+type StepUpVerifier func(ctx context.Context, ns string, acc *auth.Account, verification string) error
+
+// DefaultRiskEvaluator requires step-up verification for tokens with sensitive scopes which
+// are requested from a new device or IP. The first token issued to an account establishes
+// where it's used from, so the account can then enroll a second factor.
+func DefaultRiskEvaluator(sensitive []string) RiskEvaluator {
+	return func(ctx context.Context, a *Attempt) (*Risk, error) {
+		risk := &Risk{}
+		if a.FirstUse {
+			risk.Signals = append(risk.Signals, SignalFirstUse)
+		}
+		if !a.KnownDevice {
+			risk.Signals = append(risk.Signals, SignalNewDevice)
+		}
+		if !a.KnownIP {
+			risk.Signals = append(risk.Signals, SignalNewIP)
+		}
+
+		for _, s := range sensitive {
+			if hasScope(a.Account.Scopes, s) {
+				risk.Signals = append(risk.Signals, SignalSensitiveScope)
+				risk.StepUp = !a.FirstUse && (!a.KnownDevice || !a.KnownIP)
+				break
+			}
+		}
+		return risk, nil
+	}
+}
+
+// known are the IPs and devices an account has been issued tokens from
+type known struct {
+	IPs     []string `json:"ips"`
+	Devices []string `json:"devices"`
+}
+
+// AuditEntry records an attempt to obtain a token which the risk of was evaluated
+type AuditEntry struct {
+	Account   string   `json:"account"`
+	IP        string   `json:"ip"`
+	Device    string   `json:"device"`
+	Signals   []string `json:"signals"`
+	StepUp    bool     `json:"step_up"`
+	Verified  bool     `json:"verified"`
+	Issued    bool     `json:"issued"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// requestOrigin returns the IP and device the request was made from. The IP is the peer the
+// server received the request from, unless the peer is a trusted proxy in which case the
+// X-Forwarded-For header is walked back to the first address which isn't one.
+func requestOrigin(ctx context.Context, trusted []*net.IPNet) (string, string) {
+	var ip string
+	if remote, ok := metadata.Get(ctx, "Remote"); ok {
+		if host, _, err := net.SplitHostPort(remote); err == nil {
+			ip = host
+		} else {
+			ip = remote
+		}
+	}
+
+	if isTrustedProxy(trusted, ip) {
+		fwd, _ := metadata.Get(ctx, "X-Forwarded-For")
+		hops := strings.Split(fwd, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if len(hop) == 0 {
+				break
+			}
+			ip = hop
+			if !isTrustedProxy(trusted, hop) {
+				break
+			}
+		}
+	}
+
+	device, ok := metadata.Get(ctx, DeviceKey)
+	if !ok {
+		device, _ = metadata.Get(ctx, "User-Agent")
+	}
+	return ip, device
+}
+
+// isTrustedProxy returns true if the IP is in one of the trusted networks
+func isTrustedProxy(trusted []*net.IPNet, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// readKnown returns the IPs and devices the account has been issued tokens from
+func readKnown(ns, id string) (*known, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixKnown, ns, id}, joinKey))
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return &known{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var k *known
+	if err := json.Unmarshal(recs[0].Value, &k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// writeKnown records the IP and device as known for the account
+func writeKnown(ns, id string, k *known, ip, device string) error {
+	if len(ip) > 0 && !contains(k.IPs, ip) {
+		k.IPs = append(k.IPs, ip)
+	}
+	if len(device) > 0 && !contains(k.Devices, device) {
+		k.Devices = append(k.Devices, device)
+	}
+
+	bytes, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{
+		Key:   strings.Join([]string{storePrefixKnown, ns, id}, joinKey),
+		Value: bytes,
+	})
+}
+
+// writeAudit records the entry in the audit log of the namespace
+func writeAudit(ns string, entry *AuditEntry) {
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		logger.Errorf("Error marshaling audit entry for %v: %v", entry.Account, err)
+		return
+	}
+
+	// timestamps are zero padded so the keys sort in order
+	key := strings.Join([]string{storePrefixAudit, ns, fmt.Sprintf("%020d", time.Now().UnixNano())}, joinKey)
+	if err := store.Write(&gostore.Record{Key: key, Value: bytes, Expiry: DefaultAuditRetention}); err != nil {
+		logger.Errorf("Error writing audit entry for %v: %v", entry.Account, err)
+	}
+}
+
+// evaluateRisk of issuing a token to the account, returning an error if step-up verification
+// is required and wasn't provided. The attempt is recorded in the audit log.
+func (a *Auth) evaluateRisk(ctx context.Context, ns string, acc *auth.Account, verification string) error {
+	if a.Risk == nil {
+		return nil
+	}
+
+	ip, device := requestOrigin(ctx, a.TrustedProxies)
+	k, err := readKnown(ns, acc.ID)
+	if err != nil {
+		return errors.InternalServerError("auth.Auth.Token", "Unable to read known devices: %v", err)
+	}
+
+	risk, err := a.Risk(ctx, &Attempt{
+		Namespace:   ns,
+		Account:     acc,
+		IP:          ip,
+		Device:      device,
+		KnownIP:     len(ip) > 0 && contains(k.IPs, ip),
+		KnownDevice: len(device) > 0 && contains(k.Devices, device),
+		FirstUse:    len(k.IPs) == 0 && len(k.Devices) == 0,
+	})
+	if err != nil {
+		return errors.InternalServerError("auth.Auth.Token", "Unable to evaluate risk: %v", err)
+	}
+
+	entry := &AuditEntry{
+		Account:   acc.ID,
+		IP:        ip,
+		Device:    device,
+		Signals:   risk.Signals,
+		StepUp:    risk.StepUp,
+		Timestamp: time.Now().Unix(),
+	}
+	defer writeAudit(ns, entry)
+
+	if risk.StepUp {
+		if len(verification) == 0 || a.StepUp == nil {
+			return errors.Unauthorized("auth.Auth.Token", "Step-up verification required: %v", strings.Join(risk.Signals, ", "))
+		}
+		if err := a.StepUp(ctx, ns, acc, verification); err != nil {
+			return errors.Unauthorized("auth.Auth.Token", "Step-up verification failed: %v", err)
+		}
+		entry.Verified = true
+	}
+	entry.Issued = true
+
+	// only record the origin once the token can be issued from it
+	if err := writeKnown(ns, acc.ID, k, ip, device); err != nil {
+		logger.Errorf("Error writing known devices of %v: %v", acc.ID, err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
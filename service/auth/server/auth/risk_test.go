@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	gostore "github.com/micro/go-micro/v3/store"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestEvaluateRisk(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return time.Unix(1600000000, 0) }
+
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	acc := &auth.Account{ID: "john", Scopes: []string{"admin"}}
+	a := &Auth{Risk: DefaultRiskEvaluator([]string{"admin"}), StepUp: VerifyTOTP, TrustedProxies: []*net.IPNet{proxies}}
+
+	from := func(remote, forwarded, device string) context.Context {
+		md := metadata.Metadata{"Remote": remote + ":1234", DeviceKey: device}
+		if len(forwarded) > 0 {
+			md["X-Forwarded-For"] = forwarded
+		}
+		return metadata.NewContext(context.TODO(), md)
+	}
+
+	// the first token establishes where the account is used from
+	if err := a.evaluateRisk(from("1.1.1.1", "", "laptop"), "micro", acc, ""); err != nil {
+		t.Fatalf("Expected the first token to be issued, got %v", err)
+	}
+	if err := a.evaluateRisk(from("1.1.1.1", "", "laptop"), "micro", acc, ""); err != nil {
+		t.Fatalf("Expected a known device not to require verification, got %v", err)
+	}
+
+	// a new IP must be verified with the second factor, which must be enrolled
+	if err := a.evaluateRisk(from("2.2.2.2", "", "laptop"), "micro", acc, ""); err == nil {
+		t.Fatalf("Expected step-up verification to be required")
+	}
+	if err := a.evaluateRisk(from("2.2.2.2", "", "laptop"), "micro", acc, "123456"); err == nil {
+		t.Fatalf("Expected verification to fail without a second factor")
+	}
+	secret := []byte("12345678901234567890")
+	if err := writeMFA("micro", "john", &mfa{Secret: totpEncoding.EncodeToString(secret)}); err != nil {
+		t.Fatal(err)
+	}
+	code := totpCode(secret, uint64(now().Unix()/totpPeriod))
+	if err := a.evaluateRisk(from("2.2.2.2", "", "laptop"), "micro", acc, code); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := a.evaluateRisk(from("4.4.4.4", "", "laptop"), "micro", acc, code); err == nil {
+		t.Fatalf("Expected a code not to be reusable")
+	}
+
+	// the forwarded IP is only trusted from a proxy, which can't be spoofed by the client
+	if err := a.evaluateRisk(from("3.3.3.3", "1.1.1.1", "laptop"), "micro", acc, ""); err == nil {
+		t.Fatalf("Expected the forwarded IP not to be trusted from a client")
+	}
+	if err := a.evaluateRisk(from("10.0.0.1", "9.9.9.9, 1.1.1.1, 10.0.0.2", "laptop"), "micro", acc, ""); err != nil {
+		t.Fatalf("Expected the IP forwarded by the proxies to be known, got %v", err)
+	}
+
+	// an unknown origin is risky
+	if err := a.evaluateRisk(context.TODO(), "micro", acc, ""); err == nil {
+		t.Fatalf("Expected an unknown origin to require verification")
+	}
+
+	// accounts without sensitive scopes don't need to be verified
+	user := &auth.Account{ID: "jane", Scopes: []string{"user"}}
+	if err := a.evaluateRisk(from("3.3.3.3", "", "phone"), "micro", user, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// each attempt is audited
+	keys, err := store.List(gostore.ListPrefix(storePrefixAudit + joinKey + "micro" + joinKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keys)
+	if len(keys) != 10 {
+		t.Fatalf("Expected 10 audit entries, got %v", len(keys))
+	}
+	recs, err := store.Read(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry AuditEntry
+	if err := json.Unmarshal(recs[0].Value, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Issued || entry.StepUp || len(entry.Signals) != 4 || entry.Signals[0] != SignalFirstUse {
+		t.Fatalf("Expected the first attempt to be recorded with its signals, got %+v", entry)
+	}
+}
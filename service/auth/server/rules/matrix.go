@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// Matrix returns the access each account in the namespace, and the public, has to the
+// endpoints of the services, as decided by the rules. Only admins can read it since it lists the
+// scopes of every account.
+func (r *Rules) Matrix(ctx context.Context, req *pb.MatrixRequest, rsp *pb.MatrixResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+
+	// listing the rules authorizes the request
+	var list pb.ListResponse
+	if err := r.List(ctx, &pb.ListRequest{Options: req.Options}, &list); err != nil {
+		return err
+	}
+	if caller, ok := auth.AccountFromContext(ctx); !ok || !include(caller.Scopes, "admin") {
+		return errors.Forbidden("auth.Rules.Matrix", "Only admins can read the matrix")
+	}
+	if r.Accounts == nil {
+		return errors.InternalServerError("auth.Rules.Matrix", "Accounts not configured")
+	}
+
+	rules := make([]*auth.Rule, 0, len(list.Rules))
+	for _, rule := range list.Rules {
+		rules = append(rules, deserializeRule(rule))
+	}
+
+	accounts, err := r.listAccounts(ctx, req.Options)
+	if err != nil {
+		return err
+	}
+	endpoints, err := serviceEndpoints(ns, req.Services)
+	if err != nil {
+		return errors.InternalServerError("auth.Rules.Matrix", "Unable to read services: %v", err)
+	}
+
+	// the public, i.e. calls without an account, are the first identity
+	identities := append([]*auth.Account{nil}, accounts...)
+
+	services := make([]string, 0, len(endpoints))
+	for srv := range endpoints {
+		services = append(services, srv)
+	}
+	sort.Strings(services)
+
+	for _, acc := range identities {
+		for _, srv := range services {
+			for _, ep := range endpoints[srv] {
+				res := &auth.Resource{Type: "service", Name: srv, Endpoint: ep}
+				entry := &pb.MatrixEntry{
+					Service:  srv,
+					Endpoint: ep,
+					Granted:  auth.VerifyAccess(rules, acc, res) == nil,
+				}
+				if acc != nil {
+					entry.Identity = acc.ID
+					entry.Scopes = acc.Scopes
+				}
+				if rule := decidingRule(rules, acc, res); rule != nil {
+					entry.Rule = rule.ID
+				}
+				rsp.Entries = append(rsp.Entries, entry)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decidingRule returns the rule which decides the access of the account to the resource, nil if
+// no rule applies. Rules are tried in the order auth.VerifyAccess evaluates them, and a rule
+// applies if the verifier would grant access were the rule to grant it.
+func decidingRule(rules []*auth.Rule, acc *auth.Account, res *auth.Resource) *auth.Rule {
+	sorted := make([]*auth.Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority > sorted[j].Priority })
+
+	for _, rule := range sorted {
+		if rule.Resource == nil {
+			continue
+		}
+		granted := *rule
+		granted.Access = auth.AccessGranted
+		if auth.VerifyAccess([]*auth.Rule{&granted}, acc, res) == nil {
+			return rule
+		}
+	}
+	return nil
+}
+
+func include(slice []string, val string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, val) {
+			return true
+		}
+	}
+	return false
+}
+
+func deserializeRule(r *pb.Rule) *auth.Rule {
+	access := auth.AccessDenied
+	if r.Access == pb.Access_GRANTED {
+		access = auth.AccessGranted
+	}
+	rule := &auth.Rule{ID: r.Id, Scope: r.Scope, Access: access, Priority: r.Priority}
+	if r.Resource != nil {
+		rule.Resource = &auth.Resource{Type: r.Resource.Type, Name: r.Resource.Name, Endpoint: r.Resource.Endpoint}
+	}
+	return rule
+}
+
+// listAccounts returns the accounts in the namespace of the options, sorted by ID
+func (r *Rules) listAccounts(ctx context.Context, opts *pb.Options) ([]*auth.Account, error) {
+	var rsp pb.ListAccountsResponse
+	if err := r.Accounts.List(ctx, &pb.ListAccountsRequest{Options: opts}, &rsp); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*auth.Account, 0, len(rsp.Accounts))
+	for _, a := range rsp.Accounts {
+		accounts = append(accounts, &auth.Account{ID: a.Id, Scopes: a.Scopes})
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts, nil
+}
+
+// serviceEndpoints returns the sorted endpoints of the services registered in the namespace,
+// or of the services given. Services without endpoints have the wildcard endpoint.
+func serviceEndpoints(ns string, names []string) (map[string][]string, error) {
+	if len(names) == 0 {
+		srvs, err := registry.ListServices(goregistry.ListDomain(ns))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range srvs {
+			names = append(names, s.Name)
+		}
+	}
+
+	endpoints := make(map[string][]string, len(names))
+	for _, name := range names {
+		srvs, err := registry.GetService(name, goregistry.GetDomain(ns))
+		if err != nil && !util.IsNotFound(err) {
+			return nil, err
+		}
+
+		seen := make(map[string]bool)
+		for _, s := range srvs {
+			for _, e := range s.Endpoints {
+				if !seen[e.Name] {
+					seen[e.Name] = true
+					endpoints[name] = append(endpoints[name], e.Name)
+				}
+			}
+		}
+		if len(endpoints[name]) == 0 {
+			endpoints[name] = []string{"*"}
+		}
+		sort.Strings(endpoints[name])
+	}
+	return endpoints, nil
+}
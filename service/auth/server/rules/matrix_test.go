@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestMatrix(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	reg := memory.NewRegistry()
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	ns := namespace.DefaultNamespace
+	reg.Register(&goregistry.Service{
+		Name:      "foo",
+		Nodes:     []*goregistry.Node{{Id: "foo-1", Address: "127.0.0.1:1"}},
+		Endpoints: []*goregistry.Endpoint{{Name: "Foo.Read"}, {Name: "Foo.Write"}},
+	}, goregistry.RegisterDomain(ns))
+
+	r := &Rules{Accounts: testAccounts{
+		{Id: "billing", Scopes: []string{"service"}, Issuer: ns},
+		{Id: "admin", Scopes: []string{"admin"}, Issuer: ns},
+	}}
+	for _, rule := range []*pb.Rule{
+		{Id: "deny-foo", Scope: "", Access: pb.Access_DENIED, Priority: 1, Resource: &pb.Resource{Type: "service", Name: "foo", Endpoint: "*"}},
+		{Id: "read-foo", Scope: "*", Access: pb.Access_GRANTED, Priority: 2, Resource: &pb.Resource{Type: "service", Name: "foo", Endpoint: "Foo.Read"}},
+		{Id: "admin-foo", Scope: "admin", Access: pb.Access_GRANTED, Priority: 2, Resource: &pb.Resource{Type: "service", Name: "foo", Endpoint: "*"}},
+	} {
+		if err := r.writeRule(rule, ns); err != nil {
+			t.Fatalf("Unexpected error writing rule: %v", err)
+		}
+	}
+
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "billing", Scopes: []string{"service"}, Issuer: ns})
+	if err := r.Matrix(ctx, &pb.MatrixRequest{}, &pb.MatrixResponse{}); err == nil {
+		t.Errorf("Expected the matrix to be forbidden to accounts which aren't admins")
+	}
+
+	ctx = auth.ContextWithAccount(context.Background(), &auth.Account{ID: "admin", Scopes: []string{"admin"}, Issuer: ns})
+	var rsp pb.MatrixResponse
+	if err := r.Matrix(ctx, &pb.MatrixRequest{}, &rsp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	access := make(map[string]*pb.MatrixEntry, len(rsp.Entries))
+	for _, e := range rsp.Entries {
+		access[e.Identity+" "+e.Endpoint] = e
+	}
+	tt := []struct {
+		Identity string
+		Endpoint string
+		Granted  bool
+		Rule     string
+	}{
+		{"", "Foo.Read", false, "deny-foo"},
+		{"billing", "Foo.Read", true, "read-foo"},
+		{"billing", "Foo.Write", false, "deny-foo"},
+		{"admin", "Foo.Write", true, "admin-foo"},
+	}
+	if len(rsp.Entries) != 6 {
+		t.Errorf("Expected 6 entries, got %v", len(rsp.Entries))
+	}
+	for _, tc := range tt {
+		e, ok := access[tc.Identity+" "+tc.Endpoint]
+		if !ok {
+			t.Errorf("Missing entry for %q %v", tc.Identity, tc.Endpoint)
+			continue
+		}
+		if e.Granted != tc.Granted || e.Rule != tc.Rule {
+			t.Errorf("Expected %q to be granted %v to %v by %v, got %v by %v", tc.Identity, tc.Granted, tc.Endpoint, tc.Rule, e.Granted, e.Rule)
+		}
+	}
+}
+
+// testAccounts lists the accounts, the other methods of the accounts API aren't used
+type testAccounts []*pb.Account
+
+func (a testAccounts) List(ctx context.Context, req *pb.ListAccountsRequest, rsp *pb.ListAccountsResponse) error {
+	rsp.Accounts = a
+	return nil
+}
+
+func (a testAccounts) Delete(context.Context, *pb.DeleteAccountRequest, *pb.DeleteAccountResponse) error {
+	return nil
+}
+
+func (a testAccounts) ChangeSecret(context.Context, *pb.ChangeSecretRequest, *pb.ChangeSecretResponse) error {
+	return nil
+}
+
+func (a testAccounts) ReadProfile(context.Context, *pb.ReadProfileRequest, *pb.ReadProfileResponse) error {
+	return nil
+}
+
+func (a testAccounts) UpdateProfile(context.Context, *pb.UpdateProfileRequest, *pb.UpdateProfileResponse) error {
+	return nil
+}
+
+func (a testAccounts) EnrollMFA(context.Context, *pb.EnrollMFARequest, *pb.EnrollMFAResponse) error {
+	return nil
+}
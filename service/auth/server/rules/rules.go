@@ -34,6 +34,8 @@ var defaultRule = &auth.Rule{
 // Rules processes RPC calls
 type Rules struct {
 	Options auth.Options
+	// Accounts lists the accounts the matrix reports the access of
+	Accounts pb.AccountsHandler
 
 	namespaces map[string]bool
 	sync.Mutex
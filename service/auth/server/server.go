@@ -1,12 +1,17 @@
 package server
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+
 	"github.com/micro/cli/v2"
 	"github.com/micro/go-micro/v3/auth"
 	"github.com/micro/go-micro/v3/store"
 	"github.com/micro/go-micro/v3/util/token"
 	"github.com/micro/go-micro/v3/util/token/jwt"
 	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/auth/federation"
 	pb "github.com/micro/micro/v3/service/auth/proto"
 	authHandler "github.com/micro/micro/v3/service/auth/server/auth"
 	rulesHandler "github.com/micro/micro/v3/service/auth/server/rules"
@@ -19,6 +24,27 @@ const (
 	address = ":8010"
 )
 
+var (
+	// Flags specific to the auth service
+	Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "profile_schema",
+			EnvVars: []string{"MICRO_AUTH_PROFILE_SCHEMA"},
+			Usage:   "Path to a JSON file defining additional account profile attributes",
+		},
+		&cli.StringSliceFlag{
+			Name:    "step_up_scopes",
+			EnvVars: []string{"MICRO_AUTH_STEP_UP_SCOPES"},
+			Usage:   "Scopes which require a TOTP code when a token is requested from a new device or IP",
+		},
+		&cli.StringSliceFlag{
+			Name:    "trusted_proxies",
+			EnvVars: []string{"MICRO_AUTH_TRUSTED_PROXIES"},
+			Usage:   "Networks of the gateways whose X-Forwarded-For header is trusted, e.g. 10.0.0.0/8",
+		},
+	}
+)
+
 // Run the auth service
 func Run(ctx *cli.Context) error {
 	srv := service.New(
@@ -27,8 +53,8 @@ func Run(ctx *cli.Context) error {
 	)
 
 	// setup the handlers
-	ruleH := &rulesHandler.Rules{}
 	authH := &authHandler.Auth{}
+	ruleH := &rulesHandler.Rules{Accounts: authH}
 
 	// setup the auth handler to use JWTs
 	pubKey := ctx.String("auth_public_key")
@@ -40,6 +66,37 @@ func Run(ctx *cli.Context) error {
 		)
 	}
 
+	// load any additional profile attributes
+	if path := ctx.String("profile_schema"); len(path) > 0 {
+		schema, err := loadProfileSchema(path)
+		if err != nil {
+			log.Fatalf("Error loading profile schema: %v", err)
+		}
+		authH.ProfileSchema = schema
+	}
+
+	// require step-up verification for tokens with sensitive scopes
+	if scopes := ctx.StringSlice("step_up_scopes"); len(scopes) > 0 {
+		authH.Risk = authHandler.DefaultRiskEvaluator(scopes)
+		authH.StepUp = authHandler.VerifyTOTP
+	}
+	for _, cidr := range ctx.StringSlice("trusted_proxies") {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatalf("Invalid trusted proxy network %v: %v", cidr, err)
+		}
+		authH.TrustedProxies = append(authH.TrustedProxies, n)
+	}
+
+	// trust the tokens issued by peer clusters
+	if path := ctx.String("auth_federation"); len(path) > 0 {
+		f, err := federation.Load(path)
+		if err != nil {
+			log.Fatalf("Error loading federation peers: %v", err)
+		}
+		authH.Federation = f
+	}
+
 	// set the handlers store
 	mustore.DefaultStore.Init(store.Table("auth"))
 	authH.Init(auth.Store(mustore.DefaultStore))
@@ -49,6 +106,8 @@ func Run(ctx *cli.Context) error {
 	pb.RegisterAuthHandler(srv.Server(), authH)
 	pb.RegisterRulesHandler(srv.Server(), ruleH)
 	pb.RegisterAccountsHandler(srv.Server(), authH)
+	pb.RegisterGrantsHandler(srv.Server(), &authHandler.Grants{})
+	pb.RegisterDomainsHandler(srv.Server(), &authHandler.Domains{})
 
 	// run service
 	if err := srv.Run(); err != nil {
@@ -56,3 +115,26 @@ func Run(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+// loadProfileSchema reads the profile attributes from the file and merges
+// them with the default schema
+func loadProfileSchema(path string) (map[string]*authHandler.ProfileField, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]*authHandler.ProfileField
+	if err := json.Unmarshal(bytes, &fields); err != nil {
+		return nil, err
+	}
+
+	schema := make(map[string]*authHandler.ProfileField, len(fields))
+	for k, v := range authHandler.DefaultProfileSchema {
+		schema[k] = v
+	}
+	for k, v := range fields {
+		schema[k] = v
+	}
+	return schema, nil
+}
@@ -151,6 +151,7 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "auth",
 		Command: auth.Run,
+		Flags:   auth.Flags,
 	},
 	{
 		Name:    "broker",
@@ -164,6 +165,7 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "events",
 		Command: events.Run,
+		Flags:   events.Flags,
 	},
 	{
 		Name:    "health",
@@ -183,6 +185,7 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "registry",
 		Command: registry.Run,
+		Flags:   registry.Flags,
 	},
 	{
 		Name:    "router",
@@ -197,6 +200,7 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "store",
 		Command: store.Run,
+		Flags:   store.Flags,
 	},
 }
 
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/micro/go-micro/v3/client"
+)
+
+// Future is a call made with CallAsync. The response passed to CallAsync must not be read
+// until the call is done.
+type Future struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	err    error
+}
+
+// Done returns a channel which is closed once the call returns
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait for the call to return, returning its error
+func (f *Future) Wait() error {
+	<-f.done
+	return f.err
+}
+
+// Err returns the error of the call, nil if it succeeded or hasn't returned yet
+func (f *Future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Cancel the call. The call returns with the error of the cancelled context if it hadn't
+// already returned.
+func (f *Future) Cancel() {
+	f.cancel()
+}
+
+// CallAsync performs a request in the background, returning a future to wait for it with,
+// e.g. to make calls in parallel:
+//
+//	f1 := client.CallAsync(ctx, req1, rsp1)
+//	f2 := client.CallAsync(ctx, req2, rsp2)
+//	if err := client.WaitAll(f1, f2); err != nil {
+//		...
+//	}
+func CallAsync(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) *Future {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future{done: make(chan struct{}), cancel: cancel}
+
+	go func() {
+		defer cancel()
+		f.err = DefaultClient.Call(ctx, req, rsp, opts...)
+		close(f.done)
+	}()
+
+	return f
+}
+
+// WaitAll waits for every call to return, returning the first error in the order the futures
+// are passed. Once a call fails the rest are cancelled.
+func WaitAll(fs ...*Future) error {
+	var once sync.Once
+	var wg sync.WaitGroup
+	failed := make(chan struct{})
+
+	for _, f := range fs {
+		wg.Add(1)
+		go func(f *Future) {
+			defer wg.Done()
+			if f.Wait() != nil {
+				once.Do(func() { close(failed) })
+			}
+		}(f)
+		go func(f *Future) {
+			select {
+			case <-failed:
+				f.Cancel()
+			case <-f.done:
+			}
+		}(f)
+	}
+	wg.Wait()
+	once.Do(func() { close(failed) })
+
+	for _, f := range fs {
+		if f.err != nil {
+			return f.err
+		}
+	}
+	return nil
+}
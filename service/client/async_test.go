@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+)
+
+// slowClient fails calls to the fail endpoint and blocks other calls until they're cancelled
+type slowClient struct {
+	client.Client
+}
+
+func (s *slowClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if req.Endpoint() == "Foo.Fail" {
+		return errors.New("failed")
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCallAsync(t *testing.T) {
+	defer func(c client.Client) { DefaultClient = c }(DefaultClient)
+	DefaultClient = &slowClient{Client: mucp.NewClient()}
+
+	f := CallAsync(context.TODO(), NewRequest("foo", "Foo.Slow", nil), nil)
+	select {
+	case <-f.Done():
+		t.Fatalf("Expected the call to be in progress")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if f.Err() != nil {
+		t.Fatalf("Expected no error before the call returns")
+	}
+
+	f.Cancel()
+	if err := f.Wait(); err != context.Canceled {
+		t.Fatalf("Expected the call to be cancelled, got %v", err)
+	}
+
+	// a failed call cancels the rest
+	slow := CallAsync(context.TODO(), NewRequest("foo", "Foo.Slow", nil), nil)
+	fail := CallAsync(context.TODO(), NewRequest("foo", "Foo.Fail", nil), nil)
+	if err := WaitAll(slow, fail); err != context.Canceled {
+		t.Fatalf("Expected the error of the first call, got %v", err)
+	}
+	if fail.Err() == nil {
+		t.Fatalf("Expected the second call to fail")
+	}
+}
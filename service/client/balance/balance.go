@@ -0,0 +1,173 @@
+// Package balance provides a client which selects the node a call is sent to by weight or by
+// a key, when the call is made with the Weighted or Sticky option. Weighted calls are spread
+// across the nodes in proportion to the weights they advertise in the registry. Sticky calls
+// with the same key, e.g. a user ID, are sent to the same node using consistent hashing, so
+// the node's caches stay warm and only the keys of a node which leaves move elsewhere.
+//
+// The call is sent to the node selected and, if it can't be reached, to the next node in the
+// order of the strategy. Calls with an address set aren't balanced.
+package balance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// DefaultTTL is how long the weights of the nodes of a service are cached for
+var DefaultTTL = time.Minute
+
+type strategyKey struct{}
+
+// strategy of a call
+type strategy struct {
+	weighted bool
+	key      string
+}
+
+// Weighted sends the call to a node selected at random in proportion to the weights of the
+// nodes
+func Weighted() client.CallOption {
+	return withStrategy(strategy{weighted: true})
+}
+
+// Sticky sends the calls with the key to the same node, e.g. the calls for a user
+func Sticky(key string) client.CallOption {
+	return withStrategy(strategy{key: key})
+}
+
+func withStrategy(s strategy) client.CallOption {
+	return func(o *client.CallOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, strategyKey{}, s)
+	}
+}
+
+// strategyFromOptions returns the strategy set on the call options, false if none was set
+func strategyFromOptions(o client.CallOptions) (strategy, bool) {
+	if o.Context == nil {
+		return strategy{}, false
+	}
+	s, ok := o.Context.Value(strategyKey{}).(strategy)
+	return s, ok
+}
+
+// weights of the nodes of a service, keyed by address
+type weights struct {
+	nodes   map[string]int
+	expires time.Time
+}
+
+type balanceClient struct {
+	client.Client
+
+	sync.RWMutex
+	weights map[string]weights
+}
+
+// NewClient returns a client which balances the calls made with the Weighted or Sticky option
+func NewClient(c client.Client) client.Client {
+	return &balanceClient{Client: c, weights: make(map[string]weights)}
+}
+
+func (b *balanceClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	addrs := b.order(ctx, req, opts)
+	if len(addrs) == 0 {
+		return b.Client.Call(ctx, req, rsp, opts...)
+	}
+
+	err := b.Client.Call(ctx, req, rsp, append(opts, client.WithAddress(addrs[0]))...)
+	if err == nil || len(addrs) == 1 || !unreachable(err) {
+		return err
+	}
+
+	logger.Debugf("Node %v of %v unreachable, calling %v: %v", addrs[0], req.Service(), addrs[1], err)
+	return b.Client.Call(ctx, req, rsp, append(opts, client.WithAddress(addrs[1]))...)
+}
+
+func (b *balanceClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	addrs := b.order(ctx, req, opts)
+	if len(addrs) == 0 {
+		return b.Client.Stream(ctx, req, opts...)
+	}
+	return b.Client.Stream(ctx, req, append(opts, client.WithAddress(addrs[0]))...)
+}
+
+// order returns the addresses of the service in the order the call should try them, nil if
+// the call isn't balanced
+func (b *balanceClient) order(ctx context.Context, req client.Request, opts []client.CallOption) []string {
+	options := b.Client.Options().CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	s, ok := strategyFromOptions(options)
+	if !ok || len(options.Address) > 0 {
+		return nil
+	}
+
+	lookup := b.Client.Options().Lookup
+	if lookup == nil {
+		return nil
+	}
+	addrs, err := lookup(ctx, req, options)
+	if err != nil || len(addrs) == 0 {
+		return nil
+	}
+
+	if s.weighted {
+		return weightedOrder(addrs, b.nodeWeights(req.Service()))
+	}
+	return newRing(addrs).order(s.key)
+}
+
+// nodeWeights returns the weights of the nodes of the service
+func (b *balanceClient) nodeWeights(service string) map[string]int {
+	b.RLock()
+	w, ok := b.weights[service]
+	b.RUnlock()
+	if ok && time.Now().Before(w.expires) {
+		return w.nodes
+	}
+
+	nodes := make(map[string]int)
+	if registry.DefaultRegistry != nil {
+		if srvs, err := registry.DefaultRegistry.GetService(service); err == nil {
+			for _, s := range srvs {
+				for _, n := range s.Nodes {
+					nodes[n.Address] = util.Weight(n)
+				}
+			}
+		}
+	}
+
+	b.Lock()
+	b.weights[service] = weights{nodes: nodes, expires: time.Now().Add(DefaultTTL)}
+	b.Unlock()
+	return nodes
+}
+
+// unreachable returns true if the error indicates the node couldn't handle the call, rather
+// than the call itself failing
+func unreachable(err error) bool {
+	verr := errors.Parse(err)
+	if verr == nil {
+		return true
+	}
+	switch verr.Code {
+	case 408, 502, 503, 504:
+		return true
+	case 500:
+		// the client returns transport errors as internal server errors
+		return verr.Id == "go.micro.client"
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package balance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// testClient records the address each call is sent to, failing the calls to the down nodes
+type testClient struct {
+	client.Client
+	addrs []string
+	down  map[string]bool
+	calls []string
+}
+
+func (t *testClient) Options() client.Options {
+	opts := t.Client.Options()
+	opts.Lookup = func(ctx context.Context, req client.Request, o client.CallOptions) ([]string, error) {
+		if len(o.Address) > 0 {
+			return o.Address, nil
+		}
+		return t.addrs, nil
+	}
+	return opts
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	addr := ""
+	if len(options.Address) > 0 {
+		addr = options.Address[0]
+	}
+	t.calls = append(t.calls, addr)
+	if t.down[addr] {
+		return errors.ServiceUnavailable("foo", "unavailable")
+	}
+	return nil
+}
+
+func TestSticky(t *testing.T) {
+	tc := &testClient{Client: mucp.NewClient(), addrs: []string{"a", "b", "c"}}
+	c := NewClient(tc)
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+
+	// calls with the same key go to the same node
+	owners := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("user-%v", i)
+		for j := 0; j < 3; j++ {
+			if err := c.Call(context.TODO(), req, nil, Sticky(key)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			addr := tc.calls[len(tc.calls)-1]
+			if o, ok := owners[key]; ok && o != addr {
+				t.Fatalf("Expected %v to be sent to %v, got %v", key, o, addr)
+			}
+			owners[key] = addr
+		}
+	}
+
+	// only the keys of a node which leaves move
+	tc.addrs = []string{"a", "b"}
+	for key, owner := range owners {
+		if err := c.Call(context.TODO(), req, nil, Sticky(key)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if addr := tc.calls[len(tc.calls)-1]; owner != "c" && addr != owner {
+			t.Errorf("Expected %v to stay on %v, moved to %v", key, owner, addr)
+		}
+	}
+
+	// calls to an unreachable node go to the next node on the ring
+	tc.down = map[string]bool{owners["user-1"]: true}
+	tc.addrs = []string{"a", "b", "c"}
+	tc.calls = nil
+	if err := c.Call(context.TODO(), req, nil, Sticky("user-1")); err != nil {
+		t.Fatalf("Expected the call to fall back to another node: %v", err)
+	}
+	if len(tc.calls) != 2 || tc.calls[1] == owners["user-1"] {
+		t.Errorf("Unexpected calls %v", tc.calls)
+	}
+}
+
+func TestWeightedOrder(t *testing.T) {
+	counts := make(map[string]int)
+	weights := map[string]int{"a": 9, "b": 1, "c": 0}
+	for i := 0; i < 1000; i++ {
+		order := weightedOrder([]string{"a", "b", "c"}, weights)
+		if len(order) != 3 || order[2] != "c" {
+			t.Fatalf("Expected the node without weight to be last, got %v", order)
+		}
+		counts[order[0]]++
+	}
+	if counts["a"] < 800 || counts["b"] == 0 {
+		t.Errorf("Expected the calls to be spread by weight, got %v", counts)
+	}
+}
@@ -0,0 +1,87 @@
+package balance
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// replicas is the number of points each node has on the ring, so the keys are spread evenly
+const replicas = 100
+
+// weightedOrder returns the addresses in a random order where each address is picked ahead of
+// the others in proportion to its weight. Addresses without a weight have the default weight,
+// addresses with a zero weight are only picked when no other is left.
+func weightedOrder(addrs []string, weights map[string]int) []string {
+	left := append([]string{}, addrs...)
+	order := make([]string, 0, len(addrs))
+
+	for len(left) > 0 {
+		total := 0
+		for _, a := range left {
+			total += weight(a, weights)
+		}
+
+		i := 0
+		if total > 0 {
+			n := rand.Intn(total)
+			for ; i < len(left); i++ {
+				if n -= weight(left[i], weights); n < 0 {
+					break
+				}
+			}
+		} else {
+			i = rand.Intn(len(left))
+		}
+
+		order = append(order, left[i])
+		left = append(left[:i], left[i+1:]...)
+	}
+	return order
+}
+
+func weight(addr string, weights map[string]int) int {
+	if w, ok := weights[addr]; ok {
+		return w
+	}
+	return 1
+}
+
+// ring of addresses for consistent hashing
+type ring struct {
+	nodes  int
+	points []uint32
+	addrs  map[uint32]string
+}
+
+func newRing(addrs []string) *ring {
+	r := &ring{nodes: len(addrs), addrs: make(map[uint32]string, len(addrs)*replicas)}
+	for _, a := range addrs {
+		for i := 0; i < replicas; i++ {
+			p := crc32.ChecksumIEEE([]byte(a + "#" + strconv.Itoa(i)))
+			r.points = append(r.points, p)
+			r.addrs[p] = a
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// order returns the addresses in the order they follow the key on the ring, the first being
+// the node which owns the key
+func (r *ring) order(key string) []string {
+	h := crc32.ChecksumIEEE([]byte(key))
+	start := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+
+	seen := make(map[string]bool)
+	var order []string
+	for i := 0; i < len(r.points) && len(order) < r.nodes; i++ {
+		a := r.addrs[r.points[(start+i)%len(r.points)]]
+		if !seen[a] {
+			seen[a] = true
+			order = append(order, a)
+		}
+	}
+	return order
+}
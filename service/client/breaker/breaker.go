@@ -0,0 +1,358 @@
+// Package breaker provides a client with a circuit breaker per endpoint, so an endpoint which
+// is failing stops being called until it has had time to recover. When the ratio of calls to
+// an endpoint which fail in the window exceeds the threshold, the circuit opens and calls are
+// rejected without being made. After the cooldown the circuit is half open and a single call
+// is let through, closing the circuit if it succeeds and opening it again if it fails.
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultThreshold is the ratio of calls which must fail for the circuit to open
+	DefaultThreshold = 0.5
+	// DefaultMinRequests in the window before the circuit can open
+	DefaultMinRequests = 20
+	// DefaultWindow the failures are counted over
+	DefaultWindow = time.Second * 10
+	// DefaultCooldown before an open circuit is tested
+	DefaultCooldown = time.Second * 30
+)
+
+// numBuckets the window is divided into, the oldest bucket being dropped as the window rolls
+const numBuckets = 10
+
+// State of a circuit
+type State int
+
+const (
+	// Closed circuits let calls through
+	Closed State = iota
+	// Open circuits reject calls
+	Open
+	// HalfOpen circuits let a single call through to test if the endpoint has recovered
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Stat of a circuit
+type Stat struct {
+	Service  string
+	Endpoint string
+	State    State
+	// Requests and Failures in the window
+	Requests int
+	Failures int
+	// Trips is the number of times the circuit has opened
+	Trips uint64
+	// Rejected is the number of calls rejected whilst the circuit was open
+	Rejected uint64
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   []*breakerClient
+	checkOnce  sync.Once
+)
+
+// Stats returns the stats of the circuits of the breakers created
+func Stats() []Stat {
+	breakersMu.Lock()
+	bs := make([]*breakerClient, len(breakers))
+	copy(bs, breakers)
+	breakersMu.Unlock()
+
+	var stats []Stat
+	for _, b := range bs {
+		stats = append(stats, b.stats()...)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Service != stats[j].Service {
+			return stats[i].Service < stats[j].Service
+		}
+		return stats[i].Endpoint < stats[j].Endpoint
+	})
+	return stats
+}
+
+// metrics are registered as debug metrics, with the number of circuits in each state and the
+// trips and rejected calls of each circuit
+func metrics() map[string]float64 {
+	values := map[string]float64{Closed.String(): 0, Open.String(): 0, HalfOpen.String(): 0}
+	for _, s := range Stats() {
+		values[s.State.String()]++
+
+		name := s.Service + "." + s.Endpoint
+		values[name+".trips"] = float64(s.Trips)
+		values[name+".rejected"] = float64(s.Rejected)
+	}
+	return values
+}
+
+// check is registered as a debug check, failing whilst any circuits are open
+func check(ctx context.Context) error {
+	var open []string
+	for _, s := range Stats() {
+		if s.State != Closed {
+			open = append(open, s.Service+"."+s.Endpoint)
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("Circuits open for %v", strings.Join(open, ", "))
+	}
+	return nil
+}
+
+type bucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+type circuit struct {
+	service  string
+	endpoint string
+	threshold
+
+	state    State
+	opened   time.Time
+	probing  bool
+	buckets  [numBuckets]bucket
+	trips    uint64
+	rejected uint64
+}
+
+type breakerClient struct {
+	client.Client
+	opts Options
+
+	sync.Mutex
+	circuits map[string]*circuit
+}
+
+// NewClient returns a client which breaks the circuits of failing endpoints
+func NewClient(c client.Client, opts ...Option) client.Client {
+	options := Options{
+		Threshold:   DefaultThreshold,
+		MinRequests: DefaultMinRequests,
+		Window:      DefaultWindow,
+		Cooldown:    DefaultCooldown,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	b := &breakerClient{
+		Client:   c,
+		opts:     options,
+		circuits: make(map[string]*circuit),
+	}
+
+	breakersMu.Lock()
+	breakers = append(breakers, b)
+	breakersMu.Unlock()
+	checkOnce.Do(func() {
+		debug.RegisterCheck("circuit_breaker", check)
+		debug.RegisterMetrics("circuit_breaker", metrics)
+	})
+
+	return b
+}
+
+func (b *breakerClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	t, ok := thresholdFromOptions(options)
+
+	key := req.Service() + "." + req.Endpoint()
+	if !b.allow(key, req, t, ok) {
+		return errors.ServiceUnavailable(req.Service(), "Circuit breaker for %v is open", key)
+	}
+
+	err := b.Client.Call(ctx, req, rsp, opts...)
+
+	// calls cancelled by the caller, e.g. the losers of hedged calls, don't count
+	if err != nil && ctx.Err() == context.Canceled {
+		b.release(key)
+		return err
+	}
+	b.record(key, isFailure(err))
+	return err
+}
+
+// allow returns true if the call should be made, creating the circuit if needed
+func (b *breakerClient) allow(key string, req client.Request, t threshold, set bool) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{
+			service:   req.Service(),
+			endpoint:  req.Endpoint(),
+			threshold: threshold{b.opts.Threshold, b.opts.MinRequests},
+		}
+		b.circuits[key] = c
+	}
+	if set {
+		c.threshold = t
+	}
+
+	switch c.state {
+	case Open:
+		if time.Since(c.opened) < b.opts.Cooldown {
+			c.rejected++
+			return false
+		}
+		logger.Infof("Circuit breaker for %v is half open, testing the endpoint", key)
+		c.state = HalfOpen
+		c.probing = true
+		return true
+	case HalfOpen:
+		if c.probing {
+			c.rejected++
+			return false
+		}
+		c.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// release the circuit without recording the call, e.g. when the call was cancelled
+func (b *breakerClient) release(key string) {
+	b.Lock()
+	defer b.Unlock()
+	if c, ok := b.circuits[key]; ok {
+		c.probing = false
+	}
+}
+
+// record the result of the call, opening or closing the circuit as needed
+func (b *breakerClient) record(key string, failed bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok {
+		return
+	}
+	now := time.Now()
+
+	switch c.state {
+	case HalfOpen:
+		c.probing = false
+		if failed {
+			b.open(key, c, now)
+			return
+		}
+		logger.Infof("Circuit breaker for %v is closed, the endpoint has recovered", key)
+		c.state = Closed
+		c.buckets = [numBuckets]bucket{}
+	case Closed:
+		bkt := b.bucket(c, now)
+		bkt.requests++
+		if failed {
+			bkt.failures++
+		}
+
+		requests, failures := b.totals(c, now)
+		if requests >= c.minRequests && requests > 0 && float64(failures)/float64(requests) >= c.ratio {
+			b.open(key, c, now)
+		}
+	}
+}
+
+// open the circuit. The lock must be held.
+func (b *breakerClient) open(key string, c *circuit, now time.Time) {
+	logger.Warnf("Circuit breaker for %v is open, rejecting calls for %v", key, b.opts.Cooldown)
+	c.state = Open
+	c.opened = now
+	c.trips++
+}
+
+// bucket returns the bucket of the window the time is in, resetting it if it's left over from
+// a previous window. The lock must be held.
+func (b *breakerClient) bucket(c *circuit, now time.Time) *bucket {
+	size := b.opts.Window / numBuckets
+	if size <= 0 {
+		size = 1
+	}
+	start := now.Truncate(size)
+	bkt := &c.buckets[(start.UnixNano()/int64(size))%numBuckets]
+	if !bkt.start.Equal(start) {
+		*bkt = bucket{start: start}
+	}
+	return bkt
+}
+
+// totals returns the requests and failures in the window. The lock must be held.
+func (b *breakerClient) totals(c *circuit, now time.Time) (int, int) {
+	var requests, failures int
+	for _, bkt := range c.buckets {
+		if now.Sub(bkt.start) < b.opts.Window {
+			requests += bkt.requests
+			failures += bkt.failures
+		}
+	}
+	return requests, failures
+}
+
+// stats of the circuits
+func (b *breakerClient) stats() []Stat {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	stats := make([]Stat, 0, len(b.circuits))
+	for _, c := range b.circuits {
+		requests, failures := b.totals(c, now)
+		stats = append(stats, Stat{
+			Service:  c.service,
+			Endpoint: c.endpoint,
+			State:    c.state,
+			Requests: requests,
+			Failures: failures,
+			Trips:    c.trips,
+			Rejected: c.rejected,
+		})
+	}
+	return stats
+}
+
+// isFailure returns true if the error indicates the endpoint is failing, errors caused by the
+// request itself don't count
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	verr := errors.Parse(err)
+	if verr == nil {
+		return true
+	}
+	return verr.Code == 0 || verr.Code == 408 || verr.Code >= 500
+}
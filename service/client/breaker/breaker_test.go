@@ -0,0 +1,106 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testClient struct {
+	client.Client
+	calls int
+	err   error
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls++
+	return t.err
+}
+
+func TestBreaker(t *testing.T) {
+	c := &testClient{Client: mucp.NewClient()}
+	b := NewClient(c, Threshold(0.5), MinRequests(4), Window(time.Minute), Cooldown(time.Millisecond*50))
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+
+	call := func(opts ...client.CallOption) error {
+		return b.Call(context.TODO(), req, nil, opts...)
+	}
+	state := func() State {
+		for _, s := range Stats() {
+			if s.Service == "foo" && s.Endpoint == "Foo.Bar" {
+				return s.State
+			}
+		}
+		return Closed
+	}
+
+	// failures caused by the request don't open the circuit
+	c.err = errors.BadRequest("foo", "bad request")
+	for i := 0; i < 5; i++ {
+		call()
+	}
+	if state() != Closed {
+		t.Fatalf("Expected bad requests not to open the circuit")
+	}
+
+	// the circuit opens once half the calls fail
+	c.err = errors.InternalServerError("foo", "unavailable")
+	for i := 0; i < 5; i++ {
+		call()
+	}
+	if state() != Open {
+		t.Fatalf("Expected the circuit to be open, got %v", state())
+	}
+
+	c.calls = 0
+	err := call()
+	if verr := errors.Parse(err); verr == nil || verr.Code != 503 {
+		t.Fatalf("Expected a service unavailable error, got %v", err)
+	}
+	if c.calls != 0 {
+		t.Fatalf("Expected the call to be rejected whilst the circuit is open")
+	}
+	if err := check(context.TODO()); err == nil {
+		t.Fatalf("Expected the check to fail whilst the circuit is open")
+	}
+	if m := metrics(); m["open"] < 1 || m["foo.Foo.Bar.rejected"] < 1 {
+		t.Fatalf("Expected the metrics to count the open circuit and the rejected call, got %v", m)
+	}
+
+	// after the cooldown a failing test call opens the circuit again
+	time.Sleep(time.Millisecond * 60)
+	call()
+	if c.calls != 1 || state() != Open {
+		t.Fatalf("Expected the failed test call to open the circuit again, got %v", state())
+	}
+
+	// and a successful one closes it
+	time.Sleep(time.Millisecond * 60)
+	c.err = nil
+	if err := call(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state() != Closed {
+		t.Fatalf("Expected the circuit to be closed, got %v", state())
+	}
+	if err := check(context.TODO()); err != nil {
+		t.Fatalf("Unexpected check error: %v", err)
+	}
+}
+
+func TestWithThreshold(t *testing.T) {
+	c := &testClient{Client: mucp.NewClient(), err: errors.InternalServerError("bar", "unavailable")}
+	b := NewClient(c, MinRequests(100), Window(time.Minute), Cooldown(time.Minute))
+	req := c.NewRequest("bar", "Bar.Baz", nil)
+
+	for i := 0; i < 3; i++ {
+		b.Call(context.TODO(), req, nil, WithThreshold(0.5, 2))
+	}
+	if c.calls != 2 {
+		t.Fatalf("Expected the tuned threshold to open the circuit after 2 calls, got %v calls", c.calls)
+	}
+}
@@ -0,0 +1,80 @@
+package breaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+)
+
+// Options for the circuit breakers
+type Options struct {
+	// Threshold is the ratio of calls in the window which must fail for the circuit to open
+	Threshold float64
+	// MinRequests is the number of calls in the window below which the circuit won't open,
+	// so a few failures on a quiet endpoint don't open it
+	MinRequests int
+	// Window the failures are counted over
+	Window time.Duration
+	// Cooldown is how long the circuit stays open before a call is let through to test if the
+	// endpoint has recovered
+	Cooldown time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Threshold is the ratio of calls which must fail for the circuit to open
+func Threshold(t float64) Option {
+	return func(o *Options) {
+		o.Threshold = t
+	}
+}
+
+// MinRequests in the window before the circuit can open
+func MinRequests(n int) Option {
+	return func(o *Options) {
+		o.MinRequests = n
+	}
+}
+
+// Window the failures are counted over
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Cooldown before an open circuit is tested
+func Cooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
+
+type thresholdKey struct{}
+
+type threshold struct {
+	ratio       float64
+	minRequests int
+}
+
+// WithThreshold tunes the thresholds of the circuit of the endpoint being called, overriding
+// the breaker's options. The circuit uses the thresholds of the last call made with them.
+func WithThreshold(ratio float64, minRequests int) client.CallOption {
+	return func(o *client.CallOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, thresholdKey{}, threshold{ratio, minRequests})
+	}
+}
+
+// thresholdFromOptions returns the threshold set on the call options
+func thresholdFromOptions(o client.CallOptions) (threshold, bool) {
+	if o.Context == nil {
+		return threshold{}, false
+	}
+	t, ok := o.Context.Value(thresholdKey{}).(threshold)
+	return t, ok
+}
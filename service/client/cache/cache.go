@@ -5,23 +5,92 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/micro/go-micro/v3/client"
 	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/service/debug"
 	cache "github.com/patrickmn/go-cache"
 )
 
+var (
+	// DefaultMaxSize is the number of responses the cache holds, zero for no limit
+	DefaultMaxSize = 10000
+	// DefaultCache is the cache used by the default client
+	DefaultCache = New()
+)
+
+func init() {
+	// registered once DefaultCache is assigned, reading whichever cache the default client uses
+	debug.RegisterMetrics("client_cache", func() map[string]float64 {
+		return metrics(DefaultCache)
+	})
+}
+
 // New returns an initialised cache.
-func New() *Cache {
-	return &Cache{
-		cache: cache.New(cache.NoExpiration, 30*time.Second),
+func New(opts ...Option) *Cache {
+	c := &Cache{
+		cache:   cache.New(cache.NoExpiration, 30*time.Second),
+		maxSize: DefaultMaxSize,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	c.cache.OnEvicted(func(string, interface{}) {
+		atomic.AddUint64(&c.evictions, 1)
+	})
+	return c
+}
+
+// Option sets an option on the cache
+type Option func(c *Cache)
+
+// MaxSize sets the number of responses the cache holds, zero for no limit
+func MaxSize(n int) Option {
+	return func(c *Cache) {
+		c.maxSize = n
+	}
+}
+
+// WithSource sets the source the expiries of the endpoints are read from, so their responses
+// are cached without passing CallExpiry
+func WithSource(s Source) Option {
+	return func(c *Cache) {
+		c.source = s
 	}
 }
 
 // Cache for responses
 type Cache struct {
-	cache *cache.Cache
+	cache   *cache.Cache
+	maxSize int
+	source  Source
+
+	// setMtx serialises sets so the size limit is enforced
+	setMtx sync.Mutex
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	sync.RWMutex
+	// raw is the encoding the expiries were last parsed from
+	raw      []byte
+	expiries map[string]map[string]time.Duration
+}
+
+// Stats of the cache
+type Stats struct {
+	// Size is the number of responses cached
+	Size int
+	// Hits and Misses of calls with an expiry
+	Hits   uint64
+	Misses uint64
+	// Evictions of responses which expired, were invalidated or made room for others
+	Evictions uint64
 }
 
 type Options struct {
@@ -34,12 +103,79 @@ type optionsKey struct{}
 
 // Get a response from the cache
 func (c *Cache) Get(ctx context.Context, req client.Request) (interface{}, bool) {
-	return c.cache.Get(key(ctx, req))
+	rsp, ok := c.cache.Get(key(ctx, req))
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	return rsp, ok
 }
 
-// Set a response in the cache
+// Set a response in the cache, evicting the response which expires soonest if the cache is
+// full
 func (c *Cache) Set(ctx context.Context, req client.Request, rsp interface{}, expiry time.Duration) {
-	c.cache.Set(key(ctx, req), rsp, expiry)
+	c.setMtx.Lock()
+	defer c.setMtx.Unlock()
+
+	k := key(ctx, req)
+	if c.maxSize > 0 && c.cache.ItemCount() >= c.maxSize {
+		if _, ok := c.cache.Get(k); !ok {
+			c.evict()
+		}
+	}
+	c.cache.Set(k, rsp, expiry)
+}
+
+// evict the expired responses, or the response which expires soonest if none have expired
+func (c *Cache) evict() {
+	c.cache.DeleteExpired()
+	if c.cache.ItemCount() < c.maxSize {
+		return
+	}
+
+	var oldest string
+	var expiry int64
+	for k, item := range c.cache.Items() {
+		if len(oldest) == 0 || (item.Expiration > 0 && (expiry == 0 || item.Expiration < expiry)) {
+			oldest = k
+			expiry = item.Expiration
+		}
+	}
+	c.cache.Delete(oldest)
+}
+
+// Invalidate the responses cached for the endpoint of the service, or every endpoint of the
+// service if endpoint is blank. Returns the number of responses invalidated.
+func (c *Cache) Invalidate(service, endpoint string) int {
+	prefix := service + "/"
+	if len(endpoint) > 0 {
+		prefix += endpoint + "/"
+	}
+
+	var n int
+	for k := range c.cache.Items() {
+		if strings.HasPrefix(k, prefix) {
+			c.cache.Delete(k)
+			n++
+		}
+	}
+	return n
+}
+
+// Purge every response from the cache
+func (c *Cache) Purge() {
+	c.cache.Flush()
+}
+
+// Stats returns the stats of the cache
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Size:      c.cache.ItemCount(),
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+	}
 }
 
 // List the key value pairs in the cache
@@ -69,9 +205,10 @@ func key(ctx context.Context, req client.Request) string {
 		},
 	})
 
+	// the service and endpoint prefix the hash so their responses can be invalidated
 	h := fnv.New64()
 	h.Write(bytes)
-	return fmt.Sprintf("%x", h.Sum(nil))
+	return fmt.Sprintf("%s/%s/%x", req.Service(), req.Endpoint(), h.Sum(nil))
 }
 
 func SetOptions(ctx context.Context, opts *Options) context.Context {
@@ -103,3 +240,30 @@ func CallExpiry(t time.Duration) client.CallOption {
 		Expiry: t,
 	})
 }
+
+// Invalidate the responses cached by the default client for the endpoint of the service, or
+// every endpoint of the service if endpoint is blank
+func Invalidate(service, endpoint string) int {
+	return DefaultCache.Invalidate(service, endpoint)
+}
+
+// Purge every response cached by the default client
+func Purge() {
+	DefaultCache.Purge()
+}
+
+// CacheStats returns the stats of the default client's cache
+func CacheStats() Stats {
+	return DefaultCache.Stats()
+}
+
+// metrics returns the stats of the cache as debug metrics
+func metrics(c *Cache) map[string]float64 {
+	s := c.Stats()
+	return map[string]float64{
+		"size":      float64(s.Size),
+		"hits":      float64(s.Hits),
+		"misses":    float64(s.Misses),
+		"evictions": float64(s.Evictions),
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/service/debug"
 )
 
 func TestCache(t *testing.T) {
@@ -75,3 +76,89 @@ func TestCacheKey(t *testing.T) {
 		}
 	})
 }
+
+func TestCacheMaxSize(t *testing.T) {
+	ctx := context.TODO()
+	c := New(MaxSize(2))
+
+	req1 := &testRequest{service: "foo", endpoint: "Foo.Bar", body: "1"}
+	req2 := &testRequest{service: "foo", endpoint: "Foo.Bar", body: "2"}
+	req3 := &testRequest{service: "foo", endpoint: "Foo.Bar", body: "3"}
+
+	c.Set(ctx, req1, "1", time.Second)
+	c.Set(ctx, req2, "2", time.Minute)
+	c.Set(ctx, req3, "3", time.Minute)
+
+	if _, ok := c.Get(ctx, req1); ok {
+		t.Errorf("Expected the response which expires soonest to be evicted")
+	}
+	if _, ok := c.Get(ctx, req3); !ok {
+		t.Errorf("Expected the latest response to be cached")
+	}
+
+	stats := c.Stats()
+	if stats.Size != 2 {
+		t.Errorf("Expected size 2, got %v", stats.Size)
+	}
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %v and %v", stats.Hits, stats.Misses)
+	}
+	if stats.Evictions != 1 {
+		t.Errorf("Expected 1 eviction, got %v", stats.Evictions)
+	}
+}
+
+func TestCacheMetrics(t *testing.T) {
+	defer func(c *Cache) { DefaultCache = c }(DefaultCache)
+	DefaultCache = New()
+
+	DefaultCache.Set(context.TODO(), &testRequest{service: "foo", endpoint: "Foo.Bar"}, "1", time.Minute)
+	if size := debug.ReadMetrics()["client_cache.size"]; size != 1 {
+		t.Errorf("Expected the metrics to have a size of 1, got %v", size)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ctx := context.TODO()
+	c := New()
+
+	c.Set(ctx, &testRequest{service: "foo", endpoint: "Foo.Bar"}, "1", time.Minute)
+	c.Set(ctx, &testRequest{service: "foo", endpoint: "Foo.Baz"}, "2", time.Minute)
+	c.Set(ctx, &testRequest{service: "bar", endpoint: "Bar.Baz"}, "3", time.Minute)
+
+	if n := c.Invalidate("foo", "Foo.Bar"); n != 1 {
+		t.Errorf("Expected 1 response to be invalidated, got %v", n)
+	}
+	if n := c.Invalidate("foo", ""); n != 1 {
+		t.Errorf("Expected 1 response to be invalidated, got %v", n)
+	}
+	if _, ok := c.Get(ctx, &testRequest{service: "bar", endpoint: "Bar.Baz"}); !ok {
+		t.Errorf("Expected the responses of other services to be cached")
+	}
+
+	c.Purge()
+	if size := c.Stats().Size; size != 0 {
+		t.Errorf("Expected the cache to be empty, got %v", size)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	raw := []byte(`{"foo": {"Foo.Bar": "10s", "*": "1m"}}`)
+	c := New(WithSource(func() []byte { return raw }))
+
+	if d := c.Expiry("foo", "Foo.Bar"); d != 10*time.Second {
+		t.Errorf("Expected 10s, got %v", d)
+	}
+	if d := c.Expiry("foo", "Foo.Baz"); d != time.Minute {
+		t.Errorf("Expected 1m, got %v", d)
+	}
+	if d := c.Expiry("bar", "Bar.Baz"); d != 0 {
+		t.Errorf("Expected no expiry, got %v", d)
+	}
+
+	// invalid expiries are ignored and the last valid ones kept
+	raw = []byte(`{"foo": {"Foo.Bar": "soon"}}`)
+	if d := c.Expiry("foo", "Foo.Bar"); d != 10*time.Second {
+		t.Errorf("Expected 10s, got %v", d)
+	}
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultPath is the path of the expiries in the config. The expiries are keyed by service
+	// and endpoint, "*" matching any endpoint of the service, for example:
+	//
+	//	{
+	//		"helloworld": {"Helloworld.Call": "10s"},
+	//		"products": {"*": "1m"}
+	//	}
+	DefaultPath = []string{"client", "cache"}
+)
+
+// Source returns the encoded expiries, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the expiries from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+// ParseExpiries from their JSON encoding, where durations are strings such as "10s"
+func ParseExpiries(b []byte) (map[string]map[string]time.Duration, error) {
+	var raw map[string]map[string]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	expiries := make(map[string]map[string]time.Duration, len(raw))
+	for service, endpoints := range raw {
+		expiries[service] = make(map[string]time.Duration, len(endpoints))
+		for endpoint, v := range endpoints {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expiry for %v %v: %v", service, endpoint, err)
+			}
+			expiries[service][endpoint] = d
+		}
+	}
+	return expiries, nil
+}
+
+// Expiry returns the expiry of the endpoint read from the source, zero if its responses
+// aren't cached. The source is parsed when it changes, so changes to the config take effect
+// without restarting.
+func (c *Cache) Expiry(service, endpoint string) time.Duration {
+	if c.source == nil {
+		return 0
+	}
+	raw := c.source()
+
+	c.RLock()
+	if bytes.Equal(raw, c.raw) {
+		d := lookupExpiry(c.expiries, service, endpoint)
+		c.RUnlock()
+		return d
+	}
+	c.RUnlock()
+
+	c.Lock()
+	defer c.Unlock()
+
+	var expiries map[string]map[string]time.Duration
+	if len(bytes.TrimSpace(raw)) > 0 && !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		parsed, err := ParseExpiries(raw)
+		if err != nil {
+			// keep using the last valid expiries
+			logger.Errorf("Error parsing client cache expiries: %v", err)
+			c.raw = raw
+			return lookupExpiry(c.expiries, service, endpoint)
+		}
+		expiries = parsed
+	}
+
+	c.raw = raw
+	c.expiries = expiries
+	return lookupExpiry(c.expiries, service, endpoint)
+}
+
+func lookupExpiry(expiries map[string]map[string]time.Duration, service, endpoint string) time.Duration {
+	endpoints, ok := expiries[service]
+	if !ok {
+		return 0
+	}
+	if d, ok := endpoints[endpoint]; ok {
+		return d
+	}
+	return endpoints["*"]
+}
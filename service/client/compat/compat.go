@@ -0,0 +1,221 @@
+// Package compat provides a client which checks the requests and responses of calls are
+// compatible with the services called. Services register the schema versions and fields of
+// their endpoints' request and response types in the endpoint metadata. When the versions
+// differ from those of the types the client was generated with, their fields are compared so a
+// caller built against a proto whose fields changed number, kind or cardinality finds out
+// before fields are misread. Fields which were only added or removed are compatible.
+//
+// Every version of the service which is deployed is checked, since during a partial deploy the
+// call can be routed to any of them. Incompatible calls are logged or fail fast depending on
+// the mode.
+package compat
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// Mode of the check
+type Mode string
+
+const (
+	// Off doesn't check calls
+	Off Mode = "off"
+	// Warn logs incompatible calls and makes them anyway
+	Warn Mode = "warn"
+	// Fail fails incompatible calls without making them
+	Fail Mode = "fail"
+)
+
+var (
+	// DefaultMode is the mode of the clients created
+	DefaultMode = Warn
+	// DefaultTTL is how long the result of checking an endpoint is remembered for
+	DefaultTTL = time.Minute
+)
+
+// ParseMode parses the mode, blank being the default
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return DefaultMode, nil
+	case Off, Warn, Fail:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown schema check mode %q, expected off, warn or fail", s)
+}
+
+// Message the client was built with
+type Message struct {
+	// Version of the message's schema
+	Version string
+	// Fields of the message, see util.MessageFields
+	Fields map[string]string
+}
+
+// Mismatch of a version of the service the call is incompatible with
+type Mismatch struct {
+	// Version of the service
+	Version string
+	// Message which differs, request or response
+	Message string
+	// Conflicts between the fields of the message and those the service registered
+	Conflicts []string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%v of version %v: %v", m.Message, m.Version, strings.Join(m.Conflicts, ", "))
+}
+
+// Check returns the mismatches between the fields of the request and response and those the
+// versions of the service registered for the endpoint. Versions which registered the same
+// schema versions, or didn't register the endpoint's fields, are assumed to be compatible.
+func Check(srvs []*goregistry.Service, endpoint string, req, rsp Message) []Mismatch {
+	var mismatches []Mismatch
+	for _, s := range srvs {
+		for _, e := range s.Endpoints {
+			if e.Name != endpoint {
+				continue
+			}
+			if c := conflicts(req, e.Metadata[util.RequestVersionKey], e.Metadata[util.RequestFieldsKey]); len(c) > 0 {
+				mismatches = append(mismatches, Mismatch{Version: s.Version, Message: "request", Conflicts: c})
+			}
+			if c := conflicts(rsp, e.Metadata[util.ResponseVersionKey], e.Metadata[util.ResponseFieldsKey]); len(c) > 0 {
+				mismatches = append(mismatches, Mismatch{Version: s.Version, Message: "response", Conflicts: c})
+			}
+		}
+	}
+	return mismatches
+}
+
+// conflicts between the fields of the message and the fields registered
+func conflicts(msg Message, version, fields string) []string {
+	if len(msg.Version) == 0 || len(version) == 0 || version == msg.Version || len(fields) == 0 {
+		return nil
+	}
+	return util.FieldConflicts(msg.Fields, util.ParseFields(fields))
+}
+
+// checked result of an endpoint
+type checked struct {
+	mismatches []Mismatch
+	expires    time.Time
+}
+
+type compatClient struct {
+	client.Client
+	mode Mode
+
+	sync.Mutex
+	// messages of the types, which don't change
+	messages map[reflect.Type]Message
+	// endpoints checked, keyed by the namespace, service, endpoint and local versions
+	endpoints map[string]checked
+}
+
+// NewClient returns a client which checks the calls made are compatible with the services
+// called, using the default mode
+func NewClient(c client.Client) client.Client {
+	return &compatClient{
+		Client:    c,
+		mode:      DefaultMode,
+		messages:  make(map[reflect.Type]Message),
+		endpoints: make(map[string]checked),
+	}
+}
+
+func (c *compatClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if err := c.check(ctx, req, rsp); err != nil {
+		return err
+	}
+	return c.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (c *compatClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	// the messages of streams aren't known until they're sent
+	if err := c.check(ctx, req, nil); err != nil {
+		return nil, err
+	}
+	return c.Client.Stream(ctx, req, opts...)
+}
+
+// check the call, returning an error if it's incompatible and the mode is Fail
+func (c *compatClient) check(ctx context.Context, req client.Request, rsp interface{}) error {
+	if c.mode == Off || registry.DefaultRegistry == nil {
+		return nil
+	}
+
+	reqMsg := c.message(req.Body())
+	rspMsg := c.message(rsp)
+	if len(reqMsg.Version) == 0 && len(rspMsg.Version) == 0 {
+		return nil
+	}
+
+	// the service is looked up in the namespace it's called in
+	domain := namespace.FromContext(ctx)
+	if len(domain) == 0 {
+		domain = goregistry.DefaultDomain
+	}
+
+	key := strings.Join([]string{domain, req.Service(), req.Endpoint(), reqMsg.Version, rspMsg.Version}, ":")
+	c.Lock()
+	res, ok := c.endpoints[key]
+	c.Unlock()
+
+	if !ok || time.Now().After(res.expires) {
+		srvs, err := registry.DefaultRegistry.GetService(req.Service(), goregistry.GetDomain(domain))
+		if err != nil {
+			// the call fails to find the service if it's not registered
+			return nil
+		}
+		res = checked{
+			mismatches: Check(srvs, req.Endpoint(), reqMsg, rspMsg),
+			expires:    time.Now().Add(DefaultTTL),
+		}
+		c.Lock()
+		c.endpoints[key] = res
+		c.Unlock()
+
+		// the mismatches are logged when they're found rather than on every call
+		for _, m := range res.mismatches {
+			logger.Warnf("Call to %v.%v is incompatible with the service: %v", req.Service(), req.Endpoint(), m)
+		}
+	}
+
+	if len(res.mismatches) == 0 || c.mode != Fail {
+		return nil
+	}
+	return errors.PreconditionFailed(req.Service(), "Call to %v.%v is incompatible with the service: %v",
+		req.Service(), req.Endpoint(), res.mismatches[0])
+}
+
+// message returns the schema version and fields of the message, blank if it isn't a protobuf
+// message
+func (c *compatClient) message(msg interface{}) Message {
+	if msg == nil {
+		return Message{}
+	}
+	t := reflect.TypeOf(msg)
+
+	c.Lock()
+	defer c.Unlock()
+	m, ok := c.messages[t]
+	if !ok {
+		m.Version, _ = util.TypeVersion(t)
+		m.Fields, _ = util.TypeFields(t)
+		c.messages[t] = m
+	}
+	return m
+}
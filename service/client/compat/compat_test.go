@@ -0,0 +1,125 @@
+package compat
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+type testClient struct {
+	client.Client
+	calls int
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls++
+	return nil
+}
+
+func TestParseMode(t *testing.T) {
+	if m, err := ParseMode(" Fail "); err != nil || m != Fail {
+		t.Errorf("Expected fail, got %v %v", m, err)
+	}
+	if m, err := ParseMode(""); err != nil || m != DefaultMode {
+		t.Errorf("Expected the default mode, got %v %v", m, err)
+	}
+	if _, err := ParseMode("strict"); err == nil {
+		t.Errorf("Expected an unknown mode to be invalid")
+	}
+}
+
+func TestClient(t *testing.T) {
+	reg := memory.NewRegistry()
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	reqVer, _ := util.TypeVersion(reflect.TypeOf(&pb.GetRequest{}))
+	rspVer, _ := util.TypeVersion(reflect.TypeOf(&pb.GetResponse{}))
+	reqFields, _ := util.TypeFields(reflect.TypeOf(&pb.GetRequest{}))
+	register := func(version, req string, fields map[string]string, opts ...goregistry.RegisterOption) {
+		reg.Register(&goregistry.Service{
+			Name:    "foo",
+			Version: version,
+			Nodes:   []*goregistry.Node{{Id: "foo-" + version, Address: "127.0.0.1:1"}},
+			Endpoints: []*goregistry.Endpoint{{
+				Name: "Foo.Get",
+				Metadata: map[string]string{
+					util.RequestVersionKey:  req,
+					util.RequestFieldsKey:   util.EncodeFields(fields),
+					util.ResponseVersionKey: rspVer,
+				},
+			}},
+		}, opts...)
+	}
+	register("v1", reqVer, reqFields)
+
+	defer func(m Mode) { DefaultMode = m }(DefaultMode)
+	DefaultMode = Fail
+
+	tc := &testClient{Client: mucp.NewClient()}
+	c := NewClient(tc)
+	req := c.NewRequest("foo", "Foo.Get", &pb.GetRequest{})
+	if err := c.Call(context.TODO(), req, &pb.GetResponse{}); err != nil {
+		t.Fatalf("Expected the compatible call to be made, got %v", err)
+	}
+
+	// a version which only added a field is compatible
+	added := map[string]string{"registry.GetRequest.99": "optional string"}
+	for k, v := range reqFields {
+		added[k] = v
+	}
+	register("v2", "0123456789abcdef", added)
+	c = NewClient(tc)
+	if err := c.Call(context.TODO(), req, &pb.GetResponse{}); err != nil {
+		t.Fatalf("Expected the call with an added field to be made, got %v", err)
+	}
+
+	// a partial deploy of a version which changed the kind of a field
+	changed := map[string]string{"registry.GetRequest.1": "optional int64"}
+	register("v3", "fedcba9876543210", changed)
+	c = NewClient(tc)
+	err := c.Call(context.TODO(), req, &pb.GetResponse{})
+	if verr := errors.Parse(err); verr == nil || verr.Code != 412 {
+		t.Fatalf("Expected the incompatible call to fail, got %v", err)
+	}
+	if tc.calls != 2 {
+		t.Errorf("Expected the incompatible call not to be made, got %v calls", tc.calls)
+	}
+
+	// services are looked up in the namespace they're called in
+	reg.Deregister(&goregistry.Service{Name: "foo", Version: "v3", Nodes: []*goregistry.Node{{Id: "foo-v3"}}})
+	register("v3", "fedcba9876543210", changed, goregistry.RegisterDomain("bar"))
+	c = NewClient(tc)
+	if err := c.Call(context.TODO(), req, &pb.GetResponse{}); err != nil {
+		t.Fatalf("Expected the call in the default namespace to be made, got %v", err)
+	}
+	ctx := namespace.ContextWithNamespace(context.TODO(), "bar")
+	if err := c.Call(ctx, req, &pb.GetResponse{}); errors.Parse(err) == nil || errors.Parse(err).Code != 412 {
+		t.Fatalf("Expected the call in the namespace to fail, got %v", err)
+	}
+
+	// calls are only logged in the warn mode
+	DefaultMode = Warn
+	c = NewClient(tc)
+	if err := c.Call(ctx, req, &pb.GetResponse{}); err != nil {
+		t.Fatalf("Expected the incompatible call to be made, got %v", err)
+	}
+
+	// bodies which aren't messages aren't checked
+	DefaultMode = Fail
+	c = NewClient(tc)
+	req = c.NewRequest("foo", "Foo.Get", map[string]string{})
+	if err := c.Call(context.TODO(), req, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected the call to be made, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+// Package compress provides a client which compresses large requests, reducing the bandwidth
+// used by chunky payloads. Requests at least as large as the threshold are compressed with the
+// first of the DefaultCompressors which is registered with grpc and accepted by every node of
+// the service, which servers advertise in their metadata. Servers compress their responses with
+// the compressor of the request, so calls with large responses can set a threshold of zero to
+// always compress. gzip is registered by this package, other compressors such as zstd are used
+// once a plugin registers them with the grpc encoding package.
+package compress
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	gclient "github.com/micro/go-micro/v3/client/grpc"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/client/limit"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	// register the gzip compressor
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+var (
+	// DefaultCompressors are the compressors requests can be compressed with, in order of
+	// preference
+	DefaultCompressors = []string{"zstd", "gzip"}
+	// DefaultThreshold is the size in bytes of the smallest request compressed, negative to
+	// disable compression
+	DefaultThreshold = 32 * 1024
+	// DefaultTTL is how long the compressor negotiated with a service is remembered for
+	DefaultTTL = time.Minute * 5
+	// DefaultErrorTTL is how long requests to a service are left uncompressed for when it
+	// can't be looked up, so calls to it don't each hit the registry
+	DefaultErrorTTL = time.Second * 5
+)
+
+type thresholdKey struct{}
+
+// Threshold sets the size in bytes of the smallest request the call compresses, zero to always
+// compress and negative to never compress
+func Threshold(n int) client.CallOption {
+	return func(o *client.CallOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, thresholdKey{}, n)
+	}
+}
+
+// Registered returns the DefaultCompressors which are registered with grpc
+func Registered() []string {
+	var names []string
+	for _, name := range DefaultCompressors {
+		if encoding.GetCompressor(name) != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// negotiated compressor of a service, blank if the service doesn't accept any or couldn't be
+// looked up
+type negotiated struct {
+	name    string
+	expires time.Time
+}
+
+type compressClient struct {
+	client.Client
+
+	sync.RWMutex
+	// services are keyed by the domain they're looked up in and their name
+	services map[string]negotiated
+}
+
+// NewClient returns a client which compresses large requests
+func NewClient(c client.Client) client.Client {
+	return &compressClient{Client: c, services: make(map[string]negotiated)}
+}
+
+func (c *compressClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if n := threshold(opts); n >= 0 && (n == 0 || limit.Size(req.Body()) >= n) {
+		if name := c.compressor(ctx, req.Service()); len(name) > 0 {
+			opts = append(opts, gclient.CallOptions(grpc.UseCompressor(name)))
+		}
+	}
+	return c.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (c *compressClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	// the size of the messages isn't known up front, so streams are only compressed if asked to
+	if threshold(opts) == 0 {
+		if name := c.compressor(ctx, req.Service()); len(name) > 0 {
+			opts = append(opts, gclient.CallOptions(grpc.UseCompressor(name)))
+		}
+	}
+	return c.Client.Stream(ctx, req, opts...)
+}
+
+// compressor returns the compressor to use for requests to the service, blank if none of the
+// registered compressors is accepted by every node
+func (c *compressClient) compressor(ctx context.Context, service string) string {
+	// the service is looked up in the namespace it's called in
+	domain := namespace.FromContext(ctx)
+	if len(domain) == 0 {
+		domain = goregistry.DefaultDomain
+	}
+	key := domain + ":" + service
+
+	c.RLock()
+	neg, ok := c.services[key]
+	c.RUnlock()
+	if ok && time.Now().Before(neg.expires) {
+		return neg.name
+	}
+	if registry.DefaultRegistry == nil {
+		return ""
+	}
+
+	var name string
+	ttl := DefaultTTL
+	if srvs, err := registry.DefaultRegistry.GetService(service, goregistry.GetDomain(domain)); err != nil {
+		ttl = DefaultErrorTTL
+	} else {
+		for _, n := range Registered() {
+			if accepts(srvs, n) {
+				name = n
+				break
+			}
+		}
+	}
+
+	c.Lock()
+	c.services[key] = negotiated{name: name, expires: time.Now().Add(ttl)}
+	c.Unlock()
+	return name
+}
+
+// threshold returns the threshold of the call, falling back to the default
+func threshold(opts []client.CallOption) int {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Context != nil {
+		if n, ok := options.Context.Value(thresholdKey{}).(int); ok {
+			return n
+		}
+	}
+	return DefaultThreshold
+}
+
+// accepts returns true if every node of the services advertises it accepts the compressor.
+// Unlike content types, nodes which don't advertise compressors may not decode them, so they
+// never accept any.
+func accepts(srvs []*goregistry.Service, name string) bool {
+	var nodes int
+	for _, s := range srvs {
+		for _, node := range s.Nodes {
+			nodes++
+			var found bool
+			for _, c := range util.Compressors(node) {
+				if c == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return nodes > 0
+}
@@ -0,0 +1,110 @@
+package compress
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// testClient records the number of options of the last call, the compressor being one more
+type testClient struct {
+	client.Client
+	opts int
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.opts = len(opts)
+	return nil
+}
+
+func TestCompress(t *testing.T) {
+	reg := memory.NewRegistry()
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	gzip := &goregistry.Node{Id: "foo-1", Address: "127.0.0.1:1"}
+	util.SetCompressors(gzip, []string{"gzip"})
+	reg.Register(&goregistry.Service{Name: "foo", Nodes: []*goregistry.Node{gzip}})
+	reg.Register(&goregistry.Service{Name: "bar", Nodes: []*goregistry.Node{{Id: "bar-1", Address: "127.0.0.1:2"}}})
+
+	large := map[string]string{"data": strings.Repeat("x", DefaultThreshold)}
+	small := map[string]string{"data": "x"}
+
+	tt := []struct {
+		Name     string
+		Service  string
+		Body     interface{}
+		Opts     []client.CallOption
+		Compress bool
+	}{
+		{"Large", "foo", large, nil, true},
+		{"Small", "foo", small, nil, false},
+		{"Always", "foo", small, []client.CallOption{Threshold(0)}, true},
+		{"Never", "foo", large, []client.CallOption{Threshold(-1)}, false},
+		{"Unsupported", "bar", large, nil, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc2 := &testClient{Client: mucp.NewClient()}
+			c := NewClient(tc2)
+			req := c.NewRequest(tc.Service, "Foo.Bar", tc.Body)
+			if err := c.Call(context.TODO(), req, nil, tc.Opts...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if compressed := tc2.opts > len(tc.Opts); compressed != tc.Compress {
+				t.Errorf("Expected compressed to be %v", tc.Compress)
+			}
+		})
+	}
+}
+
+// countingRegistry counts the services looked up
+type countingRegistry struct {
+	goregistry.Registry
+	lookups int
+}
+
+func (c *countingRegistry) GetService(name string, opts ...goregistry.GetOption) ([]*goregistry.Service, error) {
+	c.lookups++
+	return c.Registry.GetService(name, opts...)
+}
+
+func TestCompressNamespace(t *testing.T) {
+	reg := &countingRegistry{Registry: memory.NewRegistry()}
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	node := &goregistry.Node{Id: "foo-1", Address: "127.0.0.1:1"}
+	util.SetCompressors(node, []string{"gzip"})
+	reg.Register(&goregistry.Service{Name: "foo", Nodes: []*goregistry.Node{node}}, goregistry.RegisterDomain("acme"))
+
+	tc := &testClient{Client: mucp.NewClient()}
+	c := NewClient(tc)
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+
+	// the service is looked up in the namespace of the call
+	ctx := namespace.ContextWithNamespace(context.TODO(), "acme")
+	if c.Call(ctx, req, nil, Threshold(0)); tc.opts != 2 {
+		t.Errorf("Expected the call in the namespace of the service to be compressed")
+	}
+
+	// the service isn't registered in the default namespace, which is remembered
+	lookups := reg.lookups
+	for i := 0; i < 3; i++ {
+		if c.Call(context.TODO(), req, nil, Threshold(0)); tc.opts != 1 {
+			t.Errorf("Expected the call in the default namespace not to be compressed")
+		}
+	}
+	if n := reg.lookups - lookups; n != 1 {
+		t.Errorf("Expected the missing service to be looked up once, got %v", n)
+	}
+}
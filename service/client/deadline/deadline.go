@@ -0,0 +1,150 @@
+// Package deadline propagates the deadline of requests across hops. The client sends the time
+// remaining until the request times out in the BudgetKey header, and the server handles the
+// request with a context which expires when the budget runs out, so the calls made while
+// handling it shrink their own timeouts to what's left. Since the budget is relative, clock
+// skew between services doesn't affect it.
+//
+// Calls whose budget is already below a minimum fail fast with a timeout error rather than
+// doing work which can't complete in time.
+package deadline
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// BudgetKey is the header the remaining budget of a request is sent in, in milliseconds
+	BudgetKey = "Micro-Deadline-Budget"
+
+	// DefaultMinBudget is the budget calls made without the MinBudget option need to be sent,
+	// zero to send calls whatever their budget
+	DefaultMinBudget time.Duration
+)
+
+type minBudgetKey struct{}
+
+// MinBudget fails the call without sending it if less than the budget remains before it
+// times out
+func MinBudget(d time.Duration) client.CallOption {
+	return func(o *client.CallOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, minBudgetKey{}, d)
+	}
+}
+
+// minBudgetFromOptions returns the minimum budget set on the call options, or the default
+func minBudgetFromOptions(o client.CallOptions) time.Duration {
+	if o.Context == nil {
+		return DefaultMinBudget
+	}
+	if d, ok := o.Context.Value(minBudgetKey{}).(time.Duration); ok {
+		return d
+	}
+	return DefaultMinBudget
+}
+
+// FromContext returns the budget remaining before the context expires, false if the context
+// has no deadline
+func FromContext(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(d), true
+}
+
+type deadlineClient struct {
+	client.Client
+}
+
+// NewClient returns a client which sends the remaining budget of calls with them
+func NewClient(c client.Client) client.Client {
+	return &deadlineClient{Client: c}
+}
+
+func (d *deadlineClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	options := d.Client.Options().CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// the call times out after the request timeout unless the context expires first
+	budget := options.RequestTimeout
+	if left, ok := FromContext(ctx); ok && (budget <= 0 || left < budget) {
+		budget = left
+	}
+
+	ctx, err := d.withBudget(ctx, req, budget, minBudgetFromOptions(options))
+	if err != nil {
+		return err
+	}
+	return d.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (d *deadlineClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	options := d.Client.Options().CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// streams outlive the request timeout, so only the deadline of the context applies
+	budget, ok := FromContext(ctx)
+	if !ok {
+		return d.Client.Stream(ctx, req, opts...)
+	}
+
+	ctx, err := d.withBudget(ctx, req, budget, minBudgetFromOptions(options))
+	if err != nil {
+		return nil, err
+	}
+	return d.Client.Stream(ctx, req, opts...)
+}
+
+// withBudget returns a context which sends the budget with the request, or an error if the
+// budget is below the minimum
+func (d *deadlineClient) withBudget(ctx context.Context, req client.Request, budget, min time.Duration) (context.Context, error) {
+	if budget <= 0 {
+		return ctx, nil
+	}
+	if budget < min {
+		return ctx, errors.Timeout(req.Service(), "Not calling %v, %v of the deadline left is less than the minimum %v", req.Endpoint(), budget, min)
+	}
+	return metadata.Set(ctx, BudgetKey, strconv.FormatInt(budget.Milliseconds(), 10)), nil
+}
+
+// HandlerWrapper handles requests with a context which expires when their budget runs out,
+// rejecting requests with less than the minimum budget left. Zero accepts requests whatever
+// their budget.
+func HandlerWrapper(min time.Duration) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			v, ok := metadata.Get(ctx, BudgetKey)
+			if !ok {
+				return h(ctx, req, rsp)
+			}
+			ms, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return h(ctx, req, rsp)
+			}
+
+			budget := time.Duration(ms) * time.Millisecond
+			if budget <= 0 || budget < min {
+				return errors.Timeout(req.Service(), "Not handling %v, %v of the deadline left is less than the minimum %v", req.Endpoint(), budget, min)
+			}
+
+			// the context may already expire sooner, e.g. if the transport propagates deadlines
+			ctx, cancel := context.WithTimeout(ctx, budget)
+			defer cancel()
+			return h(ctx, req, rsp)
+		}
+	}
+}
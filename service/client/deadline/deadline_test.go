@@ -0,0 +1,100 @@
+package deadline
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// testClient records the budget calls are sent with
+type testClient struct {
+	client.Client
+	budget string
+	calls  int
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls++
+	t.budget, _ = metadata.Get(ctx, BudgetKey)
+	return nil
+}
+
+type testRequest struct {
+	server.Request
+}
+
+func (t *testRequest) Service() string  { return "foo" }
+func (t *testRequest) Endpoint() string { return "Foo.Bar" }
+
+func TestClient(t *testing.T) {
+	tc := &testClient{Client: mucp.NewClient(client.RequestTimeout(time.Second))}
+	c := NewClient(tc)
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+
+	t.Run("RequestTimeout", func(t *testing.T) {
+		if err := c.Call(context.TODO(), req, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if tc.budget != "1000" {
+			t.Errorf("Expected a budget of 1000ms, got %q", tc.budget)
+		}
+	})
+
+	t.Run("ContextDeadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 200*time.Millisecond)
+		defer cancel()
+		if err := c.Call(ctx, req, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ms, _ := strconv.Atoi(tc.budget); ms <= 0 || ms > 200 {
+			t.Errorf("Expected a budget of at most 200ms, got %q", tc.budget)
+		}
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		tc.calls = 0
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		err := c.Call(ctx, req, nil, MinBudget(100*time.Millisecond))
+		if verr := errors.Parse(err); verr == nil || verr.Code != 408 {
+			t.Fatalf("Expected a timeout error, got %v", err)
+		}
+		if tc.calls != 0 {
+			t.Errorf("Expected the call not to be sent")
+		}
+	})
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	var left time.Duration
+	h := HandlerWrapper(50 * time.Millisecond)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		left, _ = FromContext(ctx)
+		return nil
+	})
+
+	ctx := metadata.Set(context.TODO(), BudgetKey, "500")
+	if err := h(ctx, &testRequest{}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if left <= 0 || left > 500*time.Millisecond {
+		t.Errorf("Expected the handler to have at most 500ms, got %v", left)
+	}
+
+	ctx = metadata.Set(context.TODO(), BudgetKey, "10")
+	if err := h(ctx, &testRequest{}, nil); err == nil {
+		t.Errorf("Expected the request to be rejected")
+	}
+
+	// requests without a budget are handled as they are
+	left = 0
+	if err := h(context.TODO(), &testRequest{}, nil); err != nil || left != 0 {
+		t.Errorf("Expected the request to be handled without a deadline, got %v %v", left, err)
+	}
+}
@@ -0,0 +1,291 @@
+// Package failover provides a client which fails calls over to a secondary region
+// when the services in the local region are unavailable.
+package failover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultThreshold of consecutive failures before calls skip the local region
+	DefaultThreshold = 5
+	// DefaultCooldown before the local region is tried again
+	DefaultCooldown = time.Second * 30
+	// DefaultTopic failover events are published to
+	DefaultTopic = "client.failover"
+	// DefaultEventInterval is the minimum interval between the events published for a service
+	DefaultEventInterval = time.Second * 10
+)
+
+// Event is published when calls fail over to the secondary region, at most once per event
+// interval for each service unless the threshold is exceeded
+type Event struct {
+	// Service and endpoint being called
+	Service  string `json:"service"`
+	Endpoint string `json:"endpoint"`
+	// Address of the secondary region
+	Address string `json:"address"`
+	// Error returned by the local region, blank if the call skipped it
+	Error string `json:"error"`
+	// Tripped indicates the failure threshold was exceeded by this call
+	Tripped bool `json:"tripped"`
+	// Calls to the service failed over since the previous event, including this one
+	Calls int `json:"calls"`
+	// Timestamp of the failover
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type state struct {
+	failures int
+	tripped  time.Time
+}
+
+// published events of a service
+type published struct {
+	last  time.Time
+	calls int
+}
+
+type failoverClient struct {
+	client.Client
+	opts     Options
+	services map[string]bool
+
+	sync.Mutex
+	states    map[string]*state
+	published map[string]*published
+}
+
+// NewClient returns a client which fails calls to the configured services over to
+// the secondary region
+func NewClient(c client.Client, opts ...Option) client.Client {
+	options := Options{
+		Threshold:     DefaultThreshold,
+		Cooldown:      DefaultCooldown,
+		Topic:         DefaultTopic,
+		EventInterval: DefaultEventInterval,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	services := make(map[string]bool, len(options.Services))
+	for _, s := range options.Services {
+		services[s] = true
+	}
+
+	return &failoverClient{
+		Client:    c,
+		opts:      options,
+		services:  services,
+		states:    make(map[string]*state),
+		published: make(map[string]*published),
+	}
+}
+
+func (f *failoverClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if len(f.opts.Address) == 0 || !f.services[req.Service()] {
+		return f.Client.Call(ctx, req, rsp, opts...)
+	}
+
+	// skip the local region whilst the threshold is exceeded
+	if f.tripped(req.Service()) {
+		f.record(req, nil, false)
+		return f.callSecondary(ctx, req, rsp, opts...)
+	}
+
+	err := f.Client.Call(ctx, req, rsp, opts...)
+	if !shouldFailover(err) {
+		f.reset(req.Service())
+		return err
+	}
+
+	tripped := f.fail(req.Service())
+	f.record(req, err, tripped)
+	return f.callSecondary(ctx, req, rsp, opts...)
+}
+
+// Stream fails over like Call when the stream can't be opened in the local region. Streams
+// which fail once they're open aren't failed over, since messages may have been sent.
+func (f *failoverClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	if len(f.opts.Address) == 0 || !f.services[req.Service()] {
+		return f.Client.Stream(ctx, req, opts...)
+	}
+
+	// skip the local region whilst the threshold is exceeded
+	if f.tripped(req.Service()) {
+		f.record(req, nil, false)
+		return f.streamSecondary(ctx, req, opts...)
+	}
+
+	stream, err := f.Client.Stream(ctx, req, opts...)
+	if !shouldFailover(err) {
+		f.reset(req.Service())
+		return stream, err
+	}
+
+	tripped := f.fail(req.Service())
+	f.record(req, err, tripped)
+	return f.streamSecondary(ctx, req, opts...)
+}
+
+// callSecondary executes the call against the secondary region
+func (f *failoverClient) callSecondary(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	ctx, opts, err := f.secondary(ctx, req, opts)
+	if err != nil {
+		return err
+	}
+	return f.Client.Call(ctx, req, rsp, opts...)
+}
+
+// streamSecondary opens the stream in the secondary region
+func (f *failoverClient) streamSecondary(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	ctx, opts, err := f.secondary(ctx, req, opts)
+	if err != nil {
+		return nil, err
+	}
+	return f.Client.Stream(ctx, req, opts...)
+}
+
+// secondary returns the context and options of a request to the secondary region
+func (f *failoverClient) secondary(ctx context.Context, req client.Request, opts []client.CallOption) (context.Context, []client.CallOption, error) {
+	opts = append(opts, client.WithAddress(f.opts.Address))
+
+	if f.opts.Token != nil {
+		tok, err := f.opts.Token()
+		if err != nil {
+			return nil, nil, errors.Unauthorized(req.Service(), "Error getting token for secondary region: %v", err)
+		}
+
+		// don't let the auth wrapper override the token for the secondary region
+		ctx = metadata.Set(ctx, "Authorization", goauth.BearerScheme+tok)
+		opts = append(opts, func(o *client.CallOptions) { o.AuthToken = false })
+	}
+
+	return ctx, opts, nil
+}
+
+// tripped returns true if calls to the service should skip the local region
+func (f *failoverClient) tripped(service string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	s, ok := f.states[service]
+	if !ok || s.tripped.IsZero() {
+		return false
+	}
+	if time.Since(s.tripped) < f.opts.Cooldown {
+		return true
+	}
+
+	// the cooldown has passed, give the local region another chance
+	s.tripped = time.Time{}
+	s.failures = 0
+	return false
+}
+
+// fail records a failure and returns true if the threshold was exceeded
+func (f *failoverClient) fail(service string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	s, ok := f.states[service]
+	if !ok {
+		s = &state{}
+		f.states[service] = s
+	}
+	s.failures++
+
+	if f.opts.Threshold > 0 && s.failures >= f.opts.Threshold && s.tripped.IsZero() {
+		s.tripped = time.Now()
+		return true
+	}
+	return false
+}
+
+// reset the failures for the service
+func (f *failoverClient) reset(service string) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.states, service)
+}
+
+// record the failover, publishing an event for alerting unless one was published for the
+// service within the event interval, so an outage doesn't publish an event per call
+func (f *failoverClient) record(req client.Request, err error, tripped bool) {
+	if tripped {
+		logger.Warnf("Failure threshold exceeded for %v, failing over to %v for %v", req.Service(), f.opts.Address, f.opts.Cooldown)
+	} else {
+		logger.Debugf("Failing over call to %v.%v to %v", req.Service(), req.Endpoint(), f.opts.Address)
+	}
+
+	if len(f.opts.Topic) == 0 {
+		return
+	}
+	calls, ok := f.publish(req.Service(), tripped)
+	if !ok {
+		return
+	}
+
+	ev := &Event{
+		Service:   req.Service(),
+		Endpoint:  req.Endpoint(),
+		Address:   f.opts.Address,
+		Tripped:   tripped,
+		Calls:     calls,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	go func() {
+		if err := events.Publish(f.opts.Topic, ev); err != nil {
+			logger.Debugf("Error publishing failover event: %v", err)
+		}
+	}()
+}
+
+// publish counts the call failed over and returns true if an event should be published, with
+// the number of calls failed over since the previous event. Tripping the threshold is always
+// published.
+func (f *failoverClient) publish(service string, tripped bool) (int, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	p, ok := f.published[service]
+	if !ok {
+		p = &published{}
+		f.published[service] = p
+	}
+	p.calls++
+
+	if !tripped && !p.last.IsZero() && time.Since(p.last) < f.opts.EventInterval {
+		return 0, false
+	}
+	calls := p.calls
+	p.calls = 0
+	p.last = time.Now()
+	return calls, true
+}
+
+// shouldFailover returns true if the error indicates the local region is unavailable,
+// errors caused by the request itself are not retried in the secondary region
+func shouldFailover(err error) bool {
+	if err == nil {
+		return false
+	}
+	verr := errors.Parse(err)
+	if verr == nil {
+		return true
+	}
+	return verr.Code == 0 || verr.Code == 408 || verr.Code >= 500
+}
@@ -0,0 +1,122 @@
+package failover
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testClient struct {
+	client.Client
+	local     int
+	secondary int
+	err       error
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if len(options.Address) > 0 {
+		t.secondary++
+		return nil
+	}
+	t.local++
+	return t.err
+}
+
+func (t *testClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	if err := t.Call(ctx, req, nil, opts...); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func TestFailover(t *testing.T) {
+	c := &testClient{Client: mucp.NewClient()}
+	f := NewClient(c, Address("secondary:8081"), Services("foo"), Threshold(2), Cooldown(time.Hour), Topic(""))
+
+	t.Run("Success", func(t *testing.T) {
+		if err := f.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if c.local != 1 || c.secondary != 0 {
+			t.Fatalf("Expected the call to be served locally, got %v local and %v secondary", c.local, c.secondary)
+		}
+	})
+
+	t.Run("BadRequest", func(t *testing.T) {
+		c.local, c.secondary = 0, 0
+		c.err = errors.BadRequest("foo", "bad request")
+		if err := f.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), nil); err == nil {
+			t.Fatalf("Expected the bad request error to be returned")
+		}
+		if c.secondary != 0 {
+			t.Fatalf("Expected bad requests not to fail over")
+		}
+	})
+
+	t.Run("OtherService", func(t *testing.T) {
+		c.local, c.secondary = 0, 0
+		c.err = errors.InternalServerError("bar", "unavailable")
+		if err := f.Call(context.TODO(), c.NewRequest("bar", "Bar.Baz", nil), nil); err == nil {
+			t.Fatalf("Expected the error to be returned")
+		}
+		if c.secondary != 0 {
+			t.Fatalf("Expected services not configured not to fail over")
+		}
+	})
+
+	t.Run("Threshold", func(t *testing.T) {
+		c.local, c.secondary = 0, 0
+		c.err = errors.InternalServerError("foo", "unavailable")
+		for i := 0; i < 4; i++ {
+			if err := f.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if c.local != 2 || c.secondary != 4 {
+			t.Fatalf("Expected local region to be skipped after the threshold, got %v local and %v secondary", c.local, c.secondary)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		c.local, c.secondary = 0, 0
+		c.err = errors.InternalServerError("baz", "unavailable")
+		f := NewClient(c, Address("secondary:8081"), Services("baz"), Threshold(1), Cooldown(time.Hour), Topic(""))
+
+		for i := 0; i < 2; i++ {
+			if _, err := f.Stream(context.TODO(), c.NewRequest("baz", "Baz.Stream", nil)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if c.local != 1 || c.secondary != 2 {
+			t.Fatalf("Expected the stream to fail over, got %v local and %v secondary", c.local, c.secondary)
+		}
+	})
+}
+
+func TestPublish(t *testing.T) {
+	f := NewClient(&testClient{}, EventInterval(time.Hour)).(*failoverClient)
+
+	if calls, ok := f.publish("foo", false); !ok || calls != 1 {
+		t.Fatalf("Expected the first failover to be published, got %v %v", calls, ok)
+	}
+	for i := 0; i < 3; i++ {
+		if _, ok := f.publish("foo", false); ok {
+			t.Fatalf("Expected failovers within the interval not to be published")
+		}
+	}
+	if _, ok := f.publish("bar", false); !ok {
+		t.Fatalf("Expected the events of each service to be limited separately")
+	}
+	if calls, ok := f.publish("foo", true); !ok || calls != 4 {
+		t.Fatalf("Expected tripping the threshold to be published with 4 calls, got %v %v", calls, ok)
+	}
+}
@@ -0,0 +1,77 @@
+package failover
+
+import "time"
+
+// Options for failing over calls to a secondary region
+type Options struct {
+	// Address of the secondary region's proxy or gateway
+	Address string
+	// Services which are permitted to fail over
+	Services []string
+	// Threshold is the number of consecutive failures in the local region after
+	// which calls are sent straight to the secondary region
+	Threshold int
+	// Cooldown is how long calls are sent to the secondary region once the
+	// threshold has been exceeded, after which the local region is tried again
+	Cooldown time.Duration
+	// Token returns the token used to authenticate with the secondary region. If
+	// nil, the client's own auth token is used
+	Token func() (string, error)
+	// Topic failover events are published to, leave blank to disable
+	Topic string
+	// EventInterval is the minimum interval between the events published for a service,
+	// the calls failed over in between are counted in the next event
+	EventInterval time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Address of the secondary region
+func Address(a string) Option {
+	return func(o *Options) {
+		o.Address = a
+	}
+}
+
+// Services which can fail over
+func Services(s ...string) Option {
+	return func(o *Options) {
+		o.Services = append(o.Services, s...)
+	}
+}
+
+// Threshold of consecutive failures before calls go straight to the secondary region
+func Threshold(n int) Option {
+	return func(o *Options) {
+		o.Threshold = n
+	}
+}
+
+// Cooldown before the local region is tried again
+func Cooldown(t time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = t
+	}
+}
+
+// Token used to authenticate with the secondary region
+func Token(fn func() (string, error)) Option {
+	return func(o *Options) {
+		o.Token = fn
+	}
+}
+
+// Topic to publish failover events to
+func Topic(t string) Option {
+	return func(o *Options) {
+		o.Topic = t
+	}
+}
+
+// EventInterval is the minimum interval between the events published for a service
+func EventInterval(t time.Duration) Option {
+	return func(o *Options) {
+		o.EventInterval = t
+	}
+}
@@ -0,0 +1,153 @@
+// Package hedge provides a client which hedges calls to reduce tail latency. When a call is
+// made with the Hedge option and hasn't returned after the delay, a second attempt is sent to
+// a different node and whichever response arrives first is used, the other being cancelled.
+// Since the call may be served twice, only read-only calls should be hedged.
+package hedge
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+type hedgeKey struct{}
+
+// Hedge the call, sending a second attempt to a different node if there's no response after
+// the delay
+func Hedge(delay time.Duration) client.CallOption {
+	return func(o *client.CallOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, hedgeKey{}, delay)
+	}
+}
+
+// delayFromOptions returns the hedging delay set on the call options, zero if not hedged
+func delayFromOptions(o client.CallOptions) time.Duration {
+	if o.Context == nil {
+		return 0
+	}
+	d, _ := o.Context.Value(hedgeKey{}).(time.Duration)
+	return d
+}
+
+type hedgeClient struct {
+	client.Client
+}
+
+// NewClient returns a client which hedges the calls made with the Hedge option
+func NewClient(c client.Client) client.Client {
+	return &hedgeClient{Client: c}
+}
+
+// result of an attempt
+type result struct {
+	rsp    interface{}
+	err    error
+	hedged bool
+}
+
+func (h *hedgeClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// the response must be a pointer so each attempt can decode into its own copy
+	delay := delayFromOptions(options)
+	if delay <= 0 || reflect.ValueOf(rsp).Kind() != reflect.Ptr {
+		return h.Client.Call(ctx, req, rsp, opts...)
+	}
+
+	// record the node the first attempt was sent to so the hedge can avoid it
+	var mtx sync.Mutex
+	var first string
+	record := client.WithCallWrapper(func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, addr string, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			mtx.Lock()
+			if len(first) == 0 {
+				first = addr
+			}
+			mtx.Unlock()
+			return cf(ctx, addr, req, rsp, opts)
+		}
+	})
+
+	results := make(chan result, 2)
+	attempt := func(ctx context.Context, hedged bool, extra ...client.CallOption) {
+		r := reflect.New(reflect.TypeOf(rsp).Elem()).Interface()
+		err := h.Client.Call(ctx, req, r, append(opts[:len(opts):len(opts)], extra...)...)
+		results <- result{rsp: r, err: err, hedged: hedged}
+	}
+
+	// cancelling the context cancels whichever attempt lost
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go attempt(ctx, false, record)
+	pending := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	hedge := timer.C
+
+	for {
+		select {
+		case <-hedge:
+			hedge = nil
+
+			mtx.Lock()
+			addr := first
+			mtx.Unlock()
+
+			var extra []client.CallOption
+			if others := h.others(ctx, req, options, addr); len(others) > 0 {
+				extra = append(extra, client.WithAddress(others...))
+			}
+			logger.Debugf("Hedging call to %v.%v after %v", req.Service(), req.Endpoint(), delay)
+			go attempt(ctx, true, extra...)
+			pending++
+		case r := <-results:
+			pending--
+
+			if r.err == nil {
+				if r.hedged {
+					logger.Debugf("Hedged call to %v.%v returned first", req.Service(), req.Endpoint())
+				}
+				reflect.ValueOf(rsp).Elem().Set(reflect.ValueOf(r.rsp).Elem())
+				return nil
+			}
+
+			// the first attempt failed before the call was hedged, it has already been retried
+			// by the client so don't hedge it
+			if hedge != nil || pending == 0 {
+				return r.err
+			}
+		}
+	}
+}
+
+// others returns the addresses of the service other than the one given, or nil if there are
+// none, in which case the hedge is sent to whichever node the client selects
+func (h *hedgeClient) others(ctx context.Context, req client.Request, opts client.CallOptions, addr string) []string {
+	lookup := h.Client.Options().Lookup
+	if lookup == nil {
+		return nil
+	}
+	addrs, err := lookup(ctx, req, opts)
+	if err != nil {
+		return nil
+	}
+
+	var others []string
+	for _, a := range addrs {
+		if a != addr {
+			others = append(others, a)
+		}
+	}
+	return others
+}
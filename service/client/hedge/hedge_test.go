@@ -0,0 +1,162 @@
+package hedge
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testResponse struct {
+	Node string
+}
+
+// testClient sends calls to node "a" unless an address is set, each node responding after
+// its latency
+type testClient struct {
+	client.Client
+	latency map[string]time.Duration
+	errs    map[string]error
+
+	sync.Mutex
+	calls     []string
+	cancelled []string
+}
+
+func (t *testClient) Options() client.Options {
+	opts := t.Client.Options()
+	opts.Lookup = func(ctx context.Context, req client.Request, o client.CallOptions) ([]string, error) {
+		if len(o.Address) > 0 {
+			return o.Address, nil
+		}
+		return []string{"a", "b"}, nil
+	}
+	return opts
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	cf := func(ctx context.Context, addr string, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		t.Lock()
+		t.calls = append(t.calls, addr)
+		t.Unlock()
+
+		select {
+		case <-time.After(t.latency[addr]):
+		case <-ctx.Done():
+			t.Lock()
+			t.cancelled = append(t.cancelled, addr)
+			t.Unlock()
+			return ctx.Err()
+		}
+
+		if err := t.errs[addr]; err != nil {
+			return err
+		}
+		rsp.(*testResponse).Node = addr
+		return nil
+	}
+	for i := len(options.CallWrappers); i > 0; i-- {
+		cf = options.CallWrappers[i-1](cf)
+	}
+
+	addr := "a"
+	if len(options.Address) > 0 {
+		addr = options.Address[0]
+	}
+	return cf(ctx, addr, req, rsp, options)
+}
+
+func TestHedge(t *testing.T) {
+	t.Run("NotHedged", func(t *testing.T) {
+		c := &testClient{Client: mucp.NewClient(), latency: map[string]time.Duration{"a": time.Millisecond * 50}}
+		h := NewClient(c)
+
+		var rsp testResponse
+		if err := h.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), &rsp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(c.calls) != 1 || rsp.Node != "a" {
+			t.Fatalf("Expected a single call to a, got %v", c.calls)
+		}
+	})
+
+	t.Run("FastPrimary", func(t *testing.T) {
+		c := &testClient{Client: mucp.NewClient()}
+		h := NewClient(c)
+
+		var rsp testResponse
+		if err := h.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), &rsp, Hedge(time.Millisecond*50)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(c.calls) != 1 || rsp.Node != "a" {
+			t.Fatalf("Expected a single call to a, got %v", c.calls)
+		}
+	})
+
+	t.Run("SlowPrimary", func(t *testing.T) {
+		c := &testClient{Client: mucp.NewClient(), latency: map[string]time.Duration{"a": time.Second}}
+		h := NewClient(c)
+
+		start := time.Now()
+		var rsp testResponse
+		if err := h.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), &rsp, Hedge(time.Millisecond*10)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rsp.Node != "b" {
+			t.Fatalf("Expected the response from b, got %v", rsp.Node)
+		}
+		if time.Since(start) >= time.Second {
+			t.Fatalf("Expected the call not to wait for the slow node")
+		}
+
+		// the slow attempt should be cancelled
+		time.Sleep(time.Millisecond * 50)
+		c.Lock()
+		defer c.Unlock()
+		if len(c.cancelled) != 1 || c.cancelled[0] != "a" {
+			t.Fatalf("Expected the attempt to a to be cancelled, got %v", c.cancelled)
+		}
+	})
+
+	t.Run("HedgeFails", func(t *testing.T) {
+		c := &testClient{
+			Client:  mucp.NewClient(),
+			latency: map[string]time.Duration{"a": time.Millisecond * 50},
+			errs:    map[string]error{"b": errors.InternalServerError("foo", "unavailable")},
+		}
+		h := NewClient(c)
+
+		var rsp testResponse
+		if err := h.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), &rsp, Hedge(time.Millisecond*10)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rsp.Node != "a" {
+			t.Fatalf("Expected the response from a, got %v", rsp.Node)
+		}
+	})
+
+	t.Run("PrimaryFailsFirst", func(t *testing.T) {
+		c := &testClient{
+			Client: mucp.NewClient(),
+			errs:   map[string]error{"a": errors.BadRequest("foo", "bad request")},
+		}
+		h := NewClient(c)
+
+		var rsp testResponse
+		if err := h.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), &rsp, Hedge(time.Millisecond*50)); err == nil {
+			t.Fatalf("Expected the error to be returned")
+		}
+		if len(c.calls) != 1 {
+			t.Fatalf("Expected the call not to be hedged, got %v", c.calls)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/micro/go-micro/v3/client"
+)
+
+// Before is called before a request is sent. The context returned is used for the call, so
+// metadata can be added to it with the metadata package. Returning an error aborts the call.
+type Before func(ctx context.Context, req client.Request) (context.Context, error)
+
+// After is called once the call returns with the response, which is nil for streams, and the
+// error of the call. The error returned is the one returned to the caller.
+type After func(ctx context.Context, req client.Request, rsp interface{}, err error) error
+
+// Interceptor hooks into the requests made by the default client. Either hook can be nil.
+type Interceptor struct {
+	Before Before
+	After  After
+}
+
+var (
+	interceptMtx sync.RWMutex
+	interceptors []Interceptor
+)
+
+// Intercept the requests made by the default client. The Before hooks are called in the order
+// the interceptors were added and the After hooks in reverse, so the first interceptor added
+// sees the request first and the response last.
+func Intercept(i ...Interceptor) {
+	interceptMtx.Lock()
+	defer interceptMtx.Unlock()
+	interceptors = append(interceptors, i...)
+}
+
+func getInterceptors() []Interceptor {
+	interceptMtx.RLock()
+	defer interceptMtx.RUnlock()
+	return interceptors
+}
+
+type interceptClient struct {
+	client.Client
+}
+
+// InterceptClient returns a client which calls the interceptors added with Intercept
+func InterceptClient(c client.Client) client.Client {
+	return &interceptClient{Client: c}
+}
+
+func (i *interceptClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	chain := getInterceptors()
+	if len(chain) == 0 {
+		return i.Client.Call(ctx, req, rsp, opts...)
+	}
+
+	ctx, n, err := before(ctx, req, chain)
+	if err == nil {
+		err = i.Client.Call(ctx, req, rsp, opts...)
+	}
+	return after(ctx, req, rsp, err, chain[:n])
+}
+
+func (i *interceptClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	chain := getInterceptors()
+	if len(chain) == 0 {
+		return i.Client.Stream(ctx, req, opts...)
+	}
+
+	ctx, n, err := before(ctx, req, chain)
+	var stream client.Stream
+	if err == nil {
+		stream, err = i.Client.Stream(ctx, req, opts...)
+	}
+	if err = after(ctx, req, nil, err, chain[:n]); err != nil {
+		if stream != nil {
+			stream.Close()
+		}
+		return nil, err
+	}
+	return stream, nil
+}
+
+// before calls the Before hooks in order, stopping at the first error. The number of hooks
+// called is returned so only the After hooks of those interceptors are called.
+func before(ctx context.Context, req client.Request, chain []Interceptor) (context.Context, int, error) {
+	for n, i := range chain {
+		if i.Before == nil {
+			continue
+		}
+		c, err := i.Before(ctx, req)
+		if err != nil {
+			return ctx, n, err
+		}
+		ctx = c
+	}
+	return ctx, len(chain), nil
+}
+
+// after calls the After hooks in reverse order, each receiving the error returned by the last
+func after(ctx context.Context, req client.Request, rsp interface{}, err error, chain []Interceptor) error {
+	for n := len(chain) - 1; n >= 0; n-- {
+		if chain[n].After != nil {
+			err = chain[n].After(ctx, req, rsp, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/go-micro/v3/metadata"
+)
+
+type testClient struct {
+	client.Client
+	md  metadata.Metadata
+	err error
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.md, _ = metadata.FromContext(ctx)
+	return t.err
+}
+
+func TestIntercept(t *testing.T) {
+	defer func() { interceptors = nil }()
+
+	var order []string
+	Intercept(Interceptor{
+		Before: func(ctx context.Context, req client.Request) (context.Context, error) {
+			order = append(order, "before1")
+			return metadata.Set(ctx, "Foo", "bar"), nil
+		},
+		After: func(ctx context.Context, req client.Request, rsp interface{}, err error) error {
+			order = append(order, "after1")
+			return err
+		},
+	}, Interceptor{
+		After: func(ctx context.Context, req client.Request, rsp interface{}, err error) error {
+			order = append(order, "after2")
+			if err != nil {
+				return errors.New("wrapped: " + err.Error())
+			}
+			return nil
+		},
+	})
+
+	c := &testClient{Client: mucp.NewClient(), err: errors.New("failed")}
+	ic := InterceptClient(c)
+
+	err := ic.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), nil)
+	if err == nil || err.Error() != "wrapped: failed" {
+		t.Errorf("Expected the error returned by the interceptor, got %v", err)
+	}
+	if v, _ := c.md.Get("Foo"); v != "bar" {
+		t.Errorf("Expected the metadata set by the interceptor, got %v", c.md)
+	}
+
+	expected := []string{"before1", "after2", "after1"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, order)
+		}
+	}
+
+	t.Run("BeforeError", func(t *testing.T) {
+		order = nil
+		c.md = nil
+		Intercept(Interceptor{
+			Before: func(ctx context.Context, req client.Request) (context.Context, error) {
+				return ctx, errors.New("forbidden")
+			},
+			After: func(ctx context.Context, req client.Request, rsp interface{}, err error) error {
+				t.Errorf("Expected the after hook of the interceptor which errored not to be called")
+				return err
+			},
+		})
+
+		err := ic.Call(context.TODO(), c.NewRequest("foo", "Foo.Bar", nil), nil)
+		if err == nil || err.Error() != "wrapped: forbidden" {
+			t.Errorf("Expected the error returned before the call, got %v", err)
+		}
+		if c.md != nil {
+			t.Errorf("Expected the call not to be made")
+		}
+	})
+}
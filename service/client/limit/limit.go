@@ -0,0 +1,301 @@
+// Package limit provides a client which limits the size of the messages sent and received by
+// calls and the bandwidth of streams, so a single misbehaving endpoint can't exhaust the memory
+// of its callers. The per call limits are checked against the size of the encoded messages, and
+// the Options bound the messages the grpc transport accepts at all, which is what protects the
+// caller from a response too large to hold in memory. The limits of each call are passed to the
+// transport as grpc call options too.
+package limit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro/v3/client"
+	gclient "github.com/micro/go-micro/v3/client/grpc"
+	"github.com/micro/go-micro/v3/codec/bytes"
+	"github.com/micro/micro/v3/service/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// DefaultMaxSendSize is the maximum size in bytes of a message sent, zero for no limit
+	DefaultMaxSendSize = 0
+	// DefaultMaxRecvSize is the maximum size in bytes of a message received, zero for no limit
+	DefaultMaxRecvSize = 0
+	// DefaultStreamRate is the maximum number of bytes per second sent and received on a
+	// stream, zero for no limit
+	DefaultStreamRate = 0
+)
+
+type limitsKey struct{}
+
+// limits of a call
+type limits struct {
+	send int
+	recv int
+	rate int
+}
+
+func setLimit(o *client.CallOptions, fn func(l *limits)) {
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	l, ok := o.Context.Value(limitsKey{}).(limits)
+	if !ok {
+		l = limits{send: -1, recv: -1, rate: -1}
+	}
+	fn(&l)
+	o.Context = context.WithValue(o.Context, limitsKey{}, l)
+}
+
+// MaxSendSize limits the size in bytes of the messages sent by the call, zero for no limit
+func MaxSendSize(n int) client.CallOption {
+	return func(o *client.CallOptions) {
+		setLimit(o, func(l *limits) { l.send = n })
+	}
+}
+
+// MaxRecvSize limits the size in bytes of the messages received by the call, zero for no limit
+func MaxRecvSize(n int) client.CallOption {
+	return func(o *client.CallOptions) {
+		setLimit(o, func(l *limits) { l.recv = n })
+	}
+}
+
+// StreamRate limits the number of bytes per second sent and received on a stream, zero for no
+// limit
+func StreamRate(n int) client.CallOption {
+	return func(o *client.CallOptions) {
+		setLimit(o, func(l *limits) { l.rate = n })
+	}
+}
+
+// limitsFromOptions returns the limits of the call, falling back to the defaults
+func limitsFromOptions(opts []client.CallOption) limits {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	l := limits{send: -1, recv: -1, rate: -1}
+	if options.Context != nil {
+		if v, ok := options.Context.Value(limitsKey{}).(limits); ok {
+			l = v
+		}
+	}
+	if l.send < 0 {
+		l.send = DefaultMaxSendSize
+	}
+	if l.recv < 0 {
+		l.recv = DefaultMaxRecvSize
+	}
+	if l.rate < 0 {
+		l.rate = DefaultStreamRate
+	}
+	return l
+}
+
+// Options returns the client options which bound the size of the messages the grpc transport
+// sends and receives. Zero values leave the client's defaults in place.
+func Options(send, recv int) []client.Option {
+	var opts []client.Option
+	if send > 0 {
+		opts = append(opts, gclient.MaxSendMsgSize(send))
+	}
+	if recv > 0 {
+		opts = append(opts, gclient.MaxRecvMsgSize(recv))
+	}
+	if callOpts := grpcCallOptions(send, recv); len(callOpts) > 0 {
+		// the grpc client reads the grpc call options from its default call options
+		opts = append(opts, func(o *client.Options) {
+			gclient.CallOptions(callOpts...)(&o.CallOptions)
+		})
+	}
+	return opts
+}
+
+// grpcCallOptions returns the grpc call options which stop the transport sending or receiving
+// messages larger than the limits, before they're held in memory
+func grpcCallOptions(send, recv int) []grpc.CallOption {
+	var opts []grpc.CallOption
+	if send > 0 {
+		opts = append(opts, grpc.MaxCallSendMsgSize(send))
+	}
+	if recv > 0 {
+		opts = append(opts, grpc.MaxCallRecvMsgSize(recv))
+	}
+	return opts
+}
+
+// Size returns the encoded size of a message, zero if it can't be determined
+func Size(v interface{}) int {
+	switch m := v.(type) {
+	case nil:
+		return 0
+	case *bytes.Frame:
+		return len(m.Data)
+	case []byte:
+		return len(m)
+	case json.RawMessage:
+		return len(m)
+	case proto.Message:
+		return proto.Size(m)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+type limitClient struct {
+	client.Client
+}
+
+// NewClient returns a client which limits the size of messages and bandwidth of streams
+func NewClient(c client.Client) client.Client {
+	return &limitClient{Client: c}
+}
+
+func (l *limitClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	lim := limitsFromOptions(opts)
+	if err := checkSize(req, "request", req.Body(), lim.send); err != nil {
+		return err
+	}
+
+	// the transport rejects a response larger than the limit as it's received. Clients which
+	// don't apply grpc call options per call have the response checked once it's decoded.
+	if callOpts := grpcCallOptions(lim.send, lim.recv); len(callOpts) > 0 {
+		opts = append(opts, gclient.CallOptions(callOpts...))
+	}
+	if err := l.Client.Call(ctx, req, rsp, opts...); err != nil {
+		if isSizeError(err) {
+			return errors.RequestEntityTooLarge(req.Service(), "%v.%v: %v", req.Service(), req.Endpoint(), errors.Parse(err).Detail)
+		}
+		return err
+	}
+	return checkSize(req, "response", rsp, lim.recv)
+}
+
+// isSizeError returns true if the transport rejected a message larger than the limit
+func isSizeError(err error) bool {
+	if status.Code(err) == codes.ResourceExhausted {
+		return true
+	}
+	// the grpc client returns the status as a micro error
+	verr := errors.Parse(err)
+	return verr != nil && verr.Code == 500 && strings.Contains(verr.Detail, "larger than max")
+}
+
+func (l *limitClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	lim := limitsFromOptions(opts)
+	if lim.send == 0 && lim.recv == 0 && lim.rate == 0 {
+		return l.Client.Stream(ctx, req, opts...)
+	}
+
+	if callOpts := grpcCallOptions(lim.send, lim.recv); len(callOpts) > 0 {
+		opts = append(opts, gclient.CallOptions(callOpts...))
+	}
+	stream, err := l.Client.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &limitStream{Stream: stream, req: req, limits: lim}
+	if lim.rate > 0 {
+		s.sendRate = &rate{bytesPerSec: lim.rate}
+		s.recvRate = &rate{bytesPerSec: lim.rate}
+	}
+	return s, nil
+}
+
+// checkSize returns an error if the message is larger than the limit
+func checkSize(req client.Request, kind string, msg interface{}, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if n := Size(msg); n > limit {
+		return errors.RequestEntityTooLarge(req.Service(), "%v.%v %v of %v bytes exceeds the limit of %v bytes", req.Service(), req.Endpoint(), kind, n, limit)
+	}
+	return nil
+}
+
+type limitStream struct {
+	client.Stream
+	req    client.Request
+	limits limits
+
+	sendRate *rate
+	recvRate *rate
+}
+
+func (s *limitStream) Send(msg interface{}) error {
+	if err := checkSize(s.req, "message", msg, s.limits.send); err != nil {
+		return err
+	}
+	if s.sendRate != nil {
+		if err := s.sendRate.wait(s.Context(), Size(msg)); err != nil {
+			return err
+		}
+	}
+	return s.Stream.Send(msg)
+}
+
+func (s *limitStream) Recv(msg interface{}) error {
+	if err := s.Stream.Recv(msg); err != nil {
+		return err
+	}
+	if err := checkSize(s.req, "message", msg, s.limits.recv); err != nil {
+		// the server isn't honouring the limit, stop it sending any more
+		s.Stream.Close()
+		return err
+	}
+	if s.recvRate != nil {
+		// delaying the next read applies back pressure to the server through the transport's
+		// flow control
+		return s.recvRate.wait(s.Context(), Size(msg))
+	}
+	return nil
+}
+
+// rate paces the bytes transferred so they don't exceed the bytes per second on average
+type rate struct {
+	bytesPerSec int
+
+	sync.Mutex
+	start time.Time
+	total int
+}
+
+// wait until the bytes can be transferred within the rate
+func (r *rate) wait(ctx context.Context, n int) error {
+	r.Lock()
+	now := time.Now()
+	if r.start.IsZero() {
+		r.start = now
+	}
+	r.total += n
+	due := r.start.Add(time.Duration(float64(r.total) / float64(r.bytesPerSec) * float64(time.Second)))
+	r.Unlock()
+
+	d := due.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,98 @@
+package limit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	gclient "github.com/micro/go-micro/v3/client/grpc"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testClient struct {
+	client.Client
+	rsp   string
+	err   error
+	calls int
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls++
+	if t.err != nil {
+		return t.err
+	}
+	*rsp.(*string) = t.rsp
+	return nil
+}
+
+func TestCall(t *testing.T) {
+	tc := &testClient{Client: mucp.NewClient(), rsp: "0123456789"}
+	c := NewClient(tc)
+	req := c.NewRequest("foo", "Foo.Bar", "request")
+
+	var rsp string
+	if err := c.Call(context.TODO(), req, &rsp); err != nil {
+		t.Fatalf("Unexpected error without limits: %v", err)
+	}
+
+	// the request is encoded as "request" with quotes, 9 bytes
+	err := c.Call(context.TODO(), req, &rsp, MaxSendSize(8))
+	if verr := errors.Parse(err); verr == nil || verr.Code != 413 {
+		t.Fatalf("Expected a 413 error sending the request, got %v", err)
+	}
+	if tc.calls != 1 {
+		t.Errorf("Expected the request not to be sent")
+	}
+
+	err = c.Call(context.TODO(), req, &rsp, MaxRecvSize(8))
+	if verr := errors.Parse(err); verr == nil || verr.Code != 413 {
+		t.Fatalf("Expected a 413 error receiving the response, got %v", err)
+	}
+
+	// responses the transport rejects for exceeding the limit are too large, not server errors
+	tc.err = errors.InternalServerError("go.micro.client", "grpc: received message larger than max (10 vs. 8)")
+	err = c.Call(context.TODO(), req, &rsp, MaxRecvSize(8))
+	if verr := errors.Parse(err); verr == nil || verr.Code != 413 {
+		t.Fatalf("Expected a 413 error from the transport, got %v", err)
+	}
+	tc.err = nil
+
+	// the call options take priority over the defaults
+	defer func() { DefaultMaxSendSize = 0 }()
+	DefaultMaxSendSize = 1
+	if err := c.Call(context.TODO(), req, &rsp, MaxSendSize(0)); err != nil {
+		t.Fatalf("Unexpected error with the limit disabled: %v", err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	if opts := Options(0, 0); len(opts) != 0 {
+		t.Errorf("Expected no options without limits, got %v", len(opts))
+	}
+
+	c := gclient.NewClient(Options(1024, 2048)...)
+	if c.Options().CallOptions.Context == nil {
+		t.Errorf("Expected the grpc call options to be set in the default call options")
+	}
+}
+
+func TestRate(t *testing.T) {
+	r := &rate{bytesPerSec: 1000}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := r.wait(context.TODO(), 50); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if d := time.Since(start); d < time.Millisecond*140 {
+		t.Errorf("Expected 150 bytes at 1000 bytes per second to take 150ms, took %v", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if err := r.wait(ctx, 1000); err != context.Canceled {
+		t.Errorf("Expected the wait to be canceled, got %v", err)
+	}
+}
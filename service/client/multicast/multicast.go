@@ -0,0 +1,211 @@
+// Package multicast fans a request out to every node of a service and gathers the responses,
+// for patterns such as invalidating caches or broadcasting admin commands where a call must
+// reach all the nodes rather than any one of them.
+package multicast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	muclient "github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+var (
+	// DefaultConcurrency is the number of nodes called at once
+	DefaultConcurrency = 10
+
+	// ErrNoNodes is returned by CallAll if the service has no nodes
+	ErrNoNodes = errors.New("no nodes found")
+)
+
+// Options for CallAll
+type Options struct {
+	// Client to make the calls with, defaults to the default client
+	Client client.Client
+	// Prefix calls every service whose name starts with the service passed to CallAll
+	Prefix bool
+	// Concurrency is the number of nodes called at once
+	Concurrency int
+	// CallOptions are passed to each call
+	CallOptions []client.CallOption
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// WithClient sets the client the calls are made with
+func WithClient(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// Prefix calls the nodes of every service whose name starts with the service passed to CallAll
+func Prefix() Option {
+	return func(o *Options) {
+		o.Prefix = true
+	}
+}
+
+// Concurrency sets the number of nodes called at once
+func Concurrency(n int) Option {
+	return func(o *Options) {
+		o.Concurrency = n
+	}
+}
+
+// WithCallOptions sets the options passed to each call
+func WithCallOptions(opts ...client.CallOption) Option {
+	return func(o *Options) {
+		o.CallOptions = append(o.CallOptions, opts...)
+	}
+}
+
+// Response of a node to the request
+type Response struct {
+	// Service and Version of the node
+	Service string
+	Version string
+	// Address of the node
+	Address string
+	// Response decoded into a new value of the type passed to CallAll, nil if the call failed
+	Response interface{}
+	// Error returned by the call
+	Error error
+}
+
+// Responses of the nodes, sorted by service and address
+type Responses []*Response
+
+// Err returns an error describing the nodes whose calls failed, nil if every call succeeded
+func (r Responses) Err() error {
+	var failed []string
+	for _, rsp := range r {
+		if rsp.Error != nil {
+			failed = append(failed, fmt.Sprintf("%v (%v): %v", rsp.Service, rsp.Address, rsp.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%v of %v nodes failed: %v", len(failed), len(r), strings.Join(failed, "; "))
+}
+
+// CallAll makes the request to every node of the service, decoding each node's response into a
+// new value of the type rsp points to. An error is only returned if the nodes couldn't be
+// looked up, the errors of the individual calls are set on the responses.
+func CallAll(ctx context.Context, service, endpoint string, req, rsp interface{}, opts ...Option) (Responses, error) {
+	options := Options{
+		Client:      muclient.DefaultClient,
+		Concurrency: DefaultConcurrency,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+
+	typ := reflect.TypeOf(rsp)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("response must be a pointer, got %T", rsp)
+	}
+
+	srvs, err := lookup(service, options.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// every node is called once, even if it's registered under several versions
+	var rsps Responses
+	seen := make(map[string]bool)
+	for _, srv := range srvs {
+		for _, node := range srv.Nodes {
+			if seen[node.Address] {
+				continue
+			}
+			seen[node.Address] = true
+			rsps = append(rsps, &Response{Service: srv.Name, Version: srv.Version, Address: node.Address})
+		}
+	}
+	if len(rsps) == 0 {
+		return nil, ErrNoNodes
+	}
+	sort.Slice(rsps, func(i, j int) bool {
+		if rsps[i].Service != rsps[j].Service {
+			return rsps[i].Service < rsps[j].Service
+		}
+		return rsps[i].Address < rsps[j].Address
+	})
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, options.Concurrency)
+	for _, r := range rsps {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *Response) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			// retrying would call another node, so calls are only made once
+			callOpts := append([]client.CallOption{client.WithRetries(0)}, options.CallOptions...)
+			callOpts = append(callOpts, client.WithAddress(r.Address))
+
+			out := reflect.New(typ.Elem()).Interface()
+			creq := options.Client.NewRequest(r.Service, endpoint, req)
+			if err := options.Client.Call(ctx, creq, out, callOpts...); err != nil {
+				r.Error = err
+				return
+			}
+			r.Response = out
+		}(r)
+	}
+	wg.Wait()
+
+	return rsps, nil
+}
+
+// lookup the services to call, with their nodes
+func lookup(service string, prefix bool) ([]*goregistry.Service, error) {
+	if !prefix {
+		srvs, err := registry.GetService(service)
+		if util.IsNotFound(err) {
+			return nil, ErrNoNodes
+		}
+		return srvs, err
+	}
+
+	list, err := registry.ListServices()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, srv := range list {
+		if strings.HasPrefix(srv.Name, service) {
+			names[srv.Name] = true
+		}
+	}
+
+	var srvs []*goregistry.Service
+	for name := range names {
+		s, err := registry.GetService(name)
+		if util.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		srvs = append(srvs, s...)
+	}
+	return srvs, nil
+}
@@ -0,0 +1,100 @@
+package multicast
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// testClient replies with the address of the node called, failing calls to the failing address
+type testClient struct {
+	client.Client
+	sync.Mutex
+	calls   []string
+	failing string
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	addr := options.Address[0]
+	t.Lock()
+	t.calls = append(t.calls, addr)
+	t.Unlock()
+
+	if addr == t.failing {
+		return errors.InternalServerError("foo", "node unavailable")
+	}
+	*rsp.(*map[string]string) = map[string]string{"address": addr}
+	return nil
+}
+
+func TestCallAll(t *testing.T) {
+	reg := memory.NewRegistry()
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	reg.Register(&goregistry.Service{Name: "foo", Version: "v1", Nodes: []*goregistry.Node{
+		{Id: "foo-1", Address: "127.0.0.1:1"},
+		{Id: "foo-2", Address: "127.0.0.1:2"},
+	}})
+	reg.Register(&goregistry.Service{Name: "foo", Version: "v2", Nodes: []*goregistry.Node{
+		{Id: "foo-3", Address: "127.0.0.1:3"},
+	}})
+	reg.Register(&goregistry.Service{Name: "foobar", Nodes: []*goregistry.Node{
+		{Id: "foobar-1", Address: "127.0.0.1:4"},
+	}})
+
+	t.Run("Service", func(t *testing.T) {
+		tc := &testClient{Client: mucp.NewClient(), failing: "127.0.0.1:2"}
+		rsps, err := CallAll(context.TODO(), "foo", "Foo.Invalidate", map[string]string{}, &map[string]string{}, WithClient(tc))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rsps) != 3 || len(tc.calls) != 3 {
+			t.Fatalf("Expected every node of foo to be called, got %v", tc.calls)
+		}
+		for _, r := range rsps {
+			if r.Address == tc.failing {
+				if r.Error == nil || r.Response != nil {
+					t.Errorf("Expected the call to %v to fail", r.Address)
+				}
+				continue
+			}
+			if r.Error != nil || (*r.Response.(*map[string]string))["address"] != r.Address {
+				t.Errorf("Unexpected response from %v: %v %v", r.Address, r.Response, r.Error)
+			}
+		}
+		if rsps.Err() == nil {
+			t.Errorf("Expected the failed call to be reported")
+		}
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		tc := &testClient{Client: mucp.NewClient()}
+		rsps, err := CallAll(context.TODO(), "foo", "Foo.Invalidate", map[string]string{}, &map[string]string{}, WithClient(tc), Prefix())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rsps) != 4 || rsps.Err() != nil {
+			t.Fatalf("Expected 4 successful calls, got %v: %v", len(rsps), rsps.Err())
+		}
+	})
+
+	t.Run("NoNodes", func(t *testing.T) {
+		tc := &testClient{Client: mucp.NewClient()}
+		if _, err := CallAll(context.TODO(), "bar", "Bar.Invalidate", nil, &map[string]string{}, WithClient(tc)); err != ErrNoNodes {
+			t.Fatalf("Expected ErrNoNodes, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+// Package negotiate provides a client which negotiates the content type of requests with the
+// services called. Requests are sent with a content type the service advertises it accepts,
+// and if a call fails because the service can't decode the request, it's retried with the
+// JSON or protobuf counterpart of its content type. The content type which worked is
+// remembered for the service, so only the first call pays for the fallback.
+package negotiate
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+var (
+	// DefaultTTL is how long the content type negotiated with a service is remembered for
+	DefaultTTL = time.Minute * 5
+
+	// counterparts are the content types requests are retried with when the service can't
+	// decode them
+	counterparts = map[string]string{
+		"application/protobuf":   "application/json",
+		"application/json":       "application/protobuf",
+		"application/grpc":       "application/grpc+json",
+		"application/grpc+proto": "application/grpc+json",
+		"application/grpc+json":  "application/grpc+proto",
+	}
+)
+
+// Counterpart returns the JSON or protobuf counterpart of the content type, blank if it has
+// none
+func Counterpart(ct string) string {
+	return counterparts[ct]
+}
+
+// negotiated content type of a service
+type negotiated struct {
+	from    string
+	to      string
+	expires time.Time
+}
+
+type negotiateClient struct {
+	client.Client
+
+	sync.RWMutex
+	services map[string]negotiated
+}
+
+// NewClient returns a client which negotiates the content type of requests with the services
+// called
+func NewClient(c client.Client) client.Client {
+	return &negotiateClient{Client: c, services: make(map[string]negotiated)}
+}
+
+func (n *negotiateClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	orig := req.ContentType()
+	if ct := n.contentType(req); ct != orig {
+		req = n.withContentType(req, ct)
+	}
+
+	err := n.Client.Call(ctx, req, rsp, opts...)
+	if err == nil || !isCodecError(err) {
+		return err
+	}
+
+	// retry with the counterpart of the content type the service couldn't decode
+	alt := Counterpart(req.ContentType())
+	if len(alt) == 0 {
+		return err
+	}
+	logger.Debugf("Retrying call to %v.%v with content type %v: %v", req.Service(), req.Endpoint(), alt, err)
+	if rerr := n.Client.Call(ctx, n.withContentType(req, alt), rsp, opts...); rerr != nil {
+		// return the original error, the fallback was a guess
+		return err
+	}
+
+	n.remember(req.Service(), orig, alt)
+	return nil
+}
+
+// contentType returns the content type to send the request with: the one negotiated with the
+// service, or its counterpart if the service advertises it only accepts that
+func (n *negotiateClient) contentType(req client.Request) string {
+	ct := req.ContentType()
+
+	alt := Counterpart(ct)
+	if len(alt) == 0 || registry.DefaultRegistry == nil {
+		return ct
+	}
+
+	n.RLock()
+	neg, ok := n.services[req.Service()]
+	n.RUnlock()
+	if ok && neg.from == ct && time.Now().Before(neg.expires) {
+		return neg.to
+	}
+
+	// check the content types the service advertises, remembering the result so the registry
+	// isn't queried on every call
+	srvs, err := registry.DefaultRegistry.GetService(req.Service())
+	if err != nil {
+		return ct
+	}
+	to := ct
+	if !accepts(srvs, ct) && accepts(srvs, alt) {
+		to = alt
+	}
+	n.remember(req.Service(), ct, to)
+	return to
+}
+
+func (n *negotiateClient) remember(service, from, to string) {
+	n.Lock()
+	defer n.Unlock()
+	n.services[service] = negotiated{from: from, to: to, expires: time.Now().Add(DefaultTTL)}
+}
+
+func (n *negotiateClient) withContentType(req client.Request, ct string) client.Request {
+	return n.Client.NewRequest(req.Service(), req.Endpoint(), req.Body(), client.WithContentType(ct))
+}
+
+// accepts returns true if any node of the services advertises it accepts the content type, or
+// doesn't advertise the content types it accepts
+func accepts(srvs []*goregistry.Service, ct string) bool {
+	for _, s := range srvs {
+		for _, node := range s.Nodes {
+			codecs := util.Codecs(node)
+			if codecs == nil {
+				return true
+			}
+			for _, c := range codecs {
+				if c == ct {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// codecError prefixes the detail of the error clients and servers return when they have no
+// codec for the content type of a request, before the request is handled
+const codecError = "Unsupported Content-Type: "
+
+// isCodecError returns true if the request wasn't handled because its content type has no
+// codec, so it's safe to retry however the endpoint behaves
+func isCodecError(err error) bool {
+	verr := errors.Parse(err)
+	return verr != nil && verr.Code == 500 && strings.HasPrefix(verr.Detail, codecError)
+}
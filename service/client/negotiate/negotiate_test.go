@@ -0,0 +1,99 @@
+package negotiate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// testClient only accepts JSON requests
+type testClient struct {
+	client.Client
+	calls []string
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls = append(t.calls, req.ContentType())
+	if req.ContentType() != "application/json" {
+		return errors.InternalServerError("foo", "Unsupported Content-Type: %v", req.ContentType())
+	}
+	return nil
+}
+
+func TestNegotiate(t *testing.T) {
+	reg := memory.NewRegistry()
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	t.Run("Fallback", func(t *testing.T) {
+		reg.Register(&goregistry.Service{Name: "foo", Nodes: []*goregistry.Node{{Id: "foo-1", Address: "127.0.0.1:1"}}})
+
+		tc := &testClient{Client: mucp.NewClient()}
+		c := NewClient(tc)
+		req := c.NewRequest("foo", "Foo.Bar", map[string]string{}, client.WithContentType("application/protobuf"))
+
+		if err := c.Call(context.TODO(), req, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tc.calls) != 2 || tc.calls[1] != "application/json" {
+			t.Fatalf("Expected the call to be retried with json, got %v", tc.calls)
+		}
+
+		// the content type is remembered for the service
+		tc.calls = nil
+		if err := c.Call(context.TODO(), req, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tc.calls) != 1 || tc.calls[0] != "application/json" {
+			t.Fatalf("Expected the call to be made with json, got %v", tc.calls)
+		}
+	})
+
+	t.Run("Advertised", func(t *testing.T) {
+		node := &goregistry.Node{Id: "bar-1", Address: "127.0.0.1:2"}
+		util.SetCodecs(node, []string{"application/json"})
+		reg.Register(&goregistry.Service{Name: "bar", Nodes: []*goregistry.Node{node}})
+
+		tc := &testClient{Client: mucp.NewClient()}
+		c := NewClient(tc)
+		req := c.NewRequest("bar", "Bar.Baz", map[string]string{}, client.WithContentType("application/protobuf"))
+
+		if err := c.Call(context.TODO(), req, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tc.calls) != 1 || tc.calls[0] != "application/json" {
+			t.Fatalf("Expected the call to be made with json, got %v", tc.calls)
+		}
+	})
+	t.Run("OtherErrors", func(t *testing.T) {
+		tc := &failingClient{Client: mucp.NewClient(), err: errors.InternalServerError("foo", "codec failed to encode the response")}
+		c := NewClient(tc)
+		req := c.NewRequest("foo", "Foo.Bar", map[string]string{}, client.WithContentType("application/json"))
+
+		if err := c.Call(context.TODO(), req, nil); err != tc.err {
+			t.Fatalf("Expected the error to be returned, got %v", err)
+		}
+		if tc.calls != 1 {
+			t.Fatalf("Expected the call not to be retried, got %v calls", tc.calls)
+		}
+	})
+}
+
+// failingClient returns the error for every call
+type failingClient struct {
+	client.Client
+	err   error
+	calls int
+}
+
+func (f *failingClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	f.calls++
+	return f.err
+}
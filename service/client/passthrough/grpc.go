@@ -0,0 +1,256 @@
+package passthrough
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/codec"
+	"github.com/micro/go-micro/v3/codec/bytes"
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	gmetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// dial the service, balancing over the addresses its target resolves to
+func dial(srv Service) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+	}
+	if srv.TLS {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	return grpc.Dial(srv.Address, opts...)
+}
+
+func (p *passthroughClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	srv, ok := p.service(req.Service())
+	if !ok {
+		return p.Client.Call(ctx, req, rsp, opts...)
+	}
+	if err := checkMessage(req.Service(), req.Body()); err != nil {
+		return err
+	}
+	conn, err := p.conn(req.Service(), srv)
+	if err != nil {
+		return errors.InternalServerError(req.Service(), "Error dialing %v: %v", srv.Address, err)
+	}
+
+	ctx, cancel := callContext(ctx, srv, opts)
+	defer cancel()
+
+	err = conn.Invoke(ctx, srv.Method(req.Endpoint()), req.Body(), rsp, grpc.ForceCodec(frameCodec{}))
+	return microError(req.Service(), err)
+}
+
+func (p *passthroughClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	srv, ok := p.service(req.Service())
+	if !ok {
+		return p.Client.Stream(ctx, req, opts...)
+	}
+	conn, err := p.conn(req.Service(), srv)
+	if err != nil {
+		return nil, errors.InternalServerError(req.Service(), "Error dialing %v: %v", srv.Address, err)
+	}
+
+	// the stream outlives this call so it's only bound by the context's deadline
+	ctx, cancel := context.WithCancel(outgoingContext(ctx, srv))
+	desc := &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+	stream, err := conn.NewStream(ctx, desc, srv.Method(req.Endpoint()), grpc.ForceCodec(frameCodec{}))
+	if err != nil {
+		cancel()
+		return nil, microError(req.Service(), err)
+	}
+	return &grpcStream{ctx: ctx, cancel: cancel, req: req, stream: stream}, nil
+}
+
+// callContext returns the context to make a call with, bound by the request timeout of the call
+func callContext(ctx context.Context, srv Service, opts []client.CallOption) (context.Context, context.CancelFunc) {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	ctx = outgoingContext(ctx, srv)
+	if options.RequestTimeout > 0 {
+		return context.WithTimeout(ctx, options.RequestTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// outgoingContext translates the micro metadata of the context to gRPC metadata. Internal micro
+// headers and the micro auth token aren't sent to services outside the platform.
+func outgoingContext(ctx context.Context, srv Service) context.Context {
+	md := gmetadata.MD{}
+	if mmd, ok := metadata.FromContext(ctx); ok {
+		for k, v := range mmd {
+			lk := strings.ToLower(k)
+			if strings.HasPrefix(lk, "micro-") || lk == "authorization" {
+				continue
+			}
+			md.Set(lk, v)
+		}
+	}
+	for k, v := range srv.Headers {
+		md.Set(strings.ToLower(k), v)
+	}
+	return gmetadata.NewOutgoingContext(ctx, md)
+}
+
+// checkMessage returns an error if the message can't be encoded for a plain gRPC service
+func checkMessage(service string, msg interface{}) error {
+	switch msg.(type) {
+	case proto.Message, *bytes.Frame:
+		return nil
+	}
+	return errors.BadRequest(service, "Requests to gRPC services must be protobuf messages, got %T", msg)
+}
+
+// frameCodec encodes protobuf messages and passes frames of encoded bytes through as is
+type frameCodec struct{}
+
+func (frameCodec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case *bytes.Frame:
+		return m.Data, nil
+	case proto.Message:
+		return proto.Marshal(m)
+	}
+	return nil, fmt.Errorf("unsupported message %T", v)
+}
+
+func (frameCodec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case *bytes.Frame:
+		m.Data = data
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("unsupported message %T", v)
+}
+
+func (frameCodec) Name() string {
+	return "proto"
+}
+
+// statusCodes maps gRPC status codes to the HTTP status codes of micro errors
+var statusCodes = map[codes.Code]int32{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.DeadlineExceeded:   http.StatusRequestTimeout,
+	codes.Canceled:           http.StatusRequestTimeout,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+}
+
+// microError translates a gRPC error to a micro error, so callers and the retry classifiers
+// handle them the same as errors from micro services
+func microError(service string, err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return errors.InternalServerError(service, err.Error())
+	}
+	code, ok := statusCodes[st.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	return goerrors.New(service, st.Message(), code)
+}
+
+// grpcStream is a micro stream over a plain gRPC stream
+type grpcStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	req    client.Request
+	stream grpc.ClientStream
+	err    error
+}
+
+func (s *grpcStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *grpcStream) Request() client.Request {
+	return s.req
+}
+
+func (s *grpcStream) Response() client.Response {
+	return &grpcResponse{stream: s.stream}
+}
+
+func (s *grpcStream) Send(msg interface{}) error {
+	if err := s.stream.SendMsg(msg); err != nil {
+		s.err = microError(s.req.Service(), err)
+		return s.err
+	}
+	return nil
+}
+
+func (s *grpcStream) Recv(msg interface{}) error {
+	if err := s.stream.RecvMsg(msg); err != nil {
+		s.err = microError(s.req.Service(), err)
+		return s.err
+	}
+	return nil
+}
+
+func (s *grpcStream) Error() error {
+	return s.err
+}
+
+func (s *grpcStream) Close() error {
+	err := s.stream.CloseSend()
+	s.cancel()
+	return err
+}
+
+// grpcResponse exposes the headers of a plain gRPC stream
+type grpcResponse struct {
+	stream grpc.ClientStream
+}
+
+func (r *grpcResponse) Codec() codec.Reader {
+	return nil
+}
+
+func (r *grpcResponse) Header() map[string]string {
+	md, err := r.stream.Header()
+	if err != nil {
+		return nil
+	}
+	hdr := make(map[string]string, len(md))
+	for k, v := range md {
+		hdr[k] = strings.Join(v, ",")
+	}
+	return hdr
+}
+
+func (r *grpcResponse) Read() ([]byte, error) {
+	var frame bytes.Frame
+	if err := r.stream.RecvMsg(&frame); err != nil {
+		return nil, err
+	}
+	return frame.Data, nil
+}
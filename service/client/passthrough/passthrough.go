@@ -0,0 +1,167 @@
+// Package passthrough provides a client which calls plain gRPC services, i.e. those not built
+// with micro, so external gRPC APIs can be consumed through the same client as micro services.
+// The services are read from the "client.passthrough" config, for example:
+//
+//	{
+//		"greeter": {"address": "dns:///greeter.example.com:443", "package": "helloworld", "tls": true},
+//		"health": {"address": "10.0.0.5:50051", "package": "grpc.health.v1"}
+//	}
+//
+// A call to the greeter service's Greeter.SayHello endpoint is then made as the gRPC method
+// /helloworld.Greeter/SayHello. Addresses are gRPC targets, so a dns:/// address is resolved
+// and balanced over the records of the name. Requests must be protobuf messages or frames of
+// encoded bytes since plain gRPC services don't negotiate the content type.
+package passthrough
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+	"google.golang.org/grpc"
+)
+
+var (
+	// DefaultPath is the path of the services in the config
+	DefaultPath = []string{"client", "passthrough"}
+)
+
+// Service is a plain gRPC service
+type Service struct {
+	// Address is the gRPC target of the service, e.g. host:port or dns:///host:port
+	Address string `json:"address"`
+	// Package is the proto package of the service, prepended to the endpoints called
+	Package string `json:"package"`
+	// TLS dials the service with TLS
+	TLS bool `json:"tls"`
+	// Headers are added to the metadata of each call, e.g. an API key
+	Headers map[string]string `json:"headers"`
+}
+
+// Method returns the gRPC method an endpoint such as Greeter.SayHello is called with
+func (s Service) Method(endpoint string) string {
+	if idx := strings.LastIndex(endpoint, "."); idx > 0 {
+		endpoint = endpoint[:idx] + "/" + endpoint[idx+1:]
+	}
+	if len(s.Package) > 0 {
+		return "/" + s.Package + "." + endpoint
+	}
+	return "/" + endpoint
+}
+
+// Parse the services from their JSON encoding
+func Parse(b []byte) (map[string]Service, error) {
+	var services map[string]Service
+	if err := json.Unmarshal(b, &services); err != nil {
+		return nil, err
+	}
+	for name, s := range services {
+		if len(s.Address) == 0 {
+			return nil, fmt.Errorf("missing address for %v", name)
+		}
+	}
+	return services, nil
+}
+
+// Source returns the encoded services, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the services from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+type passthroughClient struct {
+	client.Client
+	source Source
+
+	sync.RWMutex
+	// raw is the encoding the services were last parsed from
+	raw      []byte
+	services map[string]Service
+	// conns to the services keyed by name
+	conns map[string]*grpc.ClientConn
+}
+
+// NewClient returns a client which calls the plain gRPC services read from the source directly,
+// and other services with the client it wraps. The source is read on each call so services can
+// be added without restarting.
+func NewClient(c client.Client, source Source) client.Client {
+	return &passthroughClient{
+		Client: c,
+		source: source,
+		conns:  make(map[string]*grpc.ClientConn),
+	}
+}
+
+// service returns the plain gRPC service with the name, parsing the services if they've changed
+func (p *passthroughClient) service(name string) (Service, bool) {
+	raw := p.source()
+
+	p.RLock()
+	if bytes.Equal(raw, p.raw) {
+		srv, ok := p.services[name]
+		p.RUnlock()
+		return srv, ok
+	}
+	p.RUnlock()
+
+	p.Lock()
+	defer p.Unlock()
+
+	var services map[string]Service
+	if len(bytes.TrimSpace(raw)) > 0 && !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		parsed, err := Parse(raw)
+		if err != nil {
+			// keep using the last valid services
+			logger.Errorf("Error parsing passthrough services: %v", err)
+			p.raw = raw
+			srv, ok := p.services[name]
+			return srv, ok
+		}
+		services = parsed
+	}
+
+	// close the connections to services which were removed or changed
+	for n, conn := range p.conns {
+		if s, ok := services[n]; !ok || s.Address != p.services[n].Address || s.TLS != p.services[n].TLS {
+			conn.Close()
+			delete(p.conns, n)
+		}
+	}
+
+	p.raw = raw
+	p.services = services
+	srv, ok := p.services[name]
+	return srv, ok
+}
+
+// conn returns the connection to the service, dialing it if needed
+func (p *passthroughClient) conn(name string, srv Service) (*grpc.ClientConn, error) {
+	p.RLock()
+	conn, ok := p.conns[name]
+	p.RUnlock()
+	if ok {
+		return conn, nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if conn, ok := p.conns[name]; ok {
+		return conn, nil
+	}
+	conn, err := dial(srv)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[name] = conn
+	return conn, nil
+}
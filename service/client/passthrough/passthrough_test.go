@@ -0,0 +1,76 @@
+package passthrough
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testClient records the calls which weren't passed through
+type testClient struct {
+	client.Client
+	calls int
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls++
+	return nil
+}
+
+func TestMethod(t *testing.T) {
+	s := Service{Package: "helloworld"}
+	if m := s.Method("Greeter.SayHello"); m != "/helloworld.Greeter/SayHello" {
+		t.Errorf("Expected /helloworld.Greeter/SayHello, got %v", m)
+	}
+	if m := (Service{}).Method("Greeter.SayHello"); m != "/Greeter/SayHello" {
+		t.Errorf("Expected /Greeter/SayHello, got %v", m)
+	}
+}
+
+func TestPassthrough(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	hs.SetServingStatus("foo", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	source := func() []byte {
+		return []byte(`{"health": {"address": "` + lis.Addr().String() + `", "package": "grpc.health.v1"}}`)
+	}
+	tc := &testClient{Client: mucp.NewClient()}
+	c := NewClient(tc, source)
+
+	req := c.NewRequest("health", "Health.Check", &healthpb.HealthCheckRequest{Service: "foo"})
+	var rsp healthpb.HealthCheckResponse
+	if err := c.Call(context.TODO(), req, &rsp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rsp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected the service to be serving, got %v", rsp.Status)
+	}
+
+	// grpc status codes are translated to micro errors
+	req = c.NewRequest("health", "Health.Check", &healthpb.HealthCheckRequest{Service: "bar"})
+	err = c.Call(context.TODO(), req, &rsp)
+	if verr := errors.Parse(err); verr == nil || verr.Code != 404 {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+
+	// other services are called with the wrapped client
+	req = c.NewRequest("other", "Foo.Bar", map[string]string{})
+	if err := c.Call(context.TODO(), req, nil); err != nil || tc.calls != 1 {
+		t.Errorf("Expected the call to be made with the wrapped client, got %v", err)
+	}
+}
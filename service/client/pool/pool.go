@@ -0,0 +1,193 @@
+// Package pool observes the connection pool of the grpc client. The client is instrumented with
+// the Instrument options, which count the connections dialed and the calls in flight on them so
+// callers making many requests can tune the size, idle TTL and streams per connection of the
+// pool using the stats.
+package pool
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	gclient "github.com/micro/go-micro/v3/client/grpc"
+	"google.golang.org/grpc"
+)
+
+var (
+	// RateWindow is the window the dial rate is measured over
+	RateWindow = time.Minute
+)
+
+// Stat of the connection pool
+type Stat struct {
+	// Open is the number of connections open
+	Open int
+	// InUse is the number of open connections with calls in flight, to the best of our
+	// knowledge since the pool doesn't report which connection a call is made on
+	InUse int
+	// Idle is the number of open connections without calls in flight
+	Idle int
+	// Calls is the number of calls in flight
+	Calls int
+	// Dials and DialErrors since the client was created
+	Dials      uint64
+	DialErrors uint64
+	// DialRate is the number of dials per second over the rate window
+	DialRate float64
+}
+
+// Options returns the client options which size the pool. Zero values leave the client's
+// defaults in place.
+func Options(size int, ttl time.Duration, maxStreams int) []client.Option {
+	var opts []client.Option
+	if size > 0 {
+		opts = append(opts, client.PoolSize(size))
+	}
+	if ttl > 0 {
+		opts = append(opts, client.PoolTTL(ttl))
+	}
+	if maxStreams > 0 {
+		opts = append(opts, gclient.PoolMaxStreams(maxStreams))
+	}
+	return opts
+}
+
+// Instrument returns the client options which instrument the pool of a grpc client, recording
+// its stats in the default metrics
+func Instrument() []client.Option {
+	return DefaultMetrics.Options()
+}
+
+// Stats returns the stats of the default client's pool
+func Stats() Stat {
+	return DefaultMetrics.Stats()
+}
+
+// DefaultMetrics are the metrics of the default client's pool
+var DefaultMetrics = NewMetrics()
+
+// Metrics of a connection pool
+type Metrics struct {
+	sync.Mutex
+	// open connections and calls in flight keyed by address
+	open  map[string]int
+	calls map[string]int
+
+	dials      uint64
+	dialErrors uint64
+	// dialed are the times of the dials in the rate window
+	dialed []time.Time
+}
+
+// NewMetrics returns metrics to instrument a client with
+func NewMetrics() *Metrics {
+	return &Metrics{
+		open:  make(map[string]int),
+		calls: make(map[string]int),
+	}
+}
+
+// Options returns the client options which record the metrics. The grpc client reads its dial
+// options from the default call options, so the dialer is set there.
+func (m *Metrics) Options() []client.Option {
+	return []client.Option{
+		func(o *client.Options) {
+			gclient.DialOptions(grpc.WithContextDialer(m.dial))(&o.CallOptions)
+		},
+		client.WrapCall(m.wrapCall),
+	}
+}
+
+// Stats of the pool
+func (m *Metrics) Stats() Stat {
+	m.Lock()
+	defer m.Unlock()
+
+	m.trim(time.Now())
+	stat := Stat{
+		Dials:      m.dials,
+		DialErrors: m.dialErrors,
+		DialRate:   float64(len(m.dialed)) / RateWindow.Seconds(),
+	}
+	for addr, n := range m.open {
+		stat.Open += n
+		if m.calls[addr] > 0 {
+			stat.InUse += n
+		} else {
+			stat.Idle += n
+		}
+	}
+	for _, n := range m.calls {
+		stat.Calls += n
+	}
+	return stat
+}
+
+// dial a connection, counting it as open until it's closed
+func (m *Metrics) dial(ctx context.Context, addr string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	m.trim(now)
+	m.dials++
+	m.dialed = append(m.dialed, now)
+	if err != nil {
+		m.dialErrors++
+		return nil, err
+	}
+
+	m.open[addr]++
+	return &metricsConn{Conn: conn, addr: addr, metrics: m}, nil
+}
+
+// trim the dials which are outside the rate window
+func (m *Metrics) trim(now time.Time) {
+	var i int
+	for i < len(m.dialed) && now.Sub(m.dialed[i]) > RateWindow {
+		i++
+	}
+	m.dialed = m.dialed[i:]
+}
+
+func (m *Metrics) wrapCall(cf client.CallFunc) client.CallFunc {
+	return func(ctx context.Context, addr string, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		m.Lock()
+		m.calls[addr]++
+		m.Unlock()
+
+		defer func() {
+			m.Lock()
+			if m.calls[addr]--; m.calls[addr] <= 0 {
+				delete(m.calls, addr)
+			}
+			m.Unlock()
+		}()
+
+		return cf(ctx, addr, req, rsp, opts)
+	}
+}
+
+// metricsConn is a connection which is counted as open until it's closed
+type metricsConn struct {
+	net.Conn
+	addr    string
+	metrics *Metrics
+	once    sync.Once
+}
+
+func (c *metricsConn) Close() error {
+	c.once.Do(func() {
+		c.metrics.Lock()
+		if c.metrics.open[c.addr]--; c.metrics.open[c.addr] <= 0 {
+			delete(c.metrics.open, c.addr)
+		}
+		c.metrics.Unlock()
+	})
+	return c.Conn.Close()
+}
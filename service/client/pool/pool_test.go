@@ -0,0 +1,102 @@
+package pool
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	gclient "github.com/micro/go-micro/v3/client/grpc"
+)
+
+func TestMetrics(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	m := NewMetrics()
+	addr := l.Addr().String()
+
+	conn1, err := m.dial(context.TODO(), addr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn2, err := m.dial(context.TODO(), addr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.dial(context.TODO(), "127.0.0.1:1"); err == nil {
+		t.Fatalf("Expected an error dialing a closed port")
+	}
+
+	stat := m.Stats()
+	if stat.Open != 2 || stat.Idle != 2 || stat.InUse != 0 {
+		t.Errorf("Expected 2 idle connections, got %+v", stat)
+	}
+	if stat.Dials != 3 || stat.DialErrors != 1 || stat.DialRate <= 0 {
+		t.Errorf("Expected 3 dials with 1 error, got %+v", stat)
+	}
+
+	// the connections are in use whilst calls are in flight
+	call := m.wrapCall(func(ctx context.Context, a string, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		stat := m.Stats()
+		if stat.Calls != 1 || stat.InUse != 2 || stat.Idle != 0 {
+			t.Errorf("Expected the connections to be in use, got %+v", stat)
+		}
+		return nil
+	})
+	call(context.TODO(), addr, nil, nil, client.CallOptions{})
+
+	conn1.Close()
+	conn1.Close()
+	conn2.Close()
+	if stat := m.Stats(); stat.Open != 0 || stat.Calls != 0 {
+		t.Errorf("Expected no connections open or calls in flight, got %+v", stat)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	m := NewMetrics()
+	c := gclient.NewClient(m.Options()...)
+	if c.Options().CallOptions.Context == nil || len(c.Options().CallOptions.CallWrappers) != 1 {
+		t.Fatalf("Expected the dialer and call wrapper to be set, got %+v", c.Options().CallOptions)
+	}
+
+	// the listener doesn't speak grpc so the call fails, but only after dialing with the metrics
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := c.NewRequest("foo", "Foo.Bar", map[string]string{}, client.WithContentType("application/json"))
+	var rsp map[string]string
+	c.Call(ctx, req, &rsp, client.WithAddress(l.Addr().String()), client.WithRetries(0), client.WithRequestTimeout(time.Millisecond*500))
+
+	if stat := m.Stats(); stat.Dials == 0 {
+		t.Errorf("Expected the client to dial with the metrics, got %+v", stat)
+	}
+}
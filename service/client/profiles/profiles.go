@@ -0,0 +1,177 @@
+// Package profiles provides a client which applies call options configured per service, e.g.
+// a short timeout with retries for payments and a long timeout without retries for reports.
+// The profiles are read from the "client.profiles" config, for example:
+//
+//	{
+//		"payments": {"timeout": "2s", "retries": 3, "backoff": "100ms"},
+//		"reports": {"timeout": "30s", "retries": 0}
+//	}
+//
+// Options passed to the call take precedence over the profile.
+package profiles
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultPath is the path of the profiles in the config
+	DefaultPath = []string{"client", "profiles"}
+)
+
+// Profile of the options calls to a service are made with. Unset values use the client's
+// defaults.
+type Profile struct {
+	// Timeout of each request
+	Timeout time.Duration
+	// Retries is the number of times calls are retried, nil to use the client's default
+	Retries *int
+	// Backoff before the first retry, which doubles on each retry
+	Backoff time.Duration
+}
+
+// CallOptions returns the call options of the profile
+func (p Profile) CallOptions() []client.CallOption {
+	var opts []client.CallOption
+	if p.Timeout > 0 {
+		opts = append(opts, client.WithRequestTimeout(p.Timeout))
+	}
+	if p.Retries != nil {
+		opts = append(opts, client.WithRetries(*p.Retries))
+	}
+	if p.Backoff > 0 {
+		base := p.Backoff
+		opts = append(opts, client.WithBackoff(func(ctx context.Context, req client.Request, attempts int) (time.Duration, error) {
+			if attempts == 0 {
+				return 0, nil
+			}
+			return base << uint(attempts-1), nil
+		}))
+	}
+	return opts
+}
+
+// Parse the profiles from their JSON encoding, where durations are strings such as "2s"
+func Parse(b []byte) (map[string]Profile, error) {
+	var raw map[string]struct {
+		Timeout string `json:"timeout"`
+		Retries *int   `json:"retries"`
+		Backoff string `json:"backoff"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]Profile, len(raw))
+	for service, r := range raw {
+		p := Profile{Retries: r.Retries}
+		if len(r.Timeout) > 0 {
+			d, err := time.ParseDuration(r.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for %v: %v", service, err)
+			}
+			p.Timeout = d
+		}
+		if len(r.Backoff) > 0 {
+			d, err := time.ParseDuration(r.Backoff)
+			if err != nil {
+				return nil, fmt.Errorf("invalid backoff for %v: %v", service, err)
+			}
+			p.Backoff = d
+		}
+		if p.Retries != nil && *p.Retries < 0 {
+			return nil, fmt.Errorf("invalid retries for %v: %v", service, *p.Retries)
+		}
+		profiles[service] = p
+	}
+	return profiles, nil
+}
+
+// Source returns the encoded profiles, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the profiles from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+type profilesClient struct {
+	client.Client
+	source Source
+
+	sync.RWMutex
+	// raw is the encoding the profiles were last parsed from
+	raw      []byte
+	profiles map[string]Profile
+}
+
+// NewClient returns a client which applies the profile of the service being called, read from
+// the source. The source is read on each call and the profiles parsed when it changes, so
+// changes to the config take effect without restarting.
+func NewClient(c client.Client, source Source) client.Client {
+	return &profilesClient{Client: c, source: source}
+}
+
+func (p *profilesClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	return p.Client.Call(ctx, req, rsp, p.options(req.Service(), opts)...)
+}
+
+func (p *profilesClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	return p.Client.Stream(ctx, req, p.options(req.Service(), opts)...)
+}
+
+// options returns the options of the service's profile followed by those passed to the call,
+// which override them
+func (p *profilesClient) options(service string, opts []client.CallOption) []client.CallOption {
+	prof, ok := p.profile(service)
+	if !ok {
+		return opts
+	}
+	return append(prof.CallOptions(), opts...)
+}
+
+// profile returns the profile of the service, parsing the profiles if they've changed
+func (p *profilesClient) profile(service string) (Profile, bool) {
+	raw := p.source()
+
+	p.RLock()
+	if bytes.Equal(raw, p.raw) {
+		prof, ok := p.profiles[service]
+		p.RUnlock()
+		return prof, ok
+	}
+	p.RUnlock()
+
+	p.Lock()
+	defer p.Unlock()
+
+	var profiles map[string]Profile
+	if len(bytes.TrimSpace(raw)) > 0 && !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		parsed, err := Parse(raw)
+		if err != nil {
+			// keep using the last valid profiles
+			logger.Errorf("Error parsing client profiles: %v", err)
+			p.raw = raw
+			prof, ok := p.profiles[service]
+			return prof, ok
+		}
+		profiles = parsed
+	}
+
+	p.raw = raw
+	p.profiles = profiles
+	prof, ok := p.profiles[service]
+	return prof, ok
+}
@@ -0,0 +1,71 @@
+package profiles
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+)
+
+type testClient struct {
+	client.Client
+	options client.CallOptions
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.options = client.CallOptions{Retries: -1}
+	for _, o := range opts {
+		o(&t.options)
+	}
+	return nil
+}
+
+func TestProfiles(t *testing.T) {
+	raw := []byte(`{"payments": {"timeout": "2s", "retries": 3, "backoff": "100ms"}, "reports": {"timeout": "30s", "retries": 0}}`)
+	c := &testClient{Client: mucp.NewClient()}
+	p := NewClient(c, func() []byte { return raw })
+
+	call := func(service string, opts ...client.CallOption) client.CallOptions {
+		if err := p.Call(context.TODO(), c.NewRequest(service, "Foo.Bar", nil), nil, opts...); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return c.options
+	}
+
+	o := call("payments")
+	if o.RequestTimeout != time.Second*2 || o.Retries != 3 || o.Backoff == nil {
+		t.Fatalf("Expected the payments profile to be applied, got %+v", o)
+	}
+	if d, _ := o.Backoff(context.TODO(), nil, 0); d != 0 {
+		t.Errorf("Expected no backoff before the first attempt, got %v", d)
+	}
+	if d, _ := o.Backoff(context.TODO(), nil, 1); d != time.Millisecond*100 {
+		t.Errorf("Expected the backoff before the first retry to be the profile's, got %v", d)
+	}
+	if d, _ := o.Backoff(context.TODO(), nil, 3); d != time.Millisecond*400 {
+		t.Errorf("Expected the backoff to double on each retry, got %v", d)
+	}
+	if o := call("reports"); o.RequestTimeout != time.Second*30 || o.Retries != 0 {
+		t.Errorf("Expected the reports profile to be applied, got %+v", o)
+	}
+	if o := call("users"); o.RequestTimeout != 0 || o.Retries != -1 {
+		t.Errorf("Expected no profile to be applied, got %+v", o)
+	}
+
+	// options passed to the call take precedence
+	if o := call("payments", client.WithRequestTimeout(time.Second)); o.RequestTimeout != time.Second {
+		t.Errorf("Expected the call's timeout to be used, got %v", o.RequestTimeout)
+	}
+
+	// changes to the source are applied and invalid profiles are ignored
+	raw = []byte(`{"payments": {"timeout": "5s"}}`)
+	if o := call("payments"); o.RequestTimeout != time.Second*5 {
+		t.Errorf("Expected the updated profile to be applied, got %v", o.RequestTimeout)
+	}
+	raw = []byte(`{"payments": {"timeout": "soon"}}`)
+	if o := call("payments"); o.RequestTimeout != time.Second*5 {
+		t.Errorf("Expected the last valid profile to be applied, got %v", o.RequestTimeout)
+	}
+}
@@ -0,0 +1,258 @@
+// Package resume provides a client which resumes streams broken by transient errors. When a
+// stream is opened with the Resume option and Recv fails with an error such as a timeout or a
+// dropped connection, the stream is re-established and the cursor is sent in place of the
+// original request, so consumers of long streams such as logs and events pick up where they
+// left off without a reconnect loop of their own. Only streams which send a single request,
+// i.e. server side streams, are resumed.
+package resume
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultAttempts is the number of times a broken stream is re-established before the error
+	// is returned
+	DefaultAttempts = 5
+	// DefaultBackoff is the delay before the first attempt, which doubles on each attempt
+	DefaultBackoff = time.Millisecond * 100
+	// DefaultMaxBackoff is the maximum delay between attempts
+	DefaultMaxBackoff = time.Second * 5
+)
+
+// Cursor returns the request to re-establish the stream with, given the request originally
+// sent and the last message received, which is nil if none were received. For example a logs
+// stream would set the start of the request to the timestamp of the last record received.
+type Cursor func(req, last interface{}) interface{}
+
+type cursorKey struct{}
+
+// Resume the stream with the request returned by the cursor if it breaks
+func Resume(c Cursor) client.CallOption {
+	return func(o *client.CallOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, cursorKey{}, c)
+	}
+}
+
+// cursorFromOptions returns the cursor set on the call options, nil if the stream isn't resumed
+func cursorFromOptions(o client.CallOptions) Cursor {
+	if o.Context == nil {
+		return nil
+	}
+	c, _ := o.Context.Value(cursorKey{}).(Cursor)
+	return c
+}
+
+type resumeClient struct {
+	client.Client
+}
+
+// NewClient returns a client which resumes the streams opened with the Resume option
+func NewClient(c client.Client) client.Client {
+	return &resumeClient{Client: c}
+}
+
+func (r *resumeClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	cursor := cursorFromOptions(options)
+	if cursor == nil {
+		return r.Client.Stream(ctx, req, opts...)
+	}
+
+	stream, err := r.Client.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resumeStream{
+		Stream: stream,
+		ctx:    ctx,
+		client: r.Client,
+		req:    req,
+		opts:   opts,
+		cursor: cursor,
+	}, nil
+}
+
+// resumeStream re-establishes the stream when Recv returns a transient error
+type resumeStream struct {
+	client.Stream
+
+	ctx    context.Context
+	client client.Client
+	req    client.Request
+	opts   []client.CallOption
+	cursor Cursor
+
+	sync.Mutex
+	// sent is the request sent on the stream, nil if none has been sent
+	sent interface{}
+	// sends is the number of messages sent, streams which sent more than one aren't resumed
+	sends int
+	// last is the last message received
+	last   interface{}
+	closed bool
+}
+
+func (s *resumeStream) Send(msg interface{}) error {
+	s.Lock()
+	stream := s.Stream
+	if s.sends == 0 {
+		s.sent = msg
+	}
+	s.sends++
+	s.Unlock()
+
+	return stream.Send(msg)
+}
+
+func (s *resumeStream) Recv(msg interface{}) error {
+	s.Lock()
+	stream := s.Stream
+	s.Unlock()
+
+	err := stream.Recv(msg)
+	for attempt := 1; err != nil && s.resumable(err) && attempt <= DefaultAttempts; attempt++ {
+		logger.Debugf("Resuming stream to %v.%v after error: %v", s.req.Service(), s.req.Endpoint(), err)
+
+		if err := s.sleep(attempt); err != nil {
+			return err
+		}
+
+		var rerr error
+		if stream, rerr = s.reconnect(); rerr != nil {
+			err = rerr
+			continue
+		}
+		err = stream.Recv(msg)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the caller may reuse the message for the next Recv, so the cursor is given a copy
+	last := clone(msg)
+	s.Lock()
+	s.last = last
+	s.Unlock()
+	return nil
+}
+
+func (s *resumeStream) Close() error {
+	s.Lock()
+	s.closed = true
+	stream := s.Stream
+	s.Unlock()
+
+	return stream.Close()
+}
+
+func (s *resumeStream) Error() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.Stream.Error()
+}
+
+// resumable returns true if the stream can be re-established after the error
+func (s *resumeStream) resumable(err error) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed || s.sends > 1 || s.ctx.Err() != nil {
+		return false
+	}
+	return transient(err)
+}
+
+// sleep for the backoff of the attempt, returning early if the context is done
+func (s *resumeStream) sleep(attempt int) error {
+	d := DefaultBackoff << uint(attempt-1)
+	if d > DefaultMaxBackoff || d <= 0 {
+		d = DefaultMaxBackoff
+	}
+
+	select {
+	case <-time.After(d):
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// reconnect opens a new stream and sends the cursor on it in place of the original request
+func (s *resumeStream) reconnect() (client.Stream, error) {
+	s.Lock()
+	sent, last, old := s.sent, s.last, s.Stream
+	s.Unlock()
+
+	old.Close()
+
+	stream, err := s.client.Stream(s.ctx, s.req, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if sent != nil {
+		if err := stream.Send(s.cursor(sent, last)); err != nil {
+			stream.Close()
+			return nil, err
+		}
+	}
+
+	s.Lock()
+	s.Stream = stream
+	s.Unlock()
+	return stream, nil
+}
+
+// clone returns a copy of the message, messages which aren't protobuf messages are copied
+// shallowly
+func clone(msg interface{}) interface{} {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Clone(m)
+	}
+
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return msg
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	return cp.Interface()
+}
+
+// transient returns true for errors which may not recur on a new stream, such as timeouts and
+// dropped connections, but not the end of the stream or errors returned by the handler for the
+// request
+func transient(err error) bool {
+	if err == io.EOF || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	verr := errors.Parse(err)
+	if verr == nil {
+		return true
+	}
+
+	switch verr.Code {
+	case 408, 500, 502, 503, 504:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,186 @@
+package resume
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testMessage struct {
+	Offset int
+}
+
+// testStream sends the messages from the offset of the request received, returning the error
+// after the number of messages
+type testStream struct {
+	client.Stream
+	offset int
+	end    int
+	after  int
+	err    error
+}
+
+func (t *testStream) Send(msg interface{}) error {
+	t.offset = msg.(*testMessage).Offset
+	return nil
+}
+
+func (t *testStream) Recv(msg interface{}) error {
+	if t.after == 0 && t.err != nil {
+		return t.err
+	}
+	if t.offset >= t.end {
+		return io.EOF
+	}
+	t.after--
+	msg.(*testMessage).Offset = t.offset
+	t.offset++
+	return nil
+}
+
+func (t *testStream) Close() error {
+	return nil
+}
+
+// testClient returns the streams in turn
+type testClient struct {
+	client.Client
+	streams []*testStream
+	opened  int
+}
+
+func (t *testClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	s := t.streams[t.opened]
+	t.opened++
+	return s, nil
+}
+
+func recvAll(t *testing.T, s client.Stream) ([]int, error) {
+	var offsets []int
+	for {
+		msg := new(testMessage)
+		if err := s.Recv(msg); err == io.EOF {
+			return offsets, nil
+		} else if err != nil {
+			return offsets, err
+		}
+		offsets = append(offsets, msg.Offset)
+	}
+}
+
+func TestResume(t *testing.T) {
+	DefaultBackoff = time.Millisecond
+	cursor := func(req, last interface{}) interface{} {
+		if last == nil {
+			return req
+		}
+		return &testMessage{Offset: last.(*testMessage).Offset + 1}
+	}
+
+	t.Run("Resumed", func(t *testing.T) {
+		c := &testClient{
+			Client: mucp.NewClient(),
+			streams: []*testStream{
+				{end: 5, after: 2, err: errors.InternalServerError("test", "connection reset")},
+				{end: 5, after: 0, err: errors.Timeout("test", "timeout")},
+				{end: 5, after: -1},
+			},
+		}
+		req := c.NewRequest("test", "Test.Stream", &testMessage{})
+
+		s, err := NewClient(c).Stream(context.TODO(), req, Resume(cursor))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		s.Send(&testMessage{Offset: 0})
+
+		offsets, err := recvAll(t, s)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(offsets) != 5 {
+			t.Fatalf("Expected 5 messages, got %v", offsets)
+		}
+		for i, o := range offsets {
+			if o != i {
+				t.Errorf("Expected offset %v, got %v", i, o)
+			}
+		}
+		if c.opened != 3 {
+			t.Errorf("Expected 3 streams, got %v", c.opened)
+		}
+	})
+
+	t.Run("ReusedMessage", func(t *testing.T) {
+		c := &testClient{
+			Client: mucp.NewClient(),
+			streams: []*testStream{
+				{end: 5, after: 2, err: errors.InternalServerError("test", "connection reset")},
+				{end: 5, after: -1},
+			},
+		}
+		req := c.NewRequest("test", "Test.Stream", &testMessage{})
+
+		s, _ := NewClient(c).Stream(context.TODO(), req, Resume(cursor))
+		s.Send(&testMessage{Offset: 0})
+
+		// the message received into is reset before each Recv
+		var offsets []int
+		msg := new(testMessage)
+		for {
+			*msg = testMessage{Offset: -1}
+			if err := s.Recv(msg); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			offsets = append(offsets, msg.Offset)
+		}
+		if len(offsets) != 5 || offsets[2] != 2 {
+			t.Errorf("Expected the stream to resume after the last message, got %v", offsets)
+		}
+	})
+
+	t.Run("NotResumed", func(t *testing.T) {
+		c := &testClient{
+			Client: mucp.NewClient(),
+			streams: []*testStream{
+				{end: 5, after: 2, err: errors.InternalServerError("test", "connection reset")},
+			},
+		}
+		req := c.NewRequest("test", "Test.Stream", &testMessage{})
+
+		s, _ := NewClient(c).Stream(context.TODO(), req)
+		s.Send(&testMessage{Offset: 0})
+
+		if _, err := recvAll(t, s); err == nil {
+			t.Errorf("Expected the error to be returned")
+		}
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		c := &testClient{
+			Client: mucp.NewClient(),
+			streams: []*testStream{
+				{end: 5, after: 2, err: errors.BadRequest("test", "invalid offset")},
+				{end: 5, after: -1},
+			},
+		}
+		req := c.NewRequest("test", "Test.Stream", &testMessage{})
+
+		s, _ := NewClient(c).Stream(context.TODO(), req, Resume(cursor))
+		s.Send(&testMessage{Offset: 0})
+
+		if _, err := recvAll(t, s); err == nil {
+			t.Errorf("Expected the error to be returned")
+		}
+		if c.opened != 1 {
+			t.Errorf("Expected 1 stream, got %v", c.opened)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+// Package retry classifies the errors calls fail with to decide whether they're retried. The
+// classifiers registered are asked in turn, the first which recognises the error deciding,
+// followed by the default classifier. An error can also carry a backoff hint, e.g. the
+// service asking for calls to back off while it's rate limited, which the retry waits for.
+//
+// Use Func as the retry function of the client so the classification applies to its calls:
+//
+//	retry.Register(func(err error) retry.Classification {
+//		if err == sql.ErrTxDone {
+//			return retry.Classification{Class: retry.NonRetryable}
+//		}
+//		return retry.Classification{}
+//	})
+package retry
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// DefaultThrottleBackoff is how long calls which were rate limited back off for before
+	// being retried
+	DefaultThrottleBackoff = time.Second
+)
+
+// Class of an error
+type Class int
+
+const (
+	// Unknown errors are classified by the next classifier
+	Unknown Class = iota
+	// Retryable errors may succeed if the call is retried
+	Retryable
+	// NonRetryable errors are caused by the call itself and will fail again if retried
+	NonRetryable
+)
+
+// Classification of an error
+type Classification struct {
+	Class Class
+	// Backoff is how long to wait before retrying, in addition to the client's backoff
+	Backoff time.Duration
+}
+
+// Classifier classifies an error, returning Unknown if it doesn't recognise the error
+type Classifier func(err error) Classification
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   []Classifier
+)
+
+// Register a classifier, which is asked before the classifiers registered previously
+func Register(c Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append([]Classifier{c}, classifiers...)
+}
+
+// Classify the error using the classifiers registered, falling back to Default
+func Classify(err error) Classification {
+	classifiersMu.RLock()
+	cs := classifiers
+	classifiersMu.RUnlock()
+
+	for _, c := range cs {
+		if cl := c(err); cl.Class != Unknown {
+			return cl
+		}
+	}
+	return Default(err)
+}
+
+// Default classifies timeouts, throttling, unavailable services and connection errors as
+// retryable and every other error as not
+func Default(err error) Classification {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return Classification{Class: NonRetryable}
+	}
+
+	if verr := errors.Parse(err); verr != nil {
+		switch verr.Code {
+		case 408, 502, 503, 504:
+			return Classification{Class: Retryable}
+		case 429:
+			return Classification{Class: Retryable, Backoff: DefaultThrottleBackoff}
+		case 500:
+			// the client returns the errors sending the request as internal server errors,
+			// errors returned by the handler aren't retried
+			if verr.Id == "go.micro.client" || verr.Id == "go.micro.client.transport" {
+				return Classification{Class: Retryable}
+			}
+		}
+		return Classification{Class: NonRetryable}
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF || err == syscall.ECONNREFUSED || err == syscall.ECONNRESET {
+		return Classification{Class: Retryable}
+	}
+	if nerr, ok := err.(net.Error); ok && (nerr.Timeout() || nerr.Temporary()) {
+		return Classification{Class: Retryable}
+	}
+	if _, ok := err.(*net.OpError); ok {
+		return Classification{Class: Retryable}
+	}
+	return Classification{Class: NonRetryable}
+}
+
+// Func is a retry function for the client which retries the errors classified as retryable,
+// waiting for their backoff hint first
+func Func(ctx context.Context, req client.Request, retryCount int, err error) (bool, error) {
+	cl := Classify(err)
+	if cl.Class != Retryable {
+		return false, nil
+	}
+	if cl.Backoff <= 0 {
+		return true, nil
+	}
+
+	t := time.NewTimer(cl.Backoff)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false, nil
+	case <-t.C:
+		return true, nil
+	}
+}
@@ -0,0 +1,57 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+func TestDefault(t *testing.T) {
+	tt := []struct {
+		Err   error
+		Class Class
+	}{
+		{errors.Timeout("foo", "timeout"), Retryable},
+		{errors.ServiceUnavailable("foo", "unavailable"), Retryable},
+		{goerrors.InternalServerError("go.micro.client", "connection error"), Retryable},
+		{errors.InternalServerError("foo", "handler failed"), NonRetryable},
+		{errors.BadRequest("foo", "invalid"), NonRetryable},
+		{errors.TooManyRequests("foo", "slow down"), Retryable},
+		{io.EOF, Retryable},
+		{context.Canceled, NonRetryable},
+	}
+	for _, tc := range tt {
+		if cl := Default(tc.Err); cl.Class != tc.Class {
+			t.Errorf("Expected %v to be classified as %v, got %v", tc.Err, tc.Class, cl.Class)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func(cs []Classifier) { classifiers = cs }(classifiers)
+
+	// retry the conflicts of a service which returns them while it's being migrated
+	Register(func(err error) Classification {
+		if verr := errors.Parse(err); verr != nil && verr.Code == 409 {
+			return Classification{Class: Retryable, Backoff: 10 * time.Millisecond}
+		}
+		return Classification{}
+	})
+
+	start := time.Now()
+	if retry, _ := Func(context.TODO(), nil, 0, errors.Conflict("foo", "migrating")); !retry {
+		t.Errorf("Expected the conflict to be retried")
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Errorf("Expected the retry to wait for the backoff hint")
+	}
+
+	// errors the classifier doesn't recognise fall back to the default
+	if retry, _ := Func(context.TODO(), nil, 0, errors.BadRequest("foo", "invalid")); retry {
+		t.Errorf("Expected the bad request not to be retried")
+	}
+}
@@ -0,0 +1,128 @@
+// Package static provides a client lookup which falls back to static addresses when the nodes
+// of a service can't be looked up, so critical dependencies such as auth stay reachable while the
+// registry or router is down. The addresses are set with DefaultAddresses, e.g. from the
+// --client_static_addresses flag, and read from the "client.static" config, for example:
+//
+//	{
+//		"auth": ["10.0.0.5:8010", "10.0.0.6:8010"]
+//	}
+//
+// The config is itself served by a service, so the addresses of the services needed to start,
+// such as auth and config, are best set with the flag. The static addresses are only used if
+// the lookup fails.
+package static
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultPath is the path of the addresses in the config
+	DefaultPath = []string{"client", "static"}
+	// DefaultAddresses of services, used alongside those in the config
+	DefaultAddresses = map[string][]string{}
+)
+
+// ParseFlag parses addresses in the form service=address, e.g. auth=10.0.0.5:8010, adding them
+// to the addresses of the service
+func ParseFlag(values []string) (map[string][]string, error) {
+	addrs := make(map[string][]string)
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("invalid static address %q, expected service=address", v)
+		}
+		addrs[parts[0]] = append(addrs[parts[0]], parts[1])
+	}
+	return addrs, nil
+}
+
+// Source returns the encoded addresses, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the addresses from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+type staticLookup struct {
+	lookup client.LookupFunc
+	source Source
+
+	sync.RWMutex
+	// raw is the encoding the addresses were last parsed from
+	raw       []byte
+	addresses map[string][]string
+}
+
+// Lookup returns a lookup function which falls back to the static addresses of a service when
+// the nodes can't be looked up with fn
+func Lookup(fn client.LookupFunc, source Source) client.LookupFunc {
+	s := &staticLookup{lookup: fn, source: source}
+	return s.Lookup
+}
+
+func (s *staticLookup) Lookup(ctx context.Context, req client.Request, opts client.CallOptions) ([]string, error) {
+	addrs, err := s.lookup(ctx, req, opts)
+	if err == nil {
+		return addrs, nil
+	}
+
+	static := s.static(req.Service())
+	if len(static) == 0 {
+		return nil, err
+	}
+	logger.Debugf("Error looking up %v, using the static addresses %v: %v", req.Service(), static, err)
+	return static, nil
+}
+
+// static returns the static addresses of the service, those set in DefaultAddresses first
+func (s *staticLookup) static(service string) []string {
+	addrs := append([]string{}, DefaultAddresses[service]...)
+	return append(addrs, s.configured()[service]...)
+}
+
+// configured returns the addresses in the config, parsing them if they've changed
+func (s *staticLookup) configured() map[string][]string {
+	if s.source == nil {
+		return nil
+	}
+	raw := s.source()
+
+	s.RLock()
+	if bytes.Equal(raw, s.raw) {
+		defer s.RUnlock()
+		return s.addresses
+	}
+	s.RUnlock()
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.raw = raw
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		s.addresses = nil
+		return nil
+	}
+
+	var addrs map[string][]string
+	if err := json.Unmarshal(raw, &addrs); err != nil {
+		// keep using the last valid addresses
+		logger.Errorf("Error parsing static addresses: %v", err)
+		return s.addresses
+	}
+	s.addresses = addrs
+	return addrs
+}
@@ -0,0 +1,51 @@
+package static
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+)
+
+func TestLookup(t *testing.T) {
+	defer func(a map[string][]string) { DefaultAddresses = a }(DefaultAddresses)
+	var err error
+	DefaultAddresses, err = ParseFlag([]string{"auth=10.0.0.5:8010", "auth=10.0.0.6:8010"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the flag: %v", err)
+	}
+
+	var lookupErr error
+	fn := Lookup(func(ctx context.Context, req client.Request, opts client.CallOptions) ([]string, error) {
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		return []string{"127.0.0.1:8010"}, nil
+	}, func() []byte { return []byte(`{"store": ["10.0.0.7:8002"]}`) })
+
+	c := mucp.NewClient()
+	lookup := func(service string) ([]string, error) {
+		return fn(context.TODO(), c.NewRequest(service, "Foo.Bar", nil), client.CallOptions{})
+	}
+
+	if addrs, err := lookup("auth"); err != nil || len(addrs) != 1 || addrs[0] != "127.0.0.1:8010" {
+		t.Fatalf("Expected the looked up address, got %v: %v", addrs, err)
+	}
+
+	lookupErr = errors.New("registry unavailable")
+	if addrs, err := lookup("auth"); err != nil || len(addrs) != 2 || addrs[0] != "10.0.0.5:8010" {
+		t.Fatalf("Expected the addresses from the flag, got %v: %v", addrs, err)
+	}
+	if addrs, err := lookup("store"); err != nil || len(addrs) != 1 || addrs[0] != "10.0.0.7:8002" {
+		t.Fatalf("Expected the address from the config, got %v: %v", addrs, err)
+	}
+	if _, err := lookup("users"); err != lookupErr {
+		t.Fatalf("Expected the lookup error for a service without static addresses, got %v", err)
+	}
+
+	if _, err := ParseFlag([]string{"auth"}); err == nil {
+		t.Errorf("Expected an error parsing an address without a service")
+	}
+}
@@ -170,12 +170,45 @@ func init() {
 					Action: setConfig,
 					Flags:  subcommandFlags,
 				},
+				{
+					Name:   "edit",
+					Usage:  "Edit a value in $EDITOR, validating it against its schema; micro config edit key",
+					Action: editConfig,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "yes",
+							Usage: "Apply the changes without confirming them",
+						},
+					},
+				},
 				{
 					Name:   "del",
 					Usage:  "Delete a value; micro config del key",
 					Action: delConfig,
 					Flags:  subcommandFlags,
 				},
+				{
+					Name:   "schema",
+					Usage:  "Manage the JSON schemas values are validated against",
+					Action: helper.UnexpectedSubcommand,
+					Subcommands: []*cli.Command{
+						{
+							Name:   "set",
+							Usage:  "Set the schema of a key from a file, or - for stdin; micro config schema set key file",
+							Action: setSchema,
+						},
+						{
+							Name:   "get",
+							Usage:  "Get the schema of a key; micro config schema get key",
+							Action: getSchema,
+						},
+						{
+							Name:   "del",
+							Usage:  "Delete the schema of a key; micro config schema del key",
+							Action: delSchema,
+						},
+					},
+				},
 			},
 		},
 	)
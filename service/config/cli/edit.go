@@ -0,0 +1,261 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/client"
+	proto "github.com/micro/micro/v3/service/config/proto"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/errors"
+	rutil "github.com/micro/micro/v3/service/registry/util"
+)
+
+// editConfig opens the value of a key in the editor, validating it against the schema registered
+// for the key when saved. The change is only applied if the config hasn't changed since it was
+// read.
+func editConfig(ctx *cli.Context) error {
+	args := ctx.Args()
+	if args.Len() == 0 {
+		return fmt.Errorf("Required usage: micro config edit key")
+	}
+	key := args.Get(0)
+	if len(key) == 0 {
+		return fmt.Errorf("key cannot be blank")
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	pb := proto.NewConfigService("config", client.DefaultClient)
+
+	// the checksum of the namespace's config is returned along with the value of the key, so the
+	// update can be rejected if anything changed in the meantime
+	var original, checksum string
+	rsp, err := pb.Read(context.DefaultContext, &proto.ReadRequest{Namespace: ns, Path: key}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); err != nil && (verr == nil || verr.Code != 404) {
+		return err
+	}
+	if err == nil && rsp.Change != nil && rsp.Change.ChangeSet != nil {
+		checksum = rsp.Change.ChangeSet.Checksum
+		if v := rsp.Change.ChangeSet.Data; len(v) > 0 && v != "null" {
+			original = indent(v)
+		}
+	}
+	if len(original) == 0 {
+		original = "{}\n"
+	}
+
+	var schema *rutil.Schema
+	srsp, err := pb.ReadSchema(context.DefaultContext, &proto.ReadSchemaRequest{Namespace: ns, Path: key}, goclient.WithAuthToken())
+	if err == nil && len(srsp.Schema) > 0 {
+		schema = new(rutil.Schema)
+		if err := json.Unmarshal([]byte(srsp.Schema), schema); err != nil {
+			return fmt.Errorf("Error parsing the schema of %v: %v", key, err)
+		}
+	} else if verr := errors.Parse(err); err != nil && (verr == nil || verr.Code != 404) {
+		return err
+	}
+
+	file, err := ioutil.TempFile("", "micro-config-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	edited := original
+	for {
+		if err := ioutil.WriteFile(file.Name(), []byte(edited), 0600); err != nil {
+			return err
+		}
+		if err := openEditor(file.Name()); err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(file.Name())
+		if err != nil {
+			return err
+		}
+		edited = string(b)
+
+		if strings.TrimSpace(edited) == strings.TrimSpace(original) {
+			fmt.Println("Edit cancelled, no changes made")
+			return nil
+		}
+
+		// reopen the editor until the value is valid or the user gives up
+		if err = validateEdit(schema, []byte(edited)); err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+		if !confirm("Edit again? [Y/n]: ", true) {
+			fmt.Println("Edit cancelled, no changes made")
+			return nil
+		}
+	}
+
+	fmt.Printf("Changes to %v:\n\n", key)
+	for _, l := range diffLines(original, edited) {
+		fmt.Println(l)
+	}
+	fmt.Println()
+	if !ctx.Bool("yes") && !confirm("Apply these changes? [y/N]: ", false) {
+		fmt.Println("Edit cancelled, no changes made")
+		return nil
+	}
+
+	_, err = pb.Update(context.DefaultContext, &proto.UpdateRequest{
+		Change: &proto.Change{
+			Namespace: ns,
+			Path:      key,
+			ChangeSet: &proto.ChangeSet{
+				Data:      compact(edited),
+				Format:    "json",
+				Source:    "cli",
+				Timestamp: time.Now().Unix(),
+			},
+		},
+		ExpectedChecksum: checksum,
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil && verr.Code == 409 {
+		return fmt.Errorf("The config has changed since it was read, run the edit again to apply your changes to the latest version")
+	} else if err != nil {
+		return err
+	}
+
+	// read back the new version of the config
+	rsp, err = pb.Read(context.DefaultContext, &proto.ReadRequest{Namespace: ns, Path: key}, goclient.WithAuthToken())
+	if err != nil || rsp.Change == nil || rsp.Change.ChangeSet == nil {
+		fmt.Println("Config updated")
+		return nil
+	}
+	fmt.Printf("Config updated to version %v\n", rsp.Change.ChangeSet.Checksum)
+	return nil
+}
+
+// openEditor opens the file in the editor set by $VISUAL or $EDITOR, defaulting to vi
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if len(editor) == 0 {
+		editor = os.Getenv("EDITOR")
+	}
+	if len(editor) == 0 {
+		editor = "vi"
+	}
+
+	// the editor can include arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Error running editor %v: %v", editor, err)
+	}
+	return nil
+}
+
+// validateEdit checks the edited value is JSON which matches the schema, if there is one
+func validateEdit(schema *rutil.Schema, b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	return rutil.Validate(schema, b)
+}
+
+// confirm prompts the user for a yes or no answer, returning def if they don't give one
+func confirm(prompt string, def bool) bool {
+	fmt.Print(prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return def
+	}
+}
+
+// indent pretty prints JSON for editing, returning it unchanged if it isn't valid
+func indent(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s + "\n"
+	}
+	return buf.String() + "\n"
+}
+
+// compact strips the whitespace added for editing
+func compact(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return strings.TrimSpace(s)
+	}
+	return buf.String()
+}
+
+// diffLines returns a line diff of two strings, prefixing removed lines with "-", added lines
+// with "+" and unchanged lines with a space
+func diffLines(a, b string) []string {
+	x := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	y := strings.Split(strings.TrimRight(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, "  "+x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, "- "+x[i])
+	}
+	for ; j < len(y); j++ {
+		out = append(out, "+ "+y[j])
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/client"
+	proto "github.com/micro/micro/v3/service/config/proto"
+	"github.com/micro/micro/v3/service/context"
+	rutil "github.com/micro/micro/v3/service/registry/util"
+)
+
+// setSchema registers the JSON schema in the file, or stdin if the file is "-", for the config
+// at a key. Updates of the config which don't match it are rejected.
+func setSchema(ctx *cli.Context) error {
+	args := ctx.Args()
+	if args.Len() < 2 {
+		return fmt.Errorf("Required usage: micro config schema set key file")
+	}
+	key, file := args.Get(0), args.Get(1)
+
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	// check the schema parses before sending it
+	var schema rutil.Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return fmt.Errorf("Error parsing the schema: %v", err)
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	pb := proto.NewConfigService("config", client.DefaultClient)
+	_, err = pb.SetSchema(context.DefaultContext, &proto.SetSchemaRequest{
+		Namespace: ns,
+		Path:      key,
+		Schema:    string(b),
+	}, goclient.WithAuthToken())
+	return err
+}
+
+// getSchema prints the JSON schema registered for the config at a key
+func getSchema(ctx *cli.Context) error {
+	args := ctx.Args()
+	if args.Len() == 0 {
+		return fmt.Errorf("Required usage: micro config schema get key")
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	pb := proto.NewConfigService("config", client.DefaultClient)
+	rsp, err := pb.ReadSchema(context.DefaultContext, &proto.ReadSchemaRequest{
+		Namespace: ns,
+		Path:      args.Get(0),
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(indent(rsp.Schema))
+	return nil
+}
+
+// delSchema removes the JSON schema registered for the config at a key
+func delSchema(ctx *cli.Context) error {
+	args := ctx.Args()
+	if args.Len() == 0 {
+		return fmt.Errorf("Required usage: micro config schema del key")
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	// a blank schema removes it
+	pb := proto.NewConfigService("config", client.DefaultClient)
+	_, err = pb.SetSchema(context.DefaultContext, &proto.SetSchemaRequest{
+		Namespace: ns,
+		Path:      args.Get(0),
+	}, goclient.WithAuthToken())
+	return err
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/config"
+	"github.com/micro/go-micro/v3/config/reader"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// DefaultRewatchInterval is how long a hook waits before watching the config again when the
+// watcher fails
+var DefaultRewatchInterval = time.Second
+
+// Hook is called with the value at a path of the config each time it changes, so services can
+// resize pools, change log levels and toggle features without restarting. The changes are
+// delivered one at a time, in the order they're made.
+type Hook struct {
+	fn   func(reader.Value) error
+	path []string
+
+	sync.Mutex
+	watcher config.Watcher
+	exit    chan struct{}
+	done    chan struct{}
+}
+
+// NewHook returns a hook which calls the func when the value at the path changes, the whole
+// config if the path is empty. Errors returned by the func are logged, the value isn't
+// delivered again.
+func NewHook(fn func(reader.Value) error, path ...string) *Hook {
+	return &Hook{fn: fn, path: path}
+}
+
+// Start watching the config, the hook isn't called with the current value
+func (h *Hook) Start() error {
+	if DefaultConfig == nil {
+		return errors.New("config is not set up")
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	if h.watcher != nil {
+		return nil
+	}
+
+	w, err := DefaultConfig.Watch(h.path...)
+	if err != nil {
+		return err
+	}
+	h.watcher = w
+	h.exit = make(chan struct{})
+	h.done = make(chan struct{})
+	go h.run(w, h.exit, h.done)
+	return nil
+}
+
+// Stop watching the config, waiting for the change being delivered to be handled
+func (h *Hook) Stop() error {
+	h.Lock()
+	w, done := h.watcher, h.done
+	if w != nil {
+		close(h.exit)
+	}
+	h.watcher = nil
+	h.Unlock()
+
+	if w == nil {
+		return nil
+	}
+	err := w.Stop()
+	<-done
+	return err
+}
+
+// run delivers the changes until the hook is stopped. The watcher also fails when a change
+// can't be decoded, in which case the config is watched again so later changes are delivered.
+func (h *Hook) run(w config.Watcher, exit, done chan struct{}) {
+	defer close(done)
+	for {
+		v, err := w.Next()
+		if err == nil {
+			h.call(v)
+			continue
+		}
+
+		select {
+		case <-exit:
+			return
+		default:
+		}
+		logger.Errorf("Error watching config %v, watching it again: %v", h.name(), err)
+		w.Stop()
+
+		if w = h.rewatch(exit); w == nil {
+			return
+		}
+	}
+}
+
+// rewatch watches the config again, retrying until it succeeds or the hook is stopped, in which
+// case nil is returned
+func (h *Hook) rewatch(exit chan struct{}) config.Watcher {
+	for {
+		select {
+		case <-exit:
+			return nil
+		case <-time.After(DefaultRewatchInterval):
+		}
+
+		w, err := DefaultConfig.Watch(h.path...)
+		if err != nil {
+			logger.Errorf("Error watching config %v: %v", h.name(), err)
+			continue
+		}
+
+		// the hook may have been stopped while the config was being watched
+		h.Lock()
+		select {
+		case <-exit:
+			h.Unlock()
+			w.Stop()
+			return nil
+		default:
+		}
+		h.watcher = w
+		h.Unlock()
+		return w
+	}
+}
+
+// call the func, recovering from panics so a bad hook doesn't take the service down
+func (h *Hook) call(v reader.Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Panic handling the change to config %v: %v", h.name(), r)
+		}
+	}()
+	if err := h.fn(v); err != nil {
+		logger.Errorf("Error handling the change to config %v: %v", h.name(), err)
+	}
+}
+
+func (h *Hook) name() string {
+	if len(h.path) == 0 {
+		return "."
+	}
+	return strings.Join(h.path, ".")
+}
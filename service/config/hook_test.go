@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/config"
+	"github.com/micro/go-micro/v3/config/reader"
+	"github.com/micro/go-micro/v3/config/source"
+	"github.com/micro/go-micro/v3/config/source/memory"
+)
+
+// testSource signals once the config is watching it, since changes written before then are lost
+type testSource struct {
+	source.Source
+	watching chan struct{}
+}
+
+func (s *testSource) Watch() (source.Watcher, error) {
+	w, err := s.Source.Watch()
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-s.watching:
+	default:
+		close(s.watching)
+	}
+	return w, nil
+}
+
+func TestHook(t *testing.T) {
+	src := &testSource{
+		Source:   memory.NewSource(memory.WithJSON([]byte(`{"pool": {"size": 10}, "log": "info"}`))),
+		watching: make(chan struct{}),
+	}
+	c, err := config.NewConfig(config.WithSource(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-src.watching:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Expected the config to watch the source")
+	}
+	defer func(c config.Config) { DefaultConfig = c }(DefaultConfig)
+	DefaultConfig = c
+
+	sizes := make(chan int, 4)
+	h := NewHook(func(v reader.Value) error {
+		size := v.Int(0)
+		sizes <- size
+		switch size {
+		case 20:
+			return errors.New("can't resize")
+		case 30:
+			panic("can't resize")
+		}
+		return nil
+	}, "pool", "size")
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(size int, log string) {
+		data := fmt.Sprintf(`{"pool": {"size": %d}, "log": %q}`, size, log)
+		src.Write(&source.ChangeSet{Data: []byte(data), Format: "json"})
+	}
+	next := func() int {
+		select {
+		case size := <-sizes:
+			return size
+		case <-time.After(time.Second * 5):
+			return 0
+		}
+	}
+
+	// hooks which fail or panic keep receiving changes
+	for _, size := range []int{20, 30, 40} {
+		update(size, "info")
+		if got := next(); got != size {
+			t.Fatalf("Expected the hook to be called with %v, got %v", size, got)
+		}
+	}
+
+	// changes to other values aren't delivered
+	update(40, "debug")
+	select {
+	case size := <-sizes:
+		t.Errorf("Expected the hook not to be called, got %v", size)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	if err := h.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	update(50, "debug")
+	select {
+	case size := <-sizes:
+		t.Errorf("Expected the stopped hook not to be called, got %v", size)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+// testWatcher returns the errors and then blocks until it's stopped
+type testWatcher struct {
+	errs []error
+	exit chan struct{}
+}
+
+func (w *testWatcher) Next() (reader.Value, error) {
+	if len(w.errs) > 0 {
+		err := w.errs[0]
+		w.errs = w.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	<-w.exit
+	return nil, errors.New("watcher stopped")
+}
+
+func (w *testWatcher) Stop() error {
+	select {
+	case <-w.exit:
+	default:
+		close(w.exit)
+	}
+	return nil
+}
+
+// testConfig returns the watchers in order
+type testConfig struct {
+	config.Config
+	watchers chan *testWatcher
+}
+
+func (c *testConfig) Watch(path ...string) (config.Watcher, error) {
+	return <-c.watchers, nil
+}
+
+func TestHookRewatch(t *testing.T) {
+	c := &testConfig{watchers: make(chan *testWatcher, 2)}
+	defer func(c config.Config) { DefaultConfig = c }(DefaultConfig)
+	DefaultConfig = c
+	defer func(d time.Duration) { DefaultRewatchInterval = d }(DefaultRewatchInterval)
+	DefaultRewatchInterval = time.Millisecond
+
+	// the first watcher fails to decode a change, the second delivers the next one
+	c.watchers <- &testWatcher{errs: []error{errors.New("bad change")}, exit: make(chan struct{})}
+	c.watchers <- &testWatcher{errs: []error{nil}, exit: make(chan struct{})}
+
+	calls := make(chan struct{}, 1)
+	h := NewHook(func(v reader.Value) error {
+		calls <- struct{}{}
+		return nil
+	}, "pool")
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-calls:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Expected the hook to be called after the config was watched again")
+	}
+	if err := h.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -221,7 +221,10 @@ func (m *CreateResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_CreateResponse proto.InternalMessageInfo
 
 type UpdateRequest struct {
-	Change               *Change  `protobuf:"bytes,1,opt,name=change,proto3" json:"change,omitempty"`
+	Change *Change `protobuf:"bytes,1,opt,name=change,proto3" json:"change,omitempty"`
+	// checksum the config of the namespace must have for the update to be applied, blank to
+	// apply it regardless
+	ExpectedChecksum     string   `protobuf:"bytes,2,opt,name=expected_checksum,json=expectedChecksum,proto3" json:"expected_checksum,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -259,6 +262,13 @@ func (m *UpdateRequest) GetChange() *Change {
 	return nil
 }
 
+func (m *UpdateRequest) GetExpectedChecksum() string {
+	if m != nil {
+		return m.ExpectedChecksum
+	}
+	return ""
+}
+
 type UpdateResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -618,6 +628,180 @@ func (m *WatchResponse) GetChangeSet() *ChangeSet {
 	return nil
 }
 
+// SetSchemaRequest registers the JSON schema the config at the path must match
+type SetSchemaRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Path      string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// JSON encoded schema, blank to remove it
+	Schema               string   `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetSchemaRequest) Reset()         { *m = SetSchemaRequest{} }
+func (m *SetSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*SetSchemaRequest) ProtoMessage()    {}
+func (*SetSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10f3d36580b48e31, []int{14}
+}
+
+func (m *SetSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSchemaRequest.Unmarshal(m, b)
+}
+func (m *SetSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSchemaRequest.Marshal(b, m, deterministic)
+}
+func (m *SetSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSchemaRequest.Merge(m, src)
+}
+func (m *SetSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_SetSchemaRequest.Size(m)
+}
+func (m *SetSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSchemaRequest proto.InternalMessageInfo
+
+func (m *SetSchemaRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *SetSchemaRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SetSchemaRequest) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
+type SetSchemaResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetSchemaResponse) Reset()         { *m = SetSchemaResponse{} }
+func (m *SetSchemaResponse) String() string { return proto.CompactTextString(m) }
+func (*SetSchemaResponse) ProtoMessage()    {}
+func (*SetSchemaResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10f3d36580b48e31, []int{15}
+}
+
+func (m *SetSchemaResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSchemaResponse.Unmarshal(m, b)
+}
+func (m *SetSchemaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSchemaResponse.Marshal(b, m, deterministic)
+}
+func (m *SetSchemaResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSchemaResponse.Merge(m, src)
+}
+func (m *SetSchemaResponse) XXX_Size() int {
+	return xxx_messageInfo_SetSchemaResponse.Size(m)
+}
+func (m *SetSchemaResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSchemaResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSchemaResponse proto.InternalMessageInfo
+
+type ReadSchemaRequest struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadSchemaRequest) Reset()         { *m = ReadSchemaRequest{} }
+func (m *ReadSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadSchemaRequest) ProtoMessage()    {}
+func (*ReadSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10f3d36580b48e31, []int{16}
+}
+
+func (m *ReadSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadSchemaRequest.Unmarshal(m, b)
+}
+func (m *ReadSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadSchemaRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadSchemaRequest.Merge(m, src)
+}
+func (m *ReadSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadSchemaRequest.Size(m)
+}
+func (m *ReadSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadSchemaRequest proto.InternalMessageInfo
+
+func (m *ReadSchemaRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReadSchemaRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type ReadSchemaResponse struct {
+	Schema               string   `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadSchemaResponse) Reset()         { *m = ReadSchemaResponse{} }
+func (m *ReadSchemaResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadSchemaResponse) ProtoMessage()    {}
+func (*ReadSchemaResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10f3d36580b48e31, []int{17}
+}
+
+func (m *ReadSchemaResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadSchemaResponse.Unmarshal(m, b)
+}
+func (m *ReadSchemaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadSchemaResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadSchemaResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadSchemaResponse.Merge(m, src)
+}
+func (m *ReadSchemaResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadSchemaResponse.Size(m)
+}
+func (m *ReadSchemaResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadSchemaResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadSchemaResponse proto.InternalMessageInfo
+
+func (m *ReadSchemaResponse) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ChangeSet)(nil), "config.ChangeSet")
 	proto.RegisterType((*Change)(nil), "config.Change")
@@ -633,42 +817,52 @@ func init() {
 	proto.RegisterType((*ReadResponse)(nil), "config.ReadResponse")
 	proto.RegisterType((*WatchRequest)(nil), "config.WatchRequest")
 	proto.RegisterType((*WatchResponse)(nil), "config.WatchResponse")
+	proto.RegisterType((*SetSchemaRequest)(nil), "config.SetSchemaRequest")
+	proto.RegisterType((*SetSchemaResponse)(nil), "config.SetSchemaResponse")
+	proto.RegisterType((*ReadSchemaRequest)(nil), "config.ReadSchemaRequest")
+	proto.RegisterType((*ReadSchemaResponse)(nil), "config.ReadSchemaResponse")
 }
 
 func init() { proto.RegisterFile("service/config/proto/config.proto", fileDescriptor_10f3d36580b48e31) }
 
 var fileDescriptor_10f3d36580b48e31 = []byte{
-	// 472 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x5d, 0x8b, 0xd3, 0x40,
-	0x14, 0x6d, 0xda, 0x6e, 0xb0, 0xb7, 0xbb, 0x8b, 0x8e, 0xee, 0x12, 0x8a, 0x0f, 0x35, 0x0f, 0xb2,
-	0x20, 0x34, 0xd2, 0xa2, 0xab, 0xf8, 0xa0, 0x58, 0x1f, 0x7d, 0x8a, 0x88, 0xe0, 0x83, 0x30, 0x3b,
-	0xbd, 0xdb, 0x84, 0xdd, 0x64, 0x62, 0x66, 0xd2, 0xff, 0xe0, 0x8f, 0xf4, 0xbf, 0xc8, 0x7c, 0x24,
-	0x93, 0x84, 0xb2, 0x96, 0xbe, 0x94, 0xb9, 0x1f, 0xe7, 0x9c, 0x7b, 0x7b, 0x0f, 0x81, 0x17, 0x02,
-	0xcb, 0x5d, 0xca, 0x30, 0x62, 0x3c, 0xbf, 0x4d, 0xb7, 0x51, 0x51, 0x72, 0xc9, 0x6d, 0xb0, 0xd0,
-	0x01, 0xf1, 0x4d, 0x14, 0xfe, 0xf1, 0x60, 0xb2, 0x4e, 0x68, 0xbe, 0xc5, 0x6f, 0x28, 0x09, 0x81,
-	0xf1, 0x86, 0x4a, 0x1a, 0x78, 0x73, 0xef, 0x6a, 0x12, 0xeb, 0x37, 0x99, 0xc1, 0x23, 0x96, 0x20,
-	0xbb, 0x13, 0x55, 0x16, 0x0c, 0x75, 0xbe, 0x89, 0xc9, 0x25, 0xf8, 0xb7, 0xbc, 0xcc, 0xa8, 0x0c,
-	0x46, 0xba, 0x62, 0x23, 0x95, 0x17, 0xbc, 0x2a, 0x19, 0x06, 0x63, 0x93, 0x37, 0x11, 0x79, 0x0e,
-	0x13, 0x99, 0x66, 0x28, 0x24, 0xcd, 0x8a, 0xe0, 0x64, 0xee, 0x5d, 0x8d, 0x62, 0x97, 0x08, 0xef,
-	0xc0, 0x37, 0xa3, 0xa8, 0xbe, 0x9c, 0x66, 0x28, 0x0a, 0xca, 0xd0, 0x0e, 0xe3, 0x12, 0x6a, 0xca,
-	0x82, 0xca, 0xc4, 0x4e, 0xa3, 0xdf, 0x24, 0x82, 0x09, 0xab, 0xd7, 0xd0, 0xc3, 0x4c, 0x97, 0x4f,
-	0x16, 0x76, 0xe3, 0x66, 0xbf, 0xd8, 0xf5, 0x84, 0xd7, 0x70, 0xb6, 0x2e, 0x91, 0x4a, 0x8c, 0xf1,
-	0x77, 0x85, 0x42, 0x92, 0x97, 0xe0, 0x9b, 0xaa, 0x16, 0x9c, 0x2e, 0xcf, 0xbb, 0xf0, 0xd8, 0x56,
-	0xc3, 0xc7, 0x70, 0x5e, 0x03, 0x45, 0xc1, 0x73, 0x81, 0x8a, 0xea, 0x7b, 0xb1, 0x39, 0x8e, 0xaa,
-	0x06, 0x3a, 0xaa, 0x2f, 0x78, 0x8f, 0x47, 0x51, 0xd5, 0x40, 0x4b, 0xf5, 0x0a, 0xa6, 0x5f, 0x53,
-	0x21, 0x6b, 0xa2, 0x07, 0xff, 0xd2, 0xf0, 0x2d, 0x9c, 0x9a, 0x66, 0x03, 0x56, 0xb2, 0x3b, 0x7a,
-	0x5f, 0xa1, 0x08, 0xbc, 0xf9, 0x68, 0x9f, 0xac, 0xa9, 0x86, 0x1f, 0x61, 0x1a, 0x23, 0xdd, 0x1c,
-	0x24, 0xb2, 0xef, 0x6e, 0x4a, 0xd8, 0x10, 0x38, 0xe1, 0x83, 0xf6, 0xfd, 0x04, 0xa7, 0x3f, 0xa8,
-	0x64, 0xc9, 0xf1, 0xca, 0xbf, 0xe0, 0xcc, 0x32, 0x58, 0xe9, 0x87, 0x29, 0x3a, 0x06, 0x1b, 0xfe,
-	0xdf, 0x60, 0xcb, 0xbf, 0x43, 0xf0, 0xd7, 0xba, 0x4e, 0xde, 0x83, 0x6f, 0x2c, 0x43, 0x2e, 0x1a,
-	0x48, 0xdb, 0x7b, 0xb3, 0xcb, 0x7e, 0xda, 0xde, 0x70, 0xa0, 0xa0, 0xc6, 0x22, 0x0e, 0xda, 0xf1,
-	0x9a, 0x83, 0xf6, 0x9c, 0xa4, 0xa1, 0xc6, 0x12, 0x0e, 0xda, 0xf1, 0x96, 0x83, 0xf6, 0x9c, 0x33,
-	0x20, 0x2b, 0x18, 0x2b, 0x3b, 0x90, 0xa7, 0x75, 0x47, 0xcb, 0x49, 0xb3, 0x67, 0xdd, 0x64, 0x1b,
-	0xa4, 0x4e, 0xe9, 0x40, 0x2d, 0x67, 0x38, 0x50, 0xfb, 0xda, 0xe1, 0x80, 0xbc, 0x83, 0x13, 0x7d,
-	0x05, 0xd2, 0x34, 0xb4, 0xcf, 0x3a, 0xbb, 0xe8, 0x65, 0x6b, 0xdc, 0x6b, 0xef, 0xf3, 0xf5, 0xcf,
-	0x37, 0xdb, 0x54, 0x26, 0xd5, 0xcd, 0x82, 0xf1, 0x2c, 0xca, 0x52, 0x56, 0x72, 0xfb, 0xbb, 0x5b,
-	0x45, 0xfb, 0x3e, 0x80, 0x1f, 0x4c, 0x70, 0xe3, 0xeb, 0x68, 0xf5, 0x2f, 0x00, 0x00, 0xff, 0xff,
-	0xa0, 0x43, 0x27, 0x57, 0x26, 0x05, 0x00, 0x00,
+	// 569 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa5, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xad, 0x9b, 0x34, 0xc2, 0x93, 0xa6, 0x4a, 0xb6, 0xb4, 0x72, 0x2d, 0x0e, 0xe0, 0x03, 0x42,
+	0x6a, 0x55, 0xa3, 0x44, 0x50, 0x10, 0x07, 0x50, 0x43, 0x6f, 0x9c, 0x5c, 0x21, 0x24, 0x84, 0x40,
+	0x5b, 0x7b, 0x1b, 0x5b, 0xad, 0x63, 0x63, 0x6f, 0x22, 0xfe, 0x02, 0xff, 0x94, 0x9f, 0xc1, 0x7e,
+	0x7a, 0x6d, 0xcb, 0xaa, 0x42, 0xb8, 0x24, 0x3b, 0x1f, 0xef, 0xcd, 0xec, 0xec, 0x3c, 0x19, 0x9e,
+	0x95, 0xa4, 0x58, 0x27, 0x21, 0xf1, 0xc3, 0x6c, 0x79, 0x9b, 0x2c, 0xfc, 0xbc, 0xc8, 0x68, 0xa6,
+	0x8c, 0x73, 0x61, 0xa0, 0x81, 0xb4, 0xbc, 0xdf, 0x16, 0xd8, 0xf3, 0x18, 0x2f, 0x17, 0xe4, 0x9a,
+	0x50, 0x84, 0xa0, 0x1f, 0x61, 0x8a, 0x1d, 0xeb, 0xa9, 0xf5, 0xc2, 0x0e, 0xc4, 0x19, 0xb9, 0xf0,
+	0x28, 0x8c, 0x49, 0x78, 0x57, 0xae, 0x52, 0x67, 0x57, 0xf8, 0x2b, 0x1b, 0x1d, 0xc3, 0xe0, 0x36,
+	0x2b, 0x52, 0x4c, 0x9d, 0x9e, 0x88, 0x28, 0x8b, 0xfb, 0xcb, 0x6c, 0x55, 0x84, 0xc4, 0xe9, 0x4b,
+	0xbf, 0xb4, 0xd0, 0x13, 0xb0, 0x69, 0x92, 0x92, 0x92, 0xe2, 0x34, 0x77, 0xf6, 0x58, 0xa8, 0x17,
+	0x18, 0x87, 0x77, 0x07, 0x03, 0xd9, 0x0a, 0xcf, 0x5b, 0x62, 0xe6, 0xce, 0x31, 0xa3, 0x90, 0xcd,
+	0x18, 0x07, 0xef, 0x32, 0xc7, 0x34, 0x56, 0xdd, 0x88, 0x33, 0xf2, 0xc1, 0x0e, 0xf5, 0x35, 0x44,
+	0x33, 0xc3, 0xe9, 0xe4, 0x5c, 0xdd, 0xb8, 0xba, 0x5f, 0x60, 0x72, 0xbc, 0x0b, 0x18, 0xcd, 0x0b,
+	0x82, 0x29, 0x09, 0xc8, 0xcf, 0x15, 0x6b, 0x00, 0x3d, 0x87, 0x81, 0x8c, 0x8a, 0x82, 0xc3, 0xe9,
+	0x41, 0x13, 0x1e, 0xa8, 0xa8, 0x37, 0x86, 0x03, 0x0d, 0x2c, 0xf3, 0x6c, 0x59, 0x12, 0x2f, 0x82,
+	0xd1, 0xe7, 0x3c, 0xfa, 0x77, 0x2a, 0x74, 0x0a, 0x13, 0xf2, 0x2b, 0x27, 0x21, 0x25, 0xd1, 0x8f,
+	0xd6, 0x8c, 0xc7, 0x3a, 0x30, 0x57, 0x7e, 0x5e, 0x57, 0x57, 0x51, 0x75, 0xd9, 0x15, 0x3e, 0x92,
+	0x7b, 0xb2, 0xd5, 0x15, 0x34, 0x50, 0x51, 0x9d, 0xc2, 0xf0, 0x53, 0x52, 0x52, 0x4d, 0xf4, 0xe0,
+	0xfc, 0xbd, 0xd7, 0xb0, 0x2f, 0x93, 0x25, 0x98, 0x97, 0x5d, 0xe3, 0x7b, 0x06, 0x64, 0xa9, 0xbd,
+	0xae, 0xb2, 0x32, 0xea, 0xbd, 0x87, 0x61, 0x40, 0x70, 0xb4, 0x51, 0x91, 0xae, 0x47, 0xe6, 0x85,
+	0x25, 0x81, 0x29, 0xbc, 0xd1, 0x7d, 0x3f, 0xc0, 0xfe, 0x17, 0x4c, 0xc3, 0x78, 0xfb, 0xca, 0xdf,
+	0x61, 0xa4, 0x18, 0x54, 0xe9, 0x87, 0x29, 0x1a, 0xdb, 0xb8, 0xbb, 0xc1, 0x36, 0x7e, 0x83, 0x31,
+	0xfb, 0xbb, 0x66, 0x4b, 0x90, 0xe2, 0xad, 0xbb, 0x14, 0xb2, 0x13, 0x14, 0x5a, 0x8e, 0xd2, 0xf2,
+	0x0e, 0x61, 0x52, 0x63, 0x57, 0x4f, 0x7e, 0x05, 0x13, 0x3e, 0xcc, 0xff, 0xac, 0xe9, 0x9d, 0x01,
+	0xaa, 0xd3, 0xa8, 0xf1, 0x98, 0x4e, 0xac, 0x7a, 0x27, 0xd3, 0x3f, 0x3d, 0xa6, 0x71, 0x31, 0x07,
+	0xf4, 0x96, 0x9d, 0x84, 0x8e, 0xd0, 0x51, 0x35, 0x9a, 0xba, 0x20, 0xdd, 0xe3, 0xb6, 0x5b, 0x35,
+	0xbe, 0xc3, 0xa1, 0x52, 0x0a, 0x06, 0xda, 0x10, 0xa0, 0x81, 0xb6, 0x14, 0x23, 0xa0, 0x72, 0xf5,
+	0x0d, 0xb4, 0xa1, 0x21, 0x03, 0x6d, 0x29, 0x64, 0x07, 0xcd, 0xa0, 0xcf, 0xd7, 0x1e, 0x1d, 0xea,
+	0x8c, 0x9a, 0x62, 0xdc, 0xc7, 0x4d, 0x67, 0x1d, 0xc4, 0xc7, 0x63, 0x40, 0x35, 0x05, 0x18, 0x50,
+	0x7d, 0xab, 0x19, 0xe8, 0x12, 0xec, 0xea, 0xbd, 0x90, 0xa3, 0x93, 0xda, 0x0b, 0xe2, 0x9e, 0x74,
+	0x44, 0x2a, 0x8e, 0x2b, 0x00, 0xf3, 0x2e, 0xe8, 0xa4, 0x5e, 0xa9, 0xc9, 0xe2, 0x76, 0x85, 0x2a,
+	0x9a, 0x37, 0xb0, 0x27, 0x16, 0x1f, 0x55, 0xbd, 0xd6, 0x95, 0xe4, 0x1e, 0xb5, 0xbc, 0x1a, 0xf7,
+	0xd2, 0xba, 0xbc, 0xf8, 0xfa, 0x6a, 0x91, 0xd0, 0x78, 0x75, 0xc3, 0x52, 0x52, 0x3f, 0x4d, 0xc2,
+	0x22, 0x53, 0xbf, 0xeb, 0x99, 0xdf, 0xf5, 0x81, 0x7a, 0x27, 0x8d, 0x9b, 0x81, 0xb0, 0x66, 0x7f,
+	0x01, 0x16, 0x7c, 0xa5, 0xdd, 0xc6, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -688,6 +882,8 @@ type ConfigClient interface {
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	SetSchema(ctx context.Context, in *SetSchemaRequest, opts ...grpc.CallOption) (*SetSchemaResponse, error)
+	ReadSchema(ctx context.Context, in *ReadSchemaRequest, opts ...grpc.CallOption) (*ReadSchemaResponse, error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Config_WatchClient, error)
 }
 
@@ -744,6 +940,24 @@ func (c *configClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *configClient) SetSchema(ctx context.Context, in *SetSchemaRequest, opts ...grpc.CallOption) (*SetSchemaResponse, error) {
+	out := new(SetSchemaResponse)
+	err := c.cc.Invoke(ctx, "/config.Config/SetSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configClient) ReadSchema(ctx context.Context, in *ReadSchemaRequest, opts ...grpc.CallOption) (*ReadSchemaResponse, error) {
+	out := new(ReadSchemaResponse)
+	err := c.cc.Invoke(ctx, "/config.Config/ReadSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *configClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Config_WatchClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_Config_serviceDesc.Streams[0], "/config.Config/Watch", opts...)
 	if err != nil {
@@ -783,6 +997,8 @@ type ConfigServer interface {
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
+	SetSchema(context.Context, *SetSchemaRequest) (*SetSchemaResponse, error)
+	ReadSchema(context.Context, *ReadSchemaRequest) (*ReadSchemaResponse, error)
 	Watch(*WatchRequest, Config_WatchServer) error
 }
 
@@ -805,6 +1021,12 @@ func (*UnimplementedConfigServer) List(ctx context.Context, req *ListRequest) (*
 func (*UnimplementedConfigServer) Read(ctx context.Context, req *ReadRequest) (*ReadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (*UnimplementedConfigServer) SetSchema(ctx context.Context, req *SetSchemaRequest) (*SetSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSchema not implemented")
+}
+func (*UnimplementedConfigServer) ReadSchema(ctx context.Context, req *ReadSchemaRequest) (*ReadSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadSchema not implemented")
+}
 func (*UnimplementedConfigServer) Watch(req *WatchRequest, srv Config_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
@@ -903,6 +1125,42 @@ func _Config_Read_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Config_SetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServer).SetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config.Config/SetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServer).SetSchema(ctx, req.(*SetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Config_ReadSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServer).ReadSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/config.Config/ReadSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServer).ReadSchema(ctx, req.(*ReadSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Config_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(WatchRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -948,6 +1206,14 @@ var _Config_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Read",
 			Handler:    _Config_Read_Handler,
 		},
+		{
+			MethodName: "SetSchema",
+			Handler:    _Config_SetSchema_Handler,
+		},
+		{
+			MethodName: "ReadSchema",
+			Handler:    _Config_ReadSchema_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
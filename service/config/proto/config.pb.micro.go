@@ -47,6 +47,8 @@ type ConfigService interface {
 	Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (*ListResponse, error)
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
+	SetSchema(ctx context.Context, in *SetSchemaRequest, opts ...client.CallOption) (*SetSchemaResponse, error)
+	ReadSchema(ctx context.Context, in *ReadSchemaRequest, opts ...client.CallOption) (*ReadSchemaResponse, error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Config_WatchService, error)
 }
 
@@ -112,6 +114,26 @@ func (c *configService) Read(ctx context.Context, in *ReadRequest, opts ...clien
 	return out, nil
 }
 
+func (c *configService) SetSchema(ctx context.Context, in *SetSchemaRequest, opts ...client.CallOption) (*SetSchemaResponse, error) {
+	req := c.c.NewRequest(c.name, "Config.SetSchema", in)
+	out := new(SetSchemaResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configService) ReadSchema(ctx context.Context, in *ReadSchemaRequest, opts ...client.CallOption) (*ReadSchemaResponse, error) {
+	req := c.c.NewRequest(c.name, "Config.ReadSchema", in)
+	out := new(ReadSchemaResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *configService) Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Config_WatchService, error) {
 	req := c.c.NewRequest(c.name, "Config.Watch", &WatchRequest{})
 	stream, err := c.c.Stream(ctx, req, opts...)
@@ -169,6 +191,8 @@ type ConfigHandler interface {
 	Delete(context.Context, *DeleteRequest, *DeleteResponse) error
 	List(context.Context, *ListRequest, *ListResponse) error
 	Read(context.Context, *ReadRequest, *ReadResponse) error
+	SetSchema(context.Context, *SetSchemaRequest, *SetSchemaResponse) error
+	ReadSchema(context.Context, *ReadSchemaRequest, *ReadSchemaResponse) error
 	Watch(context.Context, *WatchRequest, Config_WatchStream) error
 }
 
@@ -179,6 +203,8 @@ func RegisterConfigHandler(s server.Server, hdlr ConfigHandler, opts ...server.H
 		Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error
 		List(ctx context.Context, in *ListRequest, out *ListResponse) error
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
+		SetSchema(ctx context.Context, in *SetSchemaRequest, out *SetSchemaResponse) error
+		ReadSchema(ctx context.Context, in *ReadSchemaRequest, out *ReadSchemaResponse) error
 		Watch(ctx context.Context, stream server.Stream) error
 	}
 	type Config struct {
@@ -212,6 +238,14 @@ func (h *configHandler) Read(ctx context.Context, in *ReadRequest, out *ReadResp
 	return h.ConfigHandler.Read(ctx, in, out)
 }
 
+func (h *configHandler) SetSchema(ctx context.Context, in *SetSchemaRequest, out *SetSchemaResponse) error {
+	return h.ConfigHandler.SetSchema(ctx, in, out)
+}
+
+func (h *configHandler) ReadSchema(ctx context.Context, in *ReadSchemaRequest, out *ReadSchemaResponse) error {
+	return h.ConfigHandler.ReadSchema(ctx, in, out)
+}
+
 func (h *configHandler) Watch(ctx context.Context, stream server.Stream) error {
 	m := new(WatchRequest)
 	if err := stream.Recv(m); err != nil {
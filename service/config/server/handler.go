@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +18,7 @@ import (
 	pb "github.com/micro/micro/v3/service/config/proto"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/bulk"
 )
 
 const (
@@ -30,6 +33,12 @@ var (
 	// we now support json only
 	reader = jr.NewReader()
 	mtx    sync.RWMutex
+
+	// errConfigChanged is returned when the config changed since it was read
+	errConfigChanged = goerrors.New("config changed")
+	// maxWriteAttempts is the number of times a change is applied to the latest config before
+	// giving up, when it keeps being changed concurrently
+	maxWriteAttempts = 5
 )
 
 type Config struct{}
@@ -125,6 +134,11 @@ func (c *Config) Create(ctx context.Context, req *pb.CreateRequest, rsp *pb.Crea
 
 	req.Change.ChangeSet.Timestamp = time.Now().Unix()
 
+	// check the config matches the schemas registered for it
+	if err := validateSchemas(req.Change.Namespace, []byte(req.Change.ChangeSet.Data)); err != nil {
+		return errors.BadRequest("config.Config.Create", "invalid config: %v", err)
+	}
+
 	record := &gostore.Record{Key: req.Change.Namespace}
 
 	var err error
@@ -161,24 +175,55 @@ func (c *Config) Update(ctx context.Context, req *pb.UpdateRequest, rsp *pb.Upda
 
 	// set the changeset timestamp
 	req.Change.ChangeSet.Timestamp = time.Now().Unix()
+
+	// the change is applied to the latest config and only written if it's unchanged since, so
+	// concurrent updates of the namespace by any replica aren't lost
+	change := req.Change
+	for i := 0; i < maxWriteAttempts; i++ {
+		ch, err := update(change, req.ExpectedChecksum)
+		if err == errConfigChanged && len(req.ExpectedChecksum) == 0 {
+			continue
+		} else if err == errConfigChanged {
+			return errors.Conflict("config.Config.Update", "config has changed since it was read")
+		} else if err != nil {
+			return err
+		}
+
+		req.Change = ch
+		_ = publish(ctx, &pb.WatchResponse{Namespace: ch.Namespace, ChangeSet: ch.ChangeSet})
+		return nil
+	}
+
+	return errors.Conflict("config.Config.Update", "config is being changed concurrently, try again")
+}
+
+// update applies the change to the config of its namespace, returning the change written
+func update(change *pb.Change, expectedChecksum string) (*pb.Change, error) {
 	oldCh := &pb.Change{}
 
 	// Get the current change set
 	var record *gostore.Record
-	records, err := store.Read(req.Change.Namespace)
+	var old []byte
+	records, err := store.Read(change.Namespace)
 	if err != nil {
 		if err.Error() != "not found" {
-			return errors.BadRequest("config.Config.Update", "read old value error: %v", err)
+			return nil, errors.BadRequest("config.Config.Update", "read old value error: %v", err)
 		}
 		// create new record
 		record = new(gostore.Record)
-		record.Key = req.Change.Namespace
+		record.Key = change.Namespace
 	} else {
 		// Unmarshal value
 		if err := json.Unmarshal(records[0].Value, oldCh); err != nil {
-			return errors.BadRequest("config.Config.Update", "unmarshal value error: %v", err)
+			return nil, errors.BadRequest("config.Config.Update", "unmarshal value error: %v", err)
 		}
 		record = records[0]
+		old = record.Value
+	}
+
+	// reject the update if the config changed since the caller read it
+	if len(expectedChecksum) > 0 && (oldCh.ChangeSet == nil || oldCh.ChangeSet.Checksum != expectedChecksum) {
+		return nil, errConfigChanged
 	}
 
 	// generate a new base changeset
@@ -200,56 +245,64 @@ func (c *Config) Update(ctx context.Context, req *pb.UpdateRequest, rsp *pb.Upda
 	var newChange *source.ChangeSet
 
 	// Set the change at a particular path
-	if len(req.Change.Path) > 0 {
+	if len(change.Path) > 0 {
 		// Get values from existing change
 		values, err := values(changeSet)
 		if err != nil {
-			return errors.InternalServerError("config.Config.Update", "error getting existing change: %v", err)
+			return nil, errors.InternalServerError("config.Config.Update", "error getting existing change: %v", err)
 		}
 
 		// Apply the data to the existing change
-		values.Set(req.Change.ChangeSet.Data, strings.Split(req.Change.Path, pathSplitter)...)
+		values.Set(change.ChangeSet.Data, strings.Split(change.Path, pathSplitter)...)
 
 		// Create a new change
 		newChange, err = merge(&source.ChangeSet{Data: values.Bytes()})
 		if err != nil {
-			return errors.InternalServerError("config.Config.Update", "create a new change error: %v", err)
+			return nil, errors.InternalServerError("config.Config.Update", "create a new change error: %v", err)
 		}
 	} else {
 		// No path specified, business as usual
 		newChange, err = merge(changeSet, &source.ChangeSet{
-			Timestamp: time.Unix(req.Change.ChangeSet.Timestamp, 0),
-			Data:      []byte(req.Change.ChangeSet.Data),
-			Checksum:  req.Change.ChangeSet.Checksum,
-			Source:    req.Change.ChangeSet.Source,
-			Format:    req.Change.ChangeSet.Format,
+			Timestamp: time.Unix(change.ChangeSet.Timestamp, 0),
+			Data:      []byte(change.ChangeSet.Data),
+			Checksum:  change.ChangeSet.Checksum,
+			Source:    change.ChangeSet.Source,
+			Format:    change.ChangeSet.Format,
 		})
 		if err != nil {
-			return errors.BadRequest("config.Config.Update", "merge all error: %v", err)
+			return nil, errors.BadRequest("config.Config.Update", "merge all error: %v", err)
 		}
 	}
 
-	// update change set
-	req.Change.ChangeSet = &pb.ChangeSet{
-		Timestamp: newChange.Timestamp.Unix(),
-		Data:      string(newChange.Data),
-		Checksum:  newChange.Checksum,
-		Source:    newChange.Source,
-		Format:    newChange.Format,
+	// check the config matches the schemas registered for it
+	if err := validateSchemas(change.Namespace, newChange.Data); err != nil {
+		return nil, errors.BadRequest("config.Config.Update", "invalid config: %v", err)
 	}
 
-	record.Value, err = json.Marshal(req.Change)
+	// update change set
+	ch := &pb.Change{
+		Namespace: change.Namespace,
+		Path:      change.Path,
+		ChangeSet: &pb.ChangeSet{
+			Timestamp: newChange.Timestamp.Unix(),
+			Data:      string(newChange.Data),
+			Checksum:  newChange.Checksum,
+			Source:    newChange.Source,
+			Format:    newChange.Format,
+		},
+	}
+
+	record.Value, err = json.Marshal(ch)
 	if err != nil {
-		return errors.BadRequest("config.Config.Update", "marshal error: %v", err)
+		return nil, errors.BadRequest("config.Config.Update", "marshal error: %v", err)
 	}
 
-	if err := store.Write(record); err != nil {
-		return errors.BadRequest("config.Config.Update", "update into db error: %v", err)
+	if err := writeIfUnchanged(record, old); err == errConfigChanged {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.BadRequest("config.Config.Update", "update into db error: %v", err)
 	}
-
-	_ = publish(ctx, &pb.WatchResponse{Namespace: req.Change.Namespace, ChangeSet: req.Change.ChangeSet})
-
-	return nil
+	return ch, nil
 }
 
 func (c *Config) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.DeleteResponse) error {
@@ -283,21 +336,43 @@ func (c *Config) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.Dele
 		return nil
 	}
 
-	// We've got a path. Let's update the required path
+	// We've got a path. Let's update the required path, retrying if the config is changed
+	// concurrently
+	for i := 0; i < maxWriteAttempts; i++ {
+		ch, err := deletePath(req.Change)
+		if err == errConfigChanged {
+			continue
+		} else if err != nil {
+			return err
+		} else if ch == nil {
+			return nil
+		}
+
+		req.Change = ch
+		_ = publish(ctx, &pb.WatchResponse{Namespace: ch.Namespace, ChangeSet: ch.ChangeSet})
+		return nil
+	}
+
+	return errors.Conflict("config.Config.Delete", "config is being changed concurrently, try again")
+}
 
+// deletePath deletes the path of the change from the config of its namespace, returning the
+// change written or nil if there's no config
+func deletePath(change *pb.Change) (*pb.Change, error) {
 	// Get the current change set
-	records, err := store.Read(req.Change.Namespace)
+	records, err := store.Read(change.Namespace)
 	if err != nil {
 		if err.Error() != "not found" {
-			return errors.BadRequest("config.Config.Delete", "read old value error: %v", err)
+			return nil, errors.BadRequest("config.Config.Delete", "read old value error: %v", err)
 		}
-		return nil
+		return nil, nil
 	}
+	old := records[0].Value
 
 	ch := &pb.Change{}
 	// Unmarshal value
-	if err := json.Unmarshal(records[0].Value, ch); err != nil {
-		return errors.BadRequest("config.Config.Delete", "unmarshal value error: %v", err)
+	if err := json.Unmarshal(old, ch); err != nil {
+		return nil, errors.BadRequest("config.Config.Delete", "unmarshal value error: %v", err)
 	}
 
 	// Get the current config as values
@@ -309,38 +384,64 @@ func (c *Config) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.Dele
 		Format:    ch.ChangeSet.Format,
 	})
 	if err != nil {
-		return errors.BadRequest("config.Config.Delete", "Get the current config as values error: %v", err)
+		return nil, errors.BadRequest("config.Config.Delete", "Get the current config as values error: %v", err)
 	}
 
 	// Delete at the given path
-	values.Del(strings.Split(req.Change.Path, pathSplitter)...)
+	values.Del(strings.Split(change.Path, pathSplitter)...)
 
 	// Create a change record from the values
-	change, err := merge(&source.ChangeSet{Data: values.Bytes()})
+	cs, err := merge(&source.ChangeSet{Data: values.Bytes()})
 	if err != nil {
-		return errors.BadRequest("config.Config.Delete", "Create a change record from the values error: %v", err)
+		return nil, errors.BadRequest("config.Config.Delete", "Create a change record from the values error: %v", err)
 	}
 
 	// Update change set
-	req.Change.ChangeSet = &pb.ChangeSet{
-		Timestamp: change.Timestamp.Unix(),
-		Data:      string(change.Data),
-		Checksum:  change.Checksum,
-		Format:    change.Format,
-		Source:    change.Source,
-	}
-
-	records[0].Value, err = json.Marshal(req.Change)
+	ch = &pb.Change{
+		Namespace: change.Namespace,
+		Path:      change.Path,
+		ChangeSet: &pb.ChangeSet{
+			Timestamp: cs.Timestamp.Unix(),
+			Data:      string(cs.Data),
+			Checksum:  cs.Checksum,
+			Format:    cs.Format,
+			Source:    cs.Source,
+		},
+	}
+
+	records[0].Value, err = json.Marshal(ch)
 	if err != nil {
-		return errors.BadRequest("config.Config.Delete", "marshal error: %v", err)
+		return nil, errors.BadRequest("config.Config.Delete", "marshal error: %v", err)
 	}
 
-	if err := store.Write(records[0]); err != nil {
-		return errors.BadRequest("config.Config.Delete", "update record set to db error: %v", err)
+	if err := writeIfUnchanged(records[0], old); err == errConfigChanged {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.BadRequest("config.Config.Delete", "update record set to db error: %v", err)
 	}
+	return ch, nil
+}
 
-	_ = publish(ctx, &pb.WatchResponse{Namespace: req.Change.Namespace, ChangeSet: req.Change.ChangeSet})
+// writeIfUnchanged writes the record only if the value stored is still the old one, or if
+// there's no record when old is nil, returning errConfigChanged otherwise. The condition is
+// checked by the store so it holds across replicas of the config service.
+func writeIfUnchanged(record *gostore.Record, old []byte) error {
+	cond := bulk.Condition{NotExists: true}
+	if old != nil {
+		cond = bulk.Condition{ETag: bulk.ETag(old)}
+	}
 
+	results, err := store.BulkUpsert([]*bulk.Upsert{{Record: record, Condition: cond}})
+	if err != nil {
+		return err
+	} else if len(results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Conflict {
+		return errConfigChanged
+	} else if len(results[0].Error) > 0 {
+		return goerrors.New(results[0].Error)
+	}
 	return nil
 }
 
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/micro/go-micro/v3/config/source"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/config/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// schemaPrefix is prefixed to the keys the schemas are stored under, followed by the namespace
+// and path of the config they describe
+const schemaPrefix = "schema/"
+
+// SetSchema registers the JSON schema the config at a path must match. Updates to the config
+// which don't match the schema are rejected.
+func (c *Config) SetSchema(ctx context.Context, req *pb.SetSchemaRequest, rsp *pb.SetSchemaResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = defaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("config.Config.SetSchema", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("config.Config.SetSchema", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("config.Config.SetSchema", err.Error())
+	}
+
+	key := schemaPrefix + req.Namespace + "/" + req.Path
+	if len(req.Schema) == 0 {
+		if err := store.Delete(key); err != nil && err != gostore.ErrNotFound {
+			return errors.InternalServerError("config.Config.SetSchema", "delete schema error: %v", err)
+		}
+		return nil
+	}
+
+	var schema util.Schema
+	if err := json.Unmarshal([]byte(req.Schema), &schema); err != nil {
+		return errors.BadRequest("config.Config.SetSchema", "invalid schema: %v", err)
+	}
+	if err := store.Write(&gostore.Record{Key: key, Value: []byte(req.Schema)}); err != nil {
+		return errors.InternalServerError("config.Config.SetSchema", "write schema error: %v", err)
+	}
+	return nil
+}
+
+// ReadSchema returns the JSON schema registered for the config at a path
+func (c *Config) ReadSchema(ctx context.Context, req *pb.ReadSchemaRequest, rsp *pb.ReadSchemaResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = defaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("config.Config.ReadSchema", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("config.Config.ReadSchema", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("config.Config.ReadSchema", err.Error())
+	}
+
+	recs, err := store.Read(schemaPrefix + req.Namespace + "/" + req.Path)
+	if err == gostore.ErrNotFound {
+		return errors.NotFound("config.Config.ReadSchema", "Not found")
+	} else if err != nil {
+		return errors.InternalServerError("config.Config.ReadSchema", "read schema error: %v", err)
+	}
+
+	rsp.Schema = string(recs[0].Value)
+	return nil
+}
+
+// validateSchemas checks the config of the namespace matches the schemas registered for it
+func validateSchemas(ns string, data []byte) error {
+	recs, err := store.Read(schemaPrefix+ns+"/", gostore.ReadPrefix())
+	if err == gostore.ErrNotFound || len(recs) == 0 {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	vals, err := values(&source.ChangeSet{Format: "json", Data: data})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range recs {
+		var schema util.Schema
+		if err := json.Unmarshal(r.Value, &schema); err != nil {
+			continue
+		}
+
+		path := strings.TrimPrefix(r.Key, schemaPrefix+ns+"/")
+		val := vals.Get()
+		if len(path) > 0 {
+			val = vals.Get(strings.Split(path, pathSplitter)...)
+		}
+		b := val.Bytes()
+		if len(b) == 0 || string(b) == "null" {
+			continue
+		}
+		if err := util.Validate(&schema, b); err != nil {
+			if len(path) == 0 {
+				return err
+			}
+			return fmt.Errorf("%v: %v", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package context
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/go-micro/v3/metadata"
+)
+
+// Metadata keys the end user's locale is propagated with. The API sets the language from the
+// Accept-Language header if it isn't provided.
+const (
+	LanguageKey = "Micro-Language"
+	TimezoneKey = "Micro-Timezone"
+	CurrencyKey = "Micro-Currency"
+)
+
+var (
+	// DefaultLocale is returned by GetLocale for the values not set in the context
+	DefaultLocale = Locale{
+		Language: "en",
+		Timezone: "UTC",
+		Currency: "USD",
+	}
+)
+
+// Locale of the end user a request is being made on behalf of
+type Locale struct {
+	// Language is a BCP 47 language tag, e.g. en-GB
+	Language string
+	// Timezone is an IANA time zone, e.g. Europe/London
+	Timezone string
+	// Currency is an ISO 4217 currency code, e.g. GBP
+	Currency string
+}
+
+// Location returns the time zone of the locale, or UTC if it's invalid
+func (l Locale) Location() *time.Location {
+	loc, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Metadata returns the metadata the locale is propagated with, omitting blank values
+func (l Locale) Metadata() map[string]string {
+	md := make(map[string]string)
+	if len(l.Language) > 0 {
+		md[LanguageKey] = l.Language
+	}
+	if len(l.Timezone) > 0 {
+		md[TimezoneKey] = l.Timezone
+	}
+	if len(l.Currency) > 0 {
+		md[CurrencyKey] = l.Currency
+	}
+	return md
+}
+
+// SetLocale sets the locale within the context so it's sent with the requests made using it.
+// Blank values are ignored, so a single value can be overridden for a request, e.g.
+// SetLocale(ctx, Locale{Currency: "EUR"}).
+func SetLocale(ctx context.Context, l Locale) context.Context {
+	return metadata.MergeContext(ctx, l.Metadata(), true)
+}
+
+// GetLocale returns the locale from the context, using the default locale for missing values
+func GetLocale(ctx context.Context) Locale {
+	l := DefaultLocale
+	if v, ok := metadata.Get(ctx, LanguageKey); ok && len(v) > 0 {
+		l.Language = v
+	}
+	if v, ok := metadata.Get(ctx, TimezoneKey); ok && len(v) > 0 {
+		l.Timezone = v
+	}
+	if v, ok := metadata.Get(ctx, CurrencyKey); ok && len(v) > 0 {
+		l.Currency = v
+	}
+	return l
+}
@@ -0,0 +1,27 @@
+package context
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	if l := GetLocale(context.TODO()); l != DefaultLocale {
+		t.Errorf("Expected the default locale, got %v", l)
+	}
+
+	ctx := SetLocale(context.TODO(), Locale{Language: "en-GB", Timezone: "Europe/London", Currency: "GBP"})
+	// override the currency for a single request
+	override := SetLocale(ctx, Locale{Currency: "EUR"})
+
+	if l := GetLocale(ctx); l.Currency != "GBP" {
+		t.Errorf("Expected the override not to modify the parent context, got %v", l)
+	}
+	l := GetLocale(override)
+	if l.Language != "en-GB" || l.Timezone != "Europe/London" || l.Currency != "EUR" {
+		t.Errorf("Unexpected locale %v", l)
+	}
+	if l.Location().String() != "Europe/London" {
+		t.Errorf("Unexpected location %v", l.Location())
+	}
+}
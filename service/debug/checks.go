@@ -0,0 +1,42 @@
+package debug
+
+import (
+	"context"
+	"sync"
+)
+
+// Check returns an error if the dependency it checks is unhealthy
+type Check func(ctx context.Context) error
+
+var (
+	checksMu sync.RWMutex
+	checks   = map[string]Check{}
+)
+
+// RegisterCheck registers a check of a dependency, e.g. a database, which is reported by the
+// DependencyHealth endpoint alongside the store, broker and the services declared as
+// dependencies
+func RegisterCheck(name string, c Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks[name] = c
+}
+
+// Checks returns the checks registered
+func Checks() map[string]Check {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	cs := make(map[string]Check, len(checks))
+	for name, c := range checks {
+		cs[name] = c
+	}
+	return cs
+}
+
+// DeregisterCheck removes the check registered with the name
+func DeregisterCheck(name string) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	delete(checks, name)
+}
@@ -8,6 +8,7 @@ import (
 	memStats "github.com/micro/go-micro/v3/debug/stats/memory"
 	"github.com/micro/go-micro/v3/debug/trace"
 	memTrace "github.com/micro/go-micro/v3/debug/trace/memory"
+	"github.com/micro/micro/v3/service/debug/latency"
 )
 
 var (
@@ -15,4 +16,5 @@ var (
 	DefaultTracer   trace.Tracer    = memTrace.NewTracer()
 	DefaultStats    stats.Stats     = memStats.NewStats()
 	DefaultProfiler profile.Profile = nil
+	DefaultLatency                  = latency.NewRecorder()
 )
@@ -0,0 +1,6 @@
+package handler
+
+// cpuTime is not supported on windows
+func cpuTime() uint64 {
+	return 0
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/broker"
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/store"
+	mubroker "github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/debug"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+	muserver "github.com/micro/micro/v3/service/server"
+	mustore "github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultCheckTimeout is how long each dependency has to respond to its check
+	DefaultCheckTimeout = time.Second * 5
+	// HealthTopic the broker is checked by publishing to
+	HealthTopic = "micro.debug.health"
+)
+
+// dependency to check the health of
+type dependency struct {
+	name  string
+	typ   string
+	check debug.Check
+}
+
+// DependencyHealth checks the store, broker, the services declared as dependencies and the
+// checks registered with debug.RegisterCheck, reporting the status and latency of each
+func (d *Debug) DependencyHealth(ctx context.Context, req *pb.DependencyHealthRequest, rsp *pb.DependencyHealthResponse) error {
+	deps := []dependency{
+		{name: "store", typ: "store", check: checkStore},
+		{name: "broker", typ: "broker", check: checkBroker},
+	}
+	for _, name := range declaredDependencies() {
+		deps = append(deps, dependency{name: name, typ: "service", check: d.checkService(name)})
+	}
+	for name, c := range debug.Checks() {
+		deps = append(deps, dependency{name: name, typ: "check", check: c})
+	}
+
+	var wg sync.WaitGroup
+	rsp.Dependencies = make([]*pb.Dependency, len(deps))
+
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep dependency) {
+			defer wg.Done()
+
+			cctx, cancel := context.WithTimeout(ctx, DefaultCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := runCheck(cctx, dep.check)
+			res := &pb.Dependency{
+				Name:    dep.name,
+				Type:    dep.typ,
+				Status:  "ok",
+				Latency: time.Since(start).Nanoseconds(),
+			}
+			if err != nil {
+				res.Status = "error"
+				res.Error = err.Error()
+			}
+			rsp.Dependencies[i] = res
+		}(i, dep)
+	}
+
+	wg.Wait()
+
+	sort.SliceStable(rsp.Dependencies, func(i, j int) bool {
+		return rsp.Dependencies[i].Name < rsp.Dependencies[j].Name
+	})
+	return nil
+}
+
+// runCheck runs the check, returning once the context is done if the check doesn't return
+// before then, since checks may block without honouring the context
+func runCheck(ctx context.Context, check debug.Check) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %v", ctx.Err())
+	}
+}
+
+// declaredDependencies returns the services declared as dependencies of the service
+func declaredDependencies() []string {
+	var deps []string
+	for _, d := range strings.Split(muserver.DefaultServer.Options().Metadata[util.DependenciesKey], ",") {
+		if d = strings.TrimSpace(d); len(d) > 0 {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+func checkStore(ctx context.Context) error {
+	_, err := mustore.List(store.ListLimit(1))
+	if err == store.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func checkBroker(ctx context.Context) error {
+	return mubroker.Publish(HealthTopic, &broker.Message{Header: map[string]string{}})
+}
+
+// checkService returns a check which calls the health endpoint of the service
+func (d *Debug) checkService(name string) debug.Check {
+	return func(ctx context.Context) error {
+		req := d.client.NewRequest(name, "Debug.Health", &pb.HealthRequest{})
+		rsp := &pb.HealthResponse{}
+		if err := d.client.Call(ctx, req, rsp, client.WithRetries(0)); err != nil {
+			return err
+		}
+		if rsp.Status != "ok" {
+			return fmt.Errorf("status %v", rsp.Status)
+		}
+		return nil
+	}
+}
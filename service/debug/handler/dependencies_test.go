@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/broker/memory"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	mubroker "github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/debug"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	mustore "github.com/micro/micro/v3/service/store"
+)
+
+func TestDependencyHealth(t *testing.T) {
+	mustore.DefaultStore = memstore.NewStore()
+	mubroker.DefaultBroker = memory.NewBroker()
+	mubroker.DefaultBroker.Connect()
+
+	debug.RegisterCheck("database", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	rsp := &pb.DependencyHealthResponse{}
+	if err := NewHandler(nil).DependencyHealth(context.TODO(), &pb.DependencyHealthRequest{}, rsp); err != nil {
+		t.Fatalf("Unexpected error checking dependencies: %v", err)
+	}
+
+	status := map[string]string{}
+	for _, d := range rsp.Dependencies {
+		status[d.Name] = d.Status
+	}
+	if status["store"] != "ok" || status["broker"] != "ok" {
+		t.Errorf("Expected the store and broker to be healthy, got %v", rsp.Dependencies)
+	}
+	if status["database"] != "error" || rsp.Dependencies[1].Error != "connection refused" {
+		t.Errorf("Expected the database check to fail, got %v", rsp.Dependencies)
+	}
+}
+
+func TestDependencyHealthTimeout(t *testing.T) {
+	mustore.DefaultStore = memstore.NewStore()
+	mubroker.DefaultBroker = memory.NewBroker()
+	mubroker.DefaultBroker.Connect()
+
+	timeout := DefaultCheckTimeout
+	DefaultCheckTimeout = time.Millisecond * 50
+	defer func() { DefaultCheckTimeout = timeout }()
+
+	// the check blocks without honouring the context
+	block := make(chan struct{})
+	defer close(block)
+	debug.RegisterCheck("hung", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	defer debug.DeregisterCheck("hung")
+
+	start := time.Now()
+	rsp := &pb.DependencyHealthResponse{}
+	if err := NewHandler(nil).DependencyHealth(context.TODO(), &pb.DependencyHealthRequest{}, rsp); err != nil {
+		t.Fatalf("Unexpected error checking dependencies: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("Expected the checks to time out, took %v", time.Since(start))
+	}
+
+	for _, d := range rsp.Dependencies {
+		if d.Name == "hung" && d.Status != "error" {
+			t.Errorf("Expected the hung check to fail, got %v", d)
+		}
+	}
+}
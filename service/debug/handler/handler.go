@@ -10,27 +10,34 @@ import (
 	"github.com/micro/go-micro/v3/debug/stats"
 	"github.com/micro/go-micro/v3/debug/trace"
 	"github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/debug/latency"
 	pb "github.com/micro/micro/v3/service/debug/proto"
 )
 
 // NewHandler returns an instance of the Debug Handler
 func NewHandler(c client.Client) *Debug {
 	return &Debug{
-		log:   debug.DefaultLog,
-		stats: debug.DefaultStats,
-		trace: debug.DefaultTracer,
+		client:  c,
+		log:     debug.DefaultLog,
+		stats:   debug.DefaultStats,
+		trace:   debug.DefaultTracer,
+		latency: debug.DefaultLatency,
 	}
 }
 
 type Debug struct {
 	// must honour the debug handler
 	pb.DebugHandler
+	// the client used to check the health of dependencies
+	client client.Client
 	// the logger for retrieving logs
 	log log.Log
 	// the stats collector
 	stats stats.Stats
 	// the tracer
 	trace trace.Tracer
+	// the latency histograms of the endpoints
+	latency *latency.Recorder
 }
 
 func (d *Debug) Health(ctx context.Context, req *pb.HealthRequest, rsp *pb.HealthResponse) error {
@@ -44,6 +51,9 @@ func (d *Debug) Stats(ctx context.Context, req *pb.StatsRequest, rsp *pb.StatsRe
 		return err
 	}
 
+	// the metrics registered by the packages the service uses
+	rsp.Metrics = debug.ReadMetrics()
+
 	if len(stats) == 0 {
 		return nil
 	}
@@ -57,6 +67,8 @@ func (d *Debug) Stats(ctx context.Context, req *pb.StatsRequest, rsp *pb.StatsRe
 	rsp.Threads = stats[0].Threads
 	rsp.Requests = stats[0].Requests
 	rsp.Errors = stats[0].Errors
+	rsp.Cpu = cpuTime()
+	rsp.Rss = rss()
 
 	return nil
 }
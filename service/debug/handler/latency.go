@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/latency"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// Latency returns the latency histograms of the endpoints over time, with the exemplars of the
+// outlier buckets of each window
+func (d *Debug) Latency(ctx context.Context, req *pb.LatencyRequest, rsp *pb.LatencyResponse) error {
+	if req.Percentile < 0 || req.Percentile > 100 {
+		return errors.BadRequest("debug", "percentile must be between 0 and 100")
+	}
+	percentile := req.Percentile
+	if percentile == 0 {
+		percentile = latency.DefaultPercentile
+	}
+
+	var since time.Time
+	if req.Since > 0 {
+		since = time.Now().Add(-time.Duration(req.Since) * time.Second)
+	}
+
+	for _, h := range d.latency.Read(req.Endpoint, since) {
+		el := &pb.EndpointLatency{
+			Endpoint: h.Endpoint,
+			Bounds:   make([]int64, len(h.Bounds)),
+			Window:   h.Window.Nanoseconds(),
+		}
+		for i, b := range h.Bounds {
+			el.Bounds[i] = b.Nanoseconds()
+		}
+
+		for _, f := range h.Frames {
+			w := &pb.LatencyWindow{Timestamp: f.Start.Unix(), Counts: f.Counts}
+			for _, ex := range f.Outliers(percentile) {
+				w.Exemplars = append(w.Exemplars, &pb.Exemplar{
+					Bucket:    int32(ex.Bucket),
+					Trace:     ex.Trace,
+					Latency:   ex.Latency.Nanoseconds(),
+					Timestamp: ex.Time.Unix(),
+				})
+			}
+			el.Windows = append(el.Windows, w)
+		}
+		rsp.Endpoints = append(rsp.Endpoints, el)
+	}
+
+	return nil
+}
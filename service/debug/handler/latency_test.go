@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/latency"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+)
+
+func TestLatency(t *testing.T) {
+	d := NewHandler(nil)
+	d.latency = latency.NewRecorder(latency.Bounds(time.Millisecond * 10))
+	for i := 0; i < 19; i++ {
+		d.latency.Record("Users.Read", "fast", time.Millisecond)
+	}
+	d.latency.Record("Users.Read", "slow", time.Second)
+	d.latency.Record("Users.Create", "create", time.Millisecond)
+
+	rsp := &pb.LatencyResponse{}
+	if err := d.Latency(context.TODO(), &pb.LatencyRequest{Endpoint: "Users.Read", Since: 3600, Percentile: 99}, rsp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rsp.Endpoints) != 1 || len(rsp.Endpoints[0].Windows) != 1 {
+		t.Fatalf("Expected a window of Users.Read, got %v", rsp.Endpoints)
+	}
+	e := rsp.Endpoints[0]
+	if len(e.Bounds) != 1 || e.Bounds[0] != int64(time.Millisecond*10) || e.Window != int64(latency.DefaultWindow) {
+		t.Errorf("Unexpected bounds %v and window %v", e.Bounds, e.Window)
+	}
+	w := e.Windows[0]
+	if len(w.Counts) != 2 || w.Counts[0] != 19 || w.Counts[1] != 1 {
+		t.Errorf("Unexpected counts %v", w.Counts)
+	}
+	if len(w.Exemplars) != 1 || w.Exemplars[0].Trace != "slow" || w.Exemplars[0].Bucket != 1 {
+		t.Errorf("Expected the slow request to be the only exemplar at p99, got %v", w.Exemplars)
+	}
+
+	if err := d.Latency(context.TODO(), &pb.LatencyRequest{Percentile: 120}, rsp); err == nil {
+		t.Errorf("Expected an error for an invalid percentile")
+	}
+}
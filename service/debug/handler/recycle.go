@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// recycleDelay gives the response time to reach the caller before the instance shuts down
+	recycleDelay = time.Second
+
+	// shutdown stops the instance gracefully, by default by sending it the signal it shuts down on
+	shutdown = func() error {
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		return p.Signal(syscall.SIGTERM)
+	}
+)
+
+// Recycle shuts the instance down gracefully so the runtime replaces it. Only the runtime, or an
+// admin, can recycle an instance.
+func (d *Debug) Recycle(ctx context.Context, req *pb.RecycleRequest, rsp *pb.RecycleResponse) error {
+	acc, ok := goauth.AccountFromContext(ctx)
+	if !ok {
+		return errors.Unauthorized("debug", "account not found")
+	}
+	if !hasScope(acc, "service") && !hasScope(acc, "admin") {
+		return errors.Forbidden("debug", "only services and admins can recycle an instance")
+	}
+
+	logger.Infof("Instance recycled by %v: %v", acc.ID, req.Reason)
+	go func() {
+		time.Sleep(recycleDelay)
+		if err := shutdown(); err != nil {
+			logger.Errorf("Error recycling the instance: %v", err)
+		}
+	}()
+	return nil
+}
+
+func hasScope(acc *goauth.Account, scope string) bool {
+	for _, s := range acc.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+func TestRecycle(t *testing.T) {
+	recycled := make(chan bool, 1)
+	defer func(fn func() error, d time.Duration) { shutdown, recycleDelay = fn, d }(shutdown, recycleDelay)
+	shutdown = func() error {
+		recycled <- true
+		return nil
+	}
+	recycleDelay = 0
+
+	d := NewHandler(nil)
+	tt := []struct {
+		Name    string
+		Account *goauth.Account
+		Code    int32
+	}{
+		{Name: "NoAccount", Code: 401},
+		{Name: "User", Account: &goauth.Account{ID: "alice", Scopes: []string{"user"}}, Code: 403},
+		{Name: "Runtime", Account: &goauth.Account{ID: "runtime", Scopes: []string{"service"}}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tc.Account != nil {
+				ctx = goauth.ContextWithAccount(ctx, tc.Account)
+			}
+			err := d.Recycle(ctx, &pb.RecycleRequest{Reason: "test"}, &pb.RecycleResponse{})
+			if tc.Code > 0 {
+				if err == nil || errors.Parse(err).Code != tc.Code {
+					t.Fatalf("Expected a %v error, got %v", tc.Code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			select {
+			case <-recycled:
+			case <-time.After(time.Second):
+				t.Fatalf("Expected the instance to shut down")
+			}
+		})
+	}
+}
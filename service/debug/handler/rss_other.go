@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package handler
+
+// rss is only supported on linux
+func rss() uint64 {
+	return 0
+}
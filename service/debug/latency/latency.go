@@ -0,0 +1,267 @@
+// Package latency records histograms of the latency of the requests served by each endpoint
+// over time. Each window of the histogram keeps an exemplar for each bucket, the slowest request
+// in the bucket along with its trace ID, so a spike in latency links straight to a trace of a
+// request which caused it.
+package latency
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultBounds are the upper bounds of the buckets, requests slower than the last bound
+	// are counted in an extra bucket
+	DefaultBounds = []time.Duration{
+		time.Millisecond,
+		time.Microsecond * 2500,
+		time.Millisecond * 5,
+		time.Millisecond * 10,
+		time.Millisecond * 25,
+		time.Millisecond * 50,
+		time.Millisecond * 100,
+		time.Millisecond * 250,
+		time.Millisecond * 500,
+		time.Second,
+		time.Millisecond * 2500,
+		time.Second * 5,
+		time.Second * 10,
+	}
+	// DefaultWindow is the duration of each window of the histogram
+	DefaultWindow = time.Minute
+	// DefaultRetention is how long windows are kept for
+	DefaultRetention = time.Hour * 6
+	// DefaultPercentile at or above which buckets are outliers
+	DefaultPercentile = 95.0
+)
+
+// now is the time requests are recorded at, overridden by tests
+var now = time.Now
+
+// Options of a recorder
+type Options struct {
+	// Bounds are the upper bounds of the buckets, in ascending order
+	Bounds []time.Duration
+	// Window is the duration of each window
+	Window time.Duration
+	// Retention is how long windows are kept for
+	Retention time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Bounds sets the upper bounds of the buckets
+func Bounds(b ...time.Duration) Option {
+	return func(o *Options) {
+		o.Bounds = b
+	}
+}
+
+// Window sets the duration of each window
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Retention sets how long windows are kept for
+func Retention(d time.Duration) Option {
+	return func(o *Options) {
+		o.Retention = d
+	}
+}
+
+// Exemplar is the slowest request recorded in a bucket of a window
+type Exemplar struct {
+	// Bucket is the index of the bucket
+	Bucket int
+	// Trace is the ID of the trace of the request
+	Trace string
+	// Latency of the request
+	Latency time.Duration
+	// Time the request completed
+	Time time.Time
+}
+
+// Frame is the histogram of the requests completed during a window
+type Frame struct {
+	// Start of the window
+	Start time.Time
+	// Counts of the requests in each bucket
+	Counts []uint64
+	// Exemplars of each bucket, nil for buckets without requests with a trace
+	Exemplars []*Exemplar
+}
+
+// Outliers returns the exemplars of the buckets at or above the percentile of the frame
+func (f *Frame) Outliers(p float64) []*Exemplar {
+	var ex []*Exemplar
+	for i := Percentile(f.Counts, p); i >= 0 && i < len(f.Exemplars); i++ {
+		if f.Exemplars[i] != nil {
+			ex = append(ex, f.Exemplars[i])
+		}
+	}
+	return ex
+}
+
+// Histogram of the latency of an endpoint over time
+type Histogram struct {
+	Endpoint string
+	// Bounds are the upper bounds of the buckets, the last bucket has no upper bound
+	Bounds []time.Duration
+	// Window is the duration of each frame
+	Window time.Duration
+	// Frames oldest first, windows without requests have no frame
+	Frames []*Frame
+}
+
+// Percentile returns the index of the bucket the percentile of the counts falls in, or -1 if
+// there are no counts
+func Percentile(counts []uint64, p float64) int {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return -1
+	}
+
+	rank := uint64(math.Ceil(p / 100 * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen uint64
+	for i, c := range counts {
+		seen += c
+		if seen >= rank {
+			return i
+		}
+	}
+	return len(counts) - 1
+}
+
+// Recorder records the latency of the requests to each endpoint
+type Recorder struct {
+	opts Options
+
+	sync.RWMutex
+	// frames of each endpoint, oldest first
+	endpoints map[string][]*Frame
+	// start of the latest window, the frames which are no longer retained are dropped when a
+	// new window starts
+	start time.Time
+}
+
+// NewRecorder returns a recorder without any requests
+func NewRecorder(opts ...Option) *Recorder {
+	options := Options{
+		Bounds:    DefaultBounds,
+		Window:    DefaultWindow,
+		Retention: DefaultRetention,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &Recorder{
+		opts:      options,
+		endpoints: make(map[string][]*Frame),
+	}
+}
+
+// Record the latency of a request to the endpoint and the ID of its trace, blank if it wasn't
+// traced
+func (r *Recorder) Record(endpoint, trace string, d time.Duration) {
+	t := now()
+	start := t.Truncate(r.opts.Window)
+	bucket := sort.Search(len(r.opts.Bounds), func(i int) bool {
+		return d <= r.opts.Bounds[i]
+	})
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.start.Before(start) {
+		r.start = start
+		r.expire(t)
+	}
+
+	frames := r.endpoints[endpoint]
+	if len(frames) == 0 || frames[len(frames)-1].Start.Before(start) {
+		frames = append(frames, &Frame{
+			Start:     start,
+			Counts:    make([]uint64, len(r.opts.Bounds)+1),
+			Exemplars: make([]*Exemplar, len(r.opts.Bounds)+1),
+		})
+		r.endpoints[endpoint] = frames
+	}
+
+	// requests which started in an earlier window are counted in the current one
+	f := frames[len(frames)-1]
+	f.Counts[bucket]++
+	if len(trace) == 0 {
+		return
+	}
+	if ex := f.Exemplars[bucket]; ex == nil || d > ex.Latency {
+		f.Exemplars[bucket] = &Exemplar{Bucket: bucket, Trace: trace, Latency: d, Time: t}
+	}
+}
+
+// expire drops the frames of every endpoint which are no longer retained, and the endpoints
+// left without any frames
+func (r *Recorder) expire(t time.Time) {
+	expired := t.Add(-r.opts.Retention)
+	for name, frames := range r.endpoints {
+		for len(frames) > 0 && frames[0].Start.Add(r.opts.Window).Before(expired) {
+			frames = frames[1:]
+		}
+		if len(frames) == 0 {
+			delete(r.endpoints, name)
+		} else {
+			r.endpoints[name] = frames
+		}
+	}
+}
+
+// Read the histograms of the endpoint, or of every endpoint if it's blank, with the frames of
+// the windows which ended after the time
+func (r *Recorder) Read(endpoint string, since time.Time) []*Histogram {
+	// frames which are no longer retained are skipped until they're dropped
+	if expired := now().Add(-r.opts.Retention); since.Before(expired) {
+		since = expired
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	var hists []*Histogram
+	for name, frames := range r.endpoints {
+		if len(endpoint) > 0 && name != endpoint {
+			continue
+		}
+
+		h := &Histogram{Endpoint: name, Bounds: r.opts.Bounds, Window: r.opts.Window}
+		for _, f := range frames {
+			if f.Start.Add(r.opts.Window).Before(since) {
+				continue
+			}
+			cp := &Frame{
+				Start:     f.Start,
+				Counts:    make([]uint64, len(f.Counts)),
+				Exemplars: make([]*Exemplar, len(f.Exemplars)),
+			}
+			copy(cp.Counts, f.Counts)
+			copy(cp.Exemplars, f.Exemplars)
+			h.Frames = append(h.Frames, cp)
+		}
+		hists = append(hists, h)
+	}
+
+	sort.Slice(hists, func(i, j int) bool {
+		return hists[i].Endpoint < hists[j].Endpoint
+	})
+	return hists
+}
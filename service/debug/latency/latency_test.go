@@ -0,0 +1,88 @@
+package latency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	clock := start
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return clock }
+
+	r := NewRecorder(Bounds(time.Millisecond*10, time.Millisecond*100), Window(time.Minute), Retention(time.Minute*5))
+
+	// the first window is fast apart from a single slow request
+	for i := 0; i < 98; i++ {
+		r.Record("Users.Read", "fast", time.Millisecond)
+	}
+	r.Record("Users.Read", "slow", time.Millisecond*50)
+	r.Record("Users.Read", "slowest", time.Millisecond*80)
+	r.Record("Users.Read", "", time.Millisecond*90)
+	r.Record("Users.Create", "create", time.Second)
+
+	clock = start.Add(time.Minute)
+	r.Record("Users.Read", "spike", time.Second)
+
+	hists := r.Read("Users.Read", time.Time{})
+	if len(hists) != 1 || len(hists[0].Frames) != 2 {
+		t.Fatalf("Expected 2 frames of Users.Read, got %+v", hists)
+	}
+	f := hists[0].Frames[0]
+	if f.Counts[0] != 98 || f.Counts[1] != 3 || f.Counts[2] != 0 {
+		t.Errorf("Unexpected counts %v", f.Counts)
+	}
+
+	// the p95 is in the first bucket so every exemplar is an outlier, the p99 only the slowest
+	if ex := f.Outliers(95); len(ex) != 2 {
+		t.Errorf("Expected 2 outliers at p95, got %v", ex)
+	}
+	ex := f.Outliers(99)
+	if len(ex) != 1 || ex[0].Trace != "slowest" || ex[0].Bucket != 1 {
+		t.Errorf("Expected the slowest request to be the outlier at p99, got %v", ex)
+	}
+	if ex := hists[0].Frames[1].Outliers(95); len(ex) != 1 || ex[0].Trace != "spike" || ex[0].Bucket != 2 {
+		t.Errorf("Expected the spike to be the outlier of the second frame, got %v", ex)
+	}
+
+	if hists := r.Read("", time.Time{}); len(hists) != 2 || hists[0].Endpoint != "Users.Create" {
+		t.Errorf("Expected the histograms of both endpoints, got %+v", hists)
+	}
+	if hists := r.Read("Users.Read", start.Add(time.Minute+time.Second)); len(hists[0].Frames) != 1 {
+		t.Errorf("Expected the frames before the time to be skipped, got %+v", hists[0].Frames)
+	}
+
+	// frames older than the retention aren't read, and are dropped for every endpoint once a
+	// new window starts
+	clock = start.Add(time.Minute * 10)
+	if hists := r.Read("Users.Read", time.Time{}); len(hists[0].Frames) != 0 {
+		t.Errorf("Expected the expired frames to be skipped, got %+v", hists[0].Frames)
+	}
+	r.Record("Users.Read", "later", time.Millisecond)
+	if hists := r.Read("Users.Read", time.Time{}); len(hists[0].Frames) != 1 {
+		t.Errorf("Expected the expired frames to be dropped, got %+v", hists[0].Frames)
+	}
+	if hists := r.Read("Users.Create", time.Time{}); len(hists) != 0 {
+		t.Errorf("Expected the endpoints without frames to be dropped, got %+v", hists)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tt := []struct {
+		Counts     []uint64
+		Percentile float64
+		Bucket     int
+	}{
+		{[]uint64{0, 0, 0}, 95, -1},
+		{[]uint64{90, 9, 1}, 50, 0},
+		{[]uint64{90, 9, 1}, 95, 1},
+		{[]uint64{90, 9, 1}, 100, 2},
+		{[]uint64{0, 0, 5}, 0, 2},
+	}
+	for _, tc := range tt {
+		if b := Percentile(tc.Counts, tc.Percentile); b != tc.Bucket {
+			t.Errorf("Expected p%v of %v to be in bucket %v, got %v", tc.Percentile, tc.Counts, tc.Bucket, b)
+		}
+	}
+}
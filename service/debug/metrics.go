@@ -0,0 +1,45 @@
+package debug
+
+import "sync"
+
+// Metrics returns the current values of a set of metrics, keyed by name
+type Metrics func() map[string]float64
+
+var (
+	metricsMu sync.RWMutex
+	metrics   = map[string]Metrics{}
+)
+
+// RegisterMetrics registers metrics, e.g. the state of the circuit breakers, which are returned
+// by the Stats endpoint. The names of the metrics are prefixed with the name they're
+// registered with, e.g. circuit_breaker.open.
+func RegisterMetrics(name string, m Metrics) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	metrics[name] = m
+}
+
+// ReadMetrics returns the current values of the metrics registered
+func ReadMetrics() map[string]float64 {
+	metricsMu.RLock()
+	ms := make(map[string]Metrics, len(metrics))
+	for name, m := range metrics {
+		ms[name] = m
+	}
+	metricsMu.RUnlock()
+
+	values := make(map[string]float64)
+	for name, m := range ms {
+		for k, v := range m() {
+			values[name+"."+k] = v
+		}
+	}
+	return values
+}
+
+// DeregisterMetrics removes the metrics registered with the name
+func DeregisterMetrics(name string) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	delete(metrics, name)
+}
@@ -0,0 +1,19 @@
+package debug
+
+import "testing"
+
+func TestMetrics(t *testing.T) {
+	RegisterMetrics("foo", func() map[string]float64 {
+		return map[string]float64{"requests": 2}
+	})
+	defer DeregisterMetrics("foo")
+
+	if v := ReadMetrics()["foo.requests"]; v != 2 {
+		t.Fatalf("Expected foo.requests to be 2, got %v", v)
+	}
+
+	DeregisterMetrics("foo")
+	if _, ok := ReadMetrics()["foo.requests"]; ok {
+		t.Fatalf("Expected the metrics to be deregistered")
+	}
+}
@@ -0,0 +1,48 @@
+package probe
+
+import "github.com/micro/go-micro/v3/client"
+
+// Options for running probes
+type Options struct {
+	// Region the probes are run from. Probes for another region are skipped, probes without a
+	// region are run from every region.
+	Region string
+	// Source of the probe definitions
+	Source Source
+	// Client the probes call services with, defaults to the default client
+	Client client.Client
+	// Topic the results of failing probes and of probes which recover are published to, leave
+	// blank to disable
+	Topic string
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Region the probes are run from
+func Region(r string) Option {
+	return func(o *Options) {
+		o.Region = r
+	}
+}
+
+// WithSource sets the source the probe definitions are read from
+func WithSource(s Source) Option {
+	return func(o *Options) {
+		o.Source = s
+	}
+}
+
+// Client the probes call services with
+func Client(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// Topic the results of probes are published to
+func Topic(t string) Option {
+	return func(o *Options) {
+		o.Topic = t
+	}
+}
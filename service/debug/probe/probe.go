@@ -0,0 +1,162 @@
+// Package probe runs synthetic probes, which call an endpoint with a request on an interval and
+// check the response has the expected status within the expected latency, catching outages
+// before users do. The probes are read from the "probes" config, keyed by name, for example:
+//
+//	{
+//		"helloworld": {
+//			"service": "helloworld",
+//			"endpoint": "Helloworld.Call",
+//			"request": {"name": "John"},
+//			"interval": "30s",
+//			"region": "europe-west1",
+//			"status": 200,
+//			"latency": "500ms"
+//		}
+//	}
+//
+// The result of each probe is reported as a check by the DependencyHealth endpoint, counted in
+// the stats of the runner and, when the probe fails or recovers, published for alerting.
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+)
+
+var (
+	// DefaultPath is the path of the probes in the config
+	DefaultPath = []string{"probes"}
+	// DefaultTopic the results of probes are published to
+	DefaultTopic = "probes"
+	// DefaultInterval probes are run at if they don't set one
+	DefaultInterval = time.Second * 30
+	// DefaultTimeout of the calls made by probes which don't set one
+	DefaultTimeout = time.Second * 10
+)
+
+// Probe of an endpoint
+type Probe struct {
+	// Name of the probe
+	Name string
+	// Service and endpoint to call
+	Service  string
+	Endpoint string
+	// Request sent, encoded as JSON
+	Request json.RawMessage
+	// Interval the probe is run at
+	Interval time.Duration
+	// Timeout of the call
+	Timeout time.Duration
+	// Region the probe is run from, blank for every region
+	Region string
+	// Status expected, 200 for a successful call or the code of the error expected
+	Status int
+	// Latency the call must complete within, zero for no limit
+	Latency time.Duration
+}
+
+// Result of running a probe
+type Result struct {
+	// Probe which was run
+	Probe string `json:"probe"`
+	// Service and endpoint which were called
+	Service  string `json:"service"`
+	Endpoint string `json:"endpoint"`
+	// Region the probe was run from
+	Region string `json:"region"`
+	// Status of the call, 200 if it succeeded or the code of the error returned
+	Status int `json:"status"`
+	// Latency of the call
+	Latency time.Duration `json:"latency"`
+	// Error explaining why the probe failed, blank if it passed
+	Error string `json:"error,omitempty"`
+	// Timestamp the probe was run at
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Passed returns true if the probe got the status expected within the latency
+func (r Result) Passed() bool {
+	return len(r.Error) == 0
+}
+
+// Parse the probes from their JSON encoding
+func Parse(b []byte) ([]Probe, error) {
+	var raw map[string]struct {
+		Service  string          `json:"service"`
+		Endpoint string          `json:"endpoint"`
+		Request  json.RawMessage `json:"request"`
+		Interval string          `json:"interval"`
+		Timeout  string          `json:"timeout"`
+		Region   string          `json:"region"`
+		Status   int             `json:"status"`
+		Latency  string          `json:"latency"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	probes := make([]Probe, 0, len(raw))
+	for name, r := range raw {
+		if len(r.Service) == 0 || len(r.Endpoint) == 0 {
+			return nil, fmt.Errorf("probe %v is missing the service or endpoint", name)
+		}
+
+		p := Probe{
+			Name:     name,
+			Service:  r.Service,
+			Endpoint: r.Endpoint,
+			Request:  r.Request,
+			Region:   r.Region,
+			Status:   r.Status,
+			Interval: DefaultInterval,
+			Timeout:  DefaultTimeout,
+		}
+		if len(p.Request) == 0 {
+			p.Request = json.RawMessage("{}")
+		}
+		if p.Status == 0 {
+			p.Status = 200
+		}
+
+		var err error
+		if p.Interval, err = parseDuration(r.Interval, DefaultInterval); err != nil {
+			return nil, fmt.Errorf("invalid interval for probe %v: %v", name, err)
+		}
+		if p.Timeout, err = parseDuration(r.Timeout, DefaultTimeout); err != nil {
+			return nil, fmt.Errorf("invalid timeout for probe %v: %v", name, err)
+		}
+		if p.Latency, err = parseDuration(r.Latency, 0); err != nil {
+			return nil, fmt.Errorf("invalid latency for probe %v: %v", name, err)
+		}
+		probes = append(probes, p)
+	}
+	return probes, nil
+}
+
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if len(s) == 0 {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%v is not positive", s)
+	}
+	return d, nil
+}
+
+// Source returns the encoded probes, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the probes from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
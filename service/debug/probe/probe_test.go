@@ -0,0 +1,99 @@
+package probe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// testClient returns the error set for the endpoint after the delay
+type testClient struct {
+	client.Client
+	delay time.Duration
+	errs  map[string]error
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	time.Sleep(t.delay)
+	return t.errs[req.Endpoint()]
+}
+
+const testProbes = `{
+	"call": {"service": "foo", "endpoint": "Foo.Call", "request": {"name": "John"}, "interval": "1m", "latency": "50ms"},
+	"missing": {"service": "foo", "endpoint": "Foo.Missing", "status": 404},
+	"remote": {"service": "foo", "endpoint": "Foo.Call", "region": "us-east1"}
+}`
+
+func TestParse(t *testing.T) {
+	probes, err := Parse([]byte(testProbes))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(probes) != 3 {
+		t.Fatalf("Expected 3 probes, got %v", len(probes))
+	}
+	for _, p := range probes {
+		switch p.Name {
+		case "call":
+			if p.Interval != time.Minute || p.Latency != 50*time.Millisecond || p.Status != 200 || string(p.Request) != `{"name": "John"}` {
+				t.Errorf("Unexpected probe %+v", p)
+			}
+		case "missing":
+			if p.Interval != DefaultInterval || p.Timeout != DefaultTimeout || p.Status != 404 || string(p.Request) != "{}" {
+				t.Errorf("Unexpected probe %+v", p)
+			}
+		}
+	}
+
+	for _, s := range []string{`{"x": {"service": "foo"}}`, `{"x": {"service": "foo", "endpoint": "Foo.Call", "interval": "-1s"}}`} {
+		if _, err := Parse([]byte(s)); err == nil {
+			t.Errorf("Expected an error parsing %v", s)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	tc := &testClient{
+		Client: mucp.NewClient(),
+		errs:   map[string]error{"Foo.Missing": errors.NotFound("foo", "not found")},
+	}
+	r := NewRunner(Client(tc), Region("europe-west1"), Topic(""), WithSource(func() []byte { return []byte(testProbes) }))
+
+	r.load()
+	probes := r.due(time.Now())
+	if len(probes) != 2 {
+		t.Fatalf("Expected the probe for another region to be skipped, got %v probes", len(probes))
+	}
+	for _, p := range probes {
+		if res := r.Run(p); !res.Passed() {
+			t.Errorf("Expected probe %v to pass: %v", p.Name, res.Error)
+		}
+	}
+
+	// probes aren't run again until the previous run completes and their interval has passed
+	if probes := r.due(time.Now()); len(probes) != 0 {
+		t.Errorf("Expected no probes to be due, got %v", len(probes))
+	}
+
+	// slow calls fail the probe and are reported by the check
+	tc.delay = 100 * time.Millisecond
+	res := r.Run(probes[0])
+	if res.Passed() {
+		t.Fatalf("Expected the slow probe to fail")
+	}
+	r.record(probes[0], res)
+	if err := debug.Checks()[checkName(probes[0].Name)](context.TODO()); err == nil {
+		t.Errorf("Expected the check of the failed probe to error")
+	}
+	if s := r.Stats()[probes[0].Name]; s.Runs != 1 || s.Failures != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+	if m := r.Metrics(); m[probes[0].Name+".failures"] != 1 || m[probes[0].Name+".passed"] != 0 {
+		t.Errorf("Unexpected metrics %v", m)
+	}
+}
@@ -0,0 +1,289 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	muclient "github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// tick is how often the runner checks which probes are due
+var tick = time.Second
+
+// Stats of a probe
+type Stats struct {
+	// Runs and failures of the probe since the runner started
+	Runs     uint64
+	Failures uint64
+	// Last result of the probe
+	Last Result
+}
+
+// Runner runs the probes read from its source on their intervals
+type Runner struct {
+	opts Options
+
+	sync.RWMutex
+	// raw is the encoding the probes were last parsed from
+	raw    []byte
+	probes map[string]Probe
+	stats  map[string]*Stats
+	next   map[string]time.Time
+	// running are the probes being run
+	running map[string]bool
+
+	exit chan bool
+	once sync.Once
+}
+
+// NewRunner returns a runner of the probes read from the config
+func NewRunner(opts ...Option) *Runner {
+	options := Options{
+		Source: ConfigSource,
+		Topic:  DefaultTopic,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &Runner{
+		opts:    options,
+		probes:  make(map[string]Probe),
+		stats:   make(map[string]*Stats),
+		next:    make(map[string]time.Time),
+		running: make(map[string]bool),
+		exit:    make(chan bool),
+	}
+}
+
+// Start running the probes
+func (r *Runner) Start() {
+	debug.RegisterMetrics("probe", r.Metrics)
+
+	go func() {
+		t := time.NewTicker(tick)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-r.exit:
+				return
+			case now := <-t.C:
+				r.load()
+				for _, p := range r.due(now) {
+					go r.record(p, r.Run(p))
+				}
+			}
+		}
+	}()
+}
+
+// Stop running the probes
+func (r *Runner) Stop() {
+	r.once.Do(func() {
+		close(r.exit)
+		debug.DeregisterMetrics("probe")
+	})
+}
+
+// Stats returns the stats of the probes run, keyed by probe
+func (r *Runner) Stats() map[string]Stats {
+	r.RLock()
+	defer r.RUnlock()
+
+	stats := make(map[string]Stats, len(r.stats))
+	for name, s := range r.stats {
+		stats[name] = *s
+	}
+	return stats
+}
+
+// Metrics returns the stats of the probes as metrics, registered as debug metrics whilst the
+// runner is started. Each probe has its runs and failures, whether its last run passed and
+// the latency of its last run in milliseconds.
+func (r *Runner) Metrics() map[string]float64 {
+	values := make(map[string]float64)
+	for name, s := range r.Stats() {
+		values[name+".runs"] = float64(s.Runs)
+		values[name+".failures"] = float64(s.Failures)
+		values[name+".latency_ms"] = float64(s.Last.Latency) / float64(time.Millisecond)
+
+		var passed float64
+		if s.Last.Passed() {
+			passed = 1
+		}
+		values[name+".passed"] = passed
+	}
+	return values
+}
+
+// Run the probe once and return its result
+func (r *Runner) Run(p Probe) Result {
+	c := r.opts.Client
+	if c == nil {
+		c = muclient.DefaultClient
+	}
+
+	res := Result{
+		Probe:     p.Name,
+		Service:   p.Service,
+		Endpoint:  p.Endpoint,
+		Region:    r.opts.Region,
+		Timestamp: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	req := c.NewRequest(p.Service, p.Endpoint, &p.Request, client.WithContentType("application/json"))
+	var rsp json.RawMessage
+
+	// a single attempt so the latency and status are those users would see
+	err := c.Call(ctx, req, &rsp, client.WithRequestTimeout(p.Timeout), client.WithRetries(0))
+	res.Latency = time.Since(res.Timestamp)
+
+	res.Status = 200
+	if err != nil {
+		res.Status = 500
+		if verr := errors.Parse(err); verr != nil && verr.Code > 0 {
+			res.Status = int(verr.Code)
+		}
+	}
+
+	switch {
+	case res.Status != p.Status && err != nil:
+		res.Error = fmt.Sprintf("expected status %v, got %v: %v", p.Status, res.Status, err)
+	case res.Status != p.Status:
+		res.Error = fmt.Sprintf("expected status %v, got %v", p.Status, res.Status)
+	case p.Latency > 0 && res.Latency > p.Latency:
+		res.Error = fmt.Sprintf("expected a latency below %v, got %v", p.Latency, res.Latency)
+	}
+	return res
+}
+
+// load the probes from the source if they've changed, keeping the probes loaded last if they
+// can't be parsed
+func (r *Runner) load() {
+	raw := r.opts.Source()
+
+	r.RLock()
+	same := bytes.Equal(raw, r.raw)
+	r.RUnlock()
+	if same {
+		return
+	}
+
+	var probes []Probe
+	if len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+		var err error
+		if probes, err = Parse(raw); err != nil {
+			logger.Warnf("Error parsing probes: %v", err)
+			return
+		}
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.raw = raw
+
+	loaded := make(map[string]Probe, len(probes))
+	for _, p := range probes {
+		if len(p.Region) > 0 && p.Region != r.opts.Region {
+			continue
+		}
+		loaded[p.Name] = p
+	}
+
+	// stop reporting the probes which were removed
+	for name := range r.probes {
+		if _, ok := loaded[name]; ok {
+			continue
+		}
+		debug.DeregisterCheck(checkName(name))
+		delete(r.stats, name)
+		delete(r.next, name)
+	}
+	r.probes = loaded
+}
+
+// due returns the probes due to be run, which aren't already running
+func (r *Runner) due(now time.Time) []Probe {
+	r.Lock()
+	defer r.Unlock()
+
+	var probes []Probe
+	for name, p := range r.probes {
+		if r.running[name] || now.Before(r.next[name]) {
+			continue
+		}
+		r.running[name] = true
+		r.next[name] = now.Add(p.Interval)
+		probes = append(probes, p)
+	}
+
+	sort.Slice(probes, func(i, j int) bool { return probes[i].Name < probes[j].Name })
+	return probes
+}
+
+// record the result of the probe, reporting it as a check and publishing it if the probe failed
+// or recovered
+func (r *Runner) record(p Probe, res Result) {
+	r.Lock()
+	delete(r.running, p.Name)
+	if _, ok := r.probes[p.Name]; !ok {
+		// the probe was removed while it was running
+		r.Unlock()
+		return
+	}
+
+	s, ok := r.stats[p.Name]
+	if !ok {
+		s = &Stats{}
+		r.stats[p.Name] = s
+	}
+	recovered := ok && !s.Last.Passed() && res.Passed()
+	s.Runs++
+	if !res.Passed() {
+		s.Failures++
+	}
+	s.Last = res
+	r.Unlock()
+
+	debug.RegisterCheck(checkName(p.Name), func(ctx context.Context) error {
+		if res.Passed() {
+			return nil
+		}
+		return fmt.Errorf("%v", res.Error)
+	})
+
+	if res.Passed() && !recovered {
+		logger.Debugf("Probe %v passed in %v", p.Name, res.Latency)
+		return
+	}
+	if recovered {
+		logger.Infof("Probe %v recovered", p.Name)
+	} else {
+		logger.Warnf("Probe %v failed: %v", p.Name, res.Error)
+	}
+
+	if len(r.opts.Topic) == 0 {
+		return
+	}
+	if err := events.Publish(r.opts.Topic, res); err != nil {
+		logger.Debugf("Error publishing result of probe %v: %v", p.Name, err)
+	}
+}
+
+func checkName(probe string) string {
+	return "probe." + probe
+}
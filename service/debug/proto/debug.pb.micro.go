@@ -46,6 +46,9 @@ type DebugService interface {
 	Health(ctx context.Context, in *HealthRequest, opts ...client.CallOption) (*HealthResponse, error)
 	Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error)
 	Trace(ctx context.Context, in *TraceRequest, opts ...client.CallOption) (*TraceResponse, error)
+	DependencyHealth(ctx context.Context, in *DependencyHealthRequest, opts ...client.CallOption) (*DependencyHealthResponse, error)
+	Latency(ctx context.Context, in *LatencyRequest, opts ...client.CallOption) (*LatencyResponse, error)
+	Recycle(ctx context.Context, in *RecycleRequest, opts ...client.CallOption) (*RecycleResponse, error)
 }
 
 type debugService struct {
@@ -100,6 +103,36 @@ func (c *debugService) Trace(ctx context.Context, in *TraceRequest, opts ...clie
 	return out, nil
 }
 
+func (c *debugService) DependencyHealth(ctx context.Context, in *DependencyHealthRequest, opts ...client.CallOption) (*DependencyHealthResponse, error) {
+	req := c.c.NewRequest(c.name, "Debug.DependencyHealth", in)
+	out := new(DependencyHealthResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugService) Latency(ctx context.Context, in *LatencyRequest, opts ...client.CallOption) (*LatencyResponse, error) {
+	req := c.c.NewRequest(c.name, "Debug.Latency", in)
+	out := new(LatencyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugService) Recycle(ctx context.Context, in *RecycleRequest, opts ...client.CallOption) (*RecycleResponse, error) {
+	req := c.c.NewRequest(c.name, "Debug.Recycle", in)
+	out := new(RecycleResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Debug service
 
 type DebugHandler interface {
@@ -107,6 +140,9 @@ type DebugHandler interface {
 	Health(context.Context, *HealthRequest, *HealthResponse) error
 	Stats(context.Context, *StatsRequest, *StatsResponse) error
 	Trace(context.Context, *TraceRequest, *TraceResponse) error
+	DependencyHealth(context.Context, *DependencyHealthRequest, *DependencyHealthResponse) error
+	Latency(context.Context, *LatencyRequest, *LatencyResponse) error
+	Recycle(context.Context, *RecycleRequest, *RecycleResponse) error
 }
 
 func RegisterDebugHandler(s server.Server, hdlr DebugHandler, opts ...server.HandlerOption) error {
@@ -115,6 +151,9 @@ func RegisterDebugHandler(s server.Server, hdlr DebugHandler, opts ...server.Han
 		Health(ctx context.Context, in *HealthRequest, out *HealthResponse) error
 		Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error
 		Trace(ctx context.Context, in *TraceRequest, out *TraceResponse) error
+		DependencyHealth(ctx context.Context, in *DependencyHealthRequest, out *DependencyHealthResponse) error
+		Latency(ctx context.Context, in *LatencyRequest, out *LatencyResponse) error
+		Recycle(ctx context.Context, in *RecycleRequest, out *RecycleResponse) error
 	}
 	type Debug struct {
 		debug
@@ -142,3 +181,15 @@ func (h *debugHandler) Stats(ctx context.Context, in *StatsRequest, out *StatsRe
 func (h *debugHandler) Trace(ctx context.Context, in *TraceRequest, out *TraceResponse) error {
 	return h.DebugHandler.Trace(ctx, in, out)
 }
+
+func (h *debugHandler) DependencyHealth(ctx context.Context, in *DependencyHealthRequest, out *DependencyHealthResponse) error {
+	return h.DebugHandler.DependencyHealth(ctx, in, out)
+}
+
+func (h *debugHandler) Latency(ctx context.Context, in *LatencyRequest, out *LatencyResponse) error {
+	return h.DebugHandler.Latency(ctx, in, out)
+}
+
+func (h *debugHandler) Recycle(ctx context.Context, in *RecycleRequest, out *RecycleResponse) error {
+	return h.DebugHandler.Recycle(ctx, in, out)
+}
@@ -0,0 +1,17 @@
+package debug
+
+// Attributes set in the metadata of the spans of the requests served
+const (
+	// SpanService is the name of the service serving the request
+	SpanService = "service"
+	// SpanEndpoint is the endpoint called, e.g. Foo.Bar
+	SpanEndpoint = "endpoint"
+	// SpanPeer is the name of the service which made the call, if it was made by one
+	SpanPeer = "peer"
+	// SpanAccount is the ID of the account which made the call, if it was authenticated
+	SpanAccount = "account"
+	// SpanStatus is the status code the request completed with, 200 if it succeeded
+	SpanStatus = "status"
+	// SpanError is the error the request failed with
+	SpanError = "error"
+)
@@ -1,6 +1,10 @@
 package errors
 
-import "github.com/micro/go-micro/v3/errors"
+import (
+	"fmt"
+
+	"github.com/micro/go-micro/v3/errors"
+)
 
 var (
 	BadRequest          = errors.BadRequest
@@ -19,6 +23,27 @@ var (
 	Equal = errors.Equal
 )
 
+// TooManyRequests generates a 429 error, returned when a quota or rate limit is exceeded
+func TooManyRequests(id, format string, a ...interface{}) error {
+	return errors.New(id, fmt.Sprintf(format, a...), 429)
+}
+
+// RequestEntityTooLarge generates a 413 error, returned when a message exceeds a size limit
+func RequestEntityTooLarge(id, format string, a ...interface{}) error {
+	return errors.New(id, fmt.Sprintf(format, a...), 413)
+}
+
+// Gone generates a 410 error, returned when a resource existed but has recently been removed
+func Gone(id, format string, a ...interface{}) error {
+	return errors.New(id, fmt.Sprintf(format, a...), 410)
+}
+
+// PreconditionFailed generates a 412 error, returned when a call is made with expectations of
+// the service which don't hold
+func PreconditionFailed(id, format string, a ...interface{}) error {
+	return errors.New(id, fmt.Sprintf(format, a...), 412)
+}
+
 // Parse an error into a go-micro error
 func Parse(err error) *errors.Error {
 	verr, _ := err.(*errors.Error)
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v3/debug/trace"
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/service/events/util"
+)
+
+// Metadata keys the causality of events is recorded with. The producer, sequence and
+// correlation ID are set on every event published. Events published using PublishContext,
+// WithContext or WithCause join the causal chain of the request or event which caused them,
+// others start a new chain.
+const (
+	SequenceKey    = util.SequenceKey
+	ProducerKey    = util.ProducerKey
+	CorrelationKey = util.CorrelationKey
+	CausationKey   = util.CausationKey
+)
+
+// WithContext propagates the correlation and causation IDs from the request being handled to
+// the event. If the request wasn't caused by an event, the trace of the request is used and
+// if it isn't being traced, the event starts a new causal chain. WithMetadata replaces the
+// metadata, so pass it before this option.
+func WithContext(ctx context.Context) events.PublishOption {
+	correlation, ok := metadata.Get(ctx, CorrelationKey)
+	causation, _ := metadata.Get(ctx, CausationKey)
+	if !ok {
+		traceID, spanID, _ := trace.FromContext(ctx)
+		correlation = traceID
+		if len(causation) == 0 {
+			causation = spanID
+		}
+	}
+	if len(correlation) == 0 {
+		correlation = uuid.New().String()
+	}
+
+	return func(o *events.PublishOptions) {
+		setMetadata(o, CorrelationKey, correlation)
+		setMetadata(o, CausationKey, causation)
+	}
+}
+
+// WithCause marks the event as caused by another, e.g. when publishing an event in response
+// to one consumed. WithMetadata replaces the metadata, so pass it before this option.
+func WithCause(ev *events.Event) events.PublishOption {
+	correlation := ev.Metadata[CorrelationKey]
+	if len(correlation) == 0 {
+		correlation = ev.ID
+	}
+
+	return func(o *events.PublishOptions) {
+		setMetadata(o, CorrelationKey, correlation)
+		setMetadata(o, CausationKey, ev.ID)
+	}
+}
+
+// NewContext returns a context for processing the event. The correlation and causation IDs
+// are sent with the requests made using it, so events published by the services called
+// using WithContext are part of the event's causal chain. The locale the event was published
+// with is also sent.
+func NewContext(ctx context.Context, ev *events.Event) context.Context {
+	correlation := ev.Metadata[CorrelationKey]
+	if len(correlation) == 0 {
+		correlation = ev.ID
+	}
+
+	md := localeMetadata(ev.Metadata)
+	md[CorrelationKey] = correlation
+	md[CausationKey] = ev.ID
+	return metadata.MergeContext(ctx, md, true)
+}
+
+// Chain returns the events with the correlation ID published to the topics, in causal order.
+// Events are ordered by timestamp and the events each producer published at the same time by
+// their sequence.
+func Chain(correlation string, topics ...string) ([]*events.Event, error) {
+	var chain []*events.Event
+	for _, t := range topics {
+		evs, err := Read(t)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ev := range evs {
+			if ev.Metadata[CorrelationKey] == correlation || ev.ID == correlation {
+				chain = append(chain, ev)
+			}
+		}
+	}
+
+	sort.Slice(chain, func(i, j int) bool {
+		a, b := chain[i], chain[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		if a.Metadata[ProducerKey] != b.Metadata[ProducerKey] {
+			return a.Metadata[ProducerKey] < b.Metadata[ProducerKey]
+		}
+		return sequence(a) < sequence(b)
+	})
+
+	return chain, nil
+}
+
+// sequence returns the sequence the event was published with by its producer
+func sequence(ev *events.Event) uint64 {
+	seq, _ := strconv.ParseUint(ev.Metadata[SequenceKey], 10, 64)
+	return seq
+}
+
+func setMetadata(o *events.PublishOptions, k, v string) {
+	if len(v) == 0 {
+		return
+	}
+
+	// copy the metadata so the caller's map isn't modified
+	md := make(map[string]string, len(o.Metadata)+1)
+	for mk, mv := range o.Metadata {
+		md[mk] = mv
+	}
+	md[k] = v
+	o.Metadata = md
+}
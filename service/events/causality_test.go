@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/metadata"
+)
+
+type testStore map[string][]*events.Event
+
+func (s testStore) Read(topic string, opts ...events.ReadOption) ([]*events.Event, error) {
+	return s[topic], nil
+}
+
+func (s testStore) Write(ev *events.Event, opts ...events.WriteOption) error {
+	s[ev.Topic] = append(s[ev.Topic], ev)
+	return nil
+}
+
+func TestCausality(t *testing.T) {
+	root := &events.Event{ID: "1", Topic: "orders", Metadata: map[string]string{}}
+
+	// events published in response to an event are part of its chain
+	var opts events.PublishOptions
+	events.WithMetadata(map[string]string{"foo": "bar"})(&opts)
+	WithCause(root)(&opts)
+	if opts.Metadata[CorrelationKey] != "1" || opts.Metadata[CausationKey] != "1" || opts.Metadata["foo"] != "bar" {
+		t.Fatalf("Unexpected metadata %v", opts.Metadata)
+	}
+
+	// and so are the events published by requests made while processing it
+	ctx := NewContext(context.Background(), &events.Event{ID: "2", Metadata: opts.Metadata})
+	md, _ := metadata.FromContext(ctx)
+	opts = events.PublishOptions{}
+	WithContext(metadata.NewContext(context.Background(), md))(&opts)
+	if opts.Metadata[CorrelationKey] != "1" || opts.Metadata[CausationKey] != "2" {
+		t.Fatalf("Unexpected metadata %v", opts.Metadata)
+	}
+
+	// a request which wasn't caused by an event starts a new chain
+	opts = events.PublishOptions{}
+	WithContext(context.Background())(&opts)
+	if c := opts.Metadata[CorrelationKey]; len(c) == 0 || c == "1" {
+		t.Fatalf("Expected a new correlation ID, got %v", c)
+	}
+}
+
+// testStream records the options of the events published
+type testStream struct {
+	events.Stream
+	published []events.PublishOptions
+}
+
+func (s *testStream) Publish(topic string, msg interface{}, opts ...events.PublishOption) error {
+	var options events.PublishOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	s.published = append(s.published, options)
+	return nil
+}
+
+func TestPublishContext(t *testing.T) {
+	stream := &testStream{}
+	defer func(s events.Stream) { DefaultStream = s }(DefaultStream)
+	DefaultStream = stream
+
+	// events published whilst handling an event are part of its chain
+	ctx := NewContext(context.Background(), &events.Event{ID: "2", Metadata: map[string]string{CorrelationKey: "1"}})
+	if err := PublishContext(ctx, "payments", nil, events.WithMetadata(map[string]string{"foo": "bar"})); err != nil {
+		t.Fatal(err)
+	}
+	md := stream.published[0].Metadata
+	if md[CorrelationKey] != "1" || md[CausationKey] != "2" || md["foo"] != "bar" {
+		t.Errorf("Unexpected metadata %v", md)
+	}
+}
+
+func TestChain(t *testing.T) {
+	now := time.Now()
+	event := func(id, topic string, ts time.Time, producer, seq string) *events.Event {
+		return &events.Event{ID: id, Topic: topic, Timestamp: ts, Metadata: map[string]string{
+			CorrelationKey: "1", ProducerKey: producer, SequenceKey: seq,
+		}}
+	}
+
+	store := testStore{}
+	store.Write(&events.Event{ID: "1", Topic: "orders", Timestamp: now, Metadata: map[string]string{}})
+	store.Write(event("4", "orders", now.Add(time.Second), "a", "2"))
+	store.Write(event("3", "payments", now.Add(time.Second), "a", "10"))
+	store.Write(event("2", "payments", now, "b", "1"))
+	store.Write(&events.Event{ID: "5", Topic: "payments", Timestamp: now, Metadata: map[string]string{}})
+
+	defer func(s events.Store) { DefaultStore = s }(DefaultStore)
+	DefaultStore = store
+
+	chain, err := Chain("1", "orders", "payments")
+	if err != nil {
+		t.Fatalf("Unexpected error reading the chain: %v", err)
+	}
+	var ids []string
+	for _, ev := range chain {
+		ids = append(ids, ev.ID)
+	}
+	if len(ids) != 4 || ids[0] != "1" || ids[1] != "2" || ids[2] != "4" || ids[3] != "3" {
+		t.Errorf("Unexpected chain %v", ids)
+	}
+}
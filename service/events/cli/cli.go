@@ -0,0 +1,122 @@
+// Package cli implements the `micro events` subcommands
+// for example:
+//   micro events history
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/service/events"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "events",
+		Usage:  "Commands for inspecting events",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "history",
+				Usage: "Show what a consumer group did around an event",
+				Description: `History shows the events a consumer group processed either side of an event, with how
+long each took and the error returned if it failed. The consumers must record their history using
+events.RecordHistory. The event is given by its sequence in the topic or its ID, e.g.
+
+	micro events history --group=billing --topic=orders --around=1042
+	micro events history --group=billing --topic=orders --around=6f1c2a3e-...`,
+				Action: history,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "group",
+						Usage:    "consumer group",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "topic",
+						Usage:    "topic consumed",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "around",
+						Usage: "sequence or ID of the event, the latest events are shown if it's not set",
+					},
+					&cli.UintFlag{
+						Name:  "count",
+						Usage: "number of events to show either side of the event",
+						Value: 10,
+					},
+				},
+			},
+		},
+	})
+}
+
+func history(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	group, topic := ctx.String("group"), ctx.String("topic")
+	seq, err := locate(group, topic, ctx.String("around"), gostore.ReadFrom(ns, events.DefaultHistoryTable))
+	if err != nil {
+		return err
+	}
+
+	entries, err := events.History(group, topic, seq, uint64(ctx.Uint("count")), gostore.ReadFrom(ns, events.DefaultHistoryTable))
+	if err != nil {
+		return err
+	}
+	report(os.Stdout, entries, seq)
+	return nil
+}
+
+// locate returns the sequence of the event around, which is its sequence or ID. If around isn't
+// given, the latest sequence in the history is used.
+func locate(group, topic, around string, opts ...gostore.ReadOption) (uint64, error) {
+	if seq, err := strconv.ParseUint(around, 10, 64); err == nil {
+		return seq, nil
+	}
+
+	if len(around) > 0 {
+		e, err := events.FindHistory(group, topic, around, opts...)
+		if err == gostore.ErrNotFound {
+			return 0, fmt.Errorf("Event %v not found in the history", around)
+		} else if err != nil {
+			return 0, err
+		}
+		return e.Sequence, nil
+	}
+
+	seq, err := events.LatestSequence(group, topic, opts...)
+	if err == gostore.ErrNotFound {
+		return 0, fmt.Errorf("No history recorded for group %v on topic %v", group, topic)
+	}
+	return seq, err
+}
+
+// report writes the entries, marking the event at the sequence
+func report(out io.Writer, entries []*events.HistoryEntry, seq uint64) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tCONSUMER\tSEQUENCE\tID\tSTARTED\tDURATION\tRESULT\tERROR")
+	for _, e := range entries {
+		mark := ""
+		if e.Sequence == seq {
+			mark = ">"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", mark, e.Consumer, e.Sequence, e.ID,
+			e.Started.Format(time.RFC3339Nano), e.Duration, e.Result, e.Error)
+	}
+	w.Flush()
+}
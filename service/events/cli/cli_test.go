@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/events"
+	mustore "github.com/micro/micro/v3/service/store"
+)
+
+func TestLocate(t *testing.T) {
+	mustore.DefaultStore = memory.NewStore()
+
+	b, _ := json.Marshal([]*events.HistoryEntry{
+		{Consumer: "a", Sequence: 41, ID: "x"},
+		{Consumer: "a", Sequence: 42, ID: "y"},
+	})
+	rec := &store.Record{Key: "billing/orders/00000000000000000000/a/00000000000000000000", Value: b}
+	if err := mustore.Write(rec, store.WriteTo("", events.DefaultHistoryTable)); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		Name     string
+		Topic    string
+		Around   string
+		Sequence uint64
+		Err      bool
+	}{
+		{Name: "Sequence", Around: "7", Sequence: 7},
+		{Name: "ID", Around: "x", Sequence: 41},
+		{Name: "Latest", Sequence: 42},
+		{Name: "UnknownID", Around: "missing", Err: true},
+		{Name: "UnknownTopic", Topic: "other", Err: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			topic := "orders"
+			if len(tc.Topic) > 0 {
+				topic = tc.Topic
+			}
+			seq, err := locate("billing", topic, tc.Around)
+			if tc.Err {
+				if err == nil {
+					t.Errorf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seq != tc.Sequence {
+				t.Errorf("Expected sequence %v, got %v", tc.Sequence, seq)
+			}
+		})
+	}
+}
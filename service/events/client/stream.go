@@ -2,8 +2,11 @@ package client
 
 import (
 	"encoding/json"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	goclient "github.com/micro/go-micro/v3/client"
 	"github.com/micro/go-micro/v3/events"
 	"github.com/micro/micro/v3/service/client"
@@ -12,6 +15,13 @@ import (
 	"github.com/micro/micro/v3/service/events/util"
 )
 
+var (
+	// producer identifies the events published by this process
+	producer = uuid.New().String()
+	// sequence of the last event published by this process
+	sequence uint64
+)
+
 // NewStream returns an initialized stream service
 func NewStream() events.Stream {
 	return new(stream)
@@ -42,11 +52,23 @@ func (s *stream) Publish(topic string, msg interface{}, opts ...events.PublishOp
 		payload = p
 	}
 
+	// stamp the event with its producer and sequence so consumers can order the events
+	// published by each producer. Events which aren't part of a causal chain start one.
+	md := make(map[string]string, len(options.Metadata)+3)
+	for k, v := range options.Metadata {
+		md[k] = v
+	}
+	md[util.ProducerKey] = producer
+	md[util.SequenceKey] = strconv.FormatUint(atomic.AddUint64(&sequence, 1), 10)
+	if len(md[util.CorrelationKey]) == 0 {
+		md[util.CorrelationKey] = uuid.New().String()
+	}
+
 	// execute the RPC
 	_, err := s.client().Publish(context.DefaultContext, &pb.PublishRequest{
 		Topic:     topic,
 		Payload:   payload,
-		Metadata:  options.Metadata,
+		Metadata:  md,
 		Timestamp: options.Timestamp.Unix(),
 	}, goclient.WithAuthToken())
 
@@ -54,6 +76,12 @@ func (s *stream) Publish(topic string, msg interface{}, opts ...events.PublishOp
 }
 
 func (s *stream) Subscribe(topic string, opts ...events.SubscribeOption) (<-chan events.Event, error) {
+	return s.SubscribeContract("", topic, 0, opts...)
+}
+
+// SubscribeContract subscribes to a topic shared by another namespace, at a version of its
+// contract or the latest if the version is zero
+func (s *stream) SubscribeContract(namespace, topic string, version int64, opts ...events.SubscribeOption) (<-chan events.Event, error) {
 	// parse options
 	var options events.SubscribeOptions
 	for _, o := range opts {
@@ -62,9 +90,11 @@ func (s *stream) Subscribe(topic string, opts ...events.SubscribeOption) (<-chan
 
 	// start the stream
 	stream, err := s.client().Subscribe(context.DefaultContext, &pb.SubscribeRequest{
-		Topic:       topic,
-		Queue:       options.Queue,
-		StartAtTime: options.StartAtTime.Unix(),
+		Topic:           topic,
+		Queue:           options.Queue,
+		StartAtTime:     options.StartAtTime.Unix(),
+		Namespace:       namespace,
+		ContractVersion: version,
 	}, goclient.WithAuthToken())
 	if err != nil {
 		return nil, err
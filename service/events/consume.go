@@ -0,0 +1,192 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultMaxConcurrent is the number of events a consumer handles at once, so events are
+	// handled in order unless MaxConcurrent is set
+	DefaultMaxConcurrent = 1
+	// DefaultMaxRedeliveries is the number of times an event is redelivered to the handler after
+	// it returns an error
+	DefaultMaxRedeliveries = 3
+	// DefaultRedeliveryDelay is how long an event is redelivered after, doubling with each
+	// redelivery
+	DefaultRedeliveryDelay = time.Second
+)
+
+// Handler processes an event consumed from a topic. The context is created using NewContext,
+// so events published with PublishContext using it are part of the event's causal chain.
+type Handler func(ctx context.Context, ev *events.Event) error
+
+// ConsumeOptions contains the options which can be provided when consuming a topic
+type ConsumeOptions struct {
+	// MaxConcurrent is the number of events handled at once, zero for no limit
+	MaxConcurrent int
+	// Rate is the number of events handled per second, zero for no limit
+	Rate float64
+	// MaxRedeliveries is the number of times an event is redelivered after the handler returns
+	// an error, zero to never redeliver
+	MaxRedeliveries int
+	// RedeliveryDelay is how long the first redelivery is after, DefaultRedeliveryDelay if zero
+	RedeliveryDelay time.Duration
+	// SubscribeOptions are passed to the stream when subscribing to the topic
+	SubscribeOptions []events.SubscribeOption
+	// HistoryGroup is the consumer group the processing history is recorded for, blank if
+	// it's not recorded
+	HistoryGroup string
+}
+
+// ConsumeOption sets attributes on ConsumeOptions
+type ConsumeOption func(o *ConsumeOptions)
+
+// MaxConcurrent sets the number of events handled at once, zero for no limit
+func MaxConcurrent(n int) ConsumeOption {
+	return func(o *ConsumeOptions) {
+		o.MaxConcurrent = n
+	}
+}
+
+// RateLimit sets the number of events handled per second, zero for no limit
+func RateLimit(perSecond float64) ConsumeOption {
+	return func(o *ConsumeOptions) {
+		o.Rate = perSecond
+	}
+}
+
+// Redeliver sets the number of times an event is redelivered after the handler returns an error
+// and the delay before the first redelivery, which doubles with each one
+func Redeliver(n int, delay time.Duration) ConsumeOption {
+	return func(o *ConsumeOptions) {
+		o.MaxRedeliveries = n
+		o.RedeliveryDelay = delay
+	}
+}
+
+// SubscribeWith sets the options the topic is subscribed to with, e.g. WithQueue
+func SubscribeWith(opts ...events.SubscribeOption) ConsumeOption {
+	return func(o *ConsumeOptions) {
+		o.SubscribeOptions = append(o.SubscribeOptions, opts...)
+	}
+}
+
+// Consume the events published to the topic with the handler until the subscription is closed.
+// Events aren't received from the stream while the consumer is at its limits, so a burst on
+// the topic is delivered at the rate the consumer allows rather than all at once. The stream
+// acknowledges events once they're received, so events the handler returns an error for are
+// negatively acknowledged by the consumer redelivering them.
+func Consume(topic string, h Handler, opts ...ConsumeOption) error {
+	options := ConsumeOptions{
+		MaxConcurrent:   DefaultMaxConcurrent,
+		MaxRedeliveries: DefaultMaxRedeliveries,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	evChan, err := Subscribe(topic, options.SubscribeOptions...)
+	if err != nil {
+		return err
+	}
+
+	c := newConsumer(h, options)
+	if len(options.HistoryGroup) > 0 {
+		c.history = newHistory(options.HistoryGroup, topic)
+	}
+	go c.run(evChan)
+	return nil
+}
+
+type consumer struct {
+	handler Handler
+	// sem limits the number of events handled at once, nil for no limit
+	sem chan struct{}
+	// interval between the events being handled, zero for no limit
+	interval time.Duration
+	// redeliveries of an event the handler fails, and the delay before the first
+	redeliveries int
+	delay        time.Duration
+	// history the outcomes are recorded in, nil if they're not recorded
+	history *history
+}
+
+func newConsumer(h Handler, options ConsumeOptions) *consumer {
+	c := &consumer{handler: h, redeliveries: options.MaxRedeliveries, delay: options.RedeliveryDelay}
+	if c.delay <= 0 {
+		c.delay = DefaultRedeliveryDelay
+	}
+	if options.MaxConcurrent > 0 {
+		c.sem = make(chan struct{}, options.MaxConcurrent)
+	}
+	if options.Rate > 0 {
+		c.interval = time.Duration(float64(time.Second) / options.Rate)
+	}
+	return c
+}
+
+// run is the delivery loop, which handles the events until the channel is closed
+func (c *consumer) run(evChan <-chan events.Event) {
+	var next time.Time
+
+	for ev := range evChan {
+		// wait until the event is allowed by the rate limit
+		if c.interval > 0 {
+			if d := time.Until(next); d > 0 {
+				time.Sleep(d)
+			}
+			next = time.Now().Add(c.interval)
+		}
+
+		// wait for a handler to be free
+		if c.sem != nil {
+			c.sem <- struct{}{}
+		}
+
+		go func(ev events.Event) {
+			defer func() {
+				if c.sem != nil {
+					<-c.sem
+				}
+			}()
+
+			c.handle(&ev)
+		}(ev)
+	}
+
+	// wait for the events being handled to finish
+	if c.sem != nil {
+		for i := 0; i < cap(c.sem); i++ {
+			c.sem <- struct{}{}
+		}
+	}
+	if c.history != nil {
+		c.history.close()
+	}
+}
+
+// handle the event, redelivering it to the handler while it returns an error. The event holds
+// its handler until it's handled or given up on, so redeliveries count towards MaxConcurrent.
+func (c *consumer) handle(ev *events.Event) {
+	for attempt := 0; ; attempt++ {
+		started := time.Now()
+		err := c.handler(NewContext(context.Background(), ev), ev)
+		if c.history != nil {
+			c.history.record(ev, started, err)
+		}
+		if err == nil {
+			return
+		}
+
+		if attempt >= c.redeliveries {
+			logger.Errorf("Error handling event %v on topic %v, giving up after %v attempts: %v", ev.ID, ev.Topic, attempt+1, err)
+			return
+		}
+		logger.Warnf("Error handling event %v on topic %v, redelivering it: %v", ev.ID, ev.Topic, err)
+		time.Sleep(c.delay << uint(attempt))
+	}
+}
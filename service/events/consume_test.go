@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/metadata"
+)
+
+func TestConsumer(t *testing.T) {
+	send := func(n int) <-chan events.Event {
+		evChan := make(chan events.Event, n)
+		for i := 0; i < n; i++ {
+			evChan <- events.Event{ID: strconv.Itoa(i), Topic: "orders"}
+		}
+		close(evChan)
+		return evChan
+	}
+
+	t.Run("MaxConcurrent", func(t *testing.T) {
+		var mtx sync.Mutex
+		var active, peak, handled int
+		h := func(ctx context.Context, ev *events.Event) error {
+			mtx.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mtx.Unlock()
+
+			time.Sleep(time.Millisecond * 5)
+
+			mtx.Lock()
+			active--
+			handled++
+			mtx.Unlock()
+			return nil
+		}
+
+		newConsumer(h, ConsumeOptions{MaxConcurrent: 2}).run(send(10))
+		if handled != 10 {
+			t.Errorf("Expected 10 events to be handled, got %v", handled)
+		}
+		if peak != 2 {
+			t.Errorf("Expected at most 2 events to be handled at once, got %v", peak)
+		}
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		var mtx sync.Mutex
+		var handled int
+		h := func(ctx context.Context, ev *events.Event) error {
+			mtx.Lock()
+			handled++
+			mtx.Unlock()
+			return nil
+		}
+
+		start := time.Now()
+		newConsumer(h, ConsumeOptions{MaxConcurrent: 1, Rate: 100}).run(send(5))
+		if d := time.Since(start); d < time.Millisecond*40 {
+			t.Errorf("Expected 5 events at 100/s to take at least 40ms, took %v", d)
+		}
+		if handled != 5 {
+			t.Errorf("Expected 5 events to be handled, got %v", handled)
+		}
+	})
+
+	t.Run("Redeliver", func(t *testing.T) {
+		var mtx sync.Mutex
+		calls := make(map[string]int)
+		h := func(ctx context.Context, ev *events.Event) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			calls[ev.ID]++
+			if ev.ID == "0" && calls[ev.ID] < 3 {
+				return errors.New("unavailable")
+			}
+			if ev.ID == "1" {
+				return errors.New("invalid")
+			}
+			return nil
+		}
+		newConsumer(h, ConsumeOptions{MaxConcurrent: 2, MaxRedeliveries: 2, RedeliveryDelay: time.Millisecond}).run(send(3))
+
+		// the first succeeds on its last redelivery, the second is given up on
+		if calls["0"] != 3 || calls["1"] != 3 || calls["2"] != 1 {
+			t.Errorf("Expected the failed events to be redelivered twice, got %v", calls)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		var correlation string
+		h := func(ctx context.Context, ev *events.Event) error {
+			correlation, _ = metadata.Get(ctx, CorrelationKey)
+			return nil
+		}
+		newConsumer(h, ConsumeOptions{MaxConcurrent: 1}).run(send(1))
+		if correlation != "0" {
+			t.Errorf("Expected the handler context to be correlated with the event, got %v", correlation)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"fmt"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/events/proto"
+)
+
+// Share the topic with other namespaces, publishing its registered schema as a version of its
+// contract. Events published to the topic must then match the schema, which can't change for
+// the version, so a new version is needed to change it.
+func Share(topic string, version int64, description string) (*pb.Contract, error) {
+	schema, ok := Schema(topic)
+	if !ok {
+		return nil, fmt.Errorf("no schema registered for topic %v", topic)
+	}
+
+	rsp, err := pb.NewContractsService("events", client.DefaultClient).Publish(context.DefaultContext, &pb.PublishContractRequest{
+		Topic:       topic,
+		Version:     version,
+		Schema:      schema,
+		Description: description,
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Contract, nil
+}
+
+// SubscribeShared subscribes to a topic another namespace shares, at a version of its contract
+// or the latest if the version is zero. The subscription is recorded so the namespace sharing the
+// topic can see who consumes it.
+func SubscribeShared(namespace, topic string, version int64, opts ...events.SubscribeOption) (<-chan events.Event, error) {
+	s, ok := DefaultStream.(interface {
+		SubscribeContract(string, string, int64, ...events.SubscribeOption) (<-chan events.Event, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("the events stream doesn't support shared topics")
+	}
+	return s.SubscribeContract(namespace, topic, version, opts...)
+}
+
+// Subscribers returns the namespaces subscribed to a topic shared by this namespace
+func Subscribers(topic string) ([]*pb.Subscriber, error) {
+	rsp, err := pb.NewContractsService("events", client.DefaultClient).Subscribers(context.DefaultContext, &pb.ListSubscribersRequest{
+		Topic: topic,
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Subscribers, nil
+}
@@ -1,6 +1,8 @@
 package events
 
 import (
+	"context"
+
 	"github.com/micro/go-micro/v3/events"
 	"github.com/micro/micro/v3/service/events/client"
 )
@@ -17,6 +19,12 @@ func Publish(topic string, msg interface{}, opts ...events.PublishOption) error
 	return DefaultStream.Publish(topic, msg, opts...)
 }
 
+// PublishContext publishes an event to a topic as part of the causal chain of the request or
+// event being handled with the context, see WithContext
+func PublishContext(ctx context.Context, topic string, msg interface{}, opts ...events.PublishOption) error {
+	return DefaultStream.Publish(topic, msg, append(opts, WithContext(ctx))...)
+}
+
 // Subscribe to events
 func Subscribe(topic string, opts ...events.SubscribeOption) (<-chan events.Event, error) {
 	return DefaultStream.Subscribe(topic, opts...)
@@ -0,0 +1,320 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/logger"
+	mustore "github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultHistoryTable is the table the processing history of consumers is recorded in
+	DefaultHistoryTable = "events_history"
+	// DefaultHistoryRetention is how long the processing history is kept for
+	DefaultHistoryRetention = time.Hour * 24
+	// DefaultHistoryFlushInterval is how often the entries recorded are written to the store
+	DefaultHistoryFlushInterval = time.Second
+	// DefaultHistoryBlockSize is the most entries written to the store in one record
+	DefaultHistoryBlockSize = 100
+	// DefaultSequenceTable is the table the events service records the latest sequence of each
+	// topic in, in the database of the namespace
+	DefaultSequenceTable = "events_sequences"
+	// DefaultHistoryBucketSize is the number of sequences the records of each bucket of the
+	// history cover, so the history around an event is read without reading all of it
+	DefaultHistoryBucketSize uint64 = 1000
+)
+
+// Result of processing an event
+type Result string
+
+const (
+	// ResultOK is the result of an event handled without error
+	ResultOK Result = "ok"
+	// ResultError is the result of an event whose handler returned an error
+	ResultError Result = "error"
+)
+
+// HistoryEntry records the processing of an event by a consumer
+type HistoryEntry struct {
+	// Consumer which processed the event, each instance of a consumer group has its own id
+	Consumer string `json:"consumer"`
+	// Sequence of the event in the topic, set by the events service when it's published. Zero
+	// if the event wasn't published through the events service.
+	Sequence uint64 `json:"sequence"`
+	// ID of the event
+	ID string `json:"id"`
+	// Started is when the handler was called
+	Started time.Time `json:"started"`
+	// Duration the handler took
+	Duration time.Duration `json:"duration"`
+	Result   Result        `json:"result"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// RecordHistory records the outcome of processing each event in the history of the consumer
+// group, so it can be inspected with `micro events history`
+func RecordHistory(group string) ConsumeOption {
+	return func(o *ConsumeOptions) {
+		o.HistoryGroup = group
+	}
+}
+
+// StreamSequence returns the sequence of the event in its topic, zero if it has none
+func StreamSequence(ev *events.Event) uint64 {
+	seq, _ := strconv.ParseUint(ev.Metadata[util.StreamSequenceKey], 10, 64)
+	return seq
+}
+
+// TopicSequence returns the sequence of the latest event published to the topic, zero if none
+// have been. The store options set the database it's read from.
+func TopicSequence(topic string, opts ...store.ReadOption) (uint64, error) {
+	opts = append([]store.ReadOption{store.ReadFrom("", DefaultSequenceTable)}, opts...)
+	recs, err := mustore.Read(topic, opts...)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(recs[0].Value), 10, 64)
+}
+
+// History reads the group's processing history of the topic for the events within n of the
+// sequence, ordered by sequence. Only the buckets of the history covering those sequences are
+// read. The store options set the database it's read from.
+func History(group, topic string, sequence, n uint64, opts ...store.ReadOption) ([]*HistoryEntry, error) {
+	from := uint64(0)
+	if sequence > n {
+		from = sequence - n
+	}
+	to := sequence + n
+
+	var entries []*HistoryEntry
+	for b := from / DefaultHistoryBucketSize; b <= to/DefaultHistoryBucketSize; b++ {
+		block, err := readHistory(historyBucket(group, topic, b), opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range block {
+			if e.Sequence >= from && e.Sequence <= to {
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	sortHistory(entries)
+	return entries, nil
+}
+
+// LatestSequence returns the highest sequence in the group's processing history of the topic,
+// reading only the latest bucket of the history
+func LatestSequence(group, topic string, opts ...store.ReadOption) (uint64, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	prefix := group + "/" + topic + "/"
+	keys, err := mustore.List(store.ListFrom(options.Database, DefaultHistoryTable), store.ListPrefix(prefix))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint64
+	var found bool
+	for _, k := range keys {
+		parts := strings.SplitN(strings.TrimPrefix(k, prefix), "/", 2)
+		b, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if !found || b > latest {
+			latest, found = b, true
+		}
+	}
+	if !found {
+		return 0, store.ErrNotFound
+	}
+
+	entries, err := readHistory(historyBucket(group, topic, latest), opts...)
+	if err != nil {
+		return 0, err
+	}
+	var seq uint64
+	for _, e := range entries {
+		if e.Sequence > seq {
+			seq = e.Sequence
+		}
+	}
+	return seq, nil
+}
+
+// FindHistory returns the entry of the event with the ID in the group's processing history of
+// the topic. Events are found by sequence, so the whole history is read to find the event.
+func FindHistory(group, topic, id string, opts ...store.ReadOption) (*HistoryEntry, error) {
+	entries, err := readHistory(group+"/"+topic+"/", opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+// readHistory reads the entries of the records with the prefix
+func readHistory(prefix string, opts ...store.ReadOption) ([]*HistoryEntry, error) {
+	opts = append([]store.ReadOption{store.ReadFrom("", DefaultHistoryTable)}, opts...)
+	recs, err := mustore.Read(prefix, append(opts, store.ReadPrefix())...)
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []*HistoryEntry
+	for _, r := range recs {
+		var block []*HistoryEntry
+		if err := json.Unmarshal(r.Value, &block); err != nil {
+			return nil, fmt.Errorf("error decoding history %v: %v", r.Key, err)
+		}
+		entries = append(entries, block...)
+	}
+	return entries, nil
+}
+
+// sortHistory sorts the entries by sequence and then consumer
+func sortHistory(entries []*HistoryEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Sequence != entries[j].Sequence {
+			return entries[i].Sequence < entries[j].Sequence
+		}
+		return entries[i].Consumer < entries[j].Consumer
+	})
+}
+
+// historyBucket returns the prefix of the records of the bucket of the history
+func historyBucket(group, topic string, bucket uint64) string {
+	return fmt.Sprintf("%s/%s/%020d/", group, topic, bucket)
+}
+
+// history buffers the entries of a consumer and writes them to the store in blocks, keyed by
+// the bucket of their sequences
+type history struct {
+	group    string
+	topic    string
+	consumer string
+
+	sync.Mutex
+	// blocks written, which key the records of the consumer
+	blocks  uint64
+	entries []*HistoryEntry
+
+	done chan struct{}
+}
+
+// newHistory returns the history of a new consumer of the group, which is flushed until it's
+// closed
+func newHistory(group, topic string) *history {
+	h := &history{
+		group:    group,
+		topic:    topic,
+		consumer: uuid.New().String()[:8],
+		done:     make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// record the outcome of the event
+func (h *history) record(ev *events.Event, started time.Time, err error) {
+	e := &HistoryEntry{
+		Consumer: h.consumer,
+		Sequence: StreamSequence(ev),
+		ID:       ev.ID,
+		Started:  started,
+		Duration: time.Since(started),
+		Result:   ResultOK,
+	}
+	if err != nil {
+		e.Result = ResultError
+		e.Error = err.Error()
+	}
+
+	h.Lock()
+	h.entries = append(h.entries, e)
+	full := len(h.entries) >= DefaultHistoryBlockSize
+	h.Unlock()
+
+	if full {
+		h.flush()
+	}
+}
+
+// run flushes the entries periodically until the history is closed
+func (h *history) run() {
+	t := time.NewTicker(DefaultHistoryFlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			h.flush()
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// close the history once the consumer has stopped, flushing the entries left
+func (h *history) close() {
+	close(h.done)
+	h.flush()
+}
+
+// flush writes the entries recorded to the store, a record for each bucket of their sequences
+func (h *history) flush() {
+	h.Lock()
+	entries := h.entries
+	h.entries = nil
+	block := h.blocks
+	if len(entries) > 0 {
+		h.blocks++
+	}
+	h.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+
+	buckets := make(map[uint64][]*HistoryEntry)
+	for _, e := range entries {
+		b := e.Sequence / DefaultHistoryBucketSize
+		buckets[b] = append(buckets[b], e)
+	}
+
+	for b, entries := range buckets {
+		bytes, err := json.Marshal(entries)
+		if err != nil {
+			logger.Errorf("Error encoding the history of group %v on topic %v: %v", h.group, h.topic, err)
+			return
+		}
+		rec := &store.Record{
+			Key:    fmt.Sprintf("%s%s/%020d", historyBucket(h.group, h.topic, b), h.consumer, block),
+			Value:  bytes,
+			Expiry: DefaultHistoryRetention,
+		}
+		if err := mustore.Write(rec, store.WriteTo("", DefaultHistoryTable)); err != nil {
+			logger.Errorf("Error writing the history of group %v on topic %v: %v", h.group, h.topic, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/events/util"
+	mustore "github.com/micro/micro/v3/service/store"
+)
+
+func TestHistory(t *testing.T) {
+	mustore.DefaultStore = memory.NewStore()
+
+	// small buckets so the history spans several
+	size := DefaultHistoryBucketSize
+	DefaultHistoryBucketSize = 2
+	defer func() { DefaultHistoryBucketSize = size }()
+
+	evChan := make(chan events.Event, 5)
+	for i := 1; i <= 5; i++ {
+		evChan <- events.Event{
+			ID:       "ev-" + strconv.Itoa(i),
+			Topic:    "orders",
+			Metadata: map[string]string{util.StreamSequenceKey: strconv.Itoa(i)},
+		}
+	}
+	close(evChan)
+
+	h := func(ctx context.Context, ev *events.Event) error {
+		if ev.ID == "ev-3" {
+			return errors.New("declined")
+		}
+		return nil
+	}
+	c := newConsumer(h, ConsumeOptions{MaxConcurrent: 1})
+	c.history = newHistory("billing", "orders")
+	c.run(evChan)
+
+	entries, err := History("billing", "orders", 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %v", len(entries))
+	}
+	for i, e := range entries {
+		if seq := uint64(i + 2); e.Sequence != seq || e.ID != "ev-"+strconv.Itoa(i+2) {
+			t.Errorf("Expected event ev-%v at sequence %v, got %v at %v", seq, seq, e.ID, e.Sequence)
+		}
+	}
+	if e := entries[1]; e.Result != ResultError || e.Error != "declined" {
+		t.Errorf("Expected the error to be recorded, got %v %v", e.Result, e.Error)
+	}
+
+	if seq, err := LatestSequence("billing", "orders"); err != nil || seq != 5 {
+		t.Errorf("Expected the latest sequence to be 5, got %v %v", seq, err)
+	}
+	if e, err := FindHistory("billing", "orders", "ev-4"); err != nil || e.Sequence != 4 {
+		t.Errorf("Expected ev-4 at sequence 4, got %v %v", e, err)
+	}
+	if _, err := FindHistory("billing", "orders", "missing"); err != store.ErrNotFound {
+		t.Errorf("Expected not found, got %v", err)
+	}
+	if _, err := LatestSequence("billing", "other"); err != store.ErrNotFound {
+		t.Errorf("Expected not found for another topic, got %v", err)
+	}
+}
+
+func TestTopicSequence(t *testing.T) {
+	mustore.DefaultStore = memory.NewStore()
+
+	if seq, err := TopicSequence("orders"); err != nil || seq != 0 {
+		t.Errorf("Expected no sequence before events are published, got %v %v", seq, err)
+	}
+	rec := &store.Record{Key: "orders", Value: []byte("42")}
+	if err := mustore.Write(rec, store.WriteTo("", DefaultSequenceTable)); err != nil {
+		t.Fatal(err)
+	}
+	if seq, err := TopicSequence("orders"); err != nil || seq != 42 {
+		t.Errorf("Expected sequence 42, got %v %v", seq, err)
+	}
+}
@@ -0,0 +1,195 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultInboxTable is the table the IDs of the events handled are recorded in
+	DefaultInboxTable = "inbox"
+	// DefaultReconcileInterval is how often the reconciler checks for events which weren't
+	// handled
+	DefaultReconcileInterval = time.Minute
+	// DefaultReconcileLimit is the number of recent events of the topic the reconciler checks
+	DefaultReconcileLimit = 1000
+	// DefaultInboxRetention is how long the IDs of the events handled are kept for, which must
+	// be longer than events can be redelivered after
+	DefaultInboxRetention = time.Hour * 24 * 7
+)
+
+// TxHandler applies an event to the state in the database using the transaction the event is
+// recorded in the inbox with, so the event is applied if and only if it's recorded
+type TxHandler func(ctx context.Context, tx *sql.Tx, ev *events.Event) error
+
+// InboxOptions contains the options of an inbox
+type InboxOptions struct {
+	// Table the IDs of the events handled are recorded in
+	Table string
+	// ReconcileInterval is how often the reconciler runs, zero to disable it
+	ReconcileInterval time.Duration
+	// ReconcileLimit is the number of recent events of the topic the reconciler checks
+	ReconcileLimit int
+	// Retention is how long the IDs of the events handled are kept for
+	Retention time.Duration
+	// ConsumeOptions are passed to Consume
+	ConsumeOptions []ConsumeOption
+}
+
+// InboxOption sets attributes on InboxOptions
+type InboxOption func(o *InboxOptions)
+
+// InboxTable sets the table the IDs of the events handled are recorded in
+func InboxTable(t string) InboxOption {
+	return func(o *InboxOptions) {
+		o.Table = t
+	}
+}
+
+// Reconcile sets how often the reconciler runs and the number of recent events it checks,
+// an interval of zero disables it
+func Reconcile(interval time.Duration, limit int) InboxOption {
+	return func(o *InboxOptions) {
+		o.ReconcileInterval = interval
+		o.ReconcileLimit = limit
+	}
+}
+
+// InboxRetention sets how long the IDs of the events handled are kept for
+func InboxRetention(d time.Duration) InboxOption {
+	return func(o *InboxOptions) {
+		o.Retention = d
+	}
+}
+
+// ConsumeWith sets the options the topic is consumed with, e.g. MaxConcurrent
+func ConsumeWith(opts ...ConsumeOption) InboxOption {
+	return func(o *InboxOptions) {
+		o.ConsumeOptions = append(o.ConsumeOptions, opts...)
+	}
+}
+
+// Inbox records the IDs of the events handled in a table of the database the handler writes
+// to, in the same transaction as the handler's changes. An event which has been recorded is
+// skipped when it's redelivered and an event whose transaction failed isn't recorded, so each
+// event is applied exactly once. The table is created if it doesn't exist; the queries use
+// $1 style placeholders, as used by postgres and cockroach.
+type Inbox struct {
+	db      *sql.DB
+	handler TxHandler
+	opts    InboxOptions
+}
+
+// NewInbox returns an inbox which applies events with the handler
+func NewInbox(db *sql.DB, h TxHandler, opts ...InboxOption) (*Inbox, error) {
+	options := InboxOptions{
+		Table:             DefaultInboxTable,
+		ReconcileInterval: DefaultReconcileInterval,
+		ReconcileLimit:    DefaultReconcileLimit,
+		Retention:         DefaultInboxRetention,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	_, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id TEXT PRIMARY KEY,
+		topic TEXT NOT NULL,
+		handled TIMESTAMP NOT NULL
+	)`, options.Table))
+	if err != nil {
+		return nil, fmt.Errorf("error creating the inbox table: %v", err)
+	}
+
+	return &Inbox{db: db, handler: h, opts: options}, nil
+}
+
+// ConsumeInbox consumes the topic, applying the events with the handler exactly once using an
+// inbox in the database. The reconciler applies any recent events of the topic in the events
+// store which weren't received from the stream or whose handler failed.
+func ConsumeInbox(topic string, db *sql.DB, h TxHandler, opts ...InboxOption) error {
+	in, err := NewInbox(db, h, opts...)
+	if err != nil {
+		return err
+	}
+	if err := Consume(topic, in.Handle, in.opts.ConsumeOptions...); err != nil {
+		return err
+	}
+	if in.opts.ReconcileInterval > 0 {
+		go in.reconcile(topic)
+	}
+	return nil
+}
+
+// Handle the event, applying it with the handler unless it's already in the inbox
+func (i *Inbox) Handle(ctx context.Context, ev *events.Event) error {
+	tx, err := i.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var n int
+	row := tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE id = $1`, i.opts.Table), ev.ID)
+	if err := row.Scan(&n); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n > 0 {
+		// already applied
+		return tx.Rollback()
+	}
+
+	// the primary key rejects the event if it's handled concurrently
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (id, topic, handled) VALUES ($1, $2, $3)`, i.opts.Table),
+		ev.ID, ev.Topic, time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := i.handler(ctx, tx, ev); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Prune the IDs of the events handled before the retention
+func (i *Inbox) Prune() error {
+	_, err := i.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE handled < $1`, i.opts.Table),
+		time.Now().UTC().Add(-i.opts.Retention))
+	return err
+}
+
+// reconcile applies the recent events of the topic which weren't handled, e.g. because the
+// service was down when they were published, and prunes the inbox
+func (i *Inbox) reconcile(topic string) {
+	t := time.NewTicker(i.opts.ReconcileInterval)
+	defer t.Stop()
+
+	for range t.C {
+		evs, err := Read(topic, events.ReadLimit(uint(i.opts.ReconcileLimit)))
+		if err != nil {
+			logger.Errorf("Error reading events on topic %v to reconcile: %v", topic, err)
+			continue
+		}
+		for _, ev := range evs {
+			// events older than the retention may have been pruned from the inbox
+			if time.Since(ev.Timestamp) > i.opts.Retention {
+				continue
+			}
+			if err := i.Handle(NewContext(context.Background(), ev), ev); err != nil {
+				logger.Errorf("Error reconciling event %v on topic %v: %v", ev.ID, topic, err)
+			}
+		}
+
+		if err := i.Prune(); err != nil {
+			logger.Errorf("Error pruning the inbox: %v", err)
+		}
+	}
+}
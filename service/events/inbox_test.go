@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/events"
+)
+
+// testDB is an in memory database which understands the queries of the inbox, and APPLY
+// statements which the handlers in the tests use to record the events applied
+type testDB struct {
+	sync.Mutex
+	inbox   map[string]time.Time
+	applied []string
+}
+
+type testConn struct {
+	db *testDB
+	// tx buffers the changes of the transaction until it's committed, nil if not in one
+	tx *testDB
+}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{conn: c, query: query}, nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	c.tx = &testDB{inbox: make(map[string]time.Time)}
+	return c, nil
+}
+
+func (c *testConn) Commit() error {
+	c.db.Lock()
+	defer c.db.Unlock()
+	for id, t := range c.tx.inbox {
+		c.db.inbox[id] = t
+	}
+	c.db.applied = append(c.db.applied, c.tx.applied...)
+	c.tx = nil
+	return nil
+}
+
+func (c *testConn) Rollback() error {
+	c.tx = nil
+	return nil
+}
+
+type testStmt struct {
+	conn  *testConn
+	query string
+}
+
+func (s *testStmt) Close() error {
+	return nil
+}
+
+func (s *testStmt) NumInput() int {
+	return -1
+}
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db, tx := s.conn.db, s.conn.tx
+	db.Lock()
+	defer db.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE"):
+	case strings.HasPrefix(s.query, "INSERT"):
+		id := args[0].(string)
+		if _, ok := db.inbox[id]; ok {
+			return nil, errors.New("duplicate key")
+		}
+		tx.inbox[id] = args[2].(time.Time)
+	case strings.HasPrefix(s.query, "DELETE"):
+		for id, t := range db.inbox {
+			if t.Before(args[0].(time.Time)) {
+				delete(db.inbox, id)
+			}
+		}
+	case strings.HasPrefix(s.query, "APPLY"):
+		tx.applied = append(tx.applied, args[0].(string))
+	default:
+		return nil, errors.New("unknown query " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.db.Lock()
+	defer s.conn.db.Unlock()
+
+	var n int64
+	if _, ok := s.conn.db.inbox[args[0].(string)]; ok {
+		n = 1
+	}
+	return &testRows{n: n}, nil
+}
+
+type testRows struct {
+	n    int64
+	read bool
+}
+
+func (r *testRows) Columns() []string {
+	return []string{"count"}
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.n
+	return nil
+}
+
+type testConnector struct {
+	db *testDB
+}
+
+func (t *testConnector) Connect(context.Context) (driver.Conn, error) {
+	return &testConn{db: t.db}, nil
+}
+
+func (t *testConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestInbox(t *testing.T) {
+	db := &testDB{inbox: make(map[string]time.Time)}
+	sqlDB := sql.OpenDB(&testConnector{db: db})
+	defer sqlDB.Close()
+
+	fail := true
+	in, err := NewInbox(sqlDB, func(ctx context.Context, tx *sql.Tx, ev *events.Event) error {
+		if _, err := tx.ExecContext(ctx, "APPLY", ev.ID); err != nil {
+			return err
+		}
+		if ev.ID == "2" && fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, id := range []string{"1", "1", "2"} {
+		in.Handle(context.TODO(), &events.Event{ID: id, Topic: "orders"})
+	}
+	if len(db.applied) != 1 || db.applied[0] != "1" {
+		t.Errorf("Expected only event 1 to be applied, got %v", db.applied)
+	}
+	if _, ok := db.inbox["2"]; ok {
+		t.Errorf("Expected the failed event not to be recorded")
+	}
+
+	// the failed event is applied when it's retried
+	fail = false
+	if err := in.Handle(context.TODO(), &events.Event{ID: "2", Topic: "orders"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(db.applied) != 2 || db.applied[1] != "2" {
+		t.Errorf("Expected event 2 to be applied, got %v", db.applied)
+	}
+
+	// the events handled before the retention are pruned
+	in.opts.Retention = 0
+	if err := in.Prune(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(db.inbox) != 0 {
+		t.Errorf("Expected the inbox to be pruned, got %v", db.inbox)
+	}
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/metadata"
+	mucontext "github.com/micro/micro/v3/service/context"
+)
+
+// localeKeys are the metadata keys of the end user's locale
+var localeKeys = []string{
+	mucontext.LanguageKey,
+	mucontext.TimezoneKey,
+	mucontext.CurrencyKey,
+}
+
+// WithLocale propagates the locale of the request being handled to the event, so consumers
+// can use it when processing the event. WithMetadata replaces the metadata, so pass it before
+// this option.
+func WithLocale(ctx context.Context) events.PublishOption {
+	md, _ := metadata.FromContext(ctx)
+	locale := localeMetadata(md)
+
+	return func(o *events.PublishOptions) {
+		for k, v := range locale {
+			setMetadata(o, k, v)
+		}
+	}
+}
+
+// localeMetadata returns the locale keys set in the metadata
+func localeMetadata(md map[string]string) map[string]string {
+	locale := make(map[string]string)
+	for _, k := range localeKeys {
+		if v, ok := metadata.Metadata(md).Get(k); ok && len(v) > 0 {
+			locale[k] = v
+		}
+	}
+	return locale
+}
@@ -119,9 +119,14 @@ func (m *PublishResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_PublishResponse proto.InternalMessageInfo
 
 type SubscribeRequest struct {
-	Queue                string   `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
-	Topic                string   `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
-	StartAtTime          int64    `protobuf:"varint,3,opt,name=start_at_time,json=startAtTime,proto3" json:"start_at_time,omitempty"`
+	Queue       string `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Topic       string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	StartAtTime int64  `protobuf:"varint,3,opt,name=start_at_time,json=startAtTime,proto3" json:"start_at_time,omitempty"`
+	// namespace which published the contract of the topic, to subscribe to a topic shared by
+	// another namespace
+	Namespace string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// version of the contract subscribed to, defaults to the latest
+	ContractVersion      int64    `protobuf:"varint,5,opt,name=contract_version,json=contractVersion,proto3" json:"contract_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -173,6 +178,20 @@ func (m *SubscribeRequest) GetStartAtTime() int64 {
 	return 0
 }
 
+func (m *SubscribeRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetContractVersion() int64 {
+	if m != nil {
+		return m.ContractVersion
+	}
+	return 0
+}
+
 type Event struct {
 	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Topic                string            `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
@@ -416,6 +435,416 @@ func (m *WriteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_WriteResponse proto.InternalMessageInfo
 
+type Contract struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Topic     string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Version   int64  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// JSON schema the payloads of the events must match
+	Schema               string   `protobuf:"bytes,4,opt,name=schema,proto3" json:"schema,omitempty"`
+	Description          string   `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Contract) Reset()         { *m = Contract{} }
+func (m *Contract) String() string { return proto.CompactTextString(m) }
+func (*Contract) ProtoMessage()    {}
+func (*Contract) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{8}
+}
+
+func (m *Contract) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Contract.Unmarshal(m, b)
+}
+func (m *Contract) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Contract.Marshal(b, m, deterministic)
+}
+func (m *Contract) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Contract.Merge(m, src)
+}
+func (m *Contract) XXX_Size() int {
+	return xxx_messageInfo_Contract.Size(m)
+}
+func (m *Contract) XXX_DiscardUnknown() {
+	xxx_messageInfo_Contract.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Contract proto.InternalMessageInfo
+
+func (m *Contract) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Contract) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *Contract) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Contract) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
+func (m *Contract) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Contract) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+type Subscriber struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Queue                string   `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	Version              int64    `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Subscribed           int64    `protobuf:"varint,4,opt,name=subscribed,proto3" json:"subscribed,omitempty"`
+	LastSeen             int64    `protobuf:"varint,5,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Subscriber) Reset()         { *m = Subscriber{} }
+func (m *Subscriber) String() string { return proto.CompactTextString(m) }
+func (*Subscriber) ProtoMessage()    {}
+func (*Subscriber) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{9}
+}
+
+func (m *Subscriber) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Subscriber.Unmarshal(m, b)
+}
+func (m *Subscriber) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Subscriber.Marshal(b, m, deterministic)
+}
+func (m *Subscriber) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Subscriber.Merge(m, src)
+}
+func (m *Subscriber) XXX_Size() int {
+	return xxx_messageInfo_Subscriber.Size(m)
+}
+func (m *Subscriber) XXX_DiscardUnknown() {
+	xxx_messageInfo_Subscriber.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Subscriber proto.InternalMessageInfo
+
+func (m *Subscriber) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Subscriber) GetQueue() string {
+	if m != nil {
+		return m.Queue
+	}
+	return ""
+}
+
+func (m *Subscriber) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Subscriber) GetSubscribed() int64 {
+	if m != nil {
+		return m.Subscribed
+	}
+	return 0
+}
+
+func (m *Subscriber) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+type PublishContractRequest struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Version              int64    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Schema               string   `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	Description          string   `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PublishContractRequest) Reset()         { *m = PublishContractRequest{} }
+func (m *PublishContractRequest) String() string { return proto.CompactTextString(m) }
+func (*PublishContractRequest) ProtoMessage()    {}
+func (*PublishContractRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{10}
+}
+
+func (m *PublishContractRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublishContractRequest.Unmarshal(m, b)
+}
+func (m *PublishContractRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublishContractRequest.Marshal(b, m, deterministic)
+}
+func (m *PublishContractRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublishContractRequest.Merge(m, src)
+}
+func (m *PublishContractRequest) XXX_Size() int {
+	return xxx_messageInfo_PublishContractRequest.Size(m)
+}
+func (m *PublishContractRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublishContractRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PublishContractRequest proto.InternalMessageInfo
+
+func (m *PublishContractRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *PublishContractRequest) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *PublishContractRequest) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
+func (m *PublishContractRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+type PublishContractResponse struct {
+	Contract             *Contract `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PublishContractResponse) Reset()         { *m = PublishContractResponse{} }
+func (m *PublishContractResponse) String() string { return proto.CompactTextString(m) }
+func (*PublishContractResponse) ProtoMessage()    {}
+func (*PublishContractResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{11}
+}
+
+func (m *PublishContractResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublishContractResponse.Unmarshal(m, b)
+}
+func (m *PublishContractResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublishContractResponse.Marshal(b, m, deterministic)
+}
+func (m *PublishContractResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublishContractResponse.Merge(m, src)
+}
+func (m *PublishContractResponse) XXX_Size() int {
+	return xxx_messageInfo_PublishContractResponse.Size(m)
+}
+func (m *PublishContractResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublishContractResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PublishContractResponse proto.InternalMessageInfo
+
+func (m *PublishContractResponse) GetContract() *Contract {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+type ListContractsRequest struct {
+	// namespace to list the contracts of, blank for every namespace
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListContractsRequest) Reset()         { *m = ListContractsRequest{} }
+func (m *ListContractsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListContractsRequest) ProtoMessage()    {}
+func (*ListContractsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{12}
+}
+
+func (m *ListContractsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListContractsRequest.Unmarshal(m, b)
+}
+func (m *ListContractsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListContractsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListContractsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListContractsRequest.Merge(m, src)
+}
+func (m *ListContractsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListContractsRequest.Size(m)
+}
+func (m *ListContractsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListContractsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListContractsRequest proto.InternalMessageInfo
+
+func (m *ListContractsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type ListContractsResponse struct {
+	Contracts            []*Contract `protobuf:"bytes,1,rep,name=contracts,proto3" json:"contracts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListContractsResponse) Reset()         { *m = ListContractsResponse{} }
+func (m *ListContractsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListContractsResponse) ProtoMessage()    {}
+func (*ListContractsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{13}
+}
+
+func (m *ListContractsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListContractsResponse.Unmarshal(m, b)
+}
+func (m *ListContractsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListContractsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListContractsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListContractsResponse.Merge(m, src)
+}
+func (m *ListContractsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListContractsResponse.Size(m)
+}
+func (m *ListContractsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListContractsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListContractsResponse proto.InternalMessageInfo
+
+func (m *ListContractsResponse) GetContracts() []*Contract {
+	if m != nil {
+		return m.Contracts
+	}
+	return nil
+}
+
+type ListSubscribersRequest struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSubscribersRequest) Reset()         { *m = ListSubscribersRequest{} }
+func (m *ListSubscribersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSubscribersRequest) ProtoMessage()    {}
+func (*ListSubscribersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{14}
+}
+
+func (m *ListSubscribersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSubscribersRequest.Unmarshal(m, b)
+}
+func (m *ListSubscribersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSubscribersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSubscribersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSubscribersRequest.Merge(m, src)
+}
+func (m *ListSubscribersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSubscribersRequest.Size(m)
+}
+func (m *ListSubscribersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSubscribersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSubscribersRequest proto.InternalMessageInfo
+
+func (m *ListSubscribersRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+type ListSubscribersResponse struct {
+	Subscribers          []*Subscriber `protobuf:"bytes,1,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListSubscribersResponse) Reset()         { *m = ListSubscribersResponse{} }
+func (m *ListSubscribersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSubscribersResponse) ProtoMessage()    {}
+func (*ListSubscribersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c2b661093e5f4d5, []int{15}
+}
+
+func (m *ListSubscribersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSubscribersResponse.Unmarshal(m, b)
+}
+func (m *ListSubscribersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSubscribersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSubscribersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSubscribersResponse.Merge(m, src)
+}
+func (m *ListSubscribersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSubscribersResponse.Size(m)
+}
+func (m *ListSubscribersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSubscribersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSubscribersResponse proto.InternalMessageInfo
+
+func (m *ListSubscribersResponse) GetSubscribers() []*Subscriber {
+	if m != nil {
+		return m.Subscribers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*PublishRequest)(nil), "events.PublishRequest")
 	proto.RegisterMapType((map[string]string)(nil), "events.PublishRequest.MetadataEntry")
@@ -427,44 +856,71 @@ func init() {
 	proto.RegisterType((*ReadResponse)(nil), "events.ReadResponse")
 	proto.RegisterType((*WriteRequest)(nil), "events.WriteRequest")
 	proto.RegisterType((*WriteResponse)(nil), "events.WriteResponse")
+	proto.RegisterType((*Contract)(nil), "events.Contract")
+	proto.RegisterType((*Subscriber)(nil), "events.Subscriber")
+	proto.RegisterType((*PublishContractRequest)(nil), "events.PublishContractRequest")
+	proto.RegisterType((*PublishContractResponse)(nil), "events.PublishContractResponse")
+	proto.RegisterType((*ListContractsRequest)(nil), "events.ListContractsRequest")
+	proto.RegisterType((*ListContractsResponse)(nil), "events.ListContractsResponse")
+	proto.RegisterType((*ListSubscribersRequest)(nil), "events.ListSubscribersRequest")
+	proto.RegisterType((*ListSubscribersResponse)(nil), "events.ListSubscribersResponse")
 }
 
 func init() { proto.RegisterFile("service/events/proto/events.proto", fileDescriptor_0c2b661093e5f4d5) }
 
 var fileDescriptor_0c2b661093e5f4d5 = []byte{
-	// 512 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0x41, 0x6f, 0xd3, 0x30,
-	0x14, 0xc7, 0x95, 0xa4, 0xe9, 0xe8, 0x6b, 0xbb, 0x0d, 0x53, 0x46, 0x54, 0x38, 0x94, 0x00, 0x52,
-	0x4f, 0x2d, 0x74, 0x63, 0xa0, 0xed, 0x02, 0x48, 0x3d, 0x22, 0x81, 0x8b, 0x84, 0xc4, 0x65, 0x72,
-	0x12, 0x8f, 0x59, 0x24, 0x4d, 0x16, 0xbf, 0x54, 0xaa, 0xc4, 0x37, 0x45, 0x7c, 0x17, 0x14, 0xdb,
-	0xc9, 0x92, 0x0d, 0x26, 0x0e, 0xbb, 0x44, 0xf9, 0x3f, 0xfb, 0x3d, 0xff, 0xdf, 0xcf, 0x4f, 0x86,
-	0xa7, 0x92, 0xe7, 0x1b, 0x11, 0xf2, 0x39, 0xdf, 0xf0, 0x35, 0xca, 0x79, 0x96, 0xa7, 0x98, 0x1a,
-	0x31, 0x53, 0x82, 0x74, 0xb5, 0xf2, 0x7f, 0x5b, 0xb0, 0xfb, 0xa9, 0x08, 0x62, 0x21, 0x2f, 0x28,
-	0xbf, 0x2c, 0xb8, 0x44, 0x32, 0x02, 0x17, 0xd3, 0x4c, 0x84, 0x9e, 0x35, 0xb1, 0xa6, 0x3d, 0xaa,
-	0x05, 0x79, 0x07, 0xf7, 0x12, 0x8e, 0x2c, 0x62, 0xc8, 0x3c, 0x7b, 0xe2, 0x4c, 0xfb, 0x8b, 0xe7,
-	0x33, 0x53, 0xb1, 0x9d, 0x3f, 0xfb, 0x68, 0xb6, 0x2d, 0xd7, 0x98, 0x6f, 0x69, 0x9d, 0x45, 0x3c,
-	0xd8, 0xc9, 0xd8, 0x36, 0x4e, 0x59, 0xe4, 0x39, 0x13, 0x6b, 0x3a, 0xa0, 0x95, 0x24, 0x4f, 0xa0,
-	0x87, 0x22, 0xe1, 0x12, 0x59, 0x92, 0x79, 0x9d, 0x89, 0x35, 0x75, 0xe8, 0x55, 0x60, 0x7c, 0x0a,
-	0xc3, 0x56, 0x49, 0xb2, 0x0f, 0xce, 0x0f, 0xbe, 0x35, 0xf6, 0xca, 0xdf, 0xd2, 0xf2, 0x86, 0xc5,
-	0x05, 0xf7, 0x6c, 0x6d, 0x59, 0x89, 0x13, 0xfb, 0xad, 0xe5, 0xdf, 0x87, 0xbd, 0xda, 0x9e, 0xcc,
-	0xd2, 0xb5, 0xe4, 0x7e, 0x00, 0xfb, 0xab, 0x22, 0x90, 0x61, 0x2e, 0x02, 0xde, 0xe8, 0xf9, 0xb2,
-	0xe0, 0x05, 0xaf, 0x7a, 0x56, 0xe2, 0x8a, 0x84, 0xdd, 0x24, 0xe1, 0xc3, 0x50, 0x22, 0xcb, 0xf1,
-	0x8c, 0xe1, 0x59, 0xe9, 0x52, 0x75, 0xe3, 0xd0, 0xbe, 0x0a, 0xbe, 0xc7, 0x2f, 0x22, 0xe1, 0xfe,
-	0x2f, 0x0b, 0xdc, 0x65, 0x49, 0x87, 0xec, 0x82, 0x2d, 0x22, 0x53, 0xd6, 0x16, 0xd1, 0x3f, 0x6a,
-	0xbe, 0x69, 0xd0, 0x75, 0x14, 0xdd, 0xc7, 0x15, 0x5d, 0x55, 0xe6, 0x7f, 0xa0, 0x76, 0x6e, 0x81,
-	0xea, 0xde, 0x29, 0xd4, 0xcf, 0xd0, 0xa7, 0x9c, 0x45, 0xb7, 0x0f, 0xcc, 0x08, 0xdc, 0x58, 0x24,
-	0x02, 0x55, 0x7a, 0x87, 0x6a, 0x41, 0x0e, 0xa0, 0x9b, 0x9e, 0x9f, 0x4b, 0x8e, 0x8a, 0x5a, 0x87,
-	0x1a, 0xe5, 0xbf, 0x86, 0x81, 0x2e, 0xa9, 0x2f, 0x89, 0xbc, 0x00, 0x33, 0xa1, 0x9e, 0xa5, 0x70,
-	0x0c, 0x5b, 0x38, 0x68, 0x35, 0xbe, 0x4b, 0x18, 0x7c, 0xcd, 0x05, 0xd6, 0xf7, 0xf8, 0x0c, 0x5c,
-	0xb5, 0xa2, 0xac, 0xdc, 0xc8, 0xd2, 0x6b, 0x65, 0xab, 0x88, 0xb1, 0xf2, 0xe5, 0xd0, 0xf2, 0xd7,
-	0xdf, 0x83, 0xa1, 0x29, 0xa3, 0x8f, 0x5f, 0xfc, 0x84, 0xee, 0x0a, 0x73, 0xce, 0x12, 0x72, 0x02,
-	0x3b, 0x66, 0x80, 0xc8, 0xc1, 0xdf, 0x07, 0x7e, 0xfc, 0xe8, 0x46, 0xdc, 0x34, 0x71, 0x0c, 0xbd,
-	0x7a, 0xd2, 0x88, 0x57, 0xed, 0xba, 0x3e, 0x7c, 0xe3, 0xb6, 0xcb, 0x97, 0xd6, 0x22, 0x03, 0x77,
-	0x85, 0x69, 0xce, 0xc9, 0x2b, 0xe8, 0x94, 0x54, 0xc8, 0x83, 0x6a, 0x47, 0x03, 0xfb, 0x78, 0xd4,
-	0x0e, 0x9a, 0x33, 0x8f, 0xc0, 0x55, 0xad, 0x90, 0x7a, 0xb9, 0x09, 0x68, 0xfc, 0xf0, 0x5a, 0x54,
-	0x67, 0x7d, 0x38, 0xfe, 0x76, 0xf4, 0x5d, 0xe0, 0x45, 0x11, 0xcc, 0xc2, 0x34, 0x99, 0x27, 0x22,
-	0xcc, 0x53, 0xf3, 0xdd, 0x1c, 0xce, 0x5b, 0xaf, 0x89, 0x7e, 0x4c, 0x4e, 0x75, 0x95, 0xa0, 0xab,
-	0xd4, 0xe1, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa1, 0x35, 0x06, 0x6c, 0x72, 0x04, 0x00, 0x00,
+	// 816 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x56, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xae, 0xe3, 0x24, 0x8d, 0x27, 0x4d, 0x1b, 0x96, 0x36, 0xb5, 0xd2, 0x52, 0x8a, 0x01, 0xa9,
+	0x48, 0x28, 0x81, 0xb4, 0x14, 0xd4, 0x5e, 0xf8, 0x51, 0xc4, 0xa5, 0x88, 0xe2, 0x20, 0x90, 0xb8,
+	0x44, 0x1b, 0x67, 0x4b, 0x2d, 0xe2, 0xd8, 0x78, 0x37, 0x91, 0x2a, 0x71, 0xe2, 0x25, 0x78, 0x08,
+	0x78, 0x23, 0xc4, 0x4b, 0xf0, 0x04, 0xac, 0xd7, 0xbb, 0xb6, 0xf3, 0x0b, 0x07, 0x2e, 0xd1, 0xce,
+	0xec, 0xfc, 0x7c, 0x33, 0xf3, 0xcd, 0xc6, 0x70, 0x8b, 0x92, 0x70, 0xec, 0x3a, 0xa4, 0x49, 0xc6,
+	0x64, 0xc8, 0x68, 0x33, 0x08, 0x7d, 0xe6, 0x4b, 0xa1, 0x21, 0x04, 0x54, 0x8c, 0x25, 0xeb, 0x97,
+	0x06, 0xeb, 0xe7, 0xa3, 0xde, 0xc0, 0xa5, 0x97, 0x36, 0xf9, 0x3c, 0x22, 0x94, 0xa1, 0x4d, 0x28,
+	0x30, 0x3f, 0x70, 0x1d, 0x53, 0xdb, 0xd7, 0x0e, 0x0c, 0x3b, 0x16, 0xd0, 0x53, 0x28, 0x79, 0x84,
+	0xe1, 0x3e, 0x66, 0xd8, 0xcc, 0xed, 0xeb, 0x07, 0xe5, 0xd6, 0x9d, 0x86, 0x8c, 0x38, 0xe9, 0xdf,
+	0x78, 0x25, 0xcd, 0xda, 0x43, 0x16, 0x5e, 0xd9, 0x89, 0x17, 0x32, 0x61, 0x35, 0xc0, 0x57, 0x03,
+	0x1f, 0xf7, 0x4d, 0x9d, 0x47, 0x5e, 0xb3, 0x95, 0x88, 0x76, 0xc1, 0x60, 0xae, 0xc7, 0x7d, 0xb1,
+	0x17, 0x98, 0x79, 0x7e, 0xa7, 0xdb, 0xa9, 0xa2, 0x7e, 0x0a, 0x95, 0x89, 0x90, 0xa8, 0x0a, 0xfa,
+	0x27, 0x72, 0x25, 0xe1, 0x45, 0xc7, 0x08, 0xf2, 0x18, 0x0f, 0x46, 0x84, 0x23, 0x13, 0x90, 0x85,
+	0x70, 0x92, 0x7b, 0xa2, 0x59, 0xd7, 0x60, 0x23, 0x81, 0x47, 0x03, 0x7f, 0x48, 0x89, 0xf5, 0x5d,
+	0x83, 0x6a, 0x67, 0xd4, 0xa3, 0x4e, 0xe8, 0xf6, 0x48, 0xa6, 0x68, 0x7e, 0xe0, 0x11, 0x64, 0xd1,
+	0x42, 0x48, 0x5b, 0x91, 0xcb, 0xb6, 0xc2, 0x82, 0x0a, 0x47, 0x16, 0xb2, 0x2e, 0x66, 0xdd, 0x08,
+	0xa6, 0x28, 0x47, 0xb7, 0xcb, 0x42, 0xf9, 0x8c, 0xbd, 0xe5, 0xaa, 0xa8, 0xa4, 0x21, 0xe6, 0x15,
+	0x04, 0xd8, 0x21, 0xa2, 0x24, 0xc3, 0x4e, 0x15, 0xe8, 0x1e, 0x54, 0x1d, 0x9f, 0xd7, 0x82, 0x1d,
+	0xd6, 0x1d, 0x93, 0x90, 0xba, 0xfe, 0xd0, 0x2c, 0x88, 0x20, 0x1b, 0x4a, 0xff, 0x2e, 0x56, 0x5b,
+	0x3f, 0x35, 0x28, 0xb4, 0xa3, 0x3e, 0xa3, 0x75, 0xc8, 0xb9, 0x7d, 0x89, 0x8f, 0x9f, 0x16, 0x80,
+	0x7b, 0x9c, 0x99, 0x93, 0x2e, 0xe6, 0xb4, 0xa3, 0xe6, 0x24, 0xc2, 0xfc, 0xcb, 0x78, 0xf2, 0x4b,
+	0xc6, 0x53, 0xf8, 0xaf, 0xe3, 0x79, 0x03, 0x65, 0x9b, 0xe0, 0xfe, 0x72, 0xea, 0x71, 0xed, 0xc0,
+	0xf5, 0x5c, 0x26, 0xdc, 0xf3, 0x76, 0x2c, 0xa0, 0x1a, 0x14, 0xfd, 0x8b, 0x0b, 0x4a, 0x98, 0x68,
+	0x7f, 0xde, 0x96, 0x92, 0xf5, 0x08, 0xd6, 0xe2, 0x90, 0xf1, 0xb8, 0xd1, 0x5d, 0x90, 0x5c, 0xe7,
+	0x41, 0xa3, 0x76, 0x54, 0x26, 0xda, 0x61, 0xab, 0x45, 0x68, 0xc3, 0xda, 0xfb, 0xd0, 0x65, 0x09,
+	0x21, 0x6e, 0x43, 0x41, 0xdc, 0x08, 0x28, 0x33, 0x5e, 0xf1, 0x5d, 0x54, 0x2a, 0x63, 0x03, 0x81,
+	0x4b, 0xb7, 0xa3, 0xa3, 0xb5, 0x01, 0x15, 0x19, 0x46, 0xb2, 0xed, 0x87, 0x06, 0xa5, 0x17, 0x72,
+	0xa6, 0x93, 0xac, 0xd0, 0xa6, 0x59, 0x31, 0x7f, 0xa0, 0x7c, 0x2e, 0x8a, 0x22, 0x31, 0xcf, 0x94,
+	0x18, 0x75, 0x80, 0x3a, 0x97, 0xc4, 0xc3, 0x92, 0x60, 0x52, 0x42, 0xfb, 0x50, 0xee, 0x93, 0x88,
+	0xde, 0x01, 0x53, 0xc4, 0x32, 0xec, 0xac, 0x2a, 0x8a, 0xe9, 0x84, 0x04, 0x33, 0xd2, 0x37, 0x8b,
+	0x71, 0x4c, 0x29, 0x5a, 0xdf, 0x34, 0x80, 0x64, 0x39, 0xc2, 0xbf, 0x03, 0x8e, 0x97, 0x26, 0x97,
+	0x5d, 0x9a, 0xc5, 0x80, 0xf7, 0x00, 0xa8, 0x8a, 0xdd, 0x97, 0x8b, 0x9e, 0xd1, 0xa0, 0x1d, 0x30,
+	0x06, 0x98, 0xb2, 0x2e, 0x25, 0x44, 0xed, 0x43, 0x29, 0x52, 0x74, 0xb8, 0x6c, 0x7d, 0xd5, 0xa0,
+	0x26, 0x57, 0x59, 0xf5, 0x73, 0x39, 0x6d, 0x32, 0x38, 0x72, 0x8b, 0x1a, 0xa7, 0x2f, 0x6b, 0x5c,
+	0x7e, 0xa6, 0x71, 0xd6, 0x4b, 0xd8, 0x9e, 0xc1, 0x20, 0x79, 0x76, 0x1f, 0x4a, 0x6a, 0x77, 0x25,
+	0x67, 0xaa, 0x8a, 0x33, 0x89, 0x6d, 0x62, 0x61, 0x1d, 0xc1, 0xe6, 0x99, 0x4b, 0x99, 0xba, 0xa1,
+	0xaa, 0x94, 0xa5, 0x0d, 0xe7, 0xe9, 0xb7, 0xa6, 0xbc, 0x64, 0xf2, 0x06, 0x18, 0x2a, 0xb4, 0xe2,
+	0xf9, 0x6c, 0xf6, 0xd4, 0xc4, 0x6a, 0x40, 0x2d, 0x0a, 0x94, 0x4e, 0x9a, 0x2e, 0xed, 0xa5, 0xf5,
+	0x1a, 0xb6, 0x67, 0xec, 0x65, 0xea, 0x23, 0x28, 0xd3, 0x54, 0x2d, 0x93, 0x23, 0x95, 0x3c, 0xf5,
+	0xb0, 0xb3, 0x66, 0xad, 0x2f, 0x50, 0xec, 0x30, 0xce, 0x39, 0x0f, 0x9d, 0xc0, 0xaa, 0x6c, 0x29,
+	0xaa, 0xcd, 0xff, 0x47, 0xa9, 0x6f, 0xcf, 0xe8, 0x65, 0xee, 0x63, 0x30, 0x92, 0x04, 0xc8, 0x9c,
+	0xc9, 0xa9, 0xfc, 0x27, 0x97, 0xf7, 0x81, 0xd6, 0x0a, 0xa0, 0xd0, 0x61, 0x7e, 0x48, 0xd0, 0x43,
+	0xc8, 0x47, 0x8f, 0x05, 0xba, 0xae, 0x2c, 0x32, 0xaf, 0x51, 0x7d, 0x73, 0x52, 0x99, 0xd4, 0x5b,
+	0x10, 0x1b, 0x8e, 0x92, 0xeb, 0xec, 0xbb, 0x51, 0xdf, 0x9a, 0xd2, 0xc6, 0x5e, 0xad, 0xdf, 0x1a,
+	0x18, 0xc9, 0xd8, 0xd0, 0x59, 0x5a, 0xf3, 0xde, 0x54, 0x6d, 0x53, 0xdc, 0xae, 0xdf, 0x5c, 0x78,
+	0x2f, 0x1f, 0x98, 0x15, 0xd4, 0x86, 0x7c, 0x34, 0x1c, 0xb4, 0xab, 0x4c, 0xe7, 0x31, 0xab, 0x7e,
+	0x63, 0xc1, 0x6d, 0x12, 0xe6, 0x1c, 0xca, 0x99, 0xf9, 0xa6, 0xc0, 0xe6, 0x13, 0x25, 0x05, 0xb6,
+	0x80, 0x18, 0xd6, 0xca, 0xf3, 0xe3, 0x0f, 0x47, 0x1f, 0x5d, 0x76, 0x39, 0xea, 0x35, 0x1c, 0xdf,
+	0x6b, 0x7a, 0xae, 0x13, 0xfa, 0xf2, 0x77, 0x7c, 0xd8, 0x9c, 0xf8, 0x46, 0x89, 0x3f, 0x51, 0x4e,
+	0xe3, 0x88, 0xbd, 0xa2, 0x90, 0x0e, 0xff, 0x00, 0x04, 0x95, 0xdd, 0xbd, 0xc8, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -718,3 +1174,147 @@ var _Store_serviceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service/events/proto/events.proto",
 }
+
+// ContractsClient is the client API for Contracts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ContractsClient interface {
+	Publish(ctx context.Context, in *PublishContractRequest, opts ...grpc.CallOption) (*PublishContractResponse, error)
+	List(ctx context.Context, in *ListContractsRequest, opts ...grpc.CallOption) (*ListContractsResponse, error)
+	Subscribers(ctx context.Context, in *ListSubscribersRequest, opts ...grpc.CallOption) (*ListSubscribersResponse, error)
+}
+
+type contractsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewContractsClient(cc *grpc.ClientConn) ContractsClient {
+	return &contractsClient{cc}
+}
+
+func (c *contractsClient) Publish(ctx context.Context, in *PublishContractRequest, opts ...grpc.CallOption) (*PublishContractResponse, error) {
+	out := new(PublishContractResponse)
+	err := c.cc.Invoke(ctx, "/events.Contracts/Publish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractsClient) List(ctx context.Context, in *ListContractsRequest, opts ...grpc.CallOption) (*ListContractsResponse, error) {
+	out := new(ListContractsResponse)
+	err := c.cc.Invoke(ctx, "/events.Contracts/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractsClient) Subscribers(ctx context.Context, in *ListSubscribersRequest, opts ...grpc.CallOption) (*ListSubscribersResponse, error) {
+	out := new(ListSubscribersResponse)
+	err := c.cc.Invoke(ctx, "/events.Contracts/Subscribers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ContractsServer is the server API for Contracts service.
+type ContractsServer interface {
+	Publish(context.Context, *PublishContractRequest) (*PublishContractResponse, error)
+	List(context.Context, *ListContractsRequest) (*ListContractsResponse, error)
+	Subscribers(context.Context, *ListSubscribersRequest) (*ListSubscribersResponse, error)
+}
+
+// UnimplementedContractsServer can be embedded to have forward compatible implementations.
+type UnimplementedContractsServer struct {
+}
+
+func (*UnimplementedContractsServer) Publish(ctx context.Context, req *PublishContractRequest) (*PublishContractResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Publish not implemented")
+}
+func (*UnimplementedContractsServer) List(ctx context.Context, req *ListContractsRequest) (*ListContractsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedContractsServer) Subscribers(ctx context.Context, req *ListSubscribersRequest) (*ListSubscribersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribers not implemented")
+}
+
+func RegisterContractsServer(s *grpc.Server, srv ContractsServer) {
+	s.RegisterService(&_Contracts_serviceDesc, srv)
+}
+
+func _Contracts_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishContractRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractsServer).Publish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/events.Contracts/Publish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractsServer).Publish(ctx, req.(*PublishContractRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Contracts_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListContractsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/events.Contracts/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractsServer).List(ctx, req.(*ListContractsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Contracts_Subscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscribersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractsServer).Subscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/events.Contracts/Subscribers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractsServer).Subscribers(ctx, req.(*ListSubscribersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Contracts_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "events.Contracts",
+	HandlerType: (*ContractsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Publish",
+			Handler:    _Contracts_Publish_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Contracts_List_Handler,
+		},
+		{
+			MethodName: "Subscribers",
+			Handler:    _Contracts_Subscribers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service/events/proto/events.proto",
+}
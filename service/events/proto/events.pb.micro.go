@@ -259,3 +259,96 @@ func (h *storeHandler) Read(ctx context.Context, in *ReadRequest, out *ReadRespo
 func (h *storeHandler) Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error {
 	return h.StoreHandler.Write(ctx, in, out)
 }
+
+// Api Endpoints for Contracts service
+
+func NewContractsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Contracts service
+
+type ContractsService interface {
+	Publish(ctx context.Context, in *PublishContractRequest, opts ...client.CallOption) (*PublishContractResponse, error)
+	List(ctx context.Context, in *ListContractsRequest, opts ...client.CallOption) (*ListContractsResponse, error)
+	Subscribers(ctx context.Context, in *ListSubscribersRequest, opts ...client.CallOption) (*ListSubscribersResponse, error)
+}
+
+type contractsService struct {
+	c    client.Client
+	name string
+}
+
+func NewContractsService(name string, c client.Client) ContractsService {
+	return &contractsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *contractsService) Publish(ctx context.Context, in *PublishContractRequest, opts ...client.CallOption) (*PublishContractResponse, error) {
+	req := c.c.NewRequest(c.name, "Contracts.Publish", in)
+	out := new(PublishContractResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractsService) List(ctx context.Context, in *ListContractsRequest, opts ...client.CallOption) (*ListContractsResponse, error) {
+	req := c.c.NewRequest(c.name, "Contracts.List", in)
+	out := new(ListContractsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractsService) Subscribers(ctx context.Context, in *ListSubscribersRequest, opts ...client.CallOption) (*ListSubscribersResponse, error) {
+	req := c.c.NewRequest(c.name, "Contracts.Subscribers", in)
+	out := new(ListSubscribersResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Contracts service
+
+type ContractsHandler interface {
+	Publish(context.Context, *PublishContractRequest, *PublishContractResponse) error
+	List(context.Context, *ListContractsRequest, *ListContractsResponse) error
+	Subscribers(context.Context, *ListSubscribersRequest, *ListSubscribersResponse) error
+}
+
+func RegisterContractsHandler(s server.Server, hdlr ContractsHandler, opts ...server.HandlerOption) error {
+	type contracts interface {
+		Publish(ctx context.Context, in *PublishContractRequest, out *PublishContractResponse) error
+		List(ctx context.Context, in *ListContractsRequest, out *ListContractsResponse) error
+		Subscribers(ctx context.Context, in *ListSubscribersRequest, out *ListSubscribersResponse) error
+	}
+	type Contracts struct {
+		contracts
+	}
+	h := &contractsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Contracts{h}, opts...))
+}
+
+type contractsHandler struct {
+	ContractsHandler
+}
+
+func (h *contractsHandler) Publish(ctx context.Context, in *PublishContractRequest, out *PublishContractResponse) error {
+	return h.ContractsHandler.Publish(ctx, in, out)
+}
+
+func (h *contractsHandler) List(ctx context.Context, in *ListContractsRequest, out *ListContractsResponse) error {
+	return h.ContractsHandler.List(ctx, in, out)
+}
+
+func (h *contractsHandler) Subscribers(ctx context.Context, in *ListSubscribersRequest, out *ListSubscribersResponse) error {
+	return h.ContractsHandler.Subscribers(ctx, in, out)
+}
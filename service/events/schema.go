@@ -0,0 +1,66 @@
+package events
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[string]string{}
+)
+
+// RegisterSchema registers the JSON schema of the message published to the topic, so the events
+// on it can be documented and validated. It's called by the publishers generated by
+// protoc-gen-micro for the messages marked as events.
+func RegisterSchema(topic string, msg proto.Message) {
+	b, err := json.Marshal(util.MessageSchema(proto.MessageV2(msg).ProtoReflect().Descriptor()))
+	if err != nil {
+		return
+	}
+
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	schemas[topic] = string(b)
+}
+
+// Schema returns the encoded JSON schema of the messages published to the topic, false if no
+// schema was registered
+func Schema(topic string) (string, bool) {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	s, ok := schemas[topic]
+	return s, ok
+}
+
+// Topics returns the topics which have a schema registered
+func Topics() []string {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+
+	topics := make([]string, 0, len(schemas))
+	for t := range schemas {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Validate returns an error if the payload doesn't match the schema registered for the topic.
+// Payloads published to topics without a schema are valid.
+func Validate(topic string, payload []byte) error {
+	raw, ok := Schema(topic)
+	if !ok {
+		return nil
+	}
+
+	var s util.Schema
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		return err
+	}
+	return util.Validate(&s, payload)
+}
@@ -0,0 +1,323 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goevents "github.com/micro/go-micro/v3/events"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/events/proto"
+	eutil "github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	contractPrefix   = "contract/"
+	subscriberPrefix = "subscriber/"
+)
+
+var (
+	// contractTTL is how long the latest contract of a topic is cached for when publishing
+	contractTTL = time.Second * 10
+
+	contractsMtx sync.RWMutex
+	contracts    = map[string]cachedContracts{}
+
+	// namespaceTopics isolates the topics of each namespace by publishing the events of
+	// namespaces other than the default to topics prefixed with the namespace
+	namespaceTopics bool
+)
+
+type cachedContracts struct {
+	versions []*pb.Contract
+	expires  time.Time
+}
+
+type evContracts struct{}
+
+// Publish a version of a contract, sharing the topic with other namespaces. A version can't be
+// changed once published, publishing it again with the same schema is a no-op.
+func (c *evContracts) Publish(ctx context.Context, req *pb.PublishContractRequest, rsp *pb.PublishContractResponse) error {
+	ns, err := authorizeEvents(ctx, "events.Contracts.Publish")
+	if err != nil {
+		return err
+	}
+	if len(req.Topic) == 0 {
+		return errors.BadRequest("events.Contracts.Publish", "Missing topic")
+	}
+	if req.Version < 1 {
+		return errors.BadRequest("events.Contracts.Publish", "Version must be at least 1")
+	}
+	var schema util.Schema
+	if err := json.Unmarshal([]byte(req.Schema), &schema); err != nil {
+		return errors.BadRequest("events.Contracts.Publish", "Invalid schema: %v", err)
+	}
+
+	versions, err := readContracts(ns, req.Topic)
+	if err != nil {
+		return errors.InternalServerError("events.Contracts.Publish", "Error reading contracts: %v", err)
+	}
+	for _, v := range versions {
+		if v.Version != req.Version {
+			continue
+		}
+		if v.Schema != req.Schema {
+			return errors.Conflict("events.Contracts.Publish", "Version %v of the contract is frozen", req.Version)
+		}
+		rsp.Contract = v
+		return nil
+	}
+	if n := len(versions); n > 0 && versions[n-1].Version > req.Version {
+		return errors.BadRequest("events.Contracts.Publish", "Version %v is older than the latest version %v", req.Version, versions[n-1].Version)
+	}
+
+	contract := &pb.Contract{
+		Namespace:   ns,
+		Topic:       req.Topic,
+		Version:     req.Version,
+		Schema:      req.Schema,
+		Description: req.Description,
+		Created:     time.Now().Unix(),
+	}
+	b, err := json.Marshal(contract)
+	if err != nil {
+		return errors.InternalServerError("events.Contracts.Publish", "Error encoding contract: %v", err)
+	}
+	if err := store.Write(&gostore.Record{Key: contractKey(ns, req.Topic, req.Version), Value: b}); err != nil {
+		return errors.InternalServerError("events.Contracts.Publish", "Error writing contract: %v", err)
+	}
+
+	contractsMtx.Lock()
+	delete(contracts, ns+"/"+req.Topic)
+	contractsMtx.Unlock()
+
+	rsp.Contract = contract
+	return nil
+}
+
+// List the contracts which have been published. Contracts are public, so any account can list
+// them.
+func (c *evContracts) List(ctx context.Context, req *pb.ListContractsRequest, rsp *pb.ListContractsResponse) error {
+	if _, err := authorizeEvents(ctx, "events.Contracts.List"); err != nil {
+		return err
+	}
+
+	prefix := contractPrefix
+	if len(req.Namespace) > 0 {
+		prefix += req.Namespace + "/"
+	}
+	recs, err := store.Read(prefix, gostore.ReadPrefix())
+	if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("events.Contracts.List", "Error reading contracts: %v", err)
+	}
+
+	rsp.Contracts = make([]*pb.Contract, 0, len(recs))
+	for _, r := range recs {
+		var contract *pb.Contract
+		if err := json.Unmarshal(r.Value, &contract); err != nil {
+			return errors.InternalServerError("events.Contracts.List", "Error decoding contract: %v", err)
+		}
+		rsp.Contracts = append(rsp.Contracts, contract)
+	}
+	return nil
+}
+
+// Subscribers lists the namespaces subscribed to a topic shared by the caller's namespace
+func (c *evContracts) Subscribers(ctx context.Context, req *pb.ListSubscribersRequest, rsp *pb.ListSubscribersResponse) error {
+	ns, err := authorizeEvents(ctx, "events.Contracts.Subscribers")
+	if err != nil {
+		return err
+	}
+	if len(req.Topic) == 0 {
+		return errors.BadRequest("events.Contracts.Subscribers", "Missing topic")
+	}
+
+	recs, err := store.Read(subscriberPrefix+ns+"/"+req.Topic+"/", gostore.ReadPrefix())
+	if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("events.Contracts.Subscribers", "Error reading subscribers: %v", err)
+	}
+
+	rsp.Subscribers = make([]*pb.Subscriber, 0, len(recs))
+	for _, r := range recs {
+		var sub *pb.Subscriber
+		if err := json.Unmarshal(r.Value, &sub); err != nil {
+			return errors.InternalServerError("events.Contracts.Subscribers", "Error decoding subscriber: %v", err)
+		}
+		rsp.Subscribers = append(rsp.Subscribers, sub)
+	}
+	return nil
+}
+
+// authorizeEvents returns the namespace of the caller, checking it has access to it
+func authorizeEvents(ctx context.Context, method string) (string, error) {
+	ns := namespace.FromContext(ctx)
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
+		return "", errors.Forbidden(method, err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return "", errors.Unauthorized(method, err.Error())
+	} else if err != nil {
+		return "", errors.InternalServerError(method, err.Error())
+	}
+	return ns, nil
+}
+
+// namespaceTopic returns the topic events are published to for a namespace's topic. Topics are
+// only prefixed once namespace topics are enabled, and the topics of the default namespace and
+// system topics never are, so existing consumers keep receiving the events.
+func namespaceTopic(ns, topic string) string {
+	if !namespaceTopics || ns == namespace.DefaultNamespace || eutil.SystemTopic(topic) {
+		return topic
+	}
+	return ns + "." + topic
+}
+
+func contractKey(ns, topic string, version int64) string {
+	// versions are zero padded so they're listed in order
+	return fmt.Sprintf("%v%v/%v/%020d", contractPrefix, ns, topic, version)
+}
+
+// readContracts returns the versions of the contract of a topic, oldest first
+func readContracts(ns, topic string) ([]*pb.Contract, error) {
+	recs, err := store.Read(contractPrefix+ns+"/"+topic+"/", gostore.ReadPrefix())
+	if err == gostore.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var versions []*pb.Contract
+	for _, r := range recs {
+		// topics can contain slashes, so check the key is for this topic and not a sub topic
+		if strings.Contains(strings.TrimPrefix(r.Key, contractPrefix+ns+"/"+topic+"/"), "/") {
+			continue
+		}
+		var contract *pb.Contract
+		if err := json.Unmarshal(r.Value, &contract); err != nil {
+			return nil, err
+		}
+		versions = append(versions, contract)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// readContract returns a version of the contract of a topic, the latest if the version is zero
+// and nil if there is no such contract
+func readContract(ns, topic string, version int64) (*pb.Contract, error) {
+	versions, err := readContracts(ns, topic)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	if version == 0 {
+		return versions[len(versions)-1], nil
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// topicContracts returns the versions of the contract of a topic, oldest first and none if it
+// isn't shared. The result is cached since it's checked on every publish.
+func topicContracts(ns, topic string) ([]*pb.Contract, error) {
+	key := ns + "/" + topic
+
+	contractsMtx.RLock()
+	c, ok := contracts[key]
+	contractsMtx.RUnlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.versions, nil
+	}
+
+	versions, err := readContracts(ns, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	contractsMtx.Lock()
+	contracts[key] = cachedContracts{versions: versions, expires: time.Now().Add(contractTTL)}
+	contractsMtx.Unlock()
+	return versions, nil
+}
+
+// matchContracts returns the versions of the contract the payload of an event matches. The
+// payload must match the latest version, older versions it matches are included so the
+// subscribers pinned to them receive the event.
+func matchContracts(versions []*pb.Contract, payload []byte) ([]int64, error) {
+	latest := versions[len(versions)-1]
+	if err := validateContract(latest, payload); err != nil {
+		return nil, fmt.Errorf("Event doesn't match version %v of the contract: %v", latest.Version, err)
+	}
+
+	matched := []int64{latest.Version}
+	for i := len(versions) - 2; i >= 0; i-- {
+		if err := validateContract(versions[i], payload); err == nil {
+			matched = append(matched, versions[i].Version)
+		}
+	}
+	return matched, nil
+}
+
+// formatVersions encodes the versions of the contract an event matches as metadata
+func formatVersions(versions []int64) string {
+	strs := make([]string, len(versions))
+	for i, v := range versions {
+		strs[i] = strconv.FormatInt(v, 10)
+	}
+	return strings.Join(strs, ",")
+}
+
+// matchesVersion returns true if an event was validated against the version of the contract
+func matchesVersion(ev *goevents.Event, version int64) bool {
+	for _, v := range strings.Split(ev.Metadata[eutil.ContractVersionsKey], ",") {
+		if v == strconv.FormatInt(version, 10) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateContract checks the payload of an event matches the contract
+func validateContract(contract *pb.Contract, payload []byte) error {
+	var schema util.Schema
+	if err := json.Unmarshal([]byte(contract.Schema), &schema); err != nil {
+		return err
+	}
+	return util.Validate(&schema, payload)
+}
+
+// recordSubscriber adds the namespace to the subscribers of the contract, keeping the time it
+// first subscribed
+func recordSubscriber(contract *pb.Contract, ns, queue string) error {
+	key := strings.Join([]string{subscriberPrefix + contract.Namespace, contract.Topic, ns, queue}, "/")
+
+	now := time.Now().Unix()
+	sub := &pb.Subscriber{Namespace: ns, Queue: queue, Version: contract.Version, Subscribed: now, LastSeen: now}
+	if recs, err := store.Read(key); err == nil {
+		var existing *pb.Subscriber
+		if err := json.Unmarshal(recs[0].Value, &existing); err == nil {
+			sub.Subscribed = existing.Subscribed
+		}
+	}
+
+	b, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: key, Value: b})
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	goevents "github.com/micro/go-micro/v3/events"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/events/proto"
+	"github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestContracts(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	c := new(evContracts)
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Issuer: "acme"})
+	ctx = namespace.ContextWithNamespace(ctx, "acme")
+
+	v1 := `{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}}`
+	publish := func(version int64, schema string) error {
+		return c.Publish(ctx, &pb.PublishContractRequest{Topic: "orders", Version: version, Schema: schema}, &pb.PublishContractResponse{})
+	}
+
+	if err := publish(1, v1); err != nil {
+		t.Fatalf("Unexpected error publishing the contract: %v", err)
+	}
+	if err := publish(1, v1); err != nil {
+		t.Fatalf("Expected publishing the same version again to succeed, got %v", err)
+	}
+	if verr := errors.Parse(publish(1, `{"type": "object"}`)); verr == nil || verr.Code != 409 {
+		t.Fatalf("Expected the version to be frozen, got %v", verr)
+	}
+	if err := publish(2, `{"type": "object"}`); err != nil {
+		t.Fatalf("Unexpected error publishing a new version: %v", err)
+	}
+	if err := publish(1, v1); err != nil {
+		t.Fatalf("Expected republishing an old version to be a no-op, got %v", err)
+	}
+
+	contract, err := readContract("acme", "orders", 1)
+	if err != nil || contract == nil || contract.Schema != v1 {
+		t.Fatalf("Expected version 1 of the contract, got %v: %v", contract, err)
+	}
+	if err := validateContract(contract, []byte(`{"id": 1}`)); err == nil {
+		t.Errorf("Expected an event with a numeric id to be invalid")
+	}
+	if latest, _ := readContract("acme", "orders", 0); latest == nil || latest.Version != 2 {
+		t.Errorf("Expected the latest version to be 2, got %v", latest)
+	}
+
+	// subscribers are visible to the producer
+	if err := recordSubscriber(contract, "widgets", "billing"); err != nil {
+		t.Fatalf("Unexpected error recording the subscriber: %v", err)
+	}
+	var rsp pb.ListSubscribersResponse
+	if err := c.Subscribers(ctx, &pb.ListSubscribersRequest{Topic: "orders"}, &rsp); err != nil {
+		t.Fatalf("Unexpected error listing subscribers: %v", err)
+	}
+	if len(rsp.Subscribers) != 1 || rsp.Subscribers[0].Namespace != "widgets" || rsp.Subscribers[0].Version != 1 {
+		t.Errorf("Expected the widgets namespace to be subscribed, got %v", rsp.Subscribers)
+	}
+}
+
+func TestMatchContracts(t *testing.T) {
+	versions := []*pb.Contract{
+		{Version: 1, Schema: `{"type": "object", "properties": {"id": {"type": "boolean"}}}`},
+		{Version: 2, Schema: `{"type": "object", "properties": {"id": {"type": "integer"}}}`},
+	}
+
+	// events must match the latest version
+	if _, err := matchContracts(versions, []byte(`{"id": true}`)); err == nil {
+		t.Fatalf("Expected an event which only matches version 1 to be rejected")
+	}
+
+	// subscribers pinned to version 1 only receive the events which also match it
+	matched, err := matchContracts(versions, []byte(`{"id": 1}`))
+	if err != nil {
+		t.Fatalf("Unexpected error matching the event: %v", err)
+	}
+	ev := &goevents.Event{Metadata: map[string]string{util.ContractVersionsKey: formatVersions(matched)}}
+	if !matchesVersion(ev, 2) || matchesVersion(ev, 1) {
+		t.Errorf("Expected the event to only match version 2, got %v", matched)
+	}
+
+	matched, err = matchContracts(versions, []byte(`{"ref": "foo"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error matching the event: %v", err)
+	}
+	ev = &goevents.Event{Metadata: map[string]string{util.ContractVersionsKey: formatVersions(matched)}}
+	if !matchesVersion(ev, 2) || !matchesVersion(ev, 1) {
+		t.Errorf("Expected the event to match both versions, got %v", matched)
+	}
+}
+
+func TestNamespaceTopic(t *testing.T) {
+	defer func(b bool) { namespaceTopics = b }(namespaceTopics)
+
+	// topics are unchanged until namespace topics are enabled
+	namespaceTopics = false
+	if topic := namespaceTopic("acme", "orders"); topic != "orders" {
+		t.Errorf("Expected the topic to be unchanged, got %v", topic)
+	}
+
+	namespaceTopics = true
+	if topic := namespaceTopic("acme", "orders"); topic != "acme.orders" {
+		t.Errorf("Expected the topic to be prefixed with the namespace, got %v", topic)
+	}
+	if topic := namespaceTopic(namespace.DefaultNamespace, "orders"); topic != "orders" {
+		t.Errorf("Expected the topic of the default namespace to be unchanged, got %v", topic)
+	}
+	if topic := namespaceTopic("acme", util.SystemTopicPrefix+"cache"); topic != util.SystemTopicPrefix+"cache" {
+		t.Errorf("Expected system topics to be unchanged, got %v", topic)
+	}
+}
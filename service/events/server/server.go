@@ -11,10 +11,23 @@ import (
 	"github.com/micro/micro/v3/service/logger"
 )
 
-var systemTopics = []string{"runtime"}
+var (
+	systemTopics = []string{"runtime"}
+
+	// Flags specific to the events service
+	Flags = []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "namespace_topics",
+			EnvVars: []string{"MICRO_EVENTS_NAMESPACE_TOPICS"},
+			Usage:   "Publish the events of namespaces other than the default to topics prefixed with the namespace, isolating them. Consumers subscribing to the topics directly must move to the events service first.",
+		},
+	}
+)
 
 // Run the micro broker
 func Run(ctx *cli.Context) error {
+	namespaceTopics = ctx.Bool("namespace_topics")
+
 	// new service
 	srv := service.New(
 		service.Name("events"),
@@ -23,6 +36,7 @@ func Run(ctx *cli.Context) error {
 	// register the handlers
 	pb.RegisterStreamHandler(srv.Server(), new(evStream))
 	pb.RegisterStoreHandler(srv.Server(), new(evStore))
+	pb.RegisterContractsHandler(srv.Server(), new(evContracts))
 
 	// subscribe to the system topics
 	for _, topic := range systemTopics {
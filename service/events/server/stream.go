@@ -2,26 +2,27 @@ package server
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	goevents "github.com/micro/go-micro/v3/events"
+	gostore "github.com/micro/go-micro/v3/store"
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/events"
 	pb "github.com/micro/micro/v3/service/events/proto"
 	"github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
 )
 
 type evStream struct{}
 
 func (s *evStream) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.PublishResponse) error {
 	// authorize the request
-	if err := namespace.Authorize(ctx, namespace.DefaultNamespace); err == namespace.ErrForbidden {
-		return errors.Forbidden("events.Stream.Publish", err.Error())
-	} else if err == namespace.ErrUnauthorized {
-		return errors.Unauthorized("events.Stream.Publish", err.Error())
-	} else if err != nil {
-		return errors.InternalServerError("events.Stream.Publish", err.Error())
+	ns, err := authorizeEvents(ctx, "events.Stream.Publish")
+	if err != nil {
+		return err
 	}
 
 	// validate the request
@@ -29,17 +30,45 @@ func (s *evStream) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.
 		return errors.BadRequest("events.Stream.Publish", goevents.ErrMissingTopic.Error())
 	}
 
+	// subscribers can trust the namespace of the publisher since it's set here
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string)
+	}
+	req.Metadata[util.PublisherNamespaceKey] = ns
+
+	// events on topics shared with other namespaces must match the latest contract, the older
+	// versions they match are recorded so they're routed to the subscribers pinned to them
+	versions, err := topicContracts(ns, req.Topic)
+	if err != nil {
+		return errors.InternalServerError("events.Stream.Publish", "Error reading contract: %v", err)
+	}
+	if len(versions) > 0 {
+		matched, err := matchContracts(versions, req.Payload)
+		if err != nil {
+			return errors.BadRequest("events.Stream.Publish", err.Error())
+		}
+		req.Metadata[util.ContractVersionKey] = strconv.FormatInt(matched[0], 10)
+		req.Metadata[util.ContractVersionsKey] = formatVersions(matched)
+	}
+
+	// consumers record their history by the sequence of the event in the topic, which is taken
+	// from a counter in the store since the stream doesn't expose its own
+	delete(req.Metadata, util.StreamSequenceKey)
+	if seq, err := store.Increment(req.Topic, 1, gostore.WriteTo(ns, events.DefaultSequenceTable)); err != nil {
+		logger.Errorf("Error sequencing event on topic %v: %v", req.Topic, err)
+	} else {
+		req.Metadata[util.StreamSequenceKey] = strconv.FormatInt(seq, 10)
+	}
+
 	// parse options
 	var opts []goevents.PublishOption
 	if req.Timestamp > 0 {
 		opts = append(opts, goevents.WithTimestamp(time.Unix(req.Timestamp, 0)))
 	}
-	if req.Metadata != nil {
-		opts = append(opts, goevents.WithMetadata(req.Metadata))
-	}
+	opts = append(opts, goevents.WithMetadata(req.Metadata))
 
 	// publish the event
-	if err := events.Publish(req.Topic, req.Payload, opts...); err != nil {
+	if err := events.Publish(namespaceTopic(ns, req.Topic), req.Payload, opts...); err != nil {
 		return errors.InternalServerError("events.Stream.Publish", err.Error())
 	}
 
@@ -48,12 +77,9 @@ func (s *evStream) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.
 
 func (s *evStream) Subscribe(ctx context.Context, req *pb.SubscribeRequest, rsp pb.Stream_SubscribeStream) error {
 	// authorize the request
-	if err := namespace.Authorize(ctx, namespace.DefaultNamespace); err == namespace.ErrForbidden {
-		return errors.Forbidden("events.Stream.Publish", err.Error())
-	} else if err == namespace.ErrUnauthorized {
-		return errors.Unauthorized("events.Stream.Publish", err.Error())
-	} else if err != nil {
-		return errors.InternalServerError("events.Stream.Publish", err.Error())
+	ns, err := authorizeEvents(ctx, "events.Stream.Subscribe")
+	if err != nil {
+		return err
 	}
 
 	// parse options
@@ -61,12 +87,41 @@ func (s *evStream) Subscribe(ctx context.Context, req *pb.SubscribeRequest, rsp
 	if req.StartAtTime > 0 {
 		opts = append(opts, goevents.WithStartAtTime(time.Unix(req.StartAtTime, 0)))
 	}
-	if len(req.Queue) > 0 {
-		opts = append(opts, goevents.WithQueue(req.Queue))
+
+	// system topics receive the events of every namespace so only the default namespace can
+	// subscribe to them
+	if util.SystemTopic(req.Topic) && ns != namespace.DefaultNamespace {
+		return errors.Forbidden("events.Stream.Subscribe", "Only the %v namespace can subscribe to system topics", namespace.DefaultNamespace)
+	}
+
+	// topics of other namespaces can only be subscribed to if they're shared with a contract.
+	// Queues are prefixed with the subscriber's namespace so they're not shared with the
+	// producer's own consumers.
+	topic := namespaceTopic(ns, req.Topic)
+	queue := req.Queue
+	var version int64
+	if len(req.Namespace) > 0 && req.Namespace != ns {
+		contract, err := readContract(req.Namespace, req.Topic, req.ContractVersion)
+		if err != nil {
+			return errors.InternalServerError("events.Stream.Subscribe", "Error reading contract: %v", err)
+		} else if contract == nil {
+			return errors.NotFound("events.Stream.Subscribe", "Topic %v isn't shared by namespace %v", req.Topic, req.Namespace)
+		}
+		if err := recordSubscriber(contract, ns, req.Queue); err != nil {
+			return errors.InternalServerError("events.Stream.Subscribe", "Error recording subscriber: %v", err)
+		}
+		topic = namespaceTopic(req.Namespace, req.Topic)
+		version = req.ContractVersion
+		if len(queue) > 0 {
+			queue = ns + "." + queue
+		}
+	}
+	if len(queue) > 0 {
+		opts = append(opts, goevents.WithQueue(queue))
 	}
 
 	// create the subscriber
-	evChan, err := events.Subscribe(req.Topic, opts...)
+	evChan, err := events.Subscribe(topic, opts...)
 	if err != nil {
 		return errors.InternalServerError("events.Stream.Subscribe", err.Error())
 	}
@@ -78,6 +133,15 @@ func (s *evStream) Subscribe(ctx context.Context, req *pb.SubscribeRequest, rsp
 			return nil
 		}
 
+		// subscribers pinned to a version of the contract only receive the events which
+		// match it
+		if version > 0 && !matchesVersion(&ev, version) {
+			continue
+		}
+
+		// events are published to topics prefixed with the namespace, which subscribers don't
+		// need to know about
+		ev.Topic = req.Topic
 		if err := rsp.Send(util.SerializeEvent(&ev)); err != nil {
 			return err
 		}
@@ -1,12 +1,51 @@
 package util
 
 import (
+	"strings"
 	"time"
 
 	"github.com/micro/go-micro/v3/events"
 	pb "github.com/micro/micro/v3/service/events/proto"
 )
 
+// Metadata keys the causality of events is recorded with
+const (
+	// SequenceKey is the sequence of the event amongst those published by its producer
+	SequenceKey = "Micro-Sequence"
+	// ProducerKey is the id of the process which published the event
+	ProducerKey = "Micro-Producer"
+	// CorrelationKey is the id shared by every event in a causal chain
+	CorrelationKey = "Micro-Correlation-Id"
+	// CausationKey is the id of the event or request which caused the event
+	CausationKey = "Micro-Causation-Id"
+)
+
+// StreamSequenceKey is the metadata key the events service sets to the sequence of the event
+// amongst those published to its topic, overwriting any value set by the publisher
+const StreamSequenceKey = "Micro-Stream-Sequence"
+
+// ContractVersionKey is the metadata key set to the version of the contract the payload of an
+// event shared with other namespaces was validated against
+const ContractVersionKey = "Micro-Contract-Version"
+
+// ContractVersionsKey is the metadata key set to the comma separated versions of the contract
+// the payload of an event shared with other namespaces matches, latest first
+const ContractVersionsKey = "Micro-Contract-Versions"
+
+// PublisherNamespaceKey is the metadata key the events service sets to the namespace of the
+// account which published the event, overwriting any value set by the publisher
+const PublisherNamespaceKey = "Micro-Publisher-Namespace"
+
+// SystemTopicPrefix prefixes the topics shared by every namespace. Events published to them by
+// any namespace are received by the subscribers in the default namespace, which can tell the
+// namespaces apart by the PublisherNamespaceKey.
+const SystemTopicPrefix = "micro."
+
+// SystemTopic returns true if the topic is shared by every namespace
+func SystemTopic(topic string) bool {
+	return strings.HasPrefix(topic, SystemTopicPrefix)
+}
+
 func SerializeEvent(ev *events.Event) *pb.Event {
 	return &pb.Event{
 		Id:        ev.ID,
@@ -33,6 +33,14 @@ var Flags = []cli.Flag{
 
 // Run micro health
 func Run(ctx *cli.Context) error {
+	// check the health of the dependencies of the services
+	if ctx.Args().First() == "deps" {
+		util.Print(func(c *cli.Context, args []string) ([]byte, error) {
+			return qcli.QueryDependencyHealth(c, args[1:])
+		})(ctx)
+		return nil
+	}
+
 	// just check service health
 	if ctx.Args().Len() > 0 {
 		util.Print(qcli.QueryHealth)(ctx)
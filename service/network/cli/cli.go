@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/micro/cli/v2"
 	goclient "github.com/micro/go-micro/v3/client"
@@ -17,6 +18,7 @@ import (
 	clic "github.com/micro/micro/v3/internal/command"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/network/proto"
 	"github.com/olekukonko/tablewriter"
 )
 
@@ -77,6 +79,32 @@ func init() {
 				Usage:  "Get the network services",
 				Action: util.Print(networkServices),
 			},
+			{
+				Name:      "usage",
+				Usage:     "List the bytes sent and received on the network links by each namespace",
+				ArgsUsage: "[namespace]",
+				Action:    util.Print(networkUsage),
+			},
+			{
+				Name:      "test",
+				Usage:     "Measure the latency, loss and throughput of the link to a peer e.g micro network test node-id",
+				ArgsUsage: "[node]",
+				Action:    util.Print(networkTest),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "pings",
+						Usage: "Number of pings to measure the latency and loss with",
+					},
+					&cli.IntFlag{
+						Name:  "size",
+						Usage: "Size in bytes of the messages to measure the throughput with",
+					},
+					&cli.DurationFlag{
+						Name:  "duration",
+						Usage: "How long to measure the throughput for",
+					},
+				},
+			},
 			// TODO: duplicates call. Move so we reuse same stuff.
 			{
 				Name:   "call",
@@ -340,6 +368,88 @@ func networkServices(c *cli.Context, args []string) ([]byte, error) {
 	return []byte(strings.Join(services, "\n")), nil
 }
 
+func networkUsage(c *cli.Context, args []string) ([]byte, error) {
+	var ns string
+	if len(args) > 0 {
+		ns = args[0]
+	}
+
+	srv := pb.NewNetworkService("network", client.DefaultClient)
+	rsp, err := srv.Usage(context.DefaultContext, &pb.UsageRequest{Namespace: ns}, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	b := bytes.NewBuffer(nil)
+	table := tablewriter.NewWriter(b)
+	table.SetHeader([]string{"NAMESPACE", "SENT", "RECEIVED", "LIMIT", "SINCE"})
+
+	for _, u := range rsp.Usage {
+		limit := "none"
+		if u.Limit > 0 {
+			limit = strconv.FormatInt(u.Limit, 10)
+		}
+		table.Append([]string{
+			u.Namespace,
+			strconv.FormatInt(u.Sent, 10),
+			strconv.FormatInt(u.Received, 10),
+			limit,
+			time.Unix(u.Since, 0).Format(time.RFC3339),
+		})
+	}
+
+	// render table into b
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+
+	return b.Bytes(), nil
+}
+
+func networkTest(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Require a node to test e.g micro network test node-id")
+	}
+
+	srv := pb.NewNetworkService("network", client.DefaultClient)
+	req := &pb.TestRequest{
+		Node:     args[0],
+		Pings:    int64(c.Int("pings")),
+		Size:     int64(c.Int("size")),
+		Duration: c.Duration("duration").Milliseconds(),
+	}
+	// the test runs for the duration on top of the pings
+	timeout := goclient.WithRequestTimeout(time.Minute * 2)
+	rsp, err := srv.Test(context.DefaultContext, req, goclient.WithAuthToken(), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var loss float64
+	if rsp.PingsSent > 0 {
+		loss = float64(rsp.PingsSent-rsp.PingsReceived) / float64(rsp.PingsSent) * 100
+	}
+	micros := func(n int64) string {
+		return (time.Duration(n) * time.Microsecond).String()
+	}
+
+	b := bytes.NewBuffer(nil)
+	table := tablewriter.NewWriter(b)
+	table.SetHeader([]string{"NODE", "LINK", "LATENCY (MIN/AVG/MAX)", "LOSS", "THROUGHPUT"})
+	table.Append([]string{
+		rsp.Node,
+		rsp.Link,
+		fmt.Sprintf("%s/%s/%s", micros(rsp.MinLatency), micros(rsp.AvgLatency), micros(rsp.MaxLatency)),
+		fmt.Sprintf("%.1f%% (%d/%d)", loss, rsp.PingsSent-rsp.PingsReceived, rsp.PingsSent),
+		fmt.Sprintf("%.2f MB/s", float64(rsp.Throughput)/(1024*1024)),
+	})
+
+	// render table into b
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+
+	return b.Bytes(), nil
+}
+
 // netCall calls services through the network
 func netCall(c *cli.Context, args []string) ([]byte, error) {
 	os.Setenv("MICRO_PROXY", "network")
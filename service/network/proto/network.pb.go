@@ -547,6 +547,161 @@ func (m *StatusResponse) GetStatus() *Status {
 	return nil
 }
 
+type UsageRequest struct {
+	// namespace to return the usage of, blank for the namespace of the caller
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageRequest) Reset()         { *m = UsageRequest{} }
+func (m *UsageRequest) String() string { return proto.CompactTextString(m) }
+func (*UsageRequest) ProtoMessage()    {}
+func (*UsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_04ea431fa6698cb0, []int{13}
+}
+
+func (m *UsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageRequest.Unmarshal(m, b)
+}
+func (m *UsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageRequest.Marshal(b, m, deterministic)
+}
+func (m *UsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageRequest.Merge(m, src)
+}
+func (m *UsageRequest) XXX_Size() int {
+	return xxx_messageInfo_UsageRequest.Size(m)
+}
+func (m *UsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageRequest proto.InternalMessageInfo
+
+func (m *UsageRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type UsageResponse struct {
+	Usage                []*Usage `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageResponse) Reset()         { *m = UsageResponse{} }
+func (m *UsageResponse) String() string { return proto.CompactTextString(m) }
+func (*UsageResponse) ProtoMessage()    {}
+func (*UsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_04ea431fa6698cb0, []int{14}
+}
+
+func (m *UsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageResponse.Unmarshal(m, b)
+}
+func (m *UsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageResponse.Marshal(b, m, deterministic)
+}
+func (m *UsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageResponse.Merge(m, src)
+}
+func (m *UsageResponse) XXX_Size() int {
+	return xxx_messageInfo_UsageResponse.Size(m)
+}
+func (m *UsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageResponse proto.InternalMessageInfo
+
+func (m *UsageResponse) GetUsage() []*Usage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+// Usage of the network links by a namespace in the current window
+type Usage struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// bytes sent to peers in responses
+	Sent int64 `protobuf:"varint,2,opt,name=sent,proto3" json:"sent,omitempty"`
+	// bytes received from peers in requests
+	Received int64 `protobuf:"varint,3,opt,name=received,proto3" json:"received,omitempty"`
+	// unix timestamp the window started at
+	Since int64 `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+	// bytes the namespace can send in the window, zero if unlimited
+	Limit                int64    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Usage) Reset()         { *m = Usage{} }
+func (m *Usage) String() string { return proto.CompactTextString(m) }
+func (*Usage) ProtoMessage()    {}
+func (*Usage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_04ea431fa6698cb0, []int{15}
+}
+
+func (m *Usage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Usage.Unmarshal(m, b)
+}
+func (m *Usage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Usage.Marshal(b, m, deterministic)
+}
+func (m *Usage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Usage.Merge(m, src)
+}
+func (m *Usage) XXX_Size() int {
+	return xxx_messageInfo_Usage.Size(m)
+}
+func (m *Usage) XXX_DiscardUnknown() {
+	xxx_messageInfo_Usage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Usage proto.InternalMessageInfo
+
+func (m *Usage) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Usage) GetSent() int64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *Usage) GetReceived() int64 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+func (m *Usage) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *Usage) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
 // Error tracks network errors
 type Error struct {
 	Count                uint32   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
@@ -560,7 +715,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{13}
+	return fileDescriptor_04ea431fa6698cb0, []int{16}
 }
 
 func (m *Error) XXX_Unmarshal(b []byte) error {
@@ -607,7 +762,7 @@ func (m *Status) Reset()         { *m = Status{} }
 func (m *Status) String() string { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()    {}
 func (*Status) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{14}
+	return fileDescriptor_04ea431fa6698cb0, []int{17}
 }
 
 func (m *Status) XXX_Unmarshal(b []byte) error {
@@ -656,7 +811,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{15}
+	return fileDescriptor_04ea431fa6698cb0, []int{18}
 }
 
 func (m *Node) XXX_Unmarshal(b []byte) error {
@@ -725,7 +880,7 @@ func (m *Connect) Reset()         { *m = Connect{} }
 func (m *Connect) String() string { return proto.CompactTextString(m) }
 func (*Connect) ProtoMessage()    {}
 func (*Connect) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{16}
+	return fileDescriptor_04ea431fa6698cb0, []int{19}
 }
 
 func (m *Connect) XXX_Unmarshal(b []byte) error {
@@ -766,7 +921,7 @@ func (m *Close) Reset()         { *m = Close{} }
 func (m *Close) String() string { return proto.CompactTextString(m) }
 func (*Close) ProtoMessage()    {}
 func (*Close) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{17}
+	return fileDescriptor_04ea431fa6698cb0, []int{20}
 }
 
 func (m *Close) XXX_Unmarshal(b []byte) error {
@@ -809,7 +964,7 @@ func (m *Peer) Reset()         { *m = Peer{} }
 func (m *Peer) String() string { return proto.CompactTextString(m) }
 func (*Peer) ProtoMessage()    {}
 func (*Peer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{18}
+	return fileDescriptor_04ea431fa6698cb0, []int{21}
 }
 
 func (m *Peer) XXX_Unmarshal(b []byte) error {
@@ -859,7 +1014,7 @@ func (m *Sync) Reset()         { *m = Sync{} }
 func (m *Sync) String() string { return proto.CompactTextString(m) }
 func (*Sync) ProtoMessage()    {}
 func (*Sync) Descriptor() ([]byte, []int) {
-	return fileDescriptor_04ea431fa6698cb0, []int{19}
+	return fileDescriptor_04ea431fa6698cb0, []int{22}
 }
 
 func (m *Sync) XXX_Unmarshal(b []byte) error {
@@ -894,6 +1049,189 @@ func (m *Sync) GetRoutes() []*proto1.Route {
 	return nil
 }
 
+type TestRequest struct {
+	// id or address of the peer to test the link to
+	Node string `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	// number of pings the latency and loss are measured with
+	Pings int64 `protobuf:"varint,2,opt,name=pings,proto3" json:"pings,omitempty"`
+	// size in bytes of the messages the throughput is measured with
+	Size int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// milliseconds the throughput is measured for
+	Duration             int64    `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TestRequest) Reset()         { *m = TestRequest{} }
+func (m *TestRequest) String() string { return proto.CompactTextString(m) }
+func (*TestRequest) ProtoMessage()    {}
+func (*TestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_04ea431fa6698cb0, []int{23}
+}
+
+func (m *TestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestRequest.Unmarshal(m, b)
+}
+func (m *TestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestRequest.Marshal(b, m, deterministic)
+}
+func (m *TestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestRequest.Merge(m, src)
+}
+func (m *TestRequest) XXX_Size() int {
+	return xxx_messageInfo_TestRequest.Size(m)
+}
+func (m *TestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestRequest proto.InternalMessageInfo
+
+func (m *TestRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *TestRequest) GetPings() int64 {
+	if m != nil {
+		return m.Pings
+	}
+	return 0
+}
+
+func (m *TestRequest) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *TestRequest) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+// TestResponse is the quality of the link to the peer
+type TestResponse struct {
+	Node          string `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Link          string `protobuf:"bytes,2,opt,name=link,proto3" json:"link,omitempty"`
+	PingsSent     int64  `protobuf:"varint,3,opt,name=pings_sent,json=pingsSent,proto3" json:"pings_sent,omitempty"`
+	PingsReceived int64  `protobuf:"varint,4,opt,name=pings_received,json=pingsReceived,proto3" json:"pings_received,omitempty"`
+	// round trip times of the pings in microseconds
+	MinLatency int64 `protobuf:"varint,5,opt,name=min_latency,json=minLatency,proto3" json:"min_latency,omitempty"`
+	AvgLatency int64 `protobuf:"varint,6,opt,name=avg_latency,json=avgLatency,proto3" json:"avg_latency,omitempty"`
+	MaxLatency int64 `protobuf:"varint,7,opt,name=max_latency,json=maxLatency,proto3" json:"max_latency,omitempty"`
+	// bytes sent and acknowledged by the peer while measuring the throughput
+	Bytes int64 `protobuf:"varint,8,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// milliseconds the throughput was measured for
+	Duration int64 `protobuf:"varint,9,opt,name=duration,proto3" json:"duration,omitempty"`
+	// bytes per second
+	Throughput           int64    `protobuf:"varint,10,opt,name=throughput,proto3" json:"throughput,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TestResponse) Reset()         { *m = TestResponse{} }
+func (m *TestResponse) String() string { return proto.CompactTextString(m) }
+func (*TestResponse) ProtoMessage()    {}
+func (*TestResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_04ea431fa6698cb0, []int{24}
+}
+
+func (m *TestResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestResponse.Unmarshal(m, b)
+}
+func (m *TestResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestResponse.Marshal(b, m, deterministic)
+}
+func (m *TestResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestResponse.Merge(m, src)
+}
+func (m *TestResponse) XXX_Size() int {
+	return xxx_messageInfo_TestResponse.Size(m)
+}
+func (m *TestResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestResponse proto.InternalMessageInfo
+
+func (m *TestResponse) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *TestResponse) GetLink() string {
+	if m != nil {
+		return m.Link
+	}
+	return ""
+}
+
+func (m *TestResponse) GetPingsSent() int64 {
+	if m != nil {
+		return m.PingsSent
+	}
+	return 0
+}
+
+func (m *TestResponse) GetPingsReceived() int64 {
+	if m != nil {
+		return m.PingsReceived
+	}
+	return 0
+}
+
+func (m *TestResponse) GetMinLatency() int64 {
+	if m != nil {
+		return m.MinLatency
+	}
+	return 0
+}
+
+func (m *TestResponse) GetAvgLatency() int64 {
+	if m != nil {
+		return m.AvgLatency
+	}
+	return 0
+}
+
+func (m *TestResponse) GetMaxLatency() int64 {
+	if m != nil {
+		return m.MaxLatency
+	}
+	return 0
+}
+
+func (m *TestResponse) GetBytes() int64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *TestResponse) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *TestResponse) GetThroughput() int64 {
+	if m != nil {
+		return m.Throughput
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Query)(nil), "network.Query")
 	proto.RegisterType((*ConnectRequest)(nil), "network.ConnectRequest")
@@ -908,6 +1246,9 @@ func init() {
 	proto.RegisterType((*ServicesResponse)(nil), "network.ServicesResponse")
 	proto.RegisterType((*StatusRequest)(nil), "network.StatusRequest")
 	proto.RegisterType((*StatusResponse)(nil), "network.StatusResponse")
+	proto.RegisterType((*UsageRequest)(nil), "network.UsageRequest")
+	proto.RegisterType((*UsageResponse)(nil), "network.UsageResponse")
+	proto.RegisterType((*Usage)(nil), "network.Usage")
 	proto.RegisterType((*Error)(nil), "network.Error")
 	proto.RegisterType((*Status)(nil), "network.Status")
 	proto.RegisterType((*Node)(nil), "network.Node")
@@ -916,6 +1257,8 @@ func init() {
 	proto.RegisterType((*Close)(nil), "network.Close")
 	proto.RegisterType((*Peer)(nil), "network.Peer")
 	proto.RegisterType((*Sync)(nil), "network.Sync")
+	proto.RegisterType((*TestRequest)(nil), "network.TestRequest")
+	proto.RegisterType((*TestResponse)(nil), "network.TestResponse")
 }
 
 func init() {
@@ -923,50 +1266,67 @@ func init() {
 }
 
 var fileDescriptor_04ea431fa6698cb0 = []byte{
-	// 677 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x51, 0x4f, 0xdb, 0x3c,
-	0x14, 0x25, 0x69, 0xd2, 0xc2, 0xfd, 0x48, 0xe1, 0xb3, 0x36, 0xc8, 0xb2, 0x17, 0x30, 0x4c, 0x43,
-	0xd3, 0x94, 0x4a, 0x30, 0x04, 0x0c, 0x69, 0xd2, 0x86, 0xd0, 0x9e, 0x86, 0x58, 0x78, 0xdb, 0x5b,
-	0x48, 0x2c, 0xa8, 0xa0, 0x71, 0x71, 0x9c, 0xa2, 0xfe, 0x82, 0xfd, 0xd6, 0xfd, 0x85, 0x3d, 0x4d,
-	0xb6, 0x6f, 0xd2, 0xa4, 0x61, 0x1d, 0x2f, 0x55, 0x8e, 0xcf, 0xb9, 0xd7, 0xf6, 0xbd, 0xe7, 0xba,
-	0xb0, 0x93, 0x33, 0x31, 0x19, 0x26, 0x6c, 0x90, 0x31, 0xf9, 0xc8, 0xc5, 0xdd, 0x60, 0x2c, 0xb8,
-	0xe4, 0x25, 0x0a, 0x35, 0x22, 0x3d, 0x84, 0xc1, 0x76, 0xa9, 0x16, 0xbc, 0x90, 0x4c, 0xa0, 0xd8,
-	0x00, 0xa3, 0xa5, 0x3f, 0x2d, 0x70, 0xbf, 0x17, 0x4c, 0x4c, 0x89, 0x0f, 0x3d, 0x94, 0xfb, 0xd6,
-	0x96, 0xb5, 0xb7, 0x12, 0x95, 0x50, 0x31, 0x71, 0x9a, 0x0a, 0x96, 0xe7, 0xbe, 0x6d, 0x18, 0x84,
-	0x8a, 0xb9, 0x89, 0x25, 0x7b, 0x8c, 0xa7, 0x7e, 0xc7, 0x30, 0x08, 0xc9, 0x06, 0x74, 0xcd, 0x3e,
-	0xbe, 0xa3, 0x09, 0x44, 0x2a, 0x02, 0x4f, 0xe7, 0xbb, 0x26, 0x02, 0x21, 0x3d, 0x84, 0xfe, 0x19,
-	0xcf, 0x32, 0x96, 0xc8, 0x88, 0x3d, 0x14, 0x2c, 0x97, 0x64, 0x07, 0xdc, 0x8c, 0xa7, 0x2c, 0xf7,
-	0xad, 0xad, 0xce, 0xde, 0x7f, 0xfb, 0x5e, 0x58, 0x5e, 0xf3, 0x82, 0xa7, 0x2c, 0x32, 0x1c, 0xfd,
-	0x1f, 0xd6, 0xaa, 0xb0, 0x7c, 0xcc, 0xb3, 0x9c, 0xd1, 0x5d, 0x58, 0x55, 0x8a, 0xbc, 0xcc, 0xf3,
-	0x02, 0xdc, 0x94, 0x8d, 0xe5, 0xad, 0xbe, 0x97, 0x17, 0x19, 0x40, 0x3f, 0x80, 0x87, 0x2a, 0x13,
-	0xf6, 0xbc, 0xed, 0x76, 0x61, 0xf5, 0xab, 0x88, 0xc7, 0xb7, 0x8b, 0x73, 0xef, 0x83, 0x87, 0x2a,
-	0xcc, 0xbd, 0x0d, 0x8e, 0xe0, 0x5c, 0x6a, 0x55, 0x3d, 0xf5, 0x25, 0x63, 0x22, 0xd2, 0x14, 0x3d,
-	0x04, 0x2f, 0x52, 0x35, 0xaa, 0x8e, 0xbd, 0x0b, 0xee, 0x83, 0xea, 0x0c, 0x06, 0xf5, 0xab, 0x20,
-	0xdd, 0xaf, 0xc8, 0x90, 0xf4, 0x08, 0xfa, 0x65, 0x18, 0xee, 0xf5, 0x06, 0x4b, 0x3f, 0xbb, 0x08,
-	0x76, 0x5c, 0xeb, 0xb0, 0x13, 0xba, 0x70, 0x57, 0xa6, 0xc1, 0xe5, 0x8e, 0x34, 0x84, 0xf5, 0xd9,
-	0x12, 0x66, 0x0b, 0x60, 0x19, 0x7d, 0x60, 0xf2, 0xad, 0x44, 0x15, 0xa6, 0x6b, 0xe0, 0x5d, 0xc9,
-	0x58, 0x16, 0x55, 0x82, 0x13, 0xe8, 0x97, 0x0b, 0x18, 0xfe, 0x16, 0xba, 0xb9, 0x5e, 0xc1, 0x5b,
-	0xac, 0x55, 0xb7, 0x40, 0x21, 0xd2, 0x74, 0x00, 0xee, 0xb9, 0x10, 0x5c, 0xa8, 0x8a, 0x26, 0xbc,
-	0xc8, 0x64, 0x59, 0x51, 0x0d, 0xc8, 0x3a, 0x74, 0x46, 0xf9, 0x0d, 0xfa, 0x4f, 0x7d, 0xd2, 0x10,
-	0xba, 0x26, 0x85, 0x2a, 0x14, 0x53, 0xa1, 0xad, 0x42, 0xe9, 0x84, 0x91, 0x21, 0xe9, 0x2f, 0x0b,
-	0x1c, 0xd5, 0x49, 0xd2, 0x07, 0x7b, 0x98, 0xa2, 0xc7, 0xed, 0x61, 0xba, 0xd8, 0xde, 0xa5, 0x59,
-	0x3b, 0x0d, 0xb3, 0x92, 0x23, 0x58, 0x1e, 0x31, 0x19, 0xa7, 0xb1, 0x8c, 0x7d, 0x47, 0x57, 0xf9,
-	0x75, 0xc3, 0x2e, 0xe1, 0x37, 0x64, 0xcf, 0x33, 0x29, 0xa6, 0x51, 0x25, 0xae, 0xd5, 0xc3, 0x5d,
-	0x58, 0x8f, 0xe0, 0x14, 0xbc, 0x46, 0x0e, 0x55, 0x81, 0x3b, 0x36, 0xc5, 0x73, 0xab, 0x4f, 0x55,
-	0xa9, 0x49, 0x7c, 0x5f, 0x30, 0x3c, 0xb6, 0x01, 0x1f, 0xed, 0x63, 0x8b, 0xbe, 0x87, 0x1e, 0x0e,
-	0x85, 0x72, 0x9e, 0x72, 0x6e, 0xcb, 0x79, 0xda, 0xd4, 0x9a, 0xa2, 0xef, 0xc0, 0x3d, 0xbb, 0xe7,
-	0xc6, 0xa5, 0xff, 0xd2, 0x5e, 0x80, 0xa3, 0x3c, 0xfb, 0x0c, 0xa9, 0x9a, 0xa7, 0x31, 0x63, 0x42,
-	0x55, 0xb5, 0xd3, 0x36, 0xbd, 0xe1, 0xe8, 0x25, 0x38, 0x57, 0xd3, 0x2c, 0x51, 0xf9, 0xd4, 0xc2,
-	0x5f, 0x06, 0x44, 0x51, 0x35, 0x5f, 0xdb, 0x0b, 0x7c, 0xbd, 0xff, 0xdb, 0x86, 0xde, 0x05, 0xb6,
-	0xe9, 0xd3, 0xac, 0x0e, 0x9b, 0x55, 0xca, 0xe6, 0x2b, 0x13, 0xf8, 0x6d, 0x02, 0xdf, 0x91, 0x25,
-	0x72, 0x0c, 0xae, 0x9e, 0x63, 0xf2, 0xb2, 0x12, 0xd5, 0xa7, 0x3f, 0xd8, 0x98, 0x5f, 0xae, 0x47,
-	0xea, 0xd7, 0xa5, 0x16, 0x59, 0x7f, 0x93, 0x6a, 0x91, 0x8d, 0x47, 0x88, 0x2e, 0x91, 0x53, 0xe8,
-	0x9a, 0x81, 0x26, 0x33, 0x4d, 0xe3, 0x61, 0x08, 0x36, 0x5b, 0xeb, 0x55, 0xf0, 0x67, 0x58, 0x2e,
-	0x27, 0x98, 0xcc, 0x2e, 0x36, 0x37, 0xe7, 0xc1, 0xab, 0x27, 0x98, 0xfa, 0xfe, 0x38, 0x57, 0x1b,
-	0xf3, 0xde, 0x6c, 0xed, 0xdf, 0x1c, 0x76, 0xba, 0xf4, 0xe5, 0xe4, 0xc7, 0xd1, 0xcd, 0x50, 0xde,
-	0x16, 0xd7, 0x61, 0xc2, 0x47, 0x83, 0xd1, 0x30, 0x11, 0x1c, 0x7f, 0x27, 0x07, 0x83, 0x27, 0xff,
-	0xbb, 0x4e, 0x11, 0x5d, 0x77, 0x35, 0x3c, 0xf8, 0x13, 0x00, 0x00, 0xff, 0xff, 0xfc, 0x26, 0xbe,
-	0x67, 0xe3, 0x06, 0x00, 0x00,
+	// 958 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x56, 0x4b, 0x4f, 0xdb, 0x40,
+	0x10, 0x6e, 0x1e, 0xce, 0x63, 0xc0, 0x81, 0x5a, 0x05, 0x5c, 0xf7, 0x89, 0x01, 0x15, 0x55, 0x55,
+	0x22, 0x41, 0x11, 0x50, 0xa4, 0x4a, 0x2d, 0x42, 0xbd, 0xb4, 0xa8, 0x75, 0xe8, 0xa5, 0x17, 0x64,
+	0x92, 0x55, 0x62, 0x91, 0xd8, 0xc1, 0x5e, 0x07, 0xd2, 0x5b, 0x4f, 0xfd, 0x3d, 0xfd, 0x59, 0x55,
+	0xff, 0x44, 0xf7, 0x31, 0x5e, 0xdb, 0x09, 0x4d, 0xb9, 0x44, 0x3b, 0x33, 0xdf, 0xcc, 0xec, 0xcc,
+	0xce, 0xe7, 0x09, 0x6c, 0x44, 0x24, 0x1c, 0x7b, 0x1d, 0xd2, 0xf2, 0x09, 0xbd, 0x0e, 0xc2, 0xcb,
+	0xd6, 0x28, 0x0c, 0x68, 0x90, 0x48, 0x4d, 0x21, 0x19, 0x55, 0x14, 0xad, 0xf5, 0x04, 0x1d, 0x06,
+	0x31, 0x25, 0x21, 0x82, 0xa5, 0x20, 0xb1, 0xf6, 0xcf, 0x02, 0x68, 0x5f, 0x62, 0x12, 0x4e, 0x0c,
+	0x13, 0xaa, 0x08, 0x37, 0x0b, 0xcf, 0x0b, 0xdb, 0x75, 0x27, 0x11, 0xb9, 0xc5, 0xed, 0x76, 0x43,
+	0x12, 0x45, 0x66, 0x51, 0x5a, 0x50, 0xe4, 0x96, 0x9e, 0x4b, 0xc9, 0xb5, 0x3b, 0x31, 0x4b, 0xd2,
+	0x82, 0xa2, 0xb1, 0x0a, 0x15, 0x99, 0xc7, 0x2c, 0x0b, 0x03, 0x4a, 0xdc, 0x03, 0x6f, 0x67, 0x6a,
+	0xd2, 0x03, 0x45, 0x7b, 0x0f, 0x1a, 0xc7, 0x81, 0xef, 0x93, 0x0e, 0x75, 0xc8, 0x55, 0x4c, 0x22,
+	0x6a, 0x6c, 0x80, 0xe6, 0x07, 0x5d, 0x12, 0xb1, 0xfb, 0x94, 0xb6, 0x17, 0x76, 0xf4, 0x66, 0x52,
+	0xe6, 0x29, 0xd3, 0x3a, 0xd2, 0x66, 0xdf, 0x87, 0x25, 0xe5, 0x16, 0x8d, 0x02, 0x3f, 0x22, 0xf6,
+	0x26, 0x2c, 0x72, 0x44, 0x94, 0xc4, 0x79, 0x00, 0x5a, 0x97, 0x8c, 0x68, 0x5f, 0xd4, 0xa5, 0x3b,
+	0x52, 0xb0, 0x5f, 0x83, 0x8e, 0x28, 0xe9, 0x76, 0xb7, 0x74, 0x2c, 0xf6, 0x87, 0xd0, 0x1d, 0xf5,
+	0xe7, 0xc7, 0xde, 0x01, 0x1d, 0x51, 0x18, 0x7b, 0x1d, 0xca, 0x61, 0x10, 0x50, 0x81, 0xca, 0x86,
+	0xfe, 0x4c, 0x48, 0xe8, 0x08, 0x13, 0xab, 0x5f, 0x77, 0x78, 0x8f, 0xd4, 0xb5, 0x37, 0x41, 0xbb,
+	0xe2, 0x2f, 0x83, 0x4e, 0x0d, 0xe5, 0x24, 0xde, 0xcb, 0x91, 0x46, 0x7b, 0x1f, 0x1a, 0x89, 0x1b,
+	0xe6, 0xda, 0xc2, 0xd6, 0xa7, 0x85, 0xe0, 0x8b, 0x0b, 0x1c, 0xbe, 0x84, 0x68, 0x5c, 0x5b, 0x3e,
+	0x70, 0x92, 0xd1, 0x6e, 0xc2, 0x72, 0xaa, 0xc2, 0x68, 0x16, 0xd4, 0x70, 0x0e, 0x64, 0xbc, 0xba,
+	0xa3, 0x64, 0x7b, 0x09, 0xf4, 0x36, 0x75, 0x69, 0xac, 0x02, 0x1c, 0x42, 0x23, 0x51, 0xa0, 0xfb,
+	0x0b, 0xa8, 0x44, 0x42, 0x83, 0x55, 0x2c, 0xa9, 0x2a, 0x10, 0x88, 0x66, 0xfb, 0x15, 0x2c, 0x7e,
+	0x8d, 0xdc, 0x1e, 0x49, 0xaa, 0x7f, 0x0c, 0x75, 0xdf, 0x1d, 0xb2, 0x30, 0xae, 0x1a, 0xc8, 0x54,
+	0xc1, 0x9b, 0x85, 0x68, 0xcc, 0xc3, 0x9a, 0x15, 0x73, 0x05, 0xd6, 0x9c, 0x36, 0x4b, 0xc2, 0xa4,
+	0xd1, 0xfe, 0xc1, 0xa6, 0x5d, 0x28, 0xe6, 0x87, 0x37, 0x0c, 0x28, 0x47, 0xc4, 0xa7, 0x62, 0xdc,
+	0x4b, 0x8e, 0x38, 0xf3, 0x46, 0x84, 0xa4, 0x43, 0xbc, 0x31, 0xe9, 0x8a, 0x61, 0x2f, 0x39, 0x4a,
+	0xe6, 0x53, 0x10, 0x79, 0x3e, 0x8b, 0x54, 0x16, 0x06, 0x29, 0x70, 0xed, 0xc0, 0x1b, 0x7a, 0x54,
+	0x4c, 0x3a, 0xd3, 0x0a, 0xc1, 0x6e, 0x81, 0x76, 0x12, 0x86, 0x41, 0xc8, 0xcd, 0x9d, 0x20, 0xf6,
+	0x69, 0x32, 0x3a, 0x42, 0x30, 0x96, 0xa1, 0x34, 0x8c, 0x7a, 0x48, 0x34, 0x7e, 0x64, 0xaf, 0x52,
+	0x91, 0xbd, 0xe2, 0x45, 0x12, 0xee, 0x3a, 0x33, 0x11, 0x22, 0xa0, 0x23, 0x8d, 0xf6, 0xef, 0x02,
+	0x94, 0xf9, 0xc8, 0x1a, 0x0d, 0x28, 0x7a, 0x5d, 0x2c, 0x8e, 0x9d, 0xe6, 0xf3, 0x38, 0x61, 0x65,
+	0x29, 0xc7, 0x4a, 0x63, 0x1f, 0x6a, 0x43, 0x42, 0xdd, 0xae, 0x4b, 0x5d, 0x56, 0x1c, 0x6f, 0xed,
+	0xa3, 0x1c, 0x2f, 0x9a, 0x9f, 0xd0, 0x7a, 0xe2, 0x53, 0x36, 0x94, 0x0a, 0x9c, 0x79, 0x78, 0x6d,
+	0xee, 0xc3, 0x5b, 0x47, 0xa0, 0xe7, 0x62, 0xf0, 0x0e, 0x5c, 0x92, 0x09, 0xde, 0x9b, 0x1f, 0x79,
+	0xa7, 0xc6, 0xee, 0x20, 0x26, 0x78, 0x6d, 0x29, 0xbc, 0x29, 0x1e, 0x14, 0xd8, 0xd4, 0x54, 0x91,
+	0xfd, 0x9c, 0x62, 0x9c, 0xa2, 0x33, 0x14, 0x13, 0xec, 0x15, 0x26, 0xfb, 0x25, 0x68, 0xc7, 0x83,
+	0x40, 0xd2, 0xf1, 0x7f, 0xd8, 0x53, 0x28, 0x73, 0x72, 0xde, 0x01, 0xca, 0x3f, 0x1c, 0x23, 0x06,
+	0xe5, 0x5d, 0x2d, 0xcd, 0xb2, 0x5b, 0xda, 0xec, 0xcf, 0x50, 0x6e, 0x4f, 0xfc, 0x0e, 0x8f, 0xc7,
+	0x15, 0xff, 0xf8, 0x12, 0x70, 0x53, 0x86, 0xc0, 0xc5, 0x79, 0x04, 0xee, 0xc1, 0xc2, 0x19, 0x63,
+	0x4a, 0x42, 0x18, 0x23, 0x73, 0xd1, 0x3a, 0xde, 0x8c, 0x35, 0x6e, 0xe4, 0xf9, 0xbd, 0x08, 0x07,
+	0x59, 0x0a, 0x62, 0xba, 0xbd, 0xef, 0x04, 0xa7, 0x58, 0x9c, 0xf9, 0x74, 0x77, 0xe3, 0xd0, 0xa5,
+	0x5e, 0xe0, 0xe3, 0x10, 0x2b, 0xd9, 0xfe, 0x55, 0x84, 0x45, 0x99, 0x09, 0xc9, 0x76, 0x5b, 0x2a,
+	0xa6, 0x1b, 0x78, 0xfe, 0x25, 0x3e, 0x91, 0x38, 0x1b, 0x4f, 0x00, 0x44, 0xc6, 0x73, 0x41, 0x26,
+	0x99, 0xae, 0x2e, 0x34, 0x6d, 0xce, 0xa8, 0x2d, 0x68, 0x48, 0xb3, 0xe2, 0x95, 0xcc, 0xac, 0x0b,
+	0xad, 0x93, 0x90, 0xeb, 0x19, 0x2c, 0x0c, 0x3d, 0xff, 0x7c, 0xc0, 0x36, 0x8b, 0xdf, 0x99, 0x20,
+	0x99, 0x80, 0xa9, 0x3e, 0x4a, 0x0d, 0x07, 0xb8, 0xe3, 0x9e, 0x02, 0x54, 0x24, 0x80, 0xa9, 0x32,
+	0x80, 0xa1, 0x7b, 0xa3, 0x00, 0x55, 0x8c, 0xe0, 0xde, 0x24, 0x00, 0xd6, 0xa7, 0x8b, 0x09, 0x6f,
+	0x78, 0x4d, 0xf6, 0x49, 0x08, 0xb9, 0x9e, 0xd4, 0xf3, 0x3d, 0x31, 0x9e, 0x02, 0xd0, 0x3e, 0x7b,
+	0x88, 0x5e, 0x7f, 0x14, 0x53, 0x13, 0x64, 0xc4, 0x54, 0xb3, 0xf3, 0xa7, 0x04, 0xd5, 0x53, 0xe4,
+	0xd0, 0xdb, 0x74, 0x48, 0xd7, 0xd4, 0x7b, 0xe7, 0x77, 0x9d, 0x65, 0xce, 0x1a, 0x70, 0x9b, 0xdd,
+	0x33, 0x0e, 0x40, 0x13, 0xdb, 0xc4, 0x58, 0x51, 0xa0, 0xec, 0x0e, 0xb2, 0x56, 0xa7, 0xd5, 0x59,
+	0x4f, 0xb1, 0xe3, 0x32, 0x9e, 0xd9, 0xcd, 0x98, 0xf1, 0xcc, 0xad, 0x42, 0xe6, 0x79, 0x04, 0x15,
+	0xb9, 0x56, 0x8c, 0x14, 0x93, 0x5b, 0x4f, 0xd6, 0xda, 0x8c, 0x5e, 0x39, 0xbf, 0x83, 0x5a, 0xb2,
+	0x47, 0x8c, 0xb4, 0xb0, 0xa9, 0x6d, 0x63, 0x3d, 0xbc, 0xc5, 0x92, 0xcd, 0x8f, 0x1f, 0xbd, 0xd5,
+	0xe9, 0x0f, 0xc7, 0x4c, 0xfe, 0xfc, 0xca, 0x91, 0x65, 0xcb, 0xaf, 0xfc, 0xca, 0xd4, 0x1a, 0x98,
+	0x29, 0x3b, 0xb7, 0x44, 0x98, 0xe7, 0x1e, 0x94, 0xcf, 0xc4, 0x5a, 0x57, 0x88, 0x0c, 0xc5, 0xac,
+	0x95, 0x29, 0x6d, 0xe2, 0xf6, 0xfe, 0xf0, 0xdb, 0x7e, 0xcf, 0xa3, 0xfd, 0xf8, 0xa2, 0xd9, 0x09,
+	0x86, 0xad, 0xa1, 0xd7, 0x09, 0x03, 0xfc, 0x1d, 0xef, 0xb6, 0x6e, 0xfd, 0xcb, 0x76, 0x84, 0xd2,
+	0x45, 0x45, 0x88, 0xbb, 0x7f, 0x01, 0xae, 0xa7, 0x44, 0xc1, 0xda, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -993,6 +1353,10 @@ type NetworkClient interface {
 	Services(ctx context.Context, in *ServicesRequest, opts ...grpc.CallOption) (*ServicesResponse, error)
 	// Status returns network status
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Usage returns the bytes sent and received on the network links by each namespace
+	Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error)
+	// Test measures the latency, loss and throughput of the link to a peer
+	Test(ctx context.Context, in *TestRequest, opts ...grpc.CallOption) (*TestResponse, error)
 }
 
 type networkClient struct {
@@ -1057,6 +1421,24 @@ func (c *networkClient) Status(ctx context.Context, in *StatusRequest, opts ...g
 	return out, nil
 }
 
+func (c *networkClient) Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error) {
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, "/network.Network/Usage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkClient) Test(ctx context.Context, in *TestRequest, opts ...grpc.CallOption) (*TestResponse, error) {
+	out := new(TestResponse)
+	err := c.cc.Invoke(ctx, "/network.Network/Test", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NetworkServer is the server API for Network service.
 type NetworkServer interface {
 	// Connect to the network
@@ -1071,6 +1453,10 @@ type NetworkServer interface {
 	Services(context.Context, *ServicesRequest) (*ServicesResponse, error)
 	// Status returns network status
 	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	// Usage returns the bytes sent and received on the network links by each namespace
+	Usage(context.Context, *UsageRequest) (*UsageResponse, error)
+	// Test measures the latency, loss and throughput of the link to a peer
+	Test(context.Context, *TestRequest) (*TestResponse, error)
 }
 
 // UnimplementedNetworkServer can be embedded to have forward compatible implementations.
@@ -1095,6 +1481,12 @@ func (*UnimplementedNetworkServer) Services(ctx context.Context, req *ServicesRe
 func (*UnimplementedNetworkServer) Status(ctx context.Context, req *StatusRequest) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (*UnimplementedNetworkServer) Usage(ctx context.Context, req *UsageRequest) (*UsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Usage not implemented")
+}
+func (*UnimplementedNetworkServer) Test(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Test not implemented")
+}
 
 func RegisterNetworkServer(s *grpc.Server, srv NetworkServer) {
 	s.RegisterService(&_Network_serviceDesc, srv)
@@ -1208,6 +1600,42 @@ func _Network_Status_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Network_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.Network/Usage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).Usage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Network_Test_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).Test(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.Network/Test",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).Test(ctx, req.(*TestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Network_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "network.Network",
 	HandlerType: (*NetworkServer)(nil),
@@ -1236,6 +1664,14 @@ var _Network_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _Network_Status_Handler,
 		},
+		{
+			MethodName: "Usage",
+			Handler:    _Network_Usage_Handler,
+		},
+		{
+			MethodName: "Test",
+			Handler:    _Network_Test_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service/network/proto/network.proto",
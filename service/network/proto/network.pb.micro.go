@@ -55,6 +55,10 @@ type NetworkService interface {
 	Services(ctx context.Context, in *ServicesRequest, opts ...client.CallOption) (*ServicesResponse, error)
 	// Status returns network status
 	Status(ctx context.Context, in *StatusRequest, opts ...client.CallOption) (*StatusResponse, error)
+	// Usage returns the bytes sent and received on the network links by each namespace
+	Usage(ctx context.Context, in *UsageRequest, opts ...client.CallOption) (*UsageResponse, error)
+	// Test measures the latency, loss and throughput of the link to a peer
+	Test(ctx context.Context, in *TestRequest, opts ...client.CallOption) (*TestResponse, error)
 }
 
 type networkService struct {
@@ -129,6 +133,26 @@ func (c *networkService) Status(ctx context.Context, in *StatusRequest, opts ...
 	return out, nil
 }
 
+func (c *networkService) Usage(ctx context.Context, in *UsageRequest, opts ...client.CallOption) (*UsageResponse, error) {
+	req := c.c.NewRequest(c.name, "Network.Usage", in)
+	out := new(UsageResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkService) Test(ctx context.Context, in *TestRequest, opts ...client.CallOption) (*TestResponse, error) {
+	req := c.c.NewRequest(c.name, "Network.Test", in)
+	out := new(TestResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Network service
 
 type NetworkHandler interface {
@@ -144,6 +168,10 @@ type NetworkHandler interface {
 	Services(context.Context, *ServicesRequest, *ServicesResponse) error
 	// Status returns network status
 	Status(context.Context, *StatusRequest, *StatusResponse) error
+	// Usage returns the bytes sent and received on the network links by each namespace
+	Usage(context.Context, *UsageRequest, *UsageResponse) error
+	// Test measures the latency, loss and throughput of the link to a peer
+	Test(context.Context, *TestRequest, *TestResponse) error
 }
 
 func RegisterNetworkHandler(s server.Server, hdlr NetworkHandler, opts ...server.HandlerOption) error {
@@ -154,6 +182,8 @@ func RegisterNetworkHandler(s server.Server, hdlr NetworkHandler, opts ...server
 		Routes(ctx context.Context, in *RoutesRequest, out *RoutesResponse) error
 		Services(ctx context.Context, in *ServicesRequest, out *ServicesResponse) error
 		Status(ctx context.Context, in *StatusRequest, out *StatusResponse) error
+		Usage(ctx context.Context, in *UsageRequest, out *UsageResponse) error
+		Test(ctx context.Context, in *TestRequest, out *TestResponse) error
 	}
 	type Network struct {
 		network
@@ -189,3 +219,11 @@ func (h *networkHandler) Services(ctx context.Context, in *ServicesRequest, out
 func (h *networkHandler) Status(ctx context.Context, in *StatusRequest, out *StatusResponse) error {
 	return h.NetworkHandler.Status(ctx, in, out)
 }
+
+func (h *networkHandler) Usage(ctx context.Context, in *UsageRequest, out *UsageResponse) error {
+	return h.NetworkHandler.Usage(ctx, in, out)
+}
+
+func (h *networkHandler) Test(ctx context.Context, in *TestRequest, out *TestResponse) error {
+	return h.NetworkHandler.Test(ctx, in, out)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"time"
 
 	"github.com/micro/go-micro/v3/network"
 	"github.com/micro/go-micro/v3/network/mucp"
@@ -17,6 +18,9 @@ import (
 // Network implements network handler
 type Network struct {
 	Network network.Network
+
+	// meter counts the bytes sent and received on the links by each namespace
+	meter *meter
 }
 
 func flatten(n network.Node, visited map[string]bool) []network.Node {
@@ -247,3 +251,95 @@ func (n *Network) Services(ctx context.Context, req *pb.ServicesRequest, resp *p
 
 	return nil
 }
+
+// Usage returns the bytes sent and received on the network links by each namespace
+func (n *Network) Usage(ctx context.Context, req *pb.UsageRequest, resp *pb.UsageResponse) error {
+	// default the namespace to the current users namespace
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.FromContext(ctx)
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("network.Network.Usage", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("network.Network.Usage", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("network.Network.Usage", err.Error())
+	}
+
+	if n.meter == nil {
+		return nil
+	}
+
+	// for users in the default namespace, return the usage of all namespaces
+	ns := req.Namespace
+	if ns == namespace.DefaultNamespace {
+		ns = ""
+	}
+	resp.Usage = n.meter.read(ns)
+	return nil
+}
+
+// Test measures the latency, loss and throughput of the link to a peer
+func (n *Network) Test(ctx context.Context, req *pb.TestRequest, resp *pb.TestResponse) error {
+	// authorize the request. only accounts issued by micro (root accounts) can test the links
+	if err := namespace.Authorize(ctx, namespace.DefaultNamespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("network.Network.Test", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("network.Network.Test", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("network.Network.Test", err.Error())
+	}
+
+	if len(req.Node) == 0 {
+		return errors.BadRequest("network.Network.Test", "Node missing")
+	}
+	link, ok := n.link(req.Node)
+	if !ok {
+		return errors.NotFound("network.Network.Test", "No link to node %v", req.Node)
+	}
+
+	pings := int(req.Pings)
+	if pings <= 0 {
+		pings = DefaultTestPings
+	}
+	size := int(req.Size)
+	if size <= 0 {
+		size = DefaultTestSize
+	}
+	duration := time.Duration(req.Duration) * time.Millisecond
+	if duration <= 0 {
+		duration = DefaultTestDuration
+	} else if duration > MaxTestDuration {
+		duration = MaxTestDuration
+	}
+
+	resp.Node = req.Node
+	if err := runTest(n.Network.Options().Tunnel, link, pings, size, duration, resp); err != nil {
+		return errors.InternalServerError("network.Network.Test", "failed to test the link to %v: %v", req.Node, err)
+	}
+	return nil
+}
+
+// link returns the id of the tunnel link to the peer, which is identified by its id, its
+// address or the id of the link
+func (n *Network) link(node string) (string, bool) {
+	address := node
+	for _, peer := range flatten(n.Network, nil) {
+		if peer != nil && peer.Id() == node {
+			address = peer.Address()
+			break
+		}
+	}
+
+	for _, l := range n.Network.Options().Tunnel.Links() {
+		if l.Loopback() {
+			continue
+		}
+		if l.Id() == node || l.Remote() == address {
+			return l.Id(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/internal/usage"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/network/proto"
+)
+
+var (
+	// DefaultEgressWindow is the window the bytes sent by each namespace are counted over,
+	// after which the counts are reset
+	DefaultEgressWindow = time.Hour * 24
+)
+
+// meter counts the bytes sent and received on the network links by each namespace, so the
+// cost of cross region traffic can be attributed to the namespaces which caused it
+type meter struct {
+	// limit is the bytes a namespace can send in the window, zero if unlimited
+	limit  int64
+	window time.Duration
+
+	sync.Mutex
+	since time.Time
+	usage map[string]*pb.Usage
+}
+
+func newMeter(limit int64, window time.Duration) *meter {
+	return &meter{
+		limit:  limit,
+		window: window,
+		since:  time.Now(),
+		usage:  make(map[string]*pb.Usage),
+	}
+}
+
+// get the usage of the namespace, starting a new window if the current one has ended. The
+// lock must be held.
+func (m *meter) get(ns string) *pb.Usage {
+	if m.window > 0 && time.Since(m.since) >= m.window {
+		m.since = time.Now()
+		m.usage = make(map[string]*pb.Usage)
+	}
+
+	u, ok := m.usage[ns]
+	if !ok {
+		u = &pb.Usage{Namespace: ns, Since: m.since.Unix(), Limit: m.limit}
+		m.usage[ns] = u
+	}
+	return u
+}
+
+// add the bytes sent and received by the namespace
+func (m *meter) add(ns string, sent, received int) {
+	m.Lock()
+	u := m.get(ns)
+	u.Sent += int64(sent)
+	u.Received += int64(received)
+	m.Unlock()
+
+	// the totals are included in the usage reports
+	if sent > 0 {
+		usage.Add("network_sent", uint64(sent))
+	}
+	if received > 0 {
+		usage.Add("network_received", uint64(received))
+	}
+}
+
+// exceeded returns true if the namespace has sent more than the limit in the window
+func (m *meter) exceeded(ns string) bool {
+	if m.limit <= 0 {
+		return false
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	return m.get(ns).Sent >= m.limit
+}
+
+// read the usage of the namespace, or every namespace if blank
+func (m *meter) read(ns string) []*pb.Usage {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(ns) > 0 {
+		u := *m.get(ns)
+		return []*pb.Usage{&u}
+	}
+
+	// ensure the window is current
+	m.get(namespace.DefaultNamespace)
+
+	usage := make([]*pb.Usage, 0, len(m.usage))
+	for _, u := range m.usage {
+		c := *u
+		usage = append(usage, &c)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Namespace < usage[j].Namespace
+	})
+	return usage
+}
+
+// Router returns a router which meters the requests served by the router
+func (m *meter) Router(r server.Router) server.Router {
+	return &meteredRouter{Router: r, meter: m}
+}
+
+type meteredRouter struct {
+	server.Router
+	meter *meter
+}
+
+func (r *meteredRouter) ServeRequest(ctx context.Context, req server.Request, rsp server.Response) error {
+	ns := issuer(ctx, req)
+
+	if r.meter.exceeded(ns) {
+		return errors.TooManyRequests("network.Network", "Namespace %v has exceeded its network egress limit", ns)
+	}
+
+	return r.Router.ServeRequest(ctx, &meteredRequest{req, r.meter, ns}, &meteredResponse{rsp, r.meter, ns})
+}
+
+// issuer returns the namespace the request is attributed to, the issuer of the account which
+// made it. Requests without a valid account are attributed to the default namespace.
+func issuer(ctx context.Context, req server.Request) string {
+	if acc, ok := goauth.AccountFromContext(ctx); ok && acc != nil {
+		return acc.Issuer
+	}
+
+	// requests from peers aren't authenticated by the network's server
+	header, _ := metadata.Metadata(req.Header()).Get("Authorization")
+	if !strings.HasPrefix(header, goauth.BearerScheme) {
+		return namespace.DefaultNamespace
+	}
+	if acc, err := auth.Inspect(strings.TrimPrefix(header, goauth.BearerScheme)); err == nil && len(acc.Issuer) > 0 {
+		return acc.Issuer
+	}
+	return namespace.DefaultNamespace
+}
+
+// meteredRequest counts the bytes of the raw request read by the proxy
+type meteredRequest struct {
+	server.Request
+	meter *meter
+	ns    string
+}
+
+func (r *meteredRequest) Read() ([]byte, error) {
+	b, err := r.Request.Read()
+	r.meter.add(r.ns, 0, len(b))
+	return b, err
+}
+
+// meteredResponse counts the bytes of the raw response written by the proxy
+type meteredResponse struct {
+	server.Response
+	meter *meter
+	ns    string
+}
+
+func (r *meteredResponse) Write(b []byte) error {
+	r.meter.add(r.ns, len(b), 0)
+	return r.Response.Write(b)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testRequest struct {
+	server.Request
+	header map[string]string
+	body   []byte
+}
+
+func (r *testRequest) Header() map[string]string { return r.header }
+func (r *testRequest) Read() ([]byte, error)     { return r.body, nil }
+
+type testResponse struct {
+	server.Response
+}
+
+func (r *testResponse) Write(b []byte) error { return nil }
+
+// echoRouter writes the request body back as the response, like the proxy
+type echoRouter struct {
+	server.Router
+}
+
+func (echoRouter) ServeRequest(ctx context.Context, req server.Request, rsp server.Response) error {
+	b, err := req.Read()
+	if err != nil {
+		return err
+	}
+	return rsp.Write(append(b, b...))
+}
+
+func TestMeter(t *testing.T) {
+	m := newMeter(30, time.Hour)
+	r := m.Router(echoRouter{})
+
+	// requests are attributed to the issuer of the account, not the namespace they claim
+	call := func(issuer string) error {
+		ctx := context.TODO()
+		if len(issuer) > 0 {
+			ctx = auth.ContextWithAccount(ctx, &auth.Account{ID: "test", Issuer: issuer})
+		}
+		req := &testRequest{header: map[string]string{namespace.NamespaceKey: "bar"}, body: []byte("0123456789")}
+		return r.ServeRequest(ctx, req, &testResponse{})
+	}
+
+	if err := call("foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := call(""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	usage := m.read("foo")
+	if len(usage) != 1 || usage[0].Sent != 20 || usage[0].Received != 10 {
+		t.Fatalf("Expected foo to have sent 20 bytes and received 10, got %v", usage)
+	}
+	if usage := m.read(""); len(usage) != 2 || usage[0].Namespace != "foo" || usage[1].Namespace != namespace.DefaultNamespace {
+		t.Fatalf("Expected the usage of both namespaces, got %v", usage)
+	}
+
+	// foo exceeds its limit on the next request, after which requests are rejected
+	if err := call("foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err := call("foo")
+	if verr := errors.Parse(err); verr == nil || verr.Code != 429 {
+		t.Fatalf("Expected the request to be rejected, got %v", err)
+	}
+
+	// the counts are reset when the window ends
+	m.since = time.Now().Add(-time.Hour)
+	if err := call("foo"); err != nil {
+		t.Fatalf("Expected the limit to be reset, got %v", err)
+	}
+}
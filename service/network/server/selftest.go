@@ -0,0 +1,239 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/micro/go-micro/v3/network/transport"
+	"github.com/micro/go-micro/v3/network/tunnel"
+	log "github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/network/proto"
+)
+
+var (
+	// TestChannel is the tunnel channel links are tested over. It's separate from the channels
+	// the network and proxied requests use so the measurements reflect the link rather than
+	// the traffic queued on it.
+	TestChannel = "network.test"
+	// DefaultTestPings is the number of pings the latency and loss are measured with
+	DefaultTestPings = 20
+	// DefaultTestSize is the size in bytes of the messages the throughput is measured with
+	DefaultTestSize = 64 * 1024
+	// DefaultTestDuration is how long the throughput is measured for
+	DefaultTestDuration = time.Second * 3
+	// MaxTestDuration bounds the duration of a test so it can't saturate a link for long
+	MaxTestDuration = time.Second * 30
+	// DefaultTestTimeout is how long a ping or message waits to be answered before it's lost
+	DefaultTestTimeout = time.Second * 2
+)
+
+// the headers of the test messages
+const (
+	testTypeHeader = "Micro-Network-Test"
+	testSeqHeader  = "Micro-Network-Test-Seq"
+	testSizeHeader = "Micro-Network-Test-Size"
+)
+
+// the types of the test messages
+const (
+	testPing = "ping"
+	testData = "data"
+	testAck  = "ack"
+)
+
+// testWindow is the number of data messages sent without being acknowledged, enough to keep
+// the link busy while the acknowledgements are in flight
+const testWindow = 8
+
+var errTestClosed = errors.New("test session closed")
+
+// serveTests answers the tests peers run over the test channel until the listener is closed
+func serveTests(l tunnel.Listener) {
+	for {
+		s, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go serveTest(s)
+	}
+}
+
+// serveTest answers pings with pings and acknowledges data with its size, so the throughput
+// measured is that of the link to the peer rather than the link back
+func serveTest(s tunnel.Session) {
+	defer s.Close()
+	for {
+		var m transport.Message
+		if err := s.Recv(&m); err != nil {
+			return
+		}
+
+		rsp := &transport.Message{Header: map[string]string{testSeqHeader: m.Header[testSeqHeader]}}
+		switch m.Header[testTypeHeader] {
+		case testPing:
+			rsp.Header[testTypeHeader] = testPing
+		case testData:
+			rsp.Header[testTypeHeader] = testAck
+			rsp.Header[testSizeHeader] = strconv.Itoa(len(m.Body))
+		default:
+			continue
+		}
+		if err := s.Send(rsp); err != nil {
+			return
+		}
+	}
+}
+
+// linkTest measures the quality of a link over a session on the test channel
+type linkTest struct {
+	session tunnel.Session
+	recv    chan *transport.Message
+	done    chan struct{}
+	seq     int
+}
+
+// runTest measures the latency, loss and throughput of the link
+func runTest(tun tunnel.Tunnel, link string, pings, size int, duration time.Duration, rsp *pb.TestResponse) error {
+	s, err := tun.Dial(TestChannel, tunnel.DialMode(tunnel.Unicast), tunnel.DialLink(link))
+	if err != nil {
+		return err
+	}
+
+	t := &linkTest{
+		session: s,
+		recv:    make(chan *transport.Message, testWindow),
+		done:    make(chan struct{}),
+	}
+	go t.receive()
+	defer func() {
+		close(t.done)
+		s.Close()
+	}()
+
+	rsp.Link = link
+	if err := t.ping(pings, rsp); err != nil {
+		return err
+	}
+	return t.throughput(size, duration, rsp)
+}
+
+// receive the answers of the peer until the test is done
+func (t *linkTest) receive() {
+	defer close(t.recv)
+	for {
+		var m transport.Message
+		if err := t.session.Recv(&m); err != nil {
+			return
+		}
+		select {
+		case t.recv <- &m:
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// send a test message, returning its sequence number
+func (t *linkTest) send(typ string, body []byte) (string, error) {
+	t.seq++
+	seq := strconv.Itoa(t.seq)
+	return seq, t.session.Send(&transport.Message{
+		Header: map[string]string{testTypeHeader: typ, testSeqHeader: seq},
+		Body:   body,
+	})
+}
+
+// ping the peer one ping at a time, a ping which isn't answered within the timeout is lost.
+// Answers to lost pings which arrive late are ignored.
+func (t *linkTest) ping(n int, rsp *pb.TestResponse) error {
+	var total time.Duration
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		seq, err := t.send(testPing, nil)
+		if err != nil {
+			return err
+		}
+		rsp.PingsSent++
+
+		timeout := time.NewTimer(DefaultTestTimeout)
+	wait:
+		for {
+			select {
+			case m, ok := <-t.recv:
+				if !ok {
+					timeout.Stop()
+					return errTestClosed
+				}
+				if m.Header[testTypeHeader] != testPing || m.Header[testSeqHeader] != seq {
+					continue
+				}
+				rtt := time.Since(start)
+				total += rtt
+				rsp.PingsReceived++
+				if rsp.MinLatency == 0 || rtt.Microseconds() < rsp.MinLatency {
+					rsp.MinLatency = rtt.Microseconds()
+				}
+				if rtt.Microseconds() > rsp.MaxLatency {
+					rsp.MaxLatency = rtt.Microseconds()
+				}
+				timeout.Stop()
+				break wait
+			case <-timeout.C:
+				break wait
+			}
+		}
+	}
+
+	if rsp.PingsReceived > 0 {
+		rsp.AvgLatency = (total / time.Duration(rsp.PingsReceived)).Microseconds()
+	}
+	return nil
+}
+
+// throughput sends messages of the size for the duration, keeping a window of them in flight,
+// and counts the bytes the peer acknowledges. The measurement stops early if the peer stops
+// acknowledging the messages.
+func (t *linkTest) throughput(size int, duration time.Duration, rsp *pb.TestResponse) error {
+	body := make([]byte, size)
+	start := time.Now()
+	deadline := start.Add(duration)
+
+	var inflight int
+loop:
+	for {
+		if inflight < testWindow && time.Now().Before(deadline) {
+			if _, err := t.send(testData, body); err != nil {
+				return err
+			}
+			inflight++
+			continue
+		}
+		if inflight == 0 {
+			break loop
+		}
+
+		select {
+		case m, ok := <-t.recv:
+			if !ok {
+				return errTestClosed
+			}
+			if m.Header[testTypeHeader] != testAck {
+				continue
+			}
+			n, _ := strconv.ParseInt(m.Header[testSizeHeader], 10, 64)
+			rsp.Bytes += n
+			inflight--
+		case <-time.After(DefaultTestTimeout):
+			log.Debugf("Network test of link %v stalled with %d messages in flight", rsp.Link, inflight)
+			break loop
+		}
+	}
+
+	elapsed := time.Since(start)
+	rsp.Duration = elapsed.Milliseconds()
+	if elapsed > 0 {
+		rsp.Throughput = int64(float64(rsp.Bytes) / elapsed.Seconds())
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/network/transport"
+	"github.com/micro/go-micro/v3/network/tunnel"
+	pb "github.com/micro/micro/v3/service/network/proto"
+)
+
+// testSession is one end of an in memory session
+type testSession struct {
+	tunnel.Session
+	in   chan *transport.Message
+	out  chan *transport.Message
+	once *sync.Once
+	done chan struct{}
+}
+
+func (s *testSession) Recv(m *transport.Message) error {
+	select {
+	case msg := <-s.in:
+		*m = *msg
+		return nil
+	case <-s.done:
+		return errors.New("closed")
+	}
+}
+
+func (s *testSession) Send(m *transport.Message) error {
+	select {
+	case s.out <- m:
+		return nil
+	case <-s.done:
+		return errors.New("closed")
+	}
+}
+
+func (s *testSession) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return nil
+}
+
+// testTunnel dials sessions answered by serveTest
+type testTunnel struct {
+	tunnel.Tunnel
+}
+
+func (t *testTunnel) Dial(channel string, opts ...tunnel.DialOption) (tunnel.Session, error) {
+	a, b := make(chan *transport.Message, 16), make(chan *transport.Message, 16)
+	once, done := &sync.Once{}, make(chan struct{})
+	go serveTest(&testSession{in: a, out: b, once: once, done: done})
+	return &testSession{in: b, out: a, once: once, done: done}, nil
+}
+
+func TestRunTest(t *testing.T) {
+	var rsp pb.TestResponse
+	if err := runTest(&testTunnel{}, "link-1", 5, 1024, time.Millisecond*50, &rsp); err != nil {
+		t.Fatal(err)
+	}
+
+	if rsp.Link != "link-1" {
+		t.Errorf("Expected the link to be set, got %v", rsp.Link)
+	}
+	if rsp.PingsSent != 5 || rsp.PingsReceived != 5 {
+		t.Errorf("Expected every ping to be answered, got %v of %v", rsp.PingsReceived, rsp.PingsSent)
+	}
+	if rsp.MinLatency > rsp.AvgLatency || rsp.AvgLatency > rsp.MaxLatency {
+		t.Errorf("Expected min <= avg <= max latency, got %v %v %v", rsp.MinLatency, rsp.AvgLatency, rsp.MaxLatency)
+	}
+	if rsp.Bytes == 0 || rsp.Bytes%1024 != 0 {
+		t.Errorf("Expected whole messages to be acknowledged, got %v bytes", rsp.Bytes)
+	}
+	if rsp.Duration < 50 || rsp.Throughput == 0 {
+		t.Errorf("Expected the throughput to be measured for the duration, got %v bytes/s over %vms", rsp.Throughput, rsp.Duration)
+	}
+}
@@ -74,6 +74,17 @@ var (
 			Usage:   "Set the micro network token for authentication",
 			EnvVars: []string{"MICRO_NETWORK_TOKEN"},
 		},
+		&cli.Int64Flag{
+			Name:    "egress_limit",
+			Usage:   "Set the bytes each namespace can send over the network links in the egress window. 0 for no limit",
+			EnvVars: []string{"MICRO_NETWORK_EGRESS_LIMIT"},
+		},
+		&cli.DurationFlag{
+			Name:    "egress_window",
+			Usage:   "Set the window the bytes sent by each namespace are counted over",
+			EnvVars: []string{"MICRO_NETWORK_EGRESS_WINDOW"},
+			Value:   DefaultEgressWindow,
+		},
 	}
 )
 
@@ -171,9 +182,12 @@ func Run(ctx *cli.Context) error {
 		proxy.WithLink("network", netService.Client()),
 	)
 
+	// meter the traffic on the network links
+	m := newMeter(ctx.Int64("egress_limit"), ctx.Duration("egress_window"))
+
 	// create a handler
 	h := mucpServer.DefaultRouter.NewHandler(
-		&Network{Network: netService},
+		&Network{Network: netService, meter: m},
 	)
 
 	// register the handler
@@ -182,8 +196,8 @@ func Run(ctx *cli.Context) error {
 	// local mux
 	localMux := muxer.New(name, localProxy)
 
-	// network mux
-	networkMux := muxer.New(name, networkProxy)
+	// network mux, metered since it serves the requests received from peers
+	networkMux := m.Router(muxer.New(name, networkProxy))
 
 	// init the local grpc server
 	service.Server().Init(
@@ -216,6 +230,13 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
+	// answer the link tests run by peers
+	testListener, err := tun.Listen(TestChannel)
+	if err != nil {
+		log.Fatalf("Network failed to listen for link tests: %v", err)
+	}
+	go serveTests(testListener)
+
 	log.Infof("Network [%s] listening on %s", networkName, peerAddress)
 
 	if err := service.Run(); err != nil {
@@ -225,6 +246,7 @@ func Run(ctx *cli.Context) error {
 	}
 
 	// close the network
+	testListener.Close()
 	netClose(netService)
 
 	return nil
@@ -1,14 +1,19 @@
 package service
 
 import (
+	"strings"
 	"time"
 
 	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/config/reader"
 	"github.com/micro/go-micro/v3/server"
 
 	// TODO: replace with micro/v3/service/cli
 	"github.com/micro/micro/v3/cmd"
 	muclient "github.com/micro/micro/v3/service/client"
+	muconfig "github.com/micro/micro/v3/service/config"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
 	muserver "github.com/micro/micro/v3/service/server"
 )
 
@@ -81,6 +86,53 @@ func Metadata(md map[string]string) Option {
 	}
 }
 
+// Dependencies declares the upstream services the service depends on. They're registered
+// in the node metadata and returned by the registry's dependency graph.
+func Dependencies(names ...string) Option {
+	return func(o *Options) {
+		setMetadata(util.DependenciesKey, strings.Join(names, ","))
+	}
+}
+
+// ConflictPolicy sets how the registry resolves registrations of the service's node ids by
+// other processes, e.g. "reject" to keep the existing registration. See the registry's
+// conflict_policy flag for the policies available.
+func ConflictPolicy(policy string) Option {
+	return func(o *Options) {
+		setMetadata(util.ConflictPolicyKey, policy)
+	}
+}
+
+// setMetadata sets the key in the metadata the service is registered with. The metadata is
+// copied since the server's map may be shared, e.g. with the map passed to Metadata.
+func setMetadata(key, value string) {
+	current := muserver.DefaultServer.Options().Metadata
+	md := make(map[string]string, len(current)+1)
+	for k, v := range current {
+		md[k] = v
+	}
+	md[key] = value
+	muserver.DefaultServer.Init(server.Metadata(md))
+}
+
+// Labels the nodes of the service are registered with, e.g. canary=true. Labels are kept
+// apart from the node metadata and can be used to select the nodes of a service or split
+// traffic between them.
+func Labels(labels map[string]string) Option {
+	return func(o *Options) {
+		muregistry.DefaultRegistry.Init(muregistry.Labels(labels))
+	}
+}
+
+// Locality the nodes of the service are registered in, e.g. region eu-west-1 and zone
+// eu-west-1a. The locality is kept apart from the node metadata, callers can prefer the
+// nodes in their locality with the registry's GetLocality option.
+func Locality(region, zone string) Option {
+	return func(o *Options) {
+		muregistry.DefaultRegistry.Init(muregistry.Locality(region, zone))
+	}
+}
+
 // RegisterTTL specifies the TTL to use when registering the service
 func RegisterTTL(t time.Duration) Option {
 	return func(o *Options) {
@@ -171,3 +223,14 @@ func AfterStop(fn func() error) Option {
 		o.AfterStop = append(o.AfterStop, fn)
 	}
 }
+
+// OnConfigChange calls the func with the value at the path of the config each time it changes
+// whilst the service is running, so the service can reconfigure itself without restarting,
+// e.g. resizing pools, changing log levels or toggling features
+func OnConfigChange(fn func(reader.Value) error, path ...string) Option {
+	return func(o *Options) {
+		h := muconfig.NewHook(fn, path...)
+		o.AfterStart = append(o.AfterStart, h.Start)
+		o.BeforeStop = append(o.BeforeStop, h.Stop)
+	}
+}
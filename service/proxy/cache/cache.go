@@ -0,0 +1,272 @@
+// Package cache provides a proxy handler wrapper which caches the responses of idempotent
+// endpoints. Services mark the endpoints whose responses can be cached using Cacheable, and
+// the proxy serves repeated requests from the cache until the TTL expires or the service calls
+// Invalidate because the data behind the responses changed. Requests are cached by namespace,
+// endpoint and body, so endpoints whose responses depend on the caller mustn't be cacheable.
+//
+// Invalidations are published to a system topic of the events stream so every proxy receives
+// them whichever namespace they're published in. The responses invalidated are those of the
+// namespace the events service authenticated the publisher in, so services can only invalidate
+// the responses of their own namespace.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	goevents "github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/metadata"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	cache "github.com/patrickmn/go-cache"
+)
+
+const (
+	// Key is the endpoint metadata key the TTL of the endpoint's responses is stored with
+	Key = "cache.ttl"
+)
+
+var (
+	// DefaultTopic is the system topic invalidations are published to
+	DefaultTopic = util.SystemTopicPrefix + "proxy.cache"
+	// DefaultMaxSize is the number of responses the cache holds, zero for no limit
+	DefaultMaxSize = 10000
+	// DefaultLookupTTL is how long the TTLs of a service's endpoints are remembered for
+	DefaultLookupTTL = time.Second * 30
+)
+
+// Cacheable marks the endpoint of the handler as cacheable, its responses being cached by the
+// proxy for the TTL. The endpoint keeps any other metadata it's registered with.
+func Cacheable(endpoint string, ttl time.Duration) server.HandlerOption {
+	return func(o *server.HandlerOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]map[string]string)
+		}
+		md := make(map[string]string, len(o.Metadata[endpoint])+1)
+		for k, v := range o.Metadata[endpoint] {
+			md[k] = v
+		}
+		md[Key] = ttl.String()
+		o.Metadata[endpoint] = md
+	}
+}
+
+// Invalidation of the responses cached for an endpoint
+type Invalidation struct {
+	Service string `json:"service"`
+	// Endpoint whose responses are invalidated, blank for every endpoint of the service
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// Invalidate the responses cached by the proxies for the endpoint of the service, or every
+// endpoint of the service if endpoint is blank, in the namespace of the service's account
+func Invalidate(service, endpoint string) error {
+	return events.Publish(DefaultTopic, &Invalidation{Service: service, Endpoint: endpoint})
+}
+
+// ttls of the endpoints of a service
+type ttls struct {
+	endpoints map[string]time.Duration
+	expires   time.Time
+}
+
+// Cache of responses
+type Cache struct {
+	cache *cache.Cache
+
+	// setMtx serialises sets so the size limit is enforced
+	setMtx sync.Mutex
+
+	sync.RWMutex
+	// services are the TTLs of the endpoints of each service, keyed by namespace and name
+	services map[string]ttls
+}
+
+// New returns a cache
+func New() *Cache {
+	return &Cache{
+		cache:    cache.New(cache.NoExpiration, time.Minute),
+		services: make(map[string]ttls),
+	}
+}
+
+// Watch the invalidations published to the topic, removing the responses they invalidate
+func (c *Cache) Watch(topic string) error {
+	return events.Consume(topic, c.consume)
+}
+
+// consume an invalidation, in the namespace the events service authenticated its publisher in
+func (c *Cache) consume(ctx context.Context, ev *goevents.Event) error {
+	ns := ev.Metadata[util.PublisherNamespaceKey]
+	if len(ns) == 0 {
+		logger.Warnf("Ignoring cache invalidation %v, the namespace of its publisher is unknown", ev.ID)
+		return nil
+	}
+	var inv Invalidation
+	if err := ev.Unmarshal(&inv); err != nil {
+		return err
+	}
+	n := c.Invalidate(ns, inv.Service, inv.Endpoint)
+	logger.Debugf("Invalidated %v cached responses of %v %v in namespace %v", n, inv.Service, inv.Endpoint, ns)
+	return nil
+}
+
+// Invalidate the responses cached for the endpoint of the service, or every endpoint of the
+// service if endpoint is blank. Returns the number of responses invalidated.
+func (c *Cache) Invalidate(ns, service, endpoint string) int {
+	prefix := ns + "/" + service + "/"
+	if len(endpoint) > 0 {
+		prefix += endpoint + "/"
+	}
+
+	var n int
+	for k := range c.cache.Items() {
+		if strings.HasPrefix(k, prefix) {
+			c.cache.Delete(k)
+			n++
+		}
+	}
+	return n
+}
+
+// Wrapper returns a handler wrapper which serves the requests to cacheable endpoints from the
+// cache. Streaming endpoints are never cached.
+func (c *Cache) Wrapper() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			srsp, ok := rsp.(server.Response)
+			if !ok {
+				return h(ctx, req, rsp)
+			}
+			ns, _ := metadata.Get(ctx, "Micro-Namespace")
+			if len(ns) == 0 {
+				ns = namespace.DefaultNamespace
+			}
+			ttl := c.ttl(ns, req.Service(), req.Endpoint())
+			if ttl <= 0 {
+				return h(ctx, req, rsp)
+			}
+
+			body, err := req.Read()
+			if err != nil {
+				return err
+			}
+			k := key(ns, req, body)
+			if v, ok := c.cache.Get(k); ok {
+				return srsp.Write(v.([]byte))
+			}
+
+			rec := &recorder{Response: srsp}
+			if err := h(ctx, &replayRequest{Request: req, body: body}, rec); err != nil {
+				return err
+			}
+			if rec.writes == 1 {
+				c.set(k, rec.body, ttl)
+			}
+			return nil
+		}
+	}
+}
+
+// set a response in the cache, evicting the response which expires soonest if it's full
+func (c *Cache) set(k string, rsp []byte, ttl time.Duration) {
+	c.setMtx.Lock()
+	defer c.setMtx.Unlock()
+
+	if DefaultMaxSize > 0 && c.cache.ItemCount() >= DefaultMaxSize {
+		c.cache.DeleteExpired()
+	}
+	if DefaultMaxSize > 0 && c.cache.ItemCount() >= DefaultMaxSize {
+		var oldest string
+		var expiry int64
+		for k, item := range c.cache.Items() {
+			if len(oldest) == 0 || item.Expiration < expiry {
+				oldest = k
+				expiry = item.Expiration
+			}
+		}
+		c.cache.Delete(oldest)
+	}
+	c.cache.Set(k, rsp, ttl)
+}
+
+// ttl returns the TTL of the endpoint's responses, zero if it isn't cacheable
+func (c *Cache) ttl(ns, service, endpoint string) time.Duration {
+	name := ns + "/" + service
+
+	c.RLock()
+	t, ok := c.services[name]
+	c.RUnlock()
+	if ok && time.Now().Before(t.expires) {
+		return t.endpoints[endpoint]
+	}
+
+	t = ttls{endpoints: make(map[string]time.Duration), expires: time.Now().Add(DefaultLookupTTL)}
+	if srvs, err := registry.GetService(service, goregistry.GetDomain(ns)); err == nil {
+		for _, s := range srvs {
+			for _, e := range s.Endpoints {
+				if e.Metadata["stream"] == "true" || len(e.Metadata[Key]) == 0 {
+					continue
+				}
+				d, err := time.ParseDuration(e.Metadata[Key])
+				if err != nil {
+					logger.Warnf("Invalid cache TTL of %v %v: %v", service, e.Name, err)
+					continue
+				}
+				t.endpoints[e.Name] = d
+			}
+		}
+	}
+
+	c.Lock()
+	c.services[name] = t
+	c.Unlock()
+	return t.endpoints[endpoint]
+}
+
+// key returns the key of the request, prefixed by the namespace, service and endpoint so the
+// responses can be invalidated
+func key(ns string, req server.Request, body []byte) string {
+	h := fnv.New64a()
+	h.Write([]byte(req.ContentType()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return fmt.Sprintf("%s/%s/%s/%x", ns, req.Service(), req.Endpoint(), h.Sum(nil))
+}
+
+// replayRequest returns the body which was read before the request was passed on
+type replayRequest struct {
+	server.Request
+	body []byte
+	read bool
+}
+
+func (r *replayRequest) Read() ([]byte, error) {
+	if r.read {
+		return r.Request.Read()
+	}
+	r.read = true
+	return r.body, nil
+}
+
+// recorder records the messages written to the response
+type recorder struct {
+	server.Response
+	writes int
+	body   []byte
+}
+
+func (r *recorder) Write(b []byte) error {
+	r.writes++
+	r.body = append([]byte(nil), b...)
+	return r.Response.Write(b)
+}
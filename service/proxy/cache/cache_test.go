@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	goevents "github.com/micro/go-micro/v3/events"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+	body     []byte
+}
+
+func (r *testRequest) Service() string     { return "foo" }
+func (r *testRequest) Endpoint() string    { return r.endpoint }
+func (r *testRequest) ContentType() string { return "application/grpc+proto" }
+
+func (r *testRequest) Read() ([]byte, error) {
+	if r.body == nil {
+		return nil, io.EOF
+	}
+	b := r.body
+	r.body = nil
+	return b, nil
+}
+
+type testResponse struct {
+	server.Response
+	body []byte
+}
+
+func (r *testResponse) Write(b []byte) error {
+	r.body = b
+	return nil
+}
+
+func TestCache(t *testing.T) {
+	reg := memory.NewRegistry()
+	defer func(r goregistry.Registry) { registry.DefaultRegistry = r }(registry.DefaultRegistry)
+	registry.DefaultRegistry = reg
+
+	var opts server.HandlerOptions
+	Cacheable("Foo.Get", time.Minute)(&opts)
+	reg.Register(&goregistry.Service{
+		Name:  "foo",
+		Nodes: []*goregistry.Node{{Id: "foo-1", Address: "127.0.0.1:1"}},
+		Endpoints: []*goregistry.Endpoint{
+			{Name: "Foo.Get", Metadata: opts.Metadata["Foo.Get"]},
+			{Name: "Foo.Set", Metadata: map[string]string{}},
+		},
+	}, goregistry.RegisterDomain(namespace.DefaultNamespace))
+
+	var calls int
+	c := New()
+	h := c.Wrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		calls++
+		body, err := req.Read()
+		if err != nil {
+			return err
+		}
+		return rsp.(server.Response).Write(append([]byte("re: "), body...))
+	})
+
+	call := func(endpoint, body string) string {
+		rsp := &testResponse{}
+		if err := h(context.TODO(), &testRequest{endpoint: endpoint, body: []byte(body)}, rsp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return string(rsp.body)
+	}
+
+	if rsp := call("Foo.Get", "a"); rsp != "re: a" || calls != 1 {
+		t.Fatalf("Expected the request to be proxied, got %q after %v calls", rsp, calls)
+	}
+	if rsp := call("Foo.Get", "a"); rsp != "re: a" || calls != 1 {
+		t.Errorf("Expected the response to be cached, got %q after %v calls", rsp, calls)
+	}
+	call("Foo.Get", "b")
+	if calls != 2 {
+		t.Errorf("Expected requests with a different body to be proxied")
+	}
+	call("Foo.Set", "a")
+	call("Foo.Set", "a")
+	if calls != 4 {
+		t.Errorf("Expected requests to endpoints which aren't cacheable to be proxied")
+	}
+
+	if n := c.Invalidate(namespace.DefaultNamespace, "foo", "Foo.Get"); n != 2 {
+		t.Errorf("Expected 2 responses to be invalidated, got %v", n)
+	}
+	call("Foo.Get", "a")
+	if calls != 5 {
+		t.Errorf("Expected the request to be proxied once invalidated")
+	}
+
+	// invalidations only apply to the namespace their publisher was authenticated in
+	ev := func(ns string) *goevents.Event {
+		return &goevents.Event{
+			ID:       "inv",
+			Metadata: map[string]string{util.PublisherNamespaceKey: ns},
+			Payload:  []byte(`{"service": "foo"}`),
+		}
+	}
+	for _, ns := range []string{"", "other"} {
+		if err := c.consume(context.TODO(), ev(ns)); err != nil {
+			t.Fatalf("Unexpected error consuming the invalidation: %v", err)
+		}
+	}
+	call("Foo.Get", "a")
+	if calls != 5 {
+		t.Errorf("Expected the response not to be invalidated by another namespace")
+	}
+	if err := c.consume(context.TODO(), ev(namespace.DefaultNamespace)); err != nil {
+		t.Fatalf("Unexpected error consuming the invalidation: %v", err)
+	}
+	call("Foo.Get", "a")
+	if calls != 6 {
+		t.Errorf("Expected the response to be invalidated")
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/micro/micro/v3/service"
 	muclient "github.com/micro/micro/v3/service/client"
 	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/proxy/cache"
 	murouter "github.com/micro/micro/v3/service/router"
 	"github.com/micro/micro/v3/service/store"
 )
@@ -174,6 +175,15 @@ func Run(ctx *cli.Context) error {
 	// wrap the proxy using the proxy's authHandler
 	authOpt := server.WrapHandler(authHandler())
 	serverOpts = append(serverOpts, authOpt)
+
+	// serve the requests to cacheable endpoints from the cache
+	if !ctx.Bool("disable_cache") {
+		c := cache.New()
+		if err := c.Watch(cache.DefaultTopic); err != nil {
+			log.Warnf("Error watching cache invalidations: %v", err)
+		}
+		serverOpts = append(serverOpts, server.WrapHandler(c.Wrapper()))
+	}
 	serverOpts = append(serverOpts, server.WithRouter(p))
 
 	if len(Endpoint) > 0 {
@@ -228,5 +238,10 @@ var (
 			Usage:   "Set the endpoint to route to e.g greeter or localhost:9090",
 			EnvVars: []string{"MICRO_PROXY_ENDPOINT"},
 		},
+		&cli.BoolFlag{
+			Name:    "disable_cache",
+			Usage:   "Disable caching the responses of cacheable endpoints",
+			EnvVars: []string{"MICRO_PROXY_DISABLE_CACHE"},
+		},
 	)
 )
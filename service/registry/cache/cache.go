@@ -0,0 +1,320 @@
+// Package cache provides a registry wrapper which caches the results of GetService, serving
+// stale entries whilst they are refreshed in the background.
+package cache
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+var (
+	// DefaultTTL is the default time an entry is considered fresh
+	DefaultTTL = time.Minute
+	// DefaultStaleTTL is the default time a stale entry will be served for
+	DefaultStaleTTL = time.Minute
+	// DefaultBackoff is the delay before watching the registry again after the watcher
+	// fails, which doubles on each failure
+	DefaultBackoff = time.Millisecond * 100
+	// DefaultMaxBackoff is the maximum delay before watching the registry again
+	DefaultMaxBackoff = time.Second * 30
+)
+
+type entry struct {
+	services []*registry.Service
+	updated  time.Time
+}
+
+type cache struct {
+	registry.Registry
+	opts Options
+
+	sync.RWMutex
+	// entries keyed by domain and service name
+	entries map[string]map[string]*entry
+	// versions are incremented when entries are invalidated so lookups which started before
+	// don't store their results, keyed by domain and by domain and service name
+	versions map[string]uint64
+	// refreshing tracks the background refreshes in progress
+	refreshing map[string]bool
+	// watched tracks the domains being watched
+	watched map[string]bool
+	exit    chan bool
+}
+
+// New returns a registry which caches the results of GetService
+func New(r registry.Registry, opts ...Option) registry.Registry {
+	options := Options{
+		TTL:      DefaultTTL,
+		StaleTTL: DefaultStaleTTL,
+		Watch:    true,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &cache{
+		Registry:   r,
+		opts:       options,
+		entries:    make(map[string]map[string]*entry),
+		versions:   make(map[string]uint64),
+		refreshing: make(map[string]bool),
+		watched:    make(map[string]bool),
+		exit:       make(chan bool),
+	}
+}
+
+// GetService returns the service from the cache if it is fresh. Stale entries are returned
+// and refreshed in the background, missing or expired entries are looked up in the registry.
+func (c *cache) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	// the cache can't apply options such as the locality, labels or revision of the read, so
+	// these are served by the registry
+	if options.Context != nil {
+		return c.Registry.GetService(name, opts...)
+	}
+
+	domain := options.Domain
+	if len(domain) == 0 {
+		domain = registry.DefaultDomain
+	}
+
+	// start watching the domain so the entries can be invalidated
+	if c.opts.Watch {
+		c.watch(domain)
+	}
+
+	c.RLock()
+	e, ok := c.entries[domain][name]
+	c.RUnlock()
+
+	if ok {
+		age := time.Since(e.updated)
+		if age < c.opts.TTL {
+			return copyServices(e.services), nil
+		}
+		if age < c.opts.TTL+c.opts.StaleTTL {
+			go c.refresh(domain, name)
+			return copyServices(e.services), nil
+		}
+	}
+
+	return c.get(domain, name)
+}
+
+// Register a service and invalidate the cached entry
+func (c *cache) Register(srv *registry.Service, opts ...registry.RegisterOption) error {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	c.del(options.Domain, srv.Name)
+	return c.Registry.Register(srv, opts...)
+}
+
+// Deregister a service and invalidate the cached entry
+func (c *cache) Deregister(srv *registry.Service, opts ...registry.DeregisterOption) error {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	c.del(options.Domain, srv.Name)
+	return c.Registry.Deregister(srv, opts...)
+}
+
+// Stop the cache watching the registry
+func (c *cache) Stop() {
+	c.Lock()
+	defer c.Unlock()
+
+	select {
+	case <-c.exit:
+	default:
+		close(c.exit)
+	}
+}
+
+func (c *cache) String() string {
+	return "cache"
+}
+
+// get the service from the registry and store the result, unless the entry was invalidated
+// whilst it was being looked up since the result may be stale
+func (c *cache) get(domain, name string) ([]*registry.Service, error) {
+	key := domain + "/" + name
+
+	c.RLock()
+	version := c.versions[domain] + c.versions[key]
+	c.RUnlock()
+
+	services, err := c.Registry.GetService(name, registry.GetDomain(domain))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	if c.versions[domain]+c.versions[key] == version {
+		if _, ok := c.entries[domain]; !ok {
+			c.entries[domain] = make(map[string]*entry)
+		}
+		c.entries[domain][name] = &entry{services: copyServices(services), updated: time.Now()}
+	}
+	c.Unlock()
+
+	return services, nil
+}
+
+// refresh the entry in the background, only one refresh per service will run at a time
+func (c *cache) refresh(domain, name string) {
+	key := domain + "/" + name
+
+	c.Lock()
+	if c.refreshing[key] {
+		c.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.Unlock()
+
+	defer func() {
+		c.Lock()
+		delete(c.refreshing, key)
+		c.Unlock()
+	}()
+
+	if _, err := c.get(domain, name); util.IsNotFound(err) {
+		c.del(domain, name)
+	} else if err != nil {
+		logger.Debugf("Error refreshing %v in the registry cache: %v", name, err)
+	}
+}
+
+// del removes an entry from the cache
+func (c *cache) del(domain, name string) {
+	if len(domain) == 0 {
+		domain = registry.DefaultDomain
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.versions[domain+"/"+name]++
+	if d, ok := c.entries[domain]; ok {
+		delete(d, name)
+	}
+}
+
+// watch the domain for changes if it is not already being watched
+func (c *cache) watch(domain string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.watched[domain] {
+		return
+	}
+	c.watched[domain] = true
+
+	go c.run(domain)
+}
+
+// run the watcher for the domain, invalidating entries as results are received
+func (c *cache) run(domain string) {
+	var attempts int
+	for {
+		select {
+		case <-c.exit:
+			return
+		default:
+		}
+
+		// back off whilst watching fails, so a registry which is down isn't overloaded
+		if attempts > 0 && !c.sleep(attempts) {
+			return
+		}
+		attempts++
+
+		w, err := c.Registry.Watch(registry.WatchDomain(domain))
+		if err != nil {
+			logger.Debugf("Error watching the registry for the cache: %v", err)
+			continue
+		}
+
+		// stop the watcher on exit
+		done := make(chan bool)
+		go func() {
+			select {
+			case <-c.exit:
+			case <-done:
+			}
+			w.Stop()
+		}()
+
+		for {
+			res, err := w.Next()
+			if err != nil {
+				break
+			}
+			// the watcher is working, so watch again without delay if it fails
+			attempts = 0
+			if res.Service == nil {
+				continue
+			}
+			c.del(domain, res.Service.Name)
+		}
+		close(done)
+
+		// results may have been missed, so clear the domain before watching again
+		c.Lock()
+		c.versions[domain]++
+		delete(c.entries, domain)
+		c.Unlock()
+	}
+}
+
+// sleep for a random delay of up to the backoff of the attempt, returning false if the cache
+// was stopped
+func (c *cache) sleep(attempts int) bool {
+	d := DefaultBackoff << uint(attempts-1)
+	if d > DefaultMaxBackoff || d <= 0 {
+		d = DefaultMaxBackoff
+	}
+
+	select {
+	case <-c.exit:
+		return false
+	case <-time.After(time.Duration(rand.Int63n(int64(d) + 1))):
+		return true
+	}
+}
+
+// copyServices returns a copy of the services so the cached values can't be modified
+func copyServices(current []*registry.Service) []*registry.Service {
+	services := make([]*registry.Service, len(current))
+	for i, service := range current {
+		s := *service
+
+		s.Nodes = make([]*registry.Node, len(service.Nodes))
+		for j, node := range service.Nodes {
+			n := *node
+			s.Nodes[j] = &n
+		}
+
+		s.Endpoints = make([]*registry.Endpoint, len(service.Endpoints))
+		for j, ep := range service.Endpoints {
+			e := *ep
+			s.Endpoints[j] = &e
+		}
+
+		services[i] = &s
+	}
+	return services
+}
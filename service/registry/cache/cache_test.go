@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKey struct{}
+
+// blockingRegistry blocks GetService until it's unblocked
+type blockingRegistry struct {
+	registry.Registry
+	started chan bool
+	unblock chan bool
+}
+
+func (b *blockingRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	b.started <- true
+	<-b.unblock
+	return b.Registry.GetService(name, opts...)
+}
+
+// failingRegistry fails to watch, counting the attempts
+type failingRegistry struct {
+	registry.Registry
+	attempts int32
+}
+
+func (f *failingRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	atomic.AddInt32(&f.attempts, 1)
+	return nil, errors.New("unavailable")
+}
+
+func testService(version string) *registry.Service {
+	return &registry.Service{
+		Name:    "foo",
+		Version: version,
+		Nodes:   []*registry.Node{{Id: "foo-1", Address: "127.0.0.1:8080"}},
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Run("Fresh", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		c := New(mem, Watch(false), TTL(time.Hour))
+
+		assert.Nil(t, mem.Register(testService("v1")))
+		srvs, err := c.GetService("foo")
+		assert.Nil(t, err)
+		assert.Len(t, srvs, 1)
+
+		// the change should not be visible until the entry expires
+		assert.Nil(t, mem.Deregister(testService("v1")))
+		srvs, err = c.GetService("foo")
+		assert.Nil(t, err)
+		assert.Len(t, srvs, 1)
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		c := New(mem, Watch(false), TTL(time.Millisecond), StaleTTL(time.Hour))
+
+		assert.Nil(t, mem.Register(testService("v1")))
+		_, err := c.GetService("foo")
+		assert.Nil(t, err)
+
+		// the stale entry should be returned and refreshed in the background
+		assert.Nil(t, mem.Register(testService("v2")))
+		time.Sleep(time.Millisecond * 5)
+		srvs, err := c.GetService("foo")
+		assert.Nil(t, err)
+		assert.Len(t, srvs, 1)
+		assert.Equal(t, "v1", srvs[0].Version)
+
+		assert.Eventually(t, func() bool {
+			srvs, err := c.GetService("foo")
+			return err == nil && len(srvs) == 2
+		}, time.Second, time.Millisecond*10)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		c := New(mem, Watch(false), TTL(time.Millisecond), StaleTTL(time.Millisecond))
+
+		assert.Nil(t, mem.Register(testService("v1")))
+		_, err := c.GetService("foo")
+		assert.Nil(t, err)
+
+		assert.Nil(t, mem.Deregister(testService("v1")))
+		time.Sleep(time.Millisecond * 5)
+		_, err = c.GetService("foo")
+		assert.Equal(t, registry.ErrNotFound, err)
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		c := New(mem, Watch(false), TTL(time.Hour))
+
+		assert.Nil(t, mem.Register(testService("v1")))
+		_, err := c.GetService("foo")
+		assert.Nil(t, err)
+
+		// reads with options the cache can't apply, e.g. the locality, should be served by
+		// the registry
+		assert.Nil(t, mem.Deregister(testService("v1")))
+		_, err = c.GetService("foo", func(o *registry.GetOptions) {
+			o.Context = context.WithValue(context.Background(), testKey{}, true)
+		})
+		assert.Equal(t, registry.ErrNotFound, err)
+	})
+
+	t.Run("Invalidated", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		b := &blockingRegistry{Registry: mem, started: make(chan bool), unblock: make(chan bool)}
+		c := New(b, Watch(false), TTL(time.Hour)).(*cache)
+
+		assert.Nil(t, mem.Register(testService("v1")))
+		done := make(chan bool)
+		go func() {
+			c.get(registry.DefaultDomain, "foo")
+			close(done)
+		}()
+
+		// the entry is invalidated whilst it's being looked up, so the result isn't stored
+		<-b.started
+		c.del(registry.DefaultDomain, "foo")
+		close(b.unblock)
+		<-done
+
+		c.RLock()
+		_, ok := c.entries[registry.DefaultDomain]["foo"]
+		c.RUnlock()
+		assert.False(t, ok)
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		c := New(mem, Watch(false), TTL(time.Hour))
+
+		assert.Nil(t, c.Register(testService("v1")))
+		_, err := c.GetService("foo")
+		assert.Nil(t, err)
+
+		// registering through the cache should invalidate the entry
+		assert.Nil(t, c.Register(testService("v2")))
+		srvs, err := c.GetService("foo")
+		assert.Nil(t, err)
+		assert.Len(t, srvs, 2)
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		mem := memory.NewRegistry()
+		c := New(mem, TTL(time.Hour))
+		defer c.(*cache).Stop()
+
+		assert.Nil(t, mem.Register(testService("v1")))
+		_, err := c.GetService("foo")
+		assert.Nil(t, err)
+
+		// the watcher should invalidate the entry
+		assert.Eventually(t, func() bool {
+			mem.Register(testService("v2"))
+			srvs, err := c.GetService("foo")
+			return err == nil && len(srvs) == 2
+		}, time.Second, time.Millisecond*10)
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		f := &failingRegistry{Registry: memory.NewRegistry()}
+		c := New(f, TTL(time.Hour)).(*cache)
+		defer c.Stop()
+
+		// the watcher should back off rather than retrying in a loop
+		c.watch(registry.DefaultDomain)
+		time.Sleep(time.Millisecond * 200)
+		assert.Less(t, int(atomic.LoadInt32(&f.attempts)), 10)
+	})
+}
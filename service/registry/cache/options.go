@@ -0,0 +1,38 @@
+package cache
+
+import "time"
+
+// Options for the registry cache
+type Options struct {
+	// TTL is how long a cached entry is considered fresh
+	TTL time.Duration
+	// StaleTTL is how long after the TTL has passed a stale entry will
+	// continue to be served whilst it is refreshed in the background
+	StaleTTL time.Duration
+	// Watch the registry and invalidate entries when services change
+	Watch bool
+}
+
+// Option sets an option on the cache
+type Option func(o *Options)
+
+// TTL sets how long a cached entry is considered fresh
+func TTL(t time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = t
+	}
+}
+
+// StaleTTL sets how long a stale entry will be served whilst being refreshed
+func StaleTTL(t time.Duration) Option {
+	return func(o *Options) {
+		o.StaleTTL = t
+	}
+}
+
+// Watch sets whether the cache should watch the registry for changes
+func Watch(b bool) Option {
+	return func(o *Options) {
+		o.Watch = b
+	}
+}
@@ -0,0 +1,264 @@
+// Package cli implements the `micro registry` subcommands
+// for example:
+//   micro registry backup
+//   micro registry restore
+//   micro registry audit
+//   micro registry deleted
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "registry",
+		Usage:  "Commands for managing the registry",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "backup",
+				Usage:  "Back up the services in the registry to a file",
+				Action: backup,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "destination",
+						Usage: "File to write the backup to",
+						Value: "registry-backup.json",
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Back up the services in every namespace",
+					},
+				},
+			},
+			{
+				Name:   "restore",
+				Usage:  "Restore the services from a backup",
+				Action: restore,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "source",
+						Usage: "File to restore the backup from",
+						Value: "registry-backup.json",
+					},
+					&cli.StringSliceFlag{
+						Name:  "remap",
+						Usage: "Remap the addresses of nodes, e.g. --remap 10.0.0.1=10.1.0.1 or --remap 10.0.0.1:8080=10.1.0.1:9090",
+					},
+					&cli.DurationFlag{
+						Name:  "ttl",
+						Usage: "TTL to register the restored services with, blank to register them without one",
+					},
+				},
+			},
+			{
+				Name:      "audit",
+				Usage:     "Show who registered and deregistered services",
+				ArgsUsage: "[service]",
+				Action:    auditTrail,
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "since",
+						Usage: "Show the changes made within the duration, e.g. 24h",
+					},
+					&cli.StringFlag{
+						Name:  "until",
+						Usage: "Show the changes made before the time, in RFC3339 format",
+					},
+					&cli.Int64Flag{
+						Name:  "limit",
+						Usage: "Maximum number of changes to show",
+						Value: 100,
+					},
+				},
+			},
+			{
+				Name:      "deleted",
+				Usage:     "Show the services deregistered recently which haven't registered again",
+				ArgsUsage: "[service]",
+				Action:    deleted,
+			},
+		},
+	})
+}
+
+// backup is the entrypoint for micro registry backup
+func backup(ctx *cli.Context) error {
+	domain := goregistry.WildcardDomain
+	if !ctx.Bool("all") {
+		ns, err := namespace.Get(util.GetEnv(ctx).Name)
+		if err != nil {
+			return err
+		}
+		domain = ns
+	}
+
+	srv := pb.NewRegistryService("registry", client.DefaultClient)
+	rsp, err := srv.Export(context.DefaultContext, &pb.ExportRequest{
+		Options: &pb.Options{Domain: domain},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(rsp.Services, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(ctx.String("destination"), bytes, 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backed up %d services to %v\n", len(rsp.Services), ctx.String("destination"))
+	return nil
+}
+
+// restore is the entrypoint for micro registry restore
+func restore(ctx *cli.Context) error {
+	bytes, err := ioutil.ReadFile(ctx.String("source"))
+	if err != nil {
+		return err
+	}
+
+	var services []*pb.Service
+	if err := json.Unmarshal(bytes, &services); err != nil {
+		return fmt.Errorf("Error decoding backup: %v", err)
+	}
+
+	addresses, err := parseRemaps(ctx.StringSlice("remap"))
+	if err != nil {
+		return err
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	srv := pb.NewRegistryService("registry", client.DefaultClient)
+	rsp, err := srv.Import(context.DefaultContext, &pb.ImportRequest{
+		Services:  services,
+		Addresses: addresses,
+		Options:   &pb.Options{Domain: ns, Ttl: int64(ctx.Duration("ttl").Seconds())},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored %d services from %v\n", rsp.Imported, ctx.String("source"))
+	return nil
+}
+
+// parseRemaps parses the addresses to remap, e.g. 10.0.0.1=10.1.0.1, keyed by the old address
+func parseRemaps(remaps []string) (map[string]string, error) {
+	addresses := map[string]string{}
+	for _, r := range remaps {
+		comps := strings.SplitN(r, "=", 2)
+		if len(comps) != 2 || len(comps[0]) == 0 || len(comps[1]) == 0 {
+			return nil, fmt.Errorf("Invalid remap %v, expected old=new", r)
+		}
+		addresses[comps[0]] = comps[1]
+	}
+	return addresses, nil
+}
+
+// auditTrail is the entrypoint for micro registry audit
+func auditTrail(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.AuditRequest{
+		Options: &pb.Options{Domain: ns},
+		Service: ctx.Args().First(),
+		Limit:   ctx.Int64("limit"),
+	}
+	if d := ctx.Duration("since"); d > 0 {
+		req.Since = time.Now().Add(-d).Unix()
+	}
+	if u := ctx.String("until"); len(u) > 0 {
+		t, err := time.Parse(time.RFC3339, u)
+		if err != nil {
+			return fmt.Errorf("Invalid until time: %v", err)
+		}
+		req.Until = t.Unix()
+	}
+
+	srv := pb.NewRegistryService("registry", client.DefaultClient)
+	rsp, err := srv.Audit(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join([]string{"TIME", "ACTION", "SERVICE", "VERSION", "NODES", "ACCOUNT"}, "\t"))
+	for _, e := range rsp.Entries {
+		account := "registry"
+		if len(e.Account) > 0 {
+			account = e.Account
+			if len(e.Issuer) > 0 && e.Issuer != ns {
+				account += " (" + e.Issuer + ")"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			time.Unix(e.Timestamp, 0).Format(time.RFC3339), e.Action, e.Service, e.Version,
+			strings.Join(e.Nodes, ","), account)
+	}
+	return w.Flush()
+}
+
+// deleted is the entrypoint for micro registry deleted
+func deleted(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	srv := pb.NewRegistryService("registry", client.DefaultClient)
+	rsp, err := srv.ListDeleted(context.DefaultContext, &pb.ListDeletedRequest{
+		Options: &pb.Options{Domain: ns},
+		Service: ctx.Args().First(),
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join([]string{"DELETED", "SERVICE", "VERSION", "NODES", "ACCOUNT"}, "\t"))
+	for _, t := range rsp.Tombstones {
+		account := "registry"
+		if len(t.Account) > 0 {
+			account = t.Account
+			if len(t.Issuer) > 0 && t.Issuer != ns {
+				account += " (" + t.Issuer + ")"
+			}
+		}
+		var nodes []string
+		for _, n := range t.Service.Nodes {
+			nodes = append(nodes, n.Id)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			time.Unix(t.Deleted, 0).Format(time.RFC3339), t.Service.Name, t.Service.Version,
+			strings.Join(nodes, ","), account)
+	}
+	return w.Flush()
+}
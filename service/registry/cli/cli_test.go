@@ -0,0 +1,19 @@
+package cli
+
+import "testing"
+
+func TestParseRemaps(t *testing.T) {
+	addresses, err := parseRemaps([]string{"10.0.0.1=10.1.0.1", "10.0.0.2:8080=10.1.0.2:9090"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing remaps: %v", err)
+	}
+	if len(addresses) != 2 || addresses["10.0.0.1"] != "10.1.0.1" || addresses["10.0.0.2:8080"] != "10.1.0.2:9090" {
+		t.Errorf("Unexpected addresses %v", addresses)
+	}
+
+	for _, r := range []string{"10.0.0.1", "=10.1.0.1", "10.0.0.1="} {
+		if _, err := parseRemaps([]string{r}); err == nil {
+			t.Errorf("Expected an error parsing %v", r)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"sync"
 	"time"
 
 	goclient "github.com/micro/go-micro/v3/client"
@@ -18,8 +19,14 @@ type srv struct {
 	opts registry.Options
 	// address
 	address []string
-	// client to call registry
+	// client to call registry, the default client is used if it's nil
 	client pb.RegistryService
+
+	sync.Mutex
+	// registrant identifies the process in the registrations of its nodes
+	registrant string
+	// clocks of the registrations of the nodes, keyed by domain, service and node id
+	clocks map[string]*nodeClock
 }
 
 func (s *srv) callOpts() []goclient.CallOption {
@@ -35,10 +42,20 @@ func (s *srv) callOpts() []goclient.CallOption {
 		opts = append(opts, goclient.WithRequestTimeout(s.opts.Timeout))
 	}
 
-	s.client = pb.NewRegistryService(name, client.DefaultClient)
+	// retry transient errors, calls which aren't idempotent override this
+	opts = append(opts, s.retryOpts()...)
 	return opts
 }
 
+// registryClient returns the client to call the registry with. The default client is looked up
+// on each call since it can be replaced after the registry is created.
+func (s *srv) registryClient() pb.RegistryService {
+	if s.client != nil {
+		return s.client
+	}
+	return pb.NewRegistryService(name, client.DefaultClient)
+}
+
 func (s *srv) Init(opts ...registry.Option) error {
 	for _, o := range opts {
 		o(&s.opts)
@@ -61,13 +78,29 @@ func (s *srv) Register(srv *registry.Service, opts ...registry.RegisterOption) e
 		o(&options)
 	}
 
-	// encode srv into protobuf and pack TTL and domain into it
+	// encode srv into protobuf and pack TTL, domain and session into it
 	pbSrv := util.ToProto(srv)
 	pbSrv.Options.Ttl = int64(options.TTL.Seconds())
 	pbSrv.Options.Domain = options.Domain
+	pbSrv.Options.Session = getSession(options.Context)
+
+	// label the nodes and set their locality, which are kept apart from their metadata
+	labels := getLabels(s.opts.Context)
+	region, zone := getLocality(s.opts.Context)
+	for _, n := range pbSrv.Nodes {
+		n.Labels, n.Region, n.Zone = labels, region, zone
+	}
+
+	// stamp the nodes so the registry can resolve conflicting registrations
+	s.stamp(options.Domain, pbSrv, func() map[string]util.Clock {
+		return s.registeredClocks(options.Domain, pbSrv.Name)
+	})
 
 	// register the service
-	_, err := s.client.Register(context.DefaultContext, pbSrv, s.callOpts()...)
+	_, err := s.registryClient().Register(context.DefaultContext, pbSrv, s.callOpts()...)
+	if verr := errors.Parse(err); verr != nil && verr.Code == 429 {
+		return &QuotaExceededError{Detail: verr.Detail}
+	}
 	return err
 }
 
@@ -77,12 +110,16 @@ func (s *srv) Deregister(srv *registry.Service, opts ...registry.DeregisterOptio
 		o(&options)
 	}
 
-	// encode srv into protobuf and pack domain into it
+	// encode srv into protobuf and pack domain and session into it
 	pbSrv := util.ToProto(srv)
 	pbSrv.Options.Domain = options.Domain
+	pbSrv.Options.Session = getSession(options.Context)
 
 	// deregister the service
-	_, err := s.client.Deregister(context.DefaultContext, pbSrv, s.callOpts()...)
+	_, err := s.registryClient().Deregister(context.DefaultContext, pbSrv, s.callOpts()...)
+	if err == nil {
+		s.unstamp(options.Domain, pbSrv)
+	}
 	return err
 }
 
@@ -92,12 +129,23 @@ func (s *srv) GetService(name string, opts ...registry.GetOption) ([]*registry.S
 		o(&options)
 	}
 
-	rsp, err := s.client.GetService(context.DefaultContext, &pb.GetRequest{
-		Service: name, Options: &pb.Options{Domain: options.Domain},
+	region, zone := getLocality(options.Context)
+	rsp, err := s.registryClient().GetService(context.DefaultContext, &pb.GetRequest{
+		Service: name, Region: region, Zone: zone,
+		Options: &pb.Options{
+			Domain:   options.Domain,
+			Revision: getRevision(options.Context),
+			Labels:   getLabels(options.Context),
+		},
 	}, s.callOpts()...)
 
+	// services which were just deregistered are gone rather than not found
 	if verr := errors.Parse(err); verr != nil && verr.Code == 404 {
 		return nil, registry.ErrNotFound
+	} else if verr != nil && verr.Code == 410 {
+		return nil, util.ErrGone
+	} else if verr != nil && verr.Code == 409 {
+		return nil, ErrRevisionChanged
 	} else if err != nil {
 		return nil, err
 	}
@@ -109,17 +157,134 @@ func (s *srv) GetService(name string, opts ...registry.GetOption) ([]*registry.S
 	return services, nil
 }
 
-func (s *srv) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+// GetServices returns the services with the given names in a single call
+func (s *srv) GetServices(names []string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rsp, err := s.registryClient().GetServices(context.DefaultContext, &pb.GetServicesRequest{
+		Services: names,
+		Options: &pb.Options{
+			Domain:   options.Domain,
+			Revision: getRevision(options.Context),
+			Labels:   getLabels(options.Context),
+		},
+	}, s.callOpts()...)
+	if verr := errors.Parse(err); verr != nil && verr.Code == 409 {
+		return nil, ErrRevisionChanged
+	} else if err != nil {
+		return nil, err
+	}
+
+	services := make([]*registry.Service, 0, len(rsp.Services))
+	for _, service := range rsp.Services {
+		services = append(services, util.ToService(service))
+	}
+	return services, nil
+}
+
+// CreateSession creates a session registrations can be bound to. The session expires
+// unless KeepAlive is called within the ttl.
+func (s *srv) CreateSession(ttl time.Duration, opts ...registry.RegisterOption) (*pb.Session, error) {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// creating a session isn't idempotent, so it isn't retried
+	callOpts := append(s.callOpts(), goclient.WithRetries(0))
+	rsp, err := s.registryClient().CreateSession(context.DefaultContext, &pb.CreateSessionRequest{
+		Ttl: int64(ttl.Seconds()), Options: &pb.Options{Domain: options.Domain},
+	}, callOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Session, nil
+}
+
+// KeepAlive extends the expiry of the session
+func (s *srv) KeepAlive(id string, opts ...registry.RegisterOption) (*pb.Session, error) {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rsp, err := s.registryClient().KeepAlive(context.DefaultContext, &pb.KeepAliveRequest{
+		Id: id, Options: &pb.Options{Domain: options.Domain},
+	}, s.callOpts()...)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Session, nil
+}
+
+// RevokeSession removes the session and all the registrations bound to it
+func (s *srv) RevokeSession(id string, opts ...registry.DeregisterOption) error {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	_, err := s.registryClient().RevokeSession(context.DefaultContext, &pb.RevokeSessionRequest{
+		Id: id, Options: &pb.Options{Domain: options.Domain},
+	}, s.callOpts()...)
+	return err
+}
+
+// Graph returns the dependency graph of the services, optionally limited to those
+// connected to the service
+func (s *srv) Graph(service string, opts ...registry.ListOption) ([]*pb.GraphNode, error) {
 	var options registry.ListOptions
 	for _, o := range opts {
 		o(&options)
 	}
 
-	req := &pb.ListRequest{Options: &pb.Options{Domain: options.Domain}}
-	rsp, err := s.client.ListServices(context.DefaultContext, req, s.callOpts()...)
+	rsp, err := s.registryClient().Graph(context.DefaultContext, &pb.GraphRequest{
+		Service: service, Options: &pb.Options{Domain: options.Domain},
+	}, s.callOpts()...)
 	if err != nil {
 		return nil, err
 	}
+	return rsp.Nodes, nil
+}
+
+// Resolve the nodes of the service and the share of traffic each should receive
+func (s *srv) Resolve(name string, splits []*pb.Split, opts ...registry.GetOption) ([]*pb.WeightedNode, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rsp, err := s.registryClient().Resolve(context.DefaultContext, &pb.ResolveRequest{
+		Service: name, Splits: splits, Options: &pb.Options{Domain: options.Domain},
+	}, s.callOpts()...)
+	if verr := errors.Parse(err); verr != nil && verr.Code == 404 {
+		return nil, registry.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return rsp.Nodes, nil
+}
+
+func (s *srv) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	req := &pb.ListRequest{Options: &pb.Options{
+		Domain:   options.Domain,
+		Revision: getRevision(options.Context),
+		Labels:   getLabels(options.Context),
+	}}
+	rsp, err := s.registryClient().ListServices(context.DefaultContext, req, s.callOpts()...)
+	if verr := errors.Parse(err); verr != nil && verr.Code == 409 {
+		return nil, ErrRevisionChanged
+	} else if err != nil {
+		return nil, err
+	}
 
 	services := make([]*registry.Service, 0, len(rsp.Services))
 	for _, service := range rsp.Services {
@@ -129,14 +294,66 @@ func (s *srv) ListServices(opts ...registry.ListOption) ([]*registry.Service, er
 	return services, nil
 }
 
+// ListPage returns a page of the services whose names have the prefix and the cursor of the
+// next page, blank if it's the last page
+func (s *srv) ListPage(prefix, cursor string, limit int, opts ...registry.ListOption) ([]*registry.Service, string, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	req := &pb.ListRequest{
+		Options: &pb.Options{
+			Domain:   options.Domain,
+			Revision: getRevision(options.Context),
+			Labels:   getLabels(options.Context),
+		},
+		Prefix: prefix,
+		Cursor: cursor,
+		Limit:  int64(limit),
+	}
+	rsp, err := s.registryClient().ListServices(context.DefaultContext, req, s.callOpts()...)
+	if verr := errors.Parse(err); verr != nil && verr.Code == 409 {
+		return nil, "", ErrRevisionChanged
+	} else if verr != nil && verr.Code == 400 {
+		return nil, "", util.ErrInvalidCursor
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	services := make([]*registry.Service, 0, len(rsp.Services))
+	for _, service := range rsp.Services {
+		services = append(services, util.ToService(service))
+	}
+	return services, rsp.NextCursor, nil
+}
+
+// Revision returns the revision of the last change made to the domain
+func (s *srv) Revision(opts ...registry.ListOption) (int64, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rsp, err := s.registryClient().Revision(context.DefaultContext, &pb.RevisionRequest{
+		Options: &pb.Options{Domain: options.Domain},
+	}, s.callOpts()...)
+	if err != nil {
+		return 0, err
+	}
+	return rsp.Revision, nil
+}
+
 func (s *srv) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
 	var options registry.WatchOptions
 	for _, o := range opts {
 		o(&options)
 	}
 
-	stream, err := s.client.Watch(context.DefaultContext, &pb.WatchRequest{
-		Service: options.Service, Options: &pb.Options{Domain: options.Domain},
+	stream, err := s.registryClient().Watch(context.DefaultContext, &pb.WatchRequest{
+		Service: options.Service,
+		Options: &pb.Options{Domain: options.Domain, Labels: getLabels(options.Context)},
+		Since:   getSince(options.Context),
 	}, s.callOpts()...)
 
 	if err != nil {
@@ -169,6 +386,14 @@ func NewRegistry(opts ...registry.Option) registry.Registry {
 	return &srv{
 		opts:    options,
 		address: addrs,
-		client:  pb.NewRegistryService(name, client.DefaultClient),
 	}
 }
+
+// QuotaExceededError is returned by Register when the namespace has reached its quota
+type QuotaExceededError struct {
+	Detail string
+}
+
+func (q *QuotaExceededError) Error() string {
+	return q.Detail
+}
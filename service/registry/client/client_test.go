@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// testRegistry returns the error for every service
+type testRegistry struct {
+	pb.RegistryService
+	err error
+	// services returned by GetServices
+	services []*pb.Service
+	// request is the last GetServices request
+	request *pb.GetServicesRequest
+}
+
+func (t *testRegistry) GetService(ctx context.Context, in *pb.GetRequest, opts ...goclient.CallOption) (*pb.GetResponse, error) {
+	return nil, t.err
+}
+
+func (t *testRegistry) GetServices(ctx context.Context, in *pb.GetServicesRequest, opts ...goclient.CallOption) (*pb.GetServicesResponse, error) {
+	t.request = in
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &pb.GetServicesResponse{Services: t.services}, nil
+}
+
+func TestGetServiceNotFound(t *testing.T) {
+	tt := []struct {
+		Name string
+		Err  error
+		Want error
+	}{
+		{"NotFound", errors.NotFound("registry.Registry.GetService", "service not found"), registry.ErrNotFound},
+		{"Gone", errors.Gone("registry.Registry.GetService", "service foo was deregistered"), util.ErrGone},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			s := &srv{client: &testRegistry{err: tc.Err}}
+			if _, err := s.GetService("foo"); err != tc.Want {
+				t.Errorf("Expected %v, got %v", tc.Want, err)
+			}
+			if _, err := s.GetService("foo"); !util.IsNotFound(err) {
+				t.Errorf("Expected the service not to be found")
+			}
+		})
+	}
+}
+
+func TestGetServices(t *testing.T) {
+	r := &testRegistry{services: []*pb.Service{
+		{Name: "foo", Version: "v1", Nodes: []*pb.Node{{Id: "foo-1"}}},
+		{Name: "bar", Version: "latest"},
+	}}
+	s := &srv{client: r}
+
+	services, err := s.GetServices([]string{"foo", "bar"}, registry.GetDomain("other"))
+	if err != nil {
+		t.Fatalf("Unexpected error getting services: %v", err)
+	}
+	if len(services) != 2 || services[0].Name != "foo" || len(services[0].Nodes) != 1 || services[1].Name != "bar" {
+		t.Errorf("Expected foo and bar, got %v", services)
+	}
+	if len(r.request.Services) != 2 || r.request.Options.Domain != "other" {
+		t.Errorf("Expected the names and domain to be requested, got %v", r.request)
+	}
+
+	r.err = errors.InternalServerError("registry.Registry.GetServices", "boom")
+	if _, err := s.GetServices([]string{"foo"}); err == nil {
+		t.Errorf("Expected the error to be returned")
+	}
+	r.err = errors.Conflict("registry.Registry.GetServices", "registry has changed since revision 1")
+	if _, err := s.GetServices([]string{"foo"}); err != ErrRevisionChanged {
+		t.Errorf("Expected %v, got %v", ErrRevisionChanged, err)
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// nodeClock is the clock of the last registration of a node by the process
+type nodeClock struct {
+	// registration the clock was advanced for
+	registration string
+	clock        util.Clock
+}
+
+func clockKey(domain string, srv *pb.Service, node *pb.Node) string {
+	return domain + "/" + srv.Name + "/" + srv.Version + "/" + node.Id
+}
+
+// registration identifies the address and metadata the node is registered with
+func registration(node *pb.Node) string {
+	md := make(map[string]string, len(node.Metadata))
+	for k, v := range node.Metadata {
+		if k != util.ClockKey && k != util.RegistrantKey {
+			md[k] = v
+		}
+	}
+	// maps are printed sorted by key
+	return fmt.Sprintf("%v %v", node.Address, md)
+}
+
+// stamp sets the registrant and clock of the nodes of the service so the registry can resolve
+// conflicting registrations of the same node id, unless the nodes set them already. The clock
+// advances when the registration of a node changes rather than each time it's renewed, and
+// includes the clock of the node registered with the id, returned by registered, so the
+// registration happens after the one it replaces.
+func (s *srv) stamp(domain string, srv *pb.Service, registered func() map[string]util.Clock) {
+	// the registered nodes are only looked up if a registration changed
+	s.Lock()
+	var changed bool
+	for _, n := range srv.Nodes {
+		c, ok := s.clocks[clockKey(domain, srv, n)]
+		if !ok || c.registration != registration(n) {
+			changed = true
+		}
+	}
+	s.Unlock()
+
+	var existing map[string]util.Clock
+	if changed {
+		existing = registered()
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.registrant) == 0 {
+		s.registrant = uuid.New().String()
+	}
+	if s.clocks == nil {
+		s.clocks = make(map[string]*nodeClock)
+	}
+
+	for _, n := range srv.Nodes {
+		if n.Metadata == nil {
+			n.Metadata = make(map[string]string)
+		}
+		if _, ok := n.Metadata[util.RegistrantKey]; !ok {
+			n.Metadata[util.RegistrantKey] = s.registrant
+		}
+		if _, ok := n.Metadata[util.ClockKey]; ok {
+			continue
+		}
+
+		key := clockKey(domain, srv, n)
+		reg := registration(n)
+		c, ok := s.clocks[key]
+		if !ok || c.registration != reg {
+			clock := util.Clock{}
+			if ok {
+				for k, v := range c.clock {
+					clock[k] = v
+				}
+			}
+			for k, v := range existing[n.Id] {
+				if v > clock[k] {
+					clock[k] = v
+				}
+			}
+			clock[util.Registrant(n)]++
+
+			c = &nodeClock{registration: reg, clock: clock}
+			s.clocks[key] = c
+		}
+		n.Metadata[util.ClockKey] = c.clock.String()
+	}
+}
+
+// unstamp forgets the clocks of the nodes once they're deregistered
+func (s *srv) unstamp(domain string, srv *pb.Service) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, n := range srv.Nodes {
+		delete(s.clocks, clockKey(domain, srv, n))
+	}
+}
+
+// registeredClocks returns the clocks of the nodes of the service which are registered, by id
+func (s *srv) registeredClocks(domain, name string) map[string]util.Clock {
+	clocks := make(map[string]util.Clock)
+
+	opts := s.callOpts()
+	rsp, err := s.registryClient().GetService(context.DefaultContext, &pb.GetRequest{
+		Service: name, Options: &pb.Options{Domain: domain},
+	}, opts...)
+	if err != nil {
+		return clocks
+	}
+
+	for _, srv := range rsp.Services {
+		for _, n := range srv.Nodes {
+			clocks[n.Id] = util.ParseClock(n.Metadata[util.ClockKey])
+		}
+	}
+	return clocks
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"testing"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+func TestStamp(t *testing.T) {
+	s := new(srv)
+
+	var lookups int
+	registered := make(map[string]util.Clock)
+	lookup := func() map[string]util.Clock {
+		lookups++
+		return registered
+	}
+
+	register := func(node *pb.Node) *pb.Node {
+		s.stamp("micro", &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{node}}, lookup)
+		return node
+	}
+
+	n := register(&pb.Node{Id: "foo-1", Address: "10.0.0.1:8080"})
+	registrant := n.Metadata[util.RegistrantKey]
+	if len(registrant) == 0 {
+		t.Fatalf("Expected the node to be stamped with the registrant")
+	}
+	if c := n.Metadata[util.ClockKey]; c != registrant+"=1" {
+		t.Fatalf("Expected the clock to start at 1, got %v", c)
+	}
+
+	// renewing the registration doesn't advance the clock or look up the registered nodes
+	n = register(&pb.Node{Id: "foo-1", Address: "10.0.0.1:8080"})
+	if c := n.Metadata[util.ClockKey]; c != registrant+"=1" || lookups != 1 {
+		t.Errorf("Expected the clock not to advance, got %v after %v lookups", c, lookups)
+	}
+
+	// changing the registration happens after the node registered with the id
+	registered["foo-1"] = util.Clock{"other": 3}
+	n = register(&pb.Node{Id: "foo-1", Address: "10.0.0.2:8080"})
+	c := util.ParseClock(n.Metadata[util.ClockKey])
+	if c[registrant] != 2 || c["other"] != 3 {
+		t.Errorf("Expected the clock to advance and include the registered clock, got %v", c)
+	}
+	if c.Compare(registered["foo-1"]) != util.After {
+		t.Errorf("Expected the registration to happen after the registered node")
+	}
+
+	// deregistered nodes start again from the registered clock
+	s.unstamp("micro", &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1"}}})
+	registered = make(map[string]util.Clock)
+	n = register(&pb.Node{Id: "foo-1", Address: "10.0.0.2:8080"})
+	if c := n.Metadata[util.ClockKey]; c != registrant+"=1" {
+		t.Errorf("Expected the clock to start again, got %v", c)
+	}
+
+	// nodes which set their registrant and clock keep them
+	md := map[string]string{util.RegistrantKey: "a", util.ClockKey: "a=5"}
+	n = register(&pb.Node{Id: "foo-2", Address: "10.0.0.3:8080", Metadata: md})
+	if n.Metadata[util.RegistrantKey] != "a" || n.Metadata[util.ClockKey] != "a=5" {
+		t.Errorf("Expected the registrant and clock of the node to be kept, got %v", n.Metadata)
+	}
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/micro/go-micro/v3/client"
 	"github.com/micro/go-micro/v3/registry"
@@ -19,3 +21,226 @@ func WithClient(c client.Client) registry.Option {
 		o.Context = context.WithValue(o.Context, clientKey{}, c)
 	}
 }
+
+type retriesKey struct{}
+
+// Retries sets the number of times calls to the registry are retried on transient errors,
+// e.g. when the registry is restarting. Zero disables retries.
+func Retries(n int) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, retriesKey{}, n)
+	}
+}
+
+type backoffKey struct{}
+
+type backoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+// Backoff sets the delay before the first retry, which doubles on each retry up to the max.
+// A random delay up to the backoff is used so clients don't retry in lockstep.
+func Backoff(base, max time.Duration) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, backoffKey{}, backoff{base, max})
+	}
+}
+
+type sessionKey struct{}
+
+// RegisterSession binds the registration to a session created with CreateSession. The
+// registration is removed when the session expires or is revoked rather than using a ttl.
+func RegisterSession(id string) registry.RegisterOption {
+	return func(o *registry.RegisterOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, sessionKey{}, id)
+	}
+}
+
+// DeregisterSession removes the registration from the session it was bound to
+func DeregisterSession(id string) registry.DeregisterOption {
+	return func(o *registry.DeregisterOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, sessionKey{}, id)
+	}
+}
+
+func getSession(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(sessionKey{}).(string)
+	return id
+}
+
+type sinceKey struct{}
+
+// WatchSince replays the changes made after the sequence before watching. Sequences count the
+// changes to the domain, the sequence of the last change received is returned by the
+// watcher's Sequence method.
+func WatchSince(sequence int64) registry.WatchOption {
+	return func(o *registry.WatchOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, sinceKey{}, sequence)
+	}
+}
+
+func getSince(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+	since, _ := ctx.Value(sinceKey{}).(int64)
+	return since
+}
+
+// ErrRevisionChanged is returned by reads made at a revision if the services read have changed
+// since the revision
+var ErrRevisionChanged = errors.New("registry has changed since the revision")
+
+type revisionKey struct{}
+
+// GetAtRevision makes the read consistent with the revision returned by Revision, failing with
+// ErrRevisionChanged if the service has changed since
+func GetAtRevision(revision int64) registry.GetOption {
+	return func(o *registry.GetOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, revisionKey{}, revision)
+	}
+}
+
+// ListAtRevision makes the list consistent with the revision returned by Revision, failing
+// with ErrRevisionChanged if any service in the domain has changed since
+func ListAtRevision(revision int64) registry.ListOption {
+	return func(o *registry.ListOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, revisionKey{}, revision)
+	}
+}
+
+func getRevision(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+	rev, _ := ctx.Value(revisionKey{}).(int64)
+	return rev
+}
+
+type localityKey struct{}
+
+type locality struct {
+	region string
+	zone   string
+}
+
+// Locality the nodes the client registers are in, e.g. region eu-west-1 and zone eu-west-1a.
+// The locality is kept apart from the metadata of the nodes, callers can prefer the nodes in
+// their locality with GetLocality.
+func Locality(region, zone string) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, localityKey{}, locality{region, zone})
+	}
+}
+
+// GetLocality prefers the nodes in the zone, then those in the region, returning all the nodes
+// if none are in the region
+func GetLocality(region, zone string) registry.GetOption {
+	return func(o *registry.GetOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, localityKey{}, locality{region, zone})
+	}
+}
+
+func getLocality(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	}
+	l, _ := ctx.Value(localityKey{}).(locality)
+	return l.region, l.zone
+}
+
+type labelsKey struct{}
+
+// Labels the nodes the client registers are labelled with, e.g. canary=true. Labels are kept
+// apart from the metadata of the nodes and can be used to select the nodes of a service or to
+// split traffic between them with Resolve.
+func Labels(labels map[string]string) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, labelsKey{}, labels)
+	}
+}
+
+// GetLabels only returns the nodes which have all the labels
+func GetLabels(labels map[string]string) registry.GetOption {
+	return func(o *registry.GetOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, labelsKey{}, labels)
+	}
+}
+
+// ListLabels only lists the nodes which have all the labels
+func ListLabels(labels map[string]string) registry.ListOption {
+	return func(o *registry.ListOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, labelsKey{}, labels)
+	}
+}
+
+// WatchLabels only watches the nodes which have all the labels
+func WatchLabels(labels map[string]string) registry.WatchOption {
+	return func(o *registry.WatchOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, labelsKey{}, labels)
+	}
+}
+
+func getLabels(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	labels, _ := ctx.Value(labelsKey{}).(map[string]string)
+	return labels
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// DefaultRetries is the number of times calls to the registry are retried on transient errors
+	DefaultRetries = 3
+	// DefaultBackoff is the delay before the first retry, which doubles on each retry
+	DefaultBackoff = time.Millisecond * 100
+	// DefaultMaxBackoff is the maximum delay between retries
+	DefaultMaxBackoff = time.Second * 2
+)
+
+// retryOpts returns the call options which retry transient errors. Only calls which are
+// idempotent should be retried, e.g. creating a session isn't.
+func (s *srv) retryOpts() []goclient.CallOption {
+	retries, base, max := DefaultRetries, DefaultBackoff, DefaultMaxBackoff
+	if ctx := s.opts.Context; ctx != nil {
+		if r, ok := ctx.Value(retriesKey{}).(int); ok {
+			retries = r
+		}
+		if b, ok := ctx.Value(backoffKey{}).(backoff); ok {
+			base, max = b.base, b.max
+		}
+	}
+
+	return []goclient.CallOption{
+		goclient.WithRetries(retries),
+		goclient.WithRetry(retryTransient),
+		goclient.WithBackoff(jitterBackoff(base, max)),
+	}
+}
+
+// retryTransient retries errors which may succeed if retried, such as timeouts and errors
+// connecting to the registry, but not errors returned by the registry for the request
+func retryTransient(ctx context.Context, req goclient.Request, retryCount int, err error) (bool, error) {
+	verr := errors.Parse(err)
+	if verr == nil {
+		return true, nil
+	}
+
+	switch verr.Code {
+	case 408, 500, 502, 503, 504:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// jitterBackoff returns a random delay of up to the exponential backoff of the attempt, so
+// clients which failed at the same time don't retry at the same time
+func jitterBackoff(base, max time.Duration) goclient.BackoffFunc {
+	return func(ctx context.Context, req goclient.Request, attempts int) (time.Duration, error) {
+		if attempts == 0 || base <= 0 {
+			return 0, nil
+		}
+
+		d := base << uint(attempts-1)
+		if d > max || d <= 0 {
+			d = max
+		}
+		return time.Duration(rand.Int63n(int64(d) + 1)), nil
+	}
+}
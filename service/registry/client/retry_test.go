@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goerrors "github.com/micro/go-micro/v3/errors"
+)
+
+func TestRetryTransient(t *testing.T) {
+	tt := []struct {
+		Err   error
+		Retry bool
+	}{
+		{errors.New("connection refused"), true},
+		{goerrors.Timeout("registry", "timeout"), true},
+		{goerrors.InternalServerError("registry", "error"), true},
+		{goerrors.NotFound("registry", "not found"), false},
+		{goerrors.Forbidden("registry", "forbidden"), false},
+		{goerrors.Conflict("registry", "conflict"), false},
+	}
+
+	for _, tc := range tt {
+		if retry, _ := retryTransient(context.TODO(), nil, 0, tc.Err); retry != tc.Retry {
+			t.Errorf("Expected retry to be %v for %v", tc.Retry, tc.Err)
+		}
+	}
+}
+
+func TestJitterBackoff(t *testing.T) {
+	backoff := jitterBackoff(time.Millisecond*100, time.Millisecond*250)
+
+	if d, _ := backoff(context.TODO(), nil, 0); d != 0 {
+		t.Errorf("Expected no delay before the first attempt, got %v", d)
+	}
+	for attempt, max := range []time.Duration{0, time.Millisecond * 100, time.Millisecond * 200, time.Millisecond * 250, time.Millisecond * 250} {
+		for i := 0; i < 100; i++ {
+			if d, _ := backoff(context.TODO(), nil, attempt); d < 0 || d > max {
+				t.Fatalf("Expected a delay of up to %v for attempt %v, got %v", max, attempt, d)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/micro/go-micro/v3/registry"
@@ -11,6 +12,8 @@ import (
 type serviceWatcher struct {
 	stream pb.Registry_WatchService
 	closed chan bool
+	// sequence of the last result received
+	sequence int64
 }
 
 func (s *serviceWatcher) Next() (*registry.Result, error) {
@@ -45,6 +48,8 @@ func (s *serviceWatcher) Next() (*registry.Result, error) {
 			continue
 		}
 
+		atomic.StoreInt64(&s.sequence, r.Sequence)
+
 		return &registry.Result{
 			Action:  r.Action,
 			Service: util.ToService(r.Service),
@@ -52,6 +57,12 @@ func (s *serviceWatcher) Next() (*registry.Result, error) {
 	}
 }
 
+// Sequence returns the sequence of the last result received, which can be passed to
+// WatchSince to resume watching from it
+func (s *serviceWatcher) Sequence() int64 {
+	return atomic.LoadInt64(&s.sequence)
+}
+
 func (s *serviceWatcher) Stop() {
 	select {
 	case <-s.closed:
@@ -0,0 +1,219 @@
+// Package hybrid provides a registry which merges the services discovered locally, e.g. using
+// mDNS, with those in a remote registry. Services running locally are preferred, so a developer
+// can run a service on their machine against a shared environment.
+package hybrid
+
+import (
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// DefaultRetryInterval is how long the local registry is watched again after, when watching it
+// fails
+var DefaultRetryInterval = time.Second * 5
+
+type hybrid struct {
+	local  registry.Registry
+	remote registry.Registry
+}
+
+// New returns a registry which registers services locally and looks them up in both the local
+// and remote registries. When a service is found locally, the remote instances are ignored.
+func New(local, remote registry.Registry) registry.Registry {
+	return &hybrid{local: local, remote: remote}
+}
+
+// Init the remote registry, the local registry is configured when it's created
+func (h *hybrid) Init(opts ...registry.Option) error {
+	return h.remote.Init(opts...)
+}
+
+func (h *hybrid) Options() registry.Options {
+	return h.remote.Options()
+}
+
+// Register the service locally so it isn't routed to by the shared environment
+func (h *hybrid) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	return h.local.Register(s, opts...)
+}
+
+func (h *hybrid) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
+	return h.local.Deregister(s, opts...)
+}
+
+// GetService returns the local instances of the service if there are any, otherwise the remote
+// instances are returned
+func (h *hybrid) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	if srvs, err := h.local.GetService(name, opts...); err == nil && hasNodes(srvs) {
+		return srvs, nil
+	}
+	return h.remote.GetService(name, opts...)
+}
+
+// ListServices returns the services in both registries, services found locally replace those
+// with the same name in the remote registry
+func (h *hybrid) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	remote, err := h.remote.ListServices(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the local registry is best effort, e.g. mDNS may be unavailable on the network
+	local, _ := h.local.ListServices(opts...)
+
+	names := make(map[string]bool, len(local))
+	for _, s := range local {
+		names[s.Name] = true
+	}
+
+	services := local
+	for _, s := range remote {
+		if !names[s.Name] {
+			services = append(services, s)
+		}
+	}
+	return services, nil
+}
+
+// Watch both registries, the results of each are merged. The watcher fails when the remote
+// watcher does, the local registry is watched again when watching it fails.
+func (h *hybrid) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	rw, err := h.remote.Watch(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		results: make(chan *registry.Result),
+		errors:  make(chan error, 1),
+		exit:    make(chan bool),
+		remote:  rw,
+	}
+	go func() {
+		if err := w.forward(rw); err != nil {
+			w.errors <- err
+		}
+	}()
+	lw, err := h.local.Watch(opts...)
+	go w.watchLocal(h.local, opts, lw, err)
+
+	return w, nil
+}
+
+func (h *hybrid) String() string {
+	return "hybrid"
+}
+
+func hasNodes(srvs []*registry.Service) bool {
+	for _, s := range srvs {
+		if len(s.Nodes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// watcher merges the results of the local and remote watchers
+type watcher struct {
+	results chan *registry.Result
+	errors  chan error
+	exit    chan bool
+	remote  registry.Watcher
+	once    sync.Once
+
+	sync.Mutex
+	// local is the current watcher of the local registry, nil while it's being watched again
+	local registry.Watcher
+}
+
+// forward the results of the watcher until it fails or the watcher is stopped, in which case
+// nil is returned
+func (w *watcher) forward(rw registry.Watcher) error {
+	for {
+		res, err := rw.Next()
+		if err != nil {
+			select {
+			case <-w.exit:
+				return nil
+			default:
+				return err
+			}
+		}
+
+		select {
+		case w.results <- res:
+		case <-w.exit:
+			return nil
+		}
+	}
+}
+
+// watchLocal forwards the results of the local watcher until the watcher is stopped, watching
+// the local registry again whenever it fails, e.g. mDNS may be unavailable until the network
+// is up
+func (w *watcher) watchLocal(local registry.Registry, opts []registry.WatchOption, lw registry.Watcher, err error) {
+	for {
+		if err == nil {
+			if !w.setLocal(lw) {
+				lw.Stop()
+				return
+			}
+			err = w.forward(lw)
+			w.setLocal(nil)
+			lw.Stop()
+			if err == nil {
+				return
+			}
+		}
+		logger.Warnf("Error watching the local registry, watching it again in %v: %v", DefaultRetryInterval, err)
+
+		select {
+		case <-w.exit:
+			return
+		case <-time.After(DefaultRetryInterval):
+		}
+		lw, err = local.Watch(opts...)
+	}
+}
+
+// setLocal sets the current local watcher, returning false if the watcher has been stopped
+func (w *watcher) setLocal(lw registry.Watcher) bool {
+	w.Lock()
+	defer w.Unlock()
+	select {
+	case <-w.exit:
+		return false
+	default:
+	}
+	w.local = lw
+	return true
+}
+
+func (w *watcher) Next() (*registry.Result, error) {
+	select {
+	case res := <-w.results:
+		return res, nil
+	case err := <-w.errors:
+		w.Stop()
+		return nil, err
+	case <-w.exit:
+		return nil, registry.ErrWatcherStopped
+	}
+}
+
+func (w *watcher) Stop() {
+	w.once.Do(func() {
+		w.Lock()
+		close(w.exit)
+		local := w.local
+		w.Unlock()
+
+		w.remote.Stop()
+		if local != nil {
+			local.Stop()
+		}
+	})
+}
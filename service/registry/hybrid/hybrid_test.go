@@ -0,0 +1,134 @@
+package hybrid
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+)
+
+func TestHybrid(t *testing.T) {
+	local := memory.NewRegistry()
+	remote := memory.NewRegistry()
+	h := New(local, remote)
+
+	service := func(name, node string) *registry.Service {
+		return &registry.Service{Name: name, Version: "latest", Nodes: []*registry.Node{{Id: node}}}
+	}
+	remote.Register(service("foo", "foo-remote"))
+	remote.Register(service("bar", "bar-remote"))
+
+	w, err := h.Watch()
+	if err != nil {
+		t.Fatalf("Unexpected error watching: %v", err)
+	}
+	defer w.Stop()
+
+	// services are registered locally
+	if err := h.Register(service("foo", "foo-local")); err != nil {
+		t.Fatalf("Unexpected error registering: %v", err)
+	}
+	if srvs, _ := remote.GetService("foo"); len(srvs[0].Nodes) != 1 {
+		t.Errorf("Expected the service to only be registered locally")
+	}
+
+	// local instances are preferred
+	srvs, err := h.GetService("foo")
+	if err != nil || len(srvs) != 1 || srvs[0].Nodes[0].Id != "foo-local" {
+		t.Errorf("Expected the local instance of foo, got %v %v", srvs, err)
+	}
+	srvs, err = h.GetService("bar")
+	if err != nil || len(srvs) != 1 || srvs[0].Nodes[0].Id != "bar-remote" {
+		t.Errorf("Expected the remote instance of bar, got %v %v", srvs, err)
+	}
+
+	list, err := h.ListServices()
+	if err != nil || len(list) != 2 {
+		t.Errorf("Expected the services to be merged, got %v %v", list, err)
+	}
+
+	// the local registration is observed by the watcher. The memory registry sends its events
+	// asynchronously, so those of the remote registrations may be observed too.
+	results := make(chan *registry.Result)
+	go func() {
+		for {
+			res, err := w.Next()
+			if err != nil {
+				return
+			}
+			if res.Service.Name == "foo" && res.Service.Nodes[0].Id == "foo-local" {
+				results <- res
+				return
+			}
+		}
+	}()
+	select {
+	case <-results:
+	case <-time.After(time.Second):
+		t.Errorf("Expected a watch result")
+	}
+}
+
+// failingRegistry fails to watch the registry the first time, and the first watcher it returns
+// fails once it's watched
+type failingRegistry struct {
+	registry.Registry
+	watches int
+}
+
+func (f *failingRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	f.watches++
+	switch f.watches {
+	case 1:
+		return nil, errors.New("network down")
+	case 2:
+		return failingWatcher{}, nil
+	}
+	return f.Registry.Watch(opts...)
+}
+
+type failingWatcher struct{}
+
+func (failingWatcher) Next() (*registry.Result, error) {
+	return nil, errors.New("network down")
+}
+
+func (failingWatcher) Stop() {}
+
+func TestHybridWatchRetry(t *testing.T) {
+	defer func(d time.Duration) { DefaultRetryInterval = d }(DefaultRetryInterval)
+	DefaultRetryInterval = time.Millisecond
+
+	local := &failingRegistry{Registry: memory.NewRegistry()}
+	h := New(local, memory.NewRegistry())
+
+	w, err := h.Watch()
+	if err != nil {
+		t.Fatalf("Unexpected error watching: %v", err)
+	}
+	defer w.Stop()
+
+	// the local registry is watched again until it succeeds, without failing the watcher
+	results := make(chan *registry.Result)
+	go func() {
+		res, err := w.Next()
+		if err == nil {
+			results <- res
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		h.Register(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{{Id: "foo-" + strconv.Itoa(i)}}})
+		select {
+		case res := <-results:
+			if res.Service.Name != "foo" {
+				t.Errorf("Unexpected result for %v", res.Service.Name)
+			}
+			return
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+	t.Errorf("Expected a watch result once the local registry was watched again")
+}
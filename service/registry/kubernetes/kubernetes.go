@@ -0,0 +1,469 @@
+// Package kubernetes is a client for the subset of the kubernetes API used to mirror the
+// registry into kubernetes services and endpoint slices, and vice versa
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ManagerName identifies the resources created by the registry
+	ManagerName = "registry.micro.mu"
+	// ServiceAnnotation is set to the name of the micro service a kubernetes service mirrors
+	ServiceAnnotation = "micro.mu/service"
+
+	managedByLabel     = "app.kubernetes.io/managed-by"
+	sliceManagedBy     = "endpointslice.kubernetes.io/managed-by"
+	sliceServiceLabel  = "kubernetes.io/service-name"
+	serviceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// microTime is the format of the times of a lease
+	microTime = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var (
+	// ErrNotInCluster is returned when the kubernetes service account isn't mounted
+	ErrNotInCluster = errors.New("not running in a kubernetes cluster")
+
+	nameRegex = regexp.MustCompile("[^a-z0-9]+")
+)
+
+// Cluster is the subset of the kubernetes API used to sync the registry
+type Cluster interface {
+	// Services returns the services in the namespace and their ready endpoints
+	Services() ([]*Service, error)
+	// Apply creates or updates a service mirroring a micro service and its endpoints
+	Apply(*Service) error
+	// Delete a service created with Apply and its endpoints
+	Delete(name string) error
+	// AcquireLease acquires or renews the lease for the holder, returning false if it's held
+	// by another holder whose lease hasn't expired
+	AcquireLease(name, holder string, ttl time.Duration) (bool, error)
+}
+
+// Service is a kubernetes service
+type Service struct {
+	// Name of the service, see ResourceName
+	Name string
+	// Labels of the service
+	Labels map[string]string
+	// Annotations of the service
+	Annotations map[string]string
+	// Endpoints the service is served at
+	Endpoints []Endpoint
+}
+
+// Managed returns true if the service was created by the registry
+func (s *Service) Managed() bool {
+	return s.Labels[managedByLabel] == ManagerName
+}
+
+// Endpoint of a service
+type Endpoint struct {
+	IP   string
+	Port int
+}
+
+// Address returns the host:port of the endpoint
+func (e Endpoint) Address() string {
+	return net.JoinHostPort(e.IP, strconv.Itoa(e.Port))
+}
+
+// ResourceName returns the name of the micro service as a valid kubernetes resource name
+func ResourceName(name string) string {
+	name = strings.Trim(nameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// NewCluster returns a client for the namespace using the service account the process is
+// running with
+func NewCluster(namespace string) (Cluster, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(host) == 0 || len(port) == 0 {
+		return nil, ErrNotInCluster
+	}
+
+	token, err := ioutil.ReadFile(filepath.Join(serviceAccountPath, "token"))
+	if err != nil {
+		return nil, ErrNotInCluster
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(serviceAccountPath, "ca.crt"))
+	if err != nil {
+		return nil, ErrNotInCluster
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid service account certificate")
+	}
+
+	return &cluster{
+		host:      "https://" + net.JoinHostPort(host, port),
+		namespace: namespace,
+		token:     strings.TrimSpace(string(token)),
+		client: &http.Client{
+			Timeout:   time.Second * 30,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+	}, nil
+}
+
+type cluster struct {
+	host      string
+	namespace string
+	token     string
+	client    *http.Client
+}
+
+type metadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type servicePort struct {
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+type service struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Metadata   metadata `json:"metadata"`
+	Spec       struct {
+		Ports []servicePort `json:"ports,omitempty"`
+	} `json:"spec"`
+}
+
+type endpointPort struct {
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+type endpoint struct {
+	Addresses  []string `json:"addresses"`
+	Conditions struct {
+		Ready *bool `json:"ready,omitempty"`
+	} `json:"conditions"`
+}
+
+type endpointSlice struct {
+	APIVersion  string         `json:"apiVersion"`
+	Kind        string         `json:"kind"`
+	Metadata    metadata       `json:"metadata"`
+	AddressType string         `json:"addressType"`
+	Endpoints   []endpoint     `json:"endpoints"`
+	Ports       []endpointPort `json:"ports"`
+}
+
+type lease struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name            string `json:"name"`
+		ResourceVersion string `json:"resourceVersion,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		HolderIdentity       string `json:"holderIdentity"`
+		LeaseDurationSeconds int    `json:"leaseDurationSeconds"`
+		AcquireTime          string `json:"acquireTime,omitempty"`
+		RenewTime            string `json:"renewTime,omitempty"`
+	} `json:"spec"`
+}
+
+// expired returns true if the lease wasn't renewed within its duration
+func (l *lease) expired() bool {
+	renewed, err := time.Parse(microTime, l.Spec.RenewTime)
+	if err != nil {
+		return true
+	}
+	return time.Since(renewed) > time.Duration(l.Spec.LeaseDurationSeconds)*time.Second
+}
+
+// statusError is returned when the kubernetes API responds with an unsuccessful status
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (s *statusError) Error() string {
+	return s.msg
+}
+
+// isStatus returns true if the error is an unsuccessful response with the status code
+func isStatus(err error, code int) bool {
+	serr, ok := err.(*statusError)
+	return ok && serr.code == code
+}
+
+func (c *cluster) servicesPath() string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/services", c.namespace)
+}
+
+func (c *cluster) slicesPath() string {
+	return fmt.Sprintf("/apis/discovery.k8s.io/v1/namespaces/%s/endpointslices", c.namespace)
+}
+
+func (c *cluster) leasesPath() string {
+	return fmt.Sprintf("/apis/coordination.k8s.io/v1/namespaces/%s/leases", c.namespace)
+}
+
+func (c *cluster) Services() ([]*Service, error) {
+	var services struct {
+		Items []service `json:"items"`
+	}
+	if err := c.do("GET", c.servicesPath(), nil, nil, &services); err != nil {
+		return nil, err
+	}
+	var slices struct {
+		Items []endpointSlice `json:"items"`
+	}
+	if err := c.do("GET", c.slicesPath(), nil, nil, &slices); err != nil {
+		return nil, err
+	}
+
+	// the ready endpoints of each service
+	endpoints := make(map[string][]Endpoint)
+	for _, s := range slices.Items {
+		name := s.Metadata.Labels[sliceServiceLabel]
+		if len(name) == 0 {
+			continue
+		}
+		for _, e := range s.Endpoints {
+			if e.Conditions.Ready != nil && !*e.Conditions.Ready {
+				continue
+			}
+			for _, ip := range e.Addresses {
+				for _, p := range s.Ports {
+					endpoints[name] = append(endpoints[name], Endpoint{IP: ip, Port: p.Port})
+				}
+			}
+		}
+	}
+
+	result := make([]*Service, 0, len(services.Items))
+	for _, s := range services.Items {
+		result = append(result, &Service{
+			Name:        s.Metadata.Name,
+			Labels:      s.Metadata.Labels,
+			Annotations: s.Metadata.Annotations,
+			Endpoints:   endpoints[s.Metadata.Name],
+		})
+	}
+	return result, nil
+}
+
+func (c *cluster) Apply(s *Service) error {
+	labels := map[string]string{managedByLabel: ManagerName}
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+
+	// endpoint slices apply their ports to all their endpoints, so the endpoints are grouped
+	// by address type and port
+	type group struct {
+		addressType string
+		port        int
+	}
+	groups := make(map[group][]string)
+	ports := make(map[int]bool)
+	for _, e := range s.Endpoints {
+		ip := net.ParseIP(e.IP)
+		if ip == nil {
+			continue
+		}
+		g := group{"IPv6", e.Port}
+		if ip.To4() != nil {
+			g.addressType = "IPv4"
+		}
+		groups[g] = append(groups[g], e.IP)
+		ports[e.Port] = true
+	}
+
+	// the service has no selector so kubernetes doesn't manage its endpoints
+	svc := service{APIVersion: "v1", Kind: "Service", Metadata: metadata{
+		Name:        s.Name,
+		Labels:      labels,
+		Annotations: s.Annotations,
+	}}
+	for p := range ports {
+		svc.Spec.Ports = append(svc.Spec.Ports, servicePort{Name: portName(p), Port: p, Protocol: "TCP"})
+	}
+	sort.Slice(svc.Spec.Ports, func(i, j int) bool { return svc.Spec.Ports[i].Port < svc.Spec.Ports[j].Port })
+	if err := c.apply(c.servicesPath()+"/"+s.Name, svc); err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for g, ips := range groups {
+		slice := endpointSlice{
+			APIVersion: "discovery.k8s.io/v1",
+			Kind:       "EndpointSlice",
+			Metadata: metadata{
+				Name:   fmt.Sprintf("%s-%s-%d", s.Name, strings.ToLower(g.addressType), g.port),
+				Labels: map[string]string{sliceServiceLabel: s.Name, sliceManagedBy: ManagerName},
+			},
+			AddressType: g.addressType,
+			Ports:       []endpointPort{{Name: portName(g.port), Port: g.port, Protocol: "TCP"}},
+		}
+		sort.Strings(ips)
+		for _, ip := range ips {
+			slice.Endpoints = append(slice.Endpoints, endpoint{Addresses: []string{ip}})
+		}
+
+		wanted[slice.Metadata.Name] = true
+		if err := c.apply(c.slicesPath()+"/"+slice.Metadata.Name, slice); err != nil {
+			return err
+		}
+	}
+
+	// remove the slices of endpoints which are no longer registered
+	var slices struct {
+		Items []endpointSlice `json:"items"`
+	}
+	if err := c.do("GET", c.slicesPath(), c.selector(s.Name), nil, &slices); err != nil {
+		return err
+	}
+	for _, sl := range slices.Items {
+		if wanted[sl.Metadata.Name] {
+			continue
+		}
+		if err := c.do("DELETE", c.slicesPath()+"/"+sl.Metadata.Name, nil, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *cluster) Delete(name string) error {
+	if err := c.do("DELETE", c.servicesPath()+"/"+name, nil, nil, nil); err != nil {
+		return err
+	}
+	return c.do("DELETE", c.slicesPath(), c.selector(name), nil, nil)
+}
+
+func (c *cluster) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC().Format(microTime)
+
+	var l lease
+	err := c.do("GET", c.leasesPath()+"/"+name, nil, nil, &l)
+	if isStatus(err, http.StatusNotFound) {
+		l.APIVersion = "coordination.k8s.io/v1"
+		l.Kind = "Lease"
+		l.Metadata.Name = name
+	} else if err != nil {
+		return false, err
+	} else if l.Spec.HolderIdentity != holder && !l.expired() {
+		return false, nil
+	}
+
+	if l.Spec.HolderIdentity != holder {
+		l.Spec.HolderIdentity = holder
+		l.Spec.AcquireTime = now
+	}
+	l.Spec.RenewTime = now
+	l.Spec.LeaseDurationSeconds = int((ttl + time.Second - 1) / time.Second)
+
+	// the lease is created or replaced at the version read, so if another holder acquires it
+	// in the meantime the request conflicts
+	if len(l.Metadata.ResourceVersion) == 0 {
+		err = c.do("POST", c.leasesPath(), nil, l, nil)
+	} else {
+		err = c.do("PUT", c.leasesPath()+"/"+name, nil, l, nil)
+	}
+	if isStatus(err, http.StatusConflict) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// selector returns the params selecting the endpoint slices of the service created by the
+// registry
+func (c *cluster) selector(name string) url.Values {
+	return url.Values{
+		"labelSelector": []string{sliceServiceLabel + "=" + name + "," + sliceManagedBy + "=" + ManagerName},
+	}
+}
+
+// apply the object with a server side apply, which creates it if it doesn't exist
+func (c *cluster) apply(path string, obj interface{}) error {
+	params := url.Values{"fieldManager": []string{ManagerName}, "force": []string{"true"}}
+	return c.do("PATCH", path, params, obj, nil)
+}
+
+func (c *cluster) do(method, path string, params url.Values, in, out interface{}) error {
+	u := c.host + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if method == "PATCH" {
+		// json is valid yaml, which server side apply requires
+		req.Header.Set("Content-Type", "application/apply-patch+yaml")
+	} else if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	// deleting a resource which doesn't exist isn't an error
+	if method == "DELETE" && rsp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(rsp.Body)
+		msg := fmt.Sprintf("%s %s: %s: %s", method, path, rsp.Status, strings.TrimSpace(string(b)))
+		return &statusError{code: rsp.StatusCode, msg: msg}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(rsp.Body).Decode(out)
+}
+
+func portName(port int) string {
+	return "port-" + strconv.Itoa(port)
+}
@@ -0,0 +1,106 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResourceName(t *testing.T) {
+	tt := map[string]string{
+		"helloworld":             "helloworld",
+		"go.micro.service.Hello": "go-micro-service-hello",
+		"_foo__bar_":             "foo-bar",
+	}
+	for name, expected := range tt {
+		if n := ResourceName(name); n != expected {
+			t.Errorf("Expected %v to be %v, got %v", name, expected, n)
+		}
+	}
+}
+
+func TestServices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/default/services":
+			w.Write([]byte(`{"items":[{"metadata":{"name":"foo","labels":{"app":"foo"}}},{"metadata":{"name":"bar"}}]}`))
+		case "/apis/discovery.k8s.io/v1/namespaces/default/endpointslices":
+			w.Write([]byte(`{"items":[{
+				"metadata":{"name":"foo-abc","labels":{"kubernetes.io/service-name":"foo"}},
+				"addressType":"IPv4",
+				"ports":[{"port":8080}],
+				"endpoints":[
+					{"addresses":["10.0.0.1"],"conditions":{"ready":true}},
+					{"addresses":["10.0.0.2"],"conditions":{"ready":false}}
+				]
+			}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := &cluster{host: srv.URL, namespace: "default", client: srv.Client()}
+	services, err := c.Services()
+	if err != nil {
+		t.Fatalf("Unexpected error listing services: %v", err)
+	}
+	if len(services) != 2 || services[0].Labels["app"] != "foo" {
+		t.Fatalf("Expected 2 services, got %v", services)
+	}
+	if e := services[0].Endpoints; len(e) != 1 || e[0].Address() != "10.0.0.1:8080" {
+		t.Errorf("Expected the ready endpoint of foo, got %v", e)
+	}
+	if e := services[1].Endpoints; len(e) != 0 {
+		t.Errorf("Expected bar to have no endpoints, got %v", e)
+	}
+}
+
+func TestAcquireLease(t *testing.T) {
+	var stored *lease
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if stored == nil {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+		case "POST", "PUT":
+			var l lease
+			json.NewDecoder(r.Body).Decode(&l)
+			if stored != nil && l.Metadata.ResourceVersion != stored.Metadata.ResourceVersion {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			l.Metadata.ResourceVersion += "1"
+			stored = &l
+		}
+	}))
+	defer srv.Close()
+
+	c := &cluster{host: srv.URL, namespace: "default", client: srv.Client()}
+	if ok, err := c.AcquireLease("sync", "foo", time.Minute); err != nil || !ok {
+		t.Fatalf("Expected the lease to be created, got %v: %v", ok, err)
+	}
+	if stored.Spec.HolderIdentity != "foo" || stored.Spec.LeaseDurationSeconds != 60 {
+		t.Fatalf("Unexpected lease %+v", stored.Spec)
+	}
+	if ok, err := c.AcquireLease("sync", "foo", time.Minute); err != nil || !ok {
+		t.Fatalf("Expected the holder to renew the lease, got %v: %v", ok, err)
+	}
+	if ok, err := c.AcquireLease("sync", "bar", time.Minute); err != nil || ok {
+		t.Fatalf("Expected the lease not to be acquired while it's held, got %v: %v", ok, err)
+	}
+
+	// expired leases are taken over
+	stored.Spec.RenewTime = time.Now().Add(-time.Hour).UTC().Format(microTime)
+	if ok, err := c.AcquireLease("sync", "bar", time.Minute); err != nil || !ok {
+		t.Fatalf("Expected the expired lease to be acquired, got %v: %v", ok, err)
+	}
+	if stored.Spec.HolderIdentity != "bar" {
+		t.Errorf("Expected bar to hold the lease, got %v", stored.Spec.HolderIdentity)
+	}
+}
@@ -135,13 +135,18 @@ func (m *Service) GetOptions() *Options {
 
 // Node represents the node the service is on
 type Node struct {
-	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Address              string            `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
-	Port                 int64             `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
-	Metadata             map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Id       string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address  string            `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Port     int64             `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Metadata map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// labels used to select the node, e.g. canary=true
+	Labels map[string]string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// locality of the node, e.g. region eu-west-1 and zone eu-west-1a
+	Region               string   `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`
+	Zone                 string   `protobuf:"bytes,7,opt,name=zone,proto3" json:"zone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Node) Reset()         { *m = Node{} }
@@ -197,6 +202,27 @@ func (m *Node) GetMetadata() map[string]string {
 	return nil
 }
 
+func (m *Node) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Node) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *Node) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
 // Endpoint is a endpoint provided by a service
 type Endpoint struct {
 	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -319,11 +345,18 @@ func (m *Value) GetValues() []*Value {
 
 // Options are registry options
 type Options struct {
-	Ttl                  int64    `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
-	Domain               string   `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Ttl    int64  `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// session the registration is bound to
+	Session string `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	// revision reads must be consistent with, they fail with a conflict
+	// if the services read have changed since
+	Revision int64 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	// labels the nodes must have, nodes without them are omitted
+	Labels               map[string]string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Options) Reset()         { *m = Options{} }
@@ -365,11 +398,35 @@ func (m *Options) GetDomain() string {
 	return ""
 }
 
+func (m *Options) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *Options) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Options) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // Result is returns by the watcher
 type Result struct {
-	Action               string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
-	Service              *Service `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
-	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Action    string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Service   *Service `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Timestamp int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// sequence of the result in the changelog, pass the last sequence
+	// received as since when watching to resume from it
+	Sequence             int64    `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -421,6 +478,13 @@ func (m *Result) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *Result) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 type EmptyResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -453,8 +517,13 @@ func (m *EmptyResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_EmptyResponse proto.InternalMessageInfo
 
 type GetRequest struct {
-	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
-	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Service string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Options *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// locality the nodes returned are preferred from, nodes in the zone are
+	// returned if there are any, then those in the region, otherwise all
+	// the nodes are returned
+	Region               string   `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	Zone                 string   `protobuf:"bytes,4,opt,name=zone,proto3" json:"zone,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -499,6 +568,20 @@ func (m *GetRequest) GetOptions() *Options {
 	return nil
 }
 
+func (m *GetRequest) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *GetRequest) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
 type GetResponse struct {
 	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -538,86 +621,95 @@ func (m *GetResponse) GetServices() []*Service {
 	return nil
 }
 
-type ListRequest struct {
-	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+type GetServicesRequest struct {
+	Services             []string `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ListRequest) Reset()         { *m = ListRequest{} }
-func (m *ListRequest) String() string { return proto.CompactTextString(m) }
-func (*ListRequest) ProtoMessage()    {}
-func (*ListRequest) Descriptor() ([]byte, []int) {
+func (m *GetServicesRequest) Reset()         { *m = GetServicesRequest{} }
+func (m *GetServicesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetServicesRequest) ProtoMessage()    {}
+func (*GetServicesRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_bba65e34813efea5, []int{9}
 }
 
-func (m *ListRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+func (m *GetServicesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetServicesRequest.Unmarshal(m, b)
 }
-func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+func (m *GetServicesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetServicesRequest.Marshal(b, m, deterministic)
 }
-func (m *ListRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListRequest.Merge(m, src)
+func (m *GetServicesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetServicesRequest.Merge(m, src)
 }
-func (m *ListRequest) XXX_Size() int {
-	return xxx_messageInfo_ListRequest.Size(m)
+func (m *GetServicesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetServicesRequest.Size(m)
 }
-func (m *ListRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+func (m *GetServicesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetServicesRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+var xxx_messageInfo_GetServicesRequest proto.InternalMessageInfo
 
-func (m *ListRequest) GetOptions() *Options {
+func (m *GetServicesRequest) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+func (m *GetServicesRequest) GetOptions() *Options {
 	if m != nil {
 		return m.Options
 	}
 	return nil
 }
 
-type ListResponse struct {
+type GetServicesResponse struct {
 	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
 }
 
-func (m *ListResponse) Reset()         { *m = ListResponse{} }
-func (m *ListResponse) String() string { return proto.CompactTextString(m) }
-func (*ListResponse) ProtoMessage()    {}
-func (*ListResponse) Descriptor() ([]byte, []int) {
+func (m *GetServicesResponse) Reset()         { *m = GetServicesResponse{} }
+func (m *GetServicesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetServicesResponse) ProtoMessage()    {}
+func (*GetServicesResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_bba65e34813efea5, []int{10}
 }
 
-func (m *ListResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
+func (m *GetServicesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetServicesResponse.Unmarshal(m, b)
 }
-func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
+func (m *GetServicesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetServicesResponse.Marshal(b, m, deterministic)
 }
-func (m *ListResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListResponse.Merge(m, src)
+func (m *GetServicesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetServicesResponse.Merge(m, src)
 }
-func (m *ListResponse) XXX_Size() int {
-	return xxx_messageInfo_ListResponse.Size(m)
+func (m *GetServicesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetServicesResponse.Size(m)
 }
-func (m *ListResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+func (m *GetServicesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetServicesResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+var xxx_messageInfo_GetServicesResponse proto.InternalMessageInfo
 
-func (m *ListResponse) GetServices() []*Service {
+func (m *GetServicesResponse) GetServices() []*Service {
 	if m != nil {
 		return m.Services
 	}
 	return nil
 }
 
-type WatchRequest struct {
-	// service is optional
+// GraphRequest returns the dependency graph of the services in the namespace. If a
+// service is specified, only the services it depends on or which depend on it are returned.
+type GraphRequest struct {
 	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
 	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -625,238 +717,1782 @@ type WatchRequest struct {
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
-func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
-func (*WatchRequest) ProtoMessage()    {}
-func (*WatchRequest) Descriptor() ([]byte, []int) {
+func (m *GraphRequest) Reset()         { *m = GraphRequest{} }
+func (m *GraphRequest) String() string { return proto.CompactTextString(m) }
+func (*GraphRequest) ProtoMessage()    {}
+func (*GraphRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_bba65e34813efea5, []int{11}
 }
 
-func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+func (m *GraphRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GraphRequest.Unmarshal(m, b)
 }
-func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+func (m *GraphRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GraphRequest.Marshal(b, m, deterministic)
 }
-func (m *WatchRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WatchRequest.Merge(m, src)
+func (m *GraphRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GraphRequest.Merge(m, src)
 }
-func (m *WatchRequest) XXX_Size() int {
-	return xxx_messageInfo_WatchRequest.Size(m)
+func (m *GraphRequest) XXX_Size() int {
+	return xxx_messageInfo_GraphRequest.Size(m)
 }
-func (m *WatchRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+func (m *GraphRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GraphRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+var xxx_messageInfo_GraphRequest proto.InternalMessageInfo
 
-func (m *WatchRequest) GetService() string {
+func (m *GraphRequest) GetService() string {
 	if m != nil {
 		return m.Service
 	}
 	return ""
 }
 
-func (m *WatchRequest) GetOptions() *Options {
+func (m *GraphRequest) GetOptions() *Options {
 	if m != nil {
 		return m.Options
 	}
 	return nil
 }
 
-// Event is registry event
-type Event struct {
-	// Event Id
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// type of event
-	Type EventType `protobuf:"varint,2,opt,name=type,proto3,enum=registry.EventType" json:"type,omitempty"`
-	// unix timestamp of event
-	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	// service entry
-	Service              *Service `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+type GraphResponse struct {
+	Nodes                []*GraphNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GraphResponse) Reset()         { *m = GraphResponse{} }
+func (m *GraphResponse) String() string { return proto.CompactTextString(m) }
+func (*GraphResponse) ProtoMessage()    {}
+func (*GraphResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{12}
+}
+
+func (m *GraphResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GraphResponse.Unmarshal(m, b)
+}
+func (m *GraphResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GraphResponse.Marshal(b, m, deterministic)
+}
+func (m *GraphResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GraphResponse.Merge(m, src)
+}
+func (m *GraphResponse) XXX_Size() int {
+	return xxx_messageInfo_GraphResponse.Size(m)
+}
+func (m *GraphResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GraphResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GraphResponse proto.InternalMessageInfo
+
+func (m *GraphResponse) GetNodes() []*GraphNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+// GraphNode is a service in the dependency graph
+type GraphNode struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// services this service depends on
+	Dependencies []string `protobuf:"bytes,2,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
+	// services which depend on this service
+	Dependents []string `protobuf:"bytes,3,rep,name=dependents,proto3" json:"dependents,omitempty"`
+	// true if the service is a declared dependency but is not registered
+	Missing              bool     `protobuf:"varint,4,opt,name=missing,proto3" json:"missing,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Event) Reset()         { *m = Event{} }
-func (m *Event) String() string { return proto.CompactTextString(m) }
-func (*Event) ProtoMessage()    {}
-func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bba65e34813efea5, []int{12}
+func (m *GraphNode) Reset()         { *m = GraphNode{} }
+func (m *GraphNode) String() string { return proto.CompactTextString(m) }
+func (*GraphNode) ProtoMessage()    {}
+func (*GraphNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{13}
 }
 
-func (m *Event) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Event.Unmarshal(m, b)
+func (m *GraphNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GraphNode.Unmarshal(m, b)
 }
-func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+func (m *GraphNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GraphNode.Marshal(b, m, deterministic)
 }
-func (m *Event) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Event.Merge(m, src)
+func (m *GraphNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GraphNode.Merge(m, src)
 }
-func (m *Event) XXX_Size() int {
-	return xxx_messageInfo_Event.Size(m)
+func (m *GraphNode) XXX_Size() int {
+	return xxx_messageInfo_GraphNode.Size(m)
 }
-func (m *Event) XXX_DiscardUnknown() {
-	xxx_messageInfo_Event.DiscardUnknown(m)
+func (m *GraphNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_GraphNode.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Event proto.InternalMessageInfo
+var xxx_messageInfo_GraphNode proto.InternalMessageInfo
 
-func (m *Event) GetId() string {
+func (m *GraphNode) GetName() string {
 	if m != nil {
-		return m.Id
+		return m.Name
 	}
 	return ""
 }
 
-func (m *Event) GetType() EventType {
+func (m *GraphNode) GetDependencies() []string {
 	if m != nil {
-		return m.Type
+		return m.Dependencies
 	}
-	return EventType_Create
+	return nil
 }
 
-func (m *Event) GetTimestamp() int64 {
+func (m *GraphNode) GetDependents() []string {
 	if m != nil {
-		return m.Timestamp
+		return m.Dependents
 	}
-	return 0
+	return nil
 }
 
-func (m *Event) GetService() *Service {
+func (m *GraphNode) GetMissing() bool {
 	if m != nil {
-		return m.Service
+		return m.Missing
 	}
-	return nil
+	return false
 }
 
-func init() {
-	proto.RegisterEnum("registry.EventType", EventType_name, EventType_value)
-	proto.RegisterType((*Service)(nil), "registry.Service")
-	proto.RegisterMapType((map[string]string)(nil), "registry.Service.MetadataEntry")
-	proto.RegisterType((*Node)(nil), "registry.Node")
-	proto.RegisterMapType((map[string]string)(nil), "registry.Node.MetadataEntry")
-	proto.RegisterType((*Endpoint)(nil), "registry.Endpoint")
-	proto.RegisterMapType((map[string]string)(nil), "registry.Endpoint.MetadataEntry")
-	proto.RegisterType((*Value)(nil), "registry.Value")
-	proto.RegisterType((*Options)(nil), "registry.Options")
-	proto.RegisterType((*Result)(nil), "registry.Result")
-	proto.RegisterType((*EmptyResponse)(nil), "registry.EmptyResponse")
-	proto.RegisterType((*GetRequest)(nil), "registry.GetRequest")
-	proto.RegisterType((*GetResponse)(nil), "registry.GetResponse")
-	proto.RegisterType((*ListRequest)(nil), "registry.ListRequest")
-	proto.RegisterType((*ListResponse)(nil), "registry.ListResponse")
-	proto.RegisterType((*WatchRequest)(nil), "registry.WatchRequest")
-	proto.RegisterType((*Event)(nil), "registry.Event")
+// Split is a share of traffic sent to the nodes matching the labels. The
+// service version can be matched using the version label.
+type Split struct {
+	Labels               map[string]string `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Weight               int64             `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("service/registry/proto/registry.proto", fileDescriptor_bba65e34813efea5)
+func (m *Split) Reset()         { *m = Split{} }
+func (m *Split) String() string { return proto.CompactTextString(m) }
+func (*Split) ProtoMessage()    {}
+func (*Split) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{14}
 }
 
-var fileDescriptor_bba65e34813efea5 = []byte{
-	// 710 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdb, 0x6e, 0xd3, 0x4c,
-	0x10, 0x8e, 0x9d, 0xf3, 0xa4, 0x87, 0x74, 0xff, 0xfe, 0xc5, 0x8a, 0x2a, 0x11, 0x59, 0xa0, 0x06,
-	0x2a, 0x92, 0x2a, 0x15, 0x52, 0x69, 0x8a, 0x90, 0xa0, 0x11, 0x37, 0x1c, 0x24, 0x97, 0x02, 0xe2,
-	0xce, 0x8d, 0x47, 0xad, 0xd5, 0xd8, 0x6b, 0x76, 0x37, 0x91, 0xf2, 0x0c, 0x3c, 0x0f, 0xdc, 0xf0,
-	0x54, 0xbc, 0x01, 0xda, 0xf5, 0xfa, 0xd0, 0x3a, 0x50, 0xa9, 0xc0, 0x4d, 0x34, 0x33, 0xfb, 0xcd,
-	0xf9, 0x9b, 0x18, 0xee, 0x73, 0x64, 0x73, 0x7f, 0x82, 0x03, 0x86, 0xe7, 0x3e, 0x17, 0x6c, 0x31,
-	0x88, 0x18, 0x15, 0x34, 0x55, 0xfb, 0x4a, 0x25, 0x8d, 0x44, 0xb7, 0xbf, 0x99, 0x50, 0x3f, 0x89,
-	0x7d, 0x08, 0x81, 0x4a, 0xe8, 0x06, 0x68, 0x19, 0x5d, 0xa3, 0xd7, 0x74, 0x94, 0x4c, 0x2c, 0xa8,
-	0xcf, 0x91, 0x71, 0x9f, 0x86, 0x96, 0xa9, 0xcc, 0x89, 0x4a, 0x46, 0xd0, 0x08, 0x50, 0xb8, 0x9e,
-	0x2b, 0x5c, 0xab, 0xdc, 0x2d, 0xf7, 0x5a, 0xc3, 0xbb, 0xfd, 0x34, 0x8d, 0x0e, 0xd9, 0x7f, 0xad,
-	0x11, 0xe3, 0x50, 0xb0, 0x85, 0x93, 0x3a, 0x90, 0x3d, 0x68, 0x62, 0xe8, 0x45, 0xd4, 0x0f, 0x05,
-	0xb7, 0x2a, 0xca, 0x9b, 0x64, 0xde, 0x63, 0xfd, 0xe4, 0x64, 0x20, 0x72, 0x0f, 0xaa, 0x21, 0xf5,
-	0x90, 0x5b, 0x55, 0x85, 0x5e, 0xcb, 0xd0, 0x6f, 0xa8, 0x87, 0x4e, 0xfc, 0x48, 0x76, 0xa1, 0x4e,
-	0x23, 0xe1, 0xd3, 0x90, 0x5b, 0xb5, 0xae, 0xd1, 0x6b, 0x0d, 0x37, 0x32, 0xdc, 0xdb, 0xf8, 0xc1,
-	0x49, 0x10, 0x9d, 0x11, 0xac, 0x5e, 0xa9, 0x8f, 0xb4, 0xa1, 0x7c, 0x89, 0x0b, 0xdd, 0xbf, 0x14,
-	0xc9, 0x26, 0x54, 0xe7, 0xee, 0x74, 0x86, 0xba, 0xf9, 0x58, 0x39, 0x34, 0x0f, 0x0c, 0xfb, 0xbb,
-	0x01, 0x15, 0x99, 0x99, 0xac, 0x81, 0xe9, 0x7b, 0xda, 0xc7, 0xf4, 0x3d, 0x39, 0x31, 0xd7, 0xf3,
-	0x18, 0x72, 0x9e, 0x4c, 0x4c, 0xab, 0x72, 0xbe, 0x11, 0x65, 0xc2, 0x2a, 0x77, 0x8d, 0x5e, 0xd9,
-	0x51, 0x32, 0x39, 0xc8, 0x4d, 0x31, 0x9e, 0xc3, 0xf6, 0xd5, 0xce, 0x7e, 0x35, 0xc2, 0x3f, 0xab,
-	0xfe, 0x87, 0x01, 0x8d, 0x64, 0xca, 0x4b, 0xf7, 0xfe, 0x00, 0xea, 0x0c, 0x3f, 0xcf, 0x90, 0x0b,
-	0xe5, 0xdc, 0x1a, 0xae, 0x67, 0x65, 0xbd, 0x97, 0x61, 0x9c, 0xe4, 0x9d, 0xec, 0x42, 0x83, 0x21,
-	0x8f, 0x68, 0xc8, 0x51, 0xb5, 0xb6, 0x04, 0x9b, 0x02, 0xc8, 0x51, 0xa1, 0xdf, 0x6e, 0x71, 0xef,
-	0xff, 0xa6, 0xe7, 0x8f, 0x50, 0x55, 0xd5, 0x2c, 0xed, 0x97, 0x40, 0x45, 0x2c, 0xa2, 0xc4, 0x4b,
-	0xc9, 0x64, 0x07, 0x6a, 0xca, 0x9b, 0x6b, 0x7e, 0x17, 0xda, 0xd2, 0xcf, 0xf6, 0x3e, 0xd4, 0x35,
-	0xb9, 0x64, 0x41, 0x42, 0x4c, 0x55, 0xe8, 0xb2, 0x23, 0x45, 0xb2, 0x05, 0x35, 0x8f, 0x06, 0xae,
-	0x9f, 0x1c, 0x90, 0xd6, 0xec, 0x4b, 0xa8, 0x39, 0xc8, 0x67, 0x53, 0x21, 0x11, 0xee, 0x44, 0xba,
-	0xeb, 0x8a, 0xb4, 0x26, 0xc9, 0xac, 0xcf, 0x59, 0xef, 0x60, 0xa3, 0x70, 0x60, 0x4e, 0x82, 0x20,
-	0xdb, 0xd0, 0x14, 0x7e, 0x80, 0x5c, 0xb8, 0x41, 0xa4, 0x19, 0x96, 0x19, 0xec, 0x75, 0x58, 0x1d,
-	0x07, 0x91, 0x58, 0x38, 0x7a, 0x0f, 0xf6, 0x09, 0xc0, 0x4b, 0x14, 0x8e, 0x5e, 0xa1, 0x95, 0x65,
-	0x8a, 0x4b, 0x48, 0xc3, 0xe6, 0x0e, 0xca, 0xbc, 0xe9, 0xa0, 0xec, 0x23, 0x68, 0xa9, 0xa0, 0x7a,
-	0xd7, 0x8f, 0xa0, 0xa1, 0xc3, 0x70, 0xcb, 0x50, 0x13, 0x5c, 0xd2, 0x40, 0x0a, 0xb1, 0x0f, 0xa1,
-	0xf5, 0xca, 0xe7, 0x69, 0x4d, 0xb9, 0xcc, 0xc6, 0x8d, 0x99, 0x9f, 0xc2, 0x4a, 0xec, 0x7b, 0xbb,
-	0xd4, 0xa7, 0xb0, 0xf2, 0xc1, 0x15, 0x93, 0x8b, 0xbf, 0x3c, 0x8f, 0x2f, 0x06, 0x54, 0xc7, 0x73,
-	0x0c, 0x45, 0xe1, 0x4f, 0x62, 0x27, 0x47, 0xb7, 0xb5, 0xe1, 0x7f, 0xb9, 0x13, 0x90, 0xf0, 0x77,
-	0x8b, 0x08, 0x35, 0x07, 0x7f, 0xbb, 0xd6, 0x3c, 0x43, 0x2a, 0x37, 0x31, 0xe4, 0xe1, 0x00, 0x9a,
-	0x69, 0x74, 0x02, 0x50, 0x7b, 0xc1, 0xd0, 0x15, 0xd8, 0x2e, 0x49, 0xf9, 0x18, 0xa7, 0x28, 0xb0,
-	0x6d, 0x48, 0xf9, 0x34, 0xf2, 0xa4, 0xdd, 0x1c, 0x7e, 0x35, 0xa1, 0xe1, 0xe8, 0x70, 0x64, 0xa4,
-	0x08, 0x93, 0x7c, 0x2a, 0x36, 0xb3, 0x3c, 0x19, 0x8d, 0x3a, 0xff, 0x5f, 0xb3, 0x6a, 0xae, 0x95,
-	0xe4, 0xbf, 0x5c, 0x1c, 0x08, 0x19, 0x29, 0x96, 0xd8, 0xb9, 0x93, 0xeb, 0xff, 0x0a, 0x4b, 0x4b,
-	0xe4, 0x10, 0xe0, 0x18, 0xd9, 0xed, 0x7c, 0x9f, 0xc5, 0xa4, 0xd0, 0x48, 0x4e, 0x72, 0xe5, 0xe5,
-	0x88, 0xd6, 0xd9, 0xba, 0x6e, 0x4e, 0x03, 0x3c, 0x86, 0xaa, 0xa2, 0x05, 0xc9, 0x41, 0xf2, 0x3c,
-	0xe9, 0xb4, 0x33, 0x7b, 0x7c, 0xcb, 0x76, 0x69, 0xcf, 0x78, 0x3e, 0xfa, 0xf4, 0xe4, 0xdc, 0x17,
-	0x17, 0xb3, 0xb3, 0xfe, 0x84, 0x06, 0x83, 0xc0, 0x9f, 0x30, 0xaa, 0x7f, 0xe7, 0xfb, 0x83, 0xe5,
-	0x1f, 0xe8, 0x51, 0xa2, 0x9e, 0xd5, 0x94, 0xbe, 0xff, 0x33, 0x00, 0x00, 0xff, 0xff, 0x6e, 0x77,
-	0xa7, 0xb8, 0xca, 0x07, 0x00, 0x00,
+func (m *Split) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Split.Unmarshal(m, b)
 }
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// RegistryClient is the client API for Registry service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type RegistryClient interface {
-	GetService(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
-	Register(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error)
-	Deregister(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error)
-	ListServices(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
-	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Registry_WatchClient, error)
+func (m *Split) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Split.Marshal(b, m, deterministic)
 }
-
-type registryClient struct {
-	cc *grpc.ClientConn
+func (m *Split) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Split.Merge(m, src)
 }
-
-func NewRegistryClient(cc *grpc.ClientConn) RegistryClient {
-	return &registryClient{cc}
+func (m *Split) XXX_Size() int {
+	return xxx_messageInfo_Split.Size(m)
+}
+func (m *Split) XXX_DiscardUnknown() {
+	xxx_messageInfo_Split.DiscardUnknown(m)
 }
 
-func (c *registryClient) GetService(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
-	out := new(GetResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/GetService", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_Split proto.InternalMessageInfo
+
+func (m *Split) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
 	}
-	return out, nil
+	return nil
 }
 
-func (c *registryClient) Register(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/Register", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Split) GetWeight() int64 {
+	if m != nil {
+		return m.Weight
 	}
-	return out, nil
+	return 0
 }
 
-func (c *registryClient) Deregister(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/Deregister", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+// ResolveRequest resolves the nodes of a service along with the share of
+// traffic each should receive according to the splits
+type ResolveRequest struct {
+	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Splits               []*Split `protobuf:"bytes,2,rep,name=splits,proto3" json:"splits,omitempty"`
+	Options              *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResolveRequest) Reset()         { *m = ResolveRequest{} }
+func (m *ResolveRequest) String() string { return proto.CompactTextString(m) }
+func (*ResolveRequest) ProtoMessage()    {}
+func (*ResolveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{15}
+}
+
+func (m *ResolveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveRequest.Unmarshal(m, b)
+}
+func (m *ResolveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveRequest.Marshal(b, m, deterministic)
+}
+func (m *ResolveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveRequest.Merge(m, src)
+}
+func (m *ResolveRequest) XXX_Size() int {
+	return xxx_messageInfo_ResolveRequest.Size(m)
+}
+func (m *ResolveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveRequest proto.InternalMessageInfo
+
+func (m *ResolveRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *ResolveRequest) GetSplits() []*Split {
+	if m != nil {
+		return m.Splits
+	}
+	return nil
+}
+
+func (m *ResolveRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ResolveResponse struct {
+	Nodes                []*WeightedNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ResolveResponse) Reset()         { *m = ResolveResponse{} }
+func (m *ResolveResponse) String() string { return proto.CompactTextString(m) }
+func (*ResolveResponse) ProtoMessage()    {}
+func (*ResolveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{16}
+}
+
+func (m *ResolveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveResponse.Unmarshal(m, b)
+}
+func (m *ResolveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveResponse.Marshal(b, m, deterministic)
+}
+func (m *ResolveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveResponse.Merge(m, src)
+}
+func (m *ResolveResponse) XXX_Size() int {
+	return xxx_messageInfo_ResolveResponse.Size(m)
+}
+func (m *ResolveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveResponse proto.InternalMessageInfo
+
+func (m *ResolveResponse) GetNodes() []*WeightedNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type WeightedNode struct {
+	Node    *Node  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// the relative share of traffic the node should receive
+	Weight               float64  `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WeightedNode) Reset()         { *m = WeightedNode{} }
+func (m *WeightedNode) String() string { return proto.CompactTextString(m) }
+func (*WeightedNode) ProtoMessage()    {}
+func (*WeightedNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{17}
+}
+
+func (m *WeightedNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WeightedNode.Unmarshal(m, b)
+}
+func (m *WeightedNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WeightedNode.Marshal(b, m, deterministic)
+}
+func (m *WeightedNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WeightedNode.Merge(m, src)
+}
+func (m *WeightedNode) XXX_Size() int {
+	return xxx_messageInfo_WeightedNode.Size(m)
+}
+func (m *WeightedNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_WeightedNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WeightedNode proto.InternalMessageInfo
+
+func (m *WeightedNode) GetNode() *Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *WeightedNode) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *WeightedNode) GetWeight() float64 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+// ExportRequest dumps the services in the domain, use the wildcard
+// domain to export every domain
+type ExportRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportRequest) Reset()         { *m = ExportRequest{} }
+func (m *ExportRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportRequest) ProtoMessage()    {}
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{18}
+}
+
+func (m *ExportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportRequest.Unmarshal(m, b)
+}
+func (m *ExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRequest.Merge(m, src)
+}
+func (m *ExportRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportRequest.Size(m)
+}
+func (m *ExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportRequest proto.InternalMessageInfo
+
+func (m *ExportRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ExportResponse struct {
+	// services with the domain they're registered in set in the options
+	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ExportResponse) Reset()         { *m = ExportResponse{} }
+func (m *ExportResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportResponse) ProtoMessage()    {}
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{19}
+}
+
+func (m *ExportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportResponse.Unmarshal(m, b)
+}
+func (m *ExportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportResponse.Merge(m, src)
+}
+func (m *ExportResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportResponse.Size(m)
+}
+func (m *ExportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportResponse proto.InternalMessageInfo
+
+func (m *ExportResponse) GetServices() []*Service {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+// ImportRequest registers the services, e.g. from an export. Services are
+// registered in the domain set in their options, falling back to the domain
+// of the request.
+type ImportRequest struct {
+	Services []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	// addresses to remap, keyed by the old address. Keys can be a full address
+	// or a host, in which case the port is retained.
+	Addresses map[string]string `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// the ttl in the options is applied to the imported services
+	Options              *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportRequest) Reset()         { *m = ImportRequest{} }
+func (m *ImportRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportRequest) ProtoMessage()    {}
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{20}
+}
+
+func (m *ImportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportRequest.Unmarshal(m, b)
+}
+func (m *ImportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRequest.Merge(m, src)
+}
+func (m *ImportRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportRequest.Size(m)
+}
+func (m *ImportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRequest proto.InternalMessageInfo
+
+func (m *ImportRequest) GetServices() []*Service {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+func (m *ImportRequest) GetAddresses() map[string]string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *ImportRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ImportResponse struct {
+	// number of services imported
+	Imported             int64    `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportResponse) Reset()         { *m = ImportResponse{} }
+func (m *ImportResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportResponse) ProtoMessage()    {}
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{21}
+}
+
+func (m *ImportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportResponse.Unmarshal(m, b)
+}
+func (m *ImportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResponse.Merge(m, src)
+}
+func (m *ImportResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportResponse.Size(m)
+}
+func (m *ImportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResponse proto.InternalMessageInfo
+
+func (m *ImportResponse) GetImported() int64 {
+	if m != nil {
+		return m.Imported
+	}
+	return 0
+}
+
+// RevisionRequest returns the current revision of the domain
+type RevisionRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevisionRequest) Reset()         { *m = RevisionRequest{} }
+func (m *RevisionRequest) String() string { return proto.CompactTextString(m) }
+func (*RevisionRequest) ProtoMessage()    {}
+func (*RevisionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{22}
+}
+
+func (m *RevisionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevisionRequest.Unmarshal(m, b)
+}
+func (m *RevisionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevisionRequest.Marshal(b, m, deterministic)
+}
+func (m *RevisionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionRequest.Merge(m, src)
+}
+func (m *RevisionRequest) XXX_Size() int {
+	return xxx_messageInfo_RevisionRequest.Size(m)
+}
+func (m *RevisionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevisionRequest proto.InternalMessageInfo
+
+func (m *RevisionRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevisionResponse struct {
+	// revision of the last change made to the domain, revisions are the
+	// sequences of the changelog so can be passed to Watch as since
+	Revision             int64    `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevisionResponse) Reset()         { *m = RevisionResponse{} }
+func (m *RevisionResponse) String() string { return proto.CompactTextString(m) }
+func (*RevisionResponse) ProtoMessage()    {}
+func (*RevisionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{23}
+}
+
+func (m *RevisionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevisionResponse.Unmarshal(m, b)
+}
+func (m *RevisionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevisionResponse.Marshal(b, m, deterministic)
+}
+func (m *RevisionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionResponse.Merge(m, src)
+}
+func (m *RevisionResponse) XXX_Size() int {
+	return xxx_messageInfo_RevisionResponse.Size(m)
+}
+func (m *RevisionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevisionResponse proto.InternalMessageInfo
+
+func (m *RevisionResponse) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+// AuditRequest returns the registrations and deregistrations made in the
+// domain, most recent first
+type AuditRequest struct {
+	Options *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// service is optional
+	Service string `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	// unix timestamps the entries are limited to, zero for no limit
+	Since int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until int64 `protobuf:"varint,4,opt,name=until,proto3" json:"until,omitempty"`
+	// maximum number of entries to return, zero for no limit
+	Limit                int64    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditRequest) Reset()         { *m = AuditRequest{} }
+func (m *AuditRequest) String() string { return proto.CompactTextString(m) }
+func (*AuditRequest) ProtoMessage()    {}
+func (*AuditRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{24}
+}
+
+func (m *AuditRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRequest.Unmarshal(m, b)
+}
+func (m *AuditRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRequest.Marshal(b, m, deterministic)
+}
+func (m *AuditRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRequest.Merge(m, src)
+}
+func (m *AuditRequest) XXX_Size() int {
+	return xxx_messageInfo_AuditRequest.Size(m)
+}
+func (m *AuditRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRequest proto.InternalMessageInfo
+
+func (m *AuditRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *AuditRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *AuditRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *AuditRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *AuditRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type AuditResponse struct {
+	Entries              []*AuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuditResponse) Reset()         { *m = AuditResponse{} }
+func (m *AuditResponse) String() string { return proto.CompactTextString(m) }
+func (*AuditResponse) ProtoMessage()    {}
+func (*AuditResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{25}
+}
+
+func (m *AuditResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditResponse.Unmarshal(m, b)
+}
+func (m *AuditResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditResponse.Marshal(b, m, deterministic)
+}
+func (m *AuditResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditResponse.Merge(m, src)
+}
+func (m *AuditResponse) XXX_Size() int {
+	return xxx_messageInfo_AuditResponse.Size(m)
+}
+func (m *AuditResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditResponse proto.InternalMessageInfo
+
+func (m *AuditResponse) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// AuditEntry records a change to a service and who made it
+type AuditEntry struct {
+	Action  string `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Service string `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// ids of the nodes registered or deregistered
+	Nodes []string `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	// account which made the change, blank if the registry made it, e.g. when
+	// a session expired or a node failed its probes
+	Account              string   `protobuf:"bytes,5,opt,name=account,proto3" json:"account,omitempty"`
+	Issuer               string   `protobuf:"bytes,6,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Timestamp            int64    `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{26}
+}
+
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (m *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(m, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *AuditEntry) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+// ListDeletedRequest returns the tombstones of the services deregistered
+// within the tombstone window which haven't registered again
+type ListDeletedRequest struct {
+	Options *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// service is optional
+	Service              string   `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDeletedRequest) Reset()         { *m = ListDeletedRequest{} }
+func (m *ListDeletedRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDeletedRequest) ProtoMessage()    {}
+func (*ListDeletedRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{27}
+}
+
+func (m *ListDeletedRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeletedRequest.Unmarshal(m, b)
+}
+func (m *ListDeletedRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeletedRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDeletedRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeletedRequest.Merge(m, src)
+}
+func (m *ListDeletedRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDeletedRequest.Size(m)
+}
+func (m *ListDeletedRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeletedRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeletedRequest proto.InternalMessageInfo
+
+func (m *ListDeletedRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ListDeletedRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+type ListDeletedResponse struct {
+	Tombstones           []*Tombstone `protobuf:"bytes,1,rep,name=tombstones,proto3" json:"tombstones,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListDeletedResponse) Reset()         { *m = ListDeletedResponse{} }
+func (m *ListDeletedResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDeletedResponse) ProtoMessage()    {}
+func (*ListDeletedResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{28}
+}
+
+func (m *ListDeletedResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeletedResponse.Unmarshal(m, b)
+}
+func (m *ListDeletedResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeletedResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDeletedResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeletedResponse.Merge(m, src)
+}
+func (m *ListDeletedResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDeletedResponse.Size(m)
+}
+func (m *ListDeletedResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeletedResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeletedResponse proto.InternalMessageInfo
+
+func (m *ListDeletedResponse) GetTombstones() []*Tombstone {
+	if m != nil {
+		return m.Tombstones
+	}
+	return nil
+}
+
+// Tombstone of a version of a service whose nodes were deregistered
+type Tombstone struct {
+	// service with the nodes which were deregistered and haven't registered
+	// again
+	Service *Service `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Deleted int64    `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// account which made the last deregistration, blank if the registry made it
+	Account              string   `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	Issuer               string   `protobuf:"bytes,4,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Tombstone) Reset()         { *m = Tombstone{} }
+func (m *Tombstone) String() string { return proto.CompactTextString(m) }
+func (*Tombstone) ProtoMessage()    {}
+func (*Tombstone) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{29}
+}
+
+func (m *Tombstone) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Tombstone.Unmarshal(m, b)
+}
+func (m *Tombstone) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Tombstone.Marshal(b, m, deterministic)
+}
+func (m *Tombstone) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tombstone.Merge(m, src)
+}
+func (m *Tombstone) XXX_Size() int {
+	return xxx_messageInfo_Tombstone.Size(m)
+}
+func (m *Tombstone) XXX_DiscardUnknown() {
+	xxx_messageInfo_Tombstone.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Tombstone proto.InternalMessageInfo
+
+func (m *Tombstone) GetService() *Service {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *Tombstone) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+func (m *Tombstone) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *Tombstone) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+type ListRequest struct {
+	Options *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// prefix the names of the services listed must have
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// maximum number of services to list, the versions of a service are
+	// listed together and count as one. Zero lists every service.
+	Limit int64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor returned by the previous page, blank for the first page
+	Cursor               string   `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRequest) Reset()         { *m = ListRequest{} }
+func (m *ListRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()    {}
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{30}
+}
+
+func (m *ListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+}
+func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRequest.Merge(m, src)
+}
+func (m *ListRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRequest.Size(m)
+}
+func (m *ListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+
+func (m *ListRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ListRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type ListResponse struct {
+	Services []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	// cursor of the next page, blank if this is the last page
+	NextCursor           string   `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListResponse) Reset()         { *m = ListResponse{} }
+func (m *ListResponse) String() string { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()    {}
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{31}
+}
+
+func (m *ListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
+}
+func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
+}
+func (m *ListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListResponse.Merge(m, src)
+}
+func (m *ListResponse) XXX_Size() int {
+	return xxx_messageInfo_ListResponse.Size(m)
+}
+func (m *ListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+
+func (m *ListResponse) GetServices() []*Service {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+func (m *ListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	// service is optional
+	Service string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Options *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// replay the changes with a greater sequence from the changelog before
+	// watching. Sequences count the changes made to the domain.
+	Since                int64    `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{32}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+// Session holds registrations which are removed when it expires or is revoked
+type Session struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// ttl in seconds
+	Ttl int64 `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// unix timestamp the session expires at unless kept alive
+	Expiry               int64    `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{33}
+}
+
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (m *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(m, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Session) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *Session) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type CreateSessionRequest struct {
+	// ttl in seconds
+	Ttl                  int64    `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSessionRequest) Reset()         { *m = CreateSessionRequest{} }
+func (m *CreateSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSessionRequest) ProtoMessage()    {}
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{34}
+}
+
+func (m *CreateSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSessionRequest.Unmarshal(m, b)
+}
+func (m *CreateSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSessionRequest.Merge(m, src)
+}
+func (m *CreateSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSessionRequest.Size(m)
+}
+func (m *CreateSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSessionRequest proto.InternalMessageInfo
+
+func (m *CreateSessionRequest) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *CreateSessionRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CreateSessionResponse struct {
+	Session              *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSessionResponse) Reset()         { *m = CreateSessionResponse{} }
+func (m *CreateSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateSessionResponse) ProtoMessage()    {}
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{35}
+}
+
+func (m *CreateSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSessionResponse.Unmarshal(m, b)
+}
+func (m *CreateSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSessionResponse.Merge(m, src)
+}
+func (m *CreateSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateSessionResponse.Size(m)
+}
+func (m *CreateSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSessionResponse proto.InternalMessageInfo
+
+func (m *CreateSessionResponse) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type KeepAliveRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeepAliveRequest) Reset()         { *m = KeepAliveRequest{} }
+func (m *KeepAliveRequest) String() string { return proto.CompactTextString(m) }
+func (*KeepAliveRequest) ProtoMessage()    {}
+func (*KeepAliveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{36}
+}
+
+func (m *KeepAliveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeepAliveRequest.Unmarshal(m, b)
+}
+func (m *KeepAliveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeepAliveRequest.Marshal(b, m, deterministic)
+}
+func (m *KeepAliveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeepAliveRequest.Merge(m, src)
+}
+func (m *KeepAliveRequest) XXX_Size() int {
+	return xxx_messageInfo_KeepAliveRequest.Size(m)
+}
+func (m *KeepAliveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeepAliveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeepAliveRequest proto.InternalMessageInfo
+
+func (m *KeepAliveRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *KeepAliveRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type KeepAliveResponse struct {
+	Session              *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeepAliveResponse) Reset()         { *m = KeepAliveResponse{} }
+func (m *KeepAliveResponse) String() string { return proto.CompactTextString(m) }
+func (*KeepAliveResponse) ProtoMessage()    {}
+func (*KeepAliveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{37}
+}
+
+func (m *KeepAliveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeepAliveResponse.Unmarshal(m, b)
+}
+func (m *KeepAliveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeepAliveResponse.Marshal(b, m, deterministic)
+}
+func (m *KeepAliveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeepAliveResponse.Merge(m, src)
+}
+func (m *KeepAliveResponse) XXX_Size() int {
+	return xxx_messageInfo_KeepAliveResponse.Size(m)
+}
+func (m *KeepAliveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeepAliveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeepAliveResponse proto.InternalMessageInfo
+
+func (m *KeepAliveResponse) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeSessionRequest) Reset()         { *m = RevokeSessionRequest{} }
+func (m *RevokeSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeSessionRequest) ProtoMessage()    {}
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{38}
+}
+
+func (m *RevokeSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeSessionRequest.Unmarshal(m, b)
+}
+func (m *RevokeSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeSessionRequest.Merge(m, src)
+}
+func (m *RevokeSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeSessionRequest.Size(m)
+}
+func (m *RevokeSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeSessionRequest proto.InternalMessageInfo
+
+func (m *RevokeSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RevokeSessionRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevokeSessionResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeSessionResponse) Reset()         { *m = RevokeSessionResponse{} }
+func (m *RevokeSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeSessionResponse) ProtoMessage()    {}
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{39}
+}
+
+func (m *RevokeSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeSessionResponse.Unmarshal(m, b)
+}
+func (m *RevokeSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeSessionResponse.Merge(m, src)
+}
+func (m *RevokeSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeSessionResponse.Size(m)
+}
+func (m *RevokeSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeSessionResponse proto.InternalMessageInfo
+
+// Event is registry event
+type Event struct {
+	// Event Id
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// type of event
+	Type EventType `protobuf:"varint,2,opt,name=type,proto3,enum=registry.EventType" json:"type,omitempty"`
+	// unix timestamp of event
+	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// service entry
+	Service *Service `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	// sequence of the change in the changelog, zero
+	// if the event didn't change the registry
+	Sequence int64 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// domain of the service
+	Domain               string   `protobuf:"bytes,6,opt,name=domain,proto3" json:"domain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{40}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Event) GetType() EventType {
+	if m != nil {
+		return m.Type
+	}
+	return EventType_Create
+}
+
+func (m *Event) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Event) GetService() *Service {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *Event) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Event) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("registry.EventType", EventType_name, EventType_value)
+	proto.RegisterType((*Service)(nil), "registry.Service")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Service.MetadataEntry")
+	proto.RegisterType((*Node)(nil), "registry.Node")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Node.LabelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Node.MetadataEntry")
+	proto.RegisterType((*Endpoint)(nil), "registry.Endpoint")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Endpoint.MetadataEntry")
+	proto.RegisterType((*Value)(nil), "registry.Value")
+	proto.RegisterType((*Options)(nil), "registry.Options")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Options.LabelsEntry")
+	proto.RegisterType((*Result)(nil), "registry.Result")
+	proto.RegisterType((*EmptyResponse)(nil), "registry.EmptyResponse")
+	proto.RegisterType((*GetRequest)(nil), "registry.GetRequest")
+	proto.RegisterType((*GetResponse)(nil), "registry.GetResponse")
+	proto.RegisterType((*GetServicesRequest)(nil), "registry.GetServicesRequest")
+	proto.RegisterType((*GetServicesResponse)(nil), "registry.GetServicesResponse")
+	proto.RegisterType((*GraphRequest)(nil), "registry.GraphRequest")
+	proto.RegisterType((*GraphResponse)(nil), "registry.GraphResponse")
+	proto.RegisterType((*GraphNode)(nil), "registry.GraphNode")
+	proto.RegisterType((*Split)(nil), "registry.Split")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Split.LabelsEntry")
+	proto.RegisterType((*ResolveRequest)(nil), "registry.ResolveRequest")
+	proto.RegisterType((*ResolveResponse)(nil), "registry.ResolveResponse")
+	proto.RegisterType((*WeightedNode)(nil), "registry.WeightedNode")
+	proto.RegisterType((*ExportRequest)(nil), "registry.ExportRequest")
+	proto.RegisterType((*ExportResponse)(nil), "registry.ExportResponse")
+	proto.RegisterType((*ImportRequest)(nil), "registry.ImportRequest")
+	proto.RegisterMapType((map[string]string)(nil), "registry.ImportRequest.AddressesEntry")
+	proto.RegisterType((*ImportResponse)(nil), "registry.ImportResponse")
+	proto.RegisterType((*RevisionRequest)(nil), "registry.RevisionRequest")
+	proto.RegisterType((*RevisionResponse)(nil), "registry.RevisionResponse")
+	proto.RegisterType((*AuditRequest)(nil), "registry.AuditRequest")
+	proto.RegisterType((*AuditResponse)(nil), "registry.AuditResponse")
+	proto.RegisterType((*AuditEntry)(nil), "registry.AuditEntry")
+	proto.RegisterType((*ListDeletedRequest)(nil), "registry.ListDeletedRequest")
+	proto.RegisterType((*ListDeletedResponse)(nil), "registry.ListDeletedResponse")
+	proto.RegisterType((*Tombstone)(nil), "registry.Tombstone")
+	proto.RegisterType((*ListRequest)(nil), "registry.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "registry.ListResponse")
+	proto.RegisterType((*WatchRequest)(nil), "registry.WatchRequest")
+	proto.RegisterType((*Session)(nil), "registry.Session")
+	proto.RegisterType((*CreateSessionRequest)(nil), "registry.CreateSessionRequest")
+	proto.RegisterType((*CreateSessionResponse)(nil), "registry.CreateSessionResponse")
+	proto.RegisterType((*KeepAliveRequest)(nil), "registry.KeepAliveRequest")
+	proto.RegisterType((*KeepAliveResponse)(nil), "registry.KeepAliveResponse")
+	proto.RegisterType((*RevokeSessionRequest)(nil), "registry.RevokeSessionRequest")
+	proto.RegisterType((*RevokeSessionResponse)(nil), "registry.RevokeSessionResponse")
+	proto.RegisterType((*Event)(nil), "registry.Event")
+}
+
+func init() {
+	proto.RegisterFile("service/registry/proto/registry.proto", fileDescriptor_bba65e34813efea5)
+}
+
+var fileDescriptor_bba65e34813efea5 = []byte{
+	// 1652 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x18, 0x5d, 0x6f, 0xdb, 0x54,
+	0xb4, 0xce, 0x67, 0x73, 0xd2, 0x64, 0xdd, 0x6d, 0xb7, 0x05, 0xef, 0x53, 0x16, 0xb0, 0x8e, 0x8d,
+	0x74, 0x6a, 0x35, 0x69, 0x5b, 0x07, 0xdb, 0x68, 0xcb, 0x04, 0x0c, 0x26, 0x79, 0x1b, 0x20, 0x10,
+	0x20, 0x37, 0xbe, 0x74, 0xd6, 0x92, 0xd8, 0xd8, 0x4e, 0x68, 0x91, 0x10, 0x3c, 0x20, 0xf1, 0xc8,
+	0x23, 0xff, 0x84, 0xc7, 0xf1, 0x2f, 0x78, 0xe1, 0x47, 0x20, 0xfe, 0x01, 0xf7, 0xe3, 0x5c, 0xfb,
+	0xda, 0x71, 0x56, 0x92, 0xee, 0x25, 0xf2, 0xb9, 0xf7, 0x9c, 0x73, 0xcf, 0xf7, 0x47, 0xe0, 0x8d,
+	0x88, 0x86, 0x63, 0xaf, 0x47, 0xd7, 0x43, 0xba, 0xef, 0x45, 0x71, 0x78, 0xb8, 0x1e, 0x84, 0x7e,
+	0xec, 0x27, 0x60, 0x57, 0x80, 0x64, 0x51, 0xc1, 0xd6, 0x1f, 0x25, 0xa8, 0x3f, 0x96, 0x34, 0x84,
+	0x40, 0x65, 0xe8, 0x0c, 0x68, 0xc7, 0xb8, 0x64, 0xac, 0x35, 0x6c, 0xf1, 0x4d, 0x3a, 0x50, 0x1f,
+	0xd3, 0x30, 0xf2, 0xfc, 0x61, 0xa7, 0x24, 0x8e, 0x15, 0x48, 0xb6, 0x60, 0x71, 0x40, 0x63, 0xc7,
+	0x75, 0x62, 0xa7, 0x53, 0xbe, 0x54, 0x5e, 0x6b, 0x6e, 0x5c, 0xec, 0x26, 0xcf, 0x20, 0xcb, 0xee,
+	0xc7, 0x88, 0xb1, 0x3b, 0x64, 0xa7, 0x76, 0x42, 0x40, 0xae, 0x43, 0x83, 0x0e, 0xdd, 0xc0, 0xf7,
+	0x86, 0x71, 0xd4, 0xa9, 0x08, 0x6a, 0x92, 0x52, 0xef, 0xe2, 0x95, 0x9d, 0x22, 0x91, 0xd7, 0xa1,
+	0x3a, 0xf4, 0x5d, 0x1a, 0x75, 0xaa, 0x02, 0xbb, 0x9d, 0x62, 0x7f, 0xc2, 0x8e, 0x6d, 0x79, 0x49,
+	0xae, 0x42, 0xdd, 0x0f, 0x62, 0x26, 0x5e, 0xd4, 0xa9, 0x31, 0x71, 0x9b, 0x1b, 0x27, 0x53, 0xbc,
+	0x47, 0xf2, 0xc2, 0x56, 0x18, 0xe6, 0x16, 0xb4, 0x32, 0xf2, 0x91, 0x65, 0x28, 0x3f, 0xa7, 0x87,
+	0xa8, 0x3f, 0xff, 0x24, 0xab, 0x50, 0x1d, 0x3b, 0xfd, 0x11, 0x45, 0xe5, 0x25, 0x70, 0xbb, 0x74,
+	0xd3, 0xb0, 0xfe, 0x2a, 0x41, 0x85, 0xbf, 0x4c, 0xda, 0x50, 0xf2, 0x5c, 0xa4, 0x61, 0x5f, 0xdc,
+	0x62, 0x8e, 0xeb, 0x86, 0x34, 0x8a, 0x94, 0xc5, 0x10, 0xe4, 0xf6, 0x0d, 0xfc, 0x30, 0x66, 0xd6,
+	0x32, 0xd6, 0xca, 0xb6, 0xf8, 0x26, 0x37, 0x35, 0x2b, 0x4a, 0x3b, 0x9c, 0xcb, 0x6a, 0x36, 0xd5,
+	0x84, 0x1b, 0x50, 0xeb, 0x3b, 0x7b, 0xb4, 0xaf, 0x2c, 0x62, 0xe6, 0xe8, 0x1e, 0x8a, 0x4b, 0x49,
+	0x85, 0x98, 0xe4, 0x34, 0xd4, 0x38, 0x12, 0x73, 0x66, 0x4d, 0x88, 0x86, 0x10, 0x97, 0xec, 0x07,
+	0x7f, 0x48, 0x3b, 0x75, 0xe9, 0x79, 0xfe, 0x7d, 0x2c, 0xeb, 0x98, 0xb7, 0xa0, 0xa9, 0xbd, 0x3f,
+	0x93, 0x61, 0xff, 0x35, 0x60, 0x51, 0x05, 0x40, 0x61, 0x48, 0x5e, 0x81, 0x7a, 0x48, 0xbf, 0x1b,
+	0xd1, 0x28, 0x16, 0xc4, 0xcd, 0x8d, 0x13, 0xa9, 0xe6, 0x9f, 0x72, 0x36, 0xb6, 0xba, 0x67, 0xe1,
+	0xc0, 0x22, 0x3d, 0x0a, 0x98, 0xb3, 0xa9, 0xb0, 0x7a, 0x01, 0x6e, 0x82, 0x40, 0xee, 0x4c, 0xb8,
+	0xe2, 0xd2, 0x64, 0x48, 0x4e, 0x73, 0xc7, 0xf1, 0x82, 0xe9, 0x73, 0xa8, 0x0a, 0x69, 0x0a, 0xf5,
+	0x65, 0x67, 0xf1, 0x61, 0xa0, 0xa8, 0xc4, 0x37, 0xb9, 0x0c, 0x35, 0x41, 0x1d, 0x61, 0xea, 0x4d,
+	0xa8, 0x85, 0xd7, 0xd6, 0xdf, 0x06, 0xd4, 0x31, 0xf0, 0xb9, 0x44, 0x71, 0xdc, 0x17, 0xbc, 0xcb,
+	0x36, 0xff, 0xe4, 0xf1, 0xe0, 0xfa, 0x03, 0xc7, 0x53, 0xc9, 0x8d, 0x10, 0x8f, 0xe1, 0x88, 0x45,
+	0x2c, 0x0f, 0x94, 0xb2, 0x8c, 0x61, 0x04, 0x89, 0xc9, 0x2d, 0x3a, 0xf6, 0xc4, 0x55, 0x45, 0x30,
+	0x4a, 0x60, 0x72, 0x23, 0x17, 0x91, 0xe7, 0x27, 0x72, 0xaf, 0x28, 0x28, 0x8f, 0x13, 0x2b, 0xbf,
+	0x1a, 0x50, 0xb3, 0x69, 0x34, 0xea, 0xc7, 0x5c, 0x15, 0xa7, 0xc7, 0x1f, 0x41, 0x4a, 0x84, 0x78,
+	0x45, 0xc0, 0x9a, 0x88, 0xd1, 0x72, 0x72, 0xa2, 0x4a, 0xd9, 0x0a, 0x83, 0x9c, 0x83, 0x46, 0xec,
+	0x0d, 0x58, 0xe4, 0x38, 0x83, 0x00, 0xd3, 0x34, 0x3d, 0xe0, 0xba, 0x47, 0x3c, 0xb0, 0x86, 0x8c,
+	0x17, 0xea, 0xae, 0x60, 0xeb, 0x04, 0xb4, 0x76, 0x07, 0x41, 0x7c, 0x68, 0x63, 0x34, 0x59, 0x3f,
+	0x01, 0x3c, 0xa0, 0xb1, 0x8d, 0x81, 0xd8, 0x49, 0xa5, 0x30, 0x94, 0x41, 0xe5, 0x93, 0x5a, 0xc5,
+	0x2a, 0x1d, 0x55, 0xb1, 0xb4, 0xfc, 0x2d, 0x17, 0xe6, 0x6f, 0x25, 0xcd, 0x5f, 0xeb, 0x0e, 0x34,
+	0x85, 0x00, 0x18, 0xdd, 0x6f, 0x73, 0xe1, 0xc5, 0x93, 0x11, 0x13, 0xa1, 0x5c, 0x6c, 0x88, 0x04,
+	0xc5, 0xfa, 0x0a, 0x08, 0xa3, 0xc6, 0xf3, 0x48, 0xa9, 0x61, 0xe6, 0x98, 0x34, 0x52, 0x8a, 0x99,
+	0x14, 0xb1, 0x76, 0x60, 0x25, 0xc3, 0x7e, 0x3e, 0x21, 0x9f, 0xc2, 0xd2, 0x83, 0xd0, 0x09, 0x9e,
+	0xbd, 0x5a, 0x2b, 0x5b, 0xb7, 0xa1, 0x85, 0x6c, 0x51, 0xac, 0x2b, 0xaa, 0xf7, 0x48, 0x99, 0x56,
+	0x52, 0x5a, 0x81, 0xa7, 0x35, 0x20, 0xeb, 0x47, 0x68, 0x24, 0x67, 0x85, 0xd9, 0x6c, 0xc1, 0x92,
+	0x4b, 0x03, 0xd6, 0xd7, 0x58, 0xd8, 0x78, 0x94, 0x8b, 0xc3, 0xcd, 0x98, 0x39, 0x23, 0x17, 0x00,
+	0x14, 0x1c, 0xcb, 0x0c, 0x6f, 0xd8, 0xda, 0x09, 0xd7, 0x73, 0xe0, 0xb1, 0x7c, 0x1c, 0xee, 0x0b,
+	0x8f, 0x2f, 0xda, 0x0a, 0xb4, 0x7e, 0x33, 0xa0, 0xfa, 0x38, 0xe8, 0x7b, 0x31, 0xd9, 0x4c, 0x92,
+	0x51, 0x0a, 0x7d, 0x56, 0x33, 0x24, 0x47, 0x98, 0xd6, 0x1f, 0xbe, 0xa7, 0xde, 0xfe, 0x33, 0x59,
+	0x59, 0xcb, 0x36, 0x42, 0xc7, 0x49, 0xd1, 0x9f, 0x0d, 0x68, 0x33, 0x43, 0xfa, 0xfd, 0x31, 0x3d,
+	0xda, 0x4d, 0xac, 0xac, 0x45, 0x5c, 0x38, 0x69, 0x96, 0x4c, 0x59, 0x13, 0x42, 0xdb, 0x78, 0xad,
+	0xfb, 0xb3, 0x7c, 0xa4, 0x3f, 0xef, 0xc2, 0x89, 0x44, 0x02, 0xf4, 0xe8, 0xb5, 0xac, 0x47, 0x4f,
+	0xa7, 0xd4, 0x9f, 0x09, 0x8d, 0xa9, 0xab, 0x3b, 0xd5, 0x85, 0x25, 0xfd, 0x98, 0xf9, 0xb0, 0xc2,
+	0x2f, 0x84, 0xf4, 0x93, 0xa3, 0x88, 0xb8, 0x7b, 0xc9, 0xe0, 0x94, 0x1a, 0x99, 0x8b, 0x6e, 0x28,
+	0x23, 0xb3, 0x84, 0x6d, 0xed, 0x1e, 0xf0, 0xa1, 0xc0, 0x4e, 0xba, 0x57, 0xa2, 0xa4, 0xf1, 0x3f,
+	0x94, 0x6c, 0x2b, 0xea, 0xf9, 0x92, 0xe9, 0x1f, 0x03, 0x5a, 0x1f, 0x0c, 0xf4, 0xf7, 0x67, 0x63,
+	0x40, 0x76, 0xa0, 0x81, 0x93, 0x0e, 0x55, 0xfe, 0x7b, 0x33, 0xc5, 0xcf, 0xb0, 0xee, 0xde, 0x57,
+	0x88, 0x32, 0xfe, 0x52, 0xc2, 0x99, 0x3c, 0x6b, 0xde, 0x81, 0x76, 0x96, 0xd3, 0x4c, 0xa1, 0x79,
+	0x0d, 0xda, 0x4a, 0x2a, 0x34, 0x19, 0xab, 0x6f, 0x9e, 0x38, 0xa1, 0x2e, 0xb6, 0xc9, 0x04, 0xb6,
+	0xde, 0xe5, 0x51, 0x24, 0x3b, 0xdd, 0x5c, 0x0e, 0xea, 0xc2, 0x72, 0x4a, 0x9f, 0xbe, 0x97, 0x74,
+	0x53, 0x23, 0xdb, 0x4d, 0xad, 0xdf, 0x0d, 0x58, 0xba, 0x3f, 0x72, 0xbd, 0xb9, 0xc2, 0x41, 0xcf,
+	0xb1, 0x52, 0x36, 0xc7, 0x98, 0x3d, 0x58, 0xa9, 0xe8, 0x51, 0xec, 0x6f, 0x12, 0xe0, 0xa7, 0xa3,
+	0x61, 0xec, 0xf5, 0xb1, 0xb1, 0x49, 0x80, 0x9f, 0xf6, 0xbd, 0x81, 0x17, 0xb3, 0x86, 0x2e, 0x4e,
+	0x05, 0xc0, 0x42, 0xad, 0x85, 0x82, 0xa1, 0x1a, 0x5d, 0xa8, 0xb3, 0xba, 0x14, 0x7a, 0x49, 0x9c,
+	0xac, 0xa6, 0x92, 0x09, 0x4c, 0xe9, 0x65, 0x85, 0x64, 0xbd, 0x30, 0x00, 0xd2, 0xf3, 0xa9, 0xad,
+	0x7b, 0xba, 0x0e, 0x5a, 0x72, 0x95, 0xb3, 0xc9, 0xb5, 0xaa, 0x12, 0xbb, 0x22, 0xaa, 0x26, 0xae,
+	0x05, 0x7c, 0x26, 0xef, 0xf5, 0x7c, 0xa6, 0x94, 0xd0, 0x84, 0xcf, 0xe4, 0x12, 0xe4, 0x6f, 0xb3,
+	0xd2, 0x39, 0xa2, 0xa1, 0x9a, 0x88, 0x25, 0x94, 0x9d, 0x04, 0xea, 0xb9, 0x49, 0xc0, 0xfa, 0x12,
+	0xc8, 0x43, 0xa6, 0xde, 0x0e, 0xed, 0x53, 0x16, 0x1a, 0xaf, 0xd6, 0x41, 0xd6, 0x87, 0xb0, 0x92,
+	0x61, 0x8e, 0x46, 0xde, 0x04, 0x88, 0xfd, 0xc1, 0x5e, 0x14, 0xb3, 0xe6, 0x5e, 0xd0, 0x89, 0x9e,
+	0xa8, 0x3b, 0x5b, 0x43, 0xb3, 0x7e, 0x31, 0xa0, 0x91, 0xdc, 0xe8, 0xb3, 0x90, 0x71, 0xe4, 0x2c,
+	0xc4, 0x04, 0x74, 0xa5, 0x08, 0xd8, 0x0c, 0x14, 0xa8, 0x5b, 0xb3, 0x3c, 0xcd, 0x9a, 0x15, 0xdd,
+	0x9a, 0xbc, 0x09, 0x34, 0xb9, 0x4e, 0x73, 0x59, 0x8a, 0x31, 0x0d, 0x42, 0xfa, 0xad, 0x77, 0xa0,
+	0x86, 0x54, 0x09, 0xa5, 0xc1, 0x59, 0xd6, 0x82, 0x93, 0x63, 0xf7, 0x46, 0x61, 0xe4, 0x27, 0x22,
+	0x48, 0xc8, 0xfa, 0x1a, 0x96, 0xa4, 0x04, 0x73, 0x55, 0x47, 0x72, 0x11, 0x9a, 0x43, 0x7a, 0x10,
+	0x7f, 0x83, 0xbc, 0xa5, 0x24, 0xc0, 0x8f, 0xb6, 0x25, 0xff, 0xe7, 0xac, 0x47, 0x38, 0x71, 0xef,
+	0x15, 0xcf, 0x22, 0xc5, 0xd9, 0x6a, 0x6d, 0xf3, 0xa5, 0x5d, 0x0e, 0xe4, 0xf9, 0xf5, 0x13, 0x87,
+	0xfc, 0x52, 0x66, 0xc8, 0xa7, 0x07, 0x81, 0x17, 0x1e, 0x22, 0x0f, 0x84, 0xd8, 0xf4, 0xb4, 0xba,
+	0x1d, 0x52, 0x27, 0xa6, 0xc8, 0x4a, 0x49, 0x3e, 0xb9, 0x26, 0xcc, 0x38, 0xda, 0x9d, 0xca, 0xb1,
+	0x45, 0x8b, 0x5f, 0x4d, 0x97, 0x8a, 0x82, 0xe8, 0x93, 0xb8, 0x0a, 0xc3, 0x7a, 0x04, 0xcb, 0x1f,
+	0x51, 0x1a, 0xdc, 0xef, 0x7b, 0xe9, 0xdc, 0x90, 0x57, 0x75, 0x26, 0xb1, 0xee, 0xc1, 0x49, 0x8d,
+	0xe1, 0x3c, 0x22, 0x3d, 0x86, 0x55, 0x56, 0xc0, 0xfd, 0xe7, 0x79, 0x7b, 0x1d, 0x4b, 0xac, 0x33,
+	0x70, 0x2a, 0xc7, 0x14, 0xf7, 0x87, 0x3f, 0xd9, 0x24, 0xb7, 0x3b, 0x66, 0x15, 0x73, 0x82, 0xff,
+	0x65, 0x6d, 0x1f, 0x6c, 0xeb, 0x25, 0x40, 0xa0, 0x3f, 0x61, 0x57, 0xb8, 0x24, 0xbe, 0x7c, 0x9b,
+	0xd1, 0x8a, 0x41, 0xe5, 0xc8, 0x62, 0xa0, 0xaf, 0x3e, 0xd5, 0xec, 0xea, 0xa3, 0x2d, 0x91, 0x35,
+	0x7d, 0x89, 0x7c, 0x6b, 0x1d, 0x1a, 0x89, 0x44, 0x04, 0xa0, 0x26, 0xa3, 0x62, 0x79, 0x81, 0x7f,
+	0xcb, 0xe2, 0xb6, 0x6c, 0xf0, 0xef, 0xa7, 0x81, 0xcb, 0xcf, 0x4b, 0x1b, 0x2f, 0x16, 0x61, 0xd1,
+	0x46, 0x11, 0xc8, 0x96, 0xd8, 0x9f, 0xd4, 0x5f, 0x53, 0x5a, 0x43, 0x49, 0xb7, 0x2a, 0xf3, 0x54,
+	0xee, 0x14, 0x4d, 0xb7, 0x40, 0x1e, 0x8a, 0xdd, 0x47, 0xad, 0x17, 0xe4, 0x5c, 0x06, 0x2f, 0xb7,
+	0xd4, 0x98, 0xe7, 0xa7, 0xdc, 0x26, 0xdc, 0x6e, 0x2a, 0xb1, 0x58, 0x5f, 0x98, 0x34, 0x92, 0x79,
+	0x46, 0xf3, 0x40, 0x66, 0x05, 0x5c, 0x20, 0xb7, 0x01, 0x76, 0x68, 0x38, 0x1f, 0xed, 0x5d, 0x59,
+	0xb0, 0x12, 0x25, 0x34, 0x65, 0xb5, 0x52, 0x6a, 0x9e, 0xce, 0x1f, 0x27, 0x0c, 0x6e, 0x40, 0x55,
+	0x54, 0x24, 0xa2, 0x4f, 0xb7, 0x5a, 0x89, 0x32, 0x97, 0xd3, 0x73, 0xb9, 0x44, 0x5b, 0x0b, 0xd7,
+	0x0d, 0x62, 0x43, 0x2b, 0x93, 0xbf, 0xe4, 0x42, 0x8a, 0x56, 0x54, 0x2f, 0xcc, 0x8b, 0x53, 0xef,
+	0x13, 0x51, 0xde, 0x87, 0x46, 0x92, 0x7c, 0x44, 0xfb, 0xa3, 0x2a, 0x9f, 0xe2, 0xe6, 0xd9, 0xc2,
+	0xbb, 0x84, 0x0f, 0x93, 0x2d, 0x93, 0x2d, 0xba, 0x6c, 0x45, 0xb9, 0xa9, 0xcb, 0x56, 0x9c, 0x66,
+	0xdc, 0x47, 0x55, 0xb1, 0xb1, 0xe9, 0x66, 0xd2, 0xb7, 0x4a, 0xdd, 0x47, 0x99, 0xb5, 0x90, 0xd1,
+	0xde, 0x83, 0x3a, 0x6e, 0x16, 0xa4, 0x93, 0x31, 0xa6, 0xb6, 0xee, 0x98, 0xaf, 0x15, 0xdc, 0x24,
+	0x1c, 0xde, 0x81, 0x9a, 0x1c, 0xdb, 0x89, 0x1e, 0x0a, 0xfa, 0x1a, 0x60, 0x76, 0x26, 0x2f, 0x74,
+	0x72, 0x39, 0xc2, 0xea, 0xe4, 0x99, 0x51, 0x5b, 0x27, 0xcf, 0x4e, 0xbb, 0x8c, 0x7c, 0x9b, 0x47,
+	0x36, 0xfe, 0x7b, 0x93, 0x11, 0x33, 0x33, 0xe7, 0x9a, 0x66, 0xd1, 0x95, 0x6e, 0x40, 0x31, 0xcc,
+	0xe9, 0x06, 0xd4, 0x07, 0x57, 0xdd, 0x80, 0x99, 0xb9, 0x51, 0x26, 0xaa, 0x36, 0xeb, 0xe8, 0x89,
+	0x3a, 0x39, 0x5f, 0xe9, 0x89, 0x5a, 0x30, 0x20, 0x59, 0x0b, 0xef, 0x6d, 0x7d, 0x71, 0x6b, 0xdf,
+	0x8b, 0x9f, 0x8d, 0xf6, 0xba, 0x3d, 0x7f, 0xb0, 0x3e, 0xf0, 0x7a, 0xa1, 0x8f, 0xbf, 0xe3, 0xcd,
+	0xf5, 0xe2, 0x7f, 0xc6, 0xb7, 0x14, 0xb8, 0x57, 0x13, 0xf0, 0xe6, 0x7f, 0x76, 0x0c, 0x6f, 0x32,
+	0x43, 0x17, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RegistryClient is the client API for Registry service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RegistryClient interface {
+	GetService(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error)
+	Register(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error)
+	Deregister(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error)
+	ListServices(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Registry_WatchClient, error)
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (*KeepAliveResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	Graph(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (*GraphResponse, error)
+	Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error)
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error)
+	Revision(ctx context.Context, in *RevisionRequest, opts ...grpc.CallOption) (*RevisionResponse, error)
+	Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error)
+	ListDeleted(ctx context.Context, in *ListDeletedRequest, opts ...grpc.CallOption) (*ListDeletedResponse, error)
+}
+
+type registryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRegistryClient(cc *grpc.ClientConn) RegistryClient {
+	return &registryClient{cc}
+}
+
+func (c *registryClient) GetService(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error) {
+	out := new(GetServicesResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetServices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Register(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Register", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Deregister(ctx context.Context, in *Service, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Deregister", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (c *registryClient) ListServices(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
@@ -900,13 +2536,114 @@ func (x *registryWatchClient) Recv() (*Result, error) {
 	return m, nil
 }
 
+func (c *registryClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/CreateSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (*KeepAliveResponse, error) {
+	out := new(KeepAliveResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/KeepAlive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/RevokeSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Graph(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (*GraphResponse, error) {
+	out := new(GraphResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Graph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error) {
+	out := new(ResolveResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Resolve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Export", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error) {
+	out := new(ImportResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Import", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Revision(ctx context.Context, in *RevisionRequest, opts ...grpc.CallOption) (*RevisionResponse, error) {
+	out := new(RevisionResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Revision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error) {
+	out := new(AuditResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Audit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListDeleted(ctx context.Context, in *ListDeletedRequest, opts ...grpc.CallOption) (*ListDeletedResponse, error) {
+	out := new(ListDeletedResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ListDeleted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RegistryServer is the server API for Registry service.
 type RegistryServer interface {
 	GetService(context.Context, *GetRequest) (*GetResponse, error)
+	GetServices(context.Context, *GetServicesRequest) (*GetServicesResponse, error)
 	Register(context.Context, *Service) (*EmptyResponse, error)
 	Deregister(context.Context, *Service) (*EmptyResponse, error)
 	ListServices(context.Context, *ListRequest) (*ListResponse, error)
 	Watch(*WatchRequest, Registry_WatchServer) error
+	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	KeepAlive(context.Context, *KeepAliveRequest) (*KeepAliveResponse, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	Graph(context.Context, *GraphRequest) (*GraphResponse, error)
+	Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error)
+	Export(context.Context, *ExportRequest) (*ExportResponse, error)
+	Import(context.Context, *ImportRequest) (*ImportResponse, error)
+	Revision(context.Context, *RevisionRequest) (*RevisionResponse, error)
+	Audit(context.Context, *AuditRequest) (*AuditResponse, error)
+	ListDeleted(context.Context, *ListDeletedRequest) (*ListDeletedResponse, error)
 }
 
 // UnimplementedRegistryServer can be embedded to have forward compatible implementations.
@@ -916,6 +2653,9 @@ type UnimplementedRegistryServer struct {
 func (*UnimplementedRegistryServer) GetService(ctx context.Context, req *GetRequest) (*GetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetService not implemented")
 }
+func (*UnimplementedRegistryServer) GetServices(ctx context.Context, req *GetServicesRequest) (*GetServicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServices not implemented")
+}
 func (*UnimplementedRegistryServer) Register(ctx context.Context, req *Service) (*EmptyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
@@ -928,6 +2668,36 @@ func (*UnimplementedRegistryServer) ListServices(ctx context.Context, req *ListR
 func (*UnimplementedRegistryServer) Watch(req *WatchRequest, srv Registry_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (*UnimplementedRegistryServer) CreateSession(ctx context.Context, req *CreateSessionRequest) (*CreateSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (*UnimplementedRegistryServer) KeepAlive(ctx context.Context, req *KeepAliveRequest) (*KeepAliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KeepAlive not implemented")
+}
+func (*UnimplementedRegistryServer) RevokeSession(ctx context.Context, req *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (*UnimplementedRegistryServer) Graph(ctx context.Context, req *GraphRequest) (*GraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Graph not implemented")
+}
+func (*UnimplementedRegistryServer) Resolve(ctx context.Context, req *ResolveRequest) (*ResolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+func (*UnimplementedRegistryServer) Export(ctx context.Context, req *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (*UnimplementedRegistryServer) Import(ctx context.Context, req *ImportRequest) (*ImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (*UnimplementedRegistryServer) Revision(ctx context.Context, req *RevisionRequest) (*RevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revision not implemented")
+}
+func (*UnimplementedRegistryServer) Audit(ctx context.Context, req *AuditRequest) (*AuditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Audit not implemented")
+}
+func (*UnimplementedRegistryServer) ListDeleted(ctx context.Context, req *ListDeletedRequest) (*ListDeletedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeleted not implemented")
+}
 
 func RegisterRegistryServer(s *grpc.Server, srv RegistryServer) {
 	s.RegisterService(&_Registry_serviceDesc, srv)
@@ -951,6 +2721,24 @@ func _Registry_GetService_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_GetServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetServices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetServices(ctx, req.(*GetServicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Registry_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Service)
 	if err := dec(in); err != nil {
@@ -1026,6 +2814,186 @@ func (x *registryWatchServer) Send(m *Result) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Registry_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/CreateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_KeepAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeepAliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).KeepAlive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/KeepAlive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).KeepAlive(ctx, req.(*KeepAliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/RevokeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Graph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Graph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Graph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Graph(ctx, req.(*GraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Resolve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Resolve(ctx, req.(*ResolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Export",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Export(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Import_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Import(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Import",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Import(ctx, req.(*ImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Revision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Revision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Revision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Revision(ctx, req.(*RevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Audit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Audit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Audit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Audit(ctx, req.(*AuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ListDeleted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListDeleted(ctx, req.(*ListDeletedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Registry_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "registry.Registry",
 	HandlerType: (*RegistryServer)(nil),
@@ -1034,6 +3002,10 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetService",
 			Handler:    _Registry_GetService_Handler,
 		},
+		{
+			MethodName: "GetServices",
+			Handler:    _Registry_GetServices_Handler,
+		},
 		{
 			MethodName: "Register",
 			Handler:    _Registry_Register_Handler,
@@ -1046,6 +3018,46 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListServices",
 			Handler:    _Registry_ListServices_Handler,
 		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _Registry_CreateSession_Handler,
+		},
+		{
+			MethodName: "KeepAlive",
+			Handler:    _Registry_KeepAlive_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Registry_RevokeSession_Handler,
+		},
+		{
+			MethodName: "Graph",
+			Handler:    _Registry_Graph_Handler,
+		},
+		{
+			MethodName: "Resolve",
+			Handler:    _Registry_Resolve_Handler,
+		},
+		{
+			MethodName: "Export",
+			Handler:    _Registry_Export_Handler,
+		},
+		{
+			MethodName: "Import",
+			Handler:    _Registry_Import_Handler,
+		},
+		{
+			MethodName: "Revision",
+			Handler:    _Registry_Revision_Handler,
+		},
+		{
+			MethodName: "Audit",
+			Handler:    _Registry_Audit_Handler,
+		},
+		{
+			MethodName: "ListDeleted",
+			Handler:    _Registry_ListDeleted_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -43,10 +43,21 @@ func NewRegistryEndpoints() []*api.Endpoint {
 
 type RegistryService interface {
 	GetService(ctx context.Context, in *GetRequest, opts ...client.CallOption) (*GetResponse, error)
+	GetServices(ctx context.Context, in *GetServicesRequest, opts ...client.CallOption) (*GetServicesResponse, error)
 	Register(ctx context.Context, in *Service, opts ...client.CallOption) (*EmptyResponse, error)
 	Deregister(ctx context.Context, in *Service, opts ...client.CallOption) (*EmptyResponse, error)
 	ListServices(ctx context.Context, in *ListRequest, opts ...client.CallOption) (*ListResponse, error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Registry_WatchService, error)
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...client.CallOption) (*CreateSessionResponse, error)
+	KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...client.CallOption) (*KeepAliveResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...client.CallOption) (*RevokeSessionResponse, error)
+	Graph(ctx context.Context, in *GraphRequest, opts ...client.CallOption) (*GraphResponse, error)
+	Resolve(ctx context.Context, in *ResolveRequest, opts ...client.CallOption) (*ResolveResponse, error)
+	Export(ctx context.Context, in *ExportRequest, opts ...client.CallOption) (*ExportResponse, error)
+	Import(ctx context.Context, in *ImportRequest, opts ...client.CallOption) (*ImportResponse, error)
+	Revision(ctx context.Context, in *RevisionRequest, opts ...client.CallOption) (*RevisionResponse, error)
+	Audit(ctx context.Context, in *AuditRequest, opts ...client.CallOption) (*AuditResponse, error)
+	ListDeleted(ctx context.Context, in *ListDeletedRequest, opts ...client.CallOption) (*ListDeletedResponse, error)
 }
 
 type registryService struct {
@@ -71,6 +82,16 @@ func (c *registryService) GetService(ctx context.Context, in *GetRequest, opts .
 	return out, nil
 }
 
+func (c *registryService) GetServices(ctx context.Context, in *GetServicesRequest, opts ...client.CallOption) (*GetServicesResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.GetServices", in)
+	out := new(GetServicesResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *registryService) Register(ctx context.Context, in *Service, opts ...client.CallOption) (*EmptyResponse, error) {
 	req := c.c.NewRequest(c.name, "Registry.Register", in)
 	out := new(EmptyResponse)
@@ -150,23 +171,145 @@ func (x *registryServiceWatch) Recv() (*Result, error) {
 	return m, nil
 }
 
+func (c *registryService) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...client.CallOption) (*CreateSessionResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.CreateSession", in)
+	out := new(CreateSessionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...client.CallOption) (*KeepAliveResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.KeepAlive", in)
+	out := new(KeepAliveResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...client.CallOption) (*RevokeSessionResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.RevokeSession", in)
+	out := new(RevokeSessionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) Graph(ctx context.Context, in *GraphRequest, opts ...client.CallOption) (*GraphResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.Graph", in)
+	out := new(GraphResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) Resolve(ctx context.Context, in *ResolveRequest, opts ...client.CallOption) (*ResolveResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.Resolve", in)
+	out := new(ResolveResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) Export(ctx context.Context, in *ExportRequest, opts ...client.CallOption) (*ExportResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.Export", in)
+	out := new(ExportResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) Import(ctx context.Context, in *ImportRequest, opts ...client.CallOption) (*ImportResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.Import", in)
+	out := new(ImportResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) Revision(ctx context.Context, in *RevisionRequest, opts ...client.CallOption) (*RevisionResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.Revision", in)
+	out := new(RevisionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) Audit(ctx context.Context, in *AuditRequest, opts ...client.CallOption) (*AuditResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.Audit", in)
+	out := new(AuditResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryService) ListDeleted(ctx context.Context, in *ListDeletedRequest, opts ...client.CallOption) (*ListDeletedResponse, error) {
+	req := c.c.NewRequest(c.name, "Registry.ListDeleted", in)
+	out := new(ListDeletedResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Registry service
 
 type RegistryHandler interface {
 	GetService(context.Context, *GetRequest, *GetResponse) error
+	GetServices(context.Context, *GetServicesRequest, *GetServicesResponse) error
 	Register(context.Context, *Service, *EmptyResponse) error
 	Deregister(context.Context, *Service, *EmptyResponse) error
 	ListServices(context.Context, *ListRequest, *ListResponse) error
 	Watch(context.Context, *WatchRequest, Registry_WatchStream) error
+	CreateSession(context.Context, *CreateSessionRequest, *CreateSessionResponse) error
+	KeepAlive(context.Context, *KeepAliveRequest, *KeepAliveResponse) error
+	RevokeSession(context.Context, *RevokeSessionRequest, *RevokeSessionResponse) error
+	Graph(context.Context, *GraphRequest, *GraphResponse) error
+	Resolve(context.Context, *ResolveRequest, *ResolveResponse) error
+	Export(context.Context, *ExportRequest, *ExportResponse) error
+	Import(context.Context, *ImportRequest, *ImportResponse) error
+	Revision(context.Context, *RevisionRequest, *RevisionResponse) error
+	Audit(context.Context, *AuditRequest, *AuditResponse) error
+	ListDeleted(context.Context, *ListDeletedRequest, *ListDeletedResponse) error
 }
 
 func RegisterRegistryHandler(s server.Server, hdlr RegistryHandler, opts ...server.HandlerOption) error {
 	type registry interface {
 		GetService(ctx context.Context, in *GetRequest, out *GetResponse) error
+		GetServices(ctx context.Context, in *GetServicesRequest, out *GetServicesResponse) error
 		Register(ctx context.Context, in *Service, out *EmptyResponse) error
 		Deregister(ctx context.Context, in *Service, out *EmptyResponse) error
 		ListServices(ctx context.Context, in *ListRequest, out *ListResponse) error
 		Watch(ctx context.Context, stream server.Stream) error
+		CreateSession(ctx context.Context, in *CreateSessionRequest, out *CreateSessionResponse) error
+		KeepAlive(ctx context.Context, in *KeepAliveRequest, out *KeepAliveResponse) error
+		RevokeSession(ctx context.Context, in *RevokeSessionRequest, out *RevokeSessionResponse) error
+		Graph(ctx context.Context, in *GraphRequest, out *GraphResponse) error
+		Resolve(ctx context.Context, in *ResolveRequest, out *ResolveResponse) error
+		Export(ctx context.Context, in *ExportRequest, out *ExportResponse) error
+		Import(ctx context.Context, in *ImportRequest, out *ImportResponse) error
+		Revision(ctx context.Context, in *RevisionRequest, out *RevisionResponse) error
+		Audit(ctx context.Context, in *AuditRequest, out *AuditResponse) error
+		ListDeleted(ctx context.Context, in *ListDeletedRequest, out *ListDeletedResponse) error
 	}
 	type Registry struct {
 		registry
@@ -183,6 +326,10 @@ func (h *registryHandler) GetService(ctx context.Context, in *GetRequest, out *G
 	return h.RegistryHandler.GetService(ctx, in, out)
 }
 
+func (h *registryHandler) GetServices(ctx context.Context, in *GetServicesRequest, out *GetServicesResponse) error {
+	return h.RegistryHandler.GetServices(ctx, in, out)
+}
+
 func (h *registryHandler) Register(ctx context.Context, in *Service, out *EmptyResponse) error {
 	return h.RegistryHandler.Register(ctx, in, out)
 }
@@ -234,3 +381,43 @@ func (x *registryWatchStream) RecvMsg(m interface{}) error {
 func (x *registryWatchStream) Send(m *Result) error {
 	return x.stream.Send(m)
 }
+
+func (h *registryHandler) CreateSession(ctx context.Context, in *CreateSessionRequest, out *CreateSessionResponse) error {
+	return h.RegistryHandler.CreateSession(ctx, in, out)
+}
+
+func (h *registryHandler) KeepAlive(ctx context.Context, in *KeepAliveRequest, out *KeepAliveResponse) error {
+	return h.RegistryHandler.KeepAlive(ctx, in, out)
+}
+
+func (h *registryHandler) RevokeSession(ctx context.Context, in *RevokeSessionRequest, out *RevokeSessionResponse) error {
+	return h.RegistryHandler.RevokeSession(ctx, in, out)
+}
+
+func (h *registryHandler) Graph(ctx context.Context, in *GraphRequest, out *GraphResponse) error {
+	return h.RegistryHandler.Graph(ctx, in, out)
+}
+
+func (h *registryHandler) Resolve(ctx context.Context, in *ResolveRequest, out *ResolveResponse) error {
+	return h.RegistryHandler.Resolve(ctx, in, out)
+}
+
+func (h *registryHandler) Export(ctx context.Context, in *ExportRequest, out *ExportResponse) error {
+	return h.RegistryHandler.Export(ctx, in, out)
+}
+
+func (h *registryHandler) Import(ctx context.Context, in *ImportRequest, out *ImportResponse) error {
+	return h.RegistryHandler.Import(ctx, in, out)
+}
+
+func (h *registryHandler) Revision(ctx context.Context, in *RevisionRequest, out *RevisionResponse) error {
+	return h.RegistryHandler.Revision(ctx, in, out)
+}
+
+func (h *registryHandler) Audit(ctx context.Context, in *AuditRequest, out *AuditResponse) error {
+	return h.RegistryHandler.Audit(ctx, in, out)
+}
+
+func (h *registryHandler) ListDeleted(ctx context.Context, in *ListDeletedRequest, out *ListDeletedResponse) error {
+	return h.RegistryHandler.ListDeleted(ctx, in, out)
+}
@@ -4,6 +4,8 @@ package registry
 import (
 	"github.com/micro/go-micro/v3/registry"
 	"github.com/micro/micro/v3/service/registry/client"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
 )
 
 var (
@@ -11,6 +13,33 @@ var (
 	DefaultRegistry registry.Registry = client.NewRegistry()
 )
 
+// QuotaExceededError is returned by Register when the namespace has reached its quota
+type QuotaExceededError = client.QuotaExceededError
+
+var (
+	// ErrRevisionChanged is returned by reads made at a revision if the services read have
+	// changed since the revision
+	ErrRevisionChanged = client.ErrRevisionChanged
+	// GetAtRevision makes the read consistent with a revision returned by Revision
+	GetAtRevision = client.GetAtRevision
+	// ListAtRevision makes the list consistent with a revision returned by Revision
+	ListAtRevision = client.ListAtRevision
+
+	// Labels the nodes registered are labelled with, set with DefaultRegistry.Init
+	Labels = client.Labels
+	// GetLabels only returns the nodes which have all the labels
+	GetLabels = client.GetLabels
+	// ListLabels only lists the nodes which have all the labels
+	ListLabels = client.ListLabels
+	// WatchLabels only watches the nodes which have all the labels
+	WatchLabels = client.WatchLabels
+
+	// Locality the nodes registered are in, set with DefaultRegistry.Init
+	Locality = client.Locality
+	// GetLocality prefers the nodes in the zone, then those in the region
+	GetLocality = client.GetLocality
+)
+
 // Register a service
 func Register(service *registry.Service, opts ...registry.RegisterOption) error {
 	return DefaultRegistry.Register(service, opts...)
@@ -26,11 +55,123 @@ func GetService(service string, opts ...registry.GetOption) ([]*registry.Service
 	return DefaultRegistry.GetService(service, opts...)
 }
 
+// GetServices returns the services with the given names. If the registry does not
+// support bulk lookups, GetService is called for each name. Services which do not
+// exist are omitted from the result.
+func GetServices(names []string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	if r, ok := DefaultRegistry.(interface {
+		GetServices([]string, ...registry.GetOption) ([]*registry.Service, error)
+	}); ok {
+		return r.GetServices(names, opts...)
+	}
+
+	var services []*registry.Service
+	for _, name := range names {
+		srvs, err := DefaultRegistry.GetService(name, opts...)
+		if util.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		services = append(services, srvs...)
+	}
+	return services, nil
+}
+
+// Resolve the nodes of the service and the share of traffic each should receive according
+// to the splits, e.g. to send a tenth of traffic to canary nodes:
+//
+//	registry.Resolve("foo", []*pb.Split{
+//		{Labels: map[string]string{"canary": "true"}, Weight: 10},
+//		{Weight: 90},
+//	})
+//
+// Use util.Pick to select a node from the result.
+func Resolve(service string, splits []*pb.Split, opts ...registry.GetOption) ([]*pb.WeightedNode, error) {
+	if r, ok := DefaultRegistry.(interface {
+		Resolve(string, []*pb.Split, ...registry.GetOption) ([]*pb.WeightedNode, error)
+	}); ok {
+		return r.Resolve(service, splits, opts...)
+	}
+
+	// registries which don't keep the labels of the nodes can only split traffic by version
+	services, err := DefaultRegistry.GetService(service, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pbServices := make([]*pb.Service, len(services))
+	for i, s := range services {
+		pbServices[i] = util.ToProto(s)
+	}
+	return util.Resolve(pbServices, splits), nil
+}
+
+// Graph returns the dependency graph of the services, optionally limited to those connected
+// to the service. If the registry does not support graph queries, the graph is built from
+// the services listed.
+func Graph(service string, opts ...registry.ListOption) ([]*pb.GraphNode, error) {
+	if r, ok := DefaultRegistry.(interface {
+		Graph(string, ...registry.ListOption) ([]*pb.GraphNode, error)
+	}); ok {
+		return r.Graph(service, opts...)
+	}
+
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	list, err := DefaultRegistry.ListServices(opts...)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list))
+	for _, s := range list {
+		names = append(names, s.Name)
+	}
+	services, err := GetServices(names, registry.GetDomain(options.Domain))
+	if err != nil {
+		return nil, err
+	}
+	return util.Graph(services, service), nil
+}
+
+// Revision returns the revision of the last change made to the domain. Pass it to
+// GetAtRevision and ListAtRevision to make multiple reads consistent, retrying them if they
+// fail with ErrRevisionChanged, or to WatchSince to watch the changes made after it. If the
+// registry doesn't support revisions, zero is returned, which reads at a revision ignore.
+func Revision(opts ...registry.ListOption) (int64, error) {
+	if r, ok := DefaultRegistry.(interface {
+		Revision(...registry.ListOption) (int64, error)
+	}); ok {
+		return r.Revision(opts...)
+	}
+	return 0, nil
+}
+
 // ListServices in the registry
 func ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
 	return DefaultRegistry.ListServices(opts...)
 }
 
+// ListPage returns a page of at most limit services whose names have the prefix, ordered by
+// name, and the cursor of the next page, which is blank if it's the last page. Pass a blank
+// cursor for the first page. The versions of a service are returned together and count as
+// one. If the registry doesn't support pagination, the page is built from the services listed.
+func ListPage(prefix, cursor string, limit int, opts ...registry.ListOption) ([]*registry.Service, string, error) {
+	if r, ok := DefaultRegistry.(interface {
+		ListPage(string, string, int, ...registry.ListOption) ([]*registry.Service, string, error)
+	}); ok {
+		return r.ListPage(prefix, cursor, limit, opts...)
+	}
+
+	services, err := DefaultRegistry.ListServices(opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return util.Page(services, prefix, cursor, limit)
+}
+
 // Watch the registry for updates
 func Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
 	return DefaultRegistry.Watch(opts...)
@@ -0,0 +1,43 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+)
+
+// bulkRegistry counts the calls to GetServices
+type bulkRegistry struct {
+	registry.Registry
+	calls int
+}
+
+func (b *bulkRegistry) GetServices(names []string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	b.calls++
+	return []*registry.Service{{Name: names[0]}}, nil
+}
+
+func TestGetServices(t *testing.T) {
+	defer func(r registry.Registry) { DefaultRegistry = r }(DefaultRegistry)
+
+	// registries without bulk lookups are called for each service
+	DefaultRegistry = memory.NewRegistry()
+	DefaultRegistry.Register(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{{Id: "foo-1"}}})
+	DefaultRegistry.Register(&registry.Service{Name: "bar", Version: "latest", Nodes: []*registry.Node{{Id: "bar-1"}}})
+
+	services, err := GetServices([]string{"foo", "missing", "bar"})
+	if err != nil {
+		t.Fatalf("Unexpected error getting services: %v", err)
+	}
+	if len(services) != 2 || services[0].Name != "foo" || services[1].Name != "bar" {
+		t.Errorf("Expected foo and bar, got %v", services)
+	}
+
+	// bulk lookups are used when supported
+	b := &bulkRegistry{Registry: DefaultRegistry}
+	DefaultRegistry = b
+	if services, err := GetServices([]string{"foo", "bar"}); err != nil || len(services) != 1 || b.calls != 1 {
+		t.Errorf("Expected a single bulk lookup, got %v: %v", services, err)
+	}
+}
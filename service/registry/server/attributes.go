@@ -0,0 +1,180 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+var (
+	// DefaultAttributePruneInterval is the interval the attributes of the nodes which are no
+	// longer registered are removed at
+	DefaultAttributePruneInterval = time.Minute
+)
+
+// attributeIndex keeps the labels and locality of the registered nodes, since the registry
+// they're registered in only keeps their metadata. The attributes of deregistered nodes are
+// kept until they're pruned.
+type attributeIndex struct {
+	sync.RWMutex
+	// nodes are the attributes keyed by domain, service, version and node id
+	nodes map[string]*attributes
+}
+
+type attributes struct {
+	labels map[string]string
+	region string
+	zone   string
+	// missing is set once the node is found not to be registered, it's removed if it's still
+	// missing when next pruned
+	missing bool
+}
+
+func attributeKey(domain, service, version, node string) string {
+	return domain + "/" + service + "/" + version + "/" + node
+}
+
+// set the attributes of the nodes of the service, replacing any they were registered with
+func (a *attributeIndex) set(domain string, srv *pb.Service) {
+	a.Lock()
+	defer a.Unlock()
+
+	for _, n := range srv.Nodes {
+		key := attributeKey(domain, srv.Name, srv.Version, n.Id)
+		if len(n.Labels) == 0 && len(n.Region) == 0 && len(n.Zone) == 0 {
+			delete(a.nodes, key)
+			continue
+		}
+
+		labels := make(map[string]string, len(n.Labels))
+		for k, v := range n.Labels {
+			labels[k] = v
+		}
+		if a.nodes == nil {
+			a.nodes = make(map[string]*attributes)
+		}
+		a.nodes[key] = &attributes{labels: labels, region: n.Region, zone: n.Zone}
+	}
+}
+
+// apply the attributes of the node of the service to it
+func (a *attributeIndex) apply(domain, service, version string, n *pb.Node) {
+	a.RLock()
+	defer a.RUnlock()
+
+	attrs, ok := a.nodes[attributeKey(domain, service, version, n.Id)]
+	if !ok {
+		return
+	}
+	if len(attrs.labels) > 0 {
+		n.Labels = attrs.labels
+	}
+	n.Region = attrs.region
+	n.Zone = attrs.zone
+}
+
+// prune the attributes of the nodes which aren't registered, once they've been missing for
+// two prunes in a row
+func (a *attributeIndex) prune(registered map[string]bool) int {
+	a.Lock()
+	defer a.Unlock()
+
+	var pruned int
+	for key, attrs := range a.nodes {
+		if registered[key] {
+			attrs.missing = false
+		} else if attrs.missing {
+			delete(a.nodes, key)
+			pruned++
+		} else {
+			attrs.missing = true
+		}
+	}
+	return pruned
+}
+
+// serviceDomain returns the domain of the service. Services returned by wildcard queries have
+// their domain set in their metadata by the registry, otherwise they're in the domain queried.
+func serviceDomain(domain string, srv *goregistry.Service) string {
+	if domain != goregistry.WildcardDomain {
+		return domain
+	}
+	if d, ok := srv.Metadata["domain"]; ok && len(d) > 0 {
+		return d
+	}
+	return goregistry.DefaultDomain
+}
+
+// toProto serializes the services, with the attributes of their nodes
+func (r *Registry) toProto(domain string, services []*goregistry.Service) []*pb.Service {
+	result := make([]*pb.Service, len(services))
+	for i, srv := range services {
+		result[i] = util.ToProto(srv)
+		d := serviceDomain(domain, srv)
+		for _, n := range result[i].Nodes {
+			r.attributes.apply(d, srv.Name, srv.Version, n)
+		}
+	}
+	return result
+}
+
+// filterLabels returns the services with only the nodes which have all the labels. Services
+// without any such nodes are omitted.
+func (r *Registry) filterLabels(domain string, services []*goregistry.Service, labels map[string]string) []*goregistry.Service {
+	if len(labels) == 0 {
+		return services
+	}
+
+	filtered := make([]*goregistry.Service, 0, len(services))
+	for _, srv := range services {
+		d := serviceDomain(domain, srv)
+
+		var nodes []*goregistry.Node
+		for _, n := range srv.Nodes {
+			node := &pb.Node{Id: n.Id}
+			r.attributes.apply(d, srv.Name, srv.Version, node)
+			if util.HasLabels(node, labels) {
+				nodes = append(nodes, n)
+			}
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		s := *srv
+		s.Nodes = nodes
+		filtered = append(filtered, &s)
+	}
+	return filtered
+}
+
+// pruneAttributes removes the attributes of the nodes which are no longer registered at the
+// interval, it blocks so should be called in a goroutine
+func (r *Registry) pruneAttributes(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for range t.C {
+		services, err := registry.ListServices(goregistry.ListDomain(goregistry.WildcardDomain))
+		if err != nil {
+			log.Errorf("Error listing services to prune the attributes of their nodes: %v", err)
+			continue
+		}
+
+		registered := make(map[string]bool)
+		for _, srv := range services {
+			d := serviceDomain(goregistry.WildcardDomain, srv)
+			for _, n := range srv.Nodes {
+				registered[attributeKey(d, srv.Name, srv.Version, n.Id)] = true
+			}
+		}
+		if n := r.attributes.prune(registered); n > 0 {
+			log.Debugf("Pruned the attributes of %d nodes which are no longer registered", n)
+		}
+	}
+}
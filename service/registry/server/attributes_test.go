@@ -0,0 +1,68 @@
+package server
+
+import (
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func TestAttributeIndex(t *testing.T) {
+	r := &Registry{}
+	domain := goregistry.DefaultDomain
+
+	get := func(domain, node string) *pb.Node {
+		n := &pb.Node{Id: node}
+		r.attributes.apply(domain, "foo", "v1", n)
+		return n
+	}
+
+	r.attributes.set(domain, &pb.Service{Name: "foo", Version: "v1", Nodes: []*pb.Node{
+		{Id: "foo-1", Labels: map[string]string{"canary": "true"}, Region: "eu-west-1", Zone: "eu-west-1a"},
+		{Id: "foo-2"},
+	}})
+	if n := get(domain, "foo-1"); n.Labels["canary"] != "true" || n.Region != "eu-west-1" || n.Zone != "eu-west-1a" {
+		t.Fatalf("Expected the canary label and the locality, got %v", n)
+	}
+	if n := get("other", "foo-1"); n.Labels != nil || len(n.Region) > 0 {
+		t.Fatalf("Expected attributes to be kept per domain, got %v", n)
+	}
+
+	// the registry returns the nodes without their attributes
+	services := []*goregistry.Service{{Name: "foo", Version: "v1", Nodes: []*goregistry.Node{{Id: "foo-1"}, {Id: "foo-2"}}}}
+	s := r.toProto(domain, services)
+	if s[0].Nodes[0].Labels["canary"] != "true" || s[0].Nodes[0].Zone != "eu-west-1a" {
+		t.Fatalf("Expected the attributes to be added to the node, got %v", s[0].Nodes[0])
+	}
+	if s[0].Nodes[1].Labels != nil || len(s[0].Nodes[1].Region) > 0 {
+		t.Fatalf("Expected no attributes on the node, got %v", s[0].Nodes[1])
+	}
+	if s := r.filterLabels(domain, services, map[string]string{"canary": "true"}); len(s) != 1 || len(s[0].Nodes) != 1 || s[0].Nodes[0].Id != "foo-1" {
+		t.Fatalf("Expected only the canary node, got %v", s)
+	}
+	if s := r.filterLabels(domain, services, map[string]string{"canary": "false"}); len(s) != 0 {
+		t.Fatalf("Expected no services, got %v", s)
+	}
+
+	// registering again without attributes removes them
+	r.attributes.set(domain, &pb.Service{Name: "foo", Version: "v1", Nodes: []*pb.Node{{Id: "foo-1"}}})
+	if n := get(domain, "foo-1"); n.Labels != nil || len(n.Region) > 0 {
+		t.Fatalf("Expected the attributes to be removed, got %v", n)
+	}
+
+	// the attributes of nodes which aren't registered are pruned once they've been missing twice
+	r.attributes.set(domain, &pb.Service{Name: "foo", Version: "v1", Nodes: []*pb.Node{
+		{Id: "foo-1", Labels: map[string]string{"canary": "true"}},
+		{Id: "foo-3", Region: "eu-west-1"},
+	}})
+	registered := map[string]bool{attributeKey(domain, "foo", "v1", "foo-1"): true}
+	if n := r.attributes.prune(registered); n != 0 {
+		t.Fatalf("Expected nothing to be pruned the first time, got %v", n)
+	}
+	if n := r.attributes.prune(registered); n != 1 {
+		t.Fatalf("Expected 1 node to be pruned, got %v", n)
+	}
+	if n := get(domain, "foo-1"); n.Labels["canary"] != "true" {
+		t.Fatalf("Expected the attributes of the registered node to be kept, got %v", n)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultAuditRetention is how long entries are kept in the audit trail
+	DefaultAuditRetention = time.Hour * 24 * 30
+)
+
+const auditPrefix = "audit/"
+
+// auditKey returns the key of an entry, timestamps are zero padded so the keys sort in order
+func auditKey(domain string, timestamp int64) string {
+	return fmt.Sprintf("%s%s/%020d", auditPrefix, domain, timestamp)
+}
+
+// audit records the change to the service and the account in the context which made it
+func (r *Registry) audit(ctx context.Context, domain, action string, srv *pb.Service) {
+	// entries are keyed by the unix nano time they're recorded at
+	ts := time.Now().UnixNano()
+
+	entry := &pb.AuditEntry{
+		Action:    action,
+		Service:   srv.Name,
+		Version:   srv.Version,
+		Timestamp: ts / int64(time.Second),
+	}
+	for _, n := range srv.Nodes {
+		entry.Nodes = append(entry.Nodes, n.Id)
+	}
+	if acc, ok := auth.AccountFromContext(ctx); ok {
+		entry.Account = acc.ID
+		entry.Issuer = acc.Issuer
+	}
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("Error marshaling audit entry for %v: %v", srv.Name, err)
+		return
+	}
+
+	rec := &gostore.Record{
+		Key:    auditKey(domain, ts),
+		Value:  bytes,
+		Expiry: DefaultAuditRetention,
+	}
+	if err := store.Write(rec); err != nil {
+		log.Errorf("Error writing audit entry for %v: %v", srv.Name, err)
+	}
+}
+
+// readAudit returns the entries in the domain, most recent first. If service is not blank,
+// only entries for the service are returned.
+func readAudit(domain, service string, since, until, limit int64) ([]*pb.AuditEntry, error) {
+	prefix := auditPrefix + domain + "/"
+	keys, err := store.List(gostore.ListPrefix(prefix))
+	if err != nil && err != gostore.ErrNotFound {
+		return nil, err
+	}
+
+	// the keys are zero padded but stores don't guarantee the order they're listed in
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	var entries []*pb.AuditEntry
+	for _, k := range keys {
+		if limit > 0 && int64(len(entries)) >= limit {
+			break
+		}
+
+		// entries outside the range are skipped without reading them
+		if ts, err := strconv.ParseInt(strings.TrimPrefix(k, prefix), 10, 64); err == nil {
+			secs := ts / int64(time.Second)
+			if (since > 0 && secs < since) || (until > 0 && secs > until) {
+				continue
+			}
+		}
+
+		recs, err := store.Read(k)
+		if err == gostore.ErrNotFound {
+			// the entry expired since the keys were listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var entry *pb.AuditEntry
+		if err := json.Unmarshal(recs[0].Value, &entry); err != nil {
+			return nil, err
+		}
+		if len(service) > 0 && entry.Service != service {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Audit returns the registrations and deregistrations made in the domain and who made them
+func (r *Registry) Audit(ctx context.Context, req *pb.AuditRequest, rsp *pb.AuditResponse) error {
+	// parse the options
+	var domain string
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, domain); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.Audit", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.Audit", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.Audit", err.Error())
+	}
+
+	entries, err := readAudit(domain, req.Service, req.Since, req.Until, req.Limit)
+	if err != nil {
+		return errors.InternalServerError("registry.Registry.Audit", "Error reading audit trail: %v", err)
+	}
+	rsp.Entries = entries
+	return nil
+}
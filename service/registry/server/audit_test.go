@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestAudit(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	r := &Registry{}
+	domain := goregistry.DefaultDomain
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Issuer: domain})
+
+	srv := func(name string) *pb.Service {
+		return &pb.Service{Name: name, Version: "v2", Nodes: []*pb.Node{{Id: name + "-1"}}}
+	}
+	r.audit(ctx, domain, "create", srv("payments"))
+	r.audit(ctx, domain, "create", srv("users"))
+	r.audit(context.Background(), domain, "delete", srv("payments"))
+	r.audit(ctx, "other", "delete", srv("payments"))
+
+	entries, err := readAudit(domain, "", 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the audit trail: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %v", len(entries))
+	}
+	if e := entries[0]; e.Action != "delete" || e.Service != "payments" || e.Account != "" {
+		t.Errorf("Expected the most recent entry to be the deregistration by the registry, got %v", e)
+	}
+	if e := entries[2]; e.Action != "create" || e.Account != "alice" || e.Version != "v2" || e.Nodes[0] != "payments-1" {
+		t.Errorf("Expected the first entry to be the registration by alice, got %v", e)
+	}
+
+	if entries, _ := readAudit(domain, "payments", 0, 0, 1); len(entries) != 1 || entries[0].Action != "delete" {
+		t.Errorf("Expected the latest change to payments, got %v", entries)
+	}
+	if entries, _ := readAudit(domain, "", time.Now().Add(time.Hour).Unix(), 0, 0); len(entries) != 0 {
+		t.Errorf("Expected no entries after the since time, got %v", entries)
+	}
+}
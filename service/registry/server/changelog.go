@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	log "github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultChangelogRetention is how long changes are kept in the changelog for replay
+	DefaultChangelogRetention = time.Hour * 24
+)
+
+const (
+	changelogPrefix = "changelog/"
+	// sequencePrefix prefixes the counter of the sequences of the changes to each domain
+	sequencePrefix = "sequence/"
+)
+
+// changelogKey returns the key of a change, sequences are zero padded so the keys sort in order
+func changelogKey(domain string, sequence int64) string {
+	return fmt.Sprintf("%s%s/%020d", changelogPrefix, domain, sequence)
+}
+
+// nextSequence returns the sequence of the next change to the domain. Sequences are counted in
+// the store so they increase across replicas and restarts of the registry.
+func nextSequence(domain string) (int64, error) {
+	return store.Increment(sequencePrefix+domain, 1)
+}
+
+// currentSequence returns the sequence of the last change to the domain, zero if there's none
+func currentSequence(domain string) (int64, error) {
+	recs, err := store.Read(sequencePrefix + domain)
+	if err == gostore.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(recs[0].Value), 10, 64)
+}
+
+// appendChangelog persists the change so watchers can replay it
+func appendChangelog(domain string, res *pb.Result) {
+	bytes, err := json.Marshal(res)
+	if err != nil {
+		log.Errorf("Error marshaling change for %v: %v", res.Service.Name, err)
+		return
+	}
+
+	rec := &gostore.Record{
+		Key:    changelogKey(domain, res.Sequence),
+		Value:  bytes,
+		Expiry: DefaultChangelogRetention,
+	}
+	if err := store.Write(rec); err != nil {
+		log.Errorf("Error writing change for %v to the changelog: %v", res.Service.Name, err)
+	}
+}
+
+// readChangelog returns the changes in the domain with a sequence greater than since, in
+// order. If service is not blank, only changes to the service are returned.
+func readChangelog(domain, service string, since int64) ([]*pb.Result, error) {
+	prefix := changelogPrefix + domain + "/"
+	recs, err := store.Read(prefix, gostore.ReadPrefix())
+	if err != nil && err != gostore.ErrNotFound {
+		return nil, err
+	}
+
+	var results []*pb.Result
+	for _, r := range recs {
+		seq, err := strconv.ParseInt(strings.TrimPrefix(r.Key, prefix), 10, 64)
+		if err != nil || seq <= since {
+			continue
+		}
+
+		var res *pb.Result
+		if err := json.Unmarshal(r.Value, &res); err != nil {
+			return nil, err
+		}
+		if len(service) > 0 && (res.Service == nil || res.Service.Name != service) {
+			continue
+		}
+		results = append(results, res)
+	}
+
+	// the keys are zero padded but stores don't guarantee the order they're read in
+	sort.Slice(results, func(i, j int) bool { return results[i].Sequence < results[j].Sequence })
+	return results, nil
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestChangelog(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	domain := goregistry.DefaultDomain
+
+	foo := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1"}}}
+	bar := &pb.Service{Name: "bar", Version: "latest", Nodes: []*pb.Node{{Id: "bar-1"}}}
+
+	// sequences are kept in the store, so they continue across replicas and restarts
+	new(Registry).recordChange(domain, "create", foo)
+	new(Registry).recordChange(domain, "create", bar)
+	new(Registry).recordChange(domain, "delete", foo)
+	new(Registry).recordChange("other", "create", foo)
+
+	if seq, err := currentSequence(domain); err != nil || seq != 3 {
+		t.Fatalf("Expected the sequence to be 3, got %v: %v", seq, err)
+	}
+	if seq, err := currentSequence("other"); err != nil || seq != 1 {
+		t.Fatalf("Expected the sequence of the other domain to be 1, got %v: %v", seq, err)
+	}
+
+	changes, err := readChangelog(domain, "", 0)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %v: %v", changes, err)
+	}
+	for i, c := range changes {
+		if c.Sequence != int64(i+1) {
+			t.Errorf("Expected change %v to have sequence %v, got %v", i, i+1, c.Sequence)
+		}
+	}
+
+	// watchers replay the changes since the last sequence they received
+	changes, err = readChangelog(domain, "foo", 1)
+	if err != nil || len(changes) != 1 || changes[0].Action != "delete" {
+		t.Fatalf("Expected the deletion of foo, got %v: %v", changes, err)
+	}
+}
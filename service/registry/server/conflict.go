@@ -0,0 +1,120 @@
+package server
+
+import (
+	"fmt"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// ConflictPolicy resolves the registration of a node with the same id as an existing node
+// registered by another process. It returns the node to register, or an error to reject the
+// registration.
+type ConflictPolicy func(existing, incoming *pb.Node) (*pb.Node, error)
+
+var (
+	// DefaultConflictPolicy is used for nodes which don't set a policy in their metadata
+	DefaultConflictPolicy = "lww"
+	// ConflictPolicies which nodes can set in their metadata, keyed by name
+	ConflictPolicies = map[string]ConflictPolicy{
+		"lww":    LastWriteWins,
+		"reject": RejectConflicts,
+		"merge":  MergeConflicts,
+		"clock":  VectorClock,
+	}
+)
+
+// LastWriteWins replaces the existing node with the incoming node
+func LastWriteWins(existing, incoming *pb.Node) (*pb.Node, error) {
+	return incoming, nil
+}
+
+// RejectConflicts rejects the incoming node until the existing node is deregistered or expires
+func RejectConflicts(existing, incoming *pb.Node) (*pb.Node, error) {
+	return nil, fmt.Errorf("node %v is already registered by %v", existing.Id, util.Registrant(existing))
+}
+
+// MergeConflicts registers the incoming node with the metadata of the existing node it doesn't set
+func MergeConflicts(existing, incoming *pb.Node) (*pb.Node, error) {
+	md := make(map[string]string, len(existing.Metadata)+len(incoming.Metadata))
+	for k, v := range existing.Metadata {
+		md[k] = v
+	}
+	for k, v := range incoming.Metadata {
+		md[k] = v
+	}
+
+	return &pb.Node{
+		Id:       incoming.Id,
+		Address:  incoming.Address,
+		Port:     incoming.Port,
+		Metadata: md,
+	}, nil
+}
+
+// VectorClock compares the clocks of the nodes, rejecting the incoming node if it's stale.
+// Concurrent registrations are resolved in favour of the greater registrant, so every
+// registry resolves them the same way.
+func VectorClock(existing, incoming *pb.Node) (*pb.Node, error) {
+	ec := util.ParseClock(existing.Metadata[util.ClockKey])
+	ic := util.ParseClock(incoming.Metadata[util.ClockKey])
+
+	switch ic.Compare(ec) {
+	case util.Before:
+		return nil, fmt.Errorf("registration of node %v at %v is older than the registration at %v", incoming.Id, ic, ec)
+	case util.Concurrent:
+		if util.Registrant(incoming) < util.Registrant(existing) {
+			return nil, fmt.Errorf("registration of node %v is concurrent with the registration by %v", incoming.Id, util.Registrant(existing))
+		}
+	}
+
+	return incoming, nil
+}
+
+// resolveConflicts applies the conflict policies to the nodes of the service which have the
+// same id as nodes registered by another process. The policy of the existing node is used
+// so the process which registered it first decides how it's replaced.
+func resolveConflicts(domain string, srv *pb.Service) error {
+	existing, err := registry.GetService(srv.Name, goregistry.GetDomain(domain))
+	if err != nil {
+		return nil
+	}
+
+	nodes := make(map[string]*pb.Node)
+	for _, s := range existing {
+		for _, n := range util.ToProto(s).Nodes {
+			nodes[n.Id] = n
+		}
+	}
+
+	for i, n := range srv.Nodes {
+		e, ok := nodes[n.Id]
+		if !ok || util.Registrant(e) == util.Registrant(n) {
+			continue
+		}
+
+		name := e.Metadata[util.ConflictPolicyKey]
+		if len(name) == 0 {
+			name = n.Metadata[util.ConflictPolicyKey]
+		}
+		if len(name) == 0 {
+			name = DefaultConflictPolicy
+		}
+		policy, ok := ConflictPolicies[name]
+		if !ok {
+			log.Warnf("Unknown conflict policy %v for node %v, using %v", name, n.Id, DefaultConflictPolicy)
+			policy = ConflictPolicies[DefaultConflictPolicy]
+		}
+
+		resolved, err := policy(e, n)
+		if err != nil {
+			return err
+		}
+		srv.Nodes[i] = resolved
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+func TestResolveConflicts(t *testing.T) {
+	registry.DefaultRegistry = memory.NewRegistry()
+	registry.Register(&goregistry.Service{Name: "foo", Version: "latest", Nodes: []*goregistry.Node{
+		{Id: "foo-1", Address: "10.0.0.1:8080", Metadata: map[string]string{"zone": "a", util.ClockKey: "a=2"}},
+	}})
+
+	register := func(policy string, md map[string]string) (*pb.Node, error) {
+		node := &pb.Node{Id: "foo-1", Address: "10.0.0.2:8080", Metadata: map[string]string{util.ConflictPolicyKey: policy}}
+		for k, v := range md {
+			node.Metadata[k] = v
+		}
+		srv := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{node}}
+		err := resolveConflicts(goregistry.DefaultDomain, srv)
+		return srv.Nodes[0], err
+	}
+
+	// the same registrant can always update its node
+	srv := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{
+		{Id: "foo-1", Address: "10.0.0.1:8080", Metadata: map[string]string{util.ConflictPolicyKey: "reject"}},
+	}}
+	if err := resolveConflicts(goregistry.DefaultDomain, srv); err != nil {
+		t.Errorf("Expected the registrant to update its node, got %v", err)
+	}
+
+	if n, err := register("lww", nil); err != nil || n.Address != "10.0.0.2:8080" {
+		t.Errorf("Expected the last write to win, got %v: %v", n, err)
+	}
+	if _, err := register("reject", nil); err == nil {
+		t.Errorf("Expected the conflicting registration to be rejected")
+	}
+	if n, err := register("merge", map[string]string{"region": "eu"}); err != nil || n.Metadata["zone"] != "a" || n.Metadata["region"] != "eu" {
+		t.Errorf("Expected the metadata to be merged, got %v: %v", n, err)
+	}
+	if _, err := register("clock", map[string]string{util.ClockKey: "a=1"}); err == nil {
+		t.Errorf("Expected the stale registration to be rejected")
+	}
+	if _, err := register("clock", map[string]string{util.ClockKey: "a=2,b=1"}); err != nil {
+		t.Errorf("Expected the newer registration to be accepted, got %v", err)
+	}
+}
+
+func TestVectorClockConflict(t *testing.T) {
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	// register the node as the registrant would, with the clock stamped by its client
+	register := func(registrant, clock string) error {
+		srv := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{
+			Id: "foo-1", Address: "10.0.0.1:8080", Metadata: map[string]string{
+				util.ConflictPolicyKey: "clock", util.RegistrantKey: registrant, util.ClockKey: clock,
+			},
+		}}}
+		if err := resolveConflicts(goregistry.DefaultDomain, srv); err != nil {
+			return err
+		}
+
+		// the memory registry doesn't update the metadata of nodes which are registered
+		registry.Deregister(util.ToService(srv))
+		return registry.Register(util.ToService(srv))
+	}
+
+	if err := register("b", "b=1"); err != nil {
+		t.Fatalf("Unexpected error registering: %v", err)
+	}
+
+	// a process which saw the registration replaces it
+	if err := register("a", "a=1,b=1"); err != nil {
+		t.Fatalf("Expected the later registration to replace the node, got %v", err)
+	}
+
+	// the stale process can't take the node back, even though it renews its registration
+	if err := register("b", "b=1"); err == nil {
+		t.Errorf("Expected the stale registration to be rejected")
+	}
+	if err := register("a", "a=1,b=1"); err != nil {
+		t.Errorf("Expected the registrant to renew its registration, got %v", err)
+	}
+
+	// concurrent registrations are resolved in favour of the greater registrant
+	if err := register("b", "b=2"); err != nil {
+		t.Errorf("Expected the concurrent registration of the greater registrant to win, got %v", err)
+	}
+	if err := register("a", "a=2"); err == nil {
+		t.Errorf("Expected the concurrent registration of the lesser registrant to be rejected")
+	}
+}
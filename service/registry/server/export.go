@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"net"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// Export the services in the domain
+func (r *Registry) Export(ctx context.Context, req *pb.ExportRequest, rsp *pb.ExportResponse) error {
+	// parse the options
+	var domain string
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request, only the server can export the wildcard domain
+	if err := namespace.Authorize(ctx, domain); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.Export", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.Export", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.Export", err.Error())
+	}
+
+	services, err := registry.ListServices(goregistry.ListDomain(domain))
+	if err != nil {
+		return errors.InternalServerError("registry.Registry.Export", err.Error())
+	}
+
+	rsp.Services = make([]*pb.Service, 0, len(services))
+	for _, srv := range services {
+		pbSrv := r.toProto(domain, []*goregistry.Service{srv})[0]
+		pbSrv.Options.Domain = serviceDomain(domain, srv)
+
+		rsp.Services = append(rsp.Services, pbSrv)
+	}
+
+	return nil
+}
+
+// Import the services, remapping the addresses of their nodes
+func (r *Registry) Import(ctx context.Context, req *pb.ImportRequest, rsp *pb.ImportResponse) error {
+	// parse the options
+	var domain string
+	var ttl time.Duration
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+	if req.Options != nil && req.Options.Ttl > 0 {
+		ttl = time.Duration(req.Options.Ttl) * time.Second
+	}
+
+	// authorize each of the domains being imported into before registering anything
+	domains := make([]string, len(req.Services))
+	for i, srv := range req.Services {
+		domains[i] = domain
+		if srv.Options != nil && len(srv.Options.Domain) > 0 {
+			domains[i] = srv.Options.Domain
+		}
+
+		if err := namespace.Authorize(ctx, domains[i]); err == namespace.ErrForbidden {
+			return errors.Forbidden("registry.Registry.Import", err.Error())
+		} else if err == namespace.ErrUnauthorized {
+			return errors.Unauthorized("registry.Registry.Import", err.Error())
+		} else if err != nil {
+			return errors.InternalServerError("registry.Registry.Import", err.Error())
+		}
+	}
+
+	for i, srv := range req.Services {
+		for _, n := range srv.Nodes {
+			n.Address = remapAddress(n.Address, req.Addresses)
+		}
+
+		opts := []goregistry.RegisterOption{goregistry.RegisterDomain(domains[i])}
+		if ttl > 0 {
+			opts = append(opts, goregistry.RegisterTTL(ttl))
+		}
+
+		// the domain is set by the registry on export, don't import it as metadata
+		service := util.ToService(srv)
+		delete(service.Metadata, "domain")
+
+		if err := registry.Register(service, opts...); err != nil {
+			return errors.InternalServerError("registry.Registry.Import", "Error importing %v: %v", srv.Name, err)
+		}
+		r.attributes.set(domains[i], srv)
+		rsp.Imported++
+
+		srv.Options = &pb.Options{Domain: domains[i]}
+		r.audit(ctx, domains[i], "create", srv)
+		r.clearTombstone(domains[i], srv)
+		go r.publishEvent("create", srv)
+	}
+
+	return nil
+}
+
+// remapAddress returns the new address for the old one. The addresses can be keyed by the full
+// address or by host, in which case the port of the old address is retained.
+func remapAddress(addr string, addresses map[string]string) string {
+	if len(addresses) == 0 {
+		return addr
+	}
+	if a, ok := addresses[addr]; ok {
+		return a
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if h, ok := addresses[host]; ok {
+		return net.JoinHostPort(h, port)
+	}
+	return addr
+}
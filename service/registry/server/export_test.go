@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestRemapAddress(t *testing.T) {
+	addresses := map[string]string{
+		"10.0.0.1":      "10.1.0.1",
+		"10.0.0.2:8080": "10.1.0.2:9090",
+	}
+	tt := map[string]string{
+		"10.0.0.1:8080": "10.1.0.1:8080",
+		"10.0.0.2:8080": "10.1.0.2:9090",
+		"10.0.0.2:8081": "10.0.0.2:8081",
+		"10.0.0.3:8080": "10.0.0.3:8080",
+		"foo":           "foo",
+	}
+	for addr, expected := range tt {
+		if a := remapAddress(addr, addresses); a != expected {
+			t.Errorf("Expected %v to be remapped to %v, got %v", addr, expected, a)
+		}
+	}
+	if a := remapAddress("10.0.0.1:8080", nil); a != "10.0.0.1:8080" {
+		t.Errorf("Expected the address to be unchanged without remaps, got %v", a)
+	}
+}
+
+func TestServiceDomain(t *testing.T) {
+	srv := &goregistry.Service{Name: "foo", Metadata: map[string]string{"domain": "other"}}
+	if d := serviceDomain("foo", srv); d != "foo" {
+		t.Errorf("Expected the domain queried, got %v", d)
+	}
+	if d := serviceDomain(goregistry.WildcardDomain, srv); d != "other" {
+		t.Errorf("Expected the domain of wildcard queries to be read from the metadata, got %v", d)
+	}
+	if d := serviceDomain(goregistry.WildcardDomain, &goregistry.Service{Name: "foo"}); d != goregistry.DefaultDomain {
+		t.Errorf("Expected the default domain without metadata, got %v", d)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	// the server's account can access every domain
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "registry", Issuer: "micro"})
+
+	src := new(Registry)
+	foo := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{
+		{Id: "foo-1", Address: "10.0.0.1:8080", Labels: map[string]string{"canary": "true"}},
+	}}
+	bar := &pb.Service{Name: "bar", Version: "latest", Nodes: []*pb.Node{{Id: "bar-1", Address: "10.0.0.2:8080"}}, Options: &pb.Options{Domain: "other"}}
+	for _, srv := range []*pb.Service{foo, bar} {
+		if err := src.Register(ctx, srv, &pb.EmptyResponse{}); err != nil {
+			t.Fatalf("Unexpected error registering %v: %v", srv.Name, err)
+		}
+	}
+
+	// the services are exported with their domain and the attributes of their nodes. The memory
+	// registry doesn't set the domain of the services returned by wildcard queries, so each
+	// domain is exported in turn.
+	export := &pb.ExportResponse{}
+	for _, domain := range []string{goregistry.DefaultDomain, "other"} {
+		rsp := &pb.ExportResponse{}
+		if err := src.Export(ctx, &pb.ExportRequest{Options: &pb.Options{Domain: domain}}, rsp); err != nil {
+			t.Fatalf("Unexpected error exporting %v: %v", domain, err)
+		}
+		export.Services = append(export.Services, rsp.Services...)
+	}
+	exported := map[string]*pb.Service{}
+	for _, srv := range export.Services {
+		exported[srv.Name] = srv
+	}
+	if len(exported) != 2 || exported["foo"].Options.Domain != goregistry.DefaultDomain || exported["bar"].Options.Domain != "other" {
+		t.Fatalf("Expected foo and bar to be exported with their domains, got %v", export.Services)
+	}
+	if l := exported["foo"].Nodes[0].Labels; l["canary"] != "true" {
+		t.Errorf("Expected the labels of the node to be exported, got %v", l)
+	}
+
+	// other namespaces can't be imported into, and nothing is imported if any are forbidden
+	registry.DefaultRegistry = memory.NewRegistry()
+	dst := new(Registry)
+	forbidden := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Issuer: "foo"})
+	err := dst.Import(forbidden, &pb.ImportRequest{Services: export.Services}, &pb.ImportResponse{})
+	if verr := errors.Parse(err); verr == nil || verr.Code != 403 {
+		t.Fatalf("Expected a forbidden error, got %v", err)
+	}
+	if services, _ := registry.ListServices(goregistry.ListDomain(goregistry.WildcardDomain)); len(services) > 0 {
+		t.Fatalf("Expected nothing to be imported, got %v", services)
+	}
+
+	// the services are imported into their domains with their addresses remapped
+	rsp := &pb.ImportResponse{}
+	req2 := &pb.ImportRequest{Services: export.Services, Addresses: map[string]string{"10.0.0.1": "10.1.0.1"}}
+	if err := dst.Import(ctx, req2, rsp); err != nil {
+		t.Fatalf("Unexpected error importing: %v", err)
+	}
+	if rsp.Imported != 2 {
+		t.Errorf("Expected 2 services to be imported, got %v", rsp.Imported)
+	}
+
+	services, err := registry.GetService("foo")
+	if err != nil || len(services) != 1 || services[0].Nodes[0].Address != "10.1.0.1:8080" {
+		t.Fatalf("Expected foo to be imported at the remapped address, got %v: %v", services, err)
+	}
+	services, err = registry.GetService("bar", goregistry.GetDomain("other"))
+	if err != nil || len(services) != 1 || services[0].Nodes[0].Address != "10.0.0.2:8080" {
+		t.Fatalf("Expected bar to be imported into its domain, got %v: %v", services, err)
+	}
+
+	get := &pb.GetResponse{}
+	if err := dst.GetService(ctx, &pb.GetRequest{Service: "foo"}, get); err != nil || len(get.Services) != 1 {
+		t.Fatalf("Expected to get foo, got %v: %v", get.Services, err)
+	}
+	if l := get.Services[0].Nodes[0].Labels; l["canary"] != "true" {
+		t.Errorf("Expected the labels of the node to be imported, got %v", l)
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"sync"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+var (
+	// DefaultSubscriberBuffer is the number of results buffered for each watcher before
+	// it's considered too slow and disconnected
+	DefaultSubscriberBuffer = 256
+	// ErrSlowWatcher is returned to watchers which fall too far behind
+	ErrSlowWatcher = errors.New("watcher too slow")
+)
+
+// fanout broadcasts the changes recorded by the registry to the watchers of the changed
+// services, with the sequences the changes were recorded at. The zero value is ready to use.
+type fanout struct {
+	sync.Mutex
+	// subs are the watchers by domain and service, a blank service watches all services in
+	// the domain
+	subs map[string]map[*watchSub]bool
+}
+
+// watchSub receives the changes broadcast to a watcher
+type watchSub struct {
+	results chan *pb.Result
+	// err is set before results is closed
+	err error
+}
+
+// subscribe to the changes to the service in the domain, a blank service watches all
+// services in the domain
+func (f *fanout) subscribe(domain, service string) *watchSub {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.subs == nil {
+		f.subs = make(map[string]map[*watchSub]bool)
+	}
+
+	key := domain + "/" + service
+	if f.subs[key] == nil {
+		f.subs[key] = make(map[*watchSub]bool)
+	}
+
+	sub := &watchSub{results: make(chan *pb.Result, DefaultSubscriberBuffer)}
+	f.subs[key][sub] = true
+	return sub
+}
+
+// unsubscribe from the changes
+func (f *fanout) unsubscribe(domain, service string, sub *watchSub) {
+	f.Lock()
+	defer f.Unlock()
+
+	key := domain + "/" + service
+	if !f.subs[key][sub] {
+		return
+	}
+	delete(f.subs[key], sub)
+	close(sub.results)
+
+	if len(f.subs[key]) == 0 {
+		delete(f.subs, key)
+	}
+}
+
+// broadcast the change to the watchers of the service changed and of the whole domain. The
+// result is shared by the watchers so mustn't be modified.
+func (f *fanout) broadcast(domain string, res *pb.Result) {
+	if res.Service == nil {
+		return
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	for _, key := range []string{domain + "/", domain + "/" + res.Service.Name} {
+		for sub := range f.subs[key] {
+			select {
+			case sub.results <- res:
+			default:
+				// the watcher can't keep up, disconnect it so it can watch again
+				sub.err = ErrSlowWatcher
+				close(sub.results)
+				delete(f.subs[key], sub)
+			}
+		}
+		if len(f.subs[key]) == 0 {
+			delete(f.subs, key)
+		}
+	}
+}
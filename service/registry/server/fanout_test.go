@@ -0,0 +1,59 @@
+package server
+
+import (
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func TestFanout(t *testing.T) {
+	var f fanout
+	subA := f.subscribe(goregistry.DefaultDomain, "foo")
+	subB := f.subscribe(goregistry.DefaultDomain, "")
+	subC := f.subscribe(goregistry.DefaultDomain, "bar")
+
+	f.broadcast(goregistry.DefaultDomain, &pb.Result{Action: "create", Service: &pb.Service{Name: "foo"}, Sequence: 7})
+	f.broadcast("other", &pb.Result{Action: "create", Service: &pb.Service{Name: "foo"}, Sequence: 1})
+
+	for _, sub := range []*watchSub{subA, subB} {
+		select {
+		case res := <-sub.results:
+			if res.Service.Name != "foo" || res.Sequence != 7 {
+				t.Fatalf("Expected the change to foo at sequence 7, got %v at %v", res.Service.Name, res.Sequence)
+			}
+		default:
+			t.Fatalf("Expected the change to be broadcast to the watchers of the service and domain")
+		}
+		if len(sub.results) != 0 {
+			t.Fatalf("Expected only the changes to the domain to be broadcast")
+		}
+	}
+	if len(subC.results) != 0 {
+		t.Fatalf("Expected the change not to be broadcast to the watchers of other services")
+	}
+
+	f.unsubscribe(goregistry.DefaultDomain, "foo", subA)
+	f.unsubscribe(goregistry.DefaultDomain, "", subB)
+	f.unsubscribe(goregistry.DefaultDomain, "bar", subC)
+	if len(f.subs) != 0 {
+		t.Fatalf("Expected the watchers to be removed")
+	}
+}
+
+func TestFanoutSlowWatcher(t *testing.T) {
+	defer func(n int) { DefaultSubscriberBuffer = n }(DefaultSubscriberBuffer)
+	DefaultSubscriberBuffer = 1
+
+	var f fanout
+	sub := f.subscribe(goregistry.DefaultDomain, "foo")
+	for i := 0; i < 2; i++ {
+		f.broadcast(goregistry.DefaultDomain, &pb.Result{Service: &pb.Service{Name: "foo"}})
+	}
+
+	<-sub.results
+	if _, ok := <-sub.results; ok || sub.err != ErrSlowWatcher {
+		t.Fatalf("Expected the watcher to be disconnected, got %v", sub.err)
+	}
+	f.unsubscribe(goregistry.DefaultDomain, "foo", sub)
+}
@@ -19,6 +19,18 @@ type Registry struct {
 	ID string
 	// the event
 	Event *service.Event
+
+	// watchers are sent the changes recorded
+	watchers fanout
+	// attributes of the registered nodes which the registry doesn't keep
+	attributes attributeIndex
+
+	// Quota is the quota of each namespace without one in Quotas
+	Quota Quota
+	// Quotas are the quotas of specific namespaces
+	Quotas map[string]Quota
+	// usage of the quotas of the namespaces
+	usage quotaUsage
 }
 
 func ActionToEventType(action string) goregistry.EventType {
@@ -33,6 +45,23 @@ func ActionToEventType(action string) goregistry.EventType {
 }
 
 func (r *Registry) publishEvent(action string, service *pb.Service) error {
+	// persist the change so watchers can replay it
+	domain := goregistry.DefaultDomain
+	if service.Options != nil && len(service.Options.Domain) > 0 {
+		domain = service.Options.Domain
+	}
+	seq := r.recordChange(domain, action, service)
+
+	return r.publish(domain, action, service, seq)
+}
+
+// publish the event without recording the change, e.g. when it's already been recorded with
+// the sequence. The sequence is zero if the event didn't change the registry.
+func (r *Registry) publish(domain, action string, service *pb.Service, sequence int64) error {
+	if r.Event == nil {
+		return nil
+	}
+
 	// TODO: timestamp should be read from received event
 	// Right now goregistry.Result does not contain timestamp
 	event := &pb.Event{
@@ -40,6 +69,8 @@ func (r *Registry) publishEvent(action string, service *pb.Service) error {
 		Type:      pb.EventType(ActionToEventType(action)),
 		Timestamp: time.Now().UnixNano(),
 		Service:   service,
+		Sequence:  sequence,
+		Domain:    domain,
 	}
 
 	log.Debugf("publishing event %s for action %s", event.Id, action)
@@ -50,6 +81,26 @@ func (r *Registry) publishEvent(action string, service *pb.Service) error {
 	return r.Event.Publish(ctx, event)
 }
 
+// processEvent sends the changes recorded by the other replicas of the registry to the watchers
+// of this one, with the sequences they were recorded at
+func (r *Registry) processEvent(ctx context.Context, event *pb.Event) error {
+	if event.Id == r.ID || event.Sequence == 0 || event.Service == nil {
+		return nil
+	}
+
+	domain := event.Domain
+	if len(domain) == 0 {
+		domain = goregistry.DefaultDomain
+	}
+	r.watchers.broadcast(domain, &pb.Result{
+		Action:    goregistry.EventType(event.Type).String(),
+		Service:   event.Service,
+		Timestamp: time.Unix(0, event.Timestamp).Unix(),
+		Sequence:  event.Sequence,
+	})
+	return nil
+}
+
 // GetService from the registry with the name requested
 func (r *Registry) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.GetResponse) error {
 	// parse the options
@@ -72,18 +123,104 @@ func (r *Registry) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.G
 
 	// get the services in the namespace
 	services, err := registry.GetService(req.Service, goregistry.GetDomain(options.Domain))
-	if err == goregistry.ErrNotFound || len(services) == 0 {
-		return errors.NotFound("registry.Registry.GetService", goregistry.ErrNotFound.Error())
-	} else if err != nil {
+	if err != nil && err != goregistry.ErrNotFound {
 		return errors.InternalServerError("registry.Registry.GetService", err.Error())
 	}
 
-	// serialize the response
-	rsp.Services = make([]*pb.Service, len(services))
-	for i, srv := range services {
-		rsp.Services[i] = util.ToProto(srv)
+	// ensure the read is consistent with the revision requested, the service may have been
+	// deregistered since
+	if err := checkRevision("registry.Registry.GetService", req.Options, options.Domain, req.Service); err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return notFound("registry.Registry.GetService", options.Domain, req.Service)
+	}
+
+	// only return the nodes with the labels requested
+	if labels := req.Options.GetLabels(); len(labels) > 0 {
+		services = r.filterLabels(options.Domain, services, labels)
+		if len(services) == 0 {
+			return errors.NotFound("registry.Registry.GetService", "no nodes of %v have the labels %v", req.Service, labels)
+		}
+	}
+
+	// serialize the response, preferring the nodes in the locality of the caller
+	rsp.Services = r.toProto(options.Domain, services)
+	if len(req.Region) > 0 || len(req.Zone) > 0 {
+		rsp.Services = util.PreferLocality(rsp.Services, req.Region, req.Zone)
+	}
+
+	return nil
+}
+
+// GetServices from the registry with the names requested. Services which
+// cannot be found are omitted from the response rather than erroring.
+func (r *Registry) GetServices(ctx context.Context, req *pb.GetServicesRequest, rsp *pb.GetServicesResponse) error {
+	// parse the options
+	var options goregistry.GetOptions
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		options.Domain = req.Options.Domain
+	} else {
+		options.Domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request
+	publicNS := namespace.Public(goregistry.DefaultDomain)
+	if err := namespace.Authorize(ctx, options.Domain, publicNS); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.GetServices", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.GetServices", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.GetServices", err.Error())
+	}
+
+	// get each of the services in the namespace
+	for _, name := range req.Services {
+		services, err := registry.GetService(name, goregistry.GetDomain(options.Domain))
+		if err == goregistry.ErrNotFound {
+			continue
+		} else if err != nil {
+			return errors.InternalServerError("registry.Registry.GetServices", err.Error())
+		}
+
+		// serialize the services with the labels requested
+		services = r.filterLabels(options.Domain, services, req.Options.GetLabels())
+		rsp.Services = append(rsp.Services, r.toProto(options.Domain, services)...)
+	}
+
+	// ensure the read is consistent with the revision requested
+	return checkRevision("registry.Registry.GetServices", req.Options, options.Domain, req.Services...)
+}
+
+// Resolve the nodes of a service and the share of traffic each should receive
+func (r *Registry) Resolve(ctx context.Context, req *pb.ResolveRequest, rsp *pb.ResolveResponse) error {
+	// parse the options
+	var domain string
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request
+	publicNS := namespace.Public(goregistry.DefaultDomain)
+	if err := namespace.Authorize(ctx, domain, publicNS); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.Resolve", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.Resolve", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.Resolve", err.Error())
+	}
+
+	// get the services in the namespace
+	services, err := registry.GetService(req.Service, goregistry.GetDomain(domain))
+	if err == goregistry.ErrNotFound || len(services) == 0 {
+		return errors.NotFound("registry.Registry.Resolve", goregistry.ErrNotFound.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.Resolve", err.Error())
 	}
 
+	rsp.Nodes = util.Resolve(r.toProto(domain, services), req.Splits)
 	return nil
 }
 
@@ -92,9 +229,14 @@ func (r *Registry) Register(ctx context.Context, req *pb.Service, rsp *pb.EmptyR
 	var opts []goregistry.RegisterOption
 	var domain string
 
-	// parse the options
-	if req.Options != nil && req.Options.Ttl > 0 {
-		ttl := time.Duration(req.Options.Ttl) * time.Second
+	// parse the options, registrations bound to a session expire with it
+	var session string
+	var ttl time.Duration
+	if req.Options != nil {
+		session = req.Options.Session
+	}
+	if req.Options != nil && req.Options.Ttl > 0 && len(session) == 0 {
+		ttl = time.Duration(req.Options.Ttl) * time.Second
 		opts = append(opts, goregistry.RegisterTTL(ttl))
 	}
 	if req.Options != nil && len(req.Options.Domain) > 0 {
@@ -113,13 +255,61 @@ func (r *Registry) Register(ctx context.Context, req *pb.Service, rsp *pb.EmptyR
 		return errors.InternalServerError("registry.Registry.Register", err.Error())
 	}
 
-	// register the service
+	// enforce the quota of the namespace, releasing the reservation if the registration fails
+	if err := r.reserveQuota(domain, util.ToService(req)); err != nil {
+		if qerr, ok := err.(*quotaError); ok {
+			return errors.TooManyRequests("registry.Registry.Register", qerr.Error())
+		}
+		return errors.InternalServerError("registry.Registry.Register", "Error checking quota: %v", err)
+	}
+	var registered bool
+	defer func() {
+		if !registered {
+			r.usage.reset(domain)
+		}
+	}()
+
+	// resolve conflicts with nodes registered by other processes
+	if err := resolveConflicts(domain, req); err != nil {
+		return errors.Conflict("registry.Registry.Register", err.Error())
+	}
+
+	// bind the registration to the session
+	if len(session) > 0 {
+		sess, err := bindSession(domain, session, req)
+		if err == ErrSessionNotFound {
+			return errors.BadRequest("registry.Registry.Register", err.Error())
+		} else if err != nil {
+			return errors.InternalServerError("registry.Registry.Register", "Error binding to session: %v", err)
+		}
+		opts = append(opts, goregistry.RegisterTTL(time.Duration(sess.Ttl)*time.Second))
+	}
+
+	// register the service, the registry doesn't keep the labels or locality of its nodes
 	if err := registry.Register(util.ToService(req), opts...); err != nil {
 		return errors.InternalServerError("registry.Registry.Register", err.Error())
 	}
+	registered = true
+	r.attributes.set(domain, req)
+
+	// services re-register periodically, which only renews their leases and doesn't change
+	// the revision of the registry. The registry removes the nodes once their ttl passes,
+	// which is recorded once their leases expire.
+	changed, err := renewLeases(domain, req, ttl)
+	if err != nil {
+		log.Errorf("Error renewing the leases of %v: %v", req.Name, err)
+	}
+
+	// record the change before returning so reads at the new revision include it
+	var seq int64
+	if changed {
+		seq = r.recordChange(domain, "create", req)
+		r.audit(ctx, domain, "create", req)
+		r.clearTombstone(domain, req)
+	}
 
 	// publish the event
-	go r.publishEvent("create", req)
+	go r.publish(domain, "create", req, seq)
 
 	return nil
 }
@@ -147,9 +337,23 @@ func (r *Registry) Deregister(ctx context.Context, req *pb.Service, rsp *pb.Empt
 	if err := registry.Deregister(util.ToService(req), goregistry.DeregisterDomain(domain)); err != nil {
 		return errors.InternalServerError("registry.Registry.Deregister", err.Error())
 	}
+	r.usage.reset(domain)
 
-	// publish the event
-	go r.publishEvent("delete", req)
+	// remove the registration from the session it's bound to
+	if req.Options != nil && len(req.Options.Session) > 0 {
+		if err := unbindSession(domain, req.Options.Session, req); err != nil {
+			log.Errorf("Error removing %v from session %v: %v", req.Name, req.Options.Session, err)
+		}
+	}
+	if err := removeLeases(domain, req); err != nil {
+		log.Errorf("Error removing the leases of %v: %v", req.Name, err)
+	}
+
+	// record the change and publish the event
+	seq := r.recordChange(domain, "delete", req)
+	r.audit(ctx, domain, "delete", req)
+	r.tombstone(ctx, domain, req)
+	go r.publish(domain, "delete", req, seq)
 
 	return nil
 }
@@ -180,12 +384,63 @@ func (r *Registry) ListServices(ctx context.Context, req *pb.ListRequest, rsp *p
 		return errors.InternalServerError("registry.Registry.ListServices", err.Error())
 	}
 
+	// ensure the read is consistent with the revision requested
+	if err := checkRevision("registry.Registry.ListServices", req.Options, domain); err != nil {
+		return err
+	}
+
+	// only return the nodes with the labels requested
+	services = r.filterLabels(domain, services, req.Options.GetLabels())
+
+	// return the page requested
+	if len(req.Prefix) > 0 || len(req.Cursor) > 0 || req.Limit > 0 {
+		services, rsp.NextCursor, err = util.Page(services, req.Prefix, req.Cursor, int(req.Limit))
+		if err != nil {
+			return errors.BadRequest("registry.Registry.ListServices", err.Error())
+		}
+	}
+
 	// serialize the response
-	rsp.Services = make([]*pb.Service, len(services))
-	for i, srv := range services {
-		rsp.Services[i] = util.ToProto(srv)
+	rsp.Services = r.toProto(domain, services)
+
+	return nil
+}
+
+// Graph returns the dependency graph of the services
+func (r *Registry) Graph(ctx context.Context, req *pb.GraphRequest, rsp *pb.GraphResponse) error {
+	// parse the options
+	var domain string
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request
+	publicNS := namespace.Public(goregistry.DefaultDomain)
+	if err := namespace.Authorize(ctx, domain, publicNS); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.Graph", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.Graph", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.Graph", err.Error())
+	}
+
+	// list the services and lookup their nodes, which the dependencies are declared on
+	list, err := registry.ListServices(goregistry.ListDomain(domain))
+	if err != nil {
+		return errors.InternalServerError("registry.Registry.Graph", err.Error())
+	}
+	names := make([]string, 0, len(list))
+	for _, srv := range list {
+		names = append(names, srv.Name)
+	}
+	services, err := registry.GetServices(names, goregistry.GetDomain(domain))
+	if err != nil {
+		return errors.InternalServerError("registry.Registry.Graph", err.Error())
 	}
 
+	rsp.Nodes = util.Graph(services, req.Service)
 	return nil
 }
 
@@ -209,21 +464,56 @@ func (r *Registry) Watch(ctx context.Context, req *pb.WatchRequest, rsp pb.Regis
 		return errors.InternalServerError("registry.Registry.Watch", err.Error())
 	}
 
-	// setup the watcher
-	watcher, err := registry.Watch(goregistry.WatchService(req.Service), goregistry.WatchDomain(domain))
-	if err != nil {
-		return errors.InternalServerError("registry.Registry.Watch", err.Error())
+	// subscribe to the changes to the service
+	sub := r.watchers.subscribe(domain, req.Service)
+	defer r.watchers.unsubscribe(domain, req.Service, sub)
+
+	// only send the changes to the nodes with the labels requested
+	labels := req.Options.GetLabels()
+
+	// replay the changes the watcher missed. The watch is setup first so no changes are lost,
+	// meaning a change may be sent twice if it's made whilst the changelog is read.
+	if req.Since > 0 {
+		changes, err := readChangelog(domain, req.Service, req.Since)
+		if err != nil {
+			return errors.InternalServerError("registry.Registry.Watch", "Error reading changelog: %v", err)
+		}
+		for _, c := range changes {
+			services := util.FilterLabels([]*pb.Service{c.Service}, labels)
+			if len(services) == 0 {
+				continue
+			}
+			c.Service = services[0]
+			if err := rsp.Send(c); err != nil {
+				return errors.InternalServerError("registry.Registry.Watch", err.Error())
+			}
+		}
 	}
 
 	for {
-		next, err := watcher.Next()
-		if err != nil {
-			return errors.InternalServerError("registry.Registry.Watch", err.Error())
+		var next *pb.Result
+		var ok bool
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case next, ok = <-sub.results:
+		}
+		if !ok {
+			return errors.InternalServerError("registry.Registry.Watch", sub.err.Error())
+		}
+		services := util.FilterLabels([]*pb.Service{next.Service}, labels)
+		if len(services) == 0 {
+			continue
 		}
 
-		err = rsp.Send(&pb.Result{
-			Action:  next.Action,
-			Service: util.ToProto(next.Service),
+		// the result is shared with the other watchers, the sequence is the one the change was
+		// recorded at so watchers which reconnect replay the changes recorded since
+		err := rsp.Send(&pb.Result{
+			Action:    next.Action,
+			Service:   services[0],
+			Timestamp: next.Timestamp,
+			Sequence:  next.Sequence,
 		})
 		if err != nil {
 			return errors.InternalServerError("registry.Registry.Watch", err.Error())
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestGetServices(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	for _, srv := range []*goregistry.Service{
+		{Name: "foo", Version: "v1", Nodes: []*goregistry.Node{{Id: "foo-1"}}},
+		{Name: "foo", Version: "v2", Nodes: []*goregistry.Node{{Id: "foo-2"}}},
+		{Name: "bar", Version: "latest", Nodes: []*goregistry.Node{{Id: "bar-1"}}},
+	} {
+		registry.Register(srv)
+	}
+	registry.Register(&goregistry.Service{Name: "baz", Version: "latest", Nodes: []*goregistry.Node{{Id: "baz-1"}}}, goregistry.RegisterDomain("other"))
+
+	get := func(ctx context.Context, domain string, names ...string) ([]string, error) {
+		rsp := &pb.GetServicesResponse{}
+		req := &pb.GetServicesRequest{Services: names, Options: &pb.Options{Domain: domain}}
+		if err := new(Registry).GetServices(ctx, req, rsp); err != nil {
+			return nil, err
+		}
+		var result []string
+		for _, s := range rsp.Services {
+			result = append(result, s.Name+":"+s.Version)
+		}
+		sort.Strings(result)
+		return result, nil
+	}
+
+	// the default domain is public and services which don't exist are omitted
+	services, err := get(context.Background(), "", "foo", "bar", "missing")
+	if err != nil {
+		t.Fatalf("Unexpected error getting services: %v", err)
+	}
+	if len(services) != 3 || services[0] != "bar:latest" || services[1] != "foo:v1" || services[2] != "foo:v2" {
+		t.Errorf("Expected every version of foo and bar, got %v", services)
+	}
+
+	// services are only read from the domain requested
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Issuer: "other"})
+	if services, err := get(ctx, "other", "foo", "baz"); err != nil || len(services) != 1 || services[0] != "baz:latest" {
+		t.Errorf("Expected baz from the other domain, got %v: %v", services, err)
+	}
+
+	// other domains require an account of the domain
+	if _, err := get(context.Background(), "other", "baz"); errors.Parse(err) == nil || errors.Parse(err).Code != 401 {
+		t.Errorf("Expected an unauthorized error, got %v", err)
+	}
+	ctx = auth.ContextWithAccount(context.Background(), &auth.Account{ID: "bob", Issuer: "another"})
+	if _, err := get(ctx, "other", "baz"); errors.Parse(err) == nil || errors.Parse(err).Code != 403 {
+		t.Errorf("Expected a forbidden error, got %v", err)
+	}
+}
+
+type testWatchStream struct {
+	pb.Registry_WatchStream
+	results chan *pb.Result
+}
+
+func (s *testWatchStream) Send(res *pb.Result) error {
+	s.results <- res
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	// the server's account can access every domain
+	ctx, cancel := context.WithCancel(auth.ContextWithAccount(context.Background(), &auth.Account{ID: "registry", Issuer: "micro"}))
+	defer cancel()
+
+	// the changes are made in a domain of their own, since other tests may still be recording
+	// changes in the default domain
+	opts := &pb.Options{Domain: "watch"}
+
+	r := &Registry{ID: "registry-1"}
+	stream := &testWatchStream{results: make(chan *pb.Result, 10)}
+	go r.Watch(ctx, &pb.WatchRequest{Service: "foo", Options: opts}, stream)
+	for i := 0; ; i++ {
+		r.watchers.Lock()
+		n := len(r.watchers.subs)
+		r.watchers.Unlock()
+		if n > 0 {
+			break
+		} else if i == 100 {
+			t.Fatalf("Expected the watcher to subscribe")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	foo := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1"}}, Options: opts}
+	bar := &pb.Service{Name: "bar", Version: "latest", Nodes: []*pb.Node{{Id: "bar-1"}}, Options: opts}
+	r.Register(ctx, foo, &pb.EmptyResponse{})
+	r.Register(ctx, bar, &pb.EmptyResponse{})
+	r.Deregister(ctx, foo, &pb.EmptyResponse{})
+
+	// renewing a registration doesn't change the registry
+	r.Register(ctx, bar, &pb.EmptyResponse{})
+
+	// changes recorded by other replicas are sent with their sequence, the registry's own
+	// events and events which didn't change the registry are ignored
+	for _, ev := range []*pb.Event{
+		{Id: "registry-2", Type: pb.EventType(goregistry.Create), Service: foo, Sequence: 7, Domain: "watch"},
+		{Id: "registry-1", Type: pb.EventType(goregistry.Create), Service: foo, Sequence: 8, Domain: "watch"},
+		{Id: "registry-2", Type: pb.EventType(goregistry.Create), Service: foo, Domain: "watch"},
+		{Id: "registry-2", Type: pb.EventType(goregistry.Create), Service: foo, Sequence: 9, Domain: "other"},
+	} {
+		r.processEvent(ctx, ev)
+	}
+
+	// the changes to foo are sent with the sequences they were recorded at
+	expected := []struct {
+		action   string
+		sequence int64
+	}{{"create", 1}, {"delete", 3}, {"create", 7}}
+	for _, e := range expected {
+		select {
+		case res := <-stream.results:
+			if res.Action != e.action || res.Sequence != e.sequence || res.Service.Name != "foo" {
+				t.Fatalf("Expected %v of foo at sequence %v, got %v of %v at %v", e.action, e.sequence, res.Action, res.Service.Name, res.Sequence)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %v of foo at sequence %v", e.action, e.sequence)
+		}
+	}
+	if len(stream.results) != 0 {
+		t.Fatalf("Expected only the changes to foo to be sent, got %v", <-stream.results)
+	}
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/kubernetes"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+const (
+	// SourceKey is the node metadata key set to "kubernetes" on nodes imported from kubernetes
+	SourceKey = "source"
+	// runtimeLabel is set on the kubernetes services created by the micro runtime, whose
+	// pods register themselves
+	runtimeLabel = "micro"
+)
+
+// kubeSync mirrors the services in a registry domain into a kubernetes namespace and the
+// kubernetes services into the registry, so workloads on either side can discover the other.
+// Only the replica of the registry holding the sync's lease runs it.
+type kubeSync struct {
+	cluster  kubernetes.Cluster
+	id       string
+	domain   string
+	interval time.Duration
+	// leader is true while the lease is held
+	leader bool
+	// register and remove the imported services
+	register func(domain string, services []*pb.Service, ttl time.Duration)
+	remove   func(domain string, services []*pb.Service)
+
+	sync.Mutex
+	// imported are the services last imported from kubernetes, keyed by name
+	imported map[string]*pb.Service
+}
+
+func newKubeSync(c kubernetes.Cluster, id, domain string, interval time.Duration, register func(string, []*pb.Service, time.Duration), remove func(string, []*pb.Service)) *kubeSync {
+	return &kubeSync{
+		cluster:  c,
+		id:       id,
+		domain:   domain,
+		interval: interval,
+		register: register,
+		remove:   remove,
+		imported: make(map[string]*pb.Service),
+	}
+}
+
+// run the sync until the registry stops
+func (k *kubeSync) run() {
+	t := time.NewTicker(k.interval)
+	defer t.Stop()
+
+	for range t.C {
+		if !k.lead() {
+			continue
+		}
+		if err := k.sync(); err != nil {
+			log.Errorf("Error syncing with kubernetes: %v", err)
+		}
+	}
+}
+
+// lead acquires or renews the lease of the sync, returning true if it's held. The lease
+// expires if it isn't renewed for a few intervals, e.g. the leader stops, so another replica
+// takes over.
+func (k *kubeSync) lead() bool {
+	name := "micro-registry-sync-" + kubernetes.ResourceName(k.domain)
+	ok, err := k.cluster.AcquireLease(name, k.id, k.interval*3)
+	if err != nil {
+		log.Errorf("Error acquiring the kubernetes sync lease: %v", err)
+	}
+
+	if ok && !k.leader {
+		log.Infof("Leading the sync with kubernetes")
+	} else if !ok && k.leader {
+		log.Infof("No longer leading the sync with kubernetes")
+
+		// the leader tracks the imports from now on, and the registrations of those imported
+		// here expire unless it renews them
+		k.Lock()
+		k.imported = make(map[string]*pb.Service)
+		k.Unlock()
+	}
+	k.leader = ok
+	return ok
+}
+
+// sync the registry and kubernetes in both directions
+func (k *kubeSync) sync() error {
+	k.Lock()
+	defer k.Unlock()
+
+	services, err := registry.ListServices(goregistry.ListDomain(k.domain))
+	if err != nil {
+		return err
+	}
+	kubeServices, err := k.cluster.Services()
+	if err != nil {
+		return err
+	}
+
+	k.export(services, kubeServices)
+	k.importServices(kubeServices)
+	return nil
+}
+
+// export the nodes of the micro services to kubernetes, removing the kubernetes services
+// of those which are no longer registered
+func (k *kubeSync) export(services []*goregistry.Service, kubeServices []*kubernetes.Service) {
+	existing := make(map[string]*kubernetes.Service, len(kubeServices))
+	for _, s := range kubeServices {
+		existing[s.Name] = s
+	}
+
+	// group the nodes by service name since versions share a kubernetes service
+	exported := make(map[string]*kubernetes.Service)
+	for _, srv := range services {
+		for _, node := range srv.Nodes {
+			// nodes imported from kubernetes aren't exported back
+			if node.Metadata[SourceKey] == "kubernetes" {
+				continue
+			}
+			host, port, err := net.SplitHostPort(node.Address)
+			if err != nil || net.ParseIP(host) == nil {
+				continue
+			}
+			p, err := strconv.Atoi(port)
+			if err != nil {
+				continue
+			}
+
+			name := kubernetes.ResourceName(srv.Name)
+			ks, ok := exported[name]
+			if !ok {
+				ks = &kubernetes.Service{
+					Name:        name,
+					Annotations: map[string]string{kubernetes.ServiceAnnotation: srv.Name},
+				}
+				exported[name] = ks
+			}
+			ks.Endpoints = append(ks.Endpoints, kubernetes.Endpoint{IP: host, Port: p})
+		}
+	}
+
+	for name, ks := range exported {
+		// don't replace services which weren't created by the registry
+		if s, ok := existing[name]; ok && !s.Managed() {
+			continue
+		}
+		if err := k.cluster.Apply(ks); err != nil {
+			log.Errorf("Error exporting %v to kubernetes: %v", ks.Annotations[kubernetes.ServiceAnnotation], err)
+		}
+	}
+
+	for _, s := range kubeServices {
+		if _, ok := exported[s.Name]; ok || !s.Managed() {
+			continue
+		}
+		if err := k.cluster.Delete(s.Name); err != nil {
+			log.Errorf("Error removing %v from kubernetes: %v", s.Name, err)
+		}
+	}
+}
+
+// importServices registers the endpoints of the kubernetes services, deregistering the
+// endpoints which have been removed since the last sync
+func (k *kubeSync) importServices(kubeServices []*kubernetes.Service) {
+	imported := make(map[string]*pb.Service)
+	for _, s := range kubeServices {
+		// skip the services mirrored from the registry and those of the micro runtime
+		if s.Managed() || len(s.Labels[runtimeLabel]) > 0 || len(s.Endpoints) == 0 {
+			continue
+		}
+
+		srv := &pb.Service{Name: s.Name, Version: "latest", Options: &pb.Options{Domain: k.domain}}
+		for _, e := range s.Endpoints {
+			srv.Nodes = append(srv.Nodes, &pb.Node{
+				Id:      "kubernetes-" + e.Address(),
+				Address: e.Address(),
+				Metadata: map[string]string{
+					SourceKey:     "kubernetes",
+					util.ProbeKey: "false",
+				},
+			})
+		}
+		imported[s.Name] = srv
+	}
+
+	var register []*pb.Service
+	for _, srv := range imported {
+		register = append(register, srv)
+	}
+
+	// the registrations expire if the sync stops, e.g. the registry is restarted elsewhere
+	k.register(k.domain, register, k.interval*3)
+
+	var remove []*pb.Service
+	for name, prev := range k.imported {
+		if removed := removedNodes(prev, imported[name]); len(removed.Nodes) > 0 {
+			remove = append(remove, removed)
+		}
+	}
+	if len(remove) > 0 {
+		k.remove(k.domain, remove)
+	}
+
+	k.imported = imported
+}
+
+// removedNodes returns the service with the nodes of prev which aren't in cur
+func removedNodes(prev, cur *pb.Service) *pb.Service {
+	nodes := make(map[string]bool)
+	if cur != nil {
+		for _, n := range cur.Nodes {
+			nodes[n.Id] = true
+		}
+	}
+
+	removed := &pb.Service{Name: prev.Name, Version: prev.Version, Options: prev.Options}
+	for _, n := range prev.Nodes {
+		if !nodes[n.Id] {
+			removed.Nodes = append(removed.Nodes, n)
+		}
+	}
+	return removed
+}
+
+// registerAll adds the registrations, e.g. of services imported from kubernetes
+func (r *Registry) registerAll(domain string, services []*pb.Service, ttl time.Duration) {
+	for _, srv := range services {
+		err := registry.Register(util.ToService(srv), goregistry.RegisterDomain(domain), goregistry.RegisterTTL(ttl))
+		if err != nil {
+			log.Errorf("Error registering %v: %v", srv.Name, err)
+			continue
+		}
+		changed, err := renewLeases(domain, srv, ttl)
+		if err != nil {
+			log.Errorf("Error renewing the leases of %v: %v", srv.Name, err)
+		}
+		var seq int64
+		if changed {
+			seq = r.recordChange(domain, "create", srv)
+			r.audit(context.Background(), domain, "create", srv)
+			r.clearTombstone(domain, srv)
+		}
+		go r.publish(domain, "create", srv, seq)
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/kubernetes"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+type testCluster struct {
+	services map[string]*kubernetes.Service
+	// holders of the leases
+	leases map[string]string
+}
+
+func (c *testCluster) Services() ([]*kubernetes.Service, error) {
+	var services []*kubernetes.Service
+	for _, s := range c.services {
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+func (c *testCluster) Apply(s *kubernetes.Service) error {
+	s.Labels = map[string]string{"app.kubernetes.io/managed-by": kubernetes.ManagerName}
+	c.services[s.Name] = s
+	return nil
+}
+
+func (c *testCluster) Delete(name string) error {
+	delete(c.services, name)
+	return nil
+}
+
+func (c *testCluster) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	if h, ok := c.leases[name]; ok && h != holder {
+		return false, nil
+	}
+	c.leases[name] = holder
+	return true, nil
+}
+
+func TestKubeSync(t *testing.T) {
+	registry.DefaultRegistry = memory.NewRegistry()
+	registry.Register(&goregistry.Service{Name: "go.micro.foo", Version: "latest", Nodes: []*goregistry.Node{
+		{Id: "foo-1", Address: "10.0.0.1:8080"},
+		{Id: "foo-2", Address: "10.0.0.2:8080"},
+	}})
+
+	cluster := &testCluster{services: map[string]*kubernetes.Service{
+		"bar": {Name: "bar", Endpoints: []kubernetes.Endpoint{{IP: "10.1.0.1", Port: 80}, {IP: "10.1.0.2", Port: 80}}},
+		// services of the micro runtime and without endpoints aren't imported
+		"baz": {Name: "baz", Labels: map[string]string{"micro": "service"}, Endpoints: []kubernetes.Endpoint{{IP: "10.1.0.3", Port: 80}}},
+		"qux": {Name: "qux"},
+		// services mirrored from the registry are removed once the micro service is
+		"old": {Name: "old", Labels: map[string]string{"app.kubernetes.io/managed-by": kubernetes.ManagerName}},
+	}}
+
+	register := func(domain string, services []*pb.Service, ttl time.Duration) {
+		for _, srv := range services {
+			registry.Register(util.ToService(srv), goregistry.RegisterDomain(domain))
+		}
+	}
+	remove := func(domain string, services []*pb.Service) {
+		for _, srv := range services {
+			registry.Deregister(util.ToService(srv), goregistry.DeregisterDomain(domain))
+		}
+	}
+
+	k := newKubeSync(cluster, "registry-1", goregistry.DefaultDomain, time.Minute, register, remove)
+	if err := k.sync(); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+
+	// the micro service is exported
+	foo, ok := cluster.services["go-micro-foo"]
+	if !ok || len(foo.Endpoints) != 2 || foo.Annotations[kubernetes.ServiceAnnotation] != "go.micro.foo" {
+		t.Fatalf("Expected the micro service to be exported, got %+v", foo)
+	}
+	if _, ok := cluster.services["old"]; ok {
+		t.Errorf("Expected the service which is no longer registered to be removed")
+	}
+
+	// the kubernetes service is imported
+	srvs, err := registry.GetService("bar")
+	if err != nil || len(srvs) != 1 || len(srvs[0].Nodes) != 2 {
+		t.Fatalf("Expected the kubernetes service to be imported, got %v: %v", srvs, err)
+	}
+	for _, name := range []string{"baz", "qux"} {
+		if _, err := registry.GetService(name); err != goregistry.ErrNotFound {
+			t.Errorf("Expected %v not to be imported, got %v", name, err)
+		}
+	}
+
+	// endpoints removed from kubernetes are deregistered, and imported services aren't exported
+	cluster.services["bar"].Endpoints = cluster.services["bar"].Endpoints[:1]
+	if err := k.sync(); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+	srvs, err = registry.GetService("bar")
+	if err != nil || len(srvs) != 1 || len(srvs[0].Nodes) != 1 {
+		t.Fatalf("Expected the removed endpoint to be deregistered, got %v: %v", srvs, err)
+	}
+	if s := cluster.services["bar"]; s.Managed() {
+		t.Errorf("Expected the imported service not to be exported")
+	}
+}
+
+func TestKubeSyncLead(t *testing.T) {
+	cluster := &testCluster{leases: map[string]string{}}
+	a := newKubeSync(cluster, "registry-1", goregistry.DefaultDomain, time.Minute, nil, nil)
+	b := newKubeSync(cluster, "registry-2", goregistry.DefaultDomain, time.Minute, nil, nil)
+
+	if !a.lead() || !a.lead() {
+		t.Fatalf("Expected the first replica to acquire and renew the lease")
+	}
+	if b.lead() {
+		t.Fatalf("Expected the second replica not to lead while the lease is held")
+	}
+
+	// once the lease is taken over the first replica stops and forgets its imports
+	a.imported["bar"] = &pb.Service{Name: "bar"}
+	cluster.leases["micro-registry-sync-"+goregistry.DefaultDomain] = "registry-2"
+	if a.lead() || !b.lead() {
+		t.Fatalf("Expected the lease to move to the second replica")
+	}
+	if len(a.imported) > 0 {
+		t.Errorf("Expected the imports to be forgotten, got %v", a.imported)
+	}
+}
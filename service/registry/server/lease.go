@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	gostore "github.com/micro/go-micro/v3/store"
+	log "github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+var (
+	// DefaultLeaseInterval is the interval the leases of the registrations are checked at, so
+	// the registrations which expired are recorded in the changelog
+	DefaultLeaseInterval = time.Second * 10
+)
+
+const leasePrefix = "lease/"
+
+// lease of a registered node. The registry removes nodes registered with a ttl once it passes
+// without them registering again, the lease records when that is so the removal can be recorded.
+type lease struct {
+	Domain string
+	// Service with only the node of the lease
+	Service *pb.Service
+	// Expires is the unix time the registration expires unless it's renewed, zero if the node
+	// was registered without a ttl
+	Expires int64
+	// Expired is set once a replica has claimed the expiry of the lease
+	Expired bool
+}
+
+func leaseKey(domain string, srv *pb.Service, node *pb.Node) string {
+	return leasePrefix + domain + "/" + srv.Name + "/" + srv.Version + "/" + node.Id
+}
+
+// withNode returns a copy of the service with only the node
+func withNode(srv *pb.Service, node *pb.Node) *pb.Service {
+	return &pb.Service{
+		Name:      srv.Name,
+		Version:   srv.Version,
+		Metadata:  srv.Metadata,
+		Endpoints: srv.Endpoints,
+		Nodes:     []*pb.Node{node},
+		Options:   srv.Options,
+	}
+}
+
+// renewLeases extends the leases of the nodes of the service by the ttl, nodes registered
+// without a ttl hold their lease until they're deregistered. It returns true if any of the
+// nodes wasn't already registered with the same address, metadata and endpoints, so the
+// registration changed the registry rather than renewing it.
+func renewLeases(domain string, srv *pb.Service, ttl time.Duration) (bool, error) {
+	prefix := leasePrefix + domain + "/" + srv.Name + "/" + srv.Version + "/"
+	recs, err := store.Read(prefix, gostore.ReadPrefix())
+	if err != nil && err != gostore.ErrNotFound {
+		return true, err
+	}
+	leases := make(map[string]*lease, len(recs))
+	for _, rec := range recs {
+		var l *lease
+		if err := json.Unmarshal(rec.Value, &l); err == nil && l.Service != nil {
+			leases[rec.Key] = l
+		}
+	}
+
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).Unix()
+	}
+
+	var changed bool
+	for _, n := range srv.Nodes {
+		key := leaseKey(domain, srv, n)
+		l := &lease{Domain: domain, Service: withNode(srv, n), Expires: expires}
+		if prev, ok := leases[key]; !ok || prev.Expired || !sameRegistration(prev.Service, l.Service) {
+			changed = true
+		}
+
+		bytes, err := json.Marshal(l)
+		if err != nil {
+			return true, err
+		}
+		if err := store.Write(&gostore.Record{Key: key, Value: bytes}); err != nil {
+			return true, err
+		}
+	}
+	return changed, nil
+}
+
+// sameRegistration returns true if the services have the same metadata, endpoints and nodes
+func sameRegistration(a, b *pb.Service) bool {
+	return proto.Equal(
+		&pb.Service{Metadata: a.Metadata, Endpoints: a.Endpoints, Nodes: a.Nodes},
+		&pb.Service{Metadata: b.Metadata, Endpoints: b.Endpoints, Nodes: b.Nodes},
+	)
+}
+
+// removeLeases removes the leases of the nodes of the service, e.g. once it's deregistered
+func removeLeases(domain string, srv *pb.Service) error {
+	for _, n := range srv.Nodes {
+		if err := store.Delete(leaseKey(domain, srv, n)); err != nil && err != gostore.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchLeases records the expiry of the registrations at the interval
+func (r *Registry) watchLeases(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for now := range t.C {
+		if err := r.expireLeases(now); err != nil {
+			log.Errorf("Error checking the leases of the registrations: %v", err)
+		}
+	}
+}
+
+// expireLeases records, audits and tombstones the removal of the registrations whose leases
+// expired before now. Each replica of the registry checks the leases, the expiry is claimed by
+// marking the lease expired only if it's unchanged, so it's recorded once and not at all if the
+// node renewed its registration in the meantime.
+func (r *Registry) expireLeases(now time.Time) error {
+	recs, err := store.Read(leasePrefix, gostore.ReadPrefix())
+	if err == gostore.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		var l *lease
+		if err := json.Unmarshal(rec.Value, &l); err != nil || l.Service == nil {
+			log.Errorf("Error decoding lease %v: %v", strings.TrimPrefix(rec.Key, leasePrefix), err)
+			continue
+		}
+		if l.Expires == 0 || l.Expires > now.Unix() {
+			continue
+		}
+
+		// a lease left expired by a replica which failed before removing it is removed
+		if !l.Expired {
+			l.Expired = true
+			bytes, err := json.Marshal(l)
+			if err != nil {
+				return err
+			}
+			claim := &bulk.Upsert{
+				Record:    &gostore.Record{Key: rec.Key, Value: bytes},
+				Condition: bulk.Condition{ETag: bulk.ETag(rec.Value)},
+			}
+			results, err := store.BulkUpsert([]*bulk.Upsert{claim})
+			if err != nil {
+				return err
+			} else if len(results) != 1 || !results[0].Written {
+				continue
+			}
+
+			log.Infof("Registration of node %v of %v expired", l.Service.Nodes[0].Id, l.Service.Name)
+			srv := util.MarkRemoved(l.Service, util.RemovalExpired)
+			seq := r.recordChange(l.Domain, "delete", srv)
+			r.audit(context.Background(), l.Domain, "delete", l.Service)
+			r.tombstone(context.Background(), l.Domain, l.Service)
+			go r.publish(l.Domain, "delete", srv, seq)
+		}
+
+		if err := store.Delete(rec.Key); err != nil && err != gostore.ErrNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gostore "github.com/micro/go-micro/v3/store"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestLeases(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	domain := goregistry.DefaultDomain
+
+	foo := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1"}, {Id: "foo-2"}}}
+	if changed, err := renewLeases(domain, foo, time.Minute); err != nil || !changed {
+		t.Fatalf("Expected the registration to change the registry, got %v: %v", changed, err)
+	}
+
+	// renewing the registration doesn't change the registry, changing a node does
+	if changed, err := renewLeases(domain, foo, time.Minute); err != nil || changed {
+		t.Fatalf("Expected renewing the registration not to change the registry, got %v: %v", changed, err)
+	}
+	moved := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1", Address: "10.0.0.2:8080"}}}
+	if changed, err := renewLeases(domain, moved, time.Minute); err != nil || !changed {
+		t.Fatalf("Expected changing the address to change the registry, got %v: %v", changed, err)
+	}
+	renewLeases(domain, foo, time.Minute)
+
+	// nodes registered without a ttl don't expire
+	bar := &pb.Service{Name: "bar", Version: "latest", Nodes: []*pb.Node{{Id: "bar-1"}}}
+	renewLeases(domain, bar, 0)
+
+	// leases which haven't expired aren't recorded
+	a, b := new(Registry), new(Registry)
+	if err := a.expireLeases(time.Now()); err != nil {
+		t.Fatalf("Unexpected error expiring the leases: %v", err)
+	}
+	if seq, _ := currentSequence(domain); seq != 0 {
+		t.Fatalf("Expected no changes to be recorded, got sequence %v", seq)
+	}
+
+	// deregistered nodes no longer expire
+	removeLeases(domain, &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-2"}}})
+
+	// the expiry is recorded once, replicas checking the leases afterwards find them removed
+	later := time.Now().Add(time.Minute * 2)
+	if err := a.expireLeases(later); err != nil {
+		t.Fatalf("Unexpected error expiring the leases: %v", err)
+	}
+	if err := b.expireLeases(later); err != nil {
+		t.Fatalf("Unexpected error expiring the leases: %v", err)
+	}
+
+	changes, err := readChangelog(domain, "", 0)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v: %v", changes, err)
+	}
+	if c := changes[0]; c.Action != "delete" || len(c.Service.Nodes) != 1 || c.Service.Nodes[0].Id != "foo-1" {
+		t.Errorf("Expected the expiry of foo-1 to be recorded, got %v", c)
+	}
+	if entries, err := readAudit(domain, "foo", 0, 0, 0); err != nil || len(entries) != 1 || entries[0].Action != "delete" {
+		t.Errorf("Expected the expiry to be audited once, got %v: %v", entries, err)
+	}
+	if ts, err := readTombstones(domain, "foo"); err != nil || len(ts) != 1 || len(ts[0].Service.Nodes) != 1 {
+		t.Errorf("Expected the expired node to be tombstoned, got %v: %v", ts, err)
+	}
+	if recs, _ := store.Read(leasePrefix, gostore.ReadPrefix()); len(recs) != 1 || recs[0].Key != leaseKey(domain, bar, bar.Nodes[0]) {
+		t.Errorf("Expected only the lease of bar to remain, got %v", recs)
+	}
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+var (
+	// DefaultQuotaRefreshInterval is the interval the usage of a namespace's quota is reloaded
+	// from the registry at, so nodes which expire or are registered by other instances of the
+	// registry are counted
+	DefaultQuotaRefreshInterval = time.Minute
+)
+
+// Quota limits the resources a namespace can register, zero values are unlimited
+type Quota struct {
+	// Services is the number of services
+	Services int
+	// Nodes is the number of nodes across all services
+	Nodes int
+	// Endpoints is the number of endpoints across all services
+	Endpoints int
+}
+
+// Unlimited returns true if the quota has no limits
+func (q Quota) Unlimited() bool {
+	return q.Services <= 0 && q.Nodes <= 0 && q.Endpoints <= 0
+}
+
+// quota returns the quota of the domain
+func (r *Registry) quota(domain string) Quota {
+	if q, ok := r.Quotas[domain]; ok {
+		return q
+	}
+	return r.Quota
+}
+
+// quotaUsage keeps the usage of each domain's quota, so registrations are checked and
+// reserved without listing the domain's services every time
+type quotaUsage struct {
+	sync.Mutex
+	domains map[string]*usage
+}
+
+// usage of a domain's quota
+type usage struct {
+	services  map[string]bool
+	nodes     map[string]bool
+	endpoints map[string]bool
+	// loaded is the time the usage was loaded from the registry
+	loaded time.Time
+}
+
+func newUsage(services []*goregistry.Service) *usage {
+	u := &usage{
+		services:  make(map[string]bool),
+		nodes:     make(map[string]bool),
+		endpoints: make(map[string]bool),
+		loaded:    time.Now(),
+	}
+	for _, s := range services {
+		u.add(s)
+	}
+	return u
+}
+
+// add the service to the usage, nodes and endpoints already registered aren't counted twice
+func (u *usage) add(s *goregistry.Service) {
+	u.services[s.Name] = true
+	for _, n := range s.Nodes {
+		u.nodes[s.Name+"/"+n.Id] = true
+	}
+	for _, e := range s.Endpoints {
+		u.endpoints[s.Name+"/"+s.Version+"/"+e.Name] = true
+	}
+}
+
+// reset the usage of the domain so it's reloaded from the registry when next checked
+func (q *quotaUsage) reset(domain string) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.domains, domain)
+}
+
+// reserveQuota returns an error if registering the service would take the domain over its
+// quota, otherwise the service is added to the domain's usage. Checking and reserving are
+// done under a single lock so concurrent registrations can't both take the last of a quota.
+// The usage must be reset if the registration then fails. Limits the domain is already over
+// aren't enforced unless the registration increases its usage, so existing nodes can continue
+// to renew their registrations if a quota is lowered.
+func (r *Registry) reserveQuota(domain string, service *goregistry.Service) error {
+	quota := r.quota(domain)
+	if quota.Unlimited() {
+		return nil
+	}
+
+	r.usage.Lock()
+	defer r.usage.Unlock()
+
+	u, ok := r.usage.domains[domain]
+	if !ok || time.Since(u.loaded) > DefaultQuotaRefreshInterval {
+		services, err := registry.ListServices(goregistry.ListDomain(domain))
+		if err != nil {
+			return err
+		}
+		u = newUsage(services)
+		if r.usage.domains == nil {
+			r.usage.domains = make(map[string]*usage)
+		}
+		r.usage.domains[domain] = u
+	}
+
+	before := [3]int{len(u.services), len(u.nodes), len(u.endpoints)}
+	u.add(service)
+	after := [3]int{len(u.services), len(u.nodes), len(u.endpoints)}
+
+	limits := [3]int{quota.Services, quota.Nodes, quota.Endpoints}
+	for i, resource := range []string{"services", "nodes", "endpoints"} {
+		if limits[i] > 0 && after[i] > limits[i] && after[i] > before[i] {
+			// the service was added to the usage, which is reloaded rather than undone
+			delete(r.usage.domains, domain)
+			return &quotaError{domain: domain, resource: resource, limit: limits[i]}
+		}
+	}
+
+	return nil
+}
+
+type quotaError struct {
+	domain   string
+	resource string
+	limit    int
+}
+
+func (q *quotaError) Error() string {
+	return fmt.Sprintf("Namespace %v has reached its limit of %d %v", q.domain, q.limit, q.resource)
+}
+
+// ParseQuota parses the quota of a namespace, e.g. foo=services:10,nodes:100,endpoints:1000
+func ParseQuota(s string) (string, Quota, error) {
+	var q Quota
+
+	comps := strings.SplitN(s, "=", 2)
+	if len(comps) != 2 || len(comps[0]) == 0 || len(comps[1]) == 0 {
+		return "", q, fmt.Errorf("Invalid quota %v, expected namespace=resource:limit,...", s)
+	}
+
+	for _, l := range strings.Split(comps[1], ",") {
+		kv := strings.SplitN(l, ":", 2)
+		if len(kv) != 2 {
+			return "", q, fmt.Errorf("Invalid limit %v, expected resource:limit", l)
+		}
+		n, err := strconv.Atoi(kv[1])
+		if err != nil || n < 0 {
+			return "", q, fmt.Errorf("Invalid limit %v, expected a positive number", l)
+		}
+
+		switch kv[0] {
+		case "services":
+			q.Services = n
+		case "nodes":
+			q.Nodes = n
+		case "endpoints":
+			q.Endpoints = n
+		default:
+			return "", q, fmt.Errorf("Unknown resource %v, expected services, nodes or endpoints", kv[0])
+		}
+	}
+
+	return comps[0], q, nil
+}
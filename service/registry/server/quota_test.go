@@ -0,0 +1,72 @@
+package server
+
+import (
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+func TestReserveQuota(t *testing.T) {
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	r := &Registry{
+		Quota:  Quota{Nodes: 2},
+		Quotas: map[string]Quota{"foo": {Services: 1}},
+	}
+
+	node := func(id string) *goregistry.Service {
+		return &goregistry.Service{Name: "bar", Version: "latest", Nodes: []*goregistry.Node{{Id: id}}}
+	}
+
+	// reservations count towards the quota before the nodes are registered
+	for _, id := range []string{"bar-1", "bar-2"} {
+		if err := r.reserveQuota(goregistry.DefaultDomain, node(id)); err != nil {
+			t.Fatalf("Unexpected error reserving quota: %v", err)
+		}
+	}
+
+	// renewing an existing node doesn't increase usage
+	if err := r.reserveQuota(goregistry.DefaultDomain, node("bar-1")); err != nil {
+		t.Errorf("Expected existing nodes to renew, got %v", err)
+	}
+	if err := r.reserveQuota(goregistry.DefaultDomain, node("bar-3")); err == nil {
+		t.Errorf("Expected the node quota to be exceeded")
+	} else if qerr, ok := err.(*quotaError); !ok || qerr.resource != "nodes" {
+		t.Errorf("Expected a nodes quota error, got %v", err)
+	}
+
+	// once reset the usage is reloaded from the registry, releasing the unregistered nodes
+	registry.Register(node("bar-1"))
+	r.usage.reset(goregistry.DefaultDomain)
+	if err := r.reserveQuota(goregistry.DefaultDomain, node("bar-3")); err != nil {
+		t.Errorf("Expected the reservations to be released, got %v", err)
+	}
+
+	// the namespace quota overrides the default
+	registry.Register(node("bar-1"), goregistry.RegisterDomain("foo"))
+	if err := r.reserveQuota("foo", node("bar-2")); err != nil {
+		t.Errorf("Expected the override to replace the default quota, got %v", err)
+	}
+	srv := &goregistry.Service{Name: "baz", Version: "latest"}
+	if err := r.reserveQuota("foo", srv); err == nil {
+		t.Errorf("Expected the services quota to be exceeded")
+	}
+}
+
+func TestParseQuota(t *testing.T) {
+	ns, q, err := ParseQuota("foo=services:10,nodes:100,endpoints:1000")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing quota: %v", err)
+	}
+	if ns != "foo" || q != (Quota{Services: 10, Nodes: 100, Endpoints: 1000}) {
+		t.Errorf("Unexpected quota %v for %v", q, ns)
+	}
+
+	for _, s := range []string{"foo", "=services:1", "foo=services", "foo=services:-1", "foo=tables:1"} {
+		if _, _, err := ParseQuota(s); err == nil {
+			t.Errorf("Expected an error parsing %v", s)
+		}
+	}
+}
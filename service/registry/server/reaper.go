@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/service/client"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+var (
+	// DefaultProbeTimeout is how long a probe waits for a node to respond
+	DefaultProbeTimeout = time.Second * 2
+	// DefaultProbeFailures is the number of consecutive probes a node must fail to be removed
+	DefaultProbeFailures = 3
+	// maxConcurrentProbes is the number of nodes probed at once
+	maxConcurrentProbes = 32
+)
+
+// ProbeFunc checks the node at the address is reachable
+type ProbeFunc func(service, address string, timeout time.Duration) error
+
+// ProbeTCP checks a connection can be established to the node
+func ProbeTCP(service, address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// ProbeHealth checks the node responds to a health check
+func ProbeHealth(service, address string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req := client.NewRequest(service, "Debug.Health", &debug.HealthRequest{})
+	rsp := &debug.HealthResponse{}
+	if err := client.Call(ctx, req, rsp, goclient.WithAddress(address), goclient.WithRetries(0)); err != nil {
+		return err
+	}
+	if rsp.Status != "ok" {
+		return fmt.Errorf("status %v", rsp.Status)
+	}
+	return nil
+}
+
+// reaper probes the registered nodes and removes those which are unreachable, rather than
+// waiting for their registrations to expire
+type reaper struct {
+	probe    ProbeFunc
+	interval time.Duration
+	timeout  time.Duration
+	failures int
+	// remove the nodes from the registry
+	remove func(domain string, services []*pb.Service)
+
+	sync.Mutex
+	// failed is the number of consecutive probes each node has failed
+	failed map[string]int
+}
+
+func newReaper(probe ProbeFunc, interval, timeout time.Duration, failures int, remove func(string, []*pb.Service)) *reaper {
+	return &reaper{
+		probe:    probe,
+		interval: interval,
+		timeout:  timeout,
+		failures: failures,
+		remove:   remove,
+		failed:   make(map[string]int),
+	}
+}
+
+// run the reaper until the registry stops
+func (r *reaper) run() {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for range t.C {
+		if err := r.reap(); err != nil {
+			log.Errorf("Error probing nodes: %v", err)
+		}
+	}
+}
+
+// reap probes each of the nodes and removes those which have failed too many probes
+func (r *reaper) reap() error {
+	services, err := registry.ListServices(goregistry.ListDomain(goregistry.WildcardDomain))
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentProbes)
+	seen := make(map[string]bool)
+
+	for _, srv := range services {
+		domain := goregistry.DefaultDomain
+		if d, ok := srv.Metadata["domain"]; ok && len(d) > 0 {
+			domain = d
+		}
+
+		for _, node := range srv.Nodes {
+			if !util.Probed(node) || len(node.Address) == 0 {
+				continue
+			}
+
+			key := domain + "/" + srv.Name + "/" + srv.Version + "/" + node.Id
+			seen[key] = true
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(domain, key string, srv *goregistry.Service, node *goregistry.Node) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				err := r.probe(srv.Name, node.Address, r.timeout)
+				if !r.record(key, err) {
+					return
+				}
+
+				log.Infof("Removing node %v of %v at %v after %d failed probes: %v", node.Id, srv.Name, node.Address, r.failures, err)
+				pbSrv := util.ToProto(&goregistry.Service{
+					Name:    srv.Name,
+					Version: srv.Version,
+					Nodes:   []*goregistry.Node{node},
+				})
+				pbSrv.Options.Domain = domain
+				r.remove(domain, []*pb.Service{util.MarkRemoved(pbSrv, util.RemovalUnhealthy)})
+			}(domain, key, srv, node)
+		}
+	}
+
+	wg.Wait()
+
+	// forget the nodes which are no longer registered
+	r.Lock()
+	for key := range r.failed {
+		if !seen[key] {
+			delete(r.failed, key)
+		}
+	}
+	r.Unlock()
+
+	return nil
+}
+
+// record the result of a probe, returning true if the node should be removed
+func (r *reaper) record(key string, err error) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if err == nil {
+		delete(r.failed, key)
+		return false
+	}
+
+	r.failed[key]++
+	if r.failed[key] < r.failures {
+		return false
+	}
+	delete(r.failed, key)
+	return true
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+func TestReaper(t *testing.T) {
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	registry.Register(&goregistry.Service{Name: "foo", Version: "latest", Nodes: []*goregistry.Node{
+		{Id: "foo-1", Address: "10.0.0.1:8080"},
+		{Id: "foo-2", Address: "10.0.0.2:8080"},
+		{Id: "foo-3", Address: "10.0.0.3:8080", Metadata: map[string]string{util.ProbeKey: "false"}},
+	}})
+
+	probe := func(service, address string, timeout time.Duration) error {
+		if address == "10.0.0.1:8080" {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+
+	var removed []string
+	remove := func(domain string, services []*pb.Service) {
+		for _, srv := range services {
+			for _, n := range srv.Nodes {
+				removed = append(removed, n.Id)
+			}
+		}
+	}
+
+	r := newReaper(probe, time.Second, time.Second, 2, remove)
+
+	// the node is only removed after failing consecutive probes
+	if err := r.reap(); err != nil {
+		t.Fatalf("Unexpected error reaping: %v", err)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("Expected no nodes to be removed after a single failure, got %v", removed)
+	}
+	if err := r.reap(); err != nil {
+		t.Fatalf("Unexpected error reaping: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "foo-2" {
+		t.Fatalf("Expected foo-2 to be removed, got %v", removed)
+	}
+}
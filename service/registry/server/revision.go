@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+// Revision returns the revision of the last change made to the domain
+func (r *Registry) Revision(ctx context.Context, req *pb.RevisionRequest, rsp *pb.RevisionResponse) error {
+	// parse the options
+	var domain string
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request
+	publicNS := namespace.Public(goregistry.DefaultDomain)
+	if err := namespace.Authorize(ctx, domain, publicNS); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.Revision", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.Revision", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.Revision", err.Error())
+	}
+
+	rev, err := currentRevision(domain)
+	if err != nil {
+		return errors.InternalServerError("registry.Registry.Revision", "Error reading changelog: %v", err)
+	}
+	rsp.Revision = rev
+	return nil
+}
+
+// checkRevision returns a conflict error if the read isn't consistent with the revision
+// requested. It's called after the read so changes made during the read are detected.
+func checkRevision(id string, opts *pb.Options, domain string, services ...string) error {
+	if opts == nil || opts.Revision == 0 {
+		return nil
+	}
+
+	changed, err := changedSince(domain, opts.Revision, services...)
+	if err != nil {
+		return errors.InternalServerError(id, "Error reading changelog: %v", err)
+	}
+	if changed {
+		return errors.Conflict(id, "registry has changed since revision %d", opts.Revision)
+	}
+	return nil
+}
+
+// recordChange appends the change to the changelog, advancing the revision of the domain, and
+// broadcasts it to the watchers. It returns the sequence of the change, zero if it couldn't be
+// recorded.
+func (r *Registry) recordChange(domain, action string, service *pb.Service) int64 {
+	seq, err := nextSequence(domain)
+	if err != nil {
+		log.Errorf("Error recording %v of %v: %v", action, service.Name, err)
+		return 0
+	}
+	res := &pb.Result{
+		Action:    action,
+		Service:   service,
+		Timestamp: time.Now().Unix(),
+		Sequence:  seq,
+	}
+	appendChangelog(domain, res)
+	r.watchers.broadcast(domain, res)
+	return seq
+}
+
+// currentRevision returns the revision of the last change to the domain
+func currentRevision(domain string) (int64, error) {
+	return currentSequence(domain)
+}
+
+// changedSince returns true if any of the services have changed since the revision, or any
+// service in the domain if none are given
+func changedSince(domain string, revision int64, services ...string) (bool, error) {
+	rev, err := currentRevision(domain)
+	if err != nil || rev <= revision {
+		return false, err
+	}
+	if len(services) == 0 {
+		return true, nil
+	}
+
+	changes, err := readChangelog(domain, "", revision)
+	if err != nil {
+		return false, err
+	}
+
+	// changes older than the retention are removed from the changelog, so if the change after
+	// the revision is missing the services may have changed
+	if len(changes) == 0 || changes[0].Sequence > revision+1 {
+		return true, nil
+	}
+
+	for _, c := range changes {
+		if c.Service == nil {
+			continue
+		}
+		for _, name := range services {
+			if c.Service.Name == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestRevisions(t *testing.T) {
+	registry.DefaultRegistry = memory.NewRegistry()
+	store.DefaultStore = memstore.NewStore()
+
+	r := &Registry{}
+	domain := goregistry.DefaultDomain
+
+	register := func(name, id string) {
+		srv := util.ToProto(&goregistry.Service{
+			Name: name, Version: "latest", Nodes: []*goregistry.Node{{Id: id, Address: "10.0.0.1:8080"}},
+		})
+		registry.Register(util.ToService(srv))
+		if changed, _ := renewLeases(domain, srv, 0); changed {
+			r.recordChange(domain, "create", srv)
+		}
+	}
+
+	register("foo", "foo-1")
+	rev, err := currentRevision(domain)
+	if err != nil || rev == 0 {
+		t.Fatalf("Expected a revision, got %v: %v", rev, err)
+	}
+
+	// re-registering the same node doesn't change the revision
+	register("foo", "foo-1")
+	if cur, _ := currentRevision(domain); cur != rev {
+		t.Fatalf("Expected re-registering not to change the revision, got %v", cur)
+	}
+
+	opts := &pb.Options{Revision: rev}
+	if err := checkRevision("test", opts, domain, "foo"); err != nil {
+		t.Fatalf("Expected the read to be consistent, got %v", err)
+	}
+
+	// a change to another service only conflicts with reads of the whole domain
+	register("bar", "bar-1")
+	if cur, _ := currentRevision(domain); cur <= rev {
+		t.Fatalf("Expected the revision to increase, got %v", cur)
+	}
+	if err := checkRevision("test", opts, domain, "foo"); err != nil {
+		t.Errorf("Expected the read of foo to be consistent, got %v", err)
+	}
+	if err := checkRevision("test", opts, domain); err == nil {
+		t.Errorf("Expected the list to conflict with the revision")
+	}
+
+	register("foo", "foo-2")
+	if err := checkRevision("test", opts, domain, "foo"); err == nil {
+		t.Errorf("Expected the read of foo to conflict with the revision")
+	}
+
+	// reads at a revision whose changes have been removed from the changelog conflict
+	cur, _ := currentRevision(domain)
+	if err := store.Delete(changelogKey(domain, cur)); err != nil {
+		t.Fatalf("Unexpected error deleting the change: %v", err)
+	}
+	if err := checkRevision("test", &pb.Options{Revision: cur - 1}, domain, "bar"); err == nil {
+		t.Errorf("Expected the read to conflict once the changes are removed")
+	}
+}
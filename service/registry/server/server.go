@@ -6,10 +6,13 @@ import (
 
 	"github.com/micro/cli/v2"
 	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/store"
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry/kubernetes"
 	pb "github.com/micro/micro/v3/service/registry/proto"
 	"github.com/micro/micro/v3/service/registry/util"
+	mustore "github.com/micro/micro/v3/service/store"
 )
 
 var (
@@ -21,6 +24,88 @@ var (
 	topic = "registry.events"
 )
 
+// Flags specific to the registry service
+var Flags = []cli.Flag{
+	&cli.IntFlag{
+		Name:    "max_services",
+		Usage:   "Set the number of services each namespace can register, 0 for no limit",
+		EnvVars: []string{"MICRO_REGISTRY_MAX_SERVICES"},
+	},
+	&cli.IntFlag{
+		Name:    "max_nodes",
+		Usage:   "Set the number of nodes each namespace can register, 0 for no limit",
+		EnvVars: []string{"MICRO_REGISTRY_MAX_NODES"},
+	},
+	&cli.IntFlag{
+		Name:    "max_endpoints",
+		Usage:   "Set the number of endpoints each namespace can register, 0 for no limit",
+		EnvVars: []string{"MICRO_REGISTRY_MAX_ENDPOINTS"},
+	},
+	&cli.StringSliceFlag{
+		Name:    "quota",
+		Usage:   "Set the quota of a namespace, overriding the limits above, e.g. --quota foo=services:10,nodes:100,endpoints:1000",
+		EnvVars: []string{"MICRO_REGISTRY_QUOTA"},
+	},
+	&cli.DurationFlag{
+		Name:    "probe_interval",
+		Usage:   "Set the interval the registered nodes are probed at, removing those which are unreachable. 0 disables probes",
+		EnvVars: []string{"MICRO_REGISTRY_PROBE_INTERVAL"},
+	},
+	&cli.StringFlag{
+		Name:    "probe_type",
+		Usage:   "Set how nodes are probed; {tcp, health}",
+		EnvVars: []string{"MICRO_REGISTRY_PROBE_TYPE"},
+		Value:   "tcp",
+	},
+	&cli.DurationFlag{
+		Name:    "probe_timeout",
+		Usage:   "Set how long a probe waits for a node to respond",
+		EnvVars: []string{"MICRO_REGISTRY_PROBE_TIMEOUT"},
+		Value:   DefaultProbeTimeout,
+	},
+	&cli.IntFlag{
+		Name:    "probe_failures",
+		Usage:   "Set the number of consecutive probes a node must fail to be removed",
+		EnvVars: []string{"MICRO_REGISTRY_PROBE_FAILURES"},
+		Value:   DefaultProbeFailures,
+	},
+	&cli.StringFlag{
+		Name:    "conflict_policy",
+		Usage:   "Set how registrations of a node id already registered by another process are resolved, unless the node sets a policy; {lww, reject, merge, clock}",
+		EnvVars: []string{"MICRO_REGISTRY_CONFLICT_POLICY"},
+		Value:   DefaultConflictPolicy,
+	},
+	&cli.DurationFlag{
+		Name:    "kubernetes_sync_interval",
+		Usage:   "Set the interval the registry is synced with kubernetes services at when running in kubernetes. 0 disables the sync",
+		EnvVars: []string{"MICRO_REGISTRY_KUBERNETES_SYNC_INTERVAL"},
+	},
+	&cli.StringFlag{
+		Name:    "kubernetes_namespace",
+		Usage:   "Set the kubernetes namespace the registry is synced with",
+		EnvVars: []string{"MICRO_REGISTRY_KUBERNETES_NAMESPACE"},
+		Value:   "default",
+	},
+	&cli.StringFlag{
+		Name:    "kubernetes_domain",
+		Usage:   "Set the registry domain synced with the kubernetes namespace",
+		EnvVars: []string{"MICRO_REGISTRY_KUBERNETES_DOMAIN"},
+		Value:   registry.DefaultDomain,
+	},
+	&cli.DurationFlag{
+		Name:    "tombstone_window",
+		Usage:   "Set how long the tombstones of deregistered services are kept. 0 disables tombstones",
+		EnvVars: []string{"MICRO_REGISTRY_TOMBSTONE_WINDOW"},
+		Value:   DefaultTombstoneWindow,
+	},
+	&cli.DurationFlag{
+		Name:    "tombstone_gc_interval",
+		Usage:   "Set the interval expired tombstones are removed at",
+		EnvVars: []string{"MICRO_REGISTRY_TOMBSTONE_GC_INTERVAL"},
+		Value:   DefaultTombstoneGCInterval,
+	},
+}
+
 // Sub processes registry events
 type subscriber struct {
 	// id is registry id
@@ -101,11 +186,83 @@ func Run(ctx *cli.Context) error {
 	// get server id
 	id := srv.Server().Options().Id
 
+	// the changelog is persisted in the registry table
+	mustore.DefaultStore.Init(store.Table("registry"))
+
+	// parse the quotas
+	quotas := map[string]Quota{}
+	for _, q := range ctx.StringSlice("quota") {
+		ns, quota, err := ParseQuota(q)
+		if err != nil {
+			log.Fatal(err)
+		}
+		quotas[ns] = quota
+	}
+
+	// set the default conflict policy
+	if p := ctx.String("conflict_policy"); len(p) > 0 {
+		if _, ok := ConflictPolicies[p]; !ok {
+			log.Fatalf("%v is not a valid conflict policy", p)
+		}
+		DefaultConflictPolicy = p
+	}
+
+	// set how long tombstones are kept
+	DefaultTombstoneWindow = ctx.Duration("tombstone_window")
+
 	// register the handler
-	pb.RegisterRegistryHandler(srv.Server(), &Registry{
+	reg := &Registry{
 		ID:    id,
 		Event: service.NewEvent(topic),
-	})
+		Quota: Quota{
+			Services:  ctx.Int("max_services"),
+			Nodes:     ctx.Int("max_nodes"),
+			Endpoints: ctx.Int("max_endpoints"),
+		},
+		Quotas: quotas,
+	}
+	pb.RegisterRegistryHandler(srv.Server(), reg)
+
+	// send the changes recorded by the other replicas to the watchers of this one
+	srv.Subscribe(topic, reg.processEvent)
+
+	// probe the registered nodes
+	if interval := ctx.Duration("probe_interval"); interval > 0 {
+		var probe ProbeFunc
+		switch t := ctx.String("probe_type"); t {
+		case "tcp":
+			probe = ProbeTCP
+		case "health":
+			probe = ProbeHealth
+		default:
+			log.Fatalf("%v is not a valid probe type", t)
+		}
+
+		rp := newReaper(probe, interval, ctx.Duration("probe_timeout"), ctx.Int("probe_failures"), reg.deregisterAll)
+		go rp.run()
+	}
+
+	// record the registrations which expire
+	go reg.watchLeases(DefaultLeaseInterval)
+
+	// remove the attributes of the nodes which are no longer registered
+	go reg.pruneAttributes(DefaultAttributePruneInterval)
+
+	// remove the expired tombstones, the store may not expire them itself
+	if interval := ctx.Duration("tombstone_gc_interval"); DefaultTombstoneWindow > 0 && interval > 0 {
+		go reg.gcTombstones(interval)
+	}
+
+	// sync the registry with the kubernetes services
+	if interval := ctx.Duration("kubernetes_sync_interval"); interval > 0 {
+		cluster, err := kubernetes.NewCluster(ctx.String("kubernetes_namespace"))
+		if err != nil {
+			log.Fatalf("Error connecting to kubernetes: %v", err)
+		}
+
+		ks := newKubeSync(cluster, id, ctx.String("kubernetes_domain"), interval, reg.registerAll, reg.deregisterAll)
+		go ks.run()
+	}
 
 	// run the service
 	if err := srv.Run(); err != nil {
@@ -0,0 +1,295 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	merrors "github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultSessionTTL is used when a session is created without a ttl
+	DefaultSessionTTL = time.Second * 30
+	// ErrSessionNotFound is returned when a session does not exist or has expired
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// sessionPrefix is prefixed to the keys of the sessions. The registrations bound to a session
+// are stored under the key of the session followed by a slash.
+const sessionPrefix = "session/"
+
+// Sessions are kept in the store so they outlive the registry and are shared by its replicas.
+// The registrations bound to a session are registered with its ttl and renewed when it's kept
+// alive, so they expire with the session even if nothing is left to remove them.
+
+func sessionKey(domain, id string) string {
+	return sessionPrefix + domain + "/" + id
+}
+
+func bindingKey(domain, id string, srv *pb.Service, node *pb.Node) string {
+	return sessionKey(domain, id) + "/" + srv.Name + "/" + srv.Version + "/" + node.Id
+}
+
+// readSession returns the session, or ErrSessionNotFound if it doesn't exist or has expired
+func readSession(domain, id string) (*pb.Session, error) {
+	recs, err := store.Read(sessionKey(domain, id))
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, ErrSessionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var sess *pb.Session
+	if err := json.Unmarshal(recs[0].Value, &sess); err != nil {
+		return nil, err
+	}
+
+	// not all stores remove records once they expire
+	if time.Unix(sess.Expiry, 0).Before(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+	return sess, nil
+}
+
+// writeSession writes the session, expiring it after its ttl
+func writeSession(domain string, sess *pb.Session) error {
+	bytes, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(sess.Ttl) * time.Second
+	return store.Write(&gostore.Record{Key: sessionKey(domain, sess.Id), Value: bytes, Expiry: ttl})
+}
+
+// readBindings returns the registrations bound to the session
+func readBindings(domain, id string) ([]*pb.Service, error) {
+	recs, err := store.Read(sessionKey(domain, id)+"/", gostore.ReadPrefix())
+	if err == gostore.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	services := make([]*pb.Service, 0, len(recs))
+	for _, rec := range recs {
+		var srv *pb.Service
+		if err := json.Unmarshal(rec.Value, &srv); err != nil {
+			return nil, err
+		}
+		services = append(services, srv)
+	}
+	return services, nil
+}
+
+// writeBinding binds a node of the service to the session, each node being written separately
+// so concurrent registrations don't overwrite each other
+func writeBinding(domain string, sess *pb.Session, srv *pb.Service) error {
+	bytes, err := json.Marshal(srv)
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(sess.Ttl) * time.Second
+	return store.Write(&gostore.Record{Key: bindingKey(domain, sess.Id, srv, srv.Nodes[0]), Value: bytes, Expiry: ttl})
+}
+
+// createSession creates a session which expires after the ttl
+func createSession(domain string, ttl time.Duration) (*pb.Session, error) {
+	if ttl < time.Second {
+		ttl = DefaultSessionTTL
+	}
+	sess := &pb.Session{
+		Id:     uuid.New().String(),
+		Ttl:    int64(ttl.Seconds()),
+		Expiry: time.Now().Add(ttl).Unix(),
+	}
+	return sess, writeSession(domain, sess)
+}
+
+// bindSession binds the registration to the session, returning the session
+func bindSession(domain, id string, srv *pb.Service) (*pb.Session, error) {
+	sess, err := readSession(domain, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range srv.Nodes {
+		b := &pb.Service{
+			Name:      srv.Name,
+			Version:   srv.Version,
+			Metadata:  srv.Metadata,
+			Endpoints: srv.Endpoints,
+			Nodes:     []*pb.Node{n},
+			Options:   &pb.Options{Domain: domain, Session: id},
+		}
+		if err := writeBinding(domain, sess, b); err != nil {
+			return nil, err
+		}
+	}
+	return sess, nil
+}
+
+// unbindSession removes the registration from the session
+func unbindSession(domain, id string, srv *pb.Service) error {
+	for _, n := range srv.Nodes {
+		if err := store.Delete(bindingKey(domain, id, srv, n)); err != nil && err != gostore.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepAlive extends the expiry of the session and the registrations bound to it by its ttl
+func keepAlive(domain, id string) (*pb.Session, error) {
+	sess, err := readSession(domain, id)
+	if err != nil {
+		return nil, err
+	}
+	services, err := readBindings(domain, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(sess.Ttl) * time.Second
+	sess.Expiry = time.Now().Add(ttl).Unix()
+	if err := writeSession(domain, sess); err != nil {
+		return nil, err
+	}
+	for _, srv := range services {
+		if err := registry.Register(util.ToService(srv), goregistry.RegisterTTL(ttl), goregistry.RegisterDomain(domain)); err != nil {
+			return nil, err
+		}
+		if err := writeBinding(domain, sess, srv); err != nil {
+			return nil, err
+		}
+	}
+	return sess, nil
+}
+
+// revokeSession removes the session, returning the registrations bound to it
+func revokeSession(domain, id string) ([]*pb.Service, error) {
+	if _, err := readSession(domain, id); err != nil {
+		return nil, err
+	}
+	services, err := readBindings(domain, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Delete(sessionKey(domain, id)); err != nil && err != gostore.ErrNotFound {
+		return nil, err
+	}
+	for _, srv := range services {
+		if err := store.Delete(bindingKey(domain, id, srv, srv.Nodes[0])); err != nil && err != gostore.ErrNotFound {
+			return nil, err
+		}
+	}
+	return services, nil
+}
+
+// deregisterAll removes the registrations, e.g. of an expired session
+func (r *Registry) deregisterAll(domain string, services []*pb.Service) {
+	r.deregisterAllWithContext(context.Background(), domain, services)
+}
+
+// deregisterAllWithContext removes the registrations, recording the account in the context as
+// having removed them
+func (r *Registry) deregisterAllWithContext(ctx context.Context, domain string, services []*pb.Service) {
+	for _, srv := range services {
+		if err := registry.Deregister(util.ToService(srv), goregistry.DeregisterDomain(domain)); err != nil {
+			log.Errorf("Error deregistering %v: %v", srv.Name, err)
+			continue
+		}
+		if err := removeLeases(domain, srv); err != nil {
+			log.Errorf("Error removing the leases of %v: %v", srv.Name, err)
+		}
+		r.audit(ctx, domain, "delete", srv)
+		r.tombstone(ctx, domain, srv)
+		go r.publishEvent("delete", srv)
+	}
+}
+
+// CreateSession which registrations can be bound to
+func (r *Registry) CreateSession(ctx context.Context, req *pb.CreateSessionRequest, rsp *pb.CreateSessionResponse) error {
+	domain := goregistry.DefaultDomain
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, domain); err == namespace.ErrForbidden {
+		return merrors.Forbidden("registry.Registry.CreateSession", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return merrors.Unauthorized("registry.Registry.CreateSession", err.Error())
+	} else if err != nil {
+		return merrors.InternalServerError("registry.Registry.CreateSession", err.Error())
+	}
+
+	sess, err := createSession(domain, time.Duration(req.Ttl)*time.Second)
+	if err != nil {
+		return merrors.InternalServerError("registry.Registry.CreateSession", err.Error())
+	}
+	rsp.Session = sess
+	return nil
+}
+
+// KeepAlive extends the expiry of a session
+func (r *Registry) KeepAlive(ctx context.Context, req *pb.KeepAliveRequest, rsp *pb.KeepAliveResponse) error {
+	domain := goregistry.DefaultDomain
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, domain); err == namespace.ErrForbidden {
+		return merrors.Forbidden("registry.Registry.KeepAlive", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return merrors.Unauthorized("registry.Registry.KeepAlive", err.Error())
+	} else if err != nil {
+		return merrors.InternalServerError("registry.Registry.KeepAlive", err.Error())
+	}
+
+	sess, err := keepAlive(domain, req.Id)
+	if err == ErrSessionNotFound {
+		return merrors.NotFound("registry.Registry.KeepAlive", err.Error())
+	} else if err != nil {
+		return merrors.InternalServerError("registry.Registry.KeepAlive", err.Error())
+	}
+	rsp.Session = sess
+	return nil
+}
+
+// RevokeSession immediately removes a session and all the registrations bound to it
+func (r *Registry) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest, rsp *pb.RevokeSessionResponse) error {
+	domain := goregistry.DefaultDomain
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, domain); err == namespace.ErrForbidden {
+		return merrors.Forbidden("registry.Registry.RevokeSession", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return merrors.Unauthorized("registry.Registry.RevokeSession", err.Error())
+	} else if err != nil {
+		return merrors.InternalServerError("registry.Registry.RevokeSession", err.Error())
+	}
+
+	services, err := revokeSession(domain, req.Id)
+	if err == ErrSessionNotFound {
+		return merrors.NotFound("registry.Registry.RevokeSession", err.Error())
+	} else if err != nil {
+		return merrors.InternalServerError("registry.Registry.RevokeSession", err.Error())
+	}
+	r.deregisterAllWithContext(ctx, domain, services)
+	return nil
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestSessions(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	registry.DefaultRegistry = memory.NewRegistry()
+
+	domain := goregistry.DefaultDomain
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Issuer: domain})
+
+	create := &pb.CreateSessionResponse{}
+	if err := new(Registry).CreateSession(ctx, &pb.CreateSessionRequest{Ttl: 1}, create); err != nil {
+		t.Fatalf("Unexpected error creating session: %v", err)
+	}
+	id := create.Session.Id
+
+	srv := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1"}, {Id: "foo-2"}}}
+	if _, err := bindSession(domain, id, srv); err != nil {
+		t.Fatalf("Unexpected error binding to session: %v", err)
+	}
+	if _, err := bindSession(domain, "missing", srv); err != ErrSessionNotFound {
+		t.Errorf("Expected binding to an unknown session to fail, got %v", err)
+	}
+	if _, err := bindSession("other", id, srv); err != ErrSessionNotFound {
+		t.Errorf("Expected binding to a session of another domain to fail, got %v", err)
+	}
+
+	// sessions are read from the store, so any replica of the registry can keep them alive
+	keep := func() int32 {
+		rsp := &pb.KeepAliveResponse{}
+		if err := new(Registry).KeepAlive(ctx, &pb.KeepAliveRequest{Id: id}, rsp); err != nil {
+			return errors.Parse(err).Code
+		}
+		return 200
+	}
+	if code := keep(); code != 200 {
+		t.Fatalf("Expected the session to be kept alive, got %v", code)
+	}
+
+	// keeping the session alive renews the registrations bound to it
+	services, err := registry.GetService("foo")
+	if err != nil || len(services) != 1 || len(services[0].Nodes) != 2 {
+		t.Fatalf("Expected both nodes to be registered, got %v: %v", services, err)
+	}
+
+	// the registrations expire with the session
+	time.Sleep(time.Millisecond * 2500)
+	if code := keep(); code != 404 {
+		t.Errorf("Expected the session to have expired, got %v", code)
+	}
+	if services, _ := registry.GetService("foo"); len(services) > 0 && len(services[0].Nodes) > 0 {
+		t.Errorf("Expected the registrations to expire, got %v", services)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	domain := goregistry.DefaultDomain
+	sess, err := createSession(domain, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error creating session: %v", err)
+	}
+
+	foo := &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-1"}, {Id: "foo-2"}}}
+	bar := &pb.Service{Name: "bar", Version: "latest", Nodes: []*pb.Node{{Id: "bar-1"}}}
+	for _, srv := range []*pb.Service{foo, bar} {
+		if _, err := bindSession(domain, sess.Id, srv); err != nil {
+			t.Fatalf("Unexpected error binding to session: %v", err)
+		}
+	}
+
+	// deregistered nodes are no longer bound to the session
+	if err := unbindSession(domain, sess.Id, &pb.Service{Name: "foo", Version: "latest", Nodes: []*pb.Node{{Id: "foo-2"}}}); err != nil {
+		t.Fatalf("Unexpected error unbinding from session: %v", err)
+	}
+
+	if _, err := revokeSession("other", sess.Id); err != ErrSessionNotFound {
+		t.Errorf("Expected revoking a session of another domain to fail, got %v", err)
+	}
+	services, err := revokeSession(domain, sess.Id)
+	if err != nil {
+		t.Fatalf("Unexpected error revoking session: %v", err)
+	}
+	nodes := map[string]bool{}
+	for _, srv := range services {
+		nodes[srv.Nodes[0].Id] = true
+	}
+	if len(nodes) != 2 || !nodes["foo-1"] || !nodes["bar-1"] {
+		t.Errorf("Expected foo-1 and bar-1 to be revoked, got %v", nodes)
+	}
+
+	if _, err := readSession(domain, sess.Id); err != ErrSessionNotFound {
+		t.Errorf("Expected the session to be removed, got %v", err)
+	}
+	if bindings, _ := readBindings(domain, sess.Id); len(bindings) != 0 {
+		t.Errorf("Expected the bindings to be removed, got %v", bindings)
+	}
+}
@@ -0,0 +1,333 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+var (
+	// DefaultTombstoneWindow is how long the tombstones of deregistered services are kept, so
+	// a service which is restarting can be told apart from one which is gone. Zero disables
+	// tombstones.
+	DefaultTombstoneWindow = time.Hour
+	// DefaultTombstoneGCInterval is the interval expired tombstones are removed at
+	DefaultTombstoneGCInterval = time.Minute * 5
+)
+
+const tombstonePrefix = "tombstone/"
+
+// maxTombstoneAttempts is the number of times a tombstone is updated before giving up, if other
+// replicas of the registry keep updating it in the meantime
+const maxTombstoneAttempts = 10
+
+// tombstoneKey returns the key of the tombstone of the version of the service
+func tombstoneKey(domain, service, version string) string {
+	return tombstonePrefix + domain + "/" + service + "/" + version
+}
+
+// readTombstone returns the tombstone of the version of the service, or nil if there's none,
+// and the etag of the record it's stored in, which is blank if there's no record
+func readTombstone(key string) (*pb.Tombstone, string, error) {
+	recs, err := store.Read(key)
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	etag := bulk.ETag(recs[0].Value)
+	var t *pb.Tombstone
+	if err := json.Unmarshal(recs[0].Value, &t); err != nil {
+		return nil, etag, err
+	}
+
+	// not all stores remove records once they expire, so expired tombstones are ignored until
+	// they're collected
+	if expired(t, time.Now()) {
+		return nil, etag, nil
+	}
+	return t, etag, nil
+}
+
+// expired returns true if the tombstone is older than the tombstone window
+func expired(t *pb.Tombstone, now time.Time) bool {
+	return time.Unix(t.Deleted, 0).Add(DefaultTombstoneWindow).Before(now)
+}
+
+// writeTombstone writes the tombstone, deleting it if none of its nodes are left. It's only
+// written if the record it's stored in still has the etag, or doesn't exist if the etag is
+// blank, since the replicas of the registry share the store. Returns false if the record was
+// changed since it was read.
+func writeTombstone(key string, t *pb.Tombstone, etag string) (bool, error) {
+	if len(t.Service.Nodes) == 0 {
+		if len(etag) == 0 {
+			return true, nil
+		}
+		return bulk.Delete(store.DefaultStore, key, etag)
+	}
+
+	bytes, err := json.Marshal(t)
+	if err != nil {
+		return false, err
+	}
+
+	// the window restarts on each deregistration
+	upsert := &bulk.Upsert{
+		Record:    &gostore.Record{Key: key, Value: bytes, Expiry: DefaultTombstoneWindow},
+		Condition: bulk.Condition{ETag: etag, NotExists: len(etag) == 0},
+	}
+	results, err := store.BulkUpsert([]*bulk.Upsert{upsert})
+	if err != nil {
+		return false, err
+	} else if len(results) != 1 {
+		return false, fmt.Errorf("expected 1 result writing tombstone, got %v", len(results))
+	} else if len(results[0].Error) > 0 {
+		return false, fmt.Errorf("error writing tombstone: %v", results[0].Error)
+	}
+	return results[0].Written, nil
+}
+
+// updateTombstone reads the tombstone and writes it back once it's updated, reading it again
+// if it was changed by another replica of the registry in the meantime. Update is passed nil
+// if there's no tombstone and returns nil if the tombstone shouldn't be written.
+func updateTombstone(key string, update func(t *pb.Tombstone) *pb.Tombstone) error {
+	for i := 0; i < maxTombstoneAttempts; i++ {
+		t, etag, err := readTombstone(key)
+		if err != nil {
+			return err
+		}
+		if t = update(t); t == nil {
+			return nil
+		}
+		if ok, err := writeTombstone(key, t, etag); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("tombstone changed %d times while it was being updated", maxTombstoneAttempts)
+}
+
+// tombstone adds the nodes of the service to the tombstone of its version, recording the
+// account in the context as having deregistered them
+func (r *Registry) tombstone(ctx context.Context, domain string, srv *pb.Service) {
+	if DefaultTombstoneWindow <= 0 || len(srv.Nodes) == 0 {
+		return
+	}
+
+	key := tombstoneKey(domain, srv.Name, srv.Version)
+	err := updateTombstone(key, func(t *pb.Tombstone) *pb.Tombstone {
+		if t == nil {
+			t = &pb.Tombstone{Service: &pb.Service{Name: srv.Name, Version: srv.Version}}
+		}
+
+		// the metadata and endpoints are those the service was last registered with
+		t.Service.Metadata = srv.Metadata
+		t.Service.Endpoints = srv.Endpoints
+		t.Service.Nodes = mergeNodes(t.Service.Nodes, srv.Nodes)
+		t.Deleted = time.Now().Unix()
+		t.Account = ""
+		t.Issuer = ""
+		if acc, ok := auth.AccountFromContext(ctx); ok {
+			t.Account = acc.ID
+			t.Issuer = acc.Issuer
+		}
+		return t
+	})
+	if err != nil {
+		log.Errorf("Error writing tombstone of %v: %v", srv.Name, err)
+	}
+}
+
+// clearTombstone removes the nodes of the service which registered again from the tombstone of
+// its version
+func (r *Registry) clearTombstone(domain string, srv *pb.Service) {
+	if DefaultTombstoneWindow <= 0 {
+		return
+	}
+
+	registered := make(map[string]bool, len(srv.Nodes))
+	for _, n := range srv.Nodes {
+		registered[n.Id] = true
+	}
+
+	key := tombstoneKey(domain, srv.Name, srv.Version)
+	err := updateTombstone(key, func(t *pb.Tombstone) *pb.Tombstone {
+		if t == nil {
+			return nil
+		}
+
+		var nodes []*pb.Node
+		for _, n := range t.Service.Nodes {
+			if !registered[n.Id] {
+				nodes = append(nodes, n)
+			}
+		}
+		if len(nodes) == len(t.Service.Nodes) {
+			return nil
+		}
+		t.Service.Nodes = nodes
+		return t
+	})
+	if err != nil {
+		log.Errorf("Error writing tombstone of %v: %v", srv.Name, err)
+	}
+}
+
+// mergeNodes returns the nodes with the additions, replacing nodes with the same id
+func mergeNodes(nodes, add []*pb.Node) []*pb.Node {
+	for _, n := range add {
+		var found bool
+		for i, e := range nodes {
+			if e.Id == n.Id {
+				nodes[i] = n
+				found = true
+				break
+			}
+		}
+		if !found {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// readTombstones returns the tombstones in the domain, most recently deleted first. If service
+// is not blank, only the tombstones of the service are returned.
+func readTombstones(domain, service string) ([]*pb.Tombstone, error) {
+	prefix := tombstonePrefix + domain + "/"
+	if len(service) > 0 {
+		prefix += service + "/"
+	}
+	keys, err := store.List(gostore.ListPrefix(prefix))
+	if err != nil && err != gostore.ErrNotFound {
+		return nil, err
+	}
+
+	var tombstones []*pb.Tombstone
+	for _, k := range keys {
+		t, _, err := readTombstone(k)
+		if err != nil {
+			return nil, err
+		} else if t == nil {
+			// the tombstone expired since the keys were listed
+			continue
+		}
+		tombstones = append(tombstones, t)
+	}
+
+	sort.SliceStable(tombstones, func(i, j int) bool {
+		return tombstones[i].Deleted > tombstones[j].Deleted
+	})
+	return tombstones, nil
+}
+
+// notFound returns the error for a service which isn't registered. Services deregistered within
+// the tombstone window are gone rather than not found, so callers can tell a service which was
+// just removed from one which never existed.
+func notFound(id, domain, service string) error {
+	tombstones, err := readTombstones(domain, service)
+	if err != nil {
+		log.Errorf("Error reading tombstones of %v: %v", service, err)
+	}
+	if len(tombstones) == 0 {
+		return errors.NotFound(id, goregistry.ErrNotFound.Error())
+	}
+	deleted := time.Unix(tombstones[0].Deleted, 0)
+	return errors.Gone(id, "service %v was deregistered at %v", service, deleted.Format(time.RFC3339))
+}
+
+// collectTombstones removes the tombstones which have expired, returning the number removed
+func (r *Registry) collectTombstones() (int, error) {
+	keys, err := store.List(gostore.ListPrefix(tombstonePrefix))
+	if err != nil && err != gostore.ErrNotFound {
+		return 0, err
+	}
+
+	var removed int
+	now := time.Now()
+	for _, k := range keys {
+		ok, err := r.collectTombstone(k, now)
+		if err != nil {
+			return removed, err
+		} else if ok {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// collectTombstone removes the tombstone if it has expired, returning true if it was removed.
+// It's only removed if it's unchanged, since another replica of the registry may have updated
+// it after it was read.
+func (r *Registry) collectTombstone(key string, now time.Time) (bool, error) {
+	recs, err := store.Read(key)
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	var t *pb.Tombstone
+	if err := json.Unmarshal(recs[0].Value, &t); err == nil && !expired(t, now) {
+		return false, nil
+	}
+	// tombstones which can't be decoded are removed too
+	return bulk.Delete(store.DefaultStore, key, bulk.ETag(recs[0].Value))
+}
+
+// gcTombstones removes the expired tombstones at the interval, it blocks so should be called
+// in a goroutine
+func (r *Registry) gcTombstones(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n, err := r.collectTombstones(); err != nil {
+			log.Errorf("Error collecting tombstones: %v", err)
+		} else if n > 0 {
+			log.Debugf("Collected %v expired tombstones", n)
+		}
+	}
+}
+
+// ListDeleted returns the tombstones of the services deregistered within the tombstone window
+func (r *Registry) ListDeleted(ctx context.Context, req *pb.ListDeletedRequest, rsp *pb.ListDeletedResponse) error {
+	// parse the options
+	var domain string
+	if req.Options != nil && len(req.Options.Domain) > 0 {
+		domain = req.Options.Domain
+	} else {
+		domain = goregistry.DefaultDomain
+	}
+
+	// authorize the request
+	publicNS := namespace.Public(goregistry.DefaultDomain)
+	if err := namespace.Authorize(ctx, domain, publicNS); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Registry.ListDeleted", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Registry.ListDeleted", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Registry.ListDeleted", err.Error())
+	}
+
+	tombstones, err := readTombstones(domain, req.Service)
+	if err != nil {
+		return errors.InternalServerError("registry.Registry.ListDeleted", "Error reading tombstones: %v", err)
+	}
+	rsp.Tombstones = tombstones
+	return nil
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestTombstones(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	r := &Registry{}
+	domain := goregistry.DefaultDomain
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Issuer: domain})
+
+	srv := func(name string, nodes ...string) *pb.Service {
+		s := &pb.Service{Name: name, Version: "latest"}
+		for _, n := range nodes {
+			s.Nodes = append(s.Nodes, &pb.Node{Id: n})
+		}
+		return s
+	}
+
+	r.tombstone(ctx, domain, srv("payments", "payments-1"))
+	r.tombstone(context.Background(), domain, srv("payments", "payments-2"))
+	r.tombstone(ctx, domain, srv("users", "users-1"))
+	r.tombstone(ctx, "other", srv("payments", "payments-3"))
+
+	tombstones, err := readTombstones(domain, "payments")
+	if err != nil {
+		t.Fatalf("Unexpected error reading tombstones: %v", err)
+	}
+	if len(tombstones) != 1 {
+		t.Fatalf("Expected 1 tombstone, got %v", len(tombstones))
+	}
+	if ts := tombstones[0]; len(ts.Service.Nodes) != 2 || ts.Account != "" {
+		t.Errorf("Expected both nodes deregistered by the registry, got %v", ts)
+	}
+	if tombstones, _ := readTombstones(domain, ""); len(tombstones) != 2 {
+		t.Errorf("Expected 2 tombstones in the domain, got %v", len(tombstones))
+	}
+
+	// nodes which register again are removed from the tombstone
+	r.clearTombstone(domain, srv("payments", "payments-1"))
+	if tombstones, _ := readTombstones(domain, "payments"); len(tombstones) != 1 || tombstones[0].Service.Nodes[0].Id != "payments-2" {
+		t.Errorf("Expected the tombstone of payments-2 to remain, got %v", tombstones)
+	}
+	r.clearTombstone(domain, srv("payments", "payments-2"))
+	if tombstones, _ := readTombstones(domain, "payments"); len(tombstones) != 0 {
+		t.Errorf("Expected the tombstone to be removed, got %v", tombstones)
+	}
+
+	// tombstones aren't kept when the window is zero
+	defer func(w time.Duration) { DefaultTombstoneWindow = w }(DefaultTombstoneWindow)
+	DefaultTombstoneWindow = 0
+	r.tombstone(ctx, domain, srv("orders", "orders-1"))
+	if tombstones, _ := readTombstones(domain, "orders"); len(tombstones) != 0 {
+		t.Errorf("Expected no tombstones, got %v", tombstones)
+	}
+}
+
+func TestCollectTombstones(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	r := &Registry{}
+	domain := goregistry.DefaultDomain
+	r.tombstone(context.Background(), domain, &pb.Service{Name: "payments", Version: "latest", Nodes: []*pb.Node{{Id: "payments-1"}}})
+
+	// a service which was just deregistered is gone, one which never existed is not found
+	if verr := errors.Parse(notFound("registry", domain, "payments")); verr == nil || verr.Code != 410 {
+		t.Errorf("Expected payments to be gone, got %v", verr)
+	}
+	if verr := errors.Parse(notFound("registry", domain, "users")); verr == nil || verr.Code != 404 {
+		t.Errorf("Expected users to be not found, got %v", verr)
+	}
+
+	// write a tombstone older than the window, as a store which doesn't expire records would
+	old := &pb.Tombstone{
+		Service: &pb.Service{Name: "users", Version: "latest", Nodes: []*pb.Node{{Id: "users-1"}}},
+		Deleted: time.Now().Add(-DefaultTombstoneWindow * 2).Unix(),
+	}
+	if _, err := writeTombstone(tombstoneKey(domain, "users", "latest"), old, ""); err != nil {
+		t.Fatalf("Unexpected error writing tombstone: %v", err)
+	}
+	if tombstones, _ := readTombstones(domain, "users"); len(tombstones) != 0 {
+		t.Errorf("Expected the expired tombstone to be ignored, got %v", tombstones)
+	}
+
+	// tombstones are only written if they're unchanged since they were read, since the replicas
+	// of the registry share the store
+	if ok, err := writeTombstone(tombstoneKey(domain, "users", "latest"), old, ""); err != nil || ok {
+		t.Errorf("Expected the existing tombstone not to be overwritten, got %v: %v", ok, err)
+	}
+	if ok, err := writeTombstone(tombstoneKey(domain, "users", "latest"), old, "stale"); err != nil || ok {
+		t.Errorf("Expected the stale write to be rejected, got %v: %v", ok, err)
+	}
+
+	n, err := r.collectTombstones()
+	if err != nil {
+		t.Fatalf("Unexpected error collecting tombstones: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 tombstone to be collected, got %v", n)
+	}
+	if keys, _ := store.List(); len(keys) != 1 {
+		t.Errorf("Expected only the tombstone of payments to remain, got %v", keys)
+	}
+}
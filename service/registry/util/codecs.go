@@ -0,0 +1,72 @@
+package util
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro/v3/registry"
+)
+
+// CodecsKey is the node metadata key servers advertise the content types they accept with, so
+// clients can pick one the server understands
+const CodecsKey = "codecs"
+
+var (
+	// JSONCodecs are the content types the grpc server decodes as JSON, which any request
+	// type can be decoded from
+	JSONCodecs = []string{
+		"application/json",
+		"application/grpc+json",
+	}
+	// ProtoCodecs are the content types the grpc server decodes as protobuf, which only
+	// protobuf messages can be decoded from
+	ProtoCodecs = []string{
+		"application/grpc",
+		"application/grpc+proto",
+		"application/octet-stream",
+		"application/proto",
+		"application/protobuf",
+	}
+)
+
+var messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// HandlerCodecs returns the content types the requests of every endpoint of the handler can be
+// decoded from by the grpc server
+func HandlerCodecs(handler interface{}) []string {
+	typ := reflect.TypeOf(handler)
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if m.PkgPath != "" || m.Type.NumIn() != 4 {
+			continue
+		}
+		if !m.Type.In(2).Implements(messageType) {
+			return append([]string{}, JSONCodecs...)
+		}
+	}
+	return append(append([]string{}, JSONCodecs...), ProtoCodecs...)
+}
+
+// Codecs returns the content types the node accepts, nil if it doesn't advertise them
+func Codecs(n *registry.Node) []string {
+	v := n.Metadata[CodecsKey]
+	if len(v) == 0 {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// SetCodecs sets the content types the node accepts
+func SetCodecs(n *registry.Node, types []string) {
+	md := make(map[string]string, len(n.Metadata)+1)
+	for k, v := range n.Metadata {
+		md[k] = v
+	}
+
+	sorted := append([]string{}, types...)
+	sort.Strings(sorted)
+	md[CodecsKey] = strings.Join(sorted, ",")
+	n.Metadata = md
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+type protoHandler struct{}
+
+func (h *protoHandler) Get(ctx context.Context, req *pb.GetRequest, rsp *pb.GetResponse) error {
+	return nil
+}
+
+type mapHandler struct{}
+
+func (h *mapHandler) Get(ctx context.Context, req *pb.GetRequest, rsp *pb.GetResponse) error {
+	return nil
+}
+
+func (h *mapHandler) List(ctx context.Context, req map[string]string, rsp *pb.ListResponse) error {
+	return nil
+}
+
+func TestHandlerCodecs(t *testing.T) {
+	if c := HandlerCodecs(&protoHandler{}); len(c) != len(JSONCodecs)+len(ProtoCodecs) {
+		t.Errorf("Expected the json and protobuf codecs, got %v", c)
+	}
+	if c := HandlerCodecs(&mapHandler{}); len(c) != len(JSONCodecs) {
+		t.Errorf("Expected only the json codecs, got %v", c)
+	}
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+// CompressorsKey is the node metadata key servers advertise the compressors they can decode
+// requests with, so clients only compress requests to servers which understand them
+const CompressorsKey = "compressors"
+
+// Compressors returns the compressors the node accepts, nil if it doesn't advertise them
+func Compressors(n *registry.Node) []string {
+	v := n.Metadata[CompressorsKey]
+	if len(v) == 0 {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// SetCompressors sets the compressors the node accepts
+func SetCompressors(n *registry.Node, names []string) {
+	md := make(map[string]string, len(n.Metadata)+1)
+	for k, v := range n.Metadata {
+		md[k] = v
+	}
+
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	md[CompressorsKey] = strings.Join(sorted, ",")
+	n.Metadata = md
+}
@@ -0,0 +1,103 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+// Node metadata keys used to resolve conflicting registrations of the same node id
+const (
+	// ConflictPolicyKey is the policy used to resolve conflicts with the node, e.g. reject
+	ConflictPolicyKey = "conflict_policy"
+	// RegistrantKey identifies the process which registered the node, the address of the node
+	// is used if it's not set
+	RegistrantKey = "registrant"
+	// ClockKey is the vector clock of the registration, e.g. a=3,b=1
+	ClockKey = "clock"
+)
+
+// Registrant returns the identity of the process which registered the node
+func Registrant(n *pb.Node) string {
+	if r := n.Metadata[RegistrantKey]; len(r) > 0 {
+		return r
+	}
+	return n.Address
+}
+
+// Clock is a vector clock of the number of registrations made by each registrant
+type Clock map[string]int64
+
+// Ordering of two vector clocks
+type Ordering int
+
+const (
+	// Equal clocks
+	Equal Ordering = iota
+	// Before means the clock happened before the other
+	Before
+	// After means the clock happened after the other
+	After
+	// Concurrent clocks neither happened before the other
+	Concurrent
+)
+
+// ParseClock parses a clock in the format a=3,b=1, ignoring invalid entries
+func ParseClock(s string) Clock {
+	c := Clock{}
+	for _, part := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			continue
+		}
+		if v, err := strconv.ParseInt(kv[1], 10, 64); err == nil && v > 0 {
+			c[kv[0]] = v
+		}
+	}
+	return c
+}
+
+// String encodes the clock in the format parsed by ParseClock
+func (c Clock) String() string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + strconv.FormatInt(c[k], 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Compare the clock to another
+func (c Clock) Compare(o Clock) Ordering {
+	var before, after bool
+	for k, v := range c {
+		if v > o[k] {
+			after = true
+		} else if v < o[k] {
+			before = true
+		}
+	}
+	for k, v := range o {
+		if _, ok := c[k]; !ok && v > 0 {
+			before = true
+		}
+	}
+
+	switch {
+	case before && after:
+		return Concurrent
+	case before:
+		return Before
+	case after:
+		return After
+	default:
+		return Equal
+	}
+}
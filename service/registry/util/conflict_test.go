@@ -0,0 +1,29 @@
+package util
+
+import "testing"
+
+func TestClock(t *testing.T) {
+	tt := []struct {
+		A, B     string
+		Ordering Ordering
+	}{
+		{"", "", Equal},
+		{"a=1,b=2", "b=2,a=1", Equal},
+		{"a=1", "a=2", Before},
+		{"a=1", "a=1,b=1", Before},
+		{"a=2,b=1", "a=1", After},
+		{"a=2", "b=1", Concurrent},
+		{"a=2,b=1", "a=1,b=2", Concurrent},
+		{"a=invalid,b", "", Equal},
+	}
+
+	for _, tc := range tt {
+		if o := ParseClock(tc.A).Compare(ParseClock(tc.B)); o != tc.Ordering {
+			t.Errorf("Expected %q compared to %q to be %v, got %v", tc.A, tc.B, tc.Ordering, o)
+		}
+	}
+
+	if s := ParseClock("b=2, a=1").String(); s != "a=1,b=2" {
+		t.Errorf("Unexpected encoding of the clock %v", s)
+	}
+}
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"errors"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+// ErrGone is returned when getting a service which was deregistered within the tombstone window
+// of the registry, so it can be told apart from a service which never existed
+var ErrGone = errors.New("service has been deregistered")
+
+// IsNotFound returns true if the service isn't registered, whether it was deregistered recently
+// or never existed
+func IsNotFound(err error) bool {
+	return err == registry.ErrNotFound || err == ErrGone
+}
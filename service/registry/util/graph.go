@@ -0,0 +1,107 @@
+package util
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/micro/go-micro/v3/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+// DependenciesKey is the node metadata key services declare their upstream
+// dependencies under, as a comma separated list of service names
+const DependenciesKey = "dependencies"
+
+// Dependencies returns the declared dependencies of the service across all its nodes
+func Dependencies(s *registry.Service) []string {
+	seen := map[string]bool{}
+	var deps []string
+
+	for _, n := range s.Nodes {
+		for _, d := range strings.Split(n.Metadata[DependenciesKey], ",") {
+			d = strings.TrimSpace(d)
+			if len(d) == 0 || d == s.Name || seen[d] {
+				continue
+			}
+			seen[d] = true
+			deps = append(deps, d)
+		}
+	}
+
+	sort.Strings(deps)
+	return deps
+}
+
+// Graph builds the dependency graph of the services. If root is not blank, only
+// the services root transitively depends on or which transitively depend on root
+// are returned.
+func Graph(services []*registry.Service, root string) []*pb.GraphNode {
+	nodes := map[string]*pb.GraphNode{}
+	get := func(name string) *pb.GraphNode {
+		n, ok := nodes[name]
+		if !ok {
+			n = &pb.GraphNode{Name: name, Missing: true}
+			nodes[name] = n
+		}
+		return n
+	}
+
+	// services may be returned once per version so merge them by name
+	deps := map[string]map[string]bool{}
+	for _, s := range services {
+		get(s.Name).Missing = false
+		if deps[s.Name] == nil {
+			deps[s.Name] = map[string]bool{}
+		}
+		for _, d := range Dependencies(s) {
+			deps[s.Name][d] = true
+		}
+	}
+
+	for name, ds := range deps {
+		for d := range ds {
+			n := get(name)
+			n.Dependencies = append(n.Dependencies, d)
+			dn := get(d)
+			dn.Dependents = append(dn.Dependents, name)
+		}
+	}
+
+	// filter the nodes to those connected to the root
+	if len(root) > 0 {
+		upstream := map[string]bool{root: true}
+		walk(nodes, root, upstream, func(n *pb.GraphNode) []string { return n.Dependencies })
+		downstream := map[string]bool{root: true}
+		walk(nodes, root, downstream, func(n *pb.GraphNode) []string { return n.Dependents })
+
+		for name := range nodes {
+			if !upstream[name] && !downstream[name] {
+				delete(nodes, name)
+			}
+		}
+	}
+
+	result := make([]*pb.GraphNode, 0, len(nodes))
+	for _, n := range nodes {
+		sort.Strings(n.Dependencies)
+		sort.Strings(n.Dependents)
+		result = append(result, n)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// walk the graph from the node, marking every node reached via the edges
+func walk(nodes map[string]*pb.GraphNode, name string, seen map[string]bool, edges func(*pb.GraphNode) []string) {
+	n, ok := nodes[name]
+	if !ok {
+		return
+	}
+	for _, e := range edges(n) {
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		walk(nodes, e, seen, edges)
+	}
+}
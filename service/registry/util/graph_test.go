@@ -0,0 +1,60 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+func testService(name string, deps string) *registry.Service {
+	return &registry.Service{
+		Name: name,
+		Nodes: []*registry.Node{
+			{Id: name + "-1", Metadata: map[string]string{DependenciesKey: deps}},
+		},
+	}
+}
+
+func TestGraph(t *testing.T) {
+	services := []*registry.Service{
+		testService("web", "api"),
+		testService("api", "auth, store"),
+		testService("auth", "store"),
+		testService("store", ""),
+		testService("billing", "store"),
+		testService("events", ""),
+	}
+
+	t.Run("All", func(t *testing.T) {
+		nodes := Graph(services, "")
+		if len(nodes) != 6 {
+			t.Fatalf("Expected 6 nodes, got %v", len(nodes))
+		}
+		for _, n := range nodes {
+			if n.Name != "store" {
+				continue
+			}
+			if exp := []string{"api", "auth", "billing"}; !reflect.DeepEqual(n.Dependents, exp) {
+				t.Fatalf("Expected store dependents %v, got %v", exp, n.Dependents)
+			}
+		}
+	})
+
+	t.Run("Service", func(t *testing.T) {
+		var names []string
+		for _, n := range Graph(services, "auth") {
+			names = append(names, n.Name)
+		}
+		if exp := []string{"api", "auth", "store", "web"}; !reflect.DeepEqual(names, exp) {
+			t.Fatalf("Expected %v, got %v", exp, names)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		nodes := Graph([]*registry.Service{testService("web", "api")}, "")
+		if len(nodes) != 2 || nodes[0].Name != "api" || !nodes[0].Missing {
+			t.Fatalf("Expected api to be a missing dependency, got %v", nodes)
+		}
+	})
+}
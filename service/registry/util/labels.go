@@ -0,0 +1,120 @@
+package util
+
+import (
+	"math/rand"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+// VersionLabel matches the version of the service when resolving splits
+const VersionLabel = "version"
+
+// HasLabels returns true if the node has all the labels
+func HasLabels(n *pb.Node, labels map[string]string) bool {
+	for k, v := range labels {
+		if n.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterLabels returns the services with only the nodes which have all the labels. Services
+// without any such nodes are omitted.
+func FilterLabels(services []*pb.Service, labels map[string]string) []*pb.Service {
+	if len(labels) == 0 {
+		return services
+	}
+
+	filtered := make([]*pb.Service, 0, len(services))
+	for _, s := range services {
+		var nodes []*pb.Node
+		for _, n := range s.Nodes {
+			if HasLabels(n, labels) {
+				nodes = append(nodes, n)
+			}
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		srv := *s
+		srv.Nodes = nodes
+		filtered = append(filtered, &srv)
+	}
+	return filtered
+}
+
+// match returns true if the node of the service has all the labels
+func match(s *pb.Service, n *pb.Node, labels map[string]string) bool {
+	for k, v := range labels {
+		if k == VersionLabel && s.Version == v {
+			continue
+		}
+		if n.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve the nodes of the services and the share of traffic each should receive. The
+// weight of each split is shared equally between the nodes matching its labels, a node
+// matching more than one split is assigned to the first. Splits which match no nodes
+// are ignored so their traffic is shared between the rest. If no splits are provided,
+// every node receives an equal share.
+func Resolve(services []*pb.Service, splits []*pb.Split) []*pb.WeightedNode {
+	if len(splits) == 0 {
+		splits = []*pb.Split{{Weight: 1}}
+	}
+
+	type candidate struct {
+		service *pb.Service
+		node    *pb.Node
+	}
+	matches := make([][]candidate, len(splits))
+
+	for _, s := range services {
+		for _, n := range s.Nodes {
+			for i, sp := range splits {
+				if sp.Weight <= 0 || !match(s, n, sp.Labels) {
+					continue
+				}
+				matches[i] = append(matches[i], candidate{s, n})
+				break
+			}
+		}
+	}
+
+	var nodes []*pb.WeightedNode
+	for i, sp := range splits {
+		for _, c := range matches[i] {
+			nodes = append(nodes, &pb.WeightedNode{
+				Node:    c.node,
+				Version: c.service.Version,
+				Weight:  float64(sp.Weight) / float64(len(matches[i])),
+			})
+		}
+	}
+	return nodes
+}
+
+// Pick a node at random according to the weights
+func Pick(nodes []*pb.WeightedNode) *pb.WeightedNode {
+	var total float64
+	for _, n := range nodes {
+		total += n.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	r := rand.Float64() * total
+	for _, n := range nodes {
+		if r < n.Weight {
+			return n
+		}
+		r -= n.Weight
+	}
+	return nodes[len(nodes)-1]
+}
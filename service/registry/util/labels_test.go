@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func TestFilterLabels(t *testing.T) {
+	services := []*pb.Service{
+		{Name: "foo", Version: "v1", Nodes: []*pb.Node{{Id: "v1-1"}}},
+		{Name: "foo", Version: "v2", Nodes: []*pb.Node{
+			{Id: "v2-1", Labels: map[string]string{"canary": "false"}},
+			{Id: "v2-2", Labels: map[string]string{"canary": "true", "zone": "a"}},
+		}},
+	}
+
+	if s := FilterLabels(services, nil); len(s) != 2 {
+		t.Fatalf("Expected the services to be returned unfiltered, got %v", s)
+	}
+	s := FilterLabels(services, map[string]string{"canary": "true"})
+	if len(s) != 1 || len(s[0].Nodes) != 1 || s[0].Nodes[0].Id != "v2-2" {
+		t.Fatalf("Expected only the canary node, got %v", s)
+	}
+	if len(services[1].Nodes) != 2 {
+		t.Fatalf("Expected the services filtered not to be modified")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	canary := &pb.Node{Id: "canary-1", Labels: map[string]string{"canary": "true"}}
+
+	services := []*pb.Service{
+		{Name: "foo", Version: "v1", Nodes: []*pb.Node{{Id: "v1-1"}, {Id: "v1-2"}}},
+		{Name: "foo", Version: "v2", Nodes: []*pb.Node{{Id: "v2-1"}, canary}},
+	}
+
+	weights := func(nodes []*pb.WeightedNode) map[string]float64 {
+		w := map[string]float64{}
+		for _, n := range nodes {
+			w[n.Node.Id] = n.Weight
+		}
+		return w
+	}
+
+	t.Run("NoSplits", func(t *testing.T) {
+		w := weights(Resolve(services, nil))
+		if len(w) != 4 || w["v1-1"] != w["canary-1"] {
+			t.Fatalf("Expected equal weights, got %v", w)
+		}
+	})
+
+	t.Run("Splits", func(t *testing.T) {
+		w := weights(Resolve(services, []*pb.Split{
+			{Labels: map[string]string{"canary": "true"}, Weight: 10},
+			{Labels: map[string]string{VersionLabel: "v2"}, Weight: 20},
+			{Labels: map[string]string{VersionLabel: "v1"}, Weight: 70},
+		}))
+		if w["canary-1"] != 10 || w["v2-1"] != 20 || w["v1-1"] != 35 || w["v1-2"] != 35 {
+			t.Fatalf("Unexpected weights %v", w)
+		}
+	})
+
+	t.Run("Pick", func(t *testing.T) {
+		nodes := Resolve(services, []*pb.Split{{Labels: map[string]string{"canary": "true"}, Weight: 1}})
+		if n := Pick(nodes); n == nil || n.Node.Id != "canary-1" {
+			t.Fatalf("Expected the canary node to be picked, got %v", n)
+		}
+		if Pick(nil) != nil {
+			t.Fatalf("Expected no node to be picked")
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+// PreferLocality returns the nodes of the services in the zone if there are any, otherwise
+// those in the region. If no nodes are in the region, the services are returned unchanged.
+// Services without any nodes in the locality are omitted.
+func PreferLocality(services []*pb.Service, region, zone string) []*pb.Service {
+	inZone := func(n *pb.Node) bool {
+		return len(zone) > 0 && n.Zone == zone
+	}
+	inRegion := func(n *pb.Node) bool {
+		return len(region) > 0 && n.Region == region
+	}
+
+	for _, match := range []func(*pb.Node) bool{inZone, inRegion} {
+		var filtered []*pb.Service
+		for _, s := range services {
+			var nodes []*pb.Node
+			for _, n := range s.Nodes {
+				if match(n) {
+					nodes = append(nodes, n)
+				}
+			}
+			if len(nodes) == 0 {
+				continue
+			}
+
+			srv := *s
+			srv.Nodes = nodes
+			filtered = append(filtered, &srv)
+		}
+		if len(filtered) > 0 {
+			return filtered
+		}
+	}
+
+	return services
+}
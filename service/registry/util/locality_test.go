@@ -0,0 +1,56 @@
+package util
+
+import (
+	"testing"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func TestPreferLocality(t *testing.T) {
+	node := func(id, region, zone string) *pb.Node {
+		return &pb.Node{Id: id, Region: region, Zone: zone}
+	}
+	services := []*pb.Service{
+		{Name: "foo", Version: "v1", Nodes: []*pb.Node{node("a", "eu-west-1", "eu-west-1a"), node("b", "eu-west-1", "eu-west-1b")}},
+		{Name: "foo", Version: "v2", Nodes: []*pb.Node{node("c", "us-east-1", "us-east-1a")}},
+	}
+
+	ids := func(services []*pb.Service) []string {
+		var ids []string
+		for _, s := range services {
+			for _, n := range s.Nodes {
+				ids = append(ids, n.Id)
+			}
+		}
+		return ids
+	}
+
+	tt := []struct {
+		name   string
+		region string
+		zone   string
+		nodes  []string
+	}{
+		{"Zone", "eu-west-1", "eu-west-1b", []string{"b"}},
+		{"Region", "eu-west-1", "eu-west-1c", []string{"a", "b"}},
+		{"None", "ap-south-1", "ap-south-1a", []string{"a", "b", "c"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ids(PreferLocality(services, tc.region, tc.zone))
+			if len(got) != len(tc.nodes) {
+				t.Fatalf("Expected nodes %v, got %v", tc.nodes, got)
+			}
+			for i := range got {
+				if got[i] != tc.nodes[i] {
+					t.Fatalf("Expected nodes %v, got %v", tc.nodes, got)
+				}
+			}
+		})
+	}
+
+	if len(services[0].Nodes) != 2 {
+		t.Errorf("Expected the services not to be modified")
+	}
+}
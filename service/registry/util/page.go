@@ -0,0 +1,58 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+// ErrInvalidCursor is returned when paging with a cursor which wasn't returned by a previous page
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page returns the services whose names have the prefix, ordered by name and version, after the
+// cursor. At most limit services are returned, with the versions of a service counting as one,
+// and every service if limit is zero. The cursor of the next page is returned, which is blank
+// if there are no more services.
+func Page(services []*registry.Service, prefix, cursor string, limit int) ([]*registry.Service, string, error) {
+	var after string
+	if len(cursor) > 0 {
+		b, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(b) == 0 {
+			return nil, "", ErrInvalidCursor
+		}
+		after = string(b)
+	}
+
+	var matches []*registry.Service
+	for _, s := range services {
+		if strings.HasPrefix(s.Name, prefix) && s.Name > after {
+			matches = append(matches, s)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].Version < matches[j].Version
+	})
+
+	if limit <= 0 {
+		return matches, "", nil
+	}
+
+	var names int
+	for i, s := range matches {
+		if i > 0 && s.Name == matches[i-1].Name {
+			continue
+		}
+		if names == limit {
+			last := matches[i-1].Name
+			return matches[:i], base64.RawURLEncoding.EncodeToString([]byte(last)), nil
+		}
+		names++
+	}
+	return matches, "", nil
+}
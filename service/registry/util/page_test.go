@@ -0,0 +1,74 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+func TestPage(t *testing.T) {
+	services := []*registry.Service{
+		{Name: "users", Version: "v1"},
+		{Name: "orders", Version: "v2"},
+		{Name: "orders", Version: "v1"},
+		{Name: "payments", Version: "v1"},
+		{Name: "payouts", Version: "v1"},
+	}
+
+	names := func(services []*registry.Service) []string {
+		var names []string
+		for _, s := range services {
+			names = append(names, s.Name+"@"+s.Version)
+		}
+		return names
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	// page through every service two at a time
+	var pages [][]string
+	var cursor string
+	for {
+		page, next, err := Page(services, "", cursor, 2)
+		if err != nil {
+			t.Fatalf("Unexpected error paging: %v", err)
+		}
+		pages = append(pages, names(page))
+		if len(next) == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	expected := [][]string{
+		{"orders@v1", "orders@v2", "payments@v1"},
+		{"payouts@v1", "users@v1"},
+	}
+	if len(pages) != len(expected) {
+		t.Fatalf("Expected pages %v, got %v", expected, pages)
+	}
+	for i := range pages {
+		if !equal(pages[i], expected[i]) {
+			t.Errorf("Expected page %v to be %v, got %v", i, expected[i], pages[i])
+		}
+	}
+
+	// filter by prefix
+	page, next, _ := Page(services, "pay", "", 0)
+	if !equal(names(page), []string{"payments@v1", "payouts@v1"}) || len(next) > 0 {
+		t.Errorf("Expected the services with the prefix, got %v %v", names(page), next)
+	}
+
+	if _, _, err := Page(services, "", "!", 1); err != ErrInvalidCursor {
+		t.Errorf("Expected an invalid cursor error, got %v", err)
+	}
+}
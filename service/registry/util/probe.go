@@ -0,0 +1,12 @@
+package util
+
+import "github.com/micro/go-micro/v3/registry"
+
+// ProbeKey is the node metadata key services opt out of health probes with, by setting it
+// to "false", e.g. when the registry can't reach their nodes directly
+const ProbeKey = "probe"
+
+// Probed returns true if the registry should probe the health of the node
+func Probed(n *registry.Node) bool {
+	return n.Metadata[ProbeKey] != "false"
+}
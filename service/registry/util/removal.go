@@ -0,0 +1,29 @@
+package util
+
+import (
+	"github.com/golang/protobuf/proto"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+// RemovalKey is set in the node metadata of the deregistration events the registry makes
+// itself, to the reason the nodes were removed. Nodes removed by their service don't have it.
+const RemovalKey = "removal"
+
+const (
+	// RemovalExpired is the removal reason of nodes whose registration expired
+	RemovalExpired = "expired"
+	// RemovalUnhealthy is the removal reason of nodes which failed their health probes
+	RemovalUnhealthy = "unhealthy"
+)
+
+// MarkRemoved returns a copy of the service with the removal reason set on its nodes
+func MarkRemoved(srv *pb.Service, reason string) *pb.Service {
+	marked := proto.Clone(srv).(*pb.Service)
+	for _, n := range marked.Nodes {
+		if n.Metadata == nil {
+			n.Metadata = make(map[string]string)
+		}
+		n.Metadata[RemovalKey] = reason
+	}
+	return marked
+}
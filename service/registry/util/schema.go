@@ -0,0 +1,297 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Endpoint metadata keys the JSON schemas of the request and response are registered under
+const (
+	RequestSchemaKey  = "request_schema"
+	ResponseSchemaKey = "response_schema"
+)
+
+// Schema is the subset of JSON schema used to describe protobuf messages
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// EndpointSchemas returns the endpoint metadata containing the schemas, schema versions and
+// fields of the handler's request and response types, keyed by endpoint name, e.g. Foo.Bar. Types which
+// aren't protobuf messages, such as streams, are omitted.
+func EndpointSchemas(handler interface{}) map[string]map[string]string {
+	typ := reflect.TypeOf(handler)
+	name := reflect.Indirect(reflect.ValueOf(handler)).Type().Name()
+
+	endpoints := make(map[string]map[string]string)
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if m.PkgPath != "" {
+			continue
+		}
+
+		var req, rsp reflect.Type
+		switch m.Type.NumIn() {
+		case 3:
+			req, rsp = m.Type.In(1), m.Type.In(2)
+		case 4:
+			req, rsp = m.Type.In(2), m.Type.In(3)
+		default:
+			continue
+		}
+
+		md := make(map[string]string)
+		if s, ok := typeSchema(req); ok {
+			md[RequestSchemaKey] = s
+		}
+		if s, ok := typeSchema(rsp); ok {
+			md[ResponseSchemaKey] = s
+		}
+		if v, ok := TypeVersion(req); ok {
+			md[RequestVersionKey] = v
+		}
+		if v, ok := TypeVersion(rsp); ok {
+			md[ResponseVersionKey] = v
+		}
+		if f, ok := TypeFields(req); ok {
+			md[RequestFieldsKey] = EncodeFields(f)
+		}
+		if f, ok := TypeFields(rsp); ok {
+			md[ResponseFieldsKey] = EncodeFields(f)
+		}
+		if len(md) > 0 {
+			endpoints[name+"."+m.Name] = md
+		}
+	}
+
+	return endpoints
+}
+
+// typeSchema returns the encoded schema of the type if it's a protobuf message
+func typeSchema(t reflect.Type) (string, bool) {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return "", false
+	}
+	msg, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok {
+		return "", false
+	}
+
+	b, err := json.Marshal(MessageSchema(proto.MessageV2(msg).ProtoReflect().Descriptor()))
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// MessageSchema returns the schema of the message. Messages are described in the definitions
+// so recursive messages can be referenced.
+func MessageSchema(md protoreflect.MessageDescriptor) *Schema {
+	defs := make(map[string]*Schema)
+	ref := messageRef(md, defs)
+	return &Schema{Ref: ref.Ref, Definitions: defs}
+}
+
+func messageRef(md protoreflect.MessageDescriptor, defs map[string]*Schema) *Schema {
+	name := string(md.FullName())
+	ref := &Schema{Ref: "#/definitions/" + name}
+	if _, ok := defs[name]; ok {
+		return ref
+	}
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	defs[name] = s
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		s.Properties[f.JSONName()] = fieldSchema(f, defs)
+	}
+	return ref
+}
+
+func fieldSchema(f protoreflect.FieldDescriptor, defs map[string]*Schema) *Schema {
+	if f.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: kindSchema(f.MapValue(), defs)}
+	}
+	if f.IsList() {
+		return &Schema{Type: "array", Items: kindSchema(f, defs)}
+	}
+	return kindSchema(f, defs)
+}
+
+// kindSchema returns the schema of a single value of the field, using the protobuf JSON mapping
+func kindSchema(f protoreflect.FieldDescriptor, defs map[string]*Schema) *Schema {
+	switch f.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64 bit integers are encoded as strings
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &Schema{Type: "number"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		values := f.Enum().Values()
+		s := &Schema{Type: "string"}
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+		return s
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageRef(f.Message(), defs)
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// Validate the JSON payload against the schema. Fields not in the schema are ignored and null
+// values are accepted for any field, as they are when the payload is decoded. Fields can be
+// named using either their JSON or their original protobuf names.
+func Validate(schema *Schema, payload []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return fmt.Errorf("Invalid JSON: %v", err)
+	}
+	return validate(schema, schema.Definitions, v, "")
+}
+
+func validate(s *Schema, defs map[string]*Schema, v interface{}, path string) error {
+	if s == nil || v == nil {
+		return nil
+	}
+	if len(s.Ref) > 0 {
+		const prefix = "#/definitions/"
+		if len(s.Ref) <= len(prefix) {
+			return nil
+		}
+		return validate(defs[s.Ref[len(prefix):]], defs, v, path)
+	}
+
+	field := path
+	if len(field) == 0 {
+		field = "request"
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%v must be an object", field)
+		}
+		for k, val := range obj {
+			fs := s.AdditionalProperties
+			if s.Properties != nil {
+				fs = s.Properties[k]
+				if fs == nil {
+					fs = s.Properties[jsonName(k)]
+				}
+			}
+			if err := validate(fs, defs, val, join(path, k)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v must be an array", field)
+		}
+		for i, val := range arr {
+			if err := validate(s.Items, defs, val, fmt.Sprintf("%v[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%v must be a boolean", field)
+		}
+	case "integer", "number":
+		// numbers can also be encoded as strings
+		switch n := v.(type) {
+		case float64:
+			if s.Type == "integer" && n != float64(int64(n)) {
+				return fmt.Errorf("%v must be an integer", field)
+			}
+		case string:
+			if _, err := strconv.ParseFloat(n, 64); err != nil {
+				return fmt.Errorf("%v must be a number", field)
+			}
+		default:
+			return fmt.Errorf("%v must be a number", field)
+		}
+	case "string":
+		switch val := v.(type) {
+		case string:
+			if len(s.Enum) > 0 && !contains(s.Enum, val) {
+				return fmt.Errorf("%v must be one of %v", field, s.Enum)
+			}
+			if s.Format == "int64" {
+				if _, err := strconv.ParseFloat(val, 64); err != nil {
+					return fmt.Errorf("%v must be an integer", field)
+				}
+			}
+		case float64:
+			// enums can be encoded as numbers and 64 bit integers as numbers
+			if len(s.Enum) == 0 && s.Format != "int64" {
+				return fmt.Errorf("%v must be a string", field)
+			}
+		default:
+			return fmt.Errorf("%v must be a string", field)
+		}
+	}
+
+	return nil
+}
+
+// jsonName returns the JSON name of the protobuf field name, e.g. foo_bar is fooBar
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, c := range name {
+		switch {
+		case c == '_':
+			upper = true
+		case upper && 'a' <= c && c <= 'z':
+			b.WriteRune(c - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(c)
+			upper = false
+		}
+	}
+	return b.String()
+}
+
+func join(path, field string) string {
+	if len(path) == 0 {
+		return field
+	}
+	return path + "." + field
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
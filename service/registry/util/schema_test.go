@@ -0,0 +1,98 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+type testHandler struct{}
+
+func (h *testHandler) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.GetResponse) error {
+	return nil
+}
+
+func (h *testHandler) Stream(ctx context.Context, stream interface{}) error {
+	return nil
+}
+
+func TestEndpointSchemas(t *testing.T) {
+	schemas := EndpointSchemas(&testHandler{})
+	if len(schemas) != 1 {
+		t.Fatalf("Expected schemas for a single endpoint, got %v", schemas)
+	}
+
+	md, ok := schemas["testHandler.GetService"]
+	if !ok {
+		t.Fatalf("Expected schemas for testHandler.GetService, got %v", schemas)
+	}
+
+	var req Schema
+	if err := json.Unmarshal([]byte(md[RequestSchemaKey]), &req); err != nil {
+		t.Fatalf("Error decoding the request schema: %v", err)
+	}
+	if req.Ref != "#/definitions/registry.GetRequest" {
+		t.Errorf("Unexpected request schema reference %v", req.Ref)
+	}
+	if s := req.Definitions["registry.Options"]; s == nil || s.Properties["ttl"].Format != "int64" {
+		t.Errorf("Expected the options to be defined, got %v", s)
+	}
+
+	var rsp Schema
+	if err := json.Unmarshal([]byte(md[ResponseSchemaKey]), &rsp); err != nil {
+		t.Fatalf("Error decoding the response schema: %v", err)
+	}
+	if s := rsp.Definitions["registry.Service"]; s == nil || s.Properties["metadata"].AdditionalProperties.Type != "string" {
+		t.Errorf("Expected the service metadata to be a map, got %v", s)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var schema Schema
+	md := EndpointSchemas(&testHandler{})["testHandler.GetService"]
+	if err := json.Unmarshal([]byte(md[RequestSchemaKey]), &schema); err != nil {
+		t.Fatalf("Error decoding the request schema: %v", err)
+	}
+
+	tt := []struct {
+		Name    string
+		Payload string
+		Valid   bool
+	}{
+		{"Empty", `{}`, true},
+		{"Valid", `{"service": "foo", "options": {"ttl": "10", "domain": "micro"}}`, true},
+		{"NumericInt64", `{"options": {"ttl": 10}}`, true},
+		{"UnknownField", `{"foo": "bar"}`, true},
+		{"Null", `{"options": null}`, true},
+		{"InvalidJSON", `{"service": `, false},
+		{"NotObject", `[]`, false},
+		{"WrongType", `{"service": 1}`, false},
+		{"NestedWrongType", `{"options": {"domain": true}}`, false},
+		{"InvalidInt64", `{"options": {"ttl": "ten"}}`, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Validate(&schema, []byte(tc.Payload))
+			if tc.Valid && err != nil {
+				t.Errorf("Expected the payload to be valid, got %v", err)
+			} else if !tc.Valid && err == nil {
+				t.Errorf("Expected the payload to be invalid")
+			}
+		})
+	}
+}
+
+func TestValidateProtoNames(t *testing.T) {
+	schema := MessageSchema(proto.MessageV2(&pb.ListResponse{}).ProtoReflect().Descriptor())
+
+	if err := Validate(schema, []byte(`{"next_cursor": 1}`)); err == nil {
+		t.Errorf("Expected fields named using their protobuf names to be validated")
+	}
+	if err := Validate(schema, []byte(`{"next_cursor": "foo", "nextCursor": "bar"}`)); err != nil {
+		t.Errorf("Expected the payload to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Endpoint metadata keys the schema versions and fields of the request and response are
+// registered under
+const (
+	RequestVersionKey  = "request_version"
+	ResponseVersionKey = "response_version"
+	RequestFieldsKey   = "request_fields"
+	ResponseFieldsKey  = "response_fields"
+)
+
+// MessageVersion returns the schema version of the message, a hash of the numbers, names, kinds
+// and cardinality of its fields and those of the messages and enums it contains. Callers and
+// services agree on the encoding of a message if their versions of it are the same.
+func MessageVersion(md protoreflect.MessageDescriptor) string {
+	var b strings.Builder
+	describeMessage(&b, md, make(map[protoreflect.FullName]bool))
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// TypeVersion returns the schema version of the type if it's a protobuf message
+func TypeVersion(t reflect.Type) (string, bool) {
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return "", false
+	}
+	msg, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok {
+		return "", false
+	}
+	return MessageVersion(proto.MessageV2(msg).ProtoReflect().Descriptor()), true
+}
+
+// describeMessage writes the canonical description of the message, the messages already
+// described are referenced by name so recursive messages terminate
+func describeMessage(b *strings.Builder, md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) {
+	fmt.Fprintf(b, "message %v {", md.FullName())
+	if seen[md.FullName()] {
+		b.WriteString("}")
+		return
+	}
+	seen[md.FullName()] = true
+
+	fields := md.Fields()
+	sorted := make([]protoreflect.FieldDescriptor, fields.Len())
+	for i := range sorted {
+		sorted[i] = fields.Get(i)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Number() < sorted[j].Number() })
+
+	for _, f := range sorted {
+		fmt.Fprintf(b, "%d %v %v %v", f.Number(), f.JSONName(), f.Cardinality(), f.Kind())
+		if f.IsMap() {
+			fmt.Fprintf(b, " map<%v, ", f.MapKey().Kind())
+			describeValue(b, f.MapValue(), seen)
+			b.WriteString(">")
+		} else {
+			b.WriteString(" ")
+			describeValue(b, f, seen)
+		}
+		b.WriteString(";")
+	}
+	b.WriteString("}")
+}
+
+func describeValue(b *strings.Builder, f protoreflect.FieldDescriptor, seen map[protoreflect.FullName]bool) {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		describeMessage(b, f.Message(), seen)
+	case protoreflect.EnumKind:
+		values := f.Enum().Values()
+		fmt.Fprintf(b, "enum %v {", f.Enum().FullName())
+		for i := 0; i < values.Len(); i++ {
+			fmt.Fprintf(b, "%v=%d;", values.Get(i).Name(), values.Get(i).Number())
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString(f.Kind().String())
+	}
+}
+
+// MessageFields returns the cardinality and kind of the fields of the message and those of the
+// messages it contains, keyed by the full name of their message and their number. Messages
+// whose versions differ can still be compatible, which FieldConflicts determines from their
+// fields.
+func MessageFields(md protoreflect.MessageDescriptor) map[string]string {
+	fields := make(map[string]string)
+	messageFields(fields, md, make(map[protoreflect.FullName]bool))
+	return fields
+}
+
+// TypeFields returns the fields of the type if it's a protobuf message
+func TypeFields(t reflect.Type) (map[string]string, bool) {
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	msg, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok {
+		return nil, false
+	}
+	return MessageFields(proto.MessageV2(msg).ProtoReflect().Descriptor()), true
+}
+
+func messageFields(fields map[string]string, md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) {
+	if seen[md.FullName()] {
+		return
+	}
+	seen[md.FullName()] = true
+
+	for i := 0; i < md.Fields().Len(); i++ {
+		f := md.Fields().Get(i)
+		kind := f.Kind().String()
+		switch f.Kind() {
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			kind += " " + string(f.Message().FullName())
+			messageFields(fields, f.Message(), seen)
+		case protoreflect.EnumKind:
+			kind += " " + string(f.Enum().FullName())
+		}
+		fields[fmt.Sprintf("%v.%d", md.FullName(), f.Number())] = f.Cardinality().String() + " " + kind
+	}
+}
+
+// EncodeFields encodes the fields for the endpoint metadata, e.g. "foo.Bar.1=optional string"
+func EncodeFields(fields map[string]string) string {
+	entries := make([]string, 0, len(fields))
+	for k, v := range fields {
+		entries = append(entries, k+"="+v)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ";")
+}
+
+// ParseFields parses the fields encoded by EncodeFields
+func ParseFields(s string) map[string]string {
+	fields := make(map[string]string)
+	for _, entry := range strings.Split(s, ";") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			continue
+		}
+		fields[parts[0]] = parts[1]
+	}
+	return fields
+}
+
+// FieldConflicts returns the fields both sides have with a different cardinality or kind, which
+// are encoded incompatibly. Fields only one side has are ignored since they're skipped when
+// decoded, as are changes of names which the wire format doesn't include.
+func FieldConflicts(local, remote map[string]string) []string {
+	var conflicts []string
+	for k, l := range local {
+		if r, ok := remote[k]; ok && r != l {
+			conflicts = append(conflicts, fmt.Sprintf("field %v is %v, expected %v", k, r, l))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
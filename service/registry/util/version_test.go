@@ -0,0 +1,56 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func TestMessageVersion(t *testing.T) {
+	req, ok := TypeVersion(reflect.TypeOf(&pb.GetRequest{}))
+	if !ok || len(req) == 0 {
+		t.Fatalf("Expected the request to have a version")
+	}
+	if v, _ := TypeVersion(reflect.TypeOf(&pb.GetRequest{})); v != req {
+		t.Errorf("Expected the version to be stable, got %v and %v", req, v)
+	}
+	if v, _ := TypeVersion(reflect.TypeOf(&pb.GetResponse{})); v == req {
+		t.Errorf("Expected different messages to have different versions")
+	}
+	if _, ok := TypeVersion(reflect.TypeOf(map[string]string{})); ok {
+		t.Errorf("Expected types which aren't messages not to have a version")
+	}
+
+	md := EndpointSchemas(&testHandler{})["testHandler.GetService"]
+	if md[RequestVersionKey] != req {
+		t.Errorf("Expected the request version to be registered, got %v", md[RequestVersionKey])
+	}
+	if len(md[ResponseVersionKey]) == 0 {
+		t.Errorf("Expected the response version to be registered")
+	}
+
+	fields, _ := TypeFields(reflect.TypeOf(&pb.GetRequest{}))
+	if !reflect.DeepEqual(ParseFields(md[RequestFieldsKey]), fields) {
+		t.Errorf("Expected the request fields to be registered, got %v", md[RequestFieldsKey])
+	}
+}
+
+func TestFieldConflicts(t *testing.T) {
+	fields, ok := TypeFields(reflect.TypeOf(&pb.GetRequest{}))
+	if !ok || fields["registry.GetRequest.1"] != "optional string" {
+		t.Fatalf("Expected the service field to be described, got %v", fields)
+	}
+	if len(FieldConflicts(fields, fields)) > 0 {
+		t.Errorf("Expected the same fields not to conflict")
+	}
+
+	// added and removed fields don't conflict, changed kinds do
+	remote := map[string]string{
+		"registry.GetRequest.1":  "optional int64",
+		"registry.GetRequest.99": "optional string",
+	}
+	if c := FieldConflicts(fields, remote); len(c) != 1 {
+		t.Errorf("Expected the changed kind to conflict, got %v", c)
+	}
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"strconv"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+// WeightKey is the node metadata key the weight of a node is set with, the share of the calls
+// selected by weight it receives being proportional to its weight
+const WeightKey = "weight"
+
+// DefaultWeight of nodes which don't set one
+const DefaultWeight = 1
+
+// Weight returns the weight of the node, DefaultWeight if it doesn't set a valid one
+func Weight(n *registry.Node) int {
+	w, err := strconv.Atoi(n.Metadata[WeightKey])
+	if err != nil || w < 0 {
+		return DefaultWeight
+	}
+	return w
+}
+
+// SetWeight sets the weight of the node
+func SetWeight(n *registry.Node, w int) {
+	md := make(map[string]string, len(n.Metadata)+1)
+	for k, v := range n.Metadata {
+		md[k] = v
+	}
+	md[WeightKey] = strconv.Itoa(w)
+	n.Metadata = md
+}
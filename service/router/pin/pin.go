@@ -0,0 +1,213 @@
+// Package pin pins streams to the route they were established on. A stream is sent to an
+// address looked up when it's established and stays there for its lifetime, so changes to the
+// routing table don't move it, while new calls and streams pick up the changes. If the route a
+// stream is pinned to is deleted from the table and the address can't be reached another way,
+// the pin is invalidated and the stream is closed with ErrRouteDeleted, a transient error, so
+// the caller can re-establish it on a current route.
+package pin
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/router"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// ErrRouteDeleted is returned by streams whose route was deleted
+	ErrRouteDeleted = errors.ServiceUnavailable("go.micro.router", "route of the stream was deleted")
+
+	// DefaultRetryInterval is how long to wait before watching the table again if the watch
+	// fails
+	DefaultRetryInterval = time.Second * 5
+)
+
+// pin of a stream to the address of a service
+type pin struct {
+	service string
+	address string
+}
+
+type pinClient struct {
+	client.Client
+	router router.Router
+	once   sync.Once
+
+	sync.Mutex
+	streams map[*pinnedStream]pin
+}
+
+// NewClient returns a client which pins streams to the route they were established on,
+// invalidating the pins of the routes deleted from the router's table
+func NewClient(c client.Client, r router.Router) client.Client {
+	return &pinClient{
+		Client:  c,
+		router:  r,
+		streams: make(map[*pinnedStream]pin),
+	}
+}
+
+func (p *pinClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	options := p.Client.Options().CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// streams sent to an address are already pinned to it
+	lookup := p.Client.Options().Lookup
+	if len(options.Address) > 0 || lookup == nil || p.router == nil {
+		return p.Client.Stream(ctx, req, opts...)
+	}
+
+	addrs, err := lookup(ctx, req, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return p.Client.Stream(ctx, req, opts...)
+	}
+	addr := addrs[rand.Intn(len(addrs))]
+
+	stream, err := p.Client.Stream(ctx, req, append(opts, client.WithAddress(addr))...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.once.Do(func() { go p.watch() })
+
+	ps := &pinnedStream{Stream: stream, client: p}
+	p.Lock()
+	p.streams[ps] = pin{service: req.Service(), address: addr}
+	p.Unlock()
+	return ps, nil
+}
+
+// unpin the stream
+func (p *pinClient) unpin(s *pinnedStream) {
+	p.Lock()
+	defer p.Unlock()
+	delete(p.streams, s)
+}
+
+// watch the table, invalidating the pins of the routes deleted
+func (p *pinClient) watch() {
+	for {
+		w, err := p.router.Watch()
+		if err != nil {
+			logger.Debugf("Error watching the routing table: %v", err)
+			time.Sleep(DefaultRetryInterval)
+			continue
+		}
+
+		for {
+			ev, err := w.Next()
+			if err != nil {
+				logger.Debugf("Error watching the routing table: %v", err)
+				break
+			}
+			if ev.Type == router.Delete {
+				p.invalidate(ev.Route)
+			}
+		}
+
+		w.Stop()
+		time.Sleep(DefaultRetryInterval)
+	}
+}
+
+// invalidate the pins to the addresses of the route deleted, unless the address can still be
+// reached by another route
+func (p *pinClient) invalidate(route router.Route) {
+	p.Lock()
+	pinned := make(map[string][]*pinnedStream)
+	for s, pn := range p.streams {
+		if pn.service == route.Service && (pn.address == route.Address || pn.address == route.Gateway) {
+			pinned[pn.address] = append(pinned[pn.address], s)
+		}
+	}
+	p.Unlock()
+	if len(pinned) == 0 {
+		return
+	}
+
+	routes, _ := p.router.Lookup(route.Service)
+	for addr, streams := range pinned {
+		if reachable(routes, route, addr) {
+			continue
+		}
+		logger.Debugf("Route of %v at %v deleted, closing %v pinned streams", route.Service, addr, len(streams))
+		for _, s := range streams {
+			s.invalidate()
+		}
+	}
+}
+
+// reachable returns true if a route other than the one deleted leads to the address
+func reachable(routes []router.Route, deleted router.Route, addr string) bool {
+	for _, r := range routes {
+		if r.Hash() != deleted.Hash() && (r.Address == addr || r.Gateway == addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// pinnedStream is a stream pinned to an address
+type pinnedStream struct {
+	client.Stream
+	client *pinClient
+
+	sync.RWMutex
+	invalid bool
+}
+
+func (s *pinnedStream) invalidate() {
+	s.Lock()
+	s.invalid = true
+	s.Unlock()
+
+	s.client.unpin(s)
+	s.Stream.Close()
+}
+
+// err returns ErrRouteDeleted if the pin was invalidated, otherwise the error
+func (s *pinnedStream) err(err error) error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.invalid {
+		return ErrRouteDeleted
+	}
+	return err
+}
+
+// streams which fail to send or receive have ended, so they're unpinned rather than waiting to
+// be closed
+func (s *pinnedStream) Send(msg interface{}) error {
+	if err := s.Stream.Send(msg); err != nil {
+		s.client.unpin(s)
+		return s.err(err)
+	}
+	return nil
+}
+
+func (s *pinnedStream) Recv(msg interface{}) error {
+	if err := s.Stream.Recv(msg); err != nil {
+		s.client.unpin(s)
+		return s.err(err)
+	}
+	return nil
+}
+
+func (s *pinnedStream) Error() error {
+	return s.err(s.Stream.Error())
+}
+
+func (s *pinnedStream) Close() error {
+	s.client.unpin(s)
+	return s.Stream.Close()
+}
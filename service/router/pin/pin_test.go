@@ -0,0 +1,158 @@
+package pin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+	"github.com/micro/go-micro/v3/router"
+)
+
+type testRouter struct {
+	router.Router
+	events chan *router.Event
+
+	sync.Mutex
+	routes []router.Route
+}
+
+func (t *testRouter) Lookup(service string, opts ...router.LookupOption) ([]router.Route, error) {
+	t.Lock()
+	defer t.Unlock()
+	return t.routes, nil
+}
+
+func (t *testRouter) Watch(opts ...router.WatchOption) (router.Watcher, error) {
+	return &testWatcher{events: t.events}, nil
+}
+
+type testWatcher struct {
+	router.Watcher
+	events chan *router.Event
+}
+
+func (t *testWatcher) Next() (*router.Event, error) {
+	ev, ok := <-t.events
+	if !ok {
+		return nil, fmt.Errorf("watcher stopped")
+	}
+	return ev, nil
+}
+
+func (t *testWatcher) Stop() {}
+
+type testStream struct {
+	client.Stream
+	address string
+
+	sync.Mutex
+	closed bool
+}
+
+func (t *testStream) Recv(msg interface{}) error {
+	t.Lock()
+	defer t.Unlock()
+	if t.closed {
+		return fmt.Errorf("stream closed")
+	}
+	return nil
+}
+
+func (t *testStream) Close() error {
+	t.Lock()
+	defer t.Unlock()
+	t.closed = true
+	return nil
+}
+
+// testClient streams to the address given, looking up the addresses of the routes
+type testClient struct {
+	client.Client
+	router *testRouter
+}
+
+func (t *testClient) Options() client.Options {
+	opts := t.Client.Options()
+	opts.Lookup = func(ctx context.Context, req client.Request, o client.CallOptions) ([]string, error) {
+		routes, _ := t.router.Lookup(req.Service())
+		var addrs []string
+		for _, r := range routes {
+			addrs = append(addrs, r.Address)
+		}
+		return addrs, nil
+	}
+	return opts
+}
+
+func (t *testClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return &testStream{address: options.Address[0]}, nil
+}
+
+func TestPin(t *testing.T) {
+	r := &testRouter{
+		events: make(chan *router.Event),
+		routes: []router.Route{{Service: "foo", Address: "a", Link: "local"}},
+	}
+	c := NewClient(&testClient{Client: mucp.NewClient(), router: r}, r)
+	req := c.NewRequest("foo", "Foo.Stream", nil)
+
+	stream, err := c.Stream(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if addr := stream.(*pinnedStream).Stream.(*testStream).address; addr != "a" {
+		t.Fatalf("Expected the stream to be sent to a, got %v", addr)
+	}
+
+	// new streams pick up changes to the table, without moving the existing stream
+	deleted := r.routes[0]
+	r.Lock()
+	r.routes = []router.Route{{Service: "foo", Address: "b", Link: "local"}}
+	r.Unlock()
+	if s, _ := c.Stream(context.TODO(), req); s.(*pinnedStream).Stream.(*testStream).address != "b" {
+		t.Errorf("Expected the new stream to be sent to b")
+	}
+	if err := stream.Recv(nil); err != nil {
+		t.Fatalf("Expected the pinned stream to stay open: %v", err)
+	}
+
+	// deleting the route invalidates the pin
+	r.events <- &router.Event{Type: router.Delete, Route: deleted}
+	time.Sleep(10 * time.Millisecond)
+	if err := stream.Recv(nil); err != ErrRouteDeleted {
+		t.Errorf("Expected the stream to be closed with ErrRouteDeleted, got %v", err)
+	}
+}
+
+func TestUnpinEnded(t *testing.T) {
+	r := &testRouter{
+		events: make(chan *router.Event),
+		routes: []router.Route{{Service: "foo", Address: "a", Link: "local"}},
+	}
+	c := NewClient(&testClient{Client: mucp.NewClient(), router: r}, r)
+
+	stream, err := c.Stream(context.TODO(), c.NewRequest("foo", "Foo.Stream", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the stream ending on the server's side unpins it
+	stream.(*pinnedStream).Stream.(*testStream).Close()
+	if err := stream.Recv(nil); err == nil {
+		t.Fatalf("Expected the stream to have ended")
+	}
+	p := c.(*pinClient)
+	p.Lock()
+	defer p.Unlock()
+	if len(p.streams) != 0 {
+		t.Errorf("Expected the stream to be unpinned, got %v pinned", len(p.streams))
+	}
+}
@@ -197,6 +197,12 @@ type TableService interface {
 	Delete(ctx context.Context, in *Route, opts ...client.CallOption) (*DeleteResponse, error)
 	Update(ctx context.Context, in *Route, opts ...client.CallOption) (*UpdateResponse, error)
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
+	// Stage routes in the shadow table
+	Stage(ctx context.Context, in *StageRequest, opts ...client.CallOption) (*StageResponse, error)
+	// Swap the shadow table in place of the live table
+	Swap(ctx context.Context, in *SwapRequest, opts ...client.CallOption) (*SwapResponse, error)
+	// Rollback to the table in use before the last swap
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*RollbackResponse, error)
 }
 
 type tableService struct {
@@ -251,6 +257,36 @@ func (c *tableService) Read(ctx context.Context, in *ReadRequest, opts ...client
 	return out, nil
 }
 
+func (c *tableService) Stage(ctx context.Context, in *StageRequest, opts ...client.CallOption) (*StageResponse, error) {
+	req := c.c.NewRequest(c.name, "Table.Stage", in)
+	out := new(StageResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableService) Swap(ctx context.Context, in *SwapRequest, opts ...client.CallOption) (*SwapResponse, error) {
+	req := c.c.NewRequest(c.name, "Table.Swap", in)
+	out := new(SwapResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableService) Rollback(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*RollbackResponse, error) {
+	req := c.c.NewRequest(c.name, "Table.Rollback", in)
+	out := new(RollbackResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Table service
 
 type TableHandler interface {
@@ -258,6 +294,12 @@ type TableHandler interface {
 	Delete(context.Context, *Route, *DeleteResponse) error
 	Update(context.Context, *Route, *UpdateResponse) error
 	Read(context.Context, *ReadRequest, *ReadResponse) error
+	// Stage routes in the shadow table
+	Stage(context.Context, *StageRequest, *StageResponse) error
+	// Swap the shadow table in place of the live table
+	Swap(context.Context, *SwapRequest, *SwapResponse) error
+	// Rollback to the table in use before the last swap
+	Rollback(context.Context, *RollbackRequest, *RollbackResponse) error
 }
 
 func RegisterTableHandler(s server.Server, hdlr TableHandler, opts ...server.HandlerOption) error {
@@ -266,6 +308,9 @@ func RegisterTableHandler(s server.Server, hdlr TableHandler, opts ...server.Han
 		Delete(ctx context.Context, in *Route, out *DeleteResponse) error
 		Update(ctx context.Context, in *Route, out *UpdateResponse) error
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
+		Stage(ctx context.Context, in *StageRequest, out *StageResponse) error
+		Swap(ctx context.Context, in *SwapRequest, out *SwapResponse) error
+		Rollback(ctx context.Context, in *RollbackRequest, out *RollbackResponse) error
 	}
 	type Table struct {
 		table
@@ -293,3 +338,15 @@ func (h *tableHandler) Update(ctx context.Context, in *Route, out *UpdateRespons
 func (h *tableHandler) Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error {
 	return h.TableHandler.Read(ctx, in, out)
 }
+
+func (h *tableHandler) Stage(ctx context.Context, in *StageRequest, out *StageResponse) error {
+	return h.TableHandler.Stage(ctx, in, out)
+}
+
+func (h *tableHandler) Swap(ctx context.Context, in *SwapRequest, out *SwapResponse) error {
+	return h.TableHandler.Swap(ctx, in, out)
+}
+
+func (h *tableHandler) Rollback(ctx context.Context, in *RollbackRequest, out *RollbackResponse) error {
+	return h.TableHandler.Rollback(ctx, in, out)
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/router"
+	log "github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultGracePeriod is how long a withdrawn route is kept before it's verified. Zero
+	// deletes routes as soon as they're withdrawn.
+	DefaultGracePeriod = time.Duration(0)
+
+	// DefaultProbeTimeout is how long a probe waits for a route's address to respond
+	DefaultProbeTimeout = time.Second * 2
+
+	// SuspectKey is set in the metadata of withdrawn routes during their grace period
+	SuspectKey = "suspect"
+)
+
+// probeFunc checks the address of a route is reachable
+type probeFunc func(address string) error
+
+// probeTCP checks a connection can be established to the address
+func probeTCP(address string) error {
+	conn, err := net.DialTimeout("tcp", address, DefaultProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// graceTable keeps the routes withdrawn because their registration expired or they failed their
+// health probes for a grace period rather than deleting them, since the removal may have been
+// caused by a lost heartbeat or a transient network failure. The routes are marked suspect and once the grace
+// period ends they're probed, only being deleted if the probe fails.
+type graceTable struct {
+	table  router.Table
+	probe  probeFunc
+	period time.Duration
+
+	sync.Mutex
+	// suspects are the withdrawn routes by hash, with the route as it was before it was marked
+	suspects map[uint64]router.Route
+}
+
+func newGraceTable(t router.Table, probe probeFunc, period time.Duration) *graceTable {
+	return &graceTable{
+		table:    t,
+		probe:    probe,
+		period:   period,
+		suspects: make(map[uint64]router.Route),
+	}
+}
+
+// Withdraw the route, marking it suspect until it's verified
+func (g *graceTable) Withdraw(route router.Route) error {
+	if g.period <= 0 {
+		return g.table.Delete(route)
+	}
+
+	hash := route.Hash()
+
+	g.Lock()
+	defer g.Unlock()
+
+	if _, ok := g.suspects[hash]; ok {
+		return nil
+	}
+
+	// find the route in the table so it can be restored as it was
+	routes, err := g.table.Read(router.ReadService(route.Service))
+	if err != nil {
+		return err
+	}
+	var existing *router.Route
+	for i := range routes {
+		if routes[i].Hash() == hash {
+			existing = &routes[i]
+			break
+		}
+	}
+	if existing == nil {
+		return router.ErrRouteNotFound
+	}
+
+	marked := *existing
+	marked.Metadata = make(map[string]string, len(existing.Metadata)+1)
+	for k, v := range existing.Metadata {
+		marked.Metadata[k] = v
+	}
+	marked.Metadata[SuspectKey] = "true"
+	if err := g.table.Update(marked); err != nil {
+		return err
+	}
+
+	g.suspects[hash] = *existing
+	time.AfterFunc(g.period, func() { g.verify(hash) })
+	return nil
+}
+
+// Delete the route immediately, e.g. when its service deregistered it, including if it's suspect
+func (g *graceTable) Delete(route router.Route) error {
+	g.Lock()
+	delete(g.suspects, route.Hash())
+	g.Unlock()
+
+	return g.table.Delete(route)
+}
+
+// Restore the route if it's suspect, e.g. when it's advertised again during the grace period.
+// Returns true if the route was suspect, in which case it's still in the table.
+func (g *graceTable) Restore(route router.Route) bool {
+	g.Lock()
+	defer g.Unlock()
+
+	hash := route.Hash()
+	if _, ok := g.suspects[hash]; !ok {
+		return false
+	}
+	delete(g.suspects, hash)
+	return true
+}
+
+// Suspects returns the number of routes in their grace period
+func (g *graceTable) Suspects() int {
+	g.Lock()
+	defer g.Unlock()
+	return len(g.suspects)
+}
+
+// verify the suspect route once its grace period has ended, deleting it if the probe fails
+// and restoring it otherwise
+func (g *graceTable) verify(hash uint64) {
+	g.Lock()
+	route, ok := g.suspects[hash]
+	delete(g.suspects, hash)
+	g.Unlock()
+
+	// the route was advertised again during the grace period
+	if !ok {
+		return
+	}
+
+	// remote routes are reached through their gateway
+	addr := route.Address
+	if len(route.Gateway) > 0 {
+		addr = route.Gateway
+	}
+
+	if err := g.probe(addr); err != nil {
+		log.Infof("Deleting route to %v at %v after its probe failed: %v", route.Service, route.Address, err)
+		if err := g.table.Delete(route); err != nil && err != router.ErrRouteNotFound {
+			log.Errorf("Error deleting route to %v: %v", route.Service, err)
+		}
+		return
+	}
+
+	log.Infof("Restoring route to %v at %v, it was withdrawn but is reachable", route.Service, route.Address)
+	if err := g.table.Update(route); err != nil {
+		log.Errorf("Error restoring route to %v: %v", route.Service, err)
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/router"
+	"github.com/micro/micro/v3/service/registry/util"
+	pb "github.com/micro/micro/v3/service/router/proto"
+)
+
+type memTable struct {
+	router.Table
+	sync.Mutex
+	routes map[uint64]router.Route
+}
+
+func (m *memTable) Read(opts ...router.ReadOption) ([]router.Route, error) {
+	m.Lock()
+	defer m.Unlock()
+	var routes []router.Route
+	for _, r := range m.routes {
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+func (m *memTable) Update(r router.Route) error {
+	m.Lock()
+	defer m.Unlock()
+	m.routes[r.Hash()] = r
+	return nil
+}
+
+func (m *memTable) Delete(r router.Route) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.routes[r.Hash()]; !ok {
+		return router.ErrRouteNotFound
+	}
+	delete(m.routes, r.Hash())
+	return nil
+}
+
+func (m *memTable) get(r router.Route) (router.Route, bool) {
+	m.Lock()
+	defer m.Unlock()
+	route, ok := m.routes[r.Hash()]
+	return route, ok
+}
+
+func TestGraceTable(t *testing.T) {
+	up := router.Route{Service: "foo", Address: "10.0.0.1:8080", Link: router.DefaultLink}
+	down := router.Route{Service: "foo", Address: "10.1.0.1:8080", Link: router.DefaultLink}
+	table := &memTable{routes: map[uint64]router.Route{up.Hash(): up, down.Hash(): down}}
+
+	// addresses in the 10.1 range are unreachable
+	probe := func(address string) error {
+		if address[:4] == "10.1" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	g := newGraceTable(table, probe, time.Millisecond*20)
+
+	if err := g.Withdraw(router.Route{Service: "bar", Address: "10.0.0.2:8080"}); err != router.ErrRouteNotFound {
+		t.Fatalf("Expected route not found, got %v", err)
+	}
+
+	for _, r := range []router.Route{up, down} {
+		if err := g.Withdraw(r); err != nil {
+			t.Fatalf("Unexpected error withdrawing route: %v", err)
+		}
+		if r, ok := table.get(r); !ok || r.Metadata[SuspectKey] != "true" {
+			t.Fatalf("Expected the route to be kept and marked suspect, got %v", r)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for g.Suspects() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if r, ok := table.get(up); !ok || len(r.Metadata[SuspectKey]) > 0 {
+		t.Errorf("Expected the reachable route to be restored, got %v", r)
+	}
+	if _, ok := table.get(down); ok {
+		t.Errorf("Expected the unreachable route to be deleted")
+	}
+
+	// a route advertised again during the grace period isn't verified
+	g.Withdraw(up)
+	if !g.Restore(up) {
+		t.Fatalf("Expected the route to be suspect")
+	}
+	if g.Restore(up) {
+		t.Fatalf("Expected the route not to be suspect once restored")
+	}
+}
+
+func TestTableDelete(t *testing.T) {
+	expired := router.Route{Service: "foo", Address: "10.0.0.1:8080", Link: router.DefaultLink}
+	removed := router.Route{Service: "foo", Address: "10.0.0.2:8080", Link: router.DefaultLink}
+	table := &memTable{routes: map[uint64]router.Route{expired.Hash(): expired, removed.Hash(): removed}}
+	g := newGraceTable(table, func(string) error { return nil }, time.Minute)
+	h := &Table{Grace: g}
+
+	toProto := func(r router.Route, md map[string]string) *pb.Route {
+		return &pb.Route{Service: r.Service, Address: r.Address, Link: r.Link, Metadata: md}
+	}
+
+	// routes removed by the registry are kept for the grace period
+	md := map[string]string{util.RemovalKey: util.RemovalExpired}
+	if err := h.Delete(context.TODO(), toProto(expired, md), &pb.DeleteResponse{}); err != nil {
+		t.Fatalf("Unexpected error deleting route: %v", err)
+	}
+	if r, ok := table.get(expired); !ok || r.Metadata[SuspectKey] != "true" {
+		t.Fatalf("Expected the expired route to be kept and marked suspect, got %v", r)
+	}
+
+	// routes removed by their service are deleted immediately, even if they're suspect
+	for _, r := range []router.Route{removed, expired} {
+		if err := h.Delete(context.TODO(), toProto(r, nil), &pb.DeleteResponse{}); err != nil {
+			t.Fatalf("Unexpected error deleting route: %v", err)
+		}
+		if _, ok := table.get(r); ok {
+			t.Errorf("Expected the route to %v to be deleted", r.Address)
+		}
+	}
+	if n := g.Suspects(); n != 0 {
+		t.Errorf("Expected no suspect routes, got %v", n)
+	}
+}
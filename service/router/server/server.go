@@ -29,6 +29,30 @@ var (
 			Usage:   "Set the micro default gateway address. Defaults to none.",
 			EnvVars: []string{"MICRO_GATEWAY_ADDRESS"},
 		},
+		&cli.Float64Flag{
+			Name:    "swap_error_threshold",
+			Usage:   "Set the error rate above that of the previous table at which a routing table swap is rolled back",
+			EnvVars: []string{"MICRO_ROUTER_SWAP_ERROR_THRESHOLD"},
+			Value:   DefaultErrorThreshold,
+		},
+		&cli.DurationFlag{
+			Name:    "swap_window",
+			Usage:   "Set how long the error rate is watched for after a routing table swap",
+			EnvVars: []string{"MICRO_ROUTER_SWAP_WINDOW"},
+			Value:   DefaultSwapWindow,
+		},
+		&cli.DurationFlag{
+			Name:    "swap_check_interval",
+			Usage:   "Set how often the swapped in routing table is synced and its error rate checked",
+			EnvVars: []string{"MICRO_ROUTER_SWAP_CHECK_INTERVAL"},
+			Value:   DefaultCheckInterval,
+		},
+		&cli.DurationFlag{
+			Name:    "grace_period",
+			Usage:   "Set how long routes whose registration expired or which failed their health probes are kept and marked suspect before they're probed and deleted if unreachable. Routes deleted by their service are always deleted immediately. 0 (the default) deletes all routes immediately",
+			EnvVars: []string{"MICRO_ROUTER_GRACE_PERIOD"},
+			Value:   DefaultGracePeriod,
+		},
 	}
 )
 
@@ -48,6 +72,12 @@ func Run(ctx *cli.Context) error {
 	if len(ctx.String("gateway")) > 0 {
 		gateway = ctx.String("gateway")
 	}
+	if v := ctx.Float64("swap_error_threshold"); v > 0 {
+		DefaultErrorThreshold = v
+	}
+	if v := ctx.Duration("swap_window"); v > 0 {
+		DefaultSwapWindow = v
+	}
 
 	// Initialise service
 	srv := service.New(
@@ -63,9 +93,20 @@ func Run(ctx *cli.Context) error {
 		router.Gateway(gateway),
 	)
 
+	// lookups are served from the shadow table once it's swapped in by any replica
+	interval := DefaultCheckInterval
+	if v := ctx.Duration("swap_check_interval"); v > 0 {
+		interval = v
+	}
+	sr := newShadowRouter(r, debugStats, interval)
+	sr.Start()
+
+	// routes deleted by adverts are verified before they're removed
+	gt := newGraceTable(r.Table(), probeTCP, ctx.Duration("grace_period"))
+
 	// register handlers
-	pb.RegisterRouterHandler(srv.Server(), &Router{Router: r})
-	pb.RegisterTableHandler(srv.Server(), &Table{Router: r})
+	pb.RegisterRouterHandler(srv.Server(), &Router{Router: sr})
+	pb.RegisterTableHandler(srv.Server(), &Table{Router: r, Shadow: sr, Grace: gt})
 
 	return srv.Run()
 }
@@ -0,0 +1,464 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/router"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/client"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// shadowStateKey is the store key of the swapped in table, shared by the router replicas
+	shadowStateKey = "router/shadow/state"
+	// shadowStagedKey is the store key of the shadow table being built
+	shadowStagedKey = "router/shadow/staged"
+)
+
+var (
+	// DefaultErrorThreshold is the error rate above that of the previous table at which a
+	// swapped in table is rolled back
+	DefaultErrorThreshold = 0.1
+	// DefaultSwapWindow is how long the error rate is watched for after a swap
+	DefaultSwapWindow = time.Minute * 5
+	// DefaultCheckInterval is how often the replicas sync the swapped in table and check its
+	// error rate during the swap window
+	DefaultCheckInterval = time.Second * 10
+	// DefaultStatsTimeout is how long the stats of an instance are waited for
+	DefaultStatsTimeout = time.Second * 2
+
+	// ErrEmptyTable is returned when swapping in a shadow table without any routes
+	ErrEmptyTable = errors.New("shadow table is empty")
+	// ErrNoSwap is returned when rolling back without a swap to roll back
+	ErrNoSwap = errors.New("no swap to roll back")
+	// ErrSwapConflict is returned when the table is swapped or rolled back by another replica
+	// during a swap
+	ErrSwapConflict = errors.New("routing table changed during the swap")
+
+	// maxConcurrentStats is the number of instances whose stats are read at once
+	maxConcurrentStats = 32
+)
+
+// callStats are the number of requests an instance has handled and how many of them errored
+type callStats struct {
+	Requests uint64
+	Errors   uint64
+}
+
+// statsFunc returns the call stats of the instance of the service at the address
+type statsFunc func(service, address string) (callStats, error)
+
+// debugStats reads the call stats of the instance from its debug handler
+func debugStats(service, address string) (callStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultStatsTimeout)
+	defer cancel()
+
+	var rsp pb.StatsResponse
+	req := client.NewRequest(service, "Debug.Stats", &pb.StatsRequest{})
+	if err := client.Call(ctx, req, &rsp, goclient.WithAddress(address)); err != nil {
+		return callStats{}, err
+	}
+	return callStats{Requests: rsp.Requests, Errors: rsp.Errors}, nil
+}
+
+// shadowState is the swapped in table, written to the store so every replica serves it
+type shadowState struct {
+	// Generation is incremented on each swap and rollback
+	Generation int64
+	// Active is the table swapped in, the live table is used if nil
+	Active []router.Route
+	// Previous is the table in use before the last swap
+	Previous []router.Route
+	// Swapped is true if there is a swap which can be rolled back
+	Swapped bool
+	// Baseline is the error rate of the previous table, which the swapped in table is compared
+	// against until the deadline
+	Baseline  float64
+	Threshold float64
+	Deadline  time.Time
+	// Stats of the instances of the swapped in table at the time of the swap, the error rate
+	// is measured from
+	Stats map[string]callStats
+}
+
+// shadowRouter builds a shadow routing table which is swapped in atomically, rather than
+// mutating the live table route by route. Once swapped in, lookups are served from the shadow
+// table, which follows the changes to the live table, and the swap is rolled back if the error
+// rate of the calls to its routes spikes. The tables are kept in the store so the replicas of
+// the router serve the same table.
+type shadowRouter struct {
+	router.Router
+	stats    statsFunc
+	interval time.Duration
+
+	// swapMtx serialises the swaps and rollbacks of the replica
+	swapMtx sync.Mutex
+
+	sync.RWMutex
+	// active is the table swapped in, the live table is used if nil. It's replaced rather than
+	// modified so lookups can range over it without the lock.
+	active []router.Route
+	// generation of the active table
+	generation int64
+	// watcher follows the changes to the live table while a table is swapped in
+	watcher router.Watcher
+}
+
+func newShadowRouter(r router.Router, stats statsFunc, interval time.Duration) *shadowRouter {
+	return &shadowRouter{
+		Router:   r,
+		stats:    stats,
+		interval: interval,
+	}
+}
+
+// Start syncing the table swapped in by any replica and checking its error rate
+func (s *shadowRouter) Start() {
+	go func() {
+		t := time.NewTicker(s.interval)
+		defer t.Stop()
+
+		for {
+			if err := s.sync(time.Now()); err != nil {
+				log.Errorf("Error syncing the shadow routing table: %v", err)
+			}
+			<-t.C
+		}
+	}()
+}
+
+// Lookup the routes of the service in the swapped in table, or the live table if there's none.
+// Services which aren't in the swapped in table are looked up in the live table.
+func (s *shadowRouter) Lookup(service string, opts ...router.LookupOption) ([]router.Route, error) {
+	s.RLock()
+	active := s.active
+	s.RUnlock()
+
+	if active == nil {
+		return s.Router.Lookup(service, opts...)
+	}
+
+	var routes []router.Route
+	for _, r := range active {
+		if r.Service == service {
+			routes = append(routes, r)
+		}
+	}
+	if len(routes) == 0 {
+		return s.Router.Lookup(service, opts...)
+	}
+	routes = router.Filter(routes, router.NewLookup(opts...))
+	if len(routes) == 0 {
+		return nil, router.ErrRouteNotFound
+	}
+	return routes, nil
+}
+
+// Stage the routes in the shadow table, returning the number of routes staged
+func (s *shadowRouter) Stage(routes []router.Route, replace bool) (int, error) {
+	s.swapMtx.Lock()
+	defer s.swapMtx.Unlock()
+
+	var staged []router.Route
+	if !replace {
+		if err := readShadow(shadowStagedKey, &staged); err != nil {
+			return 0, err
+		}
+	}
+
+	// routes which already exist are replaced
+	for _, r := range routes {
+		staged = upsertRoute(staged, r)
+	}
+
+	if err := writeShadow(shadowStagedKey, staged); err != nil {
+		return 0, err
+	}
+	return len(staged), nil
+}
+
+// Swap the shadow table in and watch its error rate for the window, rolling back if it
+// exceeds the error rate of the previous table by the threshold
+func (s *shadowRouter) Swap(threshold float64, window time.Duration) error {
+	s.swapMtx.Lock()
+	defer s.swapMtx.Unlock()
+
+	var staged []router.Route
+	if err := readShadow(shadowStagedKey, &staged); err != nil {
+		return err
+	}
+	if len(staged) == 0 {
+		return ErrEmptyTable
+	}
+
+	var state shadowState
+	if err := readShadow(shadowStateKey, &state); err != nil {
+		return err
+	}
+	current := state.Active
+	if current == nil {
+		live, err := s.Router.Table().Read()
+		if err != nil {
+			return err
+		}
+		current = live
+	}
+
+	// measure the error rate of the current table to compare the new table against, and the
+	// stats of the new table the error rate is measured from
+	baseline := errorRate(s.readStats(current), nil)
+	stats := s.readStats(staged)
+
+	// another replica may have swapped or rolled back the table in the meantime
+	var latest shadowState
+	if err := readShadow(shadowStateKey, &latest); err != nil {
+		return err
+	}
+	if latest.Generation != state.Generation {
+		return ErrSwapConflict
+	}
+
+	next := shadowState{
+		Generation: state.Generation + 1,
+		Active:     staged,
+		Previous:   state.Active,
+		Swapped:    true,
+		Baseline:   baseline,
+		Threshold:  threshold,
+		Deadline:   time.Now().Add(window),
+		Stats:      stats.stats,
+	}
+	if err := writeShadow(shadowStateKey, next); err != nil {
+		return err
+	}
+	if err := store.Delete(shadowStagedKey); err != nil && err != gostore.ErrNotFound {
+		log.Errorf("Error deleting the staged routing table: %v", err)
+	}
+	s.apply(next)
+
+	log.Infof("Swapped in a routing table of %d routes, error rate of the previous table %.2f", len(staged), baseline)
+	return nil
+}
+
+// Rollback to the table in use before the last swap
+func (s *shadowRouter) Rollback() error {
+	s.swapMtx.Lock()
+	defer s.swapMtx.Unlock()
+
+	var state shadowState
+	if err := readShadow(shadowStateKey, &state); err != nil {
+		return err
+	}
+	return s.rollback(state)
+}
+
+// rollback the swap of the state. The swap lock must be held.
+func (s *shadowRouter) rollback(state shadowState) error {
+	if !state.Swapped {
+		return ErrNoSwap
+	}
+
+	next := shadowState{
+		Generation: state.Generation + 1,
+		Active:     state.Previous,
+	}
+	if err := writeShadow(shadowStateKey, next); err != nil {
+		return err
+	}
+	s.apply(next)
+	return nil
+}
+
+// sync the table swapped in by any replica, rolling the swap back if its error rate exceeds
+// the threshold during the swap window
+func (s *shadowRouter) sync(now time.Time) error {
+	s.swapMtx.Lock()
+	defer s.swapMtx.Unlock()
+
+	var state shadowState
+	if err := readShadow(shadowStateKey, &state); err != nil {
+		return err
+	}
+
+	s.RLock()
+	current := s.generation == state.Generation
+	s.RUnlock()
+	if !current {
+		s.apply(state)
+	}
+
+	if !state.Swapped || now.After(state.Deadline) {
+		return nil
+	}
+	rate := errorRate(s.readStats(state.Active), state.Stats)
+	if rate <= state.Baseline+state.Threshold {
+		return nil
+	}
+
+	log.Errorf("Rolling back the routing table swap, error rate %.2f exceeds %.2f", rate, state.Baseline+state.Threshold)
+	return s.rollback(state)
+}
+
+// apply the state to the replica, following the changes to the live table if a table is
+// swapped in
+func (s *shadowRouter) apply(state shadowState) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.watcher != nil {
+		s.watcher.Stop()
+		s.watcher = nil
+	}
+	s.active = state.Active
+	s.generation = state.Generation
+	if s.active == nil {
+		return
+	}
+
+	w, err := s.Router.Watch()
+	if err != nil {
+		log.Errorf("Error watching the live routing table: %v", err)
+		return
+	}
+	s.watcher = w
+	go s.follow(w, state.Generation)
+}
+
+// follow the changes to the live table, applying them to the swapped in table until the
+// watcher is stopped
+func (s *shadowRouter) follow(w router.Watcher, gen int64) {
+	for {
+		ev, err := w.Next()
+		if err != nil {
+			return
+		}
+
+		s.Lock()
+		if s.generation != gen {
+			s.Unlock()
+			return
+		}
+		active := make([]router.Route, 0, len(s.active))
+		for _, r := range s.active {
+			if r.Hash() != ev.Route.Hash() {
+				active = append(active, r)
+			}
+		}
+		if ev.Type != router.Delete {
+			active = append(active, ev.Route)
+		}
+		s.active = active
+		s.Unlock()
+	}
+}
+
+// instanceStats are the call stats of the instances of a table
+type instanceStats struct {
+	stats map[string]callStats
+	// unreachable is the number of instances whose stats couldn't be read
+	unreachable int
+	total       int
+}
+
+// readStats reads the call stats of the instances of the routes
+func (s *shadowRouter) readStats(routes []router.Route) instanceStats {
+	instances := make(map[string]string)
+	for _, r := range routes {
+		if len(r.Address) > 0 {
+			instances[r.Address] = r.Service
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var unreachable int
+	stats := make(map[string]callStats, len(instances))
+	sem := make(chan struct{}, maxConcurrentStats)
+
+	for addr, srv := range instances {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(srv, addr string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			st, err := s.stats(srv, addr)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				log.Debugf("Error reading the stats of %v at %v: %v", srv, addr, err)
+				unreachable++
+				return
+			}
+			stats[addr] = st
+		}(srv, addr)
+	}
+
+	wg.Wait()
+	return instanceStats{stats: stats, unreachable: unreachable, total: len(instances)}
+}
+
+// errorRate returns the larger of the proportion of the instances which can't be reached and
+// the proportion of the calls they handled since the stats of the start which errored.
+// Instances missing from the start are measured since they started.
+func errorRate(is instanceStats, start map[string]callStats) float64 {
+	var rate float64
+	if is.total > 0 {
+		rate = float64(is.unreachable) / float64(is.total)
+	}
+
+	var requests, errs uint64
+	for addr, st := range is.stats {
+		from := start[addr]
+		// the counters reset when an instance restarts
+		if st.Requests < from.Requests || st.Errors < from.Errors {
+			from = callStats{}
+		}
+		requests += st.Requests - from.Requests
+		errs += st.Errors - from.Errors
+	}
+	if requests > 0 && float64(errs)/float64(requests) > rate {
+		rate = float64(errs) / float64(requests)
+	}
+	return rate
+}
+
+// upsertRoute replaces the route in the routes if it exists, or appends it
+func upsertRoute(routes []router.Route, r router.Route) []router.Route {
+	for i, rt := range routes {
+		if rt.Hash() == r.Hash() {
+			routes[i] = r
+			return routes
+		}
+	}
+	return append(routes, r)
+}
+
+// readShadow decodes the record into v, leaving it unchanged if there's no record
+func readShadow(key string, v interface{}) error {
+	recs, err := store.Read(key)
+	if err == gostore.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(recs[0].Value, v)
+}
+
+func writeShadow(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: key, Value: b})
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/router"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/store"
+)
+
+type testTable struct {
+	router.Table
+	routes []router.Route
+}
+
+func (t *testTable) Read(opts ...router.ReadOption) ([]router.Route, error) {
+	return t.routes, nil
+}
+
+type testWatcher struct {
+	events chan *router.Event
+	once   sync.Once
+	exit   chan bool
+}
+
+func (w *testWatcher) Next() (*router.Event, error) {
+	select {
+	case ev := <-w.events:
+		return ev, nil
+	case <-w.exit:
+		return nil, router.ErrWatcherStopped
+	}
+}
+
+func (w *testWatcher) Chan() (<-chan *router.Event, error) {
+	return w.events, nil
+}
+
+func (w *testWatcher) Stop() {
+	w.once.Do(func() { close(w.exit) })
+}
+
+type testRouter struct {
+	router.Router
+	table *testTable
+
+	sync.Mutex
+	watchers []*testWatcher
+}
+
+func (r *testRouter) Table() router.Table {
+	return r.table
+}
+
+func (r *testRouter) Lookup(service string, opts ...router.LookupOption) ([]router.Route, error) {
+	var routes []router.Route
+	for _, rt := range r.table.routes {
+		if rt.Service == service {
+			routes = append(routes, rt)
+		}
+	}
+	if routes = router.Filter(routes, router.NewLookup(opts...)); len(routes) == 0 {
+		return nil, router.ErrRouteNotFound
+	}
+	return routes, nil
+}
+
+func (r *testRouter) Watch(opts ...router.WatchOption) (router.Watcher, error) {
+	r.Lock()
+	defer r.Unlock()
+	w := &testWatcher{events: make(chan *router.Event), exit: make(chan bool)}
+	r.watchers = append(r.watchers, w)
+	return w, nil
+}
+
+// publish the event to the watchers which haven't been stopped
+func (r *testRouter) publish(ev *router.Event) {
+	r.Lock()
+	ws := r.watchers
+	r.Unlock()
+	for _, w := range ws {
+		select {
+		case w.events <- ev:
+		case <-w.exit:
+		}
+	}
+}
+
+type testStats struct {
+	sync.Mutex
+	stats map[string]callStats
+}
+
+// stats of the instances, addresses in the 10.1 range are unreachable
+func (t *testStats) read(service, address string) (callStats, error) {
+	if address[:4] == "10.1" {
+		return callStats{}, errors.New("connection refused")
+	}
+	t.Lock()
+	defer t.Unlock()
+	return t.stats[address], nil
+}
+
+func (t *testStats) set(address string, requests, errs uint64) {
+	t.Lock()
+	defer t.Unlock()
+	t.stats[address] = callStats{Requests: requests, Errors: errs}
+}
+
+func lookupAddress(s *shadowRouter, service string) string {
+	routes, err := s.Lookup(service)
+	if err != nil || len(routes) != 1 {
+		return ""
+	}
+	return routes[0].Address
+}
+
+func TestShadowRouter(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	live := &testRouter{table: &testTable{routes: []router.Route{
+		{Service: "foo", Address: "10.0.0.1:8080", Link: router.DefaultLink},
+		{Service: "bar", Address: "10.0.0.9:8080", Link: router.DefaultLink},
+	}}}
+	stats := &testStats{stats: make(map[string]callStats)}
+	s := newShadowRouter(live, stats.read, time.Millisecond*10)
+
+	if err := s.Swap(0.1, time.Minute); err != ErrEmptyTable {
+		t.Fatalf("Expected an empty table error, got %v", err)
+	}
+	if err := s.Rollback(); err != ErrNoSwap {
+		t.Fatalf("Expected a no swap error, got %v", err)
+	}
+
+	// stage a table and swap it in
+	if n, err := s.Stage([]router.Route{{Service: "foo", Address: "10.0.0.2:8080", Link: router.DefaultLink}}, false); err != nil || n != 1 {
+		t.Fatalf("Expected 1 staged route, got %v: %v", n, err)
+	}
+	if addr := lookupAddress(s, "foo"); addr != "10.0.0.1:8080" {
+		t.Fatalf("Expected lookups to use the live table before the swap, got %v", addr)
+	}
+	if err := s.Swap(0.1, time.Minute); err != nil {
+		t.Fatalf("Unexpected error swapping: %v", err)
+	}
+	if addr := lookupAddress(s, "foo"); addr != "10.0.0.2:8080" {
+		t.Fatalf("Expected lookups to use the swapped table, got %v", addr)
+	}
+	if addr := lookupAddress(s, "bar"); addr != "10.0.0.9:8080" {
+		t.Fatalf("Expected services missing from the swapped table to use the live table, got %v", addr)
+	}
+
+	// the other replicas serve the table swapped in once they sync
+	replica := newShadowRouter(live, stats.read, time.Millisecond*10)
+	if err := replica.sync(time.Now()); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+	if addr := lookupAddress(replica, "foo"); addr != "10.0.0.2:8080" {
+		t.Fatalf("Expected the replica to use the swapped table, got %v", addr)
+	}
+
+	// changes to the live table are applied to the swapped table
+	live.publish(&router.Event{Type: router.Delete, Route: router.Route{Service: "foo", Address: "10.0.0.2:8080", Link: router.DefaultLink}})
+	live.publish(&router.Event{Type: router.Create, Route: router.Route{Service: "foo", Address: "10.0.0.3:8080", Link: router.DefaultLink}})
+	deadline := time.Now().Add(time.Second)
+	for lookupAddress(s, "foo") != "10.0.0.3:8080" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the swapped table to follow the live table, got %v", lookupAddress(s, "foo"))
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// a manual rollback returns to the live table
+	if err := s.Rollback(); err != nil {
+		t.Fatalf("Unexpected error rolling back: %v", err)
+	}
+	if addr := lookupAddress(s, "foo"); addr != "10.0.0.1:8080" {
+		t.Fatalf("Expected lookups to use the live table after rollback, got %v", addr)
+	}
+
+	// a table whose calls error is rolled back
+	stats.set("10.0.0.1:8080", 100, 1)
+	stats.set("10.0.0.4:8080", 10, 0)
+	s.Stage([]router.Route{{Service: "foo", Address: "10.0.0.4:8080", Link: router.DefaultLink}}, true)
+	if err := s.Swap(0.1, time.Minute); err != nil {
+		t.Fatalf("Unexpected error swapping: %v", err)
+	}
+	stats.set("10.0.0.4:8080", 20, 1)
+	if err := s.sync(time.Now()); err != nil || lookupAddress(s, "foo") != "10.0.0.4:8080" {
+		t.Fatalf("Expected the swap to be kept while the error rate is below the threshold: %v", err)
+	}
+	stats.set("10.0.0.4:8080", 30, 6)
+	if err := s.sync(time.Now()); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+	if addr := lookupAddress(s, "foo"); addr != "10.0.0.1:8080" {
+		t.Fatalf("Expected the swap to be rolled back, got %v", addr)
+	}
+
+	// a table of unreachable routes is rolled back
+	s.Stage([]router.Route{{Service: "foo", Address: "10.1.0.1:8080", Link: router.DefaultLink}}, true)
+	if err := s.Swap(0.1, time.Minute); err != nil {
+		t.Fatalf("Unexpected error swapping: %v", err)
+	}
+	if err := s.sync(time.Now()); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+	if addr := lookupAddress(s, "foo"); addr != "10.0.0.1:8080" {
+		t.Fatalf("Expected the swap to be rolled back, got %v", addr)
+	}
+
+	// the replica follows the rollback
+	if err := replica.sync(time.Now()); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+	if addr := lookupAddress(replica, "foo"); addr != "10.0.0.1:8080" {
+		t.Fatalf("Expected the replica to use the live table, got %v", addr)
+	}
+}
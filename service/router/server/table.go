@@ -2,18 +2,25 @@ package server
 
 import (
 	"context"
+	"time"
 
 	"github.com/micro/go-micro/v3/router"
 	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry/util"
 	pb "github.com/micro/micro/v3/service/router/proto"
 )
 
 type Table struct {
 	Router router.Router
+	// Shadow builds the shadow table which is swapped in
+	Shadow *shadowRouter
+	// Grace keeps the routes the registry removed for a grace period, routes are deleted
+	// immediately if nil
+	Grace *graceTable
 }
 
 func (t *Table) Create(ctx context.Context, route *pb.Route, resp *pb.CreateResponse) error {
-	err := t.Router.Table().Create(router.Route{
+	r := router.Route{
 		Service:  route.Service,
 		Address:  route.Address,
 		Gateway:  route.Gateway,
@@ -22,7 +29,16 @@ func (t *Table) Create(ctx context.Context, route *pb.Route, resp *pb.CreateResp
 		Link:     route.Link,
 		Metric:   route.Metric,
 		Metadata: route.Metadata,
-	})
+	}
+
+	// a suspect route which is advertised again is still in the table, so it's updated to
+	// clear its suspect mark
+	var err error
+	if t.Grace != nil && t.Grace.Restore(r) {
+		err = t.Router.Table().Update(r)
+	} else {
+		err = t.Router.Table().Create(r)
+	}
 	if err != nil {
 		return errors.InternalServerError("router.Table.Create", "failed to create route: %s", err)
 	}
@@ -31,7 +47,7 @@ func (t *Table) Create(ctx context.Context, route *pb.Route, resp *pb.CreateResp
 }
 
 func (t *Table) Update(ctx context.Context, route *pb.Route, resp *pb.UpdateResponse) error {
-	err := t.Router.Table().Update(router.Route{
+	r := router.Route{
 		Service:  route.Service,
 		Address:  route.Address,
 		Gateway:  route.Gateway,
@@ -40,8 +56,12 @@ func (t *Table) Update(ctx context.Context, route *pb.Route, resp *pb.UpdateResp
 		Link:     route.Link,
 		Metric:   route.Metric,
 		Metadata: route.Metadata,
-	})
-	if err != nil {
+	}
+	if t.Grace != nil {
+		t.Grace.Restore(r)
+	}
+
+	if err := t.Router.Table().Update(r); err != nil {
 		return errors.InternalServerError("router.Table.Update", "failed to update route: %s", err)
 	}
 
@@ -49,7 +69,7 @@ func (t *Table) Update(ctx context.Context, route *pb.Route, resp *pb.UpdateResp
 }
 
 func (t *Table) Delete(ctx context.Context, route *pb.Route, resp *pb.DeleteResponse) error {
-	err := t.Router.Table().Delete(router.Route{
+	r := router.Route{
 		Service:  route.Service,
 		Address:  route.Address,
 		Gateway:  route.Gateway,
@@ -58,7 +78,18 @@ func (t *Table) Delete(ctx context.Context, route *pb.Route, resp *pb.DeleteResp
 		Link:     route.Link,
 		Metric:   route.Metric,
 		Metadata: route.Metadata,
-	})
+	}
+
+	// routes removed by the registry rather than their service are verified before they're
+	// deleted, since the removal may have been caused by a lost heartbeat
+	var err error
+	if t.Grace != nil && len(r.Metadata[util.RemovalKey]) > 0 {
+		err = t.Grace.Withdraw(r)
+	} else if t.Grace != nil {
+		err = t.Grace.Delete(r)
+	} else {
+		err = t.Router.Table().Delete(r)
+	}
 	if err != nil {
 		return errors.InternalServerError("route.Table.Delete", "failed to delete route: %s", err)
 	}
@@ -91,3 +122,57 @@ func (t *Table) Read(ctx context.Context, req *pb.ReadRequest, resp *pb.ReadResp
 
 	return nil
 }
+
+func (t *Table) Stage(ctx context.Context, req *pb.StageRequest, resp *pb.StageResponse) error {
+	routes := make([]router.Route, 0, len(req.Routes))
+	for _, route := range req.Routes {
+		routes = append(routes, router.Route{
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Router:   route.Router,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
+		})
+	}
+
+	n, err := t.Shadow.Stage(routes, req.Replace)
+	if err != nil {
+		return errors.InternalServerError("router.Table.Stage", "failed to stage routes: %s", err)
+	}
+	resp.Count = int64(n)
+	return nil
+}
+
+func (t *Table) Swap(ctx context.Context, req *pb.SwapRequest, resp *pb.SwapResponse) error {
+	threshold := DefaultErrorThreshold
+	if req.ErrorThreshold > 0 {
+		threshold = req.ErrorThreshold
+	}
+	window := DefaultSwapWindow
+	if req.Window > 0 {
+		window = time.Duration(req.Window) * time.Second
+	}
+
+	if err := t.Shadow.Swap(threshold, window); err == ErrEmptyTable {
+		return errors.BadRequest("router.Table.Swap", err.Error())
+	} else if err == ErrSwapConflict {
+		return errors.Conflict("router.Table.Swap", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("router.Table.Swap", "failed to swap table: %s", err)
+	}
+
+	return nil
+}
+
+func (t *Table) Rollback(ctx context.Context, req *pb.RollbackRequest, resp *pb.RollbackResponse) error {
+	if err := t.Shadow.Rollback(); err == ErrNoSwap {
+		return errors.BadRequest("router.Table.Rollback", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("router.Table.Rollback", "failed to rollback table: %s", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ package runtime
 import (
 	"github.com/micro/cli/v2"
 	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
 )
 
 // flags is shared flags so we don't have to continually re-add
@@ -34,6 +35,12 @@ var flags = []cli.Flag{
 	},
 }
 
+// overrideFreezeFlag overrides a deploy freeze of the namespace, which requires an admin account
+var overrideFreezeFlag = &cli.StringFlag{
+	Name:  "override-freeze",
+	Usage: "Deploy during a freeze of the namespace giving the reason, which is recorded in the audit log",
+}
+
 func init() {
 	cmd.Register(
 		&cli.Command{
@@ -46,8 +53,15 @@ func init() {
 			micro run ../path/to/folder # deploy local folder to your local micro server
 			micro run helloworld # deploy latest version, translates to micro run github.com/micro/services/helloworld
 			micro run helloworld@9342934e6180 # deploy certain version
-			micro run helloworld@branchname	# deploy certain branch`,
-			Flags:  flags,
+			micro run helloworld@branchname	# deploy certain branch
+			micro run . --watch # rebuild and restart the service when the local folder changes`,
+			Flags: append(flags,
+				&cli.BoolFlag{
+					Name:  "watch",
+					Usage: "Rebuild and restart the service when its local source changes",
+				},
+				overrideFreezeFlag,
+			),
 			Action: runService,
 		},
 		&cli.Command{
@@ -58,8 +72,21 @@ func init() {
 			micro update .  # deploy local folder to your local micro server
 			micro update ../path/to/folder # deploy local folder to your local micro server
 			micro update helloworld # deploy master branch, translates to micro update github.com/micro/services/helloworld
-			micro update helloworld@branchname	# deploy certain branch`,
-			Flags:  flags,
+			micro update helloworld@branchname	# deploy certain branch
+			micro update helloworld --recycle="age=24h; growth=50%" # restart instances daily or once their memory grows by half
+			micro update helloworld --verify=verify.json # roll the update back unless it passes the checks in verify.json
+			micro update helloworld --override-freeze="hotfix for the checkout outage" # deploy during a freeze`,
+			Flags: append(flags,
+				&cli.StringFlag{
+					Name:  "recycle",
+					Usage: "Recycle instances by age and memory, e.g. \"age=24h; memory=512MiB; growth=50%\". A blank policy removes it",
+				},
+				&cli.StringFlag{
+					Name:  "verify",
+					Usage: "Verify updates with smoke test calls, health checks and metric thresholds, rolling them back if they fail. Set to the JSON policy or a file containing it, a blank policy removes it",
+				},
+				overrideFreezeFlag,
+			),
 			Action: updateService,
 		},
 		&cli.Command{
@@ -110,5 +137,17 @@ func init() {
 				},
 			},
 		},
+		&cli.Command{
+			Name:   "freeze",
+			Usage:  "Manage the deploy freezes of the namespace",
+			Action: helper.UnexpectedSubcommand,
+			Subcommands: []*cli.Command{
+				{
+					Name:   "overrides",
+					Usage:  "List the overrides of the freezes recorded in the audit log; micro freeze overrides",
+					Action: listFreezeOverrides,
+				},
+			},
+		},
 	)
 }
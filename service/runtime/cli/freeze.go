@@ -0,0 +1,47 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	muclient "github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+// listFreezeOverrides prints the overrides of the deploy freezes of the namespace recorded in the
+// audit log
+func listFreezeOverrides(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	rt := pb.NewRuntimeService("runtime", muclient.DefaultClient)
+	rsp, err := rt.FreezeOverrides(context.DefaultContext, &pb.FreezeOverridesRequest{Namespace: ns}, goclient.WithAuthToken())
+	if err != nil {
+		return err
+	}
+	if len(rsp.Overrides) == 0 {
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "TIME\tWINDOW\tACTION\tACCOUNT\tREASON")
+	for _, o := range rsp.Overrides {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			time.Unix(o.Timestamp, 0).UTC().Format(time.RFC3339),
+			o.Window,
+			o.Action,
+			o.Account,
+			o.Reason,
+		)
+	}
+	return writer.Flush()
+}
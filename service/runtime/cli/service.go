@@ -17,6 +17,7 @@ import (
 
 	"github.com/micro/cli/v2"
 	golog "github.com/micro/go-micro/v3/logger"
+	"github.com/micro/go-micro/v3/metadata"
 	goruntime "github.com/micro/go-micro/v3/runtime"
 	"github.com/micro/go-micro/v3/runtime/local/source/git"
 	"github.com/micro/go-micro/v3/util/file"
@@ -28,7 +29,10 @@ import (
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/freeze"
+	"github.com/micro/micro/v3/service/runtime/recycle"
 	"github.com/micro/micro/v3/service/runtime/server"
+	"github.com/micro/micro/v3/service/runtime/verify"
 	"google.golang.org/grpc/status"
 )
 
@@ -158,6 +162,9 @@ func runService(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if ctx.Bool("watch") && !source.Local {
+		return fmt.Errorf("Only local sources can be watched")
+	}
 	var newSource string
 	if source.Local {
 		if cliutil.IsPlatform(ctx) {
@@ -249,10 +256,18 @@ func runService(ctx *cli.Context) error {
 		Metadata: make(map[string]string),
 	}
 
+	if reason := ctx.String("override-freeze"); len(reason) > 0 {
+		opts = append(opts, goruntime.CreateContext(metadata.Set(context.DefaultContext, freeze.OverrideKey, reason)))
+	}
+
 	if err := runtime.Create(service, opts...); err != nil {
 		return err
 	}
 
+	if ctx.Bool("watch") {
+		return watchService(ctx, source, service, ns)
+	}
+
 	if runtime.DefaultRuntime.String() == "local" {
 		// we need to wait
 		ch := make(chan os.Signal, 1)
@@ -411,6 +426,42 @@ func updateService(ctx *cli.Context) error {
 		Version: source.Ref,
 	}
 
+	// set the recycling policy, which the runtime evaluates
+	if ctx.IsSet("recycle") {
+		policy := strings.TrimSpace(ctx.String("recycle"))
+		if len(policy) > 0 {
+			if _, err := recycle.Parse(policy); err != nil {
+				return fmt.Errorf("Invalid recycling policy: %v", err)
+			}
+		}
+		if service.Metadata == nil {
+			service.Metadata = make(map[string]string)
+		}
+		service.Metadata[recycle.Key] = policy
+	}
+
+	// set the verification policy, which the runtime checks updates against
+	if ctx.IsSet("verify") {
+		policy := strings.TrimSpace(ctx.String("verify"))
+		// the policy can be read from a file
+		if len(policy) > 0 && !strings.HasPrefix(policy, "{") {
+			b, err := ioutil.ReadFile(policy)
+			if err != nil {
+				return fmt.Errorf("Error reading the verification policy: %v", err)
+			}
+			policy = strings.TrimSpace(string(b))
+		}
+		if len(policy) > 0 {
+			if _, err := verify.Parse(policy); err != nil {
+				return fmt.Errorf("Invalid verification policy: %v", err)
+			}
+		}
+		if service.Metadata == nil {
+			service.Metadata = make(map[string]string)
+		}
+		service.Metadata[verify.Key] = policy
+	}
+
 	// determine the namespace
 	ns, err := namespace.Get(util.GetEnv(ctx).Name)
 	if err != nil {
@@ -422,7 +473,10 @@ func updateService(ctx *cli.Context) error {
 	if ok {
 		opts = append(opts, goruntime.UpdateSecret(credentialsKey, gitCreds))
 	}
-	return runtime.Update(service, goruntime.UpdateNamespace(ns))
+	if reason := ctx.String("override-freeze"); len(reason) > 0 {
+		opts = append(opts, goruntime.UpdateContext(metadata.Set(context.DefaultContext, freeze.OverrideKey, reason)))
+	}
+	return runtime.Update(service, opts...)
 }
 
 func getService(ctx *cli.Context) error {
@@ -528,6 +582,13 @@ func getService(ctx *cli.Context) error {
 		if status == "error" {
 			metadata = fmt.Sprintf("%v, error=%v", metadata, parse(service.Metadata["error"]))
 		}
+		// show the outcome of the last update of services whose updates are verified
+		if update := service.Metadata[verify.StatusKey]; len(update) > 0 {
+			metadata = fmt.Sprintf("%v, update=%v", metadata, update)
+			if reason := service.Metadata[verify.ErrorKey]; len(reason) > 0 {
+				metadata = fmt.Sprintf("%v (%v)", metadata, reason)
+			}
+		}
 
 		// parse when the service was started
 		updated := parse(timeAgo(service.Metadata["started"]))
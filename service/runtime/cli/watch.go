@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/micro/cli/v2"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/runtime/local/source/git"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+var (
+	// DefaultWatchDelay is how long to wait after a file changes before rebuilding, so saving
+	// several files at once results in a single rebuild
+	DefaultWatchDelay = time.Millisecond * 500
+)
+
+// shouldRebuild returns true if a change to the file requires the service to be rebuilt
+func shouldRebuild(name string) bool {
+	base := filepath.Base(name)
+	if strings.HasPrefix(base, ".") || strings.HasSuffix(base, "_test.go") {
+		return false
+	}
+	return strings.HasSuffix(base, ".go") || base == "go.mod" || base == "go.sum"
+}
+
+// addDirs adds the directory and the directories below it to the watcher, skipping hidden
+// and vendored directories
+func addDirs(w *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+		return w.Add(path)
+	})
+}
+
+// build the source locally, streaming the build errors to the terminal
+func build(source *git.Source) error {
+	cmd := exec.Command("go", "build", "-o", os.DevNull, ".")
+	cmd.Dir = source.FullPath
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// watchService rebuilds the service each time its source changes and restarts it with the
+// new build until interrupted. The service is updated rather than recreated, so it keeps its
+// name and version and is registered as the same service. Changes which don't build are
+// reported and the running service is left as it is.
+func watchService(ctx *cli.Context, source *git.Source, service *goruntime.Service, ns string) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := addDirs(w, source.FullPath); err != nil {
+		return err
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+
+	fmt.Printf("Watching %v for changes\n", source.FullPath)
+
+	// the timer is started by the first change and the service is rebuilt once it fires
+	timer := time.NewTimer(DefaultWatchDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-sig:
+			return runtime.Delete(service, goruntime.DeleteNamespace(ns))
+		case err := <-w.Errors:
+			fmt.Printf("Error watching %v: %v\n", source.FullPath, err)
+		case ev := <-w.Events:
+			// watch the directories created after the watch started
+			if ev.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					addDirs(w, ev.Name)
+				}
+			}
+			if shouldRebuild(ev.Name) {
+				timer.Reset(DefaultWatchDelay)
+			}
+		case <-timer.C:
+			fmt.Println("Change detected, rebuilding")
+			if err := build(source); err != nil {
+				fmt.Println("Build failed, waiting for changes")
+				continue
+			}
+
+			src, err := upload(ctx, source)
+			if err != nil {
+				fmt.Printf("Error uploading %v: %v\n", source.FullPath, err)
+				continue
+			}
+			service.Source = src
+			if err := runtime.Update(service, goruntime.UpdateNamespace(ns)); err != nil {
+				fmt.Printf("Error restarting %v: %v\n", service.Name, err)
+				continue
+			}
+			fmt.Printf("Restarted %v\n", service.Name)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package runtime
+
+import "testing"
+
+func TestShouldRebuild(t *testing.T) {
+	tt := map[string]bool{
+		"main.go":                 true,
+		"handler/handler.go":      true,
+		"go.mod":                  true,
+		"go.sum":                  true,
+		"handler/handler_test.go": false,
+		"README.md":               false,
+		".main.go.swp":            false,
+		"proto/foo.proto":         false,
+	}
+	for name, expected := range tt {
+		if got := shouldRebuild(name); got != expected {
+			t.Errorf("Expected shouldRebuild(%v) to be %v, got %v", name, expected, got)
+		}
+	}
+}
@@ -62,7 +62,11 @@ func (s *svc) Create(svc *runtime.Service, opts ...runtime.CreateOption) error {
 		},
 	}
 
-	if _, err := s.runtime.Create(context.DefaultContext, req, goclient.WithAuthToken()); err != nil {
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.DefaultContext
+	}
+	if _, err := s.runtime.Create(ctx, req, goclient.WithAuthToken()); err != nil {
 		return err
 	}
 
@@ -216,7 +220,11 @@ func (s *svc) Update(svc *runtime.Service, opts ...runtime.UpdateOption) error {
 		},
 	}
 
-	if _, err := s.runtime.Update(context.DefaultContext, req, goclient.WithAuthToken()); err != nil {
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.DefaultContext
+	}
+	if _, err := s.runtime.Update(ctx, req, goclient.WithAuthToken()); err != nil {
 		return err
 	}
 
@@ -0,0 +1,390 @@
+// Package freeze enforces deploy freeze windows, during which services can't be run or updated
+// in a namespace. The windows are read from the "runtime.freezes" config, keyed by namespace,
+// "*" applying to every namespace, for example:
+//
+//	{
+//		"*": [{"name": "weekend", "window": "fri 18:00 to mon 06:00"}],
+//		"prod": [{"name": "holidays", "window": "2020-12-24 to 2021-01-02"}]
+//	}
+//
+// A window is either weekly, from a day and time to a day and time, or a change calendar entry
+// from a date, optionally with a time, to a date. Times are UTC.
+//
+// A freeze can be overridden by an account with the OverrideScope which gives a reason in the
+// OverrideKey header. Overrides are recorded in the audit log, which micro freeze overrides lists.
+package freeze
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// OverrideKey is the header the reason a freeze is overridden is given in
+	OverrideKey = "Micro-Freeze-Override"
+	// Any is the namespace whose windows apply to every namespace
+	Any = "*"
+)
+
+var (
+	// DefaultPath is the path of the freeze windows in the config
+	DefaultPath = []string{"runtime", "freezes"}
+	// OverrideScope is the scope an account needs to override a freeze
+	OverrideScope = "admin"
+	// DefaultAuditRetention is how long overrides are kept in the audit log
+	DefaultAuditRetention = time.Hour * 24 * 365
+)
+
+var days = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+const week = time.Hour * 24 * 7
+
+// Window deploys are frozen in
+type Window struct {
+	// Name of the window, e.g. weekend
+	Name string
+	// Weekly is true if the window repeats every week, in which case Start and End are the
+	// offsets from the start of the week, Sunday 00:00
+	Weekly bool
+	Start  time.Duration
+	End    time.Duration
+	// From and To are the bounds of a window which doesn't repeat
+	From time.Time
+	To   time.Time
+}
+
+// Contains returns true if the time is in the window
+func (w Window) Contains(t time.Time) bool {
+	if !w.Weekly {
+		return !t.Before(w.From) && t.Before(w.To)
+	}
+	o := weekOffset(t.UTC())
+	if w.Start <= w.End {
+		return o >= w.Start && o < w.End
+	}
+	// the window wraps past the end of the week
+	return o >= w.Start || o < w.End
+}
+
+// Until returns when the window which contains the time ends
+func (w Window) Until(t time.Time) time.Time {
+	if !w.Weekly {
+		return w.To
+	}
+	t = t.UTC()
+	d := w.End - weekOffset(t)
+	if d <= 0 {
+		d += week
+	}
+	return t.Add(d).Truncate(time.Minute)
+}
+
+func (w Window) String() string {
+	if len(w.Name) > 0 {
+		return w.Name
+	}
+	if w.Weekly {
+		return formatOffset(w.Start) + " to " + formatOffset(w.End)
+	}
+	return w.From.Format(time.RFC3339) + " to " + w.To.Format(time.RFC3339)
+}
+
+// weekOffset returns the time since the start of the week
+func weekOffset(t time.Time) time.Duration {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return time.Duration(t.Weekday())*24*time.Hour + t.Sub(midnight)
+}
+
+func formatOffset(d time.Duration) string {
+	day := time.Weekday(d / (24 * time.Hour))
+	mins := int((d % (24 * time.Hour)) / time.Minute)
+	return fmt.Sprintf("%s %02d:%02d", strings.ToLower(day.String()[:3]), mins/60, mins%60)
+}
+
+// ParseWindow parses a window, e.g. "fri 18:00 to mon 06:00" or "2020-12-24 to 2021-01-02"
+func ParseWindow(s string) (Window, error) {
+	bounds := strings.SplitN(s, " to ", 2)
+	if len(bounds) != 2 {
+		return Window{}, fmt.Errorf("invalid window %q, expected start to end", s)
+	}
+	from, to := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+
+	if start, err := parseDayTime(from); err == nil {
+		end, err := parseDayTime(to)
+		if err != nil {
+			return Window{}, err
+		}
+		return Window{Weekly: true, Start: start, End: end}, nil
+	}
+
+	start, err := parseDate(from)
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := parseDate(to)
+	if err != nil {
+		return Window{}, err
+	}
+	if !end.After(start) {
+		return Window{}, fmt.Errorf("invalid window %q, the end is before the start", s)
+	}
+	return Window{From: start, To: end}, nil
+}
+
+// parseDayTime parses a day and time, e.g. fri 18:00, into the offset from the start of the week
+func parseDayTime(s string) (time.Duration, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("invalid day and time %q", s)
+	}
+	day, ok := days[fields[0]]
+	if !ok {
+		return 0, fmt.Errorf("invalid day %q", fields[0])
+	}
+	hm := strings.SplitN(fields[1], ":", 2)
+	if len(hm) != 2 {
+		return 0, fmt.Errorf("invalid time %q", fields[1])
+	}
+	h, err := strconv.Atoi(hm[0])
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid time %q", fields[1])
+	}
+	m, err := strconv.Atoi(hm[1])
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q", fields[1])
+	}
+	return time.Duration(day)*24*time.Hour + time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseDate parses a date, e.g. 2020-12-24, optionally with a time, e.g. 2020-12-24 18:00
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02 15:04", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return t, nil
+}
+
+// Parse the windows from their JSON encoding, keyed by namespace
+func Parse(b []byte) (map[string][]Window, error) {
+	var raw map[string][]struct {
+		Name   string `json:"name"`
+		Window string `json:"window"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	windows := make(map[string][]Window, len(raw))
+	for ns, entries := range raw {
+		for _, e := range entries {
+			w, err := ParseWindow(e.Window)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window for namespace %v: %v", ns, err)
+			}
+			w.Name = e.Name
+			windows[ns] = append(windows[ns], w)
+		}
+	}
+	return windows, nil
+}
+
+// Source returns the encoded windows, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the windows from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+// Checker checks whether deploys are frozen
+type Checker struct {
+	source Source
+
+	sync.RWMutex
+	// raw is the encoding the windows were last parsed from
+	raw     []byte
+	windows map[string][]Window
+}
+
+// NewChecker returns a checker of the windows read from the source
+func NewChecker(source Source) *Checker {
+	return &Checker{source: source}
+}
+
+// Frozen returns the window the namespace is frozen in at the time, false if it isn't frozen
+func (c *Checker) Frozen(namespace string, t time.Time) (Window, bool) {
+	windows := c.load()
+	for _, ns := range []string{namespace, Any} {
+		for _, w := range windows[ns] {
+			if w.Contains(t) {
+				return w, true
+			}
+		}
+	}
+	return Window{}, false
+}
+
+// load returns the windows, parsing them if they've changed. If they can't be parsed the
+// windows last parsed are used.
+func (c *Checker) load() map[string][]Window {
+	raw := c.source()
+
+	c.RLock()
+	if string(raw) == string(c.raw) {
+		defer c.RUnlock()
+		return c.windows
+	}
+	c.RUnlock()
+
+	windows := map[string][]Window{}
+	if len(raw) > 0 && string(raw) != "null" {
+		var err error
+		if windows, err = Parse(raw); err != nil {
+			logger.Errorf("Error parsing the freeze windows: %v", err)
+			c.RLock()
+			defer c.RUnlock()
+			return c.windows
+		}
+	}
+
+	c.Lock()
+	c.raw, c.windows = raw, windows
+	c.Unlock()
+	return windows
+}
+
+// ErrFrozen is returned by Check if the namespace is frozen and the freeze wasn't overridden
+type ErrFrozen struct {
+	Namespace string
+	Window    Window
+	Until     time.Time
+}
+
+func (e *ErrFrozen) Error() string {
+	return fmt.Sprintf("Deploys to namespace %v are frozen (%v) until %v. Give a reason in the %v header with the %v scope to override the freeze",
+		e.Namespace, e.Window, e.Until.Format(time.RFC3339), OverrideKey, OverrideScope)
+}
+
+// Check returns an ErrFrozen error if the namespace is frozen, unless the account in the
+// context has overridden the freeze, in which case the override is recorded in the audit log.
+// Action describes the change, e.g. "update helloworld".
+func (c *Checker) Check(ctx context.Context, namespace, action string) error {
+	now := time.Now()
+	w, ok := c.Frozen(namespace, now)
+	if !ok {
+		return nil
+	}
+	frozen := &ErrFrozen{Namespace: namespace, Window: w, Until: w.Until(now)}
+
+	reason, _ := metadata.Get(ctx, OverrideKey)
+	reason = strings.TrimSpace(reason)
+	if len(reason) == 0 {
+		return frozen
+	}
+	acc, ok := goauth.AccountFromContext(ctx)
+	if !ok || !hasScope(acc, OverrideScope) {
+		return frozen
+	}
+
+	logger.Warnf("Freeze %v of namespace %v overridden by %v to %v: %v", w, namespace, acc.ID, action, reason)
+	audit(&Override{
+		Namespace: namespace,
+		Window:    w.String(),
+		Action:    action,
+		Account:   acc.ID,
+		Issuer:    acc.Issuer,
+		Reason:    reason,
+		Timestamp: now.Unix(),
+	})
+	return nil
+}
+
+func hasScope(acc *goauth.Account, scope string) bool {
+	for _, s := range acc.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+const auditPrefix = "freeze/audit/"
+
+// Override of a freeze recorded in the audit log
+type Override struct {
+	Namespace string `json:"namespace"`
+	Window    string `json:"window"`
+	Action    string `json:"action"`
+	Account   string `json:"account"`
+	Issuer    string `json:"issuer"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// audit records the override, timestamps are zero padded so the keys sort in order
+func audit(o *Override) {
+	bytes, err := json.Marshal(o)
+	if err != nil {
+		logger.Errorf("Error marshaling freeze override: %v", err)
+		return
+	}
+	rec := &gostore.Record{
+		Key:    fmt.Sprintf("%s%s/%020d", auditPrefix, o.Namespace, time.Now().UnixNano()),
+		Value:  bytes,
+		Expiry: DefaultAuditRetention,
+	}
+	if err := store.Write(rec); err != nil {
+		logger.Errorf("Error writing freeze override to the audit log: %v", err)
+	}
+}
+
+// Overrides returns the overrides of freezes of the namespace in the audit log, oldest first
+func Overrides(namespace string) ([]*Override, error) {
+	recs, err := store.Read(auditPrefix+namespace+"/", gostore.ReadPrefix())
+	if err == gostore.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the keys are zero padded but stores don't guarantee the order they're read in
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Key < recs[j].Key })
+
+	overrides := make([]*Override, 0, len(recs))
+	for _, r := range recs {
+		var o Override
+		if err := json.Unmarshal(r.Value, &o); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, &o)
+	}
+	return overrides, nil
+}
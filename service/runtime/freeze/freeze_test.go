@@ -0,0 +1,97 @@
+package freeze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestWindow(t *testing.T) {
+	weekend, err := ParseWindow("fri 18:00 to mon 06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	holidays, err := ParseWindow("2020-12-24 to 2021-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2020-10-16 is a friday
+	tt := []struct {
+		Name     string
+		Window   Window
+		Time     string
+		Contains bool
+	}{
+		{Name: "FridayAfternoon", Window: weekend, Time: "2020-10-16T17:59:00Z", Contains: false},
+		{Name: "FridayEvening", Window: weekend, Time: "2020-10-16T18:00:00Z", Contains: true},
+		{Name: "Sunday", Window: weekend, Time: "2020-10-18T12:00:00Z", Contains: true},
+		{Name: "MondayMorning", Window: weekend, Time: "2020-10-19T05:59:00Z", Contains: true},
+		{Name: "MondayWorkday", Window: weekend, Time: "2020-10-19T06:00:00Z", Contains: false},
+		{Name: "Holidays", Window: holidays, Time: "2020-12-31T12:00:00Z", Contains: true},
+		{Name: "AfterHolidays", Window: holidays, Time: "2021-01-02T00:00:00Z", Contains: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			tm, _ := time.Parse(time.RFC3339, tc.Time)
+			if got := tc.Window.Contains(tm); got != tc.Contains {
+				t.Errorf("Expected %v, got %v", tc.Contains, got)
+			}
+		})
+	}
+
+	sat, _ := time.Parse(time.RFC3339, "2020-10-17T12:00:00Z")
+	if u := weekend.Until(sat); u.Format(time.RFC3339) != "2020-10-19T06:00:00Z" {
+		t.Errorf("Expected the freeze to end on monday morning, got %v", u)
+	}
+
+	for _, s := range []string{"fri 18:00", "fri 25:00 to mon 06:00", "2021-01-02 to 2020-12-24"} {
+		if _, err := ParseWindow(s); err == nil {
+			t.Errorf("Expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+
+	c := NewChecker(func() []byte {
+		return []byte(`{"*": [{"name": "always", "window": "2000-01-01 to 2100-01-01"}], "dev": []}`)
+	})
+	if _, ok := c.Frozen("dev", time.Now()); !ok {
+		t.Fatalf("Expected the windows of every namespace to apply")
+	}
+
+	if err := c.Check(context.TODO(), "prod", "update foo"); err == nil {
+		t.Errorf("Expected the update to be frozen")
+	}
+
+	acc := &goauth.Account{ID: "dev", Scopes: []string{"developer"}}
+	ctx := metadata.Set(goauth.ContextWithAccount(context.TODO(), acc), OverrideKey, "hotfix")
+	if err := c.Check(ctx, "prod", "update foo"); err == nil {
+		t.Errorf("Expected an override without the scope to be rejected")
+	}
+
+	acc = &goauth.Account{ID: "ops", Scopes: []string{OverrideScope}}
+	ctx = goauth.ContextWithAccount(context.TODO(), acc)
+	if err := c.Check(ctx, "prod", "update foo"); err == nil {
+		t.Errorf("Expected an override without a reason to be rejected")
+	}
+
+	ctx = metadata.Set(ctx, OverrideKey, "hotfix")
+	if err := c.Check(ctx, "prod", "update foo"); err != nil {
+		t.Fatalf("Expected the override to be allowed, got %v", err)
+	}
+	overrides, err := Overrides("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides) != 1 || overrides[0].Account != "ops" || overrides[0].Reason != "hotfix" {
+		t.Errorf("Expected the override to be audited, got %v", overrides)
+	}
+}
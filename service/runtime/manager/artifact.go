@@ -0,0 +1,201 @@
+package manager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/runtime/local"
+	"github.com/micro/go-micro/v3/runtime/local/source/git"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// artifactPrefix is prefixed to the keys of the artifacts of the services' last verified updates
+const artifactPrefix = "artifact:"
+
+// snapshot returns an artifact of the source of the service, by default archiving the source to
+// the uploads dir of the runtime
+var snapshot = snapshotSource
+
+// artifact is an immutable copy of the source of an update. The source of a service is a branch
+// or an upload which later updates replace, so failed updates are rolled back to the artifact
+// of the last verified update rather than its source.
+type artifact struct {
+	// Source which runs the artifact
+	Source string
+	// Digest of the archived source
+	Digest string
+}
+
+// snapshotSource archives the source of the service as an upload named after its digest. Uploads
+// are copied, local folders archived and sources in git checked out at the version being
+// deployed.
+func snapshotSource(srv *gorun.Service, secrets map[string]string) (*artifact, error) {
+	parts := strings.Split(srv.Source, "/")
+	if strings.HasSuffix(parts[0], ".tar.gz") {
+		b, err := ioutil.ReadFile(filepath.Join(local.SourceDir, parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		name, digest, err := writeUpload(strings.TrimSuffix(parts[0], ".tar.gz"), b)
+		if err != nil {
+			return nil, err
+		}
+		parts[0] = name
+		return &artifact{Source: strings.Join(parts, "/"), Digest: digest}, nil
+	}
+
+	// the folder is archived in a folder named after the service, which the runtime runs an
+	// upload from
+	if filepath.IsAbs(srv.Source) {
+		b, err := archive(srv.Source, srv.Name)
+		if err != nil {
+			return nil, err
+		}
+		name, digest, err := writeUpload(srv.Name, b)
+		if err != nil {
+			return nil, err
+		}
+		return &artifact{Source: name + "/", Digest: digest}, nil
+	}
+
+	// sources in git are a repo followed by the folder of the service
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("can't archive the source %v", srv.Source)
+	}
+	source, err := git.ParseSource(srv.Source)
+	if err != nil {
+		return nil, err
+	}
+	if len(source.Folder) > 0 && path.Base(source.Folder) != srv.Name {
+		return nil, fmt.Errorf("the folder of %v isn't named after the service", srv.Source)
+	}
+
+	gitter := git.NewGitter(os.TempDir(), secrets)
+	if err := gitter.Checkout(source.Repo, srv.Version); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(gitter.RepoDir())
+
+	b, err := archive(gitter.RepoDir(), srv.Name)
+	if err != nil {
+		return nil, err
+	}
+	name, digest, err := writeUpload(srv.Name, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// the service is in the folder of the repo it was checked out from
+	src := name + "/"
+	if len(source.Folder) > 0 {
+		src = path.Join(name, srv.Name, path.Dir(source.Folder))
+	}
+	return &artifact{Source: src, Digest: digest}, nil
+}
+
+// writeUpload writes the archive to the uploads dir, named after its digest, returning the name
+func writeUpload(prefix string, b []byte) (string, string, error) {
+	sum := sha256.Sum256(b)
+	digest := hex.EncodeToString(sum[:])
+	name := fmt.Sprintf("%v-%v.tar.gz", prefix, digest[:12])
+
+	file := filepath.Join(local.SourceDir, name)
+	if _, err := os.Stat(file); err == nil {
+		return name, digest, nil
+	}
+	if err := os.MkdirAll(local.SourceDir, 0755); err != nil {
+		return "", "", err
+	}
+	return name, digest, ioutil.WriteFile(file, b, 0644)
+}
+
+// archive the files in the dir as a gzipped tarball, in a folder with the name
+func archive(dir, name string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(zw)
+
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *manager) readArtifact(ns string, srv *gorun.Service) (*artifact, error) {
+	recs, err := store.Read(artifactKey(ns, srv))
+	if err == gostore.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var a *artifact
+	if err := json.Unmarshal(recs[0].Value, &a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (m *manager) writeArtifact(ns string, srv *gorun.Service, a *artifact) error {
+	bytes, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: artifactKey(ns, srv), Value: bytes})
+}
+
+func artifactKey(ns string, srv *gorun.Service) string {
+	return fmt.Sprintf("%v%v:%v:%v", artifactPrefix, ns, srv.Name, srv.Version)
+}
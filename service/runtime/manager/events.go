@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"time"
@@ -30,13 +31,38 @@ const (
 	eventProcessedPrefix = "processed/"
 )
 
-// publishEvent will write the event to the global store and immediately process the event
+// publishEvent will write the event to the global store and immediately process the event. Services
+// can't be created or updated during a freeze of the namespace unless the freeze is overridden in
+// the context of the options.
 func (m *manager) publishEvent(eType gorun.EventType, srv *gorun.Service, opts *gorun.CreateOptions) error {
+	if eType == gorun.Create || eType == gorun.Update {
+		ctx := opts.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		action := "run " + srv.Name
+		if eType == gorun.Update {
+			action = "update " + srv.Name
+		}
+		if err := m.freeze.Check(ctx, opts.Namespace, action); err != nil {
+			return err
+		}
+	}
+	return m.writeEvent(eType, srv, opts)
+}
+
+// writeEvent writes the event to the global store and immediately processes it, without checking
+// whether the namespace is frozen
+func (m *manager) writeEvent(eType gorun.EventType, srv *gorun.Service, opts *gorun.CreateOptions) error {
+	// the context isn't encoded with the event
+	options := *opts
+	options.Context = nil
+
 	e := &gorun.Event{
 		ID:      uuid.New().String(),
 		Type:    eType,
 		Service: srv,
-		Options: opts,
+		Options: &options,
 	}
 
 	bytes, err := json.Marshal(e)
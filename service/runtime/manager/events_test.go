@@ -1,13 +1,17 @@
 package manager
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
 	"github.com/micro/go-micro/v3/runtime"
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/profile"
 	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/freeze"
 )
 
 func TestEvents(t *testing.T) {
@@ -94,3 +98,35 @@ func TestEvents(t *testing.T) {
 		}
 	})
 }
+
+func TestFrozenEvents(t *testing.T) {
+	profile.Test.Setup(nil)
+	rt := &testRuntime{}
+	muruntime.DefaultRuntime = rt
+	m := New().(*manager)
+	m.freeze = freeze.NewChecker(func() []byte {
+		return []byte(`{"*": [{"name": "forever", "window": "2000-01-01 to 2100-01-01"}]}`)
+	})
+
+	srv := &runtime.Service{Name: "foo", Version: "latest"}
+	if err := m.Create(srv, runtime.CreateNamespace(namespace.DefaultNamespace)); err == nil {
+		t.Fatalf("Expected the service not to be created during a freeze")
+	} else if _, ok := err.(*freeze.ErrFrozen); !ok {
+		t.Fatalf("Expected a frozen error, got %v", err)
+	}
+	if srvs, err := m.readServices(namespace.DefaultNamespace, srv); err != nil || len(srvs) > 0 {
+		t.Errorf("Expected the service not to be stored, got %v: %v", srvs, err)
+	}
+
+	// services can still be deleted
+	if err := m.publishEvent(runtime.Delete, srv, &runtime.CreateOptions{Namespace: namespace.DefaultNamespace}); err != nil {
+		t.Errorf("Unexpected error deleting during a freeze: %v", err)
+	}
+
+	// admins can override the freeze giving a reason
+	ctx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "alice", Scopes: []string{freeze.OverrideScope}})
+	ctx = metadata.Set(ctx, freeze.OverrideKey, "hotfix")
+	if err := m.Update(srv, runtime.UpdateNamespace(namespace.DefaultNamespace), runtime.UpdateContext(ctx)); err != nil {
+		t.Errorf("Unexpected error overriding the freeze: %v", err)
+	}
+}
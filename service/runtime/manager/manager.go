@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"sync"
+
 	gorun "github.com/micro/go-micro/v3/runtime"
 	"github.com/micro/go-micro/v3/store"
 	cachest "github.com/micro/go-micro/v3/store/cache"
@@ -9,6 +11,9 @@ import (
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/freeze"
+	"github.com/micro/micro/v3/service/runtime/recycle"
+	"github.com/micro/micro/v3/service/runtime/verify"
 )
 
 // Init initializes the runtime
@@ -35,13 +40,14 @@ func (m *manager) Create(srv *gorun.Service, opts ...gorun.CreateOption) error {
 		srv.Version = "latest"
 	}
 
-	// write the object to the store
-	if err := m.createService(srv, &options); err != nil {
+	// publish the event, this will apply it aysnc to the runtime. It's published before the object
+	// is written so services aren't stored during a freeze
+	if err := m.publishEvent(gorun.Create, srv, &options); err != nil {
 		return err
 	}
 
-	// publish the event, this will apply it aysnc to the runtime
-	return m.publishEvent(gorun.Create, srv, &options)
+	// write the object to the store
+	return m.createService(srv, &options)
 }
 
 // Read returns the service which matches the criteria provided
@@ -79,6 +85,22 @@ func (m *manager) Read(opts ...gorun.ReadOption) ([]*gorun.Service, error) {
 		srv.Service.Metadata["error"] = md.Error
 	}
 
+	// add the status of the last update of services whose updates are verified
+	for _, srv := range ret {
+		state, err := m.readVerifyState(options.Namespace, srv)
+		if err != nil {
+			return nil, err
+		}
+		if state == nil {
+			continue
+		}
+		if srv.Metadata == nil {
+			srv.Metadata = make(map[string]string)
+		}
+		srv.Metadata[verify.StatusKey] = state.Status
+		srv.Metadata[verify.ErrorKey] = state.Error
+	}
+
 	return ret, nil
 }
 
@@ -98,8 +120,29 @@ func (m *manager) Update(srv *gorun.Service, opts ...gorun.UpdateOption) error {
 		srv.Version = "latest"
 	}
 
-	// publish the update event which will trigger an update in the runtime
-	return m.publishEvent(gorun.Update, srv, &gorun.CreateOptions{Namespace: options.Namespace})
+	// publish the update event which will trigger an update in the runtime. It's published before
+	// the policies are stored so they aren't changed during a freeze
+	if err := m.publishEvent(gorun.Update, srv, &gorun.CreateOptions{Namespace: options.Namespace, Context: options.Context}); err != nil {
+		return err
+	}
+
+	// store the recycling policy if one was set, a blank policy removes it
+	if policy, ok := srv.Metadata[recycle.Key]; ok {
+		if err := m.setRecycling(options.Namespace, srv, policy); err != nil {
+			return err
+		}
+	}
+
+	// store the verification policy if one was set, a blank policy removes it
+	if policy, ok := srv.Metadata[verify.Key]; ok {
+		if err := m.setVerification(options.Namespace, srv, policy); err != nil {
+			return err
+		}
+	}
+
+	// verify the update once it's rolled out, rolling it back if it fails
+	m.verifyUpdate(options.Namespace, srv)
+	return nil
 }
 
 // Remove a service
@@ -122,6 +165,9 @@ func (m *manager) Delete(srv *gorun.Service, opts ...gorun.DeleteOption) error {
 	if err := m.deleteService(options.Namespace, srv); err != nil {
 		return err
 	}
+	if err := m.deleteVerification(options.Namespace, srv); err != nil {
+		return err
+	}
 
 	// publish the event which will trigger a delete in the runtime
 	return m.publishEvent(gorun.Delete, srv, &gorun.CreateOptions{Namespace: options.Namespace})
@@ -159,6 +205,12 @@ func (m *manager) Start() error {
 	// Watch services that were running previously
 	go m.watchServices()
 
+	// recycle the instances of services with a recycling policy
+	go m.watchRecycling()
+
+	// resume verifying the updates which were being verified before a restart
+	go m.resumeVerifications()
+
 	return nil
 }
 
@@ -255,6 +307,12 @@ type manager struct {
 	// fileCache is a cache store used to store any information we don't want to write to the
 	// global store but want to persist across restarts, e.g. events consumed
 	fileCache store.Store
+
+	sync.Mutex
+	// verifying are the updates being verified, closed to cancel the verification
+	verifying map[string]chan bool
+	// freeze rejects services being created or updated during the freeze windows of a namespace
+	freeze *freeze.Checker
 }
 
 // New returns a manager for the runtime
@@ -262,5 +320,7 @@ func New() gorun.Runtime {
 	return &manager{
 		cache:     memory.NewStore(),
 		fileCache: cachest.NewStore(filest.NewStore()),
+		verifying: make(map[string]chan bool),
+		freeze:    freeze.NewChecker(freeze.ConfigSource),
 	}
 }
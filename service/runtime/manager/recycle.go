@@ -0,0 +1,267 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/client"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/runtime/recycle"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// recyclePrefix is prefixed to the keys of the recycling state of the services
+const recyclePrefix = "recycle:"
+
+var (
+	// recyclePollFrequency is how often the instances of services with a recycling policy are
+	// checked
+	recyclePollFrequency = time.Minute
+	// recycleBudget is the number of instances recycled per namespace on each check, so
+	// instances are recycled gradually rather than all at once
+	recycleBudget = 1
+	// recycleInterval is the minimum time between recycling instances of a service, giving the
+	// runtime time to replace the previous one
+	recycleInterval = time.Minute * 15
+
+	// instances returns the running instances of a service, by default using the stats the
+	// instances report to the debug handler
+	instances = debugInstances
+	// recycleInstance asks the instance to shut down so the runtime replaces it, by default
+	// using the debug handler
+	recycleInstance = debugRecycle
+)
+
+// recycleState of a service
+type recycleState struct {
+	// Baselines is the memory of each instance once it warmed up
+	Baselines map[string]uint64
+	// Recycled is the unix time an instance of the service was last recycled
+	Recycled int64
+}
+
+// watchRecycling periodically recycles the services whose instances are due to be recycled
+func (m *manager) watchRecycling() {
+	ticker := time.NewTicker(recyclePollFrequency)
+	defer ticker.Stop()
+
+	for {
+		m.applyRecycling(time.Now())
+		<-ticker.C
+	}
+}
+
+// applyRecycling checks the instances of the services with a recycling policy, recycling the
+// instances which are due. Only instances of services which are running are recycled, one per
+// service at a time and no more than the budget per namespace.
+func (m *manager) applyRecycling(t time.Time) {
+	nss, err := m.listNamespaces()
+	if err != nil {
+		logger.Warnf("Error listing namespaces: %v", err)
+		return
+	}
+
+	for _, ns := range nss {
+		srvs, err := m.readServices(ns, &gorun.Service{})
+		if err != nil {
+			logger.Warnf("Error reading services from the %v namespace: %v", ns, err)
+			continue
+		}
+		statuses, err := m.listStatuses(ns)
+		if err != nil {
+			logger.Warnf("Error listing statuses from the %v namespace: %v", ns, err)
+			continue
+		}
+
+		budget := recycleBudget
+		for _, srv := range srvs {
+			raw := srv.Service.Metadata[recycle.Key]
+			if len(raw) == 0 {
+				continue
+			}
+			policy, err := recycle.Parse(raw)
+			if err != nil {
+				logger.Warnf("Error parsing the recycling policy of %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+				continue
+			}
+			if status, ok := statuses[srv.Service.Name+":"+srv.Service.Version]; !ok || status.Status != "running" {
+				continue
+			}
+
+			state, inst, reason, err := m.checkInstances(ns, srv.Service, policy, t)
+			if err != nil {
+				logger.Warnf("Error checking the instances of %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+				continue
+			}
+			if inst == nil {
+				continue
+			}
+			if budget <= 0 {
+				logger.Infof("Deferring recycling instance %v of %v:%v in namespace %v, the disruption budget is spent", inst.ID, srv.Service.Name, srv.Service.Version, ns)
+				continue
+			}
+
+			logger.Infof("Recycling instance %v of %v:%v in namespace %v: %v", inst.ID, srv.Service.Name, srv.Service.Version, ns, reason)
+			if err := recycleInstance(srv.Service, inst, reason); err != nil {
+				logger.Warnf("Error recycling instance %v of %v:%v: %v", inst.ID, srv.Service.Name, srv.Service.Version, err)
+				continue
+			}
+			budget--
+
+			// the instance is replaced so its baseline is forgotten
+			delete(state.Baselines, inst.ID)
+			state.Recycled = t.Unix()
+			if err := m.writeRecycleState(ns, srv.Service, state); err != nil {
+				logger.Warnf("Error writing the recycling state of %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+			}
+		}
+	}
+}
+
+// checkInstances records the baselines of the instances of the service, returning the instance
+// which is due to be recycled and the reason, nil if there isn't one
+func (m *manager) checkInstances(ns string, srv *gorun.Service, policy *recycle.Policy, t time.Time) (*recycleState, *recycle.Instance, string, error) {
+	state, err := m.readRecycleState(ns, srv)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	insts, err := instances(ns, srv)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// instances which have been replaced are forgotten
+	baselines := make(map[string]uint64, len(insts))
+	var due *recycle.Instance
+	var reason string
+	for i, inst := range insts {
+		baseline, ok := state.Baselines[inst.ID]
+		if !ok && policy.Warm(inst, t) {
+			baseline = inst.Memory
+		}
+		if baseline > 0 {
+			baselines[inst.ID] = baseline
+		}
+		if r := policy.Due(inst, baseline, t); len(r) > 0 && due == nil {
+			due, reason = &insts[i], r
+		}
+	}
+
+	// the instance recycled last may not have been replaced yet
+	if due != nil && t.Sub(time.Unix(state.Recycled, 0)) < recycleInterval {
+		due, reason = nil, ""
+	}
+
+	state.Baselines = baselines
+	return state, due, reason, m.writeRecycleState(ns, srv, state)
+}
+
+func (m *manager) readRecycleState(ns string, srv *gorun.Service) (*recycleState, error) {
+	recs, err := store.Read(recycleKey(ns, srv))
+	if err == gostore.ErrNotFound {
+		return &recycleState{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var state *recycleState
+	if err := json.Unmarshal(recs[0].Value, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (m *manager) writeRecycleState(ns string, srv *gorun.Service, state *recycleState) error {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: recycleKey(ns, srv), Value: bytes})
+}
+
+func recycleKey(ns string, srv *gorun.Service) string {
+	return fmt.Sprintf("%v%v:%v:%v", recyclePrefix, ns, srv.Name, srv.Version)
+}
+
+// debugInstances returns the instances of the service registered in the namespace, along with
+// the stats they report. Instances which don't respond are skipped, and the memory of instances
+// which don't report their resident set size is their heap.
+func debugInstances(ns string, srv *gorun.Service) ([]recycle.Instance, error) {
+	srvs, err := registry.GetService(srv.Name, goregistry.GetDomain(ns))
+	if util.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var insts []recycle.Instance
+	for _, s := range srvs {
+		for _, n := range s.Nodes {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			req := client.NewRequest(srv.Name, "Debug.Stats", &pb.StatsRequest{})
+			rsp := &pb.StatsResponse{}
+			err := client.Call(ctx, req, rsp, goclient.WithAddress(n.Address))
+			cancel()
+			if err != nil || rsp.Started == 0 {
+				continue
+			}
+			memory := rsp.Rss
+			if memory == 0 {
+				memory = rsp.Memory
+			}
+			insts = append(insts, recycle.Instance{
+				ID:      n.Id,
+				Address: n.Address,
+				Started: time.Unix(int64(rsp.Started), 0),
+				Memory:  memory,
+			})
+		}
+	}
+	return insts, nil
+}
+
+// debugRecycle asks the instance to shut down gracefully using its debug handler
+func debugRecycle(srv *gorun.Service, inst *recycle.Instance, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	req := client.NewRequest(srv.Name, "Debug.Recycle", &pb.RecycleRequest{Reason: reason})
+	return client.Call(ctx, req, &pb.RecycleResponse{}, goclient.WithAddress(inst.Address))
+}
+
+// setRecycling stores the recycling policy of the service, removing it if it's blank
+func (m *manager) setRecycling(ns string, srv *gorun.Service, policy string) error {
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range srvs {
+		if s.Service.Name != srv.Name || s.Service.Version != srv.Version {
+			continue
+		}
+		if s.Service.Metadata == nil {
+			s.Service.Metadata = make(map[string]string)
+		}
+		if len(policy) > 0 {
+			s.Service.Metadata[recycle.Key] = policy
+		} else {
+			delete(s.Service.Metadata, recycle.Key)
+			if err := store.Delete(recycleKey(ns, srv)); err != nil && err != gostore.ErrNotFound {
+				return err
+			}
+		}
+		if err := m.createService(s.Service, s.Options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/recycle"
+)
+
+func TestRecycling(t *testing.T) {
+	profile.Test.Setup(nil)
+	rt := &testRuntime{}
+	muruntime.DefaultRuntime = rt
+	m := New().(*manager)
+
+	srv := &runtime.Service{
+		Name:     "leaky",
+		Version:  "latest",
+		Metadata: map[string]string{recycle.Key: "age=24h; growth=50%"},
+	}
+	if err := m.createService(srv, &runtime.CreateOptions{Namespace: namespace.DefaultNamespace}); err != nil {
+		t.Fatalf("Unexpected error creating the service: %v", err)
+	}
+	running := &runtime.Service{Name: srv.Name, Version: srv.Version, Metadata: map[string]string{"status": "running"}}
+	if err := m.cacheStatus(namespace.DefaultNamespace, running); err != nil {
+		t.Fatalf("Unexpected error caching the status: %v", err)
+	}
+
+	started := time.Now()
+	insts := []recycle.Instance{
+		{ID: "leaky-1", Started: started, Memory: 100 << 20},
+		{ID: "leaky-2", Started: started, Memory: 100 << 20},
+	}
+	defer func(fn func(string, *runtime.Service) ([]recycle.Instance, error)) { instances = fn }(instances)
+	instances = func(ns string, srv *runtime.Service) ([]recycle.Instance, error) {
+		return insts, nil
+	}
+	var recycled []string
+	defer func(fn func(*runtime.Service, *recycle.Instance, string) error) { recycleInstance = fn }(recycleInstance)
+	recycleInstance = func(srv *runtime.Service, inst *recycle.Instance, reason string) error {
+		recycled = append(recycled, inst.ID)
+		return nil
+	}
+
+	// the baseline is recorded once the instances have warmed up
+	m.applyRecycling(started.Add(time.Hour))
+	if len(recycled) != 0 {
+		t.Fatalf("Expected no instances to be recycled, got %v", recycled)
+	}
+
+	// only the instance whose memory doubled since warming up is recycled
+	insts[1].Memory = 200 << 20
+	tm := started.Add(time.Hour * 2)
+	m.applyRecycling(tm)
+	if len(recycled) != 1 || recycled[0] != "leaky-2" {
+		t.Fatalf("Expected leaky-2 to be recycled, got %v", recycled)
+	}
+	state, err := m.readRecycleState(namespace.DefaultNamespace, srv)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the state: %v", err)
+	}
+	if _, ok := state.Baselines["leaky-1"]; !ok || len(state.Baselines) != 1 {
+		t.Errorf("Expected only the baseline of leaky-1 to be kept, got %v", state.Baselines)
+	}
+
+	// the instance is given time to be replaced before another is recycled
+	insts = []recycle.Instance{
+		{ID: "leaky-1", Started: started.Add(-time.Hour * 48), Memory: 100 << 20},
+		{ID: "leaky-3", Started: tm, Memory: 100 << 20},
+	}
+	m.applyRecycling(tm.Add(time.Minute))
+	if len(recycled) != 1 {
+		t.Errorf("Expected no other instance to be recycled so soon, got %v", recycled)
+	}
+	m.applyRecycling(tm.Add(recycleInterval))
+	if len(recycled) != 2 || recycled[1] != "leaky-1" {
+		t.Errorf("Expected the old instance to be recycled, got %v", recycled)
+	}
+}
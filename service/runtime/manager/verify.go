@@ -0,0 +1,420 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	goregistry "github.com/micro/go-micro/v3/registry"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/client"
+	pb "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/runtime/verify"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// verifyPrefix is prefixed to the keys of the verification state written to the store
+const verifyPrefix = "verify:"
+
+var (
+	// verifyPollFrequency is how often the instances are checked while waiting for the update
+	// to roll out
+	verifyPollFrequency = time.Second * 10
+
+	// samples returns samples of the running instances of a service, by default using the
+	// health and stats the instances report to the debug handler
+	samples = debugSamples
+	// smokeCall makes the call to an instance of a service, by default using the address the
+	// instance registered
+	smokeCall = debugSmokeCall
+)
+
+// verifyState of the last update of a service
+type verifyState struct {
+	Status string
+	Error  string
+	// Deployed is the unix time the update was deployed
+	Deployed int64
+	// Source of the update
+	Source string
+	// Artifact of the update, which it's rolled back to if a later update fails
+	Artifact *artifact
+}
+
+// verifyUpdate starts verifying the update of the service if it has a verification policy,
+// cancelling the verification of the previous update
+func (m *manager) verifyUpdate(ns string, srv *gorun.Service) {
+	m.startVerifying(ns, srv, &verifyState{Deployed: time.Now().Unix(), Source: srv.Source})
+}
+
+// resumeVerifications resumes verifying the updates which were being verified when the manager
+// stopped
+func (m *manager) resumeVerifications() {
+	recs, err := store.Read(verifyPrefix, gostore.ReadPrefix())
+	if err != nil {
+		logger.Warnf("Error reading the verification states: %v", err)
+		return
+	}
+
+	for _, r := range recs {
+		var state *verifyState
+		if err := json.Unmarshal(r.Value, &state); err != nil {
+			logger.Warnf("Error unmarshaling the verification state %v: %v", r.Key, err)
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(r.Key, verifyPrefix), ":", 3)
+		if state.Status != verify.Verifying || len(parts) != 3 {
+			continue
+		}
+		m.startVerifying(parts[0], &gorun.Service{Name: parts[1], Version: parts[2], Source: state.Source}, state)
+	}
+}
+
+// startVerifying the update deployed at the time in the state
+func (m *manager) startVerifying(ns string, srv *gorun.Service, state *verifyState) {
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		logger.Warnf("Error reading service %v:%v: %v", srv.Name, srv.Version, err)
+		return
+	}
+	var stable *service
+	for _, s := range srvs {
+		if s.Service.Name == srv.Name && s.Service.Version == srv.Version {
+			stable = s
+		}
+	}
+	if stable == nil || len(stable.Service.Metadata[verify.Key]) == 0 {
+		return
+	}
+	policy, err := verify.Parse(stable.Service.Metadata[verify.Key])
+	if err != nil {
+		logger.Warnf("Error parsing the verification policy of %v:%v: %v", srv.Name, srv.Version, err)
+		return
+	}
+
+	key := verifyKey(ns, srv)
+	exit := make(chan bool)
+	m.Lock()
+	if prev, ok := m.verifying[key]; ok {
+		close(prev)
+	}
+	m.verifying[key] = exit
+	m.Unlock()
+
+	go func() {
+		m.verify(ns, srv, stable, policy, state, exit)
+
+		m.Lock()
+		if m.verifying[key] == exit {
+			delete(m.verifying, key)
+		}
+		m.Unlock()
+	}()
+}
+
+// verify the update of the service against the policy, marking it successful if it passes and
+// rolling it back to the artifact of the last verified update if it fails. The state is written
+// to the store, so the verification is resumed if the manager restarts.
+func (m *manager) verify(ns string, srv *gorun.Service, stable *service, policy *verify.Policy, state *verifyState, exit chan bool) {
+	state.Status = verify.Verifying
+	if err := m.writeVerifyState(ns, srv, state); err != nil {
+		logger.Warnf("Error writing the verification state of %v:%v: %v", srv.Name, srv.Version, err)
+	}
+	logger.Infof("Verifying the update of %v:%v in namespace %v", srv.Name, srv.Version, ns)
+
+	// archive the update whilst it's verified, since its source can change in the meantime
+	if state.Artifact == nil {
+		var secrets map[string]string
+		if stable.Options != nil {
+			secrets = stable.Options.Secrets
+		}
+		if art, err := snapshot(srv, secrets); err != nil {
+			logger.Warnf("Error archiving the update of %v:%v, it can't be rolled back to: %v", srv.Name, srv.Version, err)
+		} else {
+			state.Artifact = art
+			if err := m.writeVerifyState(ns, srv, state); err != nil {
+				logger.Warnf("Error writing the verification state of %v:%v: %v", srv.Name, srv.Version, err)
+			}
+		}
+	}
+
+	reason, cancelled := m.check(ns, srv, policy, time.Unix(state.Deployed, 0), exit)
+	if cancelled {
+		logger.Infof("Cancelled verifying the update of %v:%v in namespace %v, it's been updated again", srv.Name, srv.Version, ns)
+		return
+	}
+
+	// the artifact of the last verified update
+	last, err := m.readArtifact(ns, srv)
+	if err != nil {
+		logger.Warnf("Error reading the artifact of %v:%v: %v", srv.Name, srv.Version, err)
+	}
+
+	if len(reason) == 0 {
+		stable.Service.Source = srv.Source
+		if err := m.createService(stable.Service, stable.Options); err != nil {
+			logger.Warnf("Error writing service %v:%v: %v", srv.Name, srv.Version, err)
+		}
+		// the update becomes the artifact later updates are rolled back to
+		if state.Artifact != nil {
+			if err := m.writeArtifact(ns, srv, state.Artifact); err != nil {
+				logger.Warnf("Error writing the artifact of %v:%v: %v", srv.Name, srv.Version, err)
+			}
+		}
+		state.Status = verify.Succeeded
+		logger.Infof("Verified the update of %v:%v in namespace %v", srv.Name, srv.Version, ns)
+	} else if !policy.Rollback || last == nil || (state.Artifact != nil && state.Artifact.Digest == last.Digest) {
+		state.Status = verify.Failed
+		state.Error = reason
+		logger.Warnf("Update of %v:%v in namespace %v failed verification: %v", srv.Name, srv.Version, ns, reason)
+	} else {
+		state.Status = verify.RolledBack
+		state.Error = reason
+		logger.Warnf("Rolling back the update of %v:%v in namespace %v, it failed verification: %v", srv.Name, srv.Version, ns, reason)
+
+		// rollbacks restore the last verified update so they aren't frozen
+		rollback := *stable.Service
+		rollback.Source = last.Source
+		if err := m.writeEvent(gorun.Update, &rollback, &gorun.CreateOptions{Namespace: ns}); err != nil {
+			logger.Warnf("Error rolling back service %v:%v: %v", srv.Name, srv.Version, err)
+			state.Status = verify.Failed
+		}
+	}
+
+	if err := m.writeVerifyState(ns, srv, state); err != nil {
+		logger.Warnf("Error writing the verification state of %v:%v: %v", srv.Name, srv.Version, err)
+	}
+}
+
+// check the instances of the service against the policy, returning the reason they failed,
+// blank if they passed, or true if the verification was cancelled
+func (m *manager) check(ns string, srv *gorun.Service, policy *verify.Policy, deployed time.Time, exit chan bool) (string, bool) {
+	wait := func(d time.Duration) bool {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-exit:
+			return false
+		case <-t.C:
+			return true
+		}
+	}
+
+	// wait for the instances to be replaced by ones started since the update was deployed
+	var insts []verify.Sample
+	timeout := deployed.Add(policy.Timeout)
+	for {
+		var err error
+		if insts, err = samples(ns, srv); err != nil {
+			logger.Debugf("Error sampling the instances of %v:%v: %v", srv.Name, srv.Version, err)
+		} else if rolledOut(insts, deployed) {
+			break
+		}
+		if statuses, err := m.listStatuses(ns); err == nil {
+			if s, ok := statuses[srv.Name+":"+srv.Version]; ok && s.Status == "error" {
+				return fmt.Sprintf("the update errored: %v", s.Error), false
+			}
+		}
+		if time.Now().After(timeout) {
+			return fmt.Sprintf("the new instances didn't start within %v", policy.Timeout), false
+		}
+		if !wait(verifyPollFrequency) {
+			return "", true
+		}
+	}
+
+	// smoke test each of the new instances
+	for _, inst := range insts {
+		for _, c := range policy.Calls {
+			if err := smokeCall(ns, srv, inst.ID, c); err != nil {
+				return fmt.Sprintf("call to %v of instance %v failed: %v", c.Endpoint, inst.ID, err), false
+			}
+		}
+	}
+
+	// check the health and metrics of the instances for the duration
+	baselines := make(map[string]verify.Sample, len(insts))
+	for _, inst := range insts {
+		baselines[inst.ID] = inst
+	}
+	end := time.Now().Add(policy.Duration)
+	for {
+		if reason := policy.Check(baselines, insts); len(reason) > 0 {
+			return reason, false
+		}
+		if !time.Now().Before(end) {
+			return "", false
+		}
+		if !wait(policy.Interval) {
+			return "", true
+		}
+
+		var err error
+		if insts, err = samples(ns, srv); err != nil {
+			return fmt.Sprintf("error sampling the instances: %v", err), false
+		} else if len(insts) == 0 {
+			return "no instances are running", false
+		}
+	}
+}
+
+// rolledOut returns true if there are instances and every one started after the update
+func rolledOut(insts []verify.Sample, deployed time.Time) bool {
+	for _, inst := range insts {
+		// instances report the second they started
+		if inst.Started.Before(deployed.Truncate(time.Second)) {
+			return false
+		}
+	}
+	return len(insts) > 0
+}
+
+func (m *manager) readVerifyState(ns string, srv *gorun.Service) (*verifyState, error) {
+	recs, err := store.Read(verifyKey(ns, srv))
+	if err == gostore.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var state *verifyState
+	if err := json.Unmarshal(recs[0].Value, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (m *manager) writeVerifyState(ns string, srv *gorun.Service, state *verifyState) error {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: verifyKey(ns, srv), Value: bytes})
+}
+
+// deleteVerification removes the verification state and artifact of the service
+func (m *manager) deleteVerification(ns string, srv *gorun.Service) error {
+	for _, key := range []string{verifyKey(ns, srv), artifactKey(ns, srv)} {
+		if err := store.Delete(key); err != nil && err != gostore.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyKey(ns string, srv *gorun.Service) string {
+	return fmt.Sprintf("%v%v:%v:%v", verifyPrefix, ns, srv.Name, srv.Version)
+}
+
+// debugSamples returns samples of the instances of the service registered in the namespace,
+// using the health and stats they report. Instances which don't report their stats are skipped.
+func debugSamples(ns string, srv *gorun.Service) ([]verify.Sample, error) {
+	srvs, err := registry.GetService(srv.Name, goregistry.GetDomain(ns))
+	if util.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var insts []verify.Sample
+	for _, s := range srvs {
+		for _, n := range s.Nodes {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			stats := &pb.StatsResponse{}
+			err := client.Call(ctx, client.NewRequest(srv.Name, "Debug.Stats", &pb.StatsRequest{}), stats, goclient.WithAddress(n.Address))
+			if err != nil || stats.Started == 0 {
+				cancel()
+				continue
+			}
+			health := &pb.HealthResponse{}
+			err = client.Call(ctx, client.NewRequest(srv.Name, "Debug.Health", &pb.HealthRequest{}), health, goclient.WithAddress(n.Address))
+			cancel()
+
+			insts = append(insts, verify.Sample{
+				ID:       n.Id,
+				Started:  time.Unix(int64(stats.Started), 0),
+				Healthy:  err == nil && health.Status == "ok",
+				Requests: stats.Requests,
+				Errors:   stats.Errors,
+				Memory:   stats.Memory,
+			})
+		}
+	}
+	return insts, nil
+}
+
+// debugSmokeCall makes the call to the instance of the service, returning an error if it
+// doesn't respond with the status expected
+func debugSmokeCall(ns string, srv *gorun.Service, id string, c verify.Call) error {
+	srvs, err := registry.GetService(srv.Name, goregistry.GetDomain(ns))
+	if err != nil {
+		return err
+	}
+	var address string
+	for _, s := range srvs {
+		for _, n := range s.Nodes {
+			if n.Id == id {
+				address = n.Address
+			}
+		}
+	}
+	if len(address) == 0 {
+		return fmt.Errorf("instance %v isn't registered", id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verify.DefaultCallTimeout)
+	defer cancel()
+
+	req := client.NewRequest(srv.Name, c.Endpoint, &c.Request, goclient.WithContentType("application/json"))
+	var rsp json.RawMessage
+	err = client.Call(ctx, req, &rsp, goclient.WithAddress(address), goclient.WithRetries(0))
+
+	status := 200
+	if err != nil {
+		status = 500
+		if verr := errors.Parse(err); verr != nil && verr.Code > 0 {
+			status = int(verr.Code)
+		}
+	}
+	if status == c.Status {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("expected status %v, got %v: %v", c.Status, status, err)
+	}
+	return fmt.Errorf("expected status %v, got %v", c.Status, status)
+}
+
+// setVerification stores the verification policy of the service, removing it if it's blank
+func (m *manager) setVerification(ns string, srv *gorun.Service, policy string) error {
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range srvs {
+		if s.Service.Name != srv.Name || s.Service.Version != srv.Version {
+			continue
+		}
+		if s.Service.Metadata == nil {
+			s.Service.Metadata = make(map[string]string)
+		}
+		if len(policy) > 0 {
+			s.Service.Metadata[verify.Key] = policy
+		} else {
+			delete(s.Service.Metadata, verify.Key)
+		}
+		if err := m.createService(s.Service, s.Options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/runtime/local"
+	"github.com/micro/go-micro/v3/runtime/local/source/git"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/verify"
+)
+
+func TestVerify(t *testing.T) {
+	profile.Test.Setup(nil)
+	rt := &testRuntime{events: make(chan *runtime.Service, 1)}
+	muruntime.DefaultRuntime = rt
+	m := New().(*manager)
+
+	defer func(fn time.Duration) { verifyPollFrequency = fn }(verifyPollFrequency)
+	verifyPollFrequency = time.Millisecond
+	defer func(fn func(string, *runtime.Service) ([]verify.Sample, error)) { samples = fn }(samples)
+	defer func(fn func(string, *runtime.Service, string, verify.Call) error) { smokeCall = fn }(smokeCall)
+	defer func(fn func(*runtime.Service, map[string]string) (*artifact, error)) { snapshot = fn }(snapshot)
+	snapshot = func(srv *runtime.Service, secrets map[string]string) (*artifact, error) {
+		return &artifact{Source: srv.Source + ".tar.gz/", Digest: srv.Source}, nil
+	}
+
+	raw := `{"calls": [{"endpoint": "Helloworld.Call"}], "health": true, "duration": "20ms", "interval": "5ms"}`
+	policy, err := verify.Parse(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the policy: %v", err)
+	}
+
+	tt := []struct {
+		Name     string
+		Healthy  bool
+		CallErr  error
+		Status   string
+		Source   string
+		Verified bool
+		Rollback bool
+	}{
+		{"Succeeded", true, nil, verify.Succeeded, "v2", true, false},
+		{"FailedCall", true, fmt.Errorf("expected status 200, got 500"), verify.RolledBack, "v1", true, true},
+		{"Unhealthy", false, nil, verify.RolledBack, "v1", true, true},
+		{"NothingVerified", false, nil, verify.Failed, "v1", false, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			stable := &runtime.Service{Name: "helloworld", Version: "latest", Source: "v1", Metadata: map[string]string{verify.Key: raw}}
+			opts := &runtime.CreateOptions{Namespace: namespace.DefaultNamespace}
+			if err := m.createService(stable, opts); err != nil {
+				t.Fatalf("Unexpected error creating the service: %v", err)
+			}
+			if err := m.deleteVerification(namespace.DefaultNamespace, stable); err != nil {
+				t.Fatalf("Unexpected error deleting the artifact: %v", err)
+			}
+			// the last verified update is archived
+			if tc.Verified {
+				if err := m.writeArtifact(namespace.DefaultNamespace, stable, &artifact{Source: "v1.tar.gz/", Digest: "v1"}); err != nil {
+					t.Fatalf("Unexpected error writing the artifact: %v", err)
+				}
+			}
+			update := &runtime.Service{Name: "helloworld", Version: "latest", Source: "v2"}
+
+			deployed := time.Now()
+			samples = func(ns string, srv *runtime.Service) ([]verify.Sample, error) {
+				return []verify.Sample{{ID: "helloworld-1", Started: deployed.Add(time.Second), Healthy: tc.Healthy}}, nil
+			}
+			var calls int
+			smokeCall = func(ns string, srv *runtime.Service, id string, c verify.Call) error {
+				calls++
+				return tc.CallErr
+			}
+
+			state := &verifyState{Deployed: deployed.Unix(), Source: update.Source}
+			m.verify(namespace.DefaultNamespace, update, &service{stable, opts}, policy, state, make(chan bool))
+
+			if calls != 1 {
+				t.Errorf("Expected 1 smoke test call, got %v", calls)
+			}
+			state, err := m.readVerifyState(namespace.DefaultNamespace, update)
+			if err != nil {
+				t.Fatalf("Unexpected error reading the state: %v", err)
+			}
+			if state.Status != tc.Status {
+				t.Errorf("Expected status %v, got %v (%v)", tc.Status, state.Status, state.Error)
+			}
+
+			srvs, err := m.readServices(namespace.DefaultNamespace, update)
+			if err != nil || len(srvs) != 1 {
+				t.Fatalf("Expected the service to be stored, got %v, %v", srvs, err)
+			}
+			if srvs[0].Service.Source != tc.Source {
+				t.Errorf("Expected the stable source %v, got %v", tc.Source, srvs[0].Service.Source)
+			}
+			art, err := m.readArtifact(namespace.DefaultNamespace, update)
+			if err != nil {
+				t.Fatalf("Unexpected error reading the artifact: %v", err)
+			}
+			if tc.Verified && art.Source != tc.Source+".tar.gz/" {
+				t.Errorf("Expected the artifact of %v, got %v", tc.Source, art.Source)
+			}
+
+			if !tc.Rollback {
+				return
+			}
+			select {
+			case srv := <-rt.events:
+				if srv.Source != "v1.tar.gz/" {
+					t.Errorf("Expected the service to be rolled back to the artifact of v1, got %v", srv.Source)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Expected the service to be rolled back")
+			}
+		})
+	}
+}
+
+func TestVerifyTimeout(t *testing.T) {
+	profile.Test.Setup(nil)
+	muruntime.DefaultRuntime = &testRuntime{}
+	m := New().(*manager)
+
+	defer func(fn time.Duration) { verifyPollFrequency = fn }(verifyPollFrequency)
+	verifyPollFrequency = time.Millisecond
+	defer func(fn func(string, *runtime.Service) ([]verify.Sample, error)) { samples = fn }(samples)
+
+	// the old instance is never replaced
+	deployed := time.Now()
+	samples = func(ns string, srv *runtime.Service) ([]verify.Sample, error) {
+		return []verify.Sample{{ID: "helloworld-1", Started: deployed.Add(-time.Hour), Healthy: true}}, nil
+	}
+
+	policy, err := verify.Parse(`{"timeout": "10ms", "rollback": false}`)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the policy: %v", err)
+	}
+	srv := &runtime.Service{Name: "helloworld", Version: "latest", Source: "v2"}
+	stable := &service{&runtime.Service{Name: "helloworld", Version: "latest", Source: "v1"}, &runtime.CreateOptions{Namespace: namespace.DefaultNamespace}}
+	m.verify(namespace.DefaultNamespace, srv, stable, policy, &verifyState{Deployed: deployed.Unix()}, make(chan bool))
+
+	state, err := m.readVerifyState(namespace.DefaultNamespace, srv)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the state: %v", err)
+	}
+	if state.Status != verify.Failed {
+		t.Errorf("Expected the update to fail, got %v", state.Status)
+	}
+
+	// cancelled verifications leave the update verifying, the next update replaces the state
+	exit := make(chan bool)
+	close(exit)
+	policy.Timeout = time.Hour
+	m.verify(namespace.DefaultNamespace, srv, stable, policy, &verifyState{Deployed: deployed.Unix()}, exit)
+	if state, _ := m.readVerifyState(namespace.DefaultNamespace, srv); state.Status != verify.Verifying {
+		t.Errorf("Expected the cancelled update to still be verifying, got %v", state.Status)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(d string) { local.SourceDir = d }(local.SourceDir)
+	local.SourceDir = filepath.Join(dir, "uploads")
+
+	src := filepath.Join(dir, "helloworld")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &runtime.Service{Name: "helloworld", Version: "latest", Source: src}
+	art, err := snapshotSource(srv, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error archiving the source: %v", err)
+	}
+
+	// the source can change without changing the artifact
+	if err := ioutil.WriteFile(filepath.Join(src, "main.go"), []byte("package broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	name := strings.Split(art.Source, "/")[0]
+	uncompressed := filepath.Join(dir, "uncompressed")
+	if err := git.Uncompress(filepath.Join(local.SourceDir, name), uncompressed); err != nil {
+		t.Fatalf("Unexpected error uncompressing the artifact: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(uncompressed, "helloworld", "main.go"))
+	if err != nil || string(b) != "package main" {
+		t.Errorf("Expected the artifact to contain the source when it was archived, got %q %v", b, err)
+	}
+
+	if next, err := snapshotSource(srv, nil); err != nil || next.Digest == art.Digest {
+		t.Errorf("Expected a new artifact for the changed source, got %v %v", next, err)
+	}
+}
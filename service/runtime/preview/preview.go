@@ -0,0 +1,362 @@
+// Package preview deploys the branches of pull requests to preview namespaces when it receives
+// GitHub webhooks, and tears them down when the pull requests are merged or closed. Webhooks
+// must be signed with the secret and only the pull requests of the repositories allowed are
+// deployed, since the payload decides what's built.
+package preview
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	log "github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultImage the previews are run with
+	DefaultImage = "micro/cells:micro"
+	// DefaultRetries which should be attempted when starting a preview
+	DefaultRetries = 3
+	// DefaultAPI is the address of the GitHub API
+	DefaultAPI = "https://api.github.com"
+
+	// invalidChars are replaced when generating namespaces
+	invalidChars = regexp.MustCompile("[^a-z0-9]+")
+
+	// ErrNoSecret is returned creating a handler without a secret to verify the webhooks with
+	ErrNoSecret = errors.New("a secret is required to verify the preview webhooks")
+	// ErrNoRepositories is returned creating a handler without any repositories to deploy
+	ErrNoRepositories = errors.New("the repositories to deploy previews of are required")
+)
+
+// Options for the preview handler
+type Options struct {
+	// Runtime the previews are deployed to
+	Runtime goruntime.Runtime
+	// Secret the webhooks are signed with, required
+	Secret string
+	// Repositories whose pull requests are deployed, e.g. micro/services, required
+	Repositories []string
+	// Token used to comment on pull requests, comments are disabled if blank
+	Token string
+	// Domain the preview URLs are generated under, e.g. preview.example.com
+	Domain string
+	// Folders of the repositories containing the services to deploy, the repository
+	// itself is deployed if blank
+	Folders []string
+	// Image the previews are run with
+	Image string
+	// API is the address of the GitHub API
+	API string
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Runtime sets the runtime the previews are deployed to
+func Runtime(r goruntime.Runtime) Option {
+	return func(o *Options) {
+		o.Runtime = r
+	}
+}
+
+// Secret sets the secret the webhooks are signed with
+func Secret(s string) Option {
+	return func(o *Options) {
+		o.Secret = s
+	}
+}
+
+// Repositories sets the repositories whose pull requests are deployed
+func Repositories(r ...string) Option {
+	return func(o *Options) {
+		o.Repositories = r
+	}
+}
+
+// Token sets the token used to comment on pull requests
+func Token(t string) Option {
+	return func(o *Options) {
+		o.Token = t
+	}
+}
+
+// Domain sets the domain the preview URLs are generated under
+func Domain(d string) Option {
+	return func(o *Options) {
+		o.Domain = d
+	}
+}
+
+// Folders sets the folders of the services to deploy
+func Folders(f ...string) Option {
+	return func(o *Options) {
+		o.Folders = f
+	}
+}
+
+// Image sets the image the previews are run with
+func Image(i string) Option {
+	return func(o *Options) {
+		o.Image = i
+	}
+}
+
+// API sets the address of the GitHub API
+func API(a string) Option {
+	return func(o *Options) {
+		o.API = a
+	}
+}
+
+// NewHandler returns a http.Handler for GitHub webhooks. A secret and the repositories to
+// deploy are required.
+func NewHandler(opts ...Option) (http.Handler, error) {
+	options := Options{
+		Image: DefaultImage,
+		API:   DefaultAPI,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.Secret) == 0 {
+		return nil, ErrNoSecret
+	}
+	if len(options.Folders) == 0 {
+		options.Folders = []string{""}
+	}
+
+	repos := make(map[string]bool, len(options.Repositories))
+	for _, r := range options.Repositories {
+		if r = strings.ToLower(strings.Trim(r, "/ ")); len(r) > 0 {
+			repos[r] = true
+		}
+	}
+	if len(repos) == 0 {
+		return nil, ErrNoRepositories
+	}
+
+	return &handler{opts: options, repos: repos}, nil
+}
+
+type handler struct {
+	opts Options
+	// repos allowed, lowercased as GitHub names are case insensitive
+	repos map[string]bool
+}
+
+// pullRequestEvent is the payload of a GitHub pull_request webhook
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.verify(req.Header.Get("X-Hub-Signature"), body) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// previews are only deployed for pull requests
+	if req.Header.Get("X-GitHub-Event") != "pull_request" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var ev pullRequestEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(w, "Invalid payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.repos[strings.ToLower(ev.Repository.FullName)] {
+		http.Error(w, "Repository not allowed", http.StatusForbidden)
+		return
+	}
+	ns := Namespace(ev.Repository.FullName, ev.Number)
+
+	switch ev.Action {
+	case "opened", "reopened":
+		err = h.deploy(ns, &ev)
+		if err == nil {
+			err = h.comment(&ev, h.message(ns))
+		}
+	case "synchronize":
+		err = h.update(ns, &ev)
+	case "closed":
+		err = h.teardown(ns)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Error handling %v of pull request %v: %v", ev.Action, ns, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify the webhook was signed using the secret
+func (h *handler) verify(signature string, body []byte) bool {
+	if len(h.opts.Secret) == 0 {
+		return false
+	}
+
+	parts := strings.SplitN(signature, "=", 2)
+	if len(parts) != 2 || parts[0] != "sha1" {
+		return false
+	}
+	sha, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, []byte(h.opts.Secret))
+	mac.Write(body)
+	return hmac.Equal(sha, mac.Sum(nil))
+}
+
+// deploy the branch of the pull request to the namespace
+func (h *handler) deploy(ns string, ev *pullRequestEvent) error {
+	if err := h.opts.Runtime.CreateNamespace(ns); err != nil && err != goruntime.ErrAlreadyExists {
+		return err
+	}
+
+	for _, srv := range h.services(ev) {
+		args := srv.Source + "@" + srv.Version
+		err := h.opts.Runtime.Create(srv,
+			goruntime.CreateImage(h.opts.Image),
+			goruntime.CreateType("service"),
+			goruntime.CreateNamespace(ns),
+			goruntime.WithRetries(DefaultRetries),
+			goruntime.WithArgs(args),
+		)
+		if err != nil && err != goruntime.ErrAlreadyExists {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// update the services in the namespace so they're rebuilt from the latest commit
+func (h *handler) update(ns string, ev *pullRequestEvent) error {
+	for _, srv := range h.services(ev) {
+		if err := h.opts.Runtime.Update(srv, goruntime.UpdateNamespace(ns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teardown the services in the namespace and the namespace itself
+func (h *handler) teardown(ns string) error {
+	srvs, err := h.opts.Runtime.Read(goruntime.ReadNamespace(ns))
+	if err != nil {
+		return err
+	}
+	for _, srv := range srvs {
+		if err := h.opts.Runtime.Delete(srv, goruntime.DeleteNamespace(ns)); err != nil {
+			return err
+		}
+	}
+	return h.opts.Runtime.DeleteNamespace(ns)
+}
+
+// services returns the services to deploy for the pull request
+func (h *handler) services(ev *pullRequestEvent) []*goruntime.Service {
+	repo := "github.com/" + ev.Repository.FullName
+
+	srvs := make([]*goruntime.Service, 0, len(h.opts.Folders))
+	for _, f := range h.opts.Folders {
+		source := repo
+		name := ev.Repository.FullName[strings.LastIndex(ev.Repository.FullName, "/")+1:]
+		if f = strings.Trim(f, "/"); len(f) > 0 {
+			source = repo + "/" + f
+			name = f[strings.LastIndex(f, "/")+1:]
+		}
+
+		srvs = append(srvs, &goruntime.Service{
+			Name:     name,
+			Source:   source,
+			Version:  ev.PullRequest.Head.Ref,
+			Metadata: make(map[string]string),
+		})
+	}
+	return srvs
+}
+
+// comment on the pull request
+func (h *handler) comment(ev *pullRequestEvent, msg string) error {
+	if len(h.opts.Token) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"body": msg})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%v/repos/%v/issues/%d/comments", h.opts.API, ev.Repository.FullName, ev.Number)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+h.opts.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("Error commenting on pull request: %v", rsp.Status)
+	}
+	return nil
+}
+
+// message commented on the pull request once the preview is deployed
+func (h *handler) message(ns string) string {
+	if len(h.opts.Domain) == 0 {
+		return fmt.Sprintf("Preview deployed to the %v namespace", ns)
+	}
+	return fmt.Sprintf("Preview deployed to https://%v.%v", ns, h.opts.Domain)
+}
+
+// Namespace returns the namespace of the pull request's preview, e.g. preview-micro-42
+func Namespace(repo string, number int) string {
+	name := repo[strings.LastIndex(repo, "/")+1:]
+	name = strings.Trim(invalidChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+
+	// namespaces must be valid DNS labels
+	suffix := fmt.Sprintf("-%d", number)
+	if max := 63 - len("preview-") - len(suffix); len(name) > max {
+		name = strings.TrimRight(name[:max], "-")
+	}
+	return "preview-" + name + suffix
+}
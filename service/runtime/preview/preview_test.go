@@ -0,0 +1,132 @@
+package preview
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goruntime "github.com/micro/go-micro/v3/runtime"
+)
+
+type testRuntime struct {
+	goruntime.Runtime
+	services map[string][]*goruntime.Service
+}
+
+func (r *testRuntime) CreateNamespace(ns string) error {
+	r.services[ns] = nil
+	return nil
+}
+
+func (r *testRuntime) DeleteNamespace(ns string) error {
+	delete(r.services, ns)
+	return nil
+}
+
+func (r *testRuntime) Create(srv *goruntime.Service, opts ...goruntime.CreateOption) error {
+	var options goruntime.CreateOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	r.services[options.Namespace] = append(r.services[options.Namespace], srv)
+	return nil
+}
+
+func (r *testRuntime) Read(opts ...goruntime.ReadOption) ([]*goruntime.Service, error) {
+	var options goruntime.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return r.services[options.Namespace], nil
+}
+
+func (r *testRuntime) Delete(srv *goruntime.Service, opts ...goruntime.DeleteOption) error {
+	return nil
+}
+
+func TestPreview(t *testing.T) {
+	var comments []string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		comments = append(comments, req.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer github.Close()
+
+	rt := &testRuntime{services: map[string][]*goruntime.Service{}}
+	h, err := NewHandler(Runtime(rt), Secret("secret"), Repositories("Micro/Services"), Token("token"), API(github.URL), Folders("foo", "bar"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating the handler: %v", err)
+	}
+
+	send := func(action, signature string) int {
+		return sendWebhook(h, action, "micro/services", signature)
+	}
+
+	if code := send("opened", "sha1=invalid"); code != http.StatusUnauthorized {
+		t.Fatalf("Expected unsigned webhooks to be rejected, got %v", code)
+	}
+	if code := sendWebhook(h, "opened", "attacker/services", ""); code != http.StatusForbidden {
+		t.Fatalf("Expected webhooks of other repositories to be rejected, got %v", code)
+	}
+	if len(rt.services) != 0 {
+		t.Fatalf("Expected nothing to be deployed, got %v", rt.services)
+	}
+
+	if code := send("opened", ""); code != http.StatusNoContent {
+		t.Fatalf("Unexpected status deploying the preview: %v", code)
+	}
+	srvs := rt.services["preview-services-42"]
+	if len(srvs) != 2 || srvs[0].Name != "foo" || srvs[0].Source != "github.com/micro/services/foo" || srvs[0].Version != "feature" {
+		t.Errorf("Unexpected services deployed: %v", srvs)
+	}
+	if len(comments) != 1 || comments[0] != "/repos/micro/services/issues/42/comments" {
+		t.Errorf("Expected the preview URL to be commented on the pull request, got %v", comments)
+	}
+
+	if code := send("closed", ""); code != http.StatusNoContent {
+		t.Fatalf("Unexpected status tearing down the preview: %v", code)
+	}
+	if _, ok := rt.services["preview-services-42"]; ok {
+		t.Errorf("Expected the preview namespace to be deleted")
+	}
+}
+
+// sendWebhook sends a pull request webhook of the repo, signed with the secret unless a
+// signature is given, returning the status code
+func sendWebhook(h http.Handler, action, repo, signature string) int {
+	body := `{"action": "` + action + `", "number": 42, "pull_request": {"head": {"ref": "feature"}}, "repository": {"full_name": "` + repo + `"}}`
+	if len(signature) == 0 {
+		mac := hmac.New(sha1.New, []byte("secret"))
+		mac.Write([]byte(body))
+		signature = "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature", signature)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestNewHandler(t *testing.T) {
+	if _, err := NewHandler(Repositories("micro/services")); err != ErrNoSecret {
+		t.Errorf("Expected a handler without a secret to be refused, got %v", err)
+	}
+	if _, err := NewHandler(Secret("secret")); err != ErrNoRepositories {
+		t.Errorf("Expected a handler without repositories to be refused, got %v", err)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	if ns := Namespace("micro/My_Services", 1); ns != "preview-my-services-1" {
+		t.Errorf("Unexpected namespace %v", ns)
+	}
+	if ns := Namespace("micro/"+strings.Repeat("a", 100), 1); len(ns) > 63 {
+		t.Errorf("Expected the namespace to be a valid DNS label, got %v", ns)
+	}
+}
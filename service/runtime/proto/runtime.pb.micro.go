@@ -49,6 +49,7 @@ type RuntimeService interface {
 	Logs(ctx context.Context, in *LogsRequest, opts ...client.CallOption) (Runtime_LogsService, error)
 	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...client.CallOption) (*CreateNamespaceResponse, error)
 	DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...client.CallOption) (*DeleteNamespaceResponse, error)
+	FreezeOverrides(ctx context.Context, in *FreezeOverridesRequest, opts ...client.CallOption) (*FreezeOverridesResponse, error)
 }
 
 type runtimeService struct {
@@ -172,6 +173,16 @@ func (c *runtimeService) DeleteNamespace(ctx context.Context, in *DeleteNamespac
 	return out, nil
 }
 
+func (c *runtimeService) FreezeOverrides(ctx context.Context, in *FreezeOverridesRequest, opts ...client.CallOption) (*FreezeOverridesResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.FreezeOverrides", in)
+	out := new(FreezeOverridesResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Runtime service
 
 type RuntimeHandler interface {
@@ -182,6 +193,7 @@ type RuntimeHandler interface {
 	Logs(context.Context, *LogsRequest, Runtime_LogsStream) error
 	CreateNamespace(context.Context, *CreateNamespaceRequest, *CreateNamespaceResponse) error
 	DeleteNamespace(context.Context, *DeleteNamespaceRequest, *DeleteNamespaceResponse) error
+	FreezeOverrides(context.Context, *FreezeOverridesRequest, *FreezeOverridesResponse) error
 }
 
 func RegisterRuntimeHandler(s server.Server, hdlr RuntimeHandler, opts ...server.HandlerOption) error {
@@ -193,6 +205,7 @@ func RegisterRuntimeHandler(s server.Server, hdlr RuntimeHandler, opts ...server
 		Logs(ctx context.Context, stream server.Stream) error
 		CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, out *CreateNamespaceResponse) error
 		DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, out *DeleteNamespaceResponse) error
+		FreezeOverrides(ctx context.Context, in *FreezeOverridesRequest, out *FreezeOverridesResponse) error
 	}
 	type Runtime struct {
 		runtime
@@ -268,3 +281,7 @@ func (h *runtimeHandler) CreateNamespace(ctx context.Context, in *CreateNamespac
 func (h *runtimeHandler) DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, out *DeleteNamespaceResponse) error {
 	return h.RuntimeHandler.DeleteNamespace(ctx, in, out)
 }
+
+func (h *runtimeHandler) FreezeOverrides(ctx context.Context, in *FreezeOverridesRequest, out *FreezeOverridesResponse) error {
+	return h.RuntimeHandler.FreezeOverrides(ctx, in, out)
+}
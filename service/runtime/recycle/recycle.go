@@ -0,0 +1,163 @@
+// Package recycle parses the recycling policies of services, which restart the instances of a
+// service once they've been running for too long or their memory has grown too much, mitigating
+// slow memory leaks without manual restarts. For example:
+//
+//	age=24h; memory=512MiB; growth=50%
+//
+// recycles an instance once it has been running for a day, is using more than 512MiB
+// or is using 50% more memory than it was once it warmed up. Any of the limits can be left out.
+// Growth is measured from the memory an instance uses after the warmup, five minutes by default,
+// which can be set with e.g. "warmup=10m".
+package recycle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Key is the service metadata key the policy is stored with
+	Key = "recycle.policy"
+)
+
+var (
+	// DefaultWarmup is how long an instance runs for before its memory is used as the baseline
+	DefaultWarmup = time.Minute * 5
+
+	units = map[string]uint64{
+		"":    1,
+		"b":   1,
+		"kb":  1000,
+		"mb":  1000 * 1000,
+		"gb":  1000 * 1000 * 1000,
+		"kib": 1 << 10,
+		"mib": 1 << 20,
+		"gib": 1 << 30,
+	}
+)
+
+// Policy of a service
+type Policy struct {
+	// MaxAge is how long an instance can run for, zero for no limit
+	MaxAge time.Duration
+	// MaxMemory is the number of bytes an instance can use, zero for no limit
+	MaxMemory uint64
+	// MaxGrowth is how much the memory of an instance can grow by after the warmup as a
+	// fraction of the baseline, e.g. 0.5 for 50%, zero for no limit
+	MaxGrowth float64
+	// Warmup is how long an instance runs for before its memory is used as the baseline
+	Warmup time.Duration
+}
+
+// Instance of a service
+type Instance struct {
+	// ID of the instance, e.g. the id of the registry node
+	ID string
+	// Address the instance is recycled at
+	Address string
+	// Started is the time the instance started
+	Started time.Time
+	// Memory is the resident set size of the instance in bytes
+	Memory uint64
+}
+
+// Warm returns true if the instance has run for longer than the warmup, so its memory can be used
+// as the baseline
+func (p *Policy) Warm(inst Instance, now time.Time) bool {
+	return now.Sub(inst.Started) >= p.Warmup
+}
+
+// Due returns the reason the instance should be recycled, blank if it shouldn't. The baseline is
+// the memory the instance used once warmed up, zero if it isn't known yet.
+func (p *Policy) Due(inst Instance, baseline uint64, now time.Time) string {
+	if age := now.Sub(inst.Started); p.MaxAge > 0 && age >= p.MaxAge {
+		return fmt.Sprintf("running for %v", age.Round(time.Second))
+	}
+	if p.MaxMemory > 0 && inst.Memory > p.MaxMemory {
+		return fmt.Sprintf("using %v bytes of memory", inst.Memory)
+	}
+	if p.MaxGrowth > 0 && baseline > 0 && inst.Memory > baseline {
+		if growth := float64(inst.Memory-baseline) / float64(baseline); growth > p.MaxGrowth {
+			return fmt.Sprintf("memory grew by %.0f%% since warming up", growth*100)
+		}
+	}
+	return ""
+}
+
+// Parse a policy
+func Parse(raw string) (*Policy, error) {
+	p := &Policy{Warmup: DefaultWarmup}
+
+	var limits int
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid limit %q, expected name=value", part)
+		}
+		name, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+
+		var err error
+		switch name {
+		case "age":
+			p.MaxAge, err = parseDuration(value)
+		case "memory":
+			p.MaxMemory, err = ParseBytes(value)
+		case "growth":
+			p.MaxGrowth, err = parsePercent(value)
+		case "warmup":
+			p.Warmup, err = parseDuration(value)
+			limits--
+		default:
+			return nil, fmt.Errorf("unknown limit %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", name, err)
+		}
+		limits++
+	}
+
+	if limits <= 0 {
+		return nil, fmt.Errorf("policy has no limits")
+	}
+	return p, nil
+}
+
+func parseDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%v must be positive", v)
+	}
+	return d, nil
+}
+
+// ParseBytes parses a positive number of bytes with an optional unit, e.g. 512MiB
+func ParseBytes(v string) (uint64, error) {
+	lower := strings.ToLower(v)
+	num := strings.TrimRight(lower, "abcdefghijklmnopqrstuvwxyz")
+	unit, ok := units[strings.TrimSpace(lower[len(num):])]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit in %v", v)
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(num), 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("%v is not a positive number of bytes", v)
+	}
+	return n * unit, nil
+}
+
+func parsePercent(v string) (float64, error) {
+	n, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%v is not a positive percentage", v)
+	}
+	return n / 100, nil
+}
@@ -0,0 +1,60 @@
+package recycle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicy(t *testing.T) {
+	p, err := Parse("age=24h; memory=512MiB; growth=50%")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.MaxAge != time.Hour*24 || p.MaxMemory != 512<<20 || p.MaxGrowth != 0.5 || p.Warmup != DefaultWarmup {
+		t.Fatalf("Unexpected policy: %+v", p)
+	}
+
+	now := time.Now()
+	tt := []struct {
+		Name     string
+		Age      time.Duration
+		Memory   uint64
+		Baseline uint64
+		Due      bool
+	}{
+		{"Healthy", time.Hour, 100 << 20, 100 << 20, false},
+		{"Old", time.Hour * 25, 100 << 20, 100 << 20, true},
+		{"Large", time.Hour, 600 << 20, 500 << 20, true},
+		{"Growing", time.Hour, 160 << 20, 100 << 20, true},
+		{"NoBaseline", time.Hour, 160 << 20, 0, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			inst := Instance{ID: "foo-1", Started: now.Add(-tc.Age), Memory: tc.Memory}
+			if reason := p.Due(inst, tc.Baseline, now); (len(reason) > 0) != tc.Due {
+				t.Errorf("Expected due to be %v, got %q", tc.Due, reason)
+			}
+		})
+	}
+
+	if p.Warm(Instance{Started: now.Add(-time.Minute)}, now) {
+		t.Errorf("Expected the instance to be warming up")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"warmup=10m",
+		"age",
+		"age=-1h",
+		"memory=lots",
+		"memory=10PB",
+		"growth=0%",
+		"uptime=1h",
+	} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Expected an error parsing %q", raw)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/micro/micro/v3/service/events"
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/freeze"
 	pb "github.com/micro/micro/v3/service/runtime/proto"
 )
 
@@ -20,6 +21,15 @@ type Runtime struct {
 	Runtime gorun.Runtime
 }
 
+// runtimeError returns a forbidden error if the change was rejected because the namespace is
+// frozen, otherwise an internal server error
+func runtimeError(id string, err error) error {
+	if _, ok := err.(*freeze.ErrFrozen); ok {
+		return errors.Forbidden(id, err.Error())
+	}
+	return errors.InternalServerError(id, err.Error())
+}
+
 func (r *Runtime) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
 	// set defaults
 	if req.Options == nil {
@@ -84,7 +94,7 @@ func (r *Runtime) Create(ctx context.Context, req *pb.CreateRequest, rsp *pb.Cre
 
 	log.Infof("Creating service %s version %s source %s", service.Name, service.Version, service.Source)
 	if err := r.Runtime.Create(service, options...); err != nil {
-		return errors.InternalServerError("runtime.Runtime.Create", err.Error())
+		return runtimeError("runtime.Runtime.Create", err)
 	}
 
 	// publish the create event
@@ -131,7 +141,7 @@ func (r *Runtime) Update(ctx context.Context, req *pb.UpdateRequest, rsp *pb.Upd
 	log.Infof("Updating service %s version %s source %s", service.Name, service.Version, service.Source)
 
 	if err := r.Runtime.Update(service, options...); err != nil {
-		return errors.InternalServerError("runtime.Runtime.Update", err.Error())
+		return runtimeError("runtime.Runtime.Update", err)
 	}
 
 	// publish the update event
@@ -314,3 +324,36 @@ func (r *Runtime) DeleteNamespace(ctx context.Context, req *pb.DeleteNamespaceRe
 		"namespace": req.Namespace,
 	}))
 }
+
+// FreezeOverrides returns the overrides of the freezes of the namespace recorded in the audit log
+func (r *Runtime) FreezeOverrides(ctx context.Context, req *pb.FreezeOverridesRequest, rsp *pb.FreezeOverridesResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("runtime.Runtime.FreezeOverrides", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("runtime.Runtime.FreezeOverrides", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.FreezeOverrides", err.Error())
+	}
+
+	overrides, err := freeze.Overrides(req.Namespace)
+	if err != nil {
+		return errors.InternalServerError("runtime.Runtime.FreezeOverrides", err.Error())
+	}
+	for _, o := range overrides {
+		rsp.Overrides = append(rsp.Overrides, &pb.FreezeOverride{
+			Namespace: o.Namespace,
+			Window:    o.Window,
+			Action:    o.Action,
+			Account:   o.Account,
+			Issuer:    o.Issuer,
+			Reason:    o.Reason,
+			Timestamp: o.Timestamp,
+		})
+	}
+	return nil
+}
@@ -1,14 +1,17 @@
 package server
 
 import (
+	"net/http"
 	"os"
 
 	"github.com/micro/cli/v2"
 	goruntime "github.com/micro/go-micro/v3/runtime"
 	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/debug/probe"
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
 	"github.com/micro/micro/v3/service/runtime/manager"
+	"github.com/micro/micro/v3/service/runtime/preview"
 	pb "github.com/micro/micro/v3/service/runtime/proto"
 )
 
@@ -30,6 +33,41 @@ var (
 			Usage:   "Set the max retries per service",
 			EnvVars: []string{"MICRO_RUNTIME_RETRIES"},
 		},
+		&cli.StringFlag{
+			Name:    "preview_address",
+			Usage:   "Set the address GitHub webhooks are received on to deploy previews of pull requests, e.g. :8089",
+			EnvVars: []string{"MICRO_RUNTIME_PREVIEW_ADDRESS"},
+		},
+		&cli.StringFlag{
+			Name:    "preview_secret",
+			Usage:   "Set the secret the GitHub webhooks are signed with, required to deploy previews",
+			EnvVars: []string{"MICRO_RUNTIME_PREVIEW_SECRET"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "preview_repositories",
+			Usage:   "Set the GitHub repositories whose pull requests are deployed, e.g. micro/services",
+			EnvVars: []string{"MICRO_RUNTIME_PREVIEW_REPOSITORIES"},
+		},
+		&cli.StringFlag{
+			Name:    "preview_token",
+			Usage:   "Set the GitHub token used to comment the preview URLs on pull requests",
+			EnvVars: []string{"MICRO_RUNTIME_PREVIEW_TOKEN"},
+		},
+		&cli.StringFlag{
+			Name:    "preview_domain",
+			Usage:   "Set the domain the preview URLs are generated under, e.g. preview.example.com",
+			EnvVars: []string{"MICRO_RUNTIME_PREVIEW_DOMAIN"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "preview_folders",
+			Usage:   "Set the folders of the services in the repository to deploy, the repository is deployed if blank",
+			EnvVars: []string{"MICRO_RUNTIME_PREVIEW_FOLDERS"},
+		},
+		&cli.StringFlag{
+			Name:    "probe_region",
+			Usage:   "Set the region the synthetic probes are run from, probes for other regions are skipped",
+			EnvVars: []string{"MICRO_RUNTIME_PROBE_REGION"},
+		},
 	}
 )
 
@@ -68,6 +106,34 @@ func Run(ctx *cli.Context) error {
 		os.Exit(1)
 	}
 
+	// run the synthetic probes against the services
+	probes := probe.NewRunner(probe.Region(ctx.String("probe_region")))
+	probes.Start()
+	defer probes.Stop()
+
+	// receive webhooks to deploy previews
+	if addr := ctx.String("preview_address"); len(addr) > 0 {
+		h, err := preview.NewHandler(
+			preview.Runtime(manager),
+			preview.Secret(ctx.String("preview_secret")),
+			preview.Repositories(ctx.StringSlice("preview_repositories")...),
+			preview.Token(ctx.String("preview_token")),
+			preview.Domain(ctx.String("preview_domain")),
+			preview.Folders(ctx.StringSlice("preview_folders")...),
+		)
+		if err != nil {
+			log.Errorf("failed to receive preview webhooks: %v", err)
+			os.Exit(1)
+		}
+
+		go func() {
+			log.Infof("Receiving preview webhooks on %v", addr)
+			if err := http.ListenAndServe(addr, h); err != nil {
+				log.Errorf("Error receiving preview webhooks: %v", err)
+			}
+		}()
+	}
+
 	// register the runtime handler
 	pb.RegisterRuntimeHandler(srv.Server(), &Runtime{
 		Runtime: manager,
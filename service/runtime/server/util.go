@@ -35,6 +35,7 @@ func toService(s *pb.Service) *runtime.Service {
 func toCreateOptions(ctx context.Context, opts *pb.CreateOptions) []runtime.CreateOption {
 	options := []runtime.CreateOption{
 		runtime.CreateNamespace(opts.Namespace),
+		runtime.CreateContext(ctx),
 	}
 
 	// command options
@@ -93,6 +94,7 @@ func toReadOptions(ctx context.Context, opts *pb.ReadOptions) []runtime.ReadOpti
 func toUpdateOptions(ctx context.Context, opts *pb.UpdateOptions) []runtime.UpdateOption {
 	return []runtime.UpdateOption{
 		runtime.UpdateNamespace(opts.Namespace),
+		runtime.UpdateContext(ctx),
 	}
 }
 
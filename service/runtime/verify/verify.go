@@ -0,0 +1,208 @@
+// Package verify parses the post-deploy verification policies of services, which define when an
+// update of a service is done. Once the new instances have started, the runtime makes the smoke
+// test calls of the policy to each of them, then checks their health and metrics on an interval
+// for the duration of the policy. The update is only marked successful if every check passes,
+// otherwise it's rolled back to the last update which did. For example:
+//
+//	{
+//		"calls": [{"endpoint": "Helloworld.Call", "request": {"name": "smoke"}}],
+//		"health": true,
+//		"max_error_rate": 0.05,
+//		"max_memory": "512MiB",
+//		"duration": "5m",
+//		"interval": "30s"
+//	}
+//
+// makes a smoke test call to each new instance, then requires them to stay healthy, with fewer
+// than 5% of their requests failing and using less than 512MiB of memory, for five minutes.
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/micro/micro/v3/service/runtime/recycle"
+)
+
+const (
+	// Key is the service metadata key the policy is stored with
+	Key = "verify.policy"
+	// StatusKey is the service metadata key the status of the last update is returned with
+	StatusKey = "verify.status"
+	// ErrorKey is the service metadata key the reason the last update failed is returned with
+	ErrorKey = "verify.error"
+)
+
+// Statuses of an update
+const (
+	// Verifying updates are being checked
+	Verifying = "verifying"
+	// Succeeded updates passed every check
+	Succeeded = "succeeded"
+	// Failed updates failed a check and weren't rolled back
+	Failed = "failed"
+	// RolledBack updates failed a check and were rolled back
+	RolledBack = "rolled back"
+)
+
+var (
+	// DefaultDuration is how long the instances are checked for
+	DefaultDuration = time.Minute * 5
+	// DefaultInterval is how often the instances are checked
+	DefaultInterval = time.Second * 30
+	// DefaultTimeout is how long the new instances have to start
+	DefaultTimeout = time.Minute * 10
+	// DefaultCallTimeout is the timeout of the smoke test calls
+	DefaultCallTimeout = time.Second * 10
+)
+
+// Call made to each new instance as a smoke test
+type Call struct {
+	// Endpoint to call, e.g. Foo.Bar
+	Endpoint string
+	// Request sent, encoded as JSON
+	Request json.RawMessage
+	// Status expected, 200 for a successful call or the code of the error expected
+	Status int
+}
+
+// Policy of a service
+type Policy struct {
+	// Calls made to each new instance
+	Calls []Call
+	// Health requires the instances to report they're healthy
+	Health bool
+	// MaxErrorRate is the fraction of requests the instances can fail while they're checked,
+	// zero for no limit
+	MaxErrorRate float64
+	// MaxMemory is the number of bytes each instance can use, zero for no limit
+	MaxMemory uint64
+	// Duration the instances are checked for
+	Duration time.Duration
+	// Interval the instances are checked at
+	Interval time.Duration
+	// Timeout the new instances have to start within
+	Timeout time.Duration
+	// Rollback updates which fail to the last update which succeeded
+	Rollback bool
+}
+
+// Sample of an instance, taken each time the instances are checked
+type Sample struct {
+	// ID of the instance, e.g. the id of the registry node
+	ID string
+	// Started is the time the instance started
+	Started time.Time
+	// Healthy is true if the instance reported it's healthy
+	Healthy bool
+	// Requests and Errors served by the instance since it started
+	Requests uint64
+	Errors   uint64
+	// Memory used by the instance in bytes
+	Memory uint64
+}
+
+// Check returns the reason the samples fail the policy, blank if they pass. The baselines are
+// the samples of the instances taken once they started, which the error rate is measured from.
+func (p *Policy) Check(baselines map[string]Sample, samples []Sample) string {
+	for _, s := range samples {
+		if p.Health && !s.Healthy {
+			return fmt.Sprintf("instance %v is unhealthy", s.ID)
+		}
+		if p.MaxMemory > 0 && s.Memory > p.MaxMemory {
+			return fmt.Sprintf("instance %v is using %v bytes of memory", s.ID, s.Memory)
+		}
+		if p.MaxErrorRate <= 0 {
+			continue
+		}
+
+		// instances which restarted are measured from when they started
+		base := baselines[s.ID]
+		if s.Requests < base.Requests || s.Errors < base.Errors {
+			base = Sample{}
+		}
+		reqs, errs := s.Requests-base.Requests, s.Errors-base.Errors
+		if reqs == 0 {
+			continue
+		}
+		if rate := float64(errs) / float64(reqs); rate > p.MaxErrorRate {
+			return fmt.Sprintf("instance %v failed %.1f%% of %v requests", s.ID, rate*100, reqs)
+		}
+	}
+	return ""
+}
+
+// Parse a policy from its JSON encoding
+func Parse(raw string) (*Policy, error) {
+	var r struct {
+		Calls []struct {
+			Endpoint string          `json:"endpoint"`
+			Request  json.RawMessage `json:"request"`
+			Status   int             `json:"status"`
+		} `json:"calls"`
+		Health       bool    `json:"health"`
+		MaxErrorRate float64 `json:"max_error_rate"`
+		MaxMemory    string  `json:"max_memory"`
+		Duration     string  `json:"duration"`
+		Interval     string  `json:"interval"`
+		Timeout      string  `json:"timeout"`
+		Rollback     *bool   `json:"rollback"`
+	}
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		return nil, err
+	}
+
+	p := &Policy{Health: r.Health, MaxErrorRate: r.MaxErrorRate, Rollback: true}
+	if r.Rollback != nil {
+		p.Rollback = *r.Rollback
+	}
+	if p.MaxErrorRate < 0 || p.MaxErrorRate >= 1 {
+		return nil, fmt.Errorf("invalid max_error_rate %v, expected a fraction such as 0.05", r.MaxErrorRate)
+	}
+
+	for _, c := range r.Calls {
+		if len(c.Endpoint) == 0 {
+			return nil, fmt.Errorf("call is missing the endpoint")
+		}
+		call := Call{Endpoint: c.Endpoint, Request: c.Request, Status: c.Status}
+		if len(call.Request) == 0 {
+			call.Request = json.RawMessage("{}")
+		}
+		if call.Status == 0 {
+			call.Status = 200
+		}
+		p.Calls = append(p.Calls, call)
+	}
+
+	var err error
+	if len(r.MaxMemory) > 0 {
+		if p.MaxMemory, err = recycle.ParseBytes(r.MaxMemory); err != nil {
+			return nil, fmt.Errorf("invalid max_memory: %v", err)
+		}
+	}
+	if p.Duration, err = parseDuration(r.Duration, DefaultDuration); err != nil {
+		return nil, fmt.Errorf("invalid duration: %v", err)
+	}
+	if p.Interval, err = parseDuration(r.Interval, DefaultInterval); err != nil {
+		return nil, fmt.Errorf("invalid interval: %v", err)
+	}
+	if p.Timeout, err = parseDuration(r.Timeout, DefaultTimeout); err != nil {
+		return nil, fmt.Errorf("invalid timeout: %v", err)
+	}
+	return p, nil
+}
+
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if len(s) == 0 {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%v must be positive", s)
+	}
+	return d, nil
+}
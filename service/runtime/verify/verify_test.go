@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicy(t *testing.T) {
+	p, err := Parse(`{
+		"calls": [{"endpoint": "Helloworld.Call", "request": {"name": "smoke"}}, {"endpoint": "Helloworld.Missing", "status": 404}],
+		"health": true,
+		"max_error_rate": 0.1,
+		"max_memory": "512MiB",
+		"duration": "10m"
+	}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(p.Calls) != 2 || p.Calls[0].Status != 200 || p.Calls[1].Status != 404 || string(p.Calls[1].Request) != "{}" {
+		t.Fatalf("Unexpected calls: %+v", p.Calls)
+	}
+	if p.MaxMemory != 512<<20 || p.Duration != time.Minute*10 || p.Interval != DefaultInterval || !p.Rollback {
+		t.Fatalf("Unexpected policy: %+v", p)
+	}
+
+	baselines := map[string]Sample{"foo-1": {ID: "foo-1", Requests: 100, Errors: 50}}
+	tt := []struct {
+		Name   string
+		Sample Sample
+		Passed bool
+	}{
+		{"Healthy", Sample{ID: "foo-1", Healthy: true, Requests: 200, Errors: 55, Memory: 100 << 20}, true},
+		{"Unhealthy", Sample{ID: "foo-1", Requests: 200, Errors: 55}, false},
+		{"Large", Sample{ID: "foo-1", Healthy: true, Requests: 200, Errors: 55, Memory: 600 << 20}, false},
+		{"Failing", Sample{ID: "foo-1", Healthy: true, Requests: 200, Errors: 80}, false},
+		{"Restarted", Sample{ID: "foo-1", Healthy: true, Requests: 10, Errors: 5}, false},
+		{"NoRequests", Sample{ID: "foo-2", Healthy: true}, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if reason := p.Check(baselines, []Sample{tc.Sample}); (len(reason) == 0) != tc.Passed {
+				t.Errorf("Expected passed to be %v, got %q", tc.Passed, reason)
+			}
+		})
+	}
+
+	p, err = Parse(`{"rollback": false}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Rollback || p.Duration != DefaultDuration {
+		t.Errorf("Unexpected policy: %+v", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"health=true",
+		`{"calls": [{"request": {}}]}`,
+		`{"max_error_rate": 5}`,
+		`{"max_memory": "lots"}`,
+		`{"duration": "-1m"}`,
+		`{"interval": "often"}`,
+	} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Expected an error parsing %q", raw)
+		}
+	}
+}
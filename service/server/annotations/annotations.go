@@ -0,0 +1,408 @@
+// Package annotations enforces the rules services declare for their endpoints as options of the
+// methods in their protos, using the extension in annotations.proto, for example:
+//
+//	import "github.com/micro/micro/v3/service/server/annotations/annotations.proto";
+//
+//	rpc Delete(DeleteRequest) returns (DeleteResponse) {
+//		option (micro.server.rule) = { scopes: ["admin"], rate_limit: "10/m" };
+//	};
+//
+// The rules are read from the descriptors of the protos when the handlers are created, so the
+// scopes and rate limits live next to the endpoints they protect rather than being set up by
+// each service at startup.
+package annotations
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"google.golang.org/protobuf/encoding/protowire"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldNumber of the rule extension of the method options
+const FieldNumber protowire.Number = 51300
+
+// the field numbers of the rule message
+const (
+	scopesField    protowire.Number = 1
+	rateLimitField protowire.Number = 2
+)
+
+// AnyScope allows any account to call the endpoint
+const AnyScope = "*"
+
+var (
+	// DefaultRules are the rules of the endpoints of the handlers created by the server
+	DefaultRules = NewRules()
+	// DefaultMaxCallers is the number of callers the rate limits of each endpoint are tracked
+	// for, the least recently seen callers are forgotten once there are more
+	DefaultMaxCallers = 10000
+)
+
+// Limit of the rate requests are made at
+type Limit struct {
+	// Requests allowed in each period, zero for no limit
+	Requests int
+	Per      time.Duration
+}
+
+func (l Limit) String() string {
+	switch l.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", l.Requests)
+	case time.Minute:
+		return fmt.Sprintf("%d/m", l.Requests)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", l.Requests)
+	}
+	return fmt.Sprintf("%d/%v", l.Requests, l.Per)
+}
+
+// ParseLimit parses a limit, e.g. 100/s, 10/m, 1000/h or 5/30s
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected requests/period", s)
+	}
+	n, err := strconv.Atoi(parts[0])
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, requests must be a positive number", s)
+	}
+
+	var per time.Duration
+	switch parts[1] {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		if per, err = time.ParseDuration(parts[1]); err != nil || per <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q, unknown period %v", s, parts[1])
+		}
+	}
+	return Limit{Requests: n, Per: per}, nil
+}
+
+// Rule of an endpoint
+type Rule struct {
+	// Scopes the caller needs one of, AnyScope allowing any account. Endpoints without scopes
+	// are only protected by the auth rules.
+	Scopes []string
+	// RateLimit of each caller
+	RateLimit Limit
+}
+
+// Parse the rule from the encoded method options, nil if the options have no rule
+func Parse(b []byte) (*Rule, error) {
+	var rule *Rule
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if num != FieldNumber || typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		// repeated occurrences of a message are merged
+		if rule == nil {
+			rule = &Rule{}
+		}
+		if err := parseRule(v, rule); err != nil {
+			return nil, err
+		}
+	}
+	return rule, nil
+}
+
+func parseRule(b []byte, rule *Rule) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType || (num != scopesField && num != rateLimitField) {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch num {
+		case scopesField:
+			rule.Scopes = append(rule.Scopes, string(v))
+		case rateLimitField:
+			l, err := ParseLimit(string(v))
+			if err != nil {
+				return err
+			}
+			rule.RateLimit = l
+		}
+	}
+	return nil
+}
+
+// Method returns the rule declared in the options of the method, nil if there's none
+func Method(md protoreflect.MethodDescriptor) (*Rule, error) {
+	opts := md.Options()
+	if opts == nil {
+		return nil, nil
+	}
+	b, err := protov2.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Endpoints returns the rules of the endpoints of the handler, keyed by endpoint name, e.g.
+// Foo.Bar. The handler's methods are matched to the methods of the service of the same name in
+// the protos.
+func Endpoints(handler interface{}) (map[string]*Rule, error) {
+	name := reflect.Indirect(reflect.ValueOf(handler)).Type().Name()
+	sd := service(handler, name)
+	if sd == nil {
+		return nil, nil
+	}
+
+	rules := make(map[string]*Rule)
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		rule, err := Method(md)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule of %v.%v: %v", name, md.Name(), err)
+		}
+		if rule != nil {
+			rules[name+"."+string(md.Name())] = rule
+		}
+	}
+	return rules, nil
+}
+
+// service returns the descriptor of the service the handler implements, looking in the files
+// its request types are declared in. Services of the same name in other packages aren't used,
+// since their rules aren't the handler's.
+func service(handler interface{}, name string) protoreflect.ServiceDescriptor {
+	typ := reflect.TypeOf(handler)
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if m.PkgPath != "" || m.Type.NumIn() != 4 {
+			continue
+		}
+		req := m.Type.In(2)
+		if req.Kind() != reflect.Ptr || req.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		msg, ok := reflect.New(req.Elem()).Interface().(proto.Message)
+		if !ok {
+			continue
+		}
+		file := proto.MessageV2(msg).ProtoReflect().Descriptor().ParentFile()
+		if sd := file.Services().ByName(protoreflect.Name(name)); sd != nil {
+			return sd
+		}
+	}
+	return nil
+}
+
+// Rules of endpoints
+type Rules struct {
+	sync.RWMutex
+	rules map[string]*Rule
+}
+
+// NewRules returns a set of rules with none of the endpoints'
+func NewRules() *Rules {
+	return &Rules{rules: make(map[string]*Rule)}
+}
+
+// Register the rules declared in the protos of the handler's endpoints. Rules which can't be
+// parsed are logged, since the service would otherwise fail to start over an option.
+func (r *Rules) Register(handler interface{}) {
+	rules, err := Endpoints(handler)
+	if err != nil {
+		logger.Errorf("Error reading the rules of the endpoints: %v", err)
+		return
+	}
+	for endpoint, rule := range rules {
+		r.Set(endpoint, rule)
+	}
+}
+
+// Set the rule of the endpoint
+func (r *Rules) Set(endpoint string, rule *Rule) {
+	r.Lock()
+	r.rules[endpoint] = rule
+	r.Unlock()
+}
+
+// Get the rule of the endpoint
+func (r *Rules) Get(endpoint string) (*Rule, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	rule, ok := r.rules[endpoint]
+	return rule, ok
+}
+
+// allowed returns true if an account with the scopes may call the endpoint
+func (r *Rule) allowed(acc *goauth.Account) bool {
+	for _, s := range r.Scopes {
+		if s == AnyScope {
+			return true
+		}
+		for _, as := range acc.Scopes {
+			if s == as {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bucket of the requests a caller may make, refilled at the rate of the limit
+type bucket struct {
+	caller  string
+	tokens  float64
+	updated time.Time
+}
+
+// limiter of the rate each caller calls an endpoint at
+type limiter struct {
+	sync.Mutex
+	// buckets of the callers, the elements of the lru
+	buckets map[string]*list.Element
+	// lru is the buckets, the most recently used at the front
+	lru *list.List
+}
+
+func newLimiter() *limiter {
+	return &limiter{buckets: make(map[string]*list.Element), lru: list.New()}
+}
+
+// allow returns true if the caller is within the limit, taking a request from its bucket
+func (l *limiter) allow(caller string, limit Limit, now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	var b *bucket
+	if e, ok := l.buckets[caller]; ok {
+		l.lru.MoveToFront(e)
+		b = e.Value.(*bucket)
+	} else {
+		b = &bucket{caller: caller, tokens: float64(limit.Requests), updated: now}
+		l.buckets[caller] = l.lru.PushFront(b)
+		l.evict()
+	}
+
+	rate := float64(limit.Requests) / float64(limit.Per)
+	b.tokens += rate * float64(now.Sub(b.updated))
+	if max := float64(limit.Requests); b.tokens > max {
+		b.tokens = max
+	}
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// evict the least recently seen callers once there are too many
+func (l *limiter) evict() {
+	for DefaultMaxCallers > 0 && l.lru.Len() > DefaultMaxCallers {
+		e := l.lru.Back()
+		l.lru.Remove(e)
+		delete(l.buckets, e.Value.(*bucket).caller)
+	}
+}
+
+// HandlerWrapper enforces the rules of the endpoints in DefaultRules. Callers without one of
+// the scopes are rejected with a 401 or 403 error and callers over the rate limit with a 429.
+// The auth wrapper must be applied before this wrapper so the account is in the context.
+func HandlerWrapper() server.HandlerWrapper {
+	var mtx sync.Mutex
+	limiters := make(map[string]*limiter)
+
+	get := func(endpoint string) *limiter {
+		mtx.Lock()
+		defer mtx.Unlock()
+		l, ok := limiters[endpoint]
+		if !ok {
+			l = newLimiter()
+			limiters[endpoint] = l
+		}
+		return l
+	}
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			rule, ok := DefaultRules.Get(req.Endpoint())
+			if !ok {
+				return h(ctx, req, rsp)
+			}
+
+			acc, hasAccount := goauth.AccountFromContext(ctx)
+			if len(rule.Scopes) > 0 {
+				if !hasAccount {
+					return errors.Unauthorized(req.Service(), "Unauthorized call made to %v:%v", req.Service(), req.Endpoint())
+				}
+				if !rule.allowed(acc) {
+					return errors.Forbidden(req.Service(), "Forbidden call made to %v:%v by %v", req.Service(), req.Endpoint(), acc.ID)
+				}
+			}
+
+			if rule.RateLimit.Requests > 0 {
+				// unauthenticated callers share a limit
+				var caller string
+				if hasAccount {
+					caller = acc.ID
+				}
+				if !get(req.Endpoint()).allow(caller, rule.RateLimit, time.Now()) {
+					return errors.TooManyRequests(req.Service(), "Rate limit of %v exceeded for %v:%v", rule.RateLimit, req.Service(), req.Endpoint())
+				}
+			}
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package annotations
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestParse(t *testing.T) {
+	var rule []byte
+	rule = protowire.AppendTag(rule, scopesField, protowire.BytesType)
+	rule = protowire.AppendString(rule, "admin")
+	rule = protowire.AppendTag(rule, rateLimitField, protowire.BytesType)
+	rule = protowire.AppendString(rule, "10/m")
+
+	// the deprecated option, which should be skipped, followed by the rule
+	var opts []byte
+	opts = protowire.AppendTag(opts, 33, protowire.VarintType)
+	opts = protowire.AppendVarint(opts, 1)
+	opts = protowire.AppendTag(opts, FieldNumber, protowire.BytesType)
+	opts = protowire.AppendBytes(opts, rule)
+
+	r, err := Parse(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Rule{Scopes: []string{"admin"}, RateLimit: Limit{Requests: 10, Per: time.Minute}}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("Expected %v, got %v", expected, r)
+	}
+
+	if r, err := Parse(opts[:3]); err != nil || r != nil {
+		t.Errorf("Expected no rule, got %v %v", r, err)
+	}
+	if _, err := Parse(opts[:len(opts)-1]); err == nil {
+		t.Errorf("Expected truncated options to be invalid")
+	}
+
+	for _, s := range []string{"10", "0/s", "x/s", "10/fortnight"} {
+		if _, err := ParseLimit(s); err == nil {
+			t.Errorf("Expected %q to be invalid", s)
+		}
+	}
+	if l, err := ParseLimit("5/30s"); err != nil || l.Per != time.Second*30 {
+		t.Errorf("Expected a period of 30s, got %v %v", l, err)
+	}
+}
+
+type testRequest struct {
+	server.Request
+	endpoint string
+}
+
+func (r *testRequest) Service() string  { return "foo" }
+func (r *testRequest) Endpoint() string { return r.endpoint }
+
+func TestHandlerWrapper(t *testing.T) {
+	DefaultRules = NewRules()
+	DefaultRules.Set("Foo.Delete", &Rule{Scopes: []string{"admin"}})
+	DefaultRules.Set("Foo.Read", &Rule{RateLimit: Limit{Requests: 2, Per: time.Hour}})
+
+	h := HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+	call := func(endpoint string, acc *goauth.Account) int32 {
+		ctx := context.TODO()
+		if acc != nil {
+			ctx = goauth.ContextWithAccount(ctx, acc)
+		}
+		if err := h(ctx, &testRequest{endpoint: endpoint}, nil); err != nil {
+			return errors.Parse(err).Code
+		}
+		return 200
+	}
+
+	admin := &goauth.Account{ID: "admin", Scopes: []string{"admin"}}
+	dev := &goauth.Account{ID: "dev", Scopes: []string{"developer"}}
+	if code := call("Foo.Delete", nil); code != 401 {
+		t.Errorf("Expected an unauthenticated call to be unauthorized, got %v", code)
+	}
+	if code := call("Foo.Delete", dev); code != 403 {
+		t.Errorf("Expected a call without the scope to be forbidden, got %v", code)
+	}
+	if code := call("Foo.Delete", admin); code != 200 {
+		t.Errorf("Expected a call with the scope to be allowed, got %v", code)
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := call("Foo.Read", dev); code != 200 {
+			t.Fatalf("Expected call %v to be within the limit, got %v", i, code)
+		}
+	}
+	if code := call("Foo.Read", dev); code != 429 {
+		t.Errorf("Expected the call over the limit to be rejected, got %v", code)
+	}
+	if code := call("Foo.Read", admin); code != 200 {
+		t.Errorf("Expected each caller to have their own limit, got %v", code)
+	}
+	if code := call("Foo.Other", nil); code != 200 {
+		t.Errorf("Expected an endpoint without a rule to be allowed, got %v", code)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	max := DefaultMaxCallers
+	DefaultMaxCallers = 2
+	defer func() { DefaultMaxCallers = max }()
+
+	l := newLimiter()
+	limit := Limit{Requests: 1, Per: time.Hour}
+	now := time.Now()
+
+	if !l.allow("foo", limit, now) || l.allow("foo", limit, now) {
+		t.Fatalf("Expected foo to be limited after one call")
+	}
+	l.allow("bar", limit, now)
+	l.allow("baz", limit, now)
+	if len(l.buckets) != 2 || l.lru.Len() != 2 {
+		t.Fatalf("Expected 2 callers to be tracked, got %v", len(l.buckets))
+	}
+	if _, ok := l.buckets["foo"]; ok {
+		t.Errorf("Expected the least recently seen caller to be forgotten")
+	}
+}
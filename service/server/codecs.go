@@ -0,0 +1,62 @@
+package server
+
+import (
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/client/compress"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+// Start the server, advertising the content types and compressors it accepts in its metadata so
+// clients can negotiate a codec and compression with it
+func (s *schemaServer) Start() error {
+	opts := s.Server.Options()
+	node := &registry.Node{Metadata: opts.Metadata}
+
+	// a router decodes requests itself, so servers with one don't advertise content types
+	if types := s.accepted(); opts.Router == nil && types != nil {
+		util.SetCodecs(node, types)
+	}
+	util.SetCompressors(node, compress.Registered())
+	s.Server.Init(server.Metadata(node.Metadata))
+	return s.Server.Start()
+}
+
+// accept narrows the content types the server accepts to those a handler accepts
+func (s *schemaServer) accept(types []string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.codecs == nil {
+		s.codecs = make(map[string]bool, len(types))
+		for _, t := range types {
+			s.codecs[t] = true
+		}
+		return
+	}
+
+	accepted := make(map[string]bool, len(types))
+	for _, t := range types {
+		accepted[t] = true
+	}
+	for t := range s.codecs {
+		if !accepted[t] {
+			delete(s.codecs, t)
+		}
+	}
+}
+
+// accepted returns the content types every handler accepts, nil if there are no handlers
+func (s *schemaServer) accepted() []string {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.codecs == nil {
+		return nil
+	}
+	types := make([]string, 0, len(s.codecs))
+	for t := range s.codecs {
+		types = append(types, t)
+	}
+	return types
+}
@@ -0,0 +1,167 @@
+// Package concurrency provides a server wrapper which limits the number of requests each
+// endpoint handles at once, so one slow endpoint can't exhaust the goroutines and memory of the
+// whole server. Requests over the limit wait in a bounded queue for a handler to be free and
+// are rejected with a 429 error once the queue is full or they've waited for the timeout.
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// DefaultQueueTimeout is how long a request waits in the queue before it's rejected
+	DefaultQueueTimeout = time.Second * 5
+)
+
+// Limit of an endpoint
+type Limit struct {
+	// MaxConcurrent is the number of requests handled at once, zero for no limit
+	MaxConcurrent int
+	// MaxQueue is the number of requests waiting for a handler, zero to reject requests over
+	// the limit immediately
+	MaxQueue int
+}
+
+// Options of the wrapper
+type Options struct {
+	// Limit of the endpoints without their own
+	Limit Limit
+	// Endpoints with their own limits, keyed by endpoint name, e.g. Foo.Bar
+	Endpoints map[string]Limit
+	// QueueTimeout is how long a request waits in the queue before it's rejected
+	QueueTimeout time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// MaxConcurrent sets the number of requests each endpoint handles at once, zero for no limit
+func MaxConcurrent(n int) Option {
+	return func(o *Options) {
+		o.Limit.MaxConcurrent = n
+	}
+}
+
+// MaxQueue sets the number of requests to each endpoint waiting for a handler
+func MaxQueue(n int) Option {
+	return func(o *Options) {
+		o.Limit.MaxQueue = n
+	}
+}
+
+// QueueTimeout sets how long a request waits in the queue before it's rejected
+func QueueTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.QueueTimeout = d
+	}
+}
+
+// Endpoint sets the limit of an endpoint, overriding the default limit
+func Endpoint(name string, l Limit) Option {
+	return func(o *Options) {
+		if o.Endpoints == nil {
+			o.Endpoints = make(map[string]Limit)
+		}
+		o.Endpoints[name] = l
+	}
+}
+
+// bulkhead limits the requests to an endpoint
+type bulkhead struct {
+	// handlers has a slot for each request being handled
+	handlers chan struct{}
+	// queue has a slot for each request waiting or being handled
+	queue chan struct{}
+}
+
+func newBulkhead(l Limit) *bulkhead {
+	return &bulkhead{
+		handlers: make(chan struct{}, l.MaxConcurrent),
+		queue:    make(chan struct{}, l.MaxConcurrent+l.MaxQueue),
+	}
+}
+
+// acquire a handler, waiting in the queue if there's room. The release func must be called
+// once the request has been handled.
+func (b *bulkhead) acquire(ctx context.Context, timeout time.Duration) (func(), bool) {
+	select {
+	case b.queue <- struct{}{}:
+	default:
+		return nil, false
+	}
+
+	select {
+	case b.handlers <- struct{}{}:
+		return func() {
+			<-b.handlers
+			<-b.queue
+		}, true
+	default:
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case b.handlers <- struct{}{}:
+		return func() {
+			<-b.handlers
+			<-b.queue
+		}, true
+	case <-t.C:
+	case <-ctx.Done():
+	}
+	<-b.queue
+	return nil, false
+}
+
+// HandlerWrapper limits the number of requests each endpoint handles at once
+func HandlerWrapper(opts ...Option) server.HandlerWrapper {
+	options := Options{QueueTimeout: DefaultQueueTimeout}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	var mtx sync.Mutex
+	bulkheads := make(map[string]*bulkhead)
+
+	get := func(endpoint string) *bulkhead {
+		l, ok := options.Endpoints[endpoint]
+		if !ok {
+			l = options.Limit
+		}
+		if l.MaxConcurrent <= 0 {
+			return nil
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		b, ok := bulkheads[endpoint]
+		if !ok {
+			b = newBulkhead(l)
+			bulkheads[endpoint] = b
+		}
+		return b
+	}
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			b := get(req.Endpoint())
+			if b == nil {
+				return h(ctx, req, rsp)
+			}
+
+			release, ok := b.acquire(ctx, options.QueueTimeout)
+			if !ok {
+				return errors.TooManyRequests(req.Service(), "Endpoint %v is overloaded, try again later", req.Endpoint())
+			}
+			defer release()
+			return h(ctx, req, rsp)
+		}
+	}
+}
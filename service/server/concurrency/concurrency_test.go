@@ -0,0 +1,84 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+}
+
+func (r *testRequest) Service() string  { return "foo" }
+func (r *testRequest) Endpoint() string { return r.endpoint }
+
+func TestHandlerWrapper(t *testing.T) {
+	release := make(chan struct{})
+	h := HandlerWrapper(
+		MaxConcurrent(1),
+		MaxQueue(1),
+		QueueTimeout(time.Millisecond*50),
+		Endpoint("Foo.Fast", Limit{}),
+	)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		if req.Endpoint() == "Foo.Slow" {
+			<-release
+		}
+		return nil
+	})
+
+	call := func(endpoint string) <-chan error {
+		errChan := make(chan error, 1)
+		go func() { errChan <- h(context.TODO(), &testRequest{endpoint: endpoint}, nil) }()
+		return errChan
+	}
+	overloaded := func(err error) bool {
+		verr := errors.Parse(err)
+		return verr != nil && verr.Code == 429
+	}
+
+	// the first request is handled and the second waits in the queue
+	first := call("Foo.Slow")
+	time.Sleep(time.Millisecond * 10)
+	second := call("Foo.Slow")
+	time.Sleep(time.Millisecond * 10)
+
+	// the queue is full
+	if err := <-call("Foo.Slow"); !overloaded(err) {
+		t.Errorf("Expected the request to be rejected, got %v", err)
+	}
+	// endpoints without a limit aren't affected
+	if err := <-call("Foo.Fast"); err != nil {
+		t.Errorf("Unexpected error calling an endpoint without a limit: %v", err)
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := <-second; err != nil {
+		t.Errorf("Expected the queued request to be handled, got %v", err)
+	}
+}
+
+func TestQueueTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := HandlerWrapper(MaxConcurrent(1), MaxQueue(1), QueueTimeout(time.Millisecond*20))(
+		func(ctx context.Context, req server.Request, rsp interface{}) error {
+			<-release
+			return nil
+		})
+
+	go h(context.TODO(), &testRequest{endpoint: "Foo.Slow"}, nil)
+	time.Sleep(time.Millisecond * 10)
+
+	err := h(context.TODO(), &testRequest{endpoint: "Foo.Slow"}, nil)
+	if verr := errors.Parse(err); verr == nil || verr.Code != 429 {
+		t.Errorf("Expected the queued request to time out, got %v", err)
+	}
+}
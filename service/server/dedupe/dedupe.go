@@ -0,0 +1,279 @@
+// Package dedupe provides server wrappers which execute each request or message once, so
+// handlers triggered by redelivered events or by client retries of non-idempotent endpoints
+// don't repeat their side effects. Requests are deduplicated by the key in their
+// IdempotencyKey header, which clients set to retry writes safely, or by the ID in their
+// RequestIDKey header, set using NewContext. Messages are deduplicated by the ID the client
+// publishes them with. Requests and messages without a key or ID are always handled.
+//
+// The responses of the requests handled are kept in the store for the window, or the TTL of
+// the endpoint, so retries get the original response. Requests which fail aren't recorded and
+// can be retried. Keys and IDs are scoped to the issuer, namespace and account which made the
+// request, so requests made without an account are always handled, and an idempotency key
+// can't be reused for a request with a different body. Messages are scoped to their namespace.
+package dedupe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// RequestIDKey is the header requests are deduplicated by
+	RequestIDKey = "Micro-Request-Id"
+	// IdempotencyKey is the header clients set to deduplicate their retries of a request,
+	// which takes precedence over the RequestIDKey
+	IdempotencyKey = "Idempotency-Key"
+	// MessageIDKey is the header messages are deduplicated by, set by the client on publish
+	MessageIDKey = "Micro-Id"
+)
+
+const prefix = "dedupe/"
+
+// NewContext returns a context which requests are made with the ID, so the request is
+// executed once however many times it's retried. For example, use the ID of the event being
+// consumed so the requests it triggers are executed once if the event is redelivered.
+func NewContext(ctx context.Context, id string) context.Context {
+	return metadata.Set(ctx, RequestIDKey, id)
+}
+
+// Options of the handler wrapper
+type Options struct {
+	// Endpoints with their own TTL, keyed by endpoint name, e.g. Foo.Bar. A TTL of zero
+	// disables deduplication of the endpoint.
+	Endpoints map[string]time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Endpoint sets how long the responses of the endpoint are kept, zero to always handle its
+// requests, e.g. if it's idempotent
+func Endpoint(name string, ttl time.Duration) Option {
+	return func(o *Options) {
+		if o.Endpoints == nil {
+			o.Endpoints = make(map[string]time.Duration)
+		}
+		o.Endpoints[name] = ttl
+	}
+}
+
+// ParseEndpoints parses the TTLs of endpoints, each in the format name=ttl, e.g. Foo.Bar=24h
+func ParseEndpoints(values []string) ([]Option, error) {
+	var opts []Option
+	for _, v := range values {
+		for _, e := range strings.Split(v, ",") {
+			if e = strings.TrimSpace(e); len(e) == 0 {
+				continue
+			}
+			parts := strings.SplitN(e, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid endpoint %q, expected name=ttl", e)
+			}
+			ttl, err := time.ParseDuration(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ttl for endpoint %v: %v", parts[0], err)
+			}
+			opts = append(opts, Endpoint(parts[0], ttl))
+		}
+	}
+	return opts, nil
+}
+
+// record of a request which was handled
+type record struct {
+	Response json.RawMessage `json:"response,omitempty"`
+	// Request is the hash of the body of a request made with an idempotency key
+	Request string `json:"request,omitempty"`
+}
+
+type dedupe struct {
+	window time.Duration
+
+	sync.Mutex
+	// inflight are the keys being handled by this server
+	inflight map[string]bool
+}
+
+func newDedupe(window time.Duration) *dedupe {
+	return &dedupe{window: window, inflight: make(map[string]bool)}
+}
+
+// start handling the key, returning the record if it's already been handled. Returns an
+// error if the key is being handled.
+func (d *dedupe) start(key string) (*record, error) {
+	d.Lock()
+	if d.inflight[key] {
+		d.Unlock()
+		return nil, errors.Conflict("dedupe", "Request %v is already being handled", key)
+	}
+	d.inflight[key] = true
+	d.Unlock()
+
+	recs, err := store.Read(key)
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, nil
+	}
+
+	// the key isn't going to be handled
+	d.done(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var r record
+	if err := json.Unmarshal(recs[0].Value, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// finish handling the key, recording it as handled for the TTL if it succeeded
+func (d *dedupe) finish(key string, r *record, ttl time.Duration) {
+	defer d.done(key)
+	if r == nil {
+		return
+	}
+
+	bytes, err := json.Marshal(r)
+	if err != nil {
+		logger.Errorf("Error encoding record of %v: %v", key, err)
+		return
+	}
+	if err := store.Write(&gostore.Record{Key: key, Value: bytes, Expiry: ttl}); err != nil {
+		logger.Errorf("Error writing record of %v: %v", key, err)
+	}
+}
+
+// done removes the key from those in flight
+func (d *dedupe) done(key string) {
+	d.Lock()
+	delete(d.inflight, key)
+	d.Unlock()
+}
+
+// HandlerWrapper deduplicates the requests by their IdempotencyKey or RequestIDKey header
+// within the window, or the TTL of their endpoint. Retries of a request get the response it
+// was handled with.
+func HandlerWrapper(window time.Duration, opts ...Option) server.HandlerWrapper {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+	d := newDedupe(window)
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			ttl, ok := options.Endpoints[req.Endpoint()]
+			if !ok {
+				ttl = window
+			}
+			if ttl <= 0 || req.Stream() {
+				return h(ctx, req, rsp)
+			}
+
+			key, hash := requestKey(ctx, req)
+			if len(key) == 0 {
+				return h(ctx, req, rsp)
+			}
+
+			rec, err := d.start(key)
+			if err != nil {
+				return err
+			}
+			if rec != nil {
+				if rec.Request != hash {
+					return errors.BadRequest(req.Service(), "Idempotency key has already been used for a different request")
+				}
+				logger.Debugf("Request %v to %v has already been handled", key, req.Endpoint())
+				if len(rec.Response) == 0 {
+					return nil
+				}
+				return json.Unmarshal(rec.Response, rsp)
+			}
+
+			if err := h(ctx, req, rsp); err != nil {
+				d.finish(key, nil, ttl)
+				return err
+			}
+
+			bytes, err := json.Marshal(rsp)
+			if err != nil {
+				logger.Errorf("Error encoding response to %v: %v", key, err)
+			}
+			d.finish(key, &record{Response: bytes, Request: hash}, ttl)
+			return nil
+		}
+	}
+}
+
+// requestKey returns the key the request is deduplicated by, blank if it has no idempotency key
+// or ID or wasn't made by an account. Keys are scoped to the issuer, namespace and account of
+// the caller, and idempotency keys are returned with the hash of the request body so a key
+// can't be reused for a different request.
+func requestKey(ctx context.Context, req server.Request) (string, string) {
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok || len(acc.ID) == 0 {
+		return "", ""
+	}
+	base := prefix + strings.Join([]string{req.Service(), req.Endpoint(), acc.Issuer, namespace.FromContext(ctx), acc.ID}, "/") + "/"
+
+	if key, ok := metadata.Get(ctx, IdempotencyKey); ok && len(key) > 0 {
+		var hash string
+		if b, err := json.Marshal(req.Body()); err == nil {
+			sum := sha256.Sum256(b)
+			hash = hex.EncodeToString(sum[:])
+		}
+		return base + "key/" + key, hash
+	}
+
+	if id, ok := metadata.Get(ctx, RequestIDKey); ok && len(id) > 0 {
+		return base + "id/" + id, ""
+	}
+	return "", ""
+}
+
+// SubscriberWrapper deduplicates the messages by their MessageIDKey header within the window
+func SubscriberWrapper(window time.Duration) server.SubscriberWrapper {
+	d := newDedupe(window)
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			id := msg.Header()[MessageIDKey]
+			if len(id) == 0 {
+				return fn(ctx, msg)
+			}
+
+			key := prefix + strings.Join([]string{namespace.FromContext(ctx), msg.Topic(), id}, "/")
+			rec, err := d.start(key)
+			if err != nil {
+				return err
+			}
+			if rec != nil {
+				logger.Debugf("Message %v on %v has already been handled", id, msg.Topic())
+				return nil
+			}
+
+			if err := fn(ctx, msg); err != nil {
+				d.finish(key, nil, d.window)
+				return err
+			}
+			d.finish(key, &record{}, d.window)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package dedupe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	memstore "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+	body     interface{}
+}
+
+func (r *testRequest) Service() string   { return "foo" }
+func (r *testRequest) Stream() bool      { return false }
+func (r *testRequest) Body() interface{} { return r.body }
+
+func (r *testRequest) Endpoint() string {
+	if len(r.endpoint) > 0 {
+		return r.endpoint
+	}
+	return "Foo.Create"
+}
+
+type testMessage struct {
+	server.Message
+	id string
+}
+
+func (m *testMessage) Topic() string             { return "foo" }
+func (m *testMessage) Header() map[string]string { return map[string]string{MessageIDKey: m.id} }
+
+type testResponse struct {
+	ID int
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	var calls int
+	var fail bool
+	h := HandlerWrapper(time.Minute)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		calls++
+		if fail {
+			return errors.InternalServerError("foo", "failed")
+		}
+		rsp.(*testResponse).ID = calls
+		return nil
+	})
+
+	call := func(ctx context.Context) (*testResponse, error) {
+		var rsp testResponse
+		err := h(ctx, &testRequest{}, &rsp)
+		return &rsp, err
+	}
+	alice := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "alice", Issuer: "micro"})
+
+	t.Run("WithoutID", func(t *testing.T) {
+		call(alice)
+		call(alice)
+		if calls != 2 {
+			t.Fatalf("Expected requests without an ID to be handled, got %v calls", calls)
+		}
+	})
+
+	t.Run("WithoutAccount", func(t *testing.T) {
+		calls = 0
+		ctx := NewContext(context.TODO(), "zero")
+		call(ctx)
+		call(ctx)
+		if calls != 2 {
+			t.Fatalf("Expected requests without an account to be handled, got %v calls", calls)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		calls = 0
+		ctx := NewContext(alice, "one")
+		rsp, err := call(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		retry, err := call(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("Expected the request to be handled once, got %v calls", calls)
+		}
+		if retry.ID != rsp.ID {
+			t.Fatalf("Expected the retry to get the original response %v, got %v", rsp.ID, retry.ID)
+		}
+
+		// the same ID from another account or namespace is another request
+		bob := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "bob", Issuer: "micro"})
+		other := namespace.ContextWithNamespace(ctx, "foo")
+		for _, ctx := range []context.Context{NewContext(bob, "one"), other} {
+			if rsp, _ := call(ctx); rsp.ID == retry.ID {
+				t.Fatalf("Expected the request IDs to be scoped to the account and namespace")
+			}
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		calls = 0
+		fail = true
+		ctx := NewContext(alice, "two")
+		if _, err := call(ctx); err == nil {
+			t.Fatalf("Expected an error")
+		}
+		fail = false
+		if _, err := call(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if calls != 2 {
+			t.Fatalf("Expected failed requests to be retried, got %v calls", calls)
+		}
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	var calls int
+	h := HandlerWrapper(time.Minute, Endpoint("Foo.Read", 0))(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		calls++
+		rsp.(*testResponse).ID = calls
+		return nil
+	})
+	call := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		var rsp testResponse
+		err := h(ctx, req, &rsp)
+		return &rsp, err
+	}
+
+	alice := metadata.Set(auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "alice"}), IdempotencyKey, "k1")
+	bob := metadata.Set(auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "bob"}), IdempotencyKey, "k1")
+
+	first, _ := call(alice, &testRequest{body: map[string]int{"amount": 10}})
+	retry, err := call(alice, &testRequest{body: map[string]int{"amount": 10}})
+	if err != nil || retry.ID != first.ID || calls != 1 {
+		t.Fatalf("Expected the retry to be replayed, got %v %v after %v calls", retry.ID, err, calls)
+	}
+
+	_, err = call(alice, &testRequest{body: map[string]int{"amount": 20}})
+	if verr := errors.Parse(err); verr == nil || verr.Code != 400 {
+		t.Errorf("Expected the key to be rejected for a different request, got %v", err)
+	}
+
+	if rsp, _ := call(bob, &testRequest{body: map[string]int{"amount": 10}}); rsp.ID == first.ID {
+		t.Errorf("Expected keys to be scoped to the account")
+	}
+
+	calls = 0
+	call(alice, &testRequest{endpoint: "Foo.Read"})
+	call(alice, &testRequest{endpoint: "Foo.Read"})
+	if calls != 2 {
+		t.Errorf("Expected the requests to an endpoint with no TTL to be handled, got %v calls", calls)
+	}
+}
+
+func TestParseEndpoints(t *testing.T) {
+	opts, err := ParseEndpoints([]string{"Foo.Create=24h,Foo.Read=0s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Endpoints["Foo.Create"] != time.Hour*24 || options.Endpoints["Foo.Read"] != 0 || len(options.Endpoints) != 2 {
+		t.Errorf("Unexpected endpoints %v", options.Endpoints)
+	}
+	if _, err := ParseEndpoints([]string{"Foo.Create"}); err == nil {
+		t.Errorf("Expected an error for an endpoint without a ttl")
+	}
+}
+
+func TestSubscriberWrapper(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+
+	var calls int
+	fn := SubscriberWrapper(time.Minute)(func(ctx context.Context, msg server.Message) error {
+		calls++
+		return nil
+	})
+
+	for _, id := range []string{"one", "one", "two", ""} {
+		if err := fn(context.TODO(), &testMessage{id: id}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("Expected the redelivered message to be handled once, got %v calls", calls)
+	}
+
+	// message IDs are scoped to the namespace
+	if err := fn(namespace.ContextWithNamespace(context.TODO(), "foo"), &testMessage{id: "one"}); err != nil || calls != 4 {
+		t.Fatalf("Expected the message of another namespace to be handled, got %v calls", calls)
+	}
+}
@@ -0,0 +1,163 @@
+// Package drain provides graceful shutdown for the server, so rolling deploys don't cause errors.
+// When the service stops, the server is deregistered first, then keeps serving for the grace
+// period while clients stop routing to it, and waits for the requests in flight to complete
+// before it stops accepting connections.
+package drain
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultGracePeriod is how long the server keeps serving after it's deregistered
+	DefaultGracePeriod = time.Second * 5
+	// DefaultTimeout is how long the requests in flight are waited for after the grace period
+	DefaultTimeout = time.Second * 30
+	// DefaultDrainer drains the default server
+	DefaultDrainer = New(DefaultGracePeriod)
+
+	// pollInterval is how often the requests in flight are checked while waiting for them
+	pollInterval = time.Millisecond * 50
+)
+
+// Drainer drains a server
+type Drainer struct {
+	grace    time.Duration
+	draining int32
+	// inflight is the number of requests being handled
+	inflight int64
+
+	sync.Mutex
+	// registered are the services registered by the server, keyed by name, version and nodes
+	registered map[string]registration
+}
+
+type registration struct {
+	registry registry.Registry
+	service  *registry.Service
+	domain   string
+}
+
+func (r registration) deregister() error {
+	return r.registry.Deregister(r.service, registry.DeregisterDomain(r.domain))
+}
+
+// New returns a drainer with the grace period
+func New(grace time.Duration) *Drainer {
+	return &Drainer{grace: grace, registered: make(map[string]registration)}
+}
+
+// Draining returns true once the drain has started
+func (d *Drainer) Draining() bool {
+	return atomic.LoadInt32(&d.draining) == 1
+}
+
+// HandlerWrapper tracks the requests in flight, so they can be waited for
+func (d *Drainer) HandlerWrapper() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			atomic.AddInt64(&d.inflight, 1)
+			defer atomic.AddInt64(&d.inflight, -1)
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// Registry returns a registry which records the services the server registers, so they can be
+// deregistered when the drain starts, and which ignores the server re-registering while
+// draining
+func (d *Drainer) Registry(r registry.Registry) registry.Registry {
+	return &drainRegistry{Registry: r, drainer: d}
+}
+
+// Drain the server, deregistering it and waiting for the grace period and the requests in flight
+func (d *Drainer) Drain() {
+	if !atomic.CompareAndSwapInt32(&d.draining, 0, 1) {
+		return
+	}
+
+	d.Lock()
+	regs := make([]registration, 0, len(d.registered))
+	for k, r := range d.registered {
+		regs = append(regs, r)
+		delete(d.registered, k)
+	}
+	d.Unlock()
+
+	for _, r := range regs {
+		if err := r.deregister(); err != nil {
+			logger.Errorf("Error deregistering %v: %v", r.service.Name, err)
+		}
+	}
+
+	if d.grace > 0 {
+		logger.Infof("Draining for %v", d.grace)
+		time.Sleep(d.grace)
+	}
+
+	deadline := time.Now().Add(DefaultTimeout)
+	for atomic.LoadInt64(&d.inflight) > 0 {
+		if time.Now().After(deadline) {
+			logger.Warnf("Timed out waiting for %v requests in flight", atomic.LoadInt64(&d.inflight))
+			return
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Drain the default server
+func Drain() {
+	DefaultDrainer.Drain()
+}
+
+type drainRegistry struct {
+	registry.Registry
+	drainer *Drainer
+}
+
+func (r *drainRegistry) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	if r.drainer.Draining() {
+		return nil
+	}
+	if err := r.Registry.Register(s, opts...); err != nil {
+		return err
+	}
+
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	r.drainer.Lock()
+	r.drainer.registered[key(s)] = registration{registry: r.Registry, service: s, domain: options.Domain}
+	r.drainer.Unlock()
+	return nil
+}
+
+func (r *drainRegistry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
+	r.drainer.Lock()
+	_, ok := r.drainer.registered[key(s)]
+	delete(r.drainer.registered, key(s))
+	r.drainer.Unlock()
+
+	// the drain already deregistered the service
+	if !ok && r.drainer.Draining() {
+		return nil
+	}
+	return r.Registry.Deregister(s, opts...)
+}
+
+func key(s *registry.Service) string {
+	ids := make([]string, 0, len(s.Nodes))
+	for _, n := range s.Nodes {
+		ids = append(ids, n.Id)
+	}
+	return s.Name + ":" + s.Version + ":" + strings.Join(ids, ",")
+}
@@ -0,0 +1,70 @@
+package drain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/go-micro/v3/server"
+)
+
+func TestDrain(t *testing.T) {
+	d := New(time.Millisecond * 50)
+	reg := d.Registry(memory.NewRegistry())
+
+	srv := &registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{{Id: "foo-1", Address: "127.0.0.1:1"}}}
+	if err := reg.Register(srv); err != nil {
+		t.Fatalf("Unexpected error registering: %v", err)
+	}
+
+	// a request in flight when the drain starts
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := d.HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		close(started)
+		<-release
+		return nil
+	})
+	go h(context.TODO(), nil, nil)
+	<-started
+
+	drained := make(chan struct{})
+	go func() {
+		d.Drain()
+		close(drained)
+	}()
+
+	time.Sleep(time.Millisecond * 10)
+	if _, err := reg.GetService("foo"); err != registry.ErrNotFound {
+		t.Errorf("Expected the service to be deregistered when the drain starts, got %v", err)
+	}
+
+	// the server re-registering while draining is ignored
+	if err := reg.Register(srv); err != nil {
+		t.Fatalf("Unexpected error registering: %v", err)
+	}
+	if _, err := reg.GetService("foo"); err != registry.ErrNotFound {
+		t.Errorf("Expected the service not to be registered again, got %v", err)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	select {
+	case <-drained:
+		t.Fatalf("Expected the drain to wait for the request in flight")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the drain to complete once the request finished")
+	}
+
+	// the server deregistering on stop is a no-op
+	if err := reg.Deregister(srv); err != nil {
+		t.Errorf("Unexpected error deregistering: %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+// Package flow provides a server wrapper which controls the flow of messages handlers send on
+// streams, so a handler streaming logs or events to a slow client doesn't buffer unbounded data
+// in memory or stall on each send. Messages are sent from a window of a fixed size, and when
+// the window is full the policy decides whether Send blocks, drops the message or errors.
+//
+// Messages are sent after Send returns, so they must not be modified once they're sent.
+package flow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// DefaultWindow is the number of messages waiting to be sent on each stream
+	DefaultWindow = 64
+	// DefaultTimeout is how long Send blocks for when the window is full, zero blocks until the
+	// client disconnects
+	DefaultTimeout = time.Second * 30
+
+	// ErrWindowFull is returned by Send when the window is full and the client is too slow to
+	// receive the messages
+	ErrWindowFull = errors.TooManyRequests("flow", "Send window is full, the client is too slow")
+)
+
+// Policy decides what Send does when the window is full
+type Policy int
+
+const (
+	// Block waits for room in the window, up to the timeout
+	Block Policy = iota
+	// Drop discards the message
+	Drop
+	// Error returns ErrWindowFull
+	Error
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case Drop:
+		return "drop"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePolicy parses the name of a policy, e.g. block
+func ParsePolicy(s string) (Policy, error) {
+	for _, p := range []Policy{Block, Drop, Error} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return Block, fmt.Errorf("Unknown flow policy %v, expected block, drop or error", s)
+}
+
+// Flow of the messages sent on a stream
+type Flow struct {
+	// Window is the number of messages waiting to be sent, zero sends each message as it's sent
+	Window int
+	// Policy when the window is full
+	Policy Policy
+	// Timeout is how long Send blocks for when the policy is Block
+	Timeout time.Duration
+}
+
+// Options of the wrapper
+type Options struct {
+	// Flow of the endpoints without their own
+	Flow Flow
+	// Endpoints with their own flow, keyed by endpoint name, e.g. Foo.Bar
+	Endpoints map[string]Flow
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Window sets the number of messages waiting to be sent on each stream
+func Window(n int) Option {
+	return func(o *Options) {
+		o.Flow.Window = n
+	}
+}
+
+// OnFull sets the policy when the window is full
+func OnFull(p Policy) Option {
+	return func(o *Options) {
+		o.Flow.Policy = p
+	}
+}
+
+// Timeout sets how long Send blocks for when the window is full
+func Timeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Flow.Timeout = d
+	}
+}
+
+// Endpoint sets the flow of an endpoint, overriding the default flow
+func Endpoint(name string, f Flow) Option {
+	return func(o *Options) {
+		if o.Endpoints == nil {
+			o.Endpoints = make(map[string]Flow)
+		}
+		o.Endpoints[name] = f
+	}
+}
+
+// Stream sends the messages in its window to the client
+type Stream struct {
+	server.Stream
+	flow Flow
+
+	// mtx is held by Send so the window isn't closed whilst a message is added
+	mtx    sync.RWMutex
+	closed bool
+
+	window  chan interface{}
+	done    chan struct{}
+	dropped int64
+
+	errMtx sync.Mutex
+	err    error
+}
+
+func newStream(s server.Stream, f Flow) *Stream {
+	st := &Stream{
+		Stream: s,
+		flow:   f,
+		window: make(chan interface{}, f.Window),
+		done:   make(chan struct{}),
+	}
+	go st.run()
+	return st
+}
+
+// run sends the messages in the window until it's closed. Once a send fails the remaining
+// messages are discarded and the error is returned by the next Send.
+func (s *Stream) run() {
+	defer close(s.done)
+
+	for msg := range s.window {
+		if s.sendErr() != nil {
+			continue
+		}
+		if err := s.Stream.Send(msg); err != nil {
+			s.errMtx.Lock()
+			s.err = err
+			s.errMtx.Unlock()
+		}
+	}
+}
+
+func (s *Stream) sendErr() error {
+	s.errMtx.Lock()
+	defer s.errMtx.Unlock()
+	return s.err
+}
+
+// Send adds the message to the window, applying the policy if it's full
+func (s *Stream) Send(msg interface{}) error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.closed {
+		return errors.InternalServerError("flow", "Stream is closed")
+	}
+	if err := s.sendErr(); err != nil {
+		return err
+	}
+
+	select {
+	case s.window <- msg:
+		return nil
+	default:
+	}
+
+	switch s.flow.Policy {
+	case Drop:
+		atomic.AddInt64(&s.dropped, 1)
+		return nil
+	case Error:
+		return ErrWindowFull
+	}
+
+	var timeout <-chan time.Time
+	if s.flow.Timeout > 0 {
+		t := time.NewTimer(s.flow.Timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	select {
+	case s.window <- msg:
+		return nil
+	case <-timeout:
+		return ErrWindowFull
+	case <-s.Stream.Context().Done():
+		return s.Stream.Context().Err()
+	}
+}
+
+// Pending returns the number of messages in the window waiting to be sent
+func (s *Stream) Pending() int {
+	return len(s.window)
+}
+
+// Dropped returns the number of messages dropped because the window was full
+func (s *Stream) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// flush sends the messages left in the window and stops sending, returning the error of the
+// first send which failed
+func (s *Stream) flush() error {
+	s.mtx.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.window)
+	}
+	s.mtx.Unlock()
+
+	<-s.done
+	return s.sendErr()
+}
+
+type streamKey struct{}
+
+// NewContext returns a context with the stream
+func NewContext(ctx context.Context, s *Stream) context.Context {
+	return context.WithValue(ctx, streamKey{}, s)
+}
+
+// FromContext returns the stream the handler is sending on, so it can check how many messages
+// are pending or were dropped and adapt what it sends
+func FromContext(ctx context.Context) (*Stream, bool) {
+	s, ok := ctx.Value(streamKey{}).(*Stream)
+	return s, ok
+}
+
+// HandlerWrapper controls the flow of the messages handlers send on streams
+func HandlerWrapper(opts ...Option) server.HandlerWrapper {
+	options := Options{Flow: Flow{Window: DefaultWindow, Timeout: DefaultTimeout}}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			ss, ok := rsp.(server.Stream)
+			if !req.Stream() || !ok {
+				return h(ctx, req, rsp)
+			}
+
+			f, ok := options.Endpoints[req.Endpoint()]
+			if !ok {
+				f = options.Flow
+			}
+			if f.Window <= 0 {
+				return h(ctx, req, rsp)
+			}
+
+			// the messages left in the window are sent before the stream is closed
+			s := newStream(ss, f)
+			err := h(NewContext(ctx, s), req, s)
+			if ferr := s.flush(); err == nil && ferr != nil {
+				err = ferr
+			}
+			return err
+		}
+	}
+}
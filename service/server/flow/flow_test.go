@@ -0,0 +1,91 @@
+package flow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+)
+
+type testRequest struct {
+	server.Request
+}
+
+func (r *testRequest) Stream() bool     { return true }
+func (r *testRequest) Endpoint() string { return "Foo.Logs" }
+
+// testStream is a client which receives a message each time release is sent to
+type testStream struct {
+	server.Stream
+	release chan struct{}
+
+	sync.Mutex
+	sent []interface{}
+}
+
+func (s *testStream) Context() context.Context { return context.TODO() }
+
+func (s *testStream) Send(msg interface{}) error {
+	<-s.release
+	s.Lock()
+	s.sent = append(s.sent, msg)
+	s.Unlock()
+	return nil
+}
+
+func TestPolicies(t *testing.T) {
+	tt := []struct {
+		Policy Policy
+		// Sent is the number of messages received by the client
+		Sent int
+		// Full is true if the fourth send should fail
+		Full bool
+	}{
+		{Drop, 3, false},
+		{Error, 3, true},
+		{Block, 4, true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Policy.String(), func(t *testing.T) {
+			client := &testStream{release: make(chan struct{})}
+			h := HandlerWrapper(Window(2), OnFull(tc.Policy), Timeout(time.Millisecond*20))(
+				func(ctx context.Context, req server.Request, rsp interface{}) error {
+					stream := rsp.(server.Stream)
+
+					// the first message is being sent and the next two fill the window
+					for i := 0; i < 3; i++ {
+						if err := stream.Send(i); err != nil {
+							t.Errorf("Unexpected error sending %v: %v", i, err)
+						}
+						time.Sleep(time.Millisecond * 5)
+					}
+					err := stream.Send(3)
+					if (err == ErrWindowFull) != tc.Full {
+						t.Errorf("Expected the window to be full %v, got %v", tc.Full, err)
+					}
+
+					s, _ := FromContext(ctx)
+					if tc.Policy == Drop && s.Dropped() != 1 {
+						t.Errorf("Expected 1 message to be dropped, got %v", s.Dropped())
+					}
+
+					// the client catches up so the remaining messages are sent on return
+					close(client.release)
+					if tc.Policy == Block {
+						return stream.Send(3)
+					}
+					return nil
+				})
+
+			if err := h(context.TODO(), &testRequest{}, client); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(client.sent) != tc.Sent {
+				t.Errorf("Expected %v messages to be sent, got %v", tc.Sent, client.sent)
+			}
+		})
+	}
+}
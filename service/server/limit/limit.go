@@ -0,0 +1,374 @@
+// Package limit provides a server wrapper which bounds the size of the messages endpoints
+// receive and send and how long they wait to read and write them, so slow clients can't hold
+// handlers open indefinitely and oversized payloads are rejected before they're handled.
+//
+// The limits of the server apply to every endpoint and can be overridden for each endpoint in
+// the options or the config at DefaultPath, which is read on each request so operators can
+// change the limits without restarting, for example:
+//
+//	{
+//		"*": {"read_timeout": "30s"},
+//		"Files.Upload": {"max_recv_size": 67108864, "read_timeout": "5m"}
+//	}
+//
+// Options bound the messages the grpc transport accepts at all and how long connections have
+// to complete their handshake, which protects the server before requests reach the wrapper.
+package limit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	sgrpc "github.com/micro/go-micro/v3/server/grpc"
+	climit "github.com/micro/micro/v3/service/client/limit"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"google.golang.org/grpc"
+)
+
+var (
+	// DefaultPath is the path of the limits in the config, keyed by endpoint name, "*" applying
+	// to every endpoint
+	DefaultPath = []string{"server", "limits"}
+)
+
+// Limits of an endpoint, zero values are unlimited
+type Limits struct {
+	// MaxRecvSize is the size in bytes of the largest request or stream message received
+	MaxRecvSize int
+	// MaxSendSize is the size in bytes of the largest response or stream message sent
+	MaxSendSize int
+	// ReadTimeout is how long a stream waits for the client to send its next message
+	ReadTimeout time.Duration
+	// WriteTimeout is how long until the context of the handler of a request is cancelled, and
+	// how long a stream waits for the client to accept each message sent
+	WriteTimeout time.Duration
+}
+
+// merge the limits which are set over the limits
+func (l Limits) merge(o Limits) Limits {
+	if o.MaxRecvSize != 0 {
+		l.MaxRecvSize = o.MaxRecvSize
+	}
+	if o.MaxSendSize != 0 {
+		l.MaxSendSize = o.MaxSendSize
+	}
+	if o.ReadTimeout != 0 {
+		l.ReadTimeout = o.ReadTimeout
+	}
+	if o.WriteTimeout != 0 {
+		l.WriteTimeout = o.WriteTimeout
+	}
+	return l
+}
+
+// Source returns the encoded limits, blank if there are none
+type Source func() []byte
+
+// ConfigSource reads the limits from the config at DefaultPath
+func ConfigSource() []byte {
+	if config.DefaultConfig == nil {
+		return nil
+	}
+	return config.Get(DefaultPath...).Bytes()
+}
+
+// ParseLimits parses the limits of endpoints from their JSON encoding, where timeouts are
+// strings such as "10s"
+func ParseLimits(b []byte) (map[string]Limits, error) {
+	var raw map[string]struct {
+		MaxRecvSize  int    `json:"max_recv_size"`
+		MaxSendSize  int    `json:"max_send_size"`
+		ReadTimeout  string `json:"read_timeout"`
+		WriteTimeout string `json:"write_timeout"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]Limits, len(raw))
+	for endpoint, r := range raw {
+		if r.MaxRecvSize < 0 || r.MaxSendSize < 0 {
+			return nil, fmt.Errorf("invalid limits for %v: sizes must be positive", endpoint)
+		}
+		l := Limits{MaxRecvSize: r.MaxRecvSize, MaxSendSize: r.MaxSendSize}
+		for _, t := range []struct {
+			name string
+			val  string
+			d    *time.Duration
+		}{
+			{"read timeout", r.ReadTimeout, &l.ReadTimeout},
+			{"write timeout", r.WriteTimeout, &l.WriteTimeout},
+		} {
+			if len(t.val) == 0 {
+				continue
+			}
+			d, err := time.ParseDuration(t.val)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("invalid %v for %v: %v", t.name, endpoint, t.val)
+			}
+			*t.d = d
+		}
+		limits[endpoint] = l
+	}
+	return limits, nil
+}
+
+// Options of the wrapper
+type Options struct {
+	// Limits of the endpoints without their own
+	Limits Limits
+	// Endpoints with their own limits, keyed by endpoint name, e.g. Foo.Bar
+	Endpoints map[string]Limits
+	// Source of the limits set in the config, which take precedence over the options
+	Source Source
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// MaxRecvSize sets the size in bytes of the largest message received
+func MaxRecvSize(n int) Option {
+	return func(o *Options) {
+		o.Limits.MaxRecvSize = n
+	}
+}
+
+// MaxSendSize sets the size in bytes of the largest message sent
+func MaxSendSize(n int) Option {
+	return func(o *Options) {
+		o.Limits.MaxSendSize = n
+	}
+}
+
+// ReadTimeout sets how long streams wait for the client to send a message
+func ReadTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Limits.ReadTimeout = d
+	}
+}
+
+// WriteTimeout sets how long until the context of handlers is cancelled and how long streams
+// wait for the client to accept a message
+func WriteTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Limits.WriteTimeout = d
+	}
+}
+
+// Endpoint sets the limits of an endpoint, overriding the limits of the server which are set
+func Endpoint(name string, l Limits) Option {
+	return func(o *Options) {
+		if o.Endpoints == nil {
+			o.Endpoints = make(map[string]Limits)
+		}
+		o.Endpoints[name] = l
+	}
+}
+
+// WithSource sets the source of the limits set in the config
+func WithSource(s Source) Option {
+	return func(o *Options) {
+		o.Source = s
+	}
+}
+
+// ServerOptions returns the options which bound the size of the messages the grpc transport
+// accepts and how long connections have to complete their handshake. Zero values leave the
+// server's defaults in place.
+func ServerOptions(maxMsgSize int, handshakeTimeout time.Duration) []server.Option {
+	var opts []server.Option
+	if maxMsgSize > 0 {
+		opts = append(opts, sgrpc.MaxMsgSize(maxMsgSize))
+	}
+	if handshakeTimeout > 0 {
+		opts = append(opts, sgrpc.Options(grpc.ConnectionTimeout(handshakeTimeout)))
+	}
+	return opts
+}
+
+type limiter struct {
+	opts Options
+
+	sync.RWMutex
+	// raw is the encoding the limits were last parsed from
+	raw    []byte
+	config map[string]Limits
+}
+
+// limits of the endpoint: the server's, those in the config for every endpoint, then the
+// endpoint's in the options and in the config
+func (l *limiter) limits(endpoint string) Limits {
+	cfg := l.read()
+	lim := l.opts.Limits.merge(cfg["*"])
+	if el, ok := l.opts.Endpoints[endpoint]; ok {
+		lim = lim.merge(el)
+	}
+	return lim.merge(cfg[endpoint])
+}
+
+// read the limits in the source, parsing them when they change. Invalid limits are logged and
+// the last valid limits kept.
+func (l *limiter) read() map[string]Limits {
+	if l.opts.Source == nil {
+		return nil
+	}
+	raw := l.opts.Source()
+
+	l.RLock()
+	same := bytes.Equal(raw, l.raw)
+	cfg := l.config
+	l.RUnlock()
+	if same {
+		return cfg
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.raw = raw
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		l.config = nil
+		return nil
+	}
+	parsed, err := ParseLimits(raw)
+	if err != nil {
+		logger.Errorf("Error parsing the server limits in the config: %v", err)
+		return l.config
+	}
+	l.config = parsed
+	return parsed
+}
+
+// HandlerWrapper enforces the limits of the endpoints. Requests and messages larger than the
+// limits are rejected with a 413 error and streams which take longer than the timeouts fail
+// with a 408 error. The context of handlers which take longer than the write timeout is
+// cancelled.
+func HandlerWrapper(opts ...Option) server.HandlerWrapper {
+	l := &limiter{}
+	for _, o := range opts {
+		o(&l.opts)
+	}
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			lim := l.limits(req.Endpoint())
+			if lim == (Limits{}) {
+				return h(ctx, req, rsp)
+			}
+
+			if ss, ok := rsp.(server.Stream); ok && req.Stream() {
+				// the context of the handler is cancelled once the stream times out
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				return h(ctx, req, &stream{Stream: ss, req: req, limits: lim, ctx: ctx, cancel: cancel})
+			}
+
+			if err := checkSize(req, "request", req.Body(), lim.MaxRecvSize); err != nil {
+				return err
+			}
+
+			// the handler's context is cancelled once it's taken longer than the write timeout,
+			// what the handler then returns is up to it
+			if lim.WriteTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, lim.WriteTimeout)
+				defer cancel()
+			}
+			if err := h(ctx, req, rsp); err != nil {
+				return err
+			}
+			return checkSize(req, "response", rsp, lim.MaxSendSize)
+		}
+	}
+}
+
+// checkSize returns an error if the message is larger than the limit
+func checkSize(req server.Request, kind string, msg interface{}, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if n := climit.Size(msg); n > limit {
+		return errors.RequestEntityTooLarge(req.Service(), "%v:%v %v of %v bytes exceeds the limit of %v bytes", req.Service(), req.Endpoint(), kind, n, limit)
+	}
+	return nil
+}
+
+// stream which enforces the limits on each message. The transport can't interrupt a read or
+// write once it's started, so when one times out the context of the stream is cancelled, the
+// stream fails any further reads and writes and the handler returns the error, tearing the
+// stream down with it.
+type stream struct {
+	server.Stream
+	req    server.Request
+	limits Limits
+
+	// ctx of the stream, cancelled when it times out
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sync.Mutex
+	// err the stream timed out with
+	err error
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *stream) Send(msg interface{}) error {
+	if err := checkSize(s.req, "message", msg, s.limits.MaxSendSize); err != nil {
+		return err
+	}
+	return s.timeout("send", s.limits.WriteTimeout, func() error { return s.Stream.Send(msg) })
+}
+
+func (s *stream) Recv(msg interface{}) error {
+	if err := s.timeout("receive", s.limits.ReadTimeout, func() error { return s.Stream.Recv(msg) }); err != nil {
+		return err
+	}
+	return checkSize(s.req, "message", msg, s.limits.MaxRecvSize)
+}
+
+// timeout runs the func with a deadline derived from the context of the stream, returning a
+// timeout error if the deadline passes before it returns
+func (s *stream) timeout(op string, d time.Duration, fn func() error) error {
+	s.Lock()
+	err := s.err
+	s.Unlock()
+	if err != nil {
+		return err
+	}
+	if d <= 0 {
+		return fn()
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, d)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- fn() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	// the stream was cancelled rather than timing out
+	if s.ctx.Err() != nil {
+		return s.ctx.Err()
+	}
+
+	err = errors.Timeout(s.req.Service(), "%v:%v stream timed out after %v waiting to %v a message", s.req.Service(), s.req.Endpoint(), d, op)
+	s.Lock()
+	s.err = err
+	s.Unlock()
+	s.cancel()
+	return err
+}
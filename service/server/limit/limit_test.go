@@ -0,0 +1,120 @@
+package limit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+	stream   bool
+	body     interface{}
+}
+
+func (r *testRequest) Service() string   { return "foo" }
+func (r *testRequest) Endpoint() string  { return r.endpoint }
+func (r *testRequest) Stream() bool      { return r.stream }
+func (r *testRequest) Body() interface{} { return r.body }
+
+// testStream is a client which never sends a message
+type testStream struct {
+	server.Stream
+}
+
+func (s *testStream) Recv(msg interface{}) error {
+	select {}
+}
+
+func (s *testStream) Send(msg interface{}) error {
+	return nil
+}
+
+func code(err error) int32 {
+	if verr := errors.Parse(err); verr != nil {
+		return verr.Code
+	}
+	return 0
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits([]byte(`{"*": {"read_timeout": "30s"}, "Foo.Bar": {"max_recv_size": 1024}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits["*"].ReadTimeout != time.Second*30 || limits["Foo.Bar"].MaxRecvSize != 1024 {
+		t.Errorf("Unexpected limits %v", limits)
+	}
+
+	for _, s := range []string{`{"*": {"read_timeout": "soon"}}`, `{"*": {"max_send_size": -1}}`} {
+		if _, err := ParseLimits([]byte(s)); err == nil {
+			t.Errorf("Expected %v to be invalid", s)
+		}
+	}
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	cfg := []byte(`{"Foo.Slow": {"write_timeout": "10ms"}, "Foo.Late": {"write_timeout": "10ms"}}`)
+	wrapper := HandlerWrapper(
+		MaxRecvSize(8),
+		ReadTimeout(time.Millisecond*10),
+		Endpoint("Foo.Upload", Limits{MaxRecvSize: 64}),
+		WithSource(func() []byte { return cfg }),
+	)
+	echo := wrapper(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		if s, ok := rsp.(server.Stream); ok {
+			var msg []byte
+			err := s.Recv(&msg)
+			// the stream is cancelled once it times out
+			if s.Context().Err() == nil || s.Recv(&msg) != err {
+				t.Errorf("Expected the stream to fail once it timed out")
+			}
+			return err
+		}
+		switch req.Endpoint() {
+		case "Foo.Slow":
+			<-ctx.Done()
+			return errors.Timeout("foo", "timed out")
+		case "Foo.Late":
+			// handlers decide what to return once their context is cancelled
+			<-ctx.Done()
+			return nil
+		}
+		return nil
+	})
+
+	tt := []struct {
+		Name string
+		Req  *testRequest
+		Code int32
+	}{
+		{Name: "WithinLimit", Req: &testRequest{endpoint: "Foo.Bar", body: []byte("small")}},
+		{Name: "TooLarge", Req: &testRequest{endpoint: "Foo.Bar", body: []byte("far too large")}, Code: 413},
+		{Name: "EndpointLimit", Req: &testRequest{endpoint: "Foo.Upload", body: []byte("far too large")}},
+		{Name: "WriteTimeout", Req: &testRequest{endpoint: "Foo.Slow", body: []byte{}}, Code: 408},
+		{Name: "WriteTimeoutResult", Req: &testRequest{endpoint: "Foo.Late", body: []byte{}}},
+		{Name: "ReadTimeout", Req: &testRequest{endpoint: "Foo.Stream", stream: true}, Code: 408},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			var rsp interface{} = &[]byte{}
+			if tc.Req.stream {
+				rsp = &testStream{}
+			}
+			if err := echo(context.TODO(), tc.Req, rsp); code(err) != tc.Code {
+				t.Errorf("Expected code %v, got %v", tc.Code, err)
+			}
+		})
+	}
+
+	// changes to the config apply to the next request
+	cfg = []byte(`{"*": {"max_recv_size": 1024}}`)
+	req := &testRequest{endpoint: "Foo.Bar", body: []byte("far too large")}
+	if err := echo(context.TODO(), req, &[]byte{}); err != nil {
+		t.Errorf("Expected the config to raise the limit, got %v", err)
+	}
+}
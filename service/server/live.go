@@ -0,0 +1,648 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/micro/go-micro/v3/broker"
+	"github.com/micro/go-micro/v3/codec"
+	"github.com/micro/go-micro/v3/codec/json"
+	"github.com/micro/go-micro/v3/codec/proto"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/go-micro/v3/util/addr"
+	mnet "github.com/micro/go-micro/v3/util/net"
+	merrors "github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/server/subscriber"
+)
+
+// liveServer allows handlers and subscribers to be added and removed whilst the server is
+// running, so services can load and unload modules without restarting. The registry
+// advertisement is updated straight away rather than on the next register interval.
+//
+// Requests are dispatched from the handlers in the server's own table, so a removed handler can
+// be added again even though the underlying server can't forget it. Subscribers added whilst the
+// server is running are subscribed to the broker by the live server, since the underlying server
+// only subscribes when it first registers. Messages published to a topic whose subscribers were
+// subscribed by the underlying server are acknowledged and discarded once they're removed.
+type liveServer struct {
+	server.Server
+
+	sync.RWMutex
+	started bool
+	wrapped bool
+	// handlers which requests are dispatched to, by name
+	handlers map[string]*liveHandler
+	// handled is the names of the handlers registered with the underlying server
+	handled map[string]bool
+	// topics and their subscribers
+	topics map[string][]*liveSubscriber
+	// muted topics' messages are discarded by the subscriptions of the underlying server
+	muted map[string]bool
+	// subscribers controls the messages of the subscribers registered with options
+	subscribers *subscriber.Controller
+
+	// reloadMtx serialises changes made to the server with its registration
+	reloadMtx sync.Mutex
+}
+
+// liveHandler is a handler in the dispatch table
+type liveHandler struct {
+	server.Handler
+	// direct is true if the handler is the one registered with the underlying server, which
+	// calls it itself
+	direct bool
+}
+
+// liveSubscriber is a subscriber of a topic
+type liveSubscriber struct {
+	server.Subscriber
+	// direct is true if the subscriber was subscribed by the underlying server
+	direct bool
+	// handlers of the messages, for subscribers subscribed by the live server
+	handlers []*subscriberHandler
+	// sub is the broker subscription, for subscribers subscribed by the live server
+	sub broker.Subscriber
+}
+
+func newLiveServer(s server.Server) *liveServer {
+	return &liveServer{
+		Server:      s,
+		handlers:    make(map[string]*liveHandler),
+		handled:     make(map[string]bool),
+		topics:      make(map[string][]*liveSubscriber),
+		muted:       make(map[string]bool),
+		subscribers: subscriber.NewController(),
+	}
+}
+
+// Handle registers the handler, advertising its endpoints straight away if the server is running
+func (s *liveServer) Handle(h server.Handler) error {
+	s.reloadMtx.Lock()
+	defer s.reloadMtx.Unlock()
+
+	s.RLock()
+	_, exists := s.handlers[h.Name()]
+	handled := s.handled[h.Name()]
+	started := s.started
+	s.RUnlock()
+	if exists {
+		return fmt.Errorf("handler %v is already registered", h.Name())
+	}
+
+	// the underlying server can only register a handler once, a handler added again after
+	// being removed is called by the handler wrapper instead
+	if !handled {
+		if err := s.Server.Handle(h); err != nil {
+			return err
+		}
+	}
+
+	s.Lock()
+	s.handlers[h.Name()] = &liveHandler{Handler: h, direct: !handled}
+	s.handled[h.Name()] = true
+	s.Unlock()
+
+	if !started {
+		return nil
+	}
+	return s.register()
+}
+
+// Unhandle removes the handler with the name, rejecting its requests and removing its endpoints
+// from the advertisement
+func (s *liveServer) Unhandle(name string) error {
+	s.reloadMtx.Lock()
+	defer s.reloadMtx.Unlock()
+
+	s.Lock()
+	if _, ok := s.handlers[name]; !ok {
+		s.Unlock()
+		return fmt.Errorf("handler %v is not registered", name)
+	}
+	delete(s.handlers, name)
+	started := s.started
+	s.Unlock()
+
+	if !started {
+		return nil
+	}
+	return s.register()
+}
+
+// Subscribe registers the subscriber, subscribing to its topic straight away if the server is
+// running
+func (s *liveServer) Subscribe(sb server.Subscriber) error {
+	s.reloadMtx.Lock()
+	defer s.reloadMtx.Unlock()
+
+	s.RLock()
+	muted := s.muted[sb.Topic()]
+	started := s.started
+	s.RUnlock()
+
+	ls := &liveSubscriber{Subscriber: sb}
+	if !started && !muted {
+		// subscribed by the underlying server when it first registers
+		if err := s.Server.Subscribe(sb); err != nil {
+			return err
+		}
+		ls.direct = true
+	} else {
+		handlers, err := newSubscriberHandlers(sb)
+		if err != nil {
+			return err
+		}
+		ls.handlers = handlers
+	}
+
+	if started {
+		if err := s.subscribe(ls); err != nil {
+			return err
+		}
+	}
+
+	s.subscribers.Register(sb)
+
+	s.Lock()
+	s.topics[sb.Topic()] = append(s.topics[sb.Topic()], ls)
+	s.Unlock()
+
+	if !started {
+		return nil
+	}
+	return s.register()
+}
+
+// Unsubscribe stops handling the messages published to the topic and removes its subscribers from
+// the advertisement
+func (s *liveServer) Unsubscribe(topic string) error {
+	s.reloadMtx.Lock()
+	defer s.reloadMtx.Unlock()
+
+	s.Lock()
+	subs, ok := s.topics[topic]
+	if !ok {
+		s.Unlock()
+		return fmt.Errorf("topic %v is not subscribed to", topic)
+	}
+	delete(s.topics, topic)
+	for _, sb := range subs {
+		if sb.direct {
+			s.muted[topic] = true
+		}
+	}
+	started := s.started
+	s.Unlock()
+
+	if err := unsubscribe(subs); err != nil {
+		return err
+	}
+	if !started {
+		return nil
+	}
+	return s.register()
+}
+
+// Start the server, wrapping its registry and handlers so requests are dispatched from the
+// handlers and subscribers of the live server
+func (s *liveServer) Start() error {
+	s.reloadMtx.Lock()
+	defer s.reloadMtx.Unlock()
+
+	s.Lock()
+	if !s.wrapped {
+		s.wrapped = true
+		opts := []server.Option{
+			server.WrapHandler(s.handlerWrapper),
+			server.WrapSubscriber(s.subscriberWrapper),
+			server.WrapSubscriber(s.subscribers.Wrapper),
+		}
+		if r := s.Server.Options().Registry; r != nil {
+			opts = append(opts, server.Registry(&liveRegistry{Registry: r, server: s}))
+		}
+		s.Server.Init(opts...)
+	}
+	s.Unlock()
+
+	if err := s.Server.Start(); err != nil {
+		return err
+	}
+
+	// subscribe the subscribers which the underlying server doesn't
+	s.RLock()
+	var subs []*liveSubscriber
+	for _, ss := range s.topics {
+		for _, sb := range ss {
+			if !sb.direct {
+				subs = append(subs, sb)
+			}
+		}
+	}
+	s.RUnlock()
+	for _, sb := range subs {
+		if err := s.subscribe(sb); err != nil {
+			return err
+		}
+	}
+
+	s.Lock()
+	s.started = true
+	s.Unlock()
+	return nil
+}
+
+// Stop the server
+func (s *liveServer) Stop() error {
+	s.reloadMtx.Lock()
+	s.Lock()
+	s.started = false
+	var subs []*liveSubscriber
+	for _, ss := range s.topics {
+		subs = append(subs, ss...)
+	}
+	s.Unlock()
+	err := unsubscribe(subs)
+	s.reloadMtx.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return s.Server.Stop()
+}
+
+// register advertises the server with its current handlers and subscribers straight away, rather
+// than waiting for the underlying server to renew its registration
+func (s *liveServer) register() error {
+	opts := s.Server.Options()
+	if opts.Registry == nil || opts.Registry.String() == "noop" {
+		return nil
+	}
+
+	node, err := s.node(opts)
+	if err != nil {
+		return err
+	}
+	svc := &registry.Service{
+		Name:      opts.Name,
+		Version:   opts.Version,
+		Nodes:     []*registry.Node{node},
+		Endpoints: s.endpoints(),
+	}
+
+	return opts.Registry.Register(svc,
+		registry.RegisterTTL(opts.RegisterTTL),
+		registry.RegisterDomain(opts.Namespace),
+	)
+}
+
+// node returns the node the server is advertised as, the same as the one the underlying server
+// registers
+func (s *liveServer) node(opts server.Options) (*registry.Node, error) {
+	advt := opts.Address
+	if len(opts.Advertise) > 0 {
+		advt = opts.Advertise
+	}
+
+	host, port := advt, ""
+	if strings.Count(advt, ":") > 0 {
+		var err error
+		if host, port, err = net.SplitHostPort(advt); err != nil {
+			return nil, err
+		}
+	}
+	ip, err := addr.Extract(host)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.Copy(opts.Metadata)
+	md["broker"] = opts.Broker.String()
+	md["registry"] = opts.Registry.String()
+	md["server"] = s.Server.String()
+	md["transport"] = s.Server.String()
+	md["protocol"] = s.Server.String()
+
+	return &registry.Node{
+		Id:       opts.Name + "-" + opts.Id,
+		Address:  mnet.HostPort(ip, port),
+		Metadata: md,
+	}, nil
+}
+
+// endpoints returns the endpoints of the handlers and subscribers being served
+func (s *liveServer) endpoints() []*registry.Endpoint {
+	s.RLock()
+	defer s.RUnlock()
+
+	names := make([]string, 0, len(s.handlers))
+	for name, h := range s.handlers {
+		if !h.Options().Internal {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	topics := make([]string, 0, len(s.topics))
+	for topic := range s.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	var eps []*registry.Endpoint
+	for _, name := range names {
+		eps = append(eps, s.handlers[name].Endpoints()...)
+	}
+	for _, topic := range topics {
+		for _, sb := range s.topics[topic] {
+			if !sb.Options().Internal {
+				eps = append(eps, sb.Endpoints()...)
+			}
+		}
+	}
+	return eps
+}
+
+func (s *liveServer) handlerWrapper(fn server.HandlerFunc) server.HandlerFunc {
+	return func(ctx context.Context, req server.Request, rsp interface{}) error {
+		s.RLock()
+		h, ok := s.handlers[handlerName(req.Endpoint())]
+		s.RUnlock()
+
+		if !ok {
+			return merrors.NotFound(req.Service(), "Endpoint %v is not registered", req.Endpoint())
+		}
+		if h.direct {
+			return fn(ctx, req, rsp)
+		}
+		return call(ctx, h.Handler.Handler(), req, rsp)
+	}
+}
+
+// call the method of the handler serving the request, for handlers added again after being
+// removed which the underlying server doesn't know about
+func call(ctx context.Context, h interface{}, req server.Request, rsp interface{}) error {
+	parts := strings.SplitN(req.Endpoint(), ".", 2)
+	method := reflect.ValueOf(h).MethodByName(parts[len(parts)-1])
+
+	args := []reflect.Value{reflect.ValueOf(ctx)}
+	if !req.Stream() {
+		args = append(args, reflect.ValueOf(req.Body()))
+	}
+	args = append(args, reflect.ValueOf(rsp))
+
+	// the handler added again may not be of the same type as the one the underlying server
+	// decodes the requests for
+	if !method.IsValid() || method.Type().NumIn() != len(args) || method.Type().NumOut() != 1 {
+		return merrors.NotFound(req.Service(), "Endpoint %v is not registered", req.Endpoint())
+	}
+	for i, arg := range args {
+		if !arg.IsValid() || !arg.Type().AssignableTo(method.Type().In(i)) {
+			return merrors.NotFound(req.Service(), "Endpoint %v is not registered", req.Endpoint())
+		}
+	}
+
+	if err, ok := method.Call(args)[0].Interface().(error); ok {
+		return err
+	}
+	return nil
+}
+
+type liveKey struct{}
+
+func (s *liveServer) subscriberWrapper(fn server.SubscriberFunc) server.SubscriberFunc {
+	return func(ctx context.Context, msg server.Message) error {
+		// messages delivered to the subscriptions of the live server are always handled
+		if _, ok := ctx.Value(liveKey{}).(bool); ok {
+			return fn(ctx, msg)
+		}
+
+		s.RLock()
+		muted := s.muted[msg.Topic()]
+		s.RUnlock()
+
+		if muted {
+			return nil
+		}
+		return fn(ctx, msg)
+	}
+}
+
+// handlerName returns the name of the handler serving the endpoint, e.g. Foo for Foo.Bar
+func handlerName(endpoint string) string {
+	return strings.SplitN(endpoint, ".", 2)[0]
+}
+
+// liveRegistry advertises the handlers and subscribers of the live server in the services the
+// server registers
+type liveRegistry struct {
+	registry.Registry
+	server *liveServer
+}
+
+func (r *liveRegistry) Register(svc *registry.Service, opts ...registry.RegisterOption) error {
+	if svc.Name != r.server.Options().Name {
+		return r.Registry.Register(svc, opts...)
+	}
+
+	cp := *svc
+	cp.Endpoints = r.server.endpoints()
+	return r.Registry.Register(&cp, opts...)
+}
+
+// subscriberHandler is a function or method which handles the messages of a subscriber
+type subscriberHandler struct {
+	method  reflect.Value
+	reqType reflect.Type
+	ctx     bool
+}
+
+// newSubscriberHandlers returns the handlers of the subscriber, its function or the methods of
+// its value
+func newSubscriberHandlers(sb server.Subscriber) ([]*subscriberHandler, error) {
+	v := reflect.ValueOf(sb.Subscriber())
+
+	var methods []reflect.Value
+	if v.Kind() == reflect.Func {
+		methods = append(methods, v)
+	} else {
+		for i := 0; i < v.NumMethod(); i++ {
+			methods = append(methods, v.Method(i))
+		}
+	}
+
+	var handlers []*subscriberHandler
+	for _, m := range methods {
+		typ := m.Type()
+		if typ.NumIn() < 1 || typ.NumIn() > 2 || typ.NumOut() != 1 {
+			return nil, fmt.Errorf("subscriber %v has a handler of the wrong type %v", sb.Topic(), typ)
+		}
+		handlers = append(handlers, &subscriberHandler{
+			method:  m,
+			reqType: typ.In(typ.NumIn() - 1),
+			ctx:     typ.NumIn() == 2,
+		})
+	}
+	if len(handlers) == 0 {
+		return nil, fmt.Errorf("subscriber %v has no handlers", sb.Topic())
+	}
+	return handlers, nil
+}
+
+// marshalers decode the messages of the subscribers subscribed by the live server, by content type
+var marshalers = map[string]codec.Marshaler{
+	"application/json":         json.Marshaler{},
+	"application/grpc+json":    json.Marshaler{},
+	"application/grpc":         proto.Marshaler{},
+	"application/grpc+proto":   proto.Marshaler{},
+	"application/proto":        proto.Marshaler{},
+	"application/protobuf":     proto.Marshaler{},
+	"application/octet-stream": proto.Marshaler{},
+}
+
+// subscribe the subscriber to the broker
+func (s *liveServer) subscribe(sb *liveSubscriber) error {
+	opts := s.Server.Options()
+	if err := opts.Broker.Connect(); err != nil {
+		return err
+	}
+
+	var subOpts []broker.SubscribeOption
+	if queue := sb.Options().Queue; len(queue) > 0 {
+		subOpts = append(subOpts, broker.Queue(queue))
+	}
+	if cx := sb.Options().Context; cx != nil {
+		subOpts = append(subOpts, broker.SubscribeContext(cx))
+	}
+
+	sub, err := opts.Broker.Subscribe(sb.Topic(), s.brokerHandler(sb), subOpts...)
+	if err != nil {
+		return err
+	}
+	sb.sub = sub
+	return nil
+}
+
+// unsubscribe the subscribers subscribed by the live server from the broker
+func unsubscribe(subs []*liveSubscriber) error {
+	for _, sb := range subs {
+		if sb.sub == nil {
+			continue
+		}
+		if err := sb.sub.Unsubscribe(); err != nil {
+			return err
+		}
+		sb.sub = nil
+	}
+	return nil
+}
+
+// brokerHandler decodes the messages published to the topic of the subscriber and passes them
+// through the subscriber wrappers to its handlers
+func (s *liveServer) brokerHandler(sb *liveSubscriber) broker.Handler {
+	return func(msg *broker.Message) error {
+		ct := msg.Header["Content-Type"]
+		if len(ct) == 0 {
+			ct = "application/grpc"
+		}
+		m, ok := marshalers[ct]
+		if !ok {
+			return fmt.Errorf("Unsupported Content-Type: %s", ct)
+		}
+
+		hdr := make(map[string]string, len(msg.Header))
+		for k, v := range msg.Header {
+			hdr[k] = v
+		}
+		delete(hdr, "Content-Type")
+		ctx := context.WithValue(metadata.NewContext(context.Background(), hdr), liveKey{}, true)
+
+		for _, h := range sb.handlers {
+			req := reflect.New(h.reqType)
+			if h.reqType.Kind() == reflect.Ptr {
+				req = reflect.New(h.reqType.Elem())
+			}
+			if err := m.Unmarshal(msg.Body, req.Interface()); err != nil {
+				return err
+			}
+			if h.reqType.Kind() != reflect.Ptr {
+				req = req.Elem()
+			}
+
+			fn := func(ctx context.Context, msg server.Message) error {
+				args := []reflect.Value{reflect.ValueOf(msg.Payload())}
+				if h.ctx {
+					args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+				}
+				if err, ok := h.method.Call(args)[0].Interface().(error); ok {
+					return err
+				}
+				return nil
+			}
+			wrappers := s.Server.Options().SubWrappers
+			for i := len(wrappers); i > 0; i-- {
+				fn = wrappers[i-1](fn)
+			}
+
+			err := fn(ctx, &liveMessage{
+				topic:       sb.Topic(),
+				contentType: ct,
+				payload:     req.Interface(),
+				header:      msg.Header,
+				body:        msg.Body,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// liveMessage is a message delivered to a subscription of the live server
+type liveMessage struct {
+	topic       string
+	contentType string
+	payload     interface{}
+	header      map[string]string
+	body        []byte
+}
+
+func (m *liveMessage) Topic() string             { return m.topic }
+func (m *liveMessage) Payload() interface{}      { return m.payload }
+func (m *liveMessage) ContentType() string       { return m.contentType }
+func (m *liveMessage) Header() map[string]string { return m.header }
+func (m *liveMessage) Body() []byte              { return m.body }
+func (m *liveMessage) Codec() codec.Reader       { return nil }
+
+// live is implemented by servers which allow handlers and subscribers to be removed whilst
+// they're running
+type live interface {
+	Unhandle(name string) error
+	Unsubscribe(topic string) error
+}
+
+// Unhandle removes the handler with the name from the server, even if it's running
+func Unhandle(name string) error {
+	l, ok := DefaultServer.(live)
+	if !ok {
+		return errors.New("server doesn't support removing handlers")
+	}
+	return l.Unhandle(name)
+}
+
+// Unsubscribe removes the subscribers of the topic from the server, even if it's running
+func Unsubscribe(topic string) error {
+	l, ok := DefaultServer.(live)
+	if !ok {
+		return errors.New("server doesn't support removing subscribers")
+	}
+	return l.Unsubscribe(topic)
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/broker"
+	"github.com/micro/go-micro/v3/broker/memory"
+	"github.com/micro/go-micro/v3/registry"
+	rmemory "github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/go-micro/v3/server/grpc"
+)
+
+type Request struct {
+	Name string
+}
+
+type Response struct {
+	Msg string
+}
+
+type Greeter struct{}
+
+func (g *Greeter) Hello(ctx context.Context, req *Request, rsp *Response) error {
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+type Plugin struct{}
+
+func (p *Plugin) Run(ctx context.Context, req *Request, rsp *Response) error {
+	return nil
+}
+
+type request struct {
+	server.Request
+	endpoint string
+	body     interface{}
+}
+
+func (r request) Service() string   { return "live" }
+func (r request) Endpoint() string  { return r.endpoint }
+func (r request) Body() interface{} { return r.body }
+func (r request) Stream() bool      { return false }
+
+// testRegistry keeps the service last registered, since the memory registry doesn't update the
+// endpoints of a service which is already registered
+type testRegistry struct {
+	registry.Registry
+
+	sync.Mutex
+	last *registry.Service
+}
+
+func (r *testRegistry) Register(svc *registry.Service, opts ...registry.RegisterOption) error {
+	r.Lock()
+	r.last = svc
+	r.Unlock()
+	return r.Registry.Register(svc, opts...)
+}
+
+func endpoints(t *testing.T, r *testRegistry) map[string]bool {
+	r.Lock()
+	defer r.Unlock()
+	if r.last == nil || r.last.Name != "live" {
+		t.Fatalf("Expected the service to be registered, got %v", r.last)
+	}
+	eps := make(map[string]bool)
+	for _, ep := range r.last.Endpoints {
+		eps[ep.Name] = true
+	}
+	return eps
+}
+
+func TestLiveServer(t *testing.T) {
+	reg := &testRegistry{Registry: rmemory.NewRegistry()}
+	s := newLiveServer(&schemaServer{Server: grpc.NewServer(
+		server.Name("live"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(memory.NewBroker()),
+	)})
+
+	if err := s.Handle(s.NewHandler(&Greeter{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if eps := endpoints(t, reg); !eps["Greeter.Hello"] || eps["Plugin.Run"] {
+		t.Fatalf("Expected only the greeter to be advertised, got %v", eps)
+	}
+
+	if err := s.Handle(s.NewHandler(&Plugin{})); err != nil {
+		t.Fatal(err)
+	}
+	if eps := endpoints(t, reg); !eps["Plugin.Run"] {
+		t.Fatalf("Expected the plugin to be advertised once handled, got %v", eps)
+	}
+
+	if err := s.Subscribe(s.NewSubscriber("events", func(ctx context.Context, req *Request) error {
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if eps := endpoints(t, reg); !eps["Func"] {
+		t.Fatalf("Expected the subscriber to be advertised, got %v", eps)
+	}
+
+	if err := s.Unhandle("Plugin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unsubscribe("events"); err != nil {
+		t.Fatal(err)
+	}
+	if eps := endpoints(t, reg); eps["Plugin.Run"] || eps["Func"] || !eps["Greeter.Hello"] {
+		t.Fatalf("Expected the plugin and subscriber to be removed, got %v", eps)
+	}
+
+	if err := s.Unhandle("Missing"); err == nil {
+		t.Errorf("Expected an error removing an unknown handler")
+	}
+
+	called := false
+	h := s.handlerWrapper(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		called = true
+		return nil
+	})
+	if err := h(context.TODO(), request{endpoint: "Plugin.Run"}, nil); err == nil || called {
+		t.Errorf("Expected requests to a removed handler to be rejected")
+	}
+
+	// removed handlers and subscribers can be added again
+	if err := s.Handle(s.NewHandler(&Plugin{})); err != nil {
+		t.Fatalf("Expected a removed handler to be registered again, got %v", err)
+	}
+	if err := s.Handle(s.NewHandler(&Plugin{})); err == nil {
+		t.Errorf("Expected a handler to only be registered once")
+	}
+	if eps := endpoints(t, reg); !eps["Plugin.Run"] {
+		t.Fatalf("Expected the plugin to be advertised again, got %v", eps)
+	}
+	if err := h(context.TODO(), request{endpoint: "Plugin.Run", body: &Request{}}, &Response{}); err != nil || called {
+		t.Errorf("Expected requests to be dispatched to the handler added again, got %v", err)
+	}
+
+	msgs := make(chan *Request, 1)
+	if err := s.Subscribe(s.NewSubscriber("events", func(ctx context.Context, req *Request) error {
+		msgs <- req
+		return nil
+	})); err != nil {
+		t.Fatalf("Expected a removed topic to be subscribed to again, got %v", err)
+	}
+	if eps := endpoints(t, reg); !eps["Func"] {
+		t.Fatalf("Expected the subscriber to be advertised again, got %v", eps)
+	}
+	err := s.Options().Broker.Publish("events", &broker.Message{
+		Header: map[string]string{"Content-Type": "application/json"},
+		Body:   []byte(`{"Name":"John"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case req := <-msgs:
+		if req.Name != "John" {
+			t.Errorf("Expected the message to be decoded, got %v", req)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the subscriber added again to receive the message")
+	}
+}
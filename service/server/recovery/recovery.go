@@ -0,0 +1,153 @@
+// Package recovery provides server wrappers which recover from panics in handlers and
+// subscribers. A panic is returned to the caller as an internal server error carrying a
+// correlation id, and logged with its stack and the same id, so the error a user reports can be
+// matched to the stack without the stack leaking to them. The panics are counted by endpoint.
+package recovery
+
+import (
+	"context"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	mudebug "github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events/util"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// CorrelationKey is the header the correlation id of a request is read from, one is generated
+// if it's not set
+const CorrelationKey = util.CorrelationKey
+
+var (
+	// DefaultMetrics are the panics recovered by the wrappers
+	DefaultMetrics = NewMetrics()
+
+	metricsOnce sync.Once
+)
+
+// Metrics counts the panics recovered
+type Metrics struct {
+	sync.Mutex
+	panics map[string]uint64
+}
+
+// NewMetrics returns metrics with no panics counted
+func NewMetrics() *Metrics {
+	return &Metrics{panics: make(map[string]uint64)}
+}
+
+// Count of the panics recovered in the endpoint or the subscriber of the topic
+func (m *Metrics) Count(endpoint string) uint64 {
+	m.Lock()
+	defer m.Unlock()
+	return m.panics[endpoint]
+}
+
+// Total of the panics recovered
+func (m *Metrics) Total() uint64 {
+	m.Lock()
+	defer m.Unlock()
+	var total uint64
+	for _, n := range m.panics {
+		total += n
+	}
+	return total
+}
+
+// Endpoints which have panicked, sorted by name
+func (m *Metrics) Endpoints() []string {
+	m.Lock()
+	defer m.Unlock()
+	endpoints := make([]string, 0, len(m.panics))
+	for e := range m.panics {
+		endpoints = append(endpoints, e)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// Values of the metrics, the total of the panics recovered and the panics of each endpoint
+func (m *Metrics) Values() map[string]float64 {
+	m.Lock()
+	defer m.Unlock()
+	values := map[string]float64{"total": 0}
+	for e, n := range m.panics {
+		values["total"] += float64(n)
+		values[e] = float64(n)
+	}
+	return values
+}
+
+func (m *Metrics) inc(endpoint string) {
+	m.Lock()
+	m.panics[endpoint]++
+	m.Unlock()
+}
+
+// correlationID returns the correlation id of the request, generating one if it's not set
+func correlationID(ctx context.Context) string {
+	if id, ok := metadata.Get(ctx, CorrelationKey); ok && len(id) > 0 {
+		return id
+	}
+	return uuid.New().String()
+}
+
+// register the default metrics as debug metrics, once the wrappers are used
+func register() {
+	metricsOnce.Do(func() {
+		mudebug.RegisterMetrics("panics", func() map[string]float64 {
+			return DefaultMetrics.Values()
+		})
+	})
+}
+
+// HandlerWrapper recovers from panics in handlers, returning an internal server error
+func HandlerWrapper() server.HandlerWrapper {
+	register()
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				id := correlationID(ctx)
+				DefaultMetrics.inc(req.Endpoint())
+				logger.Errorf("Panic handling %v.%v, correlation id %v: %v\n%s", req.Service(), req.Endpoint(), id, r, debug.Stack())
+				err = errors.InternalServerError(req.Service(), "Internal server error, correlation id %v", id)
+			}()
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// SubscriberWrapper recovers from panics in subscribers, returning an error so the message is
+// handled as if the subscriber failed
+func SubscriberWrapper() server.SubscriberWrapper {
+	register()
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				id := msg.Header()[CorrelationKey]
+				if len(id) == 0 {
+					id = correlationID(ctx)
+				}
+				DefaultMetrics.inc(msg.Topic())
+				logger.Errorf("Panic handling message on topic %v, correlation id %v: %v\n%s", msg.Topic(), id, r, debug.Stack())
+				err = errors.InternalServerError("", "Internal server error, correlation id %v", id)
+			}()
+
+			return fn(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package recovery
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/server"
+	mudebug "github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+}
+
+func (r *testRequest) Service() string  { return "foo" }
+func (r *testRequest) Endpoint() string { return r.endpoint }
+
+func TestHandlerWrapper(t *testing.T) {
+	DefaultMetrics = NewMetrics()
+
+	h := HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		if req.Endpoint() == "Foo.Panic" {
+			panic("nil map")
+		}
+		return nil
+	})
+
+	if err := h(context.TODO(), &testRequest{endpoint: "Foo.Bar"}, nil); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	ctx := metadata.Set(context.TODO(), CorrelationKey, "abc")
+	err := h(ctx, &testRequest{endpoint: "Foo.Panic"}, nil)
+	verr := errors.Parse(err)
+	if verr == nil || verr.Code != 500 {
+		t.Fatalf("Expected an internal server error, got %v", err)
+	}
+	if !strings.Contains(verr.Detail, "abc") || strings.Contains(verr.Detail, "nil map") {
+		t.Errorf("Expected the correlation id and not the panic in the error, got %v", verr.Detail)
+	}
+
+	h(context.TODO(), &testRequest{endpoint: "Foo.Panic"}, nil)
+	if n := DefaultMetrics.Count("Foo.Panic"); n != 2 {
+		t.Errorf("Expected 2 panics to be counted, got %v", n)
+	}
+	if n := DefaultMetrics.Total(); n != 2 {
+		t.Errorf("Expected 2 panics in total, got %v", n)
+	}
+	if m := mudebug.ReadMetrics(); m["panics.total"] != 2 || m["panics.Foo.Panic"] != 2 {
+		t.Errorf("Expected the panics in the debug metrics, got %v", m)
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/registry/util"
+	"github.com/micro/micro/v3/service/server/annotations"
+)
+
+// schemaServer registers the schemas of the request and response types of each endpoint in
+// its metadata, so they can be documented and validated, their versions, so callers can check
+// they're compatible, and the content types it accepts. The rules declared in the options of
+// the endpoints' protos are registered to be enforced.
+type schemaServer struct {
+	server.Server
+
+	sync.Mutex
+	// codecs accepted by every handler, nil until a handler is created
+	codecs map[string]bool
+}
+
+func (s *schemaServer) NewHandler(h interface{}, opts ...server.HandlerOption) server.Handler {
+	annotations.DefaultRules.Register(h)
+	s.accept(util.HandlerCodecs(h))
+
+	var schemas []server.HandlerOption
+	for name, md := range util.EndpointSchemas(h) {
+		schemas = append(schemas, server.EndpointMetadata(name, md))
+	}
+
+	// the metadata passed by the caller takes precedence
+	return s.Server.NewHandler(h, append(schemas, opts...)...)
+}
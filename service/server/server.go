@@ -6,9 +6,9 @@ import (
 )
 
 // DefaultServer for the service
-var DefaultServer server.Server = grpc.NewServer()
+var DefaultServer server.Server = newLiveServer(&schemaServer{Server: grpc.NewServer()})
 
-// Register a handler
+// Register a handler, the handler can be registered whilst the server is running
 func Handle(hdlr server.Handler) error {
 	return DefaultServer.Handle(hdlr)
 }
@@ -23,7 +23,7 @@ func NewSubscriber(topic string, hdlr interface{}, opts ...server.SubscriberOpti
 	return DefaultServer.NewSubscriber(topic, hdlr, opts...)
 }
 
-// Register a subscriber
+// Register a subscriber, the subscriber can be registered whilst the server is running
 func Subscribe(sub server.Subscriber) error {
 	return DefaultServer.Subscribe(sub)
 }
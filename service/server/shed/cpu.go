@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package shed
+
+import "syscall"
+
+// cpuTime returns the total user and system cpu time of the process in nanoseconds
+func cpuTime() uint64 {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return uint64(ru.Utime.Nano() + ru.Stime.Nano())
+}
@@ -0,0 +1,6 @@
+package shed
+
+// cpuTime is not supported on windows, so load is shed on latency alone
+func cpuTime() uint64 {
+	return 0
+}
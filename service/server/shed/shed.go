@@ -0,0 +1,256 @@
+// Package shed provides a server wrapper which sheds load when the server is overloaded, so a
+// traffic spike slows a share of requests down to a fast rejection rather than every request
+// down to a timeout. The server is overloaded when the P99 latency of the requests handled or
+// the CPU usage of the process crosses a threshold. The share of requests shed grows for each
+// interval the server stays overloaded and shrinks once it recovers.
+//
+// Shed requests are rejected with a 503 error saying when to retry, which ParseRetryAfter
+// parses. Register Classify with the client's retry classifiers so calls which were shed are
+// retried once the server asked them to wait:
+//
+//	retry.Register(shed.Classify)
+package shed
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/client/retry"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// DefaultInterval is how often the load is measured and the share of requests shed adjusted
+	DefaultInterval = time.Second
+	// DefaultRetryAfter is how long clients are told to wait before retrying shed requests
+	DefaultRetryAfter = time.Second
+	// DefaultMaxRatio is the largest share of requests shed, some are always handled so the
+	// latency can be measured
+	DefaultMaxRatio = 0.9
+
+	// increase and decrease are how much the share of requests shed changes each interval
+	increase = 0.1
+	decrease = 0.05
+	// maxSamples is the number of latencies kept each interval
+	maxSamples = 10000
+)
+
+// Options of the controller
+type Options struct {
+	// MaxLatency is the P99 latency above which the server is overloaded, zero to ignore latency
+	MaxLatency time.Duration
+	// MaxCPU is the share of the cpus used by the process above which the server is
+	// overloaded, e.g. 0.8. Zero to ignore cpu.
+	MaxCPU float64
+	// Interval the load is measured at
+	Interval time.Duration
+	// RetryAfter is how long clients are told to wait before retrying
+	RetryAfter time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// MaxLatency sets the P99 latency above which the server is overloaded
+func MaxLatency(d time.Duration) Option {
+	return func(o *Options) {
+		o.MaxLatency = d
+	}
+}
+
+// MaxCPU sets the share of the cpus used above which the server is overloaded
+func MaxCPU(f float64) Option {
+	return func(o *Options) {
+		o.MaxCPU = f
+	}
+}
+
+// Interval sets how often the load is measured
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+// RetryAfter sets how long clients are told to wait before retrying
+func RetryAfter(d time.Duration) Option {
+	return func(o *Options) {
+		o.RetryAfter = d
+	}
+}
+
+// Controller measures the load of the server and decides which requests are shed
+type Controller struct {
+	opts Options
+
+	// cpu, now and random are replaced in tests
+	cpu    func() uint64
+	now    func() time.Time
+	random func() float64
+
+	sync.Mutex
+	// ratio is the share of requests shed
+	ratio float64
+	// latencies of the requests handled this interval
+	latencies []time.Duration
+	// count of the requests handled this interval
+	count int
+	// updated is when the load was last measured, with the cpu time at that point
+	updated time.Time
+	cpuTime uint64
+}
+
+// New returns a controller with the options
+func New(opts ...Option) *Controller {
+	options := Options{
+		Interval:   DefaultInterval,
+		RetryAfter: DefaultRetryAfter,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	c := &Controller{
+		opts:   options,
+		cpu:    cpuTime,
+		now:    time.Now,
+		random: rand.Float64,
+	}
+	c.updated = c.now()
+	c.cpuTime = c.cpu()
+	return c
+}
+
+// Ratio returns the share of requests being shed
+func (c *Controller) Ratio() float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.ratio
+}
+
+// admit returns true if the request should be handled, measuring the load if the interval has
+// passed
+func (c *Controller) admit() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if now := c.now(); now.Sub(c.updated) >= c.opts.Interval {
+		c.update(now)
+	}
+	return c.ratio == 0 || c.random() >= c.ratio
+}
+
+// record the latency of a request which was handled. Once the samples are full they're
+// replaced at random so they represent the whole interval.
+func (c *Controller) record(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.count++
+	if len(c.latencies) < maxSamples {
+		c.latencies = append(c.latencies, d)
+	} else if i := int(c.random() * float64(c.count)); i < maxSamples {
+		c.latencies[i] = d
+	}
+}
+
+// update measures the load since the last update and adjusts the share of requests shed. The
+// lock must be held.
+func (c *Controller) update(now time.Time) {
+	var overloaded bool
+
+	if c.opts.MaxLatency > 0 && percentile(c.latencies, 0.99) > c.opts.MaxLatency {
+		overloaded = true
+	}
+
+	// the cpu time is zero on platforms where it's not supported
+	cpuTime := c.cpu()
+	if elapsed := now.Sub(c.updated); c.opts.MaxCPU > 0 && cpuTime > 0 && elapsed > 0 {
+		usage := float64(cpuTime-c.cpuTime) / float64(elapsed) / float64(runtime.NumCPU())
+		if usage > c.opts.MaxCPU {
+			overloaded = true
+		}
+	}
+
+	if overloaded {
+		c.ratio += increase
+		if c.ratio > DefaultMaxRatio {
+			c.ratio = DefaultMaxRatio
+		}
+	} else {
+		c.ratio -= decrease
+		if c.ratio < 0 {
+			c.ratio = 0
+		}
+	}
+
+	c.latencies = c.latencies[:0]
+	c.count = 0
+	c.updated = now
+	c.cpuTime = cpuTime
+}
+
+// percentile returns the latency which the share of latencies are less than or equal to, zero
+// if there are none. The latencies are sorted.
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	i := int(float64(len(latencies))*p+0.5) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(latencies) {
+		i = len(latencies) - 1
+	}
+	return latencies[i]
+}
+
+// HandlerWrapper rejects the requests shed with a 503 error
+func (c *Controller) HandlerWrapper() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if !c.admit() {
+				return errors.ServiceUnavailable(req.Service(), "Server is overloaded, retry after %v", c.opts.RetryAfter)
+			}
+
+			start := time.Now()
+			err := h(ctx, req, rsp)
+			c.record(time.Since(start))
+			return err
+		}
+	}
+}
+
+var retryAfterRe = regexp.MustCompile(`retry after (\S+)$`)
+
+// ParseRetryAfter returns how long to wait before retrying a request which was shed, false if
+// the error isn't from a request which was shed
+func ParseRetryAfter(err error) (time.Duration, bool) {
+	verr := errors.Parse(err)
+	if verr == nil || verr.Code != 503 {
+		return 0, false
+	}
+	m := retryAfterRe.FindStringSubmatch(verr.Detail)
+	if m == nil {
+		return 0, false
+	}
+	d, perr := time.ParseDuration(m[1])
+	return d, perr == nil
+}
+
+// Classify is a retry classifier which retries the requests which were shed once the time the
+// server asked for has passed, leaving every other error to the next classifier
+func Classify(err error) retry.Classification {
+	d, ok := ParseRetryAfter(err)
+	if !ok {
+		return retry.Classification{}
+	}
+	return retry.Classification{Class: retry.Retryable, Backoff: d}
+}
@@ -0,0 +1,110 @@
+package shed
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/client/retry"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testRequest struct {
+	server.Request
+}
+
+func (r *testRequest) Service() string  { return "foo" }
+func (r *testRequest) Endpoint() string { return "Foo.Bar" }
+
+func TestShedLatency(t *testing.T) {
+	c := New(MaxLatency(time.Millisecond*10), RetryAfter(time.Second*2))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.random = func() float64 { return 0 }
+
+	var latency time.Duration
+	h := c.HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		time.Sleep(latency)
+		return nil
+	})
+	call := func() error { return h(context.TODO(), &testRequest{}, nil) }
+
+	// requests are handled until the load is measured
+	latency = time.Millisecond * 20
+	if err := call(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the P99 latency crossed the threshold so requests are shed
+	now = now.Add(DefaultInterval)
+	err := call()
+	if verr := errors.Parse(err); verr == nil || verr.Code != 503 {
+		t.Fatalf("Expected the request to be shed, got %v", err)
+	}
+	if d, ok := ParseRetryAfter(err); !ok || d != time.Second*2 {
+		t.Errorf("Expected to retry after 2s, got %v %v", d, ok)
+	}
+	if r := c.Ratio(); r != increase {
+		t.Errorf("Expected %v of requests to be shed, got %v", increase, r)
+	}
+
+	// requests which aren't shed are handled
+	c.random = func() float64 { return 0.99 }
+	latency = 0
+	if err := call(); err != nil {
+		t.Errorf("Expected the request to be handled, got %v", err)
+	}
+
+	// once the latency recovers fewer requests are shed
+	now = now.Add(DefaultInterval)
+	call()
+	if r := c.Ratio(); r >= increase {
+		t.Errorf("Expected fewer requests to be shed, got %v", r)
+	}
+}
+
+func TestShedCPU(t *testing.T) {
+	var cpu uint64
+	c := New(MaxCPU(0.5))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.cpu = func() uint64 { return cpu }
+	c.random = func() float64 { return 0 }
+	c.updated = now
+	c.cpuTime = 1
+
+	h := c.HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+
+	// the process used every cpu for the whole interval
+	for i := 0; i < 20; i++ {
+		now = now.Add(DefaultInterval)
+		cpu += uint64(DefaultInterval) * uint64(runtime.NumCPU())
+		h(context.TODO(), &testRequest{}, nil)
+	}
+	if r := c.Ratio(); r != DefaultMaxRatio {
+		t.Errorf("Expected the share of requests shed to be capped at %v, got %v", DefaultMaxRatio, r)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if _, ok := ParseRetryAfter(errors.ServiceUnavailable("foo", "unavailable")); ok {
+		t.Errorf("Expected no retry after for other errors")
+	}
+	if _, ok := ParseRetryAfter(nil); ok {
+		t.Errorf("Expected no retry after for a nil error")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	err := errors.ServiceUnavailable("foo", "Server is overloaded, retry after %v", 3*time.Second)
+	if cl := Classify(err); cl.Class != retry.Retryable || cl.Backoff != 3*time.Second {
+		t.Errorf("Expected the shed request to be retried after 3s, got %+v", cl)
+	}
+	if cl := Classify(errors.BadRequest("foo", "invalid")); cl.Class != retry.Unknown {
+		t.Errorf("Expected other errors to be left to the next classifier, got %+v", cl)
+	}
+}
@@ -0,0 +1,233 @@
+// Package subscriber provides options which control how the server handles the messages of a
+// subscriber, so high-volume topics can be consumed safely: the number of messages handled at
+// once, processing the messages with the same key in the order they arrive, and a deadline for
+// each message to be handled within, after which it isn't acknowledged and can be redelivered.
+//
+//	service.Subscribe("orders", handler,
+//		subscriber.MaxConcurrent(16),
+//		subscriber.OrderedBy(subscriber.Header("Micro-Order-Id")),
+//		subscriber.AckDeadline(time.Second*30),
+//	)
+//
+// The options apply to every subscriber to the topic on the server, the last subscriber
+// registered with options taking precedence.
+package subscriber
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// KeyFunc returns the key of the message, messages with the same key are handled in order.
+// Messages with a blank key aren't ordered.
+type KeyFunc func(msg server.Message) string
+
+// Header returns a key func which keys messages by the value of the header
+func Header(name string) KeyFunc {
+	return func(msg server.Message) string {
+		return msg.Header()[name]
+	}
+}
+
+// Options of a subscriber
+type Options struct {
+	// MaxConcurrent is the number of messages handled at once, zero for no limit
+	MaxConcurrent int
+	// OrderedBy keys the messages which are handled one at a time in the order they arrive
+	OrderedBy KeyFunc
+	// AckDeadline is how long a message can take to be handled before it's failed, zero for
+	// no deadline
+	AckDeadline time.Duration
+}
+
+type optionsKey struct{}
+
+// withOptions returns a subscriber option which sets the options in its context, which is
+// passed to the broker and read by the controller when the subscriber is registered
+func withOptions(fn func(o *Options)) server.SubscriberOption {
+	return func(so *server.SubscriberOptions) {
+		if so.Context == nil {
+			so.Context = context.Background()
+		}
+		var o Options
+		if prev, ok := so.Context.Value(optionsKey{}).(*Options); ok {
+			o = *prev
+		}
+		fn(&o)
+		so.Context = context.WithValue(so.Context, optionsKey{}, &o)
+	}
+}
+
+// MaxConcurrent sets the number of messages the subscriber handles at once. Messages over the
+// limit wait for a handler to be free, so the broker delivers no more than the limit.
+func MaxConcurrent(n int) server.SubscriberOption {
+	return withOptions(func(o *Options) {
+		o.MaxConcurrent = n
+	})
+}
+
+// OrderedBy handles the messages with the same key one at a time, in the order they arrive
+func OrderedBy(fn KeyFunc) server.SubscriberOption {
+	return withOptions(func(o *Options) {
+		o.OrderedBy = fn
+	})
+}
+
+// AckDeadline sets how long a message can take to be handled. Messages which take longer fail
+// with a timeout error, so they aren't acknowledged, and the handler's context is cancelled.
+func AckDeadline(d time.Duration) server.SubscriberOption {
+	return withOptions(func(o *Options) {
+		o.AckDeadline = d
+	})
+}
+
+// FromSubscriber returns the options the subscriber was created with
+func FromSubscriber(sub server.Subscriber) (Options, bool) {
+	ctx := sub.Options().Context
+	if ctx == nil {
+		return Options{}, false
+	}
+	o, ok := ctx.Value(optionsKey{}).(*Options)
+	if !ok {
+		return Options{}, false
+	}
+	return *o, true
+}
+
+// Controller applies the options of the subscribers registered to the messages of their topics
+type Controller struct {
+	sync.RWMutex
+	topics map[string]*topic
+}
+
+// NewController returns a controller without any subscribers
+func NewController() *Controller {
+	return &Controller{topics: make(map[string]*topic)}
+}
+
+// Register the subscriber, applying its options to the messages of its topic. Subscribers
+// without options are ignored.
+func (c *Controller) Register(sub server.Subscriber) {
+	opts, ok := FromSubscriber(sub)
+	if !ok {
+		return
+	}
+
+	t := &topic{opts: opts, keys: make(map[string][]chan struct{})}
+	if opts.MaxConcurrent > 0 {
+		t.slots = make(chan struct{}, opts.MaxConcurrent)
+	}
+
+	c.Lock()
+	c.topics[sub.Topic()] = t
+	c.Unlock()
+}
+
+// Wrapper is a subscriber wrapper which applies the options of the topic to its messages
+func (c *Controller) Wrapper(fn server.SubscriberFunc) server.SubscriberFunc {
+	return func(ctx context.Context, msg server.Message) error {
+		c.RLock()
+		t, ok := c.topics[msg.Topic()]
+		c.RUnlock()
+
+		if !ok {
+			return fn(ctx, msg)
+		}
+		return t.handle(ctx, msg, fn)
+	}
+}
+
+// topic whose messages are controlled
+type topic struct {
+	opts  Options
+	slots chan struct{}
+
+	sync.Mutex
+	// keys being handled, with the queue of messages waiting for their turn. The first in the
+	// queue is the message being handled.
+	keys map[string][]chan struct{}
+}
+
+func (t *topic) handle(ctx context.Context, msg server.Message, fn server.SubscriberFunc) error {
+	// wait for the messages with the same key which arrived first, then for a free handler, so
+	// messages waiting for their turn don't hold handlers
+	var key string
+	if t.opts.OrderedBy != nil {
+		key = t.opts.OrderedBy(msg)
+	}
+	if len(key) > 0 {
+		<-t.lock(key)
+	}
+	if t.slots != nil {
+		t.slots <- struct{}{}
+	}
+
+	// the key and handler are held until the handler returns, even once the deadline passes,
+	// so later messages with the key aren't handled before it
+	release := func() {
+		if t.slots != nil {
+			<-t.slots
+		}
+		if len(key) > 0 {
+			t.unlock(key)
+		}
+	}
+
+	if t.opts.AckDeadline <= 0 {
+		defer release()
+		return fn(ctx, msg)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.opts.AckDeadline)
+	errCh := make(chan error, 1)
+	go func() {
+		defer release()
+		defer cancel()
+		errCh <- fn(ctx, msg)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		// the handler may have returned just as the deadline passed
+		select {
+		case err := <-errCh:
+			return err
+		default:
+		}
+		return errors.Timeout("subscriber", "message on topic %v wasn't handled within the ack deadline of %v", msg.Topic(), t.opts.AckDeadline)
+	}
+}
+
+// lock returns a channel which is closed when it's the message's turn to be handled
+func (t *topic) lock(key string) <-chan struct{} {
+	t.Lock()
+	defer t.Unlock()
+
+	turn := make(chan struct{})
+	queue := t.keys[key]
+	if len(queue) == 0 {
+		close(turn)
+	}
+	t.keys[key] = append(queue, turn)
+	return turn
+}
+
+// unlock hands the key to the next message waiting for it
+func (t *topic) unlock(key string) {
+	t.Lock()
+	defer t.Unlock()
+
+	queue := t.keys[key][1:]
+	if len(queue) == 0 {
+		delete(t.keys, key)
+		return
+	}
+	t.keys[key] = queue
+	close(queue[0])
+}
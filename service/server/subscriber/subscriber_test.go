@@ -0,0 +1,154 @@
+package subscriber
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+type testSubscriber struct {
+	opts server.SubscriberOptions
+}
+
+func (s *testSubscriber) Topic() string                     { return "orders" }
+func (s *testSubscriber) Subscriber() interface{}           { return nil }
+func (s *testSubscriber) Endpoints() []*registry.Endpoint   { return nil }
+func (s *testSubscriber) Options() server.SubscriberOptions { return s.opts }
+
+type testMessage struct {
+	server.Message
+	header map[string]string
+}
+
+func (m *testMessage) Topic() string             { return "orders" }
+func (m *testMessage) Header() map[string]string { return m.header }
+
+func controller(opts ...server.SubscriberOption) *Controller {
+	c := NewController()
+	c.Register(&testSubscriber{opts: server.NewSubscriberOptions(opts...)})
+	return c
+}
+
+func TestMaxConcurrent(t *testing.T) {
+	var running, max int32
+	fn := controller(MaxConcurrent(2)).Wrapper(func(ctx context.Context, msg server.Message) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * 10)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(context.TODO(), &testMessage{})
+		}()
+	}
+	wg.Wait()
+
+	if max != 2 {
+		t.Errorf("Expected 2 messages to be handled at once, got %v", max)
+	}
+}
+
+func TestOrderedBy(t *testing.T) {
+	var mtx sync.Mutex
+	handled := map[string][]int{}
+	release := make(chan struct{})
+
+	fn := controller(OrderedBy(Header("Order-Id"))).Wrapper(func(ctx context.Context, msg server.Message) error {
+		if msg.Header()["Seq"] == "0" {
+			<-release
+		}
+		mtx.Lock()
+		id := msg.Header()["Order-Id"]
+		handled[id] = append(handled[id], int(msg.Header()["Seq"][0]-'0'))
+		mtx.Unlock()
+		return nil
+	})
+
+	// the first message for "a" blocks the others for "a", but not those for "b"
+	var wg sync.WaitGroup
+	send := func(id string, seq int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(context.TODO(), &testMessage{header: map[string]string{"Order-Id": id, "Seq": string(rune('0' + seq))}})
+		}()
+		time.Sleep(time.Millisecond * 5)
+	}
+	send("a", 0)
+	for i := 1; i < 5; i++ {
+		send("a", i)
+		send("b", i)
+	}
+
+	mtx.Lock()
+	if len(handled["a"]) != 0 || len(handled["b"]) != 4 {
+		t.Errorf("Expected only the messages for b to be handled, got %v", handled)
+	}
+	mtx.Unlock()
+
+	close(release)
+	wg.Wait()
+	for i, seq := range handled["a"] {
+		if seq != i {
+			t.Fatalf("Expected the messages for a to be handled in order, got %v", handled["a"])
+		}
+	}
+}
+
+func TestAckDeadline(t *testing.T) {
+	cancelled := make(chan struct{})
+	fn := controller(AckDeadline(time.Millisecond*20), OrderedBy(Header("Order-Id"))).Wrapper(func(ctx context.Context, msg server.Message) error {
+		if msg.Header()["Slow"] != "true" {
+			return nil
+		}
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 20)
+		close(cancelled)
+		return nil
+	})
+
+	err := fn(context.TODO(), &testMessage{header: map[string]string{"Order-Id": "a", "Slow": "true"}})
+	if verr := errors.Parse(err); verr == nil || verr.Code != 408 {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+
+	// the next message with the key waits for the slow handler to return
+	if err := fn(context.TODO(), &testMessage{header: map[string]string{"Order-Id": "a"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Errorf("Expected the next message to be handled after the slow handler returned")
+	}
+}
+
+func TestWithoutOptions(t *testing.T) {
+	c := NewController()
+	c.Register(&testSubscriber{opts: server.NewSubscriberOptions(server.SubscriberQueue("orders"))})
+	if len(c.topics) != 0 {
+		t.Errorf("Expected subscribers without options to be ignored")
+	}
+
+	opts, ok := FromSubscriber(&testSubscriber{opts: server.NewSubscriberOptions(MaxConcurrent(4), AckDeadline(time.Second))})
+	if !ok || opts.MaxConcurrent != 4 || opts.AckDeadline != time.Second {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+}
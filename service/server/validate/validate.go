@@ -0,0 +1,135 @@
+// Package validate provides a server wrapper which enforces the constraints declared in the
+// protos of requests before their handlers are called. The constraints are those generated by
+// protoc-gen-validate, or any request type with a Validate method. Requests which violate them
+// are rejected with a 400 error listing the fields at fault, which Violations decodes.
+package validate
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// validator is implemented by the messages protoc-gen-validate generates, Validate returns the
+// first violation
+type validator interface {
+	Validate() error
+}
+
+// allValidator is implemented by messages generated by newer versions of protoc-gen-validate,
+// ValidateAll returns every violation
+type allValidator interface {
+	ValidateAll() error
+}
+
+// fieldError is implemented by the errors protoc-gen-validate generates for each violation
+type fieldError interface {
+	Field() string
+	Reason() string
+	Cause() error
+}
+
+// multiError is implemented by the error returned by ValidateAll
+type multiError interface {
+	AllErrors() []error
+}
+
+// Violation of a constraint by a field
+type Violation struct {
+	// Field is the path of the field, e.g. address.city
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// detail is the detail of the error requests are rejected with
+type detail struct {
+	Message    string       `json:"message"`
+	Violations []*Violation `json:"violations"`
+}
+
+// Validate the message, returning a 400 error with the violations if it's invalid. Messages
+// without constraints are valid.
+func Validate(id string, msg interface{}) error {
+	var err error
+	switch v := msg.(type) {
+	case allValidator:
+		err = v.ValidateAll()
+	case validator:
+		err = v.Validate()
+	default:
+		return nil
+	}
+	if err == nil {
+		return nil
+	}
+
+	d := detail{Message: "Invalid request", Violations: violations("", err)}
+	bytes, merr := json.Marshal(d)
+	if merr != nil {
+		return errors.BadRequest(id, err.Error())
+	}
+	return errors.BadRequest(id, string(bytes))
+}
+
+// violations flattens the error into the violations of each field. Violations of nested
+// messages are reported against the nested fields, prefixed with the path of the message.
+func violations(prefix string, err error) []*Violation {
+	if m, ok := err.(multiError); ok {
+		var result []*Violation
+		for _, e := range m.AllErrors() {
+			result = append(result, violations(prefix, e)...)
+		}
+		return result
+	}
+
+	f, ok := err.(fieldError)
+	if !ok {
+		return []*Violation{{Field: prefix, Reason: err.Error()}}
+	}
+
+	field := f.Field()
+	if len(prefix) > 0 {
+		field = prefix + "." + field
+	}
+	if cause := f.Cause(); cause != nil {
+		if _, nested := cause.(fieldError); nested {
+			return violations(field, cause)
+		}
+		if _, nested := cause.(multiError); nested {
+			return violations(field, cause)
+		}
+	}
+	return []*Violation{{Field: field, Reason: f.Reason()}}
+}
+
+// Violations returns the violations in an error returned by the wrapper, nil if the error isn't
+// a validation error
+func Violations(err error) []*Violation {
+	verr := errors.Parse(err)
+	if verr == nil || verr.Code != 400 {
+		return nil
+	}
+	var d detail
+	if err := json.Unmarshal([]byte(verr.Detail), &d); err != nil {
+		return nil
+	}
+	return d.Violations
+}
+
+// HandlerWrapper validates the body of requests before they're handled. Streams aren't
+// validated, their messages are received by the handler.
+func HandlerWrapper() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if req.Stream() {
+				return h(ctx, req, rsp)
+			}
+			if err := Validate(req.Service(), req.Body()); err != nil {
+				return err
+			}
+			return h(ctx, req, rsp)
+		}
+	}
+}
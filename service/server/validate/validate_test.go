@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/micro/go-micro/v3/server"
+)
+
+// testFieldError mirrors the errors protoc-gen-validate generates
+type testFieldError struct {
+	field  string
+	reason string
+	cause  error
+}
+
+func (e testFieldError) Field() string  { return e.field }
+func (e testFieldError) Reason() string { return e.reason }
+func (e testFieldError) Cause() error   { return e.cause }
+func (e testFieldError) Error() string  { return fmt.Sprintf("invalid %v: %v", e.field, e.reason) }
+
+type testMultiError []error
+
+func (m testMultiError) AllErrors() []error { return m }
+func (m testMultiError) Error() string      { return fmt.Sprintf("%d errors", len(m)) }
+
+type signupRequest struct {
+	Email string
+	City  string
+}
+
+func (r *signupRequest) ValidateAll() error {
+	var errs testMultiError
+	if len(r.Email) == 0 {
+		errs = append(errs, testFieldError{field: "Email", reason: "value is required"})
+	}
+	if len(r.City) == 0 {
+		address := testMultiError{testFieldError{field: "City", reason: "value is required"}}
+		errs = append(errs, testFieldError{field: "Address", reason: "embedded message failed validation", cause: address})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type testRequest struct {
+	server.Request
+	body interface{}
+}
+
+func (r *testRequest) Service() string   { return "foo" }
+func (r *testRequest) Stream() bool      { return false }
+func (r *testRequest) Body() interface{} { return r.body }
+func (r *testRequest) Endpoint() string  { return "Foo.Signup" }
+
+func TestHandlerWrapper(t *testing.T) {
+	var handled bool
+	h := HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		handled = true
+		return nil
+	})
+
+	err := h(context.TODO(), &testRequest{body: &signupRequest{}}, nil)
+	if handled {
+		t.Errorf("Expected the invalid request not to be handled")
+	}
+	v := Violations(err)
+	if len(v) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", err)
+	}
+	if v[0].Field != "Email" || v[0].Reason != "value is required" {
+		t.Errorf("Expected the email to be required, got %+v", v[0])
+	}
+	if v[1].Field != "Address.City" {
+		t.Errorf("Expected the nested field to be reported, got %+v", v[1])
+	}
+
+	if err := h(context.TODO(), &testRequest{body: &signupRequest{Email: "a@b.com", City: "London"}}, nil); err != nil || !handled {
+		t.Errorf("Expected the valid request to be handled, got %v", err)
+	}
+
+	// messages without constraints are always handled
+	handled = false
+	if err := h(context.TODO(), &testRequest{body: struct{}{}}, nil); err != nil || !handled {
+		t.Errorf("Expected the request without constraints to be handled, got %v", err)
+	}
+}
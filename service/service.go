@@ -20,6 +20,7 @@ import (
 	"github.com/micro/micro/v3/service/logger"
 	mumodel "github.com/micro/micro/v3/service/model"
 	muserver "github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/service/server/drain"
 )
 
 var (
@@ -149,6 +150,9 @@ func (s *Service) Stop() error {
 		}
 	}
 
+	// deregister and drain the requests in flight before the server stops accepting them
+	drain.Drain()
+
 	if err := muserver.DefaultServer.Stop(); err != nil {
 		return err
 	}
@@ -0,0 +1,181 @@
+// Package bulk writes many records to a store in one call, each only if its condition is met,
+// so sync jobs can upsert a batch with optimistic concurrency in one round trip instead of
+// reading and writing each record. The result of each record is returned rather than failing
+// the batch, so conflicts can be resolved and retried individually.
+package bulk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"sync"
+
+	"github.com/micro/go-micro/v3/store"
+)
+
+var (
+	// MaxUpserts is the maximum number of records upserted in one call
+	MaxUpserts = 500
+)
+
+// Condition a record must meet to be written, checked against the record currently stored.
+// The zero value always writes the record.
+type Condition struct {
+	// NotExists requires the record not to exist
+	NotExists bool
+	// Exists requires the record to exist
+	Exists bool
+	// ETag requires the current value of the record to have the etag
+	ETag string
+}
+
+// Upsert of a record
+type Upsert struct {
+	Record    *store.Record
+	Condition Condition
+}
+
+// Result of an upsert
+type Result struct {
+	Key string
+	// Written is true if the record was written
+	Written bool
+	// Conflict is true if the condition wasn't met
+	Conflict bool
+	// Error writing the record, if any
+	Error string
+	// ETag of the value stored after the upsert
+	ETag string
+}
+
+// Upserter is implemented by stores which upsert records in bulk natively, checking the
+// conditions and writing the records atomically
+type Upserter interface {
+	BulkUpsert(upserts []*Upsert, opts ...store.WriteOption) ([]*Result, error)
+}
+
+// Deleter is implemented by stores which delete records conditionally natively, checking the
+// etag and deleting the record atomically
+type Deleter interface {
+	DeleteIf(key, etag string, opts ...store.DeleteOption) (bool, error)
+}
+
+// ETag returns the etag of a value
+func ETag(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:16])
+}
+
+// locks serialize the check and write of the same key within the process
+var locks [64]sync.Mutex
+
+func lock(database, table, key string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(database + "/" + table + "/" + key))
+	return &locks[h.Sum32()%uint32(len(locks))]
+}
+
+// Lock the key against the upserts applied by the process, returning the function which
+// unlocks it. Writes and deletes made without holding the lock can interleave with the check
+// and write of a condition, so a process which applies upserts to a store must hold the lock
+// for its other writes too.
+func Lock(database, table, key string) func() {
+	mtx := lock(database, table, key)
+	mtx.Lock()
+	return mtx.Unlock
+}
+
+// Write the upserts to the store, natively if it's an Upserter and by applying them one by one
+// otherwise. The results are in the order of the upserts.
+func Write(s store.Store, upserts []*Upsert, opts ...store.WriteOption) ([]*Result, error) {
+	if u, ok := s.(Upserter); ok {
+		return u.BulkUpsert(upserts, opts...)
+	}
+	return Apply(s, upserts, opts...), nil
+}
+
+// Apply the upserts to the store, checking the condition of each record against the record
+// stored before writing it. The results are in the order of the upserts. The check and write
+// are only serialised with the writes of the process which hold the lock of the key, so stores
+// shared by many processes must implement Upserter with the transactions of their backend.
+func Apply(s store.Store, upserts []*Upsert, opts ...store.WriteOption) []*Result {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	readOpts := []store.ReadOption{store.ReadFrom(options.Database, options.Table)}
+
+	results := make([]*Result, len(upserts))
+	for i, u := range upserts {
+		results[i] = apply(s, u, options, readOpts, opts)
+	}
+	return results
+}
+
+func apply(s store.Store, u *Upsert, options store.WriteOptions, readOpts []store.ReadOption, opts []store.WriteOption) *Result {
+	if u == nil || u.Record == nil {
+		return &Result{Error: "no record specified"}
+	}
+	res := &Result{Key: u.Record.Key}
+
+	mtx := lock(options.Database, options.Table, u.Record.Key)
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	c := u.Condition
+	if c.NotExists || c.Exists || len(c.ETag) > 0 {
+		recs, err := s.Read(u.Record.Key, readOpts...)
+		if err != nil && err != store.ErrNotFound {
+			res.Error = err.Error()
+			return res
+		}
+		exists := err == nil && len(recs) > 0
+
+		var etag string
+		if exists {
+			etag = ETag(recs[0].Value)
+		}
+		if (c.NotExists && exists) || (c.Exists && !exists) || (len(c.ETag) > 0 && c.ETag != etag) {
+			res.Conflict = true
+			res.ETag = etag
+			return res
+		}
+	}
+
+	if err := s.Write(u.Record, opts...); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Written = true
+	res.ETag = ETag(u.Record.Value)
+	return res
+}
+
+// Delete the record from the store only if its current value has the etag, natively if the
+// store is a Deleter and holding the lock of the key otherwise. It returns false if the record
+// doesn't exist or has a different value.
+func Delete(s store.Store, key, etag string, opts ...store.DeleteOption) (bool, error) {
+	if d, ok := s.(Deleter); ok {
+		return d.DeleteIf(key, etag, opts...)
+	}
+
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	defer Lock(options.Database, options.Table, key)()
+
+	recs, err := s.Read(key, store.ReadFrom(options.Database, options.Table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if ETag(recs[0].Value) != etag {
+		return false, nil
+	}
+	if err := s.Delete(key, opts...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package bulk
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+)
+
+func TestApply(t *testing.T) {
+	s := memory.NewStore()
+	s.Write(&store.Record{Key: "existing", Value: []byte("old")})
+
+	results := Apply(s, []*Upsert{
+		{Record: &store.Record{Key: "new", Value: []byte("a")}, Condition: Condition{NotExists: true}},
+		{Record: &store.Record{Key: "existing", Value: []byte("b")}, Condition: Condition{NotExists: true}},
+		{Record: &store.Record{Key: "missing", Value: []byte("c")}, Condition: Condition{Exists: true}},
+		{Record: &store.Record{Key: "existing", Value: []byte("d")}, Condition: Condition{ETag: "stale"}},
+		{Record: &store.Record{Key: "existing", Value: []byte("e")}, Condition: Condition{ETag: ETag([]byte("old"))}},
+		{Record: &store.Record{Key: "any", Value: []byte("f")}},
+		nil,
+	})
+
+	tt := []struct {
+		Written  bool
+		Conflict bool
+		Error    bool
+	}{
+		{Written: true},
+		{Conflict: true},
+		{Conflict: true},
+		{Conflict: true},
+		{Written: true},
+		{Written: true},
+		{Error: true},
+	}
+	if len(results) != len(tt) {
+		t.Fatalf("Expected %v results, got %v", len(tt), len(results))
+	}
+	for i, tc := range tt {
+		r := results[i]
+		if r.Written != tc.Written || r.Conflict != tc.Conflict || (len(r.Error) > 0) != tc.Error {
+			t.Errorf("Unexpected result %v: %+v", i, r)
+		}
+	}
+
+	// a conflict returns the etag of the current value to retry with
+	if results[3].ETag != ETag([]byte("old")) {
+		t.Errorf("Expected the conflict to return the current etag")
+	}
+	recs, err := s.Read("existing")
+	if err != nil || string(recs[0].Value) != "e" {
+		t.Errorf("Expected the existing record to be updated once its etag matched")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s := memory.NewStore()
+	s.Write(&store.Record{Key: "foo", Value: []byte("new")})
+
+	if ok, err := Delete(s, "foo", ETag([]byte("old"))); err != nil || ok {
+		t.Errorf("Expected a record with a different value not to be deleted, got %v %v", ok, err)
+	}
+	if ok, err := Delete(s, "bar", ETag([]byte("old"))); err != nil || ok {
+		t.Errorf("Expected a missing record not to be deleted, got %v %v", ok, err)
+	}
+	if ok, err := Delete(s, "foo", ETag([]byte("new"))); err != nil || !ok {
+		t.Errorf("Expected the record to be deleted, got %v %v", ok, err)
+	}
+	if _, err := s.Read("foo"); err != store.ErrNotFound {
+		t.Errorf("Expected the record to be deleted, got %v", err)
+	}
+}
@@ -2,7 +2,10 @@
 // for example:
 //   micro store snapshot
 //   micro store restore
+//   micro store trash
 //   micro store sync
+//   micro store migrate
+//   micro store reshard
 package cli
 
 import (
@@ -168,6 +171,29 @@ func init() {
 					},
 				},
 			},
+			{
+				Name:   "stats",
+				Usage:  "Show the usage statistics and slow operations of a table, e.g. micro store stats users",
+				Action: stats,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "store",
+						Usage: "store service to call",
+						Value: "store",
+					},
+					&cli.StringFlag{
+						Name:    "table",
+						Aliases: []string{"t"},
+						Usage:   "table to show the stats of, if not passed as an argument",
+						Value:   "micro",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "output format (json, table)",
+						Value: "table",
+					},
+				},
+			},
 			{
 				Name:   "snapshot",
 				Usage:  "Back up a store",
@@ -188,9 +214,29 @@ func init() {
 				Flags:  SyncFlags,
 			},
 			{
-				Name:   "restore",
-				Usage:  "restore a store snapshot",
-				Action: restore,
+				Name:      "migrate",
+				Usage:     "Migrate a table to another backend, verifying the records once copied",
+				UsageText: `micro store migrate --from=file --to=cockroach --table=users`,
+				Action:    migrateStore,
+				Flags:     MigrateFlags,
+			},
+			{
+				Name:      "reshard",
+				Usage:     "Move the keys of a sharded store to a new set of shards",
+				UsageText: `micro --store_shards=a=10.0.0.1:8002,b=10.0.0.2:8002 store reshard a=10.0.0.1:8002,b=10.0.0.2:8002,c=10.0.0.3:8002`,
+				Action:    reshard,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "resume",
+						Usage: "Resume moving the keys of a resharding which didn't finish",
+					},
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "restore a deleted record from the trash, or a store snapshot",
+				UsageText: `micro store restore [options] [key]`,
+				Action:    restore,
 				Flags: append(CommonFlags,
 					&cli.StringFlag{
 						Name:  "source",
@@ -199,6 +245,45 @@ func init() {
 					},
 				),
 			},
+			{
+				Name:      "trash",
+				Usage:     "list the deleted records which can be restored",
+				UsageText: `micro store trash [options] [prefix]`,
+				Action:    trashList,
+				Flags: append(TrashFlags,
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "output format (json, table)",
+						Value: "table",
+					},
+				),
+			},
+			{
+				Name:      "purge",
+				Usage:     "permanently delete a record from the trash",
+				UsageText: `micro store purge [options] key`,
+				Action:    purge,
+				Flags: append(TrashFlags,
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "purge every record in the trash of the table",
+					},
+				),
+			},
+			{
+				Name:      "protect",
+				Usage:     "protect a table from deletes",
+				UsageText: `micro store protect [options] [table]`,
+				Action:    protect(true),
+				Flags:     TrashFlags,
+			},
+			{
+				Name:      "unprotect",
+				Usage:     "remove the delete protection of a table",
+				UsageText: `micro store unprotect [options] [table]`,
+				Action:    protect(false),
+				Flags:     TrashFlags,
+			},
 		},
 	})
 }
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/micro/cli/v2"
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/store/migrate"
+	"github.com/pkg/errors"
+)
+
+// migrateStore is the entrypoint for micro store migrate
+func migrateStore(ctx *cli.Context) error {
+	if len(ctx.String("from")) == 0 || len(ctx.String("to")) == 0 {
+		return errors.New("the from and to backends must be set")
+	}
+	if ctx.String("from") == ctx.String("to") && ctx.String("from-nodes") == ctx.String("to-nodes") {
+		return errors.New("the from and to stores must differ")
+	}
+
+	from, err := migrateBackend(ctx, ctx.String("from"), ctx.String("from-nodes"))
+	if err != nil {
+		return errors.Wrap(err, "from store")
+	}
+	defer from.Close()
+	to, err := migrateBackend(ctx, ctx.String("to"), ctx.String("to-nodes"))
+	if err != nil {
+		return errors.Wrap(err, "to store")
+	}
+	defer to.Close()
+
+	start := time.Now()
+	fmt.Printf("Migrating %s/%s from %s to %s\n", ctx.String("database"), ctx.String("table"), from.String(), to.String())
+	sums, err := migrate.Migrate(from, to, migrate.Progress(func(copied, total int) {
+		if copied == total || copied%100 == 0 {
+			fmt.Printf("\rCopied %d/%d records", copied, total)
+		}
+	}))
+	if err != nil {
+		fmt.Println()
+		return err
+	}
+	fmt.Printf("\nCopied %d records in %v\n", len(sums), time.Since(start).Round(time.Millisecond))
+
+	// keep the destination in sync while the services are switched over
+	if window := ctx.Duration("dual-write"); window > 0 {
+		fmt.Printf("Mirroring writes to %s for %v, press ctrl+c to stop early\n", to.String(), window)
+
+		mctx, cancel := context.WithTimeout(context.Background(), window)
+		defer cancel()
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sig)
+		go func() {
+			select {
+			case <-sig:
+				cancel()
+			case <-mctx.Done():
+			}
+		}()
+
+		if err := migrate.Mirror(mctx, from, to, sums, migrate.Interval(ctx.Duration("interval"))); err != nil {
+			return err
+		}
+	}
+
+	if !ctx.Bool("verify") {
+		return nil
+	}
+	diff, err := migrate.Verify(from, to)
+	if err != nil {
+		return errors.Wrap(err, "couldn't verify the migration")
+	}
+	if len(diff) > 0 {
+		return errors.Errorf("%d records differ between the stores: %s", len(diff), strings.Join(diff, ", "))
+	}
+	fmt.Println("Verified the checksums of all records match")
+	return nil
+}
+
+// migrateBackend creates the store of the backend for the database and table migrated
+func migrateBackend(ctx *cli.Context, backend, nodes string) (store.Store, error) {
+	builder, err := getStore(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []store.Option{
+		store.Database(ctx.String("database")),
+		store.Table(ctx.String("table")),
+	}
+	if len(nodes) > 0 {
+		opts = append(opts, store.Nodes(strings.Split(nodes, ",")...))
+	}
+
+	s := builder(opts...)
+	if err := s.Init(); err != nil {
+		return nil, errors.Wrapf(err, "couldn't init %s", backend)
+	}
+	return s, nil
+}
+
+// MigrateFlags are the flags for micro store migrate
+var MigrateFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "from",
+		Usage: "Backend to migrate from, e.g. file",
+	},
+	&cli.StringFlag{
+		Name:  "from-nodes",
+		Usage: "Comma separated list of nodes of the backend to migrate from",
+	},
+	&cli.StringFlag{
+		Name:  "to",
+		Usage: "Backend to migrate to, e.g. cockroach",
+	},
+	&cli.StringFlag{
+		Name:  "to-nodes",
+		Usage: "Comma separated list of nodes of the backend to migrate to",
+	},
+	&cli.StringFlag{
+		Name:  "database",
+		Usage: "Database to migrate",
+		Value: "micro",
+	},
+	&cli.StringFlag{
+		Name:  "table",
+		Usage: "Table to migrate",
+		Value: "micro",
+	},
+	&cli.BoolFlag{
+		Name:  "verify",
+		Usage: "Verify the checksums of the records match once migrated",
+		Value: true,
+	},
+	&cli.DurationFlag{
+		Name:  "dual-write",
+		Usage: "How long to keep copying writes to the source to the destination once migrated, so services can be switched over",
+	},
+	&cli.DurationFlag{
+		Name:  "interval",
+		Usage: "How often the source is checked for writes during the dual-write window",
+		Value: migrate.DefaultInterval,
+	},
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/micro/cli/v2"
+	"github.com/micro/go-micro/v3/store"
+	mustore "github.com/micro/micro/v3/service/store"
+	storeClient "github.com/micro/micro/v3/service/store/client"
+	"github.com/micro/micro/v3/service/store/shard"
+	"github.com/pkg/errors"
+)
+
+// reshard is the entrypoint for micro store reshard. The store must be sharded with the
+// --store_shards flag, the keys are moved from the shards of the ring recorded in them.
+func reshard(ctx *cli.Context) error {
+	s, ok := mustore.DefaultStore.(*shard.Store)
+	if !ok {
+		return errors.New("the store isn't sharded, pass the current shards with --store_shards")
+	}
+
+	progress := func(moved int) {
+		if moved%1000 == 0 {
+			fmt.Printf("Moved %d keys\n", moved)
+		}
+	}
+
+	if ctx.Bool("resume") {
+		if err := s.ResumeReshard(progress); err != nil {
+			return errors.Wrap(err, "failed to resume resharding")
+		}
+	} else {
+		if ctx.Args().Len() == 0 {
+			return errors.New("Required usage: micro --store_shards=a=addr,b=addr store reshard a=addr,b=addr,c=addr")
+		}
+		dial := func(address string) store.Store { return storeClient.NewStore(store.Nodes(address)) }
+		shards, err := shard.ParseShards(ctx.Args().First(), dial)
+		if err != nil {
+			return err
+		}
+		for _, sh := range shards {
+			if err := sh.Store.Init(); err != nil {
+				return errors.Wrapf(err, "couldn't init shard %v", sh.Name)
+			}
+		}
+		if err := s.Reshard(shards, progress); err != nil {
+			return errors.Wrap(err, "failed to reshard")
+		}
+	}
+
+	version, _ := s.Version()
+	fmt.Printf("Resharded, the ring is at version %d\n", version)
+	return nil
+}
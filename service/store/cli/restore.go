@@ -9,8 +9,13 @@ import (
 	"github.com/pkg/errors"
 )
 
-// restore is the entrypoint for micro store restore
+// restore is the entrypoint for micro store restore, which restores the record with the key
+// from the trash or, without a key, the snapshot at the source
 func restore(ctx *cli.Context) error {
+	if key := ctx.Args().First(); len(key) > 0 {
+		return restoreKey(ctx, key)
+	}
+
 	s, err := makeStore(ctx)
 	if err != nil {
 		return errors.Wrap(err, "couldn't construct a store")
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/store/proto"
+	"github.com/pkg/errors"
+)
+
+// stats is the entrypoint for micro store stats
+func stats(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	table := ctx.Args().First()
+	if len(table) == 0 {
+		table = ctx.String("table")
+	}
+
+	sReq := client.NewRequest(ctx.String("store"), "Store.Stats", &pb.StatsRequest{
+		Database: ns,
+		Table:    table,
+	})
+	sRsp := &pb.StatsResponse{}
+	if err := client.Call(context.DefaultContext, sReq, sRsp, goclient.WithAuthToken()); err != nil {
+		return err
+	}
+
+	if ctx.String("output") == "json" {
+		b, err := json.MarshalIndent(sRsp, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed marshalling JSON")
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	fmt.Printf("Table %v/%v, collected since %v\n\n", ns, table, humanize.Time(time.Unix(sRsp.Since, 0)))
+	fmt.Printf("Records:  %d\n", sRsp.Records)
+	fmt.Printf("Key size: %d bytes avg, %d bytes max\n\n", sRsp.AvgKeySize, sRsp.MaxKeySize)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tCOUNT\tERRORS\tP50\tP95\tP99")
+	for _, o := range sRsp.Operations {
+		fmt.Fprintf(w, "%v\t%d\t%d\t%v\t%v\t%v\n", o.Operation, o.Count, o.Errors,
+			time.Duration(o.P50), time.Duration(o.P95), time.Duration(o.P99))
+	}
+	w.Flush()
+
+	if len(sRsp.HotKeys) > 0 {
+		fmt.Println("\nHot keys:")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, k := range sRsp.HotKeys {
+			fmt.Fprintf(w, "  %v\t%d\n", k.Key, k.Count)
+		}
+		w.Flush()
+	}
+
+	if len(sRsp.SlowOperations) > 0 {
+		fmt.Println("\nSlow operations:")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, o := range sRsp.SlowOperations {
+			fmt.Fprintf(w, "  %v\t%v\t%v\t%v\t%v\n", time.Unix(o.Timestamp, 0).Format(time.RFC3339),
+				o.Operation, o.Key, time.Duration(o.Duration), o.Error)
+		}
+		w.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/store/proto"
+	"github.com/pkg/errors"
+)
+
+// storeService is the name of the store service the trash is managed by
+const storeService = "store"
+
+// TrashFlags are the flags of the trash commands
+var TrashFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "store",
+		Usage: "store service to call",
+		Value: storeService,
+	},
+	&cli.StringFlag{
+		Name:    "table",
+		Aliases: []string{"t"},
+		Usage:   "table of the records",
+		Value:   "micro",
+	},
+}
+
+// trashList is the entrypoint for micro store trash
+func trashList(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	req := client.NewRequest(ctx.String("store"), "Store.Trash", &pb.TrashRequest{
+		Database: ns,
+		Table:    ctx.String("table"),
+		Prefix:   ctx.Args().First(),
+	})
+	rsp := &pb.TrashResponse{}
+	if err := client.Call(context.DefaultContext, req, rsp, goclient.WithAuthToken()); err != nil {
+		return err
+	}
+
+	if ctx.String("output") == "json" {
+		b, err := json.MarshalIndent(rsp.Records, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed marshalling JSON")
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tDELETED\tPURGED")
+	for _, r := range rsp.Records {
+		purged := "never"
+		if r.Expires > 0 {
+			purged = humanize.Time(time.Unix(r.Expires, 0))
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\n", r.Key, humanize.Time(time.Unix(r.Deleted, 0)), purged)
+	}
+	return w.Flush()
+}
+
+// restoreKey restores a deleted record from the trash
+func restoreKey(ctx *cli.Context, key string) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	req := client.NewRequest(storeService, "Store.Restore", &pb.RestoreRequest{
+		Database: ns,
+		Table:    ctx.String("table"),
+		Key:      key,
+	})
+	if err := client.Call(context.DefaultContext, req, &pb.RestoreResponse{}, goclient.WithAuthToken()); err != nil {
+		return errors.Wrapf(err, "couldn't restore key %s", key)
+	}
+	return nil
+}
+
+// purge is the entrypoint for micro store purge
+func purge(ctx *cli.Context) error {
+	key := ctx.Args().First()
+	if len(key) == 0 && !ctx.Bool("all") {
+		return errors.New("key is required, or --all to purge the whole trash")
+	}
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	req := client.NewRequest(ctx.String("store"), "Store.Purge", &pb.PurgeRequest{
+		Database: ns,
+		Table:    ctx.String("table"),
+		Key:      key,
+		All:      ctx.Bool("all"),
+	})
+	rsp := &pb.PurgeResponse{}
+	if err := client.Call(context.DefaultContext, req, rsp, goclient.WithAuthToken()); err != nil {
+		return err
+	}
+	fmt.Printf("Purged %d records\n", rsp.Purged)
+	return nil
+}
+
+// protect is the entrypoint for micro store protect and unprotect
+func protect(protected bool) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		ns, err := namespace.Get(util.GetEnv(ctx).Name)
+		if err != nil {
+			return err
+		}
+
+		table := ctx.Args().First()
+		if len(table) == 0 {
+			table = ctx.String("table")
+		}
+
+		req := client.NewRequest(ctx.String("store"), "Store.Protect", &pb.ProtectRequest{
+			Database:  ns,
+			Table:     table,
+			Protected: protected,
+		})
+		return client.Call(context.DefaultContext, req, &pb.ProtectResponse{}, goclient.WithAuthToken())
+	}
+}
@@ -5,6 +5,10 @@ import (
 
 	"github.com/micro/cli/v2"
 	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/cockroach"
+	"github.com/micro/go-micro/v3/store/file"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/store/client"
 	"github.com/pkg/errors"
 )
 
@@ -54,11 +58,18 @@ func makeStores(ctx *cli.Context) (store.Store, store.Store, error) {
 	return from, to, nil
 }
 
+// stores are the backends which can be passed to the store commands
+var stores = map[string]func(...store.Option) store.Store{
+	"memory":    memory.NewStore,
+	"file":      file.NewStore,
+	"cockroach": cockroach.NewStore,
+	"service":   client.NewStore,
+}
+
 func getStore(s string) (func(...store.Option) store.Store, error) {
-	// builtinStore, exists := cmd.DefaultStores[s]
-	// if !exists {
-	// 	return nil, errors.Errorf("store %s is not an implemented store - check your plugins", s)
-	// }
-	// return builtinStore, nil
-	return nil, nil
+	builtinStore, exists := stores[s]
+	if !exists {
+		return nil, errors.Errorf("store %s is not an implemented store", s)
+	}
+	return builtinStore, nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store/bulk"
 	pb "github.com/micro/micro/v3/service/store/proto"
 )
 
@@ -201,6 +202,71 @@ func (s *srv) Write(record *store.Record, opts ...store.WriteOption) error {
 	return err
 }
 
+// BulkUpsert writes the records whose conditions are met in one call to the store service
+func (s *srv) BulkUpsert(upserts []*bulk.Upsert, opts ...store.WriteOption) ([]*bulk.Result, error) {
+	options := store.WriteOptions{
+		Database: s.Database,
+		Table:    s.Table,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	req := &pb.BulkUpsertRequest{
+		Upserts: make([]*pb.Upsert, len(upserts)),
+		Options: &pb.WriteOptions{
+			Database: options.Database,
+			Table:    options.Table,
+		},
+	}
+	for i, u := range upserts {
+		if u == nil || u.Record == nil {
+			req.Upserts[i] = &pb.Upsert{}
+			continue
+		}
+
+		metadata := make(map[string]*pb.Field)
+		for k, v := range u.Record.Metadata {
+			metadata[k] = &pb.Field{
+				Type:  reflect.TypeOf(v).String(),
+				Value: fmt.Sprintf("%v", v),
+			}
+		}
+
+		req.Upserts[i] = &pb.Upsert{
+			Record: &pb.Record{
+				Key:      u.Record.Key,
+				Value:    u.Record.Value,
+				Expiry:   int64(u.Record.Expiry.Seconds()),
+				Metadata: metadata,
+			},
+			Condition: &pb.Condition{
+				NotExists: u.Condition.NotExists,
+				Exists:    u.Condition.Exists,
+				Etag:      u.Condition.ETag,
+			},
+		}
+	}
+
+	rsp, err := s.Client.BulkUpsert(s.Context(), req, goclient.WithAddress(s.Nodes...), goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*bulk.Result, len(rsp.Results))
+	for i, r := range rsp.Results {
+		results[i] = &bulk.Result{
+			Key:      r.Key,
+			Written:  r.Written,
+			Conflict: r.Conflict,
+			Error:    r.Error,
+			ETag:     r.Etag,
+		}
+	}
+	return results, nil
+}
+
 // Delete a record with key
 func (s *srv) Delete(key string, opts ...store.DeleteOption) error {
 	options := store.DeleteOptions{
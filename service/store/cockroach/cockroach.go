@@ -0,0 +1,277 @@
+// Package cockroach is the cockroach store, with conditional writes checked and applied in a
+// transaction of the database. The locks bulk.Apply takes only serialise the writes of one
+// process, which doesn't protect a store shared by many replicas of the store service.
+package cockroach
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/cockroach"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+var (
+	// DefaultDatabase and DefaultTable are used if the options don't set them, matching the
+	// cockroach store
+	DefaultDatabase = cockroach.DefaultDatabase
+	DefaultTable    = cockroach.DefaultTable
+	// DefaultNode is the database connected to if the options don't set one
+	DefaultNode = "postgresql://root@localhost:26257?sslmode=disable"
+	// MaxRetries is the number of times a transaction is retried when it conflicts with another
+	MaxRetries = 5
+)
+
+// the cockroach store replaces the characters tables can't contain in their names
+var re = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// serializationFailure is the code of the error returned when a transaction must be retried
+const serializationFailure = "40001"
+
+// NewStore returns a cockroach store which implements bulk.Upserter and bulk.Deleter
+func NewStore(opts ...store.Option) store.Store {
+	options := store.Options{Database: DefaultDatabase, Table: DefaultTable}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &cockroachStore{Store: cockroach.NewStore(opts...), options: options}
+}
+
+type cockroachStore struct {
+	store.Store
+	options store.Options
+
+	sync.Mutex
+	db *sql.DB
+}
+
+func (s *cockroachStore) Init(opts ...store.Option) error {
+	s.Lock()
+	for _, o := range opts {
+		o(&s.options)
+	}
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+	s.Unlock()
+
+	return s.Store.Init(opts...)
+}
+
+func (s *cockroachStore) Close() error {
+	s.Lock()
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+	s.Unlock()
+
+	return s.Store.Close()
+}
+
+// conn returns the connection to the database, opening it on first use
+func (s *cockroachStore) conn() (*sql.DB, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.db != nil {
+		return s.db, nil
+	}
+
+	source := DefaultNode
+	if len(s.options.Nodes) > 0 {
+		source = s.options.Nodes[0]
+	}
+	// connection strings which aren't URLs are in the format host=%s port=%d
+	if _, err := url.Parse(source); err != nil && !strings.Contains(source, " ") {
+		source = fmt.Sprintf("host=%s", source)
+	}
+
+	db, err := sql.Open("postgres", source)
+	if err != nil {
+		return nil, err
+	}
+	s.db = db
+	return db, nil
+}
+
+// table returns the name of the table of the records, as the cockroach store names it
+func (s *cockroachStore) table(database, table string) string {
+	if len(database) == 0 {
+		database = s.options.Database
+	}
+	if len(table) == 0 {
+		table = s.options.Table
+	}
+	return re.ReplaceAllString(database, "_") + "." + re.ReplaceAllString(table, "_")
+}
+
+// BulkUpsert writes the records whose conditions are met in one transaction. The records the
+// conditions are checked against are locked until the transaction commits, so a concurrent
+// write by any client of the database can't interleave with the check and write.
+func (s *cockroachStore) BulkUpsert(upserts []*bulk.Upsert, opts ...store.WriteOption) ([]*bulk.Result, error) {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// the cockroach store creates the table when it's first used
+	if _, err := s.Store.List(store.ListFrom(options.Database, options.Table), store.ListLimit(1)); err != nil {
+		return nil, err
+	}
+
+	db, err := s.conn()
+	if err != nil {
+		return nil, err
+	}
+	table := s.table(options.Database, options.Table)
+
+	for i := 0; ; i++ {
+		results, err := upsertAll(db, table, upserts)
+		if retryable(err) && i < MaxRetries {
+			continue
+		}
+		return results, err
+	}
+}
+
+// DeleteIf deletes the record in a transaction only if its current value has the etag. The
+// record is locked between the check and the delete, so a concurrent write can't be lost.
+func (s *cockroachStore) DeleteIf(key, etag string, opts ...store.DeleteOption) (bool, error) {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// the cockroach store creates the table when it's first used
+	if _, err := s.Store.List(store.ListFrom(options.Database, options.Table), store.ListLimit(1)); err != nil {
+		return false, err
+	}
+
+	db, err := s.conn()
+	if err != nil {
+		return false, err
+	}
+	table := s.table(options.Database, options.Table)
+
+	for i := 0; ; i++ {
+		deleted, err := deleteIf(db, table, key, etag)
+		if retryable(err) && i < MaxRetries {
+			continue
+		}
+		return deleted, err
+	}
+}
+
+// retryable returns true if the transaction failed because it conflicted with another
+func retryable(err error) bool {
+	pqerr, ok := err.(*pq.Error)
+	return ok && pqerr.Code == serializationFailure
+}
+
+func upsertAll(db *sql.DB, table string, upserts []*bulk.Upsert) ([]*bulk.Result, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]*bulk.Result, len(upserts))
+	for i, u := range upserts {
+		if results[i], err = upsert(tx, table, u); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func upsert(tx *sql.Tx, table string, u *bulk.Upsert) (*bulk.Result, error) {
+	if u == nil || u.Record == nil {
+		return &bulk.Result{Error: "no record specified"}, nil
+	}
+	res := &bulk.Result{Key: u.Record.Key}
+
+	c := u.Condition
+	if c.NotExists || c.Exists || len(c.ETag) > 0 {
+		var value []byte
+		var expiry pq.NullTime
+		query := fmt.Sprintf("SELECT value, expiry FROM %s WHERE key = $1 FOR UPDATE;", table)
+		err := tx.QueryRow(query, u.Record.Key).Scan(&value, &expiry)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		// expired records are deleted when they're read, until then they don't exist
+		exists := err == nil && (!expiry.Valid || expiry.Time.After(time.Now()))
+
+		var etag string
+		if exists {
+			etag = bulk.ETag(value)
+		}
+		if (c.NotExists && exists) || (c.Exists && !exists) || (len(c.ETag) > 0 && c.ETag != etag) {
+			res.Conflict = true
+			res.ETag = etag
+			return res, nil
+		}
+	}
+
+	metadata := make(cockroach.Metadata, len(u.Record.Metadata))
+	for k, v := range u.Record.Metadata {
+		metadata[k] = v
+	}
+	var expiry interface{}
+	if u.Record.Expiry != 0 {
+		expiry = time.Now().Add(u.Record.Expiry)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s(key, value, metadata, expiry) VALUES ($1, $2::bytea, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry;`, table)
+	if _, err := tx.Exec(query, u.Record.Key, u.Record.Value, metadata, expiry); err != nil {
+		return nil, err
+	}
+
+	res.Written = true
+	res.ETag = bulk.ETag(u.Record.Value)
+	return res, nil
+}
+
+func deleteIf(db *sql.DB, table, key, etag string) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var value []byte
+	var expiry pq.NullTime
+	query := fmt.Sprintf("SELECT value, expiry FROM %s WHERE key = $1 FOR UPDATE;", table)
+	err = tx.QueryRow(query, key).Scan(&value, &expiry)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if (expiry.Valid && !expiry.Time.After(time.Now())) || bulk.ETag(value) != etag {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE key = $1;", table), key); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
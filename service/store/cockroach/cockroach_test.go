@@ -0,0 +1,72 @@
+package cockroach
+
+import (
+	"os"
+	"testing"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+func TestBulkUpsert(t *testing.T) {
+	addr := os.Getenv("MICRO_STORE_ADDRESS")
+	if len(addr) == 0 {
+		t.Skip("MICRO_STORE_ADDRESS isn't set")
+	}
+
+	s := NewStore(store.Nodes(addr), store.Table("bulk_test"))
+	defer s.Close()
+	defer s.Delete("foo")
+	u := s.(bulk.Upserter)
+
+	upsert := func(value string, cond bulk.Condition) *bulk.Result {
+		results, err := u.BulkUpsert([]*bulk.Upsert{{Record: &store.Record{Key: "foo", Value: []byte(value)}, Condition: cond}})
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Expected one result, got %v: %v", results, err)
+		}
+		return results[0]
+	}
+
+	if res := upsert("1", bulk.Condition{Exists: true}); !res.Conflict {
+		t.Errorf("Expected a conflict updating a record which doesn't exist")
+	}
+	if res := upsert("1", bulk.Condition{NotExists: true}); !res.Written {
+		t.Fatalf("Expected the record to be created, got %v", res.Error)
+	}
+	if res := upsert("2", bulk.Condition{NotExists: true}); !res.Conflict {
+		t.Errorf("Expected a conflict creating a record which exists")
+	}
+	if res := upsert("2", bulk.Condition{ETag: bulk.ETag([]byte("0"))}); !res.Conflict || res.ETag != bulk.ETag([]byte("1")) {
+		t.Errorf("Expected a conflict with the etag of the record, got %v", res)
+	}
+	if res := upsert("2", bulk.Condition{ETag: bulk.ETag([]byte("1"))}); !res.Written {
+		t.Errorf("Expected the record to be updated, got %v", res.Error)
+	}
+
+	recs, err := s.Read("foo")
+	if err != nil || len(recs) != 1 || string(recs[0].Value) != "2" {
+		t.Errorf("Expected the record to be updated, got %v: %v", recs, err)
+	}
+}
+
+func TestDeleteIf(t *testing.T) {
+	addr := os.Getenv("MICRO_STORE_ADDRESS")
+	if len(addr) == 0 {
+		t.Skip("MICRO_STORE_ADDRESS isn't set")
+	}
+
+	s := NewStore(store.Nodes(addr), store.Table("bulk_test"))
+	defer s.Close()
+	defer s.Delete("foo")
+	d := s.(bulk.Deleter)
+
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := d.DeleteIf("foo", bulk.ETag([]byte("1"))); err != nil || ok {
+		t.Errorf("Expected a record with a different value not to be deleted, got %v %v", ok, err)
+	}
+	if ok, err := d.DeleteIf("foo", bulk.ETag([]byte("2"))); err != nil || !ok {
+		t.Errorf("Expected the record to be deleted, got %v %v", ok, err)
+	}
+}
@@ -0,0 +1,439 @@
+// Package memory is an in-memory store which persists to disk. Writes are appended to a write
+// ahead log and the store is periodically snapshotted, so it's restored on restart without
+// needing a database, e.g. on edge nodes and in integration tests. Each database and table the
+// store is initialised with has its own directory, so services don't share snapshots.
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	mem "github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/pkg/errors"
+)
+
+var (
+	// DefaultDir is the directory the snapshot and write ahead log of each database and table
+	// are stored in a directory of
+	DefaultDir = defaultDir()
+	// DefaultSnapshotInterval is how often the store is snapshotted and the log truncated
+	DefaultSnapshotInterval = time.Minute
+)
+
+const (
+	snapshotFile = "snapshot"
+	walFile      = "wal"
+)
+
+type dirKey struct{}
+
+// Dir sets the directory the snapshot and write ahead log are stored in, rather than the
+// directory of the database and table in DefaultDir
+func Dir(dir string) store.Option {
+	return func(o *store.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, dirKey{}, dir)
+	}
+}
+
+type snapshotIntervalKey struct{}
+
+// SnapshotInterval sets how often the store is snapshotted. Zero disables periodic snapshots,
+// in which case the log is only compacted when the store is opened and closed.
+func SnapshotInterval(d time.Duration) store.Option {
+	return func(o *store.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+
+		o.Context = context.WithValue(o.Context, snapshotIntervalKey{}, d)
+	}
+}
+
+// NewStore returns a memory store which is restored from the snapshot and write ahead log in
+// its directory. The store isn't persisted until it's initialised with a table or the Dir
+// option, the records written before then are persisted once it is.
+func NewStore(opts ...store.Option) store.Store {
+	s := &memoryStore{Store: mem.NewStore(), tables: make(map[table]bool)}
+	if err := s.Init(opts...); err != nil {
+		logger.Errorf("Error restoring the memory store: %v", err)
+	}
+	return s
+}
+
+type memoryStore struct {
+	store.Store
+
+	// the mutex serialises writes to the log with snapshots
+	sync.Mutex
+	dir    string
+	wal    *os.File
+	tables map[table]bool
+	exit   chan bool
+}
+
+// table is a database and table records have been written to
+type table struct {
+	Database string
+	Table    string
+}
+
+// entry is a write or delete in the log, or a record in the snapshot
+type entry struct {
+	Delete    bool                   `json:"delete,omitempty"`
+	Database  string                 `json:"database"`
+	Table     string                 `json:"table"`
+	Key       string                 `json:"key"`
+	Value     []byte                 `json:"value,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+func (m *memoryStore) Init(opts ...store.Option) error {
+	if err := m.Store.Init(opts...); err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	// the default table isn't persisted, so stores which haven't been given a table don't share
+	// the directory of the default table
+	var set store.Options
+	for _, o := range opts {
+		o(&set)
+	}
+
+	var dir string
+	interval := DefaultSnapshotInterval
+	options := m.Store.Options()
+	if len(set.Table) > 0 || len(m.dir) > 0 {
+		dir = filepath.Join(DefaultDir, options.Database, options.Table)
+	}
+	if ctx := options.Context; ctx != nil {
+		if d, ok := ctx.Value(dirKey{}).(string); ok && len(d) > 0 {
+			dir = d
+		}
+		if i, ok := ctx.Value(snapshotIntervalKey{}).(time.Duration); ok {
+			interval = i
+		}
+	}
+
+	// the store isn't persisted until its directory is known
+	if len(dir) == 0 {
+		return nil
+	}
+	// the store is already open, e.g. Init is called again with the same options
+	if m.wal != nil && m.dir == dir {
+		return nil
+	}
+	if m.wal != nil {
+		// the records restored from the previous dir are discarded
+		if err := m.close(); err != nil {
+			return err
+		}
+		o := m.Store.Options()
+		m.Store.Close()
+		m.Store = mem.NewStore(store.Nodes(o.Nodes...), store.Database(o.Database), store.Table(o.Table), store.WithContext(o.Context))
+		m.tables = make(map[table]bool)
+	}
+
+	m.dir = dir
+	if err := m.restore(); err != nil {
+		return err
+	}
+
+	// compact the log into a new snapshot now it's been restored
+	if err := m.snapshot(); err != nil {
+		return err
+	}
+
+	if interval > 0 {
+		m.exit = make(chan bool)
+		go m.run(interval, m.exit)
+	}
+	return nil
+}
+
+func (m *memoryStore) Write(r *store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	e := entry{
+		Database: options.Database,
+		Table:    options.Table,
+		Key:      r.Key,
+		Value:    r.Value,
+		Metadata: r.Metadata,
+	}
+	if r.Expiry > 0 {
+		e.ExpiresAt = time.Now().Add(r.Expiry)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.append(&e); err != nil {
+		return err
+	}
+	return m.Store.Write(r, store.WriteTo(e.Database, e.Table))
+}
+
+func (m *memoryStore) Delete(key string, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	e := entry{
+		Delete:   true,
+		Database: options.Database,
+		Table:    options.Table,
+		Key:      key,
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.append(&e); err != nil {
+		return err
+	}
+	return m.Store.Delete(key, store.DeleteFrom(e.Database, e.Table))
+}
+
+// Close snapshots the store so it's restored without replaying the log
+func (m *memoryStore) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.wal != nil {
+		if err := m.snapshot(); err != nil {
+			return err
+		}
+	}
+	if err := m.close(); err != nil {
+		return err
+	}
+	return m.Store.Close()
+}
+
+func (m *memoryStore) String() string {
+	return "memory"
+}
+
+// run snapshots the store on the interval until it's closed
+func (m *memoryStore) run(interval time.Duration, exit chan bool) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+			m.Lock()
+			if err := m.snapshot(); err != nil {
+				logger.Errorf("Error snapshotting the memory store: %v", err)
+			}
+			m.Unlock()
+		}
+	}
+}
+
+// close stops the snapshots and closes the log
+func (m *memoryStore) close() error {
+	if m.exit != nil {
+		close(m.exit)
+		m.exit = nil
+	}
+	if m.wal == nil {
+		return nil
+	}
+	err := m.wal.Close()
+	m.wal = nil
+	return err
+}
+
+// resolve sets the database and table of the entry to the defaults if blank and records the
+// table so it's included in snapshots
+func (m *memoryStore) resolve(e *entry) {
+	opts := m.Store.Options()
+	if len(e.Database) == 0 {
+		e.Database = opts.Database
+	}
+	if len(e.Table) == 0 {
+		e.Table = opts.Table
+	}
+	m.tables[table{e.Database, e.Table}] = true
+}
+
+// append the entry to the log, syncing it to disk before the write is applied. Entries written
+// before the store is persisted are only recorded in the snapshot taken once it is.
+func (m *memoryStore) append(e *entry) error {
+	m.resolve(e)
+	if len(m.dir) == 0 {
+		return nil
+	}
+	if m.wal == nil {
+		return errors.New("store is closed")
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "couldn't encode record")
+	}
+	if _, err := m.wal.Write(append(b, '\n')); err != nil {
+		return errors.Wrap(err, "couldn't write to log")
+	}
+	return m.wal.Sync()
+}
+
+// apply an entry read from the snapshot or log to the store
+func (m *memoryStore) apply(e *entry) error {
+	m.resolve(e)
+
+	if e.Delete {
+		return m.Store.Delete(e.Key, store.DeleteFrom(e.Database, e.Table))
+	}
+
+	r := &store.Record{Key: e.Key, Value: e.Value, Metadata: e.Metadata}
+	if !e.ExpiresAt.IsZero() {
+		if r.Expiry = time.Until(e.ExpiresAt); r.Expiry <= 0 {
+			return m.Store.Delete(e.Key, store.DeleteFrom(e.Database, e.Table))
+		}
+	}
+	return m.Store.Write(r, store.WriteTo(e.Database, e.Table))
+}
+
+// restore the store from the snapshot then replay the log written since
+func (m *memoryStore) restore() error {
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return errors.Wrapf(err, "couldn't create dir %s", m.dir)
+	}
+	if err := m.replay(filepath.Join(m.dir, snapshotFile)); err != nil {
+		return err
+	}
+	return m.replay(filepath.Join(m.dir, walFile))
+}
+
+// replay the entries in the file. An entry which can't be decoded at the end of the log was
+// partially written when the process exited, so it and anything after it is discarded.
+func (m *memoryStore) replay(path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "couldn't open file %s", path)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			logger.Warnf("Discarding the partially written end of %s: %v", path, err)
+			return nil
+		}
+		if err := m.apply(&e); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// snapshot writes the records in the store to a new snapshot which replaces the previous one
+// and truncates the log
+func (m *memoryStore) snapshot() error {
+	path := filepath.Join(m.dir, snapshotFile)
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't open file %s", tmp)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for t := range m.tables {
+		keys, err := m.Store.List(store.ListFrom(t.Database, t.Table))
+		if err != nil {
+			f.Close()
+			return err
+		}
+
+		for _, k := range keys {
+			recs, err := m.Store.Read(k, store.ReadFrom(t.Database, t.Table))
+			if err == store.ErrNotFound || len(recs) == 0 {
+				// the record expired since it was listed
+				continue
+			} else if err != nil {
+				f.Close()
+				return err
+			}
+
+			e := entry{
+				Database: t.Database,
+				Table:    t.Table,
+				Key:      k,
+				Value:    recs[0].Value,
+				Metadata: recs[0].Metadata,
+			}
+			if recs[0].Expiry > 0 {
+				e.ExpiresAt = time.Now().Add(recs[0].Expiry)
+			}
+			if err := enc.Encode(&e); err != nil {
+				f.Close()
+				return errors.Wrap(err, "couldn't encode record")
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "couldn't write to file %s", tmp)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "couldn't write to file %s", tmp)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrap(err, "couldn't replace snapshot")
+	}
+
+	// the log is truncated once the snapshot containing its writes is in place
+	if m.wal != nil {
+		m.wal.Close()
+	}
+	wal, err := os.OpenFile(filepath.Join(m.dir, walFile), os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		m.wal = nil
+		return errors.Wrap(err, "couldn't open log")
+	}
+	m.wal = wal
+	return nil
+}
+
+// defaultDir returns the directory in the user's home dir the stores are persisted in
+func defaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, ".micro", "store")
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+)
+
+func TestRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir), SnapshotInterval(0))
+	s.Write(&store.Record{Key: "foo", Value: []byte("bar"), Metadata: map[string]interface{}{"a": "b"}})
+	s.Write(&store.Record{Key: "baz", Value: []byte("qux")}, store.WriteTo("db", "table"))
+	s.Write(&store.Record{Key: "expired", Value: []byte("x"), Expiry: time.Millisecond})
+	s.Write(&store.Record{Key: "deleted", Value: []byte("x")})
+	s.Delete("deleted")
+
+	// restore from the log, as if the process exited without closing the store
+	restored := NewStore(Dir(dir), SnapshotInterval(0))
+	time.Sleep(time.Millisecond * 5)
+	testRecords(t, restored)
+
+	// restore from the snapshot written on close, with a partially written entry in the log
+	if err := restored.Close(); err != nil {
+		t.Fatalf("Error closing the store: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, walFile), []byte(`{"key":"torn`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	testRecords(t, NewStore(Dir(dir), SnapshotInterval(0)))
+}
+
+func testRecords(t *testing.T, s store.Store) {
+	t.Helper()
+
+	if recs, err := s.Read("foo"); err != nil || string(recs[0].Value) != "bar" || recs[0].Metadata["a"] != "b" {
+		t.Errorf("Expected foo to be restored, got %v: %v", recs, err)
+	}
+	if recs, err := s.Read("baz", store.ReadFrom("db", "table")); err != nil || string(recs[0].Value) != "qux" {
+		t.Errorf("Expected baz to be restored, got %v: %v", recs, err)
+	}
+	if _, err := s.Read("expired"); err != store.ErrNotFound {
+		t.Errorf("Expected the expired record not to be restored, got %v", err)
+	}
+	if _, err := s.Read("deleted"); err != store.ErrNotFound {
+		t.Errorf("Expected the deleted record not to be restored, got %v", err)
+	}
+}
+
+func TestSnapshotInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir), SnapshotInterval(time.Millisecond*10))
+	defer s.Close()
+	s.Write(&store.Record{Key: "foo", Value: []byte("bar")})
+
+	time.Sleep(time.Millisecond * 50)
+	if fi, err := os.Stat(filepath.Join(dir, walFile)); err != nil || fi.Size() != 0 {
+		t.Fatalf("Expected the log to be truncated by the snapshot, got %v: %v", fi, err)
+	}
+	if recs, err := NewStore(Dir(dir), SnapshotInterval(0)).Read("foo"); err != nil || string(recs[0].Value) != "bar" {
+		t.Fatalf("Expected foo to be restored from the snapshot, got %v: %v", recs, err)
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { DefaultDir = d }(DefaultDir)
+	DefaultDir = dir
+
+	// the store isn't persisted until it's given a table, e.g. by the service using it
+	s := NewStore(SnapshotInterval(0))
+	s.Write(&store.Record{Key: "foo", Value: []byte("bar")})
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("Expected nothing to be persisted without a table, got %v", files)
+	}
+
+	// the records written before then are persisted in the dir of the database and table
+	if err := s.Init(store.Database("db"), store.Table("users")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "db", "users", snapshotFile)); err != nil {
+		t.Fatalf("Expected a snapshot in the dir of the table: %v", err)
+	}
+	s.Write(&store.Record{Key: "baz", Value: []byte("qux")})
+
+	restored := NewStore(store.Database("db"), store.Table("users"), SnapshotInterval(0))
+	if recs, err := restored.Read("foo", store.ReadFrom("micro", "micro")); err != nil || string(recs[0].Value) != "bar" {
+		t.Errorf("Expected foo to be restored, got %v: %v", recs, err)
+	}
+	if recs, err := restored.Read("baz"); err != nil || string(recs[0].Value) != "qux" {
+		t.Errorf("Expected baz to be restored, got %v: %v", recs, err)
+	}
+}
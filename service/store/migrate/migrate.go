@@ -0,0 +1,219 @@
+// Package migrate moves the records of a table from one store backend to another, for example
+// when upgrading from the file store to cockroach. The records are copied, then verified by
+// comparing their checksums in both stores. Optionally the destination is kept in sync with
+// the source for a window afterwards, so services can be switched over to the new backend
+// while writes to the old one are still being made.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	// DefaultInterval is how often the source is checked for changes while mirroring
+	DefaultInterval = time.Second * 5
+)
+
+// Options for a migration
+type Options struct {
+	// Progress is called after each record is copied
+	Progress func(copied, total int)
+	// Interval is how often the source is checked for changes while mirroring
+	Interval time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Progress is called with the number of records copied and the total after each record is
+// copied
+func Progress(fn func(copied, total int)) Option {
+	return func(o *Options) {
+		o.Progress = fn
+	}
+}
+
+// Interval sets how often the source is checked for changes while mirroring
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{Interval: DefaultInterval}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Checksums of records keyed by their key
+type Checksums map[string]string
+
+// Diff returns the keys whose checksums differ from the other checksums, including those
+// missing from either
+func (c Checksums) Diff(other Checksums) []string {
+	var keys []string
+	for k, sum := range c {
+		if other[k] != sum {
+			keys = append(keys, k)
+		}
+	}
+	for k := range other {
+		if _, ok := c[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Checksum of the key, value and metadata of a record. The metadata is compared by its JSON
+// encoding since backends which persist it as JSON don't preserve the types of values.
+func Checksum(r *store.Record) (string, error) {
+	md, err := json.Marshal(r.Metadata)
+	if err != nil {
+		return "", errors.Wrapf(err, "couldn't encode the metadata of %s", r.Key)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s", len(r.Key), r.Key)
+	fmt.Fprintf(h, "%d:", len(r.Value))
+	h.Write(r.Value)
+	h.Write(md)
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// Migrate copies every record from one store to the other, returning the checksums of the
+// records copied
+func Migrate(from, to store.Store, opts ...Option) (Checksums, error) {
+	options := newOptions(opts...)
+
+	keys, err := from.List()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't list from store %s", from.String())
+	}
+
+	sums := make(Checksums, len(keys))
+	for i, k := range keys {
+		r, err := readRecord(from, k)
+		if err == store.ErrNotFound {
+			// deleted or expired since it was listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if err := to.Write(r); err != nil {
+			return nil, errors.Wrapf(err, "couldn't write %s to store %s", k, to.String())
+		}
+		if sums[k], err = Checksum(r); err != nil {
+			return nil, err
+		}
+		if options.Progress != nil {
+			options.Progress(i+1, len(keys))
+		}
+	}
+	return sums, nil
+}
+
+// Sum returns the checksums of every record in the store
+func Sum(s store.Store) (Checksums, error) {
+	keys, err := s.List()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't list from store %s", s.String())
+	}
+
+	sums := make(Checksums, len(keys))
+	for _, k := range keys {
+		r, err := readRecord(s, k)
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if sums[k], err = Checksum(r); err != nil {
+			return nil, err
+		}
+	}
+	return sums, nil
+}
+
+// Verify the records of both stores match, returning the keys of those which don't
+func Verify(from, to store.Store) ([]string, error) {
+	fromSums, err := Sum(from)
+	if err != nil {
+		return nil, err
+	}
+	toSums, err := Sum(to)
+	if err != nil {
+		return nil, err
+	}
+	return fromSums.Diff(toSums), nil
+}
+
+// Mirror keeps the destination in sync with the source until the context is done, copying the
+// records which changed since the checksums were taken and deleting those which were removed.
+// The checksums are updated as changes are copied.
+func Mirror(ctx context.Context, from, to store.Store, sums Checksums, opts ...Option) error {
+	options := newOptions(opts...)
+
+	ticker := time.NewTicker(options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := Sum(from)
+		if err != nil {
+			return err
+		}
+
+		for _, k := range sums.Diff(current) {
+			if _, ok := current[k]; !ok {
+				if err := to.Delete(k); err != nil && err != store.ErrNotFound {
+					return errors.Wrapf(err, "couldn't delete %s from store %s", k, to.String())
+				}
+				delete(sums, k)
+				continue
+			}
+
+			r, err := readRecord(from, k)
+			if err == store.ErrNotFound {
+				// picked up on the next check
+				continue
+			} else if err != nil {
+				return err
+			}
+			if err := to.Write(r); err != nil {
+				return errors.Wrapf(err, "couldn't write %s to store %s", k, to.String())
+			}
+			sums[k] = current[k]
+		}
+	}
+}
+
+func readRecord(s store.Store, key string) (*store.Record, error) {
+	recs, err := s.Read(key)
+	if err == store.ErrNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "couldn't read %s from store %s", key, s.String())
+	}
+	if len(recs) != 1 {
+		return nil, errors.Errorf("received %d records reading %s from %s", len(recs), key, s.String())
+	}
+	return recs[0], nil
+}
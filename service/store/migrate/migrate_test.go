@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+)
+
+func TestMigrate(t *testing.T) {
+	from := memory.NewStore()
+	to := memory.NewStore()
+
+	from.Write(&store.Record{Key: "foo", Value: []byte("bar"), Metadata: map[string]interface{}{"a": 1}})
+	from.Write(&store.Record{Key: "baz", Value: []byte("qux")})
+
+	var progress []int
+	sums, err := Migrate(from, to, Progress(func(copied, total int) {
+		if total != 2 {
+			t.Errorf("Expected a total of 2, got %v", total)
+		}
+		progress = append(progress, copied)
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sums) != 2 || len(progress) != 2 {
+		t.Errorf("Expected 2 records to be copied, got %v", len(sums))
+	}
+
+	if diff, err := Verify(from, to); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if len(diff) > 0 {
+		t.Errorf("Expected the stores to match, got %v", diff)
+	}
+
+	to.Write(&store.Record{Key: "foo", Value: []byte("changed")})
+	if diff, _ := Verify(from, to); len(diff) != 1 || diff[0] != "foo" {
+		t.Errorf("Expected foo to differ, got %v", diff)
+	}
+}
+
+func TestMirror(t *testing.T) {
+	from := memory.NewStore()
+	to := memory.NewStore()
+
+	from.Write(&store.Record{Key: "foo", Value: []byte("bar")})
+	from.Write(&store.Record{Key: "baz", Value: []byte("qux")})
+
+	sums, err := Migrate(from, to)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// writes made to the source while mirroring are copied to the destination
+	from.Write(&store.Record{Key: "foo", Value: []byte("changed")})
+	from.Write(&store.Record{Key: "new", Value: []byte("record")})
+	from.Delete("baz")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	if err := Mirror(ctx, from, to, sums, Interval(time.Millisecond*10)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if diff, err := Verify(from, to); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if len(diff) > 0 {
+		t.Errorf("Expected the stores to match, got %v", diff)
+	}
+	if _, err := to.Read("baz"); err != store.ErrNotFound {
+		t.Errorf("Expected baz to be deleted, got %v", err)
+	}
+}
@@ -860,6 +860,1045 @@ func (m *TablesResponse) GetTables() []string {
 	return nil
 }
 
+type StatsRequest struct {
+	Database             string   `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table                string   `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{18}
+}
+
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(m, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatsRequest.Size(m)
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+func (m *StatsRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *StatsRequest) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+// StatsResponse contains the statistics of a table collected by the store
+// service since it started
+type StatsResponse struct {
+	// number of records in the table
+	Records int64 `protobuf:"varint,1,opt,name=records,proto3" json:"records,omitempty"`
+	// key sizes in bytes
+	AvgKeySize int64 `protobuf:"varint,2,opt,name=avg_key_size,json=avgKeySize,proto3" json:"avg_key_size,omitempty"`
+	MaxKeySize int64 `protobuf:"varint,3,opt,name=max_key_size,json=maxKeySize,proto3" json:"max_key_size,omitempty"`
+	// stats for each type of operation, e.g. read
+	Operations []*OperationStats `protobuf:"bytes,4,rep,name=operations,proto3" json:"operations,omitempty"`
+	// most frequently accessed keys
+	HotKeys []*HotKey `protobuf:"bytes,5,rep,name=hot_keys,json=hotKeys,proto3" json:"hot_keys,omitempty"`
+	// most recent operations which exceeded the slow operation threshold
+	SlowOperations []*SlowOperation `protobuf:"bytes,6,rep,name=slow_operations,json=slowOperations,proto3" json:"slow_operations,omitempty"`
+	// unix timestamp the stats have been collected since
+	Since                int64    `protobuf:"varint,7,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{19}
+}
+
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetRecords() int64 {
+	if m != nil {
+		return m.Records
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetAvgKeySize() int64 {
+	if m != nil {
+		return m.AvgKeySize
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetMaxKeySize() int64 {
+	if m != nil {
+		return m.MaxKeySize
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetOperations() []*OperationStats {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *StatsResponse) GetHotKeys() []*HotKey {
+	if m != nil {
+		return m.HotKeys
+	}
+	return nil
+}
+
+func (m *StatsResponse) GetSlowOperations() []*SlowOperation {
+	if m != nil {
+		return m.SlowOperations
+	}
+	return nil
+}
+
+func (m *StatsResponse) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type OperationStats struct {
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Count     int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Errors    int64  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	// latency percentiles in nanoseconds
+	P50                  int64    `protobuf:"varint,4,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95                  int64    `protobuf:"varint,5,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99                  int64    `protobuf:"varint,6,opt,name=p99,proto3" json:"p99,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperationStats) Reset()         { *m = OperationStats{} }
+func (m *OperationStats) String() string { return proto.CompactTextString(m) }
+func (*OperationStats) ProtoMessage()    {}
+func (*OperationStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{20}
+}
+
+func (m *OperationStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperationStats.Unmarshal(m, b)
+}
+func (m *OperationStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperationStats.Marshal(b, m, deterministic)
+}
+func (m *OperationStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperationStats.Merge(m, src)
+}
+func (m *OperationStats) XXX_Size() int {
+	return xxx_messageInfo_OperationStats.Size(m)
+}
+func (m *OperationStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperationStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperationStats proto.InternalMessageInfo
+
+func (m *OperationStats) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *OperationStats) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *OperationStats) GetErrors() int64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *OperationStats) GetP50() int64 {
+	if m != nil {
+		return m.P50
+	}
+	return 0
+}
+
+func (m *OperationStats) GetP95() int64 {
+	if m != nil {
+		return m.P95
+	}
+	return 0
+}
+
+func (m *OperationStats) GetP99() int64 {
+	if m != nil {
+		return m.P99
+	}
+	return 0
+}
+
+type HotKey struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HotKey) Reset()         { *m = HotKey{} }
+func (m *HotKey) String() string { return proto.CompactTextString(m) }
+func (*HotKey) ProtoMessage()    {}
+func (*HotKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{21}
+}
+
+func (m *HotKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HotKey.Unmarshal(m, b)
+}
+func (m *HotKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HotKey.Marshal(b, m, deterministic)
+}
+func (m *HotKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HotKey.Merge(m, src)
+}
+func (m *HotKey) XXX_Size() int {
+	return xxx_messageInfo_HotKey.Size(m)
+}
+func (m *HotKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_HotKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HotKey proto.InternalMessageInfo
+
+func (m *HotKey) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *HotKey) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type SlowOperation struct {
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Key       string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// duration in nanoseconds
+	Duration int64 `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// unix timestamp of the operation
+	Timestamp            int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SlowOperation) Reset()         { *m = SlowOperation{} }
+func (m *SlowOperation) String() string { return proto.CompactTextString(m) }
+func (*SlowOperation) ProtoMessage()    {}
+func (*SlowOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{22}
+}
+
+func (m *SlowOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SlowOperation.Unmarshal(m, b)
+}
+func (m *SlowOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SlowOperation.Marshal(b, m, deterministic)
+}
+func (m *SlowOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SlowOperation.Merge(m, src)
+}
+func (m *SlowOperation) XXX_Size() int {
+	return xxx_messageInfo_SlowOperation.Size(m)
+}
+func (m *SlowOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SlowOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SlowOperation proto.InternalMessageInfo
+
+func (m *SlowOperation) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *SlowOperation) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SlowOperation) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *SlowOperation) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *SlowOperation) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// Condition a record must meet to be written, checked against the record currently stored
+type Condition struct {
+	// the record must not exist
+	NotExists bool `protobuf:"varint,1,opt,name=not_exists,json=notExists,proto3" json:"not_exists,omitempty"`
+	// the record must exist
+	Exists bool `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	// the current value of the record must have the etag
+	Etag                 string   `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Condition) Reset()         { *m = Condition{} }
+func (m *Condition) String() string { return proto.CompactTextString(m) }
+func (*Condition) ProtoMessage()    {}
+func (*Condition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{23}
+}
+
+func (m *Condition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Condition.Unmarshal(m, b)
+}
+func (m *Condition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Condition.Marshal(b, m, deterministic)
+}
+func (m *Condition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Condition.Merge(m, src)
+}
+func (m *Condition) XXX_Size() int {
+	return xxx_messageInfo_Condition.Size(m)
+}
+func (m *Condition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Condition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Condition proto.InternalMessageInfo
+
+func (m *Condition) GetNotExists() bool {
+	if m != nil {
+		return m.NotExists
+	}
+	return false
+}
+
+func (m *Condition) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+func (m *Condition) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+type Upsert struct {
+	Record               *Record    `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	Condition            *Condition `protobuf:"bytes,2,opt,name=condition,proto3" json:"condition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Upsert) Reset()         { *m = Upsert{} }
+func (m *Upsert) String() string { return proto.CompactTextString(m) }
+func (*Upsert) ProtoMessage()    {}
+func (*Upsert) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{24}
+}
+
+func (m *Upsert) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Upsert.Unmarshal(m, b)
+}
+func (m *Upsert) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Upsert.Marshal(b, m, deterministic)
+}
+func (m *Upsert) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Upsert.Merge(m, src)
+}
+func (m *Upsert) XXX_Size() int {
+	return xxx_messageInfo_Upsert.Size(m)
+}
+func (m *Upsert) XXX_DiscardUnknown() {
+	xxx_messageInfo_Upsert.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Upsert proto.InternalMessageInfo
+
+func (m *Upsert) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+func (m *Upsert) GetCondition() *Condition {
+	if m != nil {
+		return m.Condition
+	}
+	return nil
+}
+
+// BulkUpsertRequest writes the records whose conditions are met
+type BulkUpsertRequest struct {
+	Upserts              []*Upsert     `protobuf:"bytes,1,rep,name=upserts,proto3" json:"upserts,omitempty"`
+	Options              *WriteOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *BulkUpsertRequest) Reset()         { *m = BulkUpsertRequest{} }
+func (m *BulkUpsertRequest) String() string { return proto.CompactTextString(m) }
+func (*BulkUpsertRequest) ProtoMessage()    {}
+func (*BulkUpsertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{25}
+}
+
+func (m *BulkUpsertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkUpsertRequest.Unmarshal(m, b)
+}
+func (m *BulkUpsertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkUpsertRequest.Marshal(b, m, deterministic)
+}
+func (m *BulkUpsertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkUpsertRequest.Merge(m, src)
+}
+func (m *BulkUpsertRequest) XXX_Size() int {
+	return xxx_messageInfo_BulkUpsertRequest.Size(m)
+}
+func (m *BulkUpsertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkUpsertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkUpsertRequest proto.InternalMessageInfo
+
+func (m *BulkUpsertRequest) GetUpserts() []*Upsert {
+	if m != nil {
+		return m.Upserts
+	}
+	return nil
+}
+
+func (m *BulkUpsertRequest) GetOptions() *WriteOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// UpsertResult is the result of writing a record, in the order of the request
+type UpsertResult struct {
+	Key     string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Written bool   `protobuf:"varint,2,opt,name=written,proto3" json:"written,omitempty"`
+	// the condition of the record wasn't met
+	Conflict bool   `protobuf:"varint,3,opt,name=conflict,proto3" json:"conflict,omitempty"`
+	Error    string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// etag of the value stored after the upsert
+	Etag                 string   `protobuf:"bytes,5,opt,name=etag,proto3" json:"etag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpsertResult) Reset()         { *m = UpsertResult{} }
+func (m *UpsertResult) String() string { return proto.CompactTextString(m) }
+func (*UpsertResult) ProtoMessage()    {}
+func (*UpsertResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{26}
+}
+
+func (m *UpsertResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpsertResult.Unmarshal(m, b)
+}
+func (m *UpsertResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpsertResult.Marshal(b, m, deterministic)
+}
+func (m *UpsertResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpsertResult.Merge(m, src)
+}
+func (m *UpsertResult) XXX_Size() int {
+	return xxx_messageInfo_UpsertResult.Size(m)
+}
+func (m *UpsertResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpsertResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpsertResult proto.InternalMessageInfo
+
+func (m *UpsertResult) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *UpsertResult) GetWritten() bool {
+	if m != nil {
+		return m.Written
+	}
+	return false
+}
+
+func (m *UpsertResult) GetConflict() bool {
+	if m != nil {
+		return m.Conflict
+	}
+	return false
+}
+
+func (m *UpsertResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *UpsertResult) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+type BulkUpsertResponse struct {
+	Results              []*UpsertResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *BulkUpsertResponse) Reset()         { *m = BulkUpsertResponse{} }
+func (m *BulkUpsertResponse) String() string { return proto.CompactTextString(m) }
+func (*BulkUpsertResponse) ProtoMessage()    {}
+func (*BulkUpsertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{27}
+}
+
+func (m *BulkUpsertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkUpsertResponse.Unmarshal(m, b)
+}
+func (m *BulkUpsertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkUpsertResponse.Marshal(b, m, deterministic)
+}
+func (m *BulkUpsertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkUpsertResponse.Merge(m, src)
+}
+func (m *BulkUpsertResponse) XXX_Size() int {
+	return xxx_messageInfo_BulkUpsertResponse.Size(m)
+}
+func (m *BulkUpsertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkUpsertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkUpsertResponse proto.InternalMessageInfo
+
+func (m *BulkUpsertResponse) GetResults() []*UpsertResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type TrashRequest struct {
+	Database string `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table    string `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	// only list the keys with the prefix
+	Prefix               string   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrashRequest) Reset()         { *m = TrashRequest{} }
+func (m *TrashRequest) String() string { return proto.CompactTextString(m) }
+func (*TrashRequest) ProtoMessage()    {}
+func (*TrashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{28}
+}
+
+func (m *TrashRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrashRequest.Unmarshal(m, b)
+}
+func (m *TrashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrashRequest.Marshal(b, m, deterministic)
+}
+func (m *TrashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrashRequest.Merge(m, src)
+}
+func (m *TrashRequest) XXX_Size() int {
+	return xxx_messageInfo_TrashRequest.Size(m)
+}
+func (m *TrashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrashRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrashRequest proto.InternalMessageInfo
+
+func (m *TrashRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *TrashRequest) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *TrashRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+// TrashedRecord is a record in the trash
+type TrashedRecord struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// unix timestamp the record was deleted at
+	Deleted int64 `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// unix timestamp the record is purged at
+	Expires              int64    `protobuf:"varint,3,opt,name=expires,proto3" json:"expires,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrashedRecord) Reset()         { *m = TrashedRecord{} }
+func (m *TrashedRecord) String() string { return proto.CompactTextString(m) }
+func (*TrashedRecord) ProtoMessage()    {}
+func (*TrashedRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{29}
+}
+
+func (m *TrashedRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrashedRecord.Unmarshal(m, b)
+}
+func (m *TrashedRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrashedRecord.Marshal(b, m, deterministic)
+}
+func (m *TrashedRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrashedRecord.Merge(m, src)
+}
+func (m *TrashedRecord) XXX_Size() int {
+	return xxx_messageInfo_TrashedRecord.Size(m)
+}
+func (m *TrashedRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrashedRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrashedRecord proto.InternalMessageInfo
+
+func (m *TrashedRecord) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TrashedRecord) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+func (m *TrashedRecord) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+type TrashResponse struct {
+	Records              []*TrashedRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TrashResponse) Reset()         { *m = TrashResponse{} }
+func (m *TrashResponse) String() string { return proto.CompactTextString(m) }
+func (*TrashResponse) ProtoMessage()    {}
+func (*TrashResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{30}
+}
+
+func (m *TrashResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrashResponse.Unmarshal(m, b)
+}
+func (m *TrashResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrashResponse.Marshal(b, m, deterministic)
+}
+func (m *TrashResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrashResponse.Merge(m, src)
+}
+func (m *TrashResponse) XXX_Size() int {
+	return xxx_messageInfo_TrashResponse.Size(m)
+}
+func (m *TrashResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrashResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrashResponse proto.InternalMessageInfo
+
+func (m *TrashResponse) GetRecords() []*TrashedRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type RestoreRequest struct {
+	Database             string   `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table                string   `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Key                  string   `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{31}
+}
+
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type RestoreResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreResponse) Reset()         { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()    {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{32}
+}
+
+func (m *RestoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResponse.Unmarshal(m, b)
+}
+func (m *RestoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResponse.Merge(m, src)
+}
+func (m *RestoreResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreResponse.Size(m)
+}
+func (m *RestoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResponse proto.InternalMessageInfo
+
+type PurgeRequest struct {
+	Database string `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table    string `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Key      string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	// purge every record in the trash of the table
+	All                  bool     `protobuf:"varint,4,opt,name=all,proto3" json:"all,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeRequest) Reset()         { *m = PurgeRequest{} }
+func (m *PurgeRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeRequest) ProtoMessage()    {}
+func (*PurgeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{33}
+}
+
+func (m *PurgeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeRequest.Unmarshal(m, b)
+}
+func (m *PurgeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeRequest.Marshal(b, m, deterministic)
+}
+func (m *PurgeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeRequest.Merge(m, src)
+}
+func (m *PurgeRequest) XXX_Size() int {
+	return xxx_messageInfo_PurgeRequest.Size(m)
+}
+func (m *PurgeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeRequest proto.InternalMessageInfo
+
+func (m *PurgeRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *PurgeRequest) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *PurgeRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PurgeRequest) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+type PurgeResponse struct {
+	// number of records purged
+	Purged               int64    `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeResponse) Reset()         { *m = PurgeResponse{} }
+func (m *PurgeResponse) String() string { return proto.CompactTextString(m) }
+func (*PurgeResponse) ProtoMessage()    {}
+func (*PurgeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{34}
+}
+
+func (m *PurgeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeResponse.Unmarshal(m, b)
+}
+func (m *PurgeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeResponse.Marshal(b, m, deterministic)
+}
+func (m *PurgeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeResponse.Merge(m, src)
+}
+func (m *PurgeResponse) XXX_Size() int {
+	return xxx_messageInfo_PurgeResponse.Size(m)
+}
+func (m *PurgeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeResponse proto.InternalMessageInfo
+
+func (m *PurgeResponse) GetPurged() int64 {
+	if m != nil {
+		return m.Purged
+	}
+	return 0
+}
+
+type ProtectRequest struct {
+	Database string `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table    string `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	// false removes the protection
+	Protected            bool     `protobuf:"varint,3,opt,name=protected,proto3" json:"protected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProtectRequest) Reset()         { *m = ProtectRequest{} }
+func (m *ProtectRequest) String() string { return proto.CompactTextString(m) }
+func (*ProtectRequest) ProtoMessage()    {}
+func (*ProtectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{35}
+}
+
+func (m *ProtectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProtectRequest.Unmarshal(m, b)
+}
+func (m *ProtectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProtectRequest.Marshal(b, m, deterministic)
+}
+func (m *ProtectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProtectRequest.Merge(m, src)
+}
+func (m *ProtectRequest) XXX_Size() int {
+	return xxx_messageInfo_ProtectRequest.Size(m)
+}
+func (m *ProtectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProtectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProtectRequest proto.InternalMessageInfo
+
+func (m *ProtectRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *ProtectRequest) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *ProtectRequest) GetProtected() bool {
+	if m != nil {
+		return m.Protected
+	}
+	return false
+}
+
+type ProtectResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProtectResponse) Reset()         { *m = ProtectResponse{} }
+func (m *ProtectResponse) String() string { return proto.CompactTextString(m) }
+func (*ProtectResponse) ProtoMessage()    {}
+func (*ProtectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3b1a2f06b010ee4, []int{36}
+}
+
+func (m *ProtectResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProtectResponse.Unmarshal(m, b)
+}
+func (m *ProtectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProtectResponse.Marshal(b, m, deterministic)
+}
+func (m *ProtectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProtectResponse.Merge(m, src)
+}
+func (m *ProtectResponse) XXX_Size() int {
+	return xxx_messageInfo_ProtectResponse.Size(m)
+}
+func (m *ProtectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProtectResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProtectResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Field)(nil), "store.Field")
 	proto.RegisterType((*Record)(nil), "store.Record")
@@ -880,53 +1919,112 @@ func init() {
 	proto.RegisterType((*DatabasesResponse)(nil), "store.DatabasesResponse")
 	proto.RegisterType((*TablesRequest)(nil), "store.TablesRequest")
 	proto.RegisterType((*TablesResponse)(nil), "store.TablesResponse")
+	proto.RegisterType((*StatsRequest)(nil), "store.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "store.StatsResponse")
+	proto.RegisterType((*OperationStats)(nil), "store.OperationStats")
+	proto.RegisterType((*HotKey)(nil), "store.HotKey")
+	proto.RegisterType((*SlowOperation)(nil), "store.SlowOperation")
+	proto.RegisterType((*Condition)(nil), "store.Condition")
+	proto.RegisterType((*Upsert)(nil), "store.Upsert")
+	proto.RegisterType((*BulkUpsertRequest)(nil), "store.BulkUpsertRequest")
+	proto.RegisterType((*UpsertResult)(nil), "store.UpsertResult")
+	proto.RegisterType((*BulkUpsertResponse)(nil), "store.BulkUpsertResponse")
+	proto.RegisterType((*TrashRequest)(nil), "store.TrashRequest")
+	proto.RegisterType((*TrashedRecord)(nil), "store.TrashedRecord")
+	proto.RegisterType((*TrashResponse)(nil), "store.TrashResponse")
+	proto.RegisterType((*RestoreRequest)(nil), "store.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "store.RestoreResponse")
+	proto.RegisterType((*PurgeRequest)(nil), "store.PurgeRequest")
+	proto.RegisterType((*PurgeResponse)(nil), "store.PurgeResponse")
+	proto.RegisterType((*ProtectRequest)(nil), "store.ProtectRequest")
+	proto.RegisterType((*ProtectResponse)(nil), "store.ProtectResponse")
 }
 
 func init() { proto.RegisterFile("service/store/proto/store.proto", fileDescriptor_e3b1a2f06b010ee4) }
 
 var fileDescriptor_e3b1a2f06b010ee4 = []byte{
-	// 654 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xe1, 0x6e, 0xd3, 0x30,
-	0x10, 0x5e, 0xda, 0x24, 0x6b, 0x6e, 0xed, 0x28, 0xde, 0x18, 0x51, 0x41, 0xa2, 0xb2, 0x84, 0x88,
-	0x04, 0xb4, 0x6c, 0x05, 0x15, 0xb1, 0x3f, 0x03, 0x0d, 0x24, 0x10, 0x13, 0x22, 0x20, 0x21, 0xf1,
-	0x2f, 0x6d, 0x5d, 0x88, 0xd6, 0x36, 0x21, 0x76, 0xab, 0xf5, 0x61, 0x78, 0x23, 0x5e, 0x80, 0xb7,
-	0x41, 0xb6, 0xcf, 0x69, 0xd2, 0x75, 0xfc, 0x18, 0xfc, 0x99, 0x7c, 0x67, 0xdf, 0x77, 0xdf, 0xf7,
-	0xdd, 0x65, 0x85, 0x7b, 0x9c, 0x65, 0x8b, 0x78, 0xc8, 0xba, 0x5c, 0x24, 0x19, 0xeb, 0xa6, 0x59,
-	0x22, 0x12, 0x7d, 0xee, 0xa8, 0x33, 0x71, 0x54, 0x40, 0x0f, 0xc1, 0x79, 0x13, 0xb3, 0xc9, 0x88,
-	0x10, 0xb0, 0xc5, 0x32, 0x65, 0xbe, 0xd5, 0xb6, 0x02, 0x2f, 0x54, 0x67, 0xb2, 0x0f, 0xce, 0x22,
-	0x9a, 0xcc, 0x99, 0x5f, 0x51, 0x49, 0x1d, 0xd0, 0x5f, 0x16, 0xb8, 0x21, 0x1b, 0x26, 0xd9, 0x88,
-	0x34, 0xa1, 0x7a, 0xce, 0x96, 0x58, 0x23, 0x8f, 0xe5, 0x92, 0x3a, 0x96, 0x90, 0x03, 0x70, 0xd9,
-	0x45, 0x1a, 0x67, 0x4b, 0xbf, 0xda, 0xb6, 0x82, 0x6a, 0x88, 0x11, 0xe9, 0x43, 0x6d, 0xca, 0x44,
-	0x34, 0x8a, 0x44, 0xe4, 0xdb, 0xed, 0x6a, 0xb0, 0x73, 0x74, 0xa7, 0xa3, 0x49, 0xea, 0x06, 0x9d,
-	0x33, 0xbc, 0x7d, 0x3d, 0x13, 0xd9, 0x32, 0xcc, 0x1f, 0xb7, 0xde, 0x42, 0xa3, 0x74, 0xb5, 0x81,
-	0x09, 0x2d, 0x32, 0xd9, 0x39, 0xaa, 0x23, 0xb0, 0x52, 0x8b, 0xbc, 0x5e, 0x54, 0x9e, 0x5b, 0xf4,
-	0xa7, 0x05, 0x3b, 0x21, 0x8b, 0x46, 0x1f, 0x52, 0x11, 0x27, 0x33, 0x4e, 0x5a, 0x50, 0x93, 0xb0,
-	0x83, 0x88, 0x1b, 0x33, 0xf2, 0x58, 0xaa, 0x13, 0xd1, 0x60, 0x92, 0x1b, 0xa2, 0x02, 0xa9, 0x2e,
-	0xcd, 0xd8, 0x38, 0xbe, 0x50, 0xea, 0x6a, 0x21, 0x46, 0x32, 0xcf, 0xe7, 0x63, 0x99, 0xb7, 0x75,
-	0x5e, 0x47, 0x12, 0x65, 0x12, 0x4f, 0x63, 0xe1, 0x3b, 0x6d, 0x2b, 0xb0, 0x43, 0x1d, 0xc8, 0xd7,
-	0xc9, 0x78, 0xcc, 0x99, 0xf0, 0x5d, 0x95, 0xc6, 0x88, 0x9e, 0x69, 0x7a, 0x21, 0xfb, 0x31, 0x67,
-	0x5c, 0x6c, 0x10, 0xfa, 0x08, 0xb6, 0x13, 0xcd, 0x1d, 0xa5, 0x92, 0xdc, 0xc3, 0x5c, 0x55, 0x68,
-	0x9e, 0xd0, 0x3e, 0xd4, 0x35, 0x1c, 0x4f, 0x93, 0x19, 0x67, 0xe4, 0x01, 0x6c, 0x67, 0xca, 0x6b,
-	0xee, 0x5b, 0x6a, 0x02, 0x8d, 0xd2, 0x04, 0x42, 0x73, 0x4b, 0x4f, 0xa0, 0xfe, 0x25, 0x8b, 0x05,
-	0xbb, 0xb6, 0x4f, 0x74, 0x84, 0x08, 0x46, 0xca, 0x7d, 0x70, 0x35, 0xb8, 0xaa, 0xbf, 0xd4, 0x19,
-	0x2f, 0xc9, 0xe3, 0x75, 0x7d, 0x7b, 0xf8, 0xae, 0x48, 0x67, 0x25, 0xf0, 0x06, 0x34, 0xb0, 0x8b,
-	0x56, 0x48, 0x5f, 0x42, 0xe3, 0x94, 0x4d, 0xd8, 0xbf, 0x30, 0xff, 0x68, 0x20, 0xae, 0x9e, 0x42,
-	0x67, 0x9d, 0xe5, 0x3e, 0xb2, 0x2c, 0xf5, 0x5e, 0xd1, 0x6c, 0xc2, 0xae, 0x81, 0x44, 0x9e, 0x72,
-	0x11, 0xdf, 0xc7, 0x5c, 0xfc, 0xaf, 0x45, 0xf4, 0xae, 0x58, 0x44, 0xef, 0x9a, 0x8b, 0x78, 0xac,
-	0xe9, 0x19, 0x0b, 0x0a, 0x6b, 0x67, 0x95, 0xd6, 0xae, 0xa0, 0x61, 0x25, 0x37, 0x80, 0xba, 0x2e,
-	0xc6, 0xb5, 0x23, 0x60, 0x9f, 0xb3, 0xa5, 0xf4, 0xaa, 0x2a, 0xff, 0xdd, 0xc8, 0xf3, 0x3b, 0xbb,
-	0x66, 0x35, 0x2b, 0x94, 0x40, 0xf3, 0x14, 0x65, 0x72, 0xec, 0x45, 0x0f, 0xe1, 0x66, 0x21, 0x87,
-	0x10, 0x77, 0xc1, 0x33, 0x7e, 0xe8, 0xdd, 0xf5, 0xc2, 0x55, 0x82, 0x3e, 0x84, 0xc6, 0x67, 0x69,
-	0x8a, 0xc1, 0xf8, 0x9b, 0x9d, 0x34, 0x80, 0x5d, 0xf3, 0x18, 0xc1, 0x0f, 0xc0, 0x55, 0x9e, 0x1a,
-	0x64, 0x8c, 0x8e, 0x7e, 0x57, 0xc0, 0xf9, 0x24, 0x65, 0x92, 0x43, 0xb0, 0xe5, 0x87, 0x44, 0x8a,
-	0x5f, 0x1b, 0xf6, 0x6a, 0xed, 0x95, 0x72, 0x38, 0xdf, 0x2d, 0xf2, 0x14, 0x1c, 0xb5, 0x9a, 0xa4,
-	0xb4, 0xc1, 0xa6, 0x68, 0xbf, 0x9c, 0xcc, 0xab, 0xfa, 0xe0, 0xea, 0x4d, 0x21, 0xe5, 0x95, 0x32,
-	0x75, 0xb7, 0xd6, 0xb2, 0x79, 0x61, 0x0f, 0x6c, 0xe9, 0x39, 0x29, 0x0e, 0x66, 0x9d, 0x61, 0x71,
-	0x28, 0x74, 0xeb, 0x89, 0x45, 0x4e, 0xc0, 0xcb, 0xad, 0x26, 0xb7, 0x0d, 0xf4, 0xda, 0x40, 0x5a,
-	0xfe, 0xe5, 0x8b, 0x22, 0x5f, 0x6d, 0x66, 0xce, 0xb7, 0x34, 0x88, 0x9c, 0x6f, 0xd9, 0x71, 0xba,
-	0xf5, 0xea, 0xd9, 0xd7, 0xde, 0xb7, 0x58, 0x7c, 0x9f, 0x0f, 0x3a, 0xc3, 0x64, 0xda, 0x9d, 0xc6,
-	0xc3, 0x2c, 0xc1, 0xbf, 0x8b, 0x5e, 0x77, 0xc3, 0xef, 0xd9, 0xb1, 0x3a, 0x0f, 0x5c, 0x15, 0xf4,
-	0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0x69, 0xbe, 0x46, 0xb7, 0xf3, 0x06, 0x00, 0x00,
+	// 1291 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x57, 0x6d, 0x53, 0x1c, 0x45,
+	0x10, 0x66, 0xef, 0x8d, 0xbb, 0xe6, 0xee, 0x80, 0x01, 0xe2, 0x79, 0x6a, 0x49, 0x4d, 0x95, 0x15,
+	0xaa, 0x34, 0x07, 0x01, 0x29, 0x24, 0x29, 0xcb, 0x18, 0x12, 0xcb, 0xb7, 0x54, 0x92, 0x85, 0x44,
+	0xcb, 0x2f, 0xb8, 0xdc, 0x0d, 0xb0, 0xc5, 0xde, 0xed, 0xba, 0x3b, 0x47, 0xc0, 0x4f, 0xfe, 0x03,
+	0xab, 0xfc, 0xee, 0x27, 0xff, 0x84, 0x3f, 0xc2, 0x1f, 0xe5, 0xbc, 0xf4, 0xcc, 0xce, 0x1e, 0x87,
+	0x46, 0xcc, 0x17, 0xd8, 0xee, 0xe9, 0xe9, 0x7e, 0xba, 0xa7, 0xdf, 0x0e, 0xde, 0xcf, 0x58, 0x7a,
+	0x1e, 0xf6, 0xd9, 0x7a, 0xc6, 0xe3, 0x94, 0xad, 0x27, 0x69, 0xcc, 0x63, 0xfd, 0xdd, 0x53, 0xdf,
+	0xa4, 0xaa, 0x08, 0x7a, 0x17, 0xaa, 0x5f, 0x84, 0x2c, 0x1a, 0x10, 0x02, 0x15, 0x7e, 0x99, 0xb0,
+	0x8e, 0xb7, 0xea, 0xad, 0x35, 0x7c, 0xf5, 0x4d, 0x96, 0xa1, 0x7a, 0x1e, 0x44, 0x63, 0xd6, 0x29,
+	0x29, 0xa6, 0x26, 0xe8, 0x5f, 0x1e, 0xd4, 0x7c, 0xd6, 0x8f, 0xd3, 0x01, 0x59, 0x80, 0xf2, 0x19,
+	0xbb, 0xc4, 0x3b, 0xf2, 0xb3, 0x78, 0xa5, 0x89, 0x57, 0xc8, 0x2d, 0xa8, 0xb1, 0x8b, 0x24, 0x4c,
+	0x2f, 0x3b, 0x65, 0xc1, 0x2e, 0xfb, 0x48, 0x91, 0x1d, 0xa8, 0x0f, 0x19, 0x0f, 0x06, 0x01, 0x0f,
+	0x3a, 0x95, 0xd5, 0xf2, 0xda, 0xdc, 0xe6, 0x3b, 0x3d, 0x0d, 0x52, 0x1b, 0xe8, 0x3d, 0xc1, 0xd3,
+	0xc7, 0x23, 0x9e, 0x5e, 0xfa, 0x56, 0xb8, 0xfb, 0x15, 0xb4, 0x0a, 0x47, 0x53, 0x90, 0x50, 0x17,
+	0xc9, 0xdc, 0x66, 0x13, 0x15, 0x2b, 0x6f, 0x11, 0xd7, 0xbd, 0xd2, 0x27, 0x1e, 0xfd, 0xdd, 0x83,
+	0x39, 0x9f, 0x05, 0x83, 0xa7, 0x09, 0x0f, 0xe3, 0x51, 0x46, 0xba, 0x50, 0x97, 0x6a, 0x8f, 0x82,
+	0xcc, 0x04, 0xc3, 0xd2, 0xd2, 0x3b, 0xf1, 0x15, 0xd9, 0x80, 0x28, 0x42, 0x7a, 0x97, 0xa4, 0xec,
+	0x38, 0xbc, 0x50, 0xde, 0xd5, 0x7d, 0xa4, 0x24, 0x3f, 0x1b, 0x1f, 0x4b, 0x7e, 0x45, 0xf3, 0x35,
+	0x25, 0xb5, 0x44, 0xe1, 0x30, 0xe4, 0x9d, 0xaa, 0x60, 0x57, 0x7c, 0x4d, 0x48, 0xe9, 0xf8, 0xf8,
+	0x38, 0x63, 0xbc, 0x53, 0x53, 0x6c, 0xa4, 0xe8, 0x13, 0x0d, 0xcf, 0x67, 0x3f, 0x8d, 0x59, 0xc6,
+	0xa7, 0x38, 0xfa, 0x11, 0xcc, 0xc6, 0x1a, 0x3b, 0xba, 0x4a, 0x6c, 0x0c, 0xad, 0x57, 0xbe, 0x11,
+	0xa1, 0x3b, 0xd0, 0xd4, 0xea, 0xb2, 0x44, 0x90, 0x8c, 0xdc, 0x86, 0xd9, 0x54, 0xc5, 0x3a, 0x13,
+	0x3a, 0xe5, 0x0b, 0xb4, 0x0a, 0x2f, 0xe0, 0x9b, 0x53, 0xfa, 0x00, 0x9a, 0xdf, 0xa5, 0x21, 0x67,
+	0x37, 0x8e, 0x13, 0x1d, 0xa0, 0x06, 0xe3, 0xca, 0x07, 0x50, 0xd3, 0xca, 0xd5, 0xfd, 0x2b, 0x96,
+	0xf1, 0x90, 0xdc, 0x99, 0xf4, 0x6f, 0x09, 0xe5, 0x5c, 0x38, 0xb9, 0x83, 0xf3, 0xd0, 0x42, 0x2b,
+	0xda, 0x43, 0xfa, 0x39, 0xb4, 0x1e, 0xb1, 0x88, 0xfd, 0x1f, 0xe4, 0xcf, 0x8d, 0x8a, 0xeb, 0x5f,
+	0xa1, 0x37, 0x89, 0x72, 0x19, 0x51, 0x16, 0x6c, 0xe7, 0x30, 0x17, 0xa0, 0x6d, 0x54, 0x22, 0x4e,
+	0x99, 0x88, 0xdf, 0x86, 0x19, 0x7f, 0x53, 0x89, 0xd8, 0xb8, 0x26, 0x11, 0x1b, 0x37, 0x4c, 0xc4,
+	0xfb, 0x1a, 0x9e, 0x09, 0x81, 0x93, 0x76, 0x5e, 0x21, 0xed, 0x1c, 0x1f, 0x72, 0x77, 0xd7, 0xa0,
+	0xa9, 0x2f, 0x63, 0xda, 0x89, 0x76, 0x23, 0xa2, 0x26, 0x63, 0x55, 0x96, 0xed, 0x46, 0x7e, 0x7f,
+	0x5d, 0xa9, 0x7b, 0x0b, 0x25, 0x4a, 0x60, 0xe1, 0x11, 0xba, 0x99, 0xa1, 0x2d, 0xd1, 0xa5, 0x16,
+	0x1d, 0x1e, 0xaa, 0x78, 0x17, 0x1a, 0x26, 0x1e, 0x3a, 0x77, 0x1b, 0x7e, 0xce, 0xa0, 0x1f, 0x42,
+	0xeb, 0x40, 0x06, 0xc5, 0xe8, 0xf8, 0xa7, 0x70, 0x0a, 0x74, 0x6d, 0x23, 0x8c, 0xca, 0x45, 0x10,
+	0x54, 0x4c, 0x8d, 0x66, 0xa4, 0x64, 0x15, 0xec, 0xf3, 0x80, 0xbf, 0x8e, 0xd6, 0x6b, 0x72, 0xe9,
+	0x8f, 0x12, 0xb4, 0x50, 0x05, 0xda, 0xea, 0xb8, 0x25, 0x28, 0xdb, 0xa3, 0x21, 0xc9, 0x2a, 0x34,
+	0x83, 0xf3, 0x93, 0x43, 0x11, 0x9d, 0xc3, 0x2c, 0xfc, 0x59, 0x2b, 0x2a, 0xfb, 0x20, 0x78, 0xdf,
+	0xb0, 0xcb, 0x7d, 0xc1, 0x91, 0x12, 0xc3, 0xe0, 0x22, 0x97, 0xd0, 0xfd, 0x15, 0x04, 0xcf, 0x48,
+	0x6c, 0x03, 0xc4, 0x09, 0x4b, 0x03, 0xfd, 0x54, 0xba, 0xcb, 0xae, 0xe0, 0x53, 0x3d, 0x35, 0x07,
+	0x1a, 0x90, 0x23, 0x48, 0xd6, 0xa0, 0x7e, 0x1a, 0xf3, 0x43, 0xf5, 0x48, 0xd5, 0x42, 0x63, 0xf8,
+	0x32, 0xe6, 0x42, 0xb7, 0x3f, 0x7b, 0xaa, 0xfe, 0x67, 0xe4, 0x53, 0x98, 0xcf, 0xa2, 0xf8, 0xd5,
+	0xa1, 0x63, 0xa5, 0xa6, 0x2e, 0x98, 0x0a, 0xd8, 0x17, 0xa7, 0xd6, 0x92, 0xdf, 0xce, 0x5c, 0x32,
+	0x93, 0x51, 0xca, 0xc2, 0x51, 0x9f, 0x75, 0x66, 0x15, 0x74, 0x4d, 0xd0, 0xdf, 0x3c, 0x68, 0x17,
+	0xd1, 0xc9, 0xf7, 0xb6, 0x26, 0x30, 0xd6, 0x39, 0x43, 0xaa, 0xe9, 0xc7, 0xe3, 0x11, 0xc7, 0x18,
+	0x69, 0x42, 0x0d, 0x9e, 0x34, 0x8d, 0xd3, 0xcc, 0x0e, 0x1e, 0x45, 0xc9, 0xfa, 0x4d, 0xb6, 0x37,
+	0x54, 0x39, 0x94, 0x7d, 0xf9, 0xa9, 0x38, 0xbb, 0xdb, 0xaa, 0x12, 0x24, 0x67, 0x77, 0x5b, 0x73,
+	0x76, 0x55, 0x11, 0x28, 0xce, 0x2e, 0xdd, 0x80, 0x9a, 0x76, 0x7e, 0xfa, 0xe0, 0xbb, 0x6a, 0x9f,
+	0xfe, 0xea, 0x89, 0xc7, 0x76, 0xfd, 0xfd, 0x17, 0x2f, 0x50, 0x6f, 0x29, 0xd7, 0x2b, 0x13, 0x6c,
+	0x8c, 0xe2, 0xda, 0x07, 0x4b, 0x4b, 0x5d, 0x3c, 0x1c, 0x8a, 0x34, 0x0c, 0x86, 0x09, 0xfa, 0x92,
+	0x33, 0x24, 0x22, 0xe5, 0xad, 0xf2, 0x49, 0xa4, 0x9f, 0x22, 0xe8, 0x4b, 0x68, 0xec, 0xc5, 0xa3,
+	0x41, 0xa8, 0x14, 0xbc, 0x07, 0x30, 0x12, 0x8f, 0xcc, 0x2e, 0x44, 0x69, 0xea, 0xe4, 0xab, 0xfb,
+	0x0d, 0xc1, 0x79, 0xac, 0x18, 0x7a, 0x6c, 0xab, 0xa3, 0x92, 0x1e, 0x60, 0x9a, 0x92, 0xc5, 0x2b,
+	0x86, 0xef, 0x09, 0x76, 0x19, 0xf5, 0x4d, 0x0f, 0xa1, 0xf6, 0x22, 0x11, 0x4b, 0xc7, 0x6b, 0xb7,
+	0xf5, 0x1e, 0x34, 0xfa, 0x06, 0x08, 0xb6, 0xcc, 0x05, 0x94, 0xb4, 0x00, 0xfd, 0x5c, 0x84, 0x9e,
+	0xc1, 0xe2, 0xc3, 0x71, 0x74, 0xa6, 0x8d, 0x98, 0xf2, 0x13, 0xd3, 0x6b, 0xac, 0x18, 0x93, 0xd3,
+	0x0b, 0xc5, 0xcc, 0xe9, 0x7f, 0x1d, 0x22, 0xbf, 0x78, 0xd0, 0x34, 0x96, 0xb2, 0x71, 0x34, 0xad,
+	0xe1, 0x8b, 0xaa, 0x7d, 0x25, 0xee, 0x72, 0x36, 0xc2, 0xe8, 0x18, 0x52, 0x3e, 0x99, 0x80, 0x7d,
+	0x1c, 0x85, 0x7d, 0x8e, 0x1b, 0x81, 0xa5, 0xf3, 0x47, 0xa9, 0x38, 0x8f, 0x62, 0x03, 0x5a, 0x75,
+	0x02, 0xba, 0x07, 0xc4, 0xf5, 0x17, 0x7b, 0xc5, 0x1d, 0xd9, 0x2b, 0x24, 0x22, 0xe3, 0xf0, 0x52,
+	0xd1, 0x61, 0x75, 0xe6, 0x1b, 0x19, 0xfa, 0x3d, 0x34, 0x0f, 0xd2, 0x20, 0x3b, 0xbd, 0x71, 0xbb,
+	0xba, 0x6e, 0xa6, 0xd0, 0x17, 0xa2, 0xbf, 0x4a, 0xcd, 0x6c, 0x70, 0xed, 0x2e, 0x28, 0x22, 0x34,
+	0x50, 0x23, 0x6e, 0x80, 0x45, 0x61, 0x48, 0x79, 0xa2, 0x36, 0x40, 0x66, 0xea, 0xd2, 0x90, 0xf4,
+	0x33, 0x54, 0x6b, 0x1d, 0xee, 0x4d, 0xee, 0x27, 0xa6, 0xab, 0x14, 0xac, 0xe7, 0x6b, 0xca, 0x01,
+	0xb4, 0xc5, 0x5d, 0x29, 0x71, 0x73, 0x9f, 0xd1, 0x95, 0xb2, 0x75, 0x85, 0x2e, 0xc2, 0xbc, 0xd5,
+	0x8a, 0xe3, 0x5a, 0x6c, 0x33, 0xcf, 0xc6, 0xe9, 0xc9, 0x9b, 0x34, 0x23, 0x39, 0x41, 0x14, 0xe1,
+	0xba, 0x28, 0x3f, 0xe9, 0x6d, 0x68, 0xa1, 0x95, 0x7c, 0x30, 0x25, 0x92, 0x31, 0xc0, 0x59, 0x81,
+	0x14, 0xfd, 0x11, 0xda, 0xcf, 0xc4, 0x62, 0xcf, 0xfa, 0xfc, 0xe6, 0x80, 0x44, 0x3f, 0x49, 0xb4,
+	0x0e, 0xa1, 0x5e, 0x67, 0x6e, 0xce, 0x90, 0x31, 0xb0, 0x16, 0x34, 0x98, 0xcd, 0x3f, 0xab, 0x50,
+	0xdd, 0x97, 0x51, 0x21, 0x77, 0xa1, 0x22, 0xd7, 0x4a, 0xe2, 0xee, 0x9e, 0x08, 0xa4, 0xbb, 0x54,
+	0xe0, 0x61, 0xf8, 0x66, 0xc8, 0xc7, 0x50, 0x55, 0xc5, 0x47, 0x0a, 0xa5, 0x68, 0x2e, 0x2d, 0x17,
+	0x99, 0xf6, 0xd6, 0x0e, 0xd4, 0xf4, 0xde, 0x44, 0x8a, 0x0b, 0x96, 0xb9, 0xb7, 0x32, 0xc1, 0xb5,
+	0x17, 0xb7, 0xa0, 0x22, 0x37, 0x10, 0xe2, 0xae, 0x29, 0x93, 0x08, 0xdd, 0x15, 0x85, 0xce, 0x6c,
+	0x78, 0xe4, 0x01, 0x34, 0xec, 0xe2, 0x41, 0xde, 0x32, 0xaa, 0x27, 0xd6, 0x93, 0x6e, 0xe7, 0xea,
+	0x81, 0x8b, 0x57, 0xaf, 0x16, 0x16, 0x6f, 0x61, 0x2d, 0xb1, 0x78, 0x8b, 0xfb, 0x87, 0x0e, 0x8f,
+	0x9e, 0x7b, 0x06, 0x9c, 0xbb, 0x77, 0xd8, 0xf0, 0x14, 0x36, 0x09, 0x71, 0x6b, 0x0f, 0x20, 0xef,
+	0x1a, 0xc4, 0x00, 0xbb, 0xd2, 0x38, 0xbb, 0x6f, 0x4f, 0x39, 0x71, 0x4d, 0xab, 0xea, 0xb2, 0xa6,
+	0xdd, 0x1e, 0xd2, 0x5d, 0x2e, 0x32, 0xed, 0xad, 0x7b, 0x30, 0x8b, 0x35, 0x42, 0x56, 0xec, 0x8b,
+	0xbb, 0x95, 0xd8, 0xbd, 0x35, 0xc9, 0x76, 0x2d, 0xaa, 0x34, 0xb7, 0x16, 0xdd, 0xd2, 0xb2, 0x16,
+	0x0b, 0x95, 0xa0, 0x2d, 0x62, 0x46, 0x5a, 0x8b, 0xc5, 0x1a, 0xb0, 0x16, 0x27, 0x12, 0x97, 0xce,
+	0x3c, 0xdc, 0xfe, 0x61, 0xeb, 0x24, 0xe4, 0xa7, 0xe3, 0xa3, 0x5e, 0x3f, 0x1e, 0xae, 0x0f, 0xc3,
+	0x7e, 0x1a, 0xe3, 0xdf, 0xf3, 0xad, 0xf5, 0x29, 0x3f, 0x9e, 0xef, 0xab, 0xef, 0xa3, 0x9a, 0x22,
+	0xb6, 0xfe, 0x06, 0x72, 0x24, 0x19, 0xd1, 0x60, 0x0f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -947,6 +2045,16 @@ type StoreClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Store_ListClient, error)
 	Databases(ctx context.Context, in *DatabasesRequest, opts ...grpc.CallOption) (*DatabasesResponse, error)
 	Tables(ctx context.Context, in *TablesRequest, opts ...grpc.CallOption) (*TablesResponse, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	BulkUpsert(ctx context.Context, in *BulkUpsertRequest, opts ...grpc.CallOption) (*BulkUpsertResponse, error)
+	// Trash lists the records deleted from a table which can be restored
+	Trash(ctx context.Context, in *TrashRequest, opts ...grpc.CallOption) (*TrashResponse, error)
+	// Restore a deleted record from the trash
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
+	// Purge deleted records from the trash permanently
+	Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error)
+	// Protect a table from deletes
+	Protect(ctx context.Context, in *ProtectRequest, opts ...grpc.CallOption) (*ProtectResponse, error)
 }
 
 type storeClient struct {
@@ -1034,6 +2142,60 @@ func (c *storeClient) Tables(ctx context.Context, in *TablesRequest, opts ...grp
 	return out, nil
 }
 
+func (c *storeClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/store.Store/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) BulkUpsert(ctx context.Context, in *BulkUpsertRequest, opts ...grpc.CallOption) (*BulkUpsertResponse, error) {
+	out := new(BulkUpsertResponse)
+	err := c.cc.Invoke(ctx, "/store.Store/BulkUpsert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Trash(ctx context.Context, in *TrashRequest, opts ...grpc.CallOption) (*TrashResponse, error) {
+	out := new(TrashResponse)
+	err := c.cc.Invoke(ctx, "/store.Store/Trash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, "/store.Store/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error) {
+	out := new(PurgeResponse)
+	err := c.cc.Invoke(ctx, "/store.Store/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Protect(ctx context.Context, in *ProtectRequest, opts ...grpc.CallOption) (*ProtectResponse, error) {
+	out := new(ProtectResponse)
+	err := c.cc.Invoke(ctx, "/store.Store/Protect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StoreServer is the server API for Store service.
 type StoreServer interface {
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
@@ -1042,6 +2204,16 @@ type StoreServer interface {
 	List(*ListRequest, Store_ListServer) error
 	Databases(context.Context, *DatabasesRequest) (*DatabasesResponse, error)
 	Tables(context.Context, *TablesRequest) (*TablesResponse, error)
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
+	BulkUpsert(context.Context, *BulkUpsertRequest) (*BulkUpsertResponse, error)
+	// Trash lists the records deleted from a table which can be restored
+	Trash(context.Context, *TrashRequest) (*TrashResponse, error)
+	// Restore a deleted record from the trash
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
+	// Purge deleted records from the trash permanently
+	Purge(context.Context, *PurgeRequest) (*PurgeResponse, error)
+	// Protect a table from deletes
+	Protect(context.Context, *ProtectRequest) (*ProtectResponse, error)
 }
 
 // UnimplementedStoreServer can be embedded to have forward compatible implementations.
@@ -1066,6 +2238,24 @@ func (*UnimplementedStoreServer) Databases(ctx context.Context, req *DatabasesRe
 func (*UnimplementedStoreServer) Tables(ctx context.Context, req *TablesRequest) (*TablesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Tables not implemented")
 }
+func (*UnimplementedStoreServer) Stats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (*UnimplementedStoreServer) BulkUpsert(ctx context.Context, req *BulkUpsertRequest) (*BulkUpsertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkUpsert not implemented")
+}
+func (*UnimplementedStoreServer) Trash(ctx context.Context, req *TrashRequest) (*TrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Trash not implemented")
+}
+func (*UnimplementedStoreServer) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedStoreServer) Purge(ctx context.Context, req *PurgeRequest) (*PurgeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (*UnimplementedStoreServer) Protect(ctx context.Context, req *ProtectRequest) (*ProtectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Protect not implemented")
+}
 
 func RegisterStoreServer(s *grpc.Server, srv StoreServer) {
 	s.RegisterService(&_Store_serviceDesc, srv)
@@ -1182,6 +2372,114 @@ func _Store_Tables_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Store_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.Store/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_BulkUpsert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkUpsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).BulkUpsert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.Store/BulkUpsert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).BulkUpsert(ctx, req.(*BulkUpsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Trash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Trash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.Store/Trash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Trash(ctx, req.(*TrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.Store/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.Store/Purge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Purge(ctx, req.(*PurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Protect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Protect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.Store/Protect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Protect(ctx, req.(*ProtectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Store_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "store.Store",
 	HandlerType: (*StoreServer)(nil),
@@ -1206,6 +2504,30 @@ var _Store_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Tables",
 			Handler:    _Store_Tables_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _Store_Stats_Handler,
+		},
+		{
+			MethodName: "BulkUpsert",
+			Handler:    _Store_BulkUpsert_Handler,
+		},
+		{
+			MethodName: "Trash",
+			Handler:    _Store_Trash_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Store_Restore_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _Store_Purge_Handler,
+		},
+		{
+			MethodName: "Protect",
+			Handler:    _Store_Protect_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -48,6 +48,12 @@ type StoreService interface {
 	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (Store_ListService, error)
 	Databases(ctx context.Context, in *DatabasesRequest, opts ...client.CallOption) (*DatabasesResponse, error)
 	Tables(ctx context.Context, in *TablesRequest, opts ...client.CallOption) (*TablesResponse, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error)
+	BulkUpsert(ctx context.Context, in *BulkUpsertRequest, opts ...client.CallOption) (*BulkUpsertResponse, error)
+	Trash(ctx context.Context, in *TrashRequest, opts ...client.CallOption) (*TrashResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...client.CallOption) (*RestoreResponse, error)
+	Purge(ctx context.Context, in *PurgeRequest, opts ...client.CallOption) (*PurgeResponse, error)
+	Protect(ctx context.Context, in *ProtectRequest, opts ...client.CallOption) (*ProtectResponse, error)
 }
 
 type storeService struct {
@@ -161,6 +167,66 @@ func (c *storeService) Tables(ctx context.Context, in *TablesRequest, opts ...cl
 	return out, nil
 }
 
+func (c *storeService) Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Stats", in)
+	out := new(StatsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) BulkUpsert(ctx context.Context, in *BulkUpsertRequest, opts ...client.CallOption) (*BulkUpsertResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.BulkUpsert", in)
+	out := new(BulkUpsertResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) Trash(ctx context.Context, in *TrashRequest, opts ...client.CallOption) (*TrashResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Trash", in)
+	out := new(TrashResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) Restore(ctx context.Context, in *RestoreRequest, opts ...client.CallOption) (*RestoreResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Restore", in)
+	out := new(RestoreResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) Purge(ctx context.Context, in *PurgeRequest, opts ...client.CallOption) (*PurgeResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Purge", in)
+	out := new(PurgeResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) Protect(ctx context.Context, in *ProtectRequest, opts ...client.CallOption) (*ProtectResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Protect", in)
+	out := new(ProtectResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Store service
 
 type StoreHandler interface {
@@ -170,6 +236,12 @@ type StoreHandler interface {
 	List(context.Context, *ListRequest, Store_ListStream) error
 	Databases(context.Context, *DatabasesRequest, *DatabasesResponse) error
 	Tables(context.Context, *TablesRequest, *TablesResponse) error
+	Stats(context.Context, *StatsRequest, *StatsResponse) error
+	BulkUpsert(context.Context, *BulkUpsertRequest, *BulkUpsertResponse) error
+	Trash(context.Context, *TrashRequest, *TrashResponse) error
+	Restore(context.Context, *RestoreRequest, *RestoreResponse) error
+	Purge(context.Context, *PurgeRequest, *PurgeResponse) error
+	Protect(context.Context, *ProtectRequest, *ProtectResponse) error
 }
 
 func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.HandlerOption) error {
@@ -180,6 +252,12 @@ func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.Han
 		List(ctx context.Context, stream server.Stream) error
 		Databases(ctx context.Context, in *DatabasesRequest, out *DatabasesResponse) error
 		Tables(ctx context.Context, in *TablesRequest, out *TablesResponse) error
+		Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error
+		BulkUpsert(ctx context.Context, in *BulkUpsertRequest, out *BulkUpsertResponse) error
+		Trash(ctx context.Context, in *TrashRequest, out *TrashResponse) error
+		Restore(ctx context.Context, in *RestoreRequest, out *RestoreResponse) error
+		Purge(ctx context.Context, in *PurgeRequest, out *PurgeResponse) error
+		Protect(ctx context.Context, in *ProtectRequest, out *ProtectResponse) error
 	}
 	type Store struct {
 		store
@@ -251,3 +329,27 @@ func (h *storeHandler) Databases(ctx context.Context, in *DatabasesRequest, out
 func (h *storeHandler) Tables(ctx context.Context, in *TablesRequest, out *TablesResponse) error {
 	return h.StoreHandler.Tables(ctx, in, out)
 }
+
+func (h *storeHandler) Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error {
+	return h.StoreHandler.Stats(ctx, in, out)
+}
+
+func (h *storeHandler) BulkUpsert(ctx context.Context, in *BulkUpsertRequest, out *BulkUpsertResponse) error {
+	return h.StoreHandler.BulkUpsert(ctx, in, out)
+}
+
+func (h *storeHandler) Trash(ctx context.Context, in *TrashRequest, out *TrashResponse) error {
+	return h.StoreHandler.Trash(ctx, in, out)
+}
+
+func (h *storeHandler) Restore(ctx context.Context, in *RestoreRequest, out *RestoreResponse) error {
+	return h.StoreHandler.Restore(ctx, in, out)
+}
+
+func (h *storeHandler) Purge(ctx context.Context, in *PurgeRequest, out *PurgeResponse) error {
+	return h.StoreHandler.Purge(ctx, in, out)
+}
+
+func (h *storeHandler) Protect(ctx context.Context, in *ProtectRequest, out *ProtectResponse) error {
+	return h.StoreHandler.Protect(ctx, in, out)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/bulk"
+	pb "github.com/micro/micro/v3/service/store/proto"
+)
+
+// BulkUpsert writes the records whose conditions are met, returning the result of each
+func (h *handler) BulkUpsert(ctx context.Context, req *pb.BulkUpsertRequest, rsp *pb.BulkUpsertResponse) error {
+	// validate the request
+	if len(req.Upserts) == 0 {
+		return errors.BadRequest("store.Store.BulkUpsert", "no records specified")
+	}
+	if len(req.Upserts) > bulk.MaxUpserts {
+		return errors.BadRequest("store.Store.BulkUpsert", "too many records, the maximum is %v", bulk.MaxUpserts)
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.WriteOptions{}
+	}
+	if len(req.Options.Database) == 0 {
+		req.Options.Database = defaultDatabase
+	}
+	if len(req.Options.Table) == 0 {
+		req.Options.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Database); err == namespace.ErrForbidden {
+		return errors.Forbidden("store.Store.BulkUpsert", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("store.Store.BulkUpsert", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.BulkUpsert", err.Error())
+	}
+
+	// setup the store
+	if err := h.setupTable(req.Options.Database, req.Options.Table); err != nil {
+		return errors.InternalServerError("store.Store.BulkUpsert", err.Error())
+	}
+
+	// construct the upserts
+	upserts := make([]*bulk.Upsert, len(req.Upserts))
+	for i, u := range req.Upserts {
+		if u == nil || u.Record == nil {
+			upserts[i] = nil
+			continue
+		}
+		metadata := make(map[string]interface{})
+		for k, v := range u.Record.Metadata {
+			metadata[k] = v.Value
+		}
+		upserts[i] = &bulk.Upsert{
+			Record: &gostore.Record{
+				Key:      u.Record.Key,
+				Value:    u.Record.Value,
+				Expiry:   time.Duration(u.Record.Expiry) * time.Second,
+				Metadata: metadata,
+			},
+		}
+		if c := u.Condition; c != nil {
+			upserts[i].Condition = bulk.Condition{NotExists: c.NotExists, Exists: c.Exists, ETag: c.Etag}
+		}
+	}
+
+	// write to the store
+	start := time.Now()
+	results, err := bulk.Write(store.DefaultStore, upserts, gostore.WriteTo(req.Options.Database, req.Options.Table))
+	h.stats.record(req.Options.Database, req.Options.Table, "bulk_upsert", "", time.Since(start), err)
+	if err != nil {
+		return errors.InternalServerError("store.Store.BulkUpsert", err.Error())
+	}
+
+	// serialize the response
+	rsp.Results = make([]*pb.UpsertResult, len(results))
+	for i, r := range results {
+		rsp.Results[i] = &pb.UpsertResult{
+			Key:      r.Key,
+			Written:  r.Written,
+			Conflict: r.Conflict,
+			Error:    r.Error,
+			Etag:     r.ETag,
+		}
+	}
+	return nil
+}
+
+// lockKey locks the key against the upserts applied by the store service, unless the store
+// applies them in transactions. It returns the function which unlocks the key.
+func lockKey(database, table, key string) func() {
+	if _, ok := store.DefaultStore.(bulk.Upserter); ok {
+		return func() {}
+	}
+	return bulk.Lock(database, table, key)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/store"
 	pb "github.com/micro/micro/v3/service/store/proto"
+	"github.com/micro/micro/v3/service/store/trash"
 )
 
 const (
@@ -26,6 +27,10 @@ type handler struct {
 	// local stores cache
 	sync.RWMutex
 	stores map[string]bool
+	// usage statistics of the tables
+	stats *stats
+	// retention of deleted records in the trash, zero deletes them permanently
+	retention time.Duration
 }
 
 // List all the keys in a table
@@ -70,7 +75,9 @@ func (h *handler) List(ctx context.Context, req *pb.ListRequest, stream pb.Store
 	}
 
 	// list from the store
+	start := time.Now()
 	vals, err := store.List(opts...)
+	h.stats.record(req.Options.Database, req.Options.Table, "list", "", time.Since(start), err)
 	if err != nil && err == gostore.ErrNotFound {
 		return errors.NotFound("store.Store.List", err.Error())
 	} else if err != nil {
@@ -136,7 +143,9 @@ func (h *handler) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadRes
 	}
 
 	// read from the database
+	start := time.Now()
 	vals, err := store.Read(req.Key, opts...)
+	h.stats.record(req.Options.Database, req.Options.Table, "read", req.Key, time.Since(start), err)
 	if err != nil && err == gostore.ErrNotFound {
 		return errors.NotFound("store.Store.Read", err.Error())
 	} else if err != nil {
@@ -212,7 +221,11 @@ func (h *handler) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.Write
 	}
 
 	// write to the store
+	start := time.Now()
+	unlock := lockKey(req.Options.Database, req.Options.Table, req.Record.Key)
 	err := store.Write(record, opts...)
+	unlock()
+	h.stats.record(req.Options.Database, req.Options.Table, "write", req.Record.Key, time.Since(start), err)
 	if err != nil && err == gostore.ErrNotFound {
 		return errors.NotFound("store.Store.Write", err.Error())
 	} else if err != nil {
@@ -222,6 +235,7 @@ func (h *handler) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.Write
 	return nil
 }
 
+// Delete a record, moving it to the trash
 func (h *handler) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.DeleteResponse) error {
 	// set defaults
 	if req.Options == nil {
@@ -248,14 +262,27 @@ func (h *handler) Delete(ctx context.Context, req *pb.DeleteRequest, rsp *pb.Del
 		return errors.InternalServerError("store.Store.Delete", err.Error())
 	}
 
-	// setup the options
-	opts := []gostore.DeleteOption{
-		gostore.DeleteFrom(req.Options.Database, req.Options.Table),
+	// check the table isn't protected from deletes
+	if err := h.checkProtected("store.Store.Delete", req.Options.Database, req.Options.Table); err != nil {
+		return err
 	}
 
-	// delete from the store
-	if err := store.Delete(req.Key, opts...); err == gostore.ErrNotFound {
+	// move the record to the trash, or delete it from the store if the trash is disabled
+	start := time.Now()
+	var err error
+	if h.retention > 0 {
+		// the record is only deleted if it's unchanged since it was copied to the trash
+		err = trash.Delete(store.DefaultStore, req.Options.Database, req.Options.Table, req.Key, h.retention)
+	} else {
+		unlock := lockKey(req.Options.Database, req.Options.Table, req.Key)
+		err = store.Delete(req.Key, gostore.DeleteFrom(req.Options.Database, req.Options.Table))
+		unlock()
+	}
+	h.stats.record(req.Options.Database, req.Options.Table, "delete", req.Key, time.Since(start), err)
+	if err == gostore.ErrNotFound {
 		return errors.NotFound("store.Store.Delete", err.Error())
+	} else if err == trash.ErrConflict {
+		return errors.Conflict("store.Store.Delete", err.Error())
 	} else if err != nil {
 		return errors.InternalServerError("store.Store.Delete", err.Error())
 	}
@@ -329,6 +356,47 @@ func (h *handler) Tables(ctx context.Context, req *pb.TablesRequest, rsp *pb.Tab
 	return nil
 }
 
+// Stats returns the usage statistics of a table
+func (h *handler) Stats(ctx context.Context, req *pb.StatsRequest, rsp *pb.StatsResponse) error {
+	// set defaults
+	if len(req.Database) == 0 {
+		req.Database = defaultDatabase
+	}
+	if len(req.Table) == 0 {
+		req.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Database); err == namespace.ErrForbidden {
+		return errors.Forbidden("store.Store.Stats", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("store.Store.Stats", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.Stats", err.Error())
+	}
+
+	// count the records and their key sizes
+	keys, err := store.List(gostore.ListFrom(req.Database, req.Table))
+	if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("store.Store.Stats", err.Error())
+	}
+	var total int64
+	for _, k := range keys {
+		total += int64(len(k))
+		if int64(len(k)) > rsp.MaxKeySize {
+			rsp.MaxKeySize = int64(len(k))
+		}
+	}
+	rsp.Records = int64(len(keys))
+	if len(keys) > 0 {
+		rsp.AvgKeySize = total / int64(len(keys))
+	}
+
+	// read the operations recorded by the handler
+	h.stats.read(req.Database, req.Table, rsp)
+	return nil
+}
+
 func (h *handler) setupTable(database, table string) error {
 	// lock (might be a race)
 	h.Lock()
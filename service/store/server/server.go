@@ -5,6 +5,7 @@ import (
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
 	pb "github.com/micro/micro/v3/service/store/proto"
+	"github.com/micro/micro/v3/service/store/trash"
 )
 
 var (
@@ -14,6 +15,22 @@ var (
 	address = ":8002"
 )
 
+// Flags specific to the store service
+var Flags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:    "slow_operation_threshold",
+		Usage:   "Duration after which store operations are logged as slow, 0 disables the slow operation log",
+		EnvVars: []string{"MICRO_STORE_SLOW_OPERATION_THRESHOLD"},
+		Value:   DefaultSlowThreshold,
+	},
+	&cli.DurationFlag{
+		Name:    "trash_retention",
+		Usage:   "How long deleted records are kept in the trash to be restored, by default they're deleted permanently",
+		EnvVars: []string{"MICRO_STORE_TRASH_RETENTION"},
+		Value:   trash.DefaultRetention,
+	},
+}
+
 // Run micro store
 func Run(ctx *cli.Context) error {
 	if len(ctx.String("server_name")) > 0 {
@@ -31,7 +48,9 @@ func Run(ctx *cli.Context) error {
 
 	// the store handler
 	pb.RegisterStoreHandler(service.Server(), &handler{
-		stores: make(map[string]bool),
+		stores:    make(map[string]bool),
+		stats:     newStats(ctx.Duration("slow_operation_threshold")),
+		retention: ctx.Duration("trash_retention"),
 	})
 
 	// start the service
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	pb "github.com/micro/micro/v3/service/store/proto"
+)
+
+var (
+	// DefaultSlowThreshold is the duration after which an operation is logged as slow
+	DefaultSlowThreshold = time.Millisecond * 100
+	// DefaultLatencySamples is the number of latencies kept per operation to calculate percentiles
+	DefaultLatencySamples = 1000
+	// DefaultSlowOperations is the number of slow operations kept per table
+	DefaultSlowOperations = 100
+	// DefaultHotKeys is the number of hot keys returned per table
+	DefaultHotKeys = 10
+	// maxTrackedKeys is the number of keys tracked per table before the least
+	// accessed are dropped
+	maxTrackedKeys = 10000
+)
+
+// stats collects the usage statistics of the tables in memory
+type stats struct {
+	sync.Mutex
+	since     time.Time
+	threshold time.Duration
+	tables    map[string]*tableStats
+}
+
+type tableStats struct {
+	operations map[string]*operationStats
+	keys       map[string]int64
+	slow       []*pb.SlowOperation
+}
+
+type operationStats struct {
+	count  int64
+	errors int64
+	// ring buffer of the most recent latencies
+	samples []time.Duration
+	next    int
+}
+
+func newStats(threshold time.Duration) *stats {
+	return &stats{
+		since:     time.Now(),
+		threshold: threshold,
+		tables:    make(map[string]*tableStats),
+	}
+}
+
+// record an operation against a table, keys which aren't found aren't counted as errors
+func (s *stats) record(database, table, op, key string, d time.Duration, err error) {
+	if err == gostore.ErrNotFound {
+		err = nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	t, ok := s.tables[database+":"+table]
+	if !ok {
+		t = &tableStats{
+			operations: make(map[string]*operationStats),
+			keys:       make(map[string]int64),
+		}
+		s.tables[database+":"+table] = t
+	}
+
+	o, ok := t.operations[op]
+	if !ok {
+		o = &operationStats{}
+		t.operations[op] = o
+	}
+	o.count++
+	if err != nil {
+		o.errors++
+	}
+	if len(o.samples) < DefaultLatencySamples {
+		o.samples = append(o.samples, d)
+	} else {
+		o.samples[o.next] = d
+		o.next = (o.next + 1) % len(o.samples)
+	}
+
+	if len(key) > 0 {
+		t.keys[key]++
+		if len(t.keys) > maxTrackedKeys {
+			t.evictKeys()
+		}
+	}
+
+	if s.threshold > 0 && d >= s.threshold {
+		slow := &pb.SlowOperation{
+			Operation: op,
+			Key:       key,
+			Duration:  d.Nanoseconds(),
+			Timestamp: time.Now().Unix(),
+		}
+		if err != nil {
+			slow.Error = err.Error()
+		}
+		t.slow = append(t.slow, slow)
+		if len(t.slow) > DefaultSlowOperations {
+			t.slow = t.slow[len(t.slow)-DefaultSlowOperations:]
+		}
+	}
+}
+
+// evictKeys halves the access counts of the keys, dropping those which reach zero, so
+// the tracked keys stay bounded and favour recent access
+func (t *tableStats) evictKeys() {
+	for k, c := range t.keys {
+		if c/2 == 0 {
+			delete(t.keys, k)
+		} else {
+			t.keys[k] = c / 2
+		}
+	}
+}
+
+// read the stats of the table into the response
+func (s *stats) read(database, table string, rsp *pb.StatsResponse) {
+	s.Lock()
+	defer s.Unlock()
+
+	rsp.Since = s.since.Unix()
+
+	t, ok := s.tables[database+":"+table]
+	if !ok {
+		return
+	}
+
+	for name, o := range t.operations {
+		samples := make([]time.Duration, len(o.samples))
+		copy(samples, o.samples)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+		rsp.Operations = append(rsp.Operations, &pb.OperationStats{
+			Operation: name,
+			Count:     o.count,
+			Errors:    o.errors,
+			P50:       percentile(samples, 50).Nanoseconds(),
+			P95:       percentile(samples, 95).Nanoseconds(),
+			P99:       percentile(samples, 99).Nanoseconds(),
+		})
+	}
+	sort.Slice(rsp.Operations, func(i, j int) bool {
+		return rsp.Operations[i].Operation < rsp.Operations[j].Operation
+	})
+
+	for k, c := range t.keys {
+		rsp.HotKeys = append(rsp.HotKeys, &pb.HotKey{Key: k, Count: c})
+	}
+	sort.Slice(rsp.HotKeys, func(i, j int) bool {
+		if rsp.HotKeys[i].Count == rsp.HotKeys[j].Count {
+			return rsp.HotKeys[i].Key < rsp.HotKeys[j].Key
+		}
+		return rsp.HotKeys[i].Count > rsp.HotKeys[j].Count
+	})
+	if len(rsp.HotKeys) > DefaultHotKeys {
+		rsp.HotKeys = rsp.HotKeys[:DefaultHotKeys]
+	}
+
+	// return the most recent slow operations first
+	for i := len(t.slow) - 1; i >= 0; i-- {
+		rsp.SlowOperations = append(rsp.SlowOperations, t.slow[i])
+	}
+}
+
+// percentile of the sorted samples
+func percentile(samples []time.Duration, p int) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	i := (len(samples)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return samples[i]
+}
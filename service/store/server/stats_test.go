@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	pb "github.com/micro/micro/v3/service/store/proto"
+)
+
+func TestStats(t *testing.T) {
+	s := newStats(time.Millisecond * 50)
+
+	for i := 1; i <= 100; i++ {
+		s.record("micro", "users", "read", "foo", time.Duration(i)*time.Millisecond, nil)
+	}
+	s.record("micro", "users", "read", "bar", time.Millisecond, gostore.ErrNotFound)
+	s.record("micro", "users", "write", "bar", time.Millisecond, errors.New("write failed"))
+	s.record("micro", "other", "read", "baz", time.Millisecond, nil)
+
+	rsp := &pb.StatsResponse{}
+	s.read("micro", "users", rsp)
+
+	if len(rsp.Operations) != 2 {
+		t.Fatalf("Expected 2 operations, got %v", len(rsp.Operations))
+	}
+	read := rsp.Operations[0]
+	if read.Operation != "read" || read.Count != 101 || read.Errors != 0 {
+		t.Fatalf("Unexpected read stats: %+v", read)
+	}
+	if read.P50 != (time.Millisecond*50).Nanoseconds() || read.P99 != (time.Millisecond*99).Nanoseconds() {
+		t.Fatalf("Unexpected read percentiles: %+v", read)
+	}
+	if write := rsp.Operations[1]; write.Errors != 1 {
+		t.Fatalf("Expected the write error to be counted, got %+v", write)
+	}
+
+	if len(rsp.HotKeys) != 2 || rsp.HotKeys[0].Key != "foo" || rsp.HotKeys[0].Count != 100 {
+		t.Fatalf("Unexpected hot keys: %v", rsp.HotKeys)
+	}
+
+	if len(rsp.SlowOperations) != 51 {
+		t.Fatalf("Expected 51 slow operations, got %v", len(rsp.SlowOperations))
+	}
+	if rsp.SlowOperations[0].Duration != (time.Millisecond * 100).Nanoseconds() {
+		t.Fatalf("Expected the most recent slow operation first")
+	}
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	pb "github.com/micro/micro/v3/service/store/proto"
+	"github.com/micro/micro/v3/service/store/trash"
+)
+
+// protected returns true if the table is protected from deletes
+func (h *handler) protected(database, table string) (bool, error) {
+	recs, err := store.Read("protected/"+database+"/"+table, gostore.ReadFrom(defaultDatabase, internalTable))
+	if err == gostore.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return len(recs) > 0, nil
+}
+
+// checkProtected returns an error if the table is protected from deletes
+func (h *handler) checkProtected(id, database, table string) error {
+	protected, err := h.protected(database, table)
+	if err != nil {
+		return errors.InternalServerError(id, err.Error())
+	}
+	if protected {
+		return errors.PreconditionFailed(id, "table %v/%v is protected from deletes", database, table)
+	}
+	return nil
+}
+
+// Trash lists the records deleted from a table which can be restored
+func (h *handler) Trash(ctx context.Context, req *pb.TrashRequest, rsp *pb.TrashResponse) error {
+	// set defaults
+	if len(req.Database) == 0 {
+		req.Database = defaultDatabase
+	}
+	if len(req.Table) == 0 {
+		req.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Database); err == namespace.ErrForbidden {
+		return errors.Forbidden("store.Store.Trash", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("store.Store.Trash", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.Trash", err.Error())
+	}
+
+	recs, err := trash.List(store.DefaultStore, req.Database, req.Table, req.Prefix)
+	if err != nil {
+		return errors.InternalServerError("store.Store.Trash", err.Error())
+	}
+
+	// serialize the response
+	rsp.Records = make([]*pb.TrashedRecord, len(recs))
+	for i, r := range recs {
+		rsp.Records[i] = &pb.TrashedRecord{Key: r.Key, Deleted: r.Deleted.Unix()}
+		if !r.Expires.IsZero() {
+			rsp.Records[i].Expires = r.Expires.Unix()
+		}
+	}
+	return nil
+}
+
+// Restore a deleted record from the trash
+func (h *handler) Restore(ctx context.Context, req *pb.RestoreRequest, rsp *pb.RestoreResponse) error {
+	// validate the request
+	if len(req.Key) == 0 {
+		return errors.BadRequest("store.Store.Restore", "no key specified")
+	}
+
+	// set defaults
+	if len(req.Database) == 0 {
+		req.Database = defaultDatabase
+	}
+	if len(req.Table) == 0 {
+		req.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Database); err == namespace.ErrForbidden {
+		return errors.Forbidden("store.Store.Restore", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("store.Store.Restore", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.Restore", err.Error())
+	}
+
+	// restore the record
+	start := time.Now()
+	err := trash.Restore(store.DefaultStore, req.Database, req.Table, req.Key)
+	h.stats.record(req.Database, req.Table, "restore", req.Key, time.Since(start), err)
+	if err == gostore.ErrNotFound {
+		return errors.NotFound("store.Store.Restore", "%v isn't in the trash", req.Key)
+	} else if err == trash.ErrExists {
+		return errors.Conflict("store.Store.Restore", "%v has been written since it was deleted", req.Key)
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.Restore", err.Error())
+	}
+	return nil
+}
+
+// Purge deleted records from the trash permanently
+func (h *handler) Purge(ctx context.Context, req *pb.PurgeRequest, rsp *pb.PurgeResponse) error {
+	// validate the request
+	if len(req.Key) == 0 && !req.All {
+		return errors.BadRequest("store.Store.Purge", "no key specified")
+	}
+
+	// set defaults
+	if len(req.Database) == 0 {
+		req.Database = defaultDatabase
+	}
+	if len(req.Table) == 0 {
+		req.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Database); err == namespace.ErrForbidden {
+		return errors.Forbidden("store.Store.Purge", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("store.Store.Purge", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.Purge", err.Error())
+	}
+
+	// purging is a delete, so protected tables keep their trash until they're unprotected
+	if err := h.checkProtected("store.Store.Purge", req.Database, req.Table); err != nil {
+		return err
+	}
+
+	keys := []string{req.Key}
+	if req.All {
+		recs, err := trash.List(store.DefaultStore, req.Database, req.Table, "")
+		if err != nil {
+			return errors.InternalServerError("store.Store.Purge", err.Error())
+		}
+		keys = make([]string, len(recs))
+		for i, r := range recs {
+			keys[i] = r.Key
+		}
+	}
+
+	n, err := trash.Purge(store.DefaultStore, req.Database, req.Table, keys...)
+	rsp.Purged = int64(n)
+	if err != nil {
+		return errors.InternalServerError("store.Store.Purge", err.Error())
+	}
+	if n == 0 && !req.All {
+		return errors.NotFound("store.Store.Purge", "%v isn't in the trash", req.Key)
+	}
+	return nil
+}
+
+// Protect a table from deletes, or remove its protection
+func (h *handler) Protect(ctx context.Context, req *pb.ProtectRequest, rsp *pb.ProtectResponse) error {
+	// set defaults
+	if len(req.Database) == 0 {
+		req.Database = defaultDatabase
+	}
+	if len(req.Table) == 0 {
+		req.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Database); err == namespace.ErrForbidden {
+		return errors.Forbidden("store.Store.Protect", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("store.Store.Protect", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.Protect", err.Error())
+	}
+
+	// the protection is recorded in the internal store
+	key := "protected/" + req.Database + "/" + req.Table
+	opt := gostore.WriteTo(defaultDatabase, internalTable)
+	var err error
+	if req.Protected {
+		err = store.Write(&gostore.Record{Key: key, Value: []byte{}}, opt)
+	} else {
+		err = store.Delete(key, gostore.DeleteFrom(defaultDatabase, internalTable))
+	}
+	if err != nil && err != gostore.ErrNotFound {
+		return errors.InternalServerError("store.Store.Protect", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package shard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+// ErrResharding is returned by Reshard if the store is already being resharded
+var ErrResharding = errors.New("store is already being resharded")
+
+const (
+	// ringTable is the table the ring is recorded in, in every shard
+	ringTable = "shard_ring"
+	// ringKey is the key the ring is recorded under
+	ringKey = "ring"
+)
+
+// ringState is the ring recorded in the shards
+type ringState struct {
+	// Version is incremented each time the ring changes
+	Version int64 `json:"version"`
+	// Shards of the ring
+	Shards []ringShard `json:"shards"`
+	// Previous shards whilst resharding, the keys not yet moved are read from them
+	Previous []ringShard `json:"previous,omitempty"`
+}
+
+type ringShard struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+func toRingShards(shards []Shard) []ringShard {
+	rs := make([]ringShard, len(shards))
+	for i, sh := range shards {
+		rs[i] = ringShard{Name: sh.Name, Address: sh.Address}
+	}
+	return rs
+}
+
+// Reshard moves the keys to the new shards. The store stays online: keys are written to their
+// new shard straight away and read from their previous shard until they're moved. Progress is
+// called with the number of keys moved after each key, if it's not nil. If a key can't be moved
+// the store keeps resharding and ResumeReshard moves the keys left.
+//
+// The new ring is recorded in the shards and the keys are only moved once the other processes
+// have had the refresh interval to switch to it. The new shards must be initialised and have
+// the address other processes connect to them with. Only the keys in the database and table the
+// shards were initialised with are moved, and shards which are removed aren't closed.
+func (s *Store) Reshard(shards []Shard, progress func(moved int)) error {
+	s.reshardMtx.Lock()
+	defer s.reshardMtx.Unlock()
+
+	// another process may have resharded since the ring was last checked
+	if err := s.refresh(); err != nil {
+		return err
+	}
+
+	s.RLock()
+	resharding := s.previous != nil
+	state := &ringState{
+		Version:  s.version + 1,
+		Shards:   toRingShards(shards),
+		Previous: toRingShards(s.layout),
+	}
+	s.RUnlock()
+	if resharding {
+		return ErrResharding
+	}
+
+	if err := s.record(state, shards); err != nil {
+		return err
+	}
+	s.switchRing(state.Version, shards, s.currentLayout())
+
+	return s.moveKeys(progress)
+}
+
+// ResumeReshard moves the keys left to move by a resharding which failed, or which was started
+// by another process which stopped
+func (s *Store) ResumeReshard(progress func(moved int)) error {
+	s.reshardMtx.Lock()
+	defer s.reshardMtx.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	if !s.resharding() {
+		return nil
+	}
+	return s.moveKeys(progress)
+}
+
+// Version returns the version of the ring and whether the store is being resharded
+func (s *Store) Version() (int64, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.version, s.previous != nil
+}
+
+func (s *Store) currentLayout() []Shard {
+	s.RLock()
+	defer s.RUnlock()
+	return s.layout
+}
+
+// moveKeys moves the keys whose shard changed, then stops resharding
+func (s *Store) moveKeys(progress func(moved int)) error {
+	// processes switch to the new ring when they next refresh, until then they may write keys
+	// to their previous shard so the keys aren't moved until they have
+	if s.opts.RefreshInterval > 0 {
+		select {
+		case <-time.After(s.opts.RefreshInterval):
+		case <-s.exit:
+			return errors.New("store closed")
+		}
+	}
+
+	s.RLock()
+	ring, stores, layout := s.ring, s.shards, s.layout
+	previous, previousShards := s.previous, s.previousShards
+	version := s.version
+	s.RUnlock()
+
+	var moved int
+	for name, from := range previousShards {
+		keys, err := from.List()
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			// skip keys which were left behind by an earlier resharding
+			if previous.Get(key) != name {
+				continue
+			}
+			to := stores[ring.Get(key)]
+			if to == from {
+				continue
+			}
+			if err := s.move(key, from, to); err != nil {
+				return err
+			}
+			moved++
+			if progress != nil {
+				progress(moved)
+			}
+		}
+	}
+
+	// record the resharding has finished
+	state := &ringState{Version: version + 1, Shards: toRingShards(layout)}
+	if err := s.record(state, layout); err != nil {
+		return err
+	}
+	s.switchRing(state.Version, layout, nil)
+	return nil
+}
+
+// move the key between the shards, unless it's been written to its new shard since resharding
+// started. The key is only written if it doesn't exist on the new shard, which stores that
+// write conditionally check themselves so writes by other processes aren't overwritten.
+func (s *Store) move(key string, from, to store.Store) error {
+	s.moveMtx.Lock()
+	defer s.moveMtx.Unlock()
+
+	recs, err := from.Read(key)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	upserts := []*bulk.Upsert{{Record: recs[0], Condition: bulk.Condition{NotExists: true}}}
+	var results []*bulk.Result
+	if u, ok := to.(bulk.Upserter); ok {
+		results, err = u.BulkUpsert(upserts)
+	} else {
+		results = bulk.Apply(to, upserts)
+	}
+	if err != nil {
+		return err
+	} else if len(results) == 1 && len(results[0].Error) > 0 {
+		return errors.New(results[0].Error)
+	}
+
+	if err := from.Delete(key); err != nil && err != store.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// record the ring in its shards and its previous shards, so it's found by processes which know
+// either
+func (s *Store) record(state *ringState, shards []Shard) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	stores := append([]store.Store{}, s.all()...)
+	for _, sh := range shards {
+		stores = append(stores, sh.Store)
+	}
+	seen := make(map[store.Store]bool)
+	for _, st := range stores {
+		if seen[st] {
+			continue
+		}
+		seen[st] = true
+		rec := &store.Record{Key: ringKey, Value: b}
+		if err := st.Write(rec, store.WriteTo(s.options.Database, ringTable)); err != nil {
+			return fmt.Errorf("Error recording the ring: %v", err)
+		}
+	}
+	return nil
+}
+
+// refresh switches to the latest ring recorded in the shards, if it's newer than the ring used
+func (s *Store) refresh() error {
+	var latest *ringState
+	for _, st := range s.all() {
+		recs, err := st.Read(ringKey, store.ReadFrom(s.options.Database, ringTable))
+		if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		var state *ringState
+		if err := json.Unmarshal(recs[0].Value, &state); err != nil {
+			return err
+		}
+		if latest == nil || state.Version > latest.Version {
+			latest = state
+		}
+	}
+
+	s.RLock()
+	version := s.version
+	s.RUnlock()
+	if latest == nil || latest.Version <= version {
+		return nil
+	}
+
+	shards, err := s.dial(latest.Shards)
+	if err != nil {
+		return err
+	}
+	var previous []Shard
+	if len(latest.Previous) > 0 {
+		if previous, err = s.dial(latest.Previous); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Switching to version %v of the store shards", latest.Version)
+	s.switchRing(latest.Version, shards, previous)
+	return nil
+}
+
+// dial returns the shards, reusing the stores of the shards known
+func (s *Store) dial(rs []ringShard) ([]Shard, error) {
+	s.RLock()
+	known := make(map[string]Shard)
+	for _, l := range [][]Shard{s.layout, s.previousLayout} {
+		for _, sh := range l {
+			known[sh.Name] = sh
+		}
+	}
+	s.RUnlock()
+
+	shards := make([]Shard, len(rs))
+	for i, r := range rs {
+		if sh, ok := known[r.Name]; ok && sh.Address == r.Address {
+			shards[i] = sh
+			continue
+		}
+		if s.opts.Dial == nil || len(r.Address) == 0 {
+			return nil, fmt.Errorf("Unable to connect to store shard %v", r.Name)
+		}
+		// the shard is initialised with the database and table of the store
+		var opts []store.Option
+		if len(s.options.Database) > 0 {
+			opts = append(opts, store.Database(s.options.Database))
+		}
+		if len(s.options.Table) > 0 {
+			opts = append(opts, store.Table(s.options.Table))
+		}
+		st := s.opts.Dial(r.Address)
+		if err := st.Init(opts...); err != nil {
+			return nil, err
+		}
+		shards[i] = Shard{Name: r.Name, Address: r.Address, Store: st}
+	}
+	return shards, nil
+}
+
+// switchRing switches to the version of the ring, keeping the previous shards to read the keys
+// not yet moved from if there are any
+func (s *Store) switchRing(version int64, shards, previous []Shard) {
+	ring, stores := s.newRing(shards)
+
+	var prevRing *Ring
+	var prevStores map[string]store.Store
+	if len(previous) > 0 {
+		prevRing, prevStores = s.newRing(previous)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.version = version
+	s.ring, s.shards, s.layout = ring, stores, shards
+	s.previous, s.previousShards, s.previousLayout = prevRing, prevStores, previous
+}
+
+// run checks for newer versions of the ring until the store is closed
+func (s *Store) run() {
+	t := time.NewTicker(s.opts.RefreshInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := s.refresh(); err != nil {
+				logger.Errorf("Error refreshing the ring of the store shards: %v", err)
+			}
+		case <-s.exit:
+			return
+		}
+	}
+}
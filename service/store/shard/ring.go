@@ -0,0 +1,56 @@
+package shard
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// Ring assigns keys to shards by consistent hashing, so adding or removing a shard only moves
+// the keys of the shards either side of it. Each shard has a number of virtual nodes on the ring
+// so the keys are spread evenly.
+type Ring struct {
+	hashes []uint32
+	shards map[uint32]string
+}
+
+// NewRing returns a ring of the shards with the number of virtual nodes each
+func NewRing(shards []string, replicas int) *Ring {
+	if replicas <= 0 {
+		replicas = 1
+	}
+
+	r := &Ring{shards: make(map[uint32]string, len(shards)*replicas)}
+	for _, s := range shards {
+		for i := 0; i < replicas; i++ {
+			h := hash(s + "#" + strconv.Itoa(i))
+			// on a collision the shard which sorts first wins so the ring is deterministic
+			if existing, ok := r.shards[h]; ok && existing < s {
+				continue
+			} else if !ok {
+				r.hashes = append(r.hashes, h)
+			}
+			r.shards[h] = s
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// Get returns the shard which owns the key, blank if the ring is empty
+func (r *Ring) Get(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.shards[r.hashes[i]]
+}
+
+func hash(s string) uint32 {
+	return crc32.ChecksumIEEE([]byte(s))
+}
@@ -0,0 +1,382 @@
+// Package shard partitions the keys of a store across multiple store services or backends, for
+// datasets which outgrow a single backend. Keys are assigned to shards by consistent hashing, so
+// shards can be added or removed online with Reshard, which moves only the keys whose shard
+// changed. Reads made whilst resharding fall back to the previous shard of a key until it's
+// moved.
+//
+// The shards of the ring are versioned and recorded in every shard, so every process sharing the
+// shards switches to the ring a reshard started, whichever process started it, and processes
+// started with an outdated list of shards use the latest ring.
+//
+// Reads by prefix or suffix and lists are made on every shard and merged.
+package shard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultReplicas is the number of virtual nodes each shard has on the ring
+	DefaultReplicas = 100
+	// DefaultRefreshInterval is how often the ring recorded in the shards is checked for a newer
+	// version
+	DefaultRefreshInterval = time.Second * 10
+)
+
+// Shard of the keys
+type Shard struct {
+	// Name identifies the shard on the ring, so its keys stay with it if its address changes
+	Name string
+	// Address of the shard, which other processes connect to it with once it's recorded in
+	// the ring
+	Address string
+	Store   store.Store
+}
+
+// ParseShards parses a comma separated list of shards, e.g. a=10.0.0.1:8002,b=10.0.0.2:8002,
+// connecting to each with dial
+func ParseShards(s string, dial func(address string) store.Store) ([]Shard, error) {
+	var shards []Shard
+	for _, sh := range strings.Split(s, ",") {
+		parts := strings.SplitN(sh, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("invalid store shard %v, expected name=address", sh)
+		}
+		shards = append(shards, Shard{Name: parts[0], Address: parts[1], Store: dial(parts[1])})
+	}
+	return shards, nil
+}
+
+// Options of the store
+type Options struct {
+	// Replicas is the number of virtual nodes each shard has on the ring
+	Replicas int
+	// Dial returns the store of a shard added to the ring by another process
+	Dial func(address string) store.Store
+	// RefreshInterval is how often the ring recorded in the shards is checked for a newer
+	// version, zero to never check
+	RefreshInterval time.Duration
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Dial sets the func returning the store of a shard added to the ring by another process
+func Dial(fn func(address string) store.Store) Option {
+	return func(o *Options) {
+		o.Dial = fn
+	}
+}
+
+// RefreshInterval sets how often the ring recorded in the shards is checked for a newer version
+func RefreshInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.RefreshInterval = d
+	}
+}
+
+// Replicas sets the number of virtual nodes each shard has on the ring
+func Replicas(n int) Option {
+	return func(o *Options) {
+		o.Replicas = n
+	}
+}
+
+// Store partitions its keys across the shards
+type Store struct {
+	options store.Options
+	opts    Options
+
+	sync.RWMutex
+	// version of the ring recorded in the shards, zero if none is
+	version int64
+	ring    *Ring
+	shards  map[string]store.Store
+	layout  []Shard
+	// previous ring and shards whilst resharding, nil otherwise
+	previous       *Ring
+	previousShards map[string]store.Store
+	previousLayout []Shard
+
+	// reshardMtx serialises resharding within the process
+	reshardMtx sync.Mutex
+	exit       chan bool
+
+	// moveMtx serialises writes with moving keys whilst resharding, so a key being moved
+	// isn't overwritten with its previous value
+	moveMtx sync.Mutex
+}
+
+// NewStore returns a store which partitions its keys across the shards, or those of the ring
+// recorded in them if it's been resharded since
+func NewStore(shards []Shard, opts ...Option) *Store {
+	options := Options{Replicas: DefaultReplicas, RefreshInterval: DefaultRefreshInterval}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	s := &Store{opts: options, layout: shards, exit: make(chan bool)}
+	s.ring, s.shards = s.newRing(shards)
+	if err := s.refresh(); err != nil {
+		logger.Errorf("Error reading the ring of the store shards: %v", err)
+	}
+	if options.RefreshInterval > 0 {
+		go s.run()
+	}
+	return s
+}
+
+func (s *Store) newRing(shards []Shard) (*Ring, map[string]store.Store) {
+	names := make([]string, 0, len(shards))
+	stores := make(map[string]store.Store, len(shards))
+	for _, sh := range shards {
+		names = append(names, sh.Name)
+		stores[sh.Name] = sh.Store
+	}
+	return NewRing(names, s.opts.Replicas), stores
+}
+
+// owners returns the shard which owns the key and, whilst resharding, the shard which owned it
+// before if that's different
+func (s *Store) owners(key string) (store.Store, store.Store, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	owner, ok := s.shards[s.ring.Get(key)]
+	if !ok {
+		return nil, nil, fmt.Errorf("No shard for key %v", key)
+	}
+	if s.previous == nil {
+		return owner, nil, nil
+	}
+	if prev, ok := s.previousShards[s.previous.Get(key)]; ok && prev != owner {
+		return owner, prev, nil
+	}
+	return owner, nil, nil
+}
+
+// all returns every shard, including those of the previous ring whilst resharding. The
+// current shards are first.
+func (s *Store) all() []store.Store {
+	s.RLock()
+	defer s.RUnlock()
+
+	var stores []store.Store
+	seen := make(map[store.Store]bool)
+	for _, m := range []map[string]store.Store{s.shards, s.previousShards} {
+		names := make([]string, 0, len(m))
+		for n := range m {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			if !seen[m[n]] {
+				seen[m[n]] = true
+				stores = append(stores, m[n])
+			}
+		}
+	}
+	return stores
+}
+
+// resharding returns true if the keys are being moved to a new ring
+func (s *Store) resharding() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.previous != nil
+}
+
+// Init each of the shards with the options
+func (s *Store) Init(opts ...store.Option) error {
+	for _, o := range opts {
+		o(&s.options)
+	}
+	for _, st := range s.all() {
+		if err := st.Init(opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Options of the store
+func (s *Store) Options() store.Options {
+	return s.options
+}
+
+// Read the record with the key from its shard, or the records matching the prefix or suffix
+// from every shard
+func (s *Store) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Prefix || options.Suffix {
+		return s.readAll(key, options, opts)
+	}
+
+	owner, prev, err := s.owners(key)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := owner.Read(key, opts...)
+	if err == store.ErrNotFound && prev != nil {
+		// the key hasn't been moved yet
+		return prev.Read(key, opts...)
+	}
+	return recs, err
+}
+
+// readAll reads the matching records from every shard, applying the limit and offset once
+// they're merged
+func (s *Store) readAll(key string, options store.ReadOptions, opts []store.ReadOption) ([]*store.Record, error) {
+	opts = append(opts[:len(opts):len(opts)], store.ReadLimit(0), store.ReadOffset(0))
+
+	var records []*store.Record
+	seen := make(map[string]bool)
+	for _, st := range s.all() {
+		recs, err := st.Read(key, opts...)
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		// records in the current shards take precedence over those not yet moved
+		for _, r := range recs {
+			if !seen[r.Key] {
+				seen[r.Key] = true
+				records = append(records, r)
+			}
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+	start, end := page(len(records), options.Offset, options.Limit)
+	return records[start:end], nil
+}
+
+// Write the record to the shard which owns its key
+func (s *Store) Write(r *store.Record, opts ...store.WriteOption) error {
+	if s.resharding() {
+		s.moveMtx.Lock()
+		defer s.moveMtx.Unlock()
+	}
+
+	owner, prev, err := s.owners(r.Key)
+	if err != nil {
+		return err
+	}
+	if err := owner.Write(r, opts...); err != nil {
+		return err
+	}
+	if prev == nil {
+		return nil
+	}
+
+	// remove the previous value so it isn't read or moved
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	var delOpts []store.DeleteOption
+	if len(options.Database) > 0 || len(options.Table) > 0 {
+		delOpts = append(delOpts, store.DeleteFrom(options.Database, options.Table))
+	}
+	if err := prev.Delete(r.Key, delOpts...); err != nil && err != store.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// Delete the record from the shard which owns its key
+func (s *Store) Delete(key string, opts ...store.DeleteOption) error {
+	if s.resharding() {
+		s.moveMtx.Lock()
+		defer s.moveMtx.Unlock()
+	}
+
+	owner, prev, err := s.owners(key)
+	if err != nil {
+		return err
+	}
+	err = owner.Delete(key, opts...)
+	if prev == nil {
+		return err
+	}
+	if perr := prev.Delete(key, opts...); err == store.ErrNotFound {
+		return perr
+	} else if perr != nil && perr != store.ErrNotFound {
+		return perr
+	}
+	return err
+}
+
+// List the keys of every shard
+func (s *Store) List(opts ...store.ListOption) ([]string, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	opts = append(opts[:len(opts):len(opts)], store.ListLimit(0), store.ListOffset(0))
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, st := range s.all() {
+		k, err := st.List(opts...)
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, key := range k {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	start, end := page(len(keys), options.Offset, options.Limit)
+	return keys[start:end], nil
+}
+
+// Close each of the shards
+func (s *Store) Close() error {
+	select {
+	case <-s.exit:
+	default:
+		close(s.exit)
+	}
+
+	var lastErr error
+	for _, st := range s.all() {
+		if err := st.Close(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (s *Store) String() string {
+	return "shard"
+}
+
+// page returns the bounds of the items at the offset, up to the limit if it's not zero
+func page(n int, offset, limit uint) (int, int) {
+	if offset >= uint(n) {
+		return n, n
+	}
+	end := uint(n)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return int(offset), int(end)
+}
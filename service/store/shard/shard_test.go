@@ -0,0 +1,162 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing([]string{"a", "b", "c"}, DefaultReplicas)
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		counts[r.Get(fmt.Sprintf("key-%d", i))]++
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if counts[s] < 500 {
+			t.Errorf("Expected the keys to be spread evenly, shard %v has %v of 3000", s, counts[s])
+		}
+	}
+
+	// adding a shard only moves keys to it
+	r2 := NewRing([]string{"a", "b", "c", "d"}, DefaultReplicas)
+	for i := 0; i < 3000; i++ {
+		k := fmt.Sprintf("key-%d", i)
+		if before, after := r.Get(k), r2.Get(k); before != after && after != "d" {
+			t.Fatalf("Expected %v to stay on %v or move to d, got %v", k, before, after)
+		}
+	}
+
+	if NewRing(nil, DefaultReplicas).Get("foo") != "" {
+		t.Errorf("Expected no shard from an empty ring")
+	}
+}
+
+func TestStore(t *testing.T) {
+	a, b := memory.NewStore(), memory.NewStore()
+	s := NewStore([]Shard{{Name: "a", Store: a}, {Name: "b", Store: b}}, RefreshInterval(0))
+
+	for i := 0; i < 100; i++ {
+		if err := s.Write(&store.Record{Key: fmt.Sprintf("key-%02d", i), Value: []byte("v")}); err != nil {
+			t.Fatalf("Unexpected error writing: %v", err)
+		}
+	}
+
+	// the keys are partitioned across both shards
+	keysA, _ := a.List()
+	keysB, _ := b.List()
+	if len(keysA) == 0 || len(keysB) == 0 || len(keysA)+len(keysB) != 100 {
+		t.Errorf("Expected the keys to be partitioned, got %v and %v", len(keysA), len(keysB))
+	}
+
+	if recs, err := s.Read("key-42"); err != nil || len(recs) != 1 {
+		t.Errorf("Expected to read key-42, got %v %v", recs, err)
+	}
+	if keys, err := s.List(store.ListPrefix("key-1"), store.ListLimit(5)); err != nil || len(keys) != 5 || keys[0] != "key-10" {
+		t.Errorf("Expected the first 5 keys prefixed key-1, got %v %v", keys, err)
+	}
+	if recs, err := s.Read("key-9", store.ReadPrefix()); err != nil || len(recs) != 10 {
+		t.Errorf("Expected 10 records prefixed key-9, got %v %v", len(recs), err)
+	}
+	if err := s.Delete("key-42"); err != nil {
+		t.Errorf("Unexpected error deleting: %v", err)
+	}
+	if _, err := s.Read("key-42"); err != store.ErrNotFound {
+		t.Errorf("Expected key-42 to be deleted, got %v", err)
+	}
+}
+
+func TestReshard(t *testing.T) {
+	a, b, c := memory.NewStore(), memory.NewStore(), memory.NewStore()
+	s := NewStore([]Shard{{Name: "a", Store: a}, {Name: "b", Store: b}}, RefreshInterval(0))
+
+	for i := 0; i < 100; i++ {
+		s.Write(&store.Record{Key: fmt.Sprintf("key-%02d", i), Value: []byte("v")})
+	}
+
+	var moved int
+	err := s.Reshard([]Shard{{Name: "a", Store: a}, {Name: "b", Store: b}, {Name: "c", Store: c}}, func(n int) { moved = n })
+	if err != nil {
+		t.Fatalf("Unexpected error resharding: %v", err)
+	}
+	keysC, _ := c.List()
+	if moved == 0 || len(keysC) != moved {
+		t.Errorf("Expected the moved keys to be on the new shard, moved %v and c has %v", moved, len(keysC))
+	}
+
+	keys, err := s.List()
+	if err != nil || len(keys) != 100 {
+		t.Errorf("Expected every key to remain, got %v %v", len(keys), err)
+	}
+	for _, k := range keys {
+		if _, err := s.Read(k); err != nil {
+			t.Errorf("Unexpected error reading %v after resharding: %v", k, err)
+		}
+	}
+}
+
+func TestReadWhilstResharding(t *testing.T) {
+	a, b := memory.NewStore(), memory.NewStore()
+	s := NewStore([]Shard{{Name: "a", Store: a}}, RefreshInterval(0))
+	s.Write(&store.Record{Key: "foo", Value: []byte("old")})
+
+	// switch to the new ring without moving any keys
+	s.switchRing(1, []Shard{{Name: "b", Store: b}}, []Shard{{Name: "a", Store: a}})
+
+	if recs, err := s.Read("foo"); err != nil || string(recs[0].Value) != "old" {
+		t.Fatalf("Expected the key to be read from its previous shard, got %v %v", recs, err)
+	}
+
+	// writes go to the new shard and remove the previous value, so it isn't moved over them
+	s.Write(&store.Record{Key: "foo", Value: []byte("new")})
+	if _, err := a.Read("foo"); err != store.ErrNotFound {
+		t.Errorf("Expected the previous value to be removed, got %v", err)
+	}
+	if err := s.ResumeReshard(nil); err != nil {
+		t.Fatalf("Unexpected error resuming: %v", err)
+	}
+	if recs, err := s.Read("foo"); err != nil || string(recs[0].Value) != "new" {
+		t.Errorf("Expected the new value, got %v %v", recs, err)
+	}
+}
+
+func TestSharedRing(t *testing.T) {
+	stores := map[string]store.Store{"a:1": memory.NewStore(), "b:1": memory.NewStore(), "c:1": memory.NewStore()}
+	shard := func(name string) Shard {
+		return Shard{Name: name, Address: name + ":1", Store: stores[name+":1"]}
+	}
+	dial := Dial(func(address string) store.Store { return stores[address] })
+
+	s1 := NewStore([]Shard{shard("a"), shard("b")}, RefreshInterval(0), dial)
+	s2 := NewStore([]Shard{shard("a"), shard("b")}, RefreshInterval(0), dial)
+	for i := 0; i < 100; i++ {
+		s1.Write(&store.Record{Key: fmt.Sprintf("key-%02d", i), Value: []byte("v")})
+	}
+
+	if err := s1.Reshard([]Shard{shard("a"), shard("b"), shard("c")}, nil); err != nil {
+		t.Fatalf("Unexpected error resharding: %v", err)
+	}
+	if v, resharding := s1.Version(); v != 2 || resharding {
+		t.Fatalf("Expected version 2 once resharded, got %v %v", v, resharding)
+	}
+
+	// other processes switch to the ring recorded in the shards, including those started with
+	// an outdated list of shards
+	s3 := NewStore([]Shard{shard("a")}, RefreshInterval(0), dial)
+	if err := s2.refresh(); err != nil {
+		t.Fatalf("Unexpected error refreshing: %v", err)
+	}
+	for _, s := range []*Store{s2, s3} {
+		if v, _ := s.Version(); v != 2 {
+			t.Errorf("Expected the store to switch to version 2, got %v", v)
+		}
+		for i := 0; i < 100; i++ {
+			if _, err := s.Read(fmt.Sprintf("key-%02d", i)); err != nil {
+				t.Fatalf("Unexpected error reading key-%02d: %v", i, err)
+			}
+		}
+	}
+}
@@ -1,13 +1,24 @@
 package store
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/store/bulk"
 	"github.com/micro/micro/v3/service/store/client"
 )
 
 var (
 	// DefaultStore implementation
 	DefaultStore store.Store = client.NewStore()
+
+	// ErrContention is returned when a counter can't be incremented as it's being incremented
+	// concurrently by too many others
+	ErrContention = errors.New("too much contention")
+
+	// maxIncrementAttempts is the number of times an increment is retried on a conflict
+	maxIncrementAttempts = 20
 )
 
 // Read takes a single key name and optional ReadOptions. It returns matching []*Record or an error.
@@ -29,3 +40,50 @@ func Delete(key string, opts ...store.DeleteOption) error {
 func List(opts ...store.ListOption) ([]string, error) {
 	return DefaultStore.List(opts...)
 }
+
+// BulkUpsert writes each record whose condition is met, returning the result of each record in
+// the order given. Stores which don't upsert in bulk natively have the upserts applied one by one.
+func BulkUpsert(upserts []*bulk.Upsert, opts ...store.WriteOption) ([]*bulk.Result, error) {
+	return bulk.Write(DefaultStore, upserts, opts...)
+}
+
+// Increment the counter stored in the record with the key by delta, returning its new value. A
+// missing record counts from zero. The counter is written only if it's unchanged since it was
+// read, so increments made concurrently by other processes aren't lost.
+func Increment(key string, delta int64, opts ...store.WriteOption) (int64, error) {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	readOpts := []store.ReadOption{store.ReadFrom(options.Database, options.Table)}
+
+	for i := 0; i < maxIncrementAttempts; i++ {
+		var value int64
+		cond := bulk.Condition{NotExists: true}
+		recs, err := DefaultStore.Read(key, readOpts...)
+		if err != nil && err != store.ErrNotFound {
+			return 0, err
+		} else if err == nil && len(recs) > 0 {
+			if value, err = strconv.ParseInt(string(recs[0].Value), 10, 64); err != nil {
+				return 0, err
+			}
+			cond = bulk.Condition{ETag: bulk.ETag(recs[0].Value)}
+		}
+
+		value += delta
+		rec := &store.Record{Key: key, Value: []byte(strconv.FormatInt(value, 10))}
+		results, err := BulkUpsert([]*bulk.Upsert{{Record: rec, Condition: cond}}, opts...)
+		if err != nil {
+			return 0, err
+		} else if len(results) != 1 {
+			return 0, errors.New("unexpected number of results")
+		}
+		if results[0].Written {
+			return value, nil
+		} else if !results[0].Conflict {
+			return 0, errors.New(results[0].Error)
+		}
+	}
+
+	return 0, ErrContention
+}
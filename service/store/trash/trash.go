@@ -0,0 +1,195 @@
+// Package trash soft-deletes records by moving them to the trash of their table, where they're
+// kept for a retention period before the store expires them, so a record deleted by mistake can
+// be restored. Records are only removed from the trash permanently once they expire or are
+// purged explicitly. The trash of every table is kept in the internal table of the store
+// service, so it can't collide with the tables of users or be written to by them.
+package trash
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/store/bulk"
+)
+
+// Metadata keys of the records in the trash
+const (
+	// DeletedKey is the unix timestamp the record was deleted at
+	DeletedKey = "micro_trash_deleted"
+	// ExpiryKey is the expiry in seconds the record had when it was deleted, if any
+	ExpiryKey = "micro_trash_expiry"
+)
+
+var (
+	// DefaultRetention is how long deleted records are kept in the trash, zero if records are
+	// deleted permanently
+	DefaultRetention time.Duration
+
+	// Database and Table the trash is kept in, the internal table of the store service
+	Database = namespace.DefaultNamespace
+	Table    = "store"
+
+	// ErrExists is returned when restoring a record whose key has been written since it was
+	// deleted
+	ErrExists = errors.New("a record with the key exists")
+	// ErrConflict is returned when the record kept being written while it was being deleted
+	ErrConflict = errors.New("the record was written while it was being deleted")
+)
+
+// maxAttempts is the number of times a record is moved to the trash before giving up, if it's
+// written each time between being read and deleted
+const maxAttempts = 10
+
+// prefix of the keys the records deleted from the table are kept under in the trash
+func prefix(database, table string) string {
+	return "trash/" + database + "/" + table + "/"
+}
+
+// Record in the trash
+type Record struct {
+	Key string
+	// Deleted is when the record was deleted
+	Deleted time.Time
+	// Expires is when the record is purged from the trash
+	Expires time.Time
+}
+
+// Delete the record by moving it to the trash, where it's kept for the retention period.
+// Records which don't exist return store.ErrNotFound. The record is only deleted if it hasn't
+// been written since it was copied to the trash, otherwise the value written is trashed.
+func Delete(s store.Store, database, table, key string, retention time.Duration) error {
+	for i := 0; i < maxAttempts; i++ {
+		recs, err := s.Read(key, store.ReadFrom(database, table))
+		if err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			return store.ErrNotFound
+		}
+		rec := recs[0]
+
+		metadata := make(map[string]interface{}, len(rec.Metadata)+2)
+		for k, v := range rec.Metadata {
+			metadata[k] = v
+		}
+		metadata[DeletedKey] = strconv.FormatInt(time.Now().Unix(), 10)
+		if rec.Expiry > 0 {
+			metadata[ExpiryKey] = strconv.FormatInt(int64(rec.Expiry.Seconds()), 10)
+		}
+
+		// write the record to the trash before deleting it, so it can't be lost in between
+		trashed := &store.Record{Key: prefix(database, table) + rec.Key, Value: rec.Value, Expiry: retention, Metadata: metadata}
+		if err := s.Write(trashed, store.WriteTo(Database, Table)); err != nil {
+			return err
+		}
+		deleted, err := bulk.Delete(s, key, bulk.ETag(rec.Value), store.DeleteFrom(database, table))
+		if err != nil {
+			return err
+		} else if deleted {
+			return nil
+		}
+	}
+	return ErrConflict
+}
+
+// Restore the record from the trash, with the expiry it had when it was deleted. Records which
+// aren't in the trash return store.ErrNotFound, and records whose key has been written since
+// they were deleted return ErrExists.
+func Restore(s store.Store, database, table, key string) error {
+	trashKey := prefix(database, table) + key
+	recs, err := s.Read(trashKey, store.ReadFrom(Database, Table))
+	if err != nil {
+		return err
+	}
+	if len(recs) == 0 {
+		return store.ErrNotFound
+	}
+	rec := recs[0]
+
+	metadata := make(map[string]interface{}, len(rec.Metadata))
+	for k, v := range rec.Metadata {
+		if k != DeletedKey && k != ExpiryKey {
+			metadata[k] = v
+		}
+	}
+	restored := &store.Record{Key: key, Value: rec.Value, Metadata: metadata}
+	if secs := unix(rec.Metadata[ExpiryKey]); secs > 0 {
+		restored.Expiry = time.Duration(secs) * time.Second
+	}
+
+	// the record is only restored if its key hasn't been written since it was deleted
+	results, err := bulk.Write(s, []*bulk.Upsert{{Record: restored, Condition: bulk.Condition{NotExists: true}}}, store.WriteTo(database, table))
+	if err != nil {
+		return err
+	}
+	if res := results[0]; res.Conflict {
+		return ErrExists
+	} else if len(res.Error) > 0 {
+		return errors.New(res.Error)
+	}
+	return s.Delete(trashKey, store.DeleteFrom(Database, Table))
+}
+
+// List the records in the trash of the table whose keys have the prefix
+func List(s store.Store, database, table, keyPrefix string) ([]*Record, error) {
+	p := prefix(database, table)
+	recs, err := s.Read(p+keyPrefix, store.ReadFrom(Database, Table), store.ReadPrefix())
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	trashed := make([]*Record, 0, len(recs))
+	for _, r := range recs {
+		t := &Record{Key: strings.TrimPrefix(r.Key, p), Deleted: time.Unix(unix(r.Metadata[DeletedKey]), 0)}
+		if r.Expiry > 0 {
+			t.Expires = now.Add(r.Expiry)
+		}
+		trashed = append(trashed, t)
+	}
+	return trashed, nil
+}
+
+// Purge the records with the keys from the trash permanently, returning the number purged
+func Purge(s store.Store, database, table string, keys ...string) (int, error) {
+	var purged int
+	for _, k := range keys {
+		k = prefix(database, table) + k
+
+		// stores don't all fail to delete records which don't exist
+		recs, err := s.Read(k, store.ReadFrom(Database, Table))
+		if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+			continue
+		} else if err != nil {
+			return purged, err
+		}
+		if err := s.Delete(k, store.DeleteFrom(Database, Table)); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// unix parses a timestamp or number of seconds from the metadata, which stores may return as a
+// string or number
+func unix(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case string:
+		i, _ := strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+		return i
+	}
+	return 0
+}
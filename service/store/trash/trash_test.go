@@ -0,0 +1,114 @@
+package trash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+)
+
+func TestTrash(t *testing.T) {
+	s := memory.NewStore()
+	write := func(key string, expiry time.Duration) {
+		rec := &store.Record{Key: key, Value: []byte(key), Expiry: expiry, Metadata: map[string]interface{}{"owner": "ops"}}
+		if err := s.Write(rec, store.WriteTo("micro", "users")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("alice", 0)
+	write("bob", time.Hour)
+
+	if err := Delete(s, "micro", "users", "alice", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(s, "micro", "users", "bob", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(s, "micro", "users", "carol", time.Hour); err != store.ErrNotFound {
+		t.Errorf("Expected deleting a missing record to be not found, got %v", err)
+	}
+	if _, err := s.Read("alice", store.ReadFrom("micro", "users")); err != store.ErrNotFound {
+		t.Errorf("Expected the deleted record to be removed from the table, got %v", err)
+	}
+
+	recs, err := List(s, "micro", "users", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Deleted.IsZero() || recs[0].Expires.IsZero() {
+		t.Fatalf("Expected both records to be in the trash, got %v", recs)
+	}
+
+	// restored records get their metadata and expiry back
+	if err := Restore(s, "micro", "users", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := s.Read("bob", store.ReadFrom("micro", "users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := restored[0]; string(r.Value) != "bob" || r.Metadata["owner"] != "ops" || r.Metadata[DeletedKey] != nil || r.Expiry <= 0 {
+		t.Errorf("Expected the record to be restored, got %+v", r)
+	}
+	if err := Restore(s, "micro", "users", "bob"); err != store.ErrNotFound {
+		t.Errorf("Expected the restored record to be removed from the trash, got %v", err)
+	}
+
+	// records aren't restored over records written since
+	write("alice", 0)
+	if err := Restore(s, "micro", "users", "alice"); err != ErrExists {
+		t.Errorf("Expected the restore to conflict, got %v", err)
+	}
+
+	n, err := Purge(s, "micro", "users", "alice", "carol")
+	if err != nil || n != 1 {
+		t.Errorf("Expected one record to be purged, got %v %v", n, err)
+	}
+	if recs, _ := List(s, "micro", "users", ""); len(recs) != 0 {
+		t.Errorf("Expected the trash to be empty, got %v", recs)
+	}
+}
+
+// racingStore writes the record again when it's first copied to the trash, as a concurrent
+// write would
+type racingStore struct {
+	store.Store
+	raced bool
+}
+
+func (r *racingStore) Write(rec *store.Record, opts ...store.WriteOption) error {
+	if err := r.Store.Write(rec, opts...); err != nil || r.raced {
+		return err
+	}
+	r.raced = true
+	return r.Store.Write(&store.Record{Key: "alice", Value: []byte("new")}, store.WriteTo("micro", "users"))
+}
+
+func TestDeleteConflict(t *testing.T) {
+	s := &racingStore{Store: memory.NewStore()}
+	if err := s.Store.Write(&store.Record{Key: "alice", Value: []byte("old")}, store.WriteTo("micro", "users")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Delete(s, "micro", "users", "alice", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read("alice", store.ReadFrom("micro", "users")); err != store.ErrNotFound {
+		t.Errorf("Expected the record to be deleted, got %v", err)
+	}
+
+	// the value written during the delete is the one trashed
+	if err := Restore(s, "micro", "users", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := s.Read("alice", store.ReadFrom("micro", "users"))
+	if err != nil || string(recs[0].Value) != "new" {
+		t.Errorf("Expected the value written during the delete to be restored, got %v %v", recs, err)
+	}
+
+	// the trash is kept out of the table's database
+	if tables, _ := s.List(store.ListFrom("micro", "users_trash")); len(tables) != 0 {
+		t.Errorf("Expected no trash table, got %v", tables)
+	}
+}